@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/mindreframer/agentpm/internal/apiversion"
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/urfave/cli/v3"
+)
+
+// ApplyAPIVersion is the root Before hook that selects the version of the
+// JSON/XML output: --api-version, else the project's api_version, else the
+// default. The version travels in the context, see apiversion.FromContext.
+func ApplyAPIVersion(ctx context.Context, c *cli.Command) (context.Context, error) {
+	if c.IsSet("api-version") {
+		version, err := apiversion.Parse(c.String("api-version"))
+		if err != nil {
+			return ctx, fmt.Errorf("invalid --api-version: %w", err)
+		}
+		return apiversion.NewContext(ctx, version), nil
+	}
+
+	// A missing or broken config is reported by the command itself
+	configPath, err := config.ResolveConfigPath(c.String("config"))
+	if err != nil || !config.ConfigExists(configPath) {
+		return ctx, nil
+	}
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil || cfg.APIVersion == 0 {
+		return ctx, nil
+	}
+	version, err := apiversion.Parse(strconv.Itoa(cfg.APIVersion))
+	if err != nil {
+		return ctx, fmt.Errorf("invalid api_version in %s: %w", configPath, err)
+	}
+	return apiversion.NewContext(ctx, version), nil
+}
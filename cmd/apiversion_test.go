@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestApplyAPIVersion(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, ".agentpm.json")
+	epicFile := filepath.Join(tempDir, "epic.xml")
+	testEpic := epic.NewEpic("8", "Versioned")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicFile))
+
+	run := func(config string, args ...string) (string, error) {
+		require.NoError(t, os.WriteFile(configPath, []byte(config), 0644))
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config", Value: configPath},
+				&cli.StringFlag{Name: "format", Value: "text"},
+				&cli.StringFlag{Name: "api-version"},
+			},
+			Before:   ApplyAPIVersion,
+			Commands: []*cli.Command{StatusCommand()},
+		}
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		app.ErrWriter = &bytes.Buffer{}
+		err := app.Run(context.Background(), append([]string{"agentpm"}, args...))
+		return stdout.String(), err
+	}
+
+	t.Run("default keeps v1 output", func(t *testing.T) {
+		output, err := run(`{"current_epic": "epic.xml"}`, "status", "--file", epicFile, "--format", "json")
+		require.NoError(t, err)
+		assert.Contains(t, output, `"epic13_status"`)
+		assert.NotContains(t, output, `"completion"`)
+	})
+
+	t.Run("config selects v2", func(t *testing.T) {
+		output, err := run(`{"current_epic": "epic.xml", "api_version": 2}`, "status", "--file", epicFile, "--format", "json")
+		require.NoError(t, err)
+		assert.Contains(t, output, `"completion"`)
+		assert.NotContains(t, output, `"epic13_status"`)
+
+		output, err = run(`{"current_epic": "epic.xml", "api_version": 2}`, "status", "--file", epicFile, "--format", "xml")
+		require.NoError(t, err)
+		assert.Contains(t, output, "<completion>")
+	})
+
+	t.Run("flag overrides config", func(t *testing.T) {
+		output, err := run(`{"current_epic": "epic.xml", "api_version": 2}`, "--api-version", "v1", "status", "--file", epicFile, "--format", "xml")
+		require.NoError(t, err)
+		assert.Contains(t, output, "<epic13_status>")
+	})
+
+	t.Run("unsupported versions are errors", func(t *testing.T) {
+		_, err := run(`{"current_epic": "epic.xml"}`, "--api-version", "7", "status", "--file", epicFile)
+		assert.ErrorContains(t, err, "invalid --api-version: unsupported API version \"7\"")
+
+		_, err = run(`{"current_epic": "epic.xml", "api_version": 9}`, "status", "--file", epicFile)
+		assert.ErrorContains(t, err, "invalid api_version in "+configPath)
+	})
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/hints"
+	"github.com/mindreframer/agentpm/internal/phases"
+	"github.com/mindreframer/agentpm/internal/policy"
+	"github.com/mindreframer/agentpm/internal/tasks"
+	"github.com/urfave/cli/v3"
+)
+
+// appOptions describe the agentpm app runApp runs commands in
+type appOptions struct {
+	commands []func() *cli.Command // Commands of the app, created anew for every run
+	config   string                // --config (default: unset)
+	format   string                // --format (default: text)
+	time     string                // --time (default: unset, i.e. now)
+	stdin    string                // Standard input of the commands
+	hooks    []cli.BeforeFunc      // Root Before hooks, run in order like those of main
+}
+
+// withConfig returns the options with --config set to path
+func (o appOptions) withConfig(path string) appOptions {
+	o.config = path
+	return o
+}
+
+// withFormat returns the options with --format set to format
+func (o appOptions) withFormat(format string) appOptions {
+	o.format = format
+	return o
+}
+
+// withStdin returns the options with stdin as standard input
+func (o appOptions) withStdin(stdin string) appOptions {
+	o.stdin = stdin
+	return o
+}
+
+// runApp runs args in the app of opts and returns its standard output
+func runApp(t *testing.T, opts appOptions, args ...string) (string, error) {
+	t.Helper()
+	stdout, _, err := runAppOutput(t, opts, args...)
+	return stdout, err
+}
+
+// runAppOutput runs args in the app of opts, which has the root flags of main
+// that commands read, and returns its standard and error output. The state
+// root hooks set for the process is reset when the test ends.
+func runAppOutput(t *testing.T, opts appOptions, args ...string) (string, string, error) {
+	t.Helper()
+
+	format := opts.format
+	if format == "" {
+		format = "text"
+	}
+
+	app := &cli.Command{
+		Name: "agentpm",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "config", Value: opts.config},
+			&cli.StringFlag{Name: "file"},
+			&cli.StringFlag{Name: "format", Value: format},
+			&cli.StringFlag{Name: "time", Value: opts.time},
+			&cli.StringFlag{Name: "actor"},
+			&cli.BoolFlag{Name: "strict"},
+			&cli.BoolFlag{Name: "quiet"},
+		},
+	}
+	for _, command := range opts.commands {
+		app.Commands = append(app.Commands, command())
+	}
+	if len(opts.hooks) > 0 {
+		t.Cleanup(resetRootHooks)
+		app.Before = func(ctx context.Context, c *cli.Command) (context.Context, error) {
+			for _, hook := range opts.hooks {
+				var err error
+				if ctx, err = hook(ctx, c); err != nil {
+					return ctx, err
+				}
+			}
+			return ctx, nil
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	app.Reader = strings.NewReader(opts.stdin)
+	app.Writer = &stdout
+	app.ErrWriter = &stderr
+
+	err := app.Run(context.Background(), append([]string{"agentpm"}, args...))
+	return stdout.String(), stderr.String(), err
+}
+
+// resetRootHooks undoes the process-wide settings of the root hooks
+func resetRootHooks() {
+	tasks.SetStrict(false)
+	policy.SetActive(nil)
+	phases.SetGateTags(nil)
+	phases.SetTestGating("")
+	hints.Configure(nil)
+}
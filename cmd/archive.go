@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// archivedEpic is an epic file moved to the archive directory
+type archivedEpic struct {
+	Epic   string `json:"epic"`
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// archiveResult is the JSON output of archive
+type archiveResult struct {
+	DryRun        bool           `json:"dry_run"`
+	Archived      []archivedEpic `json:"archived"`
+	ConfigUpdated bool           `json:"config_updated"`
+}
+
+// ArchiveCommand moves completed epics out of the workspace
+func ArchiveCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "archive",
+		Usage:     "Move completed epics to the archive directory",
+		ArgsUsage: "[<epic-file>...]",
+		Description: `Move completed epics out of the way: the epic files given, or without
+arguments every completed epic of the workspace, are moved to the archive
+directory (archive_dir, default .agentpm/archive next to .agentpm.json) and
+unregistered from the config.
+
+Only completed epics are archived, and never the current epic - switch to
+another epic first. An epic already in the archive is not overwritten.
+--dry-run shows what would be archived.
+
+Examples:
+  agentpm archive --dry-run
+  agentpm archive
+  agentpm archive epics/epic-7.xml`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show what would be archived without moving anything",
+			},
+		},
+		Action: withQuietResult(archiveAction),
+	}
+}
+
+func archiveAction(ctx context.Context, c *cli.Command) error {
+	cfg, archiveDir, err := loadArchiveConfig(c)
+	if err != nil {
+		return err
+	}
+
+	explicit := c.Args().Present()
+	paths := c.Args().Slice()
+	if !explicit {
+		if cfg == nil {
+			return exitcode.Errorf(exitcode.Validation, "no epic files given and no configuration to find the workspace epics in")
+		}
+		paths = cfg.EpicFilePaths()
+	}
+
+	current := ""
+	if cfg != nil {
+		current = cfg.EpicFilePath()
+	}
+	fileStorage := storage.NewFileStorage()
+	var candidates []archivedEpic
+	for _, path := range paths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return fmt.Errorf("failed to resolve epic path: %w", err)
+		}
+		if current != "" && sameFile(absPath, current) {
+			if explicit {
+				return exitcode.Errorf(exitcode.Constraint, "cannot archive the current epic %s (switch to another epic first)", path)
+			}
+			continue
+		}
+		epicData, err := fileStorage.LoadEpic(path)
+		if err != nil {
+			if explicit {
+				return fmt.Errorf("failed to load epic %s: %w", path, err)
+			}
+			continue
+		}
+		if epicData.Status != epic.StatusCompleted {
+			if explicit {
+				return exitcode.Errorf(exitcode.Constraint, "cannot archive epic %s in %s: it is %s, not completed", epicData.ID, path, epicData.Status)
+			}
+			continue
+		}
+		target := filepath.Join(archiveDir, filepath.Base(path))
+		if _, err := os.Stat(target); err == nil {
+			return exitcode.Errorf(exitcode.Constraint, "%s is already archived as %s", path, target)
+		}
+		candidates = append(candidates, archivedEpic{Epic: epicData.ID, Name: epicData.Name, Source: path, Target: target})
+	}
+
+	result := archiveResult{DryRun: c.Bool("dry-run") || storage.DryRun(), Archived: []archivedEpic{}}
+	for _, candidate := range candidates {
+		if !result.DryRun {
+			if _, err := storage.CopyToArchive(candidate.Source, archiveDir, filepath.Base(candidate.Source)); err != nil {
+				return err
+			}
+			if err := os.Remove(candidate.Source); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", candidate.Source, err)
+			}
+			if cfg != nil && unregisterEpic(cfg, candidate.Source) {
+				result.ConfigUpdated = true
+			}
+		}
+		result.Archived = append(result.Archived, candidate)
+	}
+	if result.ConfigUpdated {
+		if err := config.SaveConfig(cfg, cfg.FilePath()); err != nil {
+			return fmt.Errorf("failed to update configuration: %w", err)
+		}
+	}
+
+	return writeArchiveResult(c, result)
+}
+
+// loadArchiveConfig loads the config and the archive directory it sets. Without
+// a config file the archive is .agentpm/archive in the working directory.
+func loadArchiveConfig(c *cli.Command) (*config.Config, string, error) {
+	cfg, err := config.LoadConfig(c.String("config"))
+	if err == nil {
+		return cfg, cfg.ArchiveDirPath(), nil
+	}
+	if code, ok := exitcode.Of(err); ok && code == exitcode.NotFound {
+		return nil, config.DefaultArchiveDir, nil
+	}
+	return nil, "", fmt.Errorf("failed to load configuration: %w", err)
+}
+
+// unregisterEpic removes the references to an archived epic file from the
+// config: the workspace entry and the previous epic. It reports whether any
+// reference was removed.
+func unregisterEpic(cfg *config.Config, path string) bool {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	removed := cfg.RemoveEpic(absPath)
+	if cfg.PreviousEpic != "" && sameFile(cfg.PreviousEpicFilePath(), absPath) {
+		cfg.PreviousEpic = ""
+		removed = true
+	}
+	return removed
+}
+
+// sameFile reports whether two paths name the same file
+func sameFile(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+func writeArchiveResult(c *cli.Command, result archiveResult) error {
+	w := c.Root().Writer
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal archive result to JSON: %w", err)
+		}
+		fmt.Fprintf(w, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("archive_result")
+		root.CreateAttr("dry_run", strconv.FormatBool(result.DryRun))
+		root.CreateAttr("config_updated", strconv.FormatBool(result.ConfigUpdated))
+		for _, archived := range result.Archived {
+			elem := root.CreateElement("archived")
+			elem.CreateAttr("epic", archived.Epic)
+			elem.CreateAttr("source", archived.Source)
+			elem.CreateAttr("target", archived.Target)
+			elem.SetText(archived.Name)
+		}
+		doc.Indent(4)
+		doc.WriteTo(w)
+	default:
+		if len(result.Archived) == 0 {
+			fmt.Fprintf(w, "No completed epics to archive.\n")
+			return nil
+		}
+		verb := "Archived"
+		if result.DryRun {
+			verb = "Would archive"
+		}
+		for _, archived := range result.Archived {
+			fmt.Fprintf(w, "%s epic %s (%s): %s -> %s\n", verb, archived.Epic, archived.Name, archived.Source, archived.Target)
+		}
+		if result.ConfigUpdated {
+			fmt.Fprintf(w, "Removed the archived epics from the config.\n")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var archiveApp = appOptions{commands: []func() *cli.Command{ArchiveCommand, CompactCommand}, time: "2025-08-20T12:00:00Z"}
+
+func TestArchiveCommand(t *testing.T) {
+	setup := func(t *testing.T) string {
+		dir := t.TempDir()
+		fileStorage := storage.NewFileStorage()
+		for _, e := range []struct {
+			file   string
+			status epic.Status
+		}{{"epic-8.xml", epic.StatusCompleted}, {"epic-7.xml", epic.StatusCompleted}, {"epic-9.xml", epic.StatusWIP}} {
+			epicData := epic.NewEpic(e.file[:len(e.file)-4], "Epic "+e.file)
+			epicData.Status = e.status
+			require.NoError(t, fileStorage.SaveEpic(epicData, filepath.Join(dir, e.file)))
+		}
+
+		configPath := filepath.Join(dir, ".agentpm.json")
+		cfg := config.DefaultConfig()
+		cfg.CurrentEpic = "epic-8.xml"
+		cfg.PreviousEpic = "epic-7.xml"
+		cfg.Epics = []string{"epic-7.xml", "epic-9.xml"}
+		require.NoError(t, config.SaveConfig(cfg, configPath))
+		return configPath
+	}
+
+	t.Run("moves completed workspace epics to the archive", func(t *testing.T) {
+		configPath := setup(t)
+		dir := filepath.Dir(configPath)
+		archived := filepath.Join(dir, ".agentpm", "archive", "epic-7.xml")
+
+		output, err := runApp(t, archiveApp.withConfig(configPath), "archive", "--dry-run")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Would archive epic epic-7 (Epic epic-7.xml): ")
+		assert.NotContains(t, output, "epic-8", "the current epic is left alone")
+		assert.NotContains(t, output, "epic-9", "epics in progress are left alone")
+		assert.FileExists(t, filepath.Join(dir, "epic-7.xml"))
+		assert.NoFileExists(t, archived)
+
+		output, err = runApp(t, archiveApp.withConfig(configPath), "archive")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Archived epic epic-7 (Epic epic-7.xml): ")
+		assert.Contains(t, output, "Removed the archived epics from the config.\n")
+		assert.NoFileExists(t, filepath.Join(dir, "epic-7.xml"))
+		archivedEpic, err := storage.NewFileStorage().LoadEpic(archived)
+		require.NoError(t, err)
+		assert.Equal(t, "epic-7", archivedEpic.ID)
+
+		cfg, err := config.LoadConfig(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, "epic-8.xml", cfg.CurrentEpic)
+		assert.Empty(t, cfg.PreviousEpic)
+		assert.Equal(t, []string{"epic-9.xml"}, cfg.Epics)
+
+		output, err = runApp(t, archiveApp.withConfig(configPath), "archive")
+		require.NoError(t, err)
+		assert.Equal(t, "No completed epics to archive.\n", output)
+	})
+
+	t.Run("refuses the current epic and epics in progress", func(t *testing.T) {
+		configPath := setup(t)
+		dir := filepath.Dir(configPath)
+
+		_, err := runApp(t, archiveApp.withConfig(configPath), "archive", filepath.Join(dir, "epic-8.xml"))
+		require.Error(t, err)
+		assert.Equal(t, exitcode.Constraint, ExitCode(err))
+		assert.Contains(t, err.Error(), "cannot archive the current epic")
+
+		_, err = runApp(t, archiveApp.withConfig(configPath), "archive", filepath.Join(dir, "epic-7.xml"), filepath.Join(dir, "epic-9.xml"))
+		require.Error(t, err)
+		assert.Equal(t, exitcode.Constraint, ExitCode(err))
+		assert.Contains(t, err.Error(), "it is wip, not completed")
+		assert.FileExists(t, filepath.Join(dir, "epic-7.xml"), "nothing is archived when one epic is refused")
+	})
+
+	t.Run("never overwrites the archive", func(t *testing.T) {
+		configPath := setup(t)
+		dir := filepath.Dir(configPath)
+		epic7 := filepath.Join(dir, "epic-7.xml")
+		_, err := storage.CopyToArchive(epic7, filepath.Join(dir, ".agentpm", "archive"), "epic-7.xml")
+		require.NoError(t, err)
+
+		_, err = runApp(t, archiveApp.withConfig(configPath), "archive", epic7)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already archived")
+		assert.FileExists(t, epic7)
+	})
+}
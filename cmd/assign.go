@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/commands"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/phases"
+	"github.com/mindreframer/agentpm/internal/query"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/mindreframer/agentpm/internal/tasks"
+	"github.com/urfave/cli/v3"
+)
+
+// AssignCommand hands tasks and phases to agents
+func AssignCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "assign",
+		Usage:     "Assign a task or phase to an agent",
+		ArgsUsage: "<task-id|phase-id> <agent>",
+		Description: `Hand a task or a whole phase to an agent, so that several agents can split
+an epic and each one only sees its own work with --assignee on pending,
+current and query.
+
+A task's assignee is its own, otherwise the assignee of its phase, otherwise
+the assignee of the epic. Assigning a phase therefore covers all its tasks
+that have no assignee of their own. --clear removes an assignment again.
+
+Examples:
+  agentpm assign 1A_1 agent_b          # Assign a task
+  agentpm assign 2 agent_c             # Assign all tasks of phase 2
+  agentpm assign 1A_1 --clear          # Fall back to the phase's assignee
+  agentpm pending --assignee agent_b   # The work of agent_b`,
+		Flags: append(commands.GlobalFlags(),
+			&cli.BoolFlag{
+				Name:  "clear",
+				Usage: "Remove the assignment instead of setting one",
+			},
+		),
+		Action: withQuietResult(assignAction),
+	}
+}
+
+// assigneeFlag narrows the output of read commands to the work of one agent
+func assigneeFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "assignee",
+		Usage: "Only show the work assigned to this agent",
+	}
+}
+
+// assigneeSuffix is the " @agent" a text line ends with for assigned work
+func assigneeSuffix(assignee string) string {
+	if assignee == "" {
+		return ""
+	}
+	return " @" + assignee
+}
+
+// assigneeAttr is the assignee attribute of an XML element, if any
+func assigneeAttr(assignee string) string {
+	if assignee == "" {
+		return ""
+	}
+	return fmt.Sprintf(" assignee=\"%s\"", assignee)
+}
+
+func assignAction(ctx context.Context, c *cli.Command) error {
+	clearing := c.Bool("clear")
+	wantArgs, usage := 2, "assign requires a task or phase ID and an agent"
+	if clearing {
+		wantArgs, usage = 1, "assign --clear requires exactly one task or phase ID"
+	}
+	if c.Args().Len() != wantArgs {
+		return exitcode.Errorf(exitcode.Validation, "%s", usage)
+	}
+	id, assignee := c.Args().Get(0), c.Args().Get(1)
+
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now()
+	if timeStr := c.String("time"); timeStr != "" {
+		timestamp, err = time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+		}
+	}
+
+	storageImpl := storage.NewFileStorage()
+	queryService := query.NewQueryService(storageImpl)
+
+	epicData, err := storageImpl.LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	entityType := assignTarget(epicData, id)
+	switch entityType {
+	case "task":
+		err = tasks.NewTaskService(storageImpl, queryService).AssignTask(epicData, id, assignee, timestamp)
+	case "phase":
+		err = phases.NewPhaseService(storageImpl, queryService).AssignPhase(epicData, id, assignee, timestamp)
+	default:
+		return fmt.Errorf("task or phase %s not found", id)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := storageImpl.SaveEpic(epicData, epicFile); err != nil {
+		return fmt.Errorf("failed to save epic: %w", err)
+	}
+
+	effective := epicData.PhaseAssignee(id)
+	if entityType == "task" {
+		for i := range epicData.Tasks {
+			if epicData.Tasks[i].ID == id {
+				effective = epicData.TaskAssignee(&epicData.Tasks[i])
+			}
+		}
+	}
+	return writeAssignResult(c, entityType, id, assignee, effective, timestamp)
+}
+
+// assignTarget tells whether id names a task or a phase; tasks win when both
+// share an ID. It returns "" for unknown IDs.
+func assignTarget(epicData *epic.Epic, id string) string {
+	for _, task := range epicData.Tasks {
+		if task.ID == id {
+			return "task"
+		}
+	}
+	for _, phase := range epicData.Phases {
+		if phase.ID == id {
+			return "phase"
+		}
+	}
+	return ""
+}
+
+// writeAssignResult reports the new assignment; effective is who works on
+// the task or phase now, which differs from assignee after --clear
+func writeAssignResult(c *cli.Command, entityType, id, assignee, effective string, timestamp time.Time) error {
+	operation := entityType + "_assigned"
+	if assignee == "" {
+		operation = entityType + "_unassigned"
+	}
+
+	switch c.String("format") {
+	case "json":
+		output := map[string]interface{}{
+			entityType + "_id": id,
+			"operation":        operation,
+			"assignee":         assignee,
+			"effective":        effective,
+			"timestamp":        timestamp.Format(time.RFC3339),
+		}
+		jsonData, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal result to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		fmt.Fprintf(c.Root().Writer, "<%s %s=\"%s\" assignee=\"%s\" effective=\"%s\" timestamp=\"%s\"/>\n",
+			operation, entityType, id, assignee, effective, timestamp.Format(time.RFC3339))
+	default:
+		label := "Task"
+		if entityType == "phase" {
+			label = "Phase"
+		}
+		switch {
+		case assignee != "":
+			fmt.Fprintf(c.Root().Writer, "%s %s assigned to %s.\n", label, id, assignee)
+		case effective != "":
+			fmt.Fprintf(c.Root().Writer, "%s %s unassigned, falls back to %s.\n", label, id, effective)
+		default:
+			fmt.Fprintf(c.Root().Writer, "%s %s unassigned.\n", label, id)
+		}
+	}
+	return nil
+}
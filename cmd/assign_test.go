@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var assignApp = appOptions{commands: []func() *cli.Command{AssignCommand, PendingCommand, CurrentCommand, QueryCommand}}
+
+// createSplitEpic writes an epic of two phases and a config pointing to it
+func createSplitEpic(t *testing.T) (epicFile, configPath string) {
+	t.Helper()
+	dir := t.TempDir()
+	e := &epic.Epic{
+		ID:        "split",
+		Name:      "Split",
+		Status:    epic.StatusWIP,
+		CreatedAt: time.Date(2025, 8, 1, 9, 0, 0, 0, time.UTC),
+		Assignee:  "agent_a",
+		Phases: []epic.Phase{
+			{ID: "1", Name: "Backend", Status: epic.StatusWIP},
+			{ID: "2", Name: "Frontend", Status: epic.StatusWIP},
+		},
+		Tasks: []epic.Task{
+			{ID: "1_1", PhaseID: "1", Name: "API", Status: epic.StatusWIP},
+			{ID: "1_2", PhaseID: "1", Name: "Client", Status: epic.StatusPending},
+			{ID: "2_1", PhaseID: "2", Name: "Form", Status: epic.StatusWIP},
+		},
+		Tests: []epic.Test{
+			{ID: "T1_1", TaskID: "1_1", PhaseID: "1", Name: "API works", Status: epic.StatusPending},
+			{ID: "T2_1", TaskID: "2_1", PhaseID: "2", Name: "Form works", Status: epic.StatusPending},
+		},
+	}
+	epicFile = filepath.Join(dir, "epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(e, epicFile))
+	configPath = filepath.Join(dir, ".agentpm.json")
+	require.NoError(t, config.SaveConfig(&config.Config{CurrentEpic: epicFile}, configPath))
+	return epicFile, configPath
+}
+
+func TestAssignCommand(t *testing.T) {
+	epicFile, configPath := createSplitEpic(t)
+
+	out, err := runApp(t, assignApp.withConfig(configPath), "assign", "2", "agent_b", "--file", epicFile, "--time", "2025-08-02T10:00:00Z")
+	require.NoError(t, err)
+	assert.Equal(t, "Phase 2 assigned to agent_b.\n", out)
+
+	out, err = runApp(t, assignApp.withConfig(configPath), "assign", "1_2", "agent_b", "--file", epicFile, "--format", "json")
+	require.NoError(t, err)
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(out), &result))
+	assert.Equal(t, "task_assigned", result["operation"])
+	assert.Equal(t, "agent_b", result["effective"])
+
+	e, err := storage.NewFileStorage().LoadEpic(epicFile)
+	require.NoError(t, err)
+	assert.Equal(t, "agent_b", e.Phases[1].Assignee)
+	assert.Equal(t, "agent_b", e.Tasks[1].Assignee)
+	assert.Equal(t, "Phase 2 (Frontend) assigned to agent_b", e.Events[0].Data)
+	assert.Equal(t, "Task 1_2 (Client) assigned to agent_b", e.Events[1].Data)
+
+	out, err = runApp(t, assignApp.withConfig(configPath), "assign", "1_2", "--clear", "--file", epicFile)
+	require.NoError(t, err)
+	assert.Equal(t, "Task 1_2 unassigned, falls back to agent_a.\n", out)
+
+	_, err = runApp(t, assignApp.withConfig(configPath), "assign", "9_9", "agent_b", "--file", epicFile)
+	assert.Equal(t, exitcode.NotFound, ExitCode(err))
+
+	_, err = runApp(t, assignApp.withConfig(configPath), "assign", "1_1", "--file", epicFile)
+	assert.Equal(t, exitcode.Validation, ExitCode(err))
+}
+
+func TestAssigneeFilters(t *testing.T) {
+	epicFile, configPath := createSplitEpic(t)
+	_, err := runApp(t, assignApp.withConfig(configPath), "assign", "2", "agent_b", "--file", epicFile)
+	require.NoError(t, err)
+
+	t.Run("pending", func(t *testing.T) {
+		out, err := runApp(t, assignApp.withConfig(configPath), "pending", "--assignee", "agent_b")
+		require.NoError(t, err)
+		assert.Contains(t, out, "2_1 (2) - Form [wip] @agent_b")
+		assert.NotContains(t, out, "1_1")
+		assert.Contains(t, out, "Tests (1):")
+
+		out, err = runApp(t, assignApp.withConfig(configPath), "pending", "--assignee", "agent_a", "--format", "json")
+		require.NoError(t, err)
+		var pending pendingOutput
+		require.NoError(t, json.Unmarshal([]byte(out), &pending))
+		require.Len(t, pending.Tasks, 2)
+		assert.Equal(t, "1_1", pending.Tasks[0].ID)
+		assert.Empty(t, pending.Tasks[0].Assignee, "falls back to the epic's assignee")
+	})
+
+	t.Run("current", func(t *testing.T) {
+		out, err := runApp(t, assignApp.withConfig(configPath), "current", "--assignee", "agent_b", "--format", "json")
+		require.NoError(t, err)
+		var state map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(out), &state))
+		assert.Equal(t, "2_1", state["active_task"])
+	})
+
+	t.Run("query", func(t *testing.T) {
+		out, err := runApp(t, assignApp.withConfig(configPath), "query", "//task", "--assignee", "agent_a", "--format", "json")
+		require.NoError(t, err)
+		assert.Contains(t, out, `"1_1"`)
+		assert.Contains(t, out, `"1_2"`)
+		assert.NotContains(t, out, `"2_1"`)
+	})
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/migrate"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// BackfillTimestampsCommand reconstructs missing lifecycle timestamps
+func BackfillTimestampsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "backfill-timestamps",
+		Usage: "Reconstruct missing started/completed timestamps from the event log",
+		Description: `Find phases, tasks and tests whose status calls for a timestamp they lack -
+a completed task without started_at or completed_at, a passed test without
+passed_at - as older or hand-written epics often have. Cycle times, velocity
+and forecasts skip such entities.
+
+With --from-events each missing timestamp is set from the latest event that
+recorded it (task_started, task_completed, test_passed, ...). Timestamps no
+event accounts for are listed for a manual fix. Without --from-events the
+command only lists what is missing.
+
+Examples:
+  agentpm backfill-timestamps                        # List missing timestamps
+  agentpm backfill-timestamps --from-events --dry-run
+  agentpm backfill-timestamps --from-events`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config",
+			},
+			&cli.BoolFlag{
+				Name:  "from-events",
+				Usage: "Set missing timestamps from the event log",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show what would be backfilled without making changes",
+			},
+		},
+		Action: backfillTimestampsAction,
+	}
+}
+
+func backfillTimestampsAction(ctx context.Context, c *cli.Command) error {
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+
+	fileStorage := storage.NewFileStorage()
+	epicData, err := fileStorage.LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	dryRun := c.Bool("dry-run")
+	report := migrate.Timestamps(epicData, c.Bool("from-events"))
+	if !dryRun && len(report.Backfilled) > 0 {
+		if err := fileStorage.SaveEpic(epicData, epicFile); err != nil {
+			return fmt.Errorf("failed to save epic: %w", err)
+		}
+	}
+
+	switch c.String("format") {
+	case "json":
+		output := struct {
+			*migrate.TimestampReport
+			DryRun bool `json:"dry_run"`
+		}{report, dryRun}
+		jsonData, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal backfill report to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		outputBackfillXML(c, report, dryRun)
+	default:
+		outputBackfillText(c, report, dryRun, c.Bool("from-events"))
+	}
+	return nil
+}
+
+func outputBackfillText(c *cli.Command, report *migrate.TimestampReport, dryRun, fromEvents bool) {
+	w := c.Root().Writer
+
+	if len(report.Backfilled) == 0 && len(report.Missing) == 0 {
+		fmt.Fprintf(w, "Epic %s has no missing timestamps.\n", report.EpicID)
+		return
+	}
+
+	if len(report.Backfilled) > 0 {
+		verb := "Backfilled"
+		if dryRun {
+			verb = "Would backfill"
+		}
+		fmt.Fprintf(w, "%s %d timestamps of epic %s from the event log:\n", verb, len(report.Backfilled), report.EpicID)
+		for _, backfill := range report.Backfilled {
+			fmt.Fprintf(w, "  %-5s %-10s %s = %s (event %s)\n", backfill.EntityType, backfill.EntityID, backfill.Field, backfill.Value.Format(time.RFC3339), backfill.EventID)
+		}
+	}
+
+	if len(report.Missing) > 0 {
+		if len(report.Backfilled) > 0 {
+			fmt.Fprintln(w)
+		}
+		if fromEvents {
+			fmt.Fprintf(w, "No event records these timestamps (%d); set them by hand:\n", len(report.Missing))
+		} else {
+			fmt.Fprintf(w, "Missing timestamps in epic %s (%d):\n", report.EpicID, len(report.Missing))
+		}
+		for _, gap := range report.Missing {
+			fmt.Fprintf(w, "  %-5s %-10s %s (status %s)\n", gap.EntityType, gap.EntityID, gap.Field, gap.Status)
+		}
+		if !fromEvents {
+			fmt.Fprintf(w, "\nRun 'agentpm backfill-timestamps --from-events' to reconstruct them from the event log.\n")
+		}
+	}
+}
+
+func outputBackfillXML(c *cli.Command, report *migrate.TimestampReport, dryRun bool) {
+	doc := etree.NewDocument()
+	root := doc.CreateElement("backfill_timestamps")
+	root.CreateAttr("epic", report.EpicID)
+	root.CreateAttr("dry_run", strconv.FormatBool(dryRun))
+
+	backfilledElem := root.CreateElement("backfilled")
+	for _, backfill := range report.Backfilled {
+		elem := backfilledElem.CreateElement("timestamp")
+		elem.CreateAttr("entity_type", backfill.EntityType)
+		elem.CreateAttr("entity_id", backfill.EntityID)
+		elem.CreateAttr("field", backfill.Field)
+		elem.CreateAttr("value", backfill.Value.Format(time.RFC3339))
+		elem.CreateAttr("event_id", backfill.EventID)
+	}
+
+	missingElem := root.CreateElement("missing")
+	for _, gap := range report.Missing {
+		elem := missingElem.CreateElement("timestamp")
+		elem.CreateAttr("entity_type", gap.EntityType)
+		elem.CreateAttr("entity_id", gap.EntityID)
+		elem.CreateAttr("field", gap.Field)
+		elem.CreateAttr("status", gap.Status)
+	}
+
+	doc.Indent(4)
+	doc.WriteTo(c.Root().Writer)
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+const untimedEpic = `<epic id="8" name="Untimed Epic" status="wip" created_at="2025-08-01T09:00:00Z">
+    <phases>
+        <phase id="P1" name="Build" status="wip"/>
+    </phases>
+    <tasks>
+        <task id="T1" phase_id="P1" name="Task 1" status="completed"/>
+    </tasks>
+    <tests/>
+    <events>
+        <event id="e1" type="task_started" timestamp="2025-08-02T10:00:00Z"><data>Task T1 (Task 1) started</data></event>
+        <event id="e2" type="task_completed" timestamp="2025-08-02T12:00:00Z"><data>Task T1 (Task 1) completed</data></event>
+    </events>
+</epic>`
+
+func TestBackfillTimestampsCommand(t *testing.T) {
+	run := func(t *testing.T, format string, args ...string) (string, string) {
+		t.Helper()
+		epicFile := filepath.Join(t.TempDir(), "epic.xml")
+		require.NoError(t, os.WriteFile(epicFile, []byte(untimedEpic), 0644))
+
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config", Value: filepath.Join(t.TempDir(), ".agentpm.json")},
+				&cli.StringFlag{Name: "format", Value: format},
+			},
+			Commands: []*cli.Command{BackfillTimestampsCommand()},
+		}
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		err := app.Run(context.Background(), append([]string{"agentpm", "backfill-timestamps", "--file", epicFile}, args...))
+		require.NoError(t, err)
+		return stdout.String(), epicFile
+	}
+
+	t.Run("lists missing timestamps without changes", func(t *testing.T) {
+		output, epicFile := run(t, "text")
+		assert.Contains(t, output, "Missing timestamps in epic 8 (3):")
+		assert.Contains(t, output, "task  T1         completed_at (status completed)")
+		assert.Contains(t, output, "--from-events")
+
+		content, err := os.ReadFile(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, untimedEpic, string(content))
+	})
+
+	t.Run("backfills from events", func(t *testing.T) {
+		output, epicFile := run(t, "text", "--from-events")
+		assert.Contains(t, output, "Backfilled 2 timestamps of epic 8 from the event log:")
+		assert.Contains(t, output, "task  T1         completed_at = 2025-08-02T12:00:00Z (event e2)")
+		assert.Contains(t, output, "No event records these timestamps (1); set them by hand:\n  phase P1         started_at (status wip)")
+
+		epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+		require.NoError(t, err)
+		require.NotNil(t, epicData.Tasks[0].CompletedAt)
+		assert.Equal(t, "2025-08-02T12:00:00Z", epicData.Tasks[0].CompletedAt.Format("2006-01-02T15:04:05Z"))
+	})
+
+	t.Run("dry run in JSON", func(t *testing.T) {
+		output, epicFile := run(t, "json", "--from-events", "--dry-run")
+		var result map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.Equal(t, true, result["dry_run"])
+		assert.Len(t, result["backfilled"], 2)
+		assert.Len(t, result["missing"], 1)
+
+		content, err := os.ReadFile(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, untimedEpic, string(content))
+	})
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mindreframer/agentpm/internal/badge"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/query"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// BadgeCommand generates SVG status badges for READMEs
+func BadgeCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "badge",
+		Usage: "Generate an SVG status badge (completion or tests)",
+		Description: `Render a shields-style SVG badge for embedding in READMEs:
+
+  completion   "epic 8: 72%" - the completion shown by 'agentpm status'
+  tests        "tests: 14/17" - passing tests of all tests not cancelled;
+               red as soon as one test is failing
+
+Examples:
+  agentpm badge --type completion --output docs/epic-badge.svg
+  agentpm badge --type tests --label "epic 8 tests" > tests.svg`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config",
+			},
+			&cli.StringFlag{
+				Name:  "type",
+				Usage: "Badge type: completion or tests",
+				Value: "completion",
+			},
+			&cli.StringFlag{
+				Name:  "label",
+				Usage: "Override the label on the left side of the badge",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Output file path (default: stdout)",
+			},
+		},
+		Action: badgeAction,
+	}
+}
+
+func badgeAction(ctx context.Context, c *cli.Command) error {
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+
+	epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	b, err := EpicBadge(epicData, c.String("type"))
+	if err != nil {
+		return err
+	}
+	if label := c.String("label"); label != "" {
+		b.Label = label
+	}
+
+	outputFile := c.String("output")
+	if outputFile == "" || outputFile == "-" {
+		_, err = c.Root().Writer.Write(b.SVG())
+		return err
+	}
+
+	if err := writeToFile(outputFile, string(b.SVG())); err != nil {
+		return fmt.Errorf("failed to write badge to file: %w", err)
+	}
+	fmt.Fprintf(c.Root().Writer, "Badge generated: %s (%s: %s)\n", outputFile, b.Label, b.Message)
+	return nil
+}
+
+// EpicBadge builds the badge of the given type (completion or tests) for an epic
+func EpicBadge(epicData *epic.Epic, badgeType string) (badge.Badge, error) {
+	switch badgeType {
+	case "completion":
+		memoryStorage := storage.NewMemoryStorage()
+		memoryStorage.StoreEpic(epicData.ID, epicData)
+		queryService := query.NewQueryService(memoryStorage)
+		if err := queryService.LoadEpic(epicData.ID); err != nil {
+			return badge.Badge{}, err
+		}
+		status, err := queryService.GetEpicStatus()
+		if err != nil {
+			return badge.Badge{}, err
+		}
+		return badge.Badge{
+			Label:   "epic " + epicData.ID,
+			Message: fmt.Sprintf("%d%%", status.CompletionPercentage),
+			Color:   badge.ProgressColor(status.CompletionPercentage, 100),
+		}, nil
+	case "tests":
+		passing, total, failing := 0, 0, false
+		for _, test := range epicData.Tests {
+			if test.GetTestStatusUnified() == epic.TestStatusCancelled {
+				continue
+			}
+			total++
+			if test.GetTestResult() == epic.TestResultFailing {
+				failing = true
+			} else if test.GetTestStatusUnified() == epic.TestStatusDone {
+				passing++
+			}
+		}
+		color := badge.ProgressColor(passing, total)
+		if failing {
+			color = badge.ColorRed
+		}
+		return badge.Badge{Label: "tests", Message: fmt.Sprintf("%d/%d", passing, total), Color: color}, nil
+	default:
+		return badge.Badge{}, fmt.Errorf("invalid badge type: %s (expected completion or tests)", badgeType)
+	}
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/badge"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBadgeCommand(t *testing.T) {
+	runBadge := func(t *testing.T, args ...string) (string, error) {
+		var stdout, stderr bytes.Buffer
+		cmd := BadgeCommand()
+		cmd.Root().Writer = &stdout
+		cmd.Root().ErrWriter = &stderr
+		err := cmd.Run(context.Background(), append([]string{"badge"}, args...))
+		return stdout.String(), err
+	}
+
+	dir := t.TempDir()
+	epicFile := filepath.Join(dir, "epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(createEpicForReset(), epicFile))
+
+	t.Run("completion badge to stdout", func(t *testing.T) {
+		output, err := runBadge(t, "--file", epicFile)
+		require.NoError(t, err)
+		assert.Contains(t, output, "<title>epic epic-1: ")
+		assert.Contains(t, output, "</svg>")
+	})
+
+	t.Run("tests badge to a file with a custom label", func(t *testing.T) {
+		badgeFile := filepath.Join(dir, "badges", "tests.svg")
+		output, err := runBadge(t, "--file", epicFile, "--type", "tests", "--label", "epic 1 tests", "-o", badgeFile)
+		require.NoError(t, err)
+		assert.Equal(t, "Badge generated: "+badgeFile+" (epic 1 tests: 1/2)\n", output)
+
+		content, err := os.ReadFile(badgeFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "<title>epic 1 tests: 1/2</title>")
+	})
+
+	t.Run("rejects unknown types", func(t *testing.T) {
+		_, err := runBadge(t, "--file", epicFile, "--type", "coverage")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid badge type: coverage")
+	})
+}
+
+func TestEpicBadge(t *testing.T) {
+	testEpic := createEpicForReset()
+
+	tests, err := EpicBadge(testEpic, "tests")
+	require.NoError(t, err)
+	assert.Equal(t, badge.Badge{Label: "tests", Message: "1/2", Color: badge.ColorYellow}, tests)
+
+	testEpic.Tests[1].Status = epic.StatusCancelled
+	testEpic.Tests[1].TestStatus = epic.TestStatusCancelled
+	tests, err = EpicBadge(testEpic, "tests")
+	require.NoError(t, err)
+	assert.Equal(t, "1/1", tests.Message)
+	assert.Equal(t, badge.ColorBrightGreen, tests.Color)
+
+	testEpic.Tests[0].TestResult = epic.TestResultFailing
+	tests, err = EpicBadge(testEpic, "tests")
+	require.NoError(t, err)
+	assert.Equal(t, badge.ColorRed, tests.Color)
+}
@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/commands"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// BlockerCommand records blockers and open questions on the epic
+func BlockerCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "blocker",
+		Usage: "Raise, resolve and list blockers and open questions",
+		Description: `Record what holds the work up, so that it survives agent transitions:
+unresolved blockers and open questions are listed first in 'agentpm handoff'
+and among the blockers of 'agentpm current'.
+
+A blocker concerns the whole epic, or one phase or task with --on. With
+--question it is recorded as an open question instead. Blockers are numbered
+B1, B2, ... and stay open until they are resolved.
+
+Subcommands:
+  add <description>       Raise a blocker or open question
+  resolve <id>            Resolve a blocker or question
+  list                    List open blockers and questions (--all for resolved ones too)
+
+Examples:
+  agentpm blocker add "Waiting for API credentials" --on 2A_1
+  agentpm blocker add "Which API version do we target?" --question
+  agentpm blocker resolve B1 --resolution "Credentials arrived"
+  agentpm blocker list --all`,
+		Flags: commands.GlobalFlags(),
+		Commands: []*cli.Command{
+			{
+				Name:      "add",
+				Usage:     "Raise a blocker or open question",
+				ArgsUsage: "<description>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "on",
+						Usage: "Phase or task the blocker holds up (default: the whole epic)",
+					},
+					&cli.BoolFlag{
+						Name:  "question",
+						Usage: "Record an open question instead of a blocker",
+					},
+				},
+				Action: withQuietResult(blockerAddAction),
+			},
+			{
+				Name:      "resolve",
+				Usage:     "Resolve a blocker or open question",
+				ArgsUsage: "<blocker-id>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "resolution",
+						Usage: "How it was resolved (recorded on the blocker and in the event log)",
+					},
+				},
+				Action: withQuietResult(blockerResolveAction),
+			},
+			{
+				Name:  "list",
+				Usage: "List open blockers and questions",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "all",
+						Usage: "Include resolved blockers",
+					},
+				},
+				Action: blockerListAction,
+			},
+		},
+	}
+}
+
+// blockerJSON is the JSON form of a blocker
+type blockerJSON struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	Entity      string `json:"entity,omitempty"`
+	Description string `json:"description"`
+	RaisedAt    string `json:"raised_at"`
+	RaisedBy    string `json:"raised_by,omitempty"`
+	Resolved    bool   `json:"resolved"`
+	ResolvedAt  string `json:"resolved_at,omitempty"`
+	Resolution  string `json:"resolution,omitempty"`
+}
+
+func newBlockerJSON(blocker *epic.Blocker) blockerJSON {
+	output := blockerJSON{
+		ID:          blocker.ID,
+		Type:        blocker.Kind(),
+		Entity:      blocker.Entity,
+		Description: blocker.Description,
+		RaisedAt:    blocker.RaisedAt.Format(time.RFC3339),
+		RaisedBy:    blocker.RaisedBy,
+		Resolved:    blocker.IsResolved(),
+		Resolution:  blocker.Resolution,
+	}
+	if blocker.IsResolved() {
+		output.ResolvedAt = blocker.ResolvedAt.Format(time.RFC3339)
+	}
+	return output
+}
+
+func blockerAddAction(ctx context.Context, c *cli.Command) error {
+	if c.Args().Len() == 0 {
+		return exitcode.Errorf(exitcode.Validation, "blocker add requires a description")
+	}
+	description := strings.Join(c.Args().Slice(), " ")
+
+	return updateBlockers(c, func(epicData *epic.Epic, timestamp time.Time) (*epic.Blocker, error) {
+		return service.RaiseBlocker(epicData, description, c.String("on"), c.Bool("question"), timestamp)
+	})
+}
+
+func blockerResolveAction(ctx context.Context, c *cli.Command) error {
+	if c.Args().Len() != 1 {
+		return exitcode.Errorf(exitcode.Validation, "blocker resolve requires exactly one blocker ID")
+	}
+	id := c.Args().First()
+
+	return updateBlockers(c, func(epicData *epic.Epic, timestamp time.Time) (*epic.Blocker, error) {
+		return service.ResolveBlocker(epicData, id, c.String("resolution"), timestamp)
+	})
+}
+
+// updateBlockers loads the epic, applies a change to its blockers, saves it
+// and reports the changed blocker
+func updateBlockers(c *cli.Command, change func(*epic.Epic, time.Time) (*epic.Blocker, error)) error {
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now()
+	if timeStr := c.String("time"); timeStr != "" {
+		timestamp, err = time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+		}
+	}
+
+	storageImpl := storage.NewFileStorage()
+	epicData, err := storageImpl.LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	blocker, err := change(epicData, timestamp)
+	if err != nil {
+		return err
+	}
+
+	if err := storageImpl.SaveEpic(epicData, epicFile); err != nil {
+		return fmt.Errorf("failed to save epic: %w", err)
+	}
+	return writeBlockerResult(c, blocker)
+}
+
+func writeBlockerResult(c *cli.Command, blocker *epic.Blocker) error {
+	operation := "blocker_raised"
+	if blocker.IsResolved() {
+		operation = "blocker_resolved"
+	}
+
+	switch c.String("format") {
+	case "json":
+		output := struct {
+			Operation string `json:"operation"`
+			blockerJSON
+		}{operation, newBlockerJSON(blocker)}
+		jsonData, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal result to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		writeBlockerElement(doc.CreateElement(operation), blocker)
+		doc.Indent(4)
+		doc.WriteTo(c.Root().Writer)
+	default:
+		label := "Blocker"
+		if blocker.IsQuestion() {
+			label = "Question"
+		}
+		if blocker.IsResolved() {
+			fmt.Fprintf(c.Root().Writer, "%s %s resolved.\n", label, blocker.ID)
+		} else {
+			fmt.Fprintf(c.Root().Writer, "%s %s raised: %s\n", label, blocker.ID, blocker.Description)
+		}
+	}
+	return nil
+}
+
+func blockerListAction(ctx context.Context, c *cli.Command) error {
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+
+	epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	blockers := epicData.OpenBlockers()
+	if c.Bool("all") {
+		blockers = epicData.Blockers
+	}
+
+	switch c.String("format") {
+	case "json":
+		output := make([]blockerJSON, len(blockers))
+		for i := range blockers {
+			output[i] = newBlockerJSON(&blockers[i])
+		}
+		jsonData, err := json.MarshalIndent(map[string]interface{}{
+			"epic_id":  epicData.ID,
+			"blockers": output,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal blockers to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("blockers")
+		root.CreateAttr("epic", epicData.ID)
+		for i := range blockers {
+			writeBlockerElement(root.CreateElement("blocker"), &blockers[i])
+		}
+		doc.Indent(4)
+		doc.WriteTo(c.Root().Writer)
+	default:
+		if len(blockers) == 0 {
+			fmt.Fprintf(c.Root().Writer, "No open blockers or questions in epic %s.\n", epicData.ID)
+			return nil
+		}
+		for _, blocker := range blockers {
+			state := "open"
+			if blocker.IsResolved() {
+				state = "resolved"
+			}
+			line := fmt.Sprintf("%-4s %-8s %-8s", blocker.ID, blocker.Kind(), state)
+			if blocker.Entity != "" {
+				line += fmt.Sprintf(" [%s]", blocker.Entity)
+			}
+			fmt.Fprintf(c.Root().Writer, "%s %s\n", line, blocker.Description)
+		}
+	}
+	return nil
+}
+
+// writeBlockerElement fills elem with the attributes and text of a blocker
+func writeBlockerElement(elem *etree.Element, blocker *epic.Blocker) {
+	elem.CreateAttr("id", blocker.ID)
+	elem.CreateAttr("type", blocker.Kind())
+	if blocker.Entity != "" {
+		elem.CreateAttr("entity", blocker.Entity)
+	}
+	elem.CreateAttr("raised_at", blocker.RaisedAt.Format(time.RFC3339))
+	if blocker.RaisedBy != "" {
+		elem.CreateAttr("raised_by", blocker.RaisedBy)
+	}
+	if blocker.IsResolved() {
+		elem.CreateAttr("resolved_at", blocker.ResolvedAt.Format(time.RFC3339))
+	}
+	elem.CreateElement("description").SetText(blocker.Description)
+	if blocker.Resolution != "" {
+		elem.CreateElement("resolution").SetText(blocker.Resolution)
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockerCommand(t *testing.T) {
+	run := func(t *testing.T, epicFile string, args ...string) (string, error) {
+		var stdout, stderr bytes.Buffer
+		command := BlockerCommand()
+		command.Root().Writer = &stdout
+		command.Root().ErrWriter = &stderr
+		err := command.Run(context.Background(), append([]string{"blocker", "--file", epicFile, "--time", "2025-08-16T15:30:00Z"}, args...))
+		return stdout.String(), err
+	}
+
+	epicFile := filepath.Join(t.TempDir(), "epic.xml")
+	fileStorage := storage.NewFileStorage()
+	require.NoError(t, fileStorage.SaveEpic(createEpicForReset(), epicFile))
+
+	t.Run("add requires a description", func(t *testing.T) {
+		_, err := run(t, epicFile, "add")
+		require.Error(t, err)
+		assert.Equal(t, exitcode.Validation, ExitCode(err))
+	})
+
+	t.Run("add rejects unknown entities", func(t *testing.T) {
+		_, err := run(t, epicFile, "add", "--on", "T9", "Blocked")
+		require.Error(t, err)
+		assert.Equal(t, exitcode.NotFound, ExitCode(err))
+	})
+
+	t.Run("adds a blocker", func(t *testing.T) {
+		output, err := run(t, epicFile, "add", "--on", "T2", "Waiting for", "API credentials")
+		require.NoError(t, err)
+		assert.Equal(t, "Blocker B1 raised: Waiting for API credentials\n", output)
+
+		updated, err := fileStorage.LoadEpic(epicFile)
+		require.NoError(t, err)
+		require.Len(t, updated.Blockers, 1)
+		assert.Equal(t, "T2", updated.Blockers[0].Entity)
+		assert.Equal(t, time.Date(2025, 8, 16, 15, 30, 0, 0, time.UTC), updated.Blockers[0].RaisedAt)
+		assert.Equal(t, "blocker_raised", updated.Events[len(updated.Events)-1].Type)
+	})
+
+	t.Run("adds a question", func(t *testing.T) {
+		output, err := run(t, epicFile, "--format", "json", "add", "--question", "Which API version?")
+		require.NoError(t, err)
+		assert.Contains(t, output, `"operation": "blocker_raised"`)
+		assert.Contains(t, output, `"id": "B2"`)
+		assert.Contains(t, output, `"type": "question"`)
+	})
+
+	t.Run("resolves a blocker", func(t *testing.T) {
+		output, err := run(t, epicFile, "resolve", "--resolution", "Credentials arrived", "B1")
+		require.NoError(t, err)
+		assert.Equal(t, "Blocker B1 resolved.\n", output)
+
+		updated, err := fileStorage.LoadEpic(epicFile)
+		require.NoError(t, err)
+		require.True(t, updated.Blockers[0].IsResolved())
+		assert.Equal(t, "Credentials arrived", updated.Blockers[0].Resolution)
+	})
+
+	t.Run("resolving twice is rejected", func(t *testing.T) {
+		_, err := run(t, epicFile, "resolve", "B1")
+		require.Error(t, err)
+		assert.Equal(t, exitcode.Constraint, ExitCode(err))
+	})
+
+	t.Run("resolving an unknown blocker", func(t *testing.T) {
+		_, err := run(t, epicFile, "resolve", "B7")
+		require.Error(t, err)
+		assert.Equal(t, exitcode.NotFound, ExitCode(err))
+	})
+
+	t.Run("lists open blockers", func(t *testing.T) {
+		output, err := run(t, epicFile, "list")
+		require.NoError(t, err)
+		assert.Equal(t, "B2   question open     Which API version?\n", output)
+	})
+
+	t.Run("lists all blockers as XML", func(t *testing.T) {
+		output, err := run(t, epicFile, "--format", "xml", "list", "--all")
+		require.NoError(t, err)
+		assert.Contains(t, output, `<blocker id="B1" type="blocker" entity="T2" raised_at="2025-08-16T15:30:00Z" resolved_at="2025-08-16T15:30:00Z">`)
+		assert.Contains(t, output, `<resolution>Credentials arrived</resolution>`)
+		assert.Contains(t, output, `<blocker id="B2" type="question"`)
+	})
+}
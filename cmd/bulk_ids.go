@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+)
+
+// fromFileFlag is the --from-file flag of commands that accept several IDs
+func fromFileFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "from-file",
+		Usage: "Read further IDs from a file, one per line (- for stdin, # starts a comment)",
+	}
+}
+
+// idsFromFile returns the IDs listed in the --from-file file, if given
+func idsFromFile(c *cli.Command) ([]string, error) {
+	path := c.String("from-file")
+	if path == "" {
+		return nil, nil
+	}
+
+	r := stdin(c)
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open ID file: %w", err)
+		}
+		defer file.Close()
+		r = file
+	}
+	ids, err := readIDList(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ID file: %w", err)
+	}
+	return ids, nil
+}
+
+// uniqueIDs drops repeated IDs, keeping the first occurrence
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	var unique []string
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// readIDList reads whitespace-separated IDs, skipping blank lines and # comments
+func readIDList(r io.Reader) ([]string, error) {
+	var ids []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		ids = append(ids, strings.Fields(line)...)
+	}
+	return ids, scanner.Err()
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var bulkApp = appOptions{commands: []func() *cli.Command{PassCommand, FailCommand, DoneCommand}}
+
+func createBulkEpic(t *testing.T) string {
+	t.Helper()
+	created := time.Date(2025, 8, 1, 9, 0, 0, 0, time.UTC)
+	e := &epic.Epic{
+		ID:        "bulk",
+		Name:      "Bulk",
+		Status:    epic.StatusWIP,
+		CreatedAt: created,
+		CurrentState: &epic.CurrentState{
+			ActivePhase: "P1",
+			ActiveTask:  "P1_1",
+		},
+		Phases: []epic.Phase{{ID: "P1", Name: "Phase 1", Status: epic.StatusWIP}},
+		Tasks: []epic.Task{
+			{ID: "P1_1", PhaseID: "P1", Name: "Task 1", Status: epic.StatusWIP},
+			{ID: "P1_2", PhaseID: "P1", Name: "Task 2", Status: epic.StatusWIP},
+			{ID: "P1_3", PhaseID: "P1", Name: "Task 3", Status: epic.StatusCompleted},
+			{ID: "P1_4", PhaseID: "P1", Name: "Task 4", Status: epic.StatusPending},
+		},
+	}
+	for _, id := range []string{"T1", "T2", "T3"} {
+		e.Tests = append(e.Tests, epic.Test{
+			ID: id, TaskID: "P1_1", PhaseID: "P1", Name: "Test " + id,
+			Status: epic.StatusWIP, TestStatus: epic.TestStatusWIP,
+		})
+	}
+	e.Tests = append(e.Tests, epic.Test{
+		ID: "T4", TaskID: "P1_1", PhaseID: "P1", Name: "Test T4",
+		Status: epic.StatusPending, TestStatus: epic.TestStatusPending,
+	})
+
+	epicFile := filepath.Join(t.TempDir(), "epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(e, epicFile))
+	return epicFile
+}
+
+func loadBulkTests(t *testing.T, epicFile string) map[string]epic.Test {
+	t.Helper()
+	e, err := storage.NewFileStorage().LoadEpic(epicFile)
+	require.NoError(t, err)
+	tests := make(map[string]epic.Test)
+	for _, test := range e.Tests {
+		tests[test.ID] = test
+	}
+	return tests
+}
+
+func TestReadIDList(t *testing.T) {
+	ids, err := readIDList(strings.NewReader("T1\n\n  T2 T3  # passed in run 12\n# T4\nT5\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"T1", "T2", "T3", "T5"}, ids)
+
+	assert.Equal(t, []string{"T1", "T2"}, uniqueIDs([]string{"T1", "T2", "T1"}))
+}
+
+func TestPassCommand_MultipleTests(t *testing.T) {
+	t.Run("passes all tests in one save", func(t *testing.T) {
+		epicFile := createBulkEpic(t)
+		output, err := runApp(t, bulkApp.withStdin(""), "pass", "--file", epicFile, "T1", "T2", "T3")
+		require.NoError(t, err)
+		assert.Equal(t, "Test T1 passed.\nTest T2 passed.\nTest T3 passed.\n", output)
+
+		tests := loadBulkTests(t, epicFile)
+		for _, id := range []string{"T1", "T2", "T3"} {
+			assert.Equal(t, epic.TestStatusDone, tests[id].TestStatus, id)
+		}
+	})
+
+	t.Run("reads IDs from a file and stdin", func(t *testing.T) {
+		epicFile := createBulkEpic(t)
+		idFile := filepath.Join(t.TempDir(), "passed.txt")
+		require.NoError(t, os.WriteFile(idFile, []byte("# nightly run\nT2\nT3\n"), 0644))
+
+		output, err := runApp(t, bulkApp.withStdin(""), "pass", "--file", epicFile, "--from-file", idFile, "T1")
+		require.NoError(t, err)
+		assert.Equal(t, "Test T1 passed.\nTest T2 passed.\nTest T3 passed.\n", output)
+
+		epicFile = createBulkEpic(t)
+		_, err = runApp(t, bulkApp.withStdin("T1 T3\n"), "pass", "--file", epicFile, "--from-file", "-")
+		require.NoError(t, err)
+		tests := loadBulkTests(t, epicFile)
+		assert.Equal(t, epic.TestStatusDone, tests["T1"].TestStatus)
+		assert.Equal(t, epic.TestStatusWIP, tests["T2"].TestStatus)
+		assert.Equal(t, epic.TestStatusDone, tests["T3"].TestStatus)
+	})
+
+	t.Run("changes nothing when any test cannot be passed", func(t *testing.T) {
+		epicFile := createBulkEpic(t)
+		before, err := os.ReadFile(epicFile)
+		require.NoError(t, err)
+
+		_, err = runApp(t, bulkApp.withStdin(""), "pass", "--file", epicFile, "T1", "T4", "T9")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "2 of 3 operations are invalid")
+		assert.Contains(t, err.Error(), "- T4 (pass)")
+		assert.Contains(t, err.Error(), "- T9 (pass): Test T9 not found")
+
+		after, err := os.ReadFile(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, string(before), string(after))
+	})
+}
+
+func TestFailCommand_MultipleTests(t *testing.T) {
+	t.Run("trailing reason", func(t *testing.T) {
+		epicFile := createBulkEpic(t)
+		output, err := runApp(t, bulkApp.withStdin(""), "fail", "--file", epicFile, "T1", "T2", "Database down")
+		require.NoError(t, err)
+		assert.Equal(t, "Test T1 failed: Database down\nTest T2 failed: Database down\n", output)
+
+		tests := loadBulkTests(t, epicFile)
+		assert.Equal(t, "Database down", tests["T1"].FailureNote)
+		assert.Equal(t, "Database down", tests["T2"].FailureNote)
+		assert.Equal(t, epic.TestStatusWIP, tests["T3"].TestStatus)
+		assert.Empty(t, tests["T3"].FailureNote)
+	})
+
+	t.Run("test IDs only", func(t *testing.T) {
+		epicFile := createBulkEpic(t)
+		output, err := runApp(t, bulkApp.withStdin(""), "fail", "--file", epicFile, "T1", "T2")
+		require.NoError(t, err)
+		assert.Equal(t, "Test T1 failed.\nTest T2 failed.\n", output)
+	})
+
+	t.Run("reason flag", func(t *testing.T) {
+		epicFile := createBulkEpic(t)
+		output, err := runApp(t, bulkApp.withStdin("T2\nT3\n"), "fail", "--file", epicFile, "--from-file", "-", "--reason", "Timeout", "T1")
+		require.NoError(t, err)
+		assert.Equal(t, "Test T2 failed: Timeout\nTest T3 failed: Timeout\nTest T1 failed: Timeout\n", output)
+	})
+}
+
+func TestDoneTaskCommand_MultipleTasks(t *testing.T) {
+	t.Run("completes all tasks", func(t *testing.T) {
+		epicFile := createBulkEpic(t)
+		output, err := runApp(t, bulkApp.withStdin(""), "done", "task", "--file", epicFile, "P1_1", "P1_2", "P1_3")
+		require.NoError(t, err)
+		assert.Equal(t, "Task P1_1 completed.\nTask P1_2 completed.\nTask P1_3 already completed.\n", output)
+
+		e, err := storage.NewFileStorage().LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, epic.StatusCompleted, e.Tasks[0].Status)
+		assert.Equal(t, epic.StatusCompleted, e.Tasks[1].Status)
+		assert.Empty(t, e.CurrentState.ActiveTask)
+	})
+
+	t.Run("changes nothing when any task cannot be completed", func(t *testing.T) {
+		epicFile := createBulkEpic(t)
+		before, err := os.ReadFile(epicFile)
+		require.NoError(t, err)
+
+		_, err = runApp(t, bulkApp.withStdin(""), "done", "task", "--file", epicFile, "P1_1", "P1_4", "P1_9")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Cannot complete 2 of 3 tasks, no task was changed")
+		assert.Contains(t, err.Error(), "- P1_4: ")
+		assert.Contains(t, err.Error(), "- P1_9: ")
+
+		after, err := os.ReadFile(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, string(before), string(after))
+	})
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/burndown"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// BurndownCommand reports remaining work over time, reconstructed from the event history
+func BurndownCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "burndown",
+		Usage: "Show remaining tasks and tests over time",
+		Description: `Reconstruct how many tasks and tests were still open at the end of each
+day or week, by replaying the epic's event history (completions, cancellations,
+failures and resets). The scope is the current set of tasks and tests.
+
+Text output adds a sparkline; csv and json are meant for charting tools.
+
+Examples:
+  agentpm burndown                             # Daily series with sparklines
+  agentpm burndown --interval week --format csv > burndown.csv`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config",
+			},
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"F"},
+				Usage:   "Output format: text (default), csv, json, xml",
+				Value:   "text",
+			},
+			&cli.StringFlag{
+				Name:  "interval",
+				Usage: "Bucket size: day or week",
+				Value: string(burndown.IntervalDay),
+			},
+		},
+		Action: burndownAction,
+	}
+}
+
+func burndownAction(ctx context.Context, c *cli.Command) error {
+	interval, err := burndown.ParseInterval(c.String("interval"))
+	if err != nil {
+		return err
+	}
+
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if timeStr := c.String("time"); timeStr != "" {
+		now, err = time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+		}
+	}
+
+	epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	series := burndown.Build(epicData, interval, now)
+
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(series, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal burndown to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "csv":
+		return outputBurndownCSV(c, series)
+	case "xml":
+		outputBurndownXML(c, series)
+	default:
+		outputBurndownText(c, series)
+	}
+	return nil
+}
+
+func outputBurndownText(c *cli.Command, series *burndown.Series) {
+	w := c.Root().Writer
+
+	var tasks, tests []int
+	for _, point := range series.Points {
+		tasks = append(tasks, point.RemainingTasks)
+		tests = append(tests, point.RemainingTests)
+	}
+
+	fmt.Fprintf(w, "Burn-down for epic %s (%s), per %s\n\n", series.EpicID, series.EpicName, series.Interval)
+	fmt.Fprintf(w, "Tasks: %s  %d of %d remaining\n", burndown.Sparkline(tasks), tasks[len(tasks)-1], series.TotalTasks)
+	fmt.Fprintf(w, "Tests: %s  %d of %d remaining\n\n", burndown.Sparkline(tests), tests[len(tests)-1], series.TotalTests)
+
+	fmt.Fprintf(w, "%-10s  %5s  %5s\n", "date", "tasks", "tests")
+	for _, point := range series.Points {
+		fmt.Fprintf(w, "%-10s  %5d  %5d\n", point.Date, point.RemainingTasks, point.RemainingTests)
+	}
+}
+
+func outputBurndownCSV(c *cli.Command, series *burndown.Series) error {
+	writer := csv.NewWriter(c.Root().Writer)
+	writer.Write([]string{"date", "remaining_tasks", "remaining_tests"})
+	for _, point := range series.Points {
+		writer.Write([]string{point.Date, strconv.Itoa(point.RemainingTasks), strconv.Itoa(point.RemainingTests)})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func outputBurndownXML(c *cli.Command, series *burndown.Series) {
+	doc := etree.NewDocument()
+	root := doc.CreateElement("burndown")
+	root.CreateAttr("epic", series.EpicID)
+	root.CreateAttr("interval", string(series.Interval))
+	root.CreateAttr("total_tasks", strconv.Itoa(series.TotalTasks))
+	root.CreateAttr("total_tests", strconv.Itoa(series.TotalTests))
+
+	for _, point := range series.Points {
+		pointElem := root.CreateElement("point")
+		pointElem.CreateAttr("date", point.Date)
+		pointElem.CreateAttr("remaining_tasks", strconv.Itoa(point.RemainingTasks))
+		pointElem.CreateAttr("remaining_tests", strconv.Itoa(point.RemainingTests))
+	}
+
+	doc.Indent(4)
+	doc.WriteTo(c.Root().Writer)
+}
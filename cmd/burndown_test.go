@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/burndown"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var burndownApp = appOptions{commands: []func() *cli.Command{BurndownCommand}, time: "2025-08-14T12:00:00Z"}
+
+func TestBurndownCommand(t *testing.T) {
+	epicFile := filepath.Join(t.TempDir(), "epic.xml")
+	testEpic := createEpicForReset()
+	testEpic.CreatedAt = time.Date(2025, 8, 12, 9, 0, 0, 0, time.UTC)
+	service.CreateEvent(testEpic, service.EventTestPassed, "P1", "T1", "T1_T1", "", time.Date(2025, 8, 13, 10, 0, 0, 0, time.UTC))
+	service.CreateEvent(testEpic, service.EventTaskCompleted, "P1", "T1", "", "", time.Date(2025, 8, 13, 11, 0, 0, 0, time.UTC))
+	testEpic.Tasks[0].Status = epic.StatusCompleted
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicFile))
+
+	t.Run("text output with sparklines", func(t *testing.T) {
+		output, err := runApp(t, burndownApp, "burndown", "--file", epicFile)
+		require.NoError(t, err)
+		assert.Contains(t, output, "Burn-down for epic epic-1 (Test Epic), per day")
+		assert.Contains(t, output, "Tasks: █▄▄  1 of 2 remaining")
+		assert.Contains(t, output, "2025-08-12      2      2\n")
+		assert.Contains(t, output, "2025-08-14      1      1\n")
+	})
+
+	t.Run("csv output", func(t *testing.T) {
+		output, err := runApp(t, burndownApp, "burndown", "--file", epicFile, "--format", "csv")
+		require.NoError(t, err)
+		assert.Equal(t, "date,remaining_tasks,remaining_tests\n2025-08-12,2,2\n2025-08-13,1,1\n2025-08-14,1,1\n", output)
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		output, err := runApp(t, burndownApp, "burndown", "--file", epicFile, "--format", "json", "--interval", "week")
+		require.NoError(t, err)
+
+		var series burndown.Series
+		require.NoError(t, json.Unmarshal([]byte(output), &series))
+		assert.Equal(t, burndown.IntervalWeek, series.Interval)
+		assert.Equal(t, []burndown.Point{{Date: "2025-08-11", RemainingTasks: 1, RemainingTests: 1}}, series.Points)
+	})
+
+	t.Run("rejects unknown intervals", func(t *testing.T) {
+		_, err := runApp(t, burndownApp, "burndown", "--file", epicFile, "--interval", "month")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid interval: month")
+	})
+}
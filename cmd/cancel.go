@@ -1,9 +1,18 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
+	"time"
 
+	"github.com/beevik/etree"
 	"github.com/mindreframer/agentpm/internal/commands"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/phases"
 	"github.com/urfave/cli/v3"
 )
 
@@ -17,17 +26,57 @@ Subcommands:
   task <id> [reason]     Cancel specific task
   test <id> [reason]     Cancel specific test
 
+Bulk cancellation:
+  --all-pending-in-phase <id> --confirm   Cancel every pending task and test of a phase
+                                          (without --confirm only a preview is shown)
+
+A cancelled task never satisfies a dependency. Both forms list the pending
+tasks that can no longer start because they depend on a cancelled task,
+directly or through other such tasks; the preview lists them before anything
+changes, and json/xml results carry them as "stranded".
+
 Examples:
   agentpm cancel task 3A_1 "No longer needed"   # Cancel task with reason
-  agentpm cancel test 3A_T1 "Test obsolete"     # Cancel test with reason`,
-		Flags: commands.GlobalFlags(),
+  agentpm cancel test 3A_T1 "Test obsolete"     # Cancel test with reason
+  agentpm cancel --all-pending-in-phase 3A --confirm --reason "Descoped"`,
+		Flags: append(commands.GlobalFlags(),
+			&cli.StringFlag{
+				Name:  "all-pending-in-phase",
+				Usage: "Cancel all pending tasks and tests of the given phase",
+			},
+			&cli.BoolFlag{
+				Name:  "confirm",
+				Usage: "Apply the bulk cancellation (without it only a preview is shown)",
+			},
+			&cli.StringFlag{
+				Name:  "reason",
+				Usage: "Cancellation reason recorded for bulk cancellation",
+			},
+		),
 		Commands: []*cli.Command{
 			cancelTaskSubcommand(),
 			cancelTestSubcommand(),
 		},
+		Action: withQuietResult(cancelPendingInPhaseAction),
 	}
 }
 
+// cancelPendingInPhaseAction handles 'cancel --all-pending-in-phase <id>'
+func cancelPendingInPhaseAction(ctx context.Context, c *cli.Command) error {
+	phaseID := c.String("all-pending-in-phase")
+	if phaseID == "" {
+		return exitcode.Errorf(exitcode.Validation, "specify a subcommand (task, test) or --all-pending-in-phase <phase-id>")
+	}
+
+	return runPhaseBulkOperation(c, phaseID,
+		func(ps *phases.PhaseService, e *epic.Epic) (*phases.PhaseBulkResult, error) {
+			return ps.PreviewCancelPendingInPhase(e, phaseID)
+		},
+		func(ps *phases.PhaseService, e *epic.Epic, reason string, ts time.Time) (*phases.PhaseBulkResult, error) {
+			return ps.CancelPendingInPhase(e, phaseID, reason, ts)
+		})
+}
+
 func cancelTaskSubcommand() *cli.Command {
 	return &cli.Command{
 		Name:      "task",
@@ -37,7 +86,7 @@ func cancelTaskSubcommand() *cli.Command {
 
 The task must exist in the current epic and be in a valid state to cancel.
 The cancellation reason is optional but recommended for tracking purposes.`,
-		Action: commands.CreateEntityAction(commands.EntityTypeTask, handleCancelTask),
+		Action: withQuietResult(commands.CreateEntityAction(commands.EntityTypeTask, handleCancelTask)),
 	}
 }
 
@@ -50,7 +99,7 @@ func cancelTestSubcommand() *cli.Command {
 
 The test must exist in the current epic and be in a valid state to cancel.
 The cancellation reason is optional but recommended for tracking purposes.`,
-		Action: commands.CreateEntityAction(commands.EntityTypeTest, handleCancelTest),
+		Action: withQuietResult(commands.CreateEntityAction(commands.EntityTypeTest, handleCancelTest)),
 	}
 }
 
@@ -73,11 +122,30 @@ func handleCancelTask(ctx commands.RouterContext, taskID string) error {
 	}
 
 	if result.Error != nil {
-		return fmt.Errorf("%s", result.Error.Message)
+		return serviceError(result.Error.Type, result.Error.Message, "")
 	}
 
-	// Output success message
-	fmt.Printf("Task %s cancelled.\n", taskID)
+	switch ctx.Format {
+	case "json":
+		ctx.RecordWarnings(result.Warnings)
+		jsonData, err := json.MarshalIndent(map[string]interface{}{
+			"task_id":   taskID,
+			"operation": "task_cancelled",
+			"stranded":  strandedOrEmpty(result.Stranded),
+			"warnings":  warningsOrEmpty(result.Warnings),
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal result to JSON: %w", err)
+		}
+		fmt.Fprintf(ctx.Writer, "%s\n", jsonData)
+	case "xml":
+		ctx.RecordWarnings(result.Warnings)
+		fmt.Fprintf(ctx.Writer, "<task_cancelled task=\"%s\">\n%s%s\n</task_cancelled>\n", taskID, strandedXML(result.Stranded), warningsXML(result.Warnings))
+	default:
+		fmt.Fprintf(ctx.Writer, "Task %s cancelled.\n", taskID)
+		writeStrandedWarning(ctx.Writer, result.Stranded)
+		return ctx.WriteWarnings(result.Warnings)
+	}
 	return nil
 }
 
@@ -99,13 +167,75 @@ func handleCancelTest(ctx commands.RouterContext, testID string) error {
 	}
 
 	if result.Error != nil {
-		return fmt.Errorf("%s", result.Error.Message)
+		return serviceError(result.Error.Type, result.Error.Message, "")
 	}
 
 	// Output success message based on result
 	if result.Result != nil {
-		fmt.Printf("Test %s cancelled.\n", testID)
+		fmt.Fprintf(ctx.Writer, "Test %s cancelled.\n", testID)
 	}
 
-	return nil
+	return ctx.WriteWarnings(result.Warnings)
+}
+
+// formatStranded lists stranded tasks with the dependency blocking each
+func formatStranded(stranded []epic.StrandedTask) string {
+	items := make([]string, 0, len(stranded))
+	for _, task := range stranded {
+		items = append(items, fmt.Sprintf("%s (needs %s)", task.ID, task.Via))
+	}
+	return strings.Join(items, ", ")
+}
+
+// writeStrandedWarning tells that a cancellation left later work unable to start
+func writeStrandedWarning(w io.Writer, stranded []epic.StrandedTask) {
+	if len(stranded) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "Warning: %d pending task(s) can no longer start: %s\n", len(stranded), formatStranded(stranded))
+	fmt.Fprintf(w, "Hint: cancel them as well, or drop the cancelled task from their depends_on\n")
+}
+
+// strandedOrEmpty keeps the stranded list of JSON results an array
+func strandedOrEmpty(stranded []epic.StrandedTask) []epic.StrandedTask {
+	if stranded == nil {
+		return []epic.StrandedTask{}
+	}
+	return stranded
+}
+
+// strandedXML is the <stranded> element of XML results, indented by 4
+func strandedXML(stranded []epic.StrandedTask) string {
+	if len(stranded) == 0 {
+		return `    <stranded count="0"/>`
+	}
+	output := fmt.Sprintf(`    <stranded count="%d">`, len(stranded))
+	for _, task := range stranded {
+		output += fmt.Sprintf(`
+        <task id="%s" phase_id="%s" via="%s"/>`, task.ID, task.PhaseID, task.Via)
+	}
+	return output + `
+    </stranded>`
+}
+
+// warningsOrEmpty keeps the warnings list of JSON results an array
+func warningsOrEmpty(warnings []commands.Warning) []commands.Warning {
+	if warnings == nil {
+		return []commands.Warning{}
+	}
+	return warnings
+}
+
+// warningsXML is the <warnings> element of XML results on its own line,
+// indented by 4, or nothing without warnings
+func warningsXML(warnings []commands.Warning) string {
+	if len(warnings) == 0 {
+		return ""
+	}
+	doc := etree.NewDocument()
+	commands.WarningsXML(doc.CreateElement("warnings"), warnings)
+	doc.Indent(4)
+	output, _ := doc.WriteToString()
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	return "\n    " + strings.Join(lines, "\n    ")
 }
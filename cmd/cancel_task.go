@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/exitcode"
 	"github.com/mindreframer/agentpm/internal/query"
 	"github.com/mindreframer/agentpm/internal/storage"
 	"github.com/mindreframer/agentpm/internal/tasks"
@@ -41,11 +42,11 @@ func CancelTaskCommand() *cli.Command {
 				if err != nil {
 					return fmt.Errorf("failed to load configuration: %w", err)
 				}
-				epicFile = cfg.CurrentEpic
+				epicFile = cfg.EpicFilePath()
 			}
 
 			if epicFile == "" {
-				return fmt.Errorf("no epic file specified (use --file flag or set current epic)")
+				return exitcode.Errorf(exitcode.Validation, "no epic file specified (use --file flag or set current epic)")
 			}
 
 			// Parse timestamp if provided
@@ -96,6 +97,7 @@ func CancelTaskCommand() *cli.Command {
 
 			// Output simple confirmation message
 			fmt.Fprintf(cmd.Writer, "Task %s cancelled.\n", taskID)
+			writeStrandedWarning(cmd.Writer, epicData.StrandedTasks([]string{taskID}))
 			return nil
 		},
 	}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"testing"
 
 	"github.com/urfave/cli/v3"
@@ -44,9 +45,9 @@ func TestCancelCommand_Structure(t *testing.T) {
 		t.Error("expected global flags to be present")
 	}
 
-	// Test that no action function exists (commands require subcommands)
-	if cmd.Action != nil {
-		t.Error("cancel command should not have action function - requires subcommands")
+	// The root action handles --all-pending-in-phase bulk cancellation
+	if cmd.Action == nil {
+		t.Error("cancel command should have an action function for --all-pending-in-phase")
 	}
 }
 
@@ -109,9 +110,10 @@ func TestCancelTestSubcommand_Structure(t *testing.T) {
 func TestCancelCommand_ExplicitSubcommands(t *testing.T) {
 	cmd := CancelCommand()
 
-	// Test that the command requires explicit subcommands (no auto-detection)
-	if cmd.Action != nil {
-		t.Error("cancel command should not have action - requires explicit subcommands")
+	// Test that the command requires explicit subcommands (no auto-detection);
+	// the root action only serves the explicit --all-pending-in-phase flag
+	if err := cmd.Action(context.Background(), cmd); err == nil {
+		t.Error("cancel command without subcommand or --all-pending-in-phase should fail")
 	}
 
 	// Test that command structure supports explicit entity types
@@ -174,9 +176,9 @@ func TestCancelCommand_Subcommand_Actions_NotNil(t *testing.T) {
 		}
 	}
 
-	// Test that main command has no fallback action (requires explicit subcommands)
-	if cmd.Action != nil {
-		t.Error("main cancel command should not have fallback action - requires explicit subcommands")
+	// Test that main command has no fallback behaviour (requires explicit subcommands or flag)
+	if err := cmd.Action(context.Background(), cmd); err == nil {
+		t.Error("main cancel command should not fall back to auto-detection - requires explicit subcommands")
 	}
 }
 
@@ -196,7 +198,7 @@ func TestCancelCommand_ValidIDs_Structure(t *testing.T) {
 	cmd := CancelCommand()
 
 	// Validate command requires explicit entity types
-	if cmd.Action != nil {
+	if err := cmd.Action(context.Background(), cmd); err == nil {
 		t.Error("command should not support auto-detection - requires explicit entity types")
 	}
 
@@ -234,8 +236,8 @@ func TestCancelCommand_Integration_Structure(t *testing.T) {
 		t.Error("cancel command should have global flags")
 	}
 
-	// Validate it has no action (requires explicit subcommands)
-	if cmd.Action != nil {
-		t.Error("cancel command should not have action function - requires explicit subcommands")
+	// Validate the root action refuses to run without an explicit target
+	if err := cmd.Action(context.Background(), cmd); err == nil {
+		t.Error("cancel command should require explicit subcommands or --all-pending-in-phase")
 	}
 }
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// compactResult is the JSON output of compact
+type compactResult struct {
+	Epic     string                `json:"epic"`
+	Before   time.Time             `json:"before"`
+	DryRun   bool                  `json:"dry_run"`
+	Summary  *service.EventSummary `json:"summary,omitempty"`
+	Archived string                `json:"archived,omitempty"`
+}
+
+// CompactCommand collapses the old events of an epic into a summary event
+func CompactCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "compact",
+		Usage: "Collapse events older than a cutoff into a summary event",
+		Description: `Keep the event list of a long-running epic from growing without bounds:
+all events before the cutoff are replaced by one events_compacted event that
+counts them by type. Summaries of earlier compactions and of
+'agentpm summarize-events' are kept as they are.
+
+The epic file as it was is copied to the archive directory first (archive_dir,
+default .agentpm/archive), e.g. as epic-8.20250816T120000Z.xml, so nothing is
+lost. Burn-down, velocity and cycle times of the compacted period fall back to
+the timestamps on the tasks and tests.
+
+--before takes a date (2025-08-01), an RFC3339 time or an age: a Go duration
+(72h) or whole days (30d) back from now (the root --time flag, if given).
+
+Examples:
+  agentpm compact --before 30d --dry-run
+  agentpm compact --before 2025-08-01`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config",
+			},
+			&cli.StringFlag{
+				Name:     "before",
+				Usage:    "Cutoff: events before it are compacted, e.g. 30d or 2025-08-01",
+				Required: true,
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show what would be compacted without making changes",
+			},
+		},
+		Action: withQuietResult(compactAction),
+	}
+}
+
+func compactAction(ctx context.Context, c *cli.Command) error {
+	now := time.Now()
+	if timeStr := c.String("time"); timeStr != "" {
+		var err error
+		now, err = time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+		}
+	}
+	before, err := parseCompactCutoff(c.String("before"), now)
+	if err != nil {
+		return err
+	}
+
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+	_, archiveDir, err := loadArchiveConfig(c)
+	if err != nil {
+		return err
+	}
+
+	fileStorage := storage.NewFileStorage()
+	epicData, err := fileStorage.LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	result := compactResult{Epic: epicData.ID, Before: before, DryRun: c.Bool("dry-run") || storage.DryRun()}
+	result.Summary = service.CompactEvents(epicData, before)
+	if result.Summary != nil && !result.DryRun {
+		// Before the full copy is archived, which the save check would come too late for
+		if err := epicData.EnsureMutable("compact"); err != nil {
+			return err
+		}
+		if result.Archived, err = storage.CopyToArchive(epicFile, archiveDir, storage.SnapshotName(epicFile, now)); err != nil {
+			return err
+		}
+		if err := fileStorage.SaveEpic(epicData, epicFile); err != nil {
+			return fmt.Errorf("failed to save epic: %w", err)
+		}
+	}
+
+	return writeCompactResult(c, result)
+}
+
+// parseCompactCutoff parses --before as an age back from now - a duration or
+// whole days - or as a date or RFC3339 time
+func parseCompactCutoff(value string, now time.Time) (time.Time, error) {
+	if age, err := parseSummaryWindow(value); err == nil {
+		return now.Add(-age), nil
+	}
+	before, _, err := epic.ParseDate(value)
+	if err != nil {
+		return time.Time{}, exitcode.Errorf(exitcode.Validation, "invalid --before: %v (or an age like 30d or 72h)", err)
+	}
+	return before, nil
+}
+
+func writeCompactResult(c *cli.Command, result compactResult) error {
+	w := c.Root().Writer
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal compact result to JSON: %w", err)
+		}
+		fmt.Fprintf(w, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("compact_result")
+		root.CreateAttr("epic", result.Epic)
+		root.CreateAttr("before", result.Before.Format(time.RFC3339))
+		root.CreateAttr("dry_run", strconv.FormatBool(result.DryRun))
+		if result.Archived != "" {
+			root.CreateAttr("archived", result.Archived)
+		}
+		if summary := result.Summary; summary != nil {
+			elem := root.CreateElement("summary")
+			elem.CreateAttr("id", summary.ID)
+			elem.CreateAttr("from", summary.From.Format(time.RFC3339))
+			elem.CreateAttr("to", summary.To.Format(time.RFC3339))
+			elem.CreateAttr("events", strconv.Itoa(summary.Events))
+			elem.SetText(summary.Data)
+		}
+		doc.Indent(4)
+		doc.WriteTo(w)
+	default:
+		cutoff := result.Before.UTC().Format("2006-01-02 15:04")
+		if result.Summary == nil {
+			fmt.Fprintf(w, "Nothing to compact: epic %s has fewer than 2 events before %s.\n", result.Epic, cutoff)
+			return nil
+		}
+		verb := "Compacted"
+		if result.DryRun {
+			verb = "Would compact"
+		}
+		fmt.Fprintf(w, "%s %d events of epic %s before %s:\n  %s\n", verb, result.Summary.Events, result.Epic, cutoff, result.Summary.Data)
+		if result.Archived != "" {
+			fmt.Fprintf(w, "The full epic was kept as %s.\n", result.Archived)
+		}
+	}
+	return nil
+}
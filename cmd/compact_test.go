@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompactCommand(t *testing.T) {
+	setup := func(t *testing.T) (string, string) {
+		dir := t.TempDir()
+		epicFile := filepath.Join(dir, "epic.xml")
+		epicData := createEpicForReset()
+		epicData.Events = nil
+		for day := 1; day <= 4; day++ {
+			service.CreateEvent(epicData, service.EventTaskStarted, "P1", "T1", "", "", time.Date(2025, 8, day, 9, 0, 0, 0, time.UTC))
+		}
+		service.CreateEvent(epicData, service.EventTaskCompleted, "P1", "T1", "", "", time.Date(2025, 8, 19, 9, 0, 0, 0, time.UTC))
+		require.NoError(t, storage.NewFileStorage().SaveEpic(epicData, epicFile))
+
+		configPath := filepath.Join(dir, ".agentpm.json")
+		cfg := config.DefaultConfig()
+		cfg.CurrentEpic = "epic.xml"
+		require.NoError(t, config.SaveConfig(cfg, configPath))
+		return configPath, epicFile
+	}
+
+	t.Run("collapses old events and keeps a full copy", func(t *testing.T) {
+		configPath, epicFile := setup(t)
+		archived := filepath.Join(filepath.Dir(epicFile), ".agentpm", "archive", "epic.20250820T120000Z.xml")
+
+		output, err := runApp(t, archiveApp.withConfig(configPath), "compact", "--before", "7d")
+		require.NoError(t, err)
+		assert.Equal(t, "Compacted 4 events of epic epic-1 before 2025-08-13 12:00:\n"+
+			"  Summary of 4 events from 2025-08-01 09:00 to 2025-08-04 09:00: 4 task_started\n"+
+			"The full epic was kept as "+archived+".\n", output)
+
+		compacted, err := storage.NewFileStorage().LoadEpic(epicFile)
+		require.NoError(t, err)
+		require.Len(t, compacted.Events, 2)
+		assert.Equal(t, string(service.EventCompacted), compacted.Events[0].Type)
+		assert.Equal(t, string(service.EventTaskCompleted), compacted.Events[1].Type)
+
+		original, err := storage.NewFileStorage().LoadEpic(archived)
+		require.NoError(t, err)
+		assert.Len(t, original.Events, 5)
+	})
+
+	t.Run("dry run changes nothing", func(t *testing.T) {
+		configPath, epicFile := setup(t)
+
+		output, err := runApp(t, archiveApp.withConfig(configPath), "--format", "json", "compact", "--before", "2025-08-03", "--dry-run")
+		require.NoError(t, err)
+		var result struct {
+			DryRun   bool                  `json:"dry_run"`
+			Summary  *service.EventSummary `json:"summary"`
+			Archived string                `json:"archived"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.True(t, result.DryRun)
+		require.NotNil(t, result.Summary)
+		assert.Equal(t, 2, result.Summary.Events)
+		assert.Empty(t, result.Archived)
+
+		unchanged, err := storage.NewFileStorage().LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Len(t, unchanged.Events, 5)
+		assert.NoDirExists(t, filepath.Join(filepath.Dir(epicFile), ".agentpm"))
+	})
+
+	t.Run("nothing to compact", func(t *testing.T) {
+		configPath, _ := setup(t)
+
+		output, err := runApp(t, archiveApp.withConfig(configPath), "compact", "--before", "2025-08-02")
+		require.NoError(t, err)
+		assert.Equal(t, "Nothing to compact: epic epic-1 has fewer than 2 events before 2025-08-02 00:00.\n", output)
+	})
+
+	t.Run("rejects invalid cutoffs", func(t *testing.T) {
+		configPath, _ := setup(t)
+
+		_, err := runApp(t, archiveApp.withConfig(configPath), "compact", "--before", "last week")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid --before")
+	})
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/compare"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// CompareRunsCommand compares two executions of the same plan side by side
+func CompareRunsCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "compare-runs",
+		Usage:     "Compare two epics executed from the same template",
+		ArgsUsage: "<epic-a.xml> <epic-b.xml>",
+		Description: `Put two runs of the same plan side by side - e.g. one template executed
+by two different agents - to benchmark them: duration, test failures,
+cancellations, resets and deviation from the plan.
+
+Tasks are matched by ID. A task counts as out of order when it was completed
+while a task planned ahead of it (earlier in the epic) was still open; tasks
+that only one run has count as unplanned. Unfinished runs are measured until
+now.
+
+Examples:
+  agentpm compare-runs runs/claude/epic-8.xml runs/codex/epic-8.xml
+  agentpm compare-runs a.xml b.xml --format json`,
+		Action: compareRunsAction,
+	}
+}
+
+func compareRunsAction(ctx context.Context, c *cli.Command) error {
+	if c.Args().Len() != 2 {
+		return fmt.Errorf("expected two epic files: compare-runs <epic-a.xml> <epic-b.xml>")
+	}
+
+	now := time.Now()
+	if timeStr := c.String("time"); timeStr != "" {
+		var err error
+		now, err = time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+		}
+	}
+
+	fileStorage := storage.NewFileStorage()
+	epicA, err := fileStorage.LoadEpic(c.Args().Get(0))
+	if err != nil {
+		return fmt.Errorf("failed to load epic %s: %w", c.Args().Get(0), err)
+	}
+	epicB, err := fileStorage.LoadEpic(c.Args().Get(1))
+	if err != nil {
+		return fmt.Errorf("failed to load epic %s: %w", c.Args().Get(1), err)
+	}
+
+	comparison := compare.Compare(epicA, epicB, now)
+
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(comparison, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal comparison to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		outputCompareRunsXML(c, comparison)
+	default:
+		outputCompareRunsText(c, comparison, c.Args().Get(0), c.Args().Get(1))
+	}
+	return nil
+}
+
+func outputCompareRunsText(c *cli.Command, comparison *compare.Comparison, fileA, fileB string) {
+	w := c.Root().Writer
+	a, b := comparison.A, comparison.B
+
+	row := func(label, valueA, valueB string) {
+		fmt.Fprintf(w, "%-16s  %-24s  %s\n", label, valueA, valueB)
+	}
+	count := func(label string, valueA, valueB int) {
+		row(label, strconv.Itoa(valueA), strconv.Itoa(valueB))
+	}
+
+	row("", "A", "B")
+	row("file", fileA, fileB)
+	row("epic", a.EpicID, b.EpicID)
+	row("status", a.Status, b.Status)
+	row("actors", formatActors(a.Actors), formatActors(b.Actors))
+	row("duration", formatMinutes(a.DurationMinutes), formatMinutes(b.DurationMinutes))
+	count("tasks completed", a.TasksCompleted, b.TasksCompleted)
+	count("tasks cancelled", a.TasksCancelled, b.TasksCancelled)
+	count("tests cancelled", a.TestsCancelled, b.TestsCancelled)
+	count("test failures", a.TestFailures, b.TestFailures)
+	count("resets", a.Resets, b.Resets)
+	count("out of order", a.OutOfOrder, b.OutOfOrder)
+	count("unplanned tasks", a.Unplanned, b.Unplanned)
+
+	if len(comparison.Tasks) > 0 {
+		fmt.Fprintf(w, "\n%-16s  %-24s  %s\n", "task", "A", "B")
+		for _, task := range comparison.Tasks {
+			fmt.Fprintf(w, "%-16s  %-24s  %s\n", task.ID, formatTaskRun(task.A), formatTaskRun(task.B))
+		}
+	}
+
+	if len(comparison.OnlyInA) > 0 {
+		fmt.Fprintf(w, "\nOnly in A: %s\n", strings.Join(comparison.OnlyInA, ", "))
+	}
+	if len(comparison.OnlyInB) > 0 {
+		fmt.Fprintf(w, "\nOnly in B: %s\n", strings.Join(comparison.OnlyInB, ", "))
+	}
+}
+
+func formatActors(actors []string) string {
+	if len(actors) == 0 {
+		return "-"
+	}
+	return strings.Join(actors, ", ")
+}
+
+// formatTaskRun renders a task's run as "completed 1h30m 2 failures"
+func formatTaskRun(run compare.TaskRun) string {
+	text := fmt.Sprintf("%s %s", run.Status, formatMinutes(run.ActiveMinutes))
+	switch run.Failures {
+	case 0:
+	case 1:
+		text += " 1 failure"
+	default:
+		text += fmt.Sprintf(" %d failures", run.Failures)
+	}
+	return text
+}
+
+func outputCompareRunsXML(c *cli.Command, comparison *compare.Comparison) {
+	doc := etree.NewDocument()
+	root := doc.CreateElement("compare_runs")
+
+	for _, run := range []struct {
+		name  string
+		stats compare.RunStats
+	}{{"a", comparison.A}, {"b", comparison.B}} {
+		runElem := root.CreateElement("run")
+		runElem.CreateAttr("name", run.name)
+		runElem.CreateAttr("epic", run.stats.EpicID)
+		runElem.CreateAttr("status", run.stats.Status)
+		runElem.CreateAttr("duration_minutes", strconv.Itoa(run.stats.DurationMinutes))
+		runElem.CreateAttr("tasks_completed", strconv.Itoa(run.stats.TasksCompleted))
+		runElem.CreateAttr("tasks_cancelled", strconv.Itoa(run.stats.TasksCancelled))
+		runElem.CreateAttr("tests_cancelled", strconv.Itoa(run.stats.TestsCancelled))
+		runElem.CreateAttr("test_failures", strconv.Itoa(run.stats.TestFailures))
+		runElem.CreateAttr("resets", strconv.Itoa(run.stats.Resets))
+		runElem.CreateAttr("out_of_order", strconv.Itoa(run.stats.OutOfOrder))
+		runElem.CreateAttr("unplanned", strconv.Itoa(run.stats.Unplanned))
+		for _, actor := range run.stats.Actors {
+			runElem.CreateElement("actor").SetText(actor)
+		}
+	}
+
+	for _, task := range comparison.Tasks {
+		taskElem := root.CreateElement("task")
+		taskElem.CreateAttr("id", task.ID)
+		for _, run := range []struct {
+			name string
+			run  compare.TaskRun
+		}{{"a", task.A}, {"b", task.B}} {
+			runElem := taskElem.CreateElement(run.name)
+			runElem.CreateAttr("status", run.run.Status)
+			runElem.CreateAttr("active_minutes", strconv.Itoa(run.run.ActiveMinutes))
+			runElem.CreateAttr("failures", strconv.Itoa(run.run.Failures))
+		}
+	}
+
+	for _, id := range comparison.OnlyInA {
+		root.CreateElement("only_in_a").CreateAttr("task", id)
+	}
+	for _, id := range comparison.OnlyInB {
+		root.CreateElement("only_in_b").CreateAttr("task", id)
+	}
+
+	doc.Indent(4)
+	doc.WriteTo(c.Root().Writer)
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/compare"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var compareRunsApp = appOptions{commands: []func() *cli.Command{CompareRunsCommand}, time: "2025-08-15T12:00:00Z"}
+
+func TestCompareRunsCommand(t *testing.T) {
+	dir := t.TempDir()
+	fileA := filepath.Join(dir, "a.xml")
+	fileB := filepath.Join(dir, "b.xml")
+
+	runA := createEpicForReset()
+	runA.Events = nil
+	service.CreateEvent(runA, service.EventTaskStarted, "P1", "T1", "", "", time.Date(2025, 8, 15, 9, 0, 0, 0, time.UTC))
+	service.CreateEvent(runA, service.EventTaskCompleted, "P1", "T1", "", "", time.Date(2025, 8, 15, 10, 30, 0, 0, time.UTC))
+	runA.Tasks[0].Status = epic.StatusCompleted
+
+	runB := createEpicForReset()
+	runB.Events = nil
+	runB.Tasks[1].Status = epic.StatusCancelled
+	service.CreateEvent(runB, service.EventTaskStarted, "P1", "T1", "", "", time.Date(2025, 8, 15, 9, 0, 0, 0, time.UTC))
+	service.CreateEvent(runB, service.EventTestFailed, "P1", "T1", "T1_T1", "boom", time.Date(2025, 8, 15, 9, 30, 0, 0, time.UTC))
+
+	fileStorage := storage.NewFileStorage()
+	require.NoError(t, fileStorage.SaveEpic(runA, fileA))
+	require.NoError(t, fileStorage.SaveEpic(runB, fileB))
+
+	t.Run("text output side by side", func(t *testing.T) {
+		output, err := runApp(t, compareRunsApp.withFormat("text"), "compare-runs", fileA, fileB)
+		require.NoError(t, err)
+		assert.Contains(t, output, "duration          3h00m                     3h00m\n")
+		assert.Contains(t, output, "tasks cancelled   0                         1\n")
+		assert.Contains(t, output, "test failures     0                         1\n")
+		assert.Contains(t, output, "T1                completed 1h30m           wip 3h00m 1 failure\n")
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		output, err := runApp(t, compareRunsApp.withFormat("json"), "compare-runs", fileA, fileB)
+		require.NoError(t, err)
+
+		var comparison compare.Comparison
+		require.NoError(t, json.Unmarshal([]byte(output), &comparison))
+		assert.Equal(t, 1, comparison.A.TasksCompleted)
+		assert.Equal(t, 1, comparison.B.TestFailures)
+		assert.Len(t, comparison.Tasks, 2)
+	})
+
+	t.Run("requires two epic files", func(t *testing.T) {
+		_, err := runApp(t, compareRunsApp.withFormat("text"), "compare-runs", fileA)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "expected two epic files")
+	})
+}
@@ -2,27 +2,302 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/gitmeta"
+	"github.com/mindreframer/agentpm/internal/phases"
+	"github.com/mindreframer/agentpm/internal/policy"
+	"github.com/mindreframer/agentpm/internal/service"
 	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/mindreframer/agentpm/internal/tasks"
 	"github.com/urfave/cli/v3"
 )
 
+// PinExplicitConfig is the root Before hook that makes an explicit --config exact.
+// Without the flag the config file is discovered by walking up from the working
+// directory; with it, the given path is made absolute so no discovery happens.
+func PinExplicitConfig(ctx context.Context, c *cli.Command) (context.Context, error) {
+	if !c.IsSet("config") {
+		return ctx, nil
+	}
+
+	absPath, err := filepath.Abs(c.String("config"))
+	if err != nil {
+		return ctx, fmt.Errorf("failed to resolve config path: %w", err)
+	}
+	return ctx, c.Set("config", absPath)
+}
+
 func ConfigCommand() *cli.Command {
 	return &cli.Command{
-		Name:   "config",
-		Usage:  "Display current project configuration",
+		Name:  "config",
+		Usage: "Display current project configuration",
+		Description: `Display the effective configuration for the current epic.
+
+Per-epic overrides are read from a sidecar next to the epic file
+(epic-8.xml -> epic-8.config.json) and merged over the project config.
+Supported override keys: test_gating, gate_tags, strict, hints.
+
+Subcommands:
+  validate             Strictly validate the config file (unknown keys, types, values)
+  get <key>            Print a config value (dotted path, e.g. hints.max_hints)
+  set <key> <value>    Set a config value and write the file back
+  unset <key>          Remove a config value
+
+Examples:
+  agentpm config                           # Show effective configuration
+  agentpm config --schema                  # Print the JSON Schema for .agentpm.json
+  agentpm config validate                  # Check .agentpm.json for problems
+  agentpm config set default_assignee alice
+  agentpm config get hints.max_hints
+  agentpm config unset test_gating`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "schema",
+				Usage: "Print the JSON Schema of the config file",
+			},
+		},
+		Commands: []*cli.Command{
+			configValidateSubcommand(),
+			configGetSubcommand(),
+			configSetSubcommand(),
+			configUnsetSubcommand(),
+		},
 		Action: runConfig,
 	}
 }
 
+func configValidateSubcommand() *cli.Command {
+	return &cli.Command{
+		Name:  "validate",
+		Usage: "Validate the config file against its schema",
+		Description: `Check the config file for invalid JSON, unknown keys (likely typos),
+wrongly typed values and missing required keys. Exits with an error when the
+configuration is invalid; unknown keys are reported as warnings only.`,
+		Action: runConfigValidate,
+	}
+}
+
+func configGetSubcommand() *cli.Command {
+	return &cli.Command{
+		Name:      "get",
+		Usage:     "Print a config value",
+		ArgsUsage: "<key>",
+		Action:    runConfigGet,
+	}
+}
+
+func configSetSubcommand() *cli.Command {
+	return &cli.Command{
+		Name:      "set",
+		Usage:     "Set a config value",
+		ArgsUsage: "<key> <value>",
+		Description: `Set a config value addressed by a dotted path and write the config
+file back as canonical JSON. Values are converted to the key's type
+(true/false for booleans, numbers for integers).`,
+		Action: withQuietResult(runConfigSet),
+	}
+}
+
+func configUnsetSubcommand() *cli.Command {
+	return &cli.Command{
+		Name:      "unset",
+		Usage:     "Remove a config value",
+		ArgsUsage: "<key>",
+		Action:    withQuietResult(runConfigUnset),
+	}
+}
+
+func runConfigGet(ctx context.Context, c *cli.Command) error {
+	format := c.String("format")
+	if c.Args().Len() != 1 {
+		return exitcode.Errorf(exitcode.Validation, "get requires exactly one argument")
+	}
+	key := c.Args().First()
+
+	cfg, err := config.LoadConfig(c.String("config"))
+	if err != nil {
+		return writeError(c, format, fmt.Sprintf("Failed to load configuration: %v", err))
+	}
+
+	value, err := cfg.Get(key)
+	if err != nil {
+		return writeError(c, format, err.Error())
+	}
+
+	switch format {
+	case "json":
+		jsonData, err := json.MarshalIndent(map[string]interface{}{"key": key, "value": value}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal config value to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		fmt.Fprintf(c.Root().Writer, "<config_value key=\"%s\">%s</config_value>\n", key, formatConfigValue(value))
+	default:
+		fmt.Fprintf(c.Root().Writer, "%s\n", formatConfigValue(value))
+	}
+	return nil
+}
+
+func runConfigSet(ctx context.Context, c *cli.Command) error {
+	format := c.String("format")
+	if c.Args().Len() != 2 {
+		return exitcode.Errorf(exitcode.Validation, "set requires exactly 2 arguments")
+	}
+	key, value := c.Args().Get(0), c.Args().Get(1)
+
+	return updateConfig(c, format, key, false, func(cfg *config.Config) error {
+		return cfg.Set(key, value)
+	})
+}
+
+func runConfigUnset(ctx context.Context, c *cli.Command) error {
+	format := c.String("format")
+	if c.Args().Len() != 1 {
+		return exitcode.Errorf(exitcode.Validation, "unset requires exactly one argument")
+	}
+	key := c.Args().First()
+
+	return updateConfig(c, format, key, true, func(cfg *config.Config) error {
+		return cfg.Unset(key)
+	})
+}
+
+// updateConfig applies a change to the project config file and reports the new value
+func updateConfig(c *cli.Command, format, key string, unset bool, change func(*config.Config) error) error {
+	configPath := c.String("config")
+
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return writeError(c, format, fmt.Sprintf("Failed to load configuration: %v", err))
+	}
+
+	if err := change(cfg); err != nil {
+		return writeError(c, format, err.Error())
+	}
+
+	if err := config.SaveConfig(cfg, configPath); err != nil {
+		return writeError(c, format, fmt.Sprintf("Failed to save configuration: %v", err))
+	}
+
+	value, err := cfg.Get(key)
+	if err != nil {
+		return writeError(c, format, err.Error())
+	}
+
+	switch format {
+	case "json":
+		jsonData, err := json.MarshalIndent(map[string]interface{}{"key": key, "value": value, "updated": true}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal config value to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		fmt.Fprintf(c.Root().Writer, "<config_updated key=\"%s\">%s</config_updated>\n", key, formatConfigValue(value))
+	default:
+		if unset && value == nil {
+			fmt.Fprintf(c.Root().Writer, "Unset %s\n", key)
+		} else if unset {
+			fmt.Fprintf(c.Root().Writer, "Unset %s (now %s)\n", key, formatConfigValue(value))
+		} else {
+			fmt.Fprintf(c.Root().Writer, "Set %s = %s\n", key, formatConfigValue(value))
+		}
+	}
+	return nil
+}
+
+// formatConfigValue renders a config value for text output: strings as-is, others as JSON
+func formatConfigValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	}
+}
+
+func runConfigValidate(ctx context.Context, c *cli.Command) error {
+	format := c.String("format")
+
+	report, err := config.ValidateConfigFile(c.String("config"))
+	if err != nil {
+		return writeError(c, format, err.Error())
+	}
+
+	switch format {
+	case "json":
+		output := map[string]interface{}{
+			"path":     report.Path,
+			"valid":    report.Valid(),
+			"errors":   report.Errors,
+			"warnings": report.Warnings,
+		}
+		jsonData, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal validation report to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		output := fmt.Sprintf(`<config_validation path="%s" valid="%t">`, report.Path, report.Valid())
+		for _, e := range report.Errors {
+			output += fmt.Sprintf(`
+    <error>%s</error>`, e)
+		}
+		for _, w := range report.Warnings {
+			output += fmt.Sprintf(`
+    <warning>%s</warning>`, w)
+		}
+		output += `
+</config_validation>`
+		fmt.Fprintf(c.Root().Writer, "%s\n", output)
+	default:
+		if report.Valid() {
+			fmt.Fprintf(c.Root().Writer, "✓ Config file is valid: %s\n", report.Path)
+		} else {
+			fmt.Fprintf(c.Root().Writer, "✗ Config file is invalid: %s\n", report.Path)
+		}
+		for _, e := range report.Errors {
+			fmt.Fprintf(c.Root().Writer, "  Error: %s\n", e)
+		}
+		for _, w := range report.Warnings {
+			fmt.Fprintf(c.Root().Writer, "  Warning: %s\n", w)
+		}
+	}
+
+	if !report.Valid() {
+		return fmt.Errorf("config file has %d error(s)", len(report.Errors))
+	}
+	return nil
+}
+
 func runConfig(ctx context.Context, c *cli.Command) error {
 	configPath := c.String("config")
 	format := c.String("format")
 
-	// Try to load configuration
-	cfg, err := config.LoadConfig(configPath)
+	if c.Bool("schema") {
+		schema, err := json.MarshalIndent(config.JSONSchema(), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal config schema: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", schema)
+		return nil
+	}
+
+	// Try to load configuration, merged with per-epic overrides
+	cfg, err := config.LoadEffectiveConfig(configPath, c.String("file"))
 	if err != nil {
 		return writeError(c, format, fmt.Sprintf("Failed to load configuration: %v", err))
 	}
@@ -40,6 +315,11 @@ func writeConfigResult(c *cli.Command, format string, cfg *config.Config, epicMi
 		output := fmt.Sprintf(`<config>
     <current_epic>%s</current_epic>`, cfg.CurrentEpic)
 
+		if cfg.Profile != "" {
+			output += fmt.Sprintf(`
+    <profile>%s</profile>`, cfg.Profile)
+		}
+
 		if cfg.ProjectName != "" {
 			output += fmt.Sprintf(`
     <project_name>%s</project_name>`, cfg.ProjectName)
@@ -48,10 +328,45 @@ func writeConfigResult(c *cli.Command, format string, cfg *config.Config, epicMi
 		output += fmt.Sprintf(`
     <default_assignee>%s</default_assignee>`, cfg.DefaultAssignee)
 
+		if cfg.TestGating != "" {
+			output += fmt.Sprintf(`
+    <test_gating%s>%s</test_gating>`, overrideAttr(cfg, "test_gating"), cfg.TestGating)
+		}
+		if cfg.GateTags != "" {
+			output += fmt.Sprintf(`
+    <gate_tags%s>%s</gate_tags>`, overrideAttr(cfg, "gate_tags"), cfg.GateTags)
+		}
+		if cfg.Strict {
+			output += fmt.Sprintf(`
+    <strict%s>true</strict>`, overrideAttr(cfg, "strict"))
+		}
+		if cfg.DefaultFormat != "" {
+			output += fmt.Sprintf(`
+    <default_format>%s</default_format>`, cfg.DefaultFormat)
+		}
+		if len(cfg.OverriddenKeys) > 0 {
+			output += `
+    <epic_overrides>`
+			for _, key := range cfg.OverriddenKeys {
+				output += fmt.Sprintf(`
+        <key>%s</key>`, key)
+			}
+			output += `
+    </epic_overrides>`
+		}
+
+		warnings := cfg.Warnings
 		if epicMissing {
+			warnings = append([]string{"Epic file not found"}, warnings...)
+		}
+		if len(warnings) > 0 {
+			output += `
+    <warnings>`
+			for _, warning := range warnings {
+				output += fmt.Sprintf(`
+        <warning>%s</warning>`, warning)
+			}
 			output += `
-    <warnings>
-        <warning>Epic file not found</warning>
     </warnings>`
 		}
 
@@ -63,17 +378,48 @@ func writeConfigResult(c *cli.Command, format string, cfg *config.Config, epicMi
 		output := fmt.Sprintf(`{
   "current_epic": "%s",`, cfg.CurrentEpic)
 
+		if cfg.Profile != "" {
+			output += fmt.Sprintf(`
+  "profile": "%s",`, cfg.Profile)
+		}
+
 		if cfg.ProjectName != "" {
 			output += fmt.Sprintf(`
   "project_name": "%s",`, cfg.ProjectName)
 		}
 
+		if cfg.TestGating != "" {
+			output += fmt.Sprintf(`
+  "test_gating": "%s",`, cfg.TestGating)
+		}
+		if cfg.GateTags != "" {
+			output += fmt.Sprintf(`
+  "gate_tags": "%s",`, cfg.GateTags)
+		}
+		if cfg.Strict {
+			output += `
+  "strict": true,`
+		}
+		if cfg.DefaultFormat != "" {
+			output += fmt.Sprintf(`
+  "default_format": "%s",`, cfg.DefaultFormat)
+		}
+		if len(cfg.OverriddenKeys) > 0 {
+			output += fmt.Sprintf(`
+  "epic_overrides": ["%s"],`, strings.Join(cfg.OverriddenKeys, `", "`))
+		}
+
 		output += fmt.Sprintf(`
   "default_assignee": "%s"`, cfg.DefaultAssignee)
 
+		warnings := cfg.Warnings
 		if epicMissing {
-			output += `,
-  "warnings": ["Epic file not found"]`
+			warnings = append([]string{"Epic file not found"}, warnings...)
+		}
+		if len(warnings) > 0 {
+			warningsJSON, _ := json.Marshal(warnings)
+			output += fmt.Sprintf(`,
+  "warnings": %s`, warningsJSON)
 		}
 
 		output += `
@@ -82,16 +428,212 @@ func writeConfigResult(c *cli.Command, format string, cfg *config.Config, epicMi
 
 	default: // text
 		fmt.Fprintf(c.Root().Writer, "Current Configuration:\n")
+		if cfg.FilePath() != "" {
+			fmt.Fprintf(c.Root().Writer, "  Config file: %s\n", cfg.FilePath())
+		}
+		if cfg.Profile != "" {
+			fmt.Fprintf(c.Root().Writer, "  Profile: %s\n", cfg.Profile)
+		}
 		fmt.Fprintf(c.Root().Writer, "  Current epic: %s\n", cfg.CurrentEpic)
 		if cfg.ProjectName != "" {
 			fmt.Fprintf(c.Root().Writer, "  Project name: %s\n", cfg.ProjectName)
 		}
 		fmt.Fprintf(c.Root().Writer, "  Default assignee: %s\n", cfg.DefaultAssignee)
+		if cfg.TestGating != "" {
+			fmt.Fprintf(c.Root().Writer, "  Test gating: %s%s\n", cfg.TestGating, overrideMarker(cfg, "test_gating"))
+		}
+		if cfg.GateTags != "" {
+			fmt.Fprintf(c.Root().Writer, "  Gate tags: %s%s\n", cfg.GateTags, overrideMarker(cfg, "gate_tags"))
+		}
+		if cfg.Strict {
+			fmt.Fprintf(c.Root().Writer, "  Strict mode: on%s\n", overrideMarker(cfg, "strict"))
+		}
+		if cfg.DefaultFormat != "" {
+			fmt.Fprintf(c.Root().Writer, "  Default format: %s\n", cfg.DefaultFormat)
+		}
+		for _, key := range cfg.OverriddenKeys {
+			if strings.HasPrefix(key, "hints.") {
+				fmt.Fprintf(c.Root().Writer, "  Hint setting %s overridden by epic config\n", strings.TrimPrefix(key, "hints."))
+			}
+		}
 
 		if epicMissing {
 			fmt.Fprintf(c.Root().Writer, "\n⚠ Warning: Epic file not found: %s\n", cfg.EpicFilePath())
 		}
+		for _, warning := range cfg.Warnings {
+			fmt.Fprintf(c.Root().Writer, "⚠ Warning: %s\n", warning)
+		}
 	}
 
 	return nil
 }
+
+// overrideMarker annotates text output for values coming from the epic sidecar
+func overrideMarker(cfg *config.Config, key string) string {
+	if cfg.IsOverridden(key) {
+		return " (epic override)"
+	}
+	return ""
+}
+
+// overrideAttr annotates XML output for values coming from the epic sidecar
+func overrideAttr(cfg *config.Config, key string) string {
+	if cfg.IsOverridden(key) {
+		return ` source="epic"`
+	}
+	return ""
+}
+
+// ApplyStrictMode is the root Before hook that enables strict mode for this
+// invocation from --strict (or AGENTPM_STRICT), else from the effective config
+func ApplyStrictMode(ctx context.Context, c *cli.Command) (context.Context, error) {
+	if c.IsSet("strict") {
+		tasks.SetStrict(c.Bool("strict"))
+		return ctx, nil
+	}
+
+	// A missing or broken config is reported by the command itself
+	cfg, err := config.LoadEffectiveConfig(c.String("config"), c.String("file"))
+	tasks.SetStrict(err == nil && cfg.Strict)
+	return ctx, nil
+}
+
+// ApplyGateTags is the root Before hook that limits the tests gating phases of
+// this invocation to the gate_tags of the effective config
+func ApplyGateTags(ctx context.Context, c *cli.Command) (context.Context, error) {
+	// A missing or broken config is reported by the command itself
+	cfg, err := config.LoadEffectiveConfig(c.String("config"), c.String("file"))
+	if err != nil {
+		phases.SetGateTags(nil)
+		return ctx, nil
+	}
+	phases.SetGateTags(cfg.GateTagList())
+	return ctx, nil
+}
+
+// ApplyTestGating is the root Before hook that sets how strictly tests gate
+// the phases of this invocation from the test_gating of the effective config
+func ApplyTestGating(ctx context.Context, c *cli.Command) (context.Context, error) {
+	// A missing or broken config is reported by the command itself
+	cfg, err := config.LoadEffectiveConfig(c.String("config"), c.String("file"))
+	if err != nil {
+		phases.SetTestGating("")
+		return ctx, nil
+	}
+	phases.SetTestGating(cfg.TestGating)
+	return ctx, nil
+}
+
+// ApplyProfile is the root Before hook that selects the profile of the config
+// (--profile, else AGENTPM_PROFILE) and applies its default_format to --format
+// where it is not given. It runs before the other hooks, which load the config.
+func ApplyProfile(ctx context.Context, c *cli.Command) (context.Context, error) {
+	config.SetProfile(c.String("profile"))
+
+	configPath, err := config.ResolveConfigPath(c.String("config"))
+	if err != nil || !config.ConfigExists(configPath) {
+		return ctx, nil
+	}
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		// Only an unknown profile is reported here, other problems by the command itself
+		if config.ActiveProfile() != "" {
+			return ctx, err
+		}
+		return ctx, nil
+	}
+	if cfg.DefaultFormat != "" {
+		return ctx, setDefaultFormat(c, cfg.DefaultFormat)
+	}
+	return ctx, nil
+}
+
+// setDefaultFormat sets the --format flags of the command and its subcommands
+// that were not given. The root Before hooks run once all flags are parsed.
+func setDefaultFormat(c *cli.Command, format string) error {
+	for _, flag := range c.Flags {
+		if formatFlag, ok := flag.(*cli.StringFlag); ok && formatFlag.Name == "format" && !formatFlag.IsSet() {
+			if err := formatFlag.Set("format", format); err != nil {
+				return err
+			}
+		}
+	}
+	for _, sub := range c.Commands {
+		if err := setDefaultFormat(sub, format); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyPolicy is the root Before hook that enforces the rules of the policy file
+// and the policies of the config on the mutations of this invocation. Strict
+// mode upgrades their warnings to errors, so it has to run after ApplyStrictMode.
+func ApplyPolicy(ctx context.Context, c *cli.Command) (context.Context, error) {
+	policy.SetActive(nil)
+
+	// A missing or broken config is reported by the command itself
+	cfg, err := config.LoadConfig(c.String("config"))
+	if err != nil {
+		return ctx, nil
+	}
+
+	p, err := loadPolicy(cfg)
+	if err != nil || p == nil {
+		return ctx, err
+	}
+	if tasks.Strict() {
+		p = p.Strict()
+	}
+	policy.SetActive(p)
+	return ctx, nil
+}
+
+// loadPolicy returns the rules of the policy file together with the policies
+// of the config, or nil when there are none
+func loadPolicy(cfg *config.Config) (*policy.Policy, error) {
+	p, err := policy.Load(cfg.PolicyFilePath())
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Policies) == 0 {
+		return p, nil
+	}
+	policies, err := policy.New(cfg.Policies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid policies: %w", err)
+	}
+	if p, err = policy.Merge(p, policies); err != nil {
+		return nil, fmt.Errorf("invalid policies: %w", err)
+	}
+	return p, nil
+}
+
+// RecordActor is the root Before hook that attributes the events created by this
+// invocation to --actor (or AGENTPM_ACTOR)
+func RecordActor(ctx context.Context, c *cli.Command) (context.Context, error) {
+	service.SetActor(strings.TrimSpace(c.String("actor")))
+	return ctx, nil
+}
+
+// CorrelateEvents is the root Before hook that gives the events created by
+// this invocation a shared correlation ID, so that the events of a cascade
+// (a task completing its phase and the epic) can be found with events --correlate
+func CorrelateEvents(ctx context.Context, c *cli.Command) (context.Context, error) {
+	service.SetCorrelationID(service.NewCorrelationID())
+	return ctx, nil
+}
+
+// RecordGitCommit is the root Before hook that links the events created by this
+// invocation to the git commit and branch checked out in the working directory,
+// unless --no-git (or AGENTPM_NO_GIT) is set
+func RecordGitCommit(ctx context.Context, c *cli.Command) (context.Context, error) {
+	service.SetGitInfo(nil)
+	if c.Bool("no-git") {
+		return ctx, nil
+	}
+	if dir, err := os.Getwd(); err == nil {
+		service.SetGitInfo(gitmeta.Detect(dir))
+	}
+	return ctx, nil
+}
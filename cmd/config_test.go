@@ -0,0 +1,316 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/phases"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var configApp = appOptions{commands: []func() *cli.Command{ConfigCommand}}
+
+func TestConfigValidateCommand(t *testing.T) {
+	t.Run("reports errors and warnings", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), ".agentpm.json")
+		require.NoError(t, os.WriteFile(configFile, []byte(`{"current_epic": "epic.xml", "defualt_assignee": "x", "hints": {"max_hints": "3"}}`), 0644))
+
+		output, err := runApp(t, configApp.withConfig(configFile), "config", "validate")
+		require.Error(t, err)
+		assert.Contains(t, output, "✗ Config file is invalid")
+		assert.Contains(t, output, "Error: hints.max_hints must be an integer")
+		assert.Contains(t, output, `Warning: unknown key "defualt_assignee" (did you mean "default_assignee"?)`)
+	})
+
+	t.Run("valid config", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), ".agentpm.json")
+		require.NoError(t, os.WriteFile(configFile, []byte(`{"current_epic": "epic.xml"}`), 0644))
+
+		output, err := runApp(t, configApp.withConfig(configFile), "config", "validate")
+		require.NoError(t, err)
+		assert.Contains(t, output, "✓ Config file is valid")
+	})
+
+	t.Run("config display shows unknown key warnings", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), ".agentpm.json")
+		require.NoError(t, os.WriteFile(configFile, []byte(`{"current_epic": "epic.xml", "projct_name": "x"}`), 0644))
+
+		output, err := runApp(t, configApp.withConfig(configFile), "config")
+		require.NoError(t, err)
+		assert.Contains(t, output, `⚠ Warning: unknown key "projct_name" (did you mean "project_name"?)`)
+	})
+
+	t.Run("prints JSON schema", func(t *testing.T) {
+		output, err := runApp(t, configApp.withConfig(""), "config", "--schema")
+		require.NoError(t, err)
+
+		var schema map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(output), &schema))
+		assert.Equal(t, "object", schema["type"])
+		assert.Contains(t, schema["properties"], "current_epic")
+	})
+}
+
+func TestConfigSetGetUnsetCommands(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), ".agentpm.json")
+	require.NoError(t, os.WriteFile(configFile, []byte(`{"current_epic": "epic.xml"}`), 0644))
+
+	output, err := runApp(t, configApp.withConfig(configFile), "config", "set", "default_assignee", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "Set default_assignee = alice\n", output)
+
+	output, err = runApp(t, configApp.withConfig(configFile), "config", "set", "hints.max_hints", "2")
+	require.NoError(t, err)
+	assert.Equal(t, "Set hints.max_hints = 2\n", output)
+
+	output, err = runApp(t, configApp.withConfig(configFile), "config", "get", "hints.max_hints")
+	require.NoError(t, err)
+	assert.Equal(t, "2\n", output)
+
+	output, err = runApp(t, configApp.withConfig(configFile), "config", "unset", "hints.max_hints")
+	require.NoError(t, err)
+	assert.Equal(t, "Unset hints.max_hints (now 0)\n", output)
+
+	_, err = runApp(t, configApp.withConfig(configFile), "config", "set", "hints.max_hints", "lots")
+	require.Error(t, err)
+
+	// File is written back as canonical JSON
+	data, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	var written map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &written))
+	assert.Equal(t, "alice", written["default_assignee"])
+}
+
+func TestPinExplicitConfig(t *testing.T) {
+	projectDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, ".agentpm.json"), []byte(`{"current_epic": "epic.xml"}`), 0644))
+
+	nested := filepath.Join(projectDir, "src", "pkg")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	require.NoError(t, os.Chdir(nested))
+
+	run := func(args ...string) (string, error) {
+		app := &cli.Command{
+			Name:   "agentpm",
+			Before: PinExplicitConfig,
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config", Value: "./.agentpm.json"},
+				&cli.StringFlag{Name: "file"},
+				&cli.StringFlag{Name: "format", Value: "text"},
+			},
+			Commands: []*cli.Command{
+				ConfigCommand(),
+			},
+		}
+
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		app.ErrWriter = &bytes.Buffer{}
+		err := app.Run(context.Background(), append([]string{"agentpm"}, args...))
+		return stdout.String(), err
+	}
+
+	t.Run("default config is discovered from a subdirectory", func(t *testing.T) {
+		output, err := run("config")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Config file: "+filepath.Join(projectDir, ".agentpm.json"))
+		assert.Contains(t, output, "Current epic: epic.xml")
+	})
+
+	t.Run("explicit config is not searched upwards", func(t *testing.T) {
+		_, err := run("--config", "./.agentpm.json", "config")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "config file not found: "+filepath.Join(nested, ".agentpm.json"))
+	})
+
+	t.Run("explicit config after the subcommand is pinned too", func(t *testing.T) {
+		_, err := run("config", "--config", ".agentpm.json")
+		require.Error(t, err)
+	})
+}
+
+func TestApplyGateTags(t *testing.T) {
+	t.Cleanup(func() { phases.SetGateTags(nil) })
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, ".agentpm.json")
+	epicFile := filepath.Join(tempDir, "epic.xml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`{"current_epic": "epic.xml", "gate_tags": "unit, integration"}`), 0644))
+
+	saveEpic := func() {
+		e := epic.NewEpic("8", "Checkout")
+		e.Status = epic.StatusWIP
+		e.Phases = []epic.Phase{{ID: "1", Name: "Cart", Status: epic.StatusWIP}}
+		e.Tasks = []epic.Task{{ID: "1_1", PhaseID: "1", Name: "Add to cart", Status: epic.StatusCompleted}}
+		e.Tests = []epic.Test{
+			{ID: "T1", TaskID: "1_1", PhaseID: "1", Name: "Unit", Tags: "unit", Status: epic.StatusCompleted, TestStatus: epic.TestStatusDone},
+			{ID: "T2", TaskID: "1_1", PhaseID: "1", Name: "Exploratory", Tags: "manual", Status: epic.StatusPending, TestStatus: epic.TestStatusPending},
+		}
+		require.NoError(t, storage.NewFileStorage().SaveEpic(e, epicFile))
+	}
+	run := func(args ...string) error {
+		app := &cli.Command{
+			Name:   "agentpm",
+			Before: ApplyGateTags,
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config", Value: configFile},
+				&cli.StringFlag{Name: "file", Value: epicFile},
+				&cli.StringFlag{Name: "format", Value: "text"},
+				&cli.StringFlag{Name: "time", Value: time.Date(2025, 8, 20, 12, 0, 0, 0, time.UTC).Format(time.RFC3339)},
+			},
+			Commands: []*cli.Command{DoneCommand()},
+		}
+		app.Writer = &bytes.Buffer{}
+		app.ErrWriter = &bytes.Buffer{}
+		return app.Run(context.Background(), append([]string{"agentpm"}, args...))
+	}
+
+	t.Run("manual tests do not block", func(t *testing.T) {
+		saveEpic()
+		require.NoError(t, run("done", "phase", "1", "--file", epicFile))
+		assert.Equal(t, []string{"unit", "integration"}, phases.GateTags())
+	})
+
+	t.Run("epic override gates every test", func(t *testing.T) {
+		saveEpic()
+		sidecar := filepath.Join(tempDir, "epic.config.json")
+		require.NoError(t, os.WriteFile(sidecar, []byte(`{"gate_tags": ""}`), 0644))
+		defer os.Remove(sidecar)
+
+		err := run("done", "phase", "1", "--file", epicFile)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "incomplete tests")
+		assert.Empty(t, phases.GateTags())
+	})
+}
+
+func TestApplyTestGating(t *testing.T) {
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, ".agentpm.json")
+	epicFile := filepath.Join(tempDir, "epic.xml")
+	sidecar := config.EpicOverridesPath(epicFile)
+	require.NoError(t, os.WriteFile(configFile, []byte(`{"current_epic": "epic.xml"}`), 0644))
+
+	notRun := epic.Test{ID: "T1", TaskID: "1_1", PhaseID: "1", Name: "Not run", Status: epic.StatusPending, TestStatus: epic.TestStatusPending}
+	failedAt := time.Date(2025, 8, 19, 12, 0, 0, 0, time.UTC)
+	failing := epic.Test{ID: "T2", TaskID: "1_1", PhaseID: "1", Name: "Failing", Status: epic.StatusWIP, TestStatus: epic.TestStatusWIP, FailedAt: &failedAt}
+	saveEpic := func(tests ...epic.Test) {
+		e := epic.NewEpic("8", "Checkout")
+		e.Status = epic.StatusWIP
+		e.Phases = []epic.Phase{{ID: "1", Name: "Cart", Status: epic.StatusWIP}}
+		e.Tasks = []epic.Task{{ID: "1_1", PhaseID: "1", Name: "Add to cart", Status: epic.StatusCompleted}}
+		e.Tests = tests
+		require.NoError(t, storage.NewFileStorage().SaveEpic(e, epicFile))
+	}
+	app := appOptions{commands: []func() *cli.Command{DoneCommand}, config: configFile, time: "2025-08-20T12:00:00Z", hooks: []cli.BeforeFunc{ApplyTestGating}}
+	run := func(args ...string) error {
+		_, err := runApp(t, app, args...)
+		return err
+	}
+
+	t.Run("tests gate until they pass by default", func(t *testing.T) {
+		saveEpic(notRun)
+		err := run("done", "phase", "1", "--file", epicFile)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "incomplete tests")
+	})
+
+	t.Run("lenient epic override only gates on failing tests", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(sidecar, []byte(`{"test_gating": "lenient"}`), 0644))
+		defer os.Remove(sidecar)
+
+		saveEpic(notRun, failing)
+		err := run("done", "phase", "1", "--file", epicFile)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "incomplete tests")
+
+		saveEpic(notRun)
+		require.NoError(t, run("done", "phase", "1", "--file", epicFile))
+	})
+
+	t.Run("epic override turns gating off", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(sidecar, []byte(`{"test_gating": "off"}`), 0644))
+		defer os.Remove(sidecar)
+
+		saveEpic(notRun, failing)
+		require.NoError(t, run("done", "phase", "1", "--file", epicFile))
+	})
+}
+
+func TestApplyProfile(t *testing.T) {
+	t.Cleanup(func() { config.SetProfile("") })
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, ".agentpm.json")
+	require.NoError(t, os.WriteFile(configFile, []byte(`{"current_epic": "epic.xml", "profiles": {
+		"ci": {"current_epic": "ci.xml", "default_assignee": "ci-bot", "default_format": "json"}}}`), 0644))
+	for _, file := range []string{"epic.xml", "ci.xml"} {
+		e := epic.NewEpic(file, "Epic "+file)
+		require.NoError(t, storage.NewFileStorage().SaveEpic(e, filepath.Join(tempDir, file)))
+	}
+
+	run := func(args ...string) (string, error) {
+		app := &cli.Command{
+			Name:   "agentpm",
+			Before: ApplyProfile,
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config", Value: configFile},
+				&cli.StringFlag{Name: "file"},
+				&cli.StringFlag{Name: "profile", Sources: cli.EnvVars(config.ProfileEnvVar)},
+				&cli.StringFlag{Name: "format", Value: "text"},
+				&cli.StringFlag{Name: "time"},
+			},
+			Commands: []*cli.Command{ConfigCommand(), StatusCommand()},
+		}
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		app.ErrWriter = &bytes.Buffer{}
+		err := app.Run(context.Background(), append([]string{"agentpm"}, args...))
+		return stdout.String(), err
+	}
+
+	t.Run("without profile", func(t *testing.T) {
+		out, err := run("status")
+		require.NoError(t, err)
+		assert.Contains(t, out, "Epic epic.xml")
+	})
+
+	t.Run("profile flag", func(t *testing.T) {
+		out, err := run("--profile", "ci", "status")
+		require.NoError(t, err)
+		var status map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(out), &status), "default_format of the profile applies")
+		assert.Equal(t, "ci.xml", status["epic"])
+
+		out, err = run("--profile", "ci", "config", "--format", "text")
+		require.NoError(t, err)
+		assert.Contains(t, out, "Profile: ci")
+		assert.Contains(t, out, "Default assignee: ci-bot")
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv(config.ProfileEnvVar, "ci")
+		out, err := run("config")
+		require.NoError(t, err)
+		assert.Contains(t, out, `"profile": "ci"`)
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		_, err := run("--profile", "staging", "status")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown profile "staging"`)
+	})
+}
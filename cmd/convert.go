@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/mindreframer/agentpm/internal/commands"
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// convertResult describes an epic file written in another storage format
+type convertResult struct {
+	EpicID        string `json:"epic_id"`
+	From          string `json:"from"`
+	To            string `json:"to"`
+	Source        string `json:"source"`
+	Output        string `json:"output"`
+	ConfigUpdated bool   `json:"config_updated"`
+	SourceRemoved bool   `json:"source_removed"`
+}
+
+// ConvertCommand converts an epic file between storage formats
+func ConvertCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "convert",
+		Usage: "Convert an epic file to another storage format",
+		Description: `Write the epic in another storage format, next to the original file with
+the extension of the target format, or to --output.
+
+The converted file is read back and must hold exactly the epic that was
+converted; otherwise it is removed again and nothing changes. References to
+the epic in the config (current, previous and workspace epics) are updated
+to the new file, and the original file is removed unless --keep is given.
+
+The storage format of a file is told by its extension; files without a
+known extension are XML.
+
+--file - converts the epic on standard input, its format told from the
+content, and -o - writes the converted epic to standard output, the default
+for standard input. Streams leave the config and the original file alone.
+
+Examples:
+  agentpm convert --to yaml                  # epic-8.xml becomes epic-8.yaml
+  agentpm convert --to json --keep           # Keep epic-8.xml next to epic-8.json
+  agentpm convert --to xml -o epics/epic-8.xml
+  agentpm convert --to json -o - | jq .phases  # Print the epic as JSON`,
+		Flags: append(commands.GlobalFlags(),
+			&cli.StringFlag{
+				Name:     "to",
+				Usage:    fmt.Sprintf("Target storage format (%s)", storage.FormatNames()),
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Path of the converted file (default: the epic file with the target extension)",
+			},
+			&cli.BoolFlag{
+				Name:  "keep",
+				Usage: "Keep the original file",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Overwrite an existing output file",
+			},
+		),
+		Action: withQuietResult(convertAction),
+	}
+}
+
+func convertAction(ctx context.Context, c *cli.Command) error {
+	target, err := storage.ParseFormat(c.String("to"))
+	if err != nil {
+		return exitcode.Errorf(exitcode.Validation, "%v", err)
+	}
+
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+	output := c.String("output")
+	if epicFile == storage.StdioPath || output == storage.StdioPath {
+		return convertStream(c, epicFile, output, target)
+	}
+	if output == "" {
+		output = storage.WithFormat(epicFile, target)
+	}
+
+	source, err := filepath.Abs(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to resolve epic path: %w", err)
+	}
+	destination, err := filepath.Abs(output)
+	if err != nil {
+		return fmt.Errorf("failed to resolve output path: %w", err)
+	}
+	if source == destination {
+		return fmt.Errorf("epic file %s is already stored as %s", epicFile, target)
+	}
+	if _, err := os.Stat(destination); err == nil && !c.Bool("force") {
+		return fmt.Errorf("cannot convert to %s: the file already exists (use --force to overwrite it)", output)
+	}
+
+	fileStorage := storage.NewFileStorage()
+	epicData, err := fileStorage.LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+	if err := fileStorage.SaveEpic(epicData, output); err != nil {
+		return fmt.Errorf("failed to save epic: %w", err)
+	}
+
+	converted, err := fileStorage.LoadEpic(output)
+	if err == nil && !reflect.DeepEqual(epicData, converted) {
+		err = fmt.Errorf("the converted epic differs from the original")
+	}
+	if err != nil {
+		os.Remove(destination)
+		return fmt.Errorf("invalid conversion to %s, %s is unchanged: %w", target, epicFile, err)
+	}
+
+	result := convertResult{
+		EpicID: epicData.ID,
+		From:   string(storage.FormatOf(epicFile)),
+		To:     string(target),
+		Source: epicFile,
+		Output: output,
+	}
+	if result.ConfigUpdated, err = updateEpicReferences(c, source, output); err != nil {
+		return err
+	}
+	if !c.Bool("keep") {
+		if err := os.Remove(source); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", epicFile, err)
+		}
+		result.SourceRemoved = true
+	}
+
+	return writeConvertResult(c, result)
+}
+
+// convertStream converts an epic read from or written to a stream, where
+// there is no epic file to replace
+func convertStream(c *cli.Command, epicFile, output string, target storage.Format) error {
+	if output == "" {
+		output = storage.StdioPath
+	}
+	if output != storage.StdioPath {
+		if _, err := os.Stat(output); err == nil && !c.Bool("force") {
+			return fmt.Errorf("cannot convert to %s: the file already exists (use --force to overwrite it)", output)
+		}
+	}
+
+	source := storage.FormatOf(epicFile)
+	var epicData *epic.Epic
+	if epicFile == storage.StdioPath {
+		input, format, err := readEpicInput(c, epicFile)
+		if err != nil {
+			return err
+		}
+		if epicData, err = storage.DecodeEpic(input, format); err != nil {
+			return fmt.Errorf("failed to load epic: %w", err)
+		}
+		source = format
+	} else {
+		var err error
+		if epicData, err = storage.NewFileStorage().LoadEpic(epicFile); err != nil {
+			return fmt.Errorf("failed to load epic: %w", err)
+		}
+	}
+	data, err := storage.EncodeEpic(epicData, target)
+	if err != nil {
+		return err
+	}
+	converted, err := storage.DecodeEpic(data, target)
+	if err == nil && !reflect.DeepEqual(epicData, converted) {
+		err = fmt.Errorf("the converted epic differs from the original")
+	}
+	if err != nil {
+		return fmt.Errorf("invalid conversion to %s: %w", target, err)
+	}
+
+	if output == storage.StdioPath {
+		_, err = c.Root().Writer.Write(data)
+		return err
+	}
+	if err := writeToFile(output, string(data)); err != nil {
+		return fmt.Errorf("failed to write epic to file: %w", err)
+	}
+	return writeConvertResult(c, convertResult{
+		EpicID: epicData.ID,
+		From:   string(source),
+		To:     string(target),
+		Source: epicFile,
+		Output: output,
+	})
+}
+
+// updateEpicReferences points the config's references to the epic at source
+// to output. Without a config there is nothing to update.
+func updateEpicReferences(c *cli.Command, source, output string) (bool, error) {
+	configPath := c.String("config")
+	absConfig, err := config.ResolveConfigPath(configPath)
+	if err != nil {
+		return false, err
+	}
+	if _, err := os.Stat(absConfig); err != nil {
+		return false, nil
+	}
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if !cfg.ReplaceEpic(source, output) {
+		return false, nil
+	}
+	if err := config.SaveConfig(cfg, configPath); err != nil {
+		return false, fmt.Errorf("failed to save configuration: %w", err)
+	}
+	return true, nil
+}
+
+func writeConvertResult(c *cli.Command, result convertResult) error {
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal result to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		fmt.Fprintf(c.Root().Writer, "<epic_converted epic=\"%s\" from=\"%s\" to=\"%s\" source=\"%s\" output=\"%s\" config_updated=\"%t\" source_removed=\"%t\"/>\n",
+			result.EpicID, result.From, result.To, result.Source, result.Output, result.ConfigUpdated, result.SourceRemoved)
+	default:
+		fmt.Fprintf(c.Root().Writer, "Converted epic %s from %s to %s: %s\n", result.EpicID, result.From, result.To, result.Output)
+		if result.ConfigUpdated {
+			fmt.Fprintf(c.Root().Writer, "Updated the config to use %s.\n", result.Output)
+		}
+		if result.SourceRemoved {
+			fmt.Fprintf(c.Root().Writer, "Removed %s.\n", result.Source)
+		}
+	}
+	return nil
+}
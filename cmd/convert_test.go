@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertCommand(t *testing.T) {
+	run := func(t *testing.T, configPath string, args ...string) (string, error) {
+		var stdout bytes.Buffer
+		cmd := ConvertCommand()
+		cmd.Root().Writer = &stdout
+		err := cmd.Run(context.Background(), append([]string{"convert", "--config", configPath}, args...))
+		return stdout.String(), err
+	}
+
+	setup := func(t *testing.T) (string, string) {
+		dir := t.TempDir()
+		epicFile := filepath.Join(dir, "epic.xml")
+		require.NoError(t, storage.NewFileStorage().SaveEpic(createEpicForReset(), epicFile))
+
+		configPath := filepath.Join(dir, ".agentpm.json")
+		cfg := config.DefaultConfig()
+		cfg.CurrentEpic = "epic.xml"
+		cfg.PreviousEpic = "other.xml"
+		cfg.Epics = []string{"other.xml", "epic.xml"}
+		require.NoError(t, config.SaveConfig(cfg, configPath))
+		return configPath, epicFile
+	}
+
+	t.Run("writes the epic to the new file and updates the config", func(t *testing.T) {
+		configPath, epicFile := setup(t)
+		output := filepath.Join(filepath.Dir(epicFile), "epics", "epic-1.xml")
+		require.NoError(t, os.MkdirAll(filepath.Dir(output), 0755))
+
+		stdout, err := run(t, configPath, "--to", "xml", "-o", output)
+		require.NoError(t, err)
+		assert.Contains(t, stdout, "Converted epic epic-1 from xml to xml")
+		assert.Contains(t, stdout, "Updated the config")
+
+		assert.NoFileExists(t, epicFile)
+		converted, err := storage.NewFileStorage().LoadEpic(output)
+		require.NoError(t, err)
+		assert.Equal(t, createEpicForReset().Tasks, converted.Tasks)
+
+		cfg, err := config.LoadConfig(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, "epics/epic-1.xml", cfg.CurrentEpic)
+		assert.Equal(t, "other.xml", cfg.PreviousEpic)
+		assert.Equal(t, []string{"other.xml", "epics/epic-1.xml"}, cfg.Epics)
+	})
+
+	t.Run("keeps the original file", func(t *testing.T) {
+		configPath, epicFile := setup(t)
+		output := filepath.Join(filepath.Dir(epicFile), "copy.xml")
+
+		_, err := run(t, configPath, "--to", "xml", "-o", output, "--keep")
+		require.NoError(t, err)
+		assert.FileExists(t, epicFile)
+		assert.FileExists(t, output)
+	})
+
+	t.Run("rejects unsupported formats", func(t *testing.T) {
+		configPath, epicFile := setup(t)
+
+		_, err := run(t, configPath, "--to", "toml")
+		require.Error(t, err)
+		assert.Equal(t, exitcode.Validation, ExitCode(err))
+		assert.Contains(t, err.Error(), `unsupported epic format "toml"`)
+		assert.FileExists(t, epicFile)
+	})
+
+	t.Run("refuses to overwrite files", func(t *testing.T) {
+		configPath, epicFile := setup(t)
+
+		_, err := run(t, configPath, "--to", "xml")
+		require.Error(t, err)
+		assert.Equal(t, exitcode.Constraint, ExitCode(err))
+		assert.Contains(t, err.Error(), "already stored as xml")
+
+		output := filepath.Join(filepath.Dir(epicFile), "existing.xml")
+		require.NoError(t, os.WriteFile(output, []byte("keep me"), 0644))
+		_, err = run(t, configPath, "--to", "xml", "-o", output)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "use --force")
+
+		data, err := os.ReadFile(output)
+		require.NoError(t, err)
+		assert.Equal(t, "keep me", string(data))
+	})
+}
+
+func TestConvertCommandBetweenFormats(t *testing.T) {
+	dir := t.TempDir()
+	epicFile := filepath.Join(dir, "epic.xml")
+	fileStorage := storage.NewFileStorage()
+	require.NoError(t, fileStorage.SaveEpic(createEpicForReset(), epicFile))
+	original, err := fileStorage.LoadEpic(epicFile)
+	require.NoError(t, err)
+
+	current := epicFile
+	for _, format := range []string{"yaml", "json", "xml"} {
+		var stdout bytes.Buffer
+		cmd := ConvertCommand()
+		cmd.Root().Writer = &stdout
+		require.NoError(t, cmd.Run(context.Background(), []string{"convert", "--config", filepath.Join(dir, "missing.json"), "--file", current, "--to", format}))
+
+		converted := filepath.Join(dir, "epic."+format)
+		assert.NoFileExists(t, current)
+		loaded, err := fileStorage.LoadEpic(converted)
+		require.NoError(t, err)
+		assert.Equal(t, original, loaded, "format %s", format)
+		current = converted
+	}
+}
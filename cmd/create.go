@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/mindreframer/agentpm/internal/templates"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/term"
+)
+
+// CreateCommand creates new project files from templates
+func CreateCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "create",
+		Usage: "Create a new epic from a template",
+		Description: `Create new project files from templates.
+
+Subcommands:
+  epic [file]       Scaffold a new epic file from a template
+
+Examples:
+  agentpm create epic epic-9.xml --id 9 --name "Search" --no-input
+  agentpm create epic --template service
+  agentpm create epic epic-9.xml --template ./templates/api.xml`,
+		Commands: []*cli.Command{
+			{
+				Name:      "epic",
+				Usage:     "Scaffold a new epic file from a template",
+				ArgsUsage: "[file]",
+				Description: `Scaffold a new epic file with the phase, task and test stubs of a template.
+
+--template takes the name of a built-in or local template (see 'agentpm
+template list', default basic) or the path of a template file. The epic's
+ID, name and description replace the template's.
+
+In a terminal, missing values are asked for interactively. Agents and
+scripts pass them as flags with --no-input (or AGENTPM_NO_INPUT=1): the ID
+then defaults to the file name and the name and description to the
+template's. Existing files are only replaced with --force.`,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "template",
+						Aliases: []string{"t"},
+						Usage:   "Template name or template file path",
+						Value:   "basic",
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Epic file to write (alternative to the file argument)",
+					},
+					&cli.StringFlag{
+						Name:  "id",
+						Usage: "Epic ID (default: derived from the file name)",
+					},
+					&cli.StringFlag{
+						Name:  "name",
+						Usage: "Epic name (default: the template's)",
+					},
+					&cli.StringFlag{
+						Name:  "description",
+						Usage: "Epic description (default: the template's)",
+					},
+					&cli.BoolFlag{
+						Name:    "no-input",
+						Usage:   "Never prompt; use flags and defaults only",
+						Sources: cli.EnvVars("AGENTPM_NO_INPUT"),
+					},
+					&cli.BoolFlag{
+						Name:  "force",
+						Usage: "Replace an existing epic file",
+					},
+				},
+				Action: withQuietResult(createEpicAction),
+			},
+		},
+	}
+}
+
+func createEpicAction(ctx context.Context, c *cli.Command) error {
+	if c.Args().Len() > 1 {
+		return exitcode.Errorf(exitcode.Validation, "at most one epic file is allowed")
+	}
+	output := c.Args().First()
+	if flagOutput := c.String("output"); flagOutput != "" {
+		if output != "" && output != flagOutput {
+			return exitcode.Errorf(exitcode.Validation, "epic file given both as argument (%s) and with --output (%s)", output, flagOutput)
+		}
+		output = flagOutput
+	}
+
+	data, err := loadCreateTemplate(c, c.String("template"))
+	if err != nil {
+		return err
+	}
+
+	opts := templates.Options{
+		ID:          c.String("id"),
+		Name:        c.String("name"),
+		Description: c.String("description"),
+	}
+	if !c.Bool("no-input") && !c.Bool("quiet") && isTerminal(c.Root().Reader) {
+		_, templateName := templates.Attrs(data)
+		if output, err = promptEpicOptions(c.Root().Reader, c.Root().ErrWriter, output, templateName, &opts); err != nil {
+			return err
+		}
+	}
+	if output == "" {
+		return fmt.Errorf("an epic file is required (argument or --output)")
+	}
+	if opts.ID == "" {
+		opts.ID = epicIDFromFileName(output)
+	}
+
+	fs := storage.NewFileStorage()
+	if fs.EpicExists(output) && !c.Bool("force") {
+		return fmt.Errorf("epic file already exists: %s (use --force to replace it)", output)
+	}
+
+	opts.CreatedAt = time.Now()
+	if timeStr := c.String("time"); timeStr != "" {
+		if opts.CreatedAt, err = time.Parse(time.RFC3339, timeStr); err != nil {
+			return fmt.Errorf("invalid time format: %s (use ISO 8601 format like 2025-08-16T15:30:00Z)", timeStr)
+		}
+	}
+
+	content, err := templates.InstantiateWith(data, opts)
+	if err != nil {
+		return fmt.Errorf("invalid template %s: %w", c.String("template"), err)
+	}
+	if dir := filepath.Dir(output); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create epic directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(output, content, 0644); err != nil {
+		return fmt.Errorf("failed to write epic file: %w", err)
+	}
+	epicData, err := fs.LoadEpic(output)
+	if err != nil {
+		return fmt.Errorf("template %s does not produce a loadable epic: %w", c.String("template"), err)
+	}
+
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(map[string]interface{}{
+			"epic":     epicData.ID,
+			"name":     epicData.Name,
+			"file":     output,
+			"template": c.String("template"),
+			"phases":   len(epicData.Phases),
+			"tasks":    len(epicData.Tasks),
+			"tests":    len(epicData.Tests),
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal create result to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("create_result")
+		root.CreateAttr("epic", epicData.ID)
+		root.CreateElement("name").SetText(epicData.Name)
+		root.CreateElement("file").SetText(output)
+		root.CreateElement("template").SetText(c.String("template"))
+		root.CreateElement("phases").SetText(fmt.Sprintf("%d", len(epicData.Phases)))
+		root.CreateElement("tasks").SetText(fmt.Sprintf("%d", len(epicData.Tasks)))
+		root.CreateElement("tests").SetText(fmt.Sprintf("%d", len(epicData.Tests)))
+		doc.Indent(4)
+		doc.WriteTo(c.Root().Writer)
+	default:
+		fmt.Fprintf(c.Root().Writer, "Created epic %s (%s) from template %s: %d phases, %d tasks, %d tests\n",
+			epicData.ID, epicData.Name, c.String("template"), len(epicData.Phases), len(epicData.Tasks), len(epicData.Tests))
+		fmt.Fprintf(c.Root().Writer, "Written to: %s\n", output)
+		fmt.Fprintf(c.Root().Writer, "Use it with: agentpm switch %s\n", output)
+	}
+	return nil
+}
+
+// loadCreateTemplate loads a template file when given a path, a named template otherwise
+func loadCreateTemplate(c *cli.Command, template string) ([]byte, error) {
+	if strings.ContainsAny(template, `/\`) || filepath.Ext(template) == ".xml" {
+		return templates.LoadFile(template)
+	}
+
+	templatesDir := ""
+	configPath, err := config.ResolveConfigPath(c.String("config"))
+	if err == nil && config.ConfigExists(configPath) {
+		cfg, err := config.LoadConfig(configPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load configuration: %w", err)
+		}
+		templatesDir = cfg.TemplatesDirPath()
+	}
+	return templates.Load(templatesDir, template)
+}
+
+// promptEpicOptions asks for the values not given as flags; an empty answer keeps the default
+func promptEpicOptions(r io.Reader, w io.Writer, output, templateName string, opts *templates.Options) (string, error) {
+	scanner := bufio.NewScanner(r)
+	ask := func(question, fallback string) (string, error) {
+		if fallback != "" {
+			fmt.Fprintf(w, "%s [%s]: ", question, fallback)
+		} else {
+			fmt.Fprintf(w, "%s: ", question)
+		}
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return "", fmt.Errorf("failed to read answer: %w", err)
+			}
+			return "", exitcode.Errorf(exitcode.Validation, "input ended before all values were given (use --no-input to create the epic from flags)")
+		}
+		if answer := strings.TrimSpace(scanner.Text()); answer != "" {
+			return answer, nil
+		}
+		return fallback, nil
+	}
+
+	var err error
+	if output == "" {
+		if output, err = ask("Epic file", "epic.xml"); err != nil {
+			return "", err
+		}
+	}
+	if opts.ID == "" {
+		if opts.ID, err = ask("Epic ID", epicIDFromFileName(output)); err != nil {
+			return "", err
+		}
+	}
+	if opts.Name == "" {
+		if opts.Name, err = ask("Epic name", templateName); err != nil {
+			return "", err
+		}
+	}
+	if opts.Description == "" {
+		if opts.Description, err = ask("Description (empty keeps the template's)", ""); err != nil {
+			return "", err
+		}
+	}
+	return output, nil
+}
+
+// isTerminal reports whether r is an interactive terminal
+func isTerminal(r io.Reader) bool {
+	file, ok := r.(*os.File)
+	return ok && term.IsTerminal(int(file.Fd()))
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/mindreframer/agentpm/internal/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var createApp = appOptions{commands: []func() *cli.Command{CreateCommand}, time: "2025-08-16T09:00:00Z"}
+
+func TestCreateEpicCommand(t *testing.T) {
+	t.Run("from the built-in template without input", func(t *testing.T) {
+		dir := t.TempDir()
+		epicFile := filepath.Join(dir, "epic-9.xml")
+
+		output, err := runApp(t, createApp.withConfig(filepath.Join(dir, ".agentpm.json")).withFormat("text"), "create", "epic", epicFile, "--name", "Search", "--no-input")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Created epic epic-9 (Search) from template basic: 1 phases, 1 tasks, 1 tests")
+
+		epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, "epic-9", epicData.ID)
+		assert.Equal(t, "Search", epicData.Name)
+		assert.Equal(t, "2025-08-16T09:00:00Z", epicData.CreatedAt.Format("2006-01-02T15:04:05Z"))
+	})
+
+	t.Run("from a template file", func(t *testing.T) {
+		dir := t.TempDir()
+		templateFile := filepath.Join(dir, "api.xml")
+		require.NoError(t, os.WriteFile(templateFile, []byte(`<epic id="api" name="API" status="pending">
+    <description>API skeleton</description>
+    <phases>
+        <phase id="1" name="Design" status="pending"/>
+        <phase id="2" name="Build" status="pending"/>
+    </phases>
+    <tasks/>
+    <tests/>
+    <events/>
+</epic>`), 0644))
+		epicFile := filepath.Join(dir, "out", "epic.xml")
+
+		output, err := runApp(t, createApp.withConfig(filepath.Join(dir, ".agentpm.json")).withFormat("json"), "create", "epic", "--output", epicFile, "--template", templateFile, "--id", "12", "--description", "Public API", "--no-input")
+		require.NoError(t, err)
+		var result map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.Equal(t, "12", result["epic"])
+		assert.Equal(t, "API", result["name"])
+		assert.Equal(t, float64(2), result["phases"])
+
+		epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, "Public API", strings.TrimSpace(epicData.Description))
+	})
+
+	t.Run("from a local template", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".agentpm.json"), []byte(`{"current_epic":"epic.xml"}`), 0644))
+		_, err := templates.Install(filepath.Join(dir, ".agentpm", "templates"), "service", []byte(`<epic id="service" name="Service"><phases/><tasks/><tests/><events/></epic>`), false)
+		require.NoError(t, err)
+
+		output, err := runApp(t, createApp.withConfig(filepath.Join(dir, ".agentpm.json")).withFormat("xml"), "create", "epic", filepath.Join(dir, "svc.xml"), "--template", "service", "--no-input")
+		require.NoError(t, err)
+		assert.Contains(t, output, `<create_result epic="svc">`)
+		assert.Contains(t, output, "<template>service</template>")
+	})
+
+	t.Run("refuses to replace an existing file", func(t *testing.T) {
+		dir := t.TempDir()
+		epicFile := filepath.Join(dir, "epic.xml")
+		require.NoError(t, os.WriteFile(epicFile, []byte("<epic/>"), 0644))
+
+		_, err := runApp(t, createApp.withConfig(filepath.Join(dir, ".agentpm.json")).withFormat("text"), "create", "epic", epicFile, "--no-input")
+		assert.ErrorContains(t, err, "epic file already exists")
+
+		_, err = runApp(t, createApp.withConfig(filepath.Join(dir, ".agentpm.json")).withFormat("text"), "create", "epic", epicFile, "--no-input", "--force")
+		assert.NoError(t, err)
+	})
+
+	t.Run("requires a file without input", func(t *testing.T) {
+		_, err := runApp(t, createApp.withConfig(filepath.Join(t.TempDir(), ".agentpm.json")).withFormat("text"), "create", "epic", "--no-input")
+		assert.ErrorContains(t, err, "an epic file is required")
+	})
+
+	t.Run("unknown template", func(t *testing.T) {
+		dir := t.TempDir()
+		_, err := runApp(t, createApp.withConfig(filepath.Join(dir, ".agentpm.json")).withFormat("text"), "create", "epic", filepath.Join(dir, "epic.xml"), "--template", "missing", "--no-input")
+		assert.ErrorContains(t, err, "template missing not found")
+	})
+}
+
+func TestPromptEpicOptions(t *testing.T) {
+	t.Run("asks for missing values", func(t *testing.T) {
+		var prompts bytes.Buffer
+		opts := templates.Options{Name: "Given"}
+		output, err := promptEpicOptions(strings.NewReader("search.xml\n\nFind things\n"), &prompts, "", "New Epic", &opts)
+		require.NoError(t, err)
+
+		assert.Equal(t, "search.xml", output)
+		assert.Equal(t, "search", opts.ID) // Default from the file name
+		assert.Equal(t, "Given", opts.Name)
+		assert.Equal(t, "Find things", opts.Description)
+		assert.Equal(t, "Epic file [epic.xml]: Epic ID [search]: Description (empty keeps the template's): ", prompts.String())
+	})
+
+	t.Run("input ends early", func(t *testing.T) {
+		opts := templates.Options{}
+		_, err := promptEpicOptions(strings.NewReader("search.xml\n"), &bytes.Buffer{}, "", "New Epic", &opts)
+		assert.ErrorContains(t, err, "use --no-input")
+	})
+}
@@ -2,9 +2,14 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
 
+	"github.com/beevik/etree"
 	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/hints"
 	"github.com/mindreframer/agentpm/internal/query"
 	"github.com/mindreframer/agentpm/internal/storage"
 	"github.com/urfave/cli/v3"
@@ -16,8 +21,8 @@ func CurrentCommand() *cli.Command {
 		Name:    "current",
 		Usage:   "Display current active work state",
 		Aliases: []string{"c"},
-		Action:  currentAction,
-		Flags: []cli.Flag{
+		Action:  withWatch(currentAction, currentWatchedFiles),
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:    "file",
 				Aliases: []string{"f"},
@@ -29,10 +34,19 @@ func CurrentCommand() *cli.Command {
 				Usage:   "Output format: text (default), json, xml",
 				Value:   "text",
 			},
-		},
+			assigneeFlag(),
+		}, watchFlags()...),
 	}
 }
 
+func currentWatchedFiles(c *cli.Command) ([]string, error) {
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return nil, err
+	}
+	return []string{epicFile}, nil
+}
+
 func currentAction(ctx context.Context, c *cli.Command) error {
 	// Load configuration
 	configPath := c.String("config")
@@ -48,10 +62,10 @@ func currentAction(ctx context.Context, c *cli.Command) error {
 	// Determine epic file (command flag overrides config)
 	epicFile := c.String("file")
 	if epicFile == "" {
-		epicFile = cfg.CurrentEpic
+		epicFile = cfg.EpicFilePath()
 	}
 	if epicFile == "" {
-		return fmt.Errorf("no epic file specified. Use --file flag or run 'agentpm init' first")
+		return exitcode.Errorf(exitcode.Validation, "no epic file specified. Use --file flag or run 'agentpm init' first")
 	}
 
 	// Create storage and query service
@@ -63,6 +77,11 @@ func currentAction(ctx context.Context, c *cli.Command) error {
 	if err != nil {
 		return fmt.Errorf("failed to load epic: %w", err)
 	}
+	if agent := c.String("assignee"); agent != "" {
+		if err := queryService.FilterAssignee(agent); err != nil {
+			return err
+		}
+	}
 
 	// Get current state
 	state, err := queryService.GetCurrentState()
@@ -70,19 +89,47 @@ func currentAction(ctx context.Context, c *cli.Command) error {
 		return fmt.Errorf("failed to get current state: %w", err)
 	}
 
+	// Top hint for the active work
+	hint := currentWorkHint(queryService, state)
+
 	// Output based on format
 	outputFormat := c.String("format")
 	switch outputFormat {
 	case "xml":
-		return outputCurrentXML(c, state)
+		return outputCurrentXML(c, state, hint)
 	case "json":
-		return outputCurrentJSON(c, state)
+		return outputCurrentJSON(c, state, hint)
 	default:
-		return outputCurrentText(c, state)
+		return outputCurrentText(c, state, hint)
 	}
 }
 
-func outputCurrentText(c *cli.Command, state *query.CurrentState) error {
+// currentWorkHint generates the single most relevant hint for the current work state
+func currentWorkHint(queryService *query.QueryService, state *query.CurrentState) *hints.Hint {
+	epicData, err := queryService.GetEpic()
+	if err != nil {
+		return nil
+	}
+
+	hintCtx := &hints.HintContext{
+		Epic:          epicData,
+		OperationType: "current",
+	}
+	if state.ActivePhase != "" {
+		if phase, err := queryService.GetPhase(state.ActivePhase); err == nil {
+			hintCtx.ActivePhase = phase
+		}
+	}
+	if state.ActiveTask != "" {
+		if task, err := queryService.GetTask(state.ActiveTask); err == nil {
+			hintCtx.ActiveTask = task
+		}
+	}
+
+	return hints.DefaultHintRegistry().GenerateHint(hintCtx)
+}
+
+func outputCurrentText(c *cli.Command, state *query.CurrentState, hint *hints.Hint) error {
 	fmt.Fprintf(c.Root().Writer, "Current Work State\n")
 	fmt.Fprintf(c.Root().Writer, "Epic Status: %s\n", state.EpicStatus)
 
@@ -101,43 +148,161 @@ func outputCurrentText(c *cli.Command, state *query.CurrentState) error {
 	fmt.Fprintf(c.Root().Writer, "Failing Tests: %d\n", state.FailingTests)
 	fmt.Fprintf(c.Root().Writer, "\nNext Action: %s\n", state.NextAction)
 
+	if task := state.ActiveTaskDetails; task != nil {
+		fmt.Fprintf(c.Root().Writer, "\nTask %s: %s\n", task.ID, task.Name)
+		if task.Description != "" {
+			fmt.Fprintf(c.Root().Writer, "Description: %s\n", task.Description)
+		}
+		if task.AcceptanceCriteria != "" {
+			fmt.Fprintf(c.Root().Writer, "Acceptance Criteria: %s\n", task.AcceptanceCriteria)
+		}
+
+		fmt.Fprintf(c.Root().Writer, "\nPending Tests (%d):\n", len(state.PendingTests))
+		if len(state.PendingTests) == 0 {
+			fmt.Fprintf(c.Root().Writer, "  (none)\n")
+		}
+		for _, test := range state.PendingTests {
+			fmt.Fprintf(c.Root().Writer, "  %s - %s [%s]\n", test.ID, test.Name, test.Status)
+		}
+	}
+
+	if len(state.Blockers) > 0 {
+		fmt.Fprintf(c.Root().Writer, "\nBlockers:\n")
+		for _, blocker := range state.Blockers {
+			fmt.Fprintf(c.Root().Writer, "  - %s\n", blocker)
+		}
+	}
+
+	if hint != nil {
+		fmt.Fprintf(c.Root().Writer, "\nHint: %s\n", hint.Content)
+		if hint.Command != "" {
+			fmt.Fprintf(c.Root().Writer, "  Try: %s\n", hint.Command)
+		}
+	}
+
 	return nil
 }
 
-func outputCurrentJSON(c *cli.Command, state *query.CurrentState) error {
-	jsonOutput := fmt.Sprintf(`{
-  "epic_status": "%s",
-  "active_phase": "%s",
-  "active_task": "%s",
-  "failing_tests": %d,
-  "next_action": "%s"
-}`,
-		state.EpicStatus,
-		state.ActivePhase,
-		state.ActiveTask,
-		state.FailingTests,
-		state.NextAction,
-	)
-
-	fmt.Fprintf(c.Root().Writer, "%s\n", jsonOutput)
-	return nil
+// currentOutput is the JSON output of current
+type currentOutput struct {
+	EpicStatus   string             `json:"epic_status"`
+	ActivePhase  string             `json:"active_phase" doc:"Active phase, empty when none"`
+	ActiveTask   string             `json:"active_task" doc:"Active task, empty when none"`
+	FailingTests int                `json:"failing_tests"`
+	NextAction   string             `json:"next_action"`
+	Task         *currentTask       `json:"task,omitempty" doc:"Details of the active task"`
+	PendingTests *[]currentTest     `json:"pending_tests,omitempty" doc:"Unfinished tests of the active task, present with a task"`
+	Blockers     []string           `json:"blockers"`
+	Hint         *currentHintOutput `json:"hint,omitempty"`
 }
 
-func outputCurrentXML(c *cli.Command, state *query.CurrentState) error {
-	xmlOutput := fmt.Sprintf(`<current_state>
-    <epic_status>%s</epic_status>
-    <active_phase>%s</active_phase>
-    <active_task>%s</active_task>
-    <next_action>%s</next_action>
-    <failing_tests>%d</failing_tests>
-</current_state>`,
-		state.EpicStatus,
-		state.ActivePhase,
-		state.ActiveTask,
-		state.NextAction,
-		state.FailingTests,
-	)
-
-	fmt.Fprintf(c.Root().Writer, "%s\n", xmlOutput)
+type currentTask struct {
+	ID                 string `json:"id"`
+	PhaseID            string `json:"phase_id"`
+	Name               string `json:"name"`
+	Description        string `json:"description"`
+	AcceptanceCriteria string `json:"acceptance_criteria"`
+}
+
+type currentTest struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+type currentHintOutput struct {
+	Content string `json:"content"`
+	Command string `json:"command"`
+}
+
+func outputCurrentJSON(c *cli.Command, state *query.CurrentState, hint *hints.Hint) error {
+	output := currentOutput{
+		EpicStatus:   string(state.EpicStatus),
+		ActivePhase:  state.ActivePhase,
+		ActiveTask:   state.ActiveTask,
+		FailingTests: state.FailingTests,
+		NextAction:   state.NextAction,
+		Blockers:     nonNilStrings(state.Blockers),
+	}
+
+	if task := state.ActiveTaskDetails; task != nil {
+		output.Task = &currentTask{
+			ID:                 task.ID,
+			PhaseID:            task.PhaseID,
+			Name:               task.Name,
+			Description:        task.Description,
+			AcceptanceCriteria: task.AcceptanceCriteria,
+		}
+
+		pendingTests := make([]currentTest, 0, len(state.PendingTests))
+		for _, test := range state.PendingTests {
+			pendingTests = append(pendingTests, currentTest{
+				ID:     test.ID,
+				Name:   test.Name,
+				Status: string(test.Status),
+			})
+		}
+		output.PendingTests = &pendingTests
+	}
+
+	if hint != nil {
+		output.Hint = &currentHintOutput{Content: hint.Content, Command: hint.Command}
+	}
+
+	jsonData, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal current state to JSON: %w", err)
+	}
+	fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
 	return nil
 }
+
+func outputCurrentXML(c *cli.Command, state *query.CurrentState, hint *hints.Hint) error {
+	doc := etree.NewDocument()
+	root := doc.CreateElement("current_state")
+	root.CreateElement("epic_status").SetText(string(state.EpicStatus))
+	root.CreateElement("active_phase").SetText(state.ActivePhase)
+	root.CreateElement("active_task").SetText(state.ActiveTask)
+	root.CreateElement("next_action").SetText(state.NextAction)
+	root.CreateElement("failing_tests").SetText(strconv.Itoa(state.FailingTests))
+
+	if task := state.ActiveTaskDetails; task != nil {
+		taskElem := root.CreateElement("task")
+		taskElem.CreateAttr("id", task.ID)
+		taskElem.CreateAttr("phase_id", task.PhaseID)
+		taskElem.CreateElement("name").SetText(task.Name)
+		if task.Description != "" {
+			taskElem.CreateElement("description").SetText(task.Description)
+		}
+		if task.AcceptanceCriteria != "" {
+			taskElem.CreateElement("acceptance_criteria").SetText(task.AcceptanceCriteria)
+		}
+
+		testsElem := root.CreateElement("pending_tests")
+		for _, test := range state.PendingTests {
+			testElem := testsElem.CreateElement("test")
+			testElem.CreateAttr("id", test.ID)
+			testElem.CreateAttr("status", string(test.Status))
+			testElem.SetText(test.Name)
+		}
+	}
+
+	if len(state.Blockers) > 0 {
+		blockersElem := root.CreateElement("blockers")
+		for _, blocker := range state.Blockers {
+			blockersElem.CreateElement("blocker").SetText(blocker)
+		}
+	}
+
+	if hint != nil {
+		hintElem := root.CreateElement("hint")
+		hintElem.CreateElement("content").SetText(hint.Content)
+		if hint.Command != "" {
+			hintElem.CreateElement("command").SetText(hint.Command)
+		}
+	}
+
+	doc.Indent(4)
+	_, err := doc.WriteTo(c.Root().Writer)
+	return err
+}
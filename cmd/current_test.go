@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/beevik/etree"
 	"github.com/mindreframer/agentpm/internal/config"
 	"github.com/mindreframer/agentpm/internal/epic"
 	"github.com/mindreframer/agentpm/internal/storage"
@@ -282,6 +283,124 @@ func TestCurrentCommand(t *testing.T) {
 	})
 }
 
+func createActionableEpicForCurrent() *epic.Epic {
+	failedAt := time.Date(2025, 8, 16, 10, 0, 0, 0, time.UTC)
+	return &epic.Epic{
+		ID:     "actionable-epic",
+		Name:   "Actionable Epic",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{
+			{ID: "P1", Name: "Implementation Phase", Status: epic.StatusWIP},
+		},
+		Tasks: []epic.Task{
+			{
+				ID:                 "T1",
+				PhaseID:            "P1",
+				Name:               "Build login form",
+				Description:        "Create the login form component",
+				AcceptanceCriteria: "Form validates email and password",
+				Status:             epic.StatusWIP,
+			},
+		},
+		Tests: []epic.Test{
+			{ID: "TEST1", TaskID: "T1", PhaseID: "P1", Name: "Form renders", Status: epic.StatusCompleted, TestStatus: epic.TestStatusDone},
+			{ID: "TEST2", TaskID: "T1", PhaseID: "P1", Name: "Email validation", Status: epic.StatusWIP, TestStatus: epic.TestStatusWIP, FailedAt: &failedAt, FailureNote: "regex too strict"},
+			{ID: "TEST3", TaskID: "T1", PhaseID: "P1", Name: "Password validation", Status: epic.StatusPending, TestStatus: epic.TestStatusPending},
+		},
+		Events: []epic.Event{
+			{ID: "E1", Type: "blocker", Timestamp: failedAt, Data: "Waiting for design review"},
+		},
+	}
+}
+
+func TestCurrentCommandActionableContext(t *testing.T) {
+	setupEpic := func(t *testing.T, testEpic *epic.Epic) {
+		tempDir := t.TempDir()
+		oldWd, _ := os.Getwd()
+		t.Cleanup(func() { os.Chdir(oldWd) })
+		os.Chdir(tempDir)
+
+		epicPath := filepath.Join(tempDir, "actionable.xml")
+		require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicPath))
+		require.NoError(t, config.SaveConfig(&config.Config{CurrentEpic: epicPath}, filepath.Join(tempDir, ".agentpm.json")))
+	}
+	setup := func(t *testing.T) { setupEpic(t, createActionableEpicForCurrent()) }
+
+	run := func(t *testing.T, args ...string) string {
+		var stdout, stderr bytes.Buffer
+		cmd := CurrentCommand()
+		cmd.Root().Writer = &stdout
+		cmd.Root().ErrWriter = &stderr
+		require.NoError(t, cmd.Run(context.Background(), append([]string{"current"}, args...)))
+		return stdout.String()
+	}
+
+	t.Run("text format includes task context, tests, blockers and hint", func(t *testing.T) {
+		setup(t)
+		output := run(t)
+
+		assert.Contains(t, output, "Task T1: Build login form")
+		assert.Contains(t, output, "Description: Create the login form component")
+		assert.Contains(t, output, "Acceptance Criteria: Form validates email and password")
+		assert.Contains(t, output, "Pending Tests (2):")
+		assert.Contains(t, output, "TEST2 - Email validation")
+		assert.Contains(t, output, "TEST3 - Password validation")
+		assert.NotContains(t, output, "TEST1 - Form renders")
+		assert.Contains(t, output, "Failing test TEST2: Email validation (regex too strict)")
+		assert.Contains(t, output, "Waiting for design review")
+		assert.Contains(t, output, "Hint: Test TEST2 is failing")
+		assert.Contains(t, output, "Try: agentpm pass TEST2")
+	})
+
+	t.Run("json format includes task context", func(t *testing.T) {
+		setup(t)
+		output := run(t, "--format", "json")
+
+		assert.Contains(t, output, `"acceptance_criteria": "Form validates email and password"`)
+		assert.Contains(t, output, `"pending_tests": [`)
+		assert.Contains(t, output, `"id": "TEST3"`)
+		assert.Contains(t, output, `"Waiting for design review"`)
+		assert.Contains(t, output, `"command": "agentpm pass TEST2"`)
+	})
+
+	t.Run("xml format includes task context", func(t *testing.T) {
+		setup(t)
+		output := run(t, "--format", "xml")
+
+		assert.Contains(t, output, `<task id="T1" phase_id="P1">`)
+		assert.Contains(t, output, `<acceptance_criteria>Form validates email and password</acceptance_criteria>`)
+		assert.Contains(t, output, `<test id="TEST3" status="pending">Password validation</test>`)
+		assert.Contains(t, output, `<blocker>Waiting for design review</blocker>`)
+		assert.Contains(t, output, `<command>agentpm pass TEST2</command>`)
+	})
+
+	t.Run("xml format escapes markup characters", func(t *testing.T) {
+		testEpic := createActionableEpicForCurrent()
+		testEpic.Tasks[0].Description = "Keep p95 < 200ms & cache <form> markup"
+		testEpic.Tasks[0].AcceptanceCriteria = `Errors say "invalid" & stay <200ms`
+		testEpic.Tests[1].FailureNote = "expected <nil> & got err"
+		testEpic.Tests[2].Name = "Password <min> & <max>"
+		testEpic.Events[0].Data = "Waiting for R&D <design> review"
+		setupEpic(t, testEpic)
+		output := run(t, "--format", "xml")
+
+		assert.Contains(t, output, `<description>Keep p95 &lt; 200ms &amp; cache &lt;form&gt; markup</description>`)
+
+		doc := etree.NewDocument()
+		require.NoError(t, doc.ReadFromString(output))
+		root := doc.SelectElement("current_state")
+		require.NotNil(t, root)
+		assert.Equal(t, "Keep p95 < 200ms & cache <form> markup", root.FindElement("task/description").Text())
+		assert.Equal(t, `Errors say "invalid" & stay <200ms`, root.FindElement("task/acceptance_criteria").Text())
+		assert.Equal(t, "Password <min> & <max>", root.FindElement("pending_tests/test[@id='TEST3']").Text())
+		var blockers []string
+		for _, blocker := range root.FindElements("blockers/blocker") {
+			blockers = append(blockers, blocker.Text())
+		}
+		assert.Equal(t, []string{"Failing test TEST2: Email validation (expected <nil> & got err)", "Waiting for R&D <design> review"}, blockers)
+	})
+}
+
 func TestCurrentCommandPerformance(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping performance tests in short mode")
@@ -325,3 +444,41 @@ func TestCurrentCommandPerformance(t *testing.T) {
 		t.Logf("Current command executed in: %v", duration)
 	})
 }
+
+func TestCurrentCommandFromNestedDirectory(t *testing.T) {
+	tempDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+
+	// Project root holds the config and a relative epic path
+	testEpic := createTestEpicForCurrent()
+	storage := storage.NewFileStorage()
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "epics"), 0755))
+	err = storage.SaveEpic(testEpic, filepath.Join(tempDir, "epics", "test-epic.xml"))
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		CurrentEpic:     "epics/test-epic.xml",
+		DefaultAssignee: "test_agent",
+	}
+	err = config.SaveConfig(cfg, filepath.Join(tempDir, ".agentpm.json"))
+	require.NoError(t, err)
+
+	nested := filepath.Join(tempDir, "src", "internal")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	require.NoError(t, os.Chdir(nested))
+
+	var stdout, stderr bytes.Buffer
+	cmd := CurrentCommand()
+	cmd.Root().Writer = &stdout
+	cmd.Root().ErrWriter = &stderr
+
+	err = cmd.Run(context.Background(), []string{"current"})
+	require.NoError(t, err)
+
+	output := stdout.String()
+	assert.Contains(t, output, "Current Work State")
+	assert.Contains(t, output, "Active Task: T2")
+}
@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/diff"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// DiffCommand shows what changed between two snapshots of an epic
+func DiffCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "diff",
+		Usage:     "Show what changed between two snapshots of an epic",
+		ArgsUsage: "<old.xml> [<new.xml>]",
+		Description: `Compare an earlier snapshot of an epic - a backup, a copy from git, the
+epic before an agent's session - with a later one, by default the current
+epic file (or --file), to review the work done in between:
+
+- status changes of the epic, its phases, tasks and tests (for tests also
+  their test status and result)
+- phases, tasks and tests added or removed
+- events recorded since the old snapshot
+
+Entities are matched by ID. Either snapshot may be "-" to read it from
+standard input, in any storage format.
+
+Examples:
+  agentpm diff epic-8.xml.bak.1    # What the last save changed
+  git show HEAD~1:epic-8.xml | agentpm diff - epic-8.xml
+  agentpm diff old.xml new.xml --format markdown`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config (the new snapshot)",
+			},
+		},
+		Action: diffAction,
+	}
+}
+
+func diffAction(ctx context.Context, c *cli.Command) error {
+	if c.Args().Len() < 1 || c.Args().Len() > 2 {
+		return exitcode.Errorf(exitcode.Validation, "expected one or two epic files: diff <old.xml> [<new.xml>]")
+	}
+	oldFile := c.Args().Get(0)
+	newFile := c.Args().Get(1)
+	if newFile == "" {
+		var err error
+		if newFile, err = getEpicFile(c); err != nil {
+			return err
+		}
+	}
+
+	oldEpic, err := loadEpicInput(c, oldFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic %s: %w", oldFile, err)
+	}
+	newEpic, err := loadEpicInput(c, newFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic %s: %w", newFile, err)
+	}
+
+	d := diff.Compare(oldEpic, newEpic)
+
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal diff to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		outputDiffXML(c, d, oldFile, newFile)
+	case "markdown":
+		outputDiffMarkdown(c, d, oldFile, newFile)
+	default:
+		outputDiffText(c, d, oldFile, newFile)
+	}
+	return nil
+}
+
+func outputDiffText(c *cli.Command, d *diff.Diff, oldFile, newFile string) {
+	w := c.Root().Writer
+	if d.Empty() {
+		fmt.Fprintf(w, "No changes in epic %s (%s -> %s)\n", d.EpicID, oldFile, newFile)
+		return
+	}
+	fmt.Fprintf(w, "Changes in epic %s (%s -> %s)\n", d.EpicID, oldFile, newFile)
+	writeDiffText(w, d)
+}
+
+// writeDiffText writes the sections of a diff that has changes
+func writeDiffText(w io.Writer, d *diff.Diff) {
+	if len(d.StatusChanges) > 0 {
+		fmt.Fprintf(w, "\nStatus changes (%d):\n", len(d.StatusChanges))
+		for _, change := range d.StatusChanges {
+			fmt.Fprintf(w, "  %s\n", formatStatusChange(change))
+		}
+	}
+	if len(d.Added) > 0 {
+		fmt.Fprintf(w, "\nAdded (%d):\n", len(d.Added))
+		for _, entity := range d.Added {
+			fmt.Fprintf(w, "  + %s %s  %s\n", entity.Type, entity.ID, entity.Name)
+		}
+	}
+	if len(d.Removed) > 0 {
+		fmt.Fprintf(w, "\nRemoved (%d):\n", len(d.Removed))
+		for _, entity := range d.Removed {
+			fmt.Fprintf(w, "  - %s %s  %s\n", entity.Type, entity.ID, entity.Name)
+		}
+	}
+	if len(d.NewEvents) > 0 {
+		fmt.Fprintf(w, "\nNew events (%d):\n", len(d.NewEvents))
+		for _, event := range d.NewEvents {
+			fmt.Fprintf(w, "  %s\n", formatDiffEvent(event))
+		}
+	}
+}
+
+// formatStatusChange renders a change as "task T1 (Schema): pending -> wip",
+// naming the field unless it is the status
+func formatStatusChange(change diff.StatusChange) string {
+	field := ""
+	if change.Field != "status" {
+		field = change.Field + " "
+	}
+	return fmt.Sprintf("%s %s (%s): %s%s -> %s", change.Type, change.ID, change.Name, field, statusOrNone(change.From), statusOrNone(change.To))
+}
+
+func statusOrNone(status string) string {
+	if status == "" {
+		return "none"
+	}
+	return status
+}
+
+// formatDiffEvent renders an event as "[2006-01-02 15:04 actor] type: data"
+func formatDiffEvent(event diff.Event) string {
+	header := event.Timestamp.Format("2006-01-02 15:04")
+	if event.Actor != "" {
+		header += " " + event.Actor
+	}
+	return fmt.Sprintf("[%s] %s: %s", header, event.Type, event.Data)
+}
+
+func outputDiffMarkdown(c *cli.Command, d *diff.Diff, oldFile, newFile string) {
+	md := output.NewMarkdown()
+	md.Heading(2, fmt.Sprintf("Diff: epic %s", d.EpicID))
+	md.Field("Old", oldFile)
+	md.Field("New", newFile)
+
+	if d.Empty() {
+		md.Paragraph("No changes.")
+	}
+	writeDiffMarkdown(md, d)
+	md.WriteTo(c.Root().Writer)
+}
+
+// writeDiffMarkdown adds the sections of a diff that has changes
+func writeDiffMarkdown(md *output.Markdown, d *diff.Diff) {
+	if len(d.StatusChanges) > 0 {
+		md.Heading(3, fmt.Sprintf("Status Changes (%d)", len(d.StatusChanges)))
+		for _, change := range d.StatusChanges {
+			md.Item("%s", output.Inline(formatStatusChange(change)))
+		}
+	}
+	if len(d.Added) > 0 {
+		md.Heading(3, fmt.Sprintf("Added (%d)", len(d.Added)))
+		for _, entity := range d.Added {
+			md.Item("%s %s %s", entity.Type, output.Code(entity.ID), output.Inline(entity.Name))
+		}
+	}
+	if len(d.Removed) > 0 {
+		md.Heading(3, fmt.Sprintf("Removed (%d)", len(d.Removed)))
+		for _, entity := range d.Removed {
+			md.Item("%s %s %s", entity.Type, output.Code(entity.ID), output.Inline(entity.Name))
+		}
+	}
+	if len(d.NewEvents) > 0 {
+		md.Heading(3, fmt.Sprintf("New Events (%d)", len(d.NewEvents)))
+		for _, event := range d.NewEvents {
+			md.Item("%s", output.Inline(formatDiffEvent(event)))
+		}
+	}
+}
+
+func outputDiffXML(c *cli.Command, d *diff.Diff, oldFile, newFile string) {
+	doc := etree.NewDocument()
+	root := doc.CreateElement("diff")
+	root.CreateAttr("epic", d.EpicID)
+	root.CreateAttr("old", oldFile)
+	root.CreateAttr("new", newFile)
+	addDiffElements(root, d)
+
+	doc.Indent(4)
+	doc.WriteTo(c.Root().Writer)
+}
+
+// addDiffElements adds the changes of a diff to an XML element
+func addDiffElements(root *etree.Element, d *diff.Diff) {
+	for _, change := range d.StatusChanges {
+		elem := root.CreateElement("status_change")
+		elem.CreateAttr("type", change.Type)
+		elem.CreateAttr("id", change.ID)
+		elem.CreateAttr("field", change.Field)
+		elem.CreateAttr("from", change.From)
+		elem.CreateAttr("to", change.To)
+		elem.SetText(change.Name)
+	}
+	for _, entity := range d.Added {
+		elem := root.CreateElement("added")
+		elem.CreateAttr("type", entity.Type)
+		elem.CreateAttr("id", entity.ID)
+		elem.SetText(entity.Name)
+	}
+	for _, entity := range d.Removed {
+		elem := root.CreateElement("removed")
+		elem.CreateAttr("type", entity.Type)
+		elem.CreateAttr("id", entity.ID)
+		elem.SetText(entity.Name)
+	}
+	for _, event := range d.NewEvents {
+		elem := root.CreateElement("event")
+		elem.CreateAttr("id", event.ID)
+		elem.CreateAttr("type", event.Type)
+		elem.CreateAttr("timestamp", event.Timestamp.Format(time.RFC3339))
+		if event.Actor != "" {
+			elem.CreateAttr("actor", event.Actor)
+		}
+		elem.SetText(event.Data)
+	}
+}
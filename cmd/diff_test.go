@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/diff"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var diffApp = appOptions{commands: []func() *cli.Command{DiffCommand}}
+
+func TestDiffCommand(t *testing.T) {
+	dir := t.TempDir()
+	oldFile := filepath.Join(dir, "old.xml")
+	newFile := filepath.Join(dir, "new.xml")
+
+	before := createEpicForReset()
+	before.Events = nil
+	after := createEpicForReset()
+	after.Events = nil
+	after.Tasks[0].Status = epic.StatusCompleted
+	after.Tasks = after.Tasks[:1]
+	after.Tests = append(after.Tests, epic.Test{ID: "T1_T9", TaskID: "T1", PhaseID: "P1", Name: "Edge cases", Status: epic.StatusPending, TestStatus: epic.TestStatusPending})
+	service.CreateEvent(after, service.EventTaskStarted, "P1", "T1", "", "", time.Date(2025, 8, 15, 9, 0, 0, 0, time.UTC))
+
+	fileStorage := storage.NewFileStorage()
+	require.NoError(t, fileStorage.SaveEpic(before, oldFile))
+	require.NoError(t, fileStorage.SaveEpic(after, newFile))
+
+	t.Run("text", func(t *testing.T) {
+		output, err := runApp(t, diffApp.withFormat("text"), "diff", oldFile, newFile)
+		require.NoError(t, err)
+		assert.Contains(t, output, "Changes in epic "+after.ID+" ("+oldFile+" -> "+newFile+")\n")
+		assert.Contains(t, output, "Status changes (1):\n  task T1 ("+after.Tasks[0].Name+"): wip -> completed\n")
+		assert.Contains(t, output, "Added (1):\n  + test T1_T9  Edge cases\n")
+		assert.Contains(t, output, "Removed (1):\n  - task T2  "+before.Tasks[1].Name+"\n")
+		assert.Contains(t, output, "New events (1):\n  [2025-08-15 09:00] task_started: ")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		output, err := runApp(t, diffApp.withFormat("json"), "diff", oldFile, newFile)
+		require.NoError(t, err)
+		var d diff.Diff
+		require.NoError(t, json.Unmarshal([]byte(output), &d))
+		assert.Equal(t, []diff.StatusChange{{Type: "task", ID: "T1", Name: after.Tasks[0].Name, Field: "status", From: "wip", To: "completed"}}, d.StatusChanges)
+		assert.Len(t, d.Added, 1)
+		assert.Len(t, d.Removed, 1)
+		assert.Len(t, d.NewEvents, 1)
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		output, err := runApp(t, diffApp.withFormat("markdown"), "diff", oldFile, newFile)
+		require.NoError(t, err)
+		assert.Contains(t, output, "## Diff: epic "+after.ID+"\n")
+		assert.Contains(t, output, "### Status Changes (1)\n")
+		assert.Contains(t, output, "- test `T1_T9` Edge cases\n")
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		output, err := runApp(t, diffApp.withFormat("xml"), "diff", oldFile, newFile)
+		require.NoError(t, err)
+		assert.Contains(t, output, `<status_change type="task" id="T1" field="status" from="wip" to="completed">`)
+		assert.Contains(t, output, `<added type="test" id="T1_T9">Edge cases</added>`)
+		assert.Contains(t, output, `<removed type="task" id="T2">`)
+		assert.Contains(t, output, `<event id="task_started_`)
+	})
+
+	t.Run("defaults to the current epic file", func(t *testing.T) {
+		output, err := runApp(t, diffApp.withFormat("text"), "diff", "--file", oldFile, oldFile)
+		require.NoError(t, err)
+		assert.Equal(t, "No changes in epic "+before.ID+" ("+oldFile+" -> "+oldFile+")\n", output)
+	})
+
+	t.Run("requires the old snapshot", func(t *testing.T) {
+		_, err := runApp(t, diffApp.withFormat("text"), "diff")
+		require.Error(t, err)
+		assert.Equal(t, exitcode.Validation, ExitCode(err))
+
+		_, err = runApp(t, diffApp.withFormat("text"), "diff", filepath.Join(dir, "missing.xml"), newFile)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to load epic "+filepath.Join(dir, "missing.xml"))
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 
 	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/exitcode"
 	"github.com/mindreframer/agentpm/internal/reports"
 	"github.com/mindreframer/agentpm/internal/storage"
 	"github.com/urfave/cli/v3"
@@ -34,7 +35,7 @@ func DocsCommand() *cli.Command {
 			&cli.StringFlag{
 				Name:    "output",
 				Aliases: []string{"o"},
-				Usage:   "Output file path (default: stdout)",
+				Usage:   "Output file path, - for stdout (default: docs_file from config, else stdout)",
 			},
 		},
 	}
@@ -55,10 +56,10 @@ func docsAction(ctx context.Context, c *cli.Command) error {
 	// Determine epic file (command flag overrides config)
 	epicFile := c.String("file")
 	if epicFile == "" {
-		epicFile = cfg.CurrentEpic
+		epicFile = cfg.EpicFilePath()
 	}
 	if epicFile == "" {
-		return fmt.Errorf("no epic file specified. Use --file flag or run 'agentpm init' first")
+		return exitcode.Errorf(exitcode.Validation, "no epic file specified. Use --file flag or run 'agentpm init' first")
 	}
 
 	// Create storage and reports service
@@ -74,6 +75,12 @@ func docsAction(ctx context.Context, c *cli.Command) error {
 	// Generate documentation based on format
 	outputFormat := c.String("format")
 	outputFile := c.String("output")
+	if outputFile == "" && cfg.DocsFile != "" {
+		outputFile = cfg.DocsFilePath()
+	}
+	if outputFile == "-" {
+		outputFile = ""
+	}
 
 	switch outputFormat {
 	case "json":
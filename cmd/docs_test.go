@@ -111,23 +111,23 @@ func TestDocsCommand(t *testing.T) {
 		assert.Contains(t, output, "## Phase Progress")
 		assert.Contains(t, output, "**Completed:** 1/3 phases")
 		assert.Contains(t, output, "| Phase | Status | Tasks | Started | Completed |")
-		assert.Contains(t, output, "| Setup Phase | ✅ completed | 1 |")
-		assert.Contains(t, output, "| Implementation Phase | 🔄 active | 2 |")
+		assert.Contains(t, output, `| <a id="phase-P1"></a>Setup Phase | ✅ completed | 1 |`)
+		assert.Contains(t, output, `| <a id="phase-P2"></a>Implementation Phase | 🔄 active | 2 |`)
 
 		// Verify task status section
 		assert.Contains(t, output, "## Task Status")
 		assert.Contains(t, output, "**Completed:** 1/4 tasks")
 		assert.Contains(t, output, "**Active Task:** T2")
 		assert.Contains(t, output, "| Task | Phase | Status | Assignee | Started | Completed |")
-		assert.Contains(t, output, "| Setup Task | P1 | ✅ completed | test_agent |")
-		assert.Contains(t, output, "| Active Task | P2 | 🔄 active | test_agent |")
+		assert.Contains(t, output, `| <a id="task-T1"></a>Setup Task | P1 | ✅ completed | test_agent |`)
+		assert.Contains(t, output, `| <a id="task-T2"></a>Active Task | P2 | 🔄 active | test_agent |`)
 
 		// Verify test results section
 		assert.Contains(t, output, "## Test Results")
 		assert.Contains(t, output, "**Summary:** 1 passing, 2 failing (3 total)")
 		assert.Contains(t, output, "| Test | Task | Status | Notes |")
-		assert.Contains(t, output, "| Setup Test | T1 | ✅ passed | — |")
-		assert.Contains(t, output, "| Active Test | T2 | ❌ failed | Connection timeout |")
+		assert.Contains(t, output, `| <a id="test-TEST1"></a>Setup Test | T1 | ✅ passed | — |`)
+		assert.Contains(t, output, `| <a id="test-TEST2"></a>Active Test | T2 | ❌ failed | Connection timeout |`)
 
 		// Verify blockers section
 		assert.Contains(t, output, "## Blockers")
@@ -411,7 +411,7 @@ func TestMarkdownGeneration(t *testing.T) {
 
 		// Should have proper table formatting
 		assert.Contains(t, markdown, "| Phase | Status | Tasks | Started | Completed |")
-		assert.Contains(t, markdown, "|-------|--------|-------|---------|----------|")
+		assert.Contains(t, markdown, "|-------|--------|-------|---------|-----------|")
 
 		// Should have status icons
 		assert.Contains(t, markdown, "✅ completed")
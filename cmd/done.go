@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/mindreframer/agentpm/internal/commands"
@@ -16,7 +17,7 @@ func DoneCommand() *cli.Command {
 Subcommands:
   epic           Complete current epic (transition from wip to done)
   phase <id>     Complete specific phase
-  task <id>      Complete specific task
+  task <id>...   Complete specific tasks
 
 Examples:
   agentpm done epic                     # Complete current epic
@@ -42,8 +43,22 @@ This command:
 - Sets the completed_at timestamp
 - Creates an automatic event log entry
 - Validates that the epic is in a valid state to complete
-- Generates a completion summary`,
-		Action: commands.CreateEpicAction(handleDoneEpic),
+- Generates a completion summary
+
+With --report the final report of the epic - statistics, timeline, decisions,
+retrospective data and the validation summary - is written to the given path,
+as HTML when it ends in .html or .htm and as Markdown otherwise.
+
+Examples:
+  agentpm done epic
+  agentpm done epic --report epic-8.report.md`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "report",
+				Usage: "Write the final report of the completed epic to this path (.html for HTML, Markdown otherwise)",
+			},
+		},
+		Action: withQuietResult(doneEpicSubcommandAction),
 	}
 }
 
@@ -55,30 +70,62 @@ func donePhaseSubcommand() *cli.Command {
 		Description: `Complete a specific phase in the epic.
 
 The phase must exist in the current epic and all its tasks must be completed or cancelled.`,
-		Action: commands.CreateEntityAction(commands.EntityTypePhase, handleDonePhase),
+		Action: withQuietResult(commands.CreateEntityAction(commands.EntityTypePhase, handleDonePhase)),
 	}
 }
 
 func doneTaskSubcommand() *cli.Command {
 	return &cli.Command{
 		Name:      "task",
-		Usage:     "Complete specific task",
-		ArgsUsage: "<task-id>",
+		Usage:     "Complete specific tasks",
+		ArgsUsage: "<task-id>...",
 		Description: `Complete a specific task in the epic.
 
-The task must exist in the current epic and be in a valid state to complete.`,
-		Action: commands.CreateEntityAction(commands.EntityTypeTask, handleDoneTask),
+The task must exist in the current epic and be in a valid state to complete.
+
+Several tasks can be completed at once, given as arguments or read with
+--from-file. They are completed in order and the epic is saved once; if any
+of them cannot be completed, none is changed and every failing task is
+reported.
+
+Examples:
+  agentpm done task 3A_1
+  agentpm done task 3A_1 3A_2 3A_3
+  agentpm done task --from-file done.txt`,
+		Flags:  []cli.Flag{fromFileFlag()},
+		Action: withQuietResult(doneTaskAction),
+	}
+}
+
+// doneTaskAction completes one task, or several in one load/save cycle
+func doneTaskAction(ctx context.Context, c *cli.Command) error {
+	fileIDs, err := idsFromFile(c)
+	if err != nil {
+		return err
+	}
+	taskIDs := uniqueIDs(append(c.Args().Slice(), fileIDs...))
+	if len(taskIDs) == 0 {
+		return fmt.Errorf("%s ID is required", commands.EntityTypeTask)
+	}
+	if len(taskIDs) == 1 {
+		return handleDoneTask(commands.ExtractRouterContext(c), taskIDs[0])
 	}
+	return handleDoneTasks(commands.ExtractRouterContext(c), taskIDs)
 }
 
 // Handler functions that bridge CLI to services
 
-func handleDoneEpic(ctx commands.RouterContext) error {
+func doneEpicSubcommandAction(ctx context.Context, c *cli.Command) error {
+	return handleDoneEpic(commands.ExtractRouterContext(c), c.String("report"))
+}
+
+func handleDoneEpic(ctx commands.RouterContext, reportPath string) error {
 	request := commands.DoneEpicRequest{
 		ConfigPath: ctx.ConfigPath,
 		EpicFile:   ctx.EpicFile,
 		Time:       ctx.Time,
 		Format:     ctx.Format,
+		Report:     reportPath,
 	}
 
 	result, err := commands.DoneEpicService(request)
@@ -93,14 +140,17 @@ func handleDoneEpic(ctx commands.RouterContext) error {
 	}
 
 	if result.Error != nil {
-		return fmt.Errorf("%s", result.Error.Message)
+		return serviceError(result.Error.Type, result.Error.Message, "")
 	}
 
 	// Normal success - epic was completed
 	if result.Result != nil {
-		fmt.Printf("Epic %s completed successfully\n", result.Result.EpicID)
+		fmt.Fprintf(ctx.Writer, "Epic %s completed successfully\n", result.Result.EpicID)
 		if result.Result.Summary != "" {
-			fmt.Printf("\nCompletion Summary:\n%s\n", result.Result.Summary)
+			fmt.Fprintf(ctx.Writer, "\nCompletion Summary:\n%s\n", result.Result.Summary)
+		}
+		if result.ReportPath != "" {
+			fmt.Fprintf(ctx.Writer, "\nFinal report written to %s\n", result.ReportPath)
 		}
 	}
 	return nil
@@ -121,7 +171,7 @@ func handleDonePhase(ctx commands.RouterContext, phaseID string) error {
 	}
 
 	if result.Error != nil {
-		return fmt.Errorf("%s", result.Error.Message)
+		return serviceError(result.Error.Type, result.Error.Message, "")
 	}
 
 	if result.IsAlreadyCompleted {
@@ -130,8 +180,8 @@ func handleDonePhase(ctx commands.RouterContext, phaseID string) error {
 	}
 
 	// Output success message
-	fmt.Printf("Phase %s completed.\n", phaseID)
-	return nil
+	fmt.Fprintf(ctx.Writer, "Phase %s completed.\n", phaseID)
+	return ctx.WriteWarnings(result.Warnings)
 }
 
 func handleDoneTask(ctx commands.RouterContext, taskID string) error {
@@ -149,7 +199,7 @@ func handleDoneTask(ctx commands.RouterContext, taskID string) error {
 	}
 
 	if result.Error != nil {
-		return fmt.Errorf("%s", result.Error.Message)
+		return serviceError(result.Error.Type, result.Error.Message, "")
 	}
 
 	if result.IsAlreadyCompleted {
@@ -158,6 +208,34 @@ func handleDoneTask(ctx commands.RouterContext, taskID string) error {
 	}
 
 	// Output success message
-	fmt.Printf("Task %s completed.\n", taskID)
-	return nil
+	fmt.Fprintf(ctx.Writer, "Task %s completed.\n", taskID)
+	return ctx.WriteWarnings(result.Warnings)
+}
+
+func handleDoneTasks(ctx commands.RouterContext, taskIDs []string) error {
+	request := commands.DoneTasksRequest{
+		TaskIDs:    taskIDs,
+		ConfigPath: ctx.ConfigPath,
+		EpicFile:   ctx.EpicFile,
+		Time:       ctx.Time,
+		Format:     ctx.Format,
+	}
+
+	result, err := commands.DoneTasksService(request)
+	if err != nil {
+		return err
+	}
+
+	if result.Error != nil {
+		return serviceError(result.Error.Type, result.Error.Message, "")
+	}
+
+	for _, taskResult := range result.Results {
+		if taskResult.IsAlreadyCompleted {
+			fmt.Fprintf(ctx.Writer, "Task %s already completed.\n", taskResult.TaskID)
+		} else {
+			fmt.Fprintf(ctx.Writer, "Task %s completed.\n", taskResult.TaskID)
+		}
+	}
+	return ctx.WriteWarnings(result.Warnings)
 }
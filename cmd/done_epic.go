@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/commands"
 	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/exitcode"
 	"github.com/mindreframer/agentpm/internal/lifecycle"
 	"github.com/mindreframer/agentpm/internal/messages"
 	"github.com/mindreframer/agentpm/internal/query"
@@ -32,7 +34,12 @@ This command:
 Examples:
   agentpm done-epic                     # Complete epic from config
   agentpm done-epic --file epic-5.xml  # Complete specific epic
-  agentpm done-epic --time 2025-08-16T15:30:00Z # Use specific timestamp`,
+  agentpm done-epic --time 2025-08-16T15:30:00Z # Use specific timestamp
+  agentpm done-epic --report epic-5.report.md  # Also write the final report
+
+With --report the final report of the epic - statistics, timeline, decisions,
+retrospective data and the validation summary - is written to the given path,
+as HTML when it ends in .html or .htm and as Markdown otherwise.`,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:    "file",
@@ -43,6 +50,10 @@ Examples:
 				Name:  "time",
 				Usage: "Specific timestamp for epic completion (ISO 8601 format, for deterministic testing)",
 			},
+			&cli.StringFlag{
+				Name:  "report",
+				Usage: "Write the final report of the completed epic to this path (.html for HTML, Markdown otherwise)",
+			},
 		},
 		Action: doneEpicAction,
 	}
@@ -66,7 +77,7 @@ func doneEpicAction(ctx context.Context, c *cli.Command) error {
 		}
 
 		if cfg.CurrentEpic == "" {
-			return fmt.Errorf("no epic file specified and no current epic in config")
+			return exitcode.Errorf(exitcode.Validation, "no epic file specified and no current epic in config")
 		}
 		epicFile = cfg.EpicFilePath()
 	}
@@ -108,22 +119,29 @@ func doneEpicAction(ctx context.Context, c *cli.Command) error {
 		return outputDoneEpicError(c, err, format)
 	}
 
+	reportPath := c.String("report")
+	if reportPath != "" {
+		if err := commands.WriteCompletionReport(storageImpl, epicFile, result, reportPath); err != nil {
+			return err
+		}
+	}
+
 	// Output the result
-	return outputDoneEpicResult(c, result, format)
+	return outputDoneEpicResult(c, result, reportPath, format)
 }
 
-func outputDoneEpicResult(c *cli.Command, result *lifecycle.DoneEpicResult, format string) error {
+func outputDoneEpicResult(c *cli.Command, result *lifecycle.DoneEpicResult, reportPath, format string) error {
 	switch format {
 	case "json":
-		return outputDoneEpicJSON(c, result)
+		return outputDoneEpicJSON(c, result, reportPath)
 	case "xml":
-		return outputDoneEpicXML(c, result)
+		return outputDoneEpicXML(c, result, reportPath)
 	default:
-		return outputDoneEpicText(c, result)
+		return outputDoneEpicText(c, result, reportPath)
 	}
 }
 
-func outputDoneEpicText(c *cli.Command, result *lifecycle.DoneEpicResult) error {
+func outputDoneEpicText(c *cli.Command, result *lifecycle.DoneEpicResult, reportPath string) error {
 	fmt.Fprintf(c.Root().Writer, "Epic %s completed successfully\n", result.EpicID)
 	fmt.Fprintf(c.Root().Writer, "Status: %s → %s\n", result.PreviousStatus, result.NewStatus)
 	fmt.Fprintf(c.Root().Writer, "Completed at: %s\n", result.CompletedAt.Format(time.RFC3339))
@@ -139,21 +157,29 @@ func outputDoneEpicText(c *cli.Command, result *lifecycle.DoneEpicResult) error
 		fmt.Fprintf(c.Root().Writer, "\nCompletion Summary:\n%s\n", result.Summary)
 	}
 
+	if reportPath != "" {
+		fmt.Fprintf(c.Root().Writer, "\nFinal report written to %s\n", reportPath)
+	}
+
 	return nil
 }
 
-func outputDoneEpicJSON(c *cli.Command, result *lifecycle.DoneEpicResult) error {
+func outputDoneEpicJSON(c *cli.Command, result *lifecycle.DoneEpicResult, reportPath string) error {
+	completed := map[string]interface{}{
+		"epic_id":         result.EpicID,
+		"previous_status": result.PreviousStatus.String(),
+		"new_status":      result.NewStatus.String(),
+		"completed_at":    result.CompletedAt.Format(time.RFC3339),
+		"duration":        result.Duration.String(),
+		"event_created":   result.EventCreated,
+		"message":         result.Message,
+		"summary":         result.Summary,
+	}
+	if reportPath != "" {
+		completed["report"] = reportPath
+	}
 	output := map[string]interface{}{
-		"epic_completed": map[string]interface{}{
-			"epic_id":         result.EpicID,
-			"previous_status": result.PreviousStatus.String(),
-			"new_status":      result.NewStatus.String(),
-			"completed_at":    result.CompletedAt.Format(time.RFC3339),
-			"duration":        result.Duration.String(),
-			"event_created":   result.EventCreated,
-			"message":         result.Message,
-			"summary":         result.Summary,
-		},
+		"epic_completed": completed,
 	}
 
 	encoder := json.NewEncoder(c.Root().Writer)
@@ -161,7 +187,7 @@ func outputDoneEpicJSON(c *cli.Command, result *lifecycle.DoneEpicResult) error
 	return encoder.Encode(output)
 }
 
-func outputDoneEpicXML(c *cli.Command, result *lifecycle.DoneEpicResult) error {
+func outputDoneEpicXML(c *cli.Command, result *lifecycle.DoneEpicResult, reportPath string) error {
 	doc := etree.NewDocument()
 	root := doc.CreateElement("epic_completed")
 	root.SetText("\n    ")
@@ -197,6 +223,11 @@ func outputDoneEpicXML(c *cli.Command, result *lifecycle.DoneEpicResult) error {
 		summary.SetText(result.Summary)
 	}
 
+	if reportPath != "" {
+		report := root.CreateElement("report")
+		report.SetText(reportPath)
+	}
+
 	doc.Indent(4)
 	doc.WriteTo(c.Root().Writer)
 	fmt.Fprintf(c.Root().Writer, "\n") // Add newline
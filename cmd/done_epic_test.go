@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -269,6 +270,108 @@ func TestDoneEpicCommand_XMLOutput(t *testing.T) {
 	assert.Contains(t, output, "<event_created>true</event_created>")
 }
 
+func TestDoneEpicCommand_Report(t *testing.T) {
+	newReportEpic := func(t *testing.T, dir string) string {
+		epicFile := filepath.Join(dir, "test-epic.xml")
+		writeTestEpicXML(t, epicFile, &epic.Epic{
+			ID:     "epic-report",
+			Name:   "Report Epic",
+			Status: epic.StatusWIP,
+			Phases: []epic.Phase{
+				{ID: "phase-1", Name: "Phase 1", Status: epic.StatusCompleted},
+			},
+			Tasks: []epic.Task{
+				{ID: "task-1", PhaseID: "phase-1", Name: "Task 1", Status: epic.StatusCompleted},
+			},
+			Tests: []epic.Test{
+				{ID: "test-1", TaskID: "task-1", Name: "Test 1", Status: epic.StatusCompleted},
+			},
+		})
+		return epicFile
+	}
+	runDoneEpic := func(t *testing.T, format string, args ...string) string {
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "format", Value: format},
+			},
+			Commands: []*cli.Command{
+				DoneEpicCommand(),
+			},
+		}
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		require.NoError(t, app.Run(context.Background(), append([]string{"agentpm", "done-epic"}, args...)))
+		return stdout.String()
+	}
+
+	t.Run("writes a Markdown report", func(t *testing.T) {
+		dir := t.TempDir()
+		epicFile := newReportEpic(t, dir)
+		reportFile := filepath.Join(dir, "epic-report.report.md")
+
+		output := runDoneEpic(t, "text", "--file", epicFile, "--time", "2025-08-16T15:30:00Z", "--report", reportFile)
+		assert.Contains(t, output, "Final report written to "+reportFile)
+
+		content, err := os.ReadFile(reportFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "# Completion Report: Report Epic")
+		assert.Contains(t, string(content), "**Completed:** 2025-08-16T15:30:00Z")
+		assert.Contains(t, string(content), "| Tasks | 1 | 1 | 0 |")
+		assert.Contains(t, string(content), "## Retrospective")
+	})
+
+	t.Run("writes an HTML report for an .html path", func(t *testing.T) {
+		dir := t.TempDir()
+		epicFile := newReportEpic(t, dir)
+		reportFile := filepath.Join(dir, "epic-report.html")
+
+		output := runDoneEpic(t, "json", "--file", epicFile, "--report", reportFile)
+		var result map[string]map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.Equal(t, reportFile, result["epic_completed"]["report"])
+
+		content, err := os.ReadFile(reportFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "<!DOCTYPE html>")
+		assert.Contains(t, string(content), "<h1>Completion Report: Report Epic</h1>")
+	})
+
+	t.Run("done epic writes the report too", func(t *testing.T) {
+		dir := t.TempDir()
+		epicFile := newReportEpic(t, dir)
+		reportFile := filepath.Join(dir, "epic-report.report.md")
+
+		app := &cli.Command{Name: "agentpm", Commands: []*cli.Command{DoneCommand()}}
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		require.NoError(t, app.Run(context.Background(), []string{"agentpm", "done", "--file", epicFile, "epic", "--report", reportFile}))
+		assert.Contains(t, stdout.String(), "Final report written to "+reportFile)
+
+		content, err := os.ReadFile(reportFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "# Completion Report: Report Epic")
+	})
+
+	t.Run("writes no report when the epic cannot be completed", func(t *testing.T) {
+		dir := t.TempDir()
+		epicFile := filepath.Join(dir, "test-epic.xml")
+		writeTestEpicXML(t, epicFile, &epic.Epic{ID: "epic-report", Name: "Report Epic", Status: epic.StatusPending})
+		reportFile := filepath.Join(dir, "epic-report.report.md")
+
+		app := &cli.Command{
+			Name:     "agentpm",
+			Flags:    []cli.Flag{&cli.StringFlag{Name: "format", Value: "text"}},
+			Commands: []*cli.Command{DoneEpicCommand()},
+		}
+		var stdout, stderr bytes.Buffer
+		app.Writer = &stdout
+		app.ErrWriter = &stderr
+		require.Error(t, app.Run(context.Background(), []string{"agentpm", "done-epic", "--file", epicFile, "--report", reportFile}))
+		assert.NoFileExists(t, reportFile)
+	})
+}
+
 func TestDoneEpicCommand_ErrorWrongStatus(t *testing.T) {
 	tempDir := t.TempDir()
 	epicFile := filepath.Join(tempDir, "test-epic.xml")
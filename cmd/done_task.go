@@ -9,6 +9,7 @@ import (
 	"github.com/beevik/etree"
 	"github.com/mindreframer/agentpm/internal/config"
 	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
 	"github.com/mindreframer/agentpm/internal/messages"
 	"github.com/mindreframer/agentpm/internal/query"
 	"github.com/mindreframer/agentpm/internal/storage"
@@ -45,11 +46,11 @@ func DoneTaskCommand() *cli.Command {
 				if err != nil {
 					return fmt.Errorf("failed to load configuration: %w", err)
 				}
-				epicFile = cfg.CurrentEpic
+				epicFile = cfg.EpicFilePath()
 			}
 
 			if epicFile == "" {
-				return fmt.Errorf("no epic file specified (use --file flag or set current epic)")
+				return exitcode.Errorf(exitcode.Validation, "no epic file specified (use --file flag or set current epic)")
 			}
 
 			// Parse timestamp if provided
@@ -105,6 +106,9 @@ func DoneTaskCommand() *cli.Command {
 							"target_status":  string(stateErr.TargetStatus),
 						})
 				}
+				if validationErr, ok := err.(*epic.StatusValidationError); ok {
+					return outputEpic13ValidationError(cmd, validationErr, cmd.String("format"))
+				}
 
 				return fmt.Errorf("failed to complete task: %w", err)
 			}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -10,8 +11,11 @@ import (
 	"github.com/mindreframer/agentpm/internal/storage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
 )
 
+var strictDoneApp = appOptions{commands: []func() *cli.Command{DoneCommand}, time: "2025-08-16T16:30:00Z", hooks: []cli.BeforeFunc{ApplyStrictMode}}
+
 func TestDoneTaskCommand(t *testing.T) {
 	t.Run("complete active task", func(t *testing.T) {
 		tempDir := t.TempDir()
@@ -252,3 +256,65 @@ func TestCancelTaskCommand(t *testing.T) {
 		assert.Contains(t, err.Error(), "task ID is required")
 	})
 }
+
+func TestDoneTask_StrictMode(t *testing.T) {
+	setup := func(t *testing.T, configJSON string) (string, string) {
+		tempDir := t.TempDir()
+		epicFile := filepath.Join(tempDir, "epic.xml")
+		testEpic := &epic.Epic{
+			ID:     "epic-1",
+			Name:   "Test Epic",
+			Status: epic.StatusWIP,
+			Phases: []epic.Phase{{ID: "P1", Name: "Phase 1", Status: epic.StatusWIP}},
+			Tasks: []epic.Task{{ID: "T1", PhaseID: "P1", Name: "Task 1", Status: epic.StatusWIP,
+				AcceptanceCriteria: "- [ ] Handles empty input"}},
+		}
+		require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicFile))
+
+		configPath := filepath.Join(tempDir, ".agentpm.json")
+		require.NoError(t, os.WriteFile(configPath, []byte(configJSON), 0644))
+		return configPath, epicFile
+	}
+
+	taskStatus := func(t *testing.T, epicFile string) epic.Status {
+		epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+		require.NoError(t, err)
+		return epicData.Tasks[0].Status
+	}
+
+	t.Run("without strict mode warnings do not block", func(t *testing.T) {
+		configPath, epicFile := setup(t, `{"current_epic": "epic.xml"}`)
+
+		output, err := runApp(t, strictDoneApp.withConfig(configPath), "done", "task", "T1", "--file", epicFile)
+		require.NoError(t, err)
+		assert.Contains(t, output, "Task T1 completed.")
+		assert.Equal(t, epic.StatusCompleted, taskStatus(t, epicFile))
+	})
+
+	t.Run("--strict refuses untested task with unchecked criteria", func(t *testing.T) {
+		configPath, epicFile := setup(t, `{"current_epic": "epic.xml"}`)
+
+		_, err := runApp(t, strictDoneApp.withConfig(configPath), "--strict", "done", "task", "T1", "--file", epicFile)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Task T1 cannot be completed in strict mode")
+		assert.Contains(t, err.Error(), "no tests defined")
+		assert.Contains(t, err.Error(), `acceptance criterion "Handles empty input" is unchecked`)
+		assert.Equal(t, epic.StatusWIP, taskStatus(t, epicFile))
+	})
+
+	t.Run("strict config key enables strict mode", func(t *testing.T) {
+		configPath, epicFile := setup(t, `{"current_epic": "epic.xml", "strict": true}`)
+
+		_, err := runApp(t, strictDoneApp.withConfig(configPath), "done", "task", "T1", "--file", epicFile)
+		require.Error(t, err)
+		assert.Equal(t, epic.StatusWIP, taskStatus(t, epicFile))
+	})
+
+	t.Run("--strict=false overrides the config", func(t *testing.T) {
+		configPath, epicFile := setup(t, `{"current_epic": "epic.xml", "strict": true}`)
+
+		_, err := runApp(t, strictDoneApp.withConfig(configPath), "--strict=false", "done", "task", "T1", "--file", epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, epic.StatusCompleted, taskStatus(t, epicFile))
+	})
+}
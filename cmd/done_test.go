@@ -134,8 +134,8 @@ func TestDoneTaskSubcommand_Structure(t *testing.T) {
 		t.Error("expected non-empty usage for task subcommand")
 	}
 
-	if taskSubcmd.ArgsUsage != "<task-id>" {
-		t.Errorf("expected ArgsUsage '<task-id>', got %s", taskSubcmd.ArgsUsage)
+	if taskSubcmd.ArgsUsage != "<task-id>..." {
+		t.Errorf("expected ArgsUsage '<task-id>...', got %s", taskSubcmd.ArgsUsage)
 	}
 
 	if taskSubcmd.Action == nil {
@@ -301,6 +301,9 @@ func TestDoneCommand_CompletionWorkflow(t *testing.T) {
 		}
 
 		expectedArgsUsage := fmt.Sprintf("<%s-id>", entityName)
+		if entityName == "task" {
+			expectedArgsUsage += "..." // Several tasks can be completed at once
+		}
 		if subcmd.ArgsUsage != expectedArgsUsage {
 			t.Errorf("%s subcommand should require ID argument, expected '%s', got '%s'",
 				entityName, expectedArgsUsage, subcmd.ArgsUsage)
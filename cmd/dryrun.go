@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/diff"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/output"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// dryRunChange is an epic save a dry run held back: the changes the command
+// would have written to the file
+type dryRunChange struct {
+	File string `json:"file"`
+	*diff.Diff
+}
+
+// dryRunResult is what a mutating command prints with --dry-run
+type dryRunResult struct {
+	DryRun  bool           `json:"dry_run"`
+	Command string         `json:"command"`
+	Changes []dryRunChange `json:"changes"`
+}
+
+// dryRunChanges collects the saves held back by the dry run of this
+// invocation, nil when no dry run is active
+var dryRunChanges *[]dryRunChange
+
+// noDryRunCommands write files other than the epic, which a dry run cannot
+// hold back: the config, the API tokens, the undo journal, and epic files they
+// create or remove
+var noDryRunCommands = map[string]bool{
+	"undo": true, "config set": true, "config unset": true, "token create": true, "token revoke": true,
+	"switch": true, "sw": true, "epics add": true, "epics remove": true, "epics rm": true,
+	"init": true, "create": true, "split": true, "convert": true, "template fetch": true,
+}
+
+// ApplyDryRun is the root Before hook that starts a dry run with --dry-run: no
+// epic file is written, and the mutating commands print the state changes and
+// events they would have saved instead of their usual output. Commands that
+// write other files reject --dry-run.
+func ApplyDryRun(ctx context.Context, c *cli.Command) (context.Context, error) {
+	dryRunChanges = nil
+	storage.SetDryRun(nil)
+	if !c.Bool("dry-run") {
+		return ctx, nil
+	}
+	if words := commandWords(c); len(words) > 0 {
+		if noDryRunCommands[words[0]] {
+			return ctx, errDryRunUnsupported(words[0])
+		}
+		if len(words) > 1 && noDryRunCommands[words[0]+" "+words[1]] {
+			return ctx, errDryRunUnsupported(words[0] + " " + words[1])
+		}
+	}
+
+	changes := []dryRunChange{}
+	dryRunChanges = &changes
+	storage.SetDryRun(func(path string, before, after *epic.Epic) {
+		if before == nil {
+			before = &epic.Epic{}
+		}
+		changes = append(changes, dryRunChange{File: path, Diff: diff.Compare(before, after)})
+	})
+	return ctx, nil
+}
+
+// errDryRunUnsupported rejects --dry-run for a command that writes files the
+// dry run cannot hold back
+func errDryRunUnsupported(command string) error {
+	return exitcode.Errorf(exitcode.Validation, "--dry-run is not supported by '%s': it writes files other than the epic", command)
+}
+
+// runDryRun runs a mutating command action without saving and prints the
+// changes it would have saved in place of its output
+func runDryRun(ctx context.Context, c *cli.Command, action cli.ActionFunc) error {
+	root := c.Root()
+	writer := root.Writer
+	cmdWriter := c.Writer
+
+	// Subcommands write to their own writers as well as to the root writer
+	c.Writer, root.Writer = io.Discard, io.Discard
+	err := action(ctx, c)
+	root.Writer, c.Writer = writer, cmdWriter
+	if err != nil {
+		return err
+	}
+
+	result := dryRunResult{DryRun: true, Command: quietCommandLine(c), Changes: *dryRunChanges}
+	switch c.String("format") {
+	case "json", "jsonl":
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal dry run to JSON: %w", err)
+		}
+		fmt.Fprintf(writer, "%s\n", data)
+	case "xml":
+		outputDryRunXML(writer, result)
+	case "markdown":
+		outputDryRunMarkdown(writer, result)
+	default:
+		outputDryRunText(writer, result)
+	}
+	return nil
+}
+
+func outputDryRunText(w io.Writer, result dryRunResult) {
+	fmt.Fprintf(w, "Dry run of %s: nothing was saved.\n", result.Command)
+	if len(result.Changes) == 0 {
+		fmt.Fprintf(w, "No changes.\n")
+	}
+	for _, change := range result.Changes {
+		if change.Empty() {
+			fmt.Fprintf(w, "\nNo changes in epic %s (%s)\n", change.EpicID, change.File)
+			continue
+		}
+		fmt.Fprintf(w, "\nWould change epic %s (%s)\n", change.EpicID, change.File)
+		writeDiffText(w, change.Diff)
+	}
+}
+
+func outputDryRunMarkdown(w io.Writer, result dryRunResult) {
+	md := output.NewMarkdown()
+	md.Heading(2, fmt.Sprintf("Dry Run: %s", result.Command))
+	md.Paragraph("Nothing was saved.")
+	if len(result.Changes) == 0 {
+		md.Paragraph("No changes.")
+	}
+	for _, change := range result.Changes {
+		md.Field("Epic", change.EpicID)
+		md.Field("File", change.File)
+		if change.Empty() {
+			md.Paragraph("No changes.")
+		}
+		writeDiffMarkdown(md, change.Diff)
+	}
+	md.WriteTo(w)
+}
+
+func outputDryRunXML(w io.Writer, result dryRunResult) {
+	doc := etree.NewDocument()
+	root := doc.CreateElement("dry_run")
+	root.CreateAttr("command", result.Command)
+	for _, change := range result.Changes {
+		elem := root.CreateElement("diff")
+		elem.CreateAttr("epic", change.EpicID)
+		elem.CreateAttr("file", change.File)
+		addDiffElements(elem, change.Diff)
+	}
+
+	doc.Indent(4)
+	doc.WriteTo(w)
+}
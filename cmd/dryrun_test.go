@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+const dryRunEpic = `<epic id="9" name="Dry Run Epic" status="wip" created_at="2025-08-01T09:00:00Z">
+    <phases>
+        <phase id="P1" name="Build" status="wip"/>
+    </phases>
+    <tasks>
+        <task id="T1" phase_id="P1" name="Task 1" status="wip"/>
+    </tasks>
+    <tests>
+        <test id="X1" task_id="T1" phase_id="P1" name="Test 1" test_status="wip"/>
+    </tests>
+    <events/>
+</epic>`
+
+func TestDryRun(t *testing.T) {
+	t.Cleanup(func() {
+		dryRunChanges = nil
+		storage.SetDryRun(nil)
+	})
+	dir := t.TempDir()
+	epicFile := filepath.Join(dir, "epic.xml")
+	require.NoError(t, os.WriteFile(epicFile, []byte(dryRunEpic), 0644))
+
+	run := func(args ...string) (string, error) {
+		app := &cli.Command{
+			Name:   "agentpm",
+			Before: ApplyDryRun,
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "format", Value: "text"},
+				&cli.StringFlag{Name: "time", Value: "2025-08-05T12:00:00Z"},
+				&cli.BoolFlag{Name: "dry-run"},
+			},
+			Commands: []*cli.Command{PassCommand(), FailCommand(), DoneCommand()},
+		}
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		app.ErrWriter = &bytes.Buffer{}
+		err := app.Run(context.Background(), append(append([]string{"agentpm"}, args...), "--file", epicFile))
+		return stdout.String(), err
+	}
+
+	t.Run("text", func(t *testing.T) {
+		output, err := run("pass", "X1", "--dry-run")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Dry run of pass X1: nothing was saved.")
+		assert.Contains(t, output, "test X1 (Test 1): test_status wip -> done")
+		assert.Contains(t, output, "test_passed")
+		assert.NotContains(t, output, "Test X1 passed.")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		output, err := run("fail", "X1", "timeout", "--dry-run", "--format", "json")
+		require.NoError(t, err)
+		var result dryRunResult
+		require.NoError(t, json.Unmarshal([]byte(output), &result), output)
+		assert.True(t, result.DryRun)
+		require.Len(t, result.Changes, 1)
+		assert.Equal(t, "9", result.Changes[0].EpicID)
+		require.Len(t, result.Changes[0].NewEvents, 1)
+		assert.Equal(t, "test_failed", result.Changes[0].NewEvents[0].Type)
+	})
+
+	t.Run("errors are reported", func(t *testing.T) {
+		_, err := run("done", "task", "T9", "--dry-run")
+		assert.ErrorContains(t, err, "T9")
+	})
+
+	t.Run("nothing is saved", func(t *testing.T) {
+		data, err := os.ReadFile(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, dryRunEpic, string(data))
+
+		output, err := run("done", "task", "T1", "--file", epicFile)
+		require.NoError(t, err, "without --dry-run the command saves")
+		assert.NotContains(t, output, "Dry run")
+		data, err = os.ReadFile(epicFile)
+		require.NoError(t, err)
+		assert.NotEqual(t, dryRunEpic, string(data))
+	})
+}
+
+func TestDryRunWritesNoOtherFiles(t *testing.T) {
+	t.Cleanup(func() {
+		dryRunChanges = nil
+		storage.SetDryRun(nil)
+		storage.SetSaveHook(nil)
+		storage.SetBackupRetention(0)
+	})
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, ".agentpm.json")
+	epicFile := filepath.Join(dir, "epic.xml")
+	otherFile := filepath.Join(dir, "other.xml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`{"current_epic": "epic.xml", "backups": 3}`), 0644))
+	require.NoError(t, os.WriteFile(epicFile, []byte(dryRunEpic), 0644))
+	require.NoError(t, os.WriteFile(otherFile, []byte(`<epic id="10" name="Other" status="wip"/>`), 0644))
+
+	run := func(args ...string) (string, error) {
+		app := &cli.Command{
+			Name: "agentpm",
+			Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+				ctx, err := KeepEpicBackups(ctx, c)
+				if err != nil {
+					return ctx, err
+				}
+				if ctx, err = JournalMutations(ctx, c); err != nil {
+					return ctx, err
+				}
+				return ApplyDryRun(ctx, c)
+			},
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config", Value: configFile},
+				&cli.StringFlag{Name: "format", Value: "text"},
+				&cli.StringFlag{Name: "time", Value: "2025-08-05T12:00:00Z"},
+				&cli.BoolFlag{Name: "dry-run"},
+			},
+			Commands: []*cli.Command{
+				DoneCommand(), UndoCommand(), RestoreCommand(), ConfigCommand(), TokenCommand(), MergeCommand(),
+			},
+		}
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		app.ErrWriter = &bytes.Buffer{}
+		err := app.Run(context.Background(), append([]string{"agentpm"}, args...))
+		return stdout.String(), err
+	}
+	files := func() map[string]string {
+		contents := map[string]string{}
+		require.NoError(t, filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			contents[path] = string(data)
+			return err
+		}))
+		return contents
+	}
+
+	// Journals the change and keeps the replaced version as backup 1
+	_, err := run("done", "task", "T1", "--file", epicFile)
+	require.NoError(t, err)
+	before := files()
+
+	for _, args := range [][]string{
+		{"undo", "--file", epicFile},
+		{"config", "set", "default_assignee", "alice"},
+		{"token", "create", "worker", "--scope", "read"},
+		{"merge", otherFile, "--remove", "--file", epicFile},
+	} {
+		t.Run(args[0], func(t *testing.T) {
+			_, err := run(append(args, "--dry-run")...)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "--dry-run is not supported by '"+args[0])
+			code, ok := exitcode.Of(err)
+			assert.True(t, ok)
+			assert.Equal(t, exitcode.Validation, code)
+			assert.Equal(t, before, files())
+		})
+	}
+
+	t.Run("restore", func(t *testing.T) {
+		output, err := run("restore", "--backup", "1", "--dry-run", "--file", epicFile)
+		require.NoError(t, err)
+		assert.Contains(t, output, "Dry run of restore: nothing was saved.")
+		assert.Contains(t, output, "task T1 (Task 1): completed -> wip")
+		assert.Equal(t, before, files())
+	})
+}
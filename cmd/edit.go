@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/commands"
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/customfields"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// EditCommand sets the custom fields of the epic, phases, tasks and tests
+func EditCommand() *cli.Command {
+	editFlags := func() []cli.Flag {
+		return []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:  "set",
+				Usage: "Set a custom field: custom.<name>=<value> (repeatable)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "unset",
+				Usage: "Remove a custom field: custom.<name> (repeatable)",
+			},
+		}
+	}
+
+	return &cli.Command{
+		Name:  "edit",
+		Usage: "Set custom fields of the epic, a phase, a task or a test",
+		Description: `Set or remove the custom fields declared in custom_fields of the config.
+Every field has a type the value is checked against before it is stored:
+
+  "custom_fields": {
+    "task": {
+      "sprint": {"type": "int"},
+      "release": {"type": "date"},
+      "risk": {"type": "enum", "values": ["low", "medium", "high"]},
+      "team": {"type": "string", "description": "Owning team"}
+    }
+  }
+
+Integers are stored without leading zeros, dates as 2006-01-02 or RFC3339
+and enum values as declared, so that selectors like
+'tasks[custom.sprint>=7]' compare them by their type. Fields removed from
+the config can still be unset.
+
+Subcommands:
+  epic          Edit the current epic
+  phase <id>    Edit a phase
+  task <id>     Edit a task
+  test <id>     Edit a test
+
+Examples:
+  agentpm edit task 1A_1 --set custom.sprint=7 --set custom.risk=high
+  agentpm edit phase 1A --set custom.release=2025-10-01
+  agentpm edit task 1A_1 --unset custom.risk
+  agentpm query "tasks[custom.risk=high][custom.sprint<=7]"`,
+		Flags: commands.GlobalFlags(),
+		Commands: []*cli.Command{
+			{
+				Name:   "epic",
+				Usage:  "Edit the custom fields of the current epic",
+				Flags:  editFlags(),
+				Action: withQuietResult(editAction("epic")),
+			},
+			{
+				Name:      "phase",
+				Usage:     "Edit the custom fields of a phase",
+				ArgsUsage: "<phase-id>",
+				Flags:     editFlags(),
+				Action:    withQuietResult(editAction("phase")),
+			},
+			{
+				Name:      "task",
+				Usage:     "Edit the custom fields of a task",
+				ArgsUsage: "<task-id>",
+				Flags:     editFlags(),
+				Action:    withQuietResult(editAction("task")),
+			},
+			{
+				Name:      "test",
+				Usage:     "Edit the custom fields of a test",
+				ArgsUsage: "<test-id>",
+				Flags:     editFlags(),
+				Action:    withQuietResult(editAction("test")),
+			},
+		},
+	}
+}
+
+func editAction(entityType string) cli.ActionFunc {
+	return func(ctx context.Context, c *cli.Command) error {
+		var id string
+		if entityType != "epic" {
+			if c.Args().Len() != 1 {
+				return exitcode.Errorf(exitcode.Validation, "%s requires exactly one argument", entityType)
+			}
+			id = c.Args().First()
+		} else if c.Args().Len() > 0 {
+			return exitcode.Errorf(exitcode.Validation, "epic takes no arguments, it edits the current epic")
+		}
+
+		changes, err := parseCustomFieldChanges(c.StringSlice("set"), c.StringSlice("unset"))
+		if err != nil {
+			return err
+		}
+
+		schema, err := customFieldSchema(c)
+		if err != nil {
+			return err
+		}
+
+		epicFile, err := getEpicFile(c)
+		if err != nil {
+			return err
+		}
+
+		timestamp := time.Now()
+		if timeStr := c.String("time"); timeStr != "" {
+			timestamp, err = time.Parse(time.RFC3339, timeStr)
+			if err != nil {
+				return fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+			}
+		}
+
+		storageImpl := storage.NewFileStorage()
+		epicData, err := storageImpl.LoadEpic(epicFile)
+		if err != nil {
+			return fmt.Errorf("failed to load epic: %w", err)
+		}
+
+		result, err := service.EditCustomFields(epicData, schema, entityType, id, changes, timestamp)
+		if err != nil {
+			return err
+		}
+
+		if err := storageImpl.SaveEpic(epicData, epicFile); err != nil {
+			return fmt.Errorf("failed to save epic: %w", err)
+		}
+		return writeEditResult(c, result)
+	}
+}
+
+// parseCustomFieldChanges reads --set custom.<name>=<value> and --unset custom.<name>
+func parseCustomFieldChanges(sets, unsets []string) ([]service.CustomFieldChange, error) {
+	if len(sets) == 0 && len(unsets) == 0 {
+		return nil, exitcode.Errorf(exitcode.Validation, "edit requires --set custom.<name>=<value> or --unset custom.<name>")
+	}
+
+	fieldName := func(key string) (string, error) {
+		name, ok := strings.CutPrefix(strings.TrimSpace(key), customfields.Prefix)
+		if !ok || name == "" {
+			return "", exitcode.Errorf(exitcode.Validation, "invalid field %q: only custom fields can be edited, use custom.<name>", key)
+		}
+		return name, nil
+	}
+
+	var changes []service.CustomFieldChange
+	for _, set := range sets {
+		key, value, ok := strings.Cut(set, "=")
+		if !ok {
+			return nil, exitcode.Errorf(exitcode.Validation, "invalid --set %q: expected custom.<name>=<value>", set)
+		}
+		name, err := fieldName(key)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, service.CustomFieldChange{Name: name, Value: value})
+	}
+	for _, unset := range unsets {
+		name, err := fieldName(unset)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, service.CustomFieldChange{Name: name, Unset: true})
+	}
+	return changes, nil
+}
+
+// customFieldSchema returns the custom fields declared in the config; without
+// a config file no custom fields are declared
+func customFieldSchema(c *cli.Command) (customfields.Schema, error) {
+	cfg, err := config.LoadConfig(c.String("config"))
+	if err != nil {
+		if code, ok := exitcode.Of(err); ok && code == exitcode.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return cfg.CustomFields, nil
+}
+
+func writeEditResult(c *cli.Command, result *service.CustomFieldsResult) error {
+	w := c.Root().Writer
+	switch c.String("format") {
+	case "json":
+		output := struct {
+			Operation string `json:"operation"`
+			*service.CustomFieldsResult
+		}{"custom_fields_changed", result}
+		jsonData, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal result to JSON: %w", err)
+		}
+		fmt.Fprintf(w, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("custom_fields_changed")
+		root.CreateAttr("entity_type", result.EntityType)
+		root.CreateAttr("id", result.ID)
+		for _, field := range result.Custom {
+			fieldElem := root.CreateElement("field")
+			fieldElem.CreateAttr("name", field.Name)
+			fieldElem.SetText(field.Value)
+		}
+		doc.Indent(2)
+		doc.WriteTo(w)
+	default:
+		var changes []string
+		for _, change := range result.Changes {
+			if change.Unset {
+				changes = append(changes, customfields.Prefix+change.Name+" unset")
+			} else {
+				changes = append(changes, customfields.Prefix+change.Name+"="+change.Value)
+			}
+		}
+		label := strings.ToUpper(result.EntityType[:1]) + result.EntityType[1:]
+		fmt.Fprintf(w, "%s %s: %s\n", label, result.ID, strings.Join(changes, ", "))
+	}
+	return nil
+}
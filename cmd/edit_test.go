@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/customfields"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditCommand(t *testing.T) {
+	dir := t.TempDir()
+	epicFile := filepath.Join(dir, "epic.xml")
+	fileStorage := storage.NewFileStorage()
+	require.NoError(t, fileStorage.SaveEpic(createEpicForReset(), epicFile))
+
+	configPath := filepath.Join(dir, ".agentpm.json")
+	cfg := config.DefaultConfig()
+	cfg.CurrentEpic = "epic.xml"
+	cfg.CustomFields = customfields.Schema{
+		"task": {
+			"sprint": {Type: customfields.TypeInt},
+			"risk":   {Type: customfields.TypeEnum, Values: []string{"low", "medium", "high"}},
+		},
+		"epic": {"budget": {Type: customfields.TypeInt}},
+	}
+	require.NoError(t, config.SaveConfig(cfg, configPath))
+
+	run := func(t *testing.T, args ...string) (string, error) {
+		var stdout bytes.Buffer
+		cmd := EditCommand()
+		cmd.Root().Writer = &stdout
+		err := cmd.Run(context.Background(), append([]string{"edit", "--config", configPath, "--time", "2025-08-16T15:30:00Z"}, args...))
+		return stdout.String(), err
+	}
+
+	t.Run("sets typed custom fields", func(t *testing.T) {
+		output, err := run(t, "task", "T1", "--set", "custom.sprint=07", "--set", "custom.risk=High")
+		require.NoError(t, err)
+		assert.Equal(t, "Task T1: custom.sprint=7, custom.risk=high\n", output)
+
+		updated, err := fileStorage.LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, epic.CustomFields{{Name: "risk", Value: "high"}, {Name: "sprint", Value: "7"}}, updated.Tasks[0].Custom)
+		lastEvent := updated.Events[len(updated.Events)-1]
+		assert.Equal(t, "custom_fields_changed", lastEvent.Type)
+		assert.Equal(t, "Task T1 custom fields changed: sprint=7, risk=high", lastEvent.Data)
+	})
+
+	t.Run("unsets fields and reports the remaining ones", func(t *testing.T) {
+		output, err := run(t, "--format", "json", "task", "T1", "--unset", "custom.risk")
+		require.NoError(t, err)
+
+		var result map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.Equal(t, "custom_fields_changed", result["operation"])
+		assert.Equal(t, []interface{}{map[string]interface{}{"name": "sprint", "value": "7"}}, result["custom"])
+	})
+
+	t.Run("edits the epic", func(t *testing.T) {
+		output, err := run(t, "--format", "xml", "epic", "--set", "custom.budget=1200")
+		require.NoError(t, err)
+		assert.Contains(t, output, `<custom_fields_changed entity_type="epic" id="epic-1">`)
+		assert.Contains(t, output, `<field name="budget">1200</field>`)
+	})
+
+	t.Run("rejects invalid edits", func(t *testing.T) {
+		for message, args := range map[string][]string{
+			`custom.sprint: invalid value "soon": expected an integer`: {"task", "T1", "--set", "custom.sprint=soon"},
+			`unknown custom field "team" for tasks`:                    {"task", "T1", "--set", "custom.team=core"},
+			"only custom fields can be edited":                         {"task", "T1", "--set", "name=Renamed"},
+			"edit requires --set":                                      {"task", "T1"},
+			"no custom fields are declared for tests":                  {"test", "T1_T1", "--set", "custom.sprint=1"},
+		} {
+			_, err := run(t, args...)
+			require.Error(t, err, message)
+			assert.Contains(t, err.Error(), message)
+			assert.Equal(t, exitcode.Validation, ExitCode(err), message)
+		}
+
+		_, err := run(t, "task", "T9", "--set", "custom.sprint=1")
+		require.Error(t, err)
+		assert.Equal(t, exitcode.NotFound, ExitCode(err))
+	})
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/effort"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/lint"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// EffortCommand reports the work done per agent or human
+func EffortCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "effort",
+		Usage: "Show tasks completed, tests fixed and active time per actor",
+		Description: `Attribute the epic's events to the agents and humans who caused them:
+tasks completed, tests passed and fixed (passed after failing), and active
+time (from starting a task until it is completed, cancelled or reset).
+
+Events are attributed to the --actor (or AGENTPM_ACTOR) given when they were
+recorded; older events fall back to the assignee of their task.
+
+--since and --until take a day (2025-08-01, --until includes the whole day)
+or an RFC3339 timestamp.
+
+Examples:
+  AGENTPM_ACTOR=claude-1 agentpm start task 1A_1   # Record who does the work
+  agentpm effort --by actor                         # Effort in the current epic
+  agentpm effort --all --since 2025-08-01 --format json`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config (ignored with --all)",
+			},
+			&cli.StringFlag{
+				Name:  "by",
+				Usage: "Grouping (only 'actor' is supported)",
+				Value: "actor",
+			},
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "Only count events from this date or time on",
+			},
+			&cli.StringFlag{
+				Name:  "until",
+				Usage: "Only count events up to this date or time",
+			},
+			&cli.BoolFlag{
+				Name:  "all",
+				Usage: "Include every epic of the workspace",
+			},
+		},
+		Action: effortAction,
+	}
+}
+
+func effortAction(ctx context.Context, c *cli.Command) error {
+	if by := c.String("by"); by != "actor" {
+		return fmt.Errorf("invalid grouping: %s (expected actor)", by)
+	}
+
+	var period effort.Period
+	if since := c.String("since"); since != "" {
+		from, _, err := epic.ParseDate(since)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		period.From = from
+	}
+	if until := c.String("until"); until != "" {
+		to, allDay, err := epic.ParseDate(until)
+		if err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+		if allDay {
+			to = to.AddDate(0, 0, 1)
+		}
+		period.To = to
+	}
+
+	now := time.Now()
+	if timeStr := c.String("time"); timeStr != "" {
+		var err error
+		now, err = time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+		}
+	}
+
+	epics, err := loadEffortEpics(c)
+	if err != nil {
+		return err
+	}
+
+	report := effort.Build(epics, period, now)
+
+	switch c.String("format") {
+	case "json":
+		if report.Actors == nil {
+			report.Actors = []effort.ActorEffort{}
+		}
+		jsonData, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal effort report to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		outputEffortXML(c, report)
+	default:
+		outputEffortText(c, report, period)
+	}
+	return nil
+}
+
+// loadEffortEpics loads the current epic, or every epic of the workspace with --all
+func loadEffortEpics(c *cli.Command) ([]*epic.Epic, error) {
+	fileStorage := storage.NewFileStorage()
+
+	if !c.Bool("all") {
+		epicFile, err := getEpicFile(c)
+		if err != nil {
+			return nil, err
+		}
+		epicData, err := fileStorage.LoadEpic(epicFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load epic: %w", err)
+		}
+		return []*epic.Epic{epicData}, nil
+	}
+
+	workspace, err := workspaceRoot(c.String("config"))
+	if err != nil {
+		return nil, err
+	}
+	files, err := lint.FindEpicFiles(workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	var epics []*epic.Epic
+	for _, file := range files {
+		epicData, err := fileStorage.LoadEpic(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load epic %s: %w", workspaceRelative(workspace, file), err)
+		}
+		epics = append(epics, epicData)
+	}
+	return epics, nil
+}
+
+func outputEffortText(c *cli.Command, report *effort.Report, period effort.Period) {
+	w := c.Root().Writer
+
+	scope := "all time"
+	switch {
+	case !period.From.IsZero() && !period.To.IsZero():
+		scope = fmt.Sprintf("%s to %s", period.From.Format(time.RFC3339), period.To.Format(time.RFC3339))
+	case !period.From.IsZero():
+		scope = "since " + period.From.Format(time.RFC3339)
+	case !period.To.IsZero():
+		scope = "until " + period.To.Format(time.RFC3339)
+	}
+	fmt.Fprintf(w, "Effort by actor across %d epics (%s)\n\n", report.Epics, scope)
+
+	if len(report.Actors) == 0 {
+		fmt.Fprintf(w, "No events in this period\n")
+		return
+	}
+
+	fmt.Fprintf(w, "%-20s  %5s  %6s  %5s  %8s  %6s\n", "actor", "tasks", "passed", "fixed", "active", "events")
+	for _, actor := range report.Actors {
+		fmt.Fprintf(w, "%-20s  %5d  %6d  %5d  %8s  %6d\n", actor.Actor, actor.TasksCompleted, actor.TestsPassed,
+			actor.TestsFixed, formatMinutes(actor.ActiveMinutes), actor.Events)
+	}
+}
+
+// formatMinutes renders a number of minutes as "3h05m"
+func formatMinutes(minutes int) string {
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
+
+func outputEffortXML(c *cli.Command, report *effort.Report) {
+	doc := etree.NewDocument()
+	root := doc.CreateElement("effort")
+	root.CreateAttr("epics", strconv.Itoa(report.Epics))
+
+	for _, actor := range report.Actors {
+		actorElem := root.CreateElement("actor")
+		actorElem.CreateAttr("name", actor.Actor)
+		actorElem.CreateAttr("tasks_completed", strconv.Itoa(actor.TasksCompleted))
+		actorElem.CreateAttr("tests_passed", strconv.Itoa(actor.TestsPassed))
+		actorElem.CreateAttr("tests_fixed", strconv.Itoa(actor.TestsFixed))
+		actorElem.CreateAttr("active_minutes", strconv.Itoa(actor.ActiveMinutes))
+		actorElem.CreateAttr("events", strconv.Itoa(actor.Events))
+	}
+
+	doc.Indent(4)
+	doc.WriteTo(c.Root().Writer)
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/effort"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var effortApp = appOptions{commands: []func() *cli.Command{EffortCommand}, time: "2025-08-16T12:00:00Z", hooks: []cli.BeforeFunc{RecordActor}}
+
+func TestEffortCommand(t *testing.T) {
+	t.Cleanup(func() { service.SetActor("") })
+
+	epicFile := filepath.Join(t.TempDir(), "epic.xml")
+	testEpic := createEpicForReset()
+	testEpic.Events = nil
+	service.SetActor("claude-1")
+	service.CreateEvent(testEpic, service.EventTaskStarted, "P1", "T1", "", "", time.Date(2025, 8, 15, 9, 0, 0, 0, time.UTC))
+	service.CreateEvent(testEpic, service.EventTaskCompleted, "P1", "T1", "", "", time.Date(2025, 8, 15, 10, 15, 0, 0, time.UTC))
+	service.SetActor("")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicFile))
+
+	t.Run("text output", func(t *testing.T) {
+		output, err := runApp(t, effortApp.withFormat("text"), "effort", "--file", epicFile, "--by", "actor")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Effort by actor across 1 epics (all time)")
+		assert.Contains(t, output, "claude-1                  1       0      0     1h15m       2\n")
+	})
+
+	t.Run("json output limited to a period", func(t *testing.T) {
+		output, err := runApp(t, effortApp.withFormat("json"), "effort", "--file", epicFile, "--since", "2025-08-16")
+		require.NoError(t, err)
+
+		var report effort.Report
+		require.NoError(t, json.Unmarshal([]byte(output), &report))
+		assert.Empty(t, report.Actors)
+	})
+
+	t.Run("until includes the whole day", func(t *testing.T) {
+		output, err := runApp(t, effortApp.withFormat("json"), "effort", "--file", epicFile, "--until", "2025-08-15")
+		require.NoError(t, err)
+
+		var report effort.Report
+		require.NoError(t, json.Unmarshal([]byte(output), &report))
+		require.Len(t, report.Actors, 1)
+		assert.Equal(t, 1, report.Actors[0].TasksCompleted)
+	})
+
+	t.Run("rejects other groupings", func(t *testing.T) {
+		_, err := runApp(t, effortApp.withFormat("text"), "effort", "--file", epicFile, "--by", "phase")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid grouping: phase")
+	})
+
+	t.Run("root --actor attributes new events", func(t *testing.T) {
+		_, err := runApp(t, effortApp.withFormat("text"), "--actor", "claude-2", "effort", "--file", epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, "claude-2", service.Actor())
+	})
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/query"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// EpicsCommand manages the epics registered in a multi-epic workspace
+func EpicsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "epics",
+		Usage: "Manage the epics of a multi-epic workspace",
+		Description: `Register the epics in flight in this repository, so that 'agentpm status
+--all' reports progress across all of them.
+
+The workspace consists of the current epic (current_epic) and the epics
+registered in the config's "epics" list; paths are stored relative to
+.agentpm.json. 'agentpm switch' keeps the epic it leaves registered once the
+list is in use.
+
+Subcommands:
+  list              List the epics of the workspace
+  add <file>        Register an epic file
+  remove <file>     Unregister an epic file
+
+Examples:
+  agentpm epics add epic-9.xml
+  agentpm epics list
+  agentpm status --all`,
+		Commands: []*cli.Command{
+			{
+				Name:   "list",
+				Usage:  "List the epics of the workspace",
+				Action: epicsListAction,
+			},
+			{
+				Name:      "add",
+				Usage:     "Register an epic file",
+				ArgsUsage: "<file>",
+				Action:    epicsAddAction,
+			},
+			{
+				Name:      "remove",
+				Aliases:   []string{"rm"},
+				Usage:     "Unregister an epic file (the file itself is kept)",
+				ArgsUsage: "<file>",
+				Action:    epicsRemoveAction,
+			},
+		},
+	}
+}
+
+// workspaceEpic is one epic of the workspace with its status, or the error that
+// prevented loading it
+type workspaceEpic struct {
+	Path    string
+	Current bool
+	Status  *query.EpicStatus
+	Err     error
+}
+
+// loadWorkspaceEpics loads the status of every epic of the workspace. An epic
+// that cannot be loaded is reported with its error instead of failing the lot.
+func loadWorkspaceEpics(cfg *config.Config) []workspaceEpic {
+	var epics []workspaceEpic
+	for i, path := range cfg.EpicFilePaths() {
+		entry := workspaceEpic{Path: workspacePath(cfg, path), Current: i == 0}
+		queryService := query.NewQueryService(storage.NewFileStorage())
+		if err := queryService.LoadEpic(path); err != nil {
+			entry.Err = err
+		} else {
+			entry.Status, entry.Err = queryService.GetEpicStatus()
+		}
+		epics = append(epics, entry)
+	}
+	return epics
+}
+
+// workspacePath shows an epic path relative to the project root when it lies below it
+func workspacePath(cfg *config.Config, path string) string {
+	if rel, err := filepath.Rel(cfg.Dir(), path); err == nil && filepath.IsLocal(rel) {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+func epicsListAction(ctx context.Context, c *cli.Command) error {
+	cfg, err := config.LoadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	epics := loadWorkspaceEpics(cfg)
+
+	w := c.Root().Writer
+	switch c.String("format") {
+	case "json":
+		type epicOutput struct {
+			Path    string `json:"path"`
+			Current bool   `json:"current"`
+			ID      string `json:"id,omitempty"`
+			Name    string `json:"name,omitempty"`
+			Status  string `json:"status,omitempty"`
+			Error   string `json:"error,omitempty"`
+		}
+		outputs := make([]epicOutput, len(epics))
+		for i, entry := range epics {
+			outputs[i] = epicOutput{Path: entry.Path, Current: entry.Current}
+			if entry.Err != nil {
+				outputs[i].Error = entry.Err.Error()
+				continue
+			}
+			outputs[i].ID, outputs[i].Name, outputs[i].Status = entry.Status.ID, entry.Status.Name, string(entry.Status.Status)
+		}
+		jsonData, err := json.MarshalIndent(map[string]interface{}{"epics": outputs}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal epics to JSON: %w", err)
+		}
+		fmt.Fprintf(w, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("epics")
+		root.CreateAttr("count", strconv.Itoa(len(epics)))
+		for _, entry := range epics {
+			elem := root.CreateElement("epic")
+			elem.CreateAttr("path", entry.Path)
+			elem.CreateAttr("current", strconv.FormatBool(entry.Current))
+			if entry.Err != nil {
+				elem.CreateAttr("error", entry.Err.Error())
+				continue
+			}
+			elem.CreateAttr("id", entry.Status.ID)
+			elem.CreateAttr("name", entry.Status.Name)
+			elem.CreateAttr("status", string(entry.Status.Status))
+		}
+		doc.Indent(4)
+		doc.WriteTo(w)
+	default:
+		fmt.Fprintf(w, "Epics (%d):\n", len(epics))
+		for _, entry := range epics {
+			marker := " "
+			if entry.Current {
+				marker = "*"
+			}
+			if entry.Err != nil {
+				fmt.Fprintf(w, "%s %-24s  error: %v\n", marker, entry.Path, entry.Err)
+				continue
+			}
+			fmt.Fprintf(w, "%s %-24s  %-6s %-10s %s\n", marker, entry.Path, entry.Status.ID, entry.Status.Status, entry.Status.Name)
+		}
+	}
+	return nil
+}
+
+func epicsAddAction(ctx context.Context, c *cli.Command) error {
+	return updateWorkspaceEpics(c, func(cfg *config.Config, stored, path string) (string, error) {
+		if err := validateEpicFile(path); err != nil {
+			return "", fmt.Errorf("invalid epic file: %w", err)
+		}
+		if !cfg.AddEpic(stored) {
+			return fmt.Sprintf("Epic %s is already part of the workspace", stored), nil
+		}
+		return fmt.Sprintf("Registered epic %s", stored), nil
+	})
+}
+
+func epicsRemoveAction(ctx context.Context, c *cli.Command) error {
+	return updateWorkspaceEpics(c, func(cfg *config.Config, stored, path string) (string, error) {
+		if path == cfg.EpicFilePath() {
+			return "", fmt.Errorf("cannot remove the current epic %s; switch to another epic first", stored)
+		}
+		if !cfg.RemoveEpic(stored) {
+			return "", fmt.Errorf("epic %s is not registered", stored)
+		}
+		return fmt.Sprintf("Unregistered epic %s", stored), nil
+	})
+}
+
+// updateWorkspaceEpics applies change to the epic given as argument and saves the config
+func updateWorkspaceEpics(c *cli.Command, change func(cfg *config.Config, stored, path string) (string, error)) error {
+	if c.Args().Len() != 1 {
+		return fmt.Errorf("exactly one epic file is required")
+	}
+	configPath := c.String("config")
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	epicFile := c.Args().First()
+	stored, err := config.NormalizeEpicPath(configPath, epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to resolve epic path: %w", err)
+	}
+	path, err := filepath.Abs(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to resolve epic path: %w", err)
+	}
+
+	message, err := change(cfg, stored, path)
+	if err != nil {
+		return err
+	}
+	if err := config.SaveConfig(cfg, configPath); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+	fmt.Fprintf(c.Root().Writer, "%s\n", message)
+	return nil
+}
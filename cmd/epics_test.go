@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func workspaceEpicXML(id, status, phaseStatus string) string {
+	return `<epic id="` + id + `" name="Epic ` + id + `" status="` + status + `" created_at="2025-08-01T09:00:00Z">
+    <phases>
+        <phase id="P1" name="Build" status="` + phaseStatus + `"/>
+        <phase id="P2" name="Ship" status="pending"/>
+    </phases>
+    <tasks/>
+    <tests/>
+    <events/>
+</epic>`
+}
+
+func TestEpicsCommand(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, ".agentpm.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"current_epic": "epic-8.xml"}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "epic-8.xml"), []byte(workspaceEpicXML("8", "wip", "completed")), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "epic-9.xml"), []byte(workspaceEpicXML("9", "pending", "pending")), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "epic-10.xml"), []byte(workspaceEpicXML("10", "pending", "pending")), 0644))
+
+	run := func(format string, args ...string) (string, error) {
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config", Value: configPath},
+				&cli.StringFlag{Name: "format", Value: format},
+			},
+			Commands: []*cli.Command{EpicsCommand(), StatusCommand(), SwitchCommand()},
+		}
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		err := app.Run(context.Background(), append([]string{"agentpm"}, args...))
+		return stdout.String(), err
+	}
+
+	output, err := run("text", "epics", "add", filepath.Join(tempDir, "epic-9.xml"))
+	require.NoError(t, err)
+	assert.Contains(t, output, "Registered epic")
+
+	t.Run("rejects files that are no epics", func(t *testing.T) {
+		_, err := run("text", "epics", "add", filepath.Join(tempDir, "missing.xml"))
+		assert.ErrorContains(t, err, "invalid epic file")
+	})
+
+	t.Run("lists the current epic first", func(t *testing.T) {
+		output, err := run("text", "epics", "list")
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(output), "\n")
+		require.Len(t, lines, 3)
+		assert.Equal(t, "Epics (2):", lines[0])
+		assert.Regexp(t, `^\* epic-8\.xml +8 +wip +Epic 8$`, lines[1])
+		assert.Regexp(t, `^  epic-9\.xml +9 +pending +Epic 9$`, lines[2])
+	})
+
+	t.Run("status --all aggregates the workspace", func(t *testing.T) {
+		output, err := run("text", "status", "--all")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Workspace Status: 2 epics")
+		assert.Contains(t, output, "Phases: 1/4 completed")
+
+		output, err = run("text", "status", "--all", "--format", "json")
+		require.NoError(t, err)
+		var result struct {
+			Epics  []map[string]interface{} `json:"epics"`
+			Totals workspaceTotals          `json:"totals"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		require.Len(t, result.Epics, 2)
+		assert.Equal(t, "9", result.Epics[1]["epic"])
+		assert.Equal(t, 2, result.Totals.Epics)
+		assert.Equal(t, 1, result.Totals.CompletedPhases)
+	})
+
+	t.Run("switch keeps the epic it leaves registered", func(t *testing.T) {
+		_, err := run("text", "switch", filepath.Join(tempDir, "epic-10.xml"))
+		require.NoError(t, err)
+		cfg, err := config.LoadConfig(configPath)
+		require.NoError(t, err)
+		assert.Len(t, cfg.EpicFilePaths(), 3)
+		assert.Contains(t, cfg.Epics, "epic-8.xml")
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		_, err := run("text", "epics", "remove", filepath.Join(tempDir, "epic-10.xml"))
+		assert.ErrorContains(t, err, "cannot remove the current epic")
+
+		_, err = run("text", "epics", "remove", filepath.Join(tempDir, "epic-9.xml"))
+		require.NoError(t, err)
+		_, err = run("text", "epics", "remove", filepath.Join(tempDir, "epic-9.xml"))
+		assert.ErrorContains(t, err, "is not registered")
+	})
+}
+
+func TestWorkspaceQueriesAcrossBackends(t *testing.T) {
+	for _, backend := range []string{"file", "sqlite"} {
+		t.Run(backend, func(t *testing.T) {
+			tempDir := t.TempDir()
+			configPath := filepath.Join(tempDir, ".agentpm.json")
+			require.NoError(t, os.WriteFile(configPath, []byte(`{"current_epic": "epic-8.xml", "epics": ["epic-9.xml"], "storage_backend": "`+backend+`"}`), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(tempDir, "epic-8.xml"), []byte(workspaceEpicXML("8", "wip", "completed")), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(tempDir, "epic-9.xml"), []byte(strings.Replace(workspaceEpicXML("9", "wip", "wip"),
+				"<events/>", `<events><event type="phase_started" timestamp="2025-08-02T09:00:00Z">Phase P1 (Build) started</event></events>`, 1)), 0644))
+
+			run := func(args ...string) (string, error) {
+				app := &cli.Command{
+					Name:     "agentpm",
+					Flags:    []cli.Flag{&cli.StringFlag{Name: "config", Value: configPath}},
+					Commands: []*cli.Command{QueryCommand(), EventsCommand()},
+				}
+				var stdout bytes.Buffer
+				app.Writer = &stdout
+				err := app.Run(context.Background(), append([]string{"agentpm"}, args...))
+				return stdout.String(), err
+			}
+
+			output, err := run("query", "--all", "--format", "json", "phases[status=pending]")
+			require.NoError(t, err)
+			var result struct {
+				MatchCount int `json:"match_count"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(output), &result))
+			assert.Equal(t, 2, result.MatchCount, "P2 of both epics")
+
+			_, err = run("query", "--all", "//phase")
+			assert.ErrorContains(t, err, "--all takes a selector")
+
+			output, err = run("events", "--all", "--phase", "P1")
+			require.NoError(t, err)
+			assert.Contains(t, output, "Showing 1 event(s)")
+			assert.Contains(t, output, "Epic: 9")
+		})
+	}
+}
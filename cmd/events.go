@@ -2,9 +2,15 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/output"
 	"github.com/mindreframer/agentpm/internal/query"
 	"github.com/mindreframer/agentpm/internal/storage"
 	"github.com/urfave/cli/v3"
@@ -16,7 +22,31 @@ func EventsCommand() *cli.Command {
 		Name:    "events",
 		Usage:   "Display recent events timeline",
 		Aliases: []string{"evt"},
-		Action:  eventsAction,
+		Description: `Show the events of the epic, most recent first, optionally filtered.
+
+--type takes event types (task_started) or entity prefixes (task for all
+task events) and can be repeated. --phase and --task include the events of
+the phase's tasks and the task's tests. --since takes a date, an RFC3339
+time or a duration back from now (24h).
+
+Every command records its events with one correlation ID, so that the
+events of a cascade (a task completing its phase, the epic becoming ready)
+can be told apart: --correlate <id> shows only the events of that
+invocation.
+
+--stream exports the matching events as JSON Lines, oldest first and
+without the default limit, for ingestion into log pipelines.
+
+--all merges the events of every epic of the workspace (see 'agentpm
+epics'), read through the storage_backend of the config.
+
+Examples:
+  agentpm events --type test_failed --since 24h
+  agentpm events --phase 1A --limit 50
+  agentpm events --correlate 3f9a1c2b7d40
+  agentpm events --format json --stream >> agentpm-events.log
+  agentpm events --all --type task_completed --since 24h`,
+		Action: withFieldSelection(eventsAction),
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:    "file",
@@ -26,15 +56,44 @@ func EventsCommand() *cli.Command {
 			&cli.StringFlag{
 				Name:    "format",
 				Aliases: []string{"F"},
-				Usage:   "Output format: text (default), json, xml",
+				Usage:   "Output format: text (default), json, jsonl, xml",
 				Value:   "text",
 			},
+			fieldsFlag(),
 			&cli.IntFlag{
 				Name:    "limit",
 				Aliases: []string{"l"},
 				Usage:   "Maximum number of events to show (default: 10, max: 100)",
 				Value:   10,
 			},
+			&cli.StringSliceFlag{
+				Name:  "type",
+				Usage: "Only events of this type or entity prefix, e.g. task_started or test (repeatable)",
+			},
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "Only events from this date, time or duration ago (e.g. 2025-08-16, 24h) on",
+			},
+			&cli.StringFlag{
+				Name:  "phase",
+				Usage: "Only events concerning this phase, its tasks or their tests",
+			},
+			&cli.StringFlag{
+				Name:  "task",
+				Usage: "Only events concerning this task or its tests",
+			},
+			&cli.StringFlag{
+				Name:  "correlate",
+				Usage: "Only events recorded by the command invocation with this correlation ID",
+			},
+			&cli.BoolFlag{
+				Name:  "stream",
+				Usage: "With --format json: one event per line, oldest first, unlimited unless --limit is set",
+			},
+			&cli.BoolFlag{
+				Name:  "all",
+				Usage: "Events of every epic of the workspace (see 'agentpm epics')",
+			},
 		},
 	}
 }
@@ -51,49 +110,101 @@ func eventsAction(ctx context.Context, c *cli.Command) error {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
-	// Determine epic file (command flag overrides config)
-	epicFile := c.String("file")
-	if epicFile == "" {
-		epicFile = cfg.CurrentEpic
-	}
-	if epicFile == "" {
-		return fmt.Errorf("no epic file specified. Use --file flag or run 'agentpm init' first")
-	}
-
 	// Get limit
 	limit := c.Int("limit")
 	if limit <= 0 {
 		limit = 10
 	}
 
-	// Create storage and query service
-	storage := storage.NewFileStorage()
-	queryService := query.NewQueryService(storage)
+	outputFormat := c.String("format")
+	stream := c.Bool("stream")
+	if stream && outputFormat != "json" && outputFormat != "jsonl" {
+		return exitcode.Errorf(exitcode.Validation, "--stream requires --format json")
+	}
 
-	// Load epic
-	err = queryService.LoadEpic(epicFile)
-	if err != nil {
-		return fmt.Errorf("failed to load epic: %w", err)
+	filter := query.EventFilter{
+		Types:   c.StringSlice("type"),
+		PhaseID: c.String("phase"),
+		TaskID:  c.String("task"),
+		Limit:   limit,
+
+		CorrelationID: c.String("correlate"),
+	}
+	if stream && !c.IsSet("limit") {
+		filter.Limit = -1
+	}
+	if since := c.String("since"); since != "" {
+		filter.Since, err = parseEventsSince(c, since)
+		if err != nil {
+			return err
+		}
 	}
 
-	// Get recent events
-	events, err := queryService.GetRecentEvents(limit)
+	events, err := recentEvents(c, cfg, filter)
 	if err != nil {
-		return fmt.Errorf("failed to get recent events: %w", err)
+		return err
+	}
+
+	if stream {
+		// Log pipelines expect chronological order
+		slices.Reverse(events)
+		return outputEventsJSONL(c, events)
 	}
 
 	// Output based on format
-	outputFormat := c.String("format")
 	switch outputFormat {
 	case "xml":
 		return outputEventsXML(c, events, limit)
 	case "json":
 		return outputEventsJSON(c, events, limit)
+	case "jsonl":
+		return outputEventsJSONL(c, events)
 	default:
 		return outputEventsText(c, events, limit)
 	}
 }
 
+// recentEvents returns the events matching filter of the epic file, or of
+// every epic of the workspace with --all
+func recentEvents(c *cli.Command, cfg *config.Config, filter query.EventFilter) ([]query.Event, error) {
+	if c.Bool("all") {
+		backend, err := storage.OpenBackend(cfg.StorageBackend, cfg.StorageDBPath())
+		if err != nil {
+			return nil, err
+		}
+		defer backend.Close()
+		events, err := query.RecentEventsAcross(backend, cfg.EpicFilePaths(), filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get recent events: %w", err)
+		}
+		return events, nil
+	}
+
+	// Determine epic file (command flag overrides config)
+	epicFile := c.String("file")
+	if epicFile == "" {
+		epicFile = cfg.EpicFilePath()
+	}
+	if epicFile == "" {
+		return nil, exitcode.Errorf(exitcode.Validation, "no epic file specified. Use --file flag or run 'agentpm init' first")
+	}
+
+	// Create storage and query service
+	queryService := query.NewQueryService(storage.NewFileStorage())
+
+	// Load epic
+	if err := queryService.LoadEpic(epicFile); err != nil {
+		return nil, fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	// Get recent events
+	events, err := queryService.GetRecentEvents(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent events: %w", err)
+	}
+	return events, nil
+}
+
 func outputEventsText(c *cli.Command, events []query.Event, limit int) error {
 	fmt.Fprintf(c.Root().Writer, "Recent Events Timeline (limit: %d)\n\n", limit)
 
@@ -110,6 +221,10 @@ func outputEventsText(c *cli.Command, events []query.Event, limit int) error {
 			event.Timestamp.Format("2006-01-02 15:04:05"),
 			event.Type)
 
+		if c.Bool("all") {
+			fmt.Fprintf(c.Root().Writer, "   Epic: %s\n", event.EpicID)
+		}
+
 		if event.Agent != "" {
 			fmt.Fprintf(c.Root().Writer, "   Agent: %s\n", event.Agent)
 		}
@@ -118,6 +233,14 @@ func outputEventsText(c *cli.Command, events []query.Event, limit int) error {
 			fmt.Fprintf(c.Root().Writer, "   Phase: %s\n", event.PhaseID)
 		}
 
+		if event.TaskID != "" {
+			fmt.Fprintf(c.Root().Writer, "   Task: %s\n", event.TaskID)
+		}
+
+		if event.CorrelationID != "" {
+			fmt.Fprintf(c.Root().Writer, "   Correlation: %s\n", event.CorrelationID)
+		}
+
 		if event.Content != "" {
 			// Format content with proper indentation
 			fmt.Fprintf(c.Root().Writer, "   Content: %s\n", event.Content)
@@ -129,29 +252,67 @@ func outputEventsText(c *cli.Command, events []query.Event, limit int) error {
 	return nil
 }
 
+// eventsOutput is the JSON output of events
+type eventsOutput struct {
+	Events []eventRecord `json:"events" doc:"Most recent first"`
+	Limit  int           `json:"limit"`
+	Total  int           `json:"total"`
+}
+
 func outputEventsJSON(c *cli.Command, events []query.Event, limit int) error {
-	fmt.Fprintf(c.Root().Writer, "{\n")
-	fmt.Fprintf(c.Root().Writer, "  \"events\": [\n")
+	jsonData, err := json.MarshalIndent(newEventsOutput(events, limit), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal events to JSON: %w", err)
+	}
+	fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	return nil
+}
 
-	for i, event := range events {
-		comma := ""
-		if i < len(events)-1 {
-			comma = ","
-		}
+func newEventsOutput(events []query.Event, limit int) eventsOutput {
+	output := eventsOutput{Events: make([]eventRecord, 0, len(events)), Limit: limit, Total: len(events)}
+	for _, event := range events {
+		output.Events = append(output.Events, newEventRecord(event))
+	}
+	return output
+}
+
+// eventRecord is one line of the JSONL events output
+type eventRecord struct {
+	ID        string `json:"id,omitempty"`
+	Epic      string `json:"epic,omitempty"`
+	Timestamp string `json:"timestamp"`
+	Type      string `json:"type"`
+	Agent     string `json:"agent"`
+	PhaseID   string `json:"phase_id"`
+	TaskID    string `json:"task_id,omitempty"`
+	Content   string `json:"content"`
+
+	CorrelationID string `json:"correlation_id,omitempty" doc:"Shared by the events of one command invocation"`
+}
 
-		fmt.Fprintf(c.Root().Writer, "    {\n")
-		fmt.Fprintf(c.Root().Writer, "      \"timestamp\": \"%s\",\n", event.Timestamp.Format("2006-01-02T15:04:05Z"))
-		fmt.Fprintf(c.Root().Writer, "      \"type\": \"%s\",\n", event.Type)
-		fmt.Fprintf(c.Root().Writer, "      \"agent\": \"%s\",\n", event.Agent)
-		fmt.Fprintf(c.Root().Writer, "      \"phase_id\": \"%s\",\n", event.PhaseID)
-		fmt.Fprintf(c.Root().Writer, "      \"content\": \"%s\"\n", event.Content)
-		fmt.Fprintf(c.Root().Writer, "    }%s\n", comma)
+func newEventRecord(event query.Event) eventRecord {
+	return eventRecord{
+		ID:        event.ID,
+		Epic:      event.EpicID,
+		Timestamp: event.Timestamp.UTC().Format(time.RFC3339),
+		Type:      event.Type,
+		Agent:     event.Agent,
+		PhaseID:   event.PhaseID,
+		TaskID:    event.TaskID,
+		Content:   event.Content,
+
+		CorrelationID: event.CorrelationID,
 	}
+}
 
-	fmt.Fprintf(c.Root().Writer, "  ],\n")
-	fmt.Fprintf(c.Root().Writer, "  \"limit\": %d,\n", limit)
-	fmt.Fprintf(c.Root().Writer, "  \"total\": %d\n", len(events))
-	fmt.Fprintf(c.Root().Writer, "}\n")
+// outputEventsJSONL streams one line per event in the given order
+func outputEventsJSONL(c *cli.Command, events []query.Event) error {
+	lines := output.NewJSONLWriter(c.Root().Writer)
+	for _, event := range events {
+		if err := lines.Write(newEventRecord(event)); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -170,6 +331,10 @@ func outputEventsXML(c *cli.Command, events []query.Event, limit int) error {
 			fmt.Fprintf(c.Root().Writer, " phase_id=\"%s\"", event.PhaseID)
 		}
 
+		if event.CorrelationID != "" {
+			fmt.Fprintf(c.Root().Writer, " correlation_id=\"%s\"", event.CorrelationID)
+		}
+
 		fmt.Fprintf(c.Root().Writer, ">\n")
 
 		if event.Content != "" {
@@ -182,3 +347,23 @@ func outputEventsXML(c *cli.Command, events []query.Event, limit int) error {
 	fmt.Fprintf(c.Root().Writer, "</events>\n")
 	return nil
 }
+
+// parseEventsSince parses --since as a date, an RFC3339 time or a duration
+// back from now (the root --time flag, if given)
+func parseEventsSince(c *cli.Command, since string) (time.Time, error) {
+	if duration, err := time.ParseDuration(since); err == nil {
+		now := time.Now()
+		if timeStr := c.String("time"); timeStr != "" {
+			now, err = time.Parse(time.RFC3339, timeStr)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+			}
+		}
+		return now.Add(-duration), nil
+	}
+	from, _, err := epic.ParseDate(since)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since: %w (or a duration like 24h)", err)
+	}
+	return from, nil
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var eventsApp = appOptions{commands: []func() *cli.Command{EventsCommand}, time: "2025-08-05T12:00:00Z"}
+
+const eventsEpic = `<epic id="8" name="Events Epic" status="wip" created_at="2025-08-01T09:00:00Z">
+    <phases>
+        <phase id="P1" name="Build" status="wip"/>
+        <phase id="P2" name="Ship" status="pending"/>
+    </phases>
+    <tasks>
+        <task id="T1" phase_id="P1" name="Task 1" status="completed"/>
+        <task id="T2" phase_id="P2" name="Task 2" status="pending"/>
+    </tasks>
+    <tests>
+        <test id="X1" task_id="T1" phase_id="P1" name="Test 1" test_status="done" result="failing"/>
+    </tests>
+    <events>
+        <event id="E1" type="phase_started" timestamp="2025-08-02T09:00:00Z"><data>Phase P1 started</data></event>
+        <event id="E2" type="task_started" timestamp="2025-08-02T10:00:00Z" actor="agent-1"><data>Task T1 started</data></event>
+        <event id="E3" type="test_failed" timestamp="2025-08-03T10:00:00Z"><data>Test X1 failed: timeout</data></event>
+        <event id="E4" type="task_completed" timestamp="2025-08-04T10:00:00Z" correlation_id="c0ffee000001"><data>Task T1 completed</data></event>
+        <event id="E5" type="phase_started" timestamp="2025-08-05T10:00:00Z" correlation_id="c0ffee000001"><data>Phase P2 started</data></event>
+    </events>
+</epic>`
+
+// createEventsProject writes eventsEpic as the current epic of a project and returns its config
+func createEventsProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "epic.xml"), []byte(eventsEpic), 0644))
+	configFile := filepath.Join(dir, ".agentpm.json")
+	require.NoError(t, os.WriteFile(configFile, []byte(`{"current_epic":"epic.xml"}`), 0644))
+	return configFile
+}
+
+func streamedEvents(t *testing.T, output string) []eventRecord {
+	t.Helper()
+	var records []eventRecord
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		var record eventRecord
+		require.NoError(t, json.Unmarshal([]byte(line), &record), line)
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestEventsCommandFilters(t *testing.T) {
+	t.Run("type prefix and since duration", func(t *testing.T) {
+		output, err := runApp(t, eventsApp.withConfig(createEventsProject(t)), "events", "--type", "task", "--since", "36h")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Showing 1 event(s)")
+		assert.Contains(t, output, "task_completed")
+		assert.Contains(t, output, "   Phase: P1\n   Task: T1\n")
+	})
+
+	t.Run("phase includes tests", func(t *testing.T) {
+		output, err := runApp(t, eventsApp.withConfig(createEventsProject(t)), "events", "--phase", "P1", "--format", "jsonl")
+		require.NoError(t, err)
+		records := streamedEvents(t, output)
+		require.Len(t, records, 4)
+		assert.Equal(t, "E4", records[0].ID)
+		assert.Equal(t, "E3", records[1].ID)
+		assert.Equal(t, "T1", records[1].TaskID)
+	})
+
+	t.Run("since date", func(t *testing.T) {
+		output, err := runApp(t, eventsApp.withConfig(createEventsProject(t)), "events", "--since", "2025-08-04", "--format", "jsonl")
+		require.NoError(t, err)
+		assert.Len(t, streamedEvents(t, output), 2)
+	})
+
+	t.Run("correlation id", func(t *testing.T) {
+		output, err := runApp(t, eventsApp.withConfig(createEventsProject(t)), "events", "--correlate", "c0ffee000001", "--format", "jsonl")
+		require.NoError(t, err)
+		records := streamedEvents(t, output)
+		require.Len(t, records, 2)
+		assert.Equal(t, "E5", records[0].ID)
+		assert.Equal(t, "E4", records[1].ID)
+		assert.Equal(t, "c0ffee000001", records[1].CorrelationID)
+
+		output, err = runApp(t, eventsApp.withConfig(createEventsProject(t)), "events", "--correlate", "c0ffee000001")
+		require.NoError(t, err)
+		assert.Contains(t, output, "   Correlation: c0ffee000001\n")
+	})
+
+	t.Run("invalid since", func(t *testing.T) {
+		_, err := runApp(t, eventsApp.withConfig(createEventsProject(t)), "events", "--since", "yesterday")
+		assert.ErrorContains(t, err, "invalid --since")
+	})
+}
+
+func TestEventsCommandStream(t *testing.T) {
+	t.Run("oldest first without default limit", func(t *testing.T) {
+		output, err := runApp(t, eventsApp.withConfig(createEventsProject(t)), "events", "--format", "json", "--stream")
+		require.NoError(t, err)
+		records := streamedEvents(t, output)
+		require.Len(t, records, 5)
+		assert.Equal(t, "E1", records[0].ID)
+		assert.Equal(t, "E5", records[4].ID)
+		assert.Equal(t, "8", records[1].Epic)
+		assert.Equal(t, "agent-1", records[1].Agent)
+		assert.Equal(t, "2025-08-02T10:00:00Z", records[1].Timestamp)
+	})
+
+	t.Run("explicit limit keeps the most recent", func(t *testing.T) {
+		output, err := runApp(t, eventsApp.withConfig(createEventsProject(t)), "events", "--format", "json", "--stream", "--limit", "2")
+		require.NoError(t, err)
+		records := streamedEvents(t, output)
+		require.Len(t, records, 2)
+		assert.Equal(t, "E4", records[0].ID)
+		assert.Equal(t, "E5", records[1].ID)
+	})
+
+	t.Run("with fields", func(t *testing.T) {
+		output, err := runApp(t, eventsApp.withConfig(createEventsProject(t)), "events", "--format", "json", "--stream", "--type", "test_failed", "--fields", "id,type")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"id": "E3", "type": "test_failed"}`, strings.TrimSpace(output))
+	})
+
+	t.Run("requires json", func(t *testing.T) {
+		_, err := runApp(t, eventsApp.withConfig(createEventsProject(t)), "events", "--stream")
+		assert.ErrorContains(t, err, "--stream requires --format json")
+	})
+}
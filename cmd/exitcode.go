@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/handoff"
+	"github.com/mindreframer/agentpm/internal/journal"
+	"github.com/mindreframer/agentpm/internal/lifecycle"
+	"github.com/mindreframer/agentpm/internal/phases"
+	"github.com/mindreframer/agentpm/internal/policy"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/mindreframer/agentpm/internal/tasks"
+	"github.com/mindreframer/agentpm/internal/tests"
+	"github.com/mindreframer/agentpm/internal/xmlquery"
+	"github.com/urfave/cli/v3"
+)
+
+// errorEnvelope is the JSON form of a failed command
+type errorEnvelope struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Code     string `json:"code"`
+	ExitCode int    `json:"exit_code"`
+	Message  string `json:"message"`
+	Hint     string `json:"hint,omitempty"`
+}
+
+// serviceError turns the error of a command service result into an error with
+// the exit code of its type. Most types are rejected state transitions. The
+// message is kept as is; a hint is appended on a line of its own.
+func serviceError(errType, message, hint string) error {
+	code := exitcode.Constraint
+	switch {
+	case strings.HasSuffix(errType, "not_found"):
+		code = exitcode.NotFound
+	case errType == "validation", errType == "invalid_operation":
+		code = exitcode.Validation
+	case errType == "io":
+		code = exitcode.Storage
+	case errType == "operation_failed":
+		code = classifyMessage(message)
+	}
+	return &exitcode.Error{Code: code, Err: errors.New(message), Hint: hint}
+}
+
+// ExitCode classifies err: an explicitly classified error keeps its code,
+// known error types map to theirs and anything else is classified by its
+// message
+func ExitCode(err error) int {
+	if err == nil {
+		return exitcode.Success
+	}
+	if code, ok := exitcode.Of(err); ok {
+		return code
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return exitcode.NotFound
+	}
+
+	var (
+		testErr    *tests.TestError
+		serviceErr *service.ServiceError
+		lockedErr  *storage.LockedError
+		fileErr    *xmlquery.FileAccessError
+		syntaxErr  *xmlquery.QuerySyntaxError
+		configErr  *xmlquery.ConfigurationError
+	)
+	switch {
+	case errors.As(err, &testErr):
+		switch testErr.Type {
+		case tests.ErrorTypeNotFound:
+			return exitcode.NotFound
+		case tests.ErrorTypeValidation:
+			return exitcode.Validation
+		case tests.ErrorTypeIO:
+			return exitcode.Storage
+		default:
+			return exitcode.Constraint
+		}
+	case errors.As(err, &serviceErr):
+		switch serviceErr.Type {
+		case service.ErrorTypeNotFound:
+			return exitcode.NotFound
+		case service.ErrorTypeIO:
+			return exitcode.Storage
+		default:
+			return exitcode.Validation
+		}
+	case errors.As(err, &lockedErr), errors.As(err, &fileErr):
+		return exitcode.Storage
+	case errors.As(err, &syntaxErr), errors.As(err, &configErr):
+		return exitcode.Validation
+	case isConstraintError(err):
+		return exitcode.Constraint
+	}
+	return classifyMessage(err.Error())
+}
+
+// isConstraintError reports whether err is one of the errors of rejected
+// state transitions
+func isConstraintError(err error) bool {
+	targets := []interface{}{
+		new(*tasks.TaskStateError), new(*tasks.TaskPhaseError), new(*tasks.TaskConstraintError),
+		new(*tasks.TaskAlreadyActiveError), new(*tasks.TaskDependencyError), new(*tasks.TaskSubTaskError),
+		new(*phases.PhaseStateError), new(*phases.PhaseConstraintError), new(*phases.PhaseIncompleteError),
+		new(*phases.PhaseAlreadyActiveError), new(*phases.PhaseTestDependencyError),
+		new(*phases.PhaseTestPrerequisiteError), new(*phases.PhaseDependencyError),
+		new(*epic.EpicCompletedError), new(*epic.PhaseFrozenError), new(*epic.StatusValidationError),
+		new(*policy.ViolationError), new(*lifecycle.TransitionError), new(*lifecycle.CompletionValidationError),
+		new(*journal.ModifiedError), new(*handoff.DivergedError),
+	}
+	for _, target := range targets {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyMessage is the fallback for plain errors. The order matters: a
+// "failed to read epic file" caused by a missing file is a not-found error.
+func classifyMessage(message string) int {
+	message = strings.ToLower(message)
+	hasAny := func(fragments ...string) bool {
+		for _, fragment := range fragments {
+			if strings.Contains(message, fragment) {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case hasAny("not found", "does not exist", "no such file"):
+		return exitcode.NotFound
+	case hasAny("failed to save", "failed to write", "failed to read", "locked", "permission denied"):
+		return exitcode.Storage
+	case hasAny("invalid", "required", "must be", "unknown", "malformed", "flag provided", "expected"):
+		return exitcode.Validation
+	case hasAny("cannot", "can't", "already", "frozen", "prerequisite", "blocked", "not allowed", "incomplete"):
+		return exitcode.Constraint
+	}
+	return exitcode.General
+}
+
+// reportedError marks an error the command already printed in the requested
+// output format, so that ReportError does not print a second envelope
+func reportedError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitcode.Error{Code: ExitCode(err), Err: err, Reported: true}
+}
+
+// TrackInvokedCommand records the innermost command whose action runs, so
+// that errors are reported in the output format requested on that command.
+// The returned function yields the command; when no action ran because a root
+// Before hook failed, it yields the subcommand the arguments named instead,
+// whose flags were parsed before the hooks ran. It falls back to root, e.g.
+// when the arguments could not be parsed.
+func TrackInvokedCommand(root *cli.Command) func() *cli.Command {
+	invoked := root
+	var track func(c *cli.Command)
+	track = func(c *cli.Command) {
+		if action := c.Action; action != nil {
+			c.Action = func(ctx context.Context, cmd *cli.Command) error {
+				invoked = cmd
+				return action(ctx, cmd)
+			}
+		}
+		for _, sub := range c.Commands {
+			track(sub)
+		}
+	}
+	track(root)
+	return func() *cli.Command {
+		if invoked != root {
+			return invoked
+		}
+		return parsedSubcommand(root)
+	}
+}
+
+// parsedSubcommand follows the command words left after parsing the flags of
+// each command from root down to the innermost command they name. Commands
+// whose flags were not parsed are not entered.
+func parsedSubcommand(root *cli.Command) *cli.Command {
+	c := root
+	for c.Args() != nil && c.Args().Present() {
+		sub := c.Command(c.Args().First())
+		if sub == nil || sub.Args() == nil {
+			break
+		}
+		c = sub
+	}
+	return c
+}
+
+// ReportError prints err in the output format of c and returns the exit code
+// the process ends with. Text output keeps the "Error: " line; json and xml
+// output an error envelope with the code name, the exit code, the message
+// and the hint, unless the command already printed its own.
+func ReportError(w io.Writer, c *cli.Command, err error) int {
+	code := ExitCode(err)
+	format := c.String("format")
+	if format != "json" && format != "jsonl" && format != "xml" {
+		fmt.Fprintf(w, "Error: %v\n", err)
+		return code
+	}
+	var classified *exitcode.Error
+	if errors.As(err, &classified) && classified.Reported {
+		return code
+	}
+
+	body := errorBody{Code: exitcode.Name(code), ExitCode: code, Message: err.Error()}
+	if message, hint, ok := strings.Cut(body.Message, "\nHint: "); ok {
+		body.Message, body.Hint = message, hint
+	}
+
+	switch format {
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("error")
+		root.CreateAttr("code", body.Code)
+		root.CreateAttr("exit_code", fmt.Sprintf("%d", body.ExitCode))
+		root.CreateElement("message").SetText(body.Message)
+		if body.Hint != "" {
+			root.CreateElement("hint").SetText(body.Hint)
+		}
+		doc.Indent(4)
+		doc.WriteTo(w)
+	case "jsonl":
+		jsonData, _ := json.Marshal(errorEnvelope{Error: body})
+		fmt.Fprintf(w, "%s\n", jsonData)
+	default:
+		jsonData, _ := json.MarshalIndent(errorEnvelope{Error: body}, "", "  ")
+		fmt.Fprintf(w, "%s\n", jsonData)
+	}
+	return code
+}
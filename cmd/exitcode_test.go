@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/mindreframer/agentpm/internal/tasks"
+	"github.com/mindreframer/agentpm/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestExitCode(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		code int
+	}{
+		"nil":              {nil, exitcode.Success},
+		"classified":       {fmt.Errorf("wrapped: %w", exitcode.Errorf(exitcode.Storage, "disk full")), exitcode.Storage},
+		"missing file":     {fmt.Errorf("failed to read epic file: %w", fs.ErrNotExist), exitcode.NotFound},
+		"test not found":   {&tests.TestError{Type: tests.ErrorTypeNotFound}, exitcode.NotFound},
+		"test io":          {&tests.TestError{Type: tests.ErrorTypeIO}, exitcode.Storage},
+		"test transition":  {&tests.TestError{Type: tests.ErrorTypeInvalidTransition}, exitcode.Constraint},
+		"task state":       {fmt.Errorf("failed: %w", &tasks.TaskStateError{TaskID: "1A_1"}), exitcode.Constraint},
+		"frozen phase":     {&epic.PhaseFrozenError{PhaseID: "1A"}, exitcode.Constraint},
+		"locked":           {&storage.LockedError{Path: "epic.xml"}, exitcode.Storage},
+		"unknown flag":     {errors.New("flag provided but not defined: -bogus"), exitcode.Validation},
+		"not found text":   {errors.New("task 9Z not found"), exitcode.NotFound},
+		"save failure":     {errors.New("failed to save epic: read-only file system"), exitcode.Storage},
+		"constraint text":  {errors.New("Cannot complete phase 1A: 2 tasks are still pending"), exitcode.Constraint},
+		"unclassified":     {errors.New("something went wrong"), exitcode.General},
+		"service io":       {serviceError("io", "failed", ""), exitcode.Storage},
+		"service missing":  {serviceError("test_not_found", "Test T9 not found", ""), exitcode.NotFound},
+		"service conflict": {serviceError("invalid_task_state", "Task 1A_1 is not active", ""), exitcode.Constraint},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.code, ExitCode(tc.err))
+		})
+	}
+}
+
+func TestServiceErrorKeepsMessage(t *testing.T) {
+	assert.Equal(t, "Task 1A_2 is blocked", serviceError("task_dependency_unmet", "Task 1A_2 is blocked", "").Error())
+	assert.Equal(t, "Task 1A_2 is blocked\nHint: Finish 1A_1", serviceError("task_dependency_unmet", "Task 1A_2 is blocked", "Finish 1A_1").Error())
+}
+
+func reportTo(t *testing.T, format string, err error) (string, int) {
+	t.Helper()
+	c := &cli.Command{
+		Name:  "agentpm",
+		Flags: []cli.Flag{&cli.StringFlag{Name: "format", Value: "text"}},
+	}
+	require.NoError(t, c.Run(context.Background(), []string{"agentpm", "--format", format}))
+
+	var out bytes.Buffer
+	code := ReportError(&out, c, err)
+	return out.String(), code
+}
+
+func TestReportError(t *testing.T) {
+	err := serviceError("task_constraint_violation", "Cannot start task 1A_2", "Complete 1A_1 first")
+
+	t.Run("text", func(t *testing.T) {
+		out, code := reportTo(t, "text", err)
+		assert.Equal(t, exitcode.Constraint, code)
+		assert.Equal(t, "Error: Cannot start task 1A_2\nHint: Complete 1A_1 first\n", out)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out, code := reportTo(t, "json", err)
+		assert.Equal(t, exitcode.Constraint, code)
+
+		var envelope errorEnvelope
+		require.NoError(t, json.Unmarshal([]byte(out), &envelope))
+		assert.Equal(t, errorBody{Code: "constraint", ExitCode: 3, Message: "Cannot start task 1A_2", Hint: "Complete 1A_1 first"}, envelope.Error)
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		out, code := reportTo(t, "xml", errors.New("config file not found: x.json"))
+		assert.Equal(t, exitcode.NotFound, code)
+		assert.Equal(t, "<error code=\"not_found\" exit_code=\"4\">\n    <message>config file not found: x.json</message>\n</error>\n", out)
+	})
+
+	t.Run("already reported", func(t *testing.T) {
+		out, code := reportTo(t, "json", reportedError(errors.New("unknown config key: bogus")))
+		assert.Equal(t, exitcode.Validation, code)
+		assert.Empty(t, out)
+	})
+}
+
+func TestTrackInvokedCommand(t *testing.T) {
+	epicFile := createBulkEpic(t)
+	app := &cli.Command{
+		Name: "agentpm",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "config"},
+			&cli.StringFlag{Name: "format", Value: "text"},
+		},
+		Commands: []*cli.Command{PassCommand(), StartCommand()},
+	}
+	app.Writer, app.ErrWriter = &bytes.Buffer{}, &bytes.Buffer{}
+	invoked := TrackInvokedCommand(app)
+
+	err := app.Run(context.Background(), []string{"agentpm", "pass", "T9", "--file", epicFile, "--format", "json"})
+	require.Error(t, err)
+	assert.Equal(t, "pass", invoked().Name)
+
+	var out bytes.Buffer
+	assert.Equal(t, exitcode.NotFound, ReportError(&out, invoked(), err))
+	assert.Contains(t, out.String(), `"code": "not_found"`)
+}
+
+func TestTrackInvokedCommandHookErrors(t *testing.T) {
+	newApp := func() (*cli.Command, func() *cli.Command) {
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config"},
+				&cli.StringFlag{Name: "format", Value: "text"},
+			},
+			Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+				return ctx, exitcode.Errorf(exitcode.Constraint, "epic 8 is completed")
+			},
+			Commands: []*cli.Command{StartCommand()},
+		}
+		app.Writer, app.ErrWriter = &bytes.Buffer{}, &bytes.Buffer{}
+		return app, TrackInvokedCommand(app)
+	}
+
+	t.Run("reported in the format of the subcommand", func(t *testing.T) {
+		app, invoked := newApp()
+		err := app.Run(context.Background(), []string{"agentpm", "start", "task", "1A_1", "--format", "json"})
+		require.Error(t, err)
+		assert.Equal(t, "task", invoked().Name)
+
+		var out bytes.Buffer
+		assert.Equal(t, exitcode.Constraint, ReportError(&out, invoked(), err))
+		var envelope errorEnvelope
+		require.NoError(t, json.Unmarshal(out.Bytes(), &envelope), out.String())
+		assert.Equal(t, "constraint", envelope.Error.Code)
+		assert.Equal(t, "epic 8 is completed", envelope.Error.Message)
+	})
+
+	t.Run("root without a subcommand", func(t *testing.T) {
+		app, invoked := newApp()
+		require.Error(t, app.Run(context.Background(), []string{"agentpm"}))
+		assert.Equal(t, "agentpm", invoked().Name)
+	})
+}
+
+func TestCommandExitCodes(t *testing.T) {
+	epicFile := createBulkEpic(t)
+
+	_, err := runApp(t, bulkApp.withStdin(""), "pass", "T9", "--file", epicFile)
+	assert.Equal(t, exitcode.NotFound, ExitCode(err))
+
+	// T4 is pending, it cannot pass before it was started
+	_, err = runApp(t, bulkApp.withStdin(""), "pass", "T4", "--file", epicFile)
+	require.Error(t, err)
+	assert.Equal(t, exitcode.Constraint, ExitCode(err))
+
+	_, err = runApp(t, bulkApp.withStdin(""), "pass", "T1", "--file", filepath.Join(t.TempDir(), "missing.xml"))
+	assert.Equal(t, exitcode.NotFound, ExitCode(err))
+
+	_, err = runApp(t, bulkApp.withStdin(""), "pass", "T1", "--config", filepath.Join(t.TempDir(), "missing.json"))
+	assert.Equal(t, exitcode.NotFound, ExitCode(err))
+
+	// Argument errors are classified where they are raised, not by their wording
+	_, err = runApp(t, appOptions{commands: []func() *cli.Command{QueryCommand}}, "query", "--all", "//task")
+	require.ErrorContains(t, err, "--all takes a selector")
+	assert.Equal(t, exitcode.Validation, ExitCode(err))
+
+	_, err = runApp(t, appOptions{commands: []func() *cli.Command{SearchCommand}}, "search")
+	require.Error(t, err)
+	assert.Equal(t, exitcode.Validation, ExitCode(err))
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/commands"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/ical"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// ExportCommand exports epic data to formats understood by other tools
+func ExportCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "export",
+		Usage: "Export epic data for use in other tools",
+		Description: `Write the whole epic as JSON, YAML or XML for toolchains that do not read
+the epic's own storage format. The export holds everything - timestamps,
+events, test statuses - so that 'agentpm import' restores the same epic.
+Subcommands export selected data in other formats.
+
+The epic is printed to standard output unless -o names a file ("-" is
+standard output too); --file - reads the epic to export from standard input.
+
+Examples:
+  agentpm export --format json                 # Print the epic as JSON
+  agentpm export --format yaml -o epic.yaml    # Write it to a file
+  cat epic.xml | agentpm export --format json --file -
+  agentpm export ical                          # Deadlines as an iCalendar feed`,
+		Flags: append(commands.GlobalFlags(),
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Output file path (default: stdout)",
+			},
+		),
+		Action: exportEpicAction,
+		Commands: []*cli.Command{
+			{
+				Name:  "ical",
+				Usage: "Export deadlines and milestones as an iCalendar feed",
+				Description: `Write phase and task deadlines (due="...") and milestone target dates
+as an iCalendar (.ics) feed that calendar apps can import or subscribe to.
+
+Dates are either a day (2025-09-15), exported as an all-day event, or an
+RFC3339 timestamp (2025-09-15T17:00:00Z). Event UIDs are stable, so
+re-importing updates existing events instead of duplicating them.
+
+Examples:
+  agentpm export ical                      # Print the calendar to stdout
+  agentpm export ical -o deadlines.ics     # Write it to a file`,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Output file path (default: stdout)",
+					},
+				},
+				Action: exportICalAction,
+			},
+		},
+	}
+}
+
+func exportEpicAction(ctx context.Context, c *cli.Command) error {
+	if c.Args().Len() > 0 {
+		return exitcode.Errorf(exitcode.Validation, "unknown export %q (see 'agentpm export --help')", c.Args().First())
+	}
+	format, err := storage.ParseFormat(c.String("format"))
+	if err != nil {
+		return exitcode.Errorf(exitcode.Validation, "export requires --format json, yaml or xml (got %q)", c.String("format"))
+	}
+
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+	epicData, err := loadEpicInput(c, epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+	data, err := storage.EncodeEpic(epicData, format)
+	if err != nil {
+		return err
+	}
+
+	outputFile := c.String("output")
+	if outputFile == "" || outputFile == storage.StdioPath {
+		_, err = c.Root().Writer.Write(data)
+		return err
+	}
+
+	if err := writeToFile(outputFile, string(data)); err != nil {
+		return fmt.Errorf("failed to write epic to file: %w", err)
+	}
+	fmt.Fprintf(c.Root().Writer, "Epic exported: %s (%s)\n", outputFile, format)
+	return nil
+}
+
+func exportICalAction(ctx context.Context, c *cli.Command) error {
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now()
+	if timeStr := c.String("time"); timeStr != "" {
+		timestamp, err = time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+		}
+	}
+
+	epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	events, err := ical.FromEpic(epicData)
+	if err != nil {
+		return fmt.Errorf("failed to export calendar: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := ical.Write(&buf, epicData.Name, events, timestamp); err != nil {
+		return fmt.Errorf("failed to export calendar: %w", err)
+	}
+
+	outputFile := c.String("output")
+	if outputFile == "" || outputFile == storage.StdioPath {
+		_, err = c.Root().Writer.Write(buf.Bytes())
+		return err
+	}
+
+	if err := writeToFile(outputFile, buf.String()); err != nil {
+		return fmt.Errorf("failed to write calendar to file: %w", err)
+	}
+	fmt.Fprintf(c.Root().Writer, "Calendar exported: %s (%d events)\n", outputFile, len(events))
+	return nil
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportICalCommand(t *testing.T) {
+	runExport := func(t *testing.T, epicFile string, args ...string) (string, error) {
+		var stdout, stderr bytes.Buffer
+		cmd := ExportCommand()
+		cmd.Root().Writer = &stdout
+		cmd.Root().ErrWriter = &stderr
+		err := cmd.Run(context.Background(), append([]string{"export", "--file", epicFile, "--time", "2025-08-16T15:30:00Z", "ical"}, args...))
+		return stdout.String(), err
+	}
+
+	dir := t.TempDir()
+	epicFile := filepath.Join(dir, "epic.xml")
+	testEpic := createEpicForReset()
+	testEpic.Phases[0].Due = "2025-09-15"
+	testEpic.Tasks[0].Due = "2025-09-10T17:00:00Z"
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicFile))
+
+	t.Run("prints the calendar to stdout", func(t *testing.T) {
+		output, err := runExport(t, epicFile)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(output, "BEGIN:VCALENDAR\r\n"))
+		assert.Contains(t, output, "UID:phase-P1@epic-1.agentpm\r\n")
+		assert.Contains(t, output, "SUMMARY:Task T1 due: Task 1\r\n")
+		assert.Equal(t, 2, strings.Count(output, "BEGIN:VEVENT"))
+	})
+
+	t.Run("writes the calendar to a file", func(t *testing.T) {
+		icsFile := filepath.Join(dir, "out", "deadlines.ics")
+		output, err := runExport(t, epicFile, "-o", icsFile)
+		require.NoError(t, err)
+		assert.Equal(t, "Calendar exported: "+icsFile+" (2 events)\n", output)
+
+		content, err := os.ReadFile(icsFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "DTSTART;VALUE=DATE:20250915\r\n")
+	})
+
+	t.Run("fails on invalid dates", func(t *testing.T) {
+		badFile := filepath.Join(dir, "bad.xml")
+		badEpic := createEpicForReset()
+		badEpic.Tasks[1].Due = "tomorrow"
+		require.NoError(t, storage.NewFileStorage().SaveEpic(badEpic, badFile))
+
+		_, err := runExport(t, badFile)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `task T2: invalid date "tomorrow"`)
+	})
+}
+
+func TestExportEpicCommand(t *testing.T) {
+	runExport := func(t *testing.T, epicFile string, args ...string) (string, error) {
+		var stdout bytes.Buffer
+		cmd := ExportCommand()
+		cmd.Root().Writer = &stdout
+		err := cmd.Run(context.Background(), append([]string{"export", "--file", epicFile}, args...))
+		return stdout.String(), err
+	}
+
+	dir := t.TempDir()
+	epicFile := filepath.Join(dir, "epic.xml")
+	fileStorage := storage.NewFileStorage()
+	require.NoError(t, fileStorage.SaveEpic(createEpicForReset(), epicFile))
+	original, err := fileStorage.LoadEpic(epicFile)
+	require.NoError(t, err)
+
+	t.Run("prints the epic as json", func(t *testing.T) {
+		output, err := runExport(t, epicFile, "--format", "json")
+		require.NoError(t, err)
+		assert.Contains(t, output, `"id": "epic-1"`)
+		assert.Contains(t, output, `"test_status": "done"`)
+
+		decoded, err := storage.DecodeEpic([]byte(output), storage.FormatJSON)
+		require.NoError(t, err)
+		assert.Equal(t, original, decoded)
+	})
+
+	t.Run("writes the epic as yaml to a file", func(t *testing.T) {
+		yamlFile := filepath.Join(dir, "out", "epic.yaml")
+		output, err := runExport(t, epicFile, "--format", "yaml", "-o", yamlFile)
+		require.NoError(t, err)
+		assert.Equal(t, "Epic exported: "+yamlFile+" (yaml)\n", output)
+
+		exported, err := fileStorage.LoadEpic(yamlFile)
+		require.NoError(t, err)
+		assert.Equal(t, original, exported)
+	})
+
+	t.Run("requires a storage format", func(t *testing.T) {
+		_, err := runExport(t, epicFile)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "export requires --format json, yaml or xml")
+	})
+}
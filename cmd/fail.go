@@ -3,31 +3,77 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"github.com/mindreframer/agentpm/internal/commands"
+	"github.com/mindreframer/agentpm/internal/epic"
 	"github.com/urfave/cli/v3"
 )
 
 func FailCommand() *cli.Command {
 	return &cli.Command{
 		Name:      "fail",
-		Usage:     "Mark a test as failed with reason",
-		ArgsUsage: "<test-id> [reason]",
+		Usage:     "Mark tests as failed with reason",
+		ArgsUsage: "<test-id>... [reason]",
 		Description: `Mark a test as failed with reason (transitions from wip to failed).
 
 The test must exist in the current epic and be in a valid state to fail.
-The failure reason is optional but recommended for tracking purposes.
+The failure reason is optional but recommended for tracking purposes. It is
+given with --reason or as the last argument; without --reason, a last
+argument that is not a test ID of the epic is taken as the reason.
+
+Several tests can be failed at once, given as arguments or read with
+--from-file. All of them are checked first: if any test cannot be failed,
+none is changed and every failing test is reported. Otherwise all tests are
+failed with the same reason and the epic is saved once.
+
+Manual tests (type="manual" in the epic) only fail with --verified-by naming
+the person who checked them; --evidence links what they saw.
+
+--expected, --actual, --stack-file and --link attach structured context to
+the failure. It is shown by failing, show test and handoff, and is cleared
+when the test passes. Only the first lines of a long stack trace are kept.
 
 Examples:
   agentpm fail 3A_T1 "Connection timeout"        # Fail test with reason
   agentpm fail 1B_T2                             # Fail test without reason
+  agentpm fail 3A_T1 3A_T2 "Database down"       # Fail several tests
+  agentpm fail --from-file failed.txt --reason "Nightly run"
+  agentpm fail 3A_T4 "Logo is cut off" --verified-by alice
+  agentpm fail 3A_T1 "Wrong total" --expected 42 --actual 41 --stack-file trace.txt
+  agentpm fail 3A_T1 "Flaky on CI" --link https://ci.example.com/runs/812
   agentpm fail 3A_T1 "Failed" --time 2025-08-16T15:30:00Z # Fail with timestamp`,
-		Flags:  commands.GlobalFlags(),
-		Action: failAction,
+		Flags: append(commands.GlobalFlags(), append(verificationFlags(),
+			&cli.StringFlag{
+				Name:  "reason",
+				Usage: "Failure reason (all arguments are then test IDs)",
+			},
+			fromFileFlag(),
+			&cli.StringFlag{
+				Name:  "expected",
+				Usage: "Value the test expected",
+			},
+			&cli.StringFlag{
+				Name:  "actual",
+				Usage: "Value the test got instead",
+			},
+			&cli.StringFlag{
+				Name:  "stack-file",
+				Usage: "File with the stack trace of the failure",
+			},
+			&cli.StringSliceFlag{
+				Name:  "link",
+				Usage: "URL of a CI run, log or ticket about the failure (repeatable)",
+			},
+		)...),
+		Action: withQuietResult(failAction),
 	}
 }
 
 func failAction(ctx context.Context, c *cli.Command) error {
+	if c.Args().Len() > 1 || c.IsSet("reason") || c.IsSet("from-file") {
+		return failTestsAction(c)
+	}
 	if c.Args().Len() < 1 {
 		return fmt.Errorf("test ID is required")
 	}
@@ -40,17 +86,25 @@ func failAction(ctx context.Context, c *cli.Command) error {
 		failureReason = c.Args().Get(1)
 	}
 
+	failureContext, err := failureContextFromFlags(c)
+	if err != nil {
+		return err
+	}
+
 	// Extract router context
 	routerCtx := commands.ExtractRouterContext(c)
 
 	// Create test request
 	request := commands.TestRequest{
-		TestID:        testID,
-		FailureReason: failureReason,
-		ConfigPath:    routerCtx.ConfigPath,
-		EpicFile:      routerCtx.EpicFile,
-		Time:          routerCtx.Time,
-		Format:        routerCtx.Format,
+		TestID:         testID,
+		FailureReason:  failureReason,
+		FailureContext: failureContext,
+		VerifiedBy:     c.String("verified-by"),
+		Evidence:       c.String("evidence"),
+		ConfigPath:     routerCtx.ConfigPath,
+		EpicFile:       routerCtx.EpicFile,
+		Time:           routerCtx.Time,
+		Format:         routerCtx.Format,
 	}
 
 	// Call the service
@@ -61,17 +115,85 @@ func failAction(ctx context.Context, c *cli.Command) error {
 
 	// Handle service result
 	if result.Error != nil {
-		return fmt.Errorf("%s", result.Error.Message)
+		return serviceError(result.Error.Type, result.Error.Message, result.Error.Hint)
 	}
 
 	// Output success message based on result
 	if result.Result != nil {
 		if failureReason != "" {
-			fmt.Printf("Test %s failed: %s\n", testID, failureReason)
+			fmt.Fprintf(c.Root().Writer, "Test %s failed: %s\n", testID, failureReason)
 		} else {
-			fmt.Printf("Test %s failed.\n", testID)
+			fmt.Fprintf(c.Root().Writer, "Test %s failed.\n", testID)
 		}
 	}
 
-	return nil
+	return routerCtx.WriteWarnings(result.Warnings)
+}
+
+// failTestsAction fails several tests with the same reason in one load/save cycle
+func failTestsAction(c *cli.Command) error {
+	fileIDs, err := idsFromFile(c)
+	if err != nil {
+		return err
+	}
+	// The arguments come last, where a trailing reason is looked for
+	testIDs := uniqueIDs(append(fileIDs, c.Args().Slice()...))
+	if len(testIDs) == 0 {
+		return fmt.Errorf("test ID is required")
+	}
+	failureContext, err := failureContextFromFlags(c)
+	if err != nil {
+		return err
+	}
+
+	routerCtx := commands.ExtractRouterContext(c)
+	result, err := commands.FailBatchTestService(commands.BatchTestRequest{
+		TestIDs:        testIDs,
+		Operation:      "fail",
+		FailureReason:  c.String("reason"),
+		FailureContext: failureContext,
+		TrailingReason: !c.IsSet("reason") && c.Args().Len() > 1,
+		VerifiedBy:     c.String("verified-by"),
+		Evidence:       c.String("evidence"),
+		ConfigPath:     routerCtx.ConfigPath,
+		EpicFile:       routerCtx.EpicFile,
+		Time:           routerCtx.Time,
+		Format:         routerCtx.Format,
+	})
+	if err != nil {
+		return err
+	}
+	if result.Error != nil {
+		return serviceError(result.Error.Type, result.Error.Message, result.Error.Hint)
+	}
+
+	for _, op := range result.Result.SuccessfulOperations {
+		if op.Reason != "" {
+			fmt.Fprintf(c.Root().Writer, "Test %s failed: %s\n", op.TestID, op.Reason)
+		} else {
+			fmt.Fprintf(c.Root().Writer, "Test %s failed.\n", op.TestID)
+		}
+	}
+	return routerCtx.WriteWarnings(result.Warnings)
+}
+
+// failureContextFromFlags builds the failure context from --expected,
+// --actual, --stack-file and --link, or returns nil when none is given
+func failureContextFromFlags(c *cli.Command) (*epic.FailureContext, error) {
+	failureContext := &epic.FailureContext{
+		Expected: c.String("expected"),
+		Actual:   c.String("actual"),
+		Links:    c.StringSlice("link"),
+	}
+	if path := c.String("stack-file"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stack file: %w", err)
+		}
+		failureContext.Stack = epic.TrimStack(string(data))
+	}
+	if failureContext.IsEmpty() {
+		return nil, nil
+	}
+	return failureContext, nil
 }
@@ -82,26 +82,26 @@ func failBatchAction(ctx context.Context, c *cli.Command) error {
 
 	// Handle service result
 	if result.Error != nil {
-		return fmt.Errorf("%s", result.Error.Message)
+		return serviceError(result.Error.Type, result.Error.Message, "")
 	}
 
 	// Output success message
 	if result.Result != nil {
 		summary := result.Result.Summary
-		fmt.Printf("Batch fail completed successfully:\n")
-		fmt.Printf("- %d tests failed\n", summary.FailedTests)
+		fmt.Fprintf(c.Root().Writer, "Batch fail completed successfully:\n")
+		fmt.Fprintf(c.Root().Writer, "- %d tests failed\n", summary.FailedTests)
 
 		if failureReason != "" {
-			fmt.Printf("- Reason: %s\n", failureReason)
+			fmt.Fprintf(c.Root().Writer, "- Reason: %s\n", failureReason)
 		}
 
 		if len(result.Result.SuccessfulOperations) > 0 {
-			fmt.Printf("\nFailed tests:\n")
+			fmt.Fprintf(c.Root().Writer, "\nFailed tests:\n")
 			for _, op := range result.Result.SuccessfulOperations {
 				if op.TestName != "" {
-					fmt.Printf("  %s (%s)\n", op.TestID, op.TestName)
+					fmt.Fprintf(c.Root().Writer, "  %s (%s)\n", op.TestID, op.TestName)
 				} else {
-					fmt.Printf("  %s\n", op.TestID)
+					fmt.Fprintf(c.Root().Writer, "  %s\n", op.TestID)
 				}
 			}
 		}
@@ -2,9 +2,18 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/beevik/etree"
 	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/output"
 	"github.com/mindreframer/agentpm/internal/query"
 	"github.com/mindreframer/agentpm/internal/storage"
 	"github.com/urfave/cli/v3"
@@ -29,6 +38,14 @@ func FailingCommand() *cli.Command {
 				Usage:   "Output format: text (default), json, xml",
 				Value:   "text",
 			},
+			&cli.BoolFlag{
+				Name:  "manual",
+				Usage: "List the manual tests still waiting for a person to check them",
+			},
+			&cli.BoolFlag{
+				Name:  "history",
+				Usage: "List every test that ever failed with its fail/pass counts and whether it is flaky",
+			},
 		},
 	}
 }
@@ -48,10 +65,10 @@ func failingAction(ctx context.Context, c *cli.Command) error {
 	// Determine epic file (command flag overrides config)
 	epicFile := c.String("file")
 	if epicFile == "" {
-		epicFile = cfg.CurrentEpic
+		epicFile = cfg.EpicFilePath()
 	}
 	if epicFile == "" {
-		return fmt.Errorf("no epic file specified. Use --file flag or run 'agentpm init' first")
+		return exitcode.Errorf(exitcode.Validation, "no epic file specified. Use --file flag or run 'agentpm init' first")
 	}
 
 	// Create storage and query service
@@ -64,8 +81,28 @@ func failingAction(ctx context.Context, c *cli.Command) error {
 		return fmt.Errorf("failed to load epic: %w", err)
 	}
 
-	// Get failing tests
-	failing, err := queryService.GetFailingTests()
+	if c.Bool("history") {
+		history, err := queryService.GetTestHistory()
+		if err != nil {
+			return fmt.Errorf("failed to get test history: %w", err)
+		}
+		switch c.String("format") {
+		case "xml":
+			return outputTestHistoryXML(c, history)
+		case "json":
+			return outputTestHistoryJSON(c, history)
+		default:
+			return outputTestHistoryText(c, history)
+		}
+	}
+
+	// Get failing tests, or the outstanding human checks
+	var failing []query.FailingTest
+	if c.Bool("manual") {
+		failing, err = queryService.GetManualChecks()
+	} else {
+		failing, err = queryService.GetFailingTests()
+	}
 	if err != nil {
 		return fmt.Errorf("failed to get failing tests: %w", err)
 	}
@@ -83,22 +120,34 @@ func failingAction(ctx context.Context, c *cli.Command) error {
 }
 
 func outputFailingText(c *cli.Command, failing []query.FailingTest) error {
-	fmt.Fprintf(c.Root().Writer, "Failing Tests Report\n\n")
-
-	if len(failing) == 0 {
-		fmt.Fprintf(c.Root().Writer, "✓ All tests are passing!\n")
-		return nil
+	if c.Bool("manual") {
+		fmt.Fprintf(c.Root().Writer, "Manual Checks Report\n\n")
+		if len(failing) == 0 {
+			fmt.Fprintf(c.Root().Writer, "✓ No manual checks outstanding!\n")
+			return nil
+		}
+		fmt.Fprintf(c.Root().Writer, "Found %d manual test(s) waiting for a person:\n\n", len(failing))
+	} else {
+		fmt.Fprintf(c.Root().Writer, "Failing Tests Report\n\n")
+		if len(failing) == 0 {
+			fmt.Fprintf(c.Root().Writer, "✓ All tests are passing!\n")
+			return nil
+		}
+		fmt.Fprintf(c.Root().Writer, "Found %d failing test(s):\n\n", len(failing))
 	}
 
-	fmt.Fprintf(c.Root().Writer, "Found %d failing test(s):\n\n", len(failing))
-
-	// Group by phase for better organization
+	// Group by phase for better organization, keeping the order of the list
 	phaseGroups := make(map[string][]query.FailingTest)
+	var phaseIDs []string
 	for _, test := range failing {
+		if _, seen := phaseGroups[test.PhaseID]; !seen {
+			phaseIDs = append(phaseIDs, test.PhaseID)
+		}
 		phaseGroups[test.PhaseID] = append(phaseGroups[test.PhaseID], test)
 	}
 
-	for phaseID, tests := range phaseGroups {
+	for _, phaseID := range phaseIDs {
+		tests := phaseGroups[phaseID]
 		if phaseID != "" {
 			fmt.Fprintf(c.Root().Writer, "Phase %s:\n", phaseID)
 		} else {
@@ -116,6 +165,11 @@ func outputFailingText(c *cli.Command, failing []query.FailingTest) error {
 			if test.FailureNote != "" {
 				fmt.Fprintf(c.Root().Writer, "    Failure: %s\n", test.FailureNote)
 			}
+			writeFailureContextText(c.Root().Writer, "    ", test.Context)
+
+			if test.VerifiedBy != "" {
+				fmt.Fprintf(c.Root().Writer, "    Verified by: %s\n", test.VerifiedBy)
+			}
 
 			fmt.Fprintf(c.Root().Writer, "\n")
 		}
@@ -124,29 +178,45 @@ func outputFailingText(c *cli.Command, failing []query.FailingTest) error {
 	return nil
 }
 
-func outputFailingJSON(c *cli.Command, failing []query.FailingTest) error {
-	fmt.Fprintf(c.Root().Writer, "{\n")
-	fmt.Fprintf(c.Root().Writer, "  \"failing_tests\": [\n")
+// failingOutput is the JSON output of failing
+type failingOutput struct {
+	FailingTests []failingTestOutput `json:"failing_tests"`
+	TotalFailing int                 `json:"total_failing"`
+}
 
-	for i, test := range failing {
-		comma := ""
-		if i < len(failing)-1 {
-			comma = ","
-		}
+type failingTestOutput struct {
+	ID          string               `json:"id"`
+	PhaseID     string               `json:"phase_id"`
+	TaskID      string               `json:"task_id"`
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	FailureNote string               `json:"failure_note"`
+	Context     *epic.FailureContext `json:"failure_context,omitempty"`
+	Manual      bool                 `json:"manual,omitempty"`
+	VerifiedBy  string               `json:"verified_by,omitempty"`
+}
 
-		fmt.Fprintf(c.Root().Writer, "    {\n")
-		fmt.Fprintf(c.Root().Writer, "      \"id\": \"%s\",\n", test.ID)
-		fmt.Fprintf(c.Root().Writer, "      \"phase_id\": \"%s\",\n", test.PhaseID)
-		fmt.Fprintf(c.Root().Writer, "      \"task_id\": \"%s\",\n", test.TaskID)
-		fmt.Fprintf(c.Root().Writer, "      \"name\": \"%s\",\n", test.Name)
-		fmt.Fprintf(c.Root().Writer, "      \"description\": \"%s\",\n", test.Description)
-		fmt.Fprintf(c.Root().Writer, "      \"failure_note\": \"%s\"\n", test.FailureNote)
-		fmt.Fprintf(c.Root().Writer, "    }%s\n", comma)
+func outputFailingJSON(c *cli.Command, failing []query.FailingTest) error {
+	output := failingOutput{FailingTests: make([]failingTestOutput, 0, len(failing)), TotalFailing: len(failing)}
+	for _, test := range failing {
+		output.FailingTests = append(output.FailingTests, failingTestOutput{
+			ID:          test.ID,
+			PhaseID:     test.PhaseID,
+			TaskID:      test.TaskID,
+			Name:        test.Name,
+			Description: test.Description,
+			FailureNote: test.FailureNote,
+			Context:     test.Context,
+			Manual:      test.Manual,
+			VerifiedBy:  test.VerifiedBy,
+		})
 	}
 
-	fmt.Fprintf(c.Root().Writer, "  ],\n")
-	fmt.Fprintf(c.Root().Writer, "  \"total_failing\": %d\n", len(failing))
-	fmt.Fprintf(c.Root().Writer, "}\n")
+	jsonData, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal failing tests to JSON: %w", err)
+	}
+	fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
 	return nil
 }
 
@@ -154,8 +224,14 @@ func outputFailingXML(c *cli.Command, failing []query.FailingTest) error {
 	fmt.Fprintf(c.Root().Writer, "<failing_tests>\n")
 
 	for _, test := range failing {
-		fmt.Fprintf(c.Root().Writer, "    <test id=\"%s\" phase_id=\"%s\" task_id=\"%s\">\n",
-			test.ID, test.PhaseID, test.TaskID)
+		fmt.Fprintf(c.Root().Writer, "    <test id=\"%s\" phase_id=\"%s\" task_id=\"%s\"", test.ID, test.PhaseID, test.TaskID)
+		if test.Manual {
+			fmt.Fprintf(c.Root().Writer, " type=\"%s\"", epic.TestTypeManual)
+		}
+		if test.VerifiedBy != "" {
+			fmt.Fprintf(c.Root().Writer, " verified_by=\"%s\"", test.VerifiedBy)
+		}
+		fmt.Fprintf(c.Root().Writer, ">\n")
 		fmt.Fprintf(c.Root().Writer, "        <name>%s</name>\n", test.Name)
 
 		if test.Description != "" {
@@ -165,6 +241,7 @@ func outputFailingXML(c *cli.Command, failing []query.FailingTest) error {
 		if test.FailureNote != "" {
 			fmt.Fprintf(c.Root().Writer, "        <failure_note>%s</failure_note>\n", test.FailureNote)
 		}
+		writeFailureContextXML(c.Root().Writer, "        ", test.Context)
 
 		fmt.Fprintf(c.Root().Writer, "    </test>\n")
 	}
@@ -172,3 +249,170 @@ func outputFailingXML(c *cli.Command, failing []query.FailingTest) error {
 	fmt.Fprintf(c.Root().Writer, "</failing_tests>\n")
 	return nil
 }
+
+// writeFailureContextText writes the expected and actual values, links and
+// stack of a failure as indented lines, writing nothing without a context
+func writeFailureContextText(w io.Writer, indent string, failureContext *epic.FailureContext) {
+	if failureContext.IsEmpty() {
+		return
+	}
+	if failureContext.Expected != "" {
+		fmt.Fprintf(w, "%sExpected: %s\n", indent, failureContext.Expected)
+	}
+	if failureContext.Actual != "" {
+		fmt.Fprintf(w, "%sActual: %s\n", indent, failureContext.Actual)
+	}
+	for _, link := range failureContext.Links {
+		fmt.Fprintf(w, "%sLink: %s\n", indent, link)
+	}
+	if failureContext.Stack != "" {
+		fmt.Fprintf(w, "%sStack:\n", indent)
+		for _, line := range strings.Split(failureContext.Stack, "\n") {
+			fmt.Fprintf(w, "%s  %s\n", indent, line)
+		}
+	}
+}
+
+// writeFailureContextXML writes a failure context as a <failure_context>
+// element, writing nothing without a context
+func writeFailureContextXML(w io.Writer, indent string, failureContext *epic.FailureContext) {
+	if failureContext.IsEmpty() {
+		return
+	}
+	fmt.Fprintf(w, "%s<failure_context>\n", indent)
+	if failureContext.Expected != "" {
+		fmt.Fprintf(w, "%s    <expected>%s</expected>\n", indent, xmlText(failureContext.Expected))
+	}
+	if failureContext.Actual != "" {
+		fmt.Fprintf(w, "%s    <actual>%s</actual>\n", indent, xmlText(failureContext.Actual))
+	}
+	if failureContext.Stack != "" {
+		fmt.Fprintf(w, "%s    <stack>%s</stack>\n", indent, xmlText(failureContext.Stack))
+	}
+	for _, link := range failureContext.Links {
+		fmt.Fprintf(w, "%s    <link>%s</link>\n", indent, xmlText(link))
+	}
+	fmt.Fprintf(w, "%s</failure_context>\n", indent)
+}
+
+// writeFailureContextMarkdown adds the fields of a failure context to a
+// Markdown document, with the stack as a code block
+func writeFailureContextMarkdown(md *output.Markdown, failureContext *epic.FailureContext) {
+	if failureContext.IsEmpty() {
+		return
+	}
+	if failureContext.Expected != "" {
+		md.Item("**Expected:** %s", output.Code(failureContext.Expected))
+	}
+	if failureContext.Actual != "" {
+		md.Item("**Actual:** %s", output.Code(failureContext.Actual))
+	}
+	for _, link := range failureContext.Links {
+		md.Field("Link", link)
+	}
+	if failureContext.Stack != "" {
+		md.Raw("```\n" + failureContext.Stack + "\n```\n\n")
+	}
+}
+
+// stabilityMarkers prefix the tests of the history report
+var stabilityMarkers = map[string]string{
+	epic.StabilityFailing: "✗",
+	epic.StabilityFixed:   "✓",
+	epic.StabilityFlaky:   "~",
+}
+
+func outputTestHistoryText(c *cli.Command, history []query.TestHistory) error {
+	fmt.Fprintf(c.Root().Writer, "Test History Report\n\n")
+	if len(history) == 0 {
+		fmt.Fprintf(c.Root().Writer, "✓ No test has failed yet!\n")
+		return nil
+	}
+	fmt.Fprintf(c.Root().Writer, "Found %d test(s) that failed at least once, %d flaky:\n\n", len(history), countFlaky(history))
+
+	for _, test := range history {
+		fmt.Fprintf(c.Root().Writer, "  %s %s (%s) %s\n", stabilityMarkers[test.Stability], test.ID, test.TaskID, test.Stability)
+		fmt.Fprintf(c.Root().Writer, "    %s\n", test.Name)
+		fmt.Fprintf(c.Root().Writer, "    Failed %d time(s)%s, passed %d time(s)%s\n",
+			test.FailCount, formatLastRun(test.LastFailedAt), test.PassCount, formatLastRun(test.LastPassedAt))
+		fmt.Fprintf(c.Root().Writer, "\n")
+	}
+	return nil
+}
+
+// formatLastRun renders the time of the last pass or fail, e.g. ", last 2025-08-16 15:30"
+func formatLastRun(at *time.Time) string {
+	if at == nil {
+		return ""
+	}
+	return ", last " + at.Format("2006-01-02 15:04")
+}
+
+func countFlaky(history []query.TestHistory) int {
+	flaky := 0
+	for _, test := range history {
+		if test.Stability == epic.StabilityFlaky {
+			flaky++
+		}
+	}
+	return flaky
+}
+
+// testHistoryOutput is the JSON output of failing --history
+type testHistoryOutput struct {
+	Tests      []testHistoryEntry `json:"tests"`
+	TotalTests int                `json:"total_tests"`
+	TotalFlaky int                `json:"total_flaky"`
+}
+
+type testHistoryEntry struct {
+	ID           string     `json:"id"`
+	PhaseID      string     `json:"phase_id"`
+	TaskID       string     `json:"task_id"`
+	Name         string     `json:"name"`
+	Stability    string     `json:"stability"`
+	FailCount    int        `json:"fail_count"`
+	PassCount    int        `json:"pass_count"`
+	LastFailedAt *time.Time `json:"last_failed_at,omitempty"`
+	LastPassedAt *time.Time `json:"last_passed_at,omitempty"`
+}
+
+func outputTestHistoryJSON(c *cli.Command, history []query.TestHistory) error {
+	output := testHistoryOutput{Tests: make([]testHistoryEntry, 0, len(history)), TotalTests: len(history), TotalFlaky: countFlaky(history)}
+	for _, test := range history {
+		output.Tests = append(output.Tests, testHistoryEntry(test))
+	}
+
+	jsonData, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal test history to JSON: %w", err)
+	}
+	fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	return nil
+}
+
+func outputTestHistoryXML(c *cli.Command, history []query.TestHistory) error {
+	doc := etree.NewDocument()
+	root := doc.CreateElement("test_history")
+	root.CreateAttr("flaky", strconv.Itoa(countFlaky(history)))
+	for _, test := range history {
+		elem := root.CreateElement("test")
+		elem.CreateAttr("id", test.ID)
+		elem.CreateAttr("phase_id", test.PhaseID)
+		elem.CreateAttr("task_id", test.TaskID)
+		elem.CreateAttr("stability", test.Stability)
+		elem.CreateAttr("fail_count", strconv.Itoa(test.FailCount))
+		elem.CreateAttr("pass_count", strconv.Itoa(test.PassCount))
+		if test.LastFailedAt != nil {
+			elem.CreateAttr("last_failed_at", test.LastFailedAt.Format(time.RFC3339))
+		}
+		if test.LastPassedAt != nil {
+			elem.CreateAttr("last_passed_at", test.LastPassedAt.Format(time.RFC3339))
+		}
+		elem.CreateElement("name").SetText(test.Name)
+	}
+
+	doc.Indent(4)
+	_, err := doc.WriteTo(c.Root().Writer)
+	return err
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var flakyApp = appOptions{commands: []func() *cli.Command{PassCommand, FailCommand, FailingCommand, StatusCommand, HandoffCommand, ShowCommand}}
+
+// createFlakyEpic returns an epic whose test T1 failed twice before passing
+// and whose test T2 failed once and was fixed
+func createFlakyEpic(t *testing.T) string {
+	t.Helper()
+	e := &epic.Epic{
+		ID:           "flaky",
+		Name:         "Flaky",
+		Status:       epic.StatusWIP,
+		CreatedAt:    time.Date(2025, 8, 1, 9, 0, 0, 0, time.UTC),
+		CurrentState: &epic.CurrentState{ActivePhase: "P1", ActiveTask: "P1_1"},
+		Phases:       []epic.Phase{{ID: "P1", Name: "Phase 1", Status: epic.StatusWIP}},
+		Tasks:        []epic.Task{{ID: "P1_1", PhaseID: "P1", Name: "Task 1", Status: epic.StatusWIP}},
+		Tests: []epic.Test{
+			{ID: "T1", TaskID: "P1_1", PhaseID: "P1", Name: "Uploads", Status: epic.StatusWIP, TestStatus: epic.TestStatusWIP},
+			{ID: "T2", TaskID: "P1_1", PhaseID: "P1", Name: "Downloads", Status: epic.StatusWIP, TestStatus: epic.TestStatusWIP},
+			{ID: "T3", TaskID: "P1_1", PhaseID: "P1", Name: "Renames", Status: epic.StatusWIP, TestStatus: epic.TestStatusWIP},
+		},
+	}
+	epicFile := filepath.Join(t.TempDir(), "epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(e, epicFile))
+
+	for _, args := range [][]string{
+		{"fail", "T1", "timeout"}, {"fail", "T1", "timeout"}, {"pass", "T1"},
+		{"fail", "T2", "off by one"}, {"pass", "T2"},
+		{"pass", "T3"},
+	} {
+		_, err := runApp(t, flakyApp, append(args, "--file", epicFile, "--time", "2025-08-16T15:30:00Z")...)
+		require.NoError(t, err)
+	}
+	return epicFile
+}
+
+func TestFailingHistory(t *testing.T) {
+	epicFile := createFlakyEpic(t)
+
+	t.Run("counts runs in the epic file", func(t *testing.T) {
+		tests := loadBulkTests(t, epicFile)
+		assert.Equal(t, 2, tests["T1"].FailCount)
+		assert.Equal(t, 1, tests["T1"].PassCount)
+		assert.Equal(t, 0, tests["T3"].FailCount)
+	})
+
+	t.Run("text", func(t *testing.T) {
+		out, err := runApp(t, flakyApp, "failing", "--history", "--file", epicFile)
+		require.NoError(t, err)
+		assert.Contains(t, out, "Found 2 test(s) that failed at least once, 1 flaky")
+		assert.Contains(t, out, "~ T1 (P1_1) flaky")
+		assert.Contains(t, out, "Failed 2 time(s), last 2025-08-16 15:30, passed 1 time(s), last 2025-08-16 15:30")
+		assert.Contains(t, out, "✓ T2 (P1_1) fixed")
+		assert.NotContains(t, out, "T3")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out, err := runApp(t, flakyApp, "failing", "--history", "--file", epicFile, "--format", "json")
+		require.NoError(t, err)
+		var output testHistoryOutput
+		require.NoError(t, json.Unmarshal([]byte(out), &output))
+		assert.Equal(t, 2, output.TotalTests)
+		assert.Equal(t, 1, output.TotalFlaky)
+		assert.Equal(t, epic.StabilityFlaky, output.Tests[0].Stability)
+		assert.Equal(t, 2, output.Tests[0].FailCount)
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		out, err := runApp(t, flakyApp, "failing", "--history", "--file", epicFile, "--format", "xml")
+		require.NoError(t, err)
+		assert.Contains(t, out, `<test_history flaky="1">`)
+		assert.Contains(t, out, `<test id="T1" phase_id="P1" task_id="P1_1" stability="flaky" fail_count="2" pass_count="1"`)
+	})
+
+	t.Run("status", func(t *testing.T) {
+		out, err := runApp(t, flakyApp, "status", "--file", epicFile)
+		require.NoError(t, err)
+		assert.Contains(t, out, "Flaky Tests: 1 (see agentpm failing --history)")
+
+		out, err = runApp(t, flakyApp, "status", "--file", epicFile, "--format", "json")
+		require.NoError(t, err)
+		assert.Contains(t, out, `"flaky_tests": 1`)
+	})
+
+	t.Run("handoff", func(t *testing.T) {
+		out, err := runApp(t, flakyApp, "handoff", "--file", epicFile, "--format", "text")
+		require.NoError(t, err)
+		assert.Contains(t, out, "FLAKY TESTS (1):\n  ~ T1 on P1_1 failed 2 of 3 runs: Uploads")
+
+		out, err = runApp(t, flakyApp, "handoff", "--file", epicFile, "--format", "json")
+		require.NoError(t, err)
+		var report struct {
+			FlakyTests []map[string]interface{} `json:"flaky_tests"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(out), &report))
+		require.Len(t, report.FlakyTests, 1)
+		assert.Equal(t, "T1", report.FlakyTests[0]["id"])
+
+		out, err = runApp(t, flakyApp, "handoff", "--file", epicFile)
+		require.NoError(t, err)
+		assert.Contains(t, out, `<test id="T1" task_id="P1_1" fail_count="2" pass_count="1">Uploads</test>`)
+	})
+}
+
+func TestFailureContext(t *testing.T) {
+	epicFile := createFlakyEpic(t)
+	var stack strings.Builder
+	for i := 1; i <= epic.MaxStackLines+5; i++ {
+		fmt.Fprintf(&stack, "upload.go:%d\n", i)
+	}
+	stackFile := filepath.Join(t.TempDir(), "trace.txt")
+	require.NoError(t, os.WriteFile(stackFile, []byte(stack.String()), 0644))
+
+	_, err := runApp(t, flakyApp, "fail", "T3", "Wrong size", "--expected", "1024", "--actual", "<1023>", "--stack-file", stackFile, "--link", "https://ci.example.com/runs/1", "--link", "https://ci.example.com/runs/2", "--file", epicFile)
+	require.NoError(t, err)
+
+	t.Run("stored on the test", func(t *testing.T) {
+		test := loadBulkTests(t, epicFile)["T3"]
+		require.NotNil(t, test.FailureContext)
+		assert.Equal(t, "1024", test.FailureContext.Expected)
+		assert.Equal(t, "<1023>", test.FailureContext.Actual)
+		assert.Equal(t, []string{"https://ci.example.com/runs/1", "https://ci.example.com/runs/2"}, test.FailureContext.Links)
+		assert.True(t, strings.HasSuffix(test.FailureContext.Stack, "upload.go:50\n... 5 more lines"))
+
+		content, err := os.ReadFile(epicFile)
+		require.NoError(t, err)
+		assert.Empty(t, epic.ValidateStructure(content))
+	})
+
+	t.Run("failing", func(t *testing.T) {
+		out, err := runApp(t, flakyApp, "failing", "--file", epicFile)
+		require.NoError(t, err)
+		assert.Contains(t, out, "    Failure: Wrong size\n    Expected: 1024\n    Actual: <1023>\n    Link: https://ci.example.com/runs/1\n")
+		assert.Contains(t, out, "    Stack:\n      upload.go:1\n")
+
+		out, err = runApp(t, flakyApp, "failing", "--file", epicFile, "--format", "json")
+		require.NoError(t, err)
+		assert.Contains(t, out, `"expected": "1024"`)
+
+		out, err = runApp(t, flakyApp, "failing", "--file", epicFile, "--format", "xml")
+		require.NoError(t, err)
+		assert.Contains(t, out, "<actual>&lt;1023&gt;</actual>")
+	})
+
+	t.Run("show test", func(t *testing.T) {
+		out, err := runApp(t, flakyApp, "show", "test", "T3", "--file", epicFile)
+		require.NoError(t, err)
+		assert.Contains(t, out, "Failure: Wrong size\nExpected: 1024\nActual: <1023>\n")
+
+		out, err = runApp(t, flakyApp, "show", "test", "T3", "--full", "--file", epicFile)
+		require.NoError(t, err)
+		assert.Contains(t, out, "Expected: 1024\nActual: <1023>\n")
+	})
+
+	t.Run("handoff", func(t *testing.T) {
+		out, err := runApp(t, flakyApp, "handoff", "--file", epicFile, "--format", "text")
+		require.NoError(t, err)
+		assert.Contains(t, out, "FAILING TESTS (1):\n  ✗ T3 on P1_1: Renames (Wrong size)\n    Expected: 1024\n")
+
+		out, err = runApp(t, flakyApp, "handoff", "--file", epicFile, "--format", "json")
+		require.NoError(t, err)
+		var report struct {
+			FailingTests []struct {
+				ID      string               `json:"id"`
+				Context *epic.FailureContext `json:"failure_context"`
+			} `json:"failing_tests"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(out), &report))
+		require.Len(t, report.FailingTests, 1)
+		assert.Equal(t, "<1023>", report.FailingTests[0].Context.Actual)
+	})
+
+	t.Run("cleared on pass", func(t *testing.T) {
+		_, err := runApp(t, flakyApp, "pass", "T3", "--file", epicFile)
+		require.NoError(t, err)
+		assert.Nil(t, loadBulkTests(t, epicFile)["T3"].FailureContext)
+	})
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// fieldsFlag lets json/jsonl/xml output be reduced to the fields an agent or dashboard needs
+func fieldsFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "fields",
+		Usage: "Comma separated fields to include in json/jsonl/xml output (e.g. name,progress.completion_percentage)",
+	}
+}
+
+// withFieldSelection wraps a command action so its json/jsonl/xml output only contains
+// the fields requested with --fields. The projection is applied to the rendered
+// document, so commands do not need to know about it.
+func withFieldSelection(action cli.ActionFunc) cli.ActionFunc {
+	return func(ctx context.Context, c *cli.Command) error {
+		fields := output.ParseFields(c.String("fields"))
+		if len(fields) == 0 {
+			return action(ctx, c)
+		}
+
+		format := c.String("format")
+		if format != "json" && format != "jsonl" && format != "xml" {
+			return exitcode.Errorf(exitcode.Validation, "--fields requires --format json, jsonl or xml")
+		}
+
+		root := c.Root()
+		writer := root.Writer
+
+		// JSON Lines are projected line by line so they keep streaming
+		if format == "jsonl" || (format == "json" && c.Bool("stream")) {
+			projector := output.NewJSONLProjector(writer, fields)
+			root.Writer = projector
+			err := action(ctx, c)
+			root.Writer = writer
+			if err != nil {
+				return err
+			}
+			return projector.Flush()
+		}
+
+		var buf bytes.Buffer
+		root.Writer = &buf
+		err := action(ctx, c)
+		root.Writer = writer
+
+		if err != nil {
+			// Pass error output through unchanged
+			writer.Write(buf.Bytes())
+			return err
+		}
+
+		projected, err := output.Project(format, buf.Bytes(), fields)
+		if err != nil {
+			return err
+		}
+		_, err = writer.Write(projected)
+		return err
+	}
+}
@@ -12,6 +12,8 @@ import (
 
 	"github.com/beevik/etree"
 	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/storage"
 	"github.com/urfave/cli/v3"
 )
 
@@ -75,10 +77,10 @@ func fixXMLAction(ctx context.Context, c *cli.Command) error {
 	// Determine epic file
 	epicFile := c.String("file")
 	if epicFile == "" {
-		epicFile = cfg.CurrentEpic
+		epicFile = cfg.EpicFilePath()
 	}
 	if epicFile == "" {
-		return fmt.Errorf("no epic file specified. Use --file flag or run 'agentpm init' first")
+		return exitcode.Errorf(exitcode.Validation, "no epic file specified. Use --file flag or run 'agentpm init' first")
 	}
 
 	absPath, err := filepath.Abs(epicFile)
@@ -115,7 +117,7 @@ func fixXMLAction(ctx context.Context, c *cli.Command) error {
 	}
 
 	// Dry run - just show what would be fixed
-	if c.Bool("dry-run") {
+	if c.Bool("dry-run") || storage.DryRun() {
 		fmt.Fprintf(c.Root().Writer, "\nDry run mode - no changes made.\n")
 		fmt.Fprintf(c.Root().Writer, "Run without --dry-run to apply fixes.\n")
 		return nil
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/forecast"
+	"github.com/mindreframer/agentpm/internal/lint"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// ForecastCommand estimates when the current epic will be done
+func ForecastCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "forecast",
+		Usage: "Forecast completion dates of the current epic (Monte Carlo)",
+		Description: `Simulate the remaining work of the current epic many times, drawing each
+open task's duration from the cycle times (start to completion) of the tasks
+completed in the workspace, and report the dates by which 50%, 80% and 95%
+of the simulated runs were done - together with the assumptions behind them.
+
+The workspace is the directory of the config file (or the working
+directory without one); every XML file below it with an <epic> root
+contributes history.
+
+Examples:
+  agentpm forecast                          # 1000 runs, one task at a time
+  agentpm forecast --simulate 5000 --parallel 3
+  agentpm forecast --seed 42 --format json  # Reproducible output`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config",
+			},
+			&cli.IntFlag{
+				Name:  "simulate",
+				Usage: "Number of simulated runs",
+				Value: 1000,
+			},
+			&cli.IntFlag{
+				Name:  "parallel",
+				Usage: "Number of tasks worked on at the same time",
+				Value: 1,
+			},
+			&cli.IntFlag{
+				Name:  "seed",
+				Usage: "Random seed (default: derived from the current time)",
+			},
+		},
+		Action: forecastAction,
+	}
+}
+
+func forecastAction(ctx context.Context, c *cli.Command) error {
+	if c.Int("simulate") < 1 || c.Int("parallel") < 1 {
+		return fmt.Errorf("--simulate and --parallel must be at least 1")
+	}
+
+	now := time.Now()
+	if timeStr := c.String("time"); timeStr != "" {
+		var err error
+		now, err = time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+		}
+	}
+
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+
+	fileStorage := storage.NewFileStorage()
+	epicData, err := fileStorage.LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	history, err := workspaceCycleTimes(c, epicFile)
+	if err != nil {
+		return err
+	}
+	history = append(history, forecast.CycleTimes(epicData)...)
+
+	seed := int64(c.Int("seed"))
+	if !c.IsSet("seed") {
+		seed = now.UnixNano()
+	}
+
+	result, err := forecast.Simulate(epicData, history, now, forecast.Options{
+		Runs:     int(c.Int("simulate")),
+		Parallel: int(c.Int("parallel")),
+		Seed:     seed,
+	})
+	if err != nil {
+		return err
+	}
+
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal forecast to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		outputForecastXML(c, result)
+	default:
+		outputForecastText(c, result)
+	}
+	return nil
+}
+
+// workspaceCycleTimes collects the cycle times of all workspace epics except the
+// current one
+func workspaceCycleTimes(c *cli.Command, epicFile string) ([]time.Duration, error) {
+	workspace, err := workspaceRoot(c.String("config"))
+	if err != nil {
+		return nil, err
+	}
+	files, err := lint.FindEpicFiles(workspace)
+	if err != nil {
+		return nil, err
+	}
+	absEpic, err := filepath.Abs(epicFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve epic path: %w", err)
+	}
+
+	fileStorage := storage.NewFileStorage()
+	var history []time.Duration
+	for _, file := range files {
+		if file == absEpic {
+			continue
+		}
+		epicData, err := fileStorage.LoadEpic(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load epic %s: %w", workspaceRelative(workspace, file), err)
+		}
+		history = append(history, forecast.CycleTimes(epicData)...)
+	}
+	return history, nil
+}
+
+func outputForecastText(c *cli.Command, result *forecast.Result) {
+	w := c.Root().Writer
+
+	fmt.Fprintf(w, "Forecast for epic %s: %d open tasks (%d in progress), %d simulated runs\n\n",
+		result.EpicID, result.Remaining, result.InProgress, result.Runs)
+	for _, estimate := range result.Estimates {
+		fmt.Fprintf(w, "  P%d  %s  (in %s)\n", estimate.Percentile, estimate.Date.Format("2006-01-02 15:04 MST"), formatMinutes(estimate.Minutes))
+	}
+
+	fmt.Fprintf(w, "\nAssumptions:\n")
+	for _, assumption := range result.Assumptions {
+		fmt.Fprintf(w, "  - %s\n", assumption)
+	}
+	fmt.Fprintf(w, "\nSeed: %d (pass --seed to reproduce)\n", result.Seed)
+}
+
+func outputForecastXML(c *cli.Command, result *forecast.Result) {
+	doc := etree.NewDocument()
+	root := doc.CreateElement("forecast")
+	root.CreateAttr("epic", result.EpicID)
+	root.CreateAttr("remaining_tasks", strconv.Itoa(result.Remaining))
+	root.CreateAttr("in_progress_tasks", strconv.Itoa(result.InProgress))
+	root.CreateAttr("history_samples", strconv.Itoa(result.Samples))
+	root.CreateAttr("runs", strconv.Itoa(result.Runs))
+	root.CreateAttr("parallel", strconv.Itoa(result.Parallel))
+	root.CreateAttr("seed", strconv.FormatInt(result.Seed, 10))
+
+	for _, estimate := range result.Estimates {
+		estimateElem := root.CreateElement("estimate")
+		estimateElem.CreateAttr("percentile", strconv.Itoa(estimate.Percentile))
+		estimateElem.CreateAttr("minutes", strconv.Itoa(estimate.Minutes))
+		estimateElem.CreateAttr("date", estimate.Date.Format(time.RFC3339))
+	}
+
+	assumptionsElem := root.CreateElement("assumptions")
+	for _, assumption := range result.Assumptions {
+		assumptionsElem.CreateElement("assumption").SetText(assumption)
+	}
+
+	doc.Indent(4)
+	doc.WriteTo(c.Root().Writer)
+}
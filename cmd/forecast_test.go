@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/forecast"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var forecastApp = appOptions{commands: []func() *cli.Command{ForecastCommand}, time: "2025-08-20T12:00:00Z"}
+
+func TestForecastCommand(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, ".agentpm.json")
+	require.NoError(t, os.WriteFile(configFile, []byte(`{"current_epic": "epic-2.xml"}`), 0644))
+
+	startedAt := time.Date(2025, 8, 15, 9, 0, 0, 0, time.UTC)
+	completedAt := startedAt.Add(4 * time.Hour)
+	finished := createEpicForReset()
+	for i := range finished.Tasks {
+		finished.Tasks[i].Status = epic.StatusCompleted
+		finished.Tasks[i].StartedAt = &startedAt
+		finished.Tasks[i].CompletedAt = &completedAt
+	}
+	current := createEpicForReset()
+	current.ID = "epic-2"
+	current.Tasks[0].Status = epic.StatusPending
+
+	fileStorage := storage.NewFileStorage()
+	require.NoError(t, fileStorage.SaveEpic(finished, filepath.Join(dir, "epic-1.xml")))
+	require.NoError(t, fileStorage.SaveEpic(current, filepath.Join(dir, "epic-2.xml")))
+
+	t.Run("text output lists percentiles and assumptions", func(t *testing.T) {
+		output, err := runApp(t, forecastApp.withConfig(configFile).withFormat("text"), "forecast", "--simulate", "50", "--seed", "7")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Forecast for epic epic-2: 2 open tasks (0 in progress), 50 simulated runs")
+		assert.Contains(t, output, "  P50  2025-08-20 20:00 UTC  (in 8h00m)\n")
+		assert.Contains(t, output, "  P95  2025-08-20 20:00 UTC  (in 8h00m)\n")
+		assert.Contains(t, output, "Assumptions:\n  - Cycle times (start to completion) of 2 completed tasks")
+		assert.Contains(t, output, "Seed: 7")
+	})
+
+	t.Run("json output with parallel workers", func(t *testing.T) {
+		output, err := runApp(t, forecastApp.withConfig(configFile).withFormat("json"), "forecast", "--parallel", "2")
+		require.NoError(t, err)
+
+		var result forecast.Result
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.Equal(t, 1000, result.Runs)
+		assert.Equal(t, 240, result.Estimates[0].Minutes)
+	})
+
+	t.Run("fails without history", func(t *testing.T) {
+		emptyDir := t.TempDir()
+		emptyConfig := filepath.Join(emptyDir, ".agentpm.json")
+		require.NoError(t, os.WriteFile(emptyConfig, []byte(`{"current_epic": "epic-2.xml"}`), 0644))
+		require.NoError(t, fileStorage.SaveEpic(current, filepath.Join(emptyDir, "epic-2.xml")))
+
+		_, err := runApp(t, forecastApp.withConfig(emptyConfig).withFormat("text"), "forecast")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no completed tasks")
+	})
+}
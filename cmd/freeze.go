@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/commands"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/phases"
+	"github.com/mindreframer/agentpm/internal/query"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// FreezeCommand marks phases immutable
+func FreezeCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "freeze",
+		Usage: "Freeze a phase so it can no longer change",
+		Description: `Mark a phase immutable, e.g. once it has shipped.
+
+While a phase is frozen the phase, its tasks and its tests cannot change
+status: start, done, cancel, pass, fail and reset fail with a dedicated error
+pointing to 'agentpm unfreeze'. The reason is recorded on the phase and in
+the event log.
+
+Subcommands:
+  phase <id> --reason <text>    Freeze a phase
+
+Examples:
+  agentpm freeze phase 1 --reason "Shipped in v1.2"`,
+		Flags: commands.GlobalFlags(),
+		Commands: []*cli.Command{
+			{
+				Name:      "phase",
+				Usage:     "Freeze a phase",
+				ArgsUsage: "<phase-id>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "reason",
+						Usage:    "Why the phase is frozen (recorded on the phase and in the event log)",
+						Required: true,
+					},
+				},
+				Action: withQuietResult(func(ctx context.Context, c *cli.Command) error {
+					return runPhaseFreeze(c, true)
+				}),
+			},
+		},
+	}
+}
+
+// UnfreezeCommand makes frozen phases mutable again
+func UnfreezeCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "unfreeze",
+		Usage: "Unfreeze a frozen phase",
+		Description: `Reverse 'agentpm freeze': the phase, its tasks and its tests can change
+status again.
+
+Subcommands:
+  phase <id>    Unfreeze a phase
+
+Examples:
+  agentpm unfreeze phase 1 --reason "Hotfix for search ranking"`,
+		Flags: commands.GlobalFlags(),
+		Commands: []*cli.Command{
+			{
+				Name:      "phase",
+				Usage:     "Unfreeze a phase",
+				ArgsUsage: "<phase-id>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "reason",
+						Usage: "Reason recorded in the event log",
+					},
+				},
+				Action: withQuietResult(func(ctx context.Context, c *cli.Command) error {
+					return runPhaseFreeze(c, false)
+				}),
+			},
+		},
+	}
+}
+
+func runPhaseFreeze(c *cli.Command, freeze bool) error {
+	if c.Args().Len() != 1 {
+		return exitcode.Errorf(exitcode.Validation, "phase requires exactly one argument")
+	}
+	phaseID := c.Args().First()
+
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now()
+	if timeStr := c.String("time"); timeStr != "" {
+		timestamp, err = time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+		}
+	}
+
+	storageImpl := storage.NewFileStorage()
+	phaseService := phases.NewPhaseService(storageImpl, query.NewQueryService(storageImpl))
+
+	epicData, err := storageImpl.LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	if freeze {
+		err = phaseService.FreezePhase(epicData, phaseID, c.String("reason"), timestamp)
+	} else {
+		err = phaseService.UnfreezePhase(epicData, phaseID, c.String("reason"), timestamp)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := storageImpl.SaveEpic(epicData, epicFile); err != nil {
+		return fmt.Errorf("failed to save epic: %w", err)
+	}
+
+	var phase *epic.Phase
+	for i := range epicData.Phases {
+		if epicData.Phases[i].ID == phaseID {
+			phase = &epicData.Phases[i]
+		}
+	}
+	return writePhaseFreezeResult(c, phase, timestamp)
+}
+
+func writePhaseFreezeResult(c *cli.Command, phase *epic.Phase, timestamp time.Time) error {
+	operation := "phase_unfrozen"
+	if phase.IsFrozen() {
+		operation = "phase_frozen"
+	}
+
+	switch c.String("format") {
+	case "json":
+		output := map[string]interface{}{
+			"phase_id":  phase.ID,
+			"operation": operation,
+			"frozen":    phase.IsFrozen(),
+			"timestamp": timestamp.Format(time.RFC3339),
+		}
+		if phase.IsFrozen() {
+			output["reason"] = phase.FrozenReason
+		}
+		jsonData, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal result to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		fmt.Fprintf(c.Root().Writer, "<%s phase=\"%s\" timestamp=\"%s\"", operation, phase.ID, timestamp.Format(time.RFC3339))
+		if phase.IsFrozen() {
+			fmt.Fprintf(c.Root().Writer, ">\n    <reason>%s</reason>\n</%s>\n", phase.FrozenReason, operation)
+		} else {
+			fmt.Fprintf(c.Root().Writer, "/>\n")
+		}
+	default:
+		if phase.IsFrozen() {
+			fmt.Fprintf(c.Root().Writer, "Phase %s frozen: %s\n", phase.ID, phase.FrozenReason)
+		} else {
+			fmt.Fprintf(c.Root().Writer, "Phase %s unfrozen.\n", phase.ID)
+		}
+	}
+	return nil
+}
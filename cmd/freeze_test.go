@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestFreezePhaseCommand(t *testing.T) {
+	run := func(t *testing.T, command *cli.Command, epicFile string, args ...string) (string, error) {
+		var stdout, stderr bytes.Buffer
+		command.Root().Writer = &stdout
+		command.Root().ErrWriter = &stderr
+		err := command.Run(context.Background(), append([]string{command.Name, "--file", epicFile, "--time", "2025-08-16T15:30:00Z"}, args...))
+		return stdout.String(), err
+	}
+
+	epicFile := filepath.Join(t.TempDir(), "epic.xml")
+	fileStorage := storage.NewFileStorage()
+	require.NoError(t, fileStorage.SaveEpic(createEpicForReset(), epicFile))
+
+	t.Run("requires a reason", func(t *testing.T) {
+		_, err := run(t, FreezeCommand(), epicFile, "phase", "P1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "reason")
+	})
+
+	t.Run("freezes the phase", func(t *testing.T) {
+		output, err := run(t, FreezeCommand(), epicFile, "phase", "--reason", "Shipped in v1.2", "P1")
+		require.NoError(t, err)
+		assert.Equal(t, "Phase P1 frozen: Shipped in v1.2\n", output)
+
+		updated, err := fileStorage.LoadEpic(epicFile)
+		require.NoError(t, err)
+		require.True(t, updated.Phases[0].IsFrozen())
+		assert.Equal(t, time.Date(2025, 8, 16, 15, 30, 0, 0, time.UTC), *updated.Phases[0].FrozenAt)
+		assert.Equal(t, "Shipped in v1.2", updated.Phases[0].FrozenReason)
+	})
+
+	t.Run("mutating commands fail while frozen", func(t *testing.T) {
+		_, err := run(t, ResetCommand(), epicFile, "phase", "--confirm", "P1")
+		require.Error(t, err)
+		assert.True(t, epic.IsPhaseFrozen(err))
+		assert.Contains(t, err.Error(), "Run 'agentpm unfreeze phase P1'")
+
+		updated, err := fileStorage.LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, epic.StatusWIP, updated.Tasks[0].Status)
+	})
+
+	t.Run("unfreezes the phase", func(t *testing.T) {
+		output, err := run(t, UnfreezeCommand(), epicFile, "--format", "json", "phase", "P1")
+		require.NoError(t, err)
+		assert.Contains(t, output, `"operation": "phase_unfrozen"`)
+		assert.Contains(t, output, `"frozen": false`)
+
+		updated, err := fileStorage.LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.False(t, updated.Phases[0].IsFrozen())
+
+		_, err = run(t, UnfreezeCommand(), epicFile, "phase", "P1")
+		assert.EqualError(t, err, "phase P1 is not frozen")
+	})
+}
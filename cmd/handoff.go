@@ -2,10 +2,15 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/output"
 	"github.com/mindreframer/agentpm/internal/reports"
 	"github.com/mindreframer/agentpm/internal/storage"
 	"github.com/urfave/cli/v3"
@@ -26,7 +31,7 @@ func HandoffCommand() *cli.Command {
 			&cli.StringFlag{
 				Name:    "format",
 				Aliases: []string{"F"},
-				Usage:   "Output format: text (default), json, xml",
+				Usage:   "Output format: xml (default), text, json, markdown",
 				Value:   "xml",
 			},
 			&cli.IntFlag{
@@ -35,6 +40,10 @@ func HandoffCommand() *cli.Command {
 				Usage:   "Number of recent events to include",
 				Value:   5,
 			},
+			&cli.BoolFlag{
+				Name:  "issue-token",
+				Usage: "Issue a signed handoff token for 'agentpm resume --token' instead of the report",
+			},
 		},
 	}
 }
@@ -54,10 +63,14 @@ func handoffAction(ctx context.Context, c *cli.Command) error {
 	// Determine epic file (command flag overrides config)
 	epicFile := c.String("file")
 	if epicFile == "" {
-		epicFile = cfg.CurrentEpic
+		epicFile = cfg.EpicFilePath()
 	}
 	if epicFile == "" {
-		return fmt.Errorf("no epic file specified. Use --file flag or run 'agentpm init' first")
+		return exitcode.Errorf(exitcode.Validation, "no epic file specified. Use --file flag or run 'agentpm init' first")
+	}
+
+	if c.Bool("issue-token") {
+		return issueHandoffToken(c, cfg, epicFile)
 	}
 
 	// Create storage and reports service
@@ -84,6 +97,8 @@ func handoffAction(ctx context.Context, c *cli.Command) error {
 		return outputHandoffJSON(c, report)
 	case "text":
 		return outputHandoffText(c, report)
+	case "markdown":
+		return outputHandoffMarkdown(c, report)
 	default:
 		return outputHandoffXML(c, report)
 	}
@@ -99,6 +114,15 @@ func outputHandoffText(c *cli.Command, report *reports.HandoffReport) error {
 	fmt.Fprintf(c.Root().Writer, "Assignee: %s\n", report.EpicInfo.Assignee)
 	fmt.Fprintf(c.Root().Writer, "Started: %s\n\n", report.EpicInfo.Started.Format("2006-01-02 15:04:05"))
 
+	// Open blockers and questions come first: they are what the next agent must know
+	if len(report.OpenBlockers) > 0 {
+		fmt.Fprintf(c.Root().Writer, "OPEN BLOCKERS (%d):\n", len(report.OpenBlockers))
+		for _, blocker := range report.OpenBlockers {
+			fmt.Fprintf(c.Root().Writer, "  ! %s\n", formatOpenBlocker(blocker))
+		}
+		fmt.Fprintf(c.Root().Writer, "\n")
+	}
+
 	// Current State
 	fmt.Fprintf(c.Root().Writer, "CURRENT STATE:\n")
 	if report.CurrentState.ActivePhase != "" {
@@ -115,6 +139,31 @@ func outputHandoffText(c *cli.Command, report *reports.HandoffReport) error {
 	fmt.Fprintf(c.Root().Writer, "  Phases: %d/%d completed\n", report.Summary.CompletedPhases, report.Summary.TotalPhases)
 	fmt.Fprintf(c.Root().Writer, "  Tests: %d passing, %d failing\n\n", report.Summary.PassingTests, report.Summary.FailingTests)
 
+	if len(report.FailingTests) > 0 {
+		fmt.Fprintf(c.Root().Writer, "FAILING TESTS (%d):\n", len(report.FailingTests))
+		for _, test := range report.FailingTests {
+			fmt.Fprintf(c.Root().Writer, "  ✗ %s\n", formatFailingTest(test))
+			writeFailureContextText(c.Root().Writer, "    ", test.Context)
+		}
+		fmt.Fprintf(c.Root().Writer, "\n")
+	}
+
+	if len(report.FlakyTests) > 0 {
+		fmt.Fprintf(c.Root().Writer, "FLAKY TESTS (%d):\n", len(report.FlakyTests))
+		for _, test := range report.FlakyTests {
+			fmt.Fprintf(c.Root().Writer, "  ~ %s\n", formatFlakyTest(test))
+		}
+		fmt.Fprintf(c.Root().Writer, "\n")
+	}
+
+	if len(report.Notes) > 0 {
+		fmt.Fprintf(c.Root().Writer, "NOTES (%d):\n", len(report.Notes))
+		for _, note := range report.Notes {
+			fmt.Fprintf(c.Root().Writer, "  %s\n", formatHandoffNote(note))
+		}
+		fmt.Fprintf(c.Root().Writer, "\n")
+	}
+
 	// Blockers
 	if len(report.Blockers) > 0 {
 		fmt.Fprintf(c.Root().Writer, "BLOCKERS:\n")
@@ -139,7 +188,149 @@ func outputHandoffText(c *cli.Command, report *reports.HandoffReport) error {
 	return nil
 }
 
+// outputHandoffMarkdown renders the handoff report for pull requests and issue trackers
+func outputHandoffMarkdown(c *cli.Command, report *reports.HandoffReport) error {
+	md := output.NewMarkdown()
+	md.Heading(2, fmt.Sprintf("Handoff: %s", report.EpicInfo.Name))
+	md.Field("ID", report.EpicInfo.ID)
+	md.Field("Status", report.EpicInfo.Status)
+	md.Field("Assignee", report.EpicInfo.Assignee)
+	md.Field("Started", report.EpicInfo.Started.Format("2006-01-02 15:04:05"))
+
+	if len(report.OpenBlockers) > 0 {
+		md.Heading(3, fmt.Sprintf("Open Blockers (%d)", len(report.OpenBlockers)))
+		for _, blocker := range report.OpenBlockers {
+			md.Item("%s", output.Inline(formatOpenBlocker(blocker)))
+		}
+	}
+
+	md.Heading(3, "Current State")
+	if report.CurrentState.ActivePhase != "" {
+		md.Field("Active Phase", report.CurrentState.ActivePhase)
+	}
+	if report.CurrentState.ActiveTask != "" {
+		md.Field("Active Task", report.CurrentState.ActiveTask)
+	}
+	md.Field("Next Action", report.CurrentState.NextAction)
+
+	md.Heading(3, "Progress")
+	md.Field("Completion", fmt.Sprintf("%d%%", report.Summary.CompletionPercentage))
+	md.Field("Phases", fmt.Sprintf("%d/%d completed", report.Summary.CompletedPhases, report.Summary.TotalPhases))
+	md.Field("Tests", fmt.Sprintf("%d passing, %d failing", report.Summary.PassingTests, report.Summary.FailingTests))
+
+	if len(report.FailingTests) > 0 {
+		md.Heading(3, fmt.Sprintf("Failing Tests (%d)", len(report.FailingTests)))
+		for _, test := range report.FailingTests {
+			md.Item("%s", output.Inline(formatFailingTest(test)))
+			writeFailureContextMarkdown(md, test.Context)
+		}
+	}
+
+	if len(report.FlakyTests) > 0 {
+		md.Heading(3, fmt.Sprintf("Flaky Tests (%d)", len(report.FlakyTests)))
+		for _, test := range report.FlakyTests {
+			md.Item("%s", output.Inline(formatFlakyTest(test)))
+		}
+	}
+
+	if len(report.Notes) > 0 {
+		md.Heading(3, fmt.Sprintf("Notes (%d)", len(report.Notes)))
+		for _, note := range report.Notes {
+			md.Item("%s", output.Inline(formatHandoffNote(note)))
+		}
+	}
+
+	if len(report.Blockers) > 0 {
+		md.Heading(3, "Blockers")
+		for _, blocker := range report.Blockers {
+			md.Item("%s", output.Inline(blocker))
+		}
+	}
+
+	if len(report.RecentEvents) > 0 {
+		md.Heading(3, "Recent Events")
+		var rows [][]string
+		for _, event := range report.RecentEvents {
+			rows = append(rows, []string{event.Timestamp.Format("2006-01-02 15:04:05"), event.Type, event.Data})
+		}
+		md.Table([]string{"Time", "Event", "Details"}, rows)
+	}
+
+	md.Paragraph("*Generated at %s*", report.GeneratedAt.Format("2006-01-02 15:04:05"))
+	_, err := md.WriteTo(c.Root().Writer)
+	return err
+}
+
+// formatOpenBlocker describes an open blocker on one line, e.g. "B2 question
+// on 2A_1, raised 2025-08-16 09:00 by agent_a: Which API version?"
+func formatOpenBlocker(blocker reports.OpenBlocker) string {
+	line := fmt.Sprintf("%s %s", blocker.ID, blocker.Type)
+	if blocker.Entity != "" {
+		line += fmt.Sprintf(" on %s", blocker.Entity)
+	}
+	line += fmt.Sprintf(", raised %s", blocker.RaisedAt.Format("2006-01-02 15:04"))
+	if blocker.RaisedBy != "" {
+		line += fmt.Sprintf(" by %s", blocker.RaisedBy)
+	}
+	return line + ": " + blocker.Description
+}
+
+// formatFailingTest describes a failing test on one line, e.g. "T3 on 2A_1:
+// Upload retries (timeout after 30s)"
+func formatFailingTest(test reports.FailingTest) string {
+	line := fmt.Sprintf("%s on %s: %s", test.ID, test.TaskID, test.Name)
+	if test.FailureNote != "" {
+		line += fmt.Sprintf(" (%s)", test.FailureNote)
+	}
+	return line
+}
+
+// formatFlakyTest describes a flaky test on one line, e.g. "T3 on 2A_1
+// failed 3 of 5 runs: Upload retries"
+func formatFlakyTest(test reports.FlakyTest) string {
+	return fmt.Sprintf("%s on %s failed %d of %d runs: %s", test.ID, test.TaskID, test.FailCount, test.FailCount+test.PassCount, test.Name)
+}
+
+// formatHandoffNote describes a note on one line, e.g. "[2025-08-16 09:00
+// agent_a] task 2A_1: Uses the v2 endpoint"
+func formatHandoffNote(note reports.HandoffNote) string {
+	header := note.CreatedAt.Format("2006-01-02 15:04")
+	if note.Author != "" {
+		header += " " + note.Author
+	}
+	return fmt.Sprintf("[%s] %s %s: %s", header, note.EntityType, note.EntityID, note.Text)
+}
+
 func outputHandoffJSON(c *cli.Command, report *reports.HandoffReport) error {
+	openBlockers, err := json.MarshalIndent(report.OpenBlockers, "  ", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal open blockers to JSON: %w", err)
+	}
+	failingTests := ""
+	if len(report.FailingTests) > 0 {
+		data, err := json.MarshalIndent(report.FailingTests, "  ", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal failing tests to JSON: %w", err)
+		}
+		failingTests = fmt.Sprintf("\n  \"failing_tests\": %s,", data)
+	}
+	flakyTests := ""
+	if len(report.FlakyTests) > 0 {
+		data, err := json.MarshalIndent(report.FlakyTests, "  ", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal flaky tests to JSON: %w", err)
+		}
+		flakyTests = fmt.Sprintf("\n  \"flaky_tests\": %s,", data)
+	}
+	notes := ""
+	if len(report.Notes) > 0 {
+		data, err := json.MarshalIndent(report.Notes, "  ", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal notes to JSON: %w", err)
+		}
+		notes = fmt.Sprintf("\n  \"notes\": %s,", data)
+	}
+
 	// For simplicity, we'll create a JSON-like output manually
 	// In a real implementation, you'd use json.Marshal
 	jsonOutput := fmt.Sprintf(`{
@@ -164,6 +355,7 @@ func outputHandoffJSON(c *cli.Command, report *reports.HandoffReport) error {
     "failing_tests": %d,
     "completion_percentage": %d
   },
+  "open_blockers": %s,%s%s%s
   "recent_events": [`,
 		report.EpicInfo.ID,
 		report.GeneratedAt.Format(time.RFC3339),
@@ -180,6 +372,10 @@ func outputHandoffJSON(c *cli.Command, report *reports.HandoffReport) error {
 		report.Summary.PassingTests,
 		report.Summary.FailingTests,
 		report.Summary.CompletionPercentage,
+		openBlockers,
+		failingTests,
+		flakyTests,
+		notes,
 	)
 
 	// Add events
@@ -230,6 +426,22 @@ func outputHandoffXML(c *cli.Command, report *reports.HandoffReport) error {
 	fmt.Fprintf(c.Root().Writer, "        <assignee>%s</assignee>\n", report.EpicInfo.Assignee)
 	fmt.Fprintf(c.Root().Writer, "    </epic_info>\n")
 
+	if len(report.OpenBlockers) > 0 {
+		fmt.Fprintf(c.Root().Writer, "    <open_blockers count=\"%d\">\n", len(report.OpenBlockers))
+		for _, blocker := range report.OpenBlockers {
+			fmt.Fprintf(c.Root().Writer, "        <blocker id=\"%s\" type=\"%s\"", blocker.ID, blocker.Type)
+			if blocker.Entity != "" {
+				fmt.Fprintf(c.Root().Writer, " entity=\"%s\"", blocker.Entity)
+			}
+			fmt.Fprintf(c.Root().Writer, " raised_at=\"%s\"", blocker.RaisedAt.Format(time.RFC3339))
+			if blocker.RaisedBy != "" {
+				fmt.Fprintf(c.Root().Writer, " raised_by=\"%s\"", blocker.RaisedBy)
+			}
+			fmt.Fprintf(c.Root().Writer, ">%s</blocker>\n", xmlText(blocker.Description))
+		}
+		fmt.Fprintf(c.Root().Writer, "    </open_blockers>\n")
+	}
+
 	fmt.Fprintf(c.Root().Writer, "    <current_state>\n")
 	fmt.Fprintf(c.Root().Writer, "        <active_phase>%s</active_phase>\n", report.CurrentState.ActivePhase)
 	fmt.Fprintf(c.Root().Writer, "        <active_task>%s</active_task>\n", report.CurrentState.ActiveTask)
@@ -244,6 +456,41 @@ func outputHandoffXML(c *cli.Command, report *reports.HandoffReport) error {
 	fmt.Fprintf(c.Root().Writer, "        <completion_percentage>%d</completion_percentage>\n", report.Summary.CompletionPercentage)
 	fmt.Fprintf(c.Root().Writer, "    </summary>\n")
 
+	if len(report.FailingTests) > 0 {
+		fmt.Fprintf(c.Root().Writer, "    <failing_tests count=\"%d\">\n", len(report.FailingTests))
+		for _, test := range report.FailingTests {
+			fmt.Fprintf(c.Root().Writer, "        <test id=\"%s\" task_id=\"%s\">\n", test.ID, test.TaskID)
+			fmt.Fprintf(c.Root().Writer, "            <name>%s</name>\n", xmlText(test.Name))
+			if test.FailureNote != "" {
+				fmt.Fprintf(c.Root().Writer, "            <failure_note>%s</failure_note>\n", xmlText(test.FailureNote))
+			}
+			writeFailureContextXML(c.Root().Writer, "            ", test.Context)
+			fmt.Fprintf(c.Root().Writer, "        </test>\n")
+		}
+		fmt.Fprintf(c.Root().Writer, "    </failing_tests>\n")
+	}
+
+	if len(report.FlakyTests) > 0 {
+		fmt.Fprintf(c.Root().Writer, "    <flaky_tests count=\"%d\">\n", len(report.FlakyTests))
+		for _, test := range report.FlakyTests {
+			fmt.Fprintf(c.Root().Writer, "        <test id=\"%s\" task_id=\"%s\" fail_count=\"%d\" pass_count=\"%d\">%s</test>\n",
+				test.ID, test.TaskID, test.FailCount, test.PassCount, xmlText(test.Name))
+		}
+		fmt.Fprintf(c.Root().Writer, "    </flaky_tests>\n")
+	}
+
+	if len(report.Notes) > 0 {
+		fmt.Fprintf(c.Root().Writer, "    <notes count=\"%d\">\n", len(report.Notes))
+		for _, note := range report.Notes {
+			fmt.Fprintf(c.Root().Writer, "        <note entity_type=\"%s\" entity_id=\"%s\"", note.EntityType, note.EntityID)
+			if note.Author != "" {
+				fmt.Fprintf(c.Root().Writer, " author=\"%s\"", xmlText(note.Author))
+			}
+			fmt.Fprintf(c.Root().Writer, " created_at=\"%s\">%s</note>\n", note.CreatedAt.Format(time.RFC3339), xmlText(note.Text))
+		}
+		fmt.Fprintf(c.Root().Writer, "    </notes>\n")
+	}
+
 	if len(report.RecentEvents) > 0 {
 		fmt.Fprintf(c.Root().Writer, "    <recent_events limit=\"%d\">\n", len(report.RecentEvents))
 		for _, event := range report.RecentEvents {
@@ -266,3 +513,10 @@ func outputHandoffXML(c *cli.Command, report *reports.HandoffReport) error {
 	fmt.Fprintf(c.Root().Writer, "</handoff>\n")
 	return nil
 }
+
+// xmlText escapes free text for the hand-written XML output
+func xmlText(text string) string {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(text))
+	return escaped.String()
+}
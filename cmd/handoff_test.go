@@ -3,6 +3,7 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -400,3 +401,80 @@ func TestHandoffCommand(t *testing.T) {
 		assert.Contains(t, err.Error(), "failed to load epic")
 	})
 }
+
+func TestHandoffOpenBlockers(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tempDir)
+
+	resolved := time.Date(2025, 8, 16, 12, 0, 0, 0, time.UTC)
+	testEpic := createTestEpicForHandoff()
+	testEpic.Blockers = []epic.Blocker{
+		{ID: "B1", RaisedAt: time.Date(2025, 8, 16, 10, 0, 0, 0, time.UTC), ResolvedAt: &resolved, Description: "Resolved already"},
+		{ID: "B2", Entity: "T3", RaisedAt: time.Date(2025, 8, 16, 11, 0, 0, 0, time.UTC), RaisedBy: "agent_a", Description: "Waiting for API credentials"},
+		{ID: "B3", Type: epic.BlockerTypeQuestion, RaisedAt: time.Date(2025, 8, 16, 11, 30, 0, 0, time.UTC), Description: "Which <api> version?"},
+	}
+	epicPath := filepath.Join(tempDir, "test-epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicPath))
+	require.NoError(t, config.SaveConfig(&config.Config{CurrentEpic: epicPath}, filepath.Join(tempDir, ".agentpm.json")))
+
+	run := func(t *testing.T, format string) string {
+		var stdout bytes.Buffer
+		cmd := HandoffCommand()
+		cmd.Root().Writer = &stdout
+		require.NoError(t, cmd.Run(context.Background(), []string{"handoff", "--format", format}))
+		return stdout.String()
+	}
+
+	t.Run("text lists open blockers before the current state", func(t *testing.T) {
+		output := run(t, "text")
+		assert.Contains(t, output, "OPEN BLOCKERS (2):\n"+
+			"  ! B2 blocker on T3, raised 2025-08-16 11:00 by agent_a: Waiting for API credentials\n"+
+			"  ! B3 question, raised 2025-08-16 11:30: Which <api> version?\n")
+		assert.NotContains(t, output, "Resolved already")
+		assert.Less(t, strings.Index(output, "OPEN BLOCKERS"), strings.Index(output, "CURRENT STATE"))
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		output := run(t, "xml")
+		assert.Contains(t, output, `<open_blockers count="2">`)
+		assert.Contains(t, output, `<blocker id="B2" type="blocker" entity="T3" raised_at="2025-08-16T11:00:00Z" raised_by="agent_a">Waiting for API credentials</blocker>`)
+		assert.Contains(t, output, `<blocker id="B3" type="question" raised_at="2025-08-16T11:30:00Z">Which &lt;api&gt; version?</blocker>`)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var report struct {
+			OpenBlockers []struct {
+				ID   string `json:"id"`
+				Type string `json:"type"`
+			} `json:"open_blockers"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(run(t, "json")), &report))
+		require.Len(t, report.OpenBlockers, 2)
+		assert.Equal(t, "B2", report.OpenBlockers[0].ID)
+		assert.Equal(t, "question", report.OpenBlockers[1].Type)
+	})
+}
+
+func TestHandoffMarkdown(t *testing.T) {
+	testEpic := createTestEpicForHandoff()
+	testEpic.Blockers = []epic.Blocker{
+		{ID: "B1", Entity: "T3", RaisedAt: time.Date(2025, 8, 16, 11, 0, 0, 0, time.UTC), Description: "Waiting for A | B"},
+	}
+	epicPath := filepath.Join(t.TempDir(), "test-epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicPath))
+
+	var stdout bytes.Buffer
+	cmd := HandoffCommand()
+	cmd.Root().Writer = &stdout
+	require.NoError(t, cmd.Run(context.Background(), []string{"handoff", "--file", epicPath, "--format", "markdown"}))
+
+	output := stdout.String()
+	assert.True(t, strings.HasPrefix(output, "## Handoff: Handoff Test Epic\n\n- **ID:** handoff-test-epic\n"))
+	assert.Contains(t, output, "### Open Blockers (1)\n\n- B1 blocker on T3, raised 2025-08-16 11:00: Waiting for A | B\n")
+	assert.Contains(t, output, "- **Active Task:** T2\n")
+	assert.Contains(t, output, "| Time | Event | Details |\n|------|-------|---------|\n")
+	assert.Contains(t, output, "| 2025-08-16 11:00:00 | blocker | Found dependency issue |\n")
+	assert.Less(t, strings.Index(output, "### Open Blockers"), strings.Index(output, "### Current State"))
+}
@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/hints"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// TrackHintRepeats is the root Before hook that makes hints count the errors
+// an agent hits per session, so that hints for repeated errors are escalated.
+// Tracking needs a config file; the counts live next to it.
+func TrackHintRepeats(ctx context.Context, c *cli.Command) (context.Context, error) {
+	hints.EnableRepeatTracking("", "")
+
+	configPath, err := config.ResolveConfigPath(c.String("config"))
+	if err != nil || !config.ConfigExists(configPath) {
+		return ctx, nil
+	}
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return ctx, nil
+	}
+	hints.EnableRepeatTracking(cfg.HintRepeatsPath(), strings.TrimSpace(c.String("actor")))
+	return ctx, nil
+}
+
+// ApplyHintConfig is the root Before hook that makes the hints of this
+// invocation follow the hint settings of the effective config
+func ApplyHintConfig(ctx context.Context, c *cli.Command) (context.Context, error) {
+	// A missing or broken config is reported by the command itself
+	cfg, err := config.LoadEffectiveConfig(c.String("config"), c.String("file"))
+	if err != nil {
+		hints.Configure(nil)
+		return ctx, nil
+	}
+	hints.Configure(hintRegistryConfig(cfg.Hints))
+	return ctx, nil
+}
+
+// hintRegistryConfig turns the hint settings of a config into a registry configuration
+func hintRegistryConfig(settings config.HintConfig) *hints.HintRegistryConfig {
+	registryConfig := hints.DefaultHintRegistryConfig()
+	registryConfig.Enabled = settings.Enabled
+	registryConfig.ShowCommands = settings.ShowCommands
+	registryConfig.ShowReferences = settings.ShowReferences
+	if settings.Priority != "" {
+		registryConfig.MinPriority = hints.HintPriority(settings.Priority)
+	}
+	registryConfig.MaxHints = settings.MaxHints
+	if settings.Customizations != nil {
+		registryConfig.Customizations = settings.Customizations
+	}
+	return registryConfig
+}
+
+// HintsCommand inspects hint generation
+func HintsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "hints",
+		Usage: "Inspect how hints are generated",
+		Description: `Debug why a hint did or did not appear.
+
+Subcommands:
+  explain           Show which generators fire for an error and the hint selected
+
+Examples:
+  agentpm hints explain --error PhaseConstraintError --entity phase-2
+  agentpm hints explain --error TaskStateError --entity 1A_1 --current-status wip --target-status wip`,
+		Commands: []*cli.Command{
+			{
+				Name:  "explain",
+				Usage: "Show which generators fire for an error and the hint selected",
+				Description: `Evaluate every hint generator for a synthetic error context and show, in
+evaluation order, whether it applies, its priority and the hint it produces.
+The registry selects the first generator whose hint meets the minimum
+priority; generators after it that would fire are shown as not reached.
+
+The epic of the project (or --file) provides the active phase and task,
+unless --active-phase or --active-task are given. Explaining a hint does not
+count as hitting the error.`,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "file",
+						Aliases: []string{"f"},
+						Usage:   "Override epic file from config",
+					},
+					&cli.StringFlag{
+						Name:     "error",
+						Usage:    "Error type, e.g. PhaseConstraintError, TaskStateError",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "entity",
+						Usage: "ID of the entity the operation targets",
+					},
+					&cli.StringFlag{
+						Name:  "entity-type",
+						Usage: "Entity type: epic, phase, task or test (default: from the error type)",
+					},
+					&cli.StringFlag{
+						Name:  "operation",
+						Usage: "Operation that failed: start, complete, cancel, current",
+						Value: "start",
+					},
+					&cli.StringFlag{
+						Name:  "current-status",
+						Usage: "Current status of the entity",
+					},
+					&cli.StringFlag{
+						Name:  "target-status",
+						Usage: "Status the operation tried to reach",
+					},
+					&cli.StringFlag{
+						Name:  "active-phase",
+						Usage: "Active phase (default: the epic's)",
+					},
+					&cli.StringFlag{
+						Name:  "active-task",
+						Usage: "Active task (default: the epic's)",
+					},
+					&cli.StringFlag{
+						Name:  "min-priority",
+						Usage: "Minimum hint priority to try: high, medium or low (default: the registry's)",
+					},
+				},
+				Action: hintsExplainAction,
+			},
+		},
+	}
+}
+
+func hintsExplainAction(ctx context.Context, c *cli.Command) error {
+	hintCtx := &hints.HintContext{
+		ErrorType:     c.String("error"),
+		OperationType: c.String("operation"),
+		EntityType:    c.String("entity-type"),
+		EntityID:      c.String("entity"),
+		CurrentStatus: c.String("current-status"),
+		TargetStatus:  c.String("target-status"),
+	}
+	if hintCtx.EntityType == "" {
+		hintCtx.EntityType = entityTypeOfError(hintCtx.ErrorType)
+	}
+	if epicData := loadExplainEpic(c); epicData != nil {
+		hintCtx.Epic = epicData
+		for i := range epicData.Phases {
+			if epicData.Phases[i].Status == epic.StatusWIP && hintCtx.ActivePhase == nil {
+				hintCtx.ActivePhase = &epicData.Phases[i]
+			}
+		}
+		hintCtx.ActiveTask = epicData.ActiveTask("")
+	}
+
+	// Explicit active work replaces the epic's
+	data := map[string]interface{}{}
+	if phaseID := c.String("active-phase"); phaseID != "" {
+		hintCtx.ActivePhase = &epic.Phase{ID: phaseID}
+		data["active_phase"] = phaseID
+	}
+	if taskID := c.String("active-task"); taskID != "" {
+		hintCtx.ActiveTask = &epic.Task{ID: taskID}
+		data["active_task_id"] = taskID
+		if hintCtx.ActivePhase != nil {
+			hintCtx.ActiveTask.PhaseID = hintCtx.ActivePhase.ID
+			data["phase_id"] = hintCtx.ActivePhase.ID
+		}
+	}
+	if len(data) > 0 {
+		hintCtx.AdditionalData = data
+	}
+
+	registryConfig := hints.RegistryConfig()
+	if minPriority := c.String("min-priority"); minPriority != "" {
+		switch hints.HintPriority(minPriority) {
+		case hints.HintPriorityHigh, hints.HintPriorityMedium, hints.HintPriorityLow:
+			registryConfig.MinPriority = hints.HintPriority(minPriority)
+		default:
+			return fmt.Errorf("invalid --min-priority: %s (expected high, medium or low)", minPriority)
+		}
+	}
+	registry := hints.NewHintRegistryWithConfig(registryConfig)
+	for _, generator := range hints.DefaultHintRegistry().Generators() {
+		registry.Register(generator)
+	}
+	explanation := registry.Explain(hintCtx)
+
+	w := c.Root().Writer
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(hintsExplainOutput{Explanation: explanation}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal hint explanation to JSON: %w", err)
+		}
+		fmt.Fprintf(w, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("hint_explanation")
+		root.CreateAttr("error", hintCtx.ErrorType)
+		root.CreateAttr("enabled", strconv.FormatBool(explanation.Enabled))
+		root.CreateAttr("min_priority", string(explanation.MinPriority))
+		generators := root.CreateElement("generators")
+		for _, trace := range explanation.Generators {
+			elem := generators.CreateElement("generator")
+			elem.CreateAttr("name", trace.Name)
+			elem.CreateAttr("priority", strconv.Itoa(trace.Priority))
+			elem.CreateAttr("outcome", trace.Outcome)
+			if trace.Hint != nil {
+				writeHintElement(elem, trace.Hint)
+			}
+		}
+		if explanation.Selected != nil {
+			selected := root.CreateElement("selected")
+			selected.CreateAttr("by", explanation.SelectedBy)
+			writeHintElement(selected, explanation.Selected)
+		}
+		doc.Indent(4)
+		doc.WriteTo(w)
+	default:
+		fmt.Fprintf(w, "Hint explanation for %s (%s %s", hintCtx.ErrorType, hintCtx.OperationType, hintCtx.EntityType)
+		if hintCtx.EntityID != "" {
+			fmt.Fprintf(w, " %s", hintCtx.EntityID)
+		}
+		fmt.Fprintf(w, ")\n")
+		if !explanation.Enabled {
+			fmt.Fprintf(w, "Hints are disabled.\n")
+			return nil
+		}
+
+		fmt.Fprintf(w, "\nGenerators in evaluation order (minimum priority %s):\n", explanation.MinPriority)
+		for i, trace := range explanation.Generators {
+			fmt.Fprintf(w, "  %d. %-30s priority %-3d  %s\n", i+1, trace.Name, trace.Priority, strings.ReplaceAll(trace.Outcome, "_", " "))
+			if trace.Hint != nil {
+				fmt.Fprintf(w, "       [%s] %s\n", trace.Hint.Priority, trace.Hint.Content)
+			}
+		}
+
+		if explanation.Selected == nil {
+			fmt.Fprintf(w, "\nNo hint is shown: no hint meets the minimum priority.\n")
+			return nil
+		}
+		fmt.Fprintf(w, "\nSelected hint (%s): %s\n", explanation.SelectedBy, explanation.Selected.Content)
+		fmt.Fprintf(w, "  priority: %s, category: %s\n", explanation.Selected.Priority, explanation.Selected.Category)
+		if explanation.Selected.Command != "" {
+			fmt.Fprintf(w, "  command: %s\n", explanation.Selected.Command)
+		}
+	}
+	return nil
+}
+
+// hintsExplainOutput is the JSON output of hints explain
+type hintsExplainOutput struct {
+	Explanation *hints.Explanation `json:"explanation"`
+}
+
+func writeHintElement(parent *etree.Element, hint *hints.Hint) {
+	elem := parent.CreateElement("hint")
+	elem.CreateAttr("priority", string(hint.Priority))
+	elem.CreateAttr("category", string(hint.Category))
+	if hint.Command != "" {
+		elem.CreateAttr("command", hint.Command)
+	}
+	elem.SetText(hint.Content)
+}
+
+// entityTypeOfError derives the entity type from an error type like "PhaseConstraintError"
+func entityTypeOfError(errorType string) string {
+	for _, entityType := range []string{"Epic", "Phase", "Task", "Test"} {
+		if strings.HasPrefix(errorType, entityType) {
+			return strings.ToLower(entityType)
+		}
+	}
+	return ""
+}
+
+// loadExplainEpic loads the epic of --file or the project, if there is one
+func loadExplainEpic(c *cli.Command) *epic.Epic {
+	epicFile := c.String("file")
+	if epicFile == "" {
+		configPath, err := config.ResolveConfigPath(c.String("config"))
+		if err != nil || !config.ConfigExists(configPath) {
+			return nil
+		}
+		cfg, err := config.LoadConfig(configPath)
+		if err != nil {
+			return nil
+		}
+		epicFile = cfg.EpicFilePath()
+	}
+	epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+	if err != nil {
+		return nil
+	}
+	return epicData
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/hints"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestHintRepeatEscalation(t *testing.T) {
+	t.Cleanup(func() { hints.EnableRepeatTracking("", "") })
+
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, ".agentpm.json")
+	require.NoError(t, os.WriteFile(configFile, []byte(`{"current_epic":"epic.xml"}`), 0644))
+	epicFile := filepath.Join(dir, "epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(&epic.Epic{
+		ID:     "test-epic",
+		Name:   "Test Epic",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{
+			{ID: "phase-1", Name: "First Phase", Status: epic.StatusWIP},
+			{ID: "phase-2", Name: "Second Phase", Status: epic.StatusPending},
+		},
+	}, epicFile))
+
+	startPhase := func() string {
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config", Value: configFile},
+				&cli.StringFlag{Name: "format", Value: "text"},
+				&cli.StringFlag{Name: "actor", Value: "agent-1"},
+			},
+			Before:   TrackHintRepeats,
+			Commands: []*cli.Command{StartCommand()},
+		}
+		app.Writer = &bytes.Buffer{}
+		app.ErrWriter = &bytes.Buffer{}
+		err := app.Run(context.Background(), []string{"agentpm", "start", "phase", "phase-2", "--file", epicFile})
+		require.Error(t, err)
+		return err.Error()
+	}
+
+	assert.Contains(t, startPhase(), "Hint: Complete phase 'phase-1' before starting 'phase-2'")
+	assert.Contains(t, startPhase(), "Hint: Complete phase 'phase-1' before starting 'phase-2'. Run: agentpm done-phase phase-1")
+	assert.Contains(t, startPhase(), "Hint: You've hit PhaseConstraintError 3 times this session — consider running 'agentpm done-phase phase-1'.")
+
+	store, err := hints.LoadRepeatStore(filepath.Join(dir, config.DefaultHintRepeatsFile))
+	require.NoError(t, err)
+	require.Contains(t, store.Sessions, "agent-1")
+	assert.Equal(t, 3, store.Sessions["agent-1"].Counts["PhaseConstraintError"])
+}
+
+func TestApplyHintConfig(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, ".agentpm.json")
+	require.NoError(t, os.WriteFile(configFile, []byte(`{"current_epic":"epic.xml"}`), 0644))
+	epicFile := filepath.Join(dir, "epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(&epic.Epic{
+		ID:     "test-epic",
+		Name:   "Test Epic",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{
+			{ID: "phase-1", Name: "First Phase", Status: epic.StatusWIP},
+			{ID: "phase-2", Name: "Second Phase", Status: epic.StatusPending},
+		},
+	}, epicFile))
+
+	startPhase := func(t *testing.T, sidecar string) string {
+		if sidecar != "" {
+			require.NoError(t, os.WriteFile(config.EpicOverridesPath(epicFile), []byte(sidecar), 0644))
+			defer os.Remove(config.EpicOverridesPath(epicFile))
+		}
+		app := appOptions{commands: []func() *cli.Command{StartCommand}, config: configFile, hooks: []cli.BeforeFunc{ApplyHintConfig}}
+		_, err := runApp(t, app, "start", "phase", "phase-2", "--file", epicFile)
+		require.Error(t, err)
+		return err.Error()
+	}
+
+	t.Run("project settings", func(t *testing.T) {
+		assert.Contains(t, startPhase(t, ""), "Hint: Complete phase 'phase-1' before starting 'phase-2'")
+	})
+
+	t.Run("epic override disables hints", func(t *testing.T) {
+		assert.NotContains(t, startPhase(t, `{"hints": {"enabled": false}}`), "Hint:")
+	})
+
+	t.Run("epic override customizes hints", func(t *testing.T) {
+		output := startPhase(t, `{"hints": {"customizations": {"PhaseConstraintError": "Ask the lead before switching phases"}}}`)
+		assert.Contains(t, output, "Hint: Ask the lead before switching phases")
+	})
+}
+
+func TestHintsExplainCommand(t *testing.T) {
+	dir := t.TempDir()
+	epicFile := filepath.Join(dir, "epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(&epic.Epic{
+		ID:     "test-epic",
+		Name:   "Test Epic",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{
+			{ID: "phase-1", Name: "First Phase", Status: epic.StatusWIP},
+			{ID: "phase-2", Name: "Second Phase", Status: epic.StatusPending},
+		},
+	}, epicFile))
+
+	run := func(args ...string) (string, error) {
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config", Value: filepath.Join(dir, ".agentpm.json")},
+				&cli.StringFlag{Name: "format", Value: "text"},
+			},
+			Commands: []*cli.Command{HintsCommand()},
+		}
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		app.ErrWriter = &bytes.Buffer{}
+		err := app.Run(context.Background(), append([]string{"agentpm", "hints", "explain"}, args...))
+		return stdout.String(), err
+	}
+
+	t.Run("active phase from the epic", func(t *testing.T) {
+		output, err := run("--error", "PhaseConstraintError", "--entity", "phase-2", "--file", epicFile)
+		require.NoError(t, err)
+		assert.Contains(t, output, "Hint explanation for PhaseConstraintError (start phase phase-2)")
+		assert.Contains(t, output, "PhaseConstraintHintGenerator   priority 100  selected")
+		assert.Contains(t, output, "WorkflowHintGenerator          priority 10   below minimum")
+		assert.Contains(t, output, "Selected hint (PhaseConstraintHintGenerator): Complete phase 'phase-1' before starting 'phase-2'")
+		assert.Contains(t, output, "command: agentpm done-phase phase-1")
+	})
+
+	t.Run("json with overrides", func(t *testing.T) {
+		output, err := run("--error", "PhaseConstraintError", "--entity", "phase-2", "--active-phase", "phase-0", "--format", "json")
+		require.NoError(t, err)
+
+		var result struct {
+			Explanation hints.Explanation `json:"explanation"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		require.NotNil(t, result.Explanation.Selected)
+		assert.Equal(t, "Complete phase 'phase-0' before starting 'phase-2'", result.Explanation.Selected.Content)
+	})
+
+	t.Run("minimum priority hides the hint", func(t *testing.T) {
+		output, err := run("--error", "UnknownError", "--min-priority", "high")
+		require.NoError(t, err)
+		assert.Contains(t, output, "No hint is shown: no hint meets the minimum priority.")
+	})
+
+	t.Run("invalid minimum priority", func(t *testing.T) {
+		_, err := run("--error", "UnknownError", "--min-priority", "urgent")
+		assert.ErrorContains(t, err, "invalid --min-priority: urgent")
+	})
+}
@@ -0,0 +1,348 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"unicode/utf8"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/mindreframer/agentpm/internal/testplan"
+	"github.com/urfave/cli/v3"
+)
+
+// ImportCommand imports entities maintained in other tools into the epic
+func ImportCommand() *cli.Command {
+	defaults := testplan.DefaultColumns()
+	return &cli.Command{
+		Name:      "import",
+		Usage:     "Import an epic, or epic data maintained in other tools",
+		ArgsUsage: "<epic.json|epic.yaml|epic.xml|->",
+		Description: `Read a whole epic, as written by 'agentpm export', and store it as the
+current epic file (or --file) in that file's storage format. The format of
+the imported file is told by its extension. Unknown keys are rejected rather
+than dropped.
+
+An existing epic file is only replaced by the same epic (same ID); --force
+replaces a different one. Subcommands import selected data.
+
+Examples:
+  agentpm import epic.json
+  agentpm import epic.yaml -f epics/epic-8.xml
+  curl -s https://example.com/epic.json | agentpm import -f - - | agentpm validate -
+  agentpm import tests plan.csv`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Replace an epic file holding a different epic",
+			},
+		},
+		Action: withQuietResult(importEpicAction),
+		Commands: []*cli.Command{
+			{
+				Name:      "tests",
+				Usage:     "Import tests from a test-plan CSV",
+				ArgsUsage: "<plan.csv|->",
+				Description: `Add the rows of a test-plan CSV (as exported from a spreadsheet) as
+pending tests of the epic's tasks.
+
+The first row names the columns. Every further row is one test: the task
+column holds the ID or the name of an existing task, the name column the
+test name. The ID, phase, description, tags and type columns are optional;
+without an ID the test is numbered T<task>_<n>, and a phase, given as ID or
+name, must be the phase of the task. A type of "manual" marks a test a
+person checks (see pass --verified-by).
+
+Rows with an unknown or ambiguous task, a phase that does not match, an
+unknown type, a frozen phase, a test ID already in use or a test name the
+task already has are not imported but reported as unmatched, with their
+line number; importing an updated plan again only adds its new rows.
+--dry-run reports without saving. "-" reads the CSV from standard input.
+
+Examples:
+  agentpm import tests plan.csv
+  agentpm import tests plan.csv --task-column "Story" --name-column "Test Case"
+  agentpm import tests plan.csv --delimiter ";" --dry-run`,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "file",
+						Aliases: []string{"f"},
+						Usage:   "Override epic file from config",
+					},
+					&cli.StringFlag{
+						Name:  "task-column",
+						Usage: "Column holding the task ID or name",
+						Value: defaults.Task,
+					},
+					&cli.StringFlag{
+						Name:  "name-column",
+						Usage: "Column holding the test name",
+						Value: defaults.Name,
+					},
+					&cli.StringFlag{
+						Name:  "id-column",
+						Usage: "Column holding the test ID (optional)",
+						Value: defaults.ID,
+					},
+					&cli.StringFlag{
+						Name:  "phase-column",
+						Usage: "Column holding the phase ID or name (optional)",
+						Value: defaults.Phase,
+					},
+					&cli.StringFlag{
+						Name:  "description-column",
+						Usage: "Column holding the test description (optional)",
+						Value: defaults.Description,
+					},
+					&cli.StringFlag{
+						Name:  "tags-column",
+						Usage: "Column holding comma-separated test tags, e.g. unit,manual (optional)",
+						Value: defaults.Tags,
+					},
+					&cli.StringFlag{
+						Name:  "type-column",
+						Usage: "Column holding the test type, \"manual\" for human-checked tests (optional)",
+						Value: defaults.Type,
+					},
+					&cli.StringFlag{
+						Name:  "delimiter",
+						Usage: "Field delimiter of the CSV file",
+						Value: ",",
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Report what would be imported without saving",
+					},
+				},
+				Action: withQuietResult(importTestsAction),
+			},
+		},
+	}
+}
+
+// importEpicOutput is the JSON output of import
+type importEpicOutput struct {
+	Epic     string `json:"epic"`
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+	Replaced bool   `json:"replaced"`
+	Phases   int    `json:"phases"`
+	Tasks    int    `json:"tasks"`
+	Tests    int    `json:"tests"`
+	Events   int    `json:"events"`
+}
+
+func importEpicAction(ctx context.Context, c *cli.Command) error {
+	if c.Args().Len() != 1 {
+		return exitcode.Errorf(exitcode.Validation, "exactly one epic file to import is required")
+	}
+	source := c.Args().First()
+
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+
+	data, format, err := readEpicInput(c, source)
+	if err != nil {
+		return err
+	}
+	epicData, err := storage.DecodeEpic(data, format)
+	if err != nil {
+		return fmt.Errorf("%s: %w", source, err)
+	}
+	if epicFile == storage.StdioPath {
+		return storage.WriteEpic(c.Root().Writer, epicData, streamFormat(c))
+	}
+
+	fs := storage.NewFileStorage()
+	replaced := fs.EpicExists(epicFile)
+	if replaced && !c.Bool("force") {
+		existing, err := fs.LoadEpic(epicFile)
+		if err != nil {
+			return fmt.Errorf("cannot import over %s: %v (use --force to replace it)", epicFile, err)
+		}
+		if existing.ID != epicData.ID {
+			return fmt.Errorf("cannot import epic %s over epic %s in %s (use --force to replace it)", epicData.ID, existing.ID, epicFile)
+		}
+	}
+	if err := fs.SaveEpic(epicData, epicFile); err != nil {
+		return fmt.Errorf("failed to save epic: %w", err)
+	}
+
+	output := importEpicOutput{
+		Epic:     epicData.ID,
+		Source:   source,
+		Target:   epicFile,
+		Replaced: replaced,
+		Phases:   len(epicData.Phases),
+		Tasks:    len(epicData.Tasks),
+		Tests:    len(epicData.Tests),
+		Events:   len(epicData.Events),
+	}
+	w := c.Root().Writer
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal import result to JSON: %w", err)
+		}
+		fmt.Fprintf(w, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("epic_imported")
+		root.CreateAttr("epic", output.Epic)
+		root.CreateAttr("source", output.Source)
+		root.CreateAttr("target", output.Target)
+		root.CreateAttr("replaced", fmt.Sprintf("%t", output.Replaced))
+		root.CreateAttr("phases", fmt.Sprintf("%d", output.Phases))
+		root.CreateAttr("tasks", fmt.Sprintf("%d", output.Tasks))
+		root.CreateAttr("tests", fmt.Sprintf("%d", output.Tests))
+		root.CreateAttr("events", fmt.Sprintf("%d", output.Events))
+		doc.Indent(2)
+		doc.WriteTo(w)
+	default:
+		fmt.Fprintf(w, "Imported epic %s from %s into %s (%d phases, %d tasks, %d tests, %d events)\n",
+			output.Epic, source, epicFile, output.Phases, output.Tasks, output.Tests, output.Events)
+	}
+	return nil
+}
+
+// importTestsOutput is the JSON output of import tests
+type importTestsOutput struct {
+	Epic      string               `json:"epic"`
+	Plan      string               `json:"plan"`
+	DryRun    bool                 `json:"dry_run"`
+	Imported  []importedTest       `json:"imported"`
+	Unmatched []testplan.Unmatched `json:"unmatched"`
+}
+
+type importedTest struct {
+	ID      string `json:"id"`
+	TaskID  string `json:"task_id"`
+	PhaseID string `json:"phase_id"`
+	Name    string `json:"name"`
+}
+
+func importTestsAction(ctx context.Context, c *cli.Command) error {
+	if c.Args().Len() != 1 {
+		return fmt.Errorf("exactly one test-plan CSV file is required")
+	}
+	planPath := c.Args().First()
+	delimiter, size := utf8.DecodeRuneInString(c.String("delimiter"))
+	if size == 0 || size != len(c.String("delimiter")) {
+		return fmt.Errorf("--delimiter must be a single character")
+	}
+
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+
+	plan := stdin(c)
+	if planPath != "-" {
+		file, err := os.Open(planPath)
+		if err != nil {
+			return fmt.Errorf("failed to open test plan: %w", err)
+		}
+		defer file.Close()
+		plan = file
+	}
+	rows, err := testplan.Read(plan, delimiter, testplan.Columns{
+		ID:          c.String("id-column"),
+		Task:        c.String("task-column"),
+		Phase:       c.String("phase-column"),
+		Name:        c.String("name-column"),
+		Description: c.String("description-column"),
+		Tags:        c.String("tags-column"),
+		Type:        c.String("type-column"),
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", planPath, err)
+	}
+
+	fs := storage.NewFileStorage()
+	epicData, err := fs.LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+	result := testplan.Apply(epicData, rows)
+	if !c.Bool("dry-run") && len(result.Imported) > 0 {
+		if err := fs.SaveEpic(epicData, epicFile); err != nil {
+			return fmt.Errorf("failed to save epic: %w", err)
+		}
+	}
+
+	w := c.Root().Writer
+	switch c.String("format") {
+	case "json":
+		output := importTestsOutput{
+			Epic:      epicData.ID,
+			Plan:      planPath,
+			DryRun:    c.Bool("dry-run"),
+			Imported:  []importedTest{},
+			Unmatched: []testplan.Unmatched{},
+		}
+		for _, test := range result.Imported {
+			output.Imported = append(output.Imported, importedTest{ID: test.ID, TaskID: test.TaskID, PhaseID: test.PhaseID, Name: test.Name})
+		}
+		output.Unmatched = append(output.Unmatched, result.Unmatched...)
+		jsonData, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal import result to JSON: %w", err)
+		}
+		fmt.Fprintf(w, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("import_result")
+		root.CreateAttr("epic", epicData.ID)
+		root.CreateAttr("plan", planPath)
+		root.CreateAttr("dry_run", fmt.Sprintf("%t", c.Bool("dry-run")))
+		imported := root.CreateElement("imported")
+		for _, test := range result.Imported {
+			elem := imported.CreateElement("test")
+			elem.CreateAttr("id", test.ID)
+			elem.CreateAttr("task_id", test.TaskID)
+			elem.CreateAttr("phase_id", test.PhaseID)
+			elem.SetText(test.Name)
+		}
+		unmatched := root.CreateElement("unmatched")
+		for _, row := range result.Unmatched {
+			elem := unmatched.CreateElement("row")
+			elem.CreateAttr("line", fmt.Sprintf("%d", row.Line))
+			if row.Task != "" {
+				elem.CreateAttr("task", row.Task)
+			}
+			if row.Name != "" {
+				elem.CreateAttr("name", row.Name)
+			}
+			elem.SetText(row.Reason)
+		}
+		doc.Indent(4)
+		doc.WriteTo(w)
+	default:
+		verb := "Imported"
+		if c.Bool("dry-run") {
+			verb = "Would import"
+		}
+		fmt.Fprintf(w, "%s %d of %d tests from %s into epic %s\n", verb, len(result.Imported), len(rows), planPath, epicData.ID)
+		for _, test := range result.Imported {
+			fmt.Fprintf(w, "  %s  task %s  %s\n", test.ID, test.TaskID, test.Name)
+		}
+		if len(result.Unmatched) > 0 {
+			fmt.Fprintf(w, "\nUnmatched rows (%d):\n", len(result.Unmatched))
+			for _, row := range result.Unmatched {
+				fmt.Fprintf(w, "  line %d: %s\n", row.Line, row.Reason)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestImportTestsCommand(t *testing.T) {
+	tempDir := t.TempDir()
+	epicFile := filepath.Join(tempDir, "epic.xml")
+	planFile := filepath.Join(tempDir, "plan.csv")
+
+	testEpic := epic.NewEpic("8", "Checkout")
+	testEpic.Phases = []epic.Phase{{ID: "1", Name: "Cart", Status: epic.StatusPending}}
+	testEpic.Tasks = []epic.Task{{ID: "1_1", PhaseID: "1", Name: "Add to cart", Status: epic.StatusPending}}
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicFile))
+	require.NoError(t, os.WriteFile(planFile, []byte("Story,Test Case\n1_1,Adds an item\nAdd to cart,Updates the total\n9_9,Unknown task\n"), 0644))
+
+	run := func(args ...string) (string, error) {
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config"},
+				&cli.StringFlag{Name: "format", Value: "text"},
+			},
+			Commands: []*cli.Command{ImportCommand()},
+		}
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		app.ErrWriter = &bytes.Buffer{}
+		err := app.Run(context.Background(), append([]string{"agentpm", "import", "tests", "--file", epicFile}, args...))
+		return stdout.String(), err
+	}
+
+	t.Run("dry run does not save", func(t *testing.T) {
+		output, err := run(planFile, "--task-column", "Story", "--name-column", "Test Case", "--dry-run")
+		require.NoError(t, err)
+		assert.Equal(t, "Would import 2 of 3 tests from "+planFile+" into epic 8\n"+
+			"  T1_1_1  task 1_1  Adds an item\n"+
+			"  T1_1_2  task 1_1  Updates the total\n"+
+			"\nUnmatched rows (1):\n"+
+			"  line 4: task 9_9 not found\n", output)
+
+		loaded, err := storage.NewFileStorage().LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Empty(t, loaded.Tests)
+	})
+
+	t.Run("imports matched rows", func(t *testing.T) {
+		output, err := run(planFile, "--task-column", "Story", "--name-column", "Test Case", "--format", "json")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"epic": "8",
+			"plan": "`+planFile+`",
+			"dry_run": false,
+			"imported": [
+				{"id": "T1_1_1", "task_id": "1_1", "phase_id": "1", "name": "Adds an item"},
+				{"id": "T1_1_2", "task_id": "1_1", "phase_id": "1", "name": "Updates the total"}
+			],
+			"unmatched": [{"line": 4, "task": "9_9", "name": "Unknown task", "reason": "task 9_9 not found"}]
+		}`, output)
+
+		loaded, err := storage.NewFileStorage().LoadEpic(epicFile)
+		require.NoError(t, err)
+		require.Len(t, loaded.Tests, 2)
+		assert.Equal(t, epic.TestStatusPending, loaded.Tests[1].TestStatus)
+	})
+
+	t.Run("missing columns are errors", func(t *testing.T) {
+		_, err := run(planFile)
+		assert.ErrorContains(t, err, `test plan has no "task" column (columns: Story, Test Case)`)
+
+		_, err = run(planFile, "--delimiter", ";;")
+		assert.EqualError(t, err, "--delimiter must be a single character")
+	})
+}
+
+func TestImportEpicCommand(t *testing.T) {
+	run := func(t *testing.T, args ...string) (string, error) {
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config"},
+				&cli.StringFlag{Name: "format", Value: "text"},
+			},
+			Commands: []*cli.Command{ImportCommand()},
+		}
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		app.ErrWriter = &bytes.Buffer{}
+		err := app.Run(context.Background(), append([]string{"agentpm", "import"}, args...))
+		return stdout.String(), err
+	}
+
+	tempDir := t.TempDir()
+	fileStorage := storage.NewFileStorage()
+	exported := epic.NewEpic("8", "Checkout")
+	exported.CreatedAt = time.Date(2025, 8, 1, 9, 0, 0, 0, time.UTC)
+	exported.Metadata.Created = exported.CreatedAt
+	exported.Phases = []epic.Phase{{ID: "1", Name: "Cart", Status: epic.StatusWIP}}
+	exported.Tasks = []epic.Task{{ID: "1_1", PhaseID: "1", Name: "Add to cart", Status: epic.StatusWIP}}
+	jsonFile := filepath.Join(tempDir, "export.json")
+	require.NoError(t, fileStorage.SaveEpic(exported, jsonFile))
+	expected, err := fileStorage.LoadEpic(jsonFile)
+	require.NoError(t, err)
+
+	t.Run("stores the epic in the format of the epic file", func(t *testing.T) {
+		epicFile := filepath.Join(tempDir, "epic.xml")
+		output, err := run(t, "--file", epicFile, jsonFile)
+		require.NoError(t, err)
+		assert.Equal(t, "Imported epic 8 from "+jsonFile+" into "+epicFile+" (1 phases, 1 tasks, 0 tests, 0 events)\n", output)
+
+		content, err := os.ReadFile(epicFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), `<epic id="8"`)
+		imported, err := fileStorage.LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, expected, imported)
+	})
+
+	t.Run("replaces only the same epic", func(t *testing.T) {
+		epicFile := filepath.Join(tempDir, "other.xml")
+		require.NoError(t, fileStorage.SaveEpic(epic.NewEpic("9", "Other"), epicFile))
+
+		_, err := run(t, "--file", epicFile, jsonFile)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot import epic 8 over epic 9")
+
+		_, err = run(t, "--file", epicFile, "--force", jsonFile)
+		require.NoError(t, err)
+		imported, err := fileStorage.LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, "8", imported.ID)
+	})
+
+	t.Run("rejects unknown keys", func(t *testing.T) {
+		yamlFile := filepath.Join(tempDir, "broken.yaml")
+		require.NoError(t, os.WriteFile(yamlFile, []byte("id: \"8\"\nname: Checkout\nowner: bob\n"), 0644))
+
+		_, err := run(t, "--file", filepath.Join(tempDir, "new.xml"), yamlFile)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid epic file")
+		assert.NoFileExists(t, filepath.Join(tempDir, "new.xml"))
+	})
+}
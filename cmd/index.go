@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/output"
+	"github.com/mindreframer/agentpm/internal/query"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// IndexCommand prints a compact lookup table of all entity IDs
+func IndexCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "index",
+		Usage: "List every ID with its name, status and parent, one line each",
+		Description: `Print a compact index of the epic: the epic, its phases, tasks and tests,
+one line each with type, ID, status, parent and name, in outline order.
+
+The index is meant as a cheap lookup document an agent keeps in context
+instead of calling 'agentpm show' for every ID it comes across. Tests show
+"failing" while their last run failed.
+
+Examples:
+  agentpm index                       # The whole epic
+  agentpm index --type task,test      # Only tasks and tests
+  agentpm index --format jsonl        # One JSON object per entity`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config",
+			},
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"F"},
+				Usage:   "Output format: text (default), json, jsonl, xml",
+				Value:   "text",
+			},
+			&cli.StringSliceFlag{
+				Name:  "type",
+				Usage: "Only entities of this type: epic, phase, task or test (repeatable)",
+			},
+		},
+		Action: indexAction,
+	}
+}
+
+func indexAction(ctx context.Context, c *cli.Command) error {
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+
+	queryService := query.NewQueryService(storage.NewFileStorage())
+	if err := queryService.LoadEpic(epicFile); err != nil {
+		return err
+	}
+	index, err := queryService.GetIndex()
+	if err != nil {
+		return err
+	}
+
+	if types := c.StringSlice("type"); len(types) > 0 {
+		for _, t := range types {
+			if !slices.Contains([]string{"epic", "phase", "task", "test"}, t) {
+				return fmt.Errorf("invalid entity type: %s (must be epic, phase, task or test)", t)
+			}
+		}
+		index = slices.DeleteFunc(index, func(entry query.IndexEntry) bool {
+			return !slices.Contains(types, entry.Type)
+		})
+	}
+
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(index, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal index to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "jsonl":
+		lines := output.NewJSONLWriter(c.Root().Writer)
+		for _, entry := range index {
+			if err := lines.Write(entry); err != nil {
+				return err
+			}
+		}
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("index")
+		for _, entry := range index {
+			elem := root.CreateElement(entry.Type)
+			elem.CreateAttr("id", entry.ID)
+			elem.CreateAttr("status", entry.Status)
+			if entry.Parent != "" {
+				elem.CreateAttr("parent", entry.Parent)
+			}
+			elem.SetText(entry.Name)
+		}
+		doc.Indent(4)
+		doc.WriteTo(c.Root().Writer)
+	default:
+		outputIndexText(c, index)
+	}
+	return nil
+}
+
+// outputIndexText prints the index as a table with aligned columns; a "-"
+// stands for a missing parent
+func outputIndexText(c *cli.Command, index []query.IndexEntry) {
+	rows := [][]string{{"TYPE", "ID", "STATUS", "PARENT"}}
+	for _, entry := range index {
+		parent := entry.Parent
+		if parent == "" {
+			parent = "-"
+		}
+		rows = append(rows, []string{entry.Type, entry.ID, entry.Status, parent})
+	}
+
+	widths := make([]int, 4)
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+
+	for i, row := range rows {
+		name := "NAME"
+		if i > 0 {
+			name = index[i-1].Name
+		}
+		var line strings.Builder
+		for j, cell := range row {
+			fmt.Fprintf(&line, "%-*s  ", widths[j], cell)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s%s\n", line.String(), name)
+	}
+}
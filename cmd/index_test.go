@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var indexApp = appOptions{commands: []func() *cli.Command{IndexCommand}}
+
+func TestIndexCommand(t *testing.T) {
+	epicFile := createBulkEpic(t)
+
+	t.Run("text table", func(t *testing.T) {
+		out, err := runApp(t, indexApp, "index", "--file", epicFile, "--type", "phase,task")
+		require.NoError(t, err)
+		assert.Equal(t, `TYPE   ID    STATUS     PARENT  NAME
+phase  P1    wip        bulk    Phase 1
+task   P1_1  wip        P1      Task 1
+task   P1_2  wip        P1      Task 2
+task   P1_3  completed  P1      Task 3
+task   P1_4  pending    P1      Task 4
+`, out)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out, err := runApp(t, indexApp, "index", "--file", epicFile, "--format", "json")
+		require.NoError(t, err)
+
+		var index []query.IndexEntry
+		require.NoError(t, json.Unmarshal([]byte(out), &index))
+		require.Len(t, index, 10)
+		assert.Equal(t, query.IndexEntry{Type: "epic", ID: "bulk", Name: "Bulk", Status: "wip"}, index[0])
+		assert.Equal(t, query.IndexEntry{Type: "test", ID: "T1", Name: "Test T1", Status: "wip", Parent: "P1_1"}, index[3])
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		out, err := runApp(t, indexApp, "index", "--file", epicFile, "--format", "xml", "--type", "epic")
+		require.NoError(t, err)
+		assert.Equal(t, "<index>\n    <epic id=\"bulk\" status=\"wip\">Bulk</epic>\n</index>\n", out)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		_, err := runApp(t, indexApp, "index", "--file", epicFile, "--type", "milestone")
+		assert.EqualError(t, err, "invalid entity type: milestone (must be epic, phase, task or test)")
+	})
+}
@@ -3,9 +3,14 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"time"
 
+	"github.com/mindreframer/agentpm/internal/apiversion"
 	"github.com/mindreframer/agentpm/internal/config"
 	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/mindreframer/agentpm/internal/templates"
 	"github.com/urfave/cli/v3"
 )
 
@@ -19,8 +24,12 @@ func InitCommand() *cli.Command {
 				Usage:    "Epic file to set as current",
 				Required: true,
 			},
+			&cli.StringFlag{
+				Name:  "template",
+				Usage: "Create the epic file from a template (see 'agentpm template list')",
+			},
 		},
-		Action: runInit,
+		Action: withQuietResult(runInit),
 	}
 }
 
@@ -29,37 +38,62 @@ func runInit(ctx context.Context, c *cli.Command) error {
 	configPath := c.String("config")
 	format := c.String("format")
 
+	// init always targets the config in the working directory, never a parent project's
+	if configPath == "" {
+		configPath = config.DefaultConfigFile
+	}
+	absConfigPath, err := filepath.Abs(configPath)
+	if err != nil {
+		return writeError(c, format, fmt.Sprintf("Failed to resolve config path: %v", err))
+	}
+
 	// Check if epic file exists
 	storage := storage.NewFileStorage()
+	if templateName := c.String("template"); templateName != "" {
+		if storage.EpicExists(epicFile) {
+			return writeError(c, format, fmt.Sprintf("Epic file already exists: %s (--template only creates new epic files)", epicFile))
+		}
+		if err := createEpicFromTemplate(c, absConfigPath, epicFile, templateName); err != nil {
+			return writeError(c, format, err.Error())
+		}
+	}
 	if !storage.EpicExists(epicFile) {
 		return writeError(c, format, fmt.Sprintf("Epic file not found: %s", epicFile))
 	}
 
 	// Try to load and validate the epic file
-	_, err := storage.LoadEpic(epicFile)
+	_, err = storage.LoadEpic(epicFile)
 	if err != nil {
 		return writeError(c, format, fmt.Sprintf("Failed to load epic file: %v", err))
 	}
 
+	// Store the epic path relative to the config file
+	storedEpic, err := config.NormalizeEpicPath(absConfigPath, epicFile)
+	if err != nil {
+		return writeError(c, format, fmt.Sprintf("Failed to resolve epic path: %v", err))
+	}
+
 	// Create or update configuration
 	cfg := &config.Config{
-		CurrentEpic:     epicFile,
+		CurrentEpic:     storedEpic,
 		DefaultAssignee: "agent",
+		APIVersion:      int(apiversion.Latest),
 	}
 
-	// If config already exists, preserve project name and assignee
-	if config.ConfigExists(configPath) {
-		existingCfg, err := config.LoadConfig(configPath)
+	// If config already exists, preserve project name, assignee and output version
+	if config.ConfigExists(absConfigPath) {
+		existingCfg, err := config.LoadConfig(absConfigPath)
 		if err == nil {
 			cfg.ProjectName = existingCfg.ProjectName
 			if existingCfg.DefaultAssignee != "" {
 				cfg.DefaultAssignee = existingCfg.DefaultAssignee
 			}
+			cfg.APIVersion = existingCfg.APIVersion
 		}
 	}
 
 	// Save configuration
-	err = config.SaveConfig(cfg, configPath)
+	err = config.SaveConfig(cfg, absConfigPath)
 	if err != nil {
 		return writeError(c, format, fmt.Sprintf("Failed to save configuration: %v", err))
 	}
@@ -68,6 +102,43 @@ func runInit(ctx context.Context, c *cli.Command) error {
 	return writeInitResult(c, format, cfg, configPath)
 }
 
+// createEpicFromTemplate writes a new epic file from a local or built-in template
+func createEpicFromTemplate(c *cli.Command, absConfigPath, epicFile, templateName string) error {
+	templatesDir := filepath.Join(filepath.Dir(absConfigPath), config.DefaultTemplatesDir)
+	if config.ConfigExists(absConfigPath) {
+		if existingCfg, err := config.LoadConfig(absConfigPath); err == nil {
+			templatesDir = existingCfg.TemplatesDirPath()
+		}
+	}
+
+	data, err := templates.Load(templatesDir, templateName)
+	if err != nil {
+		return err
+	}
+
+	createdAt := time.Now()
+	if timeStr := c.String("time"); timeStr != "" {
+		if createdAt, err = time.Parse(time.RFC3339, timeStr); err != nil {
+			return fmt.Errorf("invalid time format: %s (use ISO 8601 format like 2025-08-16T15:30:00Z)", timeStr)
+		}
+	}
+
+	content, err := templates.Instantiate(data, createdAt)
+	if err != nil {
+		return fmt.Errorf("invalid template %s: %w", templateName, err)
+	}
+
+	if dir := filepath.Dir(epicFile); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create epic directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(epicFile, content, 0644); err != nil {
+		return fmt.Errorf("failed to write epic file: %w", err)
+	}
+	return nil
+}
+
 func writeInitResult(c *cli.Command, format string, cfg *config.Config, configPath string) error {
 	switch format {
 	case "xml":
@@ -110,6 +181,7 @@ func writeError(c *cli.Command, format string, message string) error {
 		fmt.Fprint(c.Root().ErrWriter, output)
 	default: // text
 		fmt.Fprintf(c.Root().ErrWriter, "✗ Error: %s\n", message)
+		return fmt.Errorf("%s", message)
 	}
-	return fmt.Errorf("%s", message)
+	return reportedError(fmt.Errorf("%s", message))
 }
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/reports"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// EntityLink is a stable deep link to a phase, task or test
+type EntityLink struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Link string `json:"link"`
+}
+
+func LinkCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "link",
+		Usage:     "Print a stable deep link to a phase, task or test",
+		ArgsUsage: "<phase|task|test> <id>",
+		Description: `Produce a link that references one entity unambiguously, for pasting
+into chats and pull requests.
+
+With server_url configured the link points to the server, which
+'agentpm serve' answers:
+  <server_url>/tasks/<id>
+Otherwise it points to the entity's row in the generated documentation
+(see 'agentpm docs'), relative to the project root:
+  <docs_file>#task-<id>
+
+docs_file defaults to the epic file with a .md extension.
+
+Examples:
+  agentpm link task 1A_2                  # epic-8.md#task-1A_2
+  agentpm link test T1 --markdown         # [Test T1: ...](epic-8.md#test-T1)
+  agentpm link phase 2A --docs docs/plan.md`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config",
+			},
+			&cli.StringFlag{
+				Name:  "docs",
+				Usage: "Link into this documentation file instead of docs_file or server_url",
+			},
+			&cli.BoolFlag{
+				Name:  "markdown",
+				Usage: "Print a Markdown link labelled with the entity's ID and name",
+			},
+		},
+		Action: linkAction,
+	}
+}
+
+func linkAction(ctx context.Context, c *cli.Command) error {
+	if c.Args().Len() != 2 {
+		return exitcode.Errorf(exitcode.Validation, "link requires exactly two arguments: <phase|task|test> <id>")
+	}
+	entityType, id := c.Args().Get(0), c.Args().Get(1)
+
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+	epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	name, err := linkedEntityName(epicData, entityType, id)
+	if err != nil {
+		return err
+	}
+
+	// Without a config (--file only) links are relative to the working directory
+	cfg, err := config.LoadConfig(c.String("config"))
+	if err != nil {
+		cfg = &config.Config{CurrentEpic: epicFile}
+	}
+	if c.String("file") != "" {
+		cfg.CurrentEpic, err = filepath.Abs(epicFile)
+		if err != nil {
+			return fmt.Errorf("failed to resolve epic path: %w", err)
+		}
+	}
+
+	link := &EntityLink{Type: entityType, ID: id, Name: name}
+	switch {
+	case c.String("docs") != "":
+		docsFile, err := filepath.Abs(c.String("docs"))
+		if err != nil {
+			return fmt.Errorf("failed to resolve docs path: %w", err)
+		}
+		link.Link = docsLink(cfg, docsFile, entityType, id)
+	case cfg.ServerURL != "":
+		link.Link = fmt.Sprintf("%s/%ss/%s", strings.TrimRight(cfg.ServerURL, "/"), entityType, url.PathEscape(id))
+	default:
+		link.Link = docsLink(cfg, cfg.DocsFilePath(), entityType, id)
+	}
+
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(link, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal link to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("link")
+		root.CreateAttr("type", link.Type)
+		root.CreateAttr("id", link.ID)
+		root.CreateAttr("name", link.Name)
+		root.SetText(link.Link)
+		doc.Indent(4)
+		doc.WriteTo(c.Root().Writer)
+	default:
+		if c.Bool("markdown") {
+			label := fmt.Sprintf("%s%s %s", strings.ToUpper(entityType[:1]), entityType[1:], id)
+			if name != "" {
+				label += ": " + name
+			}
+			fmt.Fprintf(c.Root().Writer, "[%s](%s)\n", label, link.Link)
+		} else {
+			fmt.Fprintf(c.Root().Writer, "%s\n", link.Link)
+		}
+	}
+	return nil
+}
+
+func linkedEntityName(epicData *epic.Epic, entityType, id string) (string, error) {
+	switch entityType {
+	case "phase":
+		for _, phase := range epicData.Phases {
+			if phase.ID == id {
+				return phase.Name, nil
+			}
+		}
+	case "task":
+		for _, task := range epicData.Tasks {
+			if task.ID == id {
+				return task.Name, nil
+			}
+		}
+	case "test":
+		for _, test := range epicData.Tests {
+			if test.ID == id {
+				return test.Name, nil
+			}
+		}
+	default:
+		return "", fmt.Errorf("invalid entity type: %s (expected phase, task or test)", entityType)
+	}
+	return "", fmt.Errorf("%s %s not found", entityType, id)
+}
+
+// docsLink points to an entity's anchor in the documentation, relative to the project root
+func docsLink(cfg *config.Config, docsFile, entityType, id string) string {
+	path := docsFile
+	if root, err := filepath.Abs(cfg.Dir()); err == nil {
+		if abs, err := filepath.Abs(docsFile); err == nil {
+			if rel, err := filepath.Rel(root, abs); err == nil && !strings.HasPrefix(rel, "..") {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(path) + "#" + reports.Anchor(entityType, id)
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var linkApp = appOptions{commands: []func() *cli.Command{LinkCommand}}
+
+func TestLinkCommand(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "epics"), 0755))
+	require.NoError(t, storage.NewFileStorage().SaveEpic(createEpicForReset(), filepath.Join(dir, "epics", "epic-1.xml")))
+
+	configFile := filepath.Join(dir, ".agentpm.json")
+	writeConfig := func(t *testing.T, content string) {
+		require.NoError(t, os.WriteFile(configFile, []byte(content), 0644))
+	}
+
+	t.Run("links into the default docs file", func(t *testing.T) {
+		writeConfig(t, `{"current_epic": "epics/epic-1.xml"}`)
+
+		output, err := runApp(t, linkApp.withConfig(configFile), "link", "task", "T1")
+		require.NoError(t, err)
+		assert.Equal(t, "epics/epic-1.md#task-T1\n", output)
+
+		output, err = runApp(t, linkApp.withConfig(configFile), "link", "test", "T1_T1", "--markdown")
+		require.NoError(t, err)
+		assert.Equal(t, "[Test T1_T1: Test 1](epics/epic-1.md#test-T1_T1)\n", output)
+	})
+
+	t.Run("links into the configured docs file", func(t *testing.T) {
+		writeConfig(t, `{"current_epic": "epics/epic-1.xml", "docs_file": "docs/plan.md"}`)
+
+		output, err := runApp(t, linkApp.withConfig(configFile), "--format", "json", "link", "phase", "P1")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"type": "phase", "id": "P1", "name": "Phase 1", "link": "docs/plan.md#phase-P1"}`, output)
+	})
+
+	t.Run("links to the server when configured", func(t *testing.T) {
+		writeConfig(t, `{"current_epic": "epics/epic-1.xml", "server_url": "http://localhost:8080/"}`)
+
+		output, err := runApp(t, linkApp.withConfig(configFile), "link", "task", "T2")
+		require.NoError(t, err)
+		assert.Equal(t, "http://localhost:8080/tasks/T2\n", output)
+	})
+
+	t.Run("rejects unknown entities", func(t *testing.T) {
+		writeConfig(t, `{"current_epic": "epics/epic-1.xml"}`)
+
+		_, err := runApp(t, linkApp.withConfig(configFile), "link", "task", "T9")
+		assert.EqualError(t, err, "task T9 not found")
+
+		_, err = runApp(t, linkApp.withConfig(configFile), "link", "epic", "1")
+		assert.EqualError(t, err, "invalid entity type: epic (expected phase, task or test)")
+	})
+}
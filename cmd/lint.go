@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/lint"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// LintResult is the outcome of a workspace lint run
+type LintResult struct {
+	Workspace  string           `json:"workspace"`
+	Epics      int              `json:"epics"`
+	Findings   []lint.Finding   `json:"findings"`
+	Duplicates []lint.Duplicate `json:"duplicates"`
+	Errors     int              `json:"errors"`
+	Warnings   int              `json:"warnings"`
+}
+
+func LintCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "lint",
+		Usage: "Check epics for quality problems validation does not enforce",
+		Description: `Check the current epic (or with --all every epic of the workspace)
+against rules that go beyond validation:
+
+  task_without_tests         Task without tests (warn)
+  phase_without_description  Phase without description (warn)
+  test_without_task          Test not linked to a task of the epic (error)
+  duplicate_names            Phases, tasks or tests sharing a name (error)
+
+duplicate_names also flags tasks and tests whose names appear in another
+epic of the workspace - likely copy-paste duplication or overlapping scope.
+Names are compared case-insensitively; every occurrence is reported as
+file:line.
+
+The severity of each rule is error, warn or off, set in the lint key of
+.agentpm.json, e.g. {"lint": {"task_without_tests": "error"}}. Warnings
+are reported only; the command fails only on errors.
+
+The workspace is the directory of the config file (or the working
+directory without one); every XML file below it with an <epic> root is
+scanned, hidden directories are skipped.
+
+Examples:
+  agentpm lint --all                 # Check all epics against each other
+  agentpm lint                       # Check the current epic against the others
+  agentpm lint --all --format json`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Epic file to check (overrides config, ignored with --all)",
+			},
+			&cli.BoolFlag{
+				Name:  "all",
+				Usage: "Report duplicates between all epics of the workspace",
+			},
+		},
+		Action: lintAction,
+	}
+}
+
+func lintAction(ctx context.Context, c *cli.Command) error {
+	workspace, err := workspaceRoot(c.String("config"))
+	if err != nil {
+		return err
+	}
+
+	files, err := lint.FindEpicFiles(workspace)
+	if err != nil {
+		return err
+	}
+
+	var entities []lint.Entity
+	for _, file := range files {
+		fileEntities, err := lint.LoadEntities(file)
+		if err != nil {
+			return err
+		}
+		for i := range fileEntities {
+			fileEntities[i].File = workspaceRelative(workspace, file)
+		}
+		entities = append(entities, fileEntities...)
+	}
+
+	severities, err := lintSeverities(c.String("config"))
+	if err != nil {
+		return err
+	}
+
+	result := &LintResult{
+		Workspace: workspace,
+		Epics:     len(files),
+	}
+	if lint.Severity(severities, lint.RuleDuplicateNames) != lint.SeverityOff {
+		result.Duplicates = lint.FindDuplicates(entities)
+	}
+
+	checked := files
+	if !c.Bool("all") {
+		epicFile, err := getEpicFile(c)
+		if err != nil {
+			return err
+		}
+		absEpic, err := filepath.Abs(epicFile)
+		if err != nil {
+			return fmt.Errorf("failed to resolve epic path: %w", err)
+		}
+		result.Duplicates = lint.Involving(result.Duplicates, workspaceRelative(workspace, absEpic))
+		checked = []string{absEpic}
+	}
+
+	for _, file := range checked {
+		epicData, err := storage.NewFileStorage().LoadEpic(file)
+		if err != nil {
+			return fmt.Errorf("failed to load epic %s: %w", file, err)
+		}
+		for _, finding := range lint.Check(epicData, severities) {
+			finding.File = workspaceRelative(workspace, file)
+			result.Findings = append(result.Findings, finding)
+		}
+	}
+	result.count(lint.Severity(severities, lint.RuleDuplicateNames))
+
+	switch c.String("format") {
+	case "json":
+		if result.Findings == nil {
+			result.Findings = []lint.Finding{}
+		}
+		if result.Duplicates == nil {
+			result.Duplicates = []lint.Duplicate{}
+		}
+		jsonData, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal lint result to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		outputLintXML(c, result)
+	default:
+		outputLintText(c, result)
+	}
+
+	if result.Errors > 0 {
+		return exitcode.Errorf(exitcode.Validation, "lint found %d error(s)", result.Errors)
+	}
+	return nil
+}
+
+// count tallies the errors and warnings of the findings and of the duplicates
+// across epics, which have the severity of duplicate_names
+func (r *LintResult) count(duplicateSeverity string) {
+	for _, finding := range r.Findings {
+		if finding.Severity == lint.SeverityError {
+			r.Errors++
+		} else {
+			r.Warnings++
+		}
+	}
+	if duplicateSeverity == lint.SeverityError {
+		r.Errors += len(r.Duplicates)
+	} else {
+		r.Warnings += len(r.Duplicates)
+	}
+}
+
+// lintSeverities returns the severities of the lint rules set in the config,
+// none without a config file
+func lintSeverities(configPath string) (map[string]string, error) {
+	absConfig, err := config.ResolveConfigPath(configPath)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(absConfig); err != nil {
+		return nil, nil
+	}
+	cfg, err := config.LoadConfig(absConfig)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Lint, nil
+}
+
+// workspaceRoot returns the directory of the config file, or the working directory without one
+func workspaceRoot(configPath string) (string, error) {
+	absConfig, err := config.ResolveConfigPath(configPath)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(absConfig); err == nil {
+		return filepath.Dir(absConfig), nil
+	}
+	workspace, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine working directory: %w", err)
+	}
+	return workspace, nil
+}
+
+func workspaceRelative(workspace, path string) string {
+	if rel, err := filepath.Rel(workspace, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+func outputLintText(c *cli.Command, result *LintResult) {
+	w := c.Root().Writer
+
+	if len(result.Findings) == 0 {
+		fmt.Fprintf(w, "No lint findings\n")
+	} else {
+		fmt.Fprintf(w, "Lint findings: %d\n", len(result.Findings))
+		for _, finding := range result.Findings {
+			fmt.Fprintf(w, "  %-5s  %s  %s: %s\n", finding.Severity, finding.File, finding.Rule, finding.Message)
+		}
+	}
+	fmt.Fprintln(w)
+
+	if len(result.Duplicates) == 0 {
+		fmt.Fprintf(w, "No duplicate task or test names across %d epics\n", result.Epics)
+		return
+	}
+
+	fmt.Fprintf(w, "Duplicate names across epics: %d\n", len(result.Duplicates))
+	for _, duplicate := range result.Duplicates {
+		fmt.Fprintf(w, "\n%s %q\n", duplicate.Type, duplicate.Name)
+		for _, occurrence := range duplicate.Occurrences {
+			fmt.Fprintf(w, "  %s  epic %s %s %s\n", occurrence.Link(), occurrence.EpicID, occurrence.Type, occurrence.ID)
+		}
+	}
+}
+
+func outputLintXML(c *cli.Command, result *LintResult) {
+	doc := etree.NewDocument()
+	root := doc.CreateElement("lint")
+	root.CreateAttr("workspace", result.Workspace)
+	root.CreateAttr("epics", fmt.Sprintf("%d", result.Epics))
+	root.CreateAttr("errors", fmt.Sprintf("%d", result.Errors))
+	root.CreateAttr("warnings", fmt.Sprintf("%d", result.Warnings))
+
+	for _, finding := range result.Findings {
+		findingElem := root.CreateElement("finding")
+		findingElem.CreateAttr("rule", finding.Rule)
+		findingElem.CreateAttr("severity", finding.Severity)
+		findingElem.CreateAttr("file", finding.File)
+		findingElem.CreateAttr("type", finding.Type)
+		findingElem.CreateAttr("id", finding.ID)
+		findingElem.SetText(finding.Message)
+	}
+
+	for _, duplicate := range result.Duplicates {
+		duplicateElem := root.CreateElement("duplicate")
+		duplicateElem.CreateAttr("type", duplicate.Type)
+		duplicateElem.CreateAttr("name", duplicate.Name)
+		for _, occurrence := range duplicate.Occurrences {
+			occurrenceElem := duplicateElem.CreateElement("occurrence")
+			occurrenceElem.CreateAttr("file", occurrence.File)
+			occurrenceElem.CreateAttr("line", fmt.Sprintf("%d", occurrence.Line))
+			occurrenceElem.CreateAttr("epic", occurrence.EpicID)
+			occurrenceElem.CreateAttr("id", occurrence.ID)
+		}
+	}
+
+	doc.Indent(4)
+	doc.WriteTo(c.Root().Writer)
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var lintApp = appOptions{commands: []func() *cli.Command{LintCommand}}
+
+func writeLintEpic(t *testing.T, path, id, taskName string) {
+	t.Helper()
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<epic id="` + id + `" name="Epic ` + id + `" status="pending">
+    <tasks>
+        <task id="1_1" phase_id="1" name="` + taskName + `" status="pending"/>
+    </tasks>
+</epic>
+`
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestLintCommand(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, ".agentpm.json")
+	require.NoError(t, os.WriteFile(configFile, []byte(`{"current_epic": "current.xml"}`), 0644))
+
+	writeLintEpic(t, filepath.Join(dir, "current.xml"), "1", "Set up CI")
+	writeLintEpic(t, filepath.Join(dir, "epics", "other.xml"), "2", "Add login form")
+	writeLintEpic(t, filepath.Join(dir, "epics", "copy.xml"), "3", "Add Login Form")
+
+	t.Run("all epics", func(t *testing.T) {
+		output, err := runApp(t, lintApp.withConfig(configFile), "lint", "--all")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "lint found 1 error(s)")
+
+		assert.Contains(t, output, `task "Add Login Form"`)
+		assert.Contains(t, output, "epics/copy.xml:4  epic 3 task 1_1")
+		assert.Contains(t, output, "epics/other.xml:4  epic 2 task 1_1")
+	})
+
+	t.Run("current epic only", func(t *testing.T) {
+		output, err := runApp(t, lintApp.withConfig(configFile), "lint")
+		require.NoError(t, err)
+		assert.Contains(t, output, "No duplicate task or test names across 3 epics")
+		assert.Contains(t, output, "warn   current.xml  task_without_tests: task 1_1 (Set up CI) has no tests")
+		assert.NotContains(t, output, "other.xml  task_without_tests", "only the current epic is checked")
+	})
+
+	t.Run("explicit epic as json", func(t *testing.T) {
+		output, err := runApp(t, lintApp.withConfig(configFile), "--format", "json", "lint", "--file", filepath.Join(dir, "epics", "other.xml"))
+		require.Error(t, err)
+
+		var result LintResult
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.Equal(t, 3, result.Epics)
+		require.Len(t, result.Duplicates, 1)
+		assert.Equal(t, []string{"epics/copy.xml", "epics/other.xml"}, result.Duplicates[0].Files())
+	})
+}
+
+func TestLintCommandSeverities(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, ".agentpm.json")
+	epicFile := filepath.Join(dir, "current.xml")
+	require.NoError(t, os.WriteFile(epicFile, []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<epic id="1" name="Epic 1" status="pending">
+    <phases>
+        <phase id="1" name="Build"><description>Build it</description></phase>
+        <phase id="2" name="Build"/>
+    </phases>
+    <tasks>
+        <task id="1_1" phase_id="1" name="Schema" status="pending"/>
+    </tasks>
+    <tests>
+        <test id="T1" task_id="1_9" phase_id="1" name="Schema works"/>
+    </tests>
+</epic>
+`), 0644))
+
+	run := func(lintConfig string, args ...string) (LintResult, error) {
+		require.NoError(t, os.WriteFile(configFile, []byte(`{"current_epic": "current.xml", "lint": `+lintConfig+`}`), 0644))
+		output, err := runApp(t, lintApp.withConfig(configFile), append([]string{"--format", "json", "lint"}, args...)...)
+		var result LintResult
+		require.NoError(t, json.Unmarshal([]byte(output), &result), output)
+		return result, err
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		result, err := run(`{}`)
+		require.Error(t, err)
+		assert.Equal(t, 2, result.Errors, "unknown task and duplicate phase name")
+		assert.Equal(t, 2, result.Warnings, "task without tests and phase without description")
+
+		rules := map[string]string{}
+		for _, finding := range result.Findings {
+			rules[finding.Rule+" "+finding.ID] = finding.Severity
+			assert.Equal(t, "current.xml", finding.File)
+		}
+		assert.Equal(t, map[string]string{
+			"task_without_tests 1_1":      "warn",
+			"phase_without_description 2": "warn",
+			"test_without_task T1":        "error",
+			"duplicate_names 2":           "error",
+		}, rules)
+	})
+
+	t.Run("configured severities", func(t *testing.T) {
+		result, err := run(`{"test_without_task": "warn", "duplicate_names": "off", "task_without_tests": "error"}`)
+		require.Error(t, err)
+		assert.Equal(t, 1, result.Errors)
+		assert.Equal(t, 2, result.Warnings)
+	})
+
+	t.Run("warnings only", func(t *testing.T) {
+		result, err := run(`{"test_without_task": "warn", "duplicate_names": "off"}`)
+		require.NoError(t, err, "warnings do not fail lint")
+		assert.Equal(t, 0, result.Errors)
+		assert.Equal(t, 3, result.Warnings)
+	})
+
+	t.Run("unknown rule", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(configFile, []byte(`{"current_epic": "current.xml", "lint": {"no_such_rule": "warn"}}`), 0644))
+		_, err := runApp(t, lintApp.withConfig(configFile), "lint")
+		assert.ErrorContains(t, err, `unknown lint rule "no_such_rule"`)
+	})
+}
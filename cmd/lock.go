@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// LockEpicFiles is the root Before hook that makes this invocation lock the
+// epic files it loads until it saved them back, unless --no-lock is given
+func LockEpicFiles(ctx context.Context, c *cli.Command) (context.Context, error) {
+	storage.SetLocking(!c.Bool("no-lock"))
+	return ctx, nil
+}
@@ -2,13 +2,18 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/beevik/etree"
 	"github.com/mindreframer/agentpm/internal/config"
 	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/gitmeta"
 	"github.com/mindreframer/agentpm/internal/query"
+	"github.com/mindreframer/agentpm/internal/service"
 	"github.com/mindreframer/agentpm/internal/storage"
 	"github.com/urfave/cli/v3"
 )
@@ -18,6 +23,16 @@ func LogCommand() *cli.Command {
 		Name:      "log",
 		Usage:     "Log an event to the current epic",
 		ArgsUsage: "<message>",
+		Description: `Record a free-form event in the current epic.
+
+Inside a git repository every event agentpm records is linked to the commit
+and branch checked out at the time (unless --no-git is given). With
+--with-commits no event is logged; instead the task completions are listed
+with the commits they were recorded at.
+
+Examples:
+  agentpm log "Implemented pagination controls" --files "src/list.go:modified"
+  agentpm log --with-commits`,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:  "file",
@@ -36,8 +51,16 @@ func LogCommand() *cli.Command {
 				Name:  "time",
 				Usage: "Timestamp for the event (ISO 8601 format)",
 			},
+			&cli.BoolFlag{
+				Name:  "with-commits",
+				Usage: "List the task completions with the git commits they were recorded at instead of logging an event",
+			},
 		},
-		Action: func(ctx context.Context, cmd *cli.Command) error {
+		Action: withQuietResult(func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.Bool("with-commits") {
+				return logCommitsAction(cmd)
+			}
+
 			if cmd.Args().Len() < 1 {
 				return fmt.Errorf("event message is required")
 			}
@@ -51,11 +74,11 @@ func LogCommand() *cli.Command {
 				if err != nil {
 					return fmt.Errorf("failed to load configuration: %w", err)
 				}
-				epicFile = cfg.CurrentEpic
+				epicFile = cfg.EpicFilePath()
 			}
 
 			if epicFile == "" {
-				return fmt.Errorf("no epic file specified (use --file flag or set current epic)")
+				return exitcode.Errorf(exitcode.Validation, "no epic file specified (use --file flag or set current epic)")
 			}
 
 			// Parse timestamp if provided
@@ -96,8 +119,101 @@ func LogCommand() *cli.Command {
 			// Output confirmation
 			fmt.Fprintf(cmd.Writer, "Event logged: %s\n", message)
 			return nil
-		},
+		}),
+	}
+}
+
+// taskCommit is a task completion and the git commit it was recorded at
+type taskCommit struct {
+	TaskID      string    `json:"task_id"`
+	Name        string    `json:"name"`
+	CompletedAt time.Time `json:"completed_at"`
+	Commit      string    `json:"commit,omitempty"`
+	Branch      string    `json:"branch,omitempty"`
+	Actor       string    `json:"actor,omitempty"`
+}
+
+func logCommitsAction(cmd *cli.Command) error {
+	epicFile, err := getEpicFile(cmd)
+	if err != nil {
+		return err
+	}
+	epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	names := make(map[string]string, len(epicData.Tasks))
+	for _, task := range epicData.Tasks {
+		names[task.ID] = task.Name
+	}
+	completions := []taskCommit{}
+	for _, event := range epicData.Events {
+		if service.EventType(event.Type) != service.EventTaskCompleted {
+			continue
+		}
+		taskID := service.EventEntityID(event)
+		completions = append(completions, taskCommit{
+			TaskID:      taskID,
+			Name:        names[taskID],
+			CompletedAt: event.Timestamp,
+			Commit:      event.Commit,
+			Branch:      event.Branch,
+			Actor:       event.Actor,
+		})
+	}
+
+	w := cmd.Root().Writer
+	switch cmd.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(map[string]interface{}{
+			"epic_id":          epicData.ID,
+			"task_completions": completions,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal task completions to JSON: %w", err)
+		}
+		fmt.Fprintf(w, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("task_completions")
+		root.CreateAttr("epic", epicData.ID)
+		for _, completion := range completions {
+			elem := root.CreateElement("task")
+			elem.CreateAttr("id", completion.TaskID)
+			elem.CreateAttr("completed_at", completion.CompletedAt.Format(time.RFC3339))
+			if completion.Commit != "" {
+				elem.CreateAttr("commit", completion.Commit)
+			}
+			if completion.Branch != "" {
+				elem.CreateAttr("branch", completion.Branch)
+			}
+			if completion.Actor != "" {
+				elem.CreateAttr("actor", completion.Actor)
+			}
+			elem.SetText(completion.Name)
+		}
+		doc.Indent(4)
+		doc.WriteTo(w)
+	default:
+		if len(completions) == 0 {
+			fmt.Fprintf(w, "No task completions recorded in epic %s.\n", epicData.ID)
+			return nil
+		}
+		fmt.Fprintf(w, "Task completions in epic %s:\n", epicData.ID)
+		for _, completion := range completions {
+			commit := "(no commit)"
+			if completion.Commit != "" {
+				commit = gitmeta.ShortSHA(completion.Commit)
+				if completion.Branch != "" {
+					commit += " (" + completion.Branch + ")"
+				}
+			}
+			fmt.Fprintf(w, "  %-20s %-10s %s  %s\n", commit, completion.TaskID,
+				completion.CompletedAt.Format("2006-01-02 15:04"), completion.Name)
+		}
 	}
+	return nil
 }
 
 func isValidEventType(eventType string) bool {
@@ -226,7 +342,14 @@ func (ls *LogService) addEventToEpic(epicData *epic.Epic, eventType, eventData s
 		ID:        eventID,
 		Type:      eventType,
 		Timestamp: timestamp,
+		Actor:     service.Actor(),
 		Data:      eventData,
+
+		CorrelationID: service.CorrelationID(),
+	}
+	if info := service.GitInfo(); info != nil {
+		newEvent.Commit = info.Commit
+		newEvent.Branch = info.Branch
 	}
 
 	// Add event to epic
@@ -431,3 +431,39 @@ func TestIsValidFileAction(t *testing.T) {
 		})
 	}
 }
+
+func TestLogWithCommits(t *testing.T) {
+	commit := "385522073bb1687837c8f1ea7858e70a8c63d8a2"
+	epicFile := filepath.Join(t.TempDir(), "test-epic.xml")
+	testEpic := &epic.Epic{
+		ID:     "epic-1",
+		Name:   "Test Epic",
+		Status: epic.StatusWIP,
+		Tasks: []epic.Task{
+			{ID: "1_1", PhaseID: "1", Name: "Login form", Status: epic.StatusCompleted},
+			{ID: "1_2", PhaseID: "1", Name: "Session store", Status: epic.StatusCompleted},
+		},
+		Events: []epic.Event{
+			{ID: "e1", Type: "task_started", Timestamp: time.Date(2025, 8, 16, 9, 0, 0, 0, time.UTC), Commit: commit, Data: "Task 1_1 (Login form) started"},
+			{ID: "e2", Type: "task_completed", Timestamp: time.Date(2025, 8, 16, 10, 0, 0, 0, time.UTC), Commit: commit, Branch: "feature/login", Data: "Task 1_1 (Login form) completed"},
+			{ID: "e3", Type: "task_completed", Timestamp: time.Date(2025, 8, 16, 11, 0, 0, 0, time.UTC), Data: "Task 1_2 (Session store) completed"},
+		},
+	}
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicFile))
+
+	run := func(args ...string) string {
+		var stdout bytes.Buffer
+		cmd := LogCommand()
+		cmd.Root().Writer = &stdout
+		require.NoError(t, cmd.Run(context.Background(), append([]string{"log", "--with-commits", "--file", epicFile}, args...)))
+		return stdout.String()
+	}
+
+	assert.Equal(t, "Task completions in epic epic-1:\n"+
+		"  3855220 (feature/login) 1_1        2025-08-16 10:00  Login form\n"+
+		"  (no commit)          1_2        2025-08-16 11:00  Session store\n", run())
+
+	loaded, err := storage.NewFileStorage().LoadEpic(epicFile)
+	require.NoError(t, err)
+	assert.Len(t, loaded.Events, 3, "--with-commits does not log an event")
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func createManualEpic(t *testing.T) string {
+	t.Helper()
+	e := &epic.Epic{
+		ID:           "manual",
+		Name:         "Manual",
+		Status:       epic.StatusWIP,
+		CreatedAt:    time.Date(2025, 8, 1, 9, 0, 0, 0, time.UTC),
+		CurrentState: &epic.CurrentState{ActivePhase: "P1", ActiveTask: "P1_1"},
+		Phases:       []epic.Phase{{ID: "P1", Name: "Phase 1", Status: epic.StatusWIP}},
+		Tasks:        []epic.Task{{ID: "P1_1", PhaseID: "P1", Name: "Task 1", Status: epic.StatusWIP}},
+		Tests: []epic.Test{
+			{ID: "M1", TaskID: "P1_1", PhaseID: "P1", Name: "Looks right", Description: "Logo fits the header", Type: epic.TestTypeManual, Status: epic.StatusWIP, TestStatus: epic.TestStatusWIP},
+			{ID: "M2", TaskID: "P1_1", PhaseID: "P1", Name: "Reads well", Type: epic.TestTypeManual, Status: epic.StatusPending, TestStatus: epic.TestStatusPending},
+			{ID: "A1", TaskID: "P1_1", PhaseID: "P1", Name: "Builds", Status: epic.StatusWIP, TestStatus: epic.TestStatusWIP},
+		},
+	}
+	epicFile := filepath.Join(t.TempDir(), "epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(e, epicFile))
+	return epicFile
+}
+
+func TestPassManualTest(t *testing.T) {
+	epicFile := createManualEpic(t)
+
+	_, err := runApp(t, bulkApp.withStdin(""), "pass", "M1", "--file", epicFile)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "M1: it is a manual test")
+	assert.Contains(t, err.Error(), "Hint: agentpm pass M1 --verified-by <name>")
+	assert.Equal(t, exitcode.Validation, ExitCode(err))
+
+	// A batch is refused as a whole
+	_, err = runApp(t, bulkApp.withStdin(""), "pass", "A1", "M1", "--file", epicFile)
+	require.Error(t, err)
+	assert.Equal(t, epic.TestStatusWIP, loadBulkTests(t, epicFile)["A1"].TestStatus)
+
+	out, err := runApp(t, bulkApp.withStdin(""), "pass", "M1", "--file", epicFile, "--verified-by", "alice", "--evidence", "https://example.com/shot.png")
+	require.NoError(t, err)
+	assert.Equal(t, "Test M1 passed.\n", out)
+	test := loadBulkTests(t, epicFile)["M1"]
+	assert.Equal(t, epic.TestStatusDone, test.TestStatus)
+	assert.Equal(t, "alice", test.VerifiedBy)
+	assert.Equal(t, "https://example.com/shot.png", test.Evidence)
+}
+
+func TestFailingManual(t *testing.T) {
+	epicFile := createManualEpic(t)
+	run := func(args ...string) string {
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config"},
+				&cli.StringFlag{Name: "format", Value: "text"},
+			},
+			Commands: []*cli.Command{FailingCommand()},
+		}
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		require.NoError(t, app.Run(context.Background(), append([]string{"agentpm", "failing", "--manual", "--file", epicFile}, args...)))
+		return stdout.String()
+	}
+
+	text := run()
+	assert.Contains(t, text, "Found 2 manual test(s) waiting for a person")
+	assert.Contains(t, text, "✗ M1 (P1_1)")
+	assert.NotContains(t, text, "A1")
+
+	_, err := runApp(t, bulkApp.withStdin(""), "fail", "M1", "Logo cut off", "--file", epicFile, "--verified-by", "bob")
+	require.NoError(t, err)
+
+	var output failingOutput
+	require.NoError(t, json.Unmarshal([]byte(run("--format", "json")), &output))
+	require.Equal(t, 2, output.TotalFailing)
+	assert.Equal(t, failingTestOutput{
+		ID: "M1", PhaseID: "P1", TaskID: "P1_1", Name: "Looks right", Description: "Logo fits the header", FailureNote: "Logo cut off", Manual: true, VerifiedBy: "bob",
+	}, output.FailingTests[0])
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/commands"
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// mergeOutput describes the epic file folded in by the merge command
+type mergeOutput struct {
+	*service.MergeResult
+	Source        string `json:"source"`
+	SourceRemoved bool   `json:"source_removed"`
+}
+
+// MergeCommand folds another epic file into the current epic
+func MergeCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "merge",
+		Usage:     "Fold another epic into the current epic",
+		ArgsUsage: "<epic-file>",
+		Description: `Append the phases, tasks, tests, milestones, blockers, suppressions and
+events of another epic file to the current epic. Events are kept in
+chronological order.
+
+Phase, task, test, milestone and blocker IDs the current epic already uses
+are collisions: they are listed and nothing changes, unless --remap is given.
+--remap renames each colliding ID to the first free <id>-<n>, e.g. 3A-2,
+and updates every reference to it. Both epics cannot have a phase in
+progress.
+
+The other epic file is kept unless --remove is given, which also removes
+it from the epics of the workspace.
+
+Examples:
+  agentpm merge epic-9b.xml
+  agentpm merge epic-10.xml --remap
+  agentpm merge epic-9b.xml --remove`,
+		Flags: append(commands.GlobalFlags(),
+			&cli.BoolFlag{
+				Name:  "remap",
+				Usage: "Rename colliding IDs instead of failing",
+			},
+			&cli.BoolFlag{
+				Name:  "remove",
+				Usage: "Remove the merged epic file afterwards",
+			},
+		),
+		Action: withQuietResult(mergeAction),
+	}
+}
+
+func mergeAction(ctx context.Context, c *cli.Command) error {
+	if c.Args().Len() != 1 {
+		return exitcode.Errorf(exitcode.Validation, "merge requires exactly one epic file")
+	}
+	otherFile := c.Args().First()
+	if c.Bool("remove") && storage.DryRun() {
+		return errDryRunUnsupported("merge --remove")
+	}
+
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+	target, err := filepath.Abs(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to resolve epic path: %w", err)
+	}
+	source, err := filepath.Abs(otherFile)
+	if err != nil {
+		return fmt.Errorf("failed to resolve epic path: %w", err)
+	}
+	if source == target {
+		return fmt.Errorf("cannot merge %s into itself", otherFile)
+	}
+
+	timestamp := time.Now()
+	if timeStr := c.String("time"); timeStr != "" {
+		timestamp, err = time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+		}
+	}
+
+	fileStorage := storage.NewFileStorage()
+	if !fileStorage.EpicExists(otherFile) {
+		return exitcode.Errorf(exitcode.NotFound, "epic file %s not found", otherFile)
+	}
+	other, err := fileStorage.LoadEpic(otherFile)
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", otherFile, err)
+	}
+	epicData, err := fileStorage.LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	result, err := service.MergeEpic(epicData, other, c.Bool("remap"), timestamp)
+	if err != nil {
+		return err
+	}
+	if err := fileStorage.SaveEpic(epicData, epicFile); err != nil {
+		return fmt.Errorf("failed to save epic: %w", err)
+	}
+
+	output := mergeOutput{MergeResult: result, Source: otherFile}
+	if c.Bool("remove") {
+		if err := unregisterWorkspaceEpic(c, otherFile); err != nil {
+			return err
+		}
+		if err := os.Remove(source); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", otherFile, err)
+		}
+		output.SourceRemoved = true
+	}
+	return writeMergeResult(c, output)
+}
+
+// unregisterWorkspaceEpic removes the epic file from the epics of the
+// workspace, if there is a config it is registered in
+func unregisterWorkspaceEpic(c *cli.Command, epicFile string) error {
+	configPath := c.String("config")
+	absConfig, err := config.ResolveConfigPath(configPath)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(absConfig); err != nil {
+		return nil
+	}
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	stored, err := config.NormalizeEpicPath(configPath, epicFile)
+	if err != nil {
+		return err
+	}
+	if !cfg.RemoveEpic(stored) {
+		return nil
+	}
+	if err := config.SaveConfig(cfg, configPath); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+	return nil
+}
+
+func writeMergeResult(c *cli.Command, result mergeOutput) error {
+	w := c.Root().Writer
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal result to JSON: %w", err)
+		}
+		fmt.Fprintf(w, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("epic_merged")
+		root.CreateAttr("epic", result.EpicID)
+		root.CreateAttr("merged", result.MergedID)
+		root.CreateAttr("source", result.Source)
+		root.CreateAttr("tasks", strconv.Itoa(result.Tasks))
+		root.CreateAttr("tests", strconv.Itoa(result.Tests))
+		root.CreateAttr("source_removed", strconv.FormatBool(result.SourceRemoved))
+		for _, id := range result.Phases {
+			root.CreateElement("phase").CreateAttr("id", id)
+		}
+		for _, remap := range result.Remapped {
+			elem := root.CreateElement("remapped")
+			elem.CreateAttr("kind", remap.Kind)
+			elem.CreateAttr("from", remap.From)
+			elem.CreateAttr("to", remap.To)
+		}
+		doc.Indent(2)
+		doc.WriteTo(w)
+	default:
+		fmt.Fprintf(w, "Merged epic %s into epic %s: phases %s\n", result.MergedID, result.EpicID, strings.Join(result.Phases, ", "))
+		fmt.Fprintf(w, "Added %d tasks, %d tests, %d milestones, %d blockers and %d events.\n",
+			result.Tasks, result.Tests, result.Milestones, result.Blockers, result.Events)
+		for _, remap := range result.Remapped {
+			fmt.Fprintf(w, "Renamed %s %s to %s\n", remap.Kind, remap.From, remap.To)
+		}
+		if result.SourceRemoved {
+			fmt.Fprintf(w, "Removed %s.\n", result.Source)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeCommand(t *testing.T) {
+	setup := func(t *testing.T) (dir, configPath string) {
+		dir = t.TempDir()
+		fileStorage := storage.NewFileStorage()
+		require.NoError(t, fileStorage.SaveEpic(createEpicForSplit(), filepath.Join(dir, "epic-9.xml")))
+		other := createEpicForSplit()
+		other.ID = "epic-10"
+		require.NoError(t, fileStorage.SaveEpic(other, filepath.Join(dir, "epic-10.xml")))
+
+		configPath = filepath.Join(dir, ".agentpm.json")
+		cfg := config.DefaultConfig()
+		cfg.CurrentEpic = "epic-9.xml"
+		cfg.Epics = []string{"epic-10.xml"}
+		require.NoError(t, config.SaveConfig(cfg, configPath))
+		return dir, configPath
+	}
+	run := func(configPath string, args ...string) (string, error) {
+		var stdout bytes.Buffer
+		cmd := MergeCommand()
+		cmd.Root().Writer = &stdout
+		err := cmd.Run(context.Background(), append([]string{"merge", "--config", configPath, "--time", "2025-08-18T09:00:00Z"}, args...))
+		return stdout.String(), err
+	}
+
+	t.Run("lists colliding IDs and changes nothing", func(t *testing.T) {
+		dir, configPath := setup(t)
+		_, err := run(configPath, filepath.Join(dir, "epic-10.xml"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "IDs already used in epic epic-9: phase 2A, phase 3A, phase 3B, task 2A_1")
+		assert.Contains(t, err.Error(), "use --remap")
+		assert.Equal(t, exitcode.Constraint, ExitCode(err))
+
+		unchanged, err := storage.NewFileStorage().LoadEpic(filepath.Join(dir, "epic-9.xml"))
+		require.NoError(t, err)
+		assert.Len(t, unchanged.Phases, 3)
+	})
+
+	t.Run("remaps colliding IDs and removes the merged file", func(t *testing.T) {
+		dir, configPath := setup(t)
+		otherFile := filepath.Join(dir, "epic-10.xml")
+		output, err := run(configPath, "--format", "json", "--remap", "--remove", otherFile)
+		require.NoError(t, err)
+
+		var result map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.Equal(t, []interface{}{"2A-2", "3A-2", "3B-2"}, result["phases"])
+		assert.Equal(t, true, result["source_removed"])
+
+		merged, err := storage.NewFileStorage().LoadEpic(filepath.Join(dir, "epic-9.xml"))
+		require.NoError(t, err)
+		assert.Len(t, merged.Phases, 6)
+		assert.Equal(t, "3A-2", merged.Phases[5].DependsOn)
+		assert.Equal(t, "3A_1-2", merged.Tests[1].TaskID)
+		assert.NoFileExists(t, otherFile)
+
+		cfg, err := config.LoadConfig(configPath)
+		require.NoError(t, err)
+		assert.Empty(t, cfg.Epics)
+	})
+
+	t.Run("rejects invalid merges", func(t *testing.T) {
+		dir, configPath := setup(t)
+		_, err := run(configPath, filepath.Join(dir, "missing.xml"))
+		require.Error(t, err)
+		assert.Equal(t, exitcode.NotFound, ExitCode(err))
+
+		_, err = run(configPath, filepath.Join(dir, "epic-9.xml"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "into itself")
+
+		_, err = run(configPath)
+		require.Error(t, err)
+		assert.Equal(t, exitcode.Validation, ExitCode(err))
+	})
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/burndown"
+	"github.com/mindreframer/agentpm/internal/metrics"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// MetricsCommand summarises the progress of the current epic: burn-down and velocity
+func MetricsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "metrics",
+		Usage: "Show done vs remaining tasks over time and the velocity in tasks per day",
+		Description: `Summarise the progress of the current epic for whoever supervises the
+agents working on it: how many tasks are done and remaining at the end of
+each day, replayed from the event history like 'agentpm burndown', and the
+velocity in tasks per day - since the epic started and over the last days.
+
+At the recent velocity (or the overall one when nothing was done recently)
+the remaining tasks give an estimated completion date. Completed and
+cancelled tasks both count as done.
+
+Examples:
+  agentpm metrics                        # Summary with the last 7 days
+  agentpm metrics --window 14 --format json`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config",
+			},
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"F"},
+				Usage:   "Output format: text (default), json, xml",
+				Value:   "text",
+			},
+			&cli.IntFlag{
+				Name:  "window",
+				Usage: "Number of days the recent velocity spans",
+				Value: 7,
+			},
+		},
+		Action: metricsAction,
+	}
+}
+
+func metricsAction(ctx context.Context, c *cli.Command) error {
+	if c.Int("window") < 1 {
+		return fmt.Errorf("--window must be at least 1")
+	}
+
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if timeStr := c.String("time"); timeStr != "" {
+		now, err = time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+		}
+	}
+
+	epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	report := metrics.Build(epicData, now, int(c.Int("window")))
+
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal metrics to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		outputMetricsXML(c, report)
+	default:
+		outputMetricsText(c, report)
+	}
+	return nil
+}
+
+func outputMetricsText(c *cli.Command, report *metrics.Metrics) {
+	w := c.Root().Writer
+
+	fmt.Fprintf(w, "Metrics for epic %s (%s)\n\n", report.EpicID, report.EpicName)
+	fmt.Fprintf(w, "Tasks:     %d of %d done, %d remaining\n", report.DoneTasks, report.TotalTasks, report.RemainingTasks)
+	fmt.Fprintf(w, "Velocity:  %.1f tasks/day overall, %.1f tasks/day over the last %d days\n", report.Velocity, report.RecentVelocity, report.Window)
+	switch {
+	case report.RemainingTasks == 0:
+		fmt.Fprintf(w, "Estimate:  all tasks done\n")
+	case report.EstimatedCompletion != "":
+		fmt.Fprintf(w, "Estimate:  %d more days, around %s\n", report.DaysLeft, report.EstimatedCompletion)
+	default:
+		fmt.Fprintf(w, "Estimate:  none yet, no task done so far\n")
+	}
+	fmt.Fprintf(w, "Burn-down: %s\n\n", burndown.Sparkline(report.Remaining()))
+
+	days := report.Days[max(len(report.Days)-report.Window, 0):]
+	fmt.Fprintf(w, "%-10s  %4s  %9s  %6s\n", "date", "done", "remaining", "closed")
+	for _, day := range days {
+		fmt.Fprintf(w, "%-10s  %4d  %9d  %+6d\n", day.Date, day.Done, day.Remaining, day.Closed)
+	}
+}
+
+func outputMetricsXML(c *cli.Command, report *metrics.Metrics) {
+	doc := etree.NewDocument()
+	root := doc.CreateElement("metrics")
+	root.CreateAttr("epic", report.EpicID)
+	root.CreateAttr("total_tasks", strconv.Itoa(report.TotalTasks))
+	root.CreateAttr("done_tasks", strconv.Itoa(report.DoneTasks))
+	root.CreateAttr("remaining_tasks", strconv.Itoa(report.RemainingTasks))
+
+	velocity := root.CreateElement("velocity")
+	velocity.CreateAttr("overall", fmt.Sprintf("%.2f", report.Velocity))
+	velocity.CreateAttr("recent", fmt.Sprintf("%.2f", report.RecentVelocity))
+	velocity.CreateAttr("window", strconv.Itoa(report.Window))
+	if report.EstimatedCompletion != "" {
+		estimate := root.CreateElement("estimate")
+		estimate.CreateAttr("days_left", strconv.Itoa(report.DaysLeft))
+		estimate.CreateAttr("date", report.EstimatedCompletion)
+	}
+
+	burndownElem := root.CreateElement("burndown")
+	for _, day := range report.Days {
+		dayElem := burndownElem.CreateElement("day")
+		dayElem.CreateAttr("date", day.Date)
+		dayElem.CreateAttr("done", strconv.Itoa(day.Done))
+		dayElem.CreateAttr("remaining", strconv.Itoa(day.Remaining))
+		dayElem.CreateAttr("closed", strconv.Itoa(day.Closed))
+	}
+
+	doc.Indent(4)
+	doc.WriteTo(c.Root().Writer)
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/metrics"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var metricsApp = appOptions{commands: []func() *cli.Command{MetricsCommand}, time: "2025-08-14T12:00:00Z"}
+
+func TestMetricsCommand(t *testing.T) {
+	epicFile := filepath.Join(t.TempDir(), "epic.xml")
+	testEpic := createEpicForReset()
+	testEpic.CreatedAt = time.Date(2025, 8, 12, 9, 0, 0, 0, time.UTC)
+	service.CreateEvent(testEpic, service.EventTaskCompleted, "P1", "T1", "", "", time.Date(2025, 8, 13, 11, 0, 0, 0, time.UTC))
+	testEpic.Tasks[0].Status = epic.StatusCompleted
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicFile))
+
+	t.Run("text output", func(t *testing.T) {
+		output, err := runApp(t, metricsApp, "metrics", "--file", epicFile, "--window", "2")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Metrics for epic epic-1 (Test Epic)")
+		assert.Contains(t, output, "Tasks:     1 of 2 done, 1 remaining\n")
+		assert.Contains(t, output, "Velocity:  0.3 tasks/day overall, 0.5 tasks/day over the last 2 days\n")
+		assert.Contains(t, output, "Estimate:  2 more days, around 2025-08-16\n")
+		assert.Contains(t, output, "Burn-down: █▄▄\n")
+		assert.Contains(t, output, "2025-08-13     1          1      +1\n")
+		assert.NotContains(t, output, "2025-08-12  ", "only the last window days are listed")
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		output, err := runApp(t, metricsApp, "metrics", "--file", epicFile, "--format", "json")
+		require.NoError(t, err)
+
+		var report metrics.Metrics
+		require.NoError(t, json.Unmarshal([]byte(output), &report))
+		assert.Equal(t, 1, report.DoneTasks)
+		assert.Equal(t, 7, report.Window)
+		assert.Len(t, report.Days, 3)
+	})
+
+	t.Run("xml output", func(t *testing.T) {
+		output, err := runApp(t, metricsApp, "metrics", "--file", epicFile, "--format", "xml")
+		require.NoError(t, err)
+		assert.Contains(t, output, `<metrics epic="epic-1" total_tasks="2" done_tasks="1" remaining_tasks="1">`)
+		assert.Contains(t, output, `<day date="2025-08-13" done="1" remaining="1" closed="1"/>`)
+	})
+
+	t.Run("rejects an empty window", func(t *testing.T) {
+		_, err := runApp(t, metricsApp, "metrics", "--file", epicFile, "--window", "0")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--window must be at least 1")
+	})
+}
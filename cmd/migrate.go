@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/lint"
+	"github.com/mindreframer/agentpm/internal/migrate"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// MigrateCommand upgrades epic files to the current schema version
+func MigrateCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "migrate",
+		Usage: "Upgrade epic files to the current schema version",
+		Description: fmt.Sprintf(`Apply the schema migrations an epic file is missing, in order, and stamp
+the version reached as its schema_version attribute. Files without one are
+at version 0. The current version is %d:
+
+%s
+The previous version of each upgraded file is kept as <epic>.bak.1, even when
+the backups config key keeps none ('agentpm restore' rolls it back).
+
+A migration that finds statuses without a clear mapping keeps what it could
+migrate but stops the upgrade: the epic stays at the version before it until
+the cases reported are resolved and 'agentpm migrate' runs again.
+
+Examples:
+  agentpm migrate --dry-run          # Show what would change
+  agentpm migrate -f epic-3.xml
+  agentpm migrate --all              # Upgrade every epic of the workspace`, epic.CurrentSchemaVersion, migrationList()),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config (ignored with --all)",
+			},
+			&cli.BoolFlag{
+				Name:  "all",
+				Usage: "Upgrade every epic file of the workspace",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show what would be migrated without making changes",
+			},
+		},
+		Action: migrateAction,
+	}
+}
+
+// migratedEpic is the migration report of one epic file
+type migratedEpic struct {
+	File   string `json:"file"`
+	Backup string `json:"backup,omitempty"`
+	*migrate.SchemaReport
+}
+
+func migrationList() string {
+	var list string
+	for _, migration := range migrate.Migrations {
+		list += fmt.Sprintf("  %d. %s\n", migration.Version, migration.Description)
+	}
+	return list
+}
+
+func migrateAction(ctx context.Context, c *cli.Command) error {
+	// The report below covers everything a deprecation warning would say
+	storage.SetLoadHook(nil)
+
+	var files []string
+	shown := func(file string) string { return file }
+	if c.Bool("all") {
+		workspace, err := workspaceRoot(c.String("config"))
+		if err != nil {
+			return err
+		}
+		if files, err = lint.FindEpicFiles(workspace); err != nil {
+			return err
+		}
+		shown = func(file string) string { return workspaceRelative(workspace, file) }
+	} else {
+		epicFile, err := getEpicFile(c)
+		if err != nil {
+			return err
+		}
+		files = []string{epicFile}
+	}
+
+	dryRun := c.Bool("dry-run") || storage.DryRun()
+	if !dryRun && storage.BackupRetention() == 0 {
+		storage.SetBackupRetention(1)
+		defer storage.SetBackupRetention(0)
+	}
+
+	fileStorage := storage.NewFileStorage()
+	results := []migratedEpic{}
+	for _, file := range files {
+		epicData, err := fileStorage.LoadEpic(file)
+		if err != nil {
+			return fmt.Errorf("failed to load epic %s: %w", shown(file), err)
+		}
+		report, err := migrate.Schema(epicData)
+		if err != nil {
+			return fmt.Errorf("%s: %w", shown(file), err)
+		}
+
+		result := migratedEpic{File: shown(file), SchemaReport: report}
+		if !dryRun && report.Upgraded() {
+			if err := fileStorage.SaveEpic(epicData, file); err != nil {
+				return fmt.Errorf("failed to save epic %s: %w", shown(file), err)
+			}
+			result.Backup = storage.BackupPath(shown(file), 1)
+		}
+		results = append(results, result)
+	}
+
+	w := c.Root().Writer
+	switch c.String("format") {
+	case "json":
+		output := struct {
+			DryRun bool           `json:"dry_run"`
+			Epics  []migratedEpic `json:"epics"`
+		}{dryRun, results}
+		jsonData, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal migration report to JSON: %w", err)
+		}
+		fmt.Fprintf(w, "%s\n", jsonData)
+	case "xml":
+		outputMigrateXML(w, results, dryRun)
+	default:
+		outputMigrateText(w, results, dryRun)
+	}
+	return nil
+}
+
+func outputMigrateText(w io.Writer, results []migratedEpic, dryRun bool) {
+	if len(results) == 0 {
+		fmt.Fprintf(w, "No epic files found.\n")
+	}
+	for i, result := range results {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if !result.Upgraded() && len(result.Ambiguities) == 0 {
+			fmt.Fprintf(w, "Epic %s (%s) is at schema version %d, nothing to migrate.\n", result.EpicID, result.File, result.To)
+			continue
+		}
+
+		verb := "Migrated"
+		if dryRun {
+			verb = "Would migrate"
+		}
+		target := fmt.Sprintf(" from schema version %d to %d", result.From, result.To)
+		if result.To == result.From {
+			target = fmt.Sprintf(", still at schema version %d", result.From)
+		}
+		fmt.Fprintf(w, "%s epic %s (%s)%s\n", verb, result.EpicID, result.File, target)
+		for _, step := range result.Steps {
+			fmt.Fprintf(w, "  %d. %s: %d changes\n", step.Version, step.Description, len(step.Changes))
+			for _, change := range step.Changes {
+				from := change.From
+				if from == "" {
+					from = "(none)"
+				}
+				fmt.Fprintf(w, "     %-5s %-10s %s: %s -> %s\n", change.EntityType, change.EntityID, change.Field, from, change.To)
+			}
+		}
+		if result.Backup != "" {
+			fmt.Fprintf(w, "  Previous version kept as %s\n", result.Backup)
+		}
+
+		if len(result.Ambiguities) > 0 {
+			fmt.Fprintf(w, "\nNeeds a manual decision (%d):\n", len(result.Ambiguities))
+			for _, ambiguity := range result.Ambiguities {
+				fmt.Fprintf(w, "  %-5s %-10s %s=%q: %s\n", ambiguity.EntityType, ambiguity.EntityID, ambiguity.Field, ambiguity.Value, ambiguity.Reason)
+			}
+			fmt.Fprintf(w, "\nEpic stays at schema version %d: resolve the cases above and run 'agentpm migrate' again.\n", result.To)
+		}
+	}
+}
+
+func outputMigrateXML(w io.Writer, results []migratedEpic, dryRun bool) {
+	doc := etree.NewDocument()
+	root := doc.CreateElement("migrate")
+	root.CreateAttr("dry_run", strconv.FormatBool(dryRun))
+	root.CreateAttr("schema_version", strconv.Itoa(epic.CurrentSchemaVersion))
+
+	for _, result := range results {
+		epicElem := root.CreateElement("epic")
+		epicElem.CreateAttr("id", result.EpicID)
+		epicElem.CreateAttr("file", result.File)
+		epicElem.CreateAttr("from_version", strconv.Itoa(result.From))
+		epicElem.CreateAttr("to_version", strconv.Itoa(result.To))
+		if result.Backup != "" {
+			epicElem.CreateAttr("backup", result.Backup)
+		}
+
+		for _, step := range result.Steps {
+			stepElem := epicElem.CreateElement("step")
+			stepElem.CreateAttr("version", strconv.Itoa(step.Version))
+			stepElem.CreateAttr("description", step.Description)
+			for _, change := range step.Changes {
+				changeElem := stepElem.CreateElement("change")
+				changeElem.CreateAttr("entity_type", change.EntityType)
+				changeElem.CreateAttr("entity_id", change.EntityID)
+				changeElem.CreateAttr("field", change.Field)
+				changeElem.CreateAttr("from", change.From)
+				changeElem.CreateAttr("to", change.To)
+			}
+		}
+
+		for _, ambiguity := range result.Ambiguities {
+			ambiguityElem := epicElem.CreateElement("ambiguity")
+			ambiguityElem.CreateAttr("entity_type", ambiguity.EntityType)
+			ambiguityElem.CreateAttr("entity_id", ambiguity.EntityID)
+			ambiguityElem.CreateAttr("field", ambiguity.Field)
+			ambiguityElem.CreateAttr("value", ambiguity.Value)
+			ambiguityElem.SetText(ambiguity.Reason)
+		}
+	}
+
+	doc.Indent(4)
+	doc.WriteTo(w)
+}
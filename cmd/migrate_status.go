@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/migrate"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// MigrateStatusCommand rewrites legacy statuses to the unified Epic 13 model
+func MigrateStatusCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "migrate-status",
+		Usage: "Migrate legacy statuses to the unified status model",
+		Description: `Rewrite the statuses of the epic, its phases, tasks and tests to the
+unified Epic 13 model:
+
+  • Legacy spellings become canonical (planning -> pending, active -> wip,
+    done -> completed, ...)
+  • Tests get a test_status that agrees with their status
+    (status="passed" -> status="completed" test_status="done")
+
+Statuses without a clear mapping - unknown values, cancelled phases, tasks on
+hold, tests whose status and test_status disagree - are left untouched and
+reported for a manual decision. Once none are left, the epic is stamped with
+status_model="epic13".
+
+Examples:
+  agentpm migrate-status --dry-run   # Show what would change
+  agentpm migrate-status -f epic-3.xml`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show what would be migrated without making changes",
+			},
+		},
+		Action: migrateStatusAction,
+	}
+}
+
+func migrateStatusAction(ctx context.Context, c *cli.Command) error {
+	// The report below covers everything a deprecation warning would say
+	storage.SetLoadHook(nil)
+
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+
+	fileStorage := storage.NewFileStorage()
+	epicData, err := fileStorage.LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	dryRun := c.Bool("dry-run")
+	report := migrate.Statuses(epicData)
+	if !dryRun && len(report.Changes) > 0 {
+		if err := fileStorage.SaveEpic(epicData, epicFile); err != nil {
+			return fmt.Errorf("failed to save epic: %w", err)
+		}
+	}
+
+	switch c.String("format") {
+	case "json":
+		output := struct {
+			*migrate.Report
+			DryRun bool `json:"dry_run"`
+		}{report, dryRun}
+		jsonData, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal migration report to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		outputMigrateStatusXML(c, report, dryRun)
+	default:
+		outputMigrateStatusText(c, report, dryRun)
+	}
+	return nil
+}
+
+func outputMigrateStatusText(c *cli.Command, report *migrate.Report, dryRun bool) {
+	w := c.Root().Writer
+
+	if len(report.Changes) == 0 && report.Stamped {
+		fmt.Fprintf(w, "Epic %s already uses the unified status model.\n", report.EpicID)
+		return
+	}
+
+	verb := "Migrated"
+	if dryRun {
+		verb = "Would migrate"
+	}
+	fmt.Fprintf(w, "%s epic %s to the unified status model (%d changes)\n", verb, report.EpicID, len(report.Changes))
+	for _, change := range report.Changes {
+		from := change.From
+		if from == "" {
+			from = "(none)"
+		}
+		fmt.Fprintf(w, "  %-5s %-10s %s: %s -> %s\n", change.EntityType, change.EntityID, change.Field, from, change.To)
+	}
+
+	if len(report.Ambiguities) > 0 {
+		fmt.Fprintf(w, "\nNeeds a manual decision (%d):\n", len(report.Ambiguities))
+		for _, ambiguity := range report.Ambiguities {
+			fmt.Fprintf(w, "  %-5s %-10s %s=%q: %s\n", ambiguity.EntityType, ambiguity.EntityID, ambiguity.Field, ambiguity.Value, ambiguity.Reason)
+		}
+		fmt.Fprintf(w, "\nEpic not stamped as migrated: resolve the cases above and run 'agentpm migrate-status' again.\n")
+	}
+}
+
+func outputMigrateStatusXML(c *cli.Command, report *migrate.Report, dryRun bool) {
+	doc := etree.NewDocument()
+	root := doc.CreateElement("migrate_status")
+	root.CreateAttr("epic", report.EpicID)
+	root.CreateAttr("dry_run", strconv.FormatBool(dryRun))
+	root.CreateAttr("stamped", strconv.FormatBool(report.Stamped))
+
+	changesElem := root.CreateElement("changes")
+	for _, change := range report.Changes {
+		changeElem := changesElem.CreateElement("change")
+		changeElem.CreateAttr("entity_type", change.EntityType)
+		changeElem.CreateAttr("entity_id", change.EntityID)
+		changeElem.CreateAttr("field", change.Field)
+		changeElem.CreateAttr("from", change.From)
+		changeElem.CreateAttr("to", change.To)
+	}
+
+	ambiguitiesElem := root.CreateElement("ambiguities")
+	for _, ambiguity := range report.Ambiguities {
+		ambiguityElem := ambiguitiesElem.CreateElement("ambiguity")
+		ambiguityElem.CreateAttr("entity_type", ambiguity.EntityType)
+		ambiguityElem.CreateAttr("entity_id", ambiguity.EntityID)
+		ambiguityElem.CreateAttr("field", ambiguity.Field)
+		ambiguityElem.CreateAttr("value", ambiguity.Value)
+		ambiguityElem.SetText(ambiguity.Reason)
+	}
+
+	doc.Indent(4)
+	doc.WriteTo(c.Root().Writer)
+}
+
+// WarnDeprecations is the root Before hook that warns, once per epic file and
+// invocation, about statuses the unified model no longer supports, instead of
+// silently reading them as something else. --no-deprecation-warnings (or
+// AGENTPM_NO_DEPRECATION_WARNINGS) silences the warnings.
+func WarnDeprecations(ctx context.Context, c *cli.Command) (context.Context, error) {
+	if c.Bool("no-deprecation-warnings") {
+		storage.SetLoadHook(nil)
+		return ctx, nil
+	}
+
+	w := c.Root().ErrWriter
+	if w == nil {
+		w = os.Stderr
+	}
+	format := c.String("format")
+	warned := make(map[string]bool)
+	storage.SetLoadHook(func(path string, epicData *epic.Epic) {
+		if warned[path] {
+			return
+		}
+		warned[path] = true
+
+		deprecations := migrate.Deprecations(epicData)
+		if len(deprecations) == 0 {
+			return
+		}
+		outputDeprecationWarning(w, format, path, deprecations)
+	})
+	return ctx, nil
+}
+
+func outputDeprecationWarning(w io.Writer, format, path string, deprecations []migrate.Deprecation) {
+	migration := fmt.Sprintf("agentpm migrate-status --file %s", path)
+
+	switch format {
+	case "json":
+		warning := struct {
+			File             string                `json:"file"`
+			Entities         []migrate.Deprecation `json:"entities"`
+			MigrationCommand string                `json:"migration_command"`
+		}{path, deprecations, migration}
+		encoder := json.NewEncoder(w)
+		encoder.Encode(map[string]interface{}{"deprecation_warning": warning})
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("deprecation_warning")
+		root.CreateAttr("file", path)
+		root.CreateAttr("migration_command", migration)
+		for _, deprecation := range deprecations {
+			entityElem := root.CreateElement("entity")
+			entityElem.CreateAttr("type", deprecation.EntityType)
+			entityElem.CreateAttr("id", deprecation.EntityID)
+			entityElem.CreateAttr("field", deprecation.Field)
+			entityElem.CreateAttr("value", deprecation.Value)
+			if deprecation.Replacement != "" {
+				entityElem.CreateAttr("replacement", deprecation.Replacement)
+			}
+			if deprecation.Reason != "" {
+				entityElem.SetText(deprecation.Reason)
+			}
+		}
+		doc.Indent(4)
+		doc.WriteTo(w)
+	default:
+		fmt.Fprintf(w, "⚠ Deprecated: %s uses statuses the unified status model no longer supports (%d):\n", path, len(deprecations))
+		for _, deprecation := range deprecations {
+			if deprecation.Replacement != "" {
+				fmt.Fprintf(w, "  %-5s %-10s %s=%q, now %s\n", deprecation.EntityType, deprecation.EntityID, deprecation.Field, deprecation.Value, deprecation.Replacement)
+			} else {
+				fmt.Fprintf(w, "  %-5s %-10s %s=%q: %s\n", deprecation.EntityType, deprecation.EntityID, deprecation.Field, deprecation.Value, deprecation.Reason)
+			}
+		}
+		fmt.Fprintf(w, "  Run '%s' to migrate (--no-deprecation-warnings silences this warning)\n", migration)
+	}
+}
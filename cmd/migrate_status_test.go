@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/migrate"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var migrateStatusApp = appOptions{commands: []func() *cli.Command{MigrateStatusCommand}}
+
+const legacyStatusEpic = `<epic id="8" name="Legacy Epic" status="active" created_at="2025-08-01T09:00:00Z">
+    <phases>
+        <phase id="P1" name="Build" status="planning"/>
+    </phases>
+    <tasks>
+        <task id="T1" phase_id="P1" name="Task 1" status="done"/>
+        <task id="T2" phase_id="P1" name="Task 2" status="on_hold"/>
+    </tasks>
+    <tests>
+        <test id="T1_1" task_id="T1" name="Test 1" status="passed"/>
+    </tests>
+</epic>`
+
+func TestMigrateStatusCommand(t *testing.T) {
+	setup := func(t *testing.T, content string) string {
+		epicFile := filepath.Join(t.TempDir(), "epic.xml")
+		require.NoError(t, os.WriteFile(epicFile, []byte(content), 0644))
+		return epicFile
+	}
+
+	t.Run("dry run reports without writing", func(t *testing.T) {
+		epicFile := setup(t, legacyStatusEpic)
+
+		output, err := runApp(t, migrateStatusApp.withFormat("text"), "migrate-status", "--file", epicFile, "--dry-run")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Would migrate epic 8 to the unified status model (5 changes)")
+		assert.Contains(t, output, "phase P1         status: planning -> pending")
+		assert.Contains(t, output, "test  T1_1       test_status: (none) -> done")
+		assert.Contains(t, output, `task  T2         status="on_hold": tasks cannot be on hold`)
+		assert.Contains(t, output, "Epic not stamped as migrated")
+
+		content, err := os.ReadFile(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, legacyStatusEpic, string(content))
+	})
+
+	t.Run("migrates and stamps once nothing is ambiguous", func(t *testing.T) {
+		epicFile := setup(t, legacyStatusEpic)
+
+		_, err := runApp(t, migrateStatusApp.withFormat("text"), "migrate-status", "--file", epicFile)
+		require.NoError(t, err)
+		epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, epic.StatusCompleted, epicData.Tasks[0].Status)
+		assert.Empty(t, epicData.StatusModel)
+
+		// The manual decision: resume the task on hold
+		epicData.Tasks[1].Status = epic.StatusWIP
+		require.NoError(t, storage.NewFileStorage().SaveEpic(epicData, epicFile))
+
+		output, err := runApp(t, migrateStatusApp.withFormat("text"), "migrate-status", "--file", epicFile)
+		require.NoError(t, err)
+		assert.Contains(t, output, "Migrated epic 8 to the unified status model (1 changes)")
+		content, err := os.ReadFile(epicFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), `status_model="epic13"`)
+
+		output, err = runApp(t, migrateStatusApp.withFormat("text"), "migrate-status", "--file", epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, "Epic 8 already uses the unified status model.\n", output)
+	})
+
+	t.Run("xml output", func(t *testing.T) {
+		epicFile := setup(t, legacyStatusEpic)
+
+		output, err := runApp(t, migrateStatusApp.withFormat("xml"), "migrate-status", "--file", epicFile, "--dry-run")
+		require.NoError(t, err)
+		assert.Contains(t, output, `<migrate_status epic="8" dry_run="true" stamped="false">`)
+		assert.Contains(t, output, `<change entity_type="epic" entity_id="8" field="status" from="active" to="wip"/>`)
+		assert.Contains(t, output, `<ambiguity entity_type="task" entity_id="T2" field="status" value="on_hold">`)
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		epicFile := setup(t, legacyStatusEpic)
+
+		output, err := runApp(t, migrateStatusApp.withFormat("json"), "migrate-status", "--file", epicFile, "--dry-run")
+		require.NoError(t, err)
+		assert.Contains(t, output, `"dry_run": true`)
+		assert.Contains(t, output, `"stamped": false`)
+		assert.Contains(t, output, `"field": "test_status"`)
+	})
+}
+
+func TestWarnDeprecations(t *testing.T) {
+	epicFile := filepath.Join(t.TempDir(), "epic.xml")
+	require.NoError(t, os.WriteFile(epicFile, []byte(legacyStatusEpic), 0644))
+
+	run := func(t *testing.T, args ...string) (string, string) {
+		t.Helper()
+		t.Cleanup(func() { storage.SetLoadHook(nil) })
+
+		app := &cli.Command{
+			Name:   "agentpm",
+			Before: WarnDeprecations,
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config", Value: filepath.Join(t.TempDir(), ".agentpm.json")},
+				&cli.StringFlag{Name: "file", Value: epicFile},
+				&cli.StringFlag{Name: "format", Value: "text"},
+				&cli.BoolFlag{Name: "no-deprecation-warnings"},
+			},
+			Commands: []*cli.Command{
+				{
+					Name: "load-twice",
+					Action: func(ctx context.Context, c *cli.Command) error {
+						for i := 0; i < 2; i++ {
+							if _, err := storage.NewFileStorage().LoadEpic(epicFile); err != nil {
+								return err
+							}
+						}
+						return nil
+					},
+				},
+				MigrateStatusCommand(),
+			},
+		}
+
+		var stdout, stderr bytes.Buffer
+		app.Writer = &stdout
+		app.ErrWriter = &stderr
+		require.NoError(t, app.Run(context.Background(), append([]string{"agentpm"}, args...)))
+		return stdout.String(), stderr.String()
+	}
+
+	t.Run("warns once per command", func(t *testing.T) {
+		_, stderr := run(t, "load-twice")
+		assert.Equal(t, 1, strings.Count(stderr, "⚠ Deprecated:"))
+		assert.Contains(t, stderr, `uses statuses the unified status model no longer supports (5):`)
+		assert.Contains(t, stderr, `test  T1_1       status="passed", now completed`)
+		assert.Contains(t, stderr, `task  T2         status="on_hold": tasks cannot be on hold`)
+		assert.Contains(t, stderr, "Run 'agentpm migrate-status --file ")
+	})
+
+	t.Run("structured output", func(t *testing.T) {
+		_, stderr := run(t, "--format", "json", "load-twice")
+		var warning struct {
+			DeprecationWarning struct {
+				Entities         []migrate.Deprecation `json:"entities"`
+				MigrationCommand string                `json:"migration_command"`
+			} `json:"deprecation_warning"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(stderr), &warning))
+		assert.Len(t, warning.DeprecationWarning.Entities, 5)
+		assert.Contains(t, warning.DeprecationWarning.MigrationCommand, "agentpm migrate-status --file ")
+	})
+
+	t.Run("suppressible", func(t *testing.T) {
+		_, stderr := run(t, "--no-deprecation-warnings", "load-twice")
+		assert.Empty(t, stderr)
+	})
+
+	t.Run("migrate-status reports instead of warning", func(t *testing.T) {
+		stdout, stderr := run(t, "migrate-status", "--file", epicFile, "--dry-run")
+		assert.Empty(t, stderr)
+		assert.Contains(t, stdout, "Would migrate epic 8")
+	})
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var migrateApp = appOptions{commands: []func() *cli.Command{MigrateCommand}}
+
+func TestMigrateCommand(t *testing.T) {
+	setup := func(t *testing.T, content string) (configFile, epicFile string) {
+		dir := t.TempDir()
+		epicFile = filepath.Join(dir, "epic.xml")
+		require.NoError(t, os.WriteFile(epicFile, []byte(content), 0644))
+		return filepath.Join(dir, ".agentpm.json"), epicFile
+	}
+	resolvable := strings.Replace(legacyStatusEpic, `status="on_hold"`, `status="in_progress"`, 1)
+
+	t.Run("dry run reports without writing", func(t *testing.T) {
+		configFile, epicFile := setup(t, resolvable)
+
+		output, err := runApp(t, migrateApp.withConfig(configFile).withFormat("text"), "migrate", "--file", epicFile, "--dry-run")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Would migrate epic 8 ("+epicFile+") from schema version 0 to 2")
+		assert.Contains(t, output, "1. Unify statuses (legacy spellings, test_status): 7 changes")
+		assert.Contains(t, output, "task  T2         status: in_progress -> wip")
+		assert.NotContains(t, output, "Previous version kept")
+
+		content, err := os.ReadFile(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, resolvable, string(content))
+	})
+
+	t.Run("upgrades in place with a backup", func(t *testing.T) {
+		configFile, epicFile := setup(t, resolvable)
+
+		output, err := runApp(t, migrateApp.withConfig(configFile).withFormat("text"), "migrate", "--file", epicFile)
+		require.NoError(t, err)
+		assert.Contains(t, output, "Previous version kept as "+epicFile+".bak.1")
+
+		epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, epic.CurrentSchemaVersion, epicData.SchemaVersion)
+		assert.Equal(t, epic.StatusWIP, epicData.Tasks[1].Status)
+
+		backup, err := os.ReadFile(epicFile + ".bak.1")
+		require.NoError(t, err)
+		assert.Equal(t, resolvable, string(backup))
+
+		output, err = runApp(t, migrateApp.withConfig(configFile).withFormat("text"), "migrate", "--file", epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, "Epic 8 ("+epicFile+") is at schema version 2, nothing to migrate.\n", output)
+	})
+
+	t.Run("ambiguities stop the upgrade", func(t *testing.T) {
+		configFile, epicFile := setup(t, legacyStatusEpic)
+
+		output, err := runApp(t, migrateApp.withConfig(configFile).withFormat("text"), "migrate", "--file", epicFile)
+		require.NoError(t, err)
+		assert.Contains(t, output, "Migrated epic 8 ("+epicFile+"), still at schema version 0")
+		assert.Contains(t, output, `task  T2         status="on_hold": tasks cannot be on hold`)
+		assert.Contains(t, output, "Epic stays at schema version 0")
+
+		epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, 0, epicData.SchemaVersion)
+		assert.Equal(t, epic.StatusCompleted, epicData.Tasks[0].Status, "unambiguous statuses are migrated")
+	})
+
+	t.Run("refuses newer schemas", func(t *testing.T) {
+		configFile, epicFile := setup(t, `<epic id="8" name="Future" status="pending" created_at="2025-08-01T09:00:00Z" schema_version="99"/>`)
+
+		_, err := runApp(t, migrateApp.withConfig(configFile).withFormat("text"), "migrate", "--file", epicFile)
+		assert.ErrorContains(t, err, "schema version 99, newer than version 2")
+	})
+
+	t.Run("all epics as json", func(t *testing.T) {
+		configFile, epicFile := setup(t, resolvable)
+		require.NoError(t, os.WriteFile(configFile, []byte(`{"current_epic": "epic.xml"}`), 0644))
+		require.NoError(t, os.MkdirAll(filepath.Join(filepath.Dir(epicFile), "epics"), 0755))
+		current := `<epic id="9" name="Current" status="pending" created_at="2025-08-01T09:00:00Z" schema_version="2"/>`
+		require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(epicFile), "epics", "current.xml"), []byte(current), 0644))
+
+		output, err := runApp(t, migrateApp.withConfig(configFile).withFormat("json"), "migrate", "--all", "--dry-run")
+		require.NoError(t, err)
+
+		var result struct {
+			DryRun bool `json:"dry_run"`
+			Epics  []struct {
+				File        string `json:"file"`
+				FromVersion int    `json:"from_version"`
+				ToVersion   int    `json:"to_version"`
+			} `json:"epics"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(output), &result), output)
+		assert.True(t, result.DryRun)
+		require.Len(t, result.Epics, 2)
+		assert.Equal(t, "epic.xml", result.Epics[0].File)
+		assert.Equal(t, 2, result.Epics[0].ToVersion)
+		assert.Equal(t, "epics/current.xml", result.Epics[1].File)
+		assert.Equal(t, 2, result.Epics[1].FromVersion)
+	})
+
+	t.Run("xml output", func(t *testing.T) {
+		configFile, epicFile := setup(t, legacyStatusEpic)
+
+		output, err := runApp(t, migrateApp.withConfig(configFile).withFormat("xml"), "migrate", "--file", epicFile, "--dry-run")
+		require.NoError(t, err)
+		assert.Contains(t, output, `<migrate dry_run="true" schema_version="2">`)
+		assert.Contains(t, output, `from_version="0" to_version="0">`)
+		assert.Contains(t, output, `<step version="1" description="Unify statuses (legacy spellings, test_status)">`)
+		assert.Contains(t, output, `<ambiguity entity_type="task" entity_id="T2" field="status" value="on_hold">`)
+	})
+}
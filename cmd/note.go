@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/commands"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// NoteCommand leaves notes on the epic, phases, tasks and tests
+func NoteCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "note",
+		Usage: "Leave notes on the epic, a phase, a task or a test",
+		Description: `Record context that does not fit an event: why a task took a detour,
+what the next agent should watch out for, where a test's fixtures come from.
+Notes are kept on the entity with their author and time, shown by
+'agentpm show <type> <id> --full' and listed in 'agentpm handoff'.
+
+Subcommands:
+  add epic <text>           Leave a note on the current epic
+  add <type> <id> <text>    Leave a note on a phase, task or test
+
+Examples:
+  agentpm note add task 1A_1 "Uses the v2 endpoint, v1 is deprecated"
+  agentpm note add test 1A_1_T1 "Needs the sandbox account from .env.test"
+  agentpm note add epic "Scope agreed with the API team on 2025-08-16"`,
+		Flags: commands.GlobalFlags(),
+		Commands: []*cli.Command{
+			{
+				Name:      "add",
+				Usage:     "Leave a note",
+				ArgsUsage: "<epic|phase|task|test> [id] <text>",
+				Action:    withQuietResult(noteAddAction),
+			},
+		},
+	}
+}
+
+func noteAddAction(ctx context.Context, c *cli.Command) error {
+	args := c.Args().Slice()
+	if len(args) == 0 {
+		return exitcode.Errorf(exitcode.Validation, "note add requires an entity type: epic, phase, task or test")
+	}
+	entityType, args := args[0], args[1:]
+
+	var id string
+	switch entityType {
+	case epic.NoteOnEpic:
+	case epic.NoteOnPhase, epic.NoteOnTask, epic.NoteOnTest:
+		if len(args) == 0 {
+			return exitcode.Errorf(exitcode.Validation, "note add %s requires a %s ID", entityType, entityType)
+		}
+		id, args = args[0], args[1:]
+	default:
+		return exitcode.Errorf(exitcode.Validation, "invalid entity type %q (expected epic, phase, task or test)", entityType)
+	}
+	text := strings.Join(args, " ")
+	if strings.TrimSpace(text) == "" {
+		return exitcode.Errorf(exitcode.Validation, "note add requires the text of the note")
+	}
+
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now()
+	if timeStr := c.String("time"); timeStr != "" {
+		timestamp, err = time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+		}
+	}
+
+	storageImpl := storage.NewFileStorage()
+	epicData, err := storageImpl.LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	note, err := service.AddNote(epicData, entityType, id, text, timestamp)
+	if err != nil {
+		return err
+	}
+	if entityType == epic.NoteOnEpic {
+		id = epicData.ID
+	}
+
+	if err := storageImpl.SaveEpic(epicData, epicFile); err != nil {
+		return fmt.Errorf("failed to save epic: %w", err)
+	}
+	return writeNoteResult(c, entityType, id, note)
+}
+
+func writeNoteResult(c *cli.Command, entityType, id string, note *epic.Note) error {
+	w := c.Root().Writer
+	switch c.String("format") {
+	case "json":
+		output := struct {
+			Operation  string `json:"operation"`
+			EntityType string `json:"entity_type"`
+			ID         string `json:"id"`
+			*epic.Note
+		}{"note_added", entityType, id, note}
+		jsonData, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal result to JSON: %w", err)
+		}
+		fmt.Fprintf(w, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("note_added")
+		root.CreateAttr("entity_type", entityType)
+		root.CreateAttr("id", id)
+		writeNoteElement(root.CreateElement("note"), *note)
+		doc.Indent(4)
+		doc.WriteTo(w)
+	default:
+		label := strings.ToUpper(entityType[:1]) + entityType[1:]
+		fmt.Fprintf(w, "Note added to %s %s.\n", label, id)
+	}
+	return nil
+}
+
+// writeNoteElement fills a <note author="..." created_at="...">text</note> element
+func writeNoteElement(elem *etree.Element, note epic.Note) {
+	if note.Author != "" {
+		elem.CreateAttr("author", note.Author)
+	}
+	elem.CreateAttr("created_at", note.CreatedAt.Format(time.RFC3339))
+	elem.SetText(note.Text)
+}
+
+// formatNote renders a note on one line: [2025-08-16 15:30 agent_a] text
+func formatNote(note epic.Note) string {
+	header := note.CreatedAt.Format("2006-01-02 15:04")
+	if note.Author != "" {
+		header += " " + note.Author
+	}
+	return fmt.Sprintf("[%s] %s", header, note.Text)
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var noteApp = appOptions{commands: []func() *cli.Command{NoteCommand, ShowCommand, HandoffCommand}}
+
+func TestNoteCommand(t *testing.T) {
+	epicFile := filepath.Join(t.TempDir(), "epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(createEpicForReset(), epicFile))
+	notedAt := "2025-08-16T15:30:00Z"
+
+	t.Run("adds notes", func(t *testing.T) {
+		out, err := runApp(t, noteApp, "note", "--file", epicFile, "--time", notedAt, "add", "task", "T1", "Uses the", "v2 endpoint")
+		require.NoError(t, err)
+		assert.Equal(t, "Note added to Task T1.\n", out)
+
+		out, err = runApp(t, noteApp, "note", "--file", epicFile, "--time", notedAt, "--format", "xml", "add", "epic", "Scope <agreed>")
+		require.NoError(t, err)
+		assert.Contains(t, out, `<note_added entity_type="epic" id="epic-1">`)
+		assert.Contains(t, out, `Scope &lt;agreed&gt;</note>`)
+
+		_, err = runApp(t, noteApp, "note", "--file", epicFile, "--time", notedAt, "add", "test", "T1_T1", "Needs fixtures")
+		require.NoError(t, err)
+
+		updated, err := storage.NewFileStorage().LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, []epic.Note{{CreatedAt: time.Date(2025, 8, 16, 15, 30, 0, 0, time.UTC), Text: "Uses the v2 endpoint"}}, updated.Tasks[0].Notes)
+		assert.Len(t, updated.Notes, 1)
+		assert.Equal(t, "note_added", updated.Events[len(updated.Events)-1].Type)
+	})
+
+	t.Run("validates arguments", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"add"},
+			{"add", "task"},
+			{"add", "task", "T1"},
+			{"add", "sprint", "S1", "text"},
+		} {
+			_, err := runApp(t, noteApp, append([]string{"note", "--file", epicFile}, args...)...)
+			require.Error(t, err, args)
+			assert.Equal(t, exitcode.Validation, ExitCode(err), args)
+		}
+
+		_, err := runApp(t, noteApp, "note", "--file", epicFile, "add", "task", "T9", "text")
+		require.Error(t, err)
+		assert.Equal(t, exitcode.NotFound, ExitCode(err))
+	})
+
+	t.Run("show --full", func(t *testing.T) {
+		out, err := runApp(t, noteApp, "show", "--file", epicFile, "task", "T1", "--full")
+		require.NoError(t, err)
+		assert.Contains(t, out, "Notes (1):\n  [2025-08-16 15:30] Uses the v2 endpoint")
+
+		out, err = runApp(t, noteApp, "show", "--file", epicFile, "test", "T1_T1", "--full", "--format", "xml")
+		require.NoError(t, err)
+		assert.Contains(t, out, `<note created_at="2025-08-16T15:30:00Z">Needs fixtures</note>`)
+
+		out, err = runApp(t, noteApp, "show", "--file", epicFile, "epic", "--full")
+		require.NoError(t, err)
+		assert.Contains(t, out, "Notes (1):\n  [2025-08-16 15:30] Scope <agreed>")
+
+		out, err = runApp(t, noteApp, "show", "--file", epicFile, "task", "T1")
+		require.NoError(t, err)
+		assert.NotContains(t, out, "Notes")
+	})
+
+	t.Run("handoff", func(t *testing.T) {
+		out, err := runApp(t, noteApp, "handoff", "--file", epicFile, "--format", "text")
+		require.NoError(t, err)
+		assert.Contains(t, out, "NOTES (3):\n  [2025-08-16 15:30] epic epic-1: Scope <agreed>\n  [2025-08-16 15:30] task T1: Uses the v2 endpoint\n")
+
+		out, err = runApp(t, noteApp, "handoff", "--file", epicFile)
+		require.NoError(t, err)
+		assert.Contains(t, out, `<note entity_type="epic" entity_id="epic-1" created_at="2025-08-16T15:30:00Z">Scope &lt;agreed&gt;</note>`)
+	})
+}
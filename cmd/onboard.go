@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/onboard"
+	"github.com/mindreframer/agentpm/internal/policy"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// OnboardCommand prints the orientation for an agent joining the project
+func OnboardCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "onboard",
+		Usage: "Orientation for a new agent: workflow, gates, goals and the first command",
+		Description: `Explain how this project works to an agent starting on it, generated
+from the config, the policy file and the current epic:
+
+  - the workflow settings (workflow mode, test gating, strict mode, gate tags)
+  - the gates completions have to pass: gating tests, strict mode, manual
+    tests that need --verified-by, policy rules and frozen phases
+  - how phases, tasks and tests are named in the epic
+  - the goals of the epic and how far it got
+  - the spec documents its phases and tasks reference
+  - the exact command to run first
+
+Orchestration prompts can include the output instead of hard-coding it.
+
+Examples:
+  agentpm onboard
+  agentpm onboard --format json`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config",
+			},
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"F"},
+				Usage:   "Output format: text (default), json, xml",
+				Value:   "text",
+			},
+		},
+		Action: onboardAction,
+	}
+}
+
+func onboardAction(ctx context.Context, c *cli.Command) error {
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+
+	// Without a config file the defaults apply
+	cfg, err := config.LoadEffectiveConfig(c.String("config"), epicFile)
+	if err != nil {
+		if code, ok := exitcode.Of(err); !ok || code != exitcode.NotFound {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		cfg = nil
+	}
+	var rules *policy.Policy
+	if cfg != nil {
+		if rules, err = loadPolicy(cfg); err != nil {
+			return err
+		}
+	}
+
+	epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	guide := onboard.Build(cfg, rules, epicData, epicFile)
+
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(guide, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal onboarding guide to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		outputOnboardXML(c, guide)
+	default:
+		outputOnboardText(c, guide)
+	}
+	return nil
+}
+
+func outputOnboardText(c *cli.Command, guide *onboard.Guide) {
+	w := c.Root().Writer
+	goals := guide.Epic
+
+	if guide.Project != "" {
+		fmt.Fprintf(w, "Project: %s\n", guide.Project)
+	}
+	fmt.Fprintf(w, "Epic: %s (%s), %s\n", goals.Name, goals.ID, goals.Status)
+	fmt.Fprintf(w, "Epic file: %s\n", guide.EpicFile)
+	fmt.Fprintf(w, "Progress: %d of %d tasks completed in %d phases, %d tests, %d open blockers\n",
+		goals.CompletedTasks, goals.Tasks, goals.Phases, goals.Tests, goals.OpenBlockers)
+	if goals.CurrentTask != "" {
+		fmt.Fprintf(w, "In progress: phase %s, task %s\n", goals.CurrentPhase, goals.CurrentTask)
+	} else if goals.CurrentPhase != "" {
+		fmt.Fprintf(w, "In progress: phase %s\n", goals.CurrentPhase)
+	}
+
+	section := func(title, text string) {
+		if text != "" {
+			fmt.Fprintf(w, "\n%s:\n%s\n", title, text)
+		}
+	}
+	section("Goal", goals.Description)
+	section("Requirements", goals.Requirements)
+	section("Workflow", goals.Workflow)
+
+	fmt.Fprintf(w, "\nSettings:\n")
+	for _, setting := range guide.Settings {
+		overridden := ""
+		if setting.Overridden {
+			overridden = " [epic override]"
+		}
+		fmt.Fprintf(w, "  %s = %s%s\n      %s\n", setting.Key, setting.Value, overridden, setting.Meaning)
+	}
+
+	list := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "\n%s:\n", title)
+		for _, item := range items {
+			fmt.Fprintf(w, "  - %s\n", item)
+		}
+	}
+	list("Gates", guide.Gates)
+	list("Naming", guide.Naming)
+
+	if len(guide.Specs) > 0 {
+		fmt.Fprintf(w, "\nSpecs:\n")
+		for _, spec := range guide.Specs {
+			fmt.Fprintf(w, "  - %s (%d references)\n", spec.Path, spec.Refs)
+		}
+	}
+
+	fmt.Fprintf(w, "\nFirst command: %s\n", guide.FirstCommand.Command)
+	fmt.Fprintf(w, "  %s\n", guide.FirstCommand.Reason)
+}
+
+func outputOnboardXML(c *cli.Command, guide *onboard.Guide) {
+	doc := etree.NewDocument()
+	root := doc.CreateElement("onboard")
+	if guide.Project != "" {
+		root.CreateAttr("project", guide.Project)
+	}
+	root.CreateAttr("epic_file", guide.EpicFile)
+
+	goals := guide.Epic
+	epicElem := root.CreateElement("epic")
+	epicElem.CreateAttr("id", goals.ID)
+	epicElem.CreateAttr("name", goals.Name)
+	epicElem.CreateAttr("status", goals.Status)
+	epicElem.CreateAttr("phases", strconv.Itoa(goals.Phases))
+	epicElem.CreateAttr("tasks", strconv.Itoa(goals.Tasks))
+	epicElem.CreateAttr("completed_tasks", strconv.Itoa(goals.CompletedTasks))
+	epicElem.CreateAttr("tests", strconv.Itoa(goals.Tests))
+	epicElem.CreateAttr("open_blockers", strconv.Itoa(goals.OpenBlockers))
+	if goals.CurrentPhase != "" {
+		epicElem.CreateAttr("current_phase", goals.CurrentPhase)
+	}
+	if goals.CurrentTask != "" {
+		epicElem.CreateAttr("current_task", goals.CurrentTask)
+	}
+	for _, field := range []struct{ name, text string }{
+		{"description", goals.Description},
+		{"requirements", goals.Requirements},
+		{"workflow", goals.Workflow},
+	} {
+		if field.text != "" {
+			epicElem.CreateElement(field.name).SetText(field.text)
+		}
+	}
+
+	settingsElem := root.CreateElement("settings")
+	for _, setting := range guide.Settings {
+		settingElem := settingsElem.CreateElement("setting")
+		settingElem.CreateAttr("key", setting.Key)
+		settingElem.CreateAttr("value", setting.Value)
+		if setting.Overridden {
+			settingElem.CreateAttr("overridden", "true")
+		}
+		settingElem.SetText(setting.Meaning)
+	}
+
+	gatesElem := root.CreateElement("gates")
+	for _, gate := range guide.Gates {
+		gatesElem.CreateElement("gate").SetText(gate)
+	}
+	namingElem := root.CreateElement("naming")
+	for _, convention := range guide.Naming {
+		namingElem.CreateElement("convention").SetText(convention)
+	}
+	specsElem := root.CreateElement("specs")
+	for _, spec := range guide.Specs {
+		specElem := specsElem.CreateElement("spec")
+		specElem.CreateAttr("path", spec.Path)
+		specElem.CreateAttr("refs", strconv.Itoa(spec.Refs))
+	}
+
+	first := root.CreateElement("first_command")
+	first.CreateAttr("command", guide.FirstCommand.Command)
+	first.SetText(guide.FirstCommand.Reason)
+
+	doc.Indent(4)
+	doc.WriteTo(c.Root().Writer)
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/onboard"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestOnboardCommand(t *testing.T) {
+	setup := func(t *testing.T) (dir, configPath string) {
+		dir = t.TempDir()
+		require.NoError(t, storage.NewFileStorage().SaveEpic(createEpicForSplit(), filepath.Join(dir, "epic-9.xml")))
+		configPath = filepath.Join(dir, ".agentpm.json")
+		cfg := config.DefaultConfig()
+		cfg.CurrentEpic = "epic-9.xml"
+		cfg.ProjectName = "Reporting"
+		cfg.Strict = true
+		require.NoError(t, config.SaveConfig(cfg, configPath))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, ".agentpm"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".agentpm", "policy.yaml"),
+			[]byte("rules:\n  - id: tested\n    check: task_min_tests\n    description: Every task has a test\n"), 0644))
+		return dir, configPath
+	}
+	run := func(configPath string, args ...string) (string, error) {
+		app := &cli.Command{
+			Name:     "agentpm",
+			Flags:    []cli.Flag{&cli.StringFlag{Name: "config"}},
+			Commands: []*cli.Command{OnboardCommand()},
+		}
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		err := app.Run(context.Background(), append([]string{"agentpm", "--config", configPath, "onboard"}, args...))
+		return stdout.String(), err
+	}
+
+	t.Run("text orientation", func(t *testing.T) {
+		_, configPath := setup(t)
+		output, err := run(configPath)
+		require.NoError(t, err)
+		assert.Contains(t, output, "Project: Reporting")
+		assert.Contains(t, output, "Epic: Large Epic (epic-9), wip")
+		assert.Contains(t, output, "Progress: 1 of 3 tasks completed in 3 phases, 1 tests, 0 open blockers")
+		assert.Contains(t, output, "  strict = true\n")
+		assert.Contains(t, output, "  - Strict mode: 'agentpm done task' refuses")
+		assert.Contains(t, output, "  - Policy rule tested (error): Every task has a test")
+		assert.Contains(t, output, "  - Task IDs start with the ID of their phase")
+		assert.Contains(t, output, "First command: agentpm start-next\n")
+	})
+
+	t.Run("json orientation", func(t *testing.T) {
+		dir, configPath := setup(t)
+		output, err := run(configPath, "--format", "json")
+		require.NoError(t, err)
+
+		var guide onboard.Guide
+		require.NoError(t, json.Unmarshal([]byte(output), &guide))
+		assert.Equal(t, filepath.Join(dir, "epic-9.xml"), guide.EpicFile)
+		assert.Equal(t, "epic-9", guide.Epic.ID)
+		assert.Equal(t, "agentpm start-next", guide.FirstCommand.Command)
+	})
+
+	t.Run("xml orientation", func(t *testing.T) {
+		_, configPath := setup(t)
+		output, err := run(configPath, "--format", "xml")
+		require.NoError(t, err)
+		assert.Contains(t, output, `<onboard project="Reporting"`)
+		assert.Contains(t, output, `<setting key="strict" value="true">`)
+		assert.Contains(t, output, `<first_command command="agentpm start-next">`)
+	})
+
+	t.Run("works without a config file", func(t *testing.T) {
+		dir := t.TempDir()
+		epicFile := filepath.Join(dir, "epic.xml")
+		require.NoError(t, storage.NewFileStorage().SaveEpic(createEpicForSplit(), epicFile))
+		output, err := run(filepath.Join(dir, ".agentpm.json"), "--file", epicFile)
+		require.NoError(t, err)
+		assert.Contains(t, output, "  strict = false\n")
+		assert.NotContains(t, output, "Policy rule")
+	})
+}
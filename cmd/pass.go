@@ -11,26 +11,46 @@ import (
 func PassCommand() *cli.Command {
 	return &cli.Command{
 		Name:      "pass",
-		Usage:     "Mark a test as passed",
-		ArgsUsage: "<test-id>",
+		Usage:     "Mark tests as passed",
+		ArgsUsage: "<test-id>...",
 		Description: `Mark a test as passed (transitions from wip to passed).
 
 The test must exist in the current epic and be in a valid state to pass.
 
+Several tests can be passed at once, given as arguments or read with
+--from-file. All of them are checked first: if any test cannot be passed,
+none is changed and every failing test is reported. Otherwise all tests are
+passed and the epic is saved once.
+
+Manual tests (type="manual" in the epic) are checked by a person, not by an
+agent: they only pass with --verified-by naming who checked them. The
+verifier and the optional --evidence link are recorded on the test.
+
 Examples:
   agentpm pass 3A_T1                    # Pass test 3A_T1
+  agentpm pass 3A_T1 3A_T2 3A_T3        # Pass several tests
+  agentpm pass --from-file passed.txt   # Pass the tests listed in a file
+  agentpm pass 3A_T4 --verified-by alice --evidence https://example.com/shot.png
   agentpm pass 1B_T2 --time 2025-08-16T15:30:00Z # Pass with specific timestamp`,
-		Flags:  commands.GlobalFlags(),
-		Action: passAction,
+		Flags:  append(commands.GlobalFlags(), append(verificationFlags(), fromFileFlag())...),
+		Action: withQuietResult(passAction),
 	}
 }
 
 func passAction(ctx context.Context, c *cli.Command) error {
-	if c.Args().Len() < 1 {
+	fileIDs, err := idsFromFile(c)
+	if err != nil {
+		return err
+	}
+	testIDs := uniqueIDs(append(c.Args().Slice(), fileIDs...))
+	if len(testIDs) == 0 {
 		return fmt.Errorf("test ID is required")
 	}
+	if len(testIDs) > 1 {
+		return passTestsAction(c, testIDs)
+	}
 
-	testID := c.Args().First()
+	testID := testIDs[0]
 
 	// Extract router context
 	routerCtx := commands.ExtractRouterContext(c)
@@ -38,6 +58,8 @@ func passAction(ctx context.Context, c *cli.Command) error {
 	// Create test request
 	request := commands.TestRequest{
 		TestID:     testID,
+		VerifiedBy: c.String("verified-by"),
+		Evidence:   c.String("evidence"),
 		ConfigPath: routerCtx.ConfigPath,
 		EpicFile:   routerCtx.EpicFile,
 		Time:       routerCtx.Time,
@@ -52,13 +74,54 @@ func passAction(ctx context.Context, c *cli.Command) error {
 
 	// Handle service result
 	if result.Error != nil {
-		return fmt.Errorf("%s", result.Error.Message)
+		return serviceError(result.Error.Type, result.Error.Message, result.Error.Hint)
 	}
 
 	// Output success message based on result
 	if result.Result != nil {
-		fmt.Printf("Test %s passed.\n", testID)
+		fmt.Fprintf(c.Root().Writer, "Test %s passed.\n", testID)
 	}
 
-	return nil
+	return routerCtx.WriteWarnings(result.Warnings)
+}
+
+// passTestsAction passes several tests in one load/save cycle
+func passTestsAction(c *cli.Command, testIDs []string) error {
+	routerCtx := commands.ExtractRouterContext(c)
+	result, err := commands.PassBatchTestService(commands.BatchTestRequest{
+		TestIDs:    testIDs,
+		Operation:  "pass",
+		VerifiedBy: c.String("verified-by"),
+		Evidence:   c.String("evidence"),
+		ConfigPath: routerCtx.ConfigPath,
+		EpicFile:   routerCtx.EpicFile,
+		Time:       routerCtx.Time,
+		Format:     routerCtx.Format,
+	})
+	if err != nil {
+		return err
+	}
+	if result.Error != nil {
+		return serviceError(result.Error.Type, result.Error.Message, result.Error.Hint)
+	}
+
+	for _, op := range result.Result.SuccessfulOperations {
+		fmt.Fprintf(c.Root().Writer, "Test %s passed.\n", op.TestID)
+	}
+	return routerCtx.WriteWarnings(result.Warnings)
+}
+
+// verificationFlags are the flags recording who checked a test, required to
+// pass or fail a manual test
+func verificationFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "verified-by",
+			Usage: "Name of the person who checked the test (required for manual tests)",
+		},
+		&cli.StringFlag{
+			Name:  "evidence",
+			Usage: "Link to evidence of the check, e.g. a screenshot or ticket",
+		},
+	}
 }
@@ -60,22 +60,22 @@ func passBatchAction(ctx context.Context, c *cli.Command) error {
 
 	// Handle service result
 	if result.Error != nil {
-		return fmt.Errorf("%s", result.Error.Message)
+		return serviceError(result.Error.Type, result.Error.Message, "")
 	}
 
 	// Output success message
 	if result.Result != nil {
 		summary := result.Result.Summary
-		fmt.Printf("Batch pass completed successfully:\n")
-		fmt.Printf("- %d tests passed\n", summary.PassedTests)
+		fmt.Fprintf(c.Root().Writer, "Batch pass completed successfully:\n")
+		fmt.Fprintf(c.Root().Writer, "- %d tests passed\n", summary.PassedTests)
 
 		if len(result.Result.SuccessfulOperations) > 0 {
-			fmt.Printf("\nPassed tests:\n")
+			fmt.Fprintf(c.Root().Writer, "\nPassed tests:\n")
 			for _, op := range result.Result.SuccessfulOperations {
 				if op.TestName != "" {
-					fmt.Printf("  %s (%s)\n", op.TestID, op.TestName)
+					fmt.Fprintf(c.Root().Writer, "  %s (%s)\n", op.TestID, op.TestName)
 				} else {
-					fmt.Printf("  %s\n", op.TestID)
+					fmt.Fprintf(c.Root().Writer, "  %s\n", op.TestID)
 				}
 			}
 		}
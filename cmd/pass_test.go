@@ -16,8 +16,8 @@ func TestPassCommand_Structure(t *testing.T) {
 		t.Error("expected non-empty usage")
 	}
 
-	if cmd.ArgsUsage != "<test-id>" {
-		t.Errorf("expected ArgsUsage '<test-id>', got %s", cmd.ArgsUsage)
+	if cmd.ArgsUsage != "<test-id>..." {
+		t.Errorf("expected ArgsUsage '<test-id>...', got %s", cmd.ArgsUsage)
 	}
 
 	if cmd.Description == "" {
@@ -101,7 +101,7 @@ func TestPassCommand_Arguments(t *testing.T) {
 	}
 
 	// Test that it expects test ID
-	if cmd.ArgsUsage != "<test-id>" {
-		t.Errorf("expected args usage '<test-id>', got '%s'", cmd.ArgsUsage)
+	if cmd.ArgsUsage != "<test-id>..." {
+		t.Errorf("expected args usage '<test-id>...', got '%s'", cmd.ArgsUsage)
 	}
 }
@@ -2,9 +2,13 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/output"
 	"github.com/mindreframer/agentpm/internal/query"
 	"github.com/mindreframer/agentpm/internal/storage"
 	"github.com/urfave/cli/v3"
@@ -16,7 +20,16 @@ func PendingCommand() *cli.Command {
 		Name:    "pending",
 		Usage:   "Display pending work across all phases",
 		Aliases: []string{"p"},
-		Action:  pendingAction,
+		Description: `Display the phases, tasks and tests that are not completed yet.
+
+With --assignee only the work of one agent is shown: the tasks assigned to
+it (directly, through their phase or through the epic), their tests and
+its phases. See 'agentpm assign'.
+
+Examples:
+  agentpm pending
+  agentpm pending --assignee agent_b --format json`,
+		Action: pendingAction,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:    "file",
@@ -26,9 +39,10 @@ func PendingCommand() *cli.Command {
 			&cli.StringFlag{
 				Name:    "format",
 				Aliases: []string{"F"},
-				Usage:   "Output format: text (default), json, xml",
+				Usage:   "Output format: text (default), json, jsonl, xml",
 				Value:   "text",
 			},
+			assigneeFlag(),
 		},
 	}
 }
@@ -48,10 +62,10 @@ func pendingAction(ctx context.Context, c *cli.Command) error {
 	// Determine epic file (command flag overrides config)
 	epicFile := c.String("file")
 	if epicFile == "" {
-		epicFile = cfg.CurrentEpic
+		epicFile = cfg.EpicFilePath()
 	}
 	if epicFile == "" {
-		return fmt.Errorf("no epic file specified. Use --file flag or run 'agentpm init' first")
+		return exitcode.Errorf(exitcode.Validation, "no epic file specified. Use --file flag or run 'agentpm init' first")
 	}
 
 	// Create storage and query service
@@ -63,6 +77,11 @@ func pendingAction(ctx context.Context, c *cli.Command) error {
 	if err != nil {
 		return fmt.Errorf("failed to load epic: %w", err)
 	}
+	if agent := c.String("assignee"); agent != "" {
+		if err := queryService.FilterAssignee(agent); err != nil {
+			return err
+		}
+	}
 
 	// Get pending work
 	pending, err := queryService.GetPendingWork()
@@ -77,6 +96,8 @@ func pendingAction(ctx context.Context, c *cli.Command) error {
 		return outputPendingXML(c, pending)
 	case "json":
 		return outputPendingJSON(c, pending)
+	case "jsonl":
+		return outputPendingJSONL(c, pending)
 	default:
 		return outputPendingText(c, pending)
 	}
@@ -101,7 +122,7 @@ func outputPendingText(c *cli.Command, pending *query.PendingWork) error {
 		fmt.Fprintf(c.Root().Writer, "  (none)\n")
 	} else {
 		for _, task := range pending.Tasks {
-			fmt.Fprintf(c.Root().Writer, "  %s (%s) - %s [%s]\n", task.ID, task.PhaseID, task.Name, task.Status)
+			fmt.Fprintf(c.Root().Writer, "  %s (%s) - %s [%s]%s\n", task.ID, task.PhaseID, task.Name, task.Status, assigneeSuffix(task.Assignee))
 		}
 	}
 
@@ -118,45 +139,84 @@ func outputPendingText(c *cli.Command, pending *query.PendingWork) error {
 	return nil
 }
 
+// pendingOutput is the JSON output of pending
+type pendingOutput struct {
+	Phases []pendingItem `json:"phases"`
+	Tasks  []pendingItem `json:"tasks"`
+	Tests  []pendingItem `json:"tests"`
+}
+
+// pendingItem is a pending phase, task or test; phases have no phase_id and
+// phases and tasks no task_id
+type pendingItem struct {
+	ID       string      `json:"id"`
+	TaskID   string      `json:"task_id,omitempty"`
+	PhaseID  string      `json:"phase_id,omitempty"`
+	Name     string      `json:"name"`
+	Status   epic.Status `json:"status"`
+	Assignee string      `json:"assignee,omitempty"`
+}
+
 func outputPendingJSON(c *cli.Command, pending *query.PendingWork) error {
-	fmt.Fprintf(c.Root().Writer, "{\n")
-	fmt.Fprintf(c.Root().Writer, "  \"phases\": [\n")
+	jsonData, err := json.MarshalIndent(newPendingOutput(pending), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal pending work to JSON: %w", err)
+	}
+	fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	return nil
+}
 
-	for i, phase := range pending.Phases {
-		comma := ""
-		if i < len(pending.Phases)-1 {
-			comma = ","
-		}
-		fmt.Fprintf(c.Root().Writer, "    {\"id\": \"%s\", \"name\": \"%s\", \"status\": \"%s\"}%s\n",
-			phase.ID, phase.Name, phase.Status, comma)
+func newPendingOutput(pending *query.PendingWork) pendingOutput {
+	output := pendingOutput{
+		Phases: make([]pendingItem, 0, len(pending.Phases)),
+		Tasks:  make([]pendingItem, 0, len(pending.Tasks)),
+		Tests:  make([]pendingItem, 0, len(pending.Tests)),
 	}
+	for _, phase := range pending.Phases {
+		output.Phases = append(output.Phases, pendingItem{ID: phase.ID, Name: phase.Name, Status: phase.Status, Assignee: phase.Assignee})
+	}
+	for _, task := range pending.Tasks {
+		output.Tasks = append(output.Tasks, pendingItem{ID: task.ID, PhaseID: task.PhaseID, Name: task.Name, Status: task.Status, Assignee: task.Assignee})
+	}
+	for _, test := range pending.Tests {
+		output.Tests = append(output.Tests, pendingItem{ID: test.ID, TaskID: test.TaskID, PhaseID: test.PhaseID, Name: test.Name, Status: test.Status})
+	}
+	return output
+}
 
-	fmt.Fprintf(c.Root().Writer, "  ],\n")
-	fmt.Fprintf(c.Root().Writer, "  \"tasks\": [\n")
+// pendingRecord is one line of the JSONL pending output
+type pendingRecord struct {
+	Type     string      `json:"type"`
+	ID       string      `json:"id"`
+	TaskID   string      `json:"task_id,omitempty"`
+	PhaseID  string      `json:"phase_id,omitempty"`
+	Name     string      `json:"name"`
+	Status   epic.Status `json:"status"`
+	Assignee string      `json:"assignee,omitempty"`
+}
 
-	for i, task := range pending.Tasks {
-		comma := ""
-		if i < len(pending.Tasks)-1 {
-			comma = ","
+// outputPendingJSONL streams one line per pending phase, task and test
+func outputPendingJSONL(c *cli.Command, pending *query.PendingWork) error {
+	lines := output.NewJSONLWriter(c.Root().Writer)
+
+	for _, phase := range pending.Phases {
+		if err := lines.Write(pendingRecord{Type: "phase", ID: phase.ID, Name: phase.Name, Status: phase.Status, Assignee: phase.Assignee}); err != nil {
+			return err
 		}
-		fmt.Fprintf(c.Root().Writer, "    {\"id\": \"%s\", \"phase_id\": \"%s\", \"name\": \"%s\", \"status\": \"%s\"}%s\n",
-			task.ID, task.PhaseID, task.Name, task.Status, comma)
 	}
 
-	fmt.Fprintf(c.Root().Writer, "  ],\n")
-	fmt.Fprintf(c.Root().Writer, "  \"tests\": [\n")
+	for _, task := range pending.Tasks {
+		if err := lines.Write(pendingRecord{Type: "task", ID: task.ID, PhaseID: task.PhaseID, Name: task.Name, Status: task.Status, Assignee: task.Assignee}); err != nil {
+			return err
+		}
+	}
 
-	for i, test := range pending.Tests {
-		comma := ""
-		if i < len(pending.Tests)-1 {
-			comma = ","
+	for _, test := range pending.Tests {
+		if err := lines.Write(pendingRecord{Type: "test", ID: test.ID, TaskID: test.TaskID, PhaseID: test.PhaseID, Name: test.Name, Status: test.Status}); err != nil {
+			return err
 		}
-		fmt.Fprintf(c.Root().Writer, "    {\"id\": \"%s\", \"task_id\": \"%s\", \"phase_id\": \"%s\", \"name\": \"%s\", \"status\": \"%s\"}%s\n",
-			test.ID, test.TaskID, test.PhaseID, test.Name, test.Status, comma)
 	}
 
-	fmt.Fprintf(c.Root().Writer, "  ]\n")
-	fmt.Fprintf(c.Root().Writer, "}\n")
 	return nil
 }
 
@@ -165,15 +225,15 @@ func outputPendingXML(c *cli.Command, pending *query.PendingWork) error {
 
 	fmt.Fprintf(c.Root().Writer, "    <phases>\n")
 	for _, phase := range pending.Phases {
-		fmt.Fprintf(c.Root().Writer, "        <phase id=\"%s\" name=\"%s\" status=\"%s\"/>\n",
-			phase.ID, phase.Name, phase.Status)
+		fmt.Fprintf(c.Root().Writer, "        <phase id=\"%s\" name=\"%s\" status=\"%s\"%s/>\n",
+			phase.ID, phase.Name, phase.Status, assigneeAttr(phase.Assignee))
 	}
 	fmt.Fprintf(c.Root().Writer, "    </phases>\n")
 
 	fmt.Fprintf(c.Root().Writer, "    <tasks>\n")
 	for _, task := range pending.Tasks {
-		fmt.Fprintf(c.Root().Writer, "        <task id=\"%s\" phase_id=\"%s\" status=\"%s\">%s</task>\n",
-			task.ID, task.PhaseID, task.Status, task.Name)
+		fmt.Fprintf(c.Root().Writer, "        <task id=\"%s\" phase_id=\"%s\" status=\"%s\"%s>%s</task>\n",
+			task.ID, task.PhaseID, task.Status, assigneeAttr(task.Assignee), task.Name)
 	}
 	fmt.Fprintf(c.Root().Writer, "    </tasks>\n")
 
@@ -3,8 +3,10 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -396,3 +398,26 @@ func TestPendingCommandPerformance(t *testing.T) {
 		t.Logf("Pending command executed in: %v", duration)
 	})
 }
+
+func TestPendingCommandJSONL(t *testing.T) {
+	tempDir := t.TempDir()
+	epicPath := filepath.Join(tempDir, "test-epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(createTestEpicForPending(), epicPath))
+
+	var stdout, stderr bytes.Buffer
+	cmd := PendingCommand()
+	cmd.Root().Writer = &stdout
+	cmd.Root().ErrWriter = &stderr
+
+	err := cmd.Run(context.Background(), []string{"pending", "--file", epicPath, "--format", "jsonl"})
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.NotEmpty(t, lines)
+	for _, line := range lines {
+		assert.True(t, json.Valid([]byte(line)), "each line is a JSON object: %s", line)
+	}
+	assert.Contains(t, lines, `{"type":"phase","id":"P2","name":"Implementation Phase","status":"wip"}`)
+	assert.Contains(t, lines, `{"type":"task","id":"T3","phase_id":"P2","name":"Pending Task 1","status":"pending"}`)
+	assert.Contains(t, lines, `{"type":"test","id":"TEST3","task_id":"T3","phase_id":"P2","name":"Pending Test 1","status":"pending"}`)
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 
 	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/query"
+	"github.com/mindreframer/agentpm/internal/storage"
 	"github.com/mindreframer/agentpm/internal/xmlquery"
 	"github.com/urfave/cli/v3"
 )
@@ -13,8 +16,8 @@ import (
 func QueryCommand() *cli.Command {
 	return &cli.Command{
 		Name:      "query",
-		Usage:     "Execute XPath queries against epic XML files",
-		ArgsUsage: "<xpath-expression>",
+		Usage:     "Execute XPath queries or entity selectors against epic XML files",
+		ArgsUsage: "<xpath-expression|selector>",
 		Description: `Execute XPath queries against epic XML files using etree syntax.
 
 This command allows you to extract specific information from epic files using
@@ -32,7 +35,35 @@ Query patterns:
   //task[1]                       - Position-based selection
   //epic/*                        - All child elements
 
-Output formats: xml (default), text, json
+Selectors are a shorter way to pick phases, tasks or tests by their state:
+an entity name followed by conditions in brackets, all of which must hold.
+
+  tasks[status=wip][phase=2A]     - Active tasks of phase 2A
+  tests[failed]                   - Tests whose last run failed
+  tasks[status=pending,wip]       - Tasks not started or in progress
+  phases[status!=done]            - Phases not done yet
+  tasks[name~=login]              - Tasks with "login" in their name
+  tests[task=2A_1][passing]       - Passing tests of task 2A_1
+  tasks[assignee=agent_b]         - Tasks of agent_b (effective assignee)
+  tasks[custom.sprint>=7]         - Tasks of sprint 7 and later
+
+Fields: id, name, status, assignee (all), phase (tasks, tests), task and
+result (tests). Comma separated values are alternatives. A bare word is a
+status (pending, wip, done, on_hold, cancelled) or a test result (passing,
+failing; also passed, failed).
+
+Custom fields declared in custom_fields of the config are written
+custom.<name> and compared by their type: integers by number, dates in time,
+enums in their declared order. They also support <, <=, > and >=.
+
+With --all a selector is matched against every epic of the workspace (see
+'agentpm epics'), read through the storage_backend of the config; XPath
+queries take a single epic file.
+
+With --assignee only matches within the phases, tasks and tests of one agent
+are kept; matches outside of them, e.g. events, are dropped.
+
+Output formats: xml (default), text, json, jsonl
 
 Examples:
   agentpm query "//task"                          # All tasks
@@ -40,8 +71,13 @@ Examples:
   agentpm query "//task[@phase_id='1A']"         # Tasks in phase 1A
   agentpm query "//metadata/assignee"            # Epic assignee
   agentpm query "//phase[@status='wip']"         # Active phases
+  agentpm query "//task[@depends_on]"            # Tasks with dependencies
   agentpm query "//test[@status='passing']"      # Passing tests
   agentpm query "//task[@status='done']" --format text  # Text output
+  agentpm query "//task" --assignee agent_b      # Tasks of agent_b
+  agentpm query "tasks[status=wip][phase=2A]"    # Selector: active tasks in 2A
+  agentpm query "tests[failed]" --format json    # Selector: failing tests
+  agentpm query "tasks[status=wip]" --all        # Active tasks of all epics
   agentpm query "//phase" -f epic-9.xml          # Query different file`,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
@@ -52,11 +88,17 @@ Examples:
 			&cli.StringFlag{
 				Name:    "format",
 				Aliases: []string{"F"},
-				Usage:   "Output format: xml (default), text, json",
+				Usage:   "Output format: xml (default), text, json, jsonl",
 				Value:   "xml",
 			},
+			fieldsFlag(),
+			assigneeFlag(),
+			&cli.BoolFlag{
+				Name:  "all",
+				Usage: "Match a selector against every epic of the workspace",
+			},
 		},
-		Action: queryAction,
+		Action: withFieldSelection(queryAction),
 	}
 }
 
@@ -70,7 +112,17 @@ func queryAction(ctx context.Context, c *cli.Command) error {
 	// Determine epic file (prioritize command flag)
 	epicFile := c.String("file")
 
-	if epicFile == "" {
+	var workspace *config.Config
+	if c.Bool("all") {
+		if !query.IsSelector(xpathExpr) {
+			return exitcode.Errorf(exitcode.Validation, "--all takes a selector like tasks[status=wip], not an XPath expression")
+		}
+		cfg, err := config.LoadConfig(c.String("config"))
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		workspace = cfg
+	} else if epicFile == "" {
 		// Only load config if file is not explicitly provided
 		configPath := c.String("config")
 		if configPath == "" {
@@ -82,9 +134,9 @@ func queryAction(ctx context.Context, c *cli.Command) error {
 			return fmt.Errorf("failed to load configuration: %w", err)
 		}
 
-		epicFile = cfg.CurrentEpic
+		epicFile = cfg.EpicFilePath()
 		if epicFile == "" {
-			return fmt.Errorf("no epic file specified. Use --file flag or run 'agentpm init' first")
+			return exitcode.Errorf(exitcode.Validation, "no epic file specified. Use --file flag or run 'agentpm init' first")
 		}
 	}
 
@@ -98,20 +150,48 @@ func queryAction(ctx context.Context, c *cli.Command) error {
 		format = xmlquery.FormatText
 	case "json":
 		format = xmlquery.FormatJSON
+	case "jsonl":
+		format = xmlquery.FormatJSONL
 	default:
-		return fmt.Errorf("invalid output format: %s (must be xml, text, or json)", outputFormat)
+		return fmt.Errorf("invalid output format: %s (must be xml, text, json, or jsonl)", outputFormat)
 	}
 
 	// Create query service
 	service := xmlquery.NewService()
 
-	// Validate query syntax first
-	if err := service.ValidateQuery(xpathExpr); err != nil {
-		return fmt.Errorf("invalid XPath query: %w", err)
+	var result *xmlquery.QueryResult
+	var err error
+	if workspace != nil {
+		result, err = selectEntitiesAcross(c, service, workspace, xpathExpr)
+		if err != nil {
+			return err
+		}
+	} else if query.IsSelector(xpathExpr) {
+		result, err = selectEntities(c, service, epicFile, xpathExpr)
+		if err != nil {
+			return err
+		}
+	} else {
+		// Validate query syntax first
+		if err := service.ValidateQuery(xpathExpr); err != nil {
+			return fmt.Errorf("invalid XPath query: %w", err)
+		}
+
+		result, err = service.QueryEpicFile(epicFile, xpathExpr)
+		if err != nil {
+			return fmt.Errorf("query execution failed: %w", err)
+		}
+	}
+	if agent := c.String("assignee"); agent != "" {
+		service.FilterAssignee(result, agent)
+	}
+
+	// JSON Lines are streamed match by match instead of building a document
+	if format == xmlquery.FormatJSONL {
+		return xmlquery.WriteJSONL(c.Root().Writer, result)
 	}
 
-	// Execute query with formatting
-	output, err := service.QueryEpicFileFormatted(epicFile, xpathExpr, format)
+	output, err := xmlquery.NewFormatter(format).Format(result)
 	if err != nil {
 		return fmt.Errorf("query execution failed: %w", err)
 	}
@@ -124,3 +204,94 @@ func queryAction(ctx context.Context, c *cli.Command) error {
 
 	return nil
 }
+
+// parseSelector parses a selector; custom fields are compared by the types
+// the config declares for them
+func parseSelector(c *cli.Command, expr string) (*query.Selector, error) {
+	sel, err := query.ParseSelector(expr)
+	if err != nil {
+		return nil, err
+	}
+	if sel.HasCustomFields() {
+		schema, err := customFieldSchema(c)
+		if err != nil {
+			return nil, err
+		}
+		if err := sel.ResolveCustomFields(schema); err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %w", expr, err)
+		}
+	}
+	return sel, nil
+}
+
+// selectEntities resolves a selector like tasks[status=wip] against the epic
+// and returns the matching elements of the epic file
+func selectEntities(c *cli.Command, service *xmlquery.Service, epicFile, expr string) (*xmlquery.QueryResult, error) {
+	sel, err := parseSelector(c, expr)
+	if err != nil {
+		return nil, err
+	}
+
+	queryService := query.NewQueryService(storage.NewFileStorage())
+	if err := queryService.LoadEpic(epicFile); err != nil {
+		return nil, fmt.Errorf("query execution failed: %w", err)
+	}
+	matches, err := queryService.Select(sel)
+	if err != nil {
+		return nil, fmt.Errorf("query execution failed: %w", err)
+	}
+
+	ids := make([]string, len(matches))
+	for i, match := range matches {
+		ids[i] = match.ID
+	}
+	result, err := service.SelectEpicElements(epicFile, expr, sel.Entity, ids)
+	if err != nil {
+		return nil, fmt.Errorf("query execution failed: %w", err)
+	}
+	return result, nil
+}
+
+// selectEntitiesAcross resolves a selector against every epic of the workspace
+// through the storage backend of the config and returns the matching
+// elements, epic by epic. Only epic files with matches are read for their
+// elements.
+func selectEntitiesAcross(c *cli.Command, service *xmlquery.Service, cfg *config.Config, expr string) (*xmlquery.QueryResult, error) {
+	sel, err := parseSelector(c, expr)
+	if err != nil {
+		return nil, err
+	}
+	epicFiles := cfg.EpicFilePaths()
+	backend, err := storage.OpenBackend(cfg.StorageBackend, cfg.StorageDBPath())
+	if err != nil {
+		return nil, err
+	}
+	defer backend.Close()
+
+	matches, err := query.SelectAcross(backend, epicFiles, sel)
+	if err != nil {
+		return nil, fmt.Errorf("query execution failed: %w", err)
+	}
+	idsByFile := make(map[string][]string)
+	for _, match := range matches {
+		idsByFile[match.Path] = append(idsByFile[match.Path], match.ID)
+	}
+
+	result := &xmlquery.QueryResult{Query: expr}
+	for _, epicFile := range epicFiles {
+		ids, ok := idsByFile[epicFile]
+		if !ok {
+			continue
+		}
+		found, err := service.SelectEpicElements(epicFile, expr, sel.Entity, ids)
+		if err != nil {
+			return nil, fmt.Errorf("query execution failed: %w", err)
+		}
+		result.Elements = append(result.Elements, found.Elements...)
+	}
+	result.MatchCount = len(result.Elements)
+	if result.IsEmpty() {
+		result.Message = "No elements found matching query"
+	}
+	return result, nil
+}
@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/customfields"
 	"github.com/mindreframer/agentpm/internal/epic"
 	"github.com/mindreframer/agentpm/internal/storage"
 	"github.com/stretchr/testify/assert"
@@ -167,3 +169,90 @@ func TestQueryCommandFixed(t *testing.T) {
 		assert.Contains(t, err.Error(), "query execution failed")
 	})
 }
+
+func TestQuerySelectors(t *testing.T) {
+	epicPath := setupTestEpicForQuery(t)
+
+	runQuery := func(t *testing.T, args ...string) (string, error) {
+		t.Helper()
+		var stdout bytes.Buffer
+		app := &cli.Command{
+			Name:     "agentpm",
+			Writer:   &stdout,
+			Commands: []*cli.Command{QueryCommand()},
+		}
+		err := app.Run(context.Background(), append([]string{"agentpm", "query", "-f", epicPath}, args...))
+		return stdout.String(), err
+	}
+
+	t.Run("text", func(t *testing.T) {
+		output, err := runQuery(t, "tasks[status=wip][phase=10B]", "--format", "text")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Query: tasks[status=wip][phase=10B]")
+		assert.Contains(t, output, "Found 1 matches")
+		assert.Contains(t, output, "10B_1")
+		assert.NotContains(t, output, "10A_1")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		output, err := runQuery(t, "tests[done]", "--format", "json")
+		require.NoError(t, err)
+
+		var result map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.Equal(t, float64(1), result["match_count"])
+		assert.Contains(t, output, `"test_1"`)
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		output, err := runQuery(t, "phases[status!=done]")
+		require.NoError(t, err)
+		assert.Contains(t, output, "<query>phases[status!=done]</query>")
+		assert.Contains(t, output, `<attr name="id" value="10B"></attr>`)
+		assert.NotContains(t, output, `value="10A"`)
+	})
+
+	t.Run("invalid selector", func(t *testing.T) {
+		_, err := runQuery(t, "tasks[result=failing]")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown field "result" for tasks`)
+	})
+}
+
+func TestQuerySelectorsCustomFields(t *testing.T) {
+	dir := t.TempDir()
+	epicFile := filepath.Join(dir, "epic.xml")
+	testEpic := createEpicForReset()
+	testEpic.Tasks[0].Custom = epic.CustomFields{{Name: "sprint", Value: "9"}}
+	testEpic.Tasks[1].Custom = epic.CustomFields{{Name: "sprint", Value: "10"}}
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicFile))
+
+	configPath := filepath.Join(dir, ".agentpm.json")
+	cfg := config.DefaultConfig()
+	cfg.CurrentEpic = "epic.xml"
+	cfg.CustomFields = customfields.Schema{"task": {"sprint": {Type: customfields.TypeInt}}}
+	require.NoError(t, config.SaveConfig(cfg, configPath))
+
+	runQuery := func(t *testing.T, args ...string) (string, error) {
+		t.Helper()
+		var stdout bytes.Buffer
+		app := &cli.Command{
+			Name:     "agentpm",
+			Writer:   &stdout,
+			Flags:    []cli.Flag{&cli.StringFlag{Name: "config"}},
+			Commands: []*cli.Command{QueryCommand()},
+		}
+		err := app.Run(context.Background(), append([]string{"agentpm", "--config", configPath, "query"}, args...))
+		return stdout.String(), err
+	}
+
+	// Alphabetically "10" < "9"; as declared integers 10 comes after 9
+	output, err := runQuery(t, "tasks[custom.sprint>9]", "--format", "text")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Found 1 matches")
+	assert.Contains(t, output, "id=T2")
+
+	_, err = runQuery(t, "tasks[custom.sprint=next]")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid selector "tasks[custom.sprint=next]": custom.sprint: invalid value "next"`)
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/commands"
+	"github.com/mindreframer/agentpm/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// quietResult is the single line a mutating command prints with --quiet
+type quietResult struct {
+	Status   string             `json:"status"`
+	Command  string             `json:"command"`
+	Warnings []commands.Warning `json:"warnings,omitempty"`
+}
+
+// withQuietResult wraps a mutating command action so that with --quiet it prints
+// nothing but one machine-readable result line on success. Messages, hints and
+// error details are suppressed; a failure is reported through the returned error
+// and the exit code only. Warnings of the mutation are kept in the JSON and XML
+// result lines.
+//
+// During a dry run (see ApplyDryRun) the wrapped action saves nothing and the
+// changes it would have saved are printed instead of its output.
+func withQuietResult(action cli.ActionFunc) cli.ActionFunc {
+	return func(ctx context.Context, c *cli.Command) error {
+		if dryRunChanges != nil {
+			return runDryRun(ctx, c, action)
+		}
+		if !c.Bool("quiet") {
+			return action(ctx, c)
+		}
+
+		root := c.Root()
+		writer := root.Writer
+		cmdWriter, cmdErrWriter, rootErrWriter := c.Writer, c.ErrWriter, root.ErrWriter
+
+		// Subcommands write to their own writers as well as to the root writers
+		commands.ClearWarnings(c)
+		c.Writer, c.ErrWriter = io.Discard, io.Discard
+		root.Writer, root.ErrWriter = io.Discard, io.Discard
+		err := action(ctx, c)
+		root.Writer, root.ErrWriter = writer, rootErrWriter
+		c.Writer, c.ErrWriter = cmdWriter, cmdErrWriter
+
+		if err != nil {
+			return err
+		}
+		return writeQuietResult(writer, c)
+	}
+}
+
+func writeQuietResult(w io.Writer, c *cli.Command) error {
+	result := quietResult{Status: "ok", Command: quietCommandLine(c), Warnings: commands.RecordedWarnings(c)}
+
+	switch c.String("format") {
+	case "json", "jsonl":
+		return output.NewJSONLWriter(w).Write(result)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("result")
+		root.CreateAttr("status", result.Status)
+		root.CreateAttr("command", result.Command)
+		commands.WarningsXML(root, result.Warnings)
+		if _, err := doc.WriteTo(w); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintln(w)
+		return err
+	default:
+		_, err := fmt.Fprintf(w, "%s %s\n", result.Status, result.Command)
+		return err
+	}
+}
+
+// quietCommandLine names the command and its arguments without the program name,
+// e.g. "start task 1A_1"
+func quietCommandLine(c *cli.Command) string {
+	name := c.FullName()
+	if root := c.Root(); root != c {
+		name = strings.TrimPrefix(name, root.Name+" ")
+	}
+	return strings.Join(append([]string{name}, c.Args().Slice()...), " ")
+}
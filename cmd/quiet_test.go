@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+// quietTime is the time the quiet tests start tasks at
+const quietTime = "2025-08-16T10:00:00Z"
+
+var quietApp = appOptions{commands: []func() *cli.Command{StartCommand}}
+
+const quietTestEpic = `<?xml version="1.0" encoding="UTF-8"?>
+<epic id="quiet-epic" name="Quiet Epic" status="wip" created_at="2025-08-16T09:00:00Z">
+    <phases>
+        <phase id="P1" name="Phase 1" status="wip"/>
+    </phases>
+    <tasks>
+        <task id="T1" phase_id="P1" name="Task 1" status="pending"/>
+    </tasks>
+    <tests/>
+    <events/>
+</epic>
+`
+
+func TestQuietResult(t *testing.T) {
+	setup := func(t *testing.T) string {
+		epicPath := filepath.Join(t.TempDir(), "epic.xml")
+		require.NoError(t, os.WriteFile(epicPath, []byte(quietTestEpic), 0644))
+		return epicPath
+	}
+
+	t.Run("without quiet the normal message is printed", func(t *testing.T) {
+		stdout, _, err := runAppOutput(t, quietApp, "start", "task", "T1", "--file", setup(t), "--time", quietTime)
+		require.NoError(t, err)
+		assert.Equal(t, "Task T1 started.\n", stdout)
+	})
+
+	t.Run("text result line", func(t *testing.T) {
+		stdout, stderr, err := runAppOutput(t, quietApp, "start", "task", "T1", "--quiet", "--file", setup(t), "--time", quietTime)
+		require.NoError(t, err)
+		assert.Equal(t, "ok start task T1\n", stdout)
+		assert.Empty(t, stderr)
+	})
+
+	t.Run("json result line", func(t *testing.T) {
+		stdout, _, err := runAppOutput(t, quietApp, "start", "task", "T1", "-q", "--format", "json", "--file", setup(t), "--time", quietTime)
+		require.NoError(t, err)
+		assert.Equal(t, "{\"status\":\"ok\",\"command\":\"start task T1\"}\n", stdout)
+	})
+
+	t.Run("xml result line", func(t *testing.T) {
+		stdout, _, err := runAppOutput(t, quietApp, "start", "task", "T1", "-q", "--format", "xml", "--file", setup(t), "--time", quietTime)
+		require.NoError(t, err)
+		assert.Equal(t, "<result status=\"ok\" command=\"start task T1\"/>\n", stdout)
+	})
+
+	t.Run("failure prints nothing and returns the error", func(t *testing.T) {
+		stdout, stderr, err := runAppOutput(t, quietApp, "start", "task", "missing", "--quiet", "--file", setup(t), "--time", quietTime)
+		require.Error(t, err)
+		assert.Empty(t, stdout)
+		assert.Empty(t, stderr)
+	})
+}
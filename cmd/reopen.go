@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/commands"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+type reopenFunc func(e *epic.Epic, id, reason string, timestamp time.Time) (*service.ReopenResult, error)
+
+// ReopenCommand brings cancelled and completed work back
+func ReopenCommand() *cli.Command {
+	reasonFlag := func() []cli.Flag {
+		return []cli.Flag{
+			&cli.StringFlag{
+				Name:  "reason",
+				Usage: "Why the work is reopened (recorded in the event log)",
+			},
+		}
+	}
+
+	return &cli.Command{
+		Name:  "reopen",
+		Usage: "Reopen a cancelled or completed task, phase or epic",
+		Description: `Bring cancelled or completed work back.
+
+A cancelled task or phase goes back to pending; a completed task or phase
+goes back to wip, with a warning since its work was considered done. Tests
+keep their results. Reopening a task of a completed phase reopens the phase,
+and reopening anything of a completed epic reopens the epic. Every reopened
+entity gets a task_reopened, phase_reopened or epic_reopened event carrying
+the reason.
+
+Completed work can only be reopened when nothing else is in progress: no
+other task of the phase, and no other phase when a phase has to be reopened.
+
+Subcommands:
+  task <id>     Reopen a task
+  phase <id>    Reopen a phase
+  epic          Reopen the completed epic
+
+Examples:
+  agentpm reopen task 2A_1 --reason "Descoped by mistake"
+  agentpm reopen task 1A_3 --reason "Regression in the login flow"
+  agentpm reopen epic --reason "Follow-up work"`,
+		Flags: commands.GlobalFlags(),
+		Commands: []*cli.Command{
+			{
+				Name:      "task",
+				Usage:     "Reopen a cancelled or completed task",
+				ArgsUsage: "<task-id>",
+				Flags:     reasonFlag(),
+				Action:    withQuietResult(reopenAction("task", service.ReopenTask)),
+			},
+			{
+				Name:      "phase",
+				Usage:     "Reopen a cancelled or completed phase",
+				ArgsUsage: "<phase-id>",
+				Flags:     reasonFlag(),
+				Action:    withQuietResult(reopenAction("phase", service.ReopenPhase)),
+			},
+			{
+				Name:  "epic",
+				Usage: "Reopen the completed epic",
+				Flags: reasonFlag(),
+				Action: withQuietResult(reopenAction("epic", func(e *epic.Epic, _, reason string, timestamp time.Time) (*service.ReopenResult, error) {
+					return service.ReopenEpic(e, reason, timestamp)
+				})),
+			},
+		},
+	}
+}
+
+func reopenAction(entityType string, reopen reopenFunc) cli.ActionFunc {
+	return func(ctx context.Context, c *cli.Command) error {
+		var id string
+		if entityType != "epic" {
+			if c.Args().Len() != 1 {
+				return exitcode.Errorf(exitcode.Validation, "%s requires exactly one argument", entityType)
+			}
+			id = c.Args().First()
+		} else if c.Args().Len() > 0 {
+			return exitcode.Errorf(exitcode.Validation, "epic takes no arguments, it reopens the current epic")
+		}
+
+		epicFile, err := getEpicFile(c)
+		if err != nil {
+			return err
+		}
+
+		timestamp := time.Now()
+		if timeStr := c.String("time"); timeStr != "" {
+			timestamp, err = time.Parse(time.RFC3339, timeStr)
+			if err != nil {
+				return fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+			}
+		}
+
+		storageImpl := storage.NewFileStorage()
+		epicData, err := storageImpl.LoadEpic(epicFile)
+		if err != nil {
+			return fmt.Errorf("failed to load epic: %w", err)
+		}
+
+		result, err := reopen(epicData, id, c.String("reason"), timestamp)
+		if err != nil {
+			return err
+		}
+
+		if err := storageImpl.SaveEpic(epicData, epicFile); err != nil {
+			return fmt.Errorf("failed to save epic: %w", err)
+		}
+		return writeReopenResult(c, epicData, result)
+	}
+}
+
+func writeReopenResult(c *cli.Command, epicData *epic.Epic, result *service.ReopenResult) error {
+	operation := result.EntityType + "_reopened"
+
+	switch c.String("format") {
+	case "json":
+		output := struct {
+			Operation string `json:"operation"`
+			*service.ReopenResult
+		}{operation, result}
+		jsonData, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal result to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		fmt.Fprintf(c.Root().Writer, "<%s id=\"%s\" previous_status=\"%s\" status=\"%s\"", operation, result.ID, result.PreviousStatus, result.Status)
+		if result.ReopenedPhase != "" {
+			fmt.Fprintf(c.Root().Writer, " reopened_phase=\"%s\"", result.ReopenedPhase)
+		}
+		fmt.Fprintf(c.Root().Writer, " reopened_epic=\"%t\"", result.ReopenedEpic)
+		if result.Warning != "" {
+			fmt.Fprintf(c.Root().Writer, ">\n    <warning>%s</warning>\n</%s>\n", result.Warning, operation)
+		} else {
+			fmt.Fprintf(c.Root().Writer, "/>\n")
+		}
+	default:
+		label := map[string]string{"task": "Task", "phase": "Phase", "epic": "Epic"}[result.EntityType]
+		fmt.Fprintf(c.Root().Writer, "%s %s reopened as %s.\n", label, result.ID, result.Status)
+		if result.ReopenedPhase != "" {
+			fmt.Fprintf(c.Root().Writer, "Phase %s reopened as wip.\n", result.ReopenedPhase)
+		}
+		if result.ReopenedEpic && result.EntityType != "epic" {
+			fmt.Fprintf(c.Root().Writer, "Epic %s reopened as wip.\n", epicData.ID)
+		}
+		if result.Warning != "" {
+			fmt.Fprintf(c.Root().Writer, "Warning: %s\n", result.Warning)
+		}
+	}
+	return nil
+}
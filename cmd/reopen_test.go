@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReopenCommand(t *testing.T) {
+	run := func(t *testing.T, epicFile string, args ...string) (string, error) {
+		var stdout bytes.Buffer
+		cmd := ReopenCommand()
+		cmd.Root().Writer = &stdout
+		err := cmd.Run(context.Background(), append([]string{"reopen", "--file", epicFile, "--time", "2025-08-16T15:30:00Z"}, args...))
+		return stdout.String(), err
+	}
+
+	epicFile := filepath.Join(t.TempDir(), "epic.xml")
+	fileStorage := storage.NewFileStorage()
+	testEpic := createEpicForReset()
+	testEpic.Status = epic.StatusCompleted
+	testEpic.Phases[0].Status = epic.StatusCompleted
+	testEpic.Tasks[0].Status = epic.StatusCompleted
+	testEpic.Tasks[1].Status = epic.StatusCancelled
+	require.NoError(t, fileStorage.SaveEpic(testEpic, epicFile))
+
+	t.Run("cancelled task", func(t *testing.T) {
+		output, err := run(t, epicFile, "task", "--reason", "Needed after all", "T2")
+		require.NoError(t, err)
+		assert.Equal(t, "Task T2 reopened as pending.\nPhase P1 reopened as wip.\nEpic epic-1 reopened as wip.\n", output)
+
+		updated, err := fileStorage.LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, epic.StatusWIP, updated.Status)
+		assert.Equal(t, epic.StatusWIP, updated.Phases[0].Status)
+		assert.Equal(t, epic.StatusPending, updated.Tasks[1].Status)
+		lastEvent := updated.Events[len(updated.Events)-1]
+		assert.Equal(t, "task_reopened", lastEvent.Type)
+		assert.Equal(t, "Task T2 (Task 2) reopened as pending: Needed after all", lastEvent.Data)
+	})
+
+	t.Run("completed task warns", func(t *testing.T) {
+		output, err := run(t, epicFile, "--format", "json", "task", "T1")
+		require.NoError(t, err)
+
+		var result map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.Equal(t, "task_reopened", result["operation"])
+		assert.Equal(t, "completed", result["previous_status"])
+		assert.Equal(t, "wip", result["status"])
+		assert.Equal(t, false, result["reopened_epic"])
+		assert.Contains(t, result["warning"], "was completed")
+	})
+
+	t.Run("rejects work that is not done", func(t *testing.T) {
+		_, err := run(t, epicFile, "task", "T1")
+		require.Error(t, err)
+		assert.Equal(t, exitcode.Constraint, ExitCode(err))
+		assert.Contains(t, err.Error(), "only cancelled and completed tasks can be reopened")
+
+		_, err = run(t, epicFile, "epic")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "only completed epics can be reopened")
+	})
+
+	t.Run("requires an ID", func(t *testing.T) {
+		_, err := run(t, epicFile, "phase")
+		require.Error(t, err)
+		assert.Equal(t, exitcode.Validation, ExitCode(err))
+	})
+}
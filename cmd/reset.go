@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/commands"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/phases"
+	"github.com/mindreframer/agentpm/internal/query"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// ResetCommand groups administrative reset operations
+func ResetCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "reset",
+		Usage: "Reset a phase and its work back to pending",
+		Description: `Administrative recovery for when work got into a bad state.
+
+Subcommands:
+  phase <id> --to pending --confirm    Reset a phase with all its tasks and tests
+
+Without --confirm only a preview of the affected entities is printed.
+Every reset entity gets its own event in the epic's event log.
+
+Examples:
+  agentpm reset phase 2A                       # Preview the reset
+  agentpm reset phase 2A --to pending --confirm  # Reset phase 2A`,
+		Flags: commands.GlobalFlags(),
+		Commands: []*cli.Command{
+			resetPhaseSubcommand(),
+		},
+	}
+}
+
+func resetPhaseSubcommand() *cli.Command {
+	return &cli.Command{
+		Name:      "phase",
+		Usage:     "Reset a phase with all its tasks and tests",
+		ArgsUsage: "<phase-id>",
+		Description: `Reset the phase, all of its tasks and all of its tests to pending.
+
+Timestamps, test results and failure notes are cleared. If the phase or one
+of its tasks is currently active, it is no longer active afterwards.`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "to",
+				Usage: "Target status (only 'pending' is supported)",
+				Value: "pending",
+			},
+			&cli.BoolFlag{
+				Name:  "confirm",
+				Usage: "Apply the reset (without it only a preview is shown)",
+			},
+			&cli.StringFlag{
+				Name:  "reason",
+				Usage: "Reason recorded in the event log",
+			},
+		},
+		Action: withQuietResult(resetPhaseAction),
+	}
+}
+
+func resetPhaseAction(ctx context.Context, c *cli.Command) error {
+	if c.Args().Len() != 1 {
+		return exitcode.Errorf(exitcode.Validation, "phase requires exactly one argument")
+	}
+	phaseID := c.Args().First()
+
+	if target := c.String("to"); target != string(epic.StatusPending) {
+		return exitcode.Errorf(exitcode.Validation, "unsupported reset target: %s (only 'pending' is supported)", target)
+	}
+
+	return runPhaseBulkOperation(c, phaseID,
+		func(ps *phases.PhaseService, e *epic.Epic) (*phases.PhaseBulkResult, error) {
+			return ps.PreviewPhaseReset(e, phaseID)
+		},
+		func(ps *phases.PhaseService, e *epic.Epic, reason string, ts time.Time) (*phases.PhaseBulkResult, error) {
+			return ps.ResetPhase(e, phaseID, reason, ts)
+		})
+}
+
+type phaseBulkPreview func(*phases.PhaseService, *epic.Epic) (*phases.PhaseBulkResult, error)
+type phaseBulkApply func(*phases.PhaseService, *epic.Epic, string, time.Time) (*phases.PhaseBulkResult, error)
+
+// runPhaseBulkOperation loads the epic, previews or applies a bulk phase operation and prints the result.
+// Without --confirm the epic is left untouched and an error is returned after the preview.
+func runPhaseBulkOperation(c *cli.Command, phaseID string, preview phaseBulkPreview, apply phaseBulkApply) error {
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now()
+	if timeStr := c.String("time"); timeStr != "" {
+		timestamp, err = time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+		}
+	}
+
+	storageImpl := storage.NewFileStorage()
+	queryService := query.NewQueryService(storageImpl)
+	phaseService := phases.NewPhaseService(storageImpl, queryService)
+
+	epicData, err := storageImpl.LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	format := c.String("format")
+
+	if !c.Bool("confirm") {
+		result, err := preview(phaseService, epicData)
+		if err != nil {
+			return err
+		}
+		if err := writePhaseBulkResult(c, format, result, false); err != nil {
+			return err
+		}
+		return exitcode.Errorf(exitcode.Validation, "refusing to modify phase %s without --confirm", phaseID)
+	}
+
+	result, err := apply(phaseService, epicData, c.String("reason"), timestamp)
+	if err != nil {
+		return err
+	}
+
+	if !result.IsEmpty() {
+		if err := storageImpl.SaveEpic(epicData, epicFile); err != nil {
+			return fmt.Errorf("failed to save epic: %w", err)
+		}
+	}
+
+	return writePhaseBulkResult(c, format, result, true)
+}
+
+func writePhaseBulkResult(c *cli.Command, format string, result *phases.PhaseBulkResult, applied bool) error {
+	switch format {
+	case "json":
+		output := map[string]interface{}{
+			"phase_id":      result.PhaseID,
+			"operation":     result.Operation,
+			"applied":       applied,
+			"phase_changed": result.PhaseChanged,
+			"tasks":         result.Tasks,
+			"tests":         result.Tests,
+		}
+		if result.Operation == phases.OperationCancelPendingInPhase {
+			output["stranded"] = strandedOrEmpty(result.Stranded)
+		}
+		jsonData, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal result to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		output := fmt.Sprintf(`<%s phase="%s" applied="%t">
+    <phase_changed>%t</phase_changed>
+    <tasks count="%d">`, result.Operation, result.PhaseID, applied, result.PhaseChanged, len(result.Tasks))
+		for _, taskID := range result.Tasks {
+			output += fmt.Sprintf(`
+        <task id="%s"/>`, taskID)
+		}
+		output += fmt.Sprintf(`
+    </tasks>
+    <tests count="%d">`, len(result.Tests))
+		for _, testID := range result.Tests {
+			output += fmt.Sprintf(`
+        <test id="%s"/>`, testID)
+		}
+		output += `
+    </tests>`
+		if result.Operation == phases.OperationCancelPendingInPhase {
+			output += "\n" + strandedXML(result.Stranded)
+		}
+		output += fmt.Sprintf(`
+</%s>`, result.Operation)
+		fmt.Fprintf(c.Root().Writer, "%s\n", output)
+	default:
+		verb := "reset to pending"
+		if result.Operation == phases.OperationCancelPendingInPhase {
+			verb = "cancelled"
+		}
+
+		if result.IsEmpty() {
+			fmt.Fprintf(c.Root().Writer, "Nothing to change in phase %s.\n", result.PhaseID)
+			return nil
+		}
+
+		if applied {
+			fmt.Fprintf(c.Root().Writer, "Phase %s: %d task(s) and %d test(s) %s.\n",
+				result.PhaseID, len(result.Tasks), len(result.Tests), verb)
+			writeStrandedWarning(c.Root().Writer, result.Stranded)
+		} else {
+			fmt.Fprintf(c.Root().Writer, "Preview for phase %s (re-run with --confirm to apply):\n", result.PhaseID)
+			if result.PhaseChanged {
+				fmt.Fprintf(c.Root().Writer, "  Phase %s would be %s\n", result.PhaseID, verb)
+			}
+			if len(result.Tasks) > 0 {
+				fmt.Fprintf(c.Root().Writer, "  Tasks would be %s: %s\n", verb, strings.Join(result.Tasks, ", "))
+			}
+			if len(result.Tests) > 0 {
+				fmt.Fprintf(c.Root().Writer, "  Tests would be %s: %s\n", verb, strings.Join(result.Tests, ", "))
+			}
+			if len(result.Stranded) > 0 {
+				fmt.Fprintf(c.Root().Writer, "  Tasks that could never start: %s\n", formatStranded(result.Stranded))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createEpicForReset() *epic.Epic {
+	return &epic.Epic{
+		ID:     "epic-1",
+		Name:   "Test Epic",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{
+			{ID: "P1", Name: "Phase 1", Status: epic.StatusWIP},
+		},
+		Tasks: []epic.Task{
+			{ID: "T1", PhaseID: "P1", Name: "Task 1", Status: epic.StatusWIP},
+			{ID: "T2", PhaseID: "P1", Name: "Task 2", Status: epic.StatusPending},
+		},
+		Tests: []epic.Test{
+			{ID: "T1_T1", TaskID: "T1", PhaseID: "P1", Name: "Test 1", Status: epic.StatusCompleted, TestStatus: epic.TestStatusDone},
+			{ID: "T2_T1", TaskID: "T2", PhaseID: "P1", Name: "Test 2", Status: epic.StatusPending},
+		},
+	}
+}
+
+func TestResetPhaseCommand(t *testing.T) {
+	runReset := func(t *testing.T, epicFile string, args ...string) (string, error) {
+		var stdout, stderr bytes.Buffer
+		cmd := ResetCommand()
+		cmd.Root().Writer = &stdout
+		cmd.Root().ErrWriter = &stderr
+		err := cmd.Run(context.Background(), append([]string{"reset", "--file", epicFile, "--time", "2025-08-16T15:30:00Z", "phase"}, args...))
+		return stdout.String(), err
+	}
+
+	t.Run("refuses without --confirm and shows a preview", func(t *testing.T) {
+		epicFile := filepath.Join(t.TempDir(), "epic.xml")
+		fileStorage := storage.NewFileStorage()
+		require.NoError(t, fileStorage.SaveEpic(createEpicForReset(), epicFile))
+
+		output, err := runReset(t, epicFile, "P1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "without --confirm")
+		assert.Contains(t, output, "Preview for phase P1")
+		assert.Contains(t, output, "Tasks would be reset to pending: T1")
+		assert.Contains(t, output, "Tests would be reset to pending: T1_T1")
+
+		unchanged, err := fileStorage.LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, epic.StatusWIP, unchanged.Phases[0].Status)
+	})
+
+	t.Run("resets the phase with --confirm", func(t *testing.T) {
+		epicFile := filepath.Join(t.TempDir(), "epic.xml")
+		fileStorage := storage.NewFileStorage()
+		require.NoError(t, fileStorage.SaveEpic(createEpicForReset(), epicFile))
+
+		output, err := runReset(t, epicFile, "--to", "pending", "--confirm", "P1")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Phase P1: 1 task(s) and 1 test(s) reset to pending.")
+
+		updated, err := fileStorage.LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, epic.StatusPending, updated.Phases[0].Status)
+		assert.Equal(t, epic.StatusPending, updated.Tasks[0].Status)
+		assert.Equal(t, epic.TestStatusPending, updated.Tests[0].GetTestStatusUnified())
+		require.Len(t, updated.Events, 3)
+	})
+
+	t.Run("rejects unsupported target status", func(t *testing.T) {
+		epicFile := filepath.Join(t.TempDir(), "epic.xml")
+		require.NoError(t, storage.NewFileStorage().SaveEpic(createEpicForReset(), epicFile))
+
+		_, err := runReset(t, epicFile, "--to", "done", "--confirm", "P1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported reset target")
+	})
+}
+
+func TestCancelAllPendingInPhase(t *testing.T) {
+	epicFile := filepath.Join(t.TempDir(), "epic.xml")
+	fileStorage := storage.NewFileStorage()
+	require.NoError(t, fileStorage.SaveEpic(createEpicForReset(), epicFile))
+
+	var stdout bytes.Buffer
+	cmd := CancelCommand()
+	cmd.Root().Writer = &stdout
+	err := cmd.Run(context.Background(), []string{"cancel", "--file", epicFile, "--time", "2025-08-16T15:30:00Z",
+		"--all-pending-in-phase", "P1", "--confirm", "--reason", "Descoped", "--format", "xml"})
+	require.NoError(t, err)
+
+	assert.Contains(t, stdout.String(), `<cancel_pending_in_phase phase="P1" applied="true">`)
+	assert.Contains(t, stdout.String(), `<task id="T2"/>`)
+	assert.Contains(t, stdout.String(), `<test id="T2_T1"/>`)
+
+	updated, err := fileStorage.LoadEpic(epicFile)
+	require.NoError(t, err)
+	assert.Equal(t, epic.StatusWIP, updated.Tasks[0].Status)
+	assert.Equal(t, epic.StatusCancelled, updated.Tasks[1].Status)
+	assert.Equal(t, epic.TestStatusCancelled, updated.Tests[1].GetTestStatusUnified())
+}
+
+func TestCancelReportsStrandedTasks(t *testing.T) {
+	createEpic := func(t *testing.T) string {
+		e := createEpicForReset()
+		e.Phases = append(e.Phases, epic.Phase{ID: "P2", Name: "Phase 2", Status: epic.StatusPending})
+		e.Tasks = append(e.Tasks,
+			epic.Task{ID: "T3", PhaseID: "P2", Name: "Task 3", Status: epic.StatusPending, DependsOn: "T1,T2"},
+			epic.Task{ID: "T4", PhaseID: "P2", Name: "Task 4", Status: epic.StatusPending, DependsOn: "T3"},
+		)
+		epicFile := filepath.Join(t.TempDir(), "epic.xml")
+		require.NoError(t, storage.NewFileStorage().SaveEpic(e, epicFile))
+		return epicFile
+	}
+	runCancel := func(t *testing.T, args ...string) (string, error) {
+		var stdout bytes.Buffer
+		cmd := CancelCommand()
+		cmd.Root().Writer = &stdout
+		err := cmd.Run(context.Background(), append([]string{"cancel"}, args...))
+		return stdout.String(), err
+	}
+
+	t.Run("preview of bulk cancellation", func(t *testing.T) {
+		epicFile := createEpic(t)
+		out, err := runCancel(t, "--file", epicFile, "--all-pending-in-phase", "P1")
+		require.Error(t, err)
+		assert.Contains(t, out, "  Tasks that could never start: T3 (needs T2), T4 (needs T3)\n")
+	})
+
+	t.Run("bulk cancellation json", func(t *testing.T) {
+		epicFile := createEpic(t)
+		out, err := runCancel(t, "--file", epicFile, "--all-pending-in-phase", "P1", "--confirm", "--format", "json")
+		require.NoError(t, err)
+
+		var result struct {
+			Stranded []epic.StrandedTask `json:"stranded"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(out), &result))
+		assert.Equal(t, []epic.StrandedTask{{ID: "T3", PhaseID: "P2", Via: "T2"}, {ID: "T4", PhaseID: "P2", Via: "T3"}}, result.Stranded)
+	})
+
+	t.Run("single task", func(t *testing.T) {
+		epicFile := createEpic(t)
+		out, err := runCancel(t, "task", "T1", "--file", epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, "Task T1 cancelled.\n"+
+			"Warning: 2 pending task(s) can no longer start: T3 (needs T1), T4 (needs T3)\n"+
+			"Hint: cancel them as well, or drop the cancelled task from their depends_on\n", out)
+	})
+
+	t.Run("single task xml", func(t *testing.T) {
+		epicFile := createEpic(t)
+		out, err := runCancel(t, "task", "T1", "--file", epicFile, "--format", "xml")
+		require.NoError(t, err)
+		assert.Equal(t, `<task_cancelled task="T1">
+    <stranded count="2">
+        <task id="T3" phase_id="P2" via="T1"/>
+        <task id="T4" phase_id="P2" via="T3"/>
+    </stranded>
+</task_cancelled>
+`, out)
+	})
+}
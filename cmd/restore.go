@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// KeepEpicBackups is the root Before hook that makes saves keep the number of
+// epic file backups configured in the project
+func KeepEpicBackups(ctx context.Context, c *cli.Command) (context.Context, error) {
+	storage.SetBackupRetention(0)
+
+	// A missing or broken config is reported by the command itself
+	configPath, err := config.ResolveConfigPath(c.String("config"))
+	if err != nil || !config.ConfigExists(configPath) {
+		return ctx, nil
+	}
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return ctx, nil
+	}
+	storage.SetBackupRetention(cfg.Backups)
+	return ctx, nil
+}
+
+// RestoreCommand rolls the epic file back to a backup
+func RestoreCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "restore",
+		Usage: "Roll the epic file back to a backup",
+		Description: `Replace the epic file with one of its backups.
+
+With "backups": N in .agentpm.json every save keeps the previous version of the
+epic file as <epic>.bak.1, shifting older ones up to <epic>.bak.N. Backup 1 is
+the version before the last change. The replaced version becomes backup 1
+itself, so a restore can be rolled back with 'agentpm restore --backup 2'.
+
+Examples:
+  agentpm restore --list              # Show the available backups
+  agentpm restore --backup 1          # Undo the last save
+  agentpm restore --backup 3 -f epic-2.xml`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config",
+			},
+			&cli.IntFlag{
+				Name:  "backup",
+				Usage: "Backup to restore, 1 being the most recent",
+			},
+			&cli.BoolFlag{
+				Name:  "list",
+				Usage: "List the backups instead of restoring one",
+			},
+		},
+		Action: withQuietResult(restoreAction),
+	}
+}
+
+func restoreAction(ctx context.Context, c *cli.Command) error {
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+	if c.Bool("list") {
+		return listBackups(c, epicFile)
+	}
+	if !c.IsSet("backup") {
+		return fmt.Errorf("--backup is required (see 'agentpm restore --list' for the available backups)")
+	}
+	n := int(c.Int("backup"))
+	if n < 1 {
+		return fmt.Errorf("invalid --backup: %d (1 is the most recent backup)", n)
+	}
+
+	fileStorage := storage.NewFileStorage()
+	if err := fileStorage.RestoreBackup(epicFile, n); err != nil {
+		return err
+	}
+	epicData, err := fileStorage.LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load restored epic: %w", err)
+	}
+
+	w := c.Root().Writer
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(map[string]interface{}{
+			"restored": map[string]interface{}{"file": epicFile, "backup": n, "epic": epicData.ID, "status": epicData.Status},
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal restore result to JSON: %w", err)
+		}
+		fmt.Fprintf(w, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("restored")
+		root.CreateAttr("file", epicFile)
+		root.CreateAttr("backup", strconv.Itoa(n))
+		root.CreateAttr("epic", epicData.ID)
+		root.CreateAttr("status", string(epicData.Status))
+		doc.Indent(4)
+		doc.WriteTo(w)
+	default:
+		fmt.Fprintf(w, "Restored %s from backup %d (epic %s, status %s)\n", epicFile, n, epicData.ID, epicData.Status)
+	}
+	return nil
+}
+
+func listBackups(c *cli.Command, epicFile string) error {
+	backups, err := storage.ListBackups(epicFile)
+	if err != nil {
+		return err
+	}
+
+	w := c.Root().Writer
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(map[string]interface{}{"file": epicFile, "backups": backups}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal backups to JSON: %w", err)
+		}
+		fmt.Fprintf(w, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("backups")
+		root.CreateAttr("file", epicFile)
+		for _, backup := range backups {
+			elem := root.CreateElement("backup")
+			elem.CreateAttr("n", strconv.Itoa(backup.N))
+			elem.CreateAttr("saved_at", backup.ModTime.UTC().Format(time.RFC3339))
+			elem.CreateAttr("size", strconv.FormatInt(backup.Size, 10))
+			elem.SetText(backup.Path)
+		}
+		doc.Indent(4)
+		doc.WriteTo(w)
+	default:
+		if len(backups) == 0 {
+			fmt.Fprintf(w, "No backups of %s (set \"backups\" in .agentpm.json to keep them)\n", epicFile)
+			return nil
+		}
+		fmt.Fprintf(w, "Backups of %s (most recent first):\n", epicFile)
+		for _, backup := range backups {
+			fmt.Fprintf(w, "  %d  %s  %6d bytes\n", backup.N, backup.ModTime.Format("2006-01-02 15:04:05"), backup.Size)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestRestoreCommand(t *testing.T) {
+	t.Cleanup(func() { storage.SetBackupRetention(0) })
+
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, ".agentpm.json")
+	epicFile := filepath.Join(tempDir, "epic.xml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"current_epic": "epic.xml", "backups": 3}`), 0644))
+
+	run := func(args ...string) (string, error) {
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config", Value: configPath},
+				&cli.StringFlag{Name: "format", Value: "text"},
+			},
+			Before:   KeepEpicBackups,
+			Commands: []*cli.Command{StartCommand(), RestoreCommand()},
+		}
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		app.ErrWriter = &bytes.Buffer{}
+		err := app.Run(context.Background(), append([]string{"agentpm"}, args...))
+		return stdout.String(), err
+	}
+
+	testEpic := epic.NewEpic("8", "Restorable")
+	testEpic.Phases = []epic.Phase{{ID: "P1", Name: "Build", Status: epic.StatusPending}}
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicFile))
+
+	output, err := run("restore", "--list")
+	require.NoError(t, err)
+	assert.Contains(t, output, "No backups of")
+
+	_, err = run("start", "epic", "--file", epicFile)
+	require.NoError(t, err)
+	output, err = run("restore", "--list")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Backups of "+epicFile+" (most recent first):\n  1  ")
+
+	output, err = run("restore", "--backup", "1")
+	require.NoError(t, err)
+	assert.Equal(t, "Restored "+epicFile+" from backup 1 (epic 8, status pending)\n", output)
+
+	// The version replaced by the restore is backed up as well
+	output, err = run("restore", "--backup", "1", "--format", "xml")
+	require.NoError(t, err)
+	assert.Contains(t, output, `backup="1" epic="8" status="wip"`)
+
+	_, err = run("restore")
+	assert.ErrorContains(t, err, "--backup is required")
+	_, err = run("restore", "--backup", "0")
+	assert.ErrorContains(t, err, "invalid --backup: 0")
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/handoff"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// ResumeCommand resumes work from a handoff token
+func ResumeCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "resume",
+		Usage: "Resume work from a handoff token",
+		Description: `Take over the work handed off with 'agentpm handoff --issue-token'.
+
+The token is verified against the project's signing key and the epic file:
+resumption is refused when the token was issued for another epic, when the
+epic changed after the handoff, or when the token was already resumed. A
+successful resume records a handoff_resumed event, so the same handoff can
+never be resumed by two agents.
+
+Examples:
+  agentpm handoff --issue-token           # Outgoing agent
+  agentpm resume --token <token>          # Incoming agent`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config",
+			},
+			&cli.StringFlag{
+				Name:     "token",
+				Usage:    "Handoff token issued by 'agentpm handoff --issue-token'",
+				Required: true,
+			},
+		},
+		Action: resumeAction,
+	}
+}
+
+func resumeAction(ctx context.Context, c *cli.Command) error {
+	cfg, err := config.LoadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	epicFile := c.String("file")
+	if epicFile == "" {
+		epicFile = cfg.EpicFilePath()
+	}
+
+	key, err := handoff.LoadKey(cfg.HandoffKeyPath())
+	if err != nil {
+		return err
+	}
+	token, err := handoff.Parse(key, c.String("token"))
+	if err != nil {
+		return err
+	}
+
+	// Loading takes the lock on the epic file, held until the save: the state
+	// version is checked and the resume recorded without another command in between
+	fileStorage := storage.NewFileStorage()
+	epicData, err := fileStorage.LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+	content, err := os.ReadFile(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to read epic file: %w", err)
+	}
+	if err := token.Check(epicData, content); err != nil {
+		return err
+	}
+
+	service.CreateEvent(epicData, service.EventHandoffResumed, "", token.Task, "", token.ID, time.Now())
+	if err := fileStorage.SaveEpic(epicData, epicFile); err != nil {
+		return fmt.Errorf("failed to save epic: %w", err)
+	}
+
+	w := c.Root().Writer
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(map[string]interface{}{"resumed": token}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal handoff to JSON: %w", err)
+		}
+		fmt.Fprintf(w, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("resumed")
+		writeHandoffTokenAttrs(root, token)
+		doc.Indent(4)
+		doc.WriteTo(w)
+	default:
+		fmt.Fprintf(w, "Resumed handoff %s of epic %s", token.ID, token.EpicID)
+		if token.IssuedBy != "" {
+			fmt.Fprintf(w, " from %s", token.IssuedBy)
+		}
+		fmt.Fprintf(w, "\n")
+		if token.Task != "" {
+			fmt.Fprintf(w, "Continue with task %s ('agentpm current' shows the details)\n", token.Task)
+		} else {
+			fmt.Fprintf(w, "No task was active; run 'agentpm next' to pick up work\n")
+		}
+	}
+	return nil
+}
+
+// issueHandoffToken prints a signed token capturing the epic's current state
+func issueHandoffToken(c *cli.Command, cfg *config.Config, epicFile string) error {
+	format := c.String("format")
+	switch format {
+	case "text", "json", "markdown", "xml":
+	default:
+		return exitcode.Errorf(exitcode.Validation, "handoff --issue-token supports --format text, json, markdown or xml (got %q)", format)
+	}
+	// Issuing may create the signing key
+	if storage.DryRun() {
+		return errDryRunUnsupported("handoff --issue-token")
+	}
+
+	key, err := handoff.LoadKey(cfg.HandoffKeyPath())
+	if err != nil {
+		return err
+	}
+	// Read under the lock loading takes, so the version matches the epic loaded
+	epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+	content, err := os.ReadFile(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to read epic file: %w", err)
+	}
+
+	token, err := handoff.NewToken(epicData, content, time.Now())
+	if err != nil {
+		return err
+	}
+	encoded, err := handoff.Sign(key, token)
+	if err != nil {
+		return err
+	}
+
+	w := c.Root().Writer
+	switch format {
+	case "json":
+		output := struct {
+			*handoff.Token
+			Encoded string `json:"token"`
+		}{token, encoded}
+		jsonData, err := json.MarshalIndent(map[string]interface{}{"handoff_token": output}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal handoff token to JSON: %w", err)
+		}
+		fmt.Fprintf(w, "%s\n", jsonData)
+	case "text":
+		fmt.Fprintf(w, "Handoff %s of epic %s", token.ID, token.EpicID)
+		if token.Task != "" {
+			fmt.Fprintf(w, " (task %s)", token.Task)
+		}
+		fmt.Fprintf(w, "\n\n  %s\n\nResume with: agentpm resume --token <token>\n", encoded)
+		fmt.Fprintf(w, "The token is valid until the epic changes or it is resumed once.\n")
+	case "markdown":
+		fmt.Fprintf(w, "## Handoff %s\n\n", token.ID)
+		fmt.Fprintf(w, "- **Epic:** %s\n", token.EpicID)
+		if token.Task != "" {
+			fmt.Fprintf(w, "- **Task:** %s\n", token.Task)
+		}
+		fmt.Fprintf(w, "- **Issued:** %s\n", token.IssuedAt.Format(time.RFC3339))
+		if token.IssuedBy != "" {
+			fmt.Fprintf(w, "- **Issued by:** %s\n", token.IssuedBy)
+		}
+		fmt.Fprintf(w, "\nResume with:\n\n```bash\nagentpm resume --token %s\n```\n", encoded)
+	default:
+		doc := etree.NewDocument()
+		root := doc.CreateElement("handoff_token")
+		writeHandoffTokenAttrs(root, token)
+		root.SetText(encoded)
+		doc.Indent(4)
+		doc.WriteTo(w)
+	}
+	return nil
+}
+
+func writeHandoffTokenAttrs(elem *etree.Element, token *handoff.Token) {
+	elem.CreateAttr("id", token.ID)
+	elem.CreateAttr("epic", token.EpicID)
+	if token.Task != "" {
+		elem.CreateAttr("task", token.Task)
+	}
+	elem.CreateAttr("version", token.Version)
+	elem.CreateAttr("issued_at", token.IssuedAt.Format(time.RFC3339))
+	if token.IssuedBy != "" {
+		elem.CreateAttr("issued_by", token.IssuedBy)
+	}
+}
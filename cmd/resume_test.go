@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestHandoffResume(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, ".agentpm.json")
+	epicFile := filepath.Join(tempDir, "epic.xml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"current_epic": "epic.xml"}`), 0644))
+
+	testEpic := epic.NewEpic("8", "Handed Over")
+	testEpic.Status = epic.StatusWIP
+	testEpic.Phases = []epic.Phase{{ID: "P1", Name: "Build", Status: epic.StatusWIP}}
+	testEpic.Tasks = []epic.Task{{ID: "T1", PhaseID: "P1", Name: "Task 1", Status: epic.StatusWIP}}
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicFile))
+
+	run := func(args ...string) (string, error) {
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config", Value: configPath},
+				&cli.StringFlag{Name: "format", Value: "text"},
+			},
+			Commands: []*cli.Command{HandoffCommand(), ResumeCommand()},
+		}
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		err := app.Run(context.Background(), append([]string{"agentpm"}, args...))
+		return stdout.String(), err
+	}
+	issue := func() string {
+		output, err := run("handoff", "--issue-token", "--format", "json")
+		require.NoError(t, err)
+		var result struct {
+			Token struct {
+				Task  string `json:"task"`
+				Token string `json:"token"`
+			} `json:"handoff_token"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.Equal(t, "T1", result.Token.Task)
+		return result.Token.Token
+	}
+
+	t.Run("resumes once", func(t *testing.T) {
+		token := issue()
+		assert.FileExists(t, filepath.Join(tempDir, ".agentpm", "handoff.key"))
+
+		output, err := run("resume", "--token", token)
+		require.NoError(t, err)
+		assert.Contains(t, output, "Continue with task T1")
+
+		epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+		require.NoError(t, err)
+		require.NotEmpty(t, epicData.Events)
+		assert.Equal(t, "handoff_resumed", epicData.Events[len(epicData.Events)-1].Type)
+
+		_, err = run("resume", "--token", token)
+		assert.ErrorContains(t, err, "already resumed")
+	})
+
+	t.Run("rejects a diverged epic", func(t *testing.T) {
+		token := issue()
+		epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+		require.NoError(t, err)
+		epicData.Tasks[0].Status = epic.StatusCompleted
+		require.NoError(t, storage.NewFileStorage().SaveEpic(epicData, epicFile))
+
+		_, err = run("resume", "--token", token)
+		assert.ErrorContains(t, err, "the epic file was changed after the handoff")
+	})
+
+	t.Run("markdown token", func(t *testing.T) {
+		output, err := run("handoff", "--issue-token", "--format", "markdown")
+		require.NoError(t, err)
+		assert.Contains(t, output, "- **Epic:** 8")
+
+		_, block, found := strings.Cut(output, "agentpm resume --token ")
+		require.True(t, found, output)
+		token, _, _ := strings.Cut(block, "\n")
+		output, err = run("resume", "--token", token)
+		require.NoError(t, err)
+		assert.Contains(t, output, "Resumed handoff")
+	})
+
+	t.Run("rejects formats a token cannot be printed in", func(t *testing.T) {
+		_, err := run("handoff", "--issue-token", "--format", "jsonl")
+		assert.ErrorContains(t, err, `supports --format text, json, markdown or xml (got "jsonl")`)
+		assert.Equal(t, exitcode.Validation, ExitCode(err))
+	})
+}
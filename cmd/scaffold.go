@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/scaffold"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+func ScaffoldCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "scaffold",
+		Usage: "Generate a skeleton epic from a Markdown brief",
+		Description: `Derive a skeleton epic from a Markdown brief, deterministically and
+without any LLM call:
+
+- the brief's title (a single # heading) becomes the epic name
+- the headings below it become phases
+- top-level list items under a heading become tasks (nested items become
+  the task description)
+- every task gets a placeholder test
+
+With --phases N, headings beyond the Nth are folded into the last phase.
+The epic is written next to the brief (brief.md -> brief.xml) unless
+--output is given; existing files are only replaced with --force.
+
+Examples:
+  agentpm scaffold --from brief.md --phases 4
+  agentpm scaffold --from docs/search.md --output epic-9.xml --id 9`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "from",
+				Usage:    "Markdown brief to derive the epic from",
+				Required: true,
+			},
+			&cli.IntFlag{
+				Name:  "phases",
+				Usage: "Maximum number of phases (0 = one phase per heading)",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Epic file to write (default: brief name with .xml extension)",
+			},
+			&cli.StringFlag{
+				Name:  "id",
+				Usage: "Epic ID (default: derived from the brief file name)",
+			},
+			&cli.StringFlag{
+				Name:  "name",
+				Usage: "Epic name (default: the brief's title)",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Replace an existing epic file",
+			},
+		},
+		Action: withQuietResult(scaffoldAction),
+	}
+}
+
+func scaffoldAction(ctx context.Context, c *cli.Command) error {
+	briefPath := c.String("from")
+	if c.Int("phases") < 0 {
+		return exitcode.Errorf(exitcode.Validation, "--phases must not be negative")
+	}
+
+	brief, err := os.ReadFile(briefPath)
+	if err != nil {
+		return fmt.Errorf("failed to read brief: %w", err)
+	}
+
+	output := c.String("output")
+	if output == "" {
+		output = strings.TrimSuffix(briefPath, filepath.Ext(briefPath)) + ".xml"
+	}
+
+	fs := storage.NewFileStorage()
+	if fs.EpicExists(output) && !c.Bool("force") {
+		return fmt.Errorf("epic file already exists: %s (use --force to replace it)", output)
+	}
+
+	createdAt := time.Now()
+	if timeStr := c.String("time"); timeStr != "" {
+		if createdAt, err = time.Parse(time.RFC3339, timeStr); err != nil {
+			return fmt.Errorf("invalid time format: %s (use ISO 8601 format like 2025-08-16T15:30:00Z)", timeStr)
+		}
+	}
+
+	id := c.String("id")
+	if id == "" {
+		id = epicIDFromFileName(briefPath)
+	}
+
+	epicData, err := scaffold.FromBrief(string(brief), scaffold.Options{
+		ID:        id,
+		Name:      c.String("name"),
+		MaxPhases: int(c.Int("phases")),
+		CreatedAt: createdAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scaffold epic from %s: %w", briefPath, err)
+	}
+
+	if err := fs.SaveEpic(epicData, output); err != nil {
+		return fmt.Errorf("failed to save epic: %w", err)
+	}
+
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(map[string]interface{}{
+			"epic":   epicData.ID,
+			"name":   epicData.Name,
+			"file":   output,
+			"phases": len(epicData.Phases),
+			"tasks":  len(epicData.Tasks),
+			"tests":  len(epicData.Tests),
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal scaffold result to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("scaffold_result")
+		root.CreateAttr("epic", epicData.ID)
+		root.CreateElement("name").SetText(epicData.Name)
+		root.CreateElement("file").SetText(output)
+		root.CreateElement("phases").SetText(fmt.Sprintf("%d", len(epicData.Phases)))
+		root.CreateElement("tasks").SetText(fmt.Sprintf("%d", len(epicData.Tasks)))
+		root.CreateElement("tests").SetText(fmt.Sprintf("%d", len(epicData.Tests)))
+		doc.Indent(4)
+		doc.WriteTo(c.Root().Writer)
+	default:
+		fmt.Fprintf(c.Root().Writer, "Scaffolded epic %s (%s): %d phases, %d tasks, %d tests\n",
+			epicData.ID, epicData.Name, len(epicData.Phases), len(epicData.Tasks), len(epicData.Tests))
+		fmt.Fprintf(c.Root().Writer, "Written to: %s\n", output)
+		fmt.Fprintf(c.Root().Writer, "Use it with: agentpm switch %s\n", output)
+	}
+	return nil
+}
+
+var nonIDChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// epicIDFromFileName derives an epic ID from a file name: "Search Brief.md" -> "search-brief"
+func epicIDFromFileName(path string) string {
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	id := strings.Trim(nonIDChars.ReplaceAllString(strings.ToLower(base), "-"), "-")
+	if id == "" {
+		return "epic"
+	}
+	return id
+}
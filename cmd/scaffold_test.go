@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaffoldCommand(t *testing.T) {
+	dir := t.TempDir()
+	briefPath := filepath.Join(dir, "Search Brief.md")
+	require.NoError(t, os.WriteFile(briefPath, []byte("# Search\n\n## Indexing\n- Write indexer\n\n## Query\n- Parse query\n- Rank results\n"), 0644))
+
+	run := func(args ...string) (string, error) {
+		var stdout, stderr bytes.Buffer
+		cmd := ScaffoldCommand()
+		cmd.Root().Writer = &stdout
+		cmd.Root().ErrWriter = &stderr
+		err := cmd.Run(context.Background(), append([]string{"scaffold", "--from", briefPath}, args...))
+		return stdout.String(), err
+	}
+
+	output, err := run()
+	require.NoError(t, err)
+	epicPath := filepath.Join(dir, "Search Brief.xml")
+	assert.Contains(t, output, "Scaffolded epic search-brief (Search): 2 phases, 3 tasks, 3 tests")
+	assert.Contains(t, output, "Written to: "+epicPath)
+
+	epicData, err := storage.NewFileStorage().LoadEpic(epicPath)
+	require.NoError(t, err)
+	assert.Equal(t, "search-brief", epicData.ID)
+	assert.Len(t, epicData.Phases, 2)
+	assert.Len(t, epicData.Tests, 3)
+
+	_, err = run()
+	assert.ErrorContains(t, err, "epic file already exists")
+
+	output, err = run("--force", "--phases", "1", "--id", "9")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Scaffolded epic 9 (Search): 1 phases, 3 tasks, 3 tests")
+}
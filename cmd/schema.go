@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/apiversion"
+	"github.com/mindreframer/agentpm/internal/burndown"
+	"github.com/mindreframer/agentpm/internal/compare"
+	"github.com/mindreframer/agentpm/internal/effort"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/forecast"
+	"github.com/mindreframer/agentpm/internal/jsonschema"
+	"github.com/mindreframer/agentpm/internal/query"
+	"github.com/mindreframer/agentpm/internal/reports"
+	"github.com/mindreframer/agentpm/internal/spec"
+	"github.com/mindreframer/agentpm/internal/stats"
+	"github.com/mindreframer/agentpm/internal/velocity"
+	"github.com/urfave/cli/v3"
+)
+
+// outputSchema documents the structured output of a command by the Go types it marshals
+type outputSchema struct {
+	Command     string
+	Description string
+	JSON        interface{} // Marshalled with --format json
+	JSONV1      interface{} // Marshalled with --format json in API version 1, nil when the same as JSON
+	JSONL       interface{} // Marshalled per line with --format jsonl, nil when unsupported
+}
+
+// outputSchemas lists the commands with a documented output, in help order
+var outputSchemas = []outputSchema{
+	{Command: "status", Description: "Progress of the current epic", JSON: statusOutput{}, JSONV1: statusOutputV1{}},
+	{Command: "current", Description: "Active work and what to do next", JSON: currentOutput{}},
+	{Command: "pending", Description: "Phases, tasks and tests not yet done", JSON: pendingOutput{}, JSONL: pendingRecord{}},
+	{Command: "failing", Description: "Tests that are failing", JSON: failingOutput{}},
+	{Command: "upcoming", Description: "Deadlines overdue or coming up soon", JSON: upcomingOutput{}},
+	{Command: "hints explain", Description: "Generators evaluated for an error and the hint selected", JSON: hintsExplainOutput{}},
+	{Command: "lint", Description: "Quality findings of the epic", JSON: LintResult{}},
+	{Command: "events", Description: "Events of the epic, filtered and limited", JSON: eventsOutput{}, JSONL: eventRecord{}},
+	{Command: "search", Description: "Entities of all epics with matching text", JSON: searchOutput{}, JSONL: query.SearchMatch{}},
+	{Command: "docs", Description: "Documentation report of the epic", JSON: reports.DocumentationReport{}},
+	{Command: "link", Description: "Link to an epic, phase, task or test", JSON: EntityLink{}},
+	{Command: "trace", Description: "Coverage of the spec by tasks", JSON: spec.TraceReport{}},
+	{Command: "burndown", Description: "Remaining work over time", JSON: burndown.Series{}},
+	{Command: "velocity", Description: "Completed work per week", JSON: velocity.Report{}},
+	{Command: "forecast", Description: "Completion date forecast", JSON: forecast.Result{}},
+	{Command: "stats", Description: "Statistics dashboards read", JSON: stats.Stats{}},
+	{Command: "effort", Description: "Effort per actor or task", JSON: effort.Report{}},
+	{Command: "compare-runs", Description: "Differences between two runs of an epic", JSON: compare.Comparison{}},
+	{Command: "import tests", Description: "Tests imported from a test plan and the rows left unmatched", JSON: importTestsOutput{}},
+}
+
+// SchemaCommand documents the shapes of agentpm's structured output
+func SchemaCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "schema",
+		Usage: "Print JSON Schemas of command output",
+		Description: `Print the JSON Schema of the structured output of commands, for integrators
+and prompt authors relying on it.
+
+Subcommands:
+  output [command]  Print the output schema of a command, or list the documented commands
+
+Examples:
+  agentpm schema output                     # List commands with an output schema
+  agentpm schema output status              # Schema of 'agentpm status --format json'
+  agentpm schema output events --jsonl      # Schema of each line of 'agentpm events --format jsonl'
+  agentpm schema output hints explain`,
+		Commands: []*cli.Command{
+			{
+				Name:      "output",
+				Usage:     "Print the JSON Schema of a command's output",
+				ArgsUsage: "[command]",
+				Description: `Print the JSON Schema (draft 2020-12) of the --format json output of a
+command. The schemas are derived from the Go types the command marshals, so
+they always match the output. They describe the output of the selected API
+version (--api-version or api_version in the config), which their $id
+carries. Incompatible changes only land in a new API version; new fields are
+added to all versions. --fields reduces the output to a subset of the schema.
+
+Without a command, list the commands with a documented output.`,
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "jsonl",
+						Usage: "Print the schema of each line of --format jsonl instead",
+					},
+				},
+				Action: schemaOutputAction,
+			},
+		},
+	}
+}
+
+func schemaOutputAction(ctx context.Context, c *cli.Command) error {
+	version := apiversion.FromContext(ctx)
+	if c.Args().Len() == 0 {
+		return listOutputSchemas(c, version)
+	}
+
+	command := strings.Join(c.Args().Slice(), " ")
+	var found *outputSchema
+	for i := range outputSchemas {
+		if outputSchemas[i].Command == command {
+			found = &outputSchemas[i]
+			break
+		}
+	}
+	if found == nil {
+		return exitcode.Errorf(exitcode.Validation, "no output schema for command %q (see 'agentpm schema output' for the documented commands)", command)
+	}
+
+	output, title := found.jsonOutput(version), fmt.Sprintf("agentpm %s --format json", command)
+	if c.Bool("jsonl") {
+		if found.JSONL == nil {
+			return exitcode.Errorf(exitcode.Validation, "command %q has no jsonl output", command)
+		}
+		output, title = found.JSONL, fmt.Sprintf("agentpm %s --format jsonl (one line)", command)
+	}
+
+	schema := jsonschema.Reflect(output)
+	schema["$id"] = outputSchemaID(command, c.Bool("jsonl"), version)
+	schema["title"] = title
+	schema["description"] = found.Description
+	jsonData, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal output schema: %w", err)
+	}
+	fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	return nil
+}
+
+// outputSchemaID identifies an output schema in an API version, e.g. urn:agentpm:output:hints-explain:v1
+func outputSchemaID(command string, jsonl bool, version apiversion.Version) string {
+	name := strings.ReplaceAll(command, " ", "-")
+	if jsonl {
+		name += ":jsonl"
+	}
+	return fmt.Sprintf("urn:agentpm:output:%s:%s", name, version)
+}
+
+func listOutputSchemas(c *cli.Command, version apiversion.Version) error {
+	w := c.Root().Writer
+	switch c.String("format") {
+	case "json":
+		type schemaEntry struct {
+			Command     string   `json:"command"`
+			Description string   `json:"description"`
+			Formats     []string `json:"formats"`
+			ID          string   `json:"id"`
+		}
+		entries := make([]schemaEntry, 0, len(outputSchemas))
+		for _, schema := range outputSchemas {
+			entries = append(entries, schemaEntry{schema.Command, schema.Description, schema.formats(), outputSchemaID(schema.Command, false, version)})
+		}
+		jsonData, err := json.MarshalIndent(map[string]interface{}{"api_version": int(version), "schemas": entries}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal output schemas to JSON: %w", err)
+		}
+		fmt.Fprintf(w, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("output_schemas")
+		root.CreateAttr("api_version", fmt.Sprintf("%d", version))
+		for _, schema := range outputSchemas {
+			elem := root.CreateElement("schema")
+			elem.CreateAttr("command", schema.Command)
+			elem.CreateAttr("formats", strings.Join(schema.formats(), ","))
+			elem.CreateAttr("id", outputSchemaID(schema.Command, false, version))
+			elem.SetText(schema.Description)
+		}
+		doc.Indent(4)
+		doc.WriteTo(w)
+	default:
+		fmt.Fprintf(w, "Documented command output (API version %d):\n\n", version)
+		for _, schema := range outputSchemas {
+			fmt.Fprintf(w, "  %-14s %-11s %s\n", schema.Command, strings.Join(schema.formats(), ","), schema.Description)
+		}
+		fmt.Fprintf(w, "\nPrint one with: agentpm schema output <command> [--jsonl]\n")
+	}
+	return nil
+}
+
+// jsonOutput returns the type marshalled with --format json in an API version
+func (s outputSchema) jsonOutput(version apiversion.Version) interface{} {
+	if s.JSONV1 != nil && version == apiversion.V1 {
+		return s.JSONV1
+	}
+	return s.JSON
+}
+
+func (s outputSchema) formats() []string {
+	if s.JSONL != nil {
+		return []string{"json", "jsonl"}
+	}
+	return []string{"json"}
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/apiversion"
+	"github.com/mindreframer/agentpm/internal/jsonschema"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestSchemaOutputCommand(t *testing.T) {
+	run := func(args ...string) (string, error) {
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config", Value: filepath.Join(t.TempDir(), ".agentpm.json")},
+				&cli.StringFlag{Name: "format", Value: "text"},
+			},
+			Commands: []*cli.Command{SchemaCommand()},
+		}
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		app.ErrWriter = &bytes.Buffer{}
+		err := app.Run(context.Background(), append([]string{"agentpm"}, args...))
+		return stdout.String(), err
+	}
+
+	t.Run("lists documented commands", func(t *testing.T) {
+		output, err := run("schema", "output")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Documented command output (API version 1):")
+		assert.Contains(t, output, "events         json,jsonl")
+		assert.Contains(t, output, "hints explain  json")
+	})
+
+	t.Run("schema of a command", func(t *testing.T) {
+		output, err := run("schema", "output", "hints", "explain")
+		require.NoError(t, err)
+
+		var schema map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(output), &schema))
+		assert.Equal(t, "urn:agentpm:output:hints-explain:v1", schema["$id"])
+		assert.Equal(t, "agentpm hints explain --format json", schema["title"])
+		assert.Equal(t, []interface{}{"explanation"}, schema["required"])
+	})
+
+	t.Run("schema of jsonl lines", func(t *testing.T) {
+		output, err := run("schema", "output", "events", "--jsonl")
+		require.NoError(t, err)
+		assert.Contains(t, output, `"$id": "urn:agentpm:output:events:jsonl:v1"`)
+
+		_, err = run("schema", "output", "status", "--jsonl")
+		assert.ErrorContains(t, err, `command "status" has no jsonl output`)
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		_, err := run("schema", "output", "start")
+		assert.ErrorContains(t, err, `no output schema for command "start"`)
+	})
+}
+
+// TestOutputSchemasMatchOutput checks real command output against the published schemas
+func TestOutputSchemasMatchOutput(t *testing.T) {
+	epicFile := filepath.Join(t.TempDir(), "epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(createTestEpicForStatus(), epicFile))
+
+	commands := map[string]*cli.Command{
+		"status":   StatusCommand(),
+		"current":  CurrentCommand(),
+		"pending":  PendingCommand(),
+		"failing":  FailingCommand(),
+		"events":   EventsCommand(),
+		"upcoming": UpcomingCommand(),
+	}
+	for version := apiversion.V1; version <= apiversion.Latest; version++ {
+		for _, schema := range outputSchemas {
+			command, ok := commands[schema.Command]
+			if !ok {
+				continue
+			}
+			t.Run(fmt.Sprintf("%s %s", schema.Command, version), func(t *testing.T) {
+				var stdout bytes.Buffer
+				command.Root().Writer = &stdout
+				command.Root().ErrWriter = &bytes.Buffer{}
+				require.NoError(t, command.Run(apiversion.NewContext(context.Background(), version), []string{schema.Command, "--file", epicFile, "--format", "json"}))
+
+				var document interface{}
+				require.NoError(t, json.Unmarshal(stdout.Bytes(), &document))
+				assert.Empty(t, schemaViolations(document, jsonschemaOf(t, schema.jsonOutput(version)), "$"))
+			})
+		}
+	}
+}
+
+func jsonschemaOf(t *testing.T, output interface{}) map[string]interface{} {
+	t.Helper()
+	schema, err := json.Marshal(jsonschema.Reflect(output))
+	require.NoError(t, err)
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(schema, &decoded))
+	return decoded
+}
+
+// schemaViolations checks the subset of JSON Schema the output schemas use
+func schemaViolations(value interface{}, schema map[string]interface{}, path string) []string {
+	var violations []string
+	if types := schemaTypes(schema["type"]); len(types) > 0 && !types[jsonTypeOf(value)] {
+		return []string{fmt.Sprintf("%s: %s is not of type %v", path, jsonTypeOf(value), schema["type"])}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		for _, name := range schemaRequired(schema) {
+			if _, ok := v[name]; !ok {
+				violations = append(violations, fmt.Sprintf("%s: missing %s", path, name))
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if property, ok := properties[key].(map[string]interface{}); ok {
+				violations = append(violations, schemaViolations(v[key], property, path+"."+key)...)
+			} else if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+				violations = append(violations, schemaViolations(v[key], additional, path+"."+key)...)
+			} else if schema["additionalProperties"] == false {
+				violations = append(violations, fmt.Sprintf("%s: unexpected %s", path, key))
+			}
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				violations = append(violations, schemaViolations(item, items, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+	return violations
+}
+
+func schemaTypes(declared interface{}) map[string]bool {
+	types := make(map[string]bool)
+	switch t := declared.(type) {
+	case string:
+		types[t] = true
+	case []interface{}:
+		for _, name := range t {
+			types[name.(string)] = true
+		}
+	}
+	if types["number"] {
+		types["integer"] = true
+	}
+	return types
+}
+
+func schemaRequired(schema map[string]interface{}) []string {
+	var required []string
+	list, _ := schema["required"].([]interface{})
+	for _, name := range list {
+		required = append(required, name.(string))
+	}
+	return required
+}
+
+func jsonTypeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/output"
+	"github.com/mindreframer/agentpm/internal/query"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// searchOutput is the JSON output of search
+type searchOutput struct {
+	Query   string              `json:"query"`
+	Epics   int                 `json:"epics" doc:"Number of epic files searched"`
+	Matches []query.SearchMatch `json:"matches"`
+}
+
+// SearchCommand returns the search command for finding text across all epics
+func SearchCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "search",
+		Usage:     "Find text in the names, descriptions and notes of all epics",
+		ArgsUsage: "<text>",
+		Description: `Search every epic of the workspace (see 'agentpm epics') for text, ignoring
+case. The names and descriptions of the epic, its phases, tasks and tests are
+searched, as well as the deliverables of phases, the acceptance criteria of
+tasks and the failure notes of tests.
+
+Each matching entity is listed once with its epic file, type, ID, status and
+a snippet of the first field that matched, epic by epic in outline order.
+Tests show "failing" while their last run failed, like in 'agentpm index'.
+
+Examples:
+  agentpm search "rate limit"                       # Everywhere
+  agentpm search login --type task --status wip     # Active tasks about login
+  agentpm search timeout --type test --status failing
+  agentpm search checkout -f epic-9.xml --format json`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Search only this epic file instead of the workspace",
+			},
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"F"},
+				Usage:   "Output format: text (default), json, jsonl, xml",
+				Value:   "text",
+			},
+			&cli.StringSliceFlag{
+				Name:  "type",
+				Usage: "Only entities of this type: epic, phase, task or test (repeatable)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "status",
+				Usage: "Only entities with this status, e.g. wip or failing (repeatable)",
+			},
+		},
+		Action: searchAction,
+	}
+}
+
+func searchAction(ctx context.Context, c *cli.Command) error {
+	if c.Args().Len() != 1 {
+		return exitcode.Errorf(exitcode.Validation, "search requires exactly one argument: the text to find")
+	}
+	text := c.Args().First()
+
+	filter := query.SearchFilter{Types: c.StringSlice("type"), Statuses: c.StringSlice("status")}
+	for _, t := range filter.Types {
+		if !slices.Contains([]string{"epic", "phase", "task", "test"}, t) {
+			return fmt.Errorf("invalid entity type: %s (must be epic, phase, task or test)", t)
+		}
+	}
+
+	epicFiles, display, err := searchEpicFiles(c)
+	if err != nil {
+		return err
+	}
+
+	result := searchOutput{Query: text, Epics: len(epicFiles), Matches: []query.SearchMatch{}}
+	for _, epicFile := range epicFiles {
+		queryService := query.NewQueryService(storage.NewFileStorage())
+		if err := queryService.LoadEpic(epicFile); err != nil {
+			return fmt.Errorf("failed to load epic %s: %w", epicFile, err)
+		}
+		matches, err := queryService.Search(text, filter)
+		if err != nil {
+			return err
+		}
+		for _, match := range matches {
+			match.File = display(epicFile)
+			result.Matches = append(result.Matches, match)
+		}
+	}
+
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal search result to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "jsonl":
+		lines := output.NewJSONLWriter(c.Root().Writer)
+		for _, match := range result.Matches {
+			if err := lines.Write(match); err != nil {
+				return err
+			}
+		}
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("search")
+		root.CreateAttr("query", result.Query)
+		root.CreateAttr("epics", fmt.Sprint(result.Epics))
+		for _, match := range result.Matches {
+			elem := root.CreateElement(match.Type)
+			elem.CreateAttr("file", match.File)
+			elem.CreateAttr("epic", match.Epic)
+			elem.CreateAttr("id", match.ID)
+			elem.CreateAttr("status", match.Status)
+			elem.CreateAttr("field", match.Field)
+			elem.CreateAttr("name", match.Name)
+			elem.SetText(match.Snippet)
+		}
+		doc.Indent(4)
+		doc.WriteTo(c.Root().Writer)
+	default:
+		outputSearchText(c, result)
+	}
+	return nil
+}
+
+// searchEpicFiles returns the epic files to search: the one given with
+// --file or every epic of the workspace, and how to show their paths
+func searchEpicFiles(c *cli.Command) ([]string, func(string) string, error) {
+	if epicFile := c.String("file"); epicFile != "" {
+		return []string{epicFile}, func(path string) string { return path }, nil
+	}
+	cfg, err := config.LoadConfig(c.String("config"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	epicFiles := cfg.EpicFilePaths()
+	if len(epicFiles) == 0 {
+		return nil, nil, exitcode.Errorf(exitcode.Validation, "no epic file specified. Use --file flag or run 'agentpm init' first")
+	}
+	return epicFiles, func(path string) string { return workspacePath(cfg, path) }, nil
+}
+
+// outputSearchText prints one block per match: where it is, then the snippet
+func outputSearchText(c *cli.Command, result searchOutput) {
+	w := c.Root().Writer
+	if len(result.Matches) == 0 {
+		fmt.Fprintf(w, "No matches for %q in %d epic(s).\n", result.Query, result.Epics)
+		return
+	}
+
+	fmt.Fprintf(w, "%d match(es) for %q in %d epic(s):\n", len(result.Matches), result.Query, result.Epics)
+	for _, match := range result.Matches {
+		fmt.Fprintf(w, "\n%s  %s %s [%s]  %s\n", match.File, match.Type, match.ID, match.Status, match.Name)
+		fmt.Fprintf(w, "  %s: %s\n", strings.ReplaceAll(match.Field, "_", " "), match.Snippet)
+	}
+}
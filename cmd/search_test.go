@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestSearchCommand(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, ".agentpm.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"current_epic": "epic-8.xml", "epics": ["epic-9.xml"]}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "epic-8.xml"), []byte(workspaceEpicXML("8", "wip", "wip")), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "epic-9.xml"), []byte(strings.Replace(workspaceEpicXML("9", "wip", "wip"), "<tasks/>",
+		`<tasks><task id="P1_1" phase_id="P1" name="Deploy pipeline" status="wip"><acceptance_criteria>Build passes before we ship</acceptance_criteria></task></tasks>`, 1)), 0644))
+
+	run := func(args ...string) (string, error) {
+		app := &cli.Command{
+			Name:     "agentpm",
+			Flags:    []cli.Flag{&cli.StringFlag{Name: "config", Value: configPath}},
+			Commands: []*cli.Command{SearchCommand()},
+		}
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		err := app.Run(context.Background(), append([]string{"agentpm", "search"}, args...))
+		return stdout.String(), err
+	}
+
+	t.Run("text across the workspace", func(t *testing.T) {
+		output, err := run("ship")
+		require.NoError(t, err)
+		assert.Equal(t, `3 match(es) for "ship" in 2 epic(s):
+
+epic-8.xml  phase P2 [pending]  Ship
+  name: Ship
+
+epic-9.xml  task P1_1 [wip]  Deploy pipeline
+  acceptance criteria: Build passes before we ship
+
+epic-9.xml  phase P2 [pending]  Ship
+  name: Ship
+`, output)
+	})
+
+	t.Run("filters by type and status", func(t *testing.T) {
+		output, err := run("--format", "json", "--type", "phase", "--status", "wip", "build")
+		require.NoError(t, err)
+		var result searchOutput
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.Equal(t, 2, result.Epics)
+		require.Len(t, result.Matches, 2)
+		assert.Equal(t, "epic-8.xml", result.Matches[0].File)
+		assert.Equal(t, "P1", result.Matches[1].ID)
+
+		output, err = run("--status", "done", "build")
+		require.NoError(t, err)
+		assert.Equal(t, "No matches for \"build\" in 2 epic(s).\n", output)
+	})
+
+	t.Run("single file", func(t *testing.T) {
+		output, err := run("--file", filepath.Join(tempDir, "epic-9.xml"), "--format", "jsonl", "pipeline")
+		require.NoError(t, err)
+		assert.Equal(t, 1, strings.Count(output, "\n"))
+		assert.Contains(t, output, `"id":"P1_1"`)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		_, err := run()
+		assert.ErrorContains(t, err, "exactly one argument")
+		_, err = run("--type", "milestone", "ship")
+		assert.EqualError(t, err, "invalid entity type: milestone (must be epic, phase, task or test)")
+	})
+}
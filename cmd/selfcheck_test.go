@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/journal"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+const inconsistentEpic = `<?xml version="1.0" encoding="UTF-8"?>
+<epic id="epic-1" name="Inconsistent Epic" status="wip" created_at="2025-08-16T09:00:00Z">
+    <phases>
+        <phase id="P1" name="Phase 1" status="wip"/>
+        <phase id="P2" name="Phase 2" status="wip"/>
+    </phases>
+    <tasks>
+        <task id="T1" phase_id="P1" name="Task 1" status="pending"/>
+    </tasks>
+    <tests/>
+    <events/>
+</epic>`
+
+func TestCheckConsistency(t *testing.T) {
+	t.Cleanup(func() { storage.SetLoadCheck(nil) })
+
+	epicFile := filepath.Join(t.TempDir(), "epic.xml")
+	require.NoError(t, os.WriteFile(epicFile, []byte(inconsistentEpic), 0644))
+
+	run := func(args ...string) (string, error) {
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config", Value: filepath.Join(t.TempDir(), ".agentpm.json")},
+				&cli.StringFlag{Name: "format", Value: "text"},
+				&cli.BoolFlag{Name: "no-check"},
+			},
+			Before:   CheckConsistency,
+			Commands: []*cli.Command{StartCommand(), BlockerCommand()},
+		}
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		app.ErrWriter = &bytes.Buffer{}
+		err := app.Run(context.Background(), append([]string{"agentpm"}, args...))
+		return stdout.String(), err
+	}
+
+	t.Run("refuses to change an inconsistent epic", func(t *testing.T) {
+		_, err := run("start", "task", "T1", "--file", epicFile)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is inconsistent, refusing to change it further: phases P1, P2 are active at the same time")
+		assert.Equal(t, exitcode.Validation, ExitCode(err))
+
+		var classified *exitcode.Error
+		require.ErrorAs(t, err, &classified)
+		assert.Contains(t, classified.Hint, "agentpm validate")
+
+		_, err = run("blocker", "add", "Stuck", "--file", epicFile)
+		assert.Error(t, err)
+	})
+
+	t.Run("leaves read-only commands alone", func(t *testing.T) {
+		output, err := run("blocker", "list", "--file", epicFile)
+		require.NoError(t, err)
+		assert.Contains(t, output, "No open blockers")
+	})
+
+	t.Run("skips the check with --no-check", func(t *testing.T) {
+		_, err := run("--no-check", "start", "task", "T1", "--file", epicFile)
+		require.NoError(t, err)
+
+		epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, "wip", string(epicData.Tasks[0].Status))
+	})
+}
+
+const completedEpic = `<?xml version="1.0" encoding="UTF-8"?>
+<epic id="epic-1" name="Completed Epic" status="completed" created_at="2025-08-16T09:00:00Z">
+    <phases>
+        <phase id="1A" name="Phase 1" status="wip"/>
+    </phases>
+    <tasks>
+        <task id="1A_1" phase_id="1A" name="Task 1" status="wip"/>
+        <task id="1A_2" phase_id="1A" name="Task 2" status="pending"/>
+    </tasks>
+    <tests>
+        <test id="T1" task_id="1A_1" phase_id="1A" name="Test 1" status="wip" test_status="wip"/>
+    </tests>
+    <events/>
+</epic>`
+
+func TestGuardCompletedEpics(t *testing.T) {
+	t.Cleanup(func() { storage.SetSaveCheck(nil) })
+
+	dir := t.TempDir()
+	epicFile := filepath.Join(dir, "epic.xml")
+	configFile := filepath.Join(dir, ".agentpm.json")
+	require.NoError(t, os.WriteFile(configFile, []byte(`{"current_epic": "epic.xml", "custom_fields": {"task": {"sprint": {"type": "int"}}}}`), 0644))
+	run := func(args ...string) error {
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config", Value: configFile},
+				&cli.StringFlag{Name: "format", Value: "text"},
+				&cli.StringFlag{Name: "time", Value: "2025-08-17T09:00:00Z"},
+			},
+			Before: GuardCompletedEpics,
+			Commands: []*cli.Command{
+				StartCommand(), DoneCommand(), CancelCommand(), PassCommand(), FailCommand(),
+				AssignCommand(), EditCommand(), FreezeCommand(), NoteCommand(), BlockerCommand(),
+				ReopenCommand(), ResetCommand(), CompactCommand(), SummarizeEventsCommand(), UndoCommand(),
+			},
+		}
+		app.Writer = &bytes.Buffer{}
+		app.ErrWriter = &bytes.Buffer{}
+		return app.Run(context.Background(), append(append([]string{"agentpm"}, args...), "--file", epicFile, "--config", configFile))
+	}
+
+	for _, args := range [][]string{
+		{"done", "task", "1A_1"},
+		{"cancel", "task", "1A_1"},
+		{"pass", "T1"},
+		{"fail", "T1", "Broken"},
+		{"assign", "1A_2", "agent_b"},
+		{"edit", "task", "1A_1", "--set", "custom.sprint=7"},
+		{"freeze", "phase", "1A", "--reason", "Shipped"},
+		{"note", "add", "task", "1A_1", "Too late"},
+		{"blocker", "add", "Too late"},
+	} {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			require.NoError(t, os.WriteFile(epicFile, []byte(completedEpic), 0644))
+
+			err := run(args...)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "epic epic-1 is already completed")
+			assert.Equal(t, exitcode.Constraint, ExitCode(err))
+
+			content, err := os.ReadFile(epicFile)
+			require.NoError(t, err)
+			assert.Equal(t, completedEpic, string(content))
+		})
+	}
+
+	t.Run("resets, compaction and undo", func(t *testing.T) {
+		completed := createEpicForReset()
+		completed.Status = epic.StatusCompleted
+		for minute := range 3 {
+			at := time.Date(2025, 8, 1, 9, minute, 0, 0, time.UTC)
+			service.CreateEvent(completed, service.EventTestFailed, "P1", "T1", "T1_T1", "flaky", at)
+			service.CreateEvent(completed, service.EventTestPassed, "P1", "T1", "T1_T1", "", at.Add(30*time.Second))
+		}
+		storage.SetSaveCheck(nil)
+		require.NoError(t, storage.NewFileStorage().SaveEpic(completed, epicFile))
+		stored, err := os.ReadFile(epicFile)
+		require.NoError(t, err)
+		// An undo would restore the completed epic as it was before an edit
+		require.NoError(t, journal.Record(epicFile, journal.Entry{Operation: "edit", Before: string(stored), After: journal.Hash(stored)}))
+
+		for _, args := range [][]string{
+			{"reset", "phase", "P1", "--confirm"},
+			{"compact", "--before", "2025-08-10"},
+			{"summarize-events", "--window", "1h", "--min", "2"},
+			{"undo"},
+		} {
+			t.Run(args[0], func(t *testing.T) {
+				err := run(args...)
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "epic epic-1 is already completed")
+				assert.Equal(t, exitcode.Constraint, ExitCode(err))
+
+				content, err := os.ReadFile(epicFile)
+				require.NoError(t, err)
+				assert.Equal(t, string(stored), string(content))
+			})
+		}
+	})
+
+	t.Run("reopening the epic passes", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(epicFile, []byte(completedEpic), 0644))
+
+		require.NoError(t, run("reopen", "epic", "--reason", "Follow-up work"))
+		epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.NotEqual(t, epic.EpicStatusDone, epicData.GetEpicStatus())
+	})
+}
@@ -0,0 +1,440 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/apiversion"
+	"github.com/mindreframer/agentpm/internal/auth"
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/query"
+	"github.com/mindreframer/agentpm/internal/stats"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// ServeCommand serves the state of the epic over a read-only HTTP API
+func ServeCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "Serve the epic over a read-only HTTP API",
+		Description: `Serve the state of the current epic as JSON, so dashboards and other agents
+can read it without shelling out. Every request reads the epic file again,
+so the responses follow the changes commands make.
+
+Endpoints (GET or HEAD; other methods are rejected with 405):
+
+  /epic       The whole epic, as 'agentpm convert --to json' stores it
+  /status     Like 'agentpm status --format json'
+  /pending    Like 'agentpm pending --format json'; ?assignee=<agent>
+  /api/v1/epics/<id>/stats
+              Like 'agentpm stats --format json' for the served epic or another
+              epic registered in the config ('agentpm epics add'), by epic ID;
+              cached until the epic file changes
+  /events     Like 'agentpm events --format json'; ?limit=, ?type= (repeatable),
+              ?phase=, ?task=, ?since=, ?correlate=
+  /phases/<id>, /tasks/<id>, /tests/<id>
+              Like 'agentpm show <type> <id> --format json'; the links
+              'agentpm link' builds with server_url point here
+
+Responses are in the project's API version (see api_version in the config)
+unless a request asks for another with ?api_version=2, an "API-Version: 2"
+header or "Accept: application/vnd.agentpm.v2+json"; unsupported versions are
+rejected with 400. The API-Version response header names the version served.
+
+Once API tokens exist (see 'agentpm token'), requests need one as
+"Authorization: Bearer <token>"; any scope can read. Without tokens the API
+is open, so serve then only listens on a loopback host.
+
+Examples:
+  agentpm serve --port 8080
+  agentpm serve --host 0.0.0.0 --port 8080   # Needs API tokens
+  curl -H "Authorization: Bearer $TOKEN" localhost:8080/status`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config",
+			},
+			&cli.StringFlag{
+				Name:  "host",
+				Value: "127.0.0.1",
+				Usage: "Address to listen on",
+			},
+			&cli.IntFlag{
+				Name:  "port",
+				Value: 8080,
+				Usage: "Port to listen on (0 picks a free one)",
+			},
+		},
+		Action: serveAction,
+	}
+}
+
+func serveAction(ctx context.Context, c *cli.Command) error {
+	configPath := c.String("config")
+	if configPath == "" {
+		configPath = "./.agentpm.json"
+	}
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+	// The server never saves; reading without locks keeps it from blocking the
+	// commands that change the epic, whose saves replace the file atomically
+	epicStorage := storage.NewReadOnlyFileStorage()
+	if _, err := epicStorage.LoadEpic(epicFile); err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	store, err := auth.Load(cfg.TokensFilePath())
+	if err != nil {
+		return err
+	}
+	host := c.String("host")
+	if len(store.Tokens) == 0 && !isLoopback(host) {
+		return fmt.Errorf("refusing to serve an unauthenticated API on %s: create a token first ('agentpm token create <name> --scope read') or listen on 127.0.0.1", host)
+	}
+
+	server := &epicServer{storage: epicStorage, cfg: cfg, epicFile: epicFile, cmd: c, version: apiversion.FromContext(ctx)}
+	handler := server.handler()
+	if len(store.Tokens) > 0 {
+		handler = auth.Middleware(store, handler)
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(c.Int("port"))))
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	errWriter := c.Root().ErrWriter
+	if errWriter == nil {
+		errWriter = os.Stderr
+	}
+	access := fmt.Sprintf("%d API tokens", len(store.Tokens))
+	if len(store.Tokens) == 0 {
+		access = "no API tokens, unauthenticated"
+	}
+	fmt.Fprintf(errWriter, "Serving %s on http://%s (read-only, %s, Ctrl+C to stop)\n", epicFile, listener.Addr(), access)
+
+	httpServer := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server failed: %w", err)
+	}
+	return nil
+}
+
+// ServesReadOnly reports whether c runs serve, which reads the epic through a
+// read-only storage: the root hooks that lock the epic and check, journal or
+// record the changes of other commands do not apply to it
+func ServesReadOnly(c *cli.Command) bool {
+	words := commandWords(c)
+	return len(words) > 0 && words[0] == "serve"
+}
+
+// isLoopback reports whether host only accepts connections from this machine
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// epicServer answers the read API of serve from the epic file
+type epicServer struct {
+	storage  *storage.FileStorage // Read-only
+	cfg      *config.Config
+	epicFile string
+	cmd      *cli.Command       // For --time, which fixes the time status and events are computed at
+	version  apiversion.Version // Of requests that do not ask for one
+}
+
+func (s *epicServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/epic", s.serveEpic)
+	mux.HandleFunc("/status", s.serveStatus)
+	mux.HandleFunc("/pending", s.servePending)
+	mux.HandleFunc("/api/v1/epics/{id}/stats", s.serveStats(s.statsCaches()))
+	mux.HandleFunc("/events", s.serveEvents)
+	mux.HandleFunc("/phases/{id}", s.serveEntity("phase"))
+	mux.HandleFunc("/tasks/{id}", s.serveEntity("task"))
+	mux.HandleFunc("/tests/{id}", s.serveEntity("test"))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeServeError(w, http.StatusNotFound, fmt.Sprintf("no endpoint %s (expected /epic, /status, /pending, /events, /phases/<id>, /tasks/<id>, /tests/<id> or /api/v1/epics/<id>/stats)", r.URL.Path))
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeServeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("%s is not supported: the API is read-only", r.Method))
+			return
+		}
+		version, err := s.negotiateVersion(r)
+		if err != nil {
+			writeServeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		w.Header().Set("API-Version", strconv.Itoa(int(version)))
+		mux.ServeHTTP(w, r.WithContext(apiversion.NewContext(r.Context(), version)))
+	})
+}
+
+// negotiateVersion picks the output version of a request: ?api_version=, the
+// API-Version header or an application/vnd.agentpm.v<n>+json Accept header,
+// else the version the project uses
+func (s *epicServer) negotiateVersion(r *http.Request) (apiversion.Version, error) {
+	if requested := r.URL.Query().Get("api_version"); requested != "" {
+		return apiversion.Parse(requested)
+	}
+	if requested := r.Header.Get("API-Version"); requested != "" {
+		return apiversion.Negotiate(requested)
+	}
+	if accept := r.Header.Get("Accept"); strings.Contains(accept, "application/vnd.agentpm.") {
+		return apiversion.Negotiate(accept)
+	}
+	return s.version, nil
+}
+
+func (s *epicServer) now() (time.Time, error) {
+	if timeStr := s.cmd.String("time"); timeStr != "" {
+		now, err := time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+		}
+		return now, nil
+	}
+	return time.Now(), nil
+}
+
+func (s *epicServer) loadQueryService() (*query.QueryService, error) {
+	queryService := query.NewQueryService(s.storage)
+	if err := queryService.LoadEpic(s.epicFile); err != nil {
+		return nil, fmt.Errorf("failed to load epic: %w", err)
+	}
+	return queryService, nil
+}
+
+func (s *epicServer) serveEpic(w http.ResponseWriter, r *http.Request) {
+	epicData, err := s.storage.LoadEpic(s.epicFile)
+	if err != nil {
+		writeServeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to load epic: %v", err))
+		return
+	}
+	data, err := storage.EncodeEpic(epicData, storage.FormatJSON)
+	if err != nil {
+		writeServeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func (s *epicServer) serveStatus(w http.ResponseWriter, r *http.Request) {
+	now, err := s.now()
+	if err != nil {
+		writeServeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	status, err := loadEpicStatus(s.storage, s.cfg, s.epicFile, now)
+	if err != nil {
+		writeServeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeServeJSON(w, newStatusJSON(status, apiversion.FromContext(r.Context())))
+}
+
+func (s *epicServer) servePending(w http.ResponseWriter, r *http.Request) {
+	queryService, err := s.loadQueryService()
+	if err != nil {
+		writeServeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if agent := r.URL.Query().Get("assignee"); agent != "" {
+		if err := queryService.FilterAssignee(agent); err != nil {
+			writeServeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	pending, err := queryService.GetPendingWork()
+	if err != nil {
+		writeServeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to get pending work: %v", err))
+		return
+	}
+	writeServeJSON(w, newPendingOutput(pending))
+}
+
+// statsCaches returns a statistics cache for every epic serve answers
+// statistics of: the served epic first, then the others of the workspace
+func (s *epicServer) statsCaches() []*stats.Cache {
+	paths := []string{filepath.Clean(s.epicFile)}
+	for _, path := range s.cfg.EpicFilePaths() {
+		if path != "" && !slices.Contains(paths, filepath.Clean(path)) {
+			paths = append(paths, filepath.Clean(path))
+		}
+	}
+	caches := make([]*stats.Cache, len(paths))
+	for i, path := range paths {
+		caches[i] = stats.NewCache(path)
+	}
+	return caches
+}
+
+// serveStats answers the statistics of the epic with the requested ID from its
+// cache, which rebuilds them only when the epic file changes
+func (s *epicServer) serveStats(caches []*stats.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		now, err := s.now()
+		if err != nil {
+			writeServeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		id := r.PathValue("id")
+		var loadErr error
+		for _, cache := range caches {
+			epicID, data, err := cache.Document(now)
+			if err != nil {
+				// An epic that cannot be read is reported only when no other matches
+				if loadErr == nil {
+					loadErr = err
+				}
+				continue
+			}
+			if epicID == id {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(append(data, '\n'))
+				return
+			}
+		}
+		message := fmt.Sprintf("epic %s is not registered", id)
+		if loadErr != nil {
+			message += fmt.Sprintf(" (%v)", loadErr)
+		}
+		writeServeError(w, http.StatusNotFound, message)
+	}
+}
+
+func (s *epicServer) serveEvents(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	limit := 10
+	if value := params.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			writeServeError(w, http.StatusBadRequest, fmt.Sprintf("invalid limit %q (expected a positive number)", value))
+			return
+		}
+		limit = n
+	}
+
+	filter := query.EventFilter{
+		PhaseID: params.Get("phase"),
+		TaskID:  params.Get("task"),
+		Limit:   limit,
+
+		CorrelationID: params.Get("correlate"),
+	}
+	for _, value := range params["type"] {
+		filter.Types = append(filter.Types, strings.Split(value, ",")...)
+	}
+	if since := params.Get("since"); since != "" {
+		var err error
+		if filter.Since, err = parseEventsSince(s.cmd, since); err != nil {
+			writeServeError(w, http.StatusBadRequest, strings.Replace(err.Error(), "--since", "since", 1))
+			return
+		}
+	}
+
+	queryService, err := s.loadQueryService()
+	if err != nil {
+		writeServeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	events, err := queryService.GetRecentEvents(filter)
+	if err != nil {
+		writeServeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to get recent events: %v", err))
+		return
+	}
+	writeServeJSON(w, newEventsOutput(events, limit))
+}
+
+// serveEntity answers the link of a phase, task or test, see 'agentpm link'
+func (s *epicServer) serveEntity(entityType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		queryService, err := s.loadQueryService()
+		if err != nil {
+			writeServeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		id := r.PathValue("id")
+		var output map[string]interface{}
+		switch entityType {
+		case "phase":
+			var phase *epic.Phase
+			if phase, err = queryService.GetPhase(id); err == nil {
+				output = newPhaseJSON(phase, nil)
+			}
+		case "task":
+			var task *epic.Task
+			if task, err = queryService.GetTask(id); err == nil {
+				output = newTaskJSON(task, nil)
+			}
+		case "test":
+			var test *epic.Test
+			if test, err = queryService.GetTest(id); err == nil {
+				output = newTestJSON(test, nil)
+			}
+		}
+		if err != nil {
+			writeServeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		related, err := queryService.GetRelatedItems(entityType, id)
+		if err != nil {
+			writeServeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to get related items: %v", err))
+			return
+		}
+		output["related"] = related
+		writeServeJSON(w, output)
+	}
+}
+
+// writeServeJSON writes a response in the JSON the matching command prints
+func writeServeJSON(w http.ResponseWriter, value interface{}) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		writeServeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to marshal response: %v", err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(data, '\n'))
+}
+
+// writeServeError writes an error in the shape auth.Middleware rejects requests with
+func writeServeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
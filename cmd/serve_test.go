@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/apiversion"
+	"github.com/mindreframer/agentpm/internal/auth"
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/stats"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+const serveEpic = `<?xml version="1.0" encoding="UTF-8"?>
+<epic id="8" name="Served Epic" status="wip" created_at="2025-08-16T09:00:00Z">
+    <phases>
+        <phase id="1A" name="Setup" status="wip"/>
+    </phases>
+    <tasks>
+        <task id="1A_1" phase_id="1A" name="Schema" status="wip" assignee="agent_a"/>
+        <task id="1A_2" phase_id="1A" name="API" status="pending" assignee="agent_b"/>
+    </tasks>
+    <tests>
+        <test id="T1" task_id="1A_1" phase_id="1A" name="Schema works" status="pending"/>
+    </tests>
+    <events>
+        <event id="E1" type="phase_started" timestamp="2025-08-16T09:00:00Z" agent="agent_a" phase_id="1A">Phase 1A started</event>
+        <event id="E2" type="task_started" timestamp="2025-08-16T10:00:00Z" agent="agent_a" phase_id="1A" task_id="1A_1">Task 1A_1 started</event>
+    </events>
+</epic>
+`
+
+func TestServeAPI(t *testing.T) {
+	dir := t.TempDir()
+	epicFile := filepath.Join(dir, "epic-8.xml")
+	require.NoError(t, os.WriteFile(epicFile, []byte(serveEpic), 0644))
+	otherFile := filepath.Join(dir, "epic-9.xml")
+	require.NoError(t, os.WriteFile(otherFile, []byte(strings.NewReplacer(`id="8"`, `id="9"`, "Served Epic", "Other Epic").Replace(serveEpic)), 0644))
+
+	app := &cli.Command{Name: "agentpm", Flags: []cli.Flag{&cli.StringFlag{Name: "time", Value: "2025-08-16T12:00:00Z"}}}
+	cfg := &config.Config{Epics: []string{otherFile, filepath.Join(dir, "missing.xml")}}
+	server := &epicServer{storage: storage.NewReadOnlyFileStorage(), cfg: cfg, epicFile: epicFile, cmd: app, version: apiversion.Default}
+	handler := server.handler()
+
+	get := func(t *testing.T, method, path string, value interface{}) *httptest.ResponseRecorder {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+		if value != nil {
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), value), recorder.Body.String())
+		}
+		return recorder
+	}
+
+	t.Run("epic", func(t *testing.T) {
+		var result map[string]interface{}
+		response := get(t, http.MethodGet, "/epic", &result)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+		assert.Equal(t, "Served Epic", result["name"])
+		assert.Len(t, result["tasks"], 2)
+	})
+
+	t.Run("status", func(t *testing.T) {
+		var result statusOutput
+		response := get(t, http.MethodGet, "/status", &result)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "8", result.Epic)
+		assert.Equal(t, "1A", result.CurrentPhase)
+		assert.Equal(t, "1A_1", result.CurrentTask)
+	})
+
+	t.Run("stats are cached until the epic file changes", func(t *testing.T) {
+		var result stats.Stats
+		response := get(t, http.MethodGet, "/api/v1/epics/8/stats", &result)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "8", result.EpicID)
+		assert.Equal(t, "Served Epic", result.Name)
+
+		// Same size and modification time: the cached document is served
+		info, err := os.Stat(epicFile)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(epicFile, []byte(strings.Replace(serveEpic, "Served Epic", "Cached Epic", 1)), 0644))
+		require.NoError(t, os.Chtimes(epicFile, info.ModTime(), info.ModTime()))
+		get(t, http.MethodGet, "/api/v1/epics/8/stats", &result)
+		assert.Equal(t, "Served Epic", result.Name)
+
+		later := info.ModTime().Add(time.Second)
+		require.NoError(t, os.Chtimes(epicFile, later, later))
+		get(t, http.MethodGet, "/api/v1/epics/8/stats", &result)
+		assert.Equal(t, "Cached Epic", result.Name)
+
+		require.NoError(t, os.WriteFile(epicFile, []byte(serveEpic), 0644))
+	})
+
+	t.Run("stats of the registered epics by ID", func(t *testing.T) {
+		var result stats.Stats
+		response := get(t, http.MethodGet, "/api/v1/epics/9/stats", &result)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "9", result.EpicID)
+		assert.Equal(t, "Other Epic", result.Name)
+
+		response = get(t, http.MethodGet, "/api/v1/epics/42/stats", nil)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+		assert.Contains(t, response.Body.String(), "epic 42 is not registered")
+
+		response = get(t, http.MethodGet, "/stats", nil)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("status in the requested API version", func(t *testing.T) {
+		request := func(path string, header http.Header) *httptest.ResponseRecorder {
+			recorder := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, path, nil)
+			for name, values := range header {
+				r.Header[name] = values
+			}
+			handler.ServeHTTP(recorder, r)
+			return recorder
+		}
+
+		response := request("/status", nil)
+		assert.Equal(t, "1", response.Header().Get("API-Version"))
+		assert.Contains(t, response.Body.String(), `"epic13_status"`)
+
+		for _, response := range []*httptest.ResponseRecorder{
+			request("/status?api_version=2", nil),
+			request("/status", http.Header{"Api-Version": {"v2"}}),
+			request("/status", http.Header{"Accept": {"application/vnd.agentpm.v2+json"}}),
+		} {
+			assert.Equal(t, http.StatusOK, response.Code)
+			assert.Equal(t, "2", response.Header().Get("API-Version"))
+			assert.Contains(t, response.Body.String(), `"completion"`)
+			assert.NotContains(t, response.Body.String(), `"epic13_status"`)
+		}
+
+		response = request("/status", http.Header{"Api-Version": {"1"}, "Accept": {"application/json"}})
+		assert.Contains(t, response.Body.String(), `"epic13_status"`)
+
+		for _, response := range []*httptest.ResponseRecorder{
+			request("/status?api_version=7", nil),
+			request("/status", http.Header{"Api-Version": {"7"}}),
+			request("/status", http.Header{"Accept": {"application/vnd.agentpm.v9+json"}}),
+		} {
+			assert.Equal(t, http.StatusBadRequest, response.Code)
+			assert.Contains(t, response.Body.String(), "unsupported API version")
+		}
+	})
+
+	t.Run("pending for an assignee", func(t *testing.T) {
+		var result pendingOutput
+		response := get(t, http.MethodGet, "/pending?assignee=agent_b", &result)
+		assert.Equal(t, http.StatusOK, response.Code)
+		require.Len(t, result.Tasks, 1)
+		assert.Equal(t, "1A_2", result.Tasks[0].ID)
+	})
+
+	t.Run("events", func(t *testing.T) {
+		var result eventsOutput
+		response := get(t, http.MethodGet, "/events?limit=5&type=task", &result)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, 5, result.Limit)
+		require.Len(t, result.Events, 1)
+		assert.Equal(t, "E2", result.Events[0].ID)
+
+		response = get(t, http.MethodGet, "/events?limit=none", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Contains(t, response.Body.String(), `invalid limit \"none\"`)
+	})
+
+	t.Run("follows changes to the epic", func(t *testing.T) {
+		epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+		require.NoError(t, err)
+		epicData.Name = "Renamed Epic"
+		require.NoError(t, storage.NewFileStorage().SaveEpic(epicData, epicFile))
+
+		var result statusOutput
+		get(t, http.MethodGet, "/status", &result)
+		assert.Equal(t, "Renamed Epic", result.Name)
+	})
+
+	t.Run("read-only", func(t *testing.T) {
+		response := get(t, http.MethodPost, "/status", nil)
+		assert.Equal(t, http.StatusMethodNotAllowed, response.Code)
+		assert.Equal(t, "GET, HEAD", response.Header().Get("Allow"))
+	})
+
+	t.Run("entities", func(t *testing.T) {
+		var result map[string]interface{}
+		response := get(t, http.MethodGet, "/tests/T1", &result)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "Schema works", result["name"])
+		assert.Equal(t, "1A_1", result["task_id"])
+
+		response = get(t, http.MethodGet, "/tasks/9Z_9", nil)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+		assert.Contains(t, response.Body.String(), "task 9Z_9 not found")
+	})
+
+	t.Run("links resolve", func(t *testing.T) {
+		configFile := filepath.Join(dir, ".agentpm.json")
+		require.NoError(t, os.WriteFile(configFile, []byte(`{"current_epic": "epic-8.xml", "server_url": "http://agentpm.example.com/"}`), 0644))
+
+		for _, entity := range [][2]string{{"phase", "1A"}, {"task", "1A_2"}, {"test", "T1"}} {
+			output, err := runApp(t, linkApp.withConfig(configFile), "link", entity[0], entity[1], "--file", epicFile)
+			require.NoError(t, err)
+			link, err := url.Parse(strings.TrimSpace(output))
+			require.NoError(t, err)
+
+			var result map[string]interface{}
+			response := get(t, http.MethodGet, link.Path, &result)
+			assert.Equal(t, http.StatusOK, response.Code, link.String())
+			assert.Equal(t, entity[1], result["id"])
+		}
+	})
+
+	t.Run("unknown endpoint", func(t *testing.T) {
+		response := get(t, http.MethodGet, "/milestones", nil)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+		assert.Contains(t, response.Body.String(), "no endpoint /milestones")
+	})
+}
+
+func TestServeCommand(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, ".agentpm.json")
+	require.NoError(t, os.WriteFile(configFile, []byte(`{"current_epic": "epic-8.xml"}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "epic-8.xml"), []byte(serveEpic), 0644))
+
+	run := func(ctx context.Context, args ...string) (string, error) {
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config", Value: configFile},
+			},
+			Commands: []*cli.Command{ServeCommand()},
+		}
+		var stderr bytes.Buffer
+		app.ErrWriter = &stderr
+		err := app.Run(ctx, append([]string{"agentpm"}, args...))
+		return stderr.String(), err
+	}
+
+	t.Run("refuses an open API beyond loopback", func(t *testing.T) {
+		_, err := run(context.Background(), "serve", "--host", "0.0.0.0", "--port", "0")
+		assert.ErrorContains(t, err, "refusing to serve an unauthenticated API on 0.0.0.0")
+	})
+
+	t.Run("serves until cancelled", func(t *testing.T) {
+		store, err := auth.Load(filepath.Join(dir, config.DefaultTokensFile))
+		require.NoError(t, err)
+		_, err = store.Create("dashboard", auth.ScopeRead, time.Now())
+		require.NoError(t, err)
+		require.NoError(t, store.Save())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		output, err := run(ctx, "serve", "--port", "0")
+		require.NoError(t, err)
+		assert.Contains(t, output, "epic-8.xml on http://127.0.0.1:")
+		assert.Contains(t, output, "read-only, 1 API tokens")
+	})
+}
+
+func TestServesReadOnly(t *testing.T) {
+	servesReadOnly := func(args ...string) bool {
+		var result bool
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "file"},
+			},
+			Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+				result = ServesReadOnly(c)
+				return ctx, nil
+			},
+			Commands: []*cli.Command{
+				{Name: "serve", Flags: []cli.Flag{&cli.IntFlag{Name: "port"}}, Action: func(context.Context, *cli.Command) error { return nil }},
+				{Name: "status", Action: func(context.Context, *cli.Command) error { return nil }},
+			},
+		}
+		require.NoError(t, app.Run(context.Background(), append([]string{"agentpm"}, args...)))
+		return result
+	}
+
+	assert.True(t, servesReadOnly("serve", "--port", "0"))
+	assert.True(t, servesReadOnly("--file", "epic.xml", "serve"))
+	assert.False(t, servesReadOnly("status"))
+}
@@ -224,7 +224,8 @@ func writeServiceValidationResult(c *cli.Command, format string, result *service
 		if len(vr.Checks) > 0 {
 			output += `
     <checks_performed>`
-			for name, status := range vr.Checks {
+			for _, name := range vr.CheckNames() {
+				status := vr.Checks[name]
 				output += fmt.Sprintf(`
         <check name="%s">%s</check>`, name, status)
 			}
@@ -272,7 +273,8 @@ func writeServiceValidationResult(c *cli.Command, format string, result *service
 			output += `,
   "checks_performed": {`
 			i := 0
-			for name, status := range vr.Checks {
+			for _, name := range vr.CheckNames() {
+				status := vr.Checks[name]
 				if i > 0 {
 					output += `, `
 				}
@@ -4,10 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/mindreframer/agentpm/internal/config"
 	contextpkg "github.com/mindreframer/agentpm/internal/context"
+	"github.com/mindreframer/agentpm/internal/customfields"
 	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/output"
 	"github.com/mindreframer/agentpm/internal/query"
 	"github.com/mindreframer/agentpm/internal/storage"
 	"github.com/urfave/cli/v3"
@@ -27,12 +32,13 @@ Entity types:
   task <id>   - Show specific task details  
   test <id>   - Show specific test details
 
-Output formats: text (default), json, xml
+Output formats: text (default), json, xml, markdown (not with --full yet)
 
 The --full flag provides comprehensive context with complete details for all related entities:
 - For tasks: Shows parent phase, sibling tasks, and child tests with full details
 - For phases: Shows all tasks and tests in the phase with complete information
 - For tests: Shows parent task, parent phase, and sibling tests with full details
+- For all entities, including the epic: Shows the notes left with 'agentpm note add'
 
 Examples:
   agentpm show epic                          # Show complete epic
@@ -50,15 +56,16 @@ Examples:
 			&cli.StringFlag{
 				Name:    "format",
 				Aliases: []string{"F"},
-				Usage:   "Output format: text (default), json, xml",
+				Usage:   "Output format: text (default), json, xml, markdown",
 				Value:   "text",
 			},
 			&cli.BoolFlag{
 				Name:  "full",
 				Usage: "Display full context with complete details for all related entities",
 			},
+			fieldsFlag(),
 		},
-		Action: showAction,
+		Action: withFieldSelection(showAction),
 	}
 }
 
@@ -79,7 +86,7 @@ func showAction(ctx context.Context, c *cli.Command) error {
 		// Epic doesn't need an ID
 	case "phase", "task", "test":
 		if entityID == "" {
-			return fmt.Errorf("%s requires an ID", entityType)
+			return exitcode.Errorf(exitcode.Validation, "%s requires an ID", entityType)
 		}
 	default:
 		return fmt.Errorf("invalid entity type: %s (must be epic, phase, task, or test)", entityType)
@@ -99,10 +106,10 @@ func showAction(ctx context.Context, c *cli.Command) error {
 	// Determine epic file (command flag overrides config)
 	epicFile := c.String("file")
 	if epicFile == "" {
-		epicFile = cfg.CurrentEpic
+		epicFile = cfg.EpicFilePath()
 	}
 	if epicFile == "" {
-		return fmt.Errorf("no epic file specified. Use --file flag or run 'agentpm init' first")
+		return exitcode.Errorf(exitcode.Validation, "no epic file specified. Use --file flag or run 'agentpm init' first")
 	}
 
 	// Create storage and query service
@@ -122,7 +129,7 @@ func showAction(ctx context.Context, c *cli.Command) error {
 	// Handle entity display
 	switch entityType {
 	case "epic":
-		return showEpic(c, queryService, outputFormat)
+		return showEpic(c, queryService, outputFormat, useFullContext)
 	case "phase":
 		return showPhase(c, queryService, entityID, outputFormat, useFullContext)
 	case "task":
@@ -134,7 +141,7 @@ func showAction(ctx context.Context, c *cli.Command) error {
 	return nil
 }
 
-func showEpic(c *cli.Command, qs *query.QueryService, format string) error {
+func showEpic(c *cli.Command, qs *query.QueryService, format string, useFullContext bool) error {
 	epic, err := qs.GetEpic()
 	if err != nil {
 		return fmt.Errorf("failed to get epic: %w", err)
@@ -144,9 +151,11 @@ func showEpic(c *cli.Command, qs *query.QueryService, format string) error {
 	case "json":
 		return outputEpicJSON(c, epic)
 	case "xml":
-		return outputEpicXML(c, epic)
+		return outputEpicXML(c, epic, useFullContext)
+	case "markdown":
+		return outputEpicMarkdown(c, epic)
 	default:
-		return outputEpicText(c, epic)
+		return outputEpicText(c, epic, useFullContext)
 	}
 }
 
@@ -180,6 +189,8 @@ func showPhase(c *cli.Command, qs *query.QueryService, phaseID, format string, u
 		return outputPhaseJSON(c, phase, related)
 	case "xml":
 		return outputPhaseXML(c, phase, related)
+	case "markdown":
+		return outputPhaseMarkdown(c, phase, related)
 	default:
 		return outputPhaseText(c, phase, related)
 	}
@@ -215,6 +226,8 @@ func showTask(c *cli.Command, qs *query.QueryService, taskID, format string, use
 		return outputTaskJSON(c, task, related)
 	case "xml":
 		return outputTaskXML(c, task, related)
+	case "markdown":
+		return outputTaskMarkdown(c, task, related)
 	default:
 		return outputTaskText(c, task, related)
 	}
@@ -250,13 +263,15 @@ func showTest(c *cli.Command, qs *query.QueryService, testID, format string, use
 		return outputTestJSON(c, test, related)
 	case "xml":
 		return outputTestXML(c, test, related)
+	case "markdown":
+		return outputTestMarkdown(c, test, related)
 	default:
 		return outputTestText(c, test, related)
 	}
 }
 
 // Epic output functions
-func outputEpicText(c *cli.Command, epic *epic.Epic) error {
+func outputEpicText(c *cli.Command, epic *epic.Epic, withNotes bool) error {
 	fmt.Fprintf(c.Root().Writer, "Epic: %s\n", epic.Name)
 	fmt.Fprintf(c.Root().Writer, "ID: %s\n", epic.ID)
 	fmt.Fprintf(c.Root().Writer, "Status: %s\n", epic.Status)
@@ -279,6 +294,13 @@ func outputEpicText(c *cli.Command, epic *epic.Epic) error {
 		fmt.Fprintf(c.Root().Writer, "  %s (%s) - %s [%s]\n", test.ID, test.TaskID, test.Name, test.Status)
 	}
 
+	if withNotes && len(epic.Notes) > 0 {
+		fmt.Fprintf(c.Root().Writer, "\nNotes (%d):\n", len(epic.Notes))
+		for _, note := range epic.Notes {
+			fmt.Fprintf(c.Root().Writer, "  %s\n", formatNote(note))
+		}
+	}
+
 	return nil
 }
 
@@ -291,7 +313,7 @@ func outputEpicJSON(c *cli.Command, epic *epic.Epic) error {
 	return nil
 }
 
-func outputEpicXML(c *cli.Command, epic *epic.Epic) error {
+func outputEpicXML(c *cli.Command, epic *epic.Epic, withNotes bool) error {
 	fmt.Fprintf(c.Root().Writer, "<epic id=\"%s\" status=\"%s\">\n", epic.ID, epic.Status)
 	fmt.Fprintf(c.Root().Writer, "    <name>%s</name>\n", epic.Name)
 	if epic.Description != "" {
@@ -331,6 +353,18 @@ func outputEpicXML(c *cli.Command, epic *epic.Epic) error {
 	}
 	fmt.Fprintf(c.Root().Writer, "    </tests>\n")
 
+	if withNotes && len(epic.Notes) > 0 {
+		fmt.Fprintf(c.Root().Writer, "    <notes>\n")
+		for _, note := range epic.Notes {
+			fmt.Fprintf(c.Root().Writer, "        <note")
+			if note.Author != "" {
+				fmt.Fprintf(c.Root().Writer, " author=\"%s\"", xmlText(note.Author))
+			}
+			fmt.Fprintf(c.Root().Writer, " created_at=\"%s\">%s</note>\n", note.CreatedAt.Format(time.RFC3339), xmlText(note.Text))
+		}
+		fmt.Fprintf(c.Root().Writer, "    </notes>\n")
+	}
+
 	fmt.Fprintf(c.Root().Writer, "</epic>\n")
 	return nil
 }
@@ -343,6 +377,14 @@ func outputPhaseText(c *cli.Command, phase *epic.Phase, related []query.RelatedI
 	if phase.Description != "" {
 		fmt.Fprintf(c.Root().Writer, "Description: %s\n", phase.Description)
 	}
+	if phase.SpecRef != "" {
+		fmt.Fprintf(c.Root().Writer, "Spec: %s\n", phase.SpecRef)
+	}
+	if phase.IsFrozen() {
+		fmt.Fprintf(c.Root().Writer, "Frozen: %s (%s)\n", phase.FrozenAt.Format(time.RFC3339), phase.FrozenReason)
+	}
+	outputCustomFieldsText(c, phase.Custom)
+	outputDependenciesText(c, related)
 
 	// Show related tasks
 	var tasks []query.RelatedItem
@@ -356,9 +398,7 @@ func outputPhaseText(c *cli.Command, phase *epic.Phase, related []query.RelatedI
 	if len(tasks) == 0 {
 		fmt.Fprintf(c.Root().Writer, "  (none)\n")
 	} else {
-		for _, task := range tasks {
-			fmt.Fprintf(c.Root().Writer, "  %s - %s\n", task.ID, task.Name)
-		}
+		outputTaskTreeText(c, tasks)
 	}
 
 	// Show related tests
@@ -381,7 +421,37 @@ func outputPhaseText(c *cli.Command, phase *epic.Phase, related []query.RelatedI
 	return nil
 }
 
+// outputTaskTreeText lists tasks with their sub-tasks indented below them;
+// sub-tasks whose parent is not listed are shown like top-level tasks
+func outputTaskTreeText(c *cli.Command, tasks []query.RelatedItem) {
+	listed := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		listed[task.ID] = true
+	}
+	for _, task := range tasks {
+		if listed[task.ParentID] {
+			continue
+		}
+		fmt.Fprintf(c.Root().Writer, "  %s - %s\n", task.ID, task.Name)
+		for _, subTask := range tasks {
+			if subTask.ParentID == task.ID {
+				fmt.Fprintf(c.Root().Writer, "    %s - %s\n", subTask.ID, subTask.Name)
+			}
+		}
+	}
+}
+
 func outputPhaseJSON(c *cli.Command, phase *epic.Phase, related []query.RelatedItem) error {
+	jsonData, err := json.MarshalIndent(newPhaseJSON(phase, related), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal phase to JSON: %w", err)
+	}
+	fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	return nil
+}
+
+// newPhaseJSON is the phase as 'show phase --format json' prints it
+func newPhaseJSON(phase *epic.Phase, related []query.RelatedItem) map[string]interface{} {
 	output := map[string]interface{}{
 		"id":          phase.ID,
 		"name":        phase.Name,
@@ -389,13 +459,23 @@ func outputPhaseJSON(c *cli.Command, phase *epic.Phase, related []query.RelatedI
 		"description": phase.Description,
 		"related":     related,
 	}
-
-	jsonData, err := json.MarshalIndent(output, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal phase to JSON: %w", err)
+	if phase.SpecRef != "" {
+		output["spec_ref"] = phase.SpecRef
 	}
-	fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
-	return nil
+	if ids := phase.DependencyIDs(); len(ids) > 0 {
+		output["depends_on"] = ids
+	}
+	if phase.IsFrozen() {
+		output["frozen"] = map[string]interface{}{
+			"frozen_at": phase.FrozenAt.Format(time.RFC3339),
+			"reason":    phase.FrozenReason,
+		}
+	}
+	if len(phase.Custom) > 0 {
+		output["custom"] = customFieldsJSON(phase.Custom)
+	}
+
+	return output
 }
 
 func outputPhaseXML(c *cli.Command, phase *epic.Phase, related []query.RelatedItem) error {
@@ -404,6 +484,16 @@ func outputPhaseXML(c *cli.Command, phase *epic.Phase, related []query.RelatedIt
 	if phase.Description != "" {
 		fmt.Fprintf(c.Root().Writer, "    <description>%s</description>\n", phase.Description)
 	}
+	if phase.SpecRef != "" {
+		fmt.Fprintf(c.Root().Writer, "    <spec_ref>%s</spec_ref>\n", phase.SpecRef)
+	}
+	if phase.DependsOn != "" {
+		fmt.Fprintf(c.Root().Writer, "    <depends_on>%s</depends_on>\n", strings.Join(phase.DependencyIDs(), ","))
+	}
+	if phase.IsFrozen() {
+		fmt.Fprintf(c.Root().Writer, "    <frozen frozen_at=\"%s\">%s</frozen>\n", phase.FrozenAt.Format(time.RFC3339), phase.FrozenReason)
+	}
+	outputCustomFieldsXML(c, phase.Custom)
 
 	fmt.Fprintf(c.Root().Writer, "    <related>\n")
 	for _, item := range related {
@@ -416,6 +506,52 @@ func outputPhaseXML(c *cli.Command, phase *epic.Phase, related []query.RelatedIt
 	return nil
 }
 
+// outputCustomFieldsText prints the custom fields of a phase, task or test
+func outputCustomFieldsText(c *cli.Command, custom epic.CustomFields) {
+	for _, field := range custom {
+		fmt.Fprintf(c.Root().Writer, "%s%s: %s\n", customfields.Prefix, field.Name, field.Value)
+	}
+}
+
+// customFieldsJSON maps the names of custom fields to their values
+func customFieldsJSON(custom epic.CustomFields) map[string]string {
+	values := make(map[string]string, len(custom))
+	for _, field := range custom {
+		values[field.Name] = field.Value
+	}
+	return values
+}
+
+func outputCustomFieldsXML(c *cli.Command, custom epic.CustomFields) {
+	if len(custom) == 0 {
+		return
+	}
+	fmt.Fprintf(c.Root().Writer, "    <custom>\n")
+	for _, field := range custom {
+		fmt.Fprintf(c.Root().Writer, "        <field name=\"%s\">%s</field>\n", field.Name, field.Value)
+	}
+	fmt.Fprintf(c.Root().Writer, "    </custom>\n")
+}
+
+// outputDependenciesText prints the dependency and dependent items of a phase or task
+func outputDependenciesText(c *cli.Command, related []query.RelatedItem) {
+	var dependencies, dependents []string
+	for _, item := range related {
+		switch item.Relationship {
+		case "dependency":
+			dependencies = append(dependencies, item.ID)
+		case "dependent":
+			dependents = append(dependents, item.ID)
+		}
+	}
+	if len(dependencies) > 0 {
+		fmt.Fprintf(c.Root().Writer, "Depends on: %s\n", strings.Join(dependencies, ", "))
+	}
+	if len(dependents) > 0 {
+		fmt.Fprintf(c.Root().Writer, "Required by: %s\n", strings.Join(dependents, ", "))
+	}
+}
+
 // Task output functions
 func outputTaskText(c *cli.Command, task *epic.Task, related []query.RelatedItem) error {
 	fmt.Fprintf(c.Root().Writer, "Task: %s\n", task.Name)
@@ -425,14 +561,42 @@ func outputTaskText(c *cli.Command, task *epic.Task, related []query.RelatedItem
 	if task.Description != "" {
 		fmt.Fprintf(c.Root().Writer, "Description: %s\n", task.Description)
 	}
+	if task.SpecRef != "" {
+		fmt.Fprintf(c.Root().Writer, "Spec: %s\n", task.SpecRef)
+	}
+	if task.IsRecurring() {
+		fmt.Fprintf(c.Root().Writer, "Recurring: every %s\n", task.Recurring)
+	}
+	outputCustomFieldsText(c, task.Custom)
 
-	// Show parent phase
+	// Show parent phase and, for a sub-task, parent task
 	for _, item := range related {
 		if item.Type == "phase" && item.Relationship == "parent" {
 			fmt.Fprintf(c.Root().Writer, "Parent Phase: %s - %s\n", item.ID, item.Name)
 			break
 		}
 	}
+	for _, item := range related {
+		if item.Type == "task" && item.Relationship == "parent" {
+			fmt.Fprintf(c.Root().Writer, "Parent Task: %s - %s\n", item.ID, item.Name)
+			break
+		}
+	}
+	outputDependenciesText(c, related)
+
+	// Show sub-tasks
+	var subTasks []query.RelatedItem
+	for _, item := range related {
+		if item.Relationship == "subtask" {
+			subTasks = append(subTasks, item)
+		}
+	}
+	if len(subTasks) > 0 {
+		fmt.Fprintf(c.Root().Writer, "\nSub-tasks (%d):\n", len(subTasks))
+		for _, subTask := range subTasks {
+			fmt.Fprintf(c.Root().Writer, "  %s - %s\n", subTask.ID, subTask.Name)
+		}
+	}
 
 	// Show related tests
 	var tests []query.RelatedItem
@@ -455,6 +619,16 @@ func outputTaskText(c *cli.Command, task *epic.Task, related []query.RelatedItem
 }
 
 func outputTaskJSON(c *cli.Command, task *epic.Task, related []query.RelatedItem) error {
+	jsonData, err := json.MarshalIndent(newTaskJSON(task, related), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal task to JSON: %w", err)
+	}
+	fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	return nil
+}
+
+// newTaskJSON is the task as 'show task --format json' prints it
+func newTaskJSON(task *epic.Task, related []query.RelatedItem) map[string]interface{} {
 	output := map[string]interface{}{
 		"id":          task.ID,
 		"phase_id":    task.PhaseID,
@@ -463,21 +637,45 @@ func outputTaskJSON(c *cli.Command, task *epic.Task, related []query.RelatedItem
 		"description": task.Description,
 		"related":     related,
 	}
-
-	jsonData, err := json.MarshalIndent(output, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal task to JSON: %w", err)
+	if task.SpecRef != "" {
+		output["spec_ref"] = task.SpecRef
 	}
-	fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
-	return nil
+	if ids := task.DependencyIDs(); len(ids) > 0 {
+		output["depends_on"] = ids
+	}
+	if task.ParentTaskID != "" {
+		output["parent_task_id"] = task.ParentTaskID
+	}
+	if task.IsRecurring() {
+		output["recurring"] = task.Recurring
+	}
+	if len(task.Custom) > 0 {
+		output["custom"] = customFieldsJSON(task.Custom)
+	}
+
+	return output
 }
 
 func outputTaskXML(c *cli.Command, task *epic.Task, related []query.RelatedItem) error {
-	fmt.Fprintf(c.Root().Writer, "<task id=\"%s\" phase_id=\"%s\" status=\"%s\">\n", task.ID, task.PhaseID, task.Status)
+	fmt.Fprintf(c.Root().Writer, "<task id=\"%s\" phase_id=\"%s\"", task.ID, task.PhaseID)
+	if task.ParentTaskID != "" {
+		fmt.Fprintf(c.Root().Writer, " parent_task_id=\"%s\"", task.ParentTaskID)
+	}
+	if task.IsRecurring() {
+		fmt.Fprintf(c.Root().Writer, " recurring=\"%s\"", task.Recurring)
+	}
+	fmt.Fprintf(c.Root().Writer, " status=\"%s\">\n", task.Status)
 	fmt.Fprintf(c.Root().Writer, "    <name>%s</name>\n", task.Name)
 	if task.Description != "" {
 		fmt.Fprintf(c.Root().Writer, "    <description>%s</description>\n", task.Description)
 	}
+	if task.SpecRef != "" {
+		fmt.Fprintf(c.Root().Writer, "    <spec_ref>%s</spec_ref>\n", task.SpecRef)
+	}
+	if task.DependsOn != "" {
+		fmt.Fprintf(c.Root().Writer, "    <depends_on>%s</depends_on>\n", strings.Join(task.DependencyIDs(), ","))
+	}
+	outputCustomFieldsXML(c, task.Custom)
 
 	fmt.Fprintf(c.Root().Writer, "    <related>\n")
 	for _, item := range related {
@@ -499,6 +697,11 @@ func outputTestText(c *cli.Command, test *epic.Test, related []query.RelatedItem
 	if test.Description != "" {
 		fmt.Fprintf(c.Root().Writer, "Description: %s\n", test.Description)
 	}
+	if test.FailureNote != "" {
+		fmt.Fprintf(c.Root().Writer, "Failure: %s\n", test.FailureNote)
+	}
+	writeFailureContextText(c.Root().Writer, "", test.FailureContext)
+	outputCustomFieldsText(c, test.Custom)
 
 	// Show parent task and phase
 	for _, item := range related {
@@ -514,6 +717,16 @@ func outputTestText(c *cli.Command, test *epic.Test, related []query.RelatedItem
 }
 
 func outputTestJSON(c *cli.Command, test *epic.Test, related []query.RelatedItem) error {
+	jsonData, err := json.MarshalIndent(newTestJSON(test, related), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal test to JSON: %w", err)
+	}
+	fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	return nil
+}
+
+// newTestJSON is the test as 'show test --format json' prints it
+func newTestJSON(test *epic.Test, related []query.RelatedItem) map[string]interface{} {
 	output := map[string]interface{}{
 		"id":          test.ID,
 		"task_id":     test.TaskID,
@@ -522,13 +735,17 @@ func outputTestJSON(c *cli.Command, test *epic.Test, related []query.RelatedItem
 		"description": test.Description,
 		"related":     related,
 	}
-
-	jsonData, err := json.MarshalIndent(output, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal test to JSON: %w", err)
+	if test.FailureNote != "" {
+		output["failure_note"] = test.FailureNote
 	}
-	fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
-	return nil
+	if !test.FailureContext.IsEmpty() {
+		output["failure_context"] = test.FailureContext
+	}
+	if len(test.Custom) > 0 {
+		output["custom"] = customFieldsJSON(test.Custom)
+	}
+
+	return output
 }
 
 func outputTestXML(c *cli.Command, test *epic.Test, related []query.RelatedItem) error {
@@ -537,6 +754,11 @@ func outputTestXML(c *cli.Command, test *epic.Test, related []query.RelatedItem)
 	if test.Description != "" {
 		fmt.Fprintf(c.Root().Writer, "    <description>%s</description>\n", test.Description)
 	}
+	if test.FailureNote != "" {
+		fmt.Fprintf(c.Root().Writer, "    <failure_note>%s</failure_note>\n", xmlText(test.FailureNote))
+	}
+	writeFailureContextXML(c.Root().Writer, "    ", test.FailureContext)
+	outputCustomFieldsXML(c, test.Custom)
 
 	fmt.Fprintf(c.Root().Writer, "    <related>\n")
 	for _, item := range related {
@@ -548,3 +770,192 @@ func outputTestXML(c *cli.Command, test *epic.Test, related []query.RelatedItem)
 	fmt.Fprintf(c.Root().Writer, "</test>\n")
 	return nil
 }
+
+// Markdown output functions, for pasting into pull requests and issue trackers
+
+func outputEpicMarkdown(c *cli.Command, epic *epic.Epic) error {
+	md := output.NewMarkdown()
+	md.Heading(2, fmt.Sprintf("Epic: %s", epic.Name))
+	md.Field("ID", epic.ID)
+	md.Field("Status", string(epic.Status))
+	if epic.Description != "" {
+		md.Paragraph("%s", output.Inline(epic.Description))
+	}
+
+	md.Heading(3, fmt.Sprintf("Phases (%d)", len(epic.Phases)))
+	var rows [][]string
+	for _, phase := range epic.Phases {
+		rows = append(rows, []string{output.Code(phase.ID), phase.Name, string(phase.Status)})
+	}
+	md.Table([]string{"Phase", "Name", "Status"}, rows)
+
+	md.Heading(3, fmt.Sprintf("Tasks (%d)", len(epic.Tasks)))
+	rows = nil
+	for _, task := range epic.Tasks {
+		rows = append(rows, []string{output.Code(task.ID), output.Code(task.PhaseID), task.Name, string(task.Status)})
+	}
+	md.Table([]string{"Task", "Phase", "Name", "Status"}, rows)
+
+	md.Heading(3, fmt.Sprintf("Tests (%d)", len(epic.Tests)))
+	rows = nil
+	for _, test := range epic.Tests {
+		rows = append(rows, []string{output.Code(test.ID), output.Code(test.TaskID), test.Name, string(test.Status)})
+	}
+	md.Table([]string{"Test", "Task", "Name", "Status"}, rows)
+
+	_, err := md.WriteTo(c.Root().Writer)
+	return err
+}
+
+func outputPhaseMarkdown(c *cli.Command, phase *epic.Phase, related []query.RelatedItem) error {
+	md := output.NewMarkdown()
+	md.Heading(2, fmt.Sprintf("Phase %s: %s", phase.ID, phase.Name))
+	md.Field("Status", string(phase.Status))
+	if phase.SpecRef != "" {
+		md.Field("Spec", phase.SpecRef)
+	}
+	if phase.IsFrozen() {
+		md.Field("Frozen", fmt.Sprintf("%s (%s)", phase.FrozenAt.Format(time.RFC3339), phase.FrozenReason))
+	}
+	writeDependenciesMarkdown(md, related)
+	if phase.Description != "" {
+		md.Paragraph("%s", output.Inline(phase.Description))
+	}
+
+	var tasks, tests []query.RelatedItem
+	for _, item := range related {
+		switch item.Type {
+		case "task":
+			tasks = append(tasks, item)
+		case "test":
+			tests = append(tests, item)
+		}
+	}
+
+	md.Heading(3, fmt.Sprintf("Tasks (%d)", len(tasks)))
+	if len(tasks) == 0 {
+		md.Paragraph("(none)")
+	}
+	listed := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		listed[task.ID] = true
+	}
+	for _, task := range tasks {
+		if listed[task.ParentID] {
+			continue
+		}
+		md.Item("%s %s", output.Code(task.ID), output.Inline(task.Name))
+		for _, subTask := range tasks {
+			if subTask.ParentID == task.ID {
+				md.SubItem("%s %s", output.Code(subTask.ID), output.Inline(subTask.Name))
+			}
+		}
+	}
+
+	md.Heading(3, fmt.Sprintf("Tests (%d)", len(tests)))
+	writeRelatedItemsMarkdown(md, tests)
+
+	_, err := md.WriteTo(c.Root().Writer)
+	return err
+}
+
+func outputTaskMarkdown(c *cli.Command, task *epic.Task, related []query.RelatedItem) error {
+	md := output.NewMarkdown()
+	md.Heading(2, fmt.Sprintf("Task %s: %s", task.ID, task.Name))
+	md.Field("Status", string(task.Status))
+	for _, item := range related {
+		if item.Relationship != "parent" {
+			continue
+		}
+		switch item.Type {
+		case "phase":
+			md.Item("**Phase:** %s %s", output.Code(item.ID), output.Inline(item.Name))
+		case "task":
+			md.Item("**Parent Task:** %s %s", output.Code(item.ID), output.Inline(item.Name))
+		}
+	}
+	if task.SpecRef != "" {
+		md.Field("Spec", task.SpecRef)
+	}
+	if task.IsRecurring() {
+		md.Field("Recurring", "every "+task.Recurring)
+	}
+	writeDependenciesMarkdown(md, related)
+	if task.Description != "" {
+		md.Paragraph("%s", output.Inline(task.Description))
+	}
+
+	var subTasks, tests []query.RelatedItem
+	for _, item := range related {
+		switch {
+		case item.Relationship == "subtask":
+			subTasks = append(subTasks, item)
+		case item.Type == "test":
+			tests = append(tests, item)
+		}
+	}
+	if len(subTasks) > 0 {
+		md.Heading(3, fmt.Sprintf("Sub-tasks (%d)", len(subTasks)))
+		writeRelatedItemsMarkdown(md, subTasks)
+	}
+
+	md.Heading(3, fmt.Sprintf("Tests (%d)", len(tests)))
+	writeRelatedItemsMarkdown(md, tests)
+
+	_, err := md.WriteTo(c.Root().Writer)
+	return err
+}
+
+func outputTestMarkdown(c *cli.Command, test *epic.Test, related []query.RelatedItem) error {
+	md := output.NewMarkdown()
+	md.Heading(2, fmt.Sprintf("Test %s: %s", test.ID, test.Name))
+	md.Field("Status", string(test.Status))
+	for _, item := range related {
+		if item.Type == "task" && item.Relationship == "parent" {
+			md.Item("**Task:** %s %s", output.Code(item.ID), output.Inline(item.Name))
+		}
+		if item.Type == "phase" && item.Relationship == "ancestor" {
+			md.Item("**Phase:** %s %s", output.Code(item.ID), output.Inline(item.Name))
+		}
+	}
+	if test.FailureNote != "" {
+		md.Field("Failure", test.FailureNote)
+	}
+	writeFailureContextMarkdown(md, test.FailureContext)
+	if test.Description != "" {
+		md.Paragraph("%s", output.Inline(test.Description))
+	}
+
+	_, err := md.WriteTo(c.Root().Writer)
+	return err
+}
+
+// writeDependenciesMarkdown lists the dependency and dependent items of a phase or task
+func writeDependenciesMarkdown(md *output.Markdown, related []query.RelatedItem) {
+	var dependencies, dependents []string
+	for _, item := range related {
+		switch item.Relationship {
+		case "dependency":
+			dependencies = append(dependencies, output.Code(item.ID))
+		case "dependent":
+			dependents = append(dependents, output.Code(item.ID))
+		}
+	}
+	if len(dependencies) > 0 {
+		md.Item("**Depends on:** %s", strings.Join(dependencies, ", "))
+	}
+	if len(dependents) > 0 {
+		md.Item("**Required by:** %s", strings.Join(dependents, ", "))
+	}
+}
+
+// writeRelatedItemsMarkdown lists related items, or "(none)"
+func writeRelatedItemsMarkdown(md *output.Markdown, items []query.RelatedItem) {
+	if len(items) == 0 {
+		md.Paragraph("(none)")
+		return
+	}
+	for _, item := range items {
+		md.Item("%s %s", output.Code(item.ID), output.Inline(item.Name))
+	}
+}
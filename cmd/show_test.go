@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/mindreframer/agentpm/internal/config"
@@ -1189,3 +1190,129 @@ func createTestEpicForShow() *epic.Epic {
 		Events: []epic.Event{},
 	}
 }
+
+func TestShowDependencies(t *testing.T) {
+	tempDir := t.TempDir()
+	testEpic := createTestEpicForShow()
+	testEpic.Phases[1].DependsOn = "1A"
+	testEpic.Tasks[1].DependsOn = "1A_T1"
+	epicPath := filepath.Join(tempDir, "test-epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicPath))
+
+	run := func(args ...string) string {
+		var stdout bytes.Buffer
+		cmd := ShowCommand()
+		cmd.Root().Writer = &stdout
+		err := cmd.Run(context.Background(), append(append([]string{"show"}, args...), "--file", epicPath))
+		require.NoError(t, err)
+		return stdout.String()
+	}
+
+	t.Run("text lists dependencies and dependents", func(t *testing.T) {
+		output := run("phase", "1B")
+		assert.Contains(t, output, "Depends on: 1A\n")
+		assert.Contains(t, output, "Tasks (1):", "dependencies are not listed as tasks")
+
+		output = run("phase", "1A")
+		assert.Contains(t, output, "Required by: 1B\n")
+		assert.NotContains(t, output, "Depends on:")
+
+		output = run("task", "1B_T1")
+		assert.Contains(t, output, "Depends on: 1A_T1\n")
+	})
+
+	t.Run("json and xml include depends_on", func(t *testing.T) {
+		var result map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(run("task", "1B_T1", "--format", "json")), &result))
+		assert.Equal(t, []interface{}{"1A_T1"}, result["depends_on"])
+
+		output := run("phase", "1B", "--format", "xml")
+		assert.Contains(t, output, "<depends_on>1A</depends_on>")
+		assert.Contains(t, output, `<phase id="1A" relationship="dependency">Phase One</phase>`)
+	})
+}
+
+func TestShowSubTasks(t *testing.T) {
+	tempDir := t.TempDir()
+	testEpic := createTestEpicForShow()
+	testEpic.Tasks = append(testEpic.Tasks,
+		epic.Task{ID: "1A_T1a", PhaseID: "1A", ParentTaskID: "1A_T1", Name: "Sub-task One", Status: epic.StatusPending},
+		epic.Task{ID: "1A_T2", PhaseID: "1A", Name: "Task Three", Status: epic.StatusPending},
+	)
+	epicPath := filepath.Join(tempDir, "test-epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicPath))
+
+	run := func(args ...string) string {
+		var stdout bytes.Buffer
+		cmd := ShowCommand()
+		cmd.Root().Writer = &stdout
+		err := cmd.Run(context.Background(), append(append([]string{"show"}, args...), "--file", epicPath))
+		require.NoError(t, err)
+		return stdout.String()
+	}
+
+	t.Run("phase indents sub-tasks under their parent", func(t *testing.T) {
+		output := run("phase", "1A")
+		assert.Contains(t, output, "Tasks (3):\n  1A_T1 - Task One\n    1A_T1a - Sub-task One\n  1A_T2 - Task Three\n")
+	})
+
+	t.Run("task lists its sub-tasks and parent", func(t *testing.T) {
+		output := run("task", "1A_T1")
+		assert.Contains(t, output, "Sub-tasks (1):\n  1A_T1a - Sub-task One\n")
+
+		output = run("task", "1A_T1a")
+		assert.Contains(t, output, "Parent Task: 1A_T1 - Task One\n")
+		assert.NotContains(t, output, "Sub-tasks")
+	})
+
+	t.Run("json and xml include parent_task_id", func(t *testing.T) {
+		var result map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(run("task", "1A_T1a", "--format", "json")), &result))
+		assert.Equal(t, "1A_T1", result["parent_task_id"])
+
+		output := run("task", "1A_T1a", "--format", "xml")
+		assert.Contains(t, output, `<task id="1A_T1a" phase_id="1A" parent_task_id="1A_T1" status="pending">`)
+	})
+}
+
+func TestShowMarkdown(t *testing.T) {
+	tempDir := t.TempDir()
+	testEpic := createTestEpicForShow()
+	testEpic.Tasks = append(testEpic.Tasks,
+		epic.Task{ID: "1A_T1a", PhaseID: "1A", ParentTaskID: "1A_T1", Name: "Sub-task | One", Status: epic.StatusPending})
+	testEpic.Tasks[1].DependsOn = "1A_T1"
+	epicPath := filepath.Join(tempDir, "test-epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicPath))
+
+	run := func(args ...string) string {
+		var stdout bytes.Buffer
+		cmd := ShowCommand()
+		cmd.Root().Writer = &stdout
+		err := cmd.Run(context.Background(), append(append([]string{"show"}, args...), "--file", epicPath, "--format", "markdown"))
+		require.NoError(t, err)
+		return stdout.String()
+	}
+
+	t.Run("epic renders tables", func(t *testing.T) {
+		output := run("epic")
+		assert.True(t, strings.HasPrefix(output, "## Epic: Show Test Epic\n"))
+		assert.Contains(t, output, "| `1A_T1a` | `1A` | Sub-task \\| One | pending |\n")
+	})
+
+	t.Run("phase nests sub-tasks", func(t *testing.T) {
+		output := run("phase", "1A")
+		assert.Contains(t, output, "### Tasks (2)\n\n- `1A_T1` Task One\n  - `1A_T1a` Sub-task | One\n")
+	})
+
+	t.Run("task lists dependencies, sub-tasks and tests", func(t *testing.T) {
+		output := run("task", "1A_T1")
+		assert.Contains(t, output, "## Task 1A_T1: Task One\n\n- **Status:** pending\n- **Phase:** `1A` Phase One\n- **Required by:** `1B_T1`\n")
+		assert.Contains(t, output, "### Sub-tasks (1)\n\n- `1A_T1a` Sub-task | One\n")
+		assert.Contains(t, output, "### Tests (1)\n\n- `1A_T1_TEST1` Test One\n")
+	})
+
+	t.Run("test", func(t *testing.T) {
+		output := run("test", "1B_T1_TEST1")
+		assert.Contains(t, output, "- **Task:** `1B_T1` Task Two\n- **Phase:** `1B` Phase Two\n")
+	})
+}
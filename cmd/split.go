@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/commands"
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// splitOutput describes the epic file written by the split command
+type splitOutput struct {
+	*service.SplitResult
+	Output     string `json:"output"`
+	Registered bool   `json:"registered"`
+}
+
+// SplitCommand carves phases out of the current epic into a new epic file
+func SplitCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "split",
+		Usage: "Move phases of the current epic into a new epic",
+		Description: `Carve the given phases with their tasks, tests, blockers, suppressions
+and events out of the current epic into a new epic file. The new epic takes
+over the current state if its active phase or task moves.
+
+Work that stays must not depend on work that moves. Dependencies of moved
+phases, tasks and tests on work that stays are dropped and reported.
+Milestones stay with the current epic.
+
+The ID of the new epic defaults to the name of the output file without its
+extension. If a config file exists, the new epic is registered as an epic
+of the workspace, see 'agentpm epics'.
+
+Examples:
+  agentpm split --phases 3A,3B --out epic-9b.xml
+  agentpm split --phases 4A --out epics/reporting.yaml --name "Reporting"`,
+		Flags: append(commands.GlobalFlags(),
+			&cli.StringSliceFlag{
+				Name:     "phases",
+				Usage:    "IDs of the phases to move, comma-separated or repeated",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "out",
+				Aliases:  []string{"o"},
+				Usage:    "Path of the new epic file",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "id",
+				Usage: "ID of the new epic (default: the output file name without extension)",
+			},
+			&cli.StringFlag{
+				Name:  "name",
+				Usage: "Name of the new epic (default: the name of the current epic)",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Overwrite an existing output file",
+			},
+		),
+		Action: withQuietResult(splitAction),
+	}
+}
+
+func splitAction(ctx context.Context, c *cli.Command) error {
+	var phaseIDs []string
+	for _, value := range c.StringSlice("phases") {
+		for _, id := range strings.Split(value, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				phaseIDs = append(phaseIDs, id)
+			}
+		}
+	}
+	if len(phaseIDs) == 0 {
+		return exitcode.Errorf(exitcode.Validation, "split requires --phases with at least one phase ID")
+	}
+
+	output := c.String("out")
+	newID := c.String("id")
+	if newID == "" {
+		newID = strings.TrimSuffix(filepath.Base(output), filepath.Ext(output))
+	}
+
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(output); err == nil && !c.Bool("force") {
+		return fmt.Errorf("cannot split into %s: the file already exists (use --force to overwrite it)", output)
+	}
+
+	timestamp := time.Now()
+	if timeStr := c.String("time"); timeStr != "" {
+		timestamp, err = time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+		}
+	}
+
+	fileStorage := storage.NewFileStorage()
+	epicData, err := fileStorage.LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	split, result, err := service.SplitEpic(epicData, uniqueIDs(phaseIDs), newID, c.String("name"), timestamp)
+	if err != nil {
+		return err
+	}
+
+	// Write the new epic first, so that a failure leaves the current epic untouched
+	if err := fileStorage.SaveEpic(split, output); err != nil {
+		return fmt.Errorf("failed to save the new epic: %w", err)
+	}
+	if err := fileStorage.SaveEpic(epicData, epicFile); err != nil {
+		return fmt.Errorf("failed to save epic: %w", err)
+	}
+
+	registered, err := registerWorkspaceEpic(c, output)
+	if err != nil {
+		return err
+	}
+	return writeSplitResult(c, splitOutput{SplitResult: result, Output: output, Registered: registered})
+}
+
+// registerWorkspaceEpic adds the epic file to the epics of the workspace.
+// Without a config there is nothing to register it in.
+func registerWorkspaceEpic(c *cli.Command, epicFile string) (bool, error) {
+	configPath := c.String("config")
+	absConfig, err := config.ResolveConfigPath(configPath)
+	if err != nil {
+		return false, err
+	}
+	if _, err := os.Stat(absConfig); err != nil {
+		return false, nil
+	}
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	stored, err := config.NormalizeEpicPath(configPath, epicFile)
+	if err != nil {
+		return false, err
+	}
+	if !cfg.AddEpic(stored) {
+		return false, nil
+	}
+	if err := config.SaveConfig(cfg, configPath); err != nil {
+		return false, fmt.Errorf("failed to save configuration: %w", err)
+	}
+	return true, nil
+}
+
+func writeSplitResult(c *cli.Command, result splitOutput) error {
+	w := c.Root().Writer
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal result to JSON: %w", err)
+		}
+		fmt.Fprintf(w, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("epic_split")
+		root.CreateAttr("source", result.SourceID)
+		root.CreateAttr("epic", result.EpicID)
+		root.CreateAttr("output", result.Output)
+		root.CreateAttr("tasks", strconv.Itoa(result.Tasks))
+		root.CreateAttr("tests", strconv.Itoa(result.Tests))
+		root.CreateAttr("registered", strconv.FormatBool(result.Registered))
+		for _, id := range result.Phases {
+			root.CreateElement("phase").CreateAttr("id", id)
+		}
+		for _, dependency := range result.DroppedDependencies {
+			root.CreateElement("dropped_dependency").SetText(dependency)
+		}
+		doc.Indent(2)
+		doc.WriteTo(w)
+	default:
+		fmt.Fprintf(w, "Split phases %s of epic %s into epic %s: %s\n",
+			strings.Join(result.Phases, ", "), result.SourceID, result.EpicID, result.Output)
+		fmt.Fprintf(w, "Moved %d tasks, %d tests, %d blockers and %d events.\n", result.Tasks, result.Tests, result.Blockers, result.Events)
+		for _, dependency := range result.DroppedDependencies {
+			fmt.Fprintf(w, "Dropped dependency of %s\n", dependency)
+		}
+		if result.Registered {
+			fmt.Fprintf(w, "Registered %s as an epic of the workspace.\n", result.Output)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createEpicForSplit() *epic.Epic {
+	return &epic.Epic{
+		ID:     "epic-9",
+		Name:   "Large Epic",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{
+			{ID: "2A", Name: "Foundations", Status: epic.StatusCompleted},
+			{ID: "3A", Name: "Reports", Status: epic.StatusPending, DependsOn: "2A"},
+			{ID: "3B", Name: "Exports", Status: epic.StatusPending, DependsOn: "3A"},
+		},
+		Tasks: []epic.Task{
+			{ID: "2A_1", PhaseID: "2A", Name: "Schema", Status: epic.StatusCompleted},
+			{ID: "3A_1", PhaseID: "3A", Name: "Report view", Status: epic.StatusPending},
+			{ID: "3B_1", PhaseID: "3B", Name: "CSV export", Status: epic.StatusPending},
+		},
+		Tests: []epic.Test{
+			{ID: "3A_1_T1", TaskID: "3A_1", PhaseID: "3A", Name: "Report test", Status: epic.StatusPending},
+		},
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	setup := func(t *testing.T) (dir, configPath string) {
+		dir = t.TempDir()
+		require.NoError(t, storage.NewFileStorage().SaveEpic(createEpicForSplit(), filepath.Join(dir, "epic-9.xml")))
+		configPath = filepath.Join(dir, ".agentpm.json")
+		cfg := config.DefaultConfig()
+		cfg.CurrentEpic = "epic-9.xml"
+		require.NoError(t, config.SaveConfig(cfg, configPath))
+		return dir, configPath
+	}
+	run := func(configPath string, args ...string) (string, error) {
+		var stdout bytes.Buffer
+		cmd := SplitCommand()
+		cmd.Root().Writer = &stdout
+		err := cmd.Run(context.Background(), append([]string{"split", "--config", configPath, "--time", "2025-08-17T09:00:00Z"}, args...))
+		return stdout.String(), err
+	}
+
+	t.Run("writes the phases to a new epic and registers it", func(t *testing.T) {
+		dir, configPath := setup(t)
+		out := filepath.Join(dir, "epic-9b.yaml")
+		output, err := run(configPath, "--phases", "3A,3B", "--out", out)
+		require.NoError(t, err)
+		assert.Contains(t, output, "Split phases 3A, 3B of epic epic-9 into epic epic-9b")
+		assert.Contains(t, output, "Dropped dependency of phase 3A on 2A")
+
+		fileStorage := storage.NewFileStorage()
+		split, err := fileStorage.LoadEpic(out)
+		require.NoError(t, err)
+		assert.Equal(t, "epic-9b", split.ID)
+		assert.Len(t, split.Phases, 2)
+		assert.Len(t, split.Tasks, 2)
+		assert.Len(t, split.Tests, 1)
+
+		source, err := fileStorage.LoadEpic(filepath.Join(dir, "epic-9.xml"))
+		require.NoError(t, err)
+		assert.Len(t, source.Phases, 1)
+		assert.Len(t, source.Tasks, 1)
+
+		cfg, err := config.LoadConfig(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, []string{out}, cfg.Epics)
+	})
+
+	t.Run("reports JSON", func(t *testing.T) {
+		dir, configPath := setup(t)
+		output, err := run(configPath, "--format", "json", "--phases", "3B", "--id", "exports", "--out", filepath.Join(dir, "b.xml"))
+		require.NoError(t, err)
+
+		var result map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.Equal(t, "exports", result["epic_id"])
+		assert.Equal(t, []interface{}{"3B"}, result["phases"])
+		assert.Equal(t, true, result["registered"])
+	})
+
+	t.Run("rejects invalid splits", func(t *testing.T) {
+		dir, configPath := setup(t)
+		existing := filepath.Join(dir, "taken.xml")
+		require.NoError(t, os.WriteFile(existing, []byte("<epic/>"), 0644))
+
+		_, err := run(configPath, "--phases", "3A", "--out", filepath.Join(dir, "a.xml"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "phase 3B depends on 3A")
+		assert.Equal(t, exitcode.Constraint, ExitCode(err))
+
+		_, err = run(configPath, "--phases", "9Z", "--out", filepath.Join(dir, "a.xml"))
+		require.Error(t, err)
+		assert.Equal(t, exitcode.NotFound, ExitCode(err))
+
+		_, err = run(configPath, "--phases", "3B", "--out", existing)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already exists")
+		assert.NoFileExists(t, filepath.Join(dir, "a.xml"))
+	})
+}
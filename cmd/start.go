@@ -45,7 +45,7 @@ This command:
 - Sets the started_at timestamp
 - Creates an automatic event log entry
 - Validates that the epic is in a valid state to start`,
-		Action: commands.CreateEpicAction(handleStartEpic),
+		Action: withQuietResult(commands.CreateEpicAction(handleStartEpic)),
 	}
 }
 
@@ -57,7 +57,7 @@ func startPhaseSubcommand() *cli.Command {
 		Description: `Start a specific phase in the epic.
 
 The phase must exist in the current epic and be in a valid state to start.`,
-		Action: commands.CreateEntityAction(commands.EntityTypePhase, handleStartPhase),
+		Action: withQuietResult(commands.CreateEntityAction(commands.EntityTypePhase, handleStartPhase)),
 	}
 }
 
@@ -69,7 +69,7 @@ func startTaskSubcommand() *cli.Command {
 		Description: `Start a specific task in the epic.
 
 The task must exist in the current epic and its phase must be active.`,
-		Action: commands.CreateEntityAction(commands.EntityTypeTask, handleStartTask),
+		Action: withQuietResult(commands.CreateEntityAction(commands.EntityTypeTask, handleStartTask)),
 	}
 }
 
@@ -81,7 +81,7 @@ func startTestSubcommand() *cli.Command {
 		Description: `Start working on a test (transitions from pending to wip).
 
 The test must exist in the current epic and be in a valid state to start.`,
-		Action: commands.CreateEntityAction(commands.EntityTypeTest, handleStartTest),
+		Action: withQuietResult(commands.CreateEntityAction(commands.EntityTypeTest, handleStartTest)),
 	}
 }
 
@@ -107,7 +107,7 @@ func handleStartEpic(ctx commands.RouterContext) error {
 	}
 
 	// Normal success - epic was started
-	return nil
+	return ctx.WriteWarnings(result.Warnings)
 }
 
 func handleStartPhase(ctx commands.RouterContext, phaseID string) error {
@@ -125,7 +125,7 @@ func handleStartPhase(ctx commands.RouterContext, phaseID string) error {
 	}
 
 	if result.Error != nil {
-		return fmt.Errorf("%s", result.Error.Message)
+		return serviceError(result.Error.Type, result.Error.Message, result.Error.Hint)
 	}
 
 	if result.IsAlreadyActive {
@@ -134,8 +134,8 @@ func handleStartPhase(ctx commands.RouterContext, phaseID string) error {
 	}
 
 	// Output success message
-	fmt.Printf("Phase %s started.\n", phaseID)
-	return nil
+	fmt.Fprintf(ctx.Writer, "Phase %s started.\n", phaseID)
+	return ctx.WriteWarnings(result.Warnings)
 }
 
 func handleStartTask(ctx commands.RouterContext, taskID string) error {
@@ -153,7 +153,7 @@ func handleStartTask(ctx commands.RouterContext, taskID string) error {
 	}
 
 	if result.Error != nil {
-		return fmt.Errorf("%s", result.Error.Message)
+		return serviceError(result.Error.Type, result.Error.Message, result.Error.Hint)
 	}
 
 	if result.IsAlreadyActive {
@@ -162,8 +162,8 @@ func handleStartTask(ctx commands.RouterContext, taskID string) error {
 	}
 
 	// Output success message
-	fmt.Printf("Task %s started.\n", taskID)
-	return nil
+	fmt.Fprintf(ctx.Writer, "Task %s started.\n", taskID)
+	return ctx.WriteWarnings(result.Warnings)
 }
 
 func handleStartTest(ctx commands.RouterContext, testID string) error {
@@ -181,16 +181,16 @@ func handleStartTest(ctx commands.RouterContext, testID string) error {
 	}
 
 	if result.Error != nil {
-		return fmt.Errorf("%s", result.Error.Message)
+		return serviceError(result.Error.Type, result.Error.Message, result.Error.Hint)
 	}
 
 	// Output success message based on result
 	if result.Result != nil {
 		if result.Result.Status == "already_started" {
-			fmt.Printf("Test %s is already started.\n", testID)
+			fmt.Fprintf(ctx.Writer, "Test %s is already started.\n", testID)
 		} else {
-			fmt.Printf("Test %s started.\n", testID)
+			fmt.Fprintf(ctx.Writer, "Test %s started.\n", testID)
 		}
 	}
-	return nil
+	return ctx.WriteWarnings(result.Warnings)
 }
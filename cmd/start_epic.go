@@ -8,6 +8,7 @@ import (
 
 	"github.com/beevik/etree"
 	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/exitcode"
 	"github.com/mindreframer/agentpm/internal/lifecycle"
 	"github.com/mindreframer/agentpm/internal/messages"
 	"github.com/mindreframer/agentpm/internal/query"
@@ -65,7 +66,7 @@ func startEpicAction(ctx context.Context, c *cli.Command) error {
 		}
 
 		if cfg.CurrentEpic == "" {
-			return fmt.Errorf("no epic file specified and no current epic in config")
+			return exitcode.Errorf(exitcode.Validation, "no epic file specified and no current epic in config")
 		}
 		epicFile = cfg.EpicFilePath()
 	}
@@ -3,11 +3,16 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/beevik/etree"
 	"github.com/mindreframer/agentpm/internal/autonext"
 	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
 	"github.com/mindreframer/agentpm/internal/phases"
+	"github.com/mindreframer/agentpm/internal/planner"
 	"github.com/mindreframer/agentpm/internal/query"
 	"github.com/mindreframer/agentpm/internal/storage"
 	"github.com/mindreframer/agentpm/internal/tasks"
@@ -19,6 +24,26 @@ func StartNextCommand() *cli.Command {
 		Name:    "next",
 		Usage:   "Auto-start next available work",
 		Aliases: []string{"start-next"},
+		Description: `Start the next available task, activating the next phase when the
+current one is done.
+
+With --auto the selection runs in a loop: each started task is handed to the
+--exec command and completed when it exits with 0. A failing command is
+rerun up to --retries times before the loop stops. The loop stops when
+--until is reached - the phase it started in is done, or every phase of the
+epic is - and reports its progress as JSON lines on stdout:
+
+  {"event":"task_started","phase":"1A","task":"1A_1","at":"..."}
+  {"event":"task_completed","phase":"1A","task":"1A_1","at":"..."}
+  {"event":"stopped","reason":"until_reached","message":"...","at":"..."}
+
+The command sees AGENTPM_EPIC_FILE, AGENTPM_PHASE_ID, AGENTPM_TASK_ID and
+AGENTPM_ATTEMPT in its environment; its own output goes to stderr.
+
+Examples:
+  agentpm start-next
+  agentpm start-next --auto --exec './agent-work.sh' --until phase-done
+  agentpm start-next --auto --exec 'make agent-task' --retries 2 --max-tasks 10`,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:  "file",
@@ -28,8 +53,29 @@ func StartNextCommand() *cli.Command {
 				Name:  "time",
 				Usage: "Timestamp for the operation (ISO 8601 format)",
 			},
+			&cli.BoolFlag{
+				Name:  "auto",
+				Usage: "Keep starting and completing tasks, running --exec for each, until --until is reached",
+			},
+			&cli.StringFlag{
+				Name:  "exec",
+				Usage: "Shell command run for each started task in --auto mode; exit code 0 completes the task",
+			},
+			&cli.StringFlag{
+				Name:  "until",
+				Usage: "When --auto stops: phase-done or epic-done",
+				Value: untilEpicDone,
+			},
+			&cli.IntFlag{
+				Name:  "retries",
+				Usage: "How often --auto reruns a failing --exec for the same task before stopping",
+			},
+			&cli.IntFlag{
+				Name:  "max-tasks",
+				Usage: "Stop --auto after completing this many tasks (0 for no limit)",
+			},
 		},
-		Action: func(ctx context.Context, cmd *cli.Command) error {
+		Action: withQuietResult(func(ctx context.Context, cmd *cli.Command) error {
 			// Get epic file path
 			epicFile := cmd.String("file")
 			if epicFile == "" {
@@ -37,11 +83,11 @@ func StartNextCommand() *cli.Command {
 				if err != nil {
 					return fmt.Errorf("failed to load configuration: %w", err)
 				}
-				epicFile = cfg.CurrentEpic
+				epicFile = cfg.EpicFilePath()
 			}
 
 			if epicFile == "" {
-				return fmt.Errorf("no epic file specified (use --file flag or set current epic)")
+				return exitcode.Errorf(exitcode.Validation, "no epic file specified (use --file flag or set current epic)")
 			}
 
 			// Parse timestamp if provided
@@ -56,37 +102,64 @@ func StartNextCommand() *cli.Command {
 				timestamp = time.Now()
 			}
 
-			// Initialize services
-			storageImpl := storage.NewFileStorage()
-			queryService := query.NewQueryService(storageImpl)
-			phaseService := phases.NewPhaseService(storageImpl, queryService)
-			taskService := tasks.NewTaskService(storageImpl, queryService)
-			autoNextService := autonext.NewAutoNextService(storageImpl, queryService, phaseService, taskService)
-
-			// Load epic
-			epicData, err := storageImpl.LoadEpic(epicFile)
-			if err != nil {
-				return fmt.Errorf("failed to load epic: %w", err)
+			if cmd.Bool("auto") {
+				now := time.Now
+				if cmd.String("time") != "" {
+					now = func() time.Time { return timestamp }
+				}
+				return runAutoNext(ctx, cmd, epicFile, now)
 			}
 
-			// Execute auto-next selection
-			result, err := autoNextService.SelectNext(epicData, timestamp)
+			result, err := selectNext(cmd.String("config"), epicFile, timestamp)
 			if err != nil {
-				return fmt.Errorf("failed to execute auto-next selection: %w", err)
-			}
-
-			// Save the updated epic (if changes were made)
-			if result.Action != autonext.ActionNoWork && result.Action != autonext.ActionCompleteEpic {
-				err = storageImpl.SaveEpic(epicData, epicFile)
-				if err != nil {
-					return fmt.Errorf("failed to save epic: %w", err)
-				}
+				return err
 			}
 
 			// Output result based on action type
 			return outputAutoNextResult(cmd, result)
-		},
+		}),
+	}
+}
+
+// selectNext starts the next available work of the epic and saves it. Started
+// tasks are dispatched following the assignee rotation of the config, if any.
+func selectNext(configPath, epicFile string, timestamp time.Time) (*autonext.AutoNextResult, error) {
+	// Initialize services
+	storageImpl := storage.NewFileStorage()
+	queryService := query.NewQueryService(storageImpl)
+	phaseService := phases.NewPhaseService(storageImpl, queryService)
+	taskService := tasks.NewTaskService(storageImpl, queryService)
+	autoNextService := autonext.NewAutoNextService(storageImpl, queryService, phaseService, taskService)
+
+	cfg, err := config.LoadEffectiveConfig(configPath, epicFile)
+	if err != nil {
+		if code, ok := exitcode.Of(err); !ok || code != exitcode.NotFound {
+			return nil, fmt.Errorf("failed to load configuration: %w", err)
+		}
+	} else {
+		autoNextService.SetRotation(autonext.RotationFromConfig(cfg))
 	}
+
+	// Load epic
+	epicData, err := storageImpl.LoadEpic(epicFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	// Execute auto-next selection
+	result, err := autoNextService.SelectNext(epicData, timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute auto-next selection: %w", err)
+	}
+
+	// Save the updated epic (if changes were made)
+	if result.Action != autonext.ActionNoWork && result.Action != autonext.ActionCompleteEpic {
+		err = storageImpl.SaveEpic(epicData, epicFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save epic: %w", err)
+		}
+	}
+	return result, nil
 }
 
 // outputAutoNextResult outputs the appropriate format based on the auto-next result
@@ -112,7 +185,11 @@ func outputAutoNextResult(cmd *cli.Command, result *autonext.AutoNextResult) err
 		return nil
 
 	case autonext.ActionNoWork:
-		// Simple text output for no action needed
+		// Structured analysis when the epic stalls, simple text otherwise
+		if result.Stall != nil {
+			fmt.Fprintf(cmd.Writer, "%s\n", formatStallXML(result.Stall))
+			return nil
+		}
 		fmt.Fprintf(cmd.Writer, "%s\n", result.Message)
 		return nil
 
@@ -123,17 +200,21 @@ func outputAutoNextResult(cmd *cli.Command, result *autonext.AutoNextResult) err
 
 // formatTaskStartedXML creates XML output for task started in active phase
 func formatTaskStartedXML(result *autonext.AutoNextResult) string {
+	assignee := ""
+	if result.Assignee != "" {
+		assignee = fmt.Sprintf("\n    <assignee>%s</assignee>", result.Assignee)
+	}
 	return fmt.Sprintf(`<task_started epic="epic-id" task="%s">
     <task_description>%s</task_description>
     <phase_id>%s</phase_id>
     <previous_status>pending</previous_status>
     <new_status>wip</new_status>
     <started_at>%s</started_at>
-    <auto_selected>%t</auto_selected>
+    <auto_selected>%t</auto_selected>%s
     <message>%s</message>
 </task_started>`,
 		result.TaskID, result.TaskName, result.PhaseID,
-		result.StartedAt.Format(time.RFC3339), result.AutoSelected, result.Message)
+		result.StartedAt.Format(time.RFC3339), result.AutoSelected, assignee, result.Message)
 }
 
 // formatPhaseOnlyStartedXML creates XML output for phase started without tasks
@@ -156,3 +237,34 @@ func formatAllCompleteXML(result *autonext.AutoNextResult) string {
     <suggestion>Use 'agentpm done-epic' to complete the epic</suggestion>
 </all_complete>`, result.Message)
 }
+
+// formatStallXML creates XML output explaining why no work could be started
+func formatStallXML(stall *planner.Stall) string {
+	doc := etree.NewDocument()
+	root := doc.CreateElement("no_startable_work")
+	root.CreateAttr("reason", string(stall.Reason))
+	if stall.PhaseID != "" {
+		root.CreateAttr("phase", stall.PhaseID)
+	}
+	root.CreateElement("message").SetText(stall.Message)
+
+	if len(stall.Blockers) > 0 {
+		blockersElem := root.CreateElement("blockers")
+		for _, blocker := range stall.Blockers {
+			blockerElem := blockersElem.CreateElement(blocker.EntityType)
+			blockerElem.CreateAttr("id", blocker.EntityID)
+			if blocker.PhaseID != "" {
+				blockerElem.CreateAttr("phase_id", blocker.PhaseID)
+			}
+			blockerElem.CreateAttr("status", blocker.Status)
+			if blocker.Failing {
+				blockerElem.CreateAttr("result", string(epic.TestResultFailing))
+			}
+		}
+	}
+	root.CreateElement("suggestion").SetText(stall.Suggestion)
+
+	doc.Indent(4)
+	output, _ := doc.WriteToString()
+	return strings.TrimRight(output, "\n")
+}
@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/autonext"
+	"github.com/mindreframer/agentpm/internal/commands"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// Conditions that end 'start-next --auto'
+const (
+	untilPhaseDone = "phase-done" // The phase the run started in is completed
+	untilEpicDone  = "epic-done"  // Every phase of the epic is completed
+)
+
+// Reasons a 'start-next --auto' run stopped
+const (
+	stopUntilReached      = "until_reached"
+	stopMaxTasks          = "max_tasks"
+	stopStalled           = "stalled"
+	stopCallbackFailed    = "callback_failed"
+	stopCompletionRefused = "completion_refused"
+	stopError             = "error"
+)
+
+// autoProgress is one line of the JSON lines progress stream of 'start-next --auto'
+type autoProgress struct {
+	Event    string    `json:"event"` // phase_started, task_started, attempt_failed, task_completed, phase_completed or stopped
+	PhaseID  string    `json:"phase,omitempty"`
+	TaskID   string    `json:"task,omitempty"`
+	Assignee string    `json:"assignee,omitempty"` // Who the assignee rotation handed a started task to
+	Attempt  int       `json:"attempt,omitempty"`
+	ExitCode int       `json:"exit_code,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+	Message  string    `json:"message,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// autoRun is the state of one 'start-next --auto' run
+type autoRun struct {
+	epicFile   string
+	configPath string
+	command    string
+	until      string
+	retries    int
+	maxTasks   int
+	now        func() time.Time
+	out        *json.Encoder
+	callbackIO io.Writer
+
+	phaseID   string // The phase the run works in, for --until phase-done
+	completed int
+}
+
+// runAutoNext starts, works on and completes tasks until the --until
+// condition is reached, the --exec command keeps failing or no task can be started
+func runAutoNext(ctx context.Context, cmd *cli.Command, epicFile string, now func() time.Time) error {
+	until := cmd.String("until")
+	if until != untilPhaseDone && until != untilEpicDone {
+		return exitcode.Errorf(exitcode.Validation, "invalid --until %q: use %s or %s", until, untilPhaseDone, untilEpicDone)
+	}
+	command := cmd.String("exec")
+	if command == "" {
+		return exitcode.Errorf(exitcode.Validation, "--auto requires --exec with the command to run for each task")
+	}
+	retries := int(cmd.Int("retries"))
+	maxTasks := int(cmd.Int("max-tasks"))
+	if retries < 0 || maxTasks < 0 {
+		return exitcode.Errorf(exitcode.Validation, "--retries and --max-tasks cannot be negative")
+	}
+
+	callbackIO := cmd.Root().ErrWriter
+	if callbackIO == nil {
+		callbackIO = os.Stderr
+	}
+	run := &autoRun{
+		epicFile:   epicFile,
+		configPath: cmd.String("config"),
+		command:    command,
+		until:      until,
+		retries:    retries,
+		maxTasks:   maxTasks,
+		now:        now,
+		out:        json.NewEncoder(cmd.Root().Writer),
+		callbackIO: callbackIO,
+	}
+	return run.loop(ctx)
+}
+
+func (r *autoRun) loop(ctx context.Context) error {
+	for {
+		if r.maxTasks > 0 && r.completed >= r.maxTasks {
+			return r.stop(stopMaxTasks, fmt.Sprintf("Stopped after %d completed tasks (--max-tasks)", r.completed), nil)
+		}
+		if r.until == untilPhaseDone && r.phaseID != "" {
+			done, err := r.phaseTasksDone()
+			if err != nil {
+				return r.stop(stopError, err.Error(), err)
+			}
+			if done {
+				return r.completePhase()
+			}
+		}
+
+		result, err := selectNext(r.configPath, r.epicFile, r.now())
+		if err != nil {
+			return r.stop(stopError, err.Error(), err)
+		}
+
+		switch result.Action {
+		case autonext.ActionStartPhase:
+			r.track(result.PhaseID)
+			if err := r.emit(autoProgress{Event: "phase_started", PhaseID: result.PhaseID}); err != nil {
+				return err
+			}
+			if result.TaskID != "" {
+				if err := r.emit(autoProgress{Event: "task_started", PhaseID: result.PhaseID, TaskID: result.TaskID, Assignee: result.Assignee}); err != nil {
+					return err
+				}
+			}
+		case autonext.ActionStartTask:
+			r.track(result.PhaseID)
+			if err := r.emit(autoProgress{Event: "task_started", PhaseID: result.PhaseID, TaskID: result.TaskID, Assignee: result.Assignee}); err != nil {
+				return err
+			}
+		case autonext.ActionCompleteEpic:
+			return r.stop(stopUntilReached, result.Message, nil)
+		default:
+			task, err := r.activeTask()
+			if err != nil {
+				return r.stop(stopError, err.Error(), err)
+			}
+			if task == nil {
+				return r.stop(stopStalled, result.Message, exitcode.Errorf(exitcode.Constraint, "%s", result.Message))
+			}
+			r.track(task.PhaseID)
+			if err := r.work(ctx, task); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// work runs the command for the active task, retrying it on failure, and
+// completes the task once the command succeeds
+func (r *autoRun) work(ctx context.Context, task *epic.Task) error {
+	for attempt := 1; attempt <= r.retries+1; attempt++ {
+		code, err := r.runCallback(ctx, task, attempt)
+		if err != nil {
+			return r.stop(stopError, err.Error(), err)
+		}
+		if code == 0 {
+			return r.completeTask(task)
+		}
+		if err := r.emit(autoProgress{Event: "attempt_failed", PhaseID: task.PhaseID, TaskID: task.ID, Attempt: attempt, ExitCode: code}); err != nil {
+			return err
+		}
+	}
+
+	message := fmt.Sprintf("Command failed %d times for task %s", r.retries+1, task.ID)
+	return r.stop(stopCallbackFailed, message, errors.New(message))
+}
+
+// runCallback runs the --exec command for a task and returns its exit code
+func (r *autoRun) runCallback(ctx context.Context, task *epic.Task, attempt int) (int, error) {
+	callback := exec.CommandContext(ctx, "sh", "-c", r.command)
+	callback.Env = append(os.Environ(),
+		"AGENTPM_EPIC_FILE="+r.epicFile,
+		"AGENTPM_PHASE_ID="+task.PhaseID,
+		"AGENTPM_TASK_ID="+task.ID,
+		"AGENTPM_ATTEMPT="+strconv.Itoa(attempt),
+	)
+	callback.Stdout = r.callbackIO
+	callback.Stderr = r.callbackIO
+
+	err := callback.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to run %q: %w", r.command, err)
+	}
+	return 0, nil
+}
+
+func (r *autoRun) completeTask(task *epic.Task) error {
+	result, err := commands.DoneTaskService(commands.DoneTaskRequest{
+		TaskID:     task.ID,
+		ConfigPath: r.configPath,
+		EpicFile:   r.epicFile,
+		Time:       r.now().Format(time.RFC3339),
+	})
+	if err != nil {
+		return r.stop(stopError, err.Error(), err)
+	}
+	if result.Error != nil {
+		return r.stop(stopCompletionRefused, result.Error.Message, serviceError(result.Error.Type, result.Error.Message, ""))
+	}
+
+	r.completed++
+	return r.emit(autoProgress{Event: "task_completed", PhaseID: task.PhaseID, TaskID: task.ID})
+}
+
+// completePhase completes the phase of a --until phase-done run and ends it
+func (r *autoRun) completePhase() error {
+	result, err := commands.DonePhaseService(commands.DonePhaseRequest{
+		PhaseID:    r.phaseID,
+		ConfigPath: r.configPath,
+		EpicFile:   r.epicFile,
+		Time:       r.now().Format(time.RFC3339),
+	})
+	if err != nil {
+		return r.stop(stopError, err.Error(), err)
+	}
+	if result.Error != nil {
+		return r.stop(stopStalled, result.Error.Message, serviceError(result.Error.Type, result.Error.Message, ""))
+	}
+
+	if err := r.emit(autoProgress{Event: "phase_completed", PhaseID: r.phaseID}); err != nil {
+		return err
+	}
+	return r.stop(stopUntilReached, fmt.Sprintf("Phase %s completed", r.phaseID), nil)
+}
+
+// track remembers the first phase the run works in
+func (r *autoRun) track(phaseID string) {
+	if r.phaseID == "" {
+		r.phaseID = phaseID
+	}
+}
+
+func (r *autoRun) activeTask() (*epic.Task, error) {
+	e, err := storage.NewFileStorage().LoadEpic(r.epicFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load epic: %w", err)
+	}
+	return e.ActiveTask(""), nil
+}
+
+// phaseTasksDone reports whether every task of the run's phase is completed or cancelled
+func (r *autoRun) phaseTasksDone() (bool, error) {
+	e, err := storage.NewFileStorage().LoadEpic(r.epicFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to load epic: %w", err)
+	}
+	for _, task := range e.Tasks {
+		if task.PhaseID == r.phaseID && task.Status != epic.StatusCompleted && task.Status != epic.StatusCancelled {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (r *autoRun) emit(progress autoProgress) error {
+	progress.At = r.now().UTC()
+	return r.out.Encode(progress)
+}
+
+// stop reports why the run ended and returns err, the error it ends with
+func (r *autoRun) stop(reason, message string, err error) error {
+	if emitErr := r.emit(autoProgress{Event: "stopped", Reason: reason, Message: message}); emitErr != nil && err == nil {
+		return emitErr
+	}
+	return err
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"encoding/json"
+	"github.com/urfave/cli/v3"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createAutoNextEpic(t *testing.T) string {
+	epicFile := filepath.Join(t.TempDir(), "epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(&epic.Epic{
+		ID:     "epic-1",
+		Name:   "Auto Epic",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{
+			{ID: "1A", Name: "Setup", Status: epic.StatusPending},
+			{ID: "1B", Name: "Build", Status: epic.StatusPending},
+		},
+		Tasks: []epic.Task{
+			{ID: "1A_1", PhaseID: "1A", Name: "Init", Status: epic.StatusPending},
+			{ID: "1A_2", PhaseID: "1A", Name: "Tools", Status: epic.StatusPending},
+			{ID: "1B_1", PhaseID: "1B", Name: "Core", Status: epic.StatusPending},
+		},
+	}, epicFile))
+	return epicFile
+}
+
+// runAutoNextCommand runs start-next --auto and returns the progress stream
+func runAutoNextCommand(t *testing.T, epicFile string, args ...string) ([]autoProgress, string, error) {
+	t.Helper()
+	base := []string{"start-next", "--file", epicFile, "--time", "2025-08-16T15:30:00Z", "--auto"}
+	stdout, stderr, err := runAppOutput(t, appOptions{commands: []func() *cli.Command{StartNextCommand}}, append(base, args...)...)
+
+	var progress []autoProgress
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		if line == "" {
+			continue
+		}
+		var p autoProgress
+		require.NoError(t, json.Unmarshal([]byte(line), &p), line)
+		progress = append(progress, p)
+	}
+	return progress, stderr, err
+}
+
+// autoEvents summarises the stream as "<event> <task, phase or reason>"
+func autoEvents(progress []autoProgress) []string {
+	var result []string
+	for _, p := range progress {
+		subject := p.TaskID
+		if subject == "" {
+			subject = p.PhaseID
+		}
+		if subject == "" {
+			subject = p.Reason
+		}
+		result = append(result, p.Event+" "+subject)
+	}
+	return result
+}
+
+func taskStatuses(t *testing.T, epicFile string) map[string]epic.Status {
+	e, err := storage.NewFileStorage().LoadEpic(epicFile)
+	require.NoError(t, err)
+	result := make(map[string]epic.Status)
+	for _, task := range e.Tasks {
+		result[task.ID] = task.Status
+	}
+	for _, phase := range e.Phases {
+		result[phase.ID] = phase.Status
+	}
+	return result
+}
+
+func TestStartNextAuto(t *testing.T) {
+	t.Run("works through the epic until every phase is done", func(t *testing.T) {
+		epicFile := createAutoNextEpic(t)
+		progress, stderr, err := runAutoNextCommand(t, epicFile, "--exec", "echo working on $AGENTPM_PHASE_ID/$AGENTPM_TASK_ID")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{
+			"phase_started 1A", "task_started 1A_1", "task_completed 1A_1",
+			"task_started 1A_2", "task_completed 1A_2",
+			"phase_started 1B", "task_started 1B_1", "task_completed 1B_1",
+			"stopped until_reached",
+		}, autoEvents(progress))
+		assert.Contains(t, stderr, "working on 1A/1A_1")
+		assert.Contains(t, stderr, "working on 1B/1B_1")
+		assert.Equal(t, "2025-08-16T15:30:00Z", progress[0].At.Format("2006-01-02T15:04:05Z07:00"))
+
+		statuses := taskStatuses(t, epicFile)
+		assert.Equal(t, epic.StatusCompleted, statuses["1B_1"])
+		assert.Equal(t, epic.StatusCompleted, statuses["1A"])
+	})
+
+	t.Run("stops once the phase it started in is done", func(t *testing.T) {
+		epicFile := createAutoNextEpic(t)
+		progress, _, err := runAutoNextCommand(t, epicFile, "--exec", "true", "--until", "phase-done")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{
+			"phase_started 1A", "task_started 1A_1", "task_completed 1A_1",
+			"task_started 1A_2", "task_completed 1A_2",
+			"phase_completed 1A", "stopped until_reached",
+		}, autoEvents(progress))
+		statuses := taskStatuses(t, epicFile)
+		assert.Equal(t, epic.StatusCompleted, statuses["1A"])
+		assert.Equal(t, epic.StatusPending, statuses["1B"])
+	})
+
+	t.Run("retries a failing command", func(t *testing.T) {
+		epicFile := createAutoNextEpic(t)
+		progress, _, err := runAutoNextCommand(t, epicFile, "--exec", `test "$AGENTPM_ATTEMPT" = 2`, "--retries", "1", "--max-tasks", "1")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{
+			"phase_started 1A", "task_started 1A_1", "attempt_failed 1A_1", "task_completed 1A_1",
+			"stopped max_tasks",
+		}, autoEvents(progress))
+		assert.Equal(t, 1, progress[2].Attempt)
+		assert.Equal(t, 1, progress[2].ExitCode)
+	})
+
+	t.Run("stops when the command keeps failing", func(t *testing.T) {
+		epicFile := createAutoNextEpic(t)
+		progress, _, err := runAutoNextCommand(t, epicFile, "--exec", "exit 7", "--retries", "1")
+		require.Error(t, err)
+
+		assert.Equal(t, []string{
+			"phase_started 1A", "task_started 1A_1", "attempt_failed 1A_1", "attempt_failed 1A_1",
+			"stopped callback_failed",
+		}, autoEvents(progress))
+		assert.Equal(t, 7, progress[3].ExitCode)
+		assert.Equal(t, epic.StatusWIP, taskStatuses(t, epicFile)["1A_1"])
+	})
+
+	t.Run("picks up the task already in progress", func(t *testing.T) {
+		epicFile := createAutoNextEpic(t)
+		_, _, err := runAutoNextCommand(t, epicFile, "--exec", "false")
+		require.Error(t, err)
+
+		marker := filepath.Join(t.TempDir(), "ran")
+		progress, _, err := runAutoNextCommand(t, epicFile, "--exec", `echo $AGENTPM_TASK_ID >> `+marker, "--max-tasks", "1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"task_completed 1A_1", "stopped max_tasks"}, autoEvents(progress))
+
+		ran, err := os.ReadFile(marker)
+		require.NoError(t, err)
+		assert.Equal(t, "1A_1\n", string(ran))
+	})
+
+	t.Run("requires a command and a known until", func(t *testing.T) {
+		epicFile := createAutoNextEpic(t)
+		_, _, err := runAutoNextCommand(t, epicFile)
+		assert.Equal(t, exitcode.Validation, ExitCode(err))
+
+		_, _, err = runAutoNextCommand(t, epicFile, "--exec", "true", "--until", "forever")
+		assert.Equal(t, exitcode.Validation, ExitCode(err))
+		assert.Equal(t, epic.StatusPending, taskStatuses(t, epicFile)["1A_1"])
+	})
+}
@@ -5,12 +5,15 @@ import (
 	"context"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/mindreframer/agentpm/internal/config"
 	"github.com/mindreframer/agentpm/internal/epic"
 	"github.com/mindreframer/agentpm/internal/storage"
 	apmtesting "github.com/mindreframer/agentpm/internal/testing"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
 )
 
 func TestStartNextCommand(t *testing.T) {
@@ -179,6 +182,47 @@ func TestStartNextCommand(t *testing.T) {
 		assert.Contains(t, output, `<suggestion>Use 'agentpm done-epic' to complete the epic</suggestion>`)
 	})
 
+	t.Run("stalled epic reports a structured analysis", func(t *testing.T) {
+		tempDir := t.TempDir()
+		epicFile := filepath.Join(tempDir, "test-epic.xml")
+
+		// All tasks done, but a test of the phase is still failing
+		failedAt := time.Date(2025, 8, 16, 12, 0, 0, 0, time.UTC)
+		testEpic := &epic.Epic{
+			ID:     "epic-1",
+			Name:   "Test Epic",
+			Status: epic.StatusWIP,
+			Phases: []epic.Phase{
+				{ID: "phase-1", Name: "Phase 1", Status: epic.StatusWIP},
+			},
+			Tasks: []epic.Task{
+				{ID: "task-1", PhaseID: "phase-1", Name: "Task 1", Status: epic.StatusCompleted},
+			},
+			Tests: []epic.Test{
+				{ID: "test-1", PhaseID: "phase-1", TaskID: "task-1", Name: "Test 1", Status: epic.StatusWIP, TestStatus: epic.TestStatusWIP, FailedAt: &failedAt},
+			},
+		}
+
+		storage := storage.NewFileStorage()
+		require.NoError(t, storage.SaveEpic(testEpic, epicFile))
+
+		var stdout bytes.Buffer
+		cmd := StartNextCommand()
+		cmd.Root().Writer = &stdout
+
+		err := cmd.Run(context.Background(), []string{"start-next", "--file", epicFile})
+		require.NoError(t, err)
+
+		assert.Equal(t, `<no_startable_work reason="tests_blocking" phase="phase-1">
+    <message>All tasks of phase phase-1 are done, but test test-1 (failing) is not passed</message>
+    <blockers>
+        <test id="test-1" phase_id="phase-1" status="wip" result="failing"/>
+    </blockers>
+    <suggestion>agentpm pass test-1</suggestion>
+</no_startable_work>
+`, stdout.String())
+	})
+
 	t.Run("no work needed when task already active", func(t *testing.T) {
 		tempDir := t.TempDir()
 		epicFile := filepath.Join(tempDir, "test-epic.xml")
@@ -365,3 +409,43 @@ func TestStartNextCommand(t *testing.T) {
 		assert.Contains(t, output, "All phases and tasks completed")
 	})
 }
+
+func TestStartNextCommand_AssigneeRotation(t *testing.T) {
+	tempDir := t.TempDir()
+	epicFile := filepath.Join(tempDir, "epic.xml")
+	configFile := filepath.Join(tempDir, ".agentpm.json")
+	require.NoError(t, config.SaveConfig(&config.Config{
+		CurrentEpic:      epicFile,
+		Assignees:        "agent-a,agent-b",
+		AssigneeRotation: config.AssigneeRotationRoundRobin,
+	}, configFile))
+	require.NoError(t, storage.NewFileStorage().SaveEpic(&epic.Epic{
+		ID:     "epic-1",
+		Name:   "Test Epic",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{
+			{ID: "phase-1", Name: "Phase 1", Status: epic.StatusWIP},
+		},
+		Tasks: []epic.Task{
+			{ID: "task-1", PhaseID: "phase-1", Name: "Task 1", Status: epic.StatusPending},
+			{ID: "task-2", PhaseID: "phase-1", Name: "Task 2", Status: epic.StatusPending},
+		},
+	}, epicFile))
+
+	var stdout bytes.Buffer
+	startNext := StartNextCommand()
+	startNext.Writer = &stdout
+	app := &cli.Command{
+		Name:     "agentpm",
+		Flags:    []cli.Flag{&cli.StringFlag{Name: "config", Value: configFile}},
+		Commands: []*cli.Command{startNext},
+	}
+	require.NoError(t, app.Run(context.Background(), []string{"agentpm", "start-next", "--time", "2025-08-16T15:30:00Z"}))
+	assert.Contains(t, stdout.String(), "<assignee>agent-a</assignee>")
+
+	loaded, err := storage.NewFileStorage().LoadEpic(epicFile)
+	require.NoError(t, err)
+	assert.Equal(t, "agent-a", loaded.Tasks[0].Assignee)
+	assert.Equal(t, epic.StatusWIP, loaded.Tasks[0].Status)
+	assert.Empty(t, loaded.Tasks[1].Assignee)
+}
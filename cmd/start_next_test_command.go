@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/commands"
+	"github.com/mindreframer/agentpm/internal/tests"
+	"github.com/urfave/cli/v3"
+)
+
+// StartNextTestCommand picks and starts the next pending test of the active task
+func StartNextTestCommand() *cli.Command {
+	return &cli.Command{
+		Name:    "start-next-test",
+		Usage:   "Auto-start the next pending test of the active task",
+		Aliases: []string{"next-test"},
+		Description: `Pick the next pending test of the currently active task, start it
+and print what it is expected to verify.
+
+The test prerequisites (active task and phase) are checked just like
+'agentpm start test <id>'.
+
+Examples:
+  agentpm start-next-test               # Start next test of the active task
+  agentpm next-test --format=xml        # Same, with XML output`,
+		Flags:  commands.GlobalFlags(),
+		Action: withQuietResult(startNextTestAction),
+	}
+}
+
+func startNextTestAction(ctx context.Context, c *cli.Command) error {
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse timestamp if provided
+	var timestamp *time.Time
+	if timeStr := c.String("time"); timeStr != "" {
+		t, err := time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+		}
+		timestamp = &t
+	}
+
+	service := tests.NewTestService(tests.ServiceConfig{
+		UseMemory: false,
+	})
+
+	selection, err := service.StartNextTest(epicFile, timestamp)
+	if err != nil {
+		return writeTestError(c, c.String("format"), err)
+	}
+
+	return writeNextTestSelection(c, c.String("format"), selection)
+}
+
+func writeNextTestSelection(c *cli.Command, format string, selection *tests.NextTestSelection) error {
+	switch format {
+	case "json":
+		jsonData, err := json.MarshalIndent(selection, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal test selection to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		if !selection.Started {
+			fmt.Fprintf(c.Root().Writer, `<no_pending_tests task="%s">
+    <message>%s</message>
+</no_pending_tests>
+`, selection.TaskID, selection.Message)
+			return nil
+		}
+		output := fmt.Sprintf(`<test_started test="%s">
+    <test_name>%s</test_name>
+    <task_id>%s</task_id>
+    <phase_id>%s</phase_id>`,
+			selection.TestID, selection.Name, selection.TaskID, selection.PhaseID)
+		if selection.Description != "" {
+			output += fmt.Sprintf(`
+    <description>%s</description>`, selection.Description)
+		}
+		output += fmt.Sprintf(`
+    <previous_status>pending</previous_status>
+    <new_status>wip</new_status>
+    <started_at>%s</started_at>
+    <auto_selected>true</auto_selected>
+    <message>%s</message>
+</test_started>`, selection.StartedAt.Format(time.RFC3339), selection.Message)
+		fmt.Fprintf(c.Root().Writer, "%s\n", output)
+	default:
+		if !selection.Started {
+			fmt.Fprintf(c.Root().Writer, "%s\n", selection.Message)
+			return nil
+		}
+		fmt.Fprintf(c.Root().Writer, "Test %s started: %s\n", selection.TestID, selection.Name)
+		fmt.Fprintf(c.Root().Writer, "Task: %s (phase %s)\n", selection.TaskID, selection.PhaseID)
+		if selection.Description != "" {
+			fmt.Fprintf(c.Root().Writer, "Expected behavior: %s\n", selection.Description)
+		}
+	}
+	return nil
+}
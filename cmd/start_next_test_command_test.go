@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createEpicForNextTest() *epic.Epic {
+	return &epic.Epic{
+		ID:     "epic-1",
+		Name:   "Test Epic",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{
+			{ID: "P1", Name: "Phase 1", Status: epic.StatusWIP},
+		},
+		Tasks: []epic.Task{
+			{ID: "T1", PhaseID: "P1", Name: "Task 1", Status: epic.StatusWIP},
+			{ID: "T2", PhaseID: "P1", Name: "Task 2", Status: epic.StatusPending},
+		},
+		Tests: []epic.Test{
+			{ID: "T2_T1", TaskID: "T2", PhaseID: "P1", Name: "Other task test", Status: epic.StatusPending},
+			{ID: "T1_T1", TaskID: "T1", PhaseID: "P1", Name: "Already done", Status: epic.StatusCompleted, TestStatus: epic.TestStatusDone},
+			{ID: "T1_T2", TaskID: "T1", PhaseID: "P1", Name: "Login succeeds", Description: "Valid credentials redirect to dashboard", Status: epic.StatusPending},
+			{ID: "T1_T3", TaskID: "T1", PhaseID: "P1", Name: "Login fails", Status: epic.StatusPending},
+		},
+	}
+}
+
+func TestStartNextTestCommand(t *testing.T) {
+	runNextTest := func(t *testing.T, epicFile string, args ...string) (string, error) {
+		var stdout, stderr bytes.Buffer
+		cmd := StartNextTestCommand()
+		cmd.Root().Writer = &stdout
+		cmd.Root().ErrWriter = &stderr
+		err := cmd.Run(context.Background(), append([]string{"start-next-test", "--file", epicFile, "--time", "2025-08-16T15:30:00Z"}, args...))
+		return stdout.String(), err
+	}
+
+	t.Run("starts first pending test of the active task", func(t *testing.T) {
+		epicFile := filepath.Join(t.TempDir(), "epic.xml")
+		fileStorage := storage.NewFileStorage()
+		require.NoError(t, fileStorage.SaveEpic(createEpicForNextTest(), epicFile))
+
+		output, err := runNextTest(t, epicFile)
+		require.NoError(t, err)
+
+		assert.Contains(t, output, "Test T1_T2 started: Login succeeds")
+		assert.Contains(t, output, "Task: T1 (phase P1)")
+		assert.Contains(t, output, "Expected behavior: Valid credentials redirect to dashboard")
+
+		updated, err := fileStorage.LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, epic.TestStatusWIP, updated.Tests[2].TestStatus)
+		assert.Equal(t, epic.TestStatusPending, updated.Tests[3].GetTestStatusUnified())
+		assert.Equal(t, epic.StatusPending, updated.Tests[0].Status)
+		require.NotEmpty(t, updated.Events)
+		assert.Equal(t, "test_started", updated.Events[len(updated.Events)-1].Type)
+	})
+
+	t.Run("xml output mirrors start-next", func(t *testing.T) {
+		epicFile := filepath.Join(t.TempDir(), "epic.xml")
+		require.NoError(t, storage.NewFileStorage().SaveEpic(createEpicForNextTest(), epicFile))
+
+		output, err := runNextTest(t, epicFile, "--format", "xml")
+		require.NoError(t, err)
+
+		assert.Contains(t, output, `<test_started test="T1_T2">`)
+		assert.Contains(t, output, `<description>Valid credentials redirect to dashboard</description>`)
+		assert.Contains(t, output, `<started_at>2025-08-16T15:30:00Z</started_at>`)
+	})
+
+	t.Run("reports when no pending tests remain", func(t *testing.T) {
+		testEpic := createEpicForNextTest()
+		testEpic.Tests = testEpic.Tests[:2]
+		epicFile := filepath.Join(t.TempDir(), "epic.xml")
+		require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicFile))
+
+		output, err := runNextTest(t, epicFile)
+		require.NoError(t, err)
+		assert.Contains(t, output, "No pending tests left for task T1")
+	})
+
+	t.Run("json output omits the start time when no test was started", func(t *testing.T) {
+		testEpic := createEpicForNextTest()
+		testEpic.Tests = testEpic.Tests[:2]
+		epicFile := filepath.Join(t.TempDir(), "epic.xml")
+		require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicFile))
+
+		output, err := runNextTest(t, epicFile, "--format", "json")
+		require.NoError(t, err)
+		assert.Contains(t, output, `"started": false`)
+		assert.NotContains(t, output, "started_at")
+	})
+
+	t.Run("json output includes the start time of the started test", func(t *testing.T) {
+		epicFile := filepath.Join(t.TempDir(), "epic.xml")
+		require.NoError(t, storage.NewFileStorage().SaveEpic(createEpicForNextTest(), epicFile))
+
+		output, err := runNextTest(t, epicFile, "--format", "json")
+		require.NoError(t, err)
+		assert.Contains(t, output, `"started_at": "2025-08-16T15:30:00Z"`)
+	})
+
+	t.Run("fails without an active task", func(t *testing.T) {
+		testEpic := createEpicForNextTest()
+		testEpic.Tasks[0].Status = epic.StatusPending
+		epicFile := filepath.Join(t.TempDir(), "epic.xml")
+		require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicFile))
+
+		_, err := runNextTest(t, epicFile)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no task is active")
+	})
+}
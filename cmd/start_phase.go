@@ -3,9 +3,12 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/exitcode"
 	"github.com/mindreframer/agentpm/internal/hints"
 	"github.com/mindreframer/agentpm/internal/messages"
 	"github.com/mindreframer/agentpm/internal/phases"
@@ -43,11 +46,11 @@ func StartPhaseCommand() *cli.Command {
 				if err != nil {
 					return fmt.Errorf("failed to load configuration: %w", err)
 				}
-				epicFile = cfg.CurrentEpic
+				epicFile = cfg.EpicFilePath()
 			}
 
 			if epicFile == "" {
-				return fmt.Errorf("no epic file specified (use --file flag or set current epic)")
+				return exitcode.Errorf(exitcode.Validation, "no epic file specified (use --file flag or set current epic)")
 			}
 
 			// Parse timestamp if provided
@@ -177,8 +180,8 @@ func outputXMLErrorWithHint(cmd *cli.Command, errorType, message string, details
 	}
 	if len(details) > 0 {
 		fmt.Fprintf(cmd.ErrWriter, "    <details>\n")
-		for key, value := range details {
-			fmt.Fprintf(cmd.ErrWriter, "        <%s>%v</%s>\n", key, value, key)
+		for _, key := range slices.Sorted(maps.Keys(details)) {
+			fmt.Fprintf(cmd.ErrWriter, "        <%s>%v</%s>\n", key, details[key], key)
 		}
 		fmt.Fprintf(cmd.ErrWriter, "    </details>\n")
 	}
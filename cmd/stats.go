@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/stats"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// StatsCommand reports the aggregated statistics of an epic
+func StatsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "stats",
+		Usage: "Show aggregated epic statistics (status counts, cycle times, health)",
+		Description: `Aggregate the statistics of an epic into one document: phase, task and
+test counts per status, the completion shown by 'agentpm status', task cycle
+times (mean, median, P80) and a health score.
+
+The health score starts at 100 and loses 10 points per failing test (at
+most 40), 10 per overdue open phase or task (at most 30) and 30 when the
+epic fails validation: 80 and above is healthy, below 50 unhealthy.
+
+With --format json the output is the document dashboards read; 'agentpm
+serve' answers it on /api/v1/epics/<id>/stats, cached and rebuilt when the
+epic file changes.
+
+Examples:
+  agentpm stats
+  agentpm stats --format json`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config",
+			},
+		},
+		Action: statsAction,
+	}
+}
+
+func statsAction(ctx context.Context, c *cli.Command) error {
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if timeStr := c.String("time"); timeStr != "" {
+		now, err = time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+		}
+	}
+
+	epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+	report := stats.Build(epicData, now)
+
+	w := c.Root().Writer
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal statistics to JSON: %w", err)
+		}
+		fmt.Fprintf(w, "%s\n", jsonData)
+	case "xml":
+		outputStatsXML(c, report)
+	default:
+		fmt.Fprintf(w, "Epic %s: %s (%s)\n", report.EpicID, report.Name, report.Status)
+		fmt.Fprintf(w, "Completion: %d%%\n", report.Completion)
+		fmt.Fprintf(w, "Phases: %s\n", formatCounts(report.Phases))
+		fmt.Fprintf(w, "Tasks:  %s\n", formatCounts(report.Tasks))
+		fmt.Fprintf(w, "Tests:  %s\n", formatCounts(report.Tests))
+		if report.CycleTimes.Samples > 0 {
+			fmt.Fprintf(w, "Cycle times (%d tasks): mean %s, median %s, P80 %s\n", report.CycleTimes.Samples,
+				formatMinutes(report.CycleTimes.Mean), formatMinutes(report.CycleTimes.Median), formatMinutes(report.CycleTimes.P80))
+		} else {
+			fmt.Fprintf(w, "Cycle times: no completed tasks with start and completion times\n")
+		}
+		fmt.Fprintf(w, "Health: %d/100 (%s)\n", report.Health.Score, report.Health.Grade)
+		for _, finding := range report.Health.Findings {
+			fmt.Fprintf(w, "  - %s\n", finding)
+		}
+	}
+	return nil
+}
+
+func outputStatsXML(c *cli.Command, report *stats.Stats) {
+	doc := etree.NewDocument()
+	root := doc.CreateElement("stats")
+	root.CreateAttr("epic", report.EpicID)
+	root.CreateAttr("status", report.Status)
+	root.CreateAttr("completion_percent", strconv.Itoa(report.Completion))
+	root.CreateAttr("generated_at", report.GeneratedAt.Format(time.RFC3339))
+
+	for _, group := range []struct {
+		name   string
+		counts map[string]int
+	}{{"phases", report.Phases}, {"tasks", report.Tasks}, {"tests", report.Tests}} {
+		elem := root.CreateElement(group.name)
+		for _, status := range sortedKeys(group.counts) {
+			elem.CreateAttr(status, strconv.Itoa(group.counts[status]))
+		}
+	}
+
+	cycleElem := root.CreateElement("cycle_times")
+	cycleElem.CreateAttr("samples", strconv.Itoa(report.CycleTimes.Samples))
+	cycleElem.CreateAttr("mean_minutes", strconv.Itoa(report.CycleTimes.Mean))
+	cycleElem.CreateAttr("median_minutes", strconv.Itoa(report.CycleTimes.Median))
+	cycleElem.CreateAttr("p80_minutes", strconv.Itoa(report.CycleTimes.P80))
+
+	healthElem := root.CreateElement("health")
+	healthElem.CreateAttr("score", strconv.Itoa(report.Health.Score))
+	healthElem.CreateAttr("grade", report.Health.Grade)
+	for _, finding := range report.Health.Findings {
+		healthElem.CreateElement("finding").SetText(finding)
+	}
+
+	doc.Indent(4)
+	doc.WriteTo(c.Root().Writer)
+}
+
+func formatCounts(counts map[string]int) string {
+	var parts []string
+	for _, status := range sortedKeys(counts) {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[status], status))
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sortedKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
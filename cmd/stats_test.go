@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+const statsEpic = `<epic id="8" name="Stats Epic" status="wip" created_at="2025-08-01T09:00:00Z">
+    <phases>
+        <phase id="P1" name="Build" status="wip" due="2025-08-10"/>
+    </phases>
+    <tasks>
+        <task id="T1" phase_id="P1" name="Task 1" status="completed">
+            <started_at>2025-08-02T10:00:00Z</started_at>
+            <completed_at>2025-08-02T11:30:00Z</completed_at>
+        </task>
+        <task id="T2" phase_id="P1" name="Task 2" status="wip"/>
+    </tasks>
+    <tests>
+        <test id="X1" task_id="T1" phase_id="P1" name="Test 1" test_status="done" result="passing"/>
+    </tests>
+    <events/>
+</epic>`
+
+func TestStatsCommand(t *testing.T) {
+	run := func(t *testing.T, format string) string {
+		t.Helper()
+		epicFile := filepath.Join(t.TempDir(), "epic.xml")
+		require.NoError(t, os.WriteFile(epicFile, []byte(statsEpic), 0644))
+
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config", Value: filepath.Join(t.TempDir(), ".agentpm.json")},
+				&cli.StringFlag{Name: "format", Value: format},
+				&cli.StringFlag{Name: "time", Value: "2025-08-20T12:00:00Z"},
+			},
+			Commands: []*cli.Command{StatsCommand()},
+		}
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		require.NoError(t, app.Run(context.Background(), []string{"agentpm", "stats", "--file", epicFile}))
+		return stdout.String()
+	}
+
+	t.Run("text", func(t *testing.T) {
+		output := run(t, "text")
+		assert.Contains(t, output, "Epic 8: Stats Epic (wip)")
+		assert.Contains(t, output, "Tasks:  1 completed, 1 wip")
+		assert.Contains(t, output, "Cycle times (1 tasks): mean 1h30m, median 1h30m, P80 1h30m")
+		assert.Contains(t, output, "Health: 90/100 (healthy)\n  - 1 overdue phase(s) or task(s)")
+	})
+
+	t.Run("json document", func(t *testing.T) {
+		var document map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(run(t, "json")), &document))
+		assert.Equal(t, "8", document["epic_id"])
+		assert.Equal(t, "2025-08-20T12:00:00Z", document["generated_at"])
+		assert.Equal(t, map[string]interface{}{"done": 1.0, "failing": 0.0}, document["tests"])
+		assert.Equal(t, 90.0, document["health"].(map[string]interface{})["score"])
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		output := run(t, "xml")
+		assert.Contains(t, output, `<tasks completed="1" wip="1"/>`)
+		assert.Contains(t, output, `<health score="90" grade="healthy">`)
+	})
+}
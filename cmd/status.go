@@ -2,11 +2,21 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
+	"strconv"
+	"time"
 
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/apiversion"
 	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/output"
 	"github.com/mindreframer/agentpm/internal/query"
 	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/mindreframer/agentpm/internal/throughput"
 	"github.com/urfave/cli/v3"
 )
 
@@ -16,8 +26,8 @@ func StatusCommand() *cli.Command {
 		Name:    "status",
 		Usage:   "Display epic status and progress overview",
 		Aliases: []string{"s"},
-		Action:  statusAction,
-		Flags: []cli.Flag{
+		Action:  withWatch(withFieldSelection(statusAction), statusWatchedFiles),
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:    "file",
 				Aliases: []string{"f"},
@@ -26,11 +36,32 @@ func StatusCommand() *cli.Command {
 			&cli.StringFlag{
 				Name:    "format",
 				Aliases: []string{"F"},
-				Usage:   "Output format: text (default), json, xml",
+				Usage:   "Output format: text (default), json, xml, markdown",
 				Value:   "text",
 			},
-		},
+			&cli.BoolFlag{
+				Name:  "all",
+				Usage: "Aggregate progress across every epic of the workspace (see 'agentpm epics')",
+			},
+			fieldsFlag(),
+		}, watchFlags()...),
+	}
+}
+
+// statusWatchedFiles are the epic files status --watch re-renders on: all of the workspace with --all
+func statusWatchedFiles(c *cli.Command) ([]string, error) {
+	if !c.Bool("all") {
+		epicFile, err := getEpicFile(c)
+		if err != nil {
+			return nil, err
+		}
+		return []string{epicFile}, nil
 	}
+	cfg, err := config.LoadConfig(c.String("config"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return cfg.EpicFilePaths(), nil
 }
 
 func statusAction(ctx context.Context, c *cli.Command) error {
@@ -45,43 +76,73 @@ func statusAction(ctx context.Context, c *cli.Command) error {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
+	if c.Bool("all") {
+		return outputWorkspaceStatus(c, loadWorkspaceEpics(cfg))
+	}
+
 	// Determine epic file (command flag overrides config)
 	epicFile := c.String("file")
 	if epicFile == "" {
-		epicFile = cfg.CurrentEpic
+		epicFile = cfg.EpicFilePath()
 	}
 	if epicFile == "" {
-		return fmt.Errorf("no epic file specified. Use --file flag or run 'agentpm init' first")
+		return exitcode.Errorf(exitcode.Validation, "no epic file specified. Use --file flag or run 'agentpm init' first")
 	}
 
-	// Create storage and query service
-	storage := storage.NewFileStorage()
-	queryService := query.NewQueryService(storage)
-
-	// Load epic
-	err = queryService.LoadEpic(epicFile)
-	if err != nil {
-		return fmt.Errorf("failed to load epic: %w", err)
+	now := time.Now()
+	if timeStr := c.String("time"); timeStr != "" {
+		now, err = time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+		}
 	}
-
-	// Get epic status
-	status, err := queryService.GetEpicStatus()
+	status, err := loadEpicStatus(storage.NewFileStorage(), cfg, epicFile, now)
 	if err != nil {
-		return fmt.Errorf("failed to get epic status: %w", err)
+		return err
 	}
 
 	// Output based on format
 	outputFormat := c.String("format")
 	switch outputFormat {
 	case "xml":
-		return outputStatusXML(c, status)
+		return outputStatusXML(c, status, apiversion.FromContext(ctx))
 	case "json":
-		return outputStatusJSON(c, status)
+		return outputStatusJSON(c, status, apiversion.FromContext(ctx))
+	case "markdown":
+		return outputStatusMarkdown(c, status)
 	default:
 		return outputStatusText(c, status)
 	}
 }
 
+// loadEpicStatus loads an epic from store and gathers its status as of now, with
+// the test throughput of the last week, the deadlines and the remaining effort
+func loadEpicStatus(store storage.Storage, cfg *config.Config, epicFile string, now time.Time) (*query.EpicStatus, error) {
+	queryService := query.NewQueryService(store)
+	if err := queryService.LoadEpic(epicFile); err != nil {
+		return nil, fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	status, err := queryService.GetEpicStatus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get epic status: %w", err)
+	}
+	if status.TestThroughput, err = queryService.GetTestThroughput(now, 7); err != nil {
+		return nil, fmt.Errorf("failed to get test throughput: %w", err)
+	}
+	if status.Deadlines, err = queryService.GetDeadlines(now, cfg.UpcomingWindowDuration()); err != nil {
+		return nil, fmt.Errorf("failed to get deadlines: %w", err)
+	}
+	effort, err := queryService.GetRemainingEffort(now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remaining effort: %w", err)
+	}
+	if effort.HasEstimates() {
+		status.Effort = effort
+	}
+	return status, nil
+}
+
 func outputStatusText(c *cli.Command, status *query.EpicStatus) error {
 	fmt.Fprintf(c.Root().Writer, "Epic Status: %s\n", status.Name)
 	fmt.Fprintf(c.Root().Writer, "ID: %s\n", status.ID)
@@ -89,11 +150,33 @@ func outputStatusText(c *cli.Command, status *query.EpicStatus) error {
 	fmt.Fprintf(c.Root().Writer, "Progress: %d%% complete\n", status.CompletionPercentage)
 	fmt.Fprintf(c.Root().Writer, "\nPhases: %d/%d completed\n", status.CompletedPhases, status.TotalPhases)
 	fmt.Fprintf(c.Root().Writer, "Tests: %d passing, %d failing\n", status.PassingTests, status.FailingTests)
+	if status.FlakyTests > 0 {
+		fmt.Fprintf(c.Root().Writer, "Flaky Tests: %s\n", formatFlakyTests(status.FlakyTests))
+	}
+	if report := status.TestThroughput; report != nil && report.Trend != throughput.TrendIdle {
+		fmt.Fprintf(c.Root().Writer, "Test Trend: %s\n", formatTestTrend(report))
+	}
+	if status.Recurring != nil {
+		fmt.Fprintf(c.Root().Writer, "One-off Tasks: %s\n", formatOneOffTasks(status.Recurring))
+		fmt.Fprintf(c.Root().Writer, "Recurring Tasks: %s\n", formatRecurringTasks(status.Recurring))
+	}
+	if status.Effort != nil {
+		fmt.Fprintf(c.Root().Writer, "Remaining Effort: %s\n", formatEffort(status.Effort))
+	}
+
+	if len(status.Deadlines) > 0 {
+		fmt.Fprintf(c.Root().Writer, "\nDeadlines:\n")
+		for _, deadline := range status.Deadlines {
+			fmt.Fprintf(c.Root().Writer, "  %s\n", formatDeadline(newStatusDeadline(deadline)))
+		}
+	}
 
 	if status.CurrentPhase != "" {
 		fmt.Fprintf(c.Root().Writer, "\nCurrent Phase: %s\n", status.CurrentPhase)
 	}
-	if status.CurrentTask != "" {
+	if status.CurrentParentTask != "" {
+		fmt.Fprintf(c.Root().Writer, "Current Task: %s (sub-task of %s)\n", status.CurrentTask, status.CurrentParentTask)
+	} else if status.CurrentTask != "" {
 		fmt.Fprintf(c.Root().Writer, "Current Task: %s\n", status.CurrentTask)
 	}
 
@@ -145,90 +228,312 @@ func outputStatusText(c *cli.Command, status *query.EpicStatus) error {
 	return nil
 }
 
-func outputStatusJSON(c *cli.Command, status *query.EpicStatus) error {
-	// Build validation errors array
-	validationErrors := "[]"
-	if len(status.Epic13Status.ValidationErrors) > 0 {
-		validationErrors = `["`
-		for i, err := range status.Epic13Status.ValidationErrors {
-			if i > 0 {
-				validationErrors += `", "`
-			}
-			validationErrors += err
+// formatDeadline describes a deadline, marking it when it has passed, e.g.
+// "[OVERDUE] task 1A_1 (Login form) was due 2025-09-15"
+func formatDeadline(deadline statusDeadline) string {
+	if deadline.Overdue {
+		return fmt.Sprintf("[OVERDUE] %s %s (%s) was due %s", deadline.Type, deadline.ID, deadline.Name, deadline.Due)
+	}
+	return fmt.Sprintf("%s %s (%s) due %s", deadline.Type, deadline.ID, deadline.Name, deadline.Due)
+}
+
+// formatFlakyTests points at the history of the flaky tests, e.g. "2 (see agentpm failing --history)"
+func formatFlakyTests(count int) string {
+	return fmt.Sprintf("%d (see agentpm failing --history)", count)
+}
+
+// formatTestTrend summarizes a week of test results as
+// "net +3 tests passing this week (4 fixed, 1 newly failing, converging)"
+func formatTestTrend(report *throughput.Report) string {
+	return fmt.Sprintf("net %+d tests passing this week (%d fixed, %d newly failing, %s)",
+		report.Net, report.Fixed, report.NewlyFailing, report.Trend)
+}
+
+// outputStatusMarkdown renders the status for pull requests and issue trackers
+func outputStatusMarkdown(c *cli.Command, status *query.EpicStatus) error {
+	unified := status.Epic13Status.UnifiedStatuses
+
+	md := output.NewMarkdown()
+	md.Heading(2, fmt.Sprintf("Epic Status: %s", status.Name))
+	md.Field("ID", status.ID)
+	md.Field("Status", string(status.Status))
+	md.Field("Progress", fmt.Sprintf("%d%% complete", status.CompletionPercentage))
+	md.Field("Phases", fmt.Sprintf("%d/%d completed", status.CompletedPhases, status.TotalPhases))
+	md.Field("Tests", fmt.Sprintf("%d passing, %d failing", status.PassingTests, status.FailingTests))
+	if status.FlakyTests > 0 {
+		md.Field("Flaky Tests", formatFlakyTests(status.FlakyTests))
+	}
+	if status.Recurring != nil {
+		md.Field("One-off Tasks", formatOneOffTasks(status.Recurring))
+		md.Field("Recurring Tasks", formatRecurringTasks(status.Recurring))
+	}
+	if status.Effort != nil {
+		md.Field("Remaining Effort", formatEffort(status.Effort))
+	}
+	if status.CurrentPhase != "" {
+		md.Field("Current Phase", status.CurrentPhase)
+	}
+	if status.CurrentParentTask != "" {
+		md.Field("Current Task", fmt.Sprintf("%s (sub-task of %s)", status.CurrentTask, status.CurrentParentTask))
+	} else if status.CurrentTask != "" {
+		md.Field("Current Task", status.CurrentTask)
+	}
+	md.Field("Can Complete", strconv.FormatBool(status.Epic13Status.CanComplete))
+
+	if len(status.Deadlines) > 0 {
+		md.Heading(3, "Deadlines")
+		for _, deadline := range status.Deadlines {
+			md.Item("%s", output.Inline(formatDeadline(newStatusDeadline(deadline))))
 		}
-		validationErrors += `"]`
 	}
 
-	// Build next actions array
-	nextActions := "[]"
+	md.Table([]string{"Type", "WIP", "Done"}, [][]string{
+		{"Phases", strconv.Itoa(unified.PhasesWIP), strconv.Itoa(unified.PhasesDone)},
+		{"Tasks", strconv.Itoa(unified.TasksWIP), strconv.Itoa(unified.TasksDone)},
+		{"Tests", strconv.Itoa(unified.TestsWIP), strconv.Itoa(unified.TestsDone)},
+	})
+
 	if len(status.Epic13Status.NextActions) > 0 {
-		nextActions = `["`
-		for i, action := range status.Epic13Status.NextActions {
-			if i > 0 {
-				nextActions += `", "`
-			}
-			nextActions += action
+		md.Heading(3, "Next Actions")
+		for _, action := range status.Epic13Status.NextActions {
+			md.Item("%s", output.Inline(action))
 		}
-		nextActions += `"]`
-	}
-
-	jsonOutput := fmt.Sprintf(`{
-  "epic": "%s",
-  "name": "%s",
-  "status": "%s",
-  "progress": {
-    "completion_percentage": %d,
-    "completed_phases": %d,
-    "total_phases": %d,
-    "passing_tests": %d,
-    "failing_tests": %d
-  },
-  "current_phase": "%s",
-  "current_task": "%s",
-  "epic13_status": {
-    "can_complete": %t,
-    "blocking_items": %d,
-    "unified_statuses": {
-      "epic_status": "%s",
-      "phases_wip": %d,
-      "phases_done": %d,
-      "tasks_wip": %d,
-      "tasks_done": %d,
-      "tests_wip": %d,
-      "tests_done": %d
-    },
-    "validation_errors": %s,
-    "next_actions": %s
-  }
-}`,
-		status.ID,
-		status.Name,
-		status.Status,
-		status.CompletionPercentage,
-		status.CompletedPhases,
-		status.TotalPhases,
-		status.PassingTests,
-		status.FailingTests,
-		status.CurrentPhase,
-		status.CurrentTask,
-		status.Epic13Status.CanComplete,
-		status.Epic13Status.BlockingItems,
-		status.Epic13Status.UnifiedStatuses.EpicStatus,
-		status.Epic13Status.UnifiedStatuses.PhasesWIP,
-		status.Epic13Status.UnifiedStatuses.PhasesDone,
-		status.Epic13Status.UnifiedStatuses.TasksWIP,
-		status.Epic13Status.UnifiedStatuses.TasksDone,
-		status.Epic13Status.UnifiedStatuses.TestsWIP,
-		status.Epic13Status.UnifiedStatuses.TestsDone,
-		validationErrors,
-		nextActions,
-	)
+	}
+
+	if len(status.Epic13Status.ValidationErrors) > 0 {
+		md.Heading(3, "Validation Issues")
+		for _, err := range status.Epic13Status.ValidationErrors {
+			md.Item("%s", output.Inline(err))
+		}
+	}
+
+	_, err := md.WriteTo(c.Root().Writer)
+	return err
+}
+
+// formatOneOffTasks summarizes the tasks done once, e.g. "3/5 completed"
+func formatOneOffTasks(recurring *query.RecurringStatus) string {
+	return fmt.Sprintf("%d/%d completed", recurring.CompletedOneOffTasks, recurring.OneOffTasks)
+}
 
-	fmt.Fprintf(c.Root().Writer, "%s\n", jsonOutput)
+// formatRecurringTasks summarizes the occurrences of recurring tasks, e.g.
+// "1 scheduled, 4 completed, next 1A_3_r5 due 2025-09-22"
+func formatRecurringTasks(recurring *query.RecurringStatus) string {
+	text := fmt.Sprintf("%d scheduled, %d completed", recurring.OpenOccurrences, recurring.CompletedOccurrences)
+	if recurring.NextTask != "" {
+		text += fmt.Sprintf(", next %s due %s", recurring.NextTask, recurring.NextDue)
+	}
+	return text
+}
+
+// formatEffort summarizes the estimated effort and its projection, e.g.
+// "5 of 13, ETA 2025-09-20 at 1.5 per day (2 open tasks unestimated)"
+func formatEffort(effort *query.RemainingEffort) string {
+	text := fmt.Sprintf("%s of %s", formatAmount(effort.Remaining), formatAmount(effort.Total))
+	switch {
+	case !effort.ETA.IsZero():
+		text += fmt.Sprintf(", ETA %s at %s per day", effort.ETA.Format(time.DateOnly), formatAmount(effort.Velocity))
+	case effort.Remaining > 0:
+		text += ", no ETA before estimated work is done"
+	}
+	if effort.Unestimated > 0 {
+		text += fmt.Sprintf(" (%d open tasks unestimated)", effort.Unestimated)
+	}
+	return text
+}
+
+// formatAmount shows an amount of effort with at most one decimal
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(math.Round(amount*10)/10, 'f', -1, 64)
+}
+
+// statusSummary is the part of the status output all API versions share
+type statusSummary struct {
+	Epic         string           `json:"epic"`
+	Name         string           `json:"name"`
+	Status       string           `json:"status" doc:"Epic status"`
+	Progress     statusProgress   `json:"progress"`
+	CurrentPhase string           `json:"current_phase" doc:"Active phase, empty when none"`
+	CurrentTask  string           `json:"current_task" doc:"Active task, empty when none"`
+	ParentTask   string           `json:"current_parent_task,omitempty" doc:"Parent of the active task when it is a sub-task"`
+	Recurring    *statusRecurring `json:"recurring,omitempty" doc:"One-off tasks and occurrences of recurring tasks, absent without recurring tasks"`
+	Deadlines    []statusDeadline `json:"deadlines,omitempty" doc:"Open deadlines that passed or come up within upcoming_window, earliest first; absent when none"`
+	Effort       *statusEffort    `json:"effort,omitempty" doc:"Estimated effort of the tasks and when the rest is done, absent without estimates"`
+}
+
+type statusEffort struct {
+	Total       float64             `json:"total" doc:"Sum of the task estimates, in points or minutes"`
+	Remaining   float64             `json:"remaining" doc:"Estimates of the tasks not done yet"`
+	Unestimated int                 `json:"unestimated_tasks" doc:"Open tasks without an estimate"`
+	Velocity    float64             `json:"velocity" doc:"Estimated effort done per day since the first task was started"`
+	ETA         string              `json:"eta,omitempty" doc:"Projected completion at the velocity, absent when unknown"`
+	Phases      []statusPhaseEffort `json:"phases"`
+}
+
+type statusPhaseEffort struct {
+	PhaseID     string  `json:"phase_id"`
+	Name        string  `json:"name"`
+	Total       float64 `json:"total"`
+	Remaining   float64 `json:"remaining"`
+	Unestimated int     `json:"unestimated_tasks"`
+}
+
+func newStatusEffort(effort *query.RemainingEffort) *statusEffort {
+	result := &statusEffort{
+		Total: effort.Total, Remaining: effort.Remaining, Unestimated: effort.Unestimated,
+		Velocity: math.Round(effort.Velocity*100) / 100, Phases: []statusPhaseEffort{},
+	}
+	if !effort.ETA.IsZero() {
+		result.ETA = effort.ETA.UTC().Format(time.RFC3339)
+	}
+	for _, phase := range effort.Phases {
+		result.Phases = append(result.Phases, statusPhaseEffort{
+			PhaseID: phase.PhaseID, Name: phase.Name,
+			Total: phase.Total, Remaining: phase.Remaining, Unestimated: phase.Unestimated,
+		})
+	}
+	return result
+}
+
+type statusDeadline struct {
+	Type    string `json:"type" doc:"epic, phase or task"`
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Due     string `json:"due"`
+	Overdue bool   `json:"overdue"`
+}
+
+func newStatusDeadline(deadline query.Deadline) statusDeadline {
+	return statusDeadline{
+		Type: deadline.Type, ID: deadline.ID, Name: deadline.Name,
+		Status: string(deadline.Status), Due: deadline.Due, Overdue: deadline.Overdue,
+	}
+}
+
+type statusRecurring struct {
+	OneOffTasks          int    `json:"one_off_tasks"`
+	CompletedOneOffTasks int    `json:"completed_one_off_tasks"`
+	Scheduled            int    `json:"scheduled" doc:"Pending and active occurrences of recurring tasks"`
+	Completed            int    `json:"completed" doc:"Completed occurrences of recurring tasks"`
+	NextTask             string `json:"next_task,omitempty" doc:"Pending occurrence that is due next"`
+	NextDue              string `json:"next_due,omitempty"`
+}
+
+// statusOutput is the JSON output of status
+type statusOutput struct {
+	statusSummary
+	Completion statusCompletion `json:"completion" doc:"Whether the epic can be completed and what is left"`
+}
+
+// statusOutputV1 is the JSON output of status in API version 1
+type statusOutputV1 struct {
+	statusSummary
+	Epic13Status statusCompletion `json:"epic13_status"`
+}
+
+type statusProgress struct {
+	CompletionPercentage int `json:"completion_percentage"`
+	CompletedPhases      int `json:"completed_phases"`
+	TotalPhases          int `json:"total_phases"`
+	PassingTests         int `json:"passing_tests"`
+	FailingTests         int `json:"failing_tests"`
+	FlakyTests           int `json:"flaky_tests,omitempty" doc:"Tests passing again after failing repeatedly, absent when none"`
+}
+
+type statusCompletion struct {
+	CanComplete      bool                  `json:"can_complete"`
+	BlockingItems    int                   `json:"blocking_items"`
+	UnifiedStatuses  statusUnifiedStatuses `json:"unified_statuses"`
+	ValidationErrors []string              `json:"validation_errors"`
+	NextActions      []string              `json:"next_actions"`
+}
+
+type statusUnifiedStatuses struct {
+	EpicStatus string `json:"epic_status"`
+	PhasesWIP  int    `json:"phases_wip"`
+	PhasesDone int    `json:"phases_done"`
+	TasksWIP   int    `json:"tasks_wip"`
+	TasksDone  int    `json:"tasks_done"`
+	TestsWIP   int    `json:"tests_wip"`
+	TestsDone  int    `json:"tests_done"`
+}
+
+func outputStatusJSON(c *cli.Command, status *query.EpicStatus, version apiversion.Version) error {
+	jsonData, err := json.MarshalIndent(newStatusJSON(status, version), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal status to JSON: %w", err)
+	}
+	fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
 	return nil
 }
 
-func outputStatusXML(c *cli.Command, status *query.EpicStatus) error {
+// newStatusJSON returns the JSON output of status in the given API version
+func newStatusJSON(status *query.EpicStatus, version apiversion.Version) interface{} {
+	unified := status.Epic13Status.UnifiedStatuses
+	summary := statusSummary{
+		Epic:   status.ID,
+		Name:   status.Name,
+		Status: string(status.Status),
+		Progress: statusProgress{
+			CompletionPercentage: status.CompletionPercentage,
+			CompletedPhases:      status.CompletedPhases,
+			TotalPhases:          status.TotalPhases,
+			PassingTests:         status.PassingTests,
+			FailingTests:         status.FailingTests,
+			FlakyTests:           status.FlakyTests,
+		},
+		CurrentPhase: status.CurrentPhase,
+		CurrentTask:  status.CurrentTask,
+		ParentTask:   status.CurrentParentTask,
+	}
+	if recurring := status.Recurring; recurring != nil {
+		summary.Recurring = &statusRecurring{
+			OneOffTasks:          recurring.OneOffTasks,
+			CompletedOneOffTasks: recurring.CompletedOneOffTasks,
+			Scheduled:            recurring.OpenOccurrences,
+			Completed:            recurring.CompletedOccurrences,
+			NextTask:             recurring.NextTask,
+			NextDue:              recurring.NextDue,
+		}
+	}
+	for _, deadline := range status.Deadlines {
+		summary.Deadlines = append(summary.Deadlines, newStatusDeadline(deadline))
+	}
+	if status.Effort != nil {
+		summary.Effort = newStatusEffort(status.Effort)
+	}
+	completion := statusCompletion{
+		CanComplete:   status.Epic13Status.CanComplete,
+		BlockingItems: status.Epic13Status.BlockingItems,
+		UnifiedStatuses: statusUnifiedStatuses{
+			EpicStatus: string(unified.EpicStatus),
+			PhasesWIP:  unified.PhasesWIP,
+			PhasesDone: unified.PhasesDone,
+			TasksWIP:   unified.TasksWIP,
+			TasksDone:  unified.TasksDone,
+			TestsWIP:   unified.TestsWIP,
+			TestsDone:  unified.TestsDone,
+		},
+		ValidationErrors: nonNilStrings(status.Epic13Status.ValidationErrors),
+		NextActions:      nonNilStrings(status.Epic13Status.NextActions),
+	}
+
+	if version == apiversion.V1 {
+		return statusOutputV1{statusSummary: summary, Epic13Status: completion}
+	}
+	return statusOutput{statusSummary: summary, Completion: completion}
+}
+
+// nonNilStrings makes an absent list render as [] rather than null in JSON
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func outputStatusXML(c *cli.Command, status *query.EpicStatus, version apiversion.Version) error {
 	// Build validation errors XML
 	validationErrorsXML := ""
 	for _, err := range status.Epic13Status.ValidationErrors {
@@ -241,6 +546,57 @@ func outputStatusXML(c *cli.Command, status *query.EpicStatus) error {
 		nextActionsXML += fmt.Sprintf("        <action>%s</action>\n", action)
 	}
 
+	parentTaskAttr := ""
+	if status.CurrentParentTask != "" {
+		parentTaskAttr = fmt.Sprintf(` parent_task_id="%s"`, status.CurrentParentTask)
+	}
+
+	recurringXML := ""
+	if recurring := status.Recurring; recurring != nil {
+		nextAttrs := ""
+		if recurring.NextTask != "" {
+			nextAttrs = fmt.Sprintf(` next_task="%s" next_due="%s"`, recurring.NextTask, recurring.NextDue)
+		}
+		recurringXML = fmt.Sprintf("    <recurring one_off_tasks=\"%d\" completed_one_off_tasks=\"%d\" scheduled=\"%d\" completed=\"%d\"%s/>\n",
+			recurring.OneOffTasks, recurring.CompletedOneOffTasks, recurring.OpenOccurrences, recurring.CompletedOccurrences, nextAttrs)
+	}
+
+	deadlinesXML := ""
+	if len(status.Deadlines) > 0 {
+		deadlinesXML = "    <deadlines>\n"
+		for _, deadline := range status.Deadlines {
+			deadlinesXML += fmt.Sprintf("        <deadline type=\"%s\" id=\"%s\" due=\"%s\" overdue=\"%t\">%s</deadline>\n",
+				deadline.Type, deadline.ID, deadline.Due, deadline.Overdue, deadline.Name)
+		}
+		deadlinesXML += "    </deadlines>\n"
+	}
+
+	effortXML := ""
+	if status.Effort != nil {
+		effort := newStatusEffort(status.Effort)
+		etaAttr := ""
+		if effort.ETA != "" {
+			etaAttr = fmt.Sprintf(` eta="%s"`, effort.ETA)
+		}
+		effortXML = fmt.Sprintf("    <effort total=\"%s\" remaining=\"%s\" unestimated_tasks=\"%d\" velocity=\"%s\"%s>\n",
+			formatAmount(effort.Total), formatAmount(effort.Remaining), effort.Unestimated, formatAmount(effort.Velocity), etaAttr)
+		for _, phase := range effort.Phases {
+			effortXML += fmt.Sprintf("        <phase id=\"%s\" total=\"%s\" remaining=\"%s\" unestimated_tasks=\"%d\"/>\n",
+				phase.PhaseID, formatAmount(phase.Total), formatAmount(phase.Remaining), phase.Unestimated)
+		}
+		effortXML += "    </effort>\n"
+	}
+
+	flakyXML := ""
+	if status.FlakyTests > 0 {
+		flakyXML = fmt.Sprintf("        <flaky_tests>%d</flaky_tests>\n", status.FlakyTests)
+	}
+
+	completionElement := "completion"
+	if version == apiversion.V1 {
+		completionElement = "epic13_status"
+	}
+
 	xmlOutput := fmt.Sprintf(`<status epic="%s">
     <name>%s</name>
     <status>%s</status>
@@ -249,11 +605,11 @@ func outputStatusXML(c *cli.Command, status *query.EpicStatus) error {
         <total_phases>%d</total_phases>
         <passing_tests>%d</passing_tests>
         <failing_tests>%d</failing_tests>
-        <completion_percentage>%d</completion_percentage>
+%s        <completion_percentage>%d</completion_percentage>
     </progress>
     <current_phase>%s</current_phase>
-    <current_task>%s</current_task>
-    <epic13_status>
+    <current_task%s>%s</current_task>
+%s%s%s    <%s>
         <can_complete>%t</can_complete>
         <blocking_items>%d</blocking_items>
         <unified_statuses>
@@ -269,7 +625,7 @@ func outputStatusXML(c *cli.Command, status *query.EpicStatus) error {
 %s        </validation_errors>
         <next_actions>
 %s        </next_actions>
-    </epic13_status>
+    </%s>
 </status>`,
 		status.ID,
 		status.Name,
@@ -278,9 +634,15 @@ func outputStatusXML(c *cli.Command, status *query.EpicStatus) error {
 		status.TotalPhases,
 		status.PassingTests,
 		status.FailingTests,
+		flakyXML,
 		status.CompletionPercentage,
 		status.CurrentPhase,
+		parentTaskAttr,
 		status.CurrentTask,
+		recurringXML,
+		deadlinesXML,
+		effortXML,
+		completionElement,
 		status.Epic13Status.CanComplete,
 		status.Epic13Status.BlockingItems,
 		status.Epic13Status.UnifiedStatuses.EpicStatus,
@@ -292,8 +654,128 @@ func outputStatusXML(c *cli.Command, status *query.EpicStatus) error {
 		status.Epic13Status.UnifiedStatuses.TestsDone,
 		validationErrorsXML,
 		nextActionsXML,
+		completionElement,
 	)
 
 	fmt.Fprintf(c.Root().Writer, "%s\n", xmlOutput)
 	return nil
 }
+
+// workspaceTotals sums the progress of the epics that could be loaded
+type workspaceTotals struct {
+	Epics           int `json:"epics"`
+	CompletedEpics  int `json:"completed_epics"`
+	CompletedPhases int `json:"completed_phases"`
+	TotalPhases     int `json:"total_phases"`
+	PassingTests    int `json:"passing_tests"`
+	FailingTests    int `json:"failing_tests"`
+	Completion      int `json:"completion_percentage"` // Average over the epics
+}
+
+func outputWorkspaceStatus(c *cli.Command, epics []workspaceEpic) error {
+	var totals workspaceTotals
+	completionSum := 0
+	for _, entry := range epics {
+		if entry.Err != nil {
+			continue
+		}
+		totals.Epics++
+		if entry.Status.Status == epic.StatusCompleted {
+			totals.CompletedEpics++
+		}
+		totals.CompletedPhases += entry.Status.CompletedPhases
+		totals.TotalPhases += entry.Status.TotalPhases
+		totals.PassingTests += entry.Status.PassingTests
+		totals.FailingTests += entry.Status.FailingTests
+		completionSum += entry.Status.CompletionPercentage
+	}
+	if totals.Epics > 0 {
+		totals.Completion = completionSum / totals.Epics
+	}
+
+	w := c.Root().Writer
+	switch c.String("format") {
+	case "json":
+		type epicOutput struct {
+			Path                 string `json:"path"`
+			Current              bool   `json:"current"`
+			ID                   string `json:"epic,omitempty"`
+			Name                 string `json:"name,omitempty"`
+			Status               string `json:"status,omitempty"`
+			CompletionPercentage int    `json:"completion_percentage"`
+			CompletedPhases      int    `json:"completed_phases"`
+			TotalPhases          int    `json:"total_phases"`
+			PassingTests         int    `json:"passing_tests"`
+			FailingTests         int    `json:"failing_tests"`
+			Error                string `json:"error,omitempty"`
+		}
+		outputs := make([]epicOutput, len(epics))
+		for i, entry := range epics {
+			outputs[i] = epicOutput{Path: entry.Path, Current: entry.Current}
+			if entry.Err != nil {
+				outputs[i].Error = entry.Err.Error()
+				continue
+			}
+			status := entry.Status
+			outputs[i].ID, outputs[i].Name, outputs[i].Status = status.ID, status.Name, string(status.Status)
+			outputs[i].CompletionPercentage = status.CompletionPercentage
+			outputs[i].CompletedPhases, outputs[i].TotalPhases = status.CompletedPhases, status.TotalPhases
+			outputs[i].PassingTests, outputs[i].FailingTests = status.PassingTests, status.FailingTests
+		}
+		jsonData, err := json.MarshalIndent(map[string]interface{}{"epics": outputs, "totals": totals}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal workspace status to JSON: %w", err)
+		}
+		fmt.Fprintf(w, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("workspace_status")
+		for _, entry := range epics {
+			elem := root.CreateElement("epic")
+			elem.CreateAttr("path", entry.Path)
+			elem.CreateAttr("current", strconv.FormatBool(entry.Current))
+			if entry.Err != nil {
+				elem.CreateAttr("error", entry.Err.Error())
+				continue
+			}
+			status := entry.Status
+			elem.CreateAttr("id", status.ID)
+			elem.CreateAttr("name", status.Name)
+			elem.CreateAttr("status", string(status.Status))
+			elem.CreateAttr("completion_percentage", strconv.Itoa(status.CompletionPercentage))
+			elem.CreateAttr("completed_phases", strconv.Itoa(status.CompletedPhases))
+			elem.CreateAttr("total_phases", strconv.Itoa(status.TotalPhases))
+			elem.CreateAttr("passing_tests", strconv.Itoa(status.PassingTests))
+			elem.CreateAttr("failing_tests", strconv.Itoa(status.FailingTests))
+		}
+		totalsElem := root.CreateElement("totals")
+		totalsElem.CreateAttr("epics", strconv.Itoa(totals.Epics))
+		totalsElem.CreateAttr("completed_epics", strconv.Itoa(totals.CompletedEpics))
+		totalsElem.CreateAttr("completion_percentage", strconv.Itoa(totals.Completion))
+		totalsElem.CreateAttr("completed_phases", strconv.Itoa(totals.CompletedPhases))
+		totalsElem.CreateAttr("total_phases", strconv.Itoa(totals.TotalPhases))
+		totalsElem.CreateAttr("passing_tests", strconv.Itoa(totals.PassingTests))
+		totalsElem.CreateAttr("failing_tests", strconv.Itoa(totals.FailingTests))
+		doc.Indent(4)
+		doc.WriteTo(w)
+	default:
+		fmt.Fprintf(w, "Workspace Status: %d epics\n\n", len(epics))
+		for _, entry := range epics {
+			marker := " "
+			if entry.Current {
+				marker = "*"
+			}
+			if entry.Err != nil {
+				fmt.Fprintf(w, "%s %-24s  error: %v\n", marker, entry.Path, entry.Err)
+				continue
+			}
+			status := entry.Status
+			fmt.Fprintf(w, "%s %-24s  %3d%%  %-10s phases %d/%d  tests %d passing, %d failing  %s\n", marker, entry.Path,
+				status.CompletionPercentage, status.Status, status.CompletedPhases, status.TotalPhases, status.PassingTests, status.FailingTests, status.Name)
+		}
+		fmt.Fprintf(w, "\nOverall: %d%% complete (average of %d epics), %d/%d epics completed\n", totals.Completion, totals.Epics, totals.CompletedEpics, totals.Epics)
+		fmt.Fprintf(w, "Phases: %d/%d completed\n", totals.CompletedPhases, totals.TotalPhases)
+		fmt.Fprintf(w, "Tests: %d passing, %d failing\n", totals.PassingTests, totals.FailingTests)
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	apmtesting "github.com/mindreframer/agentpm/internal/testing"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
 )
 
 func createTestEpicForStatus() *epic.Epic {
@@ -627,3 +628,153 @@ func TestStatusCommandPerformance(t *testing.T) {
 		t.Logf("Status command executed in: %v", duration)
 	})
 }
+
+func TestStatusCommandFields(t *testing.T) {
+	tempDir := t.TempDir()
+	epicPath := filepath.Join(tempDir, "test-epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(createTestEpicForStatus(), epicPath))
+
+	run := func(args ...string) (string, error) {
+		var stdout, stderr bytes.Buffer
+		cmd := StatusCommand()
+		cmd.Root().Writer = &stdout
+		cmd.Root().ErrWriter = &stderr
+		err := cmd.Run(context.Background(), append([]string{"status", "--file", epicPath}, args...))
+		return stdout.String(), err
+	}
+
+	t.Run("json output keeps only requested fields", func(t *testing.T) {
+		output, err := run("--format", "json", "--fields", "name,progress.completion_percentage")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name": "Status Test Epic", "progress": {"completion_percentage": 30}}`, output)
+	})
+
+	t.Run("xml output keeps only requested fields", func(t *testing.T) {
+		output, err := run("--format", "xml", "--fields", "epic,current_task")
+		require.NoError(t, err)
+		assert.Equal(t, `<status epic="status-test-epic">
+    <current_task>T2</current_task>
+</status>
+`, output)
+	})
+
+	t.Run("text output rejects fields", func(t *testing.T) {
+		_, err := run("--fields", "name")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--fields requires --format json, jsonl or xml")
+	})
+}
+
+func TestStatusCommandSubTask(t *testing.T) {
+	testEpic := createTestEpicForStatus()
+	testEpic.Tasks = append(testEpic.Tasks,
+		epic.Task{ID: "T2a", PhaseID: "P2", ParentTaskID: "T2", Name: "Active Sub-task", Status: epic.StatusWIP})
+	epicPath := filepath.Join(t.TempDir(), "test-epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicPath))
+
+	run := func(args ...string) string {
+		var stdout bytes.Buffer
+		cmd := StatusCommand()
+		cmd.Root().Writer = &stdout
+		require.NoError(t, cmd.Run(context.Background(), append([]string{"status", "--file", epicPath}, args...)))
+		return stdout.String()
+	}
+
+	assert.Contains(t, run(), "Current Task: T2a (sub-task of T2)\n")
+	output := run("--format", "json")
+	assert.Contains(t, output, `"current_task": "T2a"`)
+	assert.Contains(t, output, `"current_parent_task": "T2"`)
+	assert.Contains(t, run("--format", "xml"), `<current_task parent_task_id="T2">T2a</current_task>`)
+}
+
+func TestStatusCommandRecurring(t *testing.T) {
+	testEpic := createTestEpicForStatus()
+	testEpic.Tasks = append(testEpic.Tasks,
+		epic.Task{ID: "T4", PhaseID: "P2", Name: "Rotate keys", Status: epic.StatusCompleted, Recurring: "7d", Due: "2025-09-08"},
+		epic.Task{ID: "T4_r2", PhaseID: "P2", Name: "Rotate keys", Status: epic.StatusPending, Recurring: "7d", Due: "2025-09-15"})
+	epicPath := filepath.Join(t.TempDir(), "test-epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicPath))
+
+	run := func(args ...string) string {
+		var stdout bytes.Buffer
+		cmd := StatusCommand()
+		cmd.Root().Writer = &stdout
+		require.NoError(t, cmd.Run(context.Background(), append([]string{"status", "--file", epicPath}, args...)))
+		return stdout.String()
+	}
+
+	output := run()
+	assert.Contains(t, output, "One-off Tasks: 1/4 completed\n")
+	assert.Contains(t, output, "Recurring Tasks: 1 scheduled, 1 completed, next T4_r2 due 2025-09-15\n")
+	assert.Contains(t, run("--format", "markdown"), "- **Recurring Tasks:** 1 scheduled, 1 completed, next T4_r2 due 2025-09-15\n")
+	output = run("--format", "json")
+	assert.Contains(t, output, `"one_off_tasks": 4`)
+	assert.Contains(t, output, `"next_task": "T4_r2"`)
+	assert.Contains(t, run("--format", "xml"),
+		`<recurring one_off_tasks="4" completed_one_off_tasks="1" scheduled="1" completed="1" next_task="T4_r2" next_due="2025-09-15"/>`)
+
+	// Epics without recurring tasks are reported as before
+	require.NoError(t, storage.NewFileStorage().SaveEpic(createTestEpicForStatus(), epicPath))
+	assert.NotContains(t, run(), "Recurring Tasks")
+	assert.NotContains(t, run("--format", "json"), `"recurring"`)
+}
+
+func TestStatusCommandMarkdown(t *testing.T) {
+	epicPath := filepath.Join(t.TempDir(), "test-epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(createTestEpicForStatus(), epicPath))
+
+	var stdout bytes.Buffer
+	cmd := StatusCommand()
+	cmd.Root().Writer = &stdout
+	require.NoError(t, cmd.Run(context.Background(), []string{"status", "--file", epicPath, "--format", "markdown"}))
+
+	output := stdout.String()
+	assert.Contains(t, output, "## Epic Status: Status Test Epic\n\n")
+	assert.Contains(t, output, "- **Progress:** 30% complete\n")
+	assert.Contains(t, output, "- **Current Task:** T2\n")
+	assert.Contains(t, output, "| Type | WIP | Done |\n|------|-----|------|\n| Phases | 1 | 1 |\n")
+	assert.Contains(t, output, "### Next Actions\n\n- ")
+}
+
+func TestStatusCommandEffort(t *testing.T) {
+	testEpic := createTestEpicForStatus()
+	testEpic.Tasks[0].Estimate = "3"
+	testEpic.Tasks[1].Estimate = "5"
+	testEpic.Tasks[2].Estimate = "2"
+	testEpic.Events = append(testEpic.Events,
+		epic.Event{ID: "E2", Type: "task_started", Timestamp: time.Date(2025, 8, 16, 10, 0, 0, 0, time.UTC), Data: "Task T1 (Setup Task) started"},
+		epic.Event{ID: "E3", Type: "task_completed", Timestamp: time.Date(2025, 8, 17, 10, 0, 0, 0, time.UTC), Data: "Task T1 (Setup Task) completed"})
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, ".agentpm.json")
+	epicPath := filepath.Join(tempDir, "test-epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicPath))
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"current_epic": "test-epic.xml"}`), 0644))
+
+	run := func(args ...string) string {
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config", Value: configPath},
+				&cli.StringFlag{Name: "time", Value: "2025-08-22T10:00:00Z"},
+			},
+			Commands: []*cli.Command{StatusCommand()},
+		}
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		require.NoError(t, app.Run(context.Background(), append([]string{"agentpm", "status", "--file", epicPath}, args...)))
+		return stdout.String()
+	}
+
+	// 3 done in the 6 days since the first start: 0.5 per day, 14 days for the remaining 7
+	assert.Contains(t, run(), "Remaining Effort: 7 of 10, ETA 2025-09-05 at 0.5 per day (1 open tasks unestimated)\n")
+	assert.Contains(t, run("--format", "markdown"), "- **Remaining Effort:** 7 of 10, ETA 2025-09-05")
+	output := run("--format", "json")
+	assert.Contains(t, output, `"eta": "2025-09-05T10:00:00Z"`)
+	assert.Contains(t, output, `"phase_id": "P2",`)
+	assert.Contains(t, run("--format", "xml"), `<effort total="10" remaining="7" unestimated_tasks="1" velocity="0.5" eta="2025-09-05T10:00:00Z">`)
+
+	// Epics without estimates are reported as before
+	require.NoError(t, storage.NewFileStorage().SaveEpic(createTestEpicForStatus(), epicPath))
+	assert.NotContains(t, run(), "Remaining Effort")
+	assert.NotContains(t, run("--format", "json"), `"effort"`)
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// stdin returns the standard input of the app
+func stdin(c *cli.Command) io.Reader {
+	if r := c.Root().Reader; r != nil {
+		return r
+	}
+	return os.Stdin
+}
+
+// readEpicInput reads the epic file at path, or standard input for "-", and
+// returns its content with its format. Standard input has no extension, so
+// its format is told from the content.
+func readEpicInput(c *cli.Command, path string) ([]byte, storage.Format, error) {
+	if path != storage.StdioPath {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		return data, storage.FormatOf(path), nil
+	}
+	data, err := io.ReadAll(stdin(c))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read standard input: %w", err)
+	}
+	return data, storage.DetectFormat(data), nil
+}
+
+// loadEpicInput loads the epic file at path, or decodes the epic on standard
+// input for "-"
+func loadEpicInput(c *cli.Command, path string) (*epic.Epic, error) {
+	if path != storage.StdioPath {
+		return storage.NewFileStorage().LoadEpic(path)
+	}
+	return storage.ReadEpic(stdin(c), "")
+}
+
+// streamFormat is the storage format an epic is written to standard output
+// in: the one --format names, XML otherwise
+func streamFormat(c *cli.Command) storage.Format {
+	if format, err := storage.ParseFormat(c.String("format")); err == nil {
+		return format
+	}
+	return storage.FormatXML
+}
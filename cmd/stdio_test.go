@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestEpicPipes(t *testing.T) {
+	run := func(t *testing.T, stdin string, args ...string) (string, error) {
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config", Value: filepath.Join(t.TempDir(), ".agentpm.json")},
+				&cli.StringFlag{Name: "format", Value: "text"},
+				&cli.StringFlag{Name: "time"},
+			},
+			Commands: []*cli.Command{ImportCommand(), ExportCommand(), ConvertCommand(), ValidateCommand()},
+		}
+		var stdout bytes.Buffer
+		app.Reader = strings.NewReader(stdin)
+		app.Writer = &stdout
+		app.ErrWriter = &bytes.Buffer{}
+		err := app.Run(context.Background(), append([]string{"agentpm"}, args...))
+		return stdout.String(), err
+	}
+
+	tempDir := t.TempDir()
+	fileStorage := storage.NewFileStorage()
+	piped := epic.NewEpic("8", "Checkout")
+	piped.CreatedAt = time.Date(2025, 8, 1, 9, 0, 0, 0, time.UTC)
+	piped.Metadata.Created = piped.CreatedAt
+	piped.Phases = []epic.Phase{{ID: "1", Name: "Cart", Status: epic.StatusPending}}
+	piped.Tasks = []epic.Task{{ID: "1_1", PhaseID: "1", Name: "Add to cart", Status: epic.StatusPending}}
+	piped.Tests = []epic.Test{{ID: "T1", TaskID: "1_1", PhaseID: "1", Name: "Adds an item", Status: epic.StatusPending, TestStatus: epic.TestStatusPending}}
+	jsonFile := filepath.Join(tempDir, "epic.json")
+	require.NoError(t, fileStorage.SaveEpic(piped, jsonFile))
+	expected, err := fileStorage.LoadEpic(jsonFile)
+	require.NoError(t, err)
+	jsonContent, err := os.ReadFile(jsonFile)
+	require.NoError(t, err)
+
+	t.Run("import from stdin to stdout", func(t *testing.T) {
+		output, err := run(t, string(jsonContent), "import", "-f", "-", "-")
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(output, "<?xml"))
+
+		imported, err := storage.DecodeEpic([]byte(output), storage.FormatXML)
+		require.NoError(t, err)
+		assert.Equal(t, expected, imported)
+
+		output, err = run(t, string(jsonContent), "--format", "yaml", "import", "-f", "-", "-")
+		require.NoError(t, err)
+		assert.Equal(t, storage.FormatYAML, storage.DetectFormat([]byte(output)))
+	})
+
+	t.Run("import from stdin into a file", func(t *testing.T) {
+		epicFile := filepath.Join(t.TempDir(), "epic.xml")
+		output, err := run(t, string(jsonContent), "import", "--file", epicFile, "-")
+		require.NoError(t, err)
+		assert.Equal(t, "Imported epic 8 from - into "+epicFile+" (1 phases, 1 tasks, 1 tests, 0 events)\n", output)
+
+		imported, err := fileStorage.LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, expected, imported)
+	})
+
+	t.Run("validate from stdin", func(t *testing.T) {
+		xmlContent, err := storage.EncodeEpic(expected, storage.FormatXML)
+		require.NoError(t, err)
+		for _, content := range []string{string(xmlContent), string(jsonContent)} {
+			_, err := run(t, content, "validate", "-")
+			assert.NoError(t, err)
+		}
+
+		broken := strings.Replace(string(xmlContent), `phase_id="1"`, `phase_id="9"`, 1)
+		output, err := run(t, broken, "validate", "-")
+		require.Error(t, err)
+		assert.Contains(t, output, "references undefined phase 9")
+
+		_, err = run(t, "", "validate", "a.xml", "b.xml")
+		require.Error(t, err)
+	})
+
+	t.Run("export from stdin", func(t *testing.T) {
+		output, err := run(t, string(jsonContent), "export", "--format", "yaml", "--file", "-")
+		require.NoError(t, err)
+		exported, err := storage.DecodeEpic([]byte(output), storage.FormatYAML)
+		require.NoError(t, err)
+		assert.Equal(t, expected, exported)
+	})
+
+	t.Run("convert from stdin", func(t *testing.T) {
+		output, err := run(t, string(jsonContent), "convert", "--to", "xml", "--file", "-")
+		require.NoError(t, err)
+		converted, err := storage.DecodeEpic([]byte(output), storage.FormatXML)
+		require.NoError(t, err)
+		assert.Equal(t, expected, converted)
+
+		yamlFile := filepath.Join(t.TempDir(), "epic.yaml")
+		output, err = run(t, string(jsonContent), "convert", "--to", "yaml", "--file", "-", "-o", yamlFile)
+		require.NoError(t, err)
+		assert.Equal(t, "Converted epic 8 from json to yaml: "+yamlFile+"\n", output)
+		converted, err = fileStorage.LoadEpic(yamlFile)
+		require.NoError(t, err)
+		assert.Equal(t, expected, converted)
+	})
+
+	t.Run("convert a file to stdout", func(t *testing.T) {
+		output, err := run(t, "", "convert", "--to", "yaml", "--file", jsonFile, "-o", "-")
+		require.NoError(t, err)
+		converted, err := storage.DecodeEpic([]byte(output), storage.FormatYAML)
+		require.NoError(t, err)
+		assert.Equal(t, expected, converted)
+		assert.FileExists(t, jsonFile)
+	})
+}
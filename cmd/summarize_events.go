@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// SummarizeEventsCommand rolls bursts of events up into summary events
+func SummarizeEventsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "summarize-events",
+		Usage: "Collapse bursts of task and test events into summary events",
+		Description: `Make the event timeline of a long-running epic readable again: per time
+window and actor, a burst of task and test events (started, passed, failed,
+...) is rolled up into one events_summarized event that counts them by type.
+Epic and phase events, warnings and handoffs always stay as they are.
+
+The summaries are added alongside the original events. With --compact the
+originals are removed, which cannot be undone; burn-down, velocity and cycle
+times then fall back to the timestamps on the tasks and tests. Bursts that
+already have a summary are left alone.
+
+--window takes a Go duration (6h) or whole days (1d); days start at midnight
+UTC.
+
+Examples:
+  agentpm summarize-events --window 1d --dry-run
+  agentpm summarize-events --window 6h --min 10
+  agentpm summarize-events --window 1d --compact`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config",
+			},
+			&cli.StringFlag{
+				Name:  "window",
+				Usage: "Width of the time windows bursts are counted in, e.g. 1d or 6h",
+				Value: "1d",
+			},
+			&cli.IntFlag{
+				Name:  "min",
+				Usage: "Fewest events of one actor in a window that get summarized",
+				Value: 5,
+			},
+			&cli.BoolFlag{
+				Name:  "compact",
+				Usage: "Remove the summarized events, keeping only the summaries",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show what would be summarized without making changes",
+			},
+		},
+		Action: summarizeEventsAction,
+	}
+}
+
+func summarizeEventsAction(ctx context.Context, c *cli.Command) error {
+	window, err := parseSummaryWindow(c.String("window"))
+	if err != nil {
+		return err
+	}
+	if c.Int("min") < 1 {
+		return fmt.Errorf("invalid --min: must be at least 1")
+	}
+
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+
+	fileStorage := storage.NewFileStorage()
+	epicData, err := fileStorage.LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	dryRun := c.Bool("dry-run")
+	report := service.SummarizeEvents(epicData, service.SummarizeOptions{
+		Window:  window,
+		Min:     int(c.Int("min")),
+		Compact: c.Bool("compact"),
+	})
+	if !dryRun && len(report.Summaries) > 0 {
+		if err := fileStorage.SaveEpic(epicData, epicFile); err != nil {
+			return fmt.Errorf("failed to save epic: %w", err)
+		}
+	}
+
+	switch c.String("format") {
+	case "json":
+		output := struct {
+			*service.SummarizeReport
+			DryRun bool `json:"dry_run"`
+		}{report, dryRun}
+		jsonData, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal summary report to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		outputSummarizeXML(c, report, dryRun)
+	default:
+		outputSummarizeText(c, report, dryRun)
+	}
+	return nil
+}
+
+// parseSummaryWindow accepts Go durations (6h, 90m) and whole days (1d)
+func parseSummaryWindow(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+	window, err := time.ParseDuration(value)
+	if err != nil || window <= 0 {
+		return 0, fmt.Errorf("invalid --window %q (use a duration like 6h or 1d)", value)
+	}
+	return window, nil
+}
+
+func outputSummarizeText(c *cli.Command, report *service.SummarizeReport, dryRun bool) {
+	w := c.Root().Writer
+
+	if len(report.Summaries) == 0 {
+		fmt.Fprintf(w, "No bursts of %d or more task and test events per %s window in epic %s.\n", report.Min, report.Window, report.EpicID)
+		return
+	}
+
+	verb := "Summarized"
+	if dryRun {
+		verb = "Would summarize"
+	}
+	fmt.Fprintf(w, "%s %d burst(s) of epic %s (%s windows, at least %d events):\n", verb, len(report.Summaries), report.EpicID, report.Window, report.Min)
+	for _, summary := range report.Summaries {
+		fmt.Fprintf(w, "  %s\n", summary.Data)
+	}
+
+	switch {
+	case report.Compact && dryRun:
+		fmt.Fprintf(w, "\nThe %d summarized events would be removed.\n", report.Removed)
+	case report.Compact:
+		fmt.Fprintf(w, "\nRemoved the %d summarized events.\n", report.Removed)
+	default:
+		fmt.Fprintf(w, "\nThe original events are kept; use --compact to remove them.\n")
+	}
+}
+
+func outputSummarizeXML(c *cli.Command, report *service.SummarizeReport, dryRun bool) {
+	doc := etree.NewDocument()
+	root := doc.CreateElement("summarize_events")
+	root.CreateAttr("epic", report.EpicID)
+	root.CreateAttr("window", report.Window)
+	root.CreateAttr("min", strconv.Itoa(report.Min))
+	root.CreateAttr("compact", strconv.FormatBool(report.Compact))
+	root.CreateAttr("dry_run", strconv.FormatBool(dryRun))
+	root.CreateAttr("removed_events", strconv.Itoa(report.Removed))
+
+	for _, summary := range report.Summaries {
+		elem := root.CreateElement("summary")
+		elem.CreateAttr("id", summary.ID)
+		if summary.Actor != "" {
+			elem.CreateAttr("actor", summary.Actor)
+		}
+		elem.CreateAttr("from", summary.From.Format(time.RFC3339))
+		elem.CreateAttr("to", summary.To.Format(time.RFC3339))
+		elem.CreateAttr("events", strconv.Itoa(summary.Events))
+		elem.SetText(summary.Data)
+	}
+
+	doc.Indent(4)
+	doc.WriteTo(c.Root().Writer)
+}
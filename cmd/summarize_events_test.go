@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var summarizeEventsApp = appOptions{commands: []func() *cli.Command{SummarizeEventsCommand}}
+
+func createBusyEpicFile(t *testing.T) string {
+	t.Helper()
+	epicFile := filepath.Join(t.TempDir(), "epic.xml")
+	testEpic := createEpicForReset()
+	for minute := range 3 {
+		at := time.Date(2025, 8, 16, 9, minute, 0, 0, time.UTC)
+		service.CreateEvent(testEpic, service.EventTestFailed, "P1", "T1", "T1_T1", "flaky", at)
+		service.CreateEvent(testEpic, service.EventTestPassed, "P1", "T1", "T1_T1", "", at.Add(30*time.Second))
+	}
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicFile))
+	return epicFile
+}
+
+func TestSummarizeEventsCommand(t *testing.T) {
+	t.Run("dry run leaves the epic unchanged", func(t *testing.T) {
+		epicFile := createBusyEpicFile(t)
+		output, err := runApp(t, summarizeEventsApp, "summarize-events", "--file", epicFile, "--compact", "--dry-run")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Would summarize 1 burst(s) of epic epic-1 (1d windows, at least 5 events):\n")
+		assert.Contains(t, output, "  Summary of 6 events from 2025-08-16 09:00 to 2025-08-16 09:02: 3 test_failed, 3 test_passed\n")
+		assert.Contains(t, output, "The 6 summarized events would be removed.")
+
+		e, err := storage.NewFileStorage().LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Len(t, e.Events, 6)
+	})
+
+	t.Run("compact replaces the burst", func(t *testing.T) {
+		epicFile := createBusyEpicFile(t)
+		output, err := runApp(t, summarizeEventsApp, "summarize-events", "--file", epicFile, "--compact", "--format", "json")
+		require.NoError(t, err)
+
+		var report service.SummarizeReport
+		require.NoError(t, json.Unmarshal([]byte(output), &report))
+		assert.Equal(t, 6, report.Removed)
+		require.Len(t, report.Summaries, 1)
+
+		e, err := storage.NewFileStorage().LoadEpic(epicFile)
+		require.NoError(t, err)
+		require.Len(t, e.Events, 1)
+		assert.Equal(t, "events_summarized", e.Events[0].Type)
+		assert.Equal(t, report.Summaries[0].Data, e.Events[0].Data)
+	})
+
+	t.Run("bursts below the minimum are kept", func(t *testing.T) {
+		epicFile := createBusyEpicFile(t)
+		output, err := runApp(t, summarizeEventsApp, "summarize-events", "--file", epicFile, "--window", "1h", "--min", "7")
+		require.NoError(t, err)
+		assert.Equal(t, "No bursts of 7 or more task and test events per 1h window in epic epic-1.\n", output)
+	})
+
+	t.Run("rejects invalid windows", func(t *testing.T) {
+		_, err := runApp(t, summarizeEventsApp, "summarize-events", "--file", createBusyEpicFile(t), "--window", "0d")
+		assert.EqualError(t, err, `invalid --window "0d" (use a duration like 6h or 1d)`)
+	})
+}
@@ -37,7 +37,7 @@ Examples:
 				Usage:   "Switch back to the previous epic",
 			},
 		},
-		Action: switchAction,
+		Action: withQuietResult(switchAction),
 	}
 }
 
@@ -83,12 +83,7 @@ func handleSwitchBack(c *cli.Command, cfg *config.Config, configPath, format str
 	}
 
 	// Validate previous epic file still exists
-	var previousPath string
-	if filepath.IsAbs(cfg.PreviousEpic) {
-		previousPath = cfg.PreviousEpic
-	} else {
-		previousPath = filepath.Join(".", cfg.PreviousEpic)
-	}
+	previousPath := cfg.PreviousEpicFilePath()
 
 	if _, err := os.Stat(previousPath); os.IsNotExist(err) {
 		return fmt.Errorf("previous epic file no longer exists: %s", previousPath)
@@ -143,9 +138,17 @@ func handleSwitchToEpic(c *cli.Command, cfg *config.Config, configPath, targetEp
 	// Store current epic as previous (for future switch back)
 	previousEpic := cfg.CurrentEpic
 
-	// Update configuration
+	// Update configuration; relative paths are stored relative to the config file
+	storedTarget, err := config.NormalizeEpicPath(configPath, targetEpic)
+	if err != nil {
+		return fmt.Errorf("failed to resolve epic path: %w", err)
+	}
 	cfg.PreviousEpic = cfg.CurrentEpic
-	cfg.CurrentEpic = targetEpic
+	cfg.CurrentEpic = storedTarget
+	// In a multi-epic workspace the epic left behind stays registered
+	if len(cfg.Epics) > 0 {
+		cfg.AddEpic(previousEpic)
+	}
 
 	// Save updated configuration
 	if err := config.SaveConfig(cfg, configPath); err != nil {
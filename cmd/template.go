@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/templates"
+	"github.com/urfave/cli/v3"
+)
+
+func TemplateCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "template",
+		Usage: "Manage epic templates used by init --template",
+		Description: `List and fetch epic templates.
+
+Templates are epic XML files. Besides the built-in templates, fetched
+templates are stored in the local templates directory (templates_dir,
+default .agentpm/templates next to .agentpm.json).
+
+A registry is a JSON index of templates with their SHA-256 checksums,
+configured with 'agentpm config set template_registry <url>':
+
+  {"templates": [{"name": "service", "url": "service.xml", "sha256": "...", "description": "..."}]}
+
+Subcommands:
+  list                 List built-in and local templates
+  fetch <url|name>     Download a template into the templates directory
+
+Examples:
+  agentpm template list
+  agentpm template list --remote
+  agentpm template fetch service
+  agentpm template fetch https://example.com/api.xml --sha256 <checksum>
+  agentpm init --epic epic-9.xml --template service`,
+		Commands: []*cli.Command{
+			templateListSubcommand(),
+			templateFetchSubcommand(),
+		},
+	}
+}
+
+func templateListSubcommand() *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Usage: "List available templates",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "remote",
+				Usage: "Also list the templates offered by the registry",
+			},
+			templateRegistryFlag(),
+		},
+		Action: templateListAction,
+	}
+}
+
+func templateFetchSubcommand() *cli.Command {
+	return &cli.Command{
+		Name:      "fetch",
+		Usage:     "Download a template into the local templates directory",
+		ArgsUsage: "<url|name>",
+		Description: `Download a template by URL or by its name in the template registry.
+The download is verified against its SHA-256 checksum (taken from the
+registry, or given with --sha256 for URLs) and must be an epic XML file.`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "sha256",
+				Usage: "Expected SHA-256 checksum (required when fetching a URL)",
+			},
+			&cli.StringFlag{
+				Name:  "name",
+				Usage: "Name to install the template as (default: registry name or URL file name)",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Replace an existing local template",
+			},
+			templateRegistryFlag(),
+		},
+		Action: withQuietResult(templateFetchAction),
+	}
+}
+
+func templateRegistryFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "registry",
+		Usage: "Template registry URL or path (overrides template_registry from config)",
+	}
+}
+
+// templateSettings returns the local templates directory and the registry location.
+// Without a config file the templates directory is next to where it would be created.
+func templateSettings(c *cli.Command) (string, string, error) {
+	configPath, err := config.ResolveConfigPath(c.String("config"))
+	if err != nil {
+		return "", "", err
+	}
+
+	dir := filepath.Join(filepath.Dir(configPath), config.DefaultTemplatesDir)
+	registry := ""
+	if config.ConfigExists(configPath) {
+		cfg, err := config.LoadConfig(configPath)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to load configuration: %w", err)
+		}
+		dir = cfg.TemplatesDirPath()
+		if cfg.TemplateRegistry != "" {
+			registry = cfg.TemplateRegistryLocation()
+		}
+	}
+
+	if override := c.String("registry"); override != "" {
+		registry = override
+	}
+	return dir, registry, nil
+}
+
+func templateListAction(ctx context.Context, c *cli.Command) error {
+	dir, registrySource, err := templateSettings(c)
+	if err != nil {
+		return err
+	}
+
+	list, err := templates.List(dir)
+	if err != nil {
+		return err
+	}
+
+	if c.Bool("remote") {
+		if registrySource == "" {
+			return exitcode.Errorf(exitcode.Validation, "no template registry configured (use --registry or 'agentpm config set template_registry <url>')")
+		}
+		registry, err := templates.NewFetcher().FetchRegistry(registrySource)
+		if err != nil {
+			return err
+		}
+		for _, entry := range registry.Templates {
+			list = append(list, templates.Template{Name: entry.Name, Source: templates.SourceRegistry, Description: entry.Description})
+		}
+	}
+
+	return outputTemplateList(c, list)
+}
+
+func outputTemplateList(c *cli.Command, list []templates.Template) error {
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(map[string]interface{}{"templates": list}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal templates to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("templates")
+		for _, template := range list {
+			elem := root.CreateElement("template")
+			elem.CreateAttr("name", template.Name)
+			elem.CreateAttr("source", template.Source)
+			if template.Path != "" {
+				elem.CreateAttr("path", template.Path)
+			}
+			if template.Description != "" {
+				elem.SetText(template.Description)
+			}
+		}
+		doc.Indent(4)
+		doc.WriteTo(c.Root().Writer)
+	default:
+		fmt.Fprintf(c.Root().Writer, "Templates:\n")
+		for _, template := range list {
+			line := fmt.Sprintf("  %-20s %-10s", template.Name, template.Source)
+			if template.Description != "" {
+				line += " " + template.Description
+			}
+			fmt.Fprintf(c.Root().Writer, "%s\n", strings.TrimRight(line, " "))
+		}
+	}
+	return nil
+}
+
+func templateFetchAction(ctx context.Context, c *cli.Command) error {
+	if c.Args().Len() != 1 {
+		return exitcode.Errorf(exitcode.Validation, "fetch requires exactly one argument: a template URL or registry name")
+	}
+	target := c.Args().First()
+
+	dir, registrySource, err := templateSettings(c)
+	if err != nil {
+		return err
+	}
+
+	fetcher := templates.NewFetcher()
+	name := c.String("name")
+	source := target
+	checksum := c.String("sha256")
+
+	if templates.IsURL(target) {
+		if name == "" {
+			name = strings.TrimSuffix(path.Base(target), ".xml")
+		}
+	} else {
+		if registrySource == "" {
+			return exitcode.Errorf(exitcode.Validation, "no template registry configured to look up %s (use --registry or 'agentpm config set template_registry <url>')", target)
+		}
+		registry, err := fetcher.FetchRegistry(registrySource)
+		if err != nil {
+			return err
+		}
+		entry, err := registry.Lookup(target)
+		if err != nil {
+			return err
+		}
+		if source, err = registry.Resolve(entry); err != nil {
+			return err
+		}
+		if checksum == "" {
+			checksum = entry.SHA256
+		}
+		if name == "" {
+			name = entry.Name
+		}
+	}
+
+	data, err := fetcher.FetchTemplate(source, checksum)
+	if err != nil {
+		return err
+	}
+
+	installed, err := templates.Install(dir, name, data, c.Bool("force"))
+	if err != nil {
+		return err
+	}
+
+	sum := templates.Checksum(data)
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(map[string]interface{}{
+			"name":   name,
+			"source": source,
+			"path":   installed,
+			"sha256": sum,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal template to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("template_fetched")
+		root.CreateAttr("name", name)
+		root.CreateElement("source").SetText(source)
+		root.CreateElement("path").SetText(installed)
+		root.CreateElement("sha256").SetText(sum)
+		doc.Indent(4)
+		doc.WriteTo(c.Root().Writer)
+	default:
+		fmt.Fprintf(c.Root().Writer, "Template %s installed: %s\n", name, installed)
+		fmt.Fprintf(c.Root().Writer, "SHA-256: %s\n", sum)
+		fmt.Fprintf(c.Root().Writer, "Use it with: agentpm init --epic <file> --template %s\n", name)
+	}
+	return nil
+}
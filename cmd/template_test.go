@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var templateApp = appOptions{commands: []func() *cli.Command{TemplateCommand, InitCommand}}
+
+func TestTemplateFetchAndInit(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, ".agentpm.json")
+
+	template := `<?xml version="1.0" encoding="UTF-8"?>
+<epic id="service" name="Service Epic" status="pending">
+    <description>Service skeleton</description>
+    <phases/>
+    <tasks/>
+    <tests/>
+    <events/>
+</epic>
+`
+	registryDir := filepath.Join(dir, "registry")
+	require.NoError(t, os.MkdirAll(registryDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(registryDir, "service.xml"), []byte(template), 0644))
+	index := fmt.Sprintf(`{"templates": [{"name": "service", "url": "service.xml", "sha256": "%s", "description": "Service skeleton"}]}`, templates.Checksum([]byte(template)))
+	require.NoError(t, os.WriteFile(filepath.Join(registryDir, "index.json"), []byte(index), 0644))
+	registry := filepath.Join(registryDir, "index.json")
+
+	output, err := runApp(t, templateApp.withConfig(configFile), "template", "list", "--remote", "--registry", registry)
+	require.NoError(t, err)
+	assert.Contains(t, output, "basic")
+	assert.Contains(t, output, "service              registry   Service skeleton")
+
+	output, err = runApp(t, templateApp.withConfig(configFile), "template", "fetch", "service", "--registry", registry)
+	require.NoError(t, err)
+	installed := filepath.Join(dir, ".agentpm", "templates", "service.xml")
+	assert.Contains(t, output, "Template service installed: "+installed)
+	assert.FileExists(t, installed)
+
+	epicFile := filepath.Join(dir, "epic-1.xml")
+	_, err = runApp(t, templateApp.withConfig(configFile), "--time", "2025-08-16T09:00:00Z", "init", "--epic", epicFile, "--template", "service")
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(epicFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `<epic id="service" name="Service Epic" status="pending" created_at="2025-08-16T09:00:00Z" schema_version="2">`)
+
+	_, err = runApp(t, templateApp.withConfig(configFile), "init", "--epic", epicFile, "--template", "service")
+	assert.ErrorContains(t, err, "Epic file already exists")
+}
+
+func TestTemplateFetchRequiresRegistryForNames(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := runApp(t, templateApp.withConfig(filepath.Join(dir, ".agentpm.json")), "template", "fetch", "service")
+	assert.ErrorContains(t, err, "no template registry configured")
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/exitcode"
 	"github.com/mindreframer/agentpm/internal/tests"
 	"github.com/urfave/cli/v3"
 )
@@ -126,7 +127,7 @@ func startTestAction(ctx context.Context, c *cli.Command) error {
 	// Validate arguments
 	args := c.Args()
 	if args.Len() != 1 {
-		return fmt.Errorf("start-test requires exactly one argument: test-id")
+		return exitcode.Errorf(exitcode.Validation, "start-test requires exactly one argument: test-id")
 	}
 	testID := args.Get(0)
 
@@ -164,7 +165,7 @@ func passTestAction(ctx context.Context, c *cli.Command) error {
 	// Validate arguments
 	args := c.Args()
 	if args.Len() != 1 {
-		return fmt.Errorf("pass-test requires exactly one argument: test-id")
+		return exitcode.Errorf(exitcode.Validation, "pass-test requires exactly one argument: test-id")
 	}
 	testID := args.Get(0)
 
@@ -190,7 +191,7 @@ func passTestAction(ctx context.Context, c *cli.Command) error {
 	})
 
 	// Execute operation
-	result, err := service.PassTest(epicFile, testID, timestamp)
+	result, err := service.PassTest(epicFile, testID, tests.Verification{}, timestamp)
 	if err != nil {
 		return writeTestError(c, c.String("format"), err)
 	}
@@ -229,7 +230,7 @@ func failTestAction(ctx context.Context, c *cli.Command) error {
 	})
 
 	// Execute operation
-	result, err := service.FailTest(epicFile, testID, failureReason, timestamp)
+	result, err := service.FailTest(epicFile, testID, failureReason, nil, tests.Verification{}, timestamp)
 	if err != nil {
 		return writeTestError(c, c.String("format"), err)
 	}
@@ -298,10 +299,10 @@ func getEpicFile(c *cli.Command) (string, error) {
 
 	// Use epic file from config
 	if cfg.CurrentEpic == "" {
-		return "", fmt.Errorf("no epic file specified. Use --file flag or run 'agentpm init' first")
+		return "", exitcode.Errorf(exitcode.Validation, "no epic file specified. Use --file flag or run 'agentpm init' first")
 	}
 
-	return cfg.CurrentEpic, nil
+	return cfg.EpicFilePath(), nil
 }
 
 func writeTestResult(c *cli.Command, format string, result *tests.TestOperation) error {
@@ -400,20 +401,33 @@ func writeTestError(c *cli.Command, format string, err error) error {
 			output := fmt.Sprintf(`<error>
     <type>%s</type>
     <test_id>%s</test_id>
-    <message>%s</message>
-</error>`, testErr.Type, testErr.TestID, testErr.Message)
+    <message>%s</message>`, testErr.Type, testErr.TestID, testErr.Message)
+			if testErr.Hint != "" {
+				output += fmt.Sprintf(`
+    <hint>%s</hint>`, testErr.Hint)
+			}
+			output += `
+</error>`
 			fmt.Fprint(c.Root().ErrWriter, output)
 		case "json":
 			output := fmt.Sprintf(`{
   "error": {
     "type": "%s",
     "test_id": "%s",
-    "message": "%s"
+    "message": "%s"`, testErr.Type, testErr.TestID, testErr.Message)
+			if testErr.Hint != "" {
+				output += fmt.Sprintf(`,
+    "hint": "%s"`, testErr.Hint)
+			}
+			output += `
   }
-}`, testErr.Type, testErr.TestID, testErr.Message)
+}`
 			fmt.Fprint(c.Root().ErrWriter, output)
 		default: // text
 			fmt.Fprintf(c.Root().ErrWriter, "✗ Error: %s\n", testErr.Message)
+			if testErr.Hint != "" {
+				fmt.Fprintf(c.Root().ErrWriter, "Hint: %s\n", testErr.Hint)
+			}
 		}
 	} else {
 		// Fallback for non-test errors
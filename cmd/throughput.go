@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/mindreframer/agentpm/internal/throughput"
+	"github.com/urfave/cli/v3"
+)
+
+// ThroughputCommand reports the tests fixed and newly failing per day
+func ThroughputCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "throughput",
+		Usage: "Show tests fixed and newly failing per day",
+		Description: `Replay the test_passed, test_failed, test_reset and test_cancelled events
+of the epic and count per day (UTC) how many failing tests were fixed, how
+many tests newly failed and how the number of passing tests changed.
+
+The trend of the reported days tells whether the work is converging (more
+tests passing), regressing (fewer tests passing), thrashing (tests pass and
+fail without more of them passing) or idle. 'agentpm status' shows the
+trend of the last 7 days.
+
+Examples:
+  agentpm throughput                     # Last 14 days
+  agentpm throughput --days 30 --format json`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config",
+			},
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"F"},
+				Usage:   "Output format: text (default), json, xml",
+				Value:   "text",
+			},
+			&cli.IntFlag{
+				Name:  "days",
+				Usage: "Number of days to report, ending with today",
+				Value: 14,
+			},
+		},
+		Action: throughputAction,
+	}
+}
+
+func throughputAction(ctx context.Context, c *cli.Command) error {
+	if c.Int("days") < 1 {
+		return fmt.Errorf("--days must be at least 1")
+	}
+
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if timeStr := c.String("time"); timeStr != "" {
+		now, err = time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+		}
+	}
+
+	epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	report := throughput.Build(epicData, now, int(c.Int("days")))
+
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal throughput report to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		outputThroughputXML(c, report)
+	default:
+		outputThroughputText(c, report)
+	}
+	return nil
+}
+
+func outputThroughputText(c *cli.Command, report *throughput.Report) {
+	w := c.Root().Writer
+
+	fmt.Fprintf(w, "Test throughput for epic %s, last %d days\n\n", report.Epic, len(report.Days))
+	fmt.Fprintf(w, "%-10s  %6s  %6s  %5s  %13s  %4s\n", "date", "passed", "failed", "fixed", "newly failing", "net")
+	for _, day := range report.Days {
+		fmt.Fprintf(w, "%-10s  %6d  %6d  %5d  %13d  %+4d\n", day.Date, day.Passed, day.Failed, day.Fixed, day.NewlyFailing, day.Net)
+	}
+	fmt.Fprintf(w, "\nTotal: %d fixed, %d newly failing, net %+d tests passing, trend: %s\n",
+		report.Fixed, report.NewlyFailing, report.Net, report.Trend)
+}
+
+func outputThroughputXML(c *cli.Command, report *throughput.Report) {
+	doc := etree.NewDocument()
+	root := doc.CreateElement("throughput")
+	root.CreateAttr("epic", report.Epic)
+	root.CreateAttr("fixed", strconv.Itoa(report.Fixed))
+	root.CreateAttr("newly_failing", strconv.Itoa(report.NewlyFailing))
+	root.CreateAttr("net", strconv.Itoa(report.Net))
+	root.CreateAttr("trend", report.Trend)
+
+	for _, day := range report.Days {
+		dayElem := root.CreateElement("day")
+		dayElem.CreateAttr("date", day.Date)
+		dayElem.CreateAttr("passed", strconv.Itoa(day.Passed))
+		dayElem.CreateAttr("failed", strconv.Itoa(day.Failed))
+		dayElem.CreateAttr("fixed", strconv.Itoa(day.Fixed))
+		dayElem.CreateAttr("newly_failing", strconv.Itoa(day.NewlyFailing))
+		dayElem.CreateAttr("net", strconv.Itoa(day.Net))
+	}
+
+	doc.Indent(4)
+	doc.WriteTo(c.Root().Writer)
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/mindreframer/agentpm/internal/throughput"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var throughputApp = appOptions{commands: []func() *cli.Command{ThroughputCommand, StatusCommand}, time: "2025-08-14T12:00:00Z"}
+
+func TestThroughputCommand(t *testing.T) {
+	epicFile := filepath.Join(t.TempDir(), "epic.xml")
+	testEpic := createEpicForReset()
+	service.CreateEvent(testEpic, service.EventTestFailed, "P1", "T1", "T1_T1", "broken", time.Date(2025, 8, 12, 10, 0, 0, 0, time.UTC))
+	service.CreateEvent(testEpic, service.EventTestPassed, "P1", "T1", "T1_T1", "", time.Date(2025, 8, 13, 10, 0, 0, 0, time.UTC))
+	service.CreateEvent(testEpic, service.EventTestPassed, "P1", "T2", "T2_T1", "", time.Date(2025, 8, 14, 10, 0, 0, 0, time.UTC))
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicFile))
+
+	t.Run("text output", func(t *testing.T) {
+		output, err := runApp(t, throughputApp, "throughput", "--file", epicFile, "--days", "3")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Test throughput for epic epic-1, last 3 days")
+		assert.Contains(t, output, "2025-08-12       0       1      0              1    +0\n")
+		assert.Contains(t, output, "2025-08-13       1       0      1              0    +1\n")
+		assert.Contains(t, output, "Total: 1 fixed, 1 newly failing, net +2 tests passing, trend: converging")
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		output, err := runApp(t, throughputApp, "throughput", "--file", epicFile, "--format", "json")
+		require.NoError(t, err)
+
+		var report throughput.Report
+		require.NoError(t, json.Unmarshal([]byte(output), &report))
+		assert.Len(t, report.Days, 14)
+		assert.Equal(t, "2025-08-14", report.Days[13].Date)
+		assert.Equal(t, 2, report.Net)
+	})
+
+	t.Run("xml output", func(t *testing.T) {
+		output, err := runApp(t, throughputApp, "throughput", "--file", epicFile, "--format", "xml", "--days", "1")
+		require.NoError(t, err)
+		assert.Contains(t, output, `<throughput epic="epic-1" fixed="0" newly_failing="0" net="1" trend="converging">`)
+		assert.Contains(t, output, `<day date="2025-08-14" passed="1" failed="0" fixed="0" newly_failing="0" net="1"/>`)
+	})
+
+	t.Run("rejects fewer than one day", func(t *testing.T) {
+		_, err := runApp(t, throughputApp, "throughput", "--file", epicFile, "--days", "0")
+		assert.Error(t, err)
+	})
+
+	t.Run("status shows the trend of the last week", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), ".agentpm.json")
+		require.NoError(t, config.SaveConfig(&config.Config{CurrentEpic: epicFile}, configPath))
+
+		output, err := runApp(t, throughputApp, "--config", configPath, "status")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Test Trend: net +2 tests passing this week (1 fixed, 1 newly failing, converging)")
+
+		output, err = runApp(t, throughputApp, "--config", configPath, "--time", "2025-09-30T12:00:00Z", "status")
+		require.NoError(t, err)
+		assert.NotContains(t, output, "Test Trend")
+	})
+}
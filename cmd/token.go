@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/auth"
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/urfave/cli/v3"
+)
+
+// TokenCommand manages the API tokens of serve mode
+func TokenCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "token",
+		Usage: "Manage API tokens and their scopes for serve mode",
+		Description: `Create, list and revoke the API tokens that serve mode accepts as
+"Authorization: Bearer <token>".
+
+Every token has one scope; each scope includes the ones before it:
+
+  read     GET/HEAD requests - dashboards, status, statistics
+  mutate   also POST/PUT/PATCH - start, done, pass, fail (worker agents)
+  admin    also DELETE - remove entities, manage the epic
+
+Requests without a valid token are rejected with 401, requests beyond the
+token's scope with 403. Only the SHA-256 hash of a token is stored
+(tokens_file, default .agentpm/tokens.json); the token itself is shown once,
+on creation.
+
+Subcommands:
+  create <name>     Create a token (--scope read|mutate|admin)
+  list              List tokens and their scopes
+  revoke <name>     Revoke a token
+
+Examples:
+  agentpm token create dashboard --scope read
+  agentpm token create worker-1 --scope mutate
+  agentpm token revoke worker-1`,
+		Commands: []*cli.Command{
+			{
+				Name:      "create",
+				Usage:     "Create a token and print it once",
+				ArgsUsage: "<name>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "scope",
+						Usage: "Token scope: read, mutate or admin",
+						Value: string(auth.ScopeRead),
+					},
+				},
+				Action: tokenCreateAction,
+			},
+			{
+				Name:   "list",
+				Usage:  "List tokens and their scopes",
+				Action: tokenListAction,
+			},
+			{
+				Name:      "revoke",
+				Usage:     "Revoke a token",
+				ArgsUsage: "<name>",
+				Action:    tokenRevokeAction,
+			},
+		},
+	}
+}
+
+// loadTokenStore loads the tokens file of the project; without a config it lives
+// in the default location next to where the config would be
+func loadTokenStore(c *cli.Command) (*auth.Store, error) {
+	configPath, err := config.ResolveConfigPath(c.String("config"))
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(filepath.Dir(configPath), config.DefaultTokensFile)
+	if config.ConfigExists(configPath) {
+		cfg, err := config.LoadConfig(configPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load configuration: %w", err)
+		}
+		path = cfg.TokensFilePath()
+	}
+	return auth.Load(path)
+}
+
+func tokenCreateAction(ctx context.Context, c *cli.Command) error {
+	if c.Args().Len() != 1 {
+		return fmt.Errorf("exactly one token name is required")
+	}
+	store, err := loadTokenStore(c)
+	if err != nil {
+		return err
+	}
+
+	name := c.Args().First()
+	scope := auth.Scope(c.String("scope"))
+	secret, err := store.Create(name, scope, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := store.Save(); err != nil {
+		return err
+	}
+
+	w := c.Root().Writer
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(map[string]string{"name": name, "scope": string(scope), "token": secret}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal token to JSON: %w", err)
+		}
+		fmt.Fprintf(w, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("token")
+		root.CreateAttr("name", name)
+		root.CreateAttr("scope", string(scope))
+		root.SetText(secret)
+		doc.Indent(4)
+		doc.WriteTo(w)
+	default:
+		fmt.Fprintf(w, "Created token %s (scope %s):\n\n  %s\n\n", name, scope, secret)
+		fmt.Fprintf(w, "Store it now - it cannot be shown again.\n")
+	}
+	return nil
+}
+
+func tokenListAction(ctx context.Context, c *cli.Command) error {
+	store, err := loadTokenStore(c)
+	if err != nil {
+		return err
+	}
+
+	w := c.Root().Writer
+	switch c.String("format") {
+	case "json":
+		type tokenOutput struct {
+			Name      string `json:"name"`
+			Scope     string `json:"scope"`
+			CreatedAt string `json:"created_at"`
+		}
+		outputs := make([]tokenOutput, len(store.Tokens))
+		for i, token := range store.Tokens {
+			outputs[i] = tokenOutput{token.Name, string(token.Scope), token.CreatedAt.Format(time.RFC3339)}
+		}
+		jsonData, err := json.MarshalIndent(map[string]interface{}{"tokens": outputs}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal tokens to JSON: %w", err)
+		}
+		fmt.Fprintf(w, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("tokens")
+		root.CreateAttr("count", strconv.Itoa(len(store.Tokens)))
+		for _, token := range store.Tokens {
+			elem := root.CreateElement("token")
+			elem.CreateAttr("name", token.Name)
+			elem.CreateAttr("scope", string(token.Scope))
+			elem.CreateAttr("created_at", token.CreatedAt.Format(time.RFC3339))
+		}
+		doc.Indent(4)
+		doc.WriteTo(w)
+	default:
+		if len(store.Tokens) == 0 {
+			fmt.Fprintf(w, "No API tokens. Create one with 'agentpm token create <name> --scope read'.\n")
+			return nil
+		}
+		fmt.Fprintf(w, "API tokens (%d):\n", len(store.Tokens))
+		for _, token := range store.Tokens {
+			fmt.Fprintf(w, "  %-20s %-7s created %s\n", token.Name, token.Scope, token.CreatedAt.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+func tokenRevokeAction(ctx context.Context, c *cli.Command) error {
+	if c.Args().Len() != 1 {
+		return fmt.Errorf("exactly one token name is required")
+	}
+	store, err := loadTokenStore(c)
+	if err != nil {
+		return err
+	}
+	name := c.Args().First()
+	if err := store.Revoke(name); err != nil {
+		return err
+	}
+	if err := store.Save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Root().Writer, "Revoked token %s\n", name)
+	return nil
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestTokenCommand(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, ".agentpm.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"current_epic": "epic.xml", "tokens_file": "secrets/tokens.json"}`), 0644))
+
+	run := func(format string, args ...string) (string, error) {
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config", Value: configPath},
+				&cli.StringFlag{Name: "format", Value: format},
+			},
+			Commands: []*cli.Command{TokenCommand()},
+		}
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		err := app.Run(context.Background(), append([]string{"agentpm"}, args...))
+		return stdout.String(), err
+	}
+
+	output, err := run("json", "token", "create", "worker", "--scope", "mutate")
+	require.NoError(t, err)
+	var created map[string]string
+	require.NoError(t, json.Unmarshal([]byte(output), &created))
+	assert.Equal(t, "mutate", created["scope"])
+
+	store, err := auth.Load(filepath.Join(tempDir, "secrets", "tokens.json"))
+	require.NoError(t, err)
+	token, ok := store.Authenticate(created["token"])
+	require.True(t, ok)
+	assert.Equal(t, auth.ScopeMutate, token.Scope)
+
+	_, err = run("text", "token", "create", "dashboard", "--scope", "superuser")
+	assert.ErrorContains(t, err, "invalid scope")
+
+	output, err = run("text", "token", "list")
+	require.NoError(t, err)
+	assert.Contains(t, output, "API tokens (1):")
+	assert.Contains(t, output, "worker")
+	assert.NotContains(t, output, created["token"])
+
+	output, err = run("text", "token", "revoke", "worker")
+	require.NoError(t, err)
+	assert.Equal(t, "Revoked token worker\n", output)
+	output, err = run("text", "token", "list")
+	require.NoError(t, err)
+	assert.Contains(t, output, "No API tokens.")
+}
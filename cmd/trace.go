@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/spec"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// TraceCommand reports which spec sections are covered by the epic's phases and tasks
+func TraceCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "trace",
+		Usage: "Show which spec sections have corresponding tasks",
+		Description: `Map the sections of spec documents to the phases and tasks that
+reference them with spec_ref="file#anchor" (paths relative to the epic file,
+anchors as GitHub renders heading links).
+
+A section is covered when a task references it, directly or through its
+phase, or when one of its subsections is covered. Every referenced spec
+document is traced; use --spec to include documents nothing refers to yet.
+
+Examples:
+  agentpm trace                            # Trace all referenced spec documents
+  agentpm trace --spec docs/spec.md        # Include a document explicitly
+  agentpm trace --uncovered --format json  # Only sections without tasks`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config",
+			},
+			&cli.StringSliceFlag{
+				Name:  "spec",
+				Usage: "Spec document to trace even if no phase or task refers to it (repeatable)",
+			},
+			&cli.BoolFlag{
+				Name:  "uncovered",
+				Usage: "Only show sections without corresponding tasks",
+			},
+		},
+		Action: traceAction,
+	}
+}
+
+func traceAction(ctx context.Context, c *cli.Command) error {
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+
+	epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	// --spec paths are given relative to the working directory
+	var extraDocs []string
+	for _, doc := range c.StringSlice("spec") {
+		absPath, err := filepath.Abs(doc)
+		if err != nil {
+			return fmt.Errorf("failed to resolve spec path: %w", err)
+		}
+		extraDocs = append(extraDocs, absPath)
+	}
+
+	baseDir, err := filepath.Abs(filepath.Dir(epicFile))
+	if err != nil {
+		return fmt.Errorf("failed to resolve epic path: %w", err)
+	}
+
+	report, err := spec.Trace(epicData, baseDir, extraDocs)
+	if err != nil {
+		return err
+	}
+
+	if c.Bool("uncovered") {
+		for i := range report.Documents {
+			doc := &report.Documents[i]
+			var uncovered []spec.SectionTrace
+			for _, section := range doc.Sections {
+				if !section.Covered {
+					uncovered = append(uncovered, section)
+				}
+			}
+			doc.Sections = uncovered
+		}
+	}
+
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal trace report to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		outputTraceXML(c, report)
+	default:
+		outputTraceText(c, report)
+	}
+	return nil
+}
+
+func outputTraceText(c *cli.Command, report *spec.TraceReport) {
+	w := c.Root().Writer
+
+	if len(report.Documents) == 0 {
+		fmt.Fprintf(w, "No spec documents referenced (set spec_ref on phases/tasks or use --spec)\n")
+	}
+
+	for i, doc := range report.Documents {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s (%d of %d sections uncovered)\n", doc.Path, doc.Uncovered, len(doc.Sections))
+		for _, section := range doc.Sections {
+			marker := "✓"
+			if !section.Covered {
+				marker = "✗"
+			}
+			line := fmt.Sprintf("  %s %s#%s", marker, strings.Repeat("  ", section.Level-1), section.Anchor)
+			var refs []string
+			if len(section.Phases) > 0 {
+				refs = append(refs, "phases: "+strings.Join(section.Phases, ", "))
+			}
+			if len(section.Tasks) > 0 {
+				refs = append(refs, "tasks: "+strings.Join(section.Tasks, ", "))
+			}
+			if len(refs) > 0 {
+				line += " (" + strings.Join(refs, "; ") + ")"
+			}
+			fmt.Fprintf(w, "%s\n", line)
+		}
+	}
+
+	if len(report.Problems) > 0 {
+		fmt.Fprintf(w, "\nBroken spec references:\n")
+		for _, problem := range report.Problems {
+			fmt.Fprintf(w, "  %s\n", problem)
+		}
+	}
+}
+
+func outputTraceXML(c *cli.Command, report *spec.TraceReport) {
+	doc := etree.NewDocument()
+	root := doc.CreateElement("trace")
+
+	for _, document := range report.Documents {
+		docElem := root.CreateElement("spec")
+		docElem.CreateAttr("path", document.Path)
+		docElem.CreateAttr("uncovered", fmt.Sprintf("%d", document.Uncovered))
+		for _, section := range document.Sections {
+			sectionElem := docElem.CreateElement("section")
+			sectionElem.CreateAttr("anchor", section.Anchor)
+			sectionElem.CreateAttr("level", fmt.Sprintf("%d", section.Level))
+			sectionElem.CreateAttr("covered", fmt.Sprintf("%t", section.Covered))
+			sectionElem.CreateElement("title").SetText(section.Title)
+			for _, phaseID := range section.Phases {
+				sectionElem.CreateElement("phase").CreateAttr("id", phaseID)
+			}
+			for _, taskID := range section.Tasks {
+				sectionElem.CreateElement("task").CreateAttr("id", taskID)
+			}
+		}
+	}
+
+	if len(report.Problems) > 0 {
+		problemsElem := root.CreateElement("problems")
+		for _, problem := range report.Problems {
+			problemElem := problemsElem.CreateElement("problem")
+			problemElem.CreateAttr("entity_type", problem.EntityType)
+			problemElem.CreateAttr("entity_id", problem.EntityID)
+			problemElem.CreateAttr("spec_ref", problem.Ref.Ref)
+			problemElem.SetText(problem.Problem)
+		}
+	}
+
+	doc.Indent(4)
+	doc.WriteTo(c.Root().Writer)
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var traceApp = appOptions{commands: []func() *cli.Command{TraceCommand, ValidateCommand}}
+
+func writeTraceEpic(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	spec := "# Search\n## Indexing\n## Ranking\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "spec.md"), []byte(spec), 0644))
+
+	epicXML := `<?xml version="1.0" encoding="UTF-8"?>
+<epic id="9" name="Search" status="pending" created_at="2025-08-16T09:00:00Z">
+    <description>Search</description>
+    <phases>
+        <phase id="1" name="Indexing" status="pending" spec_ref="spec.md#indexing"/>
+    </phases>
+    <tasks>
+        <task id="1_1" phase_id="1" status="pending" spec_ref="spec.md#indexing">
+            <name>Build index</name>
+        </task>
+        <task id="1_2" phase_id="1" status="pending" spec_ref="spec.md#rankng">
+            <name>Rank results</name>
+        </task>
+    </tasks>
+    <tests/>
+    <events/>
+</epic>
+`
+	epicFile := filepath.Join(dir, "epic.xml")
+	require.NoError(t, os.WriteFile(epicFile, []byte(epicXML), 0644))
+	return epicFile
+}
+
+func TestTraceCommand(t *testing.T) {
+	epicFile := writeTraceEpic(t)
+
+	t.Run("text output", func(t *testing.T) {
+		output, err := runApp(t, traceApp, "trace", "--file", epicFile)
+		require.NoError(t, err)
+
+		assert.Contains(t, output, "spec.md (1 of 3 sections uncovered)")
+		assert.Contains(t, output, "✓   #indexing (phases: 1; tasks: 1_1)")
+		assert.Contains(t, output, "✗   #ranking")
+		assert.Contains(t, output, "Broken spec references:")
+		assert.Contains(t, output, `task 1_2: spec_ref "spec.md#rankng": section #rankng not found in spec.md`)
+	})
+
+	t.Run("uncovered json output", func(t *testing.T) {
+		output, err := runApp(t, traceApp, "--format", "json", "trace", "--file", epicFile, "--uncovered")
+		require.NoError(t, err)
+
+		var report struct {
+			Documents []struct {
+				Path     string `json:"path"`
+				Sections []struct {
+					Anchor string `json:"anchor"`
+				} `json:"sections"`
+			} `json:"documents"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(output), &report))
+		require.Len(t, report.Documents, 1)
+		require.Len(t, report.Documents[0].Sections, 1)
+		assert.Equal(t, "ranking", report.Documents[0].Sections[0].Anchor)
+	})
+
+	t.Run("xml output", func(t *testing.T) {
+		output, err := runApp(t, traceApp, "--format", "xml", "trace", "--file", epicFile)
+		require.NoError(t, err)
+
+		assert.Contains(t, output, `<spec path="spec.md" uncovered="1">`)
+		assert.Contains(t, output, `<section anchor="indexing" level="2" covered="true">`)
+		assert.Contains(t, output, `<problem entity_type="task" entity_id="1_2" spec_ref="spec.md#rankng">`)
+	})
+}
+
+func TestValidateCheckSpecRefs(t *testing.T) {
+	epicFile := writeTraceEpic(t)
+
+	output, err := runApp(t, traceApp, "validate", "--file", epicFile, "--check-spec-refs")
+	require.Error(t, err)
+	assert.Contains(t, output, "section #rankng not found in spec.md")
+
+	_, err = runApp(t, traceApp, "validate", "--file", epicFile)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/journal"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// journaledCommands are the status-changing commands that `agentpm undo` reverts
+var journaledCommands = map[string]bool{
+	"start": true, "done": true, "cancel": true, "pass": true, "fail": true,
+	"next": true, "start-next": true, "next-test": true, "start-next-test": true,
+}
+
+// JournalMutations is the root Before hook that records the epic file as it was
+// before a start, done, cancel, pass or fail command changed it, for `agentpm
+// undo`. All saves of one invocation form a single journal entry.
+func JournalMutations(ctx context.Context, c *cli.Command) (context.Context, error) {
+	storage.SetSaveHook(nil)
+
+	words := commandWords(c)
+	if len(words) == 0 || !journaledCommands[words[0]] {
+		return ctx, nil
+	}
+	operation := strings.Join(words, " ")
+
+	errWriter := c.Root().ErrWriter
+	if errWriter == nil {
+		errWriter = os.Stderr
+	}
+	recorded := make(map[string]bool)
+	storage.SetSaveHook(func(path string, before, after []byte) {
+		var err error
+		if recorded[path] {
+			err = journal.Amend(path, journal.Hash(after))
+		} else if string(before) != string(after) {
+			recorded[path] = true
+			err = journal.Record(path, journal.Entry{
+				Operation: operation,
+				Timestamp: time.Now().UTC(),
+				Actor:     service.Actor(),
+				After:     journal.Hash(after),
+				Before:    string(before),
+			})
+		}
+		// The change itself succeeded; it just cannot be undone
+		if err != nil {
+			fmt.Fprintf(errWriter, "Warning: '%s' was not journaled and cannot be undone: %v\n", operation, err)
+		}
+	})
+	return ctx, nil
+}
+
+// commandWords returns the command line up to the first flag, e.g. [done task 1_2]
+func commandWords(c *cli.Command) []string {
+	var words []string
+	for _, arg := range c.Args().Slice() {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		words = append(words, arg)
+	}
+	return words
+}
+
+// UndoCommand reverts the last journaled status changes
+func UndoCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "undo",
+		Usage: "Revert the last status changes",
+		Description: `Restore the epic file to its state before the last start, done, cancel,
+pass, fail or next command. Every such command is journaled in a sidecar file
+next to the epic (.epic-8.xml.journal) together with the file it replaced;
+undo writes that file back atomically and drops the entry. The last 50
+operations are kept.
+
+Undo refuses when the epic file changed after the last journaled operation
+(a hand edit, or a command that is not journaled), since restoring would
+discard that change; --force restores anyway.
+
+Examples:
+  agentpm undo              # Revert the last operation
+  agentpm undo --steps 3    # Revert the last three operations
+  agentpm undo --list       # Show what can be undone`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config",
+			},
+			&cli.IntFlag{
+				Name:    "steps",
+				Aliases: []string{"n"},
+				Usage:   "Number of operations to revert",
+				Value:   1,
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Undo even if the epic file changed after the last operation",
+			},
+			&cli.BoolFlag{
+				Name:  "list",
+				Usage: "List the journaled operations instead of undoing",
+			},
+		},
+		Action: undoAction,
+	}
+}
+
+func undoAction(ctx context.Context, c *cli.Command) error {
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+
+	if c.Bool("list") {
+		entries, err := journal.Entries(epicFile)
+		if err != nil {
+			return err
+		}
+		// Most recent first, the order undo takes them
+		reversed := make([]journal.Entry, len(entries))
+		for i, entry := range entries {
+			reversed[len(entries)-1-i] = entry
+		}
+		return outputJournal(c, "journal", reversed)
+	}
+
+	if err := checkUndo(epicFile, int(c.Int("steps"))); err != nil {
+		return err
+	}
+	undone, err := journal.Undo(epicFile, int(c.Int("steps")), c.Bool("force"))
+	if err != nil {
+		var modified *journal.ModifiedError
+		if errors.As(err, &modified) {
+			return fmt.Errorf("%w (use --force to undo anyway)", err)
+		}
+		return err
+	}
+	return outputJournal(c, "undone", undone)
+}
+
+// checkUndo applies epic.CheckMutation to the file undo would restore, since
+// journal.Undo writes it back directly rather than through FileStorage. Files
+// that cannot be read are left to journal.Undo to report.
+func checkUndo(epicFile string, steps int) error {
+	entries, err := journal.Entries(epicFile)
+	if err != nil || steps < 1 || steps > len(entries) {
+		return nil
+	}
+	target := entries[len(entries)-steps]
+	data, err := os.ReadFile(epicFile)
+	if err != nil || target.Before == "" {
+		return nil
+	}
+	current, err := storage.DecodeEpic(data, storage.FormatOf(epicFile))
+	if err != nil {
+		return nil
+	}
+	restored, err := storage.DecodeEpic([]byte(target.Before), storage.FormatOf(epicFile))
+	if err != nil {
+		return nil
+	}
+	return epic.CheckMutation(current, restored, "undo")
+}
+
+func outputJournal(c *cli.Command, kind string, entries []journal.Entry) error {
+	type entryOutput struct {
+		Operation string `json:"operation"`
+		Timestamp string `json:"timestamp"`
+		Actor     string `json:"actor,omitempty"`
+	}
+	outputs := make([]entryOutput, len(entries))
+	for i, entry := range entries {
+		outputs[i] = entryOutput{entry.Operation, entry.Timestamp.Format(time.RFC3339), entry.Actor}
+	}
+
+	w := c.Root().Writer
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(map[string]interface{}{kind: outputs}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s to JSON: %w", kind, err)
+		}
+		fmt.Fprintf(w, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement(kind)
+		root.CreateAttr("count", strconv.Itoa(len(outputs)))
+		for _, output := range outputs {
+			elem := root.CreateElement("operation")
+			elem.CreateAttr("timestamp", output.Timestamp)
+			if output.Actor != "" {
+				elem.CreateAttr("actor", output.Actor)
+			}
+			elem.SetText(output.Operation)
+		}
+		doc.Indent(4)
+		doc.WriteTo(w)
+	default:
+		if kind == "journal" && len(outputs) == 0 {
+			fmt.Fprintf(w, "Nothing to undo.\n")
+			return nil
+		}
+		if kind == "journal" {
+			fmt.Fprintf(w, "Operations that can be undone (most recent first):\n")
+		} else {
+			fmt.Fprintf(w, "Undid %d operation(s):\n", len(outputs))
+		}
+		for _, output := range outputs {
+			actor := ""
+			if output.Actor != "" {
+				actor = " by " + output.Actor
+			}
+			fmt.Fprintf(w, "  %s  agentpm %s%s\n", output.Timestamp, output.Operation, actor)
+		}
+	}
+	return nil
+}
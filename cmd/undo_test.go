@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/journal"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+const undoEpic = `<epic id="8" name="Undo Epic" status="wip" created_at="2025-08-01T09:00:00Z">
+    <phases>
+        <phase id="P1" name="Build" status="wip" started_at="2025-08-01T10:00:00Z"/>
+    </phases>
+    <tasks>
+        <task id="T1" phase_id="P1" name="Task 1" status="pending"/>
+    </tasks>
+    <tests/>
+    <events/>
+</epic>`
+
+func TestUndoCommand(t *testing.T) {
+	t.Cleanup(func() { storage.SetSaveHook(nil) })
+
+	epicFile := filepath.Join(t.TempDir(), "epic.xml")
+	require.NoError(t, os.WriteFile(epicFile, []byte(undoEpic), 0644))
+
+	run := func(args ...string) (string, error) {
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config", Value: filepath.Join(t.TempDir(), ".agentpm.json")},
+				&cli.StringFlag{Name: "format", Value: "text"},
+			},
+			Before:   JournalMutations,
+			Commands: []*cli.Command{StartCommand(), DoneCommand(), UndoCommand()},
+		}
+		var stdout, stderr bytes.Buffer
+		app.Writer = &stdout
+		app.ErrWriter = &stderr
+		err := app.Run(context.Background(), append([]string{"agentpm"}, args...))
+		return stdout.String(), err
+	}
+	taskStatus := func() string {
+		epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+		require.NoError(t, err)
+		return string(epicData.Tasks[0].Status)
+	}
+
+	_, err := run("start", "task", "T1", "--file", epicFile)
+	require.NoError(t, err)
+	_, err = run("done", "task", "T1", "--file", epicFile)
+	require.NoError(t, err)
+	assert.Equal(t, "completed", taskStatus())
+
+	t.Run("lists journaled operations", func(t *testing.T) {
+		output, err := run("undo", "--list", "--file", epicFile)
+		require.NoError(t, err)
+		assert.Contains(t, output, "Operations that can be undone (most recent first):")
+		assert.Regexp(t, `agentpm done task T1(.|\n)*agentpm start task T1`, output)
+	})
+
+	t.Run("reverts the last operation", func(t *testing.T) {
+		output, err := run("undo", "--file", epicFile)
+		require.NoError(t, err)
+		assert.Contains(t, output, "Undid 1 operation(s):")
+		assert.Contains(t, output, "agentpm done task T1")
+		assert.Equal(t, "wip", taskStatus())
+	})
+
+	t.Run("refuses after a hand edit unless forced", func(t *testing.T) {
+		content, err := os.ReadFile(epicFile)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(epicFile, append(content, '\n'), 0644))
+
+		_, err = run("undo", "--file", epicFile)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "use --force to undo anyway")
+		assert.Equal(t, "wip", taskStatus())
+
+		_, err = run("undo", "--force", "--file", epicFile)
+		require.NoError(t, err)
+		content, err = os.ReadFile(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, undoEpic, string(content))
+		assert.NoFileExists(t, journal.Path(epicFile))
+	})
+
+	t.Run("nothing left to undo", func(t *testing.T) {
+		_, err := run("undo", "--file", epicFile)
+		assert.ErrorIs(t, err, journal.ErrEmpty)
+	})
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/query"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// upcomingOutput is the JSON output of upcoming
+type upcomingOutput struct {
+	AsOf      string             `json:"as_of"`
+	Window    string             `json:"window" doc:"How far ahead deadlines are listed, e.g. 7d"`
+	Deadlines []upcomingDeadline `json:"deadlines" doc:"Overdue deadlines and those within the window, earliest first"`
+}
+
+type upcomingDeadline struct {
+	File string `json:"file,omitempty" doc:"Epic file, with --all"`
+	Epic string `json:"epic"`
+	statusDeadline
+}
+
+// UpcomingCommand returns the upcoming command for listing deadlines coming up
+func UpcomingCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "upcoming",
+		Usage: "List deadlines that are overdue or coming up soon",
+		Description: `List the open epic, phase and task deadlines (the due attribute) that
+have passed or come up within a window, earliest first. Completed and
+cancelled work is left out.
+
+The window defaults to upcoming_window of the config (7d unless set) and is
+overridden with --within: days (3d), weeks (2w) or a duration (36h). status
+shows the same deadlines as reminders; deadlines are never enforced.
+
+Examples:
+  agentpm upcoming
+  agentpm upcoming --within 2w
+  agentpm upcoming --all --format json`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config",
+			},
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"F"},
+				Usage:   "Output format: text (default), json, xml",
+				Value:   "text",
+			},
+			&cli.StringFlag{
+				Name:  "within",
+				Usage: "How far ahead to look, e.g. 3d, 2w or 36h (default: upcoming_window of the config, 7d)",
+			},
+			&cli.BoolFlag{
+				Name:  "all",
+				Usage: "Deadlines of every epic of the workspace (see 'agentpm epics')",
+			},
+		},
+		Action: upcomingAction,
+	}
+}
+
+func upcomingAction(ctx context.Context, c *cli.Command) error {
+	cfg, err := config.LoadConfig(c.String("config"))
+	if err != nil && (c.Bool("all") || c.String("file") == "") {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	window := config.DefaultUpcomingWindow
+	if cfg != nil && cfg.UpcomingWindow != "" {
+		window = cfg.UpcomingWindow
+	}
+	if within := c.String("within"); within != "" {
+		window = within
+	}
+	duration, err := epic.ParseInterval(window)
+	if err != nil {
+		return fmt.Errorf("invalid --within %q (use 3d, 2w or a duration like 36h)", window)
+	}
+
+	now := time.Now()
+	if timeStr := c.String("time"); timeStr != "" {
+		now, err = time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+		}
+	}
+
+	var epicFiles []string
+	switch {
+	case c.Bool("all"):
+		epicFiles = cfg.EpicFilePaths()
+	case c.String("file") != "":
+		epicFiles = []string{c.String("file")}
+	default:
+		epicFiles = []string{cfg.EpicFilePath()}
+	}
+
+	result := upcomingOutput{AsOf: now.UTC().Format(time.RFC3339), Window: window, Deadlines: []upcomingDeadline{}}
+	for _, epicFile := range epicFiles {
+		queryService := query.NewQueryService(storage.NewFileStorage())
+		if err := queryService.LoadEpic(epicFile); err != nil {
+			return fmt.Errorf("failed to load epic %s: %w", epicFile, err)
+		}
+		deadlines, err := queryService.GetDeadlines(now, duration)
+		if err != nil {
+			return err
+		}
+		epicData, err := queryService.GetEpic()
+		if err != nil {
+			return err
+		}
+		for _, deadline := range deadlines {
+			entry := upcomingDeadline{Epic: epicData.ID, statusDeadline: newStatusDeadline(deadline)}
+			if c.Bool("all") {
+				entry.File = workspacePath(cfg, epicFile)
+			}
+			result.Deadlines = append(result.Deadlines, entry)
+		}
+	}
+
+	w := c.Root().Writer
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal upcoming deadlines to JSON: %w", err)
+		}
+		fmt.Fprintf(w, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("upcoming")
+		root.CreateAttr("as_of", result.AsOf)
+		root.CreateAttr("window", result.Window)
+		for _, deadline := range result.Deadlines {
+			elem := root.CreateElement("deadline")
+			if deadline.File != "" {
+				elem.CreateAttr("file", deadline.File)
+			}
+			elem.CreateAttr("epic", deadline.Epic)
+			elem.CreateAttr("type", deadline.Type)
+			elem.CreateAttr("id", deadline.ID)
+			elem.CreateAttr("status", deadline.Status)
+			elem.CreateAttr("due", deadline.Due)
+			elem.CreateAttr("overdue", fmt.Sprint(deadline.Overdue))
+			elem.SetText(deadline.Name)
+		}
+		doc.Indent(4)
+		doc.WriteTo(w)
+	default:
+		if len(result.Deadlines) == 0 {
+			fmt.Fprintf(w, "No open deadlines overdue or due within %s.\n", window)
+			return nil
+		}
+		fmt.Fprintf(w, "Deadlines overdue or due within %s:\n", window)
+		for _, deadline := range result.Deadlines {
+			prefix := ""
+			if deadline.File != "" {
+				prefix = deadline.File + ": "
+			}
+			fmt.Fprintf(w, "  %s%s\n", prefix, formatDeadline(deadline.statusDeadline))
+		}
+	}
+	return nil
+}
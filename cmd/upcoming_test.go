@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func createTestEpicWithDeadlines() *epic.Epic {
+	testEpic := createTestEpicForStatus()
+	testEpic.Due = "2025-09-30"
+	testEpic.Phases[1].Due = "2025-09-12"
+	testEpic.Tasks[0].Due = "2025-09-01" // Completed, never reported
+	testEpic.Tasks[1].Due = "2025-09-10"
+	testEpic.Tasks[2].Due = "2025-09-15"
+	return testEpic
+}
+
+func TestUpcomingCommand(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, ".agentpm.json")
+	epicPath := filepath.Join(tempDir, "epic-1.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(createTestEpicWithDeadlines(), epicPath))
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"current_epic": "epic-1.xml", "upcoming_window": "5d"}`), 0644))
+
+	run := func(args ...string) (string, error) {
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config", Value: configPath},
+				&cli.StringFlag{Name: "time", Value: "2025-09-11T12:00:00Z"},
+			},
+			Commands: []*cli.Command{UpcomingCommand()},
+		}
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		err := app.Run(context.Background(), append([]string{"agentpm", "upcoming"}, args...))
+		return stdout.String(), err
+	}
+
+	t.Run("text output marks overdue deadlines", func(t *testing.T) {
+		output, err := run()
+		require.NoError(t, err)
+		assert.Equal(t, "Deadlines overdue or due within 5d:\n"+
+			"  [OVERDUE] task T2 (Active Task) was due 2025-09-10\n"+
+			"  phase P2 (Implementation Phase) due 2025-09-12\n"+
+			"  task T3 (Pending Task) due 2025-09-15\n", output)
+	})
+
+	t.Run("within overrides the configured window", func(t *testing.T) {
+		output, err := run("--within", "3w")
+		require.NoError(t, err)
+		assert.Contains(t, output, "epic status-test-epic (Status Test Epic) due 2025-09-30\n")
+
+		output, err = run("--within", "12h")
+		require.NoError(t, err)
+		assert.NotContains(t, output, "T3")
+
+		_, err = run("--within", "soon")
+		assert.ErrorContains(t, err, `invalid --within "soon"`)
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		output, err := run("--format", "json")
+		require.NoError(t, err)
+		var result upcomingOutput
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.Equal(t, "2025-09-11T12:00:00Z", result.AsOf)
+		assert.Equal(t, "5d", result.Window)
+		require.Len(t, result.Deadlines, 3)
+		assert.Equal(t, "status-test-epic", result.Deadlines[0].Epic)
+		assert.Equal(t, "T2", result.Deadlines[0].ID)
+		assert.True(t, result.Deadlines[0].Overdue)
+		assert.False(t, result.Deadlines[1].Overdue)
+	})
+
+	t.Run("all lists the deadlines of the workspace", func(t *testing.T) {
+		otherEpic := createTestEpicForStatus()
+		otherEpic.ID = "other-epic"
+		otherEpic.Tasks[3].Due = "2025-09-13"
+		require.NoError(t, storage.NewFileStorage().SaveEpic(otherEpic, filepath.Join(tempDir, "epic-2.xml")))
+		require.NoError(t, os.WriteFile(configPath,
+			[]byte(`{"current_epic": "epic-1.xml", "epics": ["epic-2.xml"], "upcoming_window": "5d"}`), 0644))
+
+		output, err := run("--all")
+		require.NoError(t, err)
+		assert.Contains(t, output, "  epic-1.xml: [OVERDUE] task T2 (Active Task) was due 2025-09-10\n")
+		assert.Contains(t, output, "  epic-2.xml: task T4 (Future Task) due 2025-09-13\n")
+	})
+}
+
+func TestStatusCommandDeadlines(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, ".agentpm.json")
+	epicPath := filepath.Join(tempDir, "test-epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(createTestEpicWithDeadlines(), epicPath))
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"current_epic": "test-epic.xml"}`), 0644))
+
+	run := func(args ...string) string {
+		app := &cli.Command{
+			Name: "agentpm",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "config", Value: configPath},
+				&cli.StringFlag{Name: "time", Value: "2025-09-11T12:00:00Z"},
+			},
+			Commands: []*cli.Command{StatusCommand()},
+		}
+		var stdout bytes.Buffer
+		app.Writer = &stdout
+		require.NoError(t, app.Run(context.Background(), append([]string{"agentpm", "status", "--file", epicPath}, args...)))
+		return stdout.String()
+	}
+
+	output := run()
+	assert.Contains(t, output, "\nDeadlines:\n  [OVERDUE] task T2 (Active Task) was due 2025-09-10\n  phase P2 (Implementation Phase) due 2025-09-12\n")
+	assert.NotContains(t, output, "epic status-test-epic", "outside the default window of 7d")
+	assert.Contains(t, run("--format", "markdown"), "### Deadlines\n\n- [OVERDUE] task T2 (Active Task) was due 2025-09-10\n")
+	assert.Contains(t, run("--format", "json"), `"overdue": true`)
+	assert.Contains(t, run("--format", "xml"), `<deadline type="task" id="T2" due="2025-09-10" overdue="true">Active Task</deadline>`)
+}
@@ -3,23 +3,70 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/mindreframer/agentpm/internal/config"
 	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/migrate"
+	"github.com/mindreframer/agentpm/internal/policy"
+	"github.com/mindreframer/agentpm/internal/spec"
 	"github.com/mindreframer/agentpm/internal/storage"
 	"github.com/urfave/cli/v3"
 )
 
 func ValidateCommand() *cli.Command {
 	return &cli.Command{
-		Name:  "validate",
-		Usage: "Validate epic XML structure",
+		Name:      "validate",
+		Usage:     "Validate epic XML structure",
+		ArgsUsage: "[epic-file|-]",
+		Description: `Check the epic file (the current epic, --file or the argument; "-" reads
+the epic from standard input, its format told from the content) in two
+layers:
+
+- structure: required attributes, unknown elements and attributes, duplicate
+  IDs and references to undefined phases, tasks and tests, each reported
+  with its line number (unknown elements and attributes are warnings, as
+  agentpm ignores them and drops them on the next save)
+- rules: statuses, dependencies, dates and test coverage, and the rules of
+  the organization policy, checked once the structure has no errors
+
+With --ci warnings fail the validation too, unless their category stays
+within its budget: warning_budgets in the config, or --warning-budget, sets
+the warnings accepted per category (structure, missing_tests, policy).
+Categories without a budget accept none. Lowering the budgets step by step
+ratchets the quality of legacy epics up:
+
+  "warning_budgets": {"missing_tests": 5, "structure": 0}
+
+Examples:
+  agentpm validate
+  agentpm validate --file epic-9.xml --check-spec-refs
+  agentpm export --format json | agentpm validate -
+  agentpm validate --ci
+  agentpm validate --ci --warning-budget missing_tests=3`,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:    "file",
 				Aliases: []string{"f"},
 				Usage:   "Epic file to validate (overrides config)",
 			},
+			&cli.BoolFlag{
+				Name:  "check-spec-refs",
+				Usage: "Check that spec_ref links of phases and tasks point to existing spec sections",
+			},
+			&cli.BoolFlag{
+				Name:  "ci",
+				Usage: "Fail when a category has more warnings than its budget allows",
+			},
+			&cli.StringSliceFlag{
+				Name:  "warning-budget",
+				Usage: "Warnings --ci accepts in a category: <category>=<max> (repeatable, overrides warning_budgets)",
+			},
 		},
 		Action: runValidate,
 	}
@@ -30,7 +77,22 @@ func runValidate(ctx context.Context, c *cli.Command) error {
 	format := c.String("format")
 	epicFile := c.String("file")
 
+	var budgets map[string]int
+	if c.Bool("ci") {
+		var err error
+		budgets, err = warningBudgets(configPath, c.StringSlice("warning-budget"))
+		if err != nil {
+			return err
+		}
+	}
+
 	// Determine which epic file to validate
+	if c.Args().Len() > 1 {
+		return exitcode.Errorf(exitcode.Validation, "at most one epic file to validate is allowed")
+	}
+	if c.Args().Present() {
+		epicFile = c.Args().First()
+	}
 	if epicFile == "" {
 		// Load from config
 		cfg, err := config.LoadConfig(configPath)
@@ -39,25 +101,136 @@ func runValidate(ctx context.Context, c *cli.Command) error {
 		}
 		epicFile = cfg.EpicFilePath()
 	}
+	fromStdin := epicFile == storage.StdioPath
 
 	// Create storage and validate
-	storage := storage.NewFileStorage()
+	fileStorage := storage.NewFileStorage()
 
 	// Check if file exists first
-	if !storage.EpicExists(epicFile) {
+	if !fromStdin && !fileStorage.EpicExists(epicFile) {
 		return writeError(c, format, fmt.Sprintf("Epic file not found: %s", epicFile))
 	}
 
-	// Validate the epic
-	result, err := epic.ValidateFromFile(storage, epicFile)
+	// Check the XML structure first: it points at lines, also in files that
+	// cannot be loaded at all
+	content, epicFormat, err := readEpicInput(c, epicFile)
+	if err != nil {
+		return writeError(c, format, fmt.Sprintf("Failed to read epic file: %v", err))
+	}
+	var violations []epic.StructureViolation
+	if epicFormat == storage.FormatXML {
+		violations = epic.ValidateStructure(content)
+	}
+	for _, violation := range violations {
+		if violation.Severity == "error" {
+			// The rules checked on a broken structure only repeat its errors
+			result := &epic.ValidationResult{Valid: true}
+			result.AddStructureViolations(violations)
+			return writeValidationResult(c, format, result, epicFile)
+		}
+	}
+
+	// Standard input is decoded as it is; files are loaded like every
+	// command loads them
+	var epicData *epic.Epic
+	if fromStdin {
+		epicData, err = storage.DecodeEpic(content, epicFormat)
+	} else {
+		epicData, err = fileStorage.LoadEpic(epicFile)
+	}
 	if err != nil {
-		return writeError(c, format, fmt.Sprintf("Failed to validate epic: %v", err))
+		return writeError(c, format, fmt.Sprintf("Failed to load epic: %v", err))
+	}
+
+	// Validate the epic
+	result := epicData.Validate()
+	result.AddStructureViolations(violations)
+
+	// The invariants mutating commands check before changing the epic
+	inconsistencies := migrate.CheckConsistency(epicData)
+	for _, inconsistency := range inconsistencies {
+		result.AddError(fmt.Sprintf("Inconsistent state: %s", inconsistency.Message))
+	}
+	if len(inconsistencies) == 0 {
+		result.SetCheck("consistency", "passed")
+	} else {
+		result.SetCheck("consistency", "failed")
+	}
+
+	if c.Bool("check-spec-refs") {
+		problems := spec.CheckRefs(epicData, filepath.Dir(epicFile))
+		for _, problem := range problems {
+			result.AddError(problem.String())
+		}
+		if len(problems) == 0 {
+			result.SetCheck("spec_refs", "passed")
+		} else {
+			result.SetCheck("spec_refs", "failed")
+		}
+	}
+
+	if activePolicy := policy.Active(); activePolicy != nil {
+		now := time.Now()
+		if timeStr := c.String("time"); timeStr != "" {
+			now, err = time.Parse(time.RFC3339, timeStr)
+			if err != nil {
+				return writeError(c, format, fmt.Sprintf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr))
+			}
+		}
+		errorCount, warningCount := len(result.Errors), len(result.Warnings)
+		for _, violation := range activePolicy.Evaluate(epicData, now) {
+			if violation.Severity == policy.SeverityError {
+				result.AddError(violation.String())
+			} else {
+				result.AddCategorizedWarning(epic.WarningPolicy, violation.String())
+			}
+		}
+		switch {
+		case len(result.Errors) > errorCount:
+			result.SetCheck("policy", "failed")
+		case len(result.Warnings) > warningCount:
+			result.SetCheck("policy", "warning")
+		default:
+			result.SetCheck("policy", "passed")
+		}
+	}
+
+	if c.Bool("ci") {
+		result.ApplyWarningBudgets(budgets)
 	}
 
 	// Format and write validation result
 	return writeValidationResult(c, format, result, epicFile)
 }
 
+// warningBudgets returns the warning budgets of the config, without a config
+// file none, overridden by the <category>=<max> values of --warning-budget
+func warningBudgets(configPath string, overrides []string) (map[string]int, error) {
+	budgets := make(map[string]int)
+	cfg, err := config.LoadConfig(configPath)
+	if err == nil {
+		for category, budget := range cfg.WarningBudgets {
+			budgets[category] = budget
+		}
+	} else if code, ok := exitcode.Of(err); !ok || code != exitcode.NotFound {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	for _, override := range overrides {
+		category, value, ok := strings.Cut(override, "=")
+		budget, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || err != nil || budget < 0 {
+			return nil, exitcode.Errorf(exitcode.Validation, "invalid --warning-budget %q: expected <category>=<max>", override)
+		}
+		category = strings.TrimSpace(category)
+		if !slices.Contains(epic.WarningCategories, category) {
+			return nil, exitcode.Errorf(exitcode.Validation, "unknown warning category %q (expected %s)", category, strings.Join(epic.WarningCategories, ", "))
+		}
+		budgets[category] = budget
+	}
+	return budgets, nil
+}
+
 func writeValidationResult(c *cli.Command, format string, result *epic.ValidationResult, epicFile string) error {
 	switch format {
 	case "xml":
@@ -89,7 +262,8 @@ func writeValidationResult(c *cli.Command, format string, result *epic.Validatio
 		if len(result.Checks) > 0 {
 			output += `
     <checks_performed>`
-			for name, status := range result.Checks {
+			for _, name := range result.CheckNames() {
+				status := result.Checks[name]
 				output += fmt.Sprintf(`
         <check name="%s">%s</check>`, name, status)
 			}
@@ -137,7 +311,8 @@ func writeValidationResult(c *cli.Command, format string, result *epic.Validatio
 			output += `,
   "checks_performed": {`
 			i := 0
-			for name, status := range result.Checks {
+			for _, name := range result.CheckNames() {
+				status := result.Checks[name]
 				if i > 0 {
 					output += `, `
 				}
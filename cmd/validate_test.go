@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var policyApp = appOptions{commands: []func() *cli.Command{ValidateCommand, StartCommand, DoneCommand}, time: "2025-08-20T12:00:00Z", hooks: []cli.BeforeFunc{ApplyStrictMode, ApplyPolicy}}
+
+func TestValidateCommand_Policy(t *testing.T) {
+	setup := func(t *testing.T, policyYAML string, t1Status epic.Status, suppressions ...epic.Suppression) (string, string) {
+		dir := t.TempDir()
+		configPath := filepath.Join(dir, ".agentpm.json")
+		require.NoError(t, os.WriteFile(configPath, []byte(`{"current_epic": "epic.xml"}`), 0644))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, ".agentpm"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".agentpm", "policy.yaml"), []byte(policyYAML), 0644))
+
+		started := time.Date(2025, 8, 16, 9, 0, 0, 0, time.UTC)
+		testEpic := createEpicForReset()
+		testEpic.Tasks[0].Status = t1Status
+		testEpic.Tasks[0].StartedAt = &started
+		testEpic.Suppressions = suppressions
+		epicFile := filepath.Join(dir, "epic.xml")
+		require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicFile))
+		return configPath, epicFile
+	}
+
+	const rules = `
+rules:
+  - id: max-wip
+    check: task_max_wip
+    max: 3d
+  - id: criteria-before-start
+    check: task_acceptance_criteria
+    severity: warning
+  - id: tasks-need-two-tests
+    check: task_min_tests
+    min: 2
+`
+
+	t.Run("validate reports violations by severity", func(t *testing.T) {
+		configPath, _ := setup(t, rules, epic.StatusWIP)
+
+		output, err := runApp(t, policyApp.withConfig(configPath), "validate")
+		require.Error(t, err)
+		assert.Contains(t, output, "policy max-wip: task T1 has been wip for 4d, longer than 3d")
+		assert.Contains(t, output, "policy criteria-before-start: task T2 has no acceptance criteria")
+		assert.Contains(t, output, "Epic validation failed")
+	})
+
+	t.Run("error rules refuse the mutation", func(t *testing.T) {
+		configPath, epicFile := setup(t, rules, epic.StatusWIP)
+
+		_, err := runApp(t, policyApp.withConfig(configPath), "done", "task", "T1", "--file", epicFile)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot complete task T1: policy tasks-need-two-tests: task T1 has 1 test(s), at least 2 required")
+
+		epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, epic.StatusWIP, epicData.Tasks[0].Status)
+	})
+
+	t.Run("suppressed rules and warnings do not block", func(t *testing.T) {
+		configPath, epicFile := setup(t, rules, epic.StatusWIP, epic.Suppression{Rule: "tasks-need-two-tests", Entity: "T1", Reason: "Covered by T2_T1"})
+
+		_, err := runApp(t, policyApp.withConfig(configPath), "done", "task", "T1", "--file", epicFile)
+		require.NoError(t, err)
+		_, err = runApp(t, policyApp.withConfig(configPath), "start", "task", "T2", "--file", epicFile)
+		require.NoError(t, err)
+	})
+
+	t.Run("strict mode upgrades warnings", func(t *testing.T) {
+		configPath, epicFile := setup(t, rules, epic.StatusCompleted)
+
+		_, err := runApp(t, policyApp.withConfig(configPath), "--strict", "start", "task", "T2", "--file", epicFile)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "policy criteria-before-start: task T2 has no acceptance criteria")
+	})
+
+	t.Run("broken policy file fails loudly", func(t *testing.T) {
+		configPath, _ := setup(t, "rules: [{id: a, check: nonsense}]", epic.StatusWIP)
+
+		_, err := runApp(t, policyApp.withConfig(configPath), "validate")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown check "nonsense"`)
+	})
+}
+
+func TestDoneCommand_ConfigPolicies(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, ".agentpm.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"current_epic": "epic.xml", "policies": [
+		{"id": "criteria-before-done", "check": "task_acceptance_criteria", "on": "done"},
+		{"id": "tests-before-done", "check": "task_min_tests"}
+	]}`), 0644))
+	testEpic := createEpicForReset()
+	epicFile := filepath.Join(dir, "epic.xml")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicFile))
+
+	_, err := runApp(t, policyApp.withConfig(configPath), "done", "task", "T1", "--file", epicFile)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot complete task T1: policy criteria-before-done: task T1 has no acceptance criteria (required before it is completed)")
+	assert.Contains(t, err.Error(), "Add <acceptance_criteria> to task T1 in the epic")
+	assert.Equal(t, exitcode.Constraint, ExitCode(err))
+
+	testEpic.Tasks[0].AcceptanceCriteria = "- [x] Works"
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicFile))
+	_, err = runApp(t, policyApp.withConfig(configPath), "done", "task", "T1", "--file", epicFile)
+	require.NoError(t, err)
+}
+
+func TestValidateCommand_Structure(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, ".agentpm.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"current_epic": "epic.xml"}`), 0644))
+	epicFile := filepath.Join(dir, "epic.xml")
+
+	t.Run("structure errors are reported with lines, without rule checks", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(epicFile, []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<epic id="8" name="Checkout" status="pending" created_at="2025-08-16T09:00:00Z">
+    <tasks>
+        <task id="1_1" phase_id="9" name="Orphan" status="pending"/>
+    </tasks>
+</epic>`), 0644))
+
+		output, err := runApp(t, policyApp.withConfig(configPath), "validate", "--format", "json")
+		require.Error(t, err)
+		assert.JSONEq(t, `{
+			"epic": "epic",
+			"valid": false,
+			"message": "Epic validation failed with 1 error(s)",
+			"errors": ["line 4: task 1_1 references undefined phase 9 (phase_id)"],
+			"checks_performed": {"xml_schema": "failed"}
+		}`, output)
+	})
+
+	t.Run("malformed XML points at the line", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(epicFile, []byte("<epic id=\"8\">\n  <phases>\n</epic>\n"), 0644))
+
+		output, err := runApp(t, policyApp.withConfig(configPath), "validate")
+		require.Error(t, err)
+		assert.Contains(t, output, "line 3: malformed XML: element <phases> closed by </epic>")
+	})
+
+	t.Run("unknown elements are warnings", func(t *testing.T) {
+		testEpic := epic.NewEpic("8", "Checkout")
+		testEpic.Phases = []epic.Phase{{ID: "1", Name: "Cart", Status: epic.StatusPending}}
+		require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicFile))
+		content, err := os.ReadFile(epicFile)
+		require.NoError(t, err)
+		content = bytes.Replace(content, []byte("<phases>"), []byte("<outline/>\n    <phases>"), 1)
+		require.NoError(t, os.WriteFile(epicFile, content, 0644))
+
+		output, err := runApp(t, policyApp.withConfig(configPath), "validate")
+		require.NoError(t, err)
+		assert.Contains(t, output, "unknown element outline in epic (ignored, and dropped on the next save)")
+		assert.Contains(t, output, "xml_schema: warning")
+	})
+}
+
+func TestValidateCommand_WarningBudgets(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, ".agentpm.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"current_epic": "epic.xml", "warning_budgets": {"missing_tests": 2}}`), 0644))
+
+	// Three tasks without tests
+	testEpic := epic.NewEpic("8", "Checkout")
+	testEpic.Phases = []epic.Phase{{ID: "1", Name: "Cart", Status: epic.StatusPending}}
+	for _, id := range []string{"1_1", "1_2", "1_3"} {
+		testEpic.Tasks = append(testEpic.Tasks, epic.Task{ID: id, PhaseID: "1", Name: "Task " + id, Status: epic.StatusPending})
+	}
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, filepath.Join(dir, "epic.xml")))
+
+	t.Run("warnings pass without --ci", func(t *testing.T) {
+		_, err := runApp(t, policyApp.withConfig(configPath), "validate")
+		require.NoError(t, err)
+	})
+
+	t.Run("fails when a budget is exceeded", func(t *testing.T) {
+		output, err := runApp(t, policyApp.withConfig(configPath), "validate", "--ci")
+		require.Error(t, err)
+		assert.Contains(t, output, "Warning budget exceeded: 3 missing_tests warning(s), budget 2")
+		assert.Contains(t, output, "warning_budget: failed")
+	})
+
+	t.Run("--warning-budget overrides the config", func(t *testing.T) {
+		output, err := runApp(t, policyApp.withConfig(configPath), "validate", "--ci", "--warning-budget", "missing_tests=3", "--format", "json")
+		require.NoError(t, err)
+		assert.Contains(t, output, `"warning_budget": "passed"`)
+	})
+
+	t.Run("rejects unknown categories", func(t *testing.T) {
+		_, err := runApp(t, policyApp.withConfig(configPath), "validate", "--ci", "--warning-budget", "descriptions=3")
+		require.Error(t, err)
+		assert.Equal(t, exitcode.Validation, ExitCode(err))
+		assert.Contains(t, err.Error(), `unknown warning category "descriptions"`)
+	})
+}
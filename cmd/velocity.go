@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/lint"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/mindreframer/agentpm/internal/velocity"
+	"github.com/urfave/cli/v3"
+)
+
+// VelocityCommand reports completed tasks per week across the workspace's epics
+func VelocityCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "velocity",
+		Usage: "Show tasks completed per week across all epics",
+		Description: `Count the tasks completed per week (Monday to Sunday, UTC) across every
+epic of the workspace - completed ones as well as those still in progress -
+with a rolling average and the trend of the last window against the one
+before it.
+
+The workspace is the directory of the config file (or the working
+directory without one); every XML file below it with an <epic> root is
+included, hidden directories are skipped.
+
+Examples:
+  agentpm velocity                       # Last 8 weeks, 3-week rolling average
+  agentpm velocity --weeks 12 --window 4 --format json`,
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "weeks",
+				Usage: "Number of weeks to report, ending with the current one",
+				Value: 8,
+			},
+			&cli.IntFlag{
+				Name:  "window",
+				Usage: "Number of weeks in the rolling average",
+				Value: 3,
+			},
+		},
+		Action: velocityAction,
+	}
+}
+
+func velocityAction(ctx context.Context, c *cli.Command) error {
+	if c.Int("weeks") < 1 || c.Int("window") < 1 {
+		return fmt.Errorf("--weeks and --window must be at least 1")
+	}
+
+	now := time.Now()
+	if timeStr := c.String("time"); timeStr != "" {
+		var err error
+		now, err = time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", timeStr)
+		}
+	}
+
+	workspace, err := workspaceRoot(c.String("config"))
+	if err != nil {
+		return err
+	}
+
+	files, err := lint.FindEpicFiles(workspace)
+	if err != nil {
+		return err
+	}
+
+	fileStorage := storage.NewFileStorage()
+	var epics []*epic.Epic
+	for _, file := range files {
+		epicData, err := fileStorage.LoadEpic(file)
+		if err != nil {
+			return fmt.Errorf("failed to load epic %s: %w", workspaceRelative(workspace, file), err)
+		}
+		epics = append(epics, epicData)
+	}
+
+	report := velocity.Build(epics, now, int(c.Int("weeks")), int(c.Int("window")))
+
+	switch c.String("format") {
+	case "json":
+		jsonData, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal velocity report to JSON: %w", err)
+		}
+		fmt.Fprintf(c.Root().Writer, "%s\n", jsonData)
+	case "xml":
+		outputVelocityXML(c, report)
+	default:
+		outputVelocityText(c, report)
+	}
+	return nil
+}
+
+func outputVelocityText(c *cli.Command, report *velocity.Report) {
+	w := c.Root().Writer
+
+	fmt.Fprintf(w, "Velocity across %d epics (tasks completed per week, %d-week rolling average)\n\n", report.Epics, report.Window)
+	fmt.Fprintf(w, "%-10s  %5s  %7s  %s\n", "week of", "tasks", "rolling", "epics")
+	for _, week := range report.Weeks {
+		fmt.Fprintf(w, "%-10s  %5d  %7.1f  %s\n", week.Start, week.Tasks, week.RollingAverage, formatEpicCounts(week.ByEpic))
+	}
+	fmt.Fprintf(w, "\nAverage: %.1f tasks/week, trend: %s\n", report.Average, report.Trend)
+}
+
+// formatEpicCounts lists per-epic counts as "epic-1: 3, epic-2: 1", sorted by epic ID
+func formatEpicCounts(counts map[string]int) string {
+	ids := make([]string, 0, len(counts))
+	for id := range counts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, fmt.Sprintf("%s: %d", id, counts[id]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func outputVelocityXML(c *cli.Command, report *velocity.Report) {
+	doc := etree.NewDocument()
+	root := doc.CreateElement("velocity")
+	root.CreateAttr("epics", strconv.Itoa(report.Epics))
+	root.CreateAttr("window", strconv.Itoa(report.Window))
+	root.CreateAttr("average", fmt.Sprintf("%.2f", report.Average))
+	root.CreateAttr("trend", report.Trend)
+
+	for _, week := range report.Weeks {
+		weekElem := root.CreateElement("week")
+		weekElem.CreateAttr("start", week.Start)
+		weekElem.CreateAttr("tasks", strconv.Itoa(week.Tasks))
+		weekElem.CreateAttr("rolling_average", fmt.Sprintf("%.2f", week.RollingAverage))
+	}
+
+	doc.Indent(4)
+	doc.WriteTo(c.Root().Writer)
+}
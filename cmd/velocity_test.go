@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/mindreframer/agentpm/internal/velocity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var velocityApp = appOptions{commands: []func() *cli.Command{VelocityCommand}, time: "2025-08-21T12:00:00Z"}
+
+func TestVelocityCommand(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, ".agentpm.json")
+	require.NoError(t, os.WriteFile(configFile, []byte(`{"current_epic": "epics/epic-2.xml"}`), 0644))
+
+	completedAt := func(day int) *time.Time {
+		t := time.Date(2025, 8, day, 12, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	archived := createEpicForReset()
+	archived.Status = epic.StatusCompleted
+	for i := range archived.Tasks {
+		archived.Tasks[i].Status = epic.StatusCompleted
+		archived.Tasks[i].CompletedAt = completedAt(12)
+	}
+	active := createEpicForReset()
+	active.ID = "epic-2"
+	active.Tasks[0].Status = epic.StatusCompleted
+	active.Tasks[0].CompletedAt = completedAt(19)
+
+	fileStorage := storage.NewFileStorage()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "epics", "done"), 0755))
+	require.NoError(t, fileStorage.SaveEpic(archived, filepath.Join(dir, "epics", "done", "epic-1.xml")))
+	require.NoError(t, fileStorage.SaveEpic(active, filepath.Join(dir, "epics", "epic-2.xml")))
+
+	t.Run("text output", func(t *testing.T) {
+		output, err := runApp(t, velocityApp.withConfig(configFile).withFormat("text"), "velocity", "--weeks", "2", "--window", "1")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Velocity across 2 epics (tasks completed per week, 1-week rolling average)")
+		assert.Contains(t, output, "2025-08-11      2      2.0  epic-1: 2\n")
+		assert.Contains(t, output, "2025-08-18      1      1.0  epic-2: 1\n")
+		assert.Contains(t, output, "Average: 1.5 tasks/week, trend: falling")
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		output, err := runApp(t, velocityApp.withConfig(configFile).withFormat("json"), "velocity", "--weeks", "4")
+		require.NoError(t, err)
+
+		var report velocity.Report
+		require.NoError(t, json.Unmarshal([]byte(output), &report))
+		assert.Equal(t, 3, report.Window)
+		require.Len(t, report.Weeks, 4)
+		assert.Equal(t, "2025-07-28", report.Weeks[0].Start)
+		assert.InDelta(t, 1.0, report.Weeks[3].RollingAverage, 0.001)
+	})
+
+	t.Run("rejects an empty window", func(t *testing.T) {
+		_, err := runApp(t, velocityApp.withConfig(configFile).withFormat("text"), "velocity", "--window", "0")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must be at least 1")
+	})
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/commands"
+	"github.com/mindreframer/agentpm/internal/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+var warningsApp = appOptions{commands: []func() *cli.Command{StartCommand, DoneCommand}}
+
+const warningsTestEpic = `<?xml version="1.0" encoding="UTF-8"?>
+<epic id="warn-epic" name="Warn Epic" status="wip" created_at="2025-08-16T09:00:00Z">
+    <phases>
+        <phase id="P1" name="Phase 1" status="wip"/>
+    </phases>
+    <tasks>
+        <task id="T1" phase_id="P1" name="Task 1" status="wip" started_at="2025-08-16T09:30:00Z"/>
+        <task id="T2" phase_id="P1" name="Task 2" status="cancelled"/>
+    </tasks>
+    <tests/>
+    <events/>
+</epic>
+`
+
+func TestMutationWarnings(t *testing.T) {
+	t.Run("text warnings follow the success message", func(t *testing.T) {
+		stdout, err := runApp(t, warningsApp, "done", "task", "T1", "--file", writeWarningsEpic(t, warningsTestEpic), "--time", "2025-08-16T10:00:00Z")
+		require.NoError(t, err)
+		assert.Equal(t, "Task T1 completed.\nWarning: All tasks and tests of phase P1 are done\nHint: agentpm done phase P1\n", stdout)
+	})
+
+	t.Run("json warnings array", func(t *testing.T) {
+		stdout, err := runApp(t, warningsApp, "done", "task", "T1", "--format", "json", "--file", writeWarningsEpic(t, warningsTestEpic), "--time", "2025-08-16T10:00:00Z")
+		require.NoError(t, err)
+		require.Contains(t, stdout, "Task T1 completed.\n")
+
+		var result struct {
+			Warnings []commands.Warning `json:"warnings"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(stdout[len("Task T1 completed.\n"):]), &result))
+		assert.Equal(t, []commands.Warning{{
+			Source:  commands.WarningSourcePlanner,
+			Code:    "phase_complete",
+			Message: "All tasks and tests of phase P1 are done",
+			Hint:    "agentpm done phase P1",
+		}}, result.Warnings)
+	})
+
+	t.Run("xml warnings element", func(t *testing.T) {
+		stdout, err := runApp(t, warningsApp, "done", "task", "T1", "--format", "xml", "--file", writeWarningsEpic(t, warningsTestEpic), "--time", "2025-08-16T10:00:00Z")
+		require.NoError(t, err)
+		assert.Contains(t, stdout, "<warnings>\n    <warning source=\"planner\" code=\"phase_complete\" hint=\"agentpm done phase P1\">All tasks and tests of phase P1 are done</warning>\n</warnings>\n")
+	})
+
+	t.Run("quiet result lines carry the warnings", func(t *testing.T) {
+		stdout, err := runApp(t, warningsApp, "done", "task", "T1", "-q", "--format", "json", "--file", writeWarningsEpic(t, warningsTestEpic), "--time", "2025-08-16T10:00:00Z")
+		require.NoError(t, err)
+		assert.Equal(t, `{"status":"ok","command":"done task T1","warnings":[{"source":"planner","code":"phase_complete","message":"All tasks and tests of phase P1 are done","hint":"agentpm done phase P1"}]}`+"\n", stdout)
+
+		stdout, err = runApp(t, warningsApp, "done", "task", "T1", "-q", "--file", writeWarningsEpic(t, warningsTestEpic), "--time", "2025-08-16T10:00:00Z")
+		require.NoError(t, err)
+		assert.Equal(t, "ok done task T1\n", stdout)
+	})
+
+	t.Run("finished epic", func(t *testing.T) {
+		epicXML := strings.Replace(warningsTestEpic, `status="wip" started_at`, `status="completed" started_at`, 1)
+		stdout, err := runApp(t, warningsApp, "done", "phase", "P1", "--file", writeWarningsEpic(t, epicXML), "--time", "2025-08-16T10:00:00Z")
+		require.NoError(t, err)
+		assert.Equal(t, "Phase P1 completed.\nWarning: All phases of epic warn-epic are finished, the epic is not\nHint: agentpm done epic\n", stdout)
+	})
+
+	t.Run("warning-severity policy rules", func(t *testing.T) {
+		p, err := policy.Parse([]byte("rules:\n  - id: criteria\n    check: task_acceptance_criteria\n    severity: warning\n"))
+		require.NoError(t, err)
+		policy.SetActive(p)
+		defer policy.SetActive(nil)
+
+		epicXML := strings.Replace(warningsTestEpic, `status="wip" started_at="2025-08-16T09:30:00Z"`, `status="pending"`, 1)
+		stdout, err := runApp(t, warningsApp, "start", "task", "T1", "--file", writeWarningsEpic(t, epicXML), "--time", "2025-08-16T10:00:00Z")
+		require.NoError(t, err, "warnings do not refuse the mutation")
+		assert.Equal(t, "Task T1 started.\nWarning: policy criteria: task T1 has no acceptance criteria (required before it is started)\nHint: "+policy.SuppressionHint+"\n", stdout)
+	})
+}
+
+// writeWarningsEpic writes epicXML to an epic file and returns its path
+func writeWarningsEpic(t *testing.T, epicXML string) string {
+	t.Helper()
+	epicPath := filepath.Join(t.TempDir(), "epic.xml")
+	require.NoError(t, os.WriteFile(epicPath, []byte(epicXML), 0644))
+	return epicPath
+}
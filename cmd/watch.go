@@ -0,0 +1,340 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/fsnotify/fsnotify"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// WatchCommand validates the epic file whenever it is edited outside agentpm
+func WatchCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "watch",
+		Usage: "Validate the epic file whenever it changes",
+		Description: `Watch the epic file and validate it as soon as it changes, e.g. while a
+human hand-edits the XML next to a working agent.
+
+When an edit leaves the epic inconsistent, watch reports the validation
+errors and records a validation_warning event in the epic, so the agent sees
+the breakage in 'agentpm events' and 'agentpm log'. A file that no longer
+parses is reported only: there is no epic to record the event in. The same
+errors are reported once, until the file changes them.
+
+The file is polled; watch runs until interrupted (Ctrl+C).
+
+Examples:
+  agentpm watch                  # Watch the current epic
+  agentpm watch --interval 500ms
+  agentpm watch -f epic-3.xml --format json`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Override epic file from config",
+			},
+			&cli.DurationFlag{
+				Name:  "interval",
+				Usage: "How often to check the file for changes",
+				Value: 2 * time.Second,
+			},
+		},
+		Action: watchAction,
+	}
+}
+
+func watchAction(ctx context.Context, c *cli.Command) error {
+	epicFile, err := getEpicFile(c)
+	if err != nil {
+		return err
+	}
+	interval := c.Duration("interval")
+	if interval <= 0 {
+		return fmt.Errorf("invalid interval: %s (must be positive)", interval)
+	}
+
+	watcher := newEpicWatcher(epicFile, c.String("format"), c.Root().Writer)
+	if err := watcher.check(time.Now()); err != nil {
+		return err
+	}
+	errWriter := c.Root().ErrWriter
+	if errWriter == nil {
+		errWriter = os.Stderr
+	}
+	fmt.Fprintf(errWriter, "Watching %s (every %s, Ctrl+C to stop)\n", epicFile, interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			if err := watcher.check(now); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// epicWatcher validates an epic file each time its modification time or size changes
+type epicWatcher struct {
+	path     string
+	format   string
+	w        io.Writer
+	modTime  time.Time
+	size     int64
+	reported string // Problems last reported, so they are not repeated on every change
+}
+
+func newEpicWatcher(path, format string, w io.Writer) *epicWatcher {
+	return &epicWatcher{path: path, format: format, w: w}
+}
+
+// check validates the file if it changed since the last check. Validation
+// errors are reported and recorded as a validation_warning event; a file that
+// cannot be read or parsed is reported only.
+func (ew *epicWatcher) check(now time.Time) error {
+	defer storage.ReleaseLocks()
+	info, err := os.Stat(ew.path)
+	if err != nil {
+		return fmt.Errorf("failed to watch epic file: %w", err)
+	}
+	if info.ModTime().Equal(ew.modTime) && info.Size() == ew.size {
+		return nil
+	}
+	ew.remember(info)
+
+	fileStorage := storage.NewFileStorage()
+	epicData, err := fileStorage.LoadEpic(ew.path)
+	if err != nil {
+		ew.report([]string{err.Error()}, false, now)
+		return nil
+	}
+
+	result := epicData.Validate()
+	if result.Valid {
+		if ew.reported != "" {
+			ew.report(nil, false, now)
+		}
+		return nil
+	}
+
+	problems := strings.Join(result.Errors, "; ")
+	if problems == ew.reported {
+		return nil
+	}
+	service.CreateEvent(epicData, service.EventValidationWarning, "", "", "", problems, now)
+	if err := fileStorage.SaveEpic(epicData, ew.path); err != nil {
+		return fmt.Errorf("failed to record validation warning: %w", err)
+	}
+	// Our own write is no external edit
+	if info, err := os.Stat(ew.path); err == nil {
+		ew.remember(info)
+	}
+	ew.report(result.Errors, true, now)
+	return nil
+}
+
+func (ew *epicWatcher) remember(info os.FileInfo) {
+	ew.modTime = info.ModTime()
+	ew.size = info.Size()
+}
+
+// report writes the problems found in the file; no problems means the file is
+// consistent again
+func (ew *epicWatcher) report(problems []string, recorded bool, now time.Time) {
+	ew.reported = strings.Join(problems, "; ")
+	timestamp := now.UTC().Format(time.RFC3339)
+
+	switch ew.format {
+	case "json":
+		warning := struct {
+			File      string   `json:"file"`
+			Timestamp string   `json:"timestamp"`
+			Valid     bool     `json:"valid"`
+			Errors    []string `json:"errors,omitempty"`
+			Recorded  bool     `json:"event_recorded"`
+		}{ew.path, timestamp, len(problems) == 0, problems, recorded}
+		json.NewEncoder(ew.w).Encode(map[string]interface{}{"validation_warning": warning})
+	case "xml":
+		doc := etree.NewDocument()
+		root := doc.CreateElement("validation_warning")
+		root.CreateAttr("file", ew.path)
+		root.CreateAttr("timestamp", timestamp)
+		root.CreateAttr("valid", fmt.Sprintf("%t", len(problems) == 0))
+		root.CreateAttr("event_recorded", fmt.Sprintf("%t", recorded))
+		for _, problem := range problems {
+			root.CreateElement("error").SetText(problem)
+		}
+		doc.Indent(4)
+		doc.WriteTo(ew.w)
+	default:
+		if len(problems) == 0 {
+			fmt.Fprintf(ew.w, "[%s] ✓ %s is consistent again\n", timestamp, ew.path)
+			return
+		}
+		fmt.Fprintf(ew.w, "[%s] ⚠ %s became inconsistent (%d):\n", timestamp, ew.path, len(problems))
+		for _, problem := range problems {
+			fmt.Fprintf(ew.w, "  %s\n", problem)
+		}
+		if recorded {
+			fmt.Fprintf(ew.w, "  Recorded as a validation_warning event\n")
+		}
+	}
+}
+
+// watchFlags are the flags of commands that can re-render on changes, see withWatch
+func watchFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "watch",
+			Usage: "Re-render the output whenever the epic file changes",
+		},
+		&cli.DurationFlag{
+			Name:  "interval",
+			Usage: "With --watch: how often to poll where file notifications are unavailable",
+			Value: 2 * time.Second,
+		},
+	}
+}
+
+// withWatch runs action once, and with --watch again every time one of the
+// files returned by watched changes, until interrupted. Errors of later runs
+// (e.g. a file caught mid-edit) are reported without ending the watch.
+func withWatch(action cli.ActionFunc, watched func(c *cli.Command) ([]string, error)) cli.ActionFunc {
+	return func(ctx context.Context, c *cli.Command) error {
+		if !c.Bool("watch") {
+			return action(ctx, c)
+		}
+		// Other processes must be able to change the epic between renders
+		render := func() error {
+			defer storage.ReleaseLocks()
+			return action(ctx, c)
+		}
+		interval := c.Duration("interval")
+		if interval <= 0 {
+			return fmt.Errorf("invalid interval: %s (must be positive)", interval)
+		}
+		paths, err := watched(c)
+		if err != nil {
+			return err
+		}
+		if err := render(); err != nil {
+			return err
+		}
+
+		errWriter := c.Root().ErrWriter
+		if errWriter == nil {
+			errWriter = os.Stderr
+		}
+		fmt.Fprintf(errWriter, "Watching %s for changes (Ctrl+C to stop)\n", strings.Join(paths, ", "))
+		return watchFiles(ctx, paths, interval, true, func() {
+			if format := c.String("format"); format == "" || format == "text" {
+				fmt.Fprintf(c.Root().Writer, "\n--- %s: epic changed ---\n", time.Now().Format(time.TimeOnly))
+			}
+			if err := render(); err != nil {
+				fmt.Fprintf(errWriter, "Error: %v\n", err)
+			}
+		})
+	}
+}
+
+// watchDebounce groups the events of one save (temp file written, renamed over the original)
+const watchDebounce = 100 * time.Millisecond
+
+// watchFiles calls onChange whenever one of the files changes, until ctx is
+// done. With notify it relies on file system notifications, watching the
+// directories so that files replaced by a rename stay watched; it polls every
+// interval where notifications are unavailable.
+func watchFiles(ctx context.Context, paths []string, interval time.Duration, notify bool, onChange func()) error {
+	if notify {
+		watcher, err := fsnotify.NewWatcher()
+		if err == nil {
+			defer watcher.Close()
+			watchedFiles := make(map[string]bool)
+			for _, path := range paths {
+				absPath, _ := filepath.Abs(path)
+				watchedFiles[absPath] = true
+				if err = watcher.Add(filepath.Dir(absPath)); err != nil {
+					break
+				}
+			}
+			if err == nil {
+				return notifyChanges(ctx, watcher, watchedFiles, onChange)
+			}
+		}
+	}
+	return pollChanges(ctx, paths, interval, onChange)
+}
+
+func notifyChanges(ctx context.Context, watcher *fsnotify.Watcher, watchedFiles map[string]bool, onChange func()) error {
+	debounce := time.NewTimer(watchDebounce)
+	debounce.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			absPath, _ := filepath.Abs(event.Name)
+			if watchedFiles[absPath] && event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+				debounce.Reset(watchDebounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return fmt.Errorf("failed to watch epic file: %w", err)
+		case <-debounce.C:
+			onChange()
+		}
+	}
+}
+
+func pollChanges(ctx context.Context, paths []string, interval time.Duration, onChange func()) error {
+	type fileState struct {
+		modTime time.Time
+		size    int64
+	}
+	stat := func() []fileState {
+		states := make([]fileState, len(paths))
+		for i, path := range paths {
+			if info, err := os.Stat(path); err == nil {
+				states[i] = fileState{info.ModTime(), info.Size()}
+			}
+		}
+		return states
+	}
+
+	last := stat()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			current := stat()
+			for i := range current {
+				if current[i] != last[i] {
+					last = current
+					onChange()
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestEpicWatcher(t *testing.T) {
+	epicFile := filepath.Join(t.TempDir(), "epic.xml")
+	testEpic := epic.NewEpic("8", "Watched")
+	testEpic.Phases = []epic.Phase{{ID: "P1", Name: "Phase 1", Status: epic.StatusPending}}
+	testEpic.Tasks = []epic.Task{{ID: "T1", PhaseID: "P1", Name: "Task 1", Status: epic.StatusPending}}
+	testEpic.Tests = []epic.Test{{ID: "T1_1", TaskID: "T1", Name: "Test 1", Status: epic.StatusPending}}
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicFile))
+
+	now := time.Date(2025, 8, 16, 12, 0, 0, 0, time.UTC)
+	// Edits within the same second would keep the modification time
+	edit := func(from, to string) {
+		content, err := os.ReadFile(epicFile)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(epicFile, []byte(strings.Replace(string(content), from, to, 1)), 0644))
+		now = now.Add(time.Minute)
+		require.NoError(t, os.Chtimes(epicFile, now, now))
+	}
+	events := func() []epic.Event {
+		epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+		require.NoError(t, err)
+		return epicData.Events
+	}
+
+	var out bytes.Buffer
+	watcher := newEpicWatcher(epicFile, "text", &out)
+	require.NoError(t, watcher.check(now))
+	assert.Empty(t, out.String(), "a consistent file is not reported")
+
+	t.Run("records a validation_warning for an inconsistent edit", func(t *testing.T) {
+		edit(`phase_id="P1"`, `phase_id="P9"`)
+		require.NoError(t, watcher.check(now))
+
+		assert.Contains(t, out.String(), "became inconsistent (1):\n  Task T1 references non-existent phase: P9")
+		assert.Contains(t, out.String(), "Recorded as a validation_warning event")
+		recorded := events()
+		require.Len(t, recorded, 1)
+		assert.Equal(t, "validation_warning", recorded[0].Type)
+		assert.Contains(t, recorded[0].Data, "Task T1 references non-existent phase: P9")
+
+		out.Reset()
+		require.NoError(t, watcher.check(now))
+		assert.Empty(t, out.String(), "its own write is no external edit")
+	})
+
+	t.Run("reports unparsable files without recording", func(t *testing.T) {
+		edit(`</epic>`, ``)
+		require.NoError(t, watcher.check(now))
+		assert.Contains(t, out.String(), "became inconsistent")
+		assert.NotContains(t, out.String(), "Recorded as")
+	})
+
+	t.Run("reports recovery", func(t *testing.T) {
+		out.Reset()
+		content, err := os.ReadFile(epicFile)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(epicFile, append(content, []byte("</epic>\n")...), 0644))
+		edit(`phase_id="P9"`, `phase_id="P1"`)
+		require.NoError(t, watcher.check(now))
+		assert.Contains(t, out.String(), "is consistent again")
+		assert.Len(t, events(), 1)
+	})
+}
+
+func TestWatchFiles(t *testing.T) {
+	for _, notify := range []bool{true, false} {
+		name := "polling"
+		if notify {
+			name = "notifications"
+		}
+		t.Run(name, func(t *testing.T) {
+			epicFile := filepath.Join(t.TempDir(), "epic.xml")
+			require.NoError(t, os.WriteFile(epicFile, []byte("<epic/>"), 0644))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			changes := make(chan struct{}, 10)
+			done := make(chan error)
+			go func() {
+				done <- watchFiles(ctx, []string{epicFile}, 20*time.Millisecond, notify, func() { changes <- struct{}{} })
+			}()
+			time.Sleep(50 * time.Millisecond)
+
+			// Saved like storage does: written to a temp file, renamed over the original
+			require.NoError(t, os.WriteFile(epicFile+".tmp", []byte("<epic id=\"8\"/>"), 0644))
+			require.NoError(t, os.Rename(epicFile+".tmp", epicFile))
+
+			select {
+			case <-changes:
+			case <-time.After(2 * time.Second):
+				t.Fatal("change not detected")
+			}
+			cancel()
+			assert.NoError(t, <-done)
+		})
+	}
+}
+
+func TestCurrentCommandWatch(t *testing.T) {
+	tempDir := t.TempDir()
+	epicFile := filepath.Join(tempDir, "epic.xml")
+	configPath := filepath.Join(tempDir, ".agentpm.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"current_epic": "epic.xml"}`), 0644))
+	testEpic := epic.NewEpic("8", "Watched")
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicFile))
+
+	app := &cli.Command{
+		Name: "agentpm",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "config", Value: configPath},
+		},
+		Commands: []*cli.Command{CurrentCommand()},
+	}
+	var stdout syncBuffer
+	app.Writer = &stdout
+	app.ErrWriter = &bytes.Buffer{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- app.Run(ctx, []string{"agentpm", "current", "--watch"})
+	}()
+	require.Eventually(t, func() bool { return strings.Contains(stdout.String(), "Epic Status: pending") }, 2*time.Second, 10*time.Millisecond)
+
+	testEpic.Status = epic.StatusWIP
+	require.NoError(t, storage.NewFileStorage().SaveEpic(testEpic, epicFile))
+	require.Eventually(t, func() bool { return strings.Contains(stdout.String(), "Epic Status: wip") }, 2*time.Second, 10*time.Millisecond)
+	assert.Contains(t, stdout.String(), "epic changed ---")
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+// syncBuffer is a bytes.Buffer safe to read while a watching command writes to it
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
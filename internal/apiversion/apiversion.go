@@ -0,0 +1,81 @@
+// Package apiversion selects the version of agentpm's structured (JSON/XML)
+// output. Output changes that would break existing agent prompts or scripts,
+// like renamed fields, only land in a new version; older versions keep being
+// served as they were. New fields are added to all versions.
+package apiversion
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is an output API version
+type Version int
+
+const (
+	V1 Version = 1 // Original output
+	V2 Version = 2 // status: epic13_status renamed to completion
+)
+
+// Default is the version of projects that did not choose one, so their output never changes unasked
+const Default = V1
+
+// Latest is the newest version, which new projects start with
+const Latest = V2
+
+type contextKey struct{}
+
+// NewContext returns a context carrying the output version of an invocation or request
+func NewContext(ctx context.Context, v Version) context.Context {
+	return context.WithValue(ctx, contextKey{}, v)
+}
+
+// FromContext returns the output version ctx carries, Default if it carries none
+func FromContext(ctx context.Context) Version {
+	if v, ok := ctx.Value(contextKey{}).(Version); ok {
+		return v
+	}
+	return Default
+}
+
+// String returns the version as "v1", "v2", ...
+func (v Version) String() string {
+	return fmt.Sprintf("v%d", int(v))
+}
+
+// Parse reads a version given as "2" or "v2"
+func Parse(s string) (Version, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "v"))
+	if err != nil || n < int(V1) || n > int(Latest) {
+		return 0, fmt.Errorf("unsupported API version %q (supported: %s)", s, supported())
+	}
+	return Version(n), nil
+}
+
+// Negotiate picks the version a client asks for, given either as a version
+// ("2", "v2") or as a media type ("application/vnd.agentpm.v2+json"). Servers
+// call it with the API-Version or Accept header of a request; no preference
+// selects Default.
+func Negotiate(requested string) (Version, error) {
+	for _, part := range strings.Split(requested, ",") {
+		mediaType, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if rest, ok := strings.CutPrefix(mediaType, "application/vnd.agentpm."); ok {
+			version, _, _ := strings.Cut(rest, "+")
+			return Parse(version)
+		}
+	}
+	if strings.Contains(requested, "/") || strings.TrimSpace(requested) == "" {
+		return Default, nil
+	}
+	return Parse(requested)
+}
+
+func supported() string {
+	versions := make([]string, 0, int(Latest))
+	for v := V1; v <= Latest; v++ {
+		versions = append(versions, strconv.Itoa(int(v)))
+	}
+	return strings.Join(versions, ", ")
+}
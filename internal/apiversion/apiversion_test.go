@@ -0,0 +1,48 @@
+package apiversion
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	for input, expected := range map[string]Version{"1": V1, "2": V2, "v2": V2, " V1 ": V1} {
+		version, err := Parse(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, version, input)
+	}
+
+	for _, input := range []string{"", "0", "3", "latest"} {
+		_, err := Parse(input)
+		assert.ErrorContains(t, err, "supported: 1, 2", input)
+	}
+	assert.Equal(t, "v2", V2.String())
+}
+
+func TestNegotiate(t *testing.T) {
+	for requested, expected := range map[string]Version{
+		"":                                       Default,
+		"2":                                      V2,
+		"v1":                                     V1,
+		"application/json":                       Default,
+		"*/*":                                    Default,
+		"application/vnd.agentpm.v2+json":        V2,
+		"application/vnd.agentpm.v1+json; q=0.9": V1,
+		"text/html, application/vnd.agentpm.v2+json": V2,
+	} {
+		version, err := Negotiate(requested)
+		require.NoError(t, err, requested)
+		assert.Equal(t, expected, version, requested)
+	}
+
+	_, err := Negotiate("application/vnd.agentpm.v9+json")
+	assert.ErrorContains(t, err, "unsupported API version")
+}
+
+func TestContext(t *testing.T) {
+	assert.Equal(t, Default, FromContext(context.Background()))
+	assert.Equal(t, V2, FromContext(NewContext(context.Background(), V2)))
+}
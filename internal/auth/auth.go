@@ -0,0 +1,215 @@
+// Package auth manages the API tokens of serve mode. Each token has a scope -
+// read, mutate or admin - and only its hash is stored, in .agentpm/tokens.json
+// by default.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Scope is what a token may do; each scope includes the ones below it
+type Scope string
+
+const (
+	ScopeRead   Scope = "read"   // Query epics, status and statistics
+	ScopeMutate Scope = "mutate" // Also start, complete, pass and fail work
+	ScopeAdmin  Scope = "admin"  // Also delete entities and manage the epic
+)
+
+// Scopes lists the scopes from least to most privileged
+var Scopes = []Scope{ScopeRead, ScopeMutate, ScopeAdmin}
+
+// TokenPrefix marks agentpm API tokens, so leaked ones are easy to recognise
+const TokenPrefix = "apm_"
+
+func (s Scope) rank() int {
+	for i, scope := range Scopes {
+		if scope == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// Valid reports whether s is a known scope
+func (s Scope) Valid() bool {
+	return s.rank() >= 0
+}
+
+// Includes reports whether a token with scope s may do what required needs
+func (s Scope) Includes(required Scope) bool {
+	return s.Valid() && s.rank() >= required.rank()
+}
+
+// RequiredScope is the scope an HTTP request needs: reading for GET and HEAD,
+// admin for DELETE, mutate for everything else
+func RequiredScope(method string) Scope {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return ScopeRead
+	case http.MethodDelete:
+		return ScopeAdmin
+	default:
+		return ScopeMutate
+	}
+}
+
+// Token is a stored API token
+type Token struct {
+	Name      string    `json:"name"`
+	Scope     Scope     `json:"scope"`
+	Hash      string    `json:"hash"` // SHA-256 of the secret; the secret itself is never stored
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Store is the set of API tokens of a project
+type Store struct {
+	Path   string  `json:"-"`
+	Tokens []Token `json:"tokens"`
+}
+
+// Load reads the token store at path. A missing file is an empty store.
+func Load(path string) (*Store, error) {
+	store := &Store{Path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return store, nil
+		}
+		return nil, fmt.Errorf("failed to read tokens file %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("invalid tokens file %s: %w", path, err)
+	}
+	for _, token := range store.Tokens {
+		if !token.Scope.Valid() {
+			return nil, fmt.Errorf("invalid tokens file %s: token %s has unknown scope %q", path, token.Name, token.Scope)
+		}
+	}
+	return store, nil
+}
+
+// Save writes the token store, readable by the owner only
+func (s *Store) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal tokens: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0755); err != nil {
+		return fmt.Errorf("failed to create tokens directory: %w", err)
+	}
+
+	tempFile := s.Path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to write tokens file: %w", err)
+	}
+	if err := os.Rename(tempFile, s.Path); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to move tokens file: %w", err)
+	}
+	return nil
+}
+
+// Create adds a token and returns its secret, which is shown only this once
+func (s *Store) Create(name string, scope Scope, now time.Time) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("token name is required")
+	}
+	if !scope.Valid() {
+		return "", fmt.Errorf("invalid scope %q (expected read, mutate or admin)", scope)
+	}
+	if s.find(name) >= 0 {
+		return "", fmt.Errorf("token %s already exists; revoke it first", name)
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	secret := TokenPrefix + hex.EncodeToString(random)
+	s.Tokens = append(s.Tokens, Token{Name: name, Scope: scope, Hash: hash(secret), CreatedAt: now.UTC()})
+	return secret, nil
+}
+
+// Revoke removes a token
+func (s *Store) Revoke(name string) error {
+	i := s.find(name)
+	if i < 0 {
+		return fmt.Errorf("token %s not found", name)
+	}
+	s.Tokens = append(s.Tokens[:i], s.Tokens[i+1:]...)
+	return nil
+}
+
+// Authenticate returns the token a secret belongs to
+func (s *Store) Authenticate(secret string) (*Token, bool) {
+	secretHash := []byte(hash(secret))
+	for i := range s.Tokens {
+		if subtle.ConstantTimeCompare(secretHash, []byte(s.Tokens[i].Hash)) == 1 {
+			return &s.Tokens[i], true
+		}
+	}
+	return nil, false
+}
+
+func (s *Store) find(name string) int {
+	for i, token := range s.Tokens {
+		if token.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+type contextKey struct{}
+
+// FromContext returns the token a request was authenticated with
+func FromContext(ctx context.Context) (*Token, bool) {
+	token, ok := ctx.Value(contextKey{}).(*Token)
+	return token, ok
+}
+
+// Middleware authenticates requests by their "Authorization: Bearer <token>"
+// header and rejects those whose token lacks the scope RequiredScope demands:
+// 401 without a valid token, 403 with too narrow a scope.
+func Middleware(store *Store, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secret, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+		token, ok := store.Authenticate(strings.TrimSpace(secret))
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "invalid token")
+			return
+		}
+		if required := RequiredScope(r.Method); !token.Scope.Includes(required) {
+			writeError(w, http.StatusForbidden, fmt.Sprintf("token %s has scope %s; %s %s requires %s", token.Name, token.Scope, r.Method, r.URL.Path, required))
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), contextKey{}, token)))
+	})
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
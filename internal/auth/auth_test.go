@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScope(t *testing.T) {
+	assert.True(t, ScopeAdmin.Includes(ScopeMutate))
+	assert.True(t, ScopeMutate.Includes(ScopeRead))
+	assert.False(t, ScopeRead.Includes(ScopeMutate))
+	assert.False(t, ScopeMutate.Includes(ScopeAdmin))
+	assert.False(t, Scope("root").Includes(ScopeRead))
+
+	assert.Equal(t, ScopeRead, RequiredScope(http.MethodGet))
+	assert.Equal(t, ScopeMutate, RequiredScope(http.MethodPost))
+	assert.Equal(t, ScopeAdmin, RequiredScope(http.MethodDelete))
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".agentpm", "tokens.json")
+	store, err := Load(path)
+	require.NoError(t, err)
+	assert.Empty(t, store.Tokens)
+
+	secret, err := store.Create("dashboard", ScopeRead, time.Now())
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, TokenPrefix))
+	_, err = store.Create("dashboard", ScopeAdmin, time.Now())
+	assert.EqualError(t, err, "token dashboard already exists; revoke it first")
+	_, err = store.Create("worker", "root", time.Now())
+	assert.EqualError(t, err, `invalid scope "root" (expected read, mutate or admin)`)
+	require.NoError(t, store.Save())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), secret, "only the hash is stored")
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	token, ok := loaded.Authenticate(secret)
+	require.True(t, ok)
+	assert.Equal(t, "dashboard", token.Name)
+	_, ok = loaded.Authenticate(secret + "0")
+	assert.False(t, ok)
+
+	require.NoError(t, loaded.Revoke("dashboard"))
+	_, ok = loaded.Authenticate(secret)
+	assert.False(t, ok)
+	assert.EqualError(t, loaded.Revoke("dashboard"), "token dashboard not found")
+}
+
+func TestMiddleware(t *testing.T) {
+	store := &Store{}
+	dashboard, err := store.Create("dashboard", ScopeRead, time.Now())
+	require.NoError(t, err)
+	worker, err := store.Create("worker", ScopeMutate, time.Now())
+	require.NoError(t, err)
+	admin, err := store.Create("admin", ScopeAdmin, time.Now())
+	require.NoError(t, err)
+
+	handler := Middleware(store, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, _ := FromContext(r.Context())
+		w.Write([]byte(token.Name))
+	}))
+
+	tests := []struct {
+		name   string
+		method string
+		secret string
+		status int
+	}{
+		{"no token", http.MethodGet, "", http.StatusUnauthorized},
+		{"unknown token", http.MethodGet, "apm_unknown", http.StatusUnauthorized},
+		{"dashboard reads", http.MethodGet, dashboard, http.StatusOK},
+		{"dashboard cannot complete tasks", http.MethodPost, dashboard, http.StatusForbidden},
+		{"worker completes tasks", http.MethodPost, worker, http.StatusOK},
+		{"worker cannot delete entities", http.MethodDelete, worker, http.StatusForbidden},
+		{"admin deletes entities", http.MethodDelete, admin, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(tt.method, "/tasks/1A_1", nil)
+			if tt.secret != "" {
+				request.Header.Set("Authorization", "Bearer "+tt.secret)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.status, recorder.Code, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package autonext
+
+import (
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/service"
+)
+
+// Rotation dispatches the tasks SelectNext starts to several assignees. Tasks
+// with an assignee of their own keep it.
+type Rotation struct {
+	Policy    string   // config.AssigneeRotationRoundRobin or config.AssigneeRotationLeastLoaded, empty for none
+	Assignees []string // In rotation order
+}
+
+// RotationFromConfig returns the rotation configured by assignee_rotation and assignees
+func RotationFromConfig(cfg *config.Config) Rotation {
+	return Rotation{Policy: cfg.AssigneeRotation, Assignees: cfg.AssigneeList()}
+}
+
+// SetRotation makes SelectNext assign the tasks it starts following rotation
+func (s *AutoNextService) SetRotation(rotation Rotation) {
+	s.rotation = rotation
+}
+
+// NextAssignee picks the assignee of the next task to start, or "" when no
+// rotation is configured
+func (r Rotation) NextAssignee(epicData *epic.Epic) string {
+	if len(r.Assignees) == 0 {
+		return ""
+	}
+	switch r.Policy {
+	case config.AssigneeRotationRoundRobin:
+		return r.roundRobin(epicData)
+	case config.AssigneeRotationLeastLoaded:
+		return r.leastLoaded(epicData)
+	default:
+		return ""
+	}
+}
+
+// roundRobin picks the assignee after the one the most recently assigned task went to
+func (r Rotation) roundRobin(epicData *epic.Epic) string {
+	assignees := make(map[string]string)
+	for _, task := range epicData.Tasks {
+		assignees[task.ID] = task.Assignee
+	}
+	for i := len(epicData.Events) - 1; i >= 0; i-- {
+		event := epicData.Events[i]
+		if service.EventType(event.Type) != service.EventTaskAssigned {
+			continue
+		}
+		last := assignees[service.EventEntityID(event)]
+		for j, assignee := range r.Assignees {
+			if assignee == last {
+				return r.Assignees[(j+1)%len(r.Assignees)]
+			}
+		}
+	}
+	return r.Assignees[0]
+}
+
+// leastLoaded picks the assignee with the fewest tasks in progress, then the
+// fewest tasks assigned overall, then the first in rotation order
+func (r Rotation) leastLoaded(epicData *epic.Epic) string {
+	active := make(map[string]int)
+	assigned := make(map[string]int)
+	for _, task := range epicData.Tasks {
+		if task.Assignee == "" {
+			continue
+		}
+		assigned[task.Assignee]++
+		if task.Status == epic.StatusWIP {
+			active[task.Assignee]++
+		}
+	}
+
+	best := r.Assignees[0]
+	for _, assignee := range r.Assignees[1:] {
+		if active[assignee] < active[best] || (active[assignee] == active[best] && assigned[assignee] < assigned[best]) {
+			best = assignee
+		}
+	}
+	return best
+}
+
+// startTask starts a task, first handing it to the next assignee of the
+// rotation when it has no assignee of its own. It returns that assignee.
+func (s *AutoNextService) startTask(epicData *epic.Epic, task *epic.Task, timestamp time.Time) (string, error) {
+	var assignee string
+	if task.Assignee == "" {
+		assignee = s.rotation.NextAssignee(epicData)
+	}
+	if assignee != "" {
+		if err := s.taskService.AssignTask(epicData, task.ID, assignee, timestamp); err != nil {
+			return "", err
+		}
+	}
+	if err := s.taskService.StartTask(epicData, task.ID, timestamp); err != nil {
+		return "", err
+	}
+	return assignee, nil
+}
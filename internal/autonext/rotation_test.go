@@ -0,0 +1,118 @@
+package autonext
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/phases"
+	"github.com/mindreframer/agentpm/internal/query"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/mindreframer/agentpm/internal/tasks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRotatingService(policy string, assignees ...string) *AutoNextService {
+	storage := storage.NewMemoryStorage()
+	queryService := query.NewQueryService(storage)
+	phaseService := phases.NewPhaseService(storage, queryService)
+	taskService := tasks.NewTaskService(storage, queryService)
+	service := NewAutoNextService(storage, queryService, phaseService, taskService)
+	service.SetRotation(Rotation{Policy: policy, Assignees: assignees})
+	return service
+}
+
+func createRotationEpic() *epic.Epic {
+	return &epic.Epic{
+		ID:       "epic-1",
+		Status:   epic.StatusWIP,
+		Assignee: "lead",
+		Phases: []epic.Phase{
+			{ID: "1", Name: "Phase 1", Status: epic.StatusWIP},
+		},
+		Tasks: []epic.Task{
+			{ID: "1_1", PhaseID: "1", Name: "One", Status: epic.StatusPending},
+			{ID: "1_2", PhaseID: "1", Name: "Two", Status: epic.StatusPending},
+			{ID: "1_3", PhaseID: "1", Name: "Three", Status: epic.StatusPending},
+			{ID: "1_4", PhaseID: "1", Name: "Four", Status: epic.StatusPending},
+		},
+	}
+}
+
+// startAndComplete runs SelectNext and completes the started task, like an agent would
+func startAndComplete(t *testing.T, s *AutoNextService, e *epic.Epic, at time.Time) *AutoNextResult {
+	result, err := s.SelectNext(e, at)
+	require.NoError(t, err)
+	require.Equal(t, ActionStartTask, result.Action)
+	require.NoError(t, s.taskService.CompleteTask(e, result.TaskID, at.Add(time.Minute)))
+	return result
+}
+
+func TestRotation(t *testing.T) {
+	at := time.Date(2025, 8, 16, 15, 30, 0, 0, time.UTC)
+
+	t.Run("round-robin hands tasks to each assignee in turn", func(t *testing.T) {
+		s := newRotatingService(config.AssigneeRotationRoundRobin, "agent-a", "agent-b")
+		e := createRotationEpic()
+
+		var got []string
+		for i := 0; i < 3; i++ {
+			got = append(got, startAndComplete(t, s, e, at.Add(time.Duration(i)*time.Hour)).Assignee)
+		}
+		assert.Equal(t, []string{"agent-a", "agent-b", "agent-a"}, got)
+		assert.Equal(t, "agent-b", findTaskByID(e, "1_2").Assignee)
+	})
+
+	t.Run("least-loaded prefers the assignee with the least work", func(t *testing.T) {
+		s := newRotatingService(config.AssigneeRotationLeastLoaded, "agent-a", "agent-b", "agent-c")
+		e := createRotationEpic()
+		e.Tasks = append(e.Tasks,
+			epic.Task{ID: "2_1", PhaseID: "2", Assignee: "agent-a", Status: epic.StatusWIP},
+			epic.Task{ID: "2_2", PhaseID: "2", Assignee: "agent-b", Status: epic.StatusCompleted},
+		)
+
+		// agent-a has a task in progress, agent-b more tasks overall than agent-c
+		assert.Equal(t, "agent-c", startAndComplete(t, s, e, at).Assignee)
+		assert.Equal(t, "agent-b", startAndComplete(t, s, e, at.Add(time.Hour)).Assignee)
+	})
+
+	t.Run("tasks with an assignee of their own keep it", func(t *testing.T) {
+		s := newRotatingService(config.AssigneeRotationRoundRobin, "agent-a", "agent-b")
+		e := createRotationEpic()
+		e.Tasks[0].Assignee = "specialist"
+
+		result, err := s.SelectNext(e, at)
+		require.NoError(t, err)
+		assert.Empty(t, result.Assignee)
+		assert.Equal(t, "specialist", findTaskByID(e, "1_1").Assignee)
+	})
+
+	t.Run("no rotation leaves assignments alone", func(t *testing.T) {
+		s := newRotatingService("", "agent-a", "agent-b")
+		e := createRotationEpic()
+
+		result, err := s.SelectNext(e, at)
+		require.NoError(t, err)
+		assert.Empty(t, result.Assignee)
+		assert.Empty(t, findTaskByID(e, "1_1").Assignee)
+	})
+
+	t.Run("assigns the task the next phase starts with", func(t *testing.T) {
+		s := newRotatingService(config.AssigneeRotationRoundRobin, "agent-a")
+		e := createRotationEpic()
+		e.Phases[0].Status = epic.StatusPending
+
+		result, err := s.SelectNext(e, at)
+		require.NoError(t, err)
+		assert.Equal(t, ActionStartPhase, result.Action)
+		assert.Equal(t, "agent-a", result.Assignee)
+		assert.Contains(t, result.XMLOutput, "<assignee>agent-a</assignee>")
+	})
+}
+
+func TestRotationFromConfig(t *testing.T) {
+	cfg := &config.Config{Assignees: " agent-a, agent-b,,agent-a ", AssigneeRotation: config.AssigneeRotationLeastLoaded}
+	assert.Equal(t, Rotation{Policy: config.AssigneeRotationLeastLoaded, Assignees: []string{"agent-a", "agent-b"}}, RotationFromConfig(cfg))
+}
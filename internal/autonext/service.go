@@ -6,6 +6,7 @@ import (
 
 	"github.com/mindreframer/agentpm/internal/epic"
 	"github.com/mindreframer/agentpm/internal/phases"
+	"github.com/mindreframer/agentpm/internal/planner"
 	"github.com/mindreframer/agentpm/internal/query"
 	"github.com/mindreframer/agentpm/internal/storage"
 	"github.com/mindreframer/agentpm/internal/tasks"
@@ -35,6 +36,8 @@ type AutoNextResult struct {
 	TaskStatus   epic.Status
 	StartedAt    time.Time
 	AutoSelected bool
+	Assignee     string         // Who the rotation handed the started task to, if anyone
+	Stall        *planner.Stall // Why nothing could be started, for ActionNoWork
 }
 
 // AutoNextService provides intelligent next work selection
@@ -43,6 +46,7 @@ type AutoNextService struct {
 	query        *query.QueryService
 	phaseService *phases.PhaseService
 	taskService  *tasks.TaskService
+	rotation     Rotation
 }
 
 func NewAutoNextService(storage storage.Storage, query *query.QueryService, phaseService *phases.PhaseService, taskService *tasks.TaskService) *AutoNextService {
@@ -76,6 +80,34 @@ func (s *AutoNextService) handleActivePhase(epicData *epic.Epic, activePhase *ep
 	// Check if there's already an active task in the phase
 	activeTask := s.taskService.GetActiveTask(epicData, activePhase.ID)
 	if activeTask != nil {
+		// An active parent task continues with its next sub-task
+		var subTasks []epic.Task
+		for _, task := range filterStartable(epicData, s.taskService.GetPendingTasksInPhase(epicData, activePhase.ID)) {
+			if task.ParentTaskID == activeTask.ID {
+				subTasks = append(subTasks, task)
+			}
+		}
+		if len(subTasks) > 0 {
+			task := subTasks[0]
+			assignee, err := s.startTask(epicData, &task, timestamp)
+			if err != nil {
+				return nil, fmt.Errorf("failed to start task %s: %w", task.ID, err)
+			}
+			return &AutoNextResult{
+				Action:       ActionStartTask,
+				PhaseID:      activePhase.ID,
+				TaskID:       task.ID,
+				PhaseName:    activePhase.Name,
+				TaskName:     task.Name,
+				PhaseStatus:  activePhase.Status,
+				TaskStatus:   epic.StatusWIP,
+				StartedAt:    timestamp,
+				AutoSelected: true,
+				Assignee:     assignee,
+				Message:      fmt.Sprintf("Started Task %s: %s (sub-task of %s, auto-selected)", task.ID, task.Name, activeTask.ID),
+			}, nil
+		}
+
 		// There's already an active task, no action needed
 		return &AutoNextResult{
 			Action:  ActionNoWork,
@@ -85,13 +117,13 @@ func (s *AutoNextService) handleActivePhase(epicData *epic.Epic, activePhase *ep
 
 	// Look for next pending task in current active phase
 	pendingTasks := s.taskService.GetPendingTasksInPhase(epicData, activePhase.ID)
-	pendingTasksFiltered := filterPendingOnly(pendingTasks)
+	pendingTasksFiltered := filterStartable(epicData, pendingTasks)
 
 	if len(pendingTasksFiltered) > 0 {
 		// Start the first pending task in the active phase
 		task := pendingTasksFiltered[0]
 
-		err := s.taskService.StartTask(epicData, task.ID, timestamp)
+		assignee, err := s.startTask(epicData, &task, timestamp)
 		if err != nil {
 			return nil, fmt.Errorf("failed to start task %s: %w", task.ID, err)
 		}
@@ -106,13 +138,15 @@ func (s *AutoNextService) handleActivePhase(epicData *epic.Epic, activePhase *ep
 			TaskStatus:   epic.StatusWIP,
 			StartedAt:    timestamp,
 			AutoSelected: true,
+			Assignee:     assignee,
 			Message:      fmt.Sprintf("Started Task %s: %s (auto-selected)", task.ID, task.Name),
 		}, nil
 	}
 
 	// No pending tasks in current phase - check if phase can be completed
+	stall := planner.AnalyzeStall(epicData)
 	allTasksCompleted := s.areAllTasksCompletedOrCancelled(epicData, activePhase.ID)
-	if allTasksCompleted {
+	if allTasksCompleted && (stall == nil || stall.Reason == planner.StallPhaseComplete) {
 		// Complete the current phase
 		err := s.phaseService.CompletePhase(epicData, activePhase.ID, timestamp)
 		if err != nil {
@@ -123,17 +157,36 @@ func (s *AutoNextService) handleActivePhase(epicData *epic.Epic, activePhase *ep
 		return s.handleNoActivePhase(epicData, timestamp)
 	}
 
-	// Phase has pending work but no tasks can be started (shouldn't happen with proper validation)
+	// Blocked tasks or unfinished tests keep the phase open
+	if stall != nil {
+		return stalledResult(stall), nil
+	}
 	return &AutoNextResult{
 		Action:  ActionNoWork,
 		Message: fmt.Sprintf("Phase %s has pending work but no tasks can be started", activePhase.ID),
 	}, nil
 }
 
+// stalledResult reports why nothing could be started
+func stalledResult(stall *planner.Stall) *AutoNextResult {
+	return &AutoNextResult{
+		Action:  ActionNoWork,
+		PhaseID: stall.PhaseID,
+		Message: stall.Message,
+		Stall:   stall,
+	}
+}
+
 // handleNoActivePhase handles selection when there's no active phase
 func (s *AutoNextService) handleNoActivePhase(epicData *epic.Epic, timestamp time.Time) (*AutoNextResult, error) {
 	// Find next pending phase
 	nextPhase := s.findNextPendingPhase(epicData)
+
+	// Upstream tests unfinished, or phases that are neither pending nor finished
+	if stall := planner.AnalyzeStall(epicData); stall != nil {
+		return stalledResult(stall), nil
+	}
+
 	if nextPhase == nil {
 		// All phases are completed - epic is ready for completion
 		return &AutoNextResult{
@@ -150,13 +203,13 @@ func (s *AutoNextService) handleNoActivePhase(epicData *epic.Epic, timestamp tim
 
 	// Find first pending task in the newly started phase
 	pendingTasks := s.taskService.GetPendingTasksInPhase(epicData, nextPhase.ID)
-	pendingTasksFiltered := filterPendingOnly(pendingTasks)
+	pendingTasksFiltered := filterStartable(epicData, pendingTasks)
 
 	if len(pendingTasksFiltered) > 0 {
 		// Start the first pending task
 		task := pendingTasksFiltered[0]
 
-		err := s.taskService.StartTask(epicData, task.ID, timestamp)
+		assignee, err := s.startTask(epicData, &task, timestamp)
 		if err != nil {
 			return nil, fmt.Errorf("failed to start task %s: %w", task.ID, err)
 		}
@@ -174,8 +227,9 @@ func (s *AutoNextService) handleNoActivePhase(epicData *epic.Epic, timestamp tim
 			TaskStatus:   epic.StatusWIP,
 			StartedAt:    timestamp,
 			AutoSelected: true,
+			Assignee:     assignee,
 			Message:      fmt.Sprintf("Started Phase %s and Task %s (auto-selected)", nextPhase.ID, task.ID),
-			XMLOutput:    s.formatPhaseStartedXML(epicData.ID, nextPhase, allTasksInPhase, task.ID, timestamp),
+			XMLOutput:    s.formatPhaseStartedXML(epicData.ID, nextPhase, allTasksInPhase, task.ID, assignee, timestamp),
 		}, nil
 	}
 
@@ -190,10 +244,11 @@ func (s *AutoNextService) handleNoActivePhase(epicData *epic.Epic, timestamp tim
 	}, nil
 }
 
-// findNextPendingPhase returns the first phase in pending status
+// findNextPendingPhase returns the first phase in pending status whose
+// depends_on phases are completed
 func (s *AutoNextService) findNextPendingPhase(epicData *epic.Epic) *epic.Phase {
 	for i := range epicData.Phases {
-		if epicData.Phases[i].Status == epic.StatusPending {
+		if epicData.Phases[i].Status == epic.StatusPending && len(epicData.UnmetPhaseDependencies(&epicData.Phases[i])) == 0 {
 			return &epicData.Phases[i]
 		}
 	}
@@ -215,19 +270,40 @@ func (s *AutoNextService) areAllTasksCompletedOrCancelled(epicData *epic.Epic, p
 	return true
 }
 
-// filterPendingOnly filters tasks to only include those in pending status
-func filterPendingOnly(tasks []epic.Task) []epic.Task {
+// filterStartable filters tasks to only include those in pending status whose
+// depends_on tasks are completed, leaf tasks first
+func filterStartable(epicData *epic.Epic, tasks []epic.Task) []epic.Task {
 	var pendingTasks []epic.Task
+	for i, task := range tasks {
+		if task.Status != epic.StatusPending || len(epicData.UnmetTaskDependencies(&tasks[i])) > 0 {
+			continue
+		}
+		// Sub-tasks can only start while their parent is active
+		if parent := epicData.ParentTask(&tasks[i]); task.IsSubTask() && (parent == nil || parent.Status != epic.StatusWIP) {
+			continue
+		}
+		pendingTasks = append(pendingTasks, task)
+	}
+	return preferLeaves(epicData, pendingTasks)
+}
+
+// preferLeaves moves leaf tasks before tasks with sub-tasks, keeping the order
+// of the epic otherwise, so that agents pick up concrete work before starting
+// the parent of a decomposed task
+func preferLeaves(epicData *epic.Epic, tasks []epic.Task) []epic.Task {
+	var leaves, parents []epic.Task
 	for _, task := range tasks {
-		if task.Status == epic.StatusPending {
-			pendingTasks = append(pendingTasks, task)
+		if len(epicData.SubTasks(task.ID)) > 0 {
+			parents = append(parents, task)
+		} else {
+			leaves = append(leaves, task)
 		}
 	}
-	return pendingTasks
+	return append(leaves, parents...)
 }
 
 // formatPhaseStartedXML creates XML output for phase started with task selection
-func (s *AutoNextService) formatPhaseStartedXML(epicID string, phase *epic.Phase, tasks []epic.Task, startedTaskID string, timestamp time.Time) string {
+func (s *AutoNextService) formatPhaseStartedXML(epicID string, phase *epic.Phase, tasks []epic.Task, startedTaskID, assignee string, timestamp time.Time) string {
 	xml := fmt.Sprintf(`<phase_started epic="%s" phase="%s">
     <phase_name>%s</phase_name>
     <previous_status>pending</previous_status>
@@ -244,9 +320,14 @@ func (s *AutoNextService) formatPhaseStartedXML(epicID string, phase *epic.Phase
 
 	xml += fmt.Sprintf(`
     </tasks>
-    <started_task>%s</started_task>
+    <started_task>%s</started_task>`, startedTaskID)
+	if assignee != "" {
+		xml += fmt.Sprintf(`
+    <assignee>%s</assignee>`, assignee)
+	}
+	xml += fmt.Sprintf(`
     <message>Started Phase %s and Task %s (auto-selected)</message>
-</phase_started>`, startedTaskID, phase.ID, startedTaskID)
+</phase_started>`, phase.ID, startedTaskID)
 
 	return xml
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/mindreframer/agentpm/internal/epic"
 	"github.com/mindreframer/agentpm/internal/phases"
+	"github.com/mindreframer/agentpm/internal/planner"
 	"github.com/mindreframer/agentpm/internal/query"
 	"github.com/mindreframer/agentpm/internal/storage"
 	"github.com/mindreframer/agentpm/internal/tasks"
@@ -429,6 +430,73 @@ func findPhaseByID(epicData *epic.Epic, phaseID string) *epic.Phase {
 	return nil
 }
 
+func TestAutoNextService_StallAnalysis(t *testing.T) {
+	storage := storage.NewMemoryStorage()
+	queryService := query.NewQueryService(storage)
+	phaseService := phases.NewPhaseService(storage, queryService)
+	taskService := tasks.NewTaskService(storage, queryService)
+	autoNextService := NewAutoNextService(storage, queryService, phaseService, taskService)
+	testTime := time.Date(2025, 8, 16, 15, 30, 0, 0, time.UTC)
+
+	newEpic := func() *epic.Epic {
+		return &epic.Epic{
+			ID:     "epic-1",
+			Status: epic.StatusWIP,
+			Phases: []epic.Phase{
+				{ID: "phase-1", Name: "Phase 1", Status: epic.StatusWIP},
+				{ID: "phase-2", Name: "Phase 2", Status: epic.StatusPending},
+			},
+			Tasks: []epic.Task{
+				{ID: "task-1", PhaseID: "phase-1", Name: "Task 1", Status: epic.StatusCompleted},
+				{ID: "task-2", PhaseID: "phase-2", Name: "Task 2", Status: epic.StatusPending},
+			},
+			Tests: []epic.Test{
+				{ID: "test-1", PhaseID: "phase-1", TaskID: "task-1", Status: epic.StatusWIP, TestStatus: epic.TestStatusWIP},
+			},
+		}
+	}
+
+	t.Run("tests blocking the active phase are listed", func(t *testing.T) {
+		epicData := newEpic()
+
+		result, err := autoNextService.SelectNext(epicData, testTime)
+		require.NoError(t, err)
+
+		assert.Equal(t, ActionNoWork, result.Action)
+		require.NotNil(t, result.Stall)
+		assert.Equal(t, planner.StallTestsBlocking, result.Stall.Reason)
+		assert.Equal(t, "All tasks of phase phase-1 are done, but test test-1 (wip) is not passed", result.Message)
+		assert.Equal(t, "agentpm pass test-1", result.Stall.Suggestion)
+		assert.Equal(t, epic.StatusWIP, epicData.Phases[0].Status, "the phase stays open")
+	})
+
+	t.Run("upstream tests keep the next phase from starting", func(t *testing.T) {
+		epicData := newEpic()
+		epicData.Phases[0].Status = epic.StatusCompleted
+
+		result, err := autoNextService.SelectNext(epicData, testTime)
+		require.NoError(t, err)
+
+		require.NotNil(t, result.Stall)
+		assert.Equal(t, planner.StallDependenciesUnmet, result.Stall.Reason)
+		assert.Equal(t, "phase-2", result.PhaseID)
+		assert.Equal(t, epic.StatusPending, epicData.Phases[1].Status)
+	})
+
+	t.Run("done phases are still completed automatically", func(t *testing.T) {
+		epicData := newEpic()
+		epicData.Tests[0].Status = epic.StatusCompleted
+		epicData.Tests[0].TestStatus = epic.TestStatusDone
+
+		result, err := autoNextService.SelectNext(epicData, testTime)
+		require.NoError(t, err)
+
+		assert.Equal(t, ActionStartPhase, result.Action)
+		assert.Nil(t, result.Stall)
+		assert.Equal(t, epic.StatusCompleted, epicData.Phases[0].Status)
+	})
+}
+
 func TestAutoNextService_AutomaticEventCreation(t *testing.T) {
 	storage := storage.NewMemoryStorage()
 	queryService := query.NewQueryService(storage)
@@ -536,3 +604,38 @@ func findTaskByID(epicData *epic.Epic, taskID string) *epic.Task {
 	}
 	return nil
 }
+
+func TestAutoNextService_PrefersLeafTasks(t *testing.T) {
+	storage := storage.NewMemoryStorage()
+	queryService := query.NewQueryService(storage)
+	phaseService := phases.NewPhaseService(storage, queryService)
+	taskService := tasks.NewTaskService(storage, queryService)
+	autoNextService := NewAutoNextService(storage, queryService, phaseService, taskService)
+	testTime := time.Date(2025, 8, 16, 15, 30, 0, 0, time.UTC)
+
+	epicData := &epic.Epic{
+		ID:     "epic-1",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{
+			{ID: "phase-1", Name: "Phase 1", Status: epic.StatusWIP},
+		},
+		Tasks: []epic.Task{
+			{ID: "task-1", PhaseID: "phase-1", Name: "Parent", Status: epic.StatusPending},
+			{ID: "task-1a", PhaseID: "phase-1", Name: "Child", Status: epic.StatusPending, ParentTaskID: "task-1"},
+			{ID: "task-2", PhaseID: "phase-1", Name: "Leaf", Status: epic.StatusPending},
+		},
+	}
+
+	result, err := autoNextService.SelectNext(epicData, testTime)
+	require.NoError(t, err)
+	assert.Equal(t, "task-2", result.TaskID, "a leaf task is preferred over the parent of sub-tasks")
+
+	findTaskByID(epicData, "task-2").Status = epic.StatusCompleted
+	result, err = autoNextService.SelectNext(epicData, testTime)
+	require.NoError(t, err)
+	assert.Equal(t, "task-1", result.TaskID, "the parent starts once no leaf task is left")
+
+	result, err = autoNextService.SelectNext(epicData, testTime)
+	require.NoError(t, err)
+	assert.Equal(t, "task-1a", result.TaskID, "then its sub-tasks")
+}
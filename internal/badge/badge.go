@@ -0,0 +1,94 @@
+package badge
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+)
+
+// Colors of the shields.io palette
+const (
+	ColorBrightGreen = "#4c1"
+	ColorGreen       = "#97ca00"
+	ColorYellow      = "#dfb317"
+	ColorOrange      = "#fe7d37"
+	ColorRed         = "#e05d44"
+	ColorGrey        = "#9f9f9f"
+)
+
+// Badge is a two-part status badge: a grey label and a colored message
+type Badge struct {
+	Label   string
+	Message string
+	Color   string
+}
+
+// ProgressColor picks a color for a completion ratio from red (nothing done) to
+// bright green (all done). A zero total is grey.
+func ProgressColor(done, total int) string {
+	if total == 0 {
+		return ColorGrey
+	}
+	switch percent := done * 100 / total; {
+	case percent >= 100:
+		return ColorBrightGreen
+	case percent >= 75:
+		return ColorGreen
+	case percent >= 50:
+		return ColorYellow
+	case percent >= 25:
+		return ColorOrange
+	default:
+		return ColorRed
+	}
+}
+
+// textWidth approximates the rendered width of text in 11px Verdana
+func textWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		switch {
+		case r == ' ' || r == 'i' || r == 'l' || r == '.' || r == ':' || r == '|' || r == '!':
+			width += 4
+		case r == 'm' || r == 'w' || r == 'M' || r == 'W' || r == '%':
+			width += 10
+		case r >= 'A' && r <= 'Z':
+			width += 8
+		default:
+			width += 7
+		}
+	}
+	return width
+}
+
+// SVG renders the badge in the flat shields.io style
+func (b Badge) SVG() []byte {
+	labelWidth := textWidth(b.Label) + 10
+	messageWidth := textWidth(b.Message) + 10
+	width := labelWidth + messageWidth
+	title := escape(b.Label + ": " + b.Message)
+	label, message := escape(b.Label), escape(b.Message)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s">`, width, title)
+	fmt.Fprintf(&buf, `<title>%s</title>`, title)
+	buf.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+	fmt.Fprintf(&buf, `<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`, width)
+	fmt.Fprintf(&buf, `<g clip-path="url(#r)"><rect width="%d" height="20" fill="#555"/><rect x="%d" width="%d" height="20" fill="%s"/><rect width="%d" height="20" fill="url(#s)"/></g>`,
+		labelWidth, labelWidth, messageWidth, escape(b.Color), width)
+	buf.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`)
+	for _, part := range []struct {
+		x    int
+		text string
+	}{{labelWidth / 2, label}, {labelWidth + messageWidth/2, message}} {
+		fmt.Fprintf(&buf, `<text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%d" y="14">%s</text>`, part.x, part.text, part.x, part.text)
+	}
+	buf.WriteString("</g></svg>\n")
+	return buf.Bytes()
+}
+
+func escape(text string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(text))
+	return buf.String()
+}
@@ -0,0 +1,48 @@
+package badge
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressColor(t *testing.T) {
+	assert.Equal(t, ColorGrey, ProgressColor(0, 0))
+	assert.Equal(t, ColorRed, ProgressColor(1, 5))
+	assert.Equal(t, ColorOrange, ProgressColor(1, 4))
+	assert.Equal(t, ColorYellow, ProgressColor(14, 27))
+	assert.Equal(t, ColorGreen, ProgressColor(14, 17))
+	assert.Equal(t, ColorBrightGreen, ProgressColor(17, 17))
+}
+
+func TestBadge_SVG(t *testing.T) {
+	svg := string(Badge{Label: "epic 8", Message: "72%", Color: ColorGreen}.SVG())
+
+	var doc struct {
+		XMLName xml.Name `xml:"svg"`
+		Width   int      `xml:"width,attr"`
+		Title   string   `xml:"title"`
+	}
+	require.NoError(t, xml.Unmarshal([]byte(svg), &doc), "badge must be well-formed XML")
+	assert.Equal(t, "epic 8: 72%", doc.Title)
+	assert.Contains(t, svg, `fill="#97ca00"`)
+	assert.Equal(t, 2, strings.Count(svg, ">epic 8</text>"))
+
+	longer := Badge{Label: "epic 8", Message: "100%", Color: ColorGreen}.SVG()
+	var longerDoc struct {
+		Width int `xml:"width,attr"`
+	}
+	require.NoError(t, xml.Unmarshal(longer, &longerDoc))
+	assert.Greater(t, longerDoc.Width, doc.Width)
+}
+
+func TestBadge_SVGEscapesText(t *testing.T) {
+	svg := string(Badge{Label: "a<b>&c", Message: `"x"`, Color: ColorGrey}.SVG())
+
+	assert.Contains(t, svg, "a&lt;b&gt;&amp;c")
+	assert.NotContains(t, svg, "a<b>")
+	require.NoError(t, xml.Unmarshal([]byte(svg), new(struct{})))
+}
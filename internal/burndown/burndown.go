@@ -0,0 +1,225 @@
+package burndown
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/service"
+)
+
+// Interval is the width of one bucket of the time series
+type Interval string
+
+const (
+	IntervalDay  Interval = "day"
+	IntervalWeek Interval = "week"
+)
+
+// ParseInterval validates an interval name
+func ParseInterval(value string) (Interval, error) {
+	switch Interval(value) {
+	case IntervalDay, IntervalWeek:
+		return Interval(value), nil
+	default:
+		return "", fmt.Errorf("invalid interval: %s (expected day or week)", value)
+	}
+}
+
+// Point is the remaining work at the end of one interval
+type Point struct {
+	Date           string `json:"date"`
+	RemainingTasks int    `json:"remaining_tasks"`
+	RemainingTests int    `json:"remaining_tests"`
+}
+
+// Series is the burn-down of an epic from its start until now
+type Series struct {
+	EpicID     string   `json:"epic_id"`
+	EpicName   string   `json:"epic_name"`
+	Interval   Interval `json:"interval"`
+	TotalTasks int      `json:"total_tasks"`
+	TotalTests int      `json:"total_tests"`
+	Points     []Point  `json:"points"`
+}
+
+// change marks a task or test as done or not done again at a point in time
+type change struct {
+	at   time.Time
+	key  string
+	done bool
+}
+
+// Build reconstructs the remaining tasks and tests per interval by replaying the
+// epic's event history. Scope is the current set of tasks and tests; completed or
+// cancelled work without a matching event counts from its completion timestamp, or
+// from now when it has none.
+func Build(e *epic.Epic, interval Interval, now time.Time) *Series {
+	series := &Series{
+		EpicID:     e.ID,
+		EpicName:   e.Name,
+		Interval:   interval,
+		TotalTasks: len(e.Tasks),
+		TotalTests: len(e.Tests),
+	}
+
+	changes, seen := eventChanges(e)
+	changes = append(changes, untrackedChanges(e, seen, now)...)
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].at.Before(changes[j].at) })
+
+	start := seriesStart(e, changes, now)
+	done := make(map[string]bool)
+	remaining := func(prefix string, total int) int {
+		count := 0
+		for key, isDone := range done {
+			if isDone && strings.HasPrefix(key, prefix) {
+				count++
+			}
+		}
+		return total - count
+	}
+
+	next := 0
+	for bucket := interval.Start(start); !bucket.After(now); bucket = interval.Next(bucket) {
+		end := interval.Next(bucket)
+		for next < len(changes) && changes[next].at.Before(end) {
+			done[changes[next].key] = changes[next].done
+			next++
+		}
+		series.Points = append(series.Points, Point{
+			Date:           bucket.Format(time.DateOnly),
+			RemainingTasks: remaining("task:", series.TotalTasks),
+			RemainingTests: remaining("test:", series.TotalTests),
+		})
+	}
+
+	return series
+}
+
+// eventChanges turns task and test events into changes, for entities still in the
+// epic. It also returns which entities had at least one such event.
+func eventChanges(e *epic.Epic) ([]change, map[string]bool) {
+	known := make(map[string]bool)
+	for _, task := range e.Tasks {
+		known["task:"+task.ID] = true
+	}
+	for _, test := range e.Tests {
+		known["test:"+test.ID] = true
+	}
+
+	var changes []change
+	seen := make(map[string]bool)
+	for _, event := range e.Events {
+		var done bool
+		switch service.EventType(event.Type) {
+		case service.EventTaskCompleted, service.EventTaskCancelled,
+			service.EventTestPassed, service.EventTestCancelled:
+			done = true
+		case service.EventTaskReset, service.EventTaskReopened, service.EventTestFailed, service.EventTestReset:
+			done = false
+		default:
+			continue
+		}
+
+		entityType, _, _ := strings.Cut(event.Type, "_")
+		key := entityType + ":" + service.EventEntityID(event)
+		if !known[key] {
+			continue
+		}
+		seen[key] = true
+		changes = append(changes, change{at: event.Timestamp, key: key, done: done})
+	}
+	return changes, seen
+}
+
+// untrackedChanges covers finished work that has no events, e.g. from epics
+// edited by hand or created before events were recorded
+func untrackedChanges(e *epic.Epic, seen map[string]bool, now time.Time) []change {
+	var changes []change
+	add := func(key string, timestamps ...*time.Time) {
+		if seen[key] {
+			return
+		}
+		at := now
+		for _, ts := range timestamps {
+			if ts != nil {
+				at = *ts
+				break
+			}
+		}
+		changes = append(changes, change{at: at, key: key, done: true})
+	}
+
+	for _, task := range e.Tasks {
+		if task.Status == epic.StatusCompleted || task.Status == epic.StatusCancelled {
+			add("task:"+task.ID, task.CompletedAt, task.CancelledAt)
+		}
+	}
+	for _, test := range e.Tests {
+		status := test.GetTestStatusUnified()
+		if status == epic.TestStatusDone || status == epic.TestStatusCancelled {
+			add("test:"+test.ID, test.PassedAt, test.CancelledAt)
+		}
+	}
+	return changes
+}
+
+// seriesStart is the epic's creation time, or its first change if that is earlier
+// or the creation time is unknown
+func seriesStart(e *epic.Epic, changes []change, now time.Time) time.Time {
+	start := now
+	if !e.CreatedAt.IsZero() && e.CreatedAt.Before(start) {
+		start = e.CreatedAt
+	}
+	for _, event := range e.Events {
+		if event.Timestamp.Before(start) {
+			start = event.Timestamp
+		}
+	}
+	if len(changes) > 0 && changes[0].at.Before(start) {
+		start = changes[0].at
+	}
+	return start
+}
+
+// Start returns the start of the interval containing t, in UTC. Weeks start on
+// Monday.
+func (interval Interval) Start(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if interval == IntervalWeek {
+		offset := (int(day.Weekday()) + 6) % 7
+		day = day.AddDate(0, 0, -offset)
+	}
+	return day
+}
+
+// Next returns the start of the interval following the one starting at t
+func (interval Interval) Next(t time.Time) time.Time {
+	if interval == IntervalWeek {
+		return t.AddDate(0, 0, 7)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+var sparkChars = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as a row of block characters scaled to the largest value
+func Sparkline(values []int) string {
+	maxValue := 0
+	for _, value := range values {
+		maxValue = max(maxValue, value)
+	}
+
+	var b strings.Builder
+	for _, value := range values {
+		index := 0
+		if maxValue > 0 {
+			index = value * (len(sparkChars) - 1) / maxValue
+		}
+		b.WriteRune(sparkChars[index])
+	}
+	return b.String()
+}
@@ -0,0 +1,128 @@
+package burndown
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func day(d, hour int) time.Time {
+	return time.Date(2025, 8, d, hour, 0, 0, 0, time.UTC)
+}
+
+func createEpicWithHistory() *epic.Epic {
+	e := &epic.Epic{
+		ID:        "epic-1",
+		Name:      "Burn Epic",
+		CreatedAt: day(11, 9), // Monday
+		Phases:    []epic.Phase{{ID: "P1", Name: "Phase 1"}},
+		Tasks: []epic.Task{
+			{ID: "T1", PhaseID: "P1", Name: "Task 1", Status: epic.StatusCompleted},
+			{ID: "T2", PhaseID: "P1", Name: "Task 2", Status: epic.StatusCancelled},
+			{ID: "T3", PhaseID: "P1", Name: "Task 3", Status: epic.StatusPending},
+		},
+		Tests: []epic.Test{
+			{ID: "T1_1", TaskID: "T1", PhaseID: "P1", Name: "Test 1", TestStatus: epic.TestStatusDone},
+			{ID: "T1_2", TaskID: "T1", PhaseID: "P1", Name: "Test 2", TestStatus: epic.TestStatusWIP},
+		},
+	}
+
+	service.CreateEvent(e, service.EventTestPassed, "P1", "T1", "T1_1", "", day(12, 10))
+	service.CreateEvent(e, service.EventTaskCompleted, "P1", "T1", "", "", day(12, 11))
+	service.CreateEvent(e, service.EventTestPassed, "P1", "T1", "T1_2", "", day(13, 10))
+	service.CreateEvent(e, service.EventTestFailed, "P1", "T1", "T1_2", "regression", day(14, 10))
+	service.CreateEvent(e, service.EventTaskCancelled, "P1", "T2", "", "", day(14, 12))
+	return e
+}
+
+func TestParseInterval(t *testing.T) {
+	interval, err := ParseInterval("week")
+	require.NoError(t, err)
+	assert.Equal(t, IntervalWeek, interval)
+
+	_, err = ParseInterval("month")
+	assert.EqualError(t, err, "invalid interval: month (expected day or week)")
+}
+
+func TestBuild(t *testing.T) {
+	t.Run("replays events per day", func(t *testing.T) {
+		series := Build(createEpicWithHistory(), IntervalDay, day(15, 8))
+
+		assert.Equal(t, 3, series.TotalTasks)
+		assert.Equal(t, 2, series.TotalTests)
+		assert.Equal(t, []Point{
+			{Date: "2025-08-11", RemainingTasks: 3, RemainingTests: 2},
+			{Date: "2025-08-12", RemainingTasks: 2, RemainingTests: 1},
+			{Date: "2025-08-13", RemainingTasks: 2, RemainingTests: 0},
+			{Date: "2025-08-14", RemainingTasks: 1, RemainingTests: 1},
+			{Date: "2025-08-15", RemainingTasks: 1, RemainingTests: 1},
+		}, series.Points)
+	})
+
+	t.Run("groups weeks starting on Monday", func(t *testing.T) {
+		series := Build(createEpicWithHistory(), IntervalWeek, day(19, 8))
+
+		assert.Equal(t, []Point{
+			{Date: "2025-08-11", RemainingTasks: 1, RemainingTests: 1},
+			{Date: "2025-08-18", RemainingTasks: 1, RemainingTests: 1},
+		}, series.Points)
+	})
+
+	t.Run("resets make work remaining again", func(t *testing.T) {
+		e := createEpicWithHistory()
+		service.CreateEvent(e, service.EventTaskReset, "P1", "T1", "", "rework", day(15, 9))
+
+		series := Build(e, IntervalDay, day(15, 10))
+		assert.Equal(t, 2, series.Points[len(series.Points)-1].RemainingTasks)
+	})
+
+	t.Run("reopened tasks are remaining again", func(t *testing.T) {
+		e := &epic.Epic{
+			ID:        "epic-2",
+			CreatedAt: day(11, 9),
+			Phases:    []epic.Phase{{ID: "P1", Name: "Phase 1", Status: epic.StatusWIP}},
+			Tasks: []epic.Task{
+				{ID: "T1", PhaseID: "P1", Name: "Task 1", Status: epic.StatusCompleted},
+				{ID: "T2", PhaseID: "P1", Name: "Task 2", Status: epic.StatusPending},
+			},
+		}
+		service.CreateEvent(e, service.EventTaskCompleted, "P1", "T1", "", "", day(12, 11))
+		_, err := service.ReopenTask(e, "T1", "missed a case", day(13, 9))
+		require.NoError(t, err)
+
+		series := Build(e, IntervalDay, day(13, 10))
+		assert.Equal(t, []Point{
+			{Date: "2025-08-11", RemainingTasks: 2, RemainingTests: 0},
+			{Date: "2025-08-12", RemainingTasks: 1, RemainingTests: 0},
+			{Date: "2025-08-13", RemainingTasks: 2, RemainingTests: 0},
+		}, series.Points)
+	})
+
+	t.Run("counts finished work without events at its timestamp", func(t *testing.T) {
+		e := createEpicWithHistory()
+		completedAt := day(13, 15)
+		e.Tasks[2].Status = epic.StatusCompleted
+		e.Tasks[2].CompletedAt = &completedAt
+
+		series := Build(e, IntervalDay, day(15, 8))
+		assert.Equal(t, 1, series.Points[2].RemainingTasks)
+		assert.Equal(t, 0, series.Points[4].RemainingTasks)
+	})
+
+	t.Run("epic without history has a single point", func(t *testing.T) {
+		e := &epic.Epic{ID: "epic-2", Tasks: []epic.Task{{ID: "T1", Status: epic.StatusPending}}}
+
+		series := Build(e, IntervalDay, day(15, 8))
+		assert.Equal(t, []Point{{Date: "2025-08-15", RemainingTasks: 1, RemainingTests: 0}}, series.Points)
+	})
+}
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "█▅▃▁", Sparkline([]int{7, 4, 2, 0}))
+	assert.Equal(t, "▁▁", Sparkline([]int{0, 0}))
+	assert.Equal(t, "", Sparkline(nil))
+}
@@ -2,9 +2,11 @@ package commands
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/epic"
 	"github.com/mindreframer/agentpm/internal/query"
 	"github.com/mindreframer/agentpm/internal/storage"
 	"github.com/mindreframer/agentpm/internal/tasks"
@@ -19,8 +21,10 @@ type CancelTaskRequest struct {
 }
 
 type CancelTaskResult struct {
-	TaskID string
-	Error  *TaskError
+	TaskID   string
+	Stranded []epic.StrandedTask // Pending tasks that can no longer start, see epic.StrandedTasks
+	Error    *TaskError
+	Warnings []Warning
 }
 
 func CancelTaskService(request CancelTaskRequest) (*CancelTaskResult, error) {
@@ -40,7 +44,7 @@ func CancelTaskService(request CancelTaskRequest) (*CancelTaskResult, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to load configuration: %w", err)
 		}
-		epicFile = cfg.CurrentEpic
+		epicFile = cfg.EpicFilePath()
 	}
 
 	if epicFile == "" {
@@ -88,6 +92,12 @@ func CancelTaskService(request CancelTaskRequest) (*CancelTaskResult, error) {
 				},
 			}, nil
 		}
+		if subTaskErr, ok := err.(*tasks.TaskSubTaskError); ok {
+			return &CancelTaskResult{
+				TaskID: request.TaskID,
+				Error:  openSubTasksError("cancel", subTaskErr),
+			}, nil
+		}
 
 		return nil, fmt.Errorf("failed to cancel task: %w", err)
 	}
@@ -99,6 +109,22 @@ func CancelTaskService(request CancelTaskRequest) (*CancelTaskResult, error) {
 	}
 
 	return &CancelTaskResult{
-		TaskID: request.TaskID,
+		TaskID:   request.TaskID,
+		Stranded: epicData.StrandedTasks([]string{request.TaskID}),
+		Warnings: DetectWarnings(epicData, "", request.TaskID, timestamp),
 	}, nil
 }
+
+// openSubTasksError describes a task that cannot be completed or cancelled
+// while it has open sub-tasks
+func openSubTasksError(operation string, err *tasks.TaskSubTaskError) *TaskError {
+	return &TaskError{
+		Type:    "task_subtasks_open",
+		Message: fmt.Sprintf("Cannot %s task %s: sub-tasks %s are not completed or cancelled", operation, err.TaskID, strings.Join(err.OpenSubTasks, ", ")),
+		Details: map[string]any{
+			"task_id":        err.TaskID,
+			"open_sub_tasks": err.OpenSubTasks,
+		},
+		Hint: err.Hint,
+	}
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// describeDependencies lists unmet dependencies for an error message:
+// "phases 1 (wip), 2 (not found)"
+func describeDependencies(entityType string, unmet []epic.Dependency) string {
+	items := make([]string, len(unmet))
+	for i, dependency := range unmet {
+		state := string(dependency.Status)
+		if !dependency.Found() {
+			state = "not found"
+		}
+		items[i] = fmt.Sprintf("%s (%s)", dependency.ID, state)
+	}
+	if len(unmet) == 1 {
+		return fmt.Sprintf("%s %s, which is not completed", entityType, items[0])
+	}
+	return fmt.Sprintf("%ss %s, which are not completed", entityType, strings.Join(items, ", "))
+}
+
+// dependencyHint returns the commands that complete the first unmet dependency
+func dependencyHint(entityType string, unmet []epic.Dependency) string {
+	first := unmet[0]
+	switch first.Status {
+	case "":
+		return fmt.Sprintf("Fix the depends_on attribute: %s %s does not exist", entityType, first.ID)
+	case epic.StatusWIP:
+		return fmt.Sprintf("Complete %s %s first: agentpm done %s %s", entityType, first.ID, entityType, first.ID)
+	case epic.StatusCancelled:
+		return fmt.Sprintf("%s %s was cancelled; drop it from depends_on", strings.ToUpper(entityType[:1])+entityType[1:], first.ID)
+	default:
+		return fmt.Sprintf("Complete %s %s first: agentpm start %s %s, then agentpm done %s %s",
+			entityType, first.ID, entityType, first.ID, entityType, first.ID)
+	}
+}
+
+func dependencyIDs(unmet []epic.Dependency) []string {
+	ids := make([]string, len(unmet))
+	for i, dependency := range unmet {
+		ids[i] = dependency.ID
+	}
+	return ids
+}
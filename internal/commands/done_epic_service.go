@@ -2,9 +2,14 @@ package commands
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/mindreframer/agentpm/internal/completion"
 	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/exitcode"
 	"github.com/mindreframer/agentpm/internal/lifecycle"
 	"github.com/mindreframer/agentpm/internal/messages"
 	"github.com/mindreframer/agentpm/internal/query"
@@ -16,10 +21,12 @@ type DoneEpicRequest struct {
 	EpicFile   string
 	Time       string
 	Format     string
+	Report     string // Path to write the final report to, empty for none
 }
 
 type DoneEpicResult struct {
 	Result             *lifecycle.DoneEpicResult
+	ReportPath         string // Where the final report was written, if requested
 	Message            *messages.Message
 	IsAlreadyCompleted bool
 	Error              *EpicError
@@ -126,11 +133,44 @@ func DoneEpicService(request DoneEpicRequest) (*DoneEpicResult, error) {
 		return nil, err
 	}
 
+	// A dry run saves nothing, so there is no completed epic to report on
+	if request.Report != "" && !storage.DryRun() {
+		if err := WriteCompletionReport(storageImpl, epicFile, result, request.Report); err != nil {
+			return nil, err
+		}
+	}
+
 	return &DoneEpicResult{
-		Result: result,
+		Result:     result,
+		ReportPath: request.Report,
 	}, nil
 }
 
+// WriteCompletionReport writes the final report of the epic just completed,
+// as HTML when path ends in .html or .htm and as Markdown otherwise
+func WriteCompletionReport(storageImpl storage.Storage, epicFile string, result *lifecycle.DoneEpicResult, path string) error {
+	completed, err := storageImpl.LoadEpic(epicFile)
+	if err != nil {
+		return fmt.Errorf("epic completed, but failed to load it for the report: %w", err)
+	}
+	report := completion.Build(completed, result.Validation, result.CompletedAt)
+
+	var content string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm":
+		content, err = completion.HTML(report)
+		if err != nil {
+			return err
+		}
+	default:
+		content = completion.Markdown(report)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return exitcode.Errorf(exitcode.Storage, "epic completed, but failed to write the report: %w", err)
+	}
+	return nil
+}
+
 func convertPendingPhasesToDetails(phases []lifecycle.PendingPhase) []map[string]string {
 	result := make([]map[string]string, len(phases))
 	for i, phase := range phases {
@@ -8,6 +8,7 @@ import (
 	"github.com/mindreframer/agentpm/internal/epic"
 	"github.com/mindreframer/agentpm/internal/messages"
 	"github.com/mindreframer/agentpm/internal/phases"
+	"github.com/mindreframer/agentpm/internal/policy"
 	"github.com/mindreframer/agentpm/internal/query"
 	"github.com/mindreframer/agentpm/internal/storage"
 )
@@ -25,6 +26,7 @@ type DonePhaseResult struct {
 	Message            *messages.Message
 	IsAlreadyCompleted bool
 	Error              *PhaseError
+	Warnings           []Warning
 }
 
 func DonePhaseService(request DonePhaseRequest) (*DonePhaseResult, error) {
@@ -44,7 +46,7 @@ func DonePhaseService(request DonePhaseRequest) (*DonePhaseResult, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to load configuration: %w", err)
 		}
-		epicFile = cfg.CurrentEpic
+		epicFile = cfg.EpicFilePath()
 	}
 
 	if epicFile == "" {
@@ -129,7 +131,8 @@ func DonePhaseService(request DonePhaseRequest) (*DonePhaseResult, error) {
 	}
 
 	return &DonePhaseResult{
-		PhaseID: request.PhaseID,
+		PhaseID:  request.PhaseID,
+		Warnings: DetectWarnings(epicData, policy.ActionDonePhase, request.PhaseID, timestamp),
 	}, nil
 }
 
@@ -7,6 +7,7 @@ import (
 	"github.com/mindreframer/agentpm/internal/config"
 	"github.com/mindreframer/agentpm/internal/epic"
 	"github.com/mindreframer/agentpm/internal/messages"
+	"github.com/mindreframer/agentpm/internal/policy"
 	"github.com/mindreframer/agentpm/internal/query"
 	"github.com/mindreframer/agentpm/internal/storage"
 	"github.com/mindreframer/agentpm/internal/tasks"
@@ -25,6 +26,21 @@ type DoneTaskResult struct {
 	Message            *messages.Message
 	IsAlreadyCompleted bool
 	Error              *TaskError
+	Warnings           []Warning
+}
+
+type DoneTasksRequest struct {
+	TaskIDs    []string
+	ConfigPath string
+	EpicFile   string
+	Time       string
+	Format     string
+}
+
+type DoneTasksResult struct {
+	Results  []*DoneTaskResult // One per task ID, in request order
+	Error    *TaskError
+	Warnings []Warning // Of the epic after all tasks are completed
 }
 
 func DoneTaskService(request DoneTaskRequest) (*DoneTaskResult, error) {
@@ -32,50 +48,134 @@ func DoneTaskService(request DoneTaskRequest) (*DoneTaskResult, error) {
 		return nil, fmt.Errorf("task ID is required")
 	}
 
+	epicFile, timestamp, err := resolveDoneTaskInputs(request.ConfigPath, request.EpicFile, request.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize services
+	storageImpl := storage.NewFileStorage()
+	queryService := query.NewQueryService(storageImpl)
+	taskService := tasks.NewTaskService(storageImpl, queryService)
+
+	// Load epic
+	epicData, err := storageImpl.LoadEpic(epicFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	result, err := completeTask(taskService, epicData, request.TaskID, timestamp)
+	if err != nil || result.Error != nil || result.IsAlreadyCompleted {
+		return result, err
+	}
+
+	// Save the updated epic
+	err = storageImpl.SaveEpic(epicData, epicFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save epic: %w", err)
+	}
+
+	result.Warnings = DetectWarnings(epicData, policy.ActionDoneTask, request.TaskID, timestamp)
+	return result, nil
+}
+
+// DoneTasksService completes several tasks in one load/save cycle. Tasks are
+// completed in request order; tasks that are already completed are reported
+// as such. If any task cannot be completed, the epic is not saved and the
+// result reports the error of every failing task.
+func DoneTasksService(request DoneTasksRequest) (*DoneTasksResult, error) {
+	if len(request.TaskIDs) == 0 {
+		return nil, fmt.Errorf("at least one task ID is required")
+	}
+
+	epicFile, timestamp, err := resolveDoneTaskInputs(request.ConfigPath, request.EpicFile, request.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	storageImpl := storage.NewFileStorage()
+	queryService := query.NewQueryService(storageImpl)
+	taskService := tasks.NewTaskService(storageImpl, queryService)
+
+	epicData, err := storageImpl.LoadEpic(epicFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load epic: %w", err)
+	}
+
+	batch := &DoneTasksResult{}
+	var failed []*DoneTaskResult
+	changed := false
+	for _, taskID := range request.TaskIDs {
+		result, err := completeTask(taskService, epicData, taskID, timestamp)
+		if err != nil {
+			result = &DoneTaskResult{
+				TaskID: taskID,
+				Error:  &TaskError{Type: "operation_failed", Message: err.Error()},
+			}
+		}
+		batch.Results = append(batch.Results, result)
+		switch {
+		case result.Error != nil:
+			failed = append(failed, result)
+		case !result.IsAlreadyCompleted:
+			changed = true
+		}
+	}
+
+	if len(failed) > 0 {
+		msg := fmt.Sprintf("Cannot complete %d of %d tasks, no task was changed:", len(failed), len(request.TaskIDs))
+		for _, result := range failed {
+			msg += fmt.Sprintf("\n- %s: %s", result.TaskID, result.Error.Message)
+		}
+		batch.Error = &TaskError{Type: "batch_operation_failed", Message: msg}
+		return batch, nil
+	}
+
+	if changed {
+		if err := storageImpl.SaveEpic(epicData, epicFile); err != nil {
+			return nil, fmt.Errorf("failed to save epic: %w", err)
+		}
+		for _, taskID := range request.TaskIDs {
+			batch.Warnings = append(batch.Warnings, DetectWarnings(epicData, policy.ActionDoneTask, taskID, timestamp)...)
+		}
+		batch.Warnings = uniqueWarnings(batch.Warnings)
+	}
+	return batch, nil
+}
+
+// resolveDoneTaskInputs determines the epic file and the completion timestamp of a request
+func resolveDoneTaskInputs(configPath, epicFile, timeValue string) (string, time.Time, error) {
 	// Get epic file path
-	epicFile := request.EpicFile
 	if epicFile == "" {
-		configPath := request.ConfigPath
 		if configPath == "" {
 			configPath = "./.agentpm.json"
 		}
 
 		cfg, err := config.LoadConfig(configPath)
 		if err != nil {
-			return nil, fmt.Errorf("failed to load configuration: %w", err)
+			return "", time.Time{}, fmt.Errorf("failed to load configuration: %w", err)
 		}
-		epicFile = cfg.CurrentEpic
+		epicFile = cfg.EpicFilePath()
 	}
 
 	if epicFile == "" {
-		return nil, fmt.Errorf("no epic file specified (use --file flag or set current epic)")
+		return "", time.Time{}, fmt.Errorf("no epic file specified (use --file flag or set current epic)")
 	}
 
 	// Parse timestamp if provided
-	var timestamp time.Time
-	if request.Time != "" {
-		var err error
-		timestamp, err = time.Parse(time.RFC3339, request.Time)
-		if err != nil {
-			return nil, fmt.Errorf("invalid time format: %s (use ISO 8601 format like 2025-08-16T15:30:00Z)", request.Time)
-		}
-	} else {
-		timestamp = time.Now()
+	if timeValue == "" {
+		return epicFile, time.Now(), nil
 	}
-
-	// Initialize services
-	storageImpl := storage.NewFileStorage()
-	queryService := query.NewQueryService(storageImpl)
-	taskService := tasks.NewTaskService(storageImpl, queryService)
-
-	// Load epic
-	epicData, err := storageImpl.LoadEpic(epicFile)
+	timestamp, err := time.Parse(time.RFC3339, timeValue)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load epic: %w", err)
+		return "", time.Time{}, fmt.Errorf("invalid time format: %s (use ISO 8601 format like 2025-08-16T15:30:00Z)", timeValue)
 	}
+	return epicFile, timestamp, nil
+}
 
-	// Complete the task
-	err = taskService.CompleteTask(epicData, request.TaskID, timestamp)
+// completeTask completes a task of the loaded epic without saving it
+func completeTask(taskService *tasks.TaskService, epicData *epic.Epic, taskID string, timestamp time.Time) (*DoneTaskResult, error) {
+	err := taskService.CompleteTask(epicData, taskID, timestamp)
 	if err != nil {
 		// Handle different error types for better error output
 		if stateErr, ok := err.(*tasks.TaskStateError); ok {
@@ -83,41 +183,54 @@ func DoneTaskService(request DoneTaskRequest) (*DoneTaskResult, error) {
 			if stateErr.CurrentStatus == epic.StatusCompleted {
 				// Task is already completed - return friendly success message
 				templates := messages.NewMessageTemplates()
-				message := templates.TaskAlreadyCompleted(request.TaskID)
+				message := templates.TaskAlreadyCompleted(taskID)
 				return &DoneTaskResult{
-					TaskID:             request.TaskID,
+					TaskID:             taskID,
 					Message:            message,
 					IsAlreadyCompleted: true,
 				}, nil
 			}
 			return &DoneTaskResult{
-				TaskID: request.TaskID,
+				TaskID: taskID,
 				Error: &TaskError{
 					Type:    "invalid_task_state",
-					Message: fmt.Sprintf("Cannot complete task %s: %s", request.TaskID, stateErr.Message),
+					Message: fmt.Sprintf("Cannot complete task %s: %s", taskID, stateErr.Message),
 					Details: map[string]any{
-						"task_id":        request.TaskID,
+						"task_id":        taskID,
 						"current_status": string(stateErr.CurrentStatus),
 						"target_status":  string(stateErr.TargetStatus),
 					},
 				},
 			}, nil
 		}
+		if subTaskErr, ok := err.(*tasks.TaskSubTaskError); ok {
+			return &DoneTaskResult{
+				TaskID: taskID,
+				Error:  openSubTasksError("complete", subTaskErr),
+			}, nil
+		}
+		if validationErr, ok := err.(*epic.StatusValidationError); ok {
+			return &DoneTaskResult{
+				TaskID: taskID,
+				Error: &TaskError{
+					Type:    "strict_validation",
+					Message: validationErr.Message,
+					Details: map[string]any{
+						"task_id":        taskID,
+						"blocking_items": validationErr.BlockingItems,
+					},
+				},
+			}, nil
+		}
 
 		return nil, fmt.Errorf("failed to complete task: %w", err)
 	}
 
 	// Update current_state after completing task (Epic 7)
-	updateCurrentStateAfterTaskComplete(epicData, request.TaskID)
-
-	// Save the updated epic
-	err = storageImpl.SaveEpic(epicData, epicFile)
-	if err != nil {
-		return nil, fmt.Errorf("failed to save epic: %w", err)
-	}
+	updateCurrentStateAfterTaskComplete(epicData, taskID)
 
 	return &DoneTaskResult{
-		TaskID: request.TaskID,
+		TaskID: taskID,
 	}, nil
 }
 
@@ -133,6 +246,17 @@ func updateCurrentStateAfterTaskComplete(epicData *epic.Epic, taskID string) {
 		epicData.CurrentState.ActiveTask = ""
 	}
 
+	// Work continues on a task that is still active: the parent of a completed sub-task
+	if active := epicData.ActiveTask(""); active != nil && epicData.CurrentState.ActiveTask == "" {
+		epicData.CurrentState.ActiveTask = active.ID
+		if open := epicData.OpenSubTasks(active.ID); len(open) > 0 {
+			epicData.CurrentState.NextAction = fmt.Sprintf("Start next sub-task: %s", open[0])
+		} else {
+			epicData.CurrentState.NextAction = fmt.Sprintf("Complete task: %s", active.Name)
+		}
+		return
+	}
+
 	// Find next action based on remaining work in the current phase
 	phaseID := epicData.CurrentState.ActivePhase
 	if phaseID != "" {
@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
+	"slices"
 
 	"github.com/beevik/etree"
 	"github.com/mindreframer/agentpm/internal/messages"
@@ -31,6 +34,9 @@ type RouterContext struct {
 	EpicFile   string
 	Format     string
 	Time       string
+	Writer     io.Writer
+
+	command *cli.Command // The command run, for recording its warnings
 }
 
 // ExtractRouterContext extracts common flags from a CLI command
@@ -40,6 +46,8 @@ func ExtractRouterContext(c *cli.Command) RouterContext {
 		EpicFile:   c.String("file"),
 		Format:     c.String("format"),
 		Time:       c.String("time"),
+		Writer:     c.Root().Writer,
+		command:    c,
 	}
 }
 
@@ -86,9 +94,9 @@ func OutputXML(c *cli.Command, data any) error {
 		root.SetText(v)
 	case map[string]any:
 		root := doc.CreateElement("result")
-		for key, value := range v {
+		for _, key := range slices.Sorted(maps.Keys(v)) {
 			elem := root.CreateElement(key)
-			elem.SetText(fmt.Sprintf("%v", value))
+			elem.SetText(fmt.Sprintf("%v", v[key]))
 		}
 	default:
 		root := doc.CreateElement("result")
@@ -232,8 +240,13 @@ func GlobalFlags() []cli.Flag {
 		&cli.StringFlag{
 			Name:    "format",
 			Aliases: []string{"F"},
-			Usage:   "Output format - text (default) / json / xml",
+			Usage:   "Output format - text (default) / json / xml / markdown (docs, handoff, status, show)",
 			Value:   "text",
 		},
+		&cli.BoolFlag{
+			Name:    "quiet",
+			Aliases: []string{"q"},
+			Usage:   "Print only a single result line for mutating commands",
+		},
 	}
 }
@@ -85,7 +85,7 @@ func TestGlobalFlags(t *testing.T) {
 	flags := GlobalFlags()
 
 	// Check that we have the expected number of flags
-	expectedFlags := 4 // file, config, time, format
+	expectedFlags := 5 // file, config, time, format, quiet
 	if len(flags) != expectedFlags {
 		t.Errorf("expected %d global flags, got %d", expectedFlags, len(flags))
 	}
@@ -96,7 +96,7 @@ func TestGlobalFlags(t *testing.T) {
 		flagNames[flag.Names()[0]] = true
 	}
 
-	requiredFlags := []string{"file", "config", "time", "format"}
+	requiredFlags := []string{"file", "config", "time", "format", "quiet"}
 	for _, requiredFlag := range requiredFlags {
 		if !flagNames[requiredFlag] {
 			t.Errorf("missing required global flag: %s", requiredFlag)
@@ -23,6 +23,7 @@ type StartEpicResult struct {
 	Message            *messages.Message
 	IsAlreadyStarted   bool
 	IsAlreadyCompleted bool
+	Warnings           []Warning
 }
 
 func StartEpicService(request StartEpicRequest) (*StartEpicResult, error) {
@@ -98,7 +99,8 @@ func StartEpicService(request StartEpicRequest) (*StartEpicResult, error) {
 	message := templates.EpicStarted(result.EpicID)
 
 	return &StartEpicResult{
-		Result:  result,
-		Message: message,
+		Result:   result,
+		Message:  message,
+		Warnings: detectFileWarnings(epicFile, "", "", requestTime(request.Time)),
 	}, nil
 }
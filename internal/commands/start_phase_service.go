@@ -8,6 +8,7 @@ import (
 	"github.com/mindreframer/agentpm/internal/hints"
 	"github.com/mindreframer/agentpm/internal/messages"
 	"github.com/mindreframer/agentpm/internal/phases"
+	"github.com/mindreframer/agentpm/internal/policy"
 	"github.com/mindreframer/agentpm/internal/query"
 	"github.com/mindreframer/agentpm/internal/storage"
 )
@@ -25,6 +26,7 @@ type StartPhaseResult struct {
 	Message         *messages.Message
 	IsAlreadyActive bool
 	Error           *PhaseError
+	Warnings        []Warning
 }
 
 type PhaseError struct {
@@ -51,7 +53,7 @@ func StartPhaseService(request StartPhaseRequest) (*StartPhaseResult, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to load configuration: %w", err)
 		}
-		epicFile = cfg.CurrentEpic
+		epicFile = cfg.EpicFilePath()
 	}
 
 	if epicFile == "" {
@@ -130,6 +132,21 @@ func StartPhaseService(request StartPhaseRequest) (*StartPhaseResult, error) {
 			}, nil
 		}
 
+		if depErr, ok := err.(*phases.PhaseDependencyError); ok {
+			return &StartPhaseResult{
+				PhaseID: request.PhaseID,
+				Error: &PhaseError{
+					Type:    "phase_dependency_unmet",
+					Message: fmt.Sprintf("Cannot start phase %s: depends on %s", request.PhaseID, describeDependencies("phase", depErr.Unmet)),
+					Details: map[string]any{
+						"phase_id":     request.PhaseID,
+						"dependencies": dependencyIDs(depErr.Unmet),
+					},
+					Hint: dependencyHint("phase", depErr.Unmet),
+				},
+			}, nil
+		}
+
 		if stateErr, ok := err.(*phases.PhaseStateError); ok {
 			// Generate context-aware hint for phase state errors
 			hintCtx := &hints.HintContext{
@@ -178,6 +195,7 @@ func StartPhaseService(request StartPhaseRequest) (*StartPhaseResult, error) {
 	}
 
 	return &StartPhaseResult{
-		PhaseID: request.PhaseID,
+		PhaseID:  request.PhaseID,
+		Warnings: DetectWarnings(epicData, policy.ActionStartPhase, request.PhaseID, timestamp),
 	}, nil
 }
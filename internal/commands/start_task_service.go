@@ -8,6 +8,7 @@ import (
 	"github.com/mindreframer/agentpm/internal/epic"
 	"github.com/mindreframer/agentpm/internal/hints"
 	"github.com/mindreframer/agentpm/internal/messages"
+	"github.com/mindreframer/agentpm/internal/policy"
 	"github.com/mindreframer/agentpm/internal/query"
 	"github.com/mindreframer/agentpm/internal/storage"
 	"github.com/mindreframer/agentpm/internal/tasks"
@@ -26,6 +27,7 @@ type StartTaskResult struct {
 	Message         *messages.Message
 	IsAlreadyActive bool
 	Error           *TaskError
+	Warnings        []Warning
 }
 
 type TaskError struct {
@@ -52,7 +54,7 @@ func StartTaskService(request StartTaskRequest) (*StartTaskResult, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to load configuration: %w", err)
 		}
-		epicFile = cfg.CurrentEpic
+		epicFile = cfg.EpicFilePath()
 	}
 
 	if epicFile == "" {
@@ -134,6 +136,36 @@ func StartTaskService(request StartTaskRequest) (*StartTaskResult, error) {
 			}, nil
 		}
 
+		if depErr, ok := err.(*tasks.TaskDependencyError); ok {
+			return &StartTaskResult{
+				TaskID: request.TaskID,
+				Error: &TaskError{
+					Type:    "task_dependency_unmet",
+					Message: fmt.Sprintf("Cannot start task %s: depends on %s", request.TaskID, describeDependencies("task", depErr.Unmet)),
+					Details: map[string]any{
+						"task_id":      request.TaskID,
+						"dependencies": dependencyIDs(depErr.Unmet),
+					},
+					Hint: dependencyHint("task", depErr.Unmet),
+				},
+			}, nil
+		}
+
+		if subTaskErr, ok := err.(*tasks.TaskSubTaskError); ok {
+			return &StartTaskResult{
+				TaskID: request.TaskID,
+				Error: &TaskError{
+					Type:    "task_parent_inactive",
+					Message: fmt.Sprintf("Cannot start task %s: parent task %s is not active", request.TaskID, subTaskErr.ParentTaskID),
+					Details: map[string]any{
+						"task_id":        request.TaskID,
+						"parent_task_id": subTaskErr.ParentTaskID,
+					},
+					Hint: subTaskErr.Hint,
+				},
+			}, nil
+		}
+
 		if constraintErr, ok := err.(*tasks.TaskConstraintError); ok {
 			// Generate context-aware hint for task constraint violations
 			hintCtx := &hints.HintContext{
@@ -222,7 +254,8 @@ func StartTaskService(request StartTaskRequest) (*StartTaskResult, error) {
 	}
 
 	return &StartTaskResult{
-		TaskID: request.TaskID,
+		TaskID:   request.TaskID,
+		Warnings: DetectWarnings(epicData, policy.ActionStartTask, request.TaskID, timestamp),
 	}, nil
 }
 
@@ -13,7 +13,10 @@ import (
 type TestRequest struct {
 	TestID             string
 	FailureReason      string
+	FailureContext     *epic.FailureContext // For fail: expected and actual values, stack and links
 	CancellationReason string
+	VerifiedBy         string // For pass/fail: who checked the test, required for manual tests
+	Evidence           string // For pass/fail: link backing the verdict
 	ConfigPath         string
 	EpicFile           string
 	Time               string
@@ -22,9 +25,13 @@ type TestRequest struct {
 
 type BatchTestRequest struct {
 	TestIDs            []string
-	Operation          string // "pass", "fail", "cancel"
-	FailureReason      string // For fail operations
-	CancellationReason string // For cancel operations
+	Operation          string               // "pass", "fail", "cancel"
+	FailureReason      string               // For fail operations
+	FailureContext     *epic.FailureContext // For fail operations: expected and actual values, stack and links
+	CancellationReason string               // For cancel operations
+	TrailingReason     bool                 // For fail operations: the last of several IDs is the reason unless it names a test
+	VerifiedBy         string               // For pass/fail operations: who checked the tests, required for manual tests
+	Evidence           string               // For pass/fail operations: link backing the verdicts
 	ConfigPath         string
 	EpicFile           string
 	Time               string
@@ -32,19 +39,22 @@ type BatchTestRequest struct {
 }
 
 type TestResult struct {
-	Result *tests.TestOperation
-	Error  *TestError
+	Result   *tests.TestOperation
+	Error    *TestError
+	Warnings []Warning
 }
 
 type BatchTestResult struct {
-	Result *BatchSuccessReport
-	Error  *TestError
+	Result   *BatchSuccessReport
+	Error    *TestError
+	Warnings []Warning
 }
 
 type TestError struct {
 	Type    string
 	TestID  string
 	Message string
+	Hint    string
 }
 
 func StartTestService(request TestRequest) (*TestResult, error) {
@@ -82,6 +92,7 @@ func StartTestService(request TestRequest) (*TestResult, error) {
 					Type:    string(testErr.Type),
 					TestID:  testErr.TestID,
 					Message: testErr.Message,
+					Hint:    testErr.Hint,
 				},
 			}, nil
 		}
@@ -89,7 +100,8 @@ func StartTestService(request TestRequest) (*TestResult, error) {
 	}
 
 	return &TestResult{
-		Result: result,
+		Result:   result,
+		Warnings: detectFileWarnings(epicFile, "", request.TestID, requestTime(request.Time)),
 	}, nil
 }
 
@@ -167,7 +179,7 @@ func PassTestService(request TestRequest) (*TestResult, error) {
 	}
 
 	// Execute operation
-	result, err := service.PassTest(epicFile, request.TestID, timestamp)
+	result, err := service.PassTest(epicFile, request.TestID, tests.Verification{By: request.VerifiedBy, Evidence: request.Evidence}, timestamp)
 	if err != nil {
 		if testErr, ok := err.(*tests.TestError); ok {
 			return &TestResult{
@@ -175,6 +187,7 @@ func PassTestService(request TestRequest) (*TestResult, error) {
 					Type:    string(testErr.Type),
 					TestID:  testErr.TestID,
 					Message: testErr.Message,
+					Hint:    testErr.Hint,
 				},
 			}, nil
 		}
@@ -182,7 +195,8 @@ func PassTestService(request TestRequest) (*TestResult, error) {
 	}
 
 	return &TestResult{
-		Result: result,
+		Result:   result,
+		Warnings: detectFileWarnings(epicFile, "", request.TestID, requestTime(request.Time)),
 	}, nil
 }
 
@@ -260,7 +274,7 @@ func FailTestService(request TestRequest) (*TestResult, error) {
 	}
 
 	// Execute operation
-	result, err := service.FailTest(epicFile, request.TestID, request.FailureReason, timestamp)
+	result, err := service.FailTest(epicFile, request.TestID, request.FailureReason, request.FailureContext, tests.Verification{By: request.VerifiedBy, Evidence: request.Evidence}, timestamp)
 	if err != nil {
 		if testErr, ok := err.(*tests.TestError); ok {
 			return &TestResult{
@@ -268,6 +282,7 @@ func FailTestService(request TestRequest) (*TestResult, error) {
 					Type:    string(testErr.Type),
 					TestID:  testErr.TestID,
 					Message: testErr.Message,
+					Hint:    testErr.Hint,
 				},
 			}, nil
 		}
@@ -275,7 +290,8 @@ func FailTestService(request TestRequest) (*TestResult, error) {
 	}
 
 	return &TestResult{
-		Result: result,
+		Result:   result,
+		Warnings: detectFileWarnings(epicFile, "", request.TestID, requestTime(request.Time)),
 	}, nil
 }
 
@@ -368,7 +384,8 @@ func CancelTestService(request TestRequest) (*TestResult, error) {
 	}
 
 	return &TestResult{
-		Result: result,
+		Result:   result,
+		Warnings: detectFileWarnings(epicFile, "", request.TestID, requestTime(request.Time)),
 	}, nil
 }
 
@@ -395,33 +412,60 @@ func getEpicFileFromRequest(request TestRequest) (string, error) {
 		return "", fmt.Errorf("no epic file specified. Use --file flag or run 'agentpm init' first")
 	}
 
-	return cfg.CurrentEpic, nil
+	return cfg.EpicFilePath(), nil
 }
 
 func PassBatchTestService(request BatchTestRequest) (*BatchTestResult, error) {
 	if len(request.TestIDs) == 0 {
 		return nil, fmt.Errorf("pass-batch requires at least one test ID")
 	}
+	return runBatchTestService(request, "pass")
+}
+
+func FailBatchTestService(request BatchTestRequest) (*BatchTestResult, error) {
+	if len(request.TestIDs) == 0 {
+		return nil, fmt.Errorf("fail-batch requires at least one test ID")
+	}
+	return runBatchTestService(request, "fail")
+}
 
+// runBatchTestService validates all operations of a batch against the epic
+// and, if every one of them is valid, applies them and saves the epic once
+func runBatchTestService(request BatchTestRequest, operationType string) (*BatchTestResult, error) {
 	// Load configuration and determine epic file
 	epicFile, err := getEpicFileFromBatchRequest(request)
 	if err != nil {
 		return nil, err
 	}
 
-	// Load epic for validation
+	// Parse timestamp if provided
+	var timestamp *time.Time
+	if request.Time != "" {
+		t, err := time.Parse(time.RFC3339, request.Time)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", request.Time)
+		}
+		timestamp = &t
+	}
+
 	storageService := storage.NewFileStorage()
 	epicData, err := storageService.LoadEpic(epicFile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load epic: %w", err)
 	}
 
+	testIDs, reason := request.TestIDs, request.FailureReason
+	if request.TrailingReason && len(testIDs) > 1 && findBatchTest(epicData, testIDs[len(testIDs)-1]) == nil {
+		testIDs, reason = testIDs[:len(testIDs)-1], testIDs[len(testIDs)-1]
+	}
+
 	// Create batch operations
 	var operations []BatchOperation
-	for _, testID := range request.TestIDs {
+	for _, testID := range testIDs {
 		operations = append(operations, BatchOperation{
 			TestID:        testID,
-			OperationType: "pass",
+			OperationType: operationType,
+			Reason:        reason,
 		})
 	}
 
@@ -432,47 +476,49 @@ func PassBatchTestService(request BatchTestRequest) (*BatchTestResult, error) {
 		return nil, err
 	}
 
-	// If validation failed, return error
+	// If validation failed, return error; a single operation reports its own error
 	if !validationResult.Valid {
+		message := validationResult.ErrorMessage
+		if len(operations) == 1 {
+			message = validationResult.InvalidOperations[0].Error.Message
+		}
+		errType := "batch_validation_failed"
+		if validationResult.Summary.TestsNotFound == validationResult.Summary.InvalidOperations {
+			errType = "test_not_found"
+		}
 		return &BatchTestResult{
 			Error: &TestError{
-				Type:    "batch_validation_failed",
-				Message: validationResult.ErrorMessage,
+				Type:    errType,
+				Message: message,
 			},
 		}, nil
 	}
 
-	// Parse timestamp if provided
-	var timestamp *time.Time
-	if request.Time != "" {
-		t, err := time.Parse(time.RFC3339, request.Time)
-		if err != nil {
-			return nil, fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", request.Time)
-		}
-		timestamp = &t
-	}
-
-	// Create test service
 	service := tests.NewTestService(tests.ServiceConfig{
 		UseMemory: false,
 	})
 
-	// Execute all operations (since validation passed, all should succeed)
+	// Apply all operations to the loaded epic; nothing is saved unless all succeed
+	verification := tests.Verification{By: request.VerifiedBy, Evidence: request.Evidence}
 	var results []BatchOperationResult
 	for _, op := range operations {
-		_, err := service.PassTest(epicFile, op.TestID, timestamp)
-		if err != nil {
-			// This should not happen if validation worked correctly
-			return nil, fmt.Errorf("failed to pass test %s: %w", op.TestID, err)
+		switch op.OperationType {
+		case "pass":
+			_, err = service.ApplyPass(epicData, op.TestID, verification, timestamp)
+		case "fail":
+			_, err = service.ApplyFail(epicData, op.TestID, op.Reason, request.FailureContext, verification, timestamp)
+		default:
+			err = fmt.Errorf("invalid operation type: %s", op.OperationType)
 		}
-
-		// Find test for result
-		var test *epic.Test
-		for i := range epicData.Tests {
-			if epicData.Tests[i].ID == op.TestID {
-				test = &epicData.Tests[i]
-				break
+		if err != nil {
+			batchErr := &TestError{Type: "batch_operation_failed", TestID: op.TestID, Message: err.Error()}
+			if testErr, ok := err.(*tests.TestError); ok && len(operations) == 1 {
+				batchErr.Type, batchErr.Message, batchErr.Hint = string(testErr.Type), testErr.Message, testErr.Hint
+			}
+			if len(operations) > 1 {
+				batchErr.Message = fmt.Sprintf("Batch operation failed, no test was changed:\n- %s (%s): %v", op.TestID, op.OperationType, err)
 			}
+			return &BatchTestResult{Error: batchErr}, nil
 		}
 
 		result := BatchOperationResult{
@@ -480,20 +526,34 @@ func PassBatchTestService(request BatchTestRequest) (*BatchTestResult, error) {
 			OperationType: op.OperationType,
 			Valid:         true,
 		}
-		if test != nil {
+		if test := findBatchTest(epicData, op.TestID); test != nil {
 			result.TestName = test.Name
 		}
 		results = append(results, result)
 	}
 
+	if err := storageService.SaveEpic(epicData, epicFile); err != nil {
+		return nil, fmt.Errorf("failed to save epic: %w", err)
+	}
+
 	// Create success report
 	successReport := bvs.CreateBatchSuccessReport(operations, results)
 
 	return &BatchTestResult{
-		Result: &successReport,
+		Result:   &successReport,
+		Warnings: DetectWarnings(epicData, "", "", requestTime(request.Time)),
 	}, nil
 }
 
+func findBatchTest(epicData *epic.Epic, testID string) *epic.Test {
+	for i := range epicData.Tests {
+		if epicData.Tests[i].ID == testID {
+			return &epicData.Tests[i]
+		}
+	}
+	return nil
+}
+
 func getEpicFileFromBatchRequest(request BatchTestRequest) (string, error) {
 	// Check if file is provided directly
 	epicFile := request.EpicFile
@@ -517,102 +577,5 @@ func getEpicFileFromBatchRequest(request BatchTestRequest) (string, error) {
 		return "", fmt.Errorf("no epic file specified. Use --file flag or run 'agentpm init' first")
 	}
 
-	return cfg.CurrentEpic, nil
-}
-
-func FailBatchTestService(request BatchTestRequest) (*BatchTestResult, error) {
-	if len(request.TestIDs) == 0 {
-		return nil, fmt.Errorf("fail-batch requires at least one test ID")
-	}
-
-	// Load configuration and determine epic file
-	epicFile, err := getEpicFileFromBatchRequest(request)
-	if err != nil {
-		return nil, err
-	}
-
-	// Load epic for validation
-	storageService := storage.NewFileStorage()
-	epicData, err := storageService.LoadEpic(epicFile)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load epic: %w", err)
-	}
-
-	// Create batch operations
-	var operations []BatchOperation
-	for _, testID := range request.TestIDs {
-		operations = append(operations, BatchOperation{
-			TestID:        testID,
-			OperationType: "fail",
-			Reason:        request.FailureReason,
-		})
-	}
-
-	// Validate batch operations
-	bvs := NewBatchValidationService()
-	validationResult, err := bvs.ValidateBatchOperations(epicData, operations)
-	if err != nil {
-		return nil, err
-	}
-
-	// If validation failed, return error
-	if !validationResult.Valid {
-		return &BatchTestResult{
-			Error: &TestError{
-				Type:    "batch_validation_failed",
-				Message: validationResult.ErrorMessage,
-			},
-		}, nil
-	}
-
-	// Parse timestamp if provided
-	var timestamp *time.Time
-	if request.Time != "" {
-		t, err := time.Parse(time.RFC3339, request.Time)
-		if err != nil {
-			return nil, fmt.Errorf("invalid time format: %s (expected ISO8601/RFC3339)", request.Time)
-		}
-		timestamp = &t
-	}
-
-	// Create test service
-	service := tests.NewTestService(tests.ServiceConfig{
-		UseMemory: false,
-	})
-
-	// Execute all operations (since validation passed, all should succeed)
-	var results []BatchOperationResult
-	for _, op := range operations {
-		_, err := service.FailTest(epicFile, op.TestID, op.Reason, timestamp)
-		if err != nil {
-			// This should not happen if validation worked correctly
-			return nil, fmt.Errorf("failed to fail test %s: %w", op.TestID, err)
-		}
-
-		// Find test for result
-		var test *epic.Test
-		for i := range epicData.Tests {
-			if epicData.Tests[i].ID == op.TestID {
-				test = &epicData.Tests[i]
-				break
-			}
-		}
-
-		result := BatchOperationResult{
-			TestID:        op.TestID,
-			OperationType: op.OperationType,
-			Valid:         true,
-		}
-		if test != nil {
-			result.TestName = test.Name
-		}
-		results = append(results, result)
-	}
-
-	// Create success report
-	successReport := bvs.CreateBatchSuccessReport(operations, results)
-
-	return &BatchTestResult{
-		Result: &successReport,
-	}, nil
+	return cfg.EpicFilePath(), nil
 }
@@ -0,0 +1,188 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/planner"
+	"github.com/mindreframer/agentpm/internal/policy"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/urfave/cli/v3"
+)
+
+// Sources of a warning
+const (
+	WarningSourcePolicy  = "policy"  // A warning-severity rule of the policy
+	WarningSourcePlanner = "planner" // The next step the epic waits for
+)
+
+// WarningEpicComplete: all phases are finished, the epic is not
+const WarningEpicComplete = "epic_complete"
+
+// warningsMetadataKey is where a command run records its warnings, see
+// RecordedWarnings
+const warningsMetadataKey = "agentpm.warnings"
+
+// Warning is a soft issue noticed by a mutation that succeeded: a phase that
+// is ready for completion, a task wip for too long. It never fails the command.
+type Warning struct {
+	Source  string `json:"source"`
+	Code    string `json:"code"` // Policy rule ID or planner stall reason
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// DetectWarnings returns the warnings of the epic after an action on an entity:
+// the warning-severity policy rules the action breaks and, from the planner,
+// what keeps the epic from moving on. Actions outside the policy pass "".
+func DetectWarnings(e *epic.Epic, action policy.Action, entityID string, now time.Time) []Warning {
+	var warnings []Warning
+	if action != "" {
+		for _, violation := range policy.Warnings(e, action, entityID, now) {
+			warnings = append(warnings, Warning{
+				Source:  WarningSourcePolicy,
+				Code:    violation.Rule,
+				Message: violation.String(),
+				Hint:    policy.SuppressionHint,
+			})
+		}
+	}
+
+	if e.Status != epic.StatusWIP {
+		return warnings
+	}
+	if stall := planner.AnalyzeStall(e); stall != nil {
+		warnings = append(warnings, Warning{
+			Source:  WarningSourcePlanner,
+			Code:    string(stall.Reason),
+			Message: stall.Message,
+			Hint:    stall.Suggestion,
+		})
+	} else if allPhasesFinished(e) {
+		warnings = append(warnings, Warning{
+			Source:  WarningSourcePlanner,
+			Code:    WarningEpicComplete,
+			Message: fmt.Sprintf("All phases of epic %s are finished, the epic is not", e.ID),
+			Hint:    "agentpm done epic",
+		})
+	}
+	return warnings
+}
+
+// detectFileWarnings loads a saved epic and returns its warnings, for services
+// that leave saving to another service. An unreadable epic has none.
+func detectFileWarnings(epicFile string, action policy.Action, entityID string, now time.Time) []Warning {
+	epicData, err := storage.NewFileStorage().LoadEpic(epicFile)
+	if err != nil {
+		return nil
+	}
+	return DetectWarnings(epicData, action, entityID, now)
+}
+
+// allPhasesFinished reports whether the epic has phases, all completed or cancelled
+func allPhasesFinished(e *epic.Epic) bool {
+	if len(e.Phases) == 0 {
+		return false
+	}
+	for _, phase := range e.Phases {
+		if phase.Status != epic.StatusCompleted && phase.Status != epic.StatusCancelled {
+			return false
+		}
+	}
+	return true
+}
+
+// requestTime is the time of a request: its --time value, or now
+func requestTime(value string) time.Time {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t
+	}
+	return time.Now()
+}
+
+// WriteWarnings renders the warnings of a successful mutation after its output -
+// as "Warning:" lines in text, as a warnings array in JSON and a <warnings>
+// element in XML - and records them for the --quiet result line
+func (ctx RouterContext) WriteWarnings(warnings []Warning) error {
+	if len(warnings) == 0 {
+		return nil
+	}
+	ctx.RecordWarnings(warnings)
+
+	switch ctx.Format {
+	case "json":
+		jsonData, err := json.MarshalIndent(map[string][]Warning{"warnings": warnings}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal warnings to JSON: %w", err)
+		}
+		fmt.Fprintf(ctx.Writer, "%s\n", jsonData)
+	case "xml":
+		doc := etree.NewDocument()
+		WarningsXML(doc.CreateElement("warnings"), warnings)
+		doc.Indent(4)
+		doc.WriteTo(ctx.Writer)
+	default:
+		for _, warning := range warnings {
+			fmt.Fprintf(ctx.Writer, "Warning: %s\n", warning.Message)
+			if warning.Hint != "" {
+				fmt.Fprintf(ctx.Writer, "Hint: %s\n", warning.Hint)
+			}
+		}
+	}
+	return nil
+}
+
+// WarningsXML adds a <warning> element per warning to parent
+func WarningsXML(parent *etree.Element, warnings []Warning) {
+	for _, warning := range warnings {
+		elem := parent.CreateElement("warning")
+		elem.CreateAttr("source", warning.Source)
+		elem.CreateAttr("code", warning.Code)
+		if warning.Hint != "" {
+			elem.CreateAttr("hint", warning.Hint)
+		}
+		elem.SetText(warning.Message)
+	}
+}
+
+// RecordWarnings records warnings for the --quiet result line, for commands
+// that render them as part of their own output
+func (ctx RouterContext) RecordWarnings(warnings []Warning) {
+	if ctx.command == nil || len(warnings) == 0 {
+		return
+	}
+	root := ctx.command.Root()
+	if root.Metadata == nil {
+		root.Metadata = make(map[string]interface{})
+	}
+	recorded, _ := root.Metadata[warningsMetadataKey].([]Warning)
+	root.Metadata[warningsMetadataKey] = append(recorded, warnings...)
+}
+
+// RecordedWarnings returns the warnings recorded during the current run of the
+// command tree of c
+func RecordedWarnings(c *cli.Command) []Warning {
+	warnings, _ := c.Root().Metadata[warningsMetadataKey].([]Warning)
+	return warnings
+}
+
+// ClearWarnings forgets the warnings recorded for the command tree of c
+func ClearWarnings(c *cli.Command) {
+	delete(c.Root().Metadata, warningsMetadataKey)
+}
+
+// uniqueWarnings drops repeated warnings, keeping the first of each
+func uniqueWarnings(warnings []Warning) []Warning {
+	seen := make(map[Warning]bool)
+	var unique []Warning
+	for _, warning := range warnings {
+		if !seen[warning] {
+			seen[warning] = true
+			unique = append(unique, warning)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,209 @@
+package compare
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/service"
+)
+
+// RunStats summarizes how one epic was executed
+type RunStats struct {
+	EpicID          string   `json:"epic_id"`
+	EpicName        string   `json:"epic_name"`
+	Status          string   `json:"status"`
+	Actors          []string `json:"actors,omitempty"`
+	DurationMinutes int      `json:"duration_minutes"`
+	TasksCompleted  int      `json:"tasks_completed"`
+	TasksCancelled  int      `json:"tasks_cancelled"`
+	TestsCancelled  int      `json:"tests_cancelled"`
+	TestFailures    int      `json:"test_failures"`
+	Resets          int      `json:"resets"`
+	OutOfOrder      int      `json:"out_of_order"`
+	Unplanned       int      `json:"unplanned"`
+}
+
+// TaskRun is how one task went in one run
+type TaskRun struct {
+	Status        string `json:"status"`
+	ActiveMinutes int    `json:"active_minutes"`
+	Failures      int    `json:"failures"`
+}
+
+// TaskRow compares a task present in both runs
+type TaskRow struct {
+	ID   string  `json:"id"`
+	Name string  `json:"name"`
+	A    TaskRun `json:"a"`
+	B    TaskRun `json:"b"`
+}
+
+// Comparison puts two runs of the same plan side by side
+type Comparison struct {
+	A       RunStats  `json:"a"`
+	B       RunStats  `json:"b"`
+	Tasks   []TaskRow `json:"tasks"`
+	OnlyInA []string  `json:"only_in_a,omitempty"`
+	OnlyInB []string  `json:"only_in_b,omitempty"`
+}
+
+// Compare compares two epics executed from the same plan, e.g. cloned from one
+// template. Tasks are matched by ID; tasks only one run has count as unplanned
+// for that run. Unfinished runs are measured until now.
+func Compare(a, b *epic.Epic, now time.Time) *Comparison {
+	comparison := &Comparison{}
+	runsA := taskRuns(a, now)
+	runsB := taskRuns(b, now)
+
+	for _, task := range a.Tasks {
+		runB, ok := runsB[task.ID]
+		if !ok {
+			comparison.OnlyInA = append(comparison.OnlyInA, task.ID)
+			continue
+		}
+		comparison.Tasks = append(comparison.Tasks, TaskRow{ID: task.ID, Name: task.Name, A: runsA[task.ID], B: runB})
+	}
+	for _, task := range b.Tasks {
+		if _, ok := runsA[task.ID]; !ok {
+			comparison.OnlyInB = append(comparison.OnlyInB, task.ID)
+		}
+	}
+
+	comparison.A = runStats(a, now)
+	comparison.A.Unplanned = len(comparison.OnlyInA)
+	comparison.B = runStats(b, now)
+	comparison.B.Unplanned = len(comparison.OnlyInB)
+	return comparison
+}
+
+func runStats(e *epic.Epic, now time.Time) RunStats {
+	stats := RunStats{EpicID: e.ID, EpicName: e.Name, Status: string(e.Status)}
+
+	var start, end time.Time
+	actors := make(map[string]bool)
+	for _, event := range e.Events {
+		if start.IsZero() || event.Timestamp.Before(start) {
+			start = event.Timestamp
+		}
+		if event.Timestamp.After(end) {
+			end = event.Timestamp
+		}
+		if event.Actor != "" {
+			actors[event.Actor] = true
+		}
+
+		switch service.EventType(event.Type) {
+		case service.EventTestFailed:
+			stats.TestFailures++
+		case service.EventPhaseReset, service.EventTaskReset, service.EventTestReset:
+			stats.Resets++
+		}
+	}
+	if e.Status != epic.StatusCompleted && !start.IsZero() {
+		end = now
+	}
+	if !start.IsZero() {
+		stats.DurationMinutes = int(end.Sub(start).Minutes())
+	}
+
+	for actor := range actors {
+		stats.Actors = append(stats.Actors, actor)
+	}
+	sort.Strings(stats.Actors)
+
+	for _, task := range e.Tasks {
+		switch task.Status {
+		case epic.StatusCompleted:
+			stats.TasksCompleted++
+		case epic.StatusCancelled:
+			stats.TasksCancelled++
+		}
+	}
+	for _, test := range e.Tests {
+		if test.GetTestStatusUnified() == epic.TestStatusCancelled {
+			stats.TestsCancelled++
+		}
+	}
+	stats.OutOfOrder = outOfOrder(e)
+
+	return stats
+}
+
+// outOfOrder counts the completed tasks that were finished while a task planned
+// ahead of them was still open, the plan being the order of tasks in the epic.
+// Cancelled tasks are not part of the plan any more.
+func outOfOrder(e *epic.Epic) int {
+	count := 0
+	for i, task := range e.Tasks {
+		if task.Status != epic.StatusCompleted || task.CompletedAt == nil {
+			continue
+		}
+		for _, earlier := range e.Tasks[:i] {
+			if earlier.Status == epic.StatusCancelled {
+				continue
+			}
+			if earlier.Status != epic.StatusCompleted || (earlier.CompletedAt != nil && earlier.CompletedAt.After(*task.CompletedAt)) {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+// taskRuns measures every task of the epic: active time from task_started, or
+// task_reopened after a completion, until completed, cancelled or reset (or now),
+// and the failures of its tests
+func taskRuns(e *epic.Epic, now time.Time) map[string]TaskRun {
+	runs := make(map[string]TaskRun, len(e.Tasks))
+	testTask := make(map[string]string, len(e.Tests))
+	for _, task := range e.Tasks {
+		runs[task.ID] = TaskRun{Status: string(task.Status)}
+	}
+	for _, test := range e.Tests {
+		testTask[test.ID] = test.TaskID
+	}
+
+	events := append([]epic.Event(nil), e.Events...)
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Timestamp.Before(events[j].Timestamp) })
+
+	active := make(map[string]time.Duration)
+	started := make(map[string]time.Time)
+	completed := make(map[string]bool) // Tasks whose last close was a completion
+	for _, event := range events {
+		id := service.EventEntityID(event)
+		switch service.EventType(event.Type) {
+		case service.EventTaskStarted:
+			started[id] = event.Timestamp
+		case service.EventTaskCompleted, service.EventTaskCancelled, service.EventTaskReset:
+			if since, ok := started[id]; ok {
+				active[id] += event.Timestamp.Sub(since)
+				delete(started, id)
+			}
+			completed[id] = service.EventType(event.Type) == service.EventTaskCompleted
+		case service.EventTaskReopened:
+			// A completed task is reopened as wip, a cancelled one as pending
+			if completed[id] {
+				started[id] = event.Timestamp
+			}
+			completed[id] = false
+		case service.EventTestFailed:
+			if run, ok := runs[testTask[id]]; ok {
+				run.Failures++
+				runs[testTask[id]] = run
+			}
+		}
+	}
+	for id, since := range started {
+		active[id] += now.Sub(since)
+	}
+
+	for id, duration := range active {
+		if run, ok := runs[id]; ok {
+			run.ActiveMinutes = int(duration.Minutes())
+			runs[id] = run
+		}
+	}
+	return runs
+}
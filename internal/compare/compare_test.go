@@ -0,0 +1,108 @@
+package compare
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func at(hour, minute int) time.Time {
+	return time.Date(2025, 8, 15, hour, minute, 0, 0, time.UTC)
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
+
+// createRun returns an epic as cloned from a template, with three planned tasks
+func createRun(id string) *epic.Epic {
+	return &epic.Epic{
+		ID:     id,
+		Name:   "Search",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{{ID: "P1", Name: "Phase 1"}},
+		Tasks: []epic.Task{
+			{ID: "T1", PhaseID: "P1", Name: "Schema", Status: epic.StatusPending},
+			{ID: "T2", PhaseID: "P1", Name: "Indexer", Status: epic.StatusPending},
+			{ID: "T3", PhaseID: "P1", Name: "Query", Status: epic.StatusPending},
+		},
+		Tests: []epic.Test{
+			{ID: "T1_1", TaskID: "T1", PhaseID: "P1", Name: "Schema test"},
+			{ID: "T2_1", TaskID: "T2", PhaseID: "P1", Name: "Indexer test"},
+		},
+	}
+}
+
+func complete(e *epic.Epic, index int, start, end time.Time) {
+	task := &e.Tasks[index]
+	service.CreateEvent(e, service.EventTaskStarted, task.PhaseID, task.ID, "", "", start)
+	task.Status = epic.StatusCompleted
+	task.CompletedAt = ptr(end)
+	service.CreateEvent(e, service.EventTaskCompleted, task.PhaseID, task.ID, "", "", end)
+}
+
+func TestCompare(t *testing.T) {
+	runA := createRun("run-a")
+	runA.Events = []epic.Event{{ID: "e0", Type: "epic_started", Timestamp: at(9, 0), Actor: "claude-1", Data: "Epic started"}}
+	complete(runA, 0, at(9, 0), at(10, 0))
+	complete(runA, 1, at(10, 0), at(10, 45))
+	complete(runA, 2, at(10, 45), at(11, 0))
+	runA.Status = epic.StatusCompleted
+
+	runB := createRun("run-b")
+	runB.Tasks[2].Status = epic.StatusCancelled
+	runB.Tasks = append(runB.Tasks, epic.Task{ID: "T4", PhaseID: "P1", Name: "Extra", Status: epic.StatusPending})
+	complete(runB, 1, at(9, 0), at(9, 30))
+	service.CreateEvent(runB, service.EventTestFailed, "P1", "T1", "T1_1", "boom", at(9, 45))
+	service.CreateEvent(runB, service.EventTestFailed, "P1", "T1", "T1_1", "boom", at(9, 50))
+	service.CreateEvent(runB, service.EventTaskStarted, "P1", "T1", "", "", at(10, 0))
+	service.CreateEvent(runB, service.EventTaskReset, "P1", "T2", "", "rework", at(10, 30))
+
+	comparison := Compare(runA, runB, at(12, 0))
+
+	assert.Equal(t, []string{"claude-1"}, comparison.A.Actors)
+	assert.Equal(t, 120, comparison.A.DurationMinutes, "completed runs end with their last event")
+	assert.Equal(t, 3, comparison.A.TasksCompleted)
+	assert.Equal(t, 0, comparison.A.OutOfOrder)
+
+	assert.Equal(t, 180, comparison.B.DurationMinutes, "unfinished runs are measured until now")
+	assert.Equal(t, 1, comparison.B.TasksCancelled)
+	assert.Equal(t, 2, comparison.B.TestFailures)
+	assert.Equal(t, 1, comparison.B.Resets)
+	assert.Equal(t, 1, comparison.B.OutOfOrder, "T2 was completed while T1 was open")
+	assert.Equal(t, 1, comparison.B.Unplanned)
+
+	assert.Empty(t, comparison.OnlyInA)
+	assert.Equal(t, []string{"T4"}, comparison.OnlyInB)
+
+	require.Len(t, comparison.Tasks, 3)
+	assert.Equal(t, TaskRun{Status: "completed", ActiveMinutes: 60}, comparison.Tasks[0].A)
+	assert.Equal(t, TaskRun{Status: "pending", ActiveMinutes: 120, Failures: 2}, comparison.Tasks[0].B)
+	assert.Equal(t, TaskRun{Status: "cancelled"}, comparison.Tasks[2].B)
+}
+
+func TestTaskRuns_ReopenedTask(t *testing.T) {
+	e := createRun("run")
+	e.Phases[0].Status = epic.StatusWIP
+	complete(e, 0, at(9, 0), at(10, 0))
+	_, err := service.ReopenTask(e, "T1", "missed a case", at(11, 0))
+	require.NoError(t, err)
+
+	runs := taskRuns(e, at(11, 30))
+	assert.Equal(t, TaskRun{Status: "wip", ActiveMinutes: 90}, runs["T1"])
+}
+
+func TestOutOfOrder(t *testing.T) {
+	e := createRun("run")
+	e.Tasks[0].Status = epic.StatusCancelled
+	e.Tasks[1].Status = epic.StatusCompleted
+	e.Tasks[1].CompletedAt = ptr(at(11, 0))
+	e.Tasks[2].Status = epic.StatusCompleted
+	e.Tasks[2].CompletedAt = ptr(at(10, 0))
+
+	assert.Equal(t, 1, outOfOrder(e), "cancelled tasks do not count, T3 finished before T2")
+}
@@ -0,0 +1,244 @@
+// Package completion builds the final report of a completed epic: its
+// statistics, timeline, decisions, retrospective data and the validation
+// summary of its completion. Written next to the epic file it is the
+// canonical record of the delivery.
+package completion
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/effort"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/lifecycle"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/mindreframer/agentpm/internal/stats"
+	"github.com/mindreframer/agentpm/internal/throughput"
+)
+
+// decisionEvents are the events that record a choice about the scope or
+// course of the epic, rather than progress on it
+var decisionEvents = map[service.EventType]bool{
+	service.EventTaskCancelled:  true,
+	service.EventTestCancelled:  true,
+	service.EventPhaseReset:     true,
+	service.EventTaskReset:      true,
+	service.EventPhaseFrozen:    true,
+	service.EventPhaseUnfrozen:  true,
+	service.EventTaskReopened:   true,
+	service.EventPhaseReopened:  true,
+	service.EventEpicReopened:   true,
+	service.EventEpicSplit:      true,
+	service.EventEpicMerged:     true,
+	service.EventHandoffResumed: true,
+}
+
+// Report is the final report of a completed epic
+type Report struct {
+	EpicID      string                      `json:"epic_id"`
+	Name        string                      `json:"name"`
+	Description string                      `json:"description,omitempty"`
+	Assignee    string                      `json:"assignee,omitempty"`
+	CreatedAt   time.Time                   `json:"created_at"`
+	StartedAt   *time.Time                  `json:"started_at,omitempty"`
+	CompletedAt time.Time                   `json:"completed_at"`
+	Duration    string                      `json:"duration,omitempty"`
+	Validation  lifecycle.ValidationSummary `json:"validation"`
+	Stats       *stats.Stats                `json:"stats"`
+	Timeline    []Milestone                 `json:"timeline"`
+	Decisions   []Decision                  `json:"decisions"`
+	Retro       Retro                       `json:"retro"`
+}
+
+// Milestone is a point on the timeline of the epic: a phase starting or
+// completing, or a milestone of the epic with its target date
+type Milestone struct {
+	Date  string `json:"date"`
+	Label string `json:"label"`
+}
+
+// Decision is a choice made along the way: a resolved blocker or question,
+// a cancellation, a reset, a freeze or a reopened piece of work
+type Decision struct {
+	At    time.Time `json:"at"`
+	Actor string    `json:"actor,omitempty"`
+	Text  string    `json:"text"`
+}
+
+// Retro is the data a retrospective of the epic starts from
+type Retro struct {
+	TestFailures      int                  `json:"test_failures"` // test_failed events
+	TestsFixed        int                  `json:"tests_fixed"`   // failing tests that passed afterwards
+	TasksReopened     int                  `json:"tasks_reopened"`
+	TasksCancelled    int                  `json:"tasks_cancelled"`
+	TestsCancelled    int                  `json:"tests_cancelled"`
+	Blockers          int                  `json:"blockers"`
+	Questions         int                  `json:"questions"`
+	MeanTimeToResolve string               `json:"mean_time_to_resolve,omitempty"`
+	Effort            []effort.ActorEffort `json:"effort"`
+}
+
+// Build assembles the final report of the epic completed at completedAt,
+// with the validation summary its completion reported
+func Build(e *epic.Epic, validation lifecycle.ValidationSummary, completedAt time.Time) *Report {
+	report := &Report{
+		EpicID:      e.ID,
+		Name:        e.Name,
+		Description: strings.TrimSpace(e.Description),
+		Assignee:    e.Assignee,
+		CreatedAt:   e.CreatedAt,
+		CompletedAt: completedAt,
+		Validation:  validation,
+		Stats:       stats.Build(e, completedAt),
+		Timeline:    timeline(e),
+		Decisions:   decisions(e),
+		Retro:       retro(e, completedAt),
+	}
+	if startedAt := startedAt(e); startedAt != nil {
+		report.StartedAt = startedAt
+		report.Duration = formatDuration(completedAt.Sub(*startedAt))
+	}
+	return report
+}
+
+// startedAt is when the epic was started: its first epic_started event, or
+// else its first event of all
+func startedAt(e *epic.Epic) *time.Time {
+	var first *time.Time
+	for i := range e.Events {
+		event := &e.Events[i]
+		if service.EventType(event.Type) == service.EventEpicStarted {
+			return &event.Timestamp
+		}
+		if first == nil || event.Timestamp.Before(*first) {
+			first = &event.Timestamp
+		}
+	}
+	return first
+}
+
+func timeline(e *epic.Epic) []Milestone {
+	type entry struct {
+		at    time.Time
+		label string
+	}
+	var entries []entry
+	for _, phase := range e.Phases {
+		if phase.StartedAt != nil {
+			entries = append(entries, entry{*phase.StartedAt, fmt.Sprintf("Phase %s (%s) started", phase.ID, phase.Name)})
+		}
+		if phase.CompletedAt != nil {
+			entries = append(entries, entry{*phase.CompletedAt, fmt.Sprintf("Phase %s (%s) completed", phase.ID, phase.Name)})
+		}
+	}
+	for _, milestone := range e.Milestones {
+		date, _, err := epic.ParseDate(milestone.TargetDate)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry{date, fmt.Sprintf("Milestone %s (%s) target date", milestone.ID, milestone.Name)})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].at.Before(entries[j].at) })
+
+	result := []Milestone{}
+	for _, entry := range entries {
+		result = append(result, Milestone{Date: entry.at.Format(time.DateOnly), Label: entry.label})
+	}
+	return result
+}
+
+func decisions(e *epic.Epic) []Decision {
+	result := []Decision{}
+	for _, blocker := range e.Blockers {
+		if !blocker.IsResolved() {
+			continue
+		}
+		kind := "Blocker"
+		if blocker.IsQuestion() {
+			kind = "Question"
+		}
+		text := fmt.Sprintf("%s %s resolved: %s", kind, blocker.ID, blocker.Description)
+		if blocker.Resolution != "" {
+			text += " → " + blocker.Resolution
+		}
+		result = append(result, Decision{At: *blocker.ResolvedAt, Text: text})
+	}
+	for _, event := range e.Events {
+		if decisionEvents[service.EventType(event.Type)] {
+			result = append(result, Decision{At: event.Timestamp, Actor: event.Actor, Text: event.Data})
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].At.Before(result[j].At) })
+	return result
+}
+
+func retro(e *epic.Epic, completedAt time.Time) Retro {
+	r := Retro{Effort: effort.Build([]*epic.Epic{e}, effort.Period{}, completedAt).Actors}
+	if r.Effort == nil {
+		r.Effort = []effort.ActorEffort{}
+	}
+
+	first := completedAt
+	for _, event := range e.Events {
+		if event.Timestamp.Before(first) {
+			first = event.Timestamp
+		}
+		switch service.EventType(event.Type) {
+		case service.EventTestFailed:
+			r.TestFailures++
+		case service.EventTaskReopened:
+			r.TasksReopened++
+		}
+	}
+
+	// Count over every day from the first event up to the completion
+	days := int(completedAt.Sub(first).Hours()/24) + 2
+	r.TestsFixed = throughput.Build(e, completedAt, days).Fixed
+
+	for _, task := range e.Tasks {
+		if task.Status == epic.StatusCancelled {
+			r.TasksCancelled++
+		}
+	}
+	for _, test := range e.Tests {
+		if test.Status == epic.StatusCancelled {
+			r.TestsCancelled++
+		}
+	}
+
+	var resolving time.Duration
+	resolved := 0
+	for _, blocker := range e.Blockers {
+		if blocker.IsQuestion() {
+			r.Questions++
+		} else {
+			r.Blockers++
+		}
+		if blocker.IsResolved() {
+			resolving += blocker.ResolvedAt.Sub(blocker.RaisedAt)
+			resolved++
+		}
+	}
+	if resolved > 0 {
+		r.MeanTimeToResolve = formatDuration(resolving / time.Duration(resolved))
+	}
+	return r
+}
+
+// formatDuration writes a duration in days, hours and minutes, e.g. "3d 4h 5m"
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
@@ -0,0 +1,134 @@
+package completion
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/lifecycle"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// at returns a time on the given day of August 2025
+func at(day, hour int) time.Time {
+	return time.Date(2025, 8, day, hour, 0, 0, 0, time.UTC)
+}
+
+func timeAt(day, hour int) *time.Time {
+	t := at(day, hour)
+	return &t
+}
+
+func createCompletedEpic() *epic.Epic {
+	e := &epic.Epic{
+		ID:          "epic-8",
+		Name:        "Payments",
+		Description: "  Accept card payments  ",
+		Assignee:    "agent-a",
+		Status:      epic.StatusCompleted,
+		Phases: []epic.Phase{
+			{ID: "1A", Name: "Backend", Status: epic.StatusCompleted, StartedAt: timeAt(10, 9), CompletedAt: timeAt(11, 17)},
+			{ID: "1B", Name: "Frontend", Status: epic.StatusCompleted, StartedAt: timeAt(12, 9), CompletedAt: timeAt(13, 17)},
+		},
+		Tasks: []epic.Task{
+			{ID: "1A_1", PhaseID: "1A", Name: "API", Status: epic.StatusCompleted},
+			{ID: "1A_2", PhaseID: "1A", Name: "Webhooks", Status: epic.StatusCancelled},
+			{ID: "1B_1", PhaseID: "1B", Name: "Checkout", Status: epic.StatusCompleted},
+		},
+		Tests: []epic.Test{
+			{ID: "T1", TaskID: "1A_1", PhaseID: "1A", Name: "Charges", Status: epic.StatusCompleted},
+			{ID: "T2", TaskID: "1B_1", PhaseID: "1B", Name: "Checkout", Status: epic.StatusCancelled},
+		},
+		Milestones: []epic.Milestone{
+			{ID: "M1", Name: "Beta", TargetDate: "2025-08-12"},
+		},
+		Blockers: []epic.Blocker{
+			{ID: "B1", RaisedAt: at(10, 10), ResolvedAt: timeAt(10, 14), Description: "No sandbox keys", Resolution: "Keys issued"},
+			{ID: "Q1", Type: epic.BlockerTypeQuestion, RaisedAt: at(12, 10), ResolvedAt: timeAt(12, 12), Description: "Which currencies?"},
+			{ID: "B2", RaisedAt: at(13, 10), Description: "Still open"},
+		},
+	}
+	service.CreateEvent(e, service.EventEpicStarted, "", "", "", "", at(10, 8))
+	service.CreateEvent(e, service.EventTestFailed, "1A", "1A_1", "T1", "timeout", at(10, 11))
+	service.CreateEvent(e, service.EventTestPassed, "1A", "1A_1", "T1", "", at(10, 15))
+	service.CreateEvent(e, service.EventTaskCancelled, "1A", "1A_2", "", "out of scope", at(11, 9))
+	return e
+}
+
+func TestBuild(t *testing.T) {
+	validation := lifecycle.ValidationSummary{TotalPhases: 2, CompletedPhases: 2, CompletionPercent: 100}
+	report := Build(createCompletedEpic(), validation, at(13, 18))
+
+	t.Run("describes the epic and its duration", func(t *testing.T) {
+		assert.Equal(t, "epic-8", report.EpicID)
+		assert.Equal(t, "Accept card payments", report.Description)
+		require.NotNil(t, report.StartedAt)
+		assert.Equal(t, at(10, 8), *report.StartedAt)
+		assert.Equal(t, "3d 10h 0m", report.Duration)
+		assert.Equal(t, validation, report.Validation)
+		assert.Equal(t, 2, report.Stats.Tasks[string(epic.StatusCompleted)])
+	})
+
+	t.Run("orders phases and milestones on the timeline", func(t *testing.T) {
+		assert.Equal(t, []Milestone{
+			{Date: "2025-08-10", Label: "Phase 1A (Backend) started"},
+			{Date: "2025-08-11", Label: "Phase 1A (Backend) completed"},
+			{Date: "2025-08-12", Label: "Milestone M1 (Beta) target date"},
+			{Date: "2025-08-12", Label: "Phase 1B (Frontend) started"},
+			{Date: "2025-08-13", Label: "Phase 1B (Frontend) completed"},
+		}, report.Timeline)
+	})
+
+	t.Run("lists resolved blockers and decision events", func(t *testing.T) {
+		require.Len(t, report.Decisions, 3)
+		assert.Equal(t, "Blocker B1 resolved: No sandbox keys → Keys issued", report.Decisions[0].Text)
+		assert.Equal(t, at(11, 9), report.Decisions[1].At)
+		assert.Contains(t, report.Decisions[1].Text, "1A_2")
+		assert.Equal(t, "Question Q1 resolved: Which currencies?", report.Decisions[2].Text)
+	})
+
+	t.Run("collects retrospective data", func(t *testing.T) {
+		assert.Equal(t, 1, report.Retro.TestFailures)
+		assert.Equal(t, 1, report.Retro.TestsFixed)
+		assert.Equal(t, 1, report.Retro.TasksCancelled)
+		assert.Equal(t, 1, report.Retro.TestsCancelled)
+		assert.Equal(t, 2, report.Retro.Blockers)
+		assert.Equal(t, 1, report.Retro.Questions)
+		assert.Equal(t, "3h 0m", report.Retro.MeanTimeToResolve)
+		assert.NotNil(t, report.Retro.Effort)
+	})
+}
+
+func TestBuild_WithoutEvents(t *testing.T) {
+	report := Build(&epic.Epic{ID: "epic-1", Name: "Empty"}, lifecycle.ValidationSummary{}, at(13, 18))
+	assert.Nil(t, report.StartedAt)
+	assert.Empty(t, report.Duration)
+	assert.NotNil(t, report.Timeline)
+	assert.NotNil(t, report.Decisions)
+	assert.Empty(t, report.Retro.MeanTimeToResolve)
+}
+
+func TestRender(t *testing.T) {
+	report := Build(createCompletedEpic(), lifecycle.ValidationSummary{TotalTasks: 3, CompletedTasks: 3, CompletionPercent: 100}, at(13, 18))
+
+	t.Run("markdown", func(t *testing.T) {
+		md := Markdown(report)
+		assert.Contains(t, md, "# Completion Report: Payments")
+		assert.Contains(t, md, "| Tasks | 3 | 3 | 0 |")
+		assert.Contains(t, md, "**Completion:** 100%")
+		assert.Contains(t, md, "- **2025-08-11** Phase 1A (Backend) completed")
+		assert.Contains(t, md, "Blocker B1 resolved: No sandbox keys → Keys issued")
+		assert.Contains(t, md, "- **Blockers raised:** 2")
+		assert.Contains(t, md, "- **Mean time to resolve:** 3h 0m")
+	})
+
+	t.Run("html escapes epic content", func(t *testing.T) {
+		report.Name = "Payments <beta>"
+		html, err := HTML(report)
+		require.NoError(t, err)
+		assert.Contains(t, html, "<h1>Completion Report: Payments &lt;beta&gt;</h1>")
+		assert.Contains(t, html, "<td>Tasks</td><td>3</td><td>3</td><td>0</td>")
+	})
+}
@@ -0,0 +1,283 @@
+package completion
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/output"
+)
+
+// Markdown renders the report as a Markdown document
+func Markdown(report *Report) string {
+	md := output.NewMarkdown()
+
+	md.Heading(1, fmt.Sprintf("Completion Report: %s", report.Name))
+	md.Field("Epic", report.EpicID)
+	if report.Assignee != "" {
+		md.Field("Assignee", report.Assignee)
+	}
+	if report.StartedAt != nil {
+		md.Field("Started", report.StartedAt.Format(time.RFC3339))
+	}
+	md.Field("Completed", report.CompletedAt.Format(time.RFC3339))
+	if report.Duration != "" {
+		md.Field("Duration", report.Duration)
+	}
+	if report.Description != "" {
+		md.Paragraph("%s", output.Inline(report.Description))
+	}
+
+	md.Heading(2, "Validation Summary")
+	md.Table([]string{"", "Total", "Completed", "Open"}, validationRows(report))
+	md.Paragraph("**Completion:** %d%%", report.Validation.CompletionPercent)
+
+	md.Heading(2, "Statistics")
+	for _, row := range statisticsRows(report) {
+		md.Field(row[0], row[1])
+	}
+
+	md.Heading(2, "Timeline")
+	if len(report.Timeline) == 0 {
+		md.Paragraph("No phase start or completion times were recorded.")
+	}
+	for _, entry := range report.Timeline {
+		md.Item("**%s** %s", entry.Date, output.Inline(entry.Label))
+	}
+
+	md.Heading(2, "Decisions")
+	if len(report.Decisions) == 0 {
+		md.Paragraph("No blockers were resolved and no work was cancelled, reset, frozen or reopened.")
+	}
+	for _, decision := range report.Decisions {
+		md.Item("**%s** %s", decisionMeta(decision), output.Inline(decision.Text))
+	}
+
+	md.Heading(2, "Retrospective")
+	for _, row := range retroRows(report) {
+		md.Field(row[0], row[1])
+	}
+	if len(report.Retro.Effort) > 0 {
+		md.Table([]string{"Actor", "Tasks completed", "Tests passed", "Tests fixed", "Active minutes"}, effortRows(report))
+	}
+
+	md.Rule()
+	md.Raw(fmt.Sprintf("*Final report of epic %s, generated by AgentPM*\n", report.EpicID))
+	return md.String()
+}
+
+// HTML renders the report as a standalone HTML page
+func HTML(report *Report) (string, error) {
+	var b strings.Builder
+	err := htmlTemplate.Execute(&b, map[string]interface{}{
+		"Report":     report,
+		"Started":    formatOptionalTime(report.StartedAt),
+		"Completed":  report.CompletedAt.Format(time.RFC3339),
+		"Validation": validationRows(report),
+		"Statistics": statisticsRows(report),
+		"Decisions":  decisionRows(report),
+		"Retro":      retroRows(report),
+		"Effort":     effortRows(report),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render the report as HTML: %w", err)
+	}
+	return b.String(), nil
+}
+
+func validationRows(report *Report) [][]string {
+	v := report.Validation
+	row := func(label string, total, completed, open int) []string {
+		return []string{label, strconv.Itoa(total), strconv.Itoa(completed), strconv.Itoa(open)}
+	}
+	return [][]string{
+		row("Phases", v.TotalPhases, v.CompletedPhases, v.PendingPhases),
+		row("Tasks", v.TotalTasks, v.CompletedTasks, v.PendingTasks),
+		row("Tests", v.TotalTests, v.PassingTests, v.FailingTests),
+	}
+}
+
+func statisticsRows(report *Report) [][]string {
+	s := report.Stats
+	cycle := "no completed tasks with start and completion times"
+	if s.CycleTimes.Samples > 0 {
+		cycle = fmt.Sprintf("mean %d min, median %d min, p80 %d min (%d tasks)",
+			s.CycleTimes.Mean, s.CycleTimes.Median, s.CycleTimes.P80, s.CycleTimes.Samples)
+	}
+	return [][]string{
+		{"Phases", formatCounts(s.Phases)},
+		{"Tasks", formatCounts(s.Tasks)},
+		{"Tests", formatCounts(s.Tests)},
+		{"Task cycle time", cycle},
+		{"Health", fmt.Sprintf("%d (%s)", s.Health.Score, s.Health.Grade)},
+	}
+}
+
+func retroRows(report *Report) [][]string {
+	r := report.Retro
+	resolve := r.MeanTimeToResolve
+	if resolve == "" {
+		resolve = "—"
+	}
+	return [][]string{
+		{"Test failures", strconv.Itoa(r.TestFailures)},
+		{"Tests fixed after failing", strconv.Itoa(r.TestsFixed)},
+		{"Tasks reopened", strconv.Itoa(r.TasksReopened)},
+		{"Tasks cancelled", strconv.Itoa(r.TasksCancelled)},
+		{"Tests cancelled", strconv.Itoa(r.TestsCancelled)},
+		{"Blockers raised", strconv.Itoa(r.Blockers)},
+		{"Questions raised", strconv.Itoa(r.Questions)},
+		{"Mean time to resolve", resolve},
+	}
+}
+
+func effortRows(report *Report) [][]string {
+	var rows [][]string
+	for _, actor := range report.Retro.Effort {
+		rows = append(rows, []string{
+			actor.Actor,
+			strconv.Itoa(actor.TasksCompleted),
+			strconv.Itoa(actor.TestsPassed),
+			strconv.Itoa(actor.TestsFixed),
+			strconv.Itoa(actor.ActiveMinutes),
+		})
+	}
+	return rows
+}
+
+func decisionRows(report *Report) [][]string {
+	var rows [][]string
+	for _, decision := range report.Decisions {
+		rows = append(rows, []string{decisionMeta(decision), decision.Text})
+	}
+	return rows
+}
+
+// decisionMeta is when and by whom a decision was made, e.g. "2025-08-16 14:00 (agent-b)"
+func decisionMeta(decision Decision) string {
+	meta := decision.At.Format("2006-01-02 15:04")
+	if decision.Actor != "" {
+		meta += " (" + decision.Actor + ")"
+	}
+	return meta
+}
+
+// formatCounts lists the non-zero counts per status, e.g. "1 cancelled, 3 completed"
+func formatCounts(counts map[string]int) string {
+	var statuses []string
+	for status, count := range counts {
+		if count > 0 {
+			statuses = append(statuses, status)
+		}
+	}
+	if len(statuses) == 0 {
+		return "none"
+	}
+	sort.Strings(statuses)
+
+	parts := make([]string, len(statuses))
+	for i, status := range statuses {
+		parts[i] = fmt.Sprintf("%d %s", counts[status], status)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Completion Report: {{.Report.Name}}</title>
+<style>
+body { font-family: sans-serif; max-width: 60em; margin: 2em auto; color: #222; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.8em; text-align: left; }
+th { background: #f4f4f4; }
+</style>
+</head>
+<body>
+<h1>Completion Report: {{.Report.Name}}</h1>
+<ul>
+<li><strong>Epic:</strong> {{.Report.EpicID}}</li>
+{{- if .Report.Assignee}}
+<li><strong>Assignee:</strong> {{.Report.Assignee}}</li>
+{{- end}}
+{{- if .Started}}
+<li><strong>Started:</strong> {{.Started}}</li>
+{{- end}}
+<li><strong>Completed:</strong> {{.Completed}}</li>
+{{- if .Report.Duration}}
+<li><strong>Duration:</strong> {{.Report.Duration}}</li>
+{{- end}}
+</ul>
+{{- if .Report.Description}}
+<p>{{.Report.Description}}</p>
+{{- end}}
+
+<h2>Validation Summary</h2>
+<table>
+<tr><th></th><th>Total</th><th>Completed</th><th>Open</th></tr>
+{{- range .Validation}}
+<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</table>
+<p><strong>Completion:</strong> {{.Report.Validation.CompletionPercent}}%</p>
+
+<h2>Statistics</h2>
+<ul>
+{{- range .Statistics}}
+<li><strong>{{index . 0}}:</strong> {{index . 1}}</li>
+{{- end}}
+</ul>
+
+<h2>Timeline</h2>
+{{- if .Report.Timeline}}
+<ul>
+{{- range .Report.Timeline}}
+<li><strong>{{.Date}}</strong> {{.Label}}</li>
+{{- end}}
+</ul>
+{{- else}}
+<p>No phase start or completion times were recorded.</p>
+{{- end}}
+
+<h2>Decisions</h2>
+{{- if .Decisions}}
+<ul>
+{{- range .Decisions}}
+<li><strong>{{index . 0}}</strong> {{index . 1}}</li>
+{{- end}}
+</ul>
+{{- else}}
+<p>No blockers were resolved and no work was cancelled, reset, frozen or reopened.</p>
+{{- end}}
+
+<h2>Retrospective</h2>
+<ul>
+{{- range .Retro}}
+<li><strong>{{index . 0}}:</strong> {{index . 1}}</li>
+{{- end}}
+</ul>
+{{- if .Effort}}
+<table>
+<tr><th>Actor</th><th>Tasks completed</th><th>Tests passed</th><th>Tests fixed</th><th>Active minutes</th></tr>
+{{- range .Effort}}
+<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</table>
+{{- end}}
+
+<hr>
+<p><em>Final report of epic {{.Report.EpicID}}, generated by AgentPM</em></p>
+</body>
+</html>
+`))
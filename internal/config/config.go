@@ -5,16 +5,112 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/customfields"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/lint"
+	"github.com/mindreframer/agentpm/internal/policy"
+	"github.com/mindreframer/agentpm/internal/storage"
 )
 
 type Config struct {
-	CurrentEpic     string     `json:"current_epic"`
-	PreviousEpic    string     `json:"previous_epic,omitempty"`
-	ProjectName     string     `json:"project_name,omitempty"`
-	DefaultAssignee string     `json:"default_assignee,omitempty"`
-	Hints           HintConfig `json:"hints,omitempty"`
+	CurrentEpic      string     `json:"current_epic"`
+	PreviousEpic     string     `json:"previous_epic,omitempty"`
+	Epics            []string   `json:"epics,omitempty"` // Further epics of the workspace, see EpicFilePaths
+	ProjectName      string     `json:"project_name,omitempty"`
+	DefaultAssignee  string     `json:"default_assignee,omitempty"`
+	TestGating       string     `json:"test_gating,omitempty"`       // "strict" (default), "lenient" or "off"
+	Strict           bool       `json:"strict,omitempty"`            // Refuse completions that validation would only warn about
+	GateTags         string     `json:"gate_tags,omitempty"`         // Comma-separated test tags that gate phases (empty = all tests)
+	Assignees        string     `json:"assignees,omitempty"`         // Comma-separated assignees start-next hands new tasks to
+	AssigneeRotation string     `json:"assignee_rotation,omitempty"` // "round-robin" or "least-loaded" (empty = off)
+	Hints            HintConfig `json:"hints,omitempty"`
+	Backups          int        `json:"backups,omitempty"`     // Previous versions of epic files kept by saves (0 = none)
+	APIVersion       int        `json:"api_version,omitempty"` // Version of JSON/XML output (0 = version 1)
+
+	TemplatesDir     string `json:"templates_dir,omitempty"`     // Local epic templates, default .agentpm/templates
+	TemplateRegistry string `json:"template_registry,omitempty"` // URL or path of a template registry index
+
+	DocsFile  string `json:"docs_file,omitempty"`  // Where agentpm docs writes by default and links point to
+	ServerURL string `json:"server_url,omitempty"` // Base URL of an agentpm server; links point there when set
+
+	PolicyFile string `json:"policy_file,omitempty"` // Organization rules, default .agentpm/policy.yaml
+	TokensFile string `json:"tokens_file,omitempty"` // API tokens of serve mode, default .agentpm/tokens.json
+	ArchiveDir string `json:"archive_dir,omitempty"` // Archived epics and copies taken before compaction, default .agentpm/archive
+
+	StorageBackend string `json:"storage_backend,omitempty"` // "file" (default) or "sqlite"
+	StorageDB      string `json:"storage_db,omitempty"`      // Index database of the sqlite backend, default .agentpm/index.db
+
+	CustomFields customfields.Schema `json:"custom_fields,omitempty"` // Typed custom fields per entity type
+
+	WarningBudgets map[string]int `json:"warning_budgets,omitempty"` // Warnings validate --ci allows per category
+
+	Policies []policy.Rule `json:"policies,omitempty"` // Project rules enforced along with those of the policy file
+
+	Lint map[string]string `json:"lint,omitempty"` // Severity (error, warn or off) per lint rule
+
+	UpcomingWindow string `json:"upcoming_window,omitempty"` // How far ahead status and upcoming look for deadlines, default 7d
+
+	DefaultFormat string             `json:"default_format,omitempty"` // Output format used when --format is not given
+	Profiles      map[string]Profile `json:"profiles,omitempty"`       // Named settings selected with --profile or AGENTPM_PROFILE
+
+	// Profile is the name of the profile applied while loading (never persisted)
+	Profile string `json:"-"`
+
+	// OverriddenKeys lists the keys whose values come from per-epic overrides (never persisted)
+	OverriddenKeys []string `json:"-"`
+	// Warnings holds non-fatal problems found while loading, such as unknown keys (never persisted)
+	Warnings []string `json:"-"`
+
+	// path is the absolute path of the loaded config file; relative epic paths resolve against its directory
+	path string
+	// base holds the values the applied profile replaced, see withoutProfile
+	base *profileBase
 }
 
+// DefaultConfigFile is the config file name looked up from the working directory upwards
+const DefaultConfigFile = ".agentpm.json"
+
+// DefaultTemplatesDir is where fetched epic templates are stored, relative to the config file
+const DefaultTemplatesDir = ".agentpm/templates"
+
+// DefaultPolicyFile holds the organization rules enforced by validate and mutations, relative to the config file
+const DefaultPolicyFile = ".agentpm/policy.yaml"
+
+// DefaultTokensFile holds the hashed API tokens of serve mode, relative to the config file
+const DefaultTokensFile = ".agentpm/tokens.json"
+
+// DefaultArchiveDir holds archived epics and copies taken before compaction, relative to the config file
+const DefaultArchiveDir = ".agentpm/archive"
+
+// DefaultStorageDB holds the index of the sqlite storage backend, relative to the config file
+const DefaultStorageDB = ".agentpm/index.db"
+
+// DefaultUpcomingWindow is how far ahead status and upcoming look for deadlines
+const DefaultUpcomingWindow = "7d"
+
+// DefaultHandoffKeyFile holds the key handoff tokens are signed with, relative to the config file
+const DefaultHandoffKeyFile = ".agentpm/handoff.key"
+
+// DefaultHintRepeatsFile counts the errors agents hit per session for hint escalation, relative to the config file
+const DefaultHintRepeatsFile = ".agentpm/hint-repeats.json"
+
+// Policies start-next uses to pick the assignee of a task it starts
+const (
+	AssigneeRotationRoundRobin  = "round-robin"  // The assignee after the one who was handed a task last
+	AssigneeRotationLeastLoaded = "least-loaded" // The assignee with the fewest tasks in progress
+)
+
+// Test gating strictness levels
+const (
+	TestGatingStrict  = "strict"
+	TestGatingLenient = "lenient"
+	TestGatingOff     = "off"
+)
+
 // HintConfig controls hint generation and display behavior
 type HintConfig struct {
 	Enabled        bool              `json:"enabled"`                  // Whether hints are enabled globally
@@ -45,47 +141,126 @@ func DefaultHintConfig() HintConfig {
 }
 
 func LoadConfig(configPath string) (*Config, error) {
-	if configPath == "" {
-		configPath = ".agentpm.json"
-	}
-
-	absPath, err := filepath.Abs(configPath)
+	absPath, err := ResolveConfigPath(configPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to resolve config path: %w", err)
+		return nil, err
 	}
 
 	data, err := os.ReadFile(absPath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("config file not found: %s", absPath)
+			return nil, exitcode.Errorf(exitcode.NotFound, "config file not found: %s", absPath)
 		}
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	var config Config
+	// Strict parsing: check keys and value types before decoding
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	report := &ValidationReport{}
+	validateFields(raw, configFields, "", report)
+	if !report.Valid() {
+		return nil, fmt.Errorf("invalid configuration: %s", strings.Join(report.Errors, "; "))
+	}
+
+	// Hint settings missing from the file keep their defaults
+	config := Config{Hints: DefaultHintConfig()}
 	if err := json.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	if name := ActiveProfile(); name != "" {
+		if err := config.applyProfile(name); err != nil {
+			return nil, fmt.Errorf("invalid configuration: %w", err)
+		}
+	}
+
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
+	config.Warnings = report.Warnings
+	config.path = absPath
 	return &config, nil
 }
 
-func SaveConfig(config *Config, configPath string) error {
+// ResolveConfigPath returns the absolute path of the config file. When no path or
+// the relative default path is given, the config is discovered like git discovers
+// .git: the working directory and then its parents are searched. Any other path,
+// including an absolute path to a default-named file, is used as-is. If no config
+// file is found the path in the working directory is returned.
+func ResolveConfigPath(configPath string) (string, error) {
 	if configPath == "" {
-		configPath = ".agentpm.json"
+		configPath = DefaultConfigFile
 	}
 
+	absPath, err := filepath.Abs(configPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve config path: %w", err)
+	}
+
+	if filepath.Clean(configPath) != DefaultConfigFile {
+		return absPath, nil
+	}
+
+	if found, ok := FindConfigFile(filepath.Dir(absPath)); ok {
+		return found, nil
+	}
+	return absPath, nil
+}
+
+// FindConfigFile walks up from startDir to the filesystem root and returns the
+// first default config file found
+func FindConfigFile(startDir string) (string, bool) {
+	for dir := filepath.Clean(startDir); ; dir = filepath.Dir(dir) {
+		candidate := filepath.Join(dir, DefaultConfigFile)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, true
+		}
+		if filepath.Dir(dir) == dir {
+			return "", false
+		}
+	}
+}
+
+// NormalizeEpicPath converts an epic path given relative to the working directory
+// into the form stored in the config: relative to the config file's directory, so
+// the config keeps working from any subdirectory. Absolute paths are kept as-is.
+func NormalizeEpicPath(configPath, epicPath string) (string, error) {
+	if epicPath == "" || filepath.IsAbs(epicPath) {
+		return epicPath, nil
+	}
+
+	absConfig, err := ResolveConfigPath(configPath)
+	if err != nil {
+		return "", err
+	}
+
+	absEpic, err := filepath.Abs(epicPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve epic path: %w", err)
+	}
+
+	rel, err := filepath.Rel(filepath.Dir(absConfig), absEpic)
+	if err != nil {
+		return absEpic, nil
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+func SaveConfig(config *Config, configPath string) error {
 	if err := config.Validate(); err != nil {
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
+	// The values of the applied profile stay in the profile
+	config = config.withoutProfile()
 
-	absPath, err := filepath.Abs(configPath)
+	absPath, err := ResolveConfigPath(configPath)
 	if err != nil {
-		return fmt.Errorf("failed to resolve config path: %w", err)
+		return err
 	}
 
 	data, err := json.MarshalIndent(config, "", "  ")
@@ -120,19 +295,280 @@ func (c *Config) Validate() error {
 		c.DefaultAssignee = "agent"
 	}
 
+	switch c.TestGating {
+	case "", TestGatingStrict, TestGatingLenient, TestGatingOff:
+	default:
+		return fmt.Errorf("invalid test_gating %q (expected %s, %s or %s)", c.TestGating, TestGatingStrict, TestGatingLenient, TestGatingOff)
+	}
+
+	switch c.AssigneeRotation {
+	case "", AssigneeRotationRoundRobin, AssigneeRotationLeastLoaded:
+	default:
+		return fmt.Errorf("invalid assignee_rotation %q (expected %s or %s)", c.AssigneeRotation, AssigneeRotationRoundRobin, AssigneeRotationLeastLoaded)
+	}
+
+	switch c.StorageBackend {
+	case "", storage.BackendFile, storage.BackendSQLite:
+	default:
+		return fmt.Errorf("invalid storage_backend %q (expected %s or %s)", c.StorageBackend, storage.BackendFile, storage.BackendSQLite)
+	}
+
+	if err := c.CustomFields.Validate(); err != nil {
+		return err
+	}
+
+	if _, err := policy.New(c.Policies); err != nil {
+		return fmt.Errorf("invalid policies: %w", err)
+	}
+
+	if err := lint.ValidateSeverities(c.Lint); err != nil {
+		return fmt.Errorf("invalid lint: %w", err)
+	}
+
+	if c.DefaultFormat != "" && !containsString(Formats, c.DefaultFormat) {
+		return fmt.Errorf("invalid default_format %q (expected %s)", c.DefaultFormat, strings.Join(Formats, ", "))
+	}
+
+	if c.UpcomingWindow != "" {
+		if _, err := epic.ParseInterval(c.UpcomingWindow); err != nil {
+			return fmt.Errorf("invalid upcoming_window %q (use 7d, 2w or a duration like 12h)", c.UpcomingWindow)
+		}
+	}
+
+	for category, budget := range c.WarningBudgets {
+		if budget < 0 {
+			return fmt.Errorf("invalid warning_budgets.%s %d (budgets cannot be negative)", category, budget)
+		}
+	}
+
 	return nil
 }
 
+// IsOverridden reports whether the given key was set by per-epic overrides
+func (c *Config) IsOverridden(key string) bool {
+	for _, k := range c.OverriddenKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// EpicFilePath returns the path of the current epic file. Relative paths are
+// resolved against the directory of the config file they were loaded from.
 func (c *Config) EpicFilePath() string {
-	if filepath.IsAbs(c.CurrentEpic) {
-		return c.CurrentEpic
+	return c.resolvePath(c.CurrentEpic)
+}
+
+// EpicFilePaths returns the paths of all epics of the workspace, resolved like
+// EpicFilePath: the current epic first, then the registered ones
+func (c *Config) EpicFilePaths() []string {
+	paths := []string{c.EpicFilePath()}
+	for _, epic := range c.Epics {
+		if path := c.resolvePath(epic); !containsString(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// AddEpic registers an epic path, given in the form stored in the config (see
+// NormalizeEpicPath). It reports false if the epic is already part of the workspace.
+func (c *Config) AddEpic(epic string) bool {
+	if containsString(c.EpicFilePaths(), c.resolvePath(epic)) {
+		return false
+	}
+	c.Epics = append(c.Epics, epic)
+	return true
+}
+
+// RemoveEpic unregisters an epic path. It reports false if the epic was not registered.
+func (c *Config) RemoveEpic(epic string) bool {
+	path := c.resolvePath(epic)
+	for i, registered := range c.Epics {
+		if c.resolvePath(registered) == path {
+			c.Epics = append(c.Epics[:i], c.Epics[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// ReplaceEpic points every reference to the epic at path - as current, previous
+// or registered epic - to the epic at newPath. References stay relative to the
+// config file's directory if they were. It reports whether any reference changed.
+func (c *Config) ReplaceEpic(path, newPath string) bool {
+	target, replacement := absolutePath(path), absolutePath(newPath)
+	stored := func(ref string) string {
+		if filepath.IsAbs(ref) {
+			return replacement
+		}
+		rel, err := filepath.Rel(absolutePath(c.Dir()), replacement)
+		if err != nil {
+			return replacement
+		}
+		return filepath.ToSlash(rel)
+	}
+
+	changed := false
+	for _, ref := range []*string{&c.CurrentEpic, &c.PreviousEpic} {
+		if *ref != "" && absolutePath(c.resolvePath(*ref)) == target {
+			*ref = stored(*ref)
+			changed = true
+		}
+	}
+	for i, registered := range c.Epics {
+		if absolutePath(c.resolvePath(registered)) == target {
+			c.Epics[i] = stored(registered)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// FilePath returns the absolute path of the file the config was loaded from, if any
+func (c *Config) FilePath() string {
+	return c.path
+}
+
+// PreviousEpicFilePath returns the path of the previous epic file, resolved like EpicFilePath
+func (c *Config) PreviousEpicFilePath() string {
+	if c.PreviousEpic == "" {
+		return ""
+	}
+	return c.resolvePath(c.PreviousEpic)
+}
+
+// GateTagList returns the tags listed in GateTags
+func (c *Config) GateTagList() []string {
+	var tags []string
+	for _, tag := range strings.Split(c.GateTags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// AssigneeList returns the assignees start-next rotates new tasks through, in order
+func (c *Config) AssigneeList() []string {
+	var assignees []string
+	for _, assignee := range strings.Split(c.Assignees, ",") {
+		if assignee = strings.TrimSpace(assignee); assignee != "" && !containsString(assignees, assignee) {
+			assignees = append(assignees, assignee)
+		}
+	}
+	return assignees
+}
+
+// TemplatesDirPath returns the directory of local epic templates, resolved like EpicFilePath
+func (c *Config) TemplatesDirPath() string {
+	if c.TemplatesDir == "" {
+		return c.resolvePath(DefaultTemplatesDir)
+	}
+	return c.resolvePath(c.TemplatesDir)
+}
+
+// PolicyFilePath returns the policy file of organization rules, resolved like EpicFilePath
+func (c *Config) PolicyFilePath() string {
+	if c.PolicyFile == "" {
+		return c.resolvePath(DefaultPolicyFile)
+	}
+	return c.resolvePath(c.PolicyFile)
+}
+
+// HandoffKeyPath returns the key handoff tokens are signed with
+func (c *Config) HandoffKeyPath() string {
+	return c.resolvePath(DefaultHandoffKeyFile)
+}
+
+// HintRepeatsPath returns the file counting repeated errors for hint escalation
+func (c *Config) HintRepeatsPath() string {
+	return c.resolvePath(DefaultHintRepeatsFile)
+}
+
+// TokensFilePath returns the API tokens file, resolved like EpicFilePath
+func (c *Config) TokensFilePath() string {
+	if c.TokensFile == "" {
+		return c.resolvePath(DefaultTokensFile)
+	}
+	return c.resolvePath(c.TokensFile)
+}
+
+// ArchiveDirPath returns the directory of archived epics, resolved like EpicFilePath
+func (c *Config) ArchiveDirPath() string {
+	if c.ArchiveDir == "" {
+		return c.resolvePath(DefaultArchiveDir)
+	}
+	return c.resolvePath(c.ArchiveDir)
+}
+
+// StorageDBPath returns the index database of the sqlite storage backend, resolved like EpicFilePath
+func (c *Config) StorageDBPath() string {
+	if c.StorageDB == "" {
+		return c.resolvePath(DefaultStorageDB)
+	}
+	return c.resolvePath(c.StorageDB)
+}
+
+// UpcomingWindowDuration returns how far ahead status and upcoming look for deadlines
+func (c *Config) UpcomingWindowDuration() time.Duration {
+	window, err := epic.ParseInterval(c.UpcomingWindow)
+	if err != nil {
+		window, _ = epic.ParseInterval(DefaultUpcomingWindow)
+	}
+	return window
+}
+
+// TemplateRegistryLocation returns the template registry URL, or its path resolved like EpicFilePath
+func (c *Config) TemplateRegistryLocation() string {
+	if strings.Contains(c.TemplateRegistry, "://") {
+		return c.TemplateRegistry
+	}
+	return c.resolvePath(c.TemplateRegistry)
+}
+
+// DocsFilePath returns the path of the generated documentation, resolved like EpicFilePath.
+// Without docs_file it is the current epic's path with a .md extension.
+func (c *Config) DocsFilePath() string {
+	if c.DocsFile == "" {
+		epicFile := c.EpicFilePath()
+		return strings.TrimSuffix(epicFile, filepath.Ext(epicFile)) + ".md"
+	}
+	return c.resolvePath(c.DocsFile)
+}
+
+// Dir returns the directory of the file the config was loaded from, the project root
+func (c *Config) Dir() string {
+	if c.path == "" {
+		return "."
+	}
+	return filepath.Dir(c.path)
+}
+
+func (c *Config) resolvePath(path string) string {
+	if path == "" {
+		return ""
+	}
+	if filepath.IsAbs(path) {
+		return path
+	}
+	if c.path != "" {
+		return filepath.Join(filepath.Dir(c.path), path)
+	}
+	return "./" + path
+}
+
+func absolutePath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
 	}
-	return "./" + c.CurrentEpic
+	return filepath.Clean(path)
 }
 
 func ConfigExists(configPath string) bool {
 	if configPath == "" {
-		configPath = ".agentpm.json"
+		configPath = DefaultConfigFile
 	}
 
 	absPath, err := filepath.Abs(configPath)
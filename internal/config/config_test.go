@@ -101,6 +101,49 @@ func TestConfig_EpicFilePath(t *testing.T) {
 	}
 }
 
+func TestConfig_Epics(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, DefaultConfigFile)
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"current_epic": "epic-8.xml", "epics": ["epics/epic-9.xml"]}`), 0644))
+
+	config, err := LoadConfig(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(tempDir, "epic-8.xml"), filepath.Join(tempDir, "epics", "epic-9.xml")}, config.EpicFilePaths())
+
+	assert.False(t, config.AddEpic("epic-8.xml"), "the current epic is always part of the workspace")
+	assert.False(t, config.AddEpic("epics/epic-9.xml"))
+	assert.True(t, config.AddEpic("epic-10.xml"))
+	assert.Equal(t, []string{"epics/epic-9.xml", "epic-10.xml"}, config.Epics)
+
+	assert.True(t, config.RemoveEpic("epics/epic-9.xml"))
+	assert.False(t, config.RemoveEpic("epics/epic-9.xml"))
+	assert.False(t, config.RemoveEpic("epic-8.xml"), "the current epic is not registered")
+	assert.Equal(t, []string{"epic-10.xml"}, config.Epics)
+
+	require.NoError(t, SaveConfig(config, configPath))
+	reloaded, err := LoadConfig(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"epic-10.xml"}, reloaded.Epics)
+}
+
+func TestConfig_DocsFilePath(t *testing.T) {
+	t.Run("defaults to the epic file with .md extension", func(t *testing.T) {
+		config := &Config{CurrentEpic: "epics/epic-8.xml"}
+		assert.Equal(t, "./epics/epic-8.md", config.DocsFilePath())
+	})
+
+	t.Run("docs_file resolves against the config directory", func(t *testing.T) {
+		tempDir := t.TempDir()
+		configPath := filepath.Join(tempDir, DefaultConfigFile)
+		require.NoError(t, os.WriteFile(configPath, []byte(`{"current_epic": "epic-8.xml", "docs_file": "docs/epic.md"}`), 0644))
+
+		config, err := LoadConfig(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(tempDir, "docs", "epic.md"), config.DocsFilePath())
+		assert.Equal(t, tempDir, config.Dir())
+	})
+}
+
 func TestLoadConfig(t *testing.T) {
 	t.Run("load valid config", func(t *testing.T) {
 		tempDir := t.TempDir()
@@ -333,3 +376,100 @@ func TestConfigExists(t *testing.T) {
 		assert.True(t, ConfigExists(""))
 	})
 }
+
+func TestResolveConfigPath(t *testing.T) {
+	t.Run("default config found in parent directory", func(t *testing.T) {
+		tempDir, err := filepath.EvalSymlinks(t.TempDir())
+		require.NoError(t, err)
+		configPath := filepath.Join(tempDir, DefaultConfigFile)
+		require.NoError(t, os.WriteFile(configPath, []byte(`{"current_epic": "epic-8.xml"}`), 0644))
+
+		nested := filepath.Join(tempDir, "src", "pkg")
+		require.NoError(t, os.MkdirAll(nested, 0755))
+
+		oldWd, _ := os.Getwd()
+		defer os.Chdir(oldWd)
+		require.NoError(t, os.Chdir(nested))
+
+		resolved, err := ResolveConfigPath("")
+		require.NoError(t, err)
+		assert.Equal(t, configPath, resolved)
+	})
+
+	t.Run("explicit path is not searched upwards", func(t *testing.T) {
+		tempDir, err := filepath.EvalSymlinks(t.TempDir())
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "custom.json"), []byte(`{"current_epic": "epic-8.xml"}`), 0644))
+
+		nested := filepath.Join(tempDir, "src")
+		require.NoError(t, os.MkdirAll(nested, 0755))
+
+		oldWd, _ := os.Getwd()
+		defer os.Chdir(oldWd)
+		require.NoError(t, os.Chdir(nested))
+
+		resolved, err := ResolveConfigPath("custom.json")
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(nested, "custom.json"), resolved)
+	})
+}
+
+func TestNormalizeEpicPath(t *testing.T) {
+	tempDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	configPath := filepath.Join(tempDir, DefaultConfigFile)
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"current_epic": "epic-8.xml"}`), 0644))
+
+	nested := filepath.Join(tempDir, "epics")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	require.NoError(t, os.Chdir(nested))
+
+	t.Run("relative path is stored relative to the config directory", func(t *testing.T) {
+		normalized, err := NormalizeEpicPath("", "epic-9.xml")
+		require.NoError(t, err)
+		assert.Equal(t, "epics/epic-9.xml", normalized)
+	})
+
+	t.Run("absolute path is kept", func(t *testing.T) {
+		normalized, err := NormalizeEpicPath("", "/abs/path/epic-9.xml")
+		require.NoError(t, err)
+		assert.Equal(t, "/abs/path/epic-9.xml", normalized)
+	})
+
+	t.Run("loaded config resolves relative to its directory", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(configPath, []byte(`{"current_epic": "epics/epic-9.xml", "previous_epic": "epic-8.xml"}`), 0644))
+
+		cfg, err := LoadConfig("")
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(tempDir, "epics", "epic-9.xml"), cfg.EpicFilePath())
+		assert.Equal(t, filepath.Join(tempDir, "epic-8.xml"), cfg.PreviousEpicFilePath())
+	})
+}
+
+func TestFindConfigFile(t *testing.T) {
+	t.Run("finds the nearest config walking up", func(t *testing.T) {
+		root := t.TempDir()
+		inner := filepath.Join(root, "service")
+		nested := filepath.Join(inner, "pkg", "deep")
+		require.NoError(t, os.MkdirAll(nested, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, DefaultConfigFile), []byte(`{}`), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(inner, DefaultConfigFile), []byte(`{}`), 0644))
+
+		found, ok := FindConfigFile(nested)
+		assert.True(t, ok)
+		assert.Equal(t, filepath.Join(inner, DefaultConfigFile), found)
+	})
+
+	t.Run("ignores a directory named like the config", func(t *testing.T) {
+		root := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(root, DefaultConfigFile), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(root), DefaultConfigFile), []byte(`{}`), 0644))
+
+		found, ok := FindConfigFile(root)
+		assert.True(t, ok)
+		assert.Equal(t, filepath.Join(filepath.Dir(root), DefaultConfigFile), found)
+	})
+}
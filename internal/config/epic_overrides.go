@@ -0,0 +1,131 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// EpicOverrides holds per-epic policy settings that take precedence over the project config.
+// They are stored in a JSON sidecar next to the epic file (epic-8.xml -> epic-8.config.json).
+// Only keys present in the sidecar override the project values.
+type EpicOverrides struct {
+	TestGating *string        `json:"test_gating,omitempty"`
+	Strict     *bool          `json:"strict,omitempty"`
+	GateTags   *string        `json:"gate_tags,omitempty"`
+	Hints      *HintOverrides `json:"hints,omitempty"`
+
+	// Warnings holds non-fatal problems found in the sidecar, such as unknown keys
+	Warnings []string `json:"-"`
+}
+
+// HintOverrides mirrors HintConfig with optional fields
+type HintOverrides struct {
+	Enabled        *bool             `json:"enabled,omitempty"`
+	ShowCommands   *bool             `json:"show_commands,omitempty"`
+	ShowReferences *bool             `json:"show_references,omitempty"`
+	Priority       *string           `json:"priority,omitempty"`
+	MaxHints       *int              `json:"max_hints,omitempty"`
+	Customizations map[string]string `json:"customizations,omitempty"`
+}
+
+// EpicOverridesPath returns the sidecar path holding overrides for the given epic file
+func EpicOverridesPath(epicFile string) string {
+	ext := filepath.Ext(epicFile)
+	return strings.TrimSuffix(epicFile, ext) + ".config.json"
+}
+
+// LoadEpicOverrides reads the sidecar of an epic file. A missing sidecar is not an error
+// and yields nil overrides.
+func LoadEpicOverrides(epicFile string) (*EpicOverrides, error) {
+	path := EpicOverridesPath(epicFile)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read epic config %s: %w", path, err)
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse epic config %s: %w", path, err)
+	}
+
+	report := validateOverrideData(raw)
+	if !report.Valid() {
+		return nil, fmt.Errorf("invalid epic config %s: %s", path, strings.Join(report.Errors, "; "))
+	}
+
+	var overrides EpicOverrides
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse epic config %s: %w", path, err)
+	}
+
+	overrides.Warnings = report.Warnings
+	return &overrides, nil
+}
+
+// WithEpicOverrides returns a copy of the config with the overrides merged on top.
+// The original config is left untouched, so it can still be saved without the overrides.
+func (c *Config) WithEpicOverrides(overrides *EpicOverrides) *Config {
+	merged := *c
+	merged.OverriddenKeys = nil
+	merged.Hints = copyHints(c.Hints)
+
+	merged.Warnings = append([]string(nil), c.Warnings...)
+
+	if overrides == nil {
+		return &merged
+	}
+
+	for _, warning := range overrides.Warnings {
+		merged.Warnings = append(merged.Warnings, "epic config: "+warning)
+	}
+
+	if overrides.TestGating != nil {
+		merged.TestGating = *overrides.TestGating
+		merged.OverriddenKeys = append(merged.OverriddenKeys, "test_gating")
+	}
+	if overrides.Strict != nil {
+		merged.Strict = *overrides.Strict
+		merged.OverriddenKeys = append(merged.OverriddenKeys, "strict")
+	}
+	if overrides.GateTags != nil {
+		merged.GateTags = *overrides.GateTags
+		merged.OverriddenKeys = append(merged.OverriddenKeys, "gate_tags")
+	}
+
+	merged.OverriddenKeys = append(merged.OverriddenKeys, applyHintOverrides(&merged.Hints, overrides.Hints)...)
+
+	return &merged
+}
+
+// LoadEffectiveConfig loads the project config and merges the overrides of the given
+// epic file over it. When epicFile is empty the config's current epic is used.
+// The result is meant for reading policies; persist changes with LoadConfig/SaveConfig.
+func LoadEffectiveConfig(configPath, epicFile string) (*Config, error) {
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if epicFile == "" {
+		epicFile = cfg.EpicFilePath()
+	}
+
+	overrides, err := LoadEpicOverrides(epicFile)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := cfg.WithEpicOverrides(overrides)
+	if err := merged.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid epic config %s: %w", EpicOverridesPath(epicFile), err)
+	}
+
+	return merged, nil
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEpicOverridesPath(t *testing.T) {
+	assert.Equal(t, "epic-8.config.json", EpicOverridesPath("epic-8.xml"))
+	assert.Equal(t, "/work/epics/epic-9.config.json", EpicOverridesPath("/work/epics/epic-9.xml"))
+}
+
+func TestLoadEffectiveConfig(t *testing.T) {
+	setup := func(t *testing.T, sidecar string) (string, string) {
+		tempDir := t.TempDir()
+		configPath := filepath.Join(tempDir, ".agentpm.json")
+		epicFile := filepath.Join(tempDir, "epic-8.xml")
+
+		cfg := DefaultConfig()
+		cfg.CurrentEpic = epicFile
+		cfg.TestGating = TestGatingStrict
+		require.NoError(t, SaveConfig(cfg, configPath))
+
+		if sidecar != "" {
+			require.NoError(t, os.WriteFile(EpicOverridesPath(epicFile), []byte(sidecar), 0644))
+		}
+		return configPath, epicFile
+	}
+
+	t.Run("without sidecar the project config is used", func(t *testing.T) {
+		configPath, _ := setup(t, "")
+
+		cfg, err := LoadEffectiveConfig(configPath, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, "agent", cfg.DefaultAssignee)
+		assert.Equal(t, TestGatingStrict, cfg.TestGating)
+		assert.Empty(t, cfg.OverriddenKeys)
+	})
+
+	t.Run("sidecar values are merged over project config", func(t *testing.T) {
+		configPath, _ := setup(t, `{
+  "test_gating": "lenient",
+  "strict": true,
+  "hints": {"max_hints": 1, "enabled": false}
+}`)
+
+		cfg, err := LoadEffectiveConfig(configPath, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, TestGatingLenient, cfg.TestGating)
+		assert.True(t, cfg.Strict)
+		assert.Equal(t, 1, cfg.Hints.MaxHints)
+		assert.False(t, cfg.Hints.Enabled)
+		// Untouched hint settings keep the project value
+		assert.True(t, cfg.Hints.ShowCommands)
+		assert.True(t, cfg.IsOverridden("test_gating"))
+		assert.True(t, cfg.IsOverridden("hints.max_hints"))
+		assert.True(t, cfg.IsOverridden("strict"))
+		assert.False(t, cfg.IsOverridden("gate_tags"))
+
+		// The project config on disk is not changed by merging
+		project, err := LoadConfig(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, "agent", project.DefaultAssignee)
+		assert.Equal(t, TestGatingStrict, project.TestGating)
+		assert.False(t, project.Strict)
+	})
+
+	t.Run("explicit epic file selects its own sidecar", func(t *testing.T) {
+		configPath, epicFile := setup(t, "")
+		otherEpic := filepath.Join(filepath.Dir(epicFile), "epic-9.xml")
+		require.NoError(t, os.WriteFile(EpicOverridesPath(otherEpic), []byte(`{"test_gating": "off"}`), 0644))
+
+		cfg, err := LoadEffectiveConfig(configPath, otherEpic)
+		require.NoError(t, err)
+		assert.Equal(t, TestGatingOff, cfg.TestGating)
+	})
+
+	t.Run("keys that are not overridable are ignored with a warning", func(t *testing.T) {
+		configPath, _ := setup(t, `{"default_assignee": "alice"}`)
+
+		cfg, err := LoadEffectiveConfig(configPath, "")
+		require.NoError(t, err)
+		assert.Equal(t, "agent", cfg.DefaultAssignee)
+		assert.False(t, cfg.IsOverridden("default_assignee"))
+		require.Len(t, cfg.Warnings, 1)
+		assert.Contains(t, cfg.Warnings[0], `unknown key "default_assignee"`)
+	})
+
+	t.Run("invalid override values are rejected", func(t *testing.T) {
+		configPath, _ := setup(t, `{"test_gating": "sometimes"}`)
+
+		_, err := LoadEffectiveConfig(configPath, "")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "test_gating must be one of")
+	})
+
+	t.Run("malformed sidecar", func(t *testing.T) {
+		configPath, _ := setup(t, `{not json`)
+
+		_, err := LoadEffectiveConfig(configPath, "")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse epic config")
+	})
+}
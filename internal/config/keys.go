@@ -0,0 +1,204 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// lookupField resolves a dotted key like "hints.max_hints" against the config schema.
+// Keys below a string map (hints.customizations.<name>) resolve to a string field.
+func lookupField(key string) (fieldSpec, error) {
+	parts := strings.Split(key, ".")
+	fields := configFields
+
+	for i, part := range parts {
+		var found *fieldSpec
+		for j := range fields {
+			if fields[j].Name == part {
+				found = &fields[j]
+				break
+			}
+		}
+		if found == nil {
+			return fieldSpec{}, fmt.Errorf("unknown config key: %s", key)
+		}
+
+		if i == len(parts)-1 {
+			return *found, nil
+		}
+
+		if found.StringMap {
+			if i+2 != len(parts) || parts[i+1] == "" {
+				return fieldSpec{}, fmt.Errorf("unknown config key: %s", key)
+			}
+			return fieldSpec{Name: parts[i+1], Type: "string"}, nil
+		}
+		if found.Type != "object" {
+			return fieldSpec{}, fmt.Errorf("unknown config key: %s (%s is not an object)", key, strings.Join(parts[:i+1], "."))
+		}
+		fields = found.Fields
+	}
+
+	return fieldSpec{}, fmt.Errorf("unknown config key: %s", key)
+}
+
+// Describe returns what a config key like "test_gating" or "hints.max_hints"
+// is for, or "" for unknown keys
+func Describe(key string) string {
+	field, err := lookupField(key)
+	if err != nil {
+		return ""
+	}
+	return field.Description
+}
+
+// parseFieldValue converts a command line string into the JSON value of the field's type
+func parseFieldValue(field fieldSpec, key, value string) (interface{}, error) {
+	switch field.Type {
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a boolean (true/false), got %q", key, value)
+		}
+		return b, nil
+	case "integer":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be an integer, got %q", key, value)
+		}
+		if n < 0 {
+			return nil, fmt.Errorf("%s must not be negative", key)
+		}
+		return n, nil
+	case "object":
+		return nil, fmt.Errorf("%s is an object; set its keys individually (e.g. %s.<key>)", key, key)
+	case "array":
+		return nil, fmt.Errorf("%s is a list; change it with 'agentpm %s add/remove'", key, key)
+	default:
+		if len(field.Enum) > 0 && !containsString(field.Enum, value) {
+			return nil, fmt.Errorf("%s must be one of %s, got %q", key, strings.Join(field.Enum, ", "), value)
+		}
+		return value, nil
+	}
+}
+
+// toRaw converts the config into its generic JSON representation
+func (c *Config) toRaw() (map[string]interface{}, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	return raw, nil
+}
+
+// fromRaw replaces the config values with the given generic JSON representation
+func (c *Config) fromRaw(raw map[string]interface{}) error {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	var updated Config
+	if err := json.Unmarshal(data, &updated); err != nil {
+		return fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	updated.OverriddenKeys = c.OverriddenKeys
+	updated.Warnings = c.Warnings
+	updated.path = c.path
+	*c = updated
+	return nil
+}
+
+// Get returns the value of a dotted config key. Known keys that are not set return nil.
+func (c *Config) Get(key string) (interface{}, error) {
+	if _, err := lookupField(key); err != nil {
+		return nil, err
+	}
+
+	raw, err := c.toRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	var current interface{} = raw
+	for _, part := range strings.Split(key, ".") {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+		current, ok = obj[part]
+		if !ok {
+			return nil, nil
+		}
+	}
+	return current, nil
+}
+
+// Set assigns a dotted config key from its string form, converting it to the key's type
+func (c *Config) Set(key, value string) error {
+	field, err := lookupField(key)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := parseFieldValue(field, key, value)
+	if err != nil {
+		return err
+	}
+
+	raw, err := c.toRaw()
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(key, ".")
+	obj := raw
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := obj[part].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			obj[part] = child
+		}
+		obj = child
+	}
+	obj[parts[len(parts)-1]] = parsed
+
+	return c.fromRaw(raw)
+}
+
+// Unset removes a dotted config key, restoring its zero value
+func (c *Config) Unset(key string) error {
+	field, err := lookupField(key)
+	if err != nil {
+		return err
+	}
+	if field.Required {
+		return fmt.Errorf("cannot unset required config key: %s", key)
+	}
+
+	raw, err := c.toRaw()
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(key, ".")
+	obj := raw
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := obj[part].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		obj = child
+	}
+	delete(obj, parts[len(parts)-1])
+
+	return c.fromRaw(raw)
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_GetSetUnset(t *testing.T) {
+	newConfig := func() *Config {
+		cfg := DefaultConfig()
+		cfg.CurrentEpic = "epic-8.xml"
+		return cfg
+	}
+
+	t.Run("set and get typed values", func(t *testing.T) {
+		cfg := newConfig()
+
+		require.NoError(t, cfg.Set("default_assignee", "alice"))
+		require.NoError(t, cfg.Set("hints.max_hints", "5"))
+		require.NoError(t, cfg.Set("hints.enabled", "false"))
+		require.NoError(t, cfg.Set("hints.customizations.start", "Use start task"))
+		require.NoError(t, cfg.Set("test_gating", "lenient"))
+
+		assert.Equal(t, "alice", cfg.DefaultAssignee)
+		assert.Equal(t, 5, cfg.Hints.MaxHints)
+		assert.False(t, cfg.Hints.Enabled)
+		assert.Equal(t, "Use start task", cfg.Hints.Customizations["start"])
+		assert.Equal(t, TestGatingLenient, cfg.TestGating)
+
+		value, err := cfg.Get("hints.max_hints")
+		require.NoError(t, err)
+		assert.Equal(t, float64(5), value)
+
+		value, err = cfg.Get("default_assignee")
+		require.NoError(t, err)
+		assert.Equal(t, "alice", value)
+	})
+
+	t.Run("known but unset keys return nil", func(t *testing.T) {
+		value, err := newConfig().Get("test_gating")
+		require.NoError(t, err)
+		assert.Nil(t, value)
+	})
+
+	t.Run("unset removes values", func(t *testing.T) {
+		cfg := newConfig()
+		require.NoError(t, cfg.Set("project_name", "Demo"))
+		require.NoError(t, cfg.Unset("project_name"))
+		assert.Empty(t, cfg.ProjectName)
+
+		require.NoError(t, cfg.Set("hints.customizations.a", "b"))
+		require.NoError(t, cfg.Unset("hints.customizations.a"))
+		assert.NotContains(t, cfg.Hints.Customizations, "a")
+	})
+
+	t.Run("invalid keys and values", func(t *testing.T) {
+		cfg := newConfig()
+
+		assert.EqualError(t, cfg.Set("defualt_assignee", "x"), "unknown config key: defualt_assignee")
+		assert.EqualError(t, cfg.Set("hints.max_hints", "many"), `hints.max_hints must be an integer, got "many"`)
+		assert.EqualError(t, cfg.Set("hints.enabled", "sure"), `hints.enabled must be a boolean (true/false), got "sure"`)
+		assert.EqualError(t, cfg.Set("test_gating", "chaos"), `test_gating must be one of strict, lenient, off, got "chaos"`)
+		assert.Contains(t, cfg.Set("hints", "x").Error(), "hints is an object")
+		assert.Contains(t, cfg.Set("project_name.sub", "x").Error(), "is not an object")
+		assert.EqualError(t, cfg.Unset("current_epic"), "cannot unset required config key: current_epic")
+
+		_, err := cfg.Get("nope")
+		assert.EqualError(t, err, "unknown config key: nope")
+	})
+
+	t.Run("changes round-trip through the config file", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), ".agentpm.json")
+		cfg := newConfig()
+		require.NoError(t, cfg.Set("hints.priority", "high"))
+		require.NoError(t, SaveConfig(cfg, configPath))
+
+		loaded, err := LoadConfig(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, "high", loaded.Hints.Priority)
+		assert.Empty(t, loaded.Warnings)
+	})
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ProfileEnvVar selects the profile applied by LoadConfig when --profile is not given
+const ProfileEnvVar = "AGENTPM_PROFILE"
+
+// Profile is a named set of settings in the profiles of the config, e.g. "ci"
+// or "local", applied over the top-level values when it is selected. Only keys
+// present in the profile override the top-level values.
+type Profile struct {
+	CurrentEpic     string         `json:"current_epic,omitempty"`
+	DefaultAssignee string         `json:"default_assignee,omitempty"`
+	DefaultFormat   string         `json:"default_format,omitempty"`
+	Hints           *HintOverrides `json:"hints,omitempty"`
+}
+
+// Output formats default_format accepts
+var Formats = []string{"text", "json", "xml", "markdown"}
+
+// profile is the profile selected with SetProfile
+var profile string
+
+// SetProfile selects the profile LoadConfig applies; an empty name falls back to AGENTPM_PROFILE
+func SetProfile(name string) {
+	profile = name
+}
+
+// ActiveProfile returns the name of the selected profile, empty when none is selected
+func ActiveProfile() string {
+	if profile != "" {
+		return profile
+	}
+	return os.Getenv(ProfileEnvVar)
+}
+
+// profileBase holds the top-level values a profile replaced, so that saving
+// the config does not persist the values of the profile
+type profileBase struct {
+	applied         Profile
+	appliedHints    HintConfig
+	CurrentEpic     string
+	DefaultAssignee string
+	DefaultFormat   string
+	Hints           HintConfig
+}
+
+// ProfileNames returns the names of the profiles of the config, sorted
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyProfile applies the named profile over the top-level values
+func (c *Config) applyProfile(name string) error {
+	p, ok := c.Profiles[name]
+	if !ok {
+		if len(c.Profiles) == 0 {
+			return fmt.Errorf("unknown profile %q (the config defines no profiles)", name)
+		}
+		return fmt.Errorf("unknown profile %q (defined: %s)", name, strings.Join(c.ProfileNames(), ", "))
+	}
+
+	c.base = &profileBase{
+		applied:         p,
+		CurrentEpic:     c.CurrentEpic,
+		DefaultAssignee: c.DefaultAssignee,
+		DefaultFormat:   c.DefaultFormat,
+		Hints:           c.Hints,
+	}
+	c.Profile = name
+	if p.CurrentEpic != "" {
+		c.CurrentEpic = p.CurrentEpic
+	}
+	if p.DefaultAssignee != "" {
+		c.DefaultAssignee = p.DefaultAssignee
+	}
+	if p.DefaultFormat != "" {
+		c.DefaultFormat = p.DefaultFormat
+	}
+	c.Hints = copyHints(c.Hints)
+	applyHintOverrides(&c.Hints, p.Hints)
+	c.base.appliedHints = copyHints(c.Hints)
+	return nil
+}
+
+// withoutProfile returns the config to save: values the profile set and the
+// caller left alone are put back to their top-level values
+func (c *Config) withoutProfile() *Config {
+	if c.base == nil {
+		return c
+	}
+	saved := *c
+	p := c.base.applied
+	if p.CurrentEpic != "" && saved.CurrentEpic == p.CurrentEpic {
+		saved.CurrentEpic = c.base.CurrentEpic
+	}
+	if p.DefaultAssignee != "" && saved.DefaultAssignee == p.DefaultAssignee {
+		saved.DefaultAssignee = c.base.DefaultAssignee
+	}
+	if p.DefaultFormat != "" && saved.DefaultFormat == p.DefaultFormat {
+		saved.DefaultFormat = c.base.DefaultFormat
+	}
+	if p.Hints != nil && reflect.DeepEqual(saved.Hints, c.base.appliedHints) {
+		saved.Hints = c.base.Hints
+	}
+	return &saved
+}
+
+// copyHints returns hints whose customizations can be changed without
+// changing those of the original
+func copyHints(hints HintConfig) HintConfig {
+	customizations := make(map[string]string, len(hints.Customizations))
+	for k, v := range hints.Customizations {
+		customizations[k] = v
+	}
+	hints.Customizations = customizations
+	return hints
+}
+
+// applyHintOverrides sets the hint settings present in the overrides and
+// returns their keys
+func applyHintOverrides(hints *HintConfig, h *HintOverrides) []string {
+	if h == nil {
+		return nil
+	}
+	var keys []string
+	if h.Enabled != nil {
+		hints.Enabled = *h.Enabled
+		keys = append(keys, "hints.enabled")
+	}
+	if h.ShowCommands != nil {
+		hints.ShowCommands = *h.ShowCommands
+		keys = append(keys, "hints.show_commands")
+	}
+	if h.ShowReferences != nil {
+		hints.ShowReferences = *h.ShowReferences
+		keys = append(keys, "hints.show_references")
+	}
+	if h.Priority != nil {
+		hints.Priority = *h.Priority
+		keys = append(keys, "hints.priority")
+	}
+	if h.MaxHints != nil {
+		hints.MaxHints = *h.MaxHints
+		keys = append(keys, "hints.max_hints")
+	}
+	for k, v := range h.Customizations {
+		hints.Customizations[k] = v
+		keys = append(keys, "hints.customizations."+k)
+	}
+	return keys
+}
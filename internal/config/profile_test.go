@@ -0,0 +1,92 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const profilesConfig = `{
+  "current_epic": "epic.xml",
+  "default_assignee": "alice",
+  "hints": {"enabled": true, "show_commands": true, "priority": "medium", "max_hints": 3},
+  "profiles": {
+    "ci": {"current_epic": "ci-epic.xml", "default_assignee": "ci-bot", "default_format": "json", "hints": {"enabled": false}},
+    "local": {"default_format": "text"}
+  }
+}`
+
+func TestLoadConfigProfiles(t *testing.T) {
+	t.Cleanup(func() { SetProfile("") })
+	configPath := filepath.Join(t.TempDir(), DefaultConfigFile)
+	require.NoError(t, os.WriteFile(configPath, []byte(profilesConfig), 0644))
+
+	t.Run("no profile", func(t *testing.T) {
+		SetProfile("")
+		t.Setenv(ProfileEnvVar, "")
+		cfg, err := LoadConfig(configPath)
+		require.NoError(t, err)
+		assert.Empty(t, cfg.Profile)
+		assert.Equal(t, "alice", cfg.DefaultAssignee)
+		assert.Equal(t, []string{"ci", "local"}, cfg.ProfileNames())
+	})
+
+	t.Run("selected profile", func(t *testing.T) {
+		SetProfile("ci")
+		cfg, err := LoadConfig(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, "ci", cfg.Profile)
+		assert.Equal(t, filepath.Join(filepath.Dir(configPath), "ci-epic.xml"), cfg.EpicFilePath())
+		assert.Equal(t, "ci-bot", cfg.DefaultAssignee)
+		assert.Equal(t, "json", cfg.DefaultFormat)
+		assert.False(t, cfg.Hints.Enabled)
+		assert.Equal(t, 3, cfg.Hints.MaxHints, "hint settings the profile leaves out are kept")
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		SetProfile("")
+		t.Setenv(ProfileEnvVar, "local")
+		cfg, err := LoadConfig(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, "local", cfg.Profile)
+		assert.Equal(t, "text", cfg.DefaultFormat)
+		assert.Equal(t, "alice", cfg.DefaultAssignee)
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		SetProfile("staging")
+		_, err := LoadConfig(configPath)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown profile "staging" (defined: ci, local)`)
+	})
+
+	t.Run("saving keeps the profile values in the profile", func(t *testing.T) {
+		SetProfile("ci")
+		cfg, err := LoadConfig(configPath)
+		require.NoError(t, err)
+		cfg.ProjectName = "Checkout"
+		cfg.DefaultAssignee = "bob"
+		require.NoError(t, SaveConfig(cfg, configPath))
+
+		SetProfile("")
+		t.Setenv(ProfileEnvVar, "")
+		saved, err := LoadConfig(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, "Checkout", saved.ProjectName)
+		assert.Equal(t, "epic.xml", saved.CurrentEpic)
+		assert.Empty(t, saved.DefaultFormat)
+		assert.True(t, saved.Hints.Enabled)
+		assert.Equal(t, "bob", saved.DefaultAssignee, "values changed while the profile was applied are saved")
+		assert.Equal(t, "ci-bot", saved.Profiles["ci"].DefaultAssignee)
+	})
+}
+
+func TestValidateConfigDataProfiles(t *testing.T) {
+	report := ValidateConfigData([]byte(`{"current_epic": "epic.xml", "profiles": {"ci": {"default_format": "yaml", "asignee": "x"}}}`))
+	assert.Contains(t, report.Errors, `profiles.ci.default_format must be one of text, json, xml, markdown, got "yaml"`)
+	require.Len(t, report.Warnings, 1)
+	assert.Contains(t, report.Warnings[0], `profiles.ci.asignee`)
+}
@@ -0,0 +1,402 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mindreframer/agentpm/internal/customfields"
+	"github.com/mindreframer/agentpm/internal/exitcode"
+	"github.com/mindreframer/agentpm/internal/lint"
+	"github.com/mindreframer/agentpm/internal/policy"
+	"github.com/mindreframer/agentpm/internal/storage"
+)
+
+// fieldSpec describes one configuration key. The same table drives strict
+// parsing, type validation and the published JSON Schema.
+type fieldSpec struct {
+	Name        string
+	Type        string // "string", "boolean", "integer", "object" or "array" (of strings unless Items is set)
+	Description string
+	Enum        []string
+	Fields      []fieldSpec // known keys of an object
+	StringMap   bool        // object with arbitrary keys and string values
+	MapOf       []fieldSpec // object with arbitrary keys whose values are objects of these fields
+	Items       []fieldSpec // array of objects of these fields
+	Required    bool
+	Overridable bool // may be set in a per-epic sidecar
+}
+
+var configFields = []fieldSpec{
+	{Name: "current_epic", Type: "string", Required: true, Description: "Path to the active epic XML file"},
+	{Name: "previous_epic", Type: "string", Description: "Path to the previously active epic (used by switch --back)"},
+	{Name: "epics", Type: "array", Description: "Paths to further epics of the workspace (managed by agentpm epics, aggregated by status --all)"},
+	{Name: "project_name", Type: "string", Description: "Human readable project name"},
+	{Name: "default_assignee", Type: "string", Description: "Assignee used for new work"},
+	{Name: "test_gating", Type: "string", Overridable: true, Enum: []string{TestGatingStrict, TestGatingLenient, TestGatingOff}, Description: "How strictly tests gate the completion of their phase (and the start of the phases after it): strict until they pass or are cancelled, lenient only while they fail, off not at all (default strict)"},
+	{Name: "gate_tags", Type: "string", Overridable: true, Description: "Comma-separated test tags (e.g. unit,integration) whose tests gate phase completion; tests with only other tags, such as manual, do not block. Untagged tests always gate (default: all tests gate)"},
+	{Name: "assignees", Type: "string", Description: "Comma-separated assignees (e.g. agent-a,agent-b) start-next hands the tasks it starts to, following assignee_rotation"},
+	{Name: "assignee_rotation", Type: "string", Enum: []string{AssigneeRotationRoundRobin, AssigneeRotationLeastLoaded}, Description: "How start-next picks the assignee of a task without one of its own: round-robin through assignees, or least-loaded (fewest tasks in progress, then fewest tasks overall). Empty turns dispatching off"},
+	{Name: "strict", Type: "boolean", Overridable: true, Description: "Upgrade validation warnings to errors when completing tasks (missing tests, unchecked acceptance criteria)"},
+	{Name: "hints", Type: "object", Overridable: true, Description: "Hint generation and display settings", Fields: hintFields},
+	{Name: "default_format", Type: "string", Enum: Formats, Description: "Output format used when --format is not given (default: each command's own default)"},
+	{Name: "profiles", Type: "object", MapOf: profileSpec, Description: "Named settings such as ci or local, selected with --profile or AGENTPM_PROFILE and applied over the top-level values"},
+	{Name: "backups", Type: "integer", Description: "Previous versions of epic files kept as <epic>.bak.N by every save, restorable with agentpm restore (0 = none)"},
+	{Name: "api_version", Type: "integer", Description: "Version of the JSON/XML output, so output can evolve without breaking agent prompts and scripts (default 1; agentpm init uses the latest)"},
+	{Name: "templates_dir", Type: "string", Description: "Directory of local epic templates (default .agentpm/templates)"},
+	{Name: "template_registry", Type: "string", Description: "URL or path of the template registry index used by template fetch"},
+	{Name: "docs_file", Type: "string", Description: "Default output of agentpm docs and target of agentpm link (default: epic file with .md extension)"},
+	{Name: "policy_file", Type: "string", Description: "Policy file of organization rules checked by validate and before mutations (default .agentpm/policy.yaml)"},
+	{Name: "policies", Type: "array", Items: policyRuleSpec, Description: "Rules enforced like those of policy_file, e.g. requiring acceptance criteria and tests before a task is done; rule IDs must be unique across both"},
+	{Name: "lint", Type: "object", Fields: lintFields(), Description: "Severity of each agentpm lint rule: error fails lint, warn only reports, off disables the rule"},
+	{Name: "tokens_file", Type: "string", Description: "File of hashed API tokens with scopes, managed by agentpm token (default .agentpm/tokens.json)"},
+	{Name: "archive_dir", Type: "string", Description: "Directory agentpm archive moves completed epics to and agentpm compact keeps full copies in (default .agentpm/archive)"},
+	{Name: "storage_backend", Type: "string", Enum: []string{storage.BackendFile, storage.BackendSQLite}, Description: "How query --all and events --all read the epics of the workspace: file loads every epic file, sqlite keeps an index of them in storage_db that only re-reads changed files (default file)"},
+	{Name: "upcoming_window", Type: "string", Description: "How far ahead status reminds of deadlines and agentpm upcoming lists them, e.g. 3d, 2w or 36h (default 7d)"},
+	{Name: "storage_db", Type: "string", Description: "Index database of the sqlite storage backend (default .agentpm/index.db)"},
+	{Name: "server_url", Type: "string", Description: "Base URL of an agentpm server; agentpm link produces server URLs when set"},
+	{Name: "custom_fields", Type: "object", Description: "Typed custom fields of epics, phases, tasks and tests by name, set with agentpm edit --set custom.<name>=<value> and filtered with query 'task[custom.<name>=<value>]'", Fields: []fieldSpec{
+		{Name: "epic", Type: "object", MapOf: customFieldSpec, Description: "Custom fields of the epic"},
+		{Name: "phase", Type: "object", MapOf: customFieldSpec, Description: "Custom fields of phases"},
+		{Name: "task", Type: "object", MapOf: customFieldSpec, Description: "Custom fields of tasks"},
+		{Name: "test", Type: "object", MapOf: customFieldSpec, Description: "Custom fields of tests"},
+	}},
+	{Name: "warning_budgets", Type: "object", Description: "Maximum number of warnings per category validate --ci accepts before it fails; categories without a budget accept none", Fields: []fieldSpec{
+		{Name: "structure", Type: "integer", Description: "Unknown elements and attributes of the epic file"},
+		{Name: "missing_tests", Type: "integer", Description: "Tasks without tests"},
+		{Name: "policy", Type: "integer", Description: "Violated warning rules of the organization policy"},
+	}},
+}
+
+// hintFields describes the hint settings of the config and of its profiles
+var hintFields = []fieldSpec{
+	{Name: "enabled", Type: "boolean", Description: "Whether hints are enabled globally"},
+	{Name: "show_commands", Type: "boolean", Description: "Whether to show suggested commands in hints"},
+	{Name: "show_references", Type: "boolean", Description: "Whether to show documentation references"},
+	{Name: "priority", Type: "string", Enum: []string{"high", "medium", "low"}, Description: "Minimum priority of hints to show"},
+	{Name: "max_hints", Type: "integer", Description: "Maximum number of hints per error (0 = unlimited)"},
+	{Name: "customizations", Type: "object", StringMap: true, Description: "Custom hint text overrides"},
+}
+
+// profileSpec describes one profile of the profiles
+var profileSpec = []fieldSpec{
+	{Name: "current_epic", Type: "string", Description: "Epic file used instead of current_epic"},
+	{Name: "default_assignee", Type: "string", Description: "Assignee used for new work instead of default_assignee"},
+	{Name: "default_format", Type: "string", Enum: Formats, Description: "Output format used when --format is not given"},
+	{Name: "hints", Type: "object", Fields: hintFields, Description: "Hint settings applied over those of hints"},
+}
+
+// policyRuleSpec describes one rule of the policies
+var policyRuleSpec = []fieldSpec{
+	{Name: "id", Type: "string", Required: true, Description: "Name of the rule, shown in violations and used to suppress it"},
+	{Name: "check", Type: "string", Required: true, Enum: []string{policy.CheckPhaseMinTests, policy.CheckTaskMinTests, policy.CheckTaskAcceptanceCriteria, policy.CheckTaskMaxWIP}, Description: "Built-in check of the rule"},
+	{Name: "severity", Type: "string", Enum: []string{policy.SeverityError, policy.SeverityWarning, policy.SeverityOff}, Description: "error refuses the mutations the rule gates, warning reports them (default error)"},
+	{Name: "description", Type: "string", Description: "What the rule is for"},
+	{Name: "min", Type: "integer", Description: "Minimum number of tests of phase_min_tests and task_min_tests (default 1)"},
+	{Name: "max", Type: "string", Description: "Longest time a task may stay wip under task_max_wip, e.g. 72h or 3d"},
+	{Name: "on", Type: "string", Enum: []string{policy.OnStart, policy.OnDone}, Description: "When task_acceptance_criteria is checked: before the task is started, or before it and its phase are completed (default start)"},
+}
+
+// lintFields describes the severities of the lint rules
+func lintFields() []fieldSpec {
+	fields := make([]fieldSpec, len(lint.Rules))
+	for i, rule := range lint.Rules {
+		fields[i] = fieldSpec{
+			Name:        rule.Name,
+			Type:        "string",
+			Enum:        []string{lint.SeverityError, lint.SeverityWarn, lint.SeverityOff},
+			Description: fmt.Sprintf("%s (default %s)", rule.Description, rule.Severity),
+		}
+	}
+	return fields
+}
+
+// customFieldSpec describes the declaration of one custom field
+var customFieldSpec = []fieldSpec{
+	{Name: "type", Type: "string", Required: true, Enum: customfields.Types, Description: "Type values are checked against"},
+	{Name: "values", Type: "array", Description: "Allowed values of an enum, in their sort order"},
+	{Name: "description", Type: "string", Description: "What the field is for"},
+}
+
+// ValidationReport collects the problems found in a configuration file.
+// Errors make the configuration unusable, warnings (like unknown keys) do not.
+type ValidationReport struct {
+	Path     string   `json:"path"`
+	Errors   []string `json:"errors"`
+	Warnings []string `json:"warnings"`
+}
+
+// Valid reports whether the configuration has no errors
+func (r *ValidationReport) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+// ValidateConfigFile strictly validates the project config file at configPath
+func ValidateConfigFile(configPath string) (*ValidationReport, error) {
+	absPath, err := ResolveConfigPath(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, exitcode.Errorf(exitcode.NotFound, "config file not found: %s", absPath)
+		}
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	report := ValidateConfigData(data)
+	report.Path = absPath
+	return report, nil
+}
+
+// ValidateConfigData checks raw config JSON for syntax errors, unknown keys,
+// wrongly typed values and missing required keys
+func ValidateConfigData(data []byte) *ValidationReport {
+	report := &ValidationReport{Errors: []string{}, Warnings: []string{}}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("invalid JSON: %v", err))
+		return report
+	}
+
+	validateFields(raw, configFields, "", report)
+	return report
+}
+
+// validateOverrideData checks a per-epic sidecar, which may only contain overridable keys
+func validateOverrideData(raw map[string]interface{}) *ValidationReport {
+	report := &ValidationReport{Errors: []string{}, Warnings: []string{}}
+
+	var fields []fieldSpec
+	for _, field := range configFields {
+		if field.Overridable {
+			fields = append(fields, field)
+		}
+	}
+	validateFields(raw, fields, "", report)
+	return report
+}
+
+func validateFields(raw map[string]interface{}, fields []fieldSpec, prefix string, report *ValidationReport) {
+	known := make(map[string]fieldSpec, len(fields))
+	for _, field := range fields {
+		known[field.Name] = field
+		if field.Required {
+			if _, ok := raw[field.Name]; !ok {
+				report.Errors = append(report.Errors, fmt.Sprintf("%s%s is required", prefix, field.Name))
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field, ok := known[key]
+		if !ok {
+			warning := fmt.Sprintf("unknown key %q", prefix+key)
+			if suggestion := closestField(key, fields); suggestion != "" {
+				warning += fmt.Sprintf(" (did you mean %q?)", prefix+suggestion)
+			}
+			report.Warnings = append(report.Warnings, warning)
+			continue
+		}
+		validateValue(raw[key], field, prefix+key, report)
+	}
+}
+
+func validateValue(value interface{}, field fieldSpec, path string, report *ValidationReport) {
+	switch field.Type {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s must be a string", path))
+			return
+		}
+		if len(field.Enum) > 0 && s != "" && !containsString(field.Enum, s) {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s must be one of %s, got %q", path, strings.Join(field.Enum, ", "), s))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s must be a boolean", path))
+		}
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || n != math.Trunc(n) {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s must be an integer", path))
+			return
+		}
+		if n < 0 {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s must not be negative", path))
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s must be an array", path))
+			return
+		}
+		for i, item := range items {
+			if field.Items != nil {
+				if obj, ok := item.(map[string]interface{}); ok {
+					validateFields(obj, field.Items, fmt.Sprintf("%s[%d].", path, i), report)
+				} else {
+					report.Errors = append(report.Errors, fmt.Sprintf("%s[%d] must be an object", path, i))
+				}
+				continue
+			}
+			if _, ok := item.(string); !ok {
+				report.Errors = append(report.Errors, fmt.Sprintf("%s[%d] must be a string", path, i))
+			}
+		}
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s must be an object", path))
+			return
+		}
+		if field.StringMap {
+			for key, v := range obj {
+				if _, ok := v.(string); !ok {
+					report.Errors = append(report.Errors, fmt.Sprintf("%s.%s must be a string", path, key))
+				}
+			}
+			return
+		}
+		if field.MapOf != nil {
+			keys := make([]string, 0, len(obj))
+			for key := range obj {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				entry, ok := obj[key].(map[string]interface{})
+				if !ok {
+					report.Errors = append(report.Errors, fmt.Sprintf("%s.%s must be an object", path, key))
+					continue
+				}
+				validateFields(entry, field.MapOf, path+"."+key+".", report)
+			}
+			return
+		}
+		validateFields(obj, field.Fields, path+".", report)
+	}
+}
+
+// closestField suggests a known key for a likely typo
+func closestField(key string, fields []fieldSpec) string {
+	best, bestDistance := "", 3
+	for _, field := range fields {
+		if d := levenshtein(key, field.Name); d < bestDistance {
+			best, bestDistance = field.Name, d
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr := make([]int, len(b)+1)
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+	return prev[len(b)]
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// JSONSchema returns the JSON Schema (draft 2020-12) describing .agentpm.json
+func JSONSchema() map[string]interface{} {
+	schema := objectSchema(configFields)
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "agentpm project configuration"
+	return schema
+}
+
+func objectSchema(fields []fieldSpec) map[string]interface{} {
+	properties := make(map[string]interface{}, len(fields))
+	required := []string{}
+	for _, field := range fields {
+		properties[field.Name] = fieldSchema(field)
+		if field.Required {
+			required = append(required, field.Name)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func fieldSchema(field fieldSpec) map[string]interface{} {
+	var schema map[string]interface{}
+	switch {
+	case field.StringMap:
+		schema = map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": map[string]interface{}{"type": "string"},
+		}
+	case field.MapOf != nil:
+		schema = map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": objectSchema(field.MapOf),
+		}
+	case field.Type == "object":
+		schema = objectSchema(field.Fields)
+	case field.Items != nil:
+		schema = map[string]interface{}{
+			"type":  "array",
+			"items": objectSchema(field.Items),
+		}
+	case field.Type == "array":
+		schema = map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "string"},
+		}
+	default:
+		schema = map[string]interface{}{"type": field.Type}
+	}
+
+	if field.Description != "" {
+		schema["description"] = field.Description
+	}
+	if len(field.Enum) > 0 {
+		schema["enum"] = field.Enum
+	}
+	if field.Type == "integer" {
+		schema["minimum"] = 0
+	}
+	return schema
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfigData(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		errors   []string
+		warnings []string
+	}{
+		{
+			name: "valid config",
+			data: `{"current_epic": "epic-8.xml", "default_assignee": "alice", "hints": {"enabled": true, "max_hints": 2}}`,
+		},
+		{
+			name:   "invalid JSON",
+			data:   `{"current_epic": `,
+			errors: []string{"invalid JSON: unexpected end of JSON input"},
+		},
+		{
+			name:   "missing required key",
+			data:   `{"project_name": "test"}`,
+			errors: []string{"current_epic is required"},
+		},
+		{
+			name:     "unknown key with suggestion",
+			data:     `{"current_epic": "epic-8.xml", "defualt_assignee": "alice"}`,
+			warnings: []string{`unknown key "defualt_assignee" (did you mean "default_assignee"?)`},
+		},
+		{
+			name:     "unknown nested key",
+			data:     `{"current_epic": "epic-8.xml", "hints": {"colour": "red"}}`,
+			warnings: []string{`unknown key "hints.colour"`},
+		},
+		{
+			name: "wrong types",
+			data: `{"current_epic": 8, "hints": {"enabled": "yes", "max_hints": 1.5, "customizations": {"a": 1}}}`,
+			errors: []string{
+				"current_epic must be a string",
+				"hints.customizations.a must be a string",
+				"hints.enabled must be a boolean",
+				"hints.max_hints must be an integer",
+			},
+		},
+		{
+			name:   "epics must be a list of paths",
+			data:   `{"current_epic": "epic-8.xml", "epics": ["epic-9.xml", 10]}`,
+			errors: []string{"epics[1] must be a string"},
+		},
+		{
+			name:   "invalid enum value",
+			data:   `{"current_epic": "epic-8.xml", "test_gating": "sometimes"}`,
+			errors: []string{`test_gating must be one of strict, lenient, off, got "sometimes"`},
+		},
+		{
+			name: "policies",
+			data: `{"current_epic": "epic-8.xml", "policies": [{"id": "criteria-before-done", "check": "task_acceptance_criteria", "on": "done"}]}`,
+		},
+		{
+			name:     "invalid policies",
+			data:     `{"current_epic": "epic-8.xml", "policies": [{"check": "task_min_tests", "mni": 2}, "tests"]}`,
+			errors:   []string{"policies[0].id is required", "policies[1] must be an object"},
+			warnings: []string{`unknown key "policies[0].mni" (did you mean "policies[0].min"?)`},
+		},
+		{
+			name: "custom fields",
+			data: `{"current_epic": "epic-8.xml", "custom_fields": {"task": {"sprint": {"type": "int"}, "risk": {"type": "enum", "values": ["low", "high"]}}}}`,
+		},
+		{
+			name:     "invalid custom fields",
+			data:     `{"current_epic": "epic-8.xml", "custom_fields": {"task": {"sprint": {"type": "float"}, "team": "core", "risk": {"kind": "enum", "type": "enum"}}, "milestone": {}}}`,
+			errors:   []string{"custom_fields.task.sprint.type must be one of string, int, date, enum, got \"float\"", "custom_fields.task.team must be an object"},
+			warnings: []string{`unknown key "custom_fields.milestone"`, `unknown key "custom_fields.task.risk.kind"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := ValidateConfigData([]byte(tt.data))
+
+			expectedErrors := tt.errors
+			if expectedErrors == nil {
+				expectedErrors = []string{}
+			}
+			expectedWarnings := tt.warnings
+			if expectedWarnings == nil {
+				expectedWarnings = []string{}
+			}
+
+			assert.Equal(t, expectedErrors, report.Errors)
+			assert.Equal(t, expectedWarnings, report.Warnings)
+			assert.Equal(t, len(expectedErrors) == 0, report.Valid())
+		})
+	}
+}
+
+func TestLoadConfig_StrictParsing(t *testing.T) {
+	t.Run("unknown keys become warnings", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), ".agentpm.json")
+		require.NoError(t, os.WriteFile(configPath, []byte(`{"current_epic": "epic-8.xml", "projct_name": "x"}`), 0644))
+
+		cfg, err := LoadConfig(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, []string{`unknown key "projct_name" (did you mean "project_name"?)`}, cfg.Warnings)
+	})
+
+	t.Run("type errors are reported by key", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), ".agentpm.json")
+		require.NoError(t, os.WriteFile(configPath, []byte(`{"current_epic": "epic-8.xml", "hints": {"max_hints": "3"}}`), 0644))
+
+		_, err := LoadConfig(configPath)
+		require.Error(t, err)
+		assert.Equal(t, "invalid configuration: hints.max_hints must be an integer", err.Error())
+	})
+
+	t.Run("custom fields are declared consistently", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), ".agentpm.json")
+		require.NoError(t, os.WriteFile(configPath, []byte(`{"current_epic": "epic-8.xml", "custom_fields": {"task": {"risk": {"type": "enum"}}}}`), 0644))
+
+		_, err := LoadConfig(configPath)
+		require.Error(t, err)
+		assert.Equal(t, "invalid configuration: invalid custom_fields.task.risk: an enum requires values", err.Error())
+	})
+}
+
+func TestJSONSchema(t *testing.T) {
+	schema := JSONSchema()
+
+	assert.Equal(t, "object", schema["type"])
+	assert.Equal(t, []string{"current_epic"}, schema["required"])
+
+	properties := schema["properties"].(map[string]interface{})
+	for _, field := range configFields {
+		assert.Contains(t, properties, field.Name)
+	}
+
+	testGating := properties["test_gating"].(map[string]interface{})
+	assert.Equal(t, []string{TestGatingStrict, TestGatingLenient, TestGatingOff}, testGating["enum"])
+
+	hints := properties["hints"].(map[string]interface{})
+	hintProperties := hints["properties"].(map[string]interface{})
+	maxHints := hintProperties["max_hints"].(map[string]interface{})
+	assert.Equal(t, "integer", maxHints["type"])
+
+	customFields := properties["custom_fields"].(map[string]interface{})
+	taskFields := customFields["properties"].(map[string]interface{})["task"].(map[string]interface{})
+	definition := taskFields["additionalProperties"].(map[string]interface{})
+	assert.Equal(t, []string{"type"}, definition["required"])
+}
@@ -25,9 +25,11 @@ type ProgressSummary struct {
 type TaskDetails struct {
 	ID                 string      `json:"id" xml:"id,attr"`
 	PhaseID            string      `json:"phase_id" xml:"phase_id,attr"`
+	ParentTaskID       string      `json:"parent_task_id,omitempty" xml:"parent_task_id,attr,omitempty"`
 	Name               string      `json:"name" xml:"name"`
 	Description        string      `json:"description" xml:"description"`
 	AcceptanceCriteria string      `json:"acceptance_criteria" xml:"acceptance_criteria"`
+	SpecRef            string      `json:"spec_ref,omitempty" xml:"spec_ref,omitempty"`
 	Status             epic.Status `json:"status" xml:"status,attr"`
 	Assignee           string      `json:"assignee" xml:"assignee,attr,omitempty"`
 	StartedAt          *time.Time  `json:"started_at" xml:"started_at,omitempty"`
@@ -40,6 +42,7 @@ type PhaseDetails struct {
 	Name         string           `json:"name" xml:"name"`
 	Description  string           `json:"description" xml:"description"`
 	Deliverables string           `json:"deliverables" xml:"deliverables"`
+	SpecRef      string           `json:"spec_ref,omitempty" xml:"spec_ref,omitempty"`
 	Status       epic.Status      `json:"status" xml:"status,attr"`
 	StartedAt    *time.Time       `json:"started_at" xml:"started_at,omitempty"`
 	CompletedAt  *time.Time       `json:"completed_at" xml:"completed_at,omitempty"`
@@ -48,17 +51,18 @@ type PhaseDetails struct {
 
 // TestDetails represents detailed test information with all fields
 type TestDetails struct {
-	ID          string          `json:"id" xml:"id,attr"`
-	TaskID      string          `json:"task_id" xml:"task_id,attr"`
-	PhaseID     string          `json:"phase_id" xml:"phase_id,attr"`
-	Name        string          `json:"name" xml:"name"`
-	Description string          `json:"description" xml:"description"`
-	Status      epic.Status     `json:"status" xml:"status,attr"`
-	TestStatus  epic.TestStatus `json:"test_status" xml:"test_status,attr"`
-	StartedAt   *time.Time      `json:"started_at" xml:"started_at,omitempty"`
-	PassedAt    *time.Time      `json:"passed_at" xml:"passed_at,omitempty"`
-	FailedAt    *time.Time      `json:"failed_at" xml:"failed_at,omitempty"`
-	FailureNote string          `json:"failure_note" xml:"failure_note,omitempty"`
+	ID          string               `json:"id" xml:"id,attr"`
+	TaskID      string               `json:"task_id" xml:"task_id,attr"`
+	PhaseID     string               `json:"phase_id" xml:"phase_id,attr"`
+	Name        string               `json:"name" xml:"name"`
+	Description string               `json:"description" xml:"description"`
+	Status      epic.Status          `json:"status" xml:"status,attr"`
+	TestStatus  epic.TestStatus      `json:"test_status" xml:"test_status,attr"`
+	StartedAt   *time.Time           `json:"started_at" xml:"started_at,omitempty"`
+	PassedAt    *time.Time           `json:"passed_at" xml:"passed_at,omitempty"`
+	FailedAt    *time.Time           `json:"failed_at" xml:"failed_at,omitempty"`
+	FailureNote string               `json:"failure_note" xml:"failure_note,omitempty"`
+	Context     *epic.FailureContext `json:"failure_context,omitempty" xml:"failure_context,omitempty"`
 }
 
 // TaskWithTests represents a task with its associated tests
@@ -71,8 +75,11 @@ type TaskWithTests struct {
 type TaskContext struct {
 	TaskDetails  TaskDetails   `json:"task_details" xml:"task_details"`
 	ParentPhase  *PhaseDetails `json:"parent_phase,omitempty" xml:"parent_phase,omitempty"`
+	ParentTask   *TaskDetails  `json:"parent_task,omitempty" xml:"parent_task,omitempty"`
+	SubTasks     []TaskDetails `json:"sub_tasks,omitempty" xml:"sub_tasks>task,omitempty"`
 	SiblingTasks []TaskDetails `json:"sibling_tasks,omitempty" xml:"sibling_tasks>task,omitempty"`
 	ChildTests   []TestDetails `json:"child_tests,omitempty" xml:"child_tests>test,omitempty"`
+	Notes        []epic.Note   `json:"notes,omitempty" xml:"notes>note,omitempty"`
 }
 
 // PhaseContext represents the full context around a phase
@@ -82,6 +89,7 @@ type PhaseContext struct {
 	AllTasks        []TaskWithTests  `json:"all_tasks,omitempty" xml:"all_tasks>task,omitempty"`
 	PhaseTests      []TestDetails    `json:"phase_tests,omitempty" xml:"phase_tests>test,omitempty"`
 	SiblingPhases   []PhaseDetails   `json:"sibling_phases,omitempty" xml:"sibling_phases>phase,omitempty"`
+	Notes           []epic.Note      `json:"notes,omitempty" xml:"notes>note,omitempty"`
 }
 
 // TestContext represents the full context around a test
@@ -90,6 +98,7 @@ type TestContext struct {
 	ParentTask   *TaskDetails  `json:"parent_task,omitempty" xml:"parent_task,omitempty"`
 	ParentPhase  *PhaseDetails `json:"parent_phase,omitempty" xml:"parent_phase,omitempty"`
 	SiblingTests []TestDetails `json:"sibling_tests,omitempty" xml:"sibling_tests>test,omitempty"`
+	Notes        []epic.Note   `json:"notes,omitempty" xml:"notes>note,omitempty"`
 }
 
 // ContextResult is a wrapper for all context types with additional metadata
@@ -37,9 +37,11 @@ func (e *Engine) GetTaskContext(taskID string, includeFullDetails bool) (*TaskCo
 		TaskDetails: TaskDetails{
 			ID:                 task.ID,
 			PhaseID:            task.PhaseID,
+			ParentTaskID:       task.ParentTaskID,
 			Name:               task.Name,
 			Description:        task.Description,
 			AcceptanceCriteria: task.AcceptanceCriteria,
+			SpecRef:            task.SpecRef,
 			Status:             task.Status,
 			Assignee:           task.Assignee,
 			StartedAt:          task.StartedAt,
@@ -67,6 +69,9 @@ func (e *Engine) GetTaskContext(taskID string, includeFullDetails bool) (*TaskCo
 			}
 		}
 
+		// Get the parent task and the sub-tasks
+		context.ParentTask, context.SubTasks = e.getTaskHierarchy(task)
+
 		// Get sibling tasks in the same phase
 		if task.PhaseID != "" {
 			context.SiblingTasks = e.getSiblingTasks(taskID, task.PhaseID, includeFullDetails)
@@ -74,6 +79,8 @@ func (e *Engine) GetTaskContext(taskID string, includeFullDetails bool) (*TaskCo
 
 		// Get child tests for this task
 		context.ChildTests = e.getChildTests(taskID, includeFullDetails)
+
+		context.Notes = task.Notes
 	}
 
 	return context, nil
@@ -92,6 +99,7 @@ func (e *Engine) GetPhaseContext(phaseID string, includeFullDetails bool) (*Phas
 			Name:         phase.Name,
 			Description:  phase.Description,
 			Deliverables: phase.Deliverables,
+			SpecRef:      phase.SpecRef,
 			Status:       phase.Status,
 			StartedAt:    phase.StartedAt,
 			CompletedAt:  phase.CompletedAt,
@@ -110,6 +118,8 @@ func (e *Engine) GetPhaseContext(phaseID string, includeFullDetails bool) (*Phas
 
 		// Get sibling phases
 		context.SiblingPhases = e.getSiblingPhases(phaseID, includeFullDetails)
+
+		context.Notes = phase.Notes
 	}
 
 	return context, nil
@@ -135,6 +145,7 @@ func (e *Engine) GetTestContext(testID string, includeFullDetails bool) (*TestCo
 			PassedAt:    test.PassedAt,
 			FailedAt:    test.FailedAt,
 			FailureNote: test.FailureNote,
+			Context:     test.FailureContext,
 		},
 	}
 
@@ -181,6 +192,8 @@ func (e *Engine) GetTestContext(testID string, includeFullDetails bool) (*TestCo
 		if test.TaskID != "" {
 			context.SiblingTests = e.getSiblingTests(testID, test.TaskID, includeFullDetails)
 		}
+
+		context.Notes = test.Notes
 	}
 
 	return context, nil
@@ -275,17 +288,26 @@ func (e *Engine) getSiblingTasks(taskID, phaseID string, includeFullDetails bool
 		return nil
 	}
 
+	// The parent task and the sub-tasks are listed on their own
+	parentTaskID := ""
+	for _, task := range epicData.Tasks {
+		if task.ID == taskID {
+			parentTaskID = task.ParentTaskID
+		}
+	}
+
 	var siblings []TaskDetails
 	for _, task := range epicData.Tasks {
-		if task.PhaseID == phaseID && task.ID != taskID {
+		if task.PhaseID == phaseID && task.ID != taskID && task.ID != parentTaskID && task.ParentTaskID != taskID {
 			taskDetails := TaskDetails{
-				ID:          task.ID,
-				PhaseID:     task.PhaseID,
-				Name:        task.Name,
-				Status:      task.Status,
-				Assignee:    task.Assignee,
-				StartedAt:   task.StartedAt,
-				CompletedAt: task.CompletedAt,
+				ID:           task.ID,
+				PhaseID:      task.PhaseID,
+				ParentTaskID: task.ParentTaskID,
+				Name:         task.Name,
+				Status:       task.Status,
+				Assignee:     task.Assignee,
+				StartedAt:    task.StartedAt,
+				CompletedAt:  task.CompletedAt,
 			}
 
 			if includeFullDetails {
@@ -300,6 +322,44 @@ func (e *Engine) getSiblingTasks(taskID, phaseID string, includeFullDetails bool
 	return siblings
 }
 
+// getTaskHierarchy returns the parent of a sub-task and the sub-tasks of a task
+func (e *Engine) getTaskHierarchy(task *epic.Task) (*TaskDetails, []TaskDetails) {
+	epicData, err := e.queryService.GetEpic()
+	if err != nil {
+		return nil, nil
+	}
+
+	var parent *TaskDetails
+	if parentTask := epicData.ParentTask(task); parentTask != nil {
+		parent = &TaskDetails{
+			ID:          parentTask.ID,
+			PhaseID:     parentTask.PhaseID,
+			Name:        parentTask.Name,
+			Description: parentTask.Description,
+			Status:      parentTask.Status,
+			Assignee:    parentTask.Assignee,
+			StartedAt:   parentTask.StartedAt,
+			CompletedAt: parentTask.CompletedAt,
+		}
+	}
+
+	var subTasks []TaskDetails
+	for _, subTask := range epicData.SubTasks(task.ID) {
+		subTasks = append(subTasks, TaskDetails{
+			ID:           subTask.ID,
+			PhaseID:      subTask.PhaseID,
+			ParentTaskID: subTask.ParentTaskID,
+			Name:         subTask.Name,
+			Description:  subTask.Description,
+			Status:       subTask.Status,
+			Assignee:     subTask.Assignee,
+			StartedAt:    subTask.StartedAt,
+			CompletedAt:  subTask.CompletedAt,
+		})
+	}
+	return parent, subTasks
+}
+
 func (e *Engine) getChildTests(taskID string, includeFullDetails bool) []TestDetails {
 	epicData, err := e.queryService.GetEpic()
 	if err != nil {
@@ -324,6 +384,7 @@ func (e *Engine) getChildTests(taskID string, includeFullDetails bool) []TestDet
 			if includeFullDetails {
 				testDetails.Description = test.Description
 				testDetails.FailureNote = test.FailureNote
+				testDetails.Context = test.FailureContext
 			}
 
 			tests = append(tests, testDetails)
@@ -394,6 +455,7 @@ func (e *Engine) getPhaseTests(phaseID string, includeFullDetails bool) []TestDe
 			if includeFullDetails {
 				testDetails.Description = test.Description
 				testDetails.FailureNote = test.FailureNote
+				testDetails.Context = test.FailureContext
 			}
 
 			tests = append(tests, testDetails)
@@ -456,6 +518,7 @@ func (e *Engine) getSiblingTests(testID, taskID string, includeFullDetails bool)
 			if includeFullDetails {
 				testDetails.Description = test.Description
 				testDetails.FailureNote = test.FailureNote
+				testDetails.Context = test.FailureContext
 			}
 
 			siblings = append(siblings, testDetails)
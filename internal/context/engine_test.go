@@ -1,6 +1,8 @@
 package context
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 	"time"
 
@@ -559,3 +561,72 @@ func createTestEpic() *epic.Epic {
 
 	return testEpic
 }
+
+func TestEngine_GetTaskContext_SubTasks(t *testing.T) {
+	memory := storage.NewMemoryStorage()
+	queryService := query.NewQueryService(memory)
+	err := memory.SaveEpic(&epic.Epic{
+		ID:     "epic-1",
+		Phases: []epic.Phase{{ID: "1A", Name: "Phase"}},
+		Tasks: []epic.Task{
+			{ID: "1A_1", PhaseID: "1A", Name: "Parent", Status: epic.StatusWIP},
+			{ID: "1A_1a", PhaseID: "1A", Name: "Child A", Status: epic.StatusCompleted, ParentTaskID: "1A_1"},
+			{ID: "1A_1b", PhaseID: "1A", Name: "Child B", Status: epic.StatusPending, ParentTaskID: "1A_1"},
+			{ID: "1A_2", PhaseID: "1A", Name: "Other", Status: epic.StatusPending},
+		},
+	}, "epic.xml")
+	if err != nil {
+		t.Fatalf("Failed to save test epic: %v", err)
+	}
+	if err := queryService.LoadEpic("epic.xml"); err != nil {
+		t.Fatalf("Failed to load test epic: %v", err)
+	}
+	engine := NewEngine(queryService)
+
+	parentContext, err := engine.GetTaskContext("1A_1", true)
+	if err != nil {
+		t.Fatalf("GetTaskContext() error = %v", err)
+	}
+	if len(parentContext.SubTasks) != 2 || parentContext.SubTasks[0].ID != "1A_1a" {
+		t.Errorf("GetTaskContext() sub-tasks = %v, want 1A_1a and 1A_1b", parentContext.SubTasks)
+	}
+	if parentContext.ParentTask != nil {
+		t.Errorf("GetTaskContext() parent task = %v, want none", parentContext.ParentTask)
+	}
+	if len(parentContext.SiblingTasks) != 1 || parentContext.SiblingTasks[0].ID != "1A_2" {
+		t.Errorf("GetTaskContext() siblings = %v, want only 1A_2 (sub-tasks are listed on their own)", parentContext.SiblingTasks)
+	}
+
+	childContext, err := engine.GetTaskContext("1A_1b", true)
+	if err != nil {
+		t.Fatalf("GetTaskContext() error = %v", err)
+	}
+	if childContext.ParentTask == nil || childContext.ParentTask.ID != "1A_1" {
+		t.Errorf("GetTaskContext() parent task = %v, want 1A_1", childContext.ParentTask)
+	}
+	if len(childContext.SiblingTasks) != 2 || childContext.SiblingTasks[0].ParentTaskID != "1A_1" {
+		t.Errorf("GetTaskContext() siblings = %v, want 1A_1a (sub-task of 1A_1) and 1A_2", childContext.SiblingTasks)
+	}
+
+	var out bytes.Buffer
+	if err := NewFormatter("text").FormatTaskContext(parentContext, &out); err != nil {
+		t.Fatalf("FormatTaskContext() error = %v", err)
+	}
+	if want := "Sub-Tasks (2):\n  1A_1a - Child A [completed]\n  1A_1b - Child B [pending]\n"; !strings.Contains(out.String(), want) {
+		t.Errorf("FormatTaskContext() = %q, want it to contain %q", out.String(), want)
+	}
+
+	out.Reset()
+	if err := NewFormatter("xml").FormatTaskContext(childContext, &out); err != nil {
+		t.Fatalf("FormatTaskContext() error = %v", err)
+	}
+	for _, want := range []string{
+		`<task_context id="1A_1b" phase_id="1A" parent_task_id="1A_1" status="pending">`,
+		`<parent_task id="1A_1" status="wip">`,
+		`<task id="1A_1a" parent_task_id="1A_1" status="completed">`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("FormatTaskContext() = %q, want it to contain %q", out.String(), want)
+		}
+	}
+}
@@ -2,10 +2,13 @@ package context
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"io"
 	"strings"
 	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
 )
 
 // OutputFormatter handles formatting context results for different output types
@@ -40,8 +43,12 @@ func NewFormatter(format string) OutputFormatter {
 
 func (f *XMLFormatter) FormatTaskContext(ctx *TaskContext, writer io.Writer) error {
 	// Create XML structure according to specification
-	fmt.Fprintf(writer, "<task_context id=\"%s\" phase_id=\"%s\" status=\"%s\">\n",
-		ctx.TaskDetails.ID, ctx.TaskDetails.PhaseID, ctx.TaskDetails.Status)
+	parentTaskAttr := ""
+	if ctx.TaskDetails.ParentTaskID != "" {
+		parentTaskAttr = fmt.Sprintf(" parent_task_id=\"%s\"", ctx.TaskDetails.ParentTaskID)
+	}
+	fmt.Fprintf(writer, "<task_context id=\"%s\" phase_id=\"%s\"%s status=\"%s\">\n",
+		ctx.TaskDetails.ID, ctx.TaskDetails.PhaseID, parentTaskAttr, ctx.TaskDetails.Status)
 
 	// Task details
 	fmt.Fprintf(writer, "    <task_details>\n")
@@ -52,6 +59,9 @@ func (f *XMLFormatter) FormatTaskContext(ctx *TaskContext, writer io.Writer) err
 	if ctx.TaskDetails.AcceptanceCriteria != "" {
 		fmt.Fprintf(writer, "        <acceptance_criteria>%s</acceptance_criteria>\n", ctx.TaskDetails.AcceptanceCriteria)
 	}
+	if ctx.TaskDetails.SpecRef != "" {
+		fmt.Fprintf(writer, "        <spec_ref>%s</spec_ref>\n", ctx.TaskDetails.SpecRef)
+	}
 	if ctx.TaskDetails.Assignee != "" {
 		fmt.Fprintf(writer, "        <assignee>%s</assignee>\n", ctx.TaskDetails.Assignee)
 	}
@@ -63,6 +73,8 @@ func (f *XMLFormatter) FormatTaskContext(ctx *TaskContext, writer io.Writer) err
 	}
 	fmt.Fprintf(writer, "    </task_details>\n")
 
+	f.writeNotesXML(writer, ctx.Notes)
+
 	// Parent phase
 	if ctx.ParentPhase != nil {
 		fmt.Fprintf(writer, "    <parent_phase id=\"%s\" status=\"%s\">\n", ctx.ParentPhase.ID, ctx.ParentPhase.Status)
@@ -82,11 +94,37 @@ func (f *XMLFormatter) FormatTaskContext(ctx *TaskContext, writer io.Writer) err
 		fmt.Fprintf(writer, "    </parent_phase>\n")
 	}
 
+	// Parent task and sub-tasks
+	if ctx.ParentTask != nil {
+		fmt.Fprintf(writer, "    <parent_task id=\"%s\" status=\"%s\">\n", ctx.ParentTask.ID, ctx.ParentTask.Status)
+		fmt.Fprintf(writer, "        <name>%s</name>\n", ctx.ParentTask.Name)
+		if ctx.ParentTask.Description != "" {
+			fmt.Fprintf(writer, "        <description>%s</description>\n", ctx.ParentTask.Description)
+		}
+		fmt.Fprintf(writer, "    </parent_task>\n")
+	}
+	if len(ctx.SubTasks) > 0 {
+		fmt.Fprintf(writer, "    <sub_tasks>\n")
+		for _, task := range ctx.SubTasks {
+			fmt.Fprintf(writer, "        <task id=\"%s\" status=\"%s\">\n", task.ID, task.Status)
+			fmt.Fprintf(writer, "            <name>%s</name>\n", task.Name)
+			if task.Description != "" {
+				fmt.Fprintf(writer, "            <description>%s</description>\n", task.Description)
+			}
+			fmt.Fprintf(writer, "        </task>\n")
+		}
+		fmt.Fprintf(writer, "    </sub_tasks>\n")
+	}
+
 	// Sibling tasks
 	if len(ctx.SiblingTasks) > 0 {
 		fmt.Fprintf(writer, "    <sibling_tasks>\n")
 		for _, task := range ctx.SiblingTasks {
-			fmt.Fprintf(writer, "        <task id=\"%s\" status=\"%s\">\n", task.ID, task.Status)
+			if task.ParentTaskID != "" {
+				fmt.Fprintf(writer, "        <task id=\"%s\" parent_task_id=\"%s\" status=\"%s\">\n", task.ID, task.ParentTaskID, task.Status)
+			} else {
+				fmt.Fprintf(writer, "        <task id=\"%s\" status=\"%s\">\n", task.ID, task.Status)
+			}
 			fmt.Fprintf(writer, "            <name>%s</name>\n", task.Name)
 			if task.Description != "" {
 				fmt.Fprintf(writer, "            <description>%s</description>\n", task.Description)
@@ -142,11 +180,16 @@ func (f *XMLFormatter) FormatPhaseContext(ctx *PhaseContext, writer io.Writer) e
 	if ctx.PhaseDetails.Deliverables != "" {
 		fmt.Fprintf(writer, "        <deliverables>%s</deliverables>\n", ctx.PhaseDetails.Deliverables)
 	}
+	if ctx.PhaseDetails.SpecRef != "" {
+		fmt.Fprintf(writer, "        <spec_ref>%s</spec_ref>\n", ctx.PhaseDetails.SpecRef)
+	}
 	if ctx.PhaseDetails.StartedAt != nil {
 		fmt.Fprintf(writer, "        <started_at>%s</started_at>\n", ctx.PhaseDetails.StartedAt.Format(time.RFC3339))
 	}
 	fmt.Fprintf(writer, "    </phase_details>\n")
 
+	f.writeNotesXML(writer, ctx.Notes)
+
 	// Progress summary
 	if ctx.ProgressSummary != nil {
 		f.writeProgressXML(writer, ctx.ProgressSummary, "    ")
@@ -256,6 +299,8 @@ func (f *XMLFormatter) FormatTestContext(ctx *TestContext, writer io.Writer) err
 	}
 	fmt.Fprintf(writer, "    </test_details>\n")
 
+	f.writeNotesXML(writer, ctx.Notes)
+
 	// Parent task
 	if ctx.ParentTask != nil {
 		fmt.Fprintf(writer, "    <parent_task id=\"%s\" status=\"%s\">\n", ctx.ParentTask.ID, ctx.ParentTask.Status)
@@ -320,6 +365,28 @@ func (f *XMLFormatter) writeProgressXML(writer io.Writer, progress *ProgressSumm
 	fmt.Fprintf(writer, "%s</progress>\n", indent)
 }
 
+// writeNotesXML writes the notes on the entity, their text escaped
+func (f *XMLFormatter) writeNotesXML(writer io.Writer, notes []epic.Note) {
+	if len(notes) == 0 {
+		return
+	}
+	fmt.Fprintf(writer, "    <notes>\n")
+	for _, note := range notes {
+		fmt.Fprintf(writer, "        <note")
+		if note.Author != "" {
+			fmt.Fprintf(writer, " author=\"%s\"", escapeXML(note.Author))
+		}
+		fmt.Fprintf(writer, " created_at=\"%s\">%s</note>\n", note.CreatedAt.Format(time.RFC3339), escapeXML(note.Text))
+	}
+	fmt.Fprintf(writer, "    </notes>\n")
+}
+
+func escapeXML(text string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
+
 // JSON Formatter Implementation
 
 func (f *JSONFormatter) FormatTaskContext(ctx *TaskContext, writer io.Writer) error {
@@ -347,6 +414,10 @@ func (f *TextFormatter) FormatTaskContext(ctx *TaskContext, writer io.Writer) er
 	fmt.Fprintf(writer, "Phase: %s\n", ctx.TaskDetails.PhaseID)
 	fmt.Fprintf(writer, "Status: %s\n", ctx.TaskDetails.Status)
 
+	if ctx.ParentTask != nil {
+		fmt.Fprintf(writer, "Parent Task: %s - %s [%s]\n", ctx.ParentTask.ID, ctx.ParentTask.Name, ctx.ParentTask.Status)
+	}
+
 	if ctx.TaskDetails.Description != "" {
 		fmt.Fprintf(writer, "Description: %s\n", ctx.TaskDetails.Description)
 	}
@@ -355,6 +426,10 @@ func (f *TextFormatter) FormatTaskContext(ctx *TaskContext, writer io.Writer) er
 		fmt.Fprintf(writer, "Acceptance Criteria:\n%s\n", indentText(ctx.TaskDetails.AcceptanceCriteria, "  "))
 	}
 
+	if ctx.TaskDetails.SpecRef != "" {
+		fmt.Fprintf(writer, "Spec: %s\n", ctx.TaskDetails.SpecRef)
+	}
+
 	if ctx.TaskDetails.Assignee != "" {
 		fmt.Fprintf(writer, "Assignee: %s\n", ctx.TaskDetails.Assignee)
 	}
@@ -367,6 +442,8 @@ func (f *TextFormatter) FormatTaskContext(ctx *TaskContext, writer io.Writer) er
 		fmt.Fprintf(writer, "Completed: %s\n", ctx.TaskDetails.CompletedAt.Format("2006-01-02 15:04:05"))
 	}
 
+	writeNotesText(writer, ctx.Notes)
+
 	// Parent phase
 	if ctx.ParentPhase != nil {
 		fmt.Fprintf(writer, "\nParent Phase: %s (%s)\n", ctx.ParentPhase.Name, ctx.ParentPhase.ID)
@@ -382,11 +459,26 @@ func (f *TextFormatter) FormatTaskContext(ctx *TaskContext, writer io.Writer) er
 		}
 	}
 
+	// Sub-tasks
+	if len(ctx.SubTasks) > 0 {
+		fmt.Fprintf(writer, "\nSub-Tasks (%d):\n", len(ctx.SubTasks))
+		for _, task := range ctx.SubTasks {
+			fmt.Fprintf(writer, "  %s - %s [%s]\n", task.ID, task.Name, task.Status)
+			if task.Description != "" {
+				fmt.Fprintf(writer, "     Description: %s\n", task.Description)
+			}
+		}
+	}
+
 	// Sibling tasks
 	if len(ctx.SiblingTasks) > 0 {
 		fmt.Fprintf(writer, "\nSibling Tasks (%d):\n", len(ctx.SiblingTasks))
 		for _, task := range ctx.SiblingTasks {
-			fmt.Fprintf(writer, "  %s - %s [%s]\n", task.ID, task.Name, task.Status)
+			if task.ParentTaskID != "" {
+				fmt.Fprintf(writer, "  %s - %s [%s] (sub-task of %s)\n", task.ID, task.Name, task.Status, task.ParentTaskID)
+			} else {
+				fmt.Fprintf(writer, "  %s - %s [%s]\n", task.ID, task.Name, task.Status)
+			}
 			if task.Description != "" {
 				fmt.Fprintf(writer, "    Description: %s\n", task.Description)
 			}
@@ -426,6 +518,10 @@ func (f *TextFormatter) FormatPhaseContext(ctx *PhaseContext, writer io.Writer)
 		fmt.Fprintf(writer, "Deliverables:\n%s\n", indentText(ctx.PhaseDetails.Deliverables, "  "))
 	}
 
+	if ctx.PhaseDetails.SpecRef != "" {
+		fmt.Fprintf(writer, "Spec: %s\n", ctx.PhaseDetails.SpecRef)
+	}
+
 	if ctx.PhaseDetails.StartedAt != nil {
 		fmt.Fprintf(writer, "Started: %s\n", ctx.PhaseDetails.StartedAt.Format("2006-01-02 15:04:05"))
 	}
@@ -434,6 +530,8 @@ func (f *TextFormatter) FormatPhaseContext(ctx *PhaseContext, writer io.Writer)
 		fmt.Fprintf(writer, "Completed: %s\n", ctx.PhaseDetails.CompletedAt.Format("2006-01-02 15:04:05"))
 	}
 
+	writeNotesText(writer, ctx.Notes)
+
 	// Progress summary
 	if ctx.ProgressSummary != nil {
 		fmt.Fprintf(writer, "\nProgress Summary:\n")
@@ -530,6 +628,9 @@ func (f *TextFormatter) FormatTestContext(ctx *TestContext, writer io.Writer) er
 	if ctx.TestDetails.FailureNote != "" {
 		fmt.Fprintf(writer, "Failure Note: %s\n", ctx.TestDetails.FailureNote)
 	}
+	writeFailureContextText(writer, ctx.TestDetails.Context)
+
+	writeNotesText(writer, ctx.Notes)
 
 	// Parent task
 	if ctx.ParentTask != nil {
@@ -567,6 +668,21 @@ func (f *TextFormatter) FormatTestContext(ctx *TestContext, writer io.Writer) er
 	return nil
 }
 
+// writeNotesText lists the notes on the entity, oldest first
+func writeNotesText(writer io.Writer, notes []epic.Note) {
+	if len(notes) == 0 {
+		return
+	}
+	fmt.Fprintf(writer, "\nNotes (%d):\n", len(notes))
+	for _, note := range notes {
+		header := note.CreatedAt.Format("2006-01-02 15:04")
+		if note.Author != "" {
+			header += " " + note.Author
+		}
+		fmt.Fprintf(writer, "  [%s] %s\n", header, note.Text)
+	}
+}
+
 // Helper function to indent multi-line text
 func indentText(text, indent string) string {
 	lines := strings.Split(text, "\n")
@@ -577,3 +693,23 @@ func indentText(text, indent string) string {
 	}
 	return strings.Join(lines, "\n")
 }
+
+// writeFailureContextText writes the expected and actual values, links and
+// stack of the last failure of a test
+func writeFailureContextText(writer io.Writer, failureContext *epic.FailureContext) {
+	if failureContext.IsEmpty() {
+		return
+	}
+	if failureContext.Expected != "" {
+		fmt.Fprintf(writer, "Expected: %s\n", failureContext.Expected)
+	}
+	if failureContext.Actual != "" {
+		fmt.Fprintf(writer, "Actual: %s\n", failureContext.Actual)
+	}
+	for _, link := range failureContext.Links {
+		fmt.Fprintf(writer, "Link: %s\n", link)
+	}
+	if failureContext.Stack != "" {
+		fmt.Fprintf(writer, "Stack:\n%s\n", failureContext.Stack)
+	}
+}
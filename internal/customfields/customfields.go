@@ -0,0 +1,148 @@
+// Package customfields checks the typed custom fields a project declares for
+// its epics, phases, tasks and tests in the custom_fields section of the
+// config. Values are stored as strings in the epic file; their type decides
+// what may be set and how selectors compare them.
+package customfields
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// Types of custom fields
+const (
+	TypeString = "string"
+	TypeInt    = "int"
+	TypeDate   = "date" // A day (2025-09-15) or an RFC3339 timestamp, see epic.ParseDate
+	TypeEnum   = "enum" // One of the declared values
+)
+
+// Types lists the types a custom field can have
+var Types = []string{TypeString, TypeInt, TypeDate, TypeEnum}
+
+// EntityTypes lists the entities that can have custom fields
+var EntityTypes = []string{"epic", "phase", "task", "test"}
+
+// Prefix marks a custom field in edit keys and query selectors: custom.sprint
+const Prefix = "custom."
+
+// Definition declares one custom field
+type Definition struct {
+	Type        string   `json:"type"`
+	Values      []string `json:"values,omitempty"` // Allowed values of an enum, in their order
+	Description string   `json:"description,omitempty"`
+}
+
+// Schema declares the custom fields of each entity type by name, as in the
+// custom_fields section of the config:
+//
+//	"custom_fields": {"task": {"sprint": {"type": "int"}}}
+type Schema map[string]map[string]Definition
+
+// Validate checks that the schema only declares known entity types and field
+// types, and that enums list their values
+func (s Schema) Validate() error {
+	for _, entityType := range sortedKeys(s) {
+		if !slices.Contains(EntityTypes, entityType) {
+			return fmt.Errorf("invalid custom_fields.%s: unknown entity type (expected %s)", entityType, strings.Join(EntityTypes, ", "))
+		}
+		for _, name := range sortedKeys(s[entityType]) {
+			def := s[entityType][name]
+			path := fmt.Sprintf("invalid custom_fields.%s.%s", entityType, name)
+			if name == "" || strings.ContainsAny(name, " .=[]") {
+				return fmt.Errorf("%s: field names must not contain spaces, dots, = or brackets", path)
+			}
+			if !slices.Contains(Types, def.Type) {
+				return fmt.Errorf("%s: unknown type %q (expected %s)", path, def.Type, strings.Join(Types, ", "))
+			}
+			if def.Type == TypeEnum && len(def.Values) == 0 {
+				return fmt.Errorf("%s: an enum requires values", path)
+			}
+			if def.Type != TypeEnum && len(def.Values) > 0 {
+				return fmt.Errorf("%s: values are only allowed for enums", path)
+			}
+		}
+	}
+	return nil
+}
+
+// Lookup returns the definition of a custom field of an entity type
+func (s Schema) Lookup(entityType, name string) (Definition, error) {
+	if def, ok := s[entityType][name]; ok {
+		return def, nil
+	}
+	declared := sortedKeys(s[entityType])
+	if len(declared) == 0 {
+		return Definition{}, fmt.Errorf("unknown custom field %q: no custom fields are declared for %ss (see custom_fields in .agentpm.json)", name, entityType)
+	}
+	return Definition{}, fmt.Errorf("unknown custom field %q for %ss (declared: %s)", name, entityType, strings.Join(declared, ", "))
+}
+
+// Normalize checks a value against the field's type and returns it in its
+// canonical form: integers without sign or leading zeros where not needed,
+// dates as 2006-01-02 or RFC3339, enum values as declared
+func (d Definition) Normalize(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	switch d.Type {
+	case TypeInt:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid value %q: expected an integer", value)
+		}
+		return strconv.Itoa(n), nil
+	case TypeDate:
+		date, allDay, err := epic.ParseDate(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid value %q: expected a date like 2025-09-15 or an RFC3339 timestamp", value)
+		}
+		if allDay {
+			return date.Format(time.DateOnly), nil
+		}
+		return date.Format(time.RFC3339), nil
+	case TypeEnum:
+		for _, allowed := range d.Values {
+			if strings.EqualFold(value, allowed) {
+				return allowed, nil
+			}
+		}
+		return "", fmt.Errorf("invalid value %q: expected one of %s", value, strings.Join(d.Values, ", "))
+	default:
+		return value, nil
+	}
+}
+
+// Compare orders two normalized values by the field's type: integers by
+// number, dates chronologically, enum values in their declared order and
+// strings alphabetically, ignoring case
+func (d Definition) Compare(a, b string) int {
+	switch d.Type {
+	case TypeInt:
+		x, _ := strconv.Atoi(a)
+		y, _ := strconv.Atoi(b)
+		return cmp.Compare(x, y)
+	case TypeDate:
+		x, _, _ := epic.ParseDate(a)
+		y, _, _ := epic.ParseDate(b)
+		return x.Compare(y)
+	case TypeEnum:
+		return cmp.Compare(slices.Index(d.Values, a), slices.Index(d.Values, b))
+	default:
+		return cmp.Compare(strings.ToLower(a), strings.ToLower(b))
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,78 @@
+package customfields
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaValidate(t *testing.T) {
+	valid := Schema{
+		"task":  {"sprint": {Type: TypeInt}, "risk": {Type: TypeEnum, Values: []string{"low", "high"}}},
+		"phase": {"release": {Type: TypeDate}, "team": {Type: TypeString, Description: "Owning team"}},
+	}
+	assert.NoError(t, valid.Validate())
+	assert.NoError(t, Schema(nil).Validate())
+
+	for message, schema := range map[string]Schema{
+		"invalid custom_fields.milestone: unknown entity type":         {"milestone": {"x": {Type: TypeString}}},
+		`invalid custom_fields.task.sprint: unknown type "float"`:      {"task": {"sprint": {Type: "float"}}},
+		"invalid custom_fields.task.risk: an enum requires values":     {"task": {"risk": {Type: TypeEnum}}},
+		"invalid custom_fields.task.team: values are only allowed":     {"task": {"team": {Type: TypeString, Values: []string{"a"}}}},
+		"invalid custom_fields.task.a.b: field names must not contain": {"task": {"a.b": {Type: TypeString}}},
+	} {
+		assert.ErrorContains(t, schema.Validate(), message)
+	}
+}
+
+func TestSchemaLookup(t *testing.T) {
+	schema := Schema{"task": {"sprint": {Type: TypeInt}, "risk": {Type: TypeString}}}
+
+	def, err := schema.Lookup("task", "sprint")
+	require.NoError(t, err)
+	assert.Equal(t, TypeInt, def.Type)
+
+	_, err = schema.Lookup("task", "team")
+	assert.EqualError(t, err, `unknown custom field "team" for tasks (declared: risk, sprint)`)
+
+	_, err = schema.Lookup("phase", "team")
+	assert.ErrorContains(t, err, "no custom fields are declared for phases")
+}
+
+func TestDefinitionNormalize(t *testing.T) {
+	tests := []struct {
+		def   Definition
+		value string
+		want  string
+		err   string
+	}{
+		{def: Definition{Type: TypeString}, value: " Core team ", want: "Core team"},
+		{def: Definition{Type: TypeInt}, value: "007", want: "7"},
+		{def: Definition{Type: TypeInt}, value: "-3", want: "-3"},
+		{def: Definition{Type: TypeInt}, value: "7.5", err: `invalid value "7.5": expected an integer`},
+		{def: Definition{Type: TypeDate}, value: "2025-09-15", want: "2025-09-15"},
+		{def: Definition{Type: TypeDate}, value: "2025-09-15T17:00:00+02:00", want: "2025-09-15T17:00:00+02:00"},
+		{def: Definition{Type: TypeDate}, value: "15.09.2025", err: "expected a date"},
+		{def: Definition{Type: TypeEnum, Values: []string{"low", "high"}}, value: "HIGH", want: "high"},
+		{def: Definition{Type: TypeEnum, Values: []string{"low", "high"}}, value: "medium", err: "expected one of low, high"},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.def.Normalize(tt.value)
+		if tt.err != "" {
+			assert.ErrorContains(t, err, tt.err, tt.value)
+			continue
+		}
+		require.NoError(t, err, tt.value)
+		assert.Equal(t, tt.want, got, tt.value)
+	}
+}
+
+func TestDefinitionCompare(t *testing.T) {
+	assert.Negative(t, Definition{Type: TypeInt}.Compare("9", "10"))
+	assert.Positive(t, Definition{Type: TypeString}.Compare("9", "10"))
+	assert.Zero(t, Definition{Type: TypeString}.Compare("Core", "core"))
+	assert.Negative(t, Definition{Type: TypeDate}.Compare("2025-09-15", "2025-09-15T08:00:00Z"))
+	assert.Positive(t, Definition{Type: TypeEnum, Values: []string{"low", "medium", "high"}}.Compare("high", "medium"))
+}
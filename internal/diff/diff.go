@@ -0,0 +1,162 @@
+package diff
+
+import (
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// Entity types of added, removed and changed entities
+const (
+	TypeEpic  = "epic"
+	TypePhase = "phase"
+	TypeTask  = "task"
+	TypeTest  = "test"
+)
+
+// Entity is a phase, task or test only one of the epics has
+type Entity struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// StatusChange is a status that differs between the epics. Field is "status",
+// for tests also "test_status" or "result", the outcome of the test.
+type StatusChange struct {
+	Type  string `json:"type"`
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// Event is an event the new epic has and the old one has not
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Actor     string    `json:"actor,omitempty"`
+	Data      string    `json:"data"`
+}
+
+// Diff lists what changed between two snapshots of an epic
+type Diff struct {
+	EpicID        string         `json:"epic_id"`
+	StatusChanges []StatusChange `json:"status_changes"`
+	Added         []Entity       `json:"added"`
+	Removed       []Entity       `json:"removed"`
+	NewEvents     []Event        `json:"new_events"`
+}
+
+// Empty reports whether the snapshots do not differ in anything the diff covers
+func (d *Diff) Empty() bool {
+	return len(d.StatusChanges) == 0 && len(d.Added) == 0 && len(d.Removed) == 0 && len(d.NewEvents) == 0
+}
+
+// entry is a phase, task or test with the statuses the diff compares
+type entry struct {
+	entity   Entity
+	statuses [][2]string // field, value
+}
+
+// Compare lists what changed from old to new: status changes of the epic and
+// of the phases, tasks and tests both snapshots have, the ones only one of
+// them has, and the events added since old. Entities are matched by ID and
+// listed in the order of the epic; events are matched as a whole, as event
+// IDs need not be unique.
+func Compare(old, new *epic.Epic) *Diff {
+	d := &Diff{
+		EpicID:        new.ID,
+		StatusChanges: []StatusChange{},
+		Added:         []Entity{},
+		Removed:       []Entity{},
+		NewEvents:     []Event{},
+	}
+
+	if old.Status != new.Status {
+		d.StatusChanges = append(d.StatusChanges, StatusChange{
+			Type: TypeEpic, ID: new.ID, Name: new.Name, Field: "status", From: string(old.Status), To: string(new.Status),
+		})
+	}
+
+	oldEntries, newEntries := entries(old), entries(new)
+	oldByKey := make(map[Entity]entry, len(oldEntries))
+	for _, oldEntry := range oldEntries {
+		oldByKey[key(oldEntry.entity)] = oldEntry
+	}
+	newKeys := make(map[Entity]bool, len(newEntries))
+	for _, newEntry := range newEntries {
+		newKeys[key(newEntry.entity)] = true
+		oldEntry, ok := oldByKey[key(newEntry.entity)]
+		if !ok {
+			d.Added = append(d.Added, newEntry.entity)
+			continue
+		}
+		for i, status := range newEntry.statuses {
+			if from := oldEntry.statuses[i][1]; from != status[1] {
+				d.StatusChanges = append(d.StatusChanges, StatusChange{
+					Type:  newEntry.entity.Type,
+					ID:    newEntry.entity.ID,
+					Name:  newEntry.entity.Name,
+					Field: status[0],
+					From:  from,
+					To:    status[1],
+				})
+			}
+		}
+	}
+	for _, oldEntry := range oldEntries {
+		if !newKeys[key(oldEntry.entity)] {
+			d.Removed = append(d.Removed, oldEntry.entity)
+		}
+	}
+
+	seen := make(map[epic.Event]int, len(old.Events))
+	for _, event := range old.Events {
+		seen[event]++
+	}
+	for _, event := range new.Events {
+		if seen[event] > 0 {
+			seen[event]--
+			continue
+		}
+		d.NewEvents = append(d.NewEvents, Event{
+			ID:        event.ID,
+			Type:      event.Type,
+			Timestamp: event.Timestamp,
+			Actor:     event.Actor,
+			Data:      event.Data,
+		})
+	}
+	return d
+}
+
+// key identifies an entity across snapshots, whatever its name
+func key(entity Entity) Entity {
+	return Entity{Type: entity.Type, ID: entity.ID}
+}
+
+func entries(e *epic.Epic) []entry {
+	var result []entry
+	for _, phase := range e.Phases {
+		result = append(result, entry{
+			entity:   Entity{Type: TypePhase, ID: phase.ID, Name: phase.Name},
+			statuses: [][2]string{{"status", string(phase.Status)}},
+		})
+	}
+	for _, task := range e.Tasks {
+		result = append(result, entry{
+			entity:   Entity{Type: TypeTask, ID: task.ID, Name: task.Name},
+			statuses: [][2]string{{"status", string(task.Status)}},
+		})
+	}
+	for _, test := range e.Tests {
+		result = append(result, entry{
+			entity:   Entity{Type: TypeTest, ID: test.ID, Name: test.Name},
+			statuses: [][2]string{{"status", string(test.Status)}, {"test_status", string(test.TestStatus)}, {"result", string(test.TestResult)}},
+		})
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package diff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/stretchr/testify/assert"
+)
+
+func snapshot() *epic.Epic {
+	return &epic.Epic{
+		ID:     "8",
+		Name:   "Search",
+		Status: epic.StatusPending,
+		Phases: []epic.Phase{{ID: "P1", Name: "Index", Status: epic.StatusPending}},
+		Tasks: []epic.Task{
+			{ID: "T1", PhaseID: "P1", Name: "Schema", Status: epic.StatusPending},
+			{ID: "T2", PhaseID: "P1", Name: "Indexer", Status: epic.StatusPending},
+		},
+		Tests: []epic.Test{
+			{ID: "T1_1", TaskID: "T1", PhaseID: "P1", Name: "Schema test", Status: epic.StatusPending, TestStatus: epic.TestStatusPending},
+		},
+		Events: []epic.Event{
+			{ID: "epic_created_1", Type: "epic_created", Timestamp: time.Date(2025, 8, 15, 9, 0, 0, 0, time.UTC), Data: "Epic created"},
+		},
+	}
+}
+
+func TestCompare(t *testing.T) {
+	old := snapshot()
+	new := snapshot()
+	new.Status = epic.StatusWIP
+	new.Phases[0].Status = epic.StatusWIP
+	new.Tasks[0].Status = epic.StatusCompleted
+	new.Tasks[0].Name = "Index schema"
+	new.Tasks = append(new.Tasks[:1], epic.Task{ID: "T3", PhaseID: "P1", Name: "Query", Status: epic.StatusPending})
+	new.Tests[0].Status = epic.StatusCompleted
+	new.Tests[0].TestStatus = epic.TestStatusDone
+	new.Tests[0].TestResult = epic.TestResultPassing
+	started := time.Date(2025, 8, 15, 10, 0, 0, 0, time.UTC)
+	new.Events = append(new.Events,
+		epic.Event{ID: "task_started_1", Type: "task_started", Timestamp: started, Actor: "agent_claude", Data: "Task T1 started"},
+		epic.Event{ID: "task_started_1", Type: "task_started", Timestamp: started, Actor: "agent_claude", Data: "Task T3 started"},
+	)
+
+	d := Compare(old, new)
+
+	assert.Equal(t, "8", d.EpicID)
+	assert.False(t, d.Empty())
+	assert.Equal(t, []StatusChange{
+		{Type: TypeEpic, ID: "8", Name: "Search", Field: "status", From: "pending", To: "wip"},
+		{Type: TypePhase, ID: "P1", Name: "Index", Field: "status", From: "pending", To: "wip"},
+		{Type: TypeTask, ID: "T1", Name: "Index schema", Field: "status", From: "pending", To: "completed"},
+		{Type: TypeTest, ID: "T1_1", Name: "Schema test", Field: "status", From: "pending", To: "completed"},
+		{Type: TypeTest, ID: "T1_1", Name: "Schema test", Field: "test_status", From: "pending", To: "done"},
+		{Type: TypeTest, ID: "T1_1", Name: "Schema test", Field: "result", From: "", To: "passing"},
+	}, d.StatusChanges)
+	assert.Equal(t, []Entity{{Type: TypeTask, ID: "T3", Name: "Query"}}, d.Added)
+	assert.Equal(t, []Entity{{Type: TypeTask, ID: "T2", Name: "Indexer"}}, d.Removed)
+	assert.Equal(t, []Event{
+		{ID: "task_started_1", Type: "task_started", Timestamp: started, Actor: "agent_claude", Data: "Task T1 started"},
+		{ID: "task_started_1", Type: "task_started", Timestamp: started, Actor: "agent_claude", Data: "Task T3 started"},
+	}, d.NewEvents)
+}
+
+func TestCompareUnchanged(t *testing.T) {
+	d := Compare(snapshot(), snapshot())
+
+	assert.True(t, d.Empty())
+	assert.Empty(t, d.StatusChanges)
+	assert.Empty(t, d.NewEvents)
+}
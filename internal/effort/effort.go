@@ -0,0 +1,186 @@
+package effort
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/service"
+)
+
+// Unattributed collects events that have neither an actor nor an assigned task
+const Unattributed = "(unattributed)"
+
+// Period limits a report to events in [From, To). A zero bound is open.
+type Period struct {
+	From time.Time
+	To   time.Time
+}
+
+// Contains reports whether t lies within the period
+func (p Period) Contains(t time.Time) bool {
+	return (p.From.IsZero() || !t.Before(p.From)) && (p.To.IsZero() || t.Before(p.To))
+}
+
+// overlap returns how much of [start, end) lies within the period
+func (p Period) overlap(start, end time.Time) time.Duration {
+	if !p.From.IsZero() && start.Before(p.From) {
+		start = p.From
+	}
+	if !p.To.IsZero() && end.After(p.To) {
+		end = p.To
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// ActorEffort is the work attributed to one agent or human
+type ActorEffort struct {
+	Actor          string `json:"actor"`
+	TasksCompleted int    `json:"tasks_completed"`
+	TestsPassed    int    `json:"tests_passed"`
+	TestsFixed     int    `json:"tests_fixed"`
+	ActiveMinutes  int    `json:"active_minutes"`
+	Events         int    `json:"events"`
+
+	active time.Duration
+}
+
+// Report is the effort per actor across one or more epics
+type Report struct {
+	Epics  int           `json:"epics"`
+	Actors []ActorEffort `json:"actors"`
+}
+
+// openTask is a task started by an actor that has not been finished yet
+type openTask struct {
+	actor string
+	since time.Time
+}
+
+// Build attributes the events of the epics within the period to actors. Events
+// without an actor count for the assignee of their task. A task's active time runs
+// from task_started until it is completed, cancelled or reset (or until now) and
+// belongs to the actor who started it; reopening a completed task resumes it for the
+// actor who reopened it. A test counts as fixed when it passes after having failed.
+func Build(epics []*epic.Epic, period Period, now time.Time) *Report {
+	report := &Report{Epics: len(epics)}
+	byActor := make(map[string]*ActorEffort)
+	get := func(name string) *ActorEffort {
+		if byActor[name] == nil {
+			byActor[name] = &ActorEffort{Actor: name}
+		}
+		return byActor[name]
+	}
+
+	for _, e := range epics {
+		events := append([]epic.Event(nil), e.Events...)
+		sort.SliceStable(events, func(i, j int) bool { return events[i].Timestamp.Before(events[j].Timestamp) })
+
+		open := make(map[string]openTask)
+		completed := make(map[string]bool) // Tasks whose last close was a completion
+		failing := make(map[string]bool)
+		for _, event := range events {
+			actor := eventActor(e, event)
+			id := service.EventEntityID(event)
+
+			switch service.EventType(event.Type) {
+			case service.EventTaskStarted:
+				open[id] = openTask{actor: actor, since: event.Timestamp}
+			case service.EventTaskCompleted, service.EventTaskCancelled, service.EventTaskReset:
+				if started, ok := open[id]; ok {
+					get(started.actor).active += period.overlap(started.since, event.Timestamp)
+					delete(open, id)
+				}
+				completed[id] = service.EventType(event.Type) == service.EventTaskCompleted
+			case service.EventTaskReopened:
+				// A completed task is reopened as wip, a cancelled one as pending
+				if completed[id] {
+					open[id] = openTask{actor: actor, since: event.Timestamp}
+				}
+				completed[id] = false
+			}
+
+			if !period.Contains(event.Timestamp) {
+				// Still track failures before the period, so fixes within it count
+				switch service.EventType(event.Type) {
+				case service.EventTestFailed:
+					failing[id] = true
+				case service.EventTestPassed, service.EventTestReset:
+					failing[id] = false
+				}
+				continue
+			}
+
+			effort := get(actor)
+			effort.Events++
+			switch service.EventType(event.Type) {
+			case service.EventTaskCompleted:
+				effort.TasksCompleted++
+			case service.EventTestPassed:
+				effort.TestsPassed++
+				if failing[id] {
+					effort.TestsFixed++
+				}
+				failing[id] = false
+			case service.EventTestFailed:
+				failing[id] = true
+			case service.EventTestReset:
+				failing[id] = false
+			}
+		}
+
+		for _, started := range open {
+			get(started.actor).active += period.overlap(started.since, now)
+		}
+	}
+
+	for _, effort := range byActor {
+		effort.ActiveMinutes = int(effort.active.Minutes())
+		if effort.Events == 0 && effort.active == 0 {
+			continue
+		}
+		report.Actors = append(report.Actors, *effort)
+	}
+	sort.Slice(report.Actors, func(i, j int) bool {
+		a, b := report.Actors[i], report.Actors[j]
+		if a.TasksCompleted != b.TasksCompleted {
+			return a.TasksCompleted > b.TasksCompleted
+		}
+		return a.Actor < b.Actor
+	})
+
+	return report
+}
+
+// eventActor returns who an event is attributed to: its recorded actor, else the
+// assignee of the task it (or its test) belongs to
+func eventActor(e *epic.Epic, event epic.Event) string {
+	if event.Actor != "" {
+		return event.Actor
+	}
+
+	entityType, _, _ := strings.Cut(event.Type, "_")
+	taskID := ""
+	switch entityType {
+	case "task":
+		taskID = service.EventEntityID(event)
+	case "test":
+		testID := service.EventEntityID(event)
+		for _, test := range e.Tests {
+			if test.ID == testID {
+				taskID = test.TaskID
+				break
+			}
+		}
+	}
+	for _, task := range e.Tasks {
+		if task.ID == taskID && task.Assignee != "" {
+			return task.Assignee
+		}
+	}
+	return Unattributed
+}
@@ -0,0 +1,130 @@
+package effort
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func at(day, hour, minute int) time.Time {
+	return time.Date(2025, 8, day, hour, minute, 0, 0, time.UTC)
+}
+
+func createEpicWithActors(t *testing.T) *epic.Epic {
+	t.Helper()
+	t.Cleanup(func() { service.SetActor("") })
+
+	e := &epic.Epic{
+		ID:     "epic-1",
+		Phases: []epic.Phase{{ID: "P1", Name: "Phase 1"}},
+		Tasks: []epic.Task{
+			{ID: "T1", PhaseID: "P1", Name: "Task 1"},
+			{ID: "T2", PhaseID: "P1", Name: "Task 2", Assignee: "bob"},
+		},
+		Tests: []epic.Test{
+			{ID: "T1_1", TaskID: "T1", PhaseID: "P1", Name: "Test 1"},
+			{ID: "T2_1", TaskID: "T2", PhaseID: "P1", Name: "Test 2"},
+		},
+	}
+
+	service.SetActor("claude-1")
+	service.CreateEvent(e, service.EventTaskStarted, "P1", "T1", "", "", at(11, 9, 0))
+	service.CreateEvent(e, service.EventTestFailed, "P1", "T1", "T1_1", "boom", at(11, 10, 0))
+	service.CreateEvent(e, service.EventTestPassed, "P1", "T1", "T1_1", "", at(12, 9, 0))
+	service.CreateEvent(e, service.EventTaskCompleted, "P1", "T1", "", "", at(12, 9, 30))
+
+	// Recorded without an actor: attributed to the task's assignee
+	service.SetActor("")
+	service.CreateEvent(e, service.EventTaskStarted, "P1", "T2", "", "", at(12, 10, 0))
+	service.CreateEvent(e, service.EventTestPassed, "P1", "T2", "T2_1", "", at(12, 11, 0))
+	service.CreateEvent(e, service.EventPhaseStarted, "P1", "", "", "", at(12, 11, 0))
+	return e
+}
+
+func findActor(t *testing.T, report *Report, name string) ActorEffort {
+	t.Helper()
+	for _, actor := range report.Actors {
+		if actor.Actor == name {
+			return actor
+		}
+	}
+	require.Failf(t, "actor not found", "no effort for %s", name)
+	return ActorEffort{}
+}
+
+func TestBuild(t *testing.T) {
+	t.Run("attributes work to actors", func(t *testing.T) {
+		report := Build([]*epic.Epic{createEpicWithActors(t)}, Period{}, at(12, 12, 0))
+
+		require.Len(t, report.Actors, 3)
+		assert.Equal(t, "claude-1", report.Actors[0].Actor)
+
+		claude := findActor(t, report, "claude-1")
+		assert.Equal(t, 1, claude.TasksCompleted)
+		assert.Equal(t, 1, claude.TestsPassed)
+		assert.Equal(t, 1, claude.TestsFixed)
+		assert.Equal(t, 24*60+30, claude.ActiveMinutes)
+		assert.Equal(t, 4, claude.Events)
+
+		bob := findActor(t, report, "bob")
+		assert.Equal(t, 0, bob.TestsFixed)
+		assert.Equal(t, 120, bob.ActiveMinutes, "open tasks count until now")
+
+		assert.Equal(t, 1, findActor(t, report, Unattributed).Events)
+	})
+
+	t.Run("limits counts and active time to the period", func(t *testing.T) {
+		period := Period{From: at(12, 0, 0), To: at(12, 10, 30)}
+		report := Build([]*epic.Epic{createEpicWithActors(t)}, period, at(12, 12, 0))
+
+		claude := findActor(t, report, "claude-1")
+		assert.Equal(t, 1, claude.TestsFixed, "failures before the period still count")
+		assert.Equal(t, 9*60+30, claude.ActiveMinutes)
+		assert.Equal(t, 2, claude.Events)
+
+		bob := findActor(t, report, "bob")
+		assert.Equal(t, 30, bob.ActiveMinutes)
+		assert.Equal(t, 1, bob.Events)
+	})
+}
+
+func TestBuild_ReopenedTask(t *testing.T) {
+	t.Cleanup(func() { service.SetActor("") })
+	e := &epic.Epic{
+		ID:     "epic-1",
+		Phases: []epic.Phase{{ID: "P1", Name: "Phase 1", Status: epic.StatusWIP}},
+		Tasks: []epic.Task{
+			{ID: "T1", PhaseID: "P1", Name: "Task 1"},
+			{ID: "T2", PhaseID: "P1", Name: "Task 2"},
+		},
+	}
+	service.SetActor("claude-1")
+	service.CreateEvent(e, service.EventTaskStarted, "P1", "T1", "", "", at(11, 9, 0))
+	service.CreateEvent(e, service.EventTaskCompleted, "P1", "T1", "", "", at(11, 10, 0))
+	service.CreateEvent(e, service.EventTaskCancelled, "P1", "T2", "", "", at(11, 10, 0))
+	e.Tasks[0].Status = epic.StatusCompleted
+	e.Tasks[1].Status = epic.StatusCancelled
+
+	service.SetActor("claude-2")
+	_, err := service.ReopenTask(e, "T1", "missed a case", at(11, 11, 0))
+	require.NoError(t, err)
+	service.CreateEvent(e, service.EventTaskCompleted, "P1", "T1", "", "", at(11, 11, 30))
+	_, err = service.ReopenTask(e, "T2", "needed after all", at(11, 12, 0))
+	require.NoError(t, err)
+
+	report := Build([]*epic.Epic{e}, Period{}, at(11, 13, 0))
+	assert.Equal(t, 60, findActor(t, report, "claude-1").ActiveMinutes)
+	assert.Equal(t, 30, findActor(t, report, "claude-2").ActiveMinutes, "a cancelled task is reopened as pending")
+}
+
+func TestPeriod_Contains(t *testing.T) {
+	period := Period{From: at(12, 0, 0), To: at(13, 0, 0)}
+	assert.True(t, period.Contains(at(12, 0, 0)))
+	assert.False(t, period.Contains(at(13, 0, 0)))
+	assert.False(t, period.Contains(at(11, 23, 59)))
+	assert.True(t, Period{}.Contains(at(1, 0, 0)))
+}
@@ -0,0 +1,69 @@
+package epic
+
+import "strings"
+
+// TaskAssignee returns who works on a task: its own assignee, otherwise the
+// assignee of its phase, otherwise the assignee of the epic
+func (e *Epic) TaskAssignee(task *Task) string {
+	if task.Assignee != "" {
+		return task.Assignee
+	}
+	return e.PhaseAssignee(task.PhaseID)
+}
+
+// PhaseAssignee returns the assignee of a phase, or the assignee of the epic
+// when the phase has none or does not exist
+func (e *Epic) PhaseAssignee(phaseID string) string {
+	for i := range e.Phases {
+		if e.Phases[i].ID == phaseID && e.Phases[i].Assignee != "" {
+			return e.Phases[i].Assignee
+		}
+	}
+	return e.Assignee
+}
+
+// TestAssignee returns the assignee of a test's task, or of its phase when
+// the test belongs to no known task
+func (e *Epic) TestAssignee(test *Test) string {
+	for i := range e.Tasks {
+		if e.Tasks[i].ID == test.TaskID {
+			return e.TaskAssignee(&e.Tasks[i])
+		}
+	}
+	return e.PhaseAssignee(test.PhaseID)
+}
+
+// SameAssignee reports whether two assignee names denote the same agent;
+// names are compared ignoring case and surrounding whitespace
+func SameAssignee(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+// ForAssignee returns a copy of the epic narrowed to the work of one agent:
+// the tasks assigned to it, their tests, and the phases it is assigned to or
+// has tasks in. Events and the other epic fields are shared with e.
+func (e *Epic) ForAssignee(agent string) *Epic {
+	filtered := *e
+	filtered.Tasks = nil
+	filtered.Tests = nil
+	filtered.Phases = nil
+
+	phaseIDs := make(map[string]bool)
+	for i := range e.Tasks {
+		if SameAssignee(e.TaskAssignee(&e.Tasks[i]), agent) {
+			filtered.Tasks = append(filtered.Tasks, e.Tasks[i])
+			phaseIDs[e.Tasks[i].PhaseID] = true
+		}
+	}
+	for i := range e.Tests {
+		if SameAssignee(e.TestAssignee(&e.Tests[i]), agent) {
+			filtered.Tests = append(filtered.Tests, e.Tests[i])
+		}
+	}
+	for _, phase := range e.Phases {
+		if phaseIDs[phase.ID] || SameAssignee(e.PhaseAssignee(phase.ID), agent) {
+			filtered.Phases = append(filtered.Phases, phase)
+		}
+	}
+	return &filtered
+}
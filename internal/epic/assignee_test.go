@@ -0,0 +1,76 @@
+package epic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createAssigneeEpic() *Epic {
+	return &Epic{
+		ID:       "split",
+		Assignee: "agent_a",
+		Phases: []Phase{
+			{ID: "1", Name: "Backend"},
+			{ID: "2", Name: "Frontend", Assignee: "agent_b"},
+		},
+		Tasks: []Task{
+			{ID: "1_1", PhaseID: "1"},
+			{ID: "1_2", PhaseID: "1", Assignee: "agent_b"},
+			{ID: "2_1", PhaseID: "2"},
+			{ID: "2_2", PhaseID: "2", Assignee: "agent_c"},
+		},
+		Tests: []Test{
+			{ID: "T1_1", TaskID: "1_1"},
+			{ID: "T1_2", TaskID: "1_2"},
+			{ID: "T2_1", TaskID: "2_1"},
+			{ID: "T2_2", TaskID: "2_2"},
+			{ID: "T2", PhaseID: "2"},
+		},
+	}
+}
+
+func TestTaskAssignee(t *testing.T) {
+	e := createAssigneeEpic()
+
+	assert.Equal(t, "agent_a", e.TaskAssignee(&e.Tasks[0]), "falls back to the epic")
+	assert.Equal(t, "agent_b", e.TaskAssignee(&e.Tasks[1]), "own assignee")
+	assert.Equal(t, "agent_b", e.TaskAssignee(&e.Tasks[2]), "falls back to the phase")
+	assert.Equal(t, "agent_c", e.TaskAssignee(&e.Tasks[3]), "own assignee wins over the phase")
+
+	assert.Equal(t, "agent_c", e.TestAssignee(&e.Tests[3]))
+	assert.Equal(t, "agent_b", e.TestAssignee(&e.Tests[4]), "test without task uses its phase")
+}
+
+func TestForAssignee(t *testing.T) {
+	e := createAssigneeEpic()
+
+	ids := func(filtered *Epic) (phases, tasks, tests []string) {
+		for _, p := range filtered.Phases {
+			phases = append(phases, p.ID)
+		}
+		for _, task := range filtered.Tasks {
+			tasks = append(tasks, task.ID)
+		}
+		for _, test := range filtered.Tests {
+			tests = append(tests, test.ID)
+		}
+		return
+	}
+
+	phases, tasks, tests := ids(e.ForAssignee("Agent_B"))
+	assert.Equal(t, []string{"1", "2"}, phases)
+	assert.Equal(t, []string{"1_2", "2_1"}, tasks)
+	assert.Equal(t, []string{"T1_2", "T2_1", "T2"}, tests)
+
+	phases, tasks, tests = ids(e.ForAssignee("agent_c"))
+	assert.Equal(t, []string{"2"}, phases)
+	assert.Equal(t, []string{"2_2"}, tasks)
+	assert.Equal(t, []string{"T2_2"}, tests)
+
+	phases, tasks, _ = ids(e.ForAssignee("nobody"))
+	assert.Empty(t, phases)
+	assert.Empty(t, tasks)
+
+	assert.Len(t, e.Tasks, 4, "the epic itself is not changed")
+}
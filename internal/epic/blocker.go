@@ -0,0 +1,77 @@
+package epic
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BlockerTypeQuestion marks a blocker as an open question rather than an impediment
+const BlockerTypeQuestion = "question"
+
+// Blocker is an impediment or open question raised while working on the
+// epic. It stays open until it is resolved, so that whoever picks up the
+// work next sees it.
+type Blocker struct {
+	ID          string     `xml:"id,attr"`
+	Type        string     `xml:"type,attr,omitempty"`   // Empty for blockers, BlockerTypeQuestion for open questions
+	Entity      string     `xml:"entity,attr,omitempty"` // Phase or task held up, empty for the whole epic
+	RaisedAt    time.Time  `xml:"raised_at,attr"`
+	RaisedBy    string     `xml:"raised_by,attr,omitempty"`
+	ResolvedAt  *time.Time `xml:"resolved_at,attr,omitempty"`
+	Description string     `xml:"description"`
+	Resolution  string     `xml:"resolution,omitempty"`
+}
+
+// IsResolved reports whether the blocker has been resolved
+func (b *Blocker) IsResolved() bool {
+	return b.ResolvedAt != nil
+}
+
+// IsQuestion reports whether the blocker is an open question
+func (b *Blocker) IsQuestion() bool {
+	return b.Type == BlockerTypeQuestion
+}
+
+// Kind returns "question" for open questions and "blocker" otherwise
+func (b *Blocker) Kind() string {
+	if b.IsQuestion() {
+		return "question"
+	}
+	return "blocker"
+}
+
+// FindBlocker returns the blocker with the given ID, or nil
+func (e *Epic) FindBlocker(id string) *Blocker {
+	for i := range e.Blockers {
+		if e.Blockers[i].ID == id {
+			return &e.Blockers[i]
+		}
+	}
+	return nil
+}
+
+// OpenBlockers returns the blockers and questions not resolved yet, in the
+// order they were raised
+func (e *Epic) OpenBlockers() []Blocker {
+	var open []Blocker
+	for _, blocker := range e.Blockers {
+		if !blocker.IsResolved() {
+			open = append(open, blocker)
+		}
+	}
+	return open
+}
+
+// NextBlockerID returns the ID for a new blocker: B followed by one more
+// than the highest number in use (B1, B2, ...)
+func (e *Epic) NextBlockerID() string {
+	highest := 0
+	for _, blocker := range e.Blockers {
+		if n, err := strconv.Atoi(strings.TrimPrefix(blocker.ID, "B")); err == nil && n > highest {
+			highest = n
+		}
+	}
+	return fmt.Sprintf("B%d", highest+1)
+}
@@ -0,0 +1,41 @@
+package epic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockers(t *testing.T) {
+	resolved := time.Date(2025, 8, 16, 11, 0, 0, 0, time.UTC)
+	e := &Epic{
+		Blockers: []Blocker{
+			{ID: "B1", Description: "Waiting for credentials", ResolvedAt: &resolved},
+			{ID: "B2", Type: BlockerTypeQuestion, Description: "Which API version?"},
+			{ID: "B10", Description: "Flaky staging database"},
+		},
+	}
+
+	t.Run("open blockers in the order they were raised", func(t *testing.T) {
+		open := e.OpenBlockers()
+		assert.Len(t, open, 2)
+		assert.Equal(t, "B2", open[0].ID)
+		assert.Equal(t, "B10", open[1].ID)
+	})
+
+	t.Run("kind", func(t *testing.T) {
+		assert.Equal(t, "blocker", e.Blockers[0].Kind())
+		assert.Equal(t, "question", e.Blockers[1].Kind())
+	})
+
+	t.Run("find", func(t *testing.T) {
+		assert.True(t, e.FindBlocker("B1").IsResolved())
+		assert.Nil(t, e.FindBlocker("B3"))
+	})
+
+	t.Run("next ID follows the highest number", func(t *testing.T) {
+		assert.Equal(t, "B11", e.NextBlockerID())
+		assert.Equal(t, "B1", (&Epic{}).NextBlockerID())
+	})
+}
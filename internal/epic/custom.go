@@ -0,0 +1,48 @@
+package epic
+
+import "sort"
+
+// CustomField is the value of a custom field declared in the config, stored as
+// <custom><field name="sprint">7</field></custom>
+type CustomField struct {
+	Name  string `xml:"name,attr" json:"name" yaml:"name"`
+	Value string `xml:",chardata" json:"value" yaml:"value"`
+}
+
+// CustomFields holds the custom field values of an epic, phase, task or test,
+// sorted by name
+type CustomFields []CustomField
+
+// Get returns the value of the named field
+func (c CustomFields) Get(name string) (string, bool) {
+	for _, field := range c {
+		if field.Name == name {
+			return field.Value, true
+		}
+	}
+	return "", false
+}
+
+// Set returns the fields with the named field set to value
+func (c CustomFields) Set(name, value string) CustomFields {
+	for i := range c {
+		if c[i].Name == name {
+			c[i].Value = value
+			return c
+		}
+	}
+	c = append(c, CustomField{Name: name, Value: value})
+	sort.Slice(c, func(i, j int) bool { return c[i].Name < c[j].Name })
+	return c
+}
+
+// Unset returns the fields without the named field, nil once none are left
+func (c CustomFields) Unset(name string) CustomFields {
+	var kept CustomFields
+	for _, field := range c {
+		if field.Name != name {
+			kept = append(kept, field)
+		}
+	}
+	return kept
+}
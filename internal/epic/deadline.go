@@ -0,0 +1,64 @@
+package epic
+
+import (
+	"sort"
+	"time"
+)
+
+// Deadline is the due date of an epic, phase or task that is still open
+type Deadline struct {
+	Type   string // epic, phase or task
+	ID     string
+	Name   string
+	Status Status
+	Due    string    // As written in the epic, see ParseDate
+	At     time.Time // When the deadline passes, see DueAt
+}
+
+// DueAt returns when a deadline passes: the end of a calendar day (2025-09-15
+// is met until midnight), or the point in time given
+func DueAt(due string) (time.Time, error) {
+	date, allDay, err := ParseDate(due)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if allDay {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date, nil
+}
+
+// IsOverdue reports whether the deadline has passed at now
+func (d Deadline) IsOverdue(now time.Time) bool {
+	return now.After(d.At)
+}
+
+// OpenDeadlines lists the due dates of the epic, its phases and its tasks that
+// are neither completed nor cancelled, earliest first. Invalid due dates are
+// left out; validation reports them.
+func (e *Epic) OpenDeadlines() []Deadline {
+	var deadlines []Deadline
+	add := func(entityType, id, name string, status Status, due string) {
+		if due == "" || status == StatusCompleted || status == StatusCancelled {
+			return
+		}
+		at, err := DueAt(due)
+		if err != nil {
+			return
+		}
+		deadlines = append(deadlines, Deadline{Type: entityType, ID: id, Name: name, Status: status, Due: due, At: at})
+	}
+
+	add("epic", e.ID, e.Name, e.Status, e.Due)
+	for _, phase := range e.Phases {
+		add("phase", phase.ID, phase.Name, phase.Status, phase.Due)
+	}
+	for _, task := range e.Tasks {
+		add("task", task.ID, task.Name, task.Status, task.Due)
+	}
+
+	sort.SliceStable(deadlines, func(i, j int) bool {
+		return deadlines[i].At.Before(deadlines[j].At)
+	})
+	return deadlines
+}
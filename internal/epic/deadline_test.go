@@ -0,0 +1,50 @@
+package epic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDueAt(t *testing.T) {
+	at, err := DueAt("2025-09-15")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 9, 16, 0, 0, 0, 0, time.UTC), at, "a calendar day is met until midnight")
+
+	at, err = DueAt("2025-09-15T12:00:00Z")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 9, 15, 12, 0, 0, 0, time.UTC), at)
+
+	_, err = DueAt("next week")
+	assert.Error(t, err)
+}
+
+func TestOpenDeadlines(t *testing.T) {
+	e := &Epic{
+		ID: "E1", Name: "Checkout", Status: StatusWIP, Due: "2025-10-01",
+		Phases: []Phase{
+			{ID: "1", Name: "Backend", Status: StatusWIP, Due: "2025-09-20"},
+			{ID: "2", Name: "Frontend", Status: StatusCompleted, Due: "2025-09-10"},
+		},
+		Tasks: []Task{
+			{ID: "1_1", PhaseID: "1", Name: "Payments", Status: StatusPending, Due: "2025-09-15T12:00:00Z"},
+			{ID: "1_2", PhaseID: "1", Name: "Refunds", Status: StatusCancelled, Due: "2025-09-12"},
+			{ID: "1_3", PhaseID: "1", Name: "Receipts", Status: StatusPending, Due: "soon"},
+			{ID: "1_4", PhaseID: "1", Name: "Invoices", Status: StatusPending},
+		},
+	}
+
+	deadlines := e.OpenDeadlines()
+	require.Len(t, deadlines, 3)
+	assert.Equal(t, Deadline{
+		Type: "task", ID: "1_1", Name: "Payments", Status: StatusPending,
+		Due: "2025-09-15T12:00:00Z", At: time.Date(2025, 9, 15, 12, 0, 0, 0, time.UTC),
+	}, deadlines[0])
+	assert.Equal(t, "phase", deadlines[1].Type)
+	assert.Equal(t, "epic", deadlines[2].Type)
+
+	assert.False(t, deadlines[1].IsOverdue(time.Date(2025, 9, 20, 23, 0, 0, 0, time.UTC)))
+	assert.True(t, deadlines[1].IsOverdue(time.Date(2025, 9, 21, 0, 0, 1, 0, time.UTC)))
+}
@@ -0,0 +1,183 @@
+package epic
+
+// Dependency is an entry of a depends_on list together with the state of the
+// phase or task it names
+type Dependency struct {
+	ID     string
+	Status Status // Empty when the epic has no phase or task with this ID
+}
+
+// Found reports whether the dependency names an existing phase or task
+func (d Dependency) Found() bool {
+	return d.Status != ""
+}
+
+// UnmetPhaseDependencies returns the dependencies of a phase that are not
+// completed yet, unknown phase IDs included
+func (e *Epic) UnmetPhaseDependencies(phase *Phase) []Dependency {
+	var unmet []Dependency
+	for _, id := range phase.DependencyIDs() {
+		dependency := Dependency{ID: id}
+		for _, candidate := range e.Phases {
+			if candidate.ID == id {
+				dependency.Status = candidate.Status
+				break
+			}
+		}
+		if dependency.Status != StatusCompleted {
+			unmet = append(unmet, dependency)
+		}
+	}
+	return unmet
+}
+
+// UnmetTaskDependencies returns the dependencies of a task that are not
+// completed yet, unknown task IDs included. A cancelled task does not satisfy
+// a dependency: the work it stood for was never done.
+func (e *Epic) UnmetTaskDependencies(task *Task) []Dependency {
+	var unmet []Dependency
+	for _, id := range task.DependencyIDs() {
+		dependency := Dependency{ID: id}
+		for _, candidate := range e.Tasks {
+			if candidate.ID == id {
+				dependency.Status = candidate.Status
+				break
+			}
+		}
+		if dependency.Status != StatusCompleted {
+			unmet = append(unmet, dependency)
+		}
+	}
+	return unmet
+}
+
+// UpstreamPhaseIDs returns the phases whose tests must be done before a phase
+// can start: its depends_on phases when it declares any, otherwise all phases
+// before it in document order
+func (e *Epic) UpstreamPhaseIDs(phase *Phase) []string {
+	if ids := phase.DependencyIDs(); len(ids) > 0 {
+		return ids
+	}
+	var ids []string
+	for _, candidate := range e.Phases {
+		if candidate.ID == phase.ID {
+			break
+		}
+		ids = append(ids, candidate.ID)
+	}
+	return ids
+}
+
+// PhaseDependents returns the IDs of the phases that depend on phase id
+func (e *Epic) PhaseDependents(id string) []string {
+	var dependents []string
+	for i := range e.Phases {
+		for _, dependencyID := range e.Phases[i].DependencyIDs() {
+			if dependencyID == id {
+				dependents = append(dependents, e.Phases[i].ID)
+				break
+			}
+		}
+	}
+	return dependents
+}
+
+// TaskDependents returns the IDs of the tasks that depend on task id
+func (e *Epic) TaskDependents(id string) []string {
+	var dependents []string
+	for i := range e.Tasks {
+		for _, dependencyID := range e.Tasks[i].DependencyIDs() {
+			if dependencyID == id {
+				dependents = append(dependents, e.Tasks[i].ID)
+				break
+			}
+		}
+	}
+	return dependents
+}
+
+// StrandedTask is a pending task that can never start because a task it
+// depends on, directly or through other stranded tasks, is cancelled
+type StrandedTask struct {
+	ID      string `json:"id"`
+	PhaseID string `json:"phase_id"`
+	Via     string `json:"via"` // The cancelled or stranded dependency that blocks it
+}
+
+// StrandedTasks returns the pending tasks that cancelling the given tasks
+// leaves with a dependency that can never be met, nearest dependents first.
+// Tasks that already started are not affected: dependencies only gate the
+// start of a task. The cancelled tasks themselves are not listed.
+func (e *Epic) StrandedTasks(cancelled []string) []StrandedTask {
+	seen := make(map[string]bool)
+	for _, id := range cancelled {
+		seen[id] = true
+	}
+
+	var stranded []StrandedTask
+	queue := append([]string(nil), cancelled...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, dependentID := range e.TaskDependents(id) {
+			if seen[dependentID] {
+				continue
+			}
+			for i := range e.Tasks {
+				if e.Tasks[i].ID == dependentID && e.Tasks[i].Status == StatusPending {
+					seen[dependentID] = true
+					stranded = append(stranded, StrandedTask{ID: dependentID, PhaseID: e.Tasks[i].PhaseID, Via: id})
+					queue = append(queue, dependentID)
+					break
+				}
+			}
+		}
+	}
+	return stranded
+}
+
+// dependencyCycle returns the path of a cycle in a dependency graph, or nil.
+// The graph maps each ID to the IDs it depends on.
+func dependencyCycle(graph map[string][]string, order []string) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+	var path []string
+
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		switch state[id] {
+		case visited:
+			return nil
+		case visiting:
+			for i, step := range path {
+				if step == id {
+					return append(append([]string(nil), path[i:]...), id)
+				}
+			}
+		}
+		state[id] = visiting
+		path = append(path, id)
+		for _, dependencyID := range graph[id] {
+			if _, ok := graph[dependencyID]; !ok {
+				continue
+			}
+			if cycle := visit(dependencyID); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = visited
+		return nil
+	}
+
+	for _, id := range order {
+		if cycle := visit(id); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
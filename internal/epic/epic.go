@@ -1,6 +1,8 @@
 package epic
 
 import (
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -14,22 +16,34 @@ const (
 	StatusCancelled Status = "cancelled"
 )
 
+// CurrentSchemaVersion is the version of the epic file format new epics are
+// created with; 'agentpm migrate' upgrades older files to it
+const CurrentSchemaVersion = 2
+
 type Epic struct {
-	ID           string        `xml:"id,attr"`
-	Name         string        `xml:"name,attr"`
-	Status       Status        `xml:"status,attr"`
-	CreatedAt    time.Time     `xml:"created_at,attr"`
-	Assignee     string        `xml:"assignee"`
-	Description  string        `xml:"description"`
-	Workflow     string        `xml:"workflow,omitempty"`
-	Requirements string        `xml:"requirements,omitempty"`
-	Dependencies string        `xml:"dependencies,omitempty"`
-	Metadata     *EpicMetadata `xml:"metadata,omitempty"`
-	CurrentState *CurrentState `xml:"current_state,omitempty"`
-	Phases       []Phase       `xml:"phases>phase"`
-	Tasks        []Task        `xml:"tasks>task"`
-	Tests        []Test        `xml:"tests>test"`
-	Events       []Event       `xml:"events>event"`
+	ID            string        `xml:"id,attr"`
+	Name          string        `xml:"name,attr"`
+	Status        Status        `xml:"status,attr"`
+	CreatedAt     time.Time     `xml:"created_at,attr"`
+	StatusModel   string        `xml:"status_model,attr,omitempty"`   // "epic13" once migrated to the unified status model
+	SchemaVersion int           `xml:"schema_version,attr,omitempty"` // Version of the file format, 0 for files older than versioning
+	Due           string        `xml:"due,attr,omitempty"`            // Deadline, see ParseDate
+	Assignee      string        `xml:"assignee"`
+	Description   string        `xml:"description"`
+	Workflow      string        `xml:"workflow,omitempty"`
+	Requirements  string        `xml:"requirements,omitempty"`
+	Dependencies  string        `xml:"dependencies,omitempty"`
+	Custom        CustomFields  `xml:"custom>field"` // Values of the custom fields declared in the config
+	Notes         []Note        `xml:"notes>note"`   // Left with 'agentpm note add', see note.go
+	Metadata      *EpicMetadata `xml:"metadata,omitempty"`
+	CurrentState  *CurrentState `xml:"current_state,omitempty"`
+	Phases        []Phase       `xml:"phases>phase"`
+	Milestones    []Milestone   `xml:"milestones>milestone"`
+	Suppressions  []Suppression `xml:"suppressions>suppress"`
+	Blockers      []Blocker     `xml:"blockers>blocker"`
+	Tasks         []Task        `xml:"tasks>task"`
+	Tests         []Test        `xml:"tests>test"`
+	Events        []Event       `xml:"events>event"`
 }
 
 // Epic 13 Status System Methods
@@ -57,13 +71,31 @@ type CurrentState struct {
 }
 
 type Phase struct {
-	ID           string     `xml:"id,attr"`
-	Name         string     `xml:"name,attr"`
-	Description  string     `xml:"description"`
-	Deliverables string     `xml:"deliverables"`
-	Status       Status     `xml:"status,attr"`
-	StartedAt    *time.Time `xml:"started_at,omitempty"`
-	CompletedAt  *time.Time `xml:"completed_at,omitempty"`
+	ID           string       `xml:"id,attr"`
+	Name         string       `xml:"name,attr"`
+	Description  string       `xml:"description"`
+	Deliverables string       `xml:"deliverables"`
+	SpecRef      string       `xml:"spec_ref,attr,omitempty"`
+	Due          string       `xml:"due,attr,omitempty"`        // Deadline, see ParseDate
+	DependsOn    string       `xml:"depends_on,attr,omitempty"` // Comma-separated IDs of phases to complete first, see DependencyIDs
+	Status       Status       `xml:"status,attr"`
+	Assignee     string       `xml:"assignee,attr,omitempty"` // Default assignee of the phase's tasks
+	StartedAt    *time.Time   `xml:"started_at,omitempty"`
+	CompletedAt  *time.Time   `xml:"completed_at,omitempty"`
+	FrozenAt     *time.Time   `xml:"frozen_at,omitempty"`
+	FrozenReason string       `xml:"frozen_reason,omitempty"`
+	Custom       CustomFields `xml:"custom>field"`
+	Notes        []Note       `xml:"notes>note"`
+}
+
+// IsFrozen reports whether the phase has been frozen and must not change any more
+func (p *Phase) IsFrozen() bool {
+	return p.FrozenAt != nil
+}
+
+// DependencyIDs returns the IDs of the phases listed in DependsOn
+func (p *Phase) DependencyIDs() []string {
+	return splitIDs(p.DependsOn)
 }
 
 // Epic 13 Status System Methods
@@ -78,17 +110,59 @@ func (p *Phase) SetPhaseStatus(status PhaseStatus) {
 	p.Status = FromPhaseStatus(status)
 }
 
+// Milestone is a named target date of the epic
+type Milestone struct {
+	ID          string `xml:"id,attr"`
+	Name        string `xml:"name,attr"`
+	TargetDate  string `xml:"target_date,attr"` // see ParseDate
+	Description string `xml:"description,omitempty"`
+}
+
+// Suppression silences a policy rule for one phase or task, or for the whole
+// epic when Entity is empty
+type Suppression struct {
+	Rule   string `xml:"rule,attr"`
+	Entity string `xml:"entity,attr,omitempty"`
+	Reason string `xml:",chardata"`
+}
+
+// ParseDate parses a deadline or target date: either a calendar day (2025-09-15)
+// or a point in time in RFC3339 format (2025-09-15T17:00:00Z)
+func ParseDate(value string) (date time.Time, allDay bool, err error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, true, nil
+	}
+	date, err = time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid date %q (use 2006-01-02 or RFC3339)", value)
+	}
+	return date, false, nil
+}
+
 type Task struct {
-	ID                 string     `xml:"id,attr"`
-	PhaseID            string     `xml:"phase_id,attr"`
-	Name               string     `xml:"name,attr"`
-	Description        string     `xml:"description"`
-	AcceptanceCriteria string     `xml:"acceptance_criteria"`
-	Status             Status     `xml:"status,attr"`
-	Assignee           string     `xml:"assignee,attr,omitempty"`
-	StartedAt          *time.Time `xml:"started_at,omitempty"`
-	CompletedAt        *time.Time `xml:"completed_at,omitempty"`
-	CancelledAt        *time.Time `xml:"cancelled_at,omitempty"`
+	ID                 string       `xml:"id,attr"`
+	PhaseID            string       `xml:"phase_id,attr"`
+	Name               string       `xml:"name,attr"`
+	Description        string       `xml:"description"`
+	AcceptanceCriteria string       `xml:"acceptance_criteria"`
+	SpecRef            string       `xml:"spec_ref,attr,omitempty"`
+	Due                string       `xml:"due,attr,omitempty"`            // Deadline, see ParseDate
+	DependsOn          string       `xml:"depends_on,attr,omitempty"`     // Comma-separated IDs of tasks to complete first, see DependencyIDs
+	ParentTaskID       string       `xml:"parent_task_id,attr,omitempty"` // Task this is a sub-task of, see SubTasks
+	Recurring          string       `xml:"recurring,attr,omitempty"`      // Interval the task is scheduled again after, see NextOccurrence
+	Estimate           string       `xml:"estimate,attr,omitempty"`       // Effort in points or minutes, see EstimatedEffort
+	Status             Status       `xml:"status,attr"`
+	Assignee           string       `xml:"assignee,attr,omitempty"`
+	StartedAt          *time.Time   `xml:"started_at,omitempty"`
+	CompletedAt        *time.Time   `xml:"completed_at,omitempty"`
+	CancelledAt        *time.Time   `xml:"cancelled_at,omitempty"`
+	Custom             CustomFields `xml:"custom>field"`
+	Notes              []Note       `xml:"notes>note"`
+}
+
+// DependencyIDs returns the IDs of the tasks listed in DependsOn
+func (t *Task) DependencyIDs() []string {
+	return splitIDs(t.DependsOn)
 }
 
 // Epic 13 Status System Methods
@@ -145,14 +219,93 @@ type Test struct {
 	Description string `xml:"description"`
 	Status      Status `xml:"status,attr"`
 	// Epic 13 unified status system
-	TestStatus         TestStatus `xml:"test_status,attr"`
-	TestResult         TestResult `xml:"result,attr"`
-	StartedAt          *time.Time `xml:"started_at,omitempty"`
-	PassedAt           *time.Time `xml:"passed_at,omitempty"`
-	FailedAt           *time.Time `xml:"failed_at,omitempty"`
-	CancelledAt        *time.Time `xml:"cancelled_at,omitempty"`
-	FailureNote        string     `xml:"failure_note,omitempty"`
-	CancellationReason string     `xml:"cancellation_reason,omitempty"`
+	TestStatus         TestStatus      `xml:"test_status,attr"`
+	TestResult         TestResult      `xml:"result,attr"`
+	Requires           string          `xml:"requires,attr,omitempty"` // Comma-separated IDs of tests or tasks to finish first, see RequiredIDs
+	Tags               string          `xml:"tags,attr,omitempty"`     // Comma-separated kinds, e.g. "unit,integration", see TagList
+	Type               string          `xml:"type,attr,omitempty"`     // TestTypeManual for checks a human signs off, empty for agent-executable tests
+	VerifiedBy         string          `xml:"verified_by,attr,omitempty"`
+	Evidence           string          `xml:"evidence,attr,omitempty"`   // Link to a screenshot, recording or ticket backing the verdict
+	FailCount          int             `xml:"fail_count,attr,omitempty"` // Times the test failed, see Stability
+	PassCount          int             `xml:"pass_count,attr,omitempty"` // Times the test passed
+	StartedAt          *time.Time      `xml:"started_at,omitempty"`
+	PassedAt           *time.Time      `xml:"passed_at,omitempty"`
+	FailedAt           *time.Time      `xml:"failed_at,omitempty"`
+	CancelledAt        *time.Time      `xml:"cancelled_at,omitempty"`
+	FailureNote        string          `xml:"failure_note,omitempty"`
+	FailureContext     *FailureContext `xml:"failure_context,omitempty"` // Expected and actual values, stack and links of the last failure
+	CancellationReason string          `xml:"cancellation_reason,omitempty"`
+	Custom             CustomFields    `xml:"custom>field"`
+	Notes              []Note          `xml:"notes>note"`
+}
+
+// RequiredIDs returns the IDs of the tests and tasks listed in Requires
+func (t *Test) RequiredIDs() []string {
+	return splitIDs(t.Requires)
+}
+
+// TagList returns the tags listed in Tags
+func (t *Test) TagList() []string {
+	return splitIDs(t.Tags)
+}
+
+// HasTag reports whether the test has the tag, ignoring case
+func (t *Test) HasTag(tag string) bool {
+	for _, own := range t.TagList() {
+		if strings.EqualFold(own, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// TestTypeManual marks a test a human verifies: passing or failing it names the verifier
+const TestTypeManual = "manual"
+
+// IsManual reports whether the test is a human-checked test
+func (t *Test) IsManual() bool {
+	return strings.EqualFold(strings.TrimSpace(t.Type), TestTypeManual)
+}
+
+// FlakyFailures is how often a test has to fail before passing again makes it flaky
+const FlakyFailures = 2
+
+// Stability classifications of a test's pass/fail history
+const (
+	StabilityStable  = "stable"  // Never failed
+	StabilityFailing = "failing" // Failed and not passed since
+	StabilityFixed   = "fixed"   // Passes again after fewer than FlakyFailures failures
+	StabilityFlaky   = "flaky"   // Passes again after FlakyFailures or more failures
+)
+
+// Stability classifies the test by how often it failed and whether it passes now
+func (t *Test) Stability() string {
+	switch {
+	case t.FailCount == 0:
+		return StabilityStable
+	case t.GetTestStatusUnified() != TestStatusDone || t.PassCount == 0:
+		return StabilityFailing
+	case t.FailCount >= FlakyFailures:
+		return StabilityFlaky
+	default:
+		return StabilityFixed
+	}
+}
+
+// IsFlaky reports whether the test failed FlakyFailures times or more and passes again
+func (t *Test) IsFlaky() bool {
+	return t.Stability() == StabilityFlaky
+}
+
+// splitIDs splits a comma-separated list of IDs
+func splitIDs(list string) []string {
+	var ids []string
+	for _, id := range strings.Split(list, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
 }
 
 // Epic 13 Status System Methods for Test
@@ -213,6 +366,26 @@ func (t *Test) GetTestResult() TestResult {
 	return TestResultPassing
 }
 
+// Test gating modes, see GatesUnder
+const (
+	TestGatingLenient = "lenient"
+	TestGatingOff     = "off"
+)
+
+// GatesUnder reports whether the test may hold up the completion of its phase
+// under a test gating mode: never when gating is off, only while it is failing
+// when gating is lenient, and otherwise until it is done.
+func (t *Test) GatesUnder(mode string) bool {
+	switch mode {
+	case TestGatingOff:
+		return false
+	case TestGatingLenient:
+		return t.GetTestResult() == TestResultFailing
+	default:
+		return true
+	}
+}
+
 // SetTestResult sets the test result using the Epic 13 unified system
 func (t *Test) SetTestResult(result TestResult) {
 	t.TestResult = result
@@ -222,7 +395,12 @@ type Event struct {
 	ID        string    `xml:"id,attr"`
 	Type      string    `xml:"type,attr"`
 	Timestamp time.Time `xml:"timestamp,attr"`
-	Data      string    `xml:"data"`
+	Actor     string    `xml:"actor,attr,omitempty"`  // Agent or human who caused the event, if known
+	Commit    string    `xml:"commit,attr,omitempty"` // Git commit checked out when the event was recorded, if any
+	Branch    string    `xml:"branch,attr,omitempty"` // Git branch checked out when the event was recorded, if any
+	// CorrelationID is shared by the events recorded by one command invocation
+	CorrelationID string `xml:"correlation_id,attr,omitempty"`
+	Data          string `xml:"data"`
 }
 
 func (s Status) IsValid() bool {
@@ -0,0 +1,45 @@
+package epic
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ParseEstimate parses the estimate of a task: a non-negative number in the
+// unit the team estimates in, e.g. story points or minutes
+func ParseEstimate(value string) (float64, error) {
+	estimate, err := strconv.ParseFloat(value, 64)
+	if err != nil || estimate < 0 {
+		return 0, fmt.Errorf("invalid estimate %q (use a non-negative number, e.g. 3 or 0.5)", value)
+	}
+	return estimate, nil
+}
+
+// EstimatedEffort returns the estimate of the task and whether it has a valid one
+func (t *Task) EstimatedEffort() (float64, bool) {
+	if t.Estimate == "" {
+		return 0, false
+	}
+	estimate, err := ParseEstimate(t.Estimate)
+	return estimate, err == nil
+}
+
+// validateEstimates checks the estimates of tasks
+func (e *Epic) validateEstimates(result *ValidationResult) {
+	errorCount := len(result.Errors)
+
+	for _, task := range e.Tasks {
+		if task.Estimate == "" {
+			continue
+		}
+		if _, err := ParseEstimate(task.Estimate); err != nil {
+			result.AddError(fmt.Sprintf("Task %s has an %v", task.ID, err))
+		}
+	}
+
+	if len(result.Errors) == errorCount {
+		result.SetCheck("estimates", "passed")
+	} else {
+		result.SetCheck("estimates", "failed")
+	}
+}
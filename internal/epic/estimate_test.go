@@ -0,0 +1,35 @@
+package epic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEstimate(t *testing.T) {
+	for value, want := range map[string]float64{"3": 3, "0.5": 0.5, "0": 0} {
+		estimate, err := ParseEstimate(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, estimate, value)
+	}
+	for _, value := range []string{"", "-1", "3pts"} {
+		_, err := ParseEstimate(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestValidateEstimates(t *testing.T) {
+	e := &Epic{
+		Tasks: []Task{
+			{ID: "1_1", Estimate: "3"},
+			{ID: "1_2", Estimate: "three"},
+			{ID: "1_3"},
+		},
+	}
+	result := &ValidationResult{Valid: true, Checks: make(map[string]string)}
+	e.validateEstimates(result)
+	assert.Equal(t, "failed", result.Checks["estimates"])
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0], `Task 1_2 has an invalid estimate "three"`)
+}
@@ -0,0 +1,49 @@
+package epic
+
+import "strings"
+
+// EventEntityID returns the ID of the phase, task or test an event refers to, read
+// back from its data ("Task 1A_1 (Name) completed"). Epic events and events whose
+// data does not name their entity return "".
+func EventEntityID(event Event) string {
+	entityType, _, found := strings.Cut(event.Type, "_")
+	if !found || entityType == "epic" {
+		return ""
+	}
+
+	fields := strings.Fields(event.Data)
+	if len(fields) < 2 || !strings.EqualFold(fields[0], entityType) {
+		return ""
+	}
+	return strings.TrimSuffix(fields[1], ":")
+}
+
+// EventScope returns the phase and task an event belongs to: the phase of a
+// phase event, the task and its phase of a task event, and for a test event
+// the task of the test and its phase
+func (e *Epic) EventScope(event Event) (phaseID, taskID string) {
+	entityID := EventEntityID(event)
+	if entityID == "" {
+		return "", ""
+	}
+	entityType, _, _ := strings.Cut(event.Type, "_")
+	switch entityType {
+	case "phase":
+		return entityID, ""
+	case "task":
+		taskID = entityID
+	case "test":
+		for _, test := range e.Tests {
+			if test.ID == entityID {
+				taskID = test.TaskID
+				break
+			}
+		}
+	}
+	for _, task := range e.Tasks {
+		if task.ID == taskID {
+			return task.PhaseID, taskID
+		}
+	}
+	return "", taskID
+}
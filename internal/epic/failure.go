@@ -0,0 +1,35 @@
+package epic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FailureContext is the structured detail attached to a failing test next to
+// its failure note, stored as
+// <failure_context><expected/><actual/><stack/><link/></failure_context>
+type FailureContext struct {
+	Expected string   `xml:"expected,omitempty" json:"expected,omitempty" yaml:"expected,omitempty"`
+	Actual   string   `xml:"actual,omitempty" json:"actual,omitempty" yaml:"actual,omitempty"`
+	Stack    string   `xml:"stack,omitempty" json:"stack,omitempty" yaml:"stack,omitempty"`
+	Links    []string `xml:"link,omitempty" json:"links,omitempty" yaml:"links,omitempty"`
+}
+
+// IsEmpty reports whether the context holds no detail
+func (c *FailureContext) IsEmpty() bool {
+	return c == nil || (c.Expected == "" && c.Actual == "" && c.Stack == "" && len(c.Links) == 0)
+}
+
+// MaxStackLines is the number of stack trace lines kept in a failure context
+const MaxStackLines = 50
+
+// TrimStack keeps the first MaxStackLines lines of a stack trace, so that a
+// long trace does not swamp the epic file
+func TrimStack(stack string) string {
+	lines := strings.Split(strings.TrimRight(stack, "\n"), "\n")
+	if len(lines) <= MaxStackLines {
+		return strings.Join(lines, "\n")
+	}
+	kept := strings.Join(lines[:MaxStackLines], "\n")
+	return fmt.Sprintf("%s\n... %d more lines", kept, len(lines)-MaxStackLines)
+}
@@ -0,0 +1,98 @@
+package epic
+
+import (
+	"errors"
+	"fmt"
+)
+
+// EpicCompletedError is returned when a mutating operation targets an epic that is already done
+type EpicCompletedError struct {
+	EpicID    string
+	Operation string
+	Hint      string // Actionable hint for resolving the error
+}
+
+func (e *EpicCompletedError) Error() string {
+	return fmt.Sprintf("cannot %s: epic %s is already completed. %s", e.Operation, e.EpicID, e.Hint)
+}
+
+func NewEpicCompletedError(epicID, operation string) *EpicCompletedError {
+	return &EpicCompletedError{
+		EpicID:    epicID,
+		Operation: operation,
+		Hint:      fmt.Sprintf("Reopen epic %s with 'agentpm reopen epic' before making further changes", epicID),
+	}
+}
+
+// IsEpicCompleted reports whether err is (or wraps) an EpicCompletedError
+func IsEpicCompleted(err error) bool {
+	var completedErr *EpicCompletedError
+	return errors.As(err, &completedErr)
+}
+
+// PhaseFrozenError is returned when a mutating operation targets a frozen phase or its tasks and tests
+type PhaseFrozenError struct {
+	PhaseID   string
+	Reason    string
+	Operation string
+	Hint      string // Actionable hint for resolving the error
+}
+
+func (e *PhaseFrozenError) Error() string {
+	frozen := fmt.Sprintf("phase %s is frozen", e.PhaseID)
+	if e.Reason != "" {
+		frozen += fmt.Sprintf(" (%s)", e.Reason)
+	}
+	return fmt.Sprintf("cannot %s: %s. %s", e.Operation, frozen, e.Hint)
+}
+
+func NewPhaseFrozenError(phaseID, reason, operation string) *PhaseFrozenError {
+	return &PhaseFrozenError{
+		PhaseID:   phaseID,
+		Reason:    reason,
+		Operation: operation,
+		Hint:      fmt.Sprintf("Run 'agentpm unfreeze phase %s' before making further changes", phaseID),
+	}
+}
+
+// IsPhaseFrozen reports whether err is (or wraps) a PhaseFrozenError
+func IsPhaseFrozen(err error) bool {
+	var frozenErr *PhaseFrozenError
+	return errors.As(err, &frozenErr)
+}
+
+// EnsureMutable is the shared pre-mutation check for all services changing an epic.
+// It fails with an EpicCompletedError once the epic is done, so completed epics keep
+// their final state and reporting stays accurate.
+func (e *Epic) EnsureMutable(operation string) error {
+	if e.GetEpicStatus() == EpicStatusDone {
+		return NewEpicCompletedError(e.ID, operation)
+	}
+	return nil
+}
+
+// CheckMutation applies EnsureMutable before a changed epic is saved over the
+// stored one, for the writes no service checks: a completed epic that stays
+// completed must not change. Completing an epic and reopening it pass, and so
+// does a new epic (before is nil).
+func CheckMutation(before, after *Epic, operation string) error {
+	if before == nil || after.GetEpicStatus() != EpicStatusDone {
+		return nil
+	}
+	return before.EnsureMutable(operation)
+}
+
+// EnsurePhaseMutable extends EnsureMutable for changes to a phase, its tasks or its
+// tests: it also fails with a PhaseFrozenError while the phase is frozen. Unknown
+// phases are left to the caller's own lookup.
+func (e *Epic) EnsurePhaseMutable(phaseID, operation string) error {
+	if err := e.EnsureMutable(operation); err != nil {
+		return err
+	}
+	for i := range e.Phases {
+		if e.Phases[i].ID == phaseID && e.Phases[i].IsFrozen() {
+			return NewPhaseFrozenError(phaseID, e.Phases[i].FrozenReason, operation)
+		}
+	}
+	return nil
+}
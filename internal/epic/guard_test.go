@@ -0,0 +1,111 @@
+package epic
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEnsureMutable(t *testing.T) {
+	t.Run("active epics can be modified", func(t *testing.T) {
+		for _, status := range []Status{StatusPending, StatusWIP} {
+			e := &Epic{ID: "epic-1", Status: status}
+			if err := e.EnsureMutable("start task 1A_1"); err != nil {
+				t.Errorf("Expected %s epic to be mutable, got %v", status, err)
+			}
+		}
+	})
+
+	t.Run("completed epic is rejected with hint", func(t *testing.T) {
+		e := &Epic{ID: "epic-1", Status: StatusCompleted}
+
+		err := e.EnsureMutable("start task 1A_1")
+		if err == nil {
+			t.Fatal("Expected error for completed epic")
+		}
+
+		completedErr, ok := err.(*EpicCompletedError)
+		if !ok {
+			t.Fatalf("Expected EpicCompletedError, got %T", err)
+		}
+		if completedErr.EpicID != "epic-1" {
+			t.Errorf("Expected epic ID epic-1, got %s", completedErr.EpicID)
+		}
+		if !strings.Contains(completedErr.Hint, "Reopen epic epic-1") {
+			t.Errorf("Expected reopen hint, got %s", completedErr.Hint)
+		}
+		if !strings.Contains(err.Error(), "cannot start task 1A_1") {
+			t.Errorf("Expected operation in message, got %s", err.Error())
+		}
+	})
+
+	t.Run("IsEpicCompleted sees through wrapping", func(t *testing.T) {
+		err := fmt.Errorf("failed: %w", NewEpicCompletedError("epic-1", "pass test"))
+		if !IsEpicCompleted(err) {
+			t.Error("Expected wrapped error to be detected as EpicCompletedError")
+		}
+		if IsEpicCompleted(fmt.Errorf("other")) {
+			t.Error("Expected plain error not to be detected as EpicCompletedError")
+		}
+	})
+}
+
+func TestEnsurePhaseMutable(t *testing.T) {
+	frozenAt := time.Date(2025, 8, 16, 9, 0, 0, 0, time.UTC)
+	e := &Epic{
+		ID:     "epic-1",
+		Status: StatusWIP,
+		Phases: []Phase{
+			{ID: "1A", Status: StatusCompleted, FrozenAt: &frozenAt, FrozenReason: "Shipped in v1.2"},
+			{ID: "1B", Status: StatusWIP},
+		},
+	}
+
+	t.Run("unfrozen and unknown phases can be modified", func(t *testing.T) {
+		if err := e.EnsurePhaseMutable("1B", "start task 1B_1"); err != nil {
+			t.Errorf("Expected phase 1B to be mutable, got %v", err)
+		}
+		if err := e.EnsurePhaseMutable("unknown", "start phase unknown"); err != nil {
+			t.Errorf("Expected unknown phase to be left to the caller, got %v", err)
+		}
+	})
+
+	t.Run("frozen phase is rejected with reason and hint", func(t *testing.T) {
+		err := e.EnsurePhaseMutable("1A", "start task 1A_1")
+		if !IsPhaseFrozen(err) {
+			t.Fatalf("Expected PhaseFrozenError, got %v", err)
+		}
+
+		expected := "cannot start task 1A_1: phase 1A is frozen (Shipped in v1.2). Run 'agentpm unfreeze phase 1A' before making further changes"
+		if err.Error() != expected {
+			t.Errorf("Expected message %q, got %q", expected, err.Error())
+		}
+	})
+
+	t.Run("completed epic takes precedence", func(t *testing.T) {
+		completed := *e
+		completed.Status = StatusCompleted
+		if err := completed.EnsurePhaseMutable("1A", "start task 1A_1"); !IsEpicCompleted(err) {
+			t.Errorf("Expected EpicCompletedError, got %v", err)
+		}
+	})
+}
+
+func TestCheckMutation(t *testing.T) {
+	active := &Epic{ID: "epic-1", Status: StatusWIP}
+	completed := &Epic{ID: "epic-1", Status: StatusCompleted}
+
+	if err := CheckMutation(nil, completed, "create"); err != nil {
+		t.Errorf("Expected a new epic to pass, got %v", err)
+	}
+	if err := CheckMutation(active, completed, "done epic"); err != nil {
+		t.Errorf("Expected completing an epic to pass, got %v", err)
+	}
+	if err := CheckMutation(completed, active, "reopen epic"); err != nil {
+		t.Errorf("Expected reopening an epic to pass, got %v", err)
+	}
+	if err := CheckMutation(completed, completed, "start task 1A_1"); !IsEpicCompleted(err) {
+		t.Errorf("Expected EpicCompletedError, got %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package epic
+
+import "time"
+
+// Note is a free-text remark an agent leaves on the epic, a phase, a task or
+// a test, stored as <notes><note author="agent_a" created_at="...">text</note></notes>
+type Note struct {
+	Author    string    `xml:"author,attr,omitempty" json:"author,omitempty" yaml:"author,omitempty"`
+	CreatedAt time.Time `xml:"created_at,attr" json:"created_at" yaml:"created_at"`
+	Text      string    `xml:",chardata" json:"text" yaml:"text"`
+}
+
+// Entity types notes can be added to
+const (
+	NoteOnEpic  = "epic"
+	NoteOnPhase = "phase"
+	NoteOnTask  = "task"
+	NoteOnTest  = "test"
+)
+
+// NotesOf returns the notes of the epic, or of the phase, task or test with
+// the ID, for adding to them. It returns nil when the entity does not exist.
+func (e *Epic) NotesOf(entityType, id string) *[]Note {
+	switch entityType {
+	case NoteOnEpic:
+		return &e.Notes
+	case NoteOnPhase:
+		for i := range e.Phases {
+			if e.Phases[i].ID == id {
+				return &e.Phases[i].Notes
+			}
+		}
+	case NoteOnTask:
+		for i := range e.Tasks {
+			if e.Tasks[i].ID == id {
+				return &e.Tasks[i].Notes
+			}
+		}
+	case NoteOnTest:
+		for i := range e.Tests {
+			if e.Tests[i].ID == id {
+				return &e.Tests[i].Notes
+			}
+		}
+	}
+	return nil
+}
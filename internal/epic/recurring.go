@@ -0,0 +1,136 @@
+package epic
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurring tasks are ongoing chores: a task with a Recurring interval is
+// scheduled again each time it is completed, as a fresh pending occurrence
+// with its own ID and a due date one interval later. Pending occurrences do
+// not hold up their phase; completing the phase ends the recurrence.
+
+// occurrenceSuffix matches the suffix NextOccurrence appends to task IDs
+var occurrenceSuffix = regexp.MustCompile(`_r[0-9]+$`)
+
+// ParseInterval parses the interval of a recurring task: a number of days
+// (7d) or weeks (2w), or a duration like 12h
+func ParseInterval(value string) (time.Duration, error) {
+	units := []struct {
+		suffix string
+		unit   time.Duration
+	}{{"d", 24 * time.Hour}, {"w", 7 * 24 * time.Hour}}
+	for _, u := range units {
+		if count, ok := strings.CutSuffix(value, u.suffix); ok {
+			if n, err := strconv.Atoi(count); err == nil && n > 0 {
+				return time.Duration(n) * u.unit, nil
+			}
+		}
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		return 0, fmt.Errorf("invalid recurring interval %q (use 7d, 2w or a duration like 12h)", value)
+	}
+	return interval, nil
+}
+
+// IsRecurring reports whether the task is scheduled again when completed
+func (t *Task) IsRecurring() bool {
+	return t.Recurring != ""
+}
+
+// IsUpcoming reports whether the task is a pending occurrence of a recurring
+// task, which does not hold up its phase
+func (t *Task) IsUpcoming() bool {
+	return t.IsRecurring() && t.Status == StatusPending
+}
+
+// NextOccurrence returns the pending task that follows a recurring task
+// completed at completedAt. It is due one interval after the previous due
+// date, skipping occurrences already missed, or one interval after the
+// completion when the task had no due date. Its ID is the task's ID with an
+// occurrence suffix, e.g. 1A_3_r2.
+func (e *Epic) NextOccurrence(task *Task, completedAt time.Time) (Task, error) {
+	interval, err := ParseInterval(task.Recurring)
+	if err != nil {
+		return Task{}, err
+	}
+
+	due, allDay := completedAt, interval%(24*time.Hour) == 0
+	if task.Due != "" {
+		if due, allDay, err = ParseDate(task.Due); err != nil {
+			return Task{}, err
+		}
+	}
+	due = due.Add(interval)
+	for !due.After(completedAt) {
+		due = due.Add(interval)
+	}
+
+	next := Task{
+		ID:                 e.nextOccurrenceID(task.ID),
+		PhaseID:            task.PhaseID,
+		Name:               task.Name,
+		Description:        task.Description,
+		AcceptanceCriteria: task.AcceptanceCriteria,
+		SpecRef:            task.SpecRef,
+		Due:                due.Format(time.RFC3339),
+		Recurring:          task.Recurring,
+		Estimate:           task.Estimate,
+		Status:             StatusPending,
+		Assignee:           task.Assignee,
+	}
+	if allDay {
+		next.Due = due.Format(time.DateOnly)
+	}
+	return next, nil
+}
+
+// nextOccurrenceID numbers the occurrences of a recurring task after the ID
+// of its first occurrence, which has no suffix
+func (e *Epic) nextOccurrenceID(taskID string) string {
+	base := occurrenceSuffix.ReplaceAllString(taskID, "")
+	for n := 2; ; n++ {
+		id := fmt.Sprintf("%s_r%d", base, n)
+		if e.findTask(id) == nil {
+			return id
+		}
+	}
+}
+
+// findTask returns the task with the given ID, or nil
+func (e *Epic) findTask(taskID string) *Task {
+	for i := range e.Tasks {
+		if e.Tasks[i].ID == taskID {
+			return &e.Tasks[i]
+		}
+	}
+	return nil
+}
+
+// validateRecurring checks the intervals of recurring tasks. Sub-tasks
+// cannot recur, since their parent is done once they are.
+func (e *Epic) validateRecurring(result *ValidationResult) {
+	errorCount := len(result.Errors)
+
+	for _, task := range e.Tasks {
+		if !task.IsRecurring() {
+			continue
+		}
+		if _, err := ParseInterval(task.Recurring); err != nil {
+			result.AddError(fmt.Sprintf("Task %s has an %v", task.ID, err))
+		}
+		if task.IsSubTask() {
+			result.AddError(fmt.Sprintf("Task %s is a sub-task and cannot be recurring", task.ID))
+		}
+	}
+
+	if len(result.Errors) == errorCount {
+		result.SetCheck("recurring", "passed")
+	} else {
+		result.SetCheck("recurring", "failed")
+	}
+}
@@ -0,0 +1,93 @@
+package epic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInterval(t *testing.T) {
+	for value, want := range map[string]time.Duration{
+		"7d":  7 * 24 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+		"12h": 12 * time.Hour,
+	} {
+		interval, err := ParseInterval(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, interval, value)
+	}
+
+	for _, value := range []string{"", "0d", "-1w", "weekly", "-3h"} {
+		_, err := ParseInterval(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	e := &Epic{
+		Tasks: []Task{
+			{ID: "1_1", PhaseID: "1", Name: "Rotate keys", Description: "Rotate the API keys", Recurring: "7d", Due: "2025-09-15", Status: StatusCompleted, Assignee: "ops"},
+			{ID: "1_1_r2", PhaseID: "1", Name: "Rotate keys", Recurring: "7d", Status: StatusCompleted},
+		},
+	}
+	completedAt := time.Date(2025, 9, 16, 10, 0, 0, 0, time.UTC)
+
+	next, err := e.NextOccurrence(&e.Tasks[0], completedAt)
+	require.NoError(t, err)
+	assert.Equal(t, Task{
+		ID:          "1_1_r3",
+		PhaseID:     "1",
+		Name:        "Rotate keys",
+		Description: "Rotate the API keys",
+		Due:         "2025-09-22",
+		Recurring:   "7d",
+		Status:      StatusPending,
+		Assignee:    "ops",
+	}, next)
+
+	t.Run("skips missed occurrences", func(t *testing.T) {
+		next, err := e.NextOccurrence(&e.Tasks[0], time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Equal(t, "2025-10-06", next.Due)
+	})
+
+	t.Run("counts from the completion without a due date", func(t *testing.T) {
+		next, err := e.NextOccurrence(&e.Tasks[1], completedAt)
+		require.NoError(t, err)
+		assert.Equal(t, "1_1_r3", next.ID)
+		assert.Equal(t, "2025-09-23", next.Due)
+
+		hourly := Task{ID: "1_2", Recurring: "12h"}
+		next, err = e.NextOccurrence(&hourly, completedAt)
+		require.NoError(t, err)
+		assert.Equal(t, "1_2_r2", next.ID)
+		assert.Equal(t, "2025-09-16T22:00:00Z", next.Due)
+	})
+
+	t.Run("invalid interval", func(t *testing.T) {
+		_, err := e.NextOccurrence(&Task{ID: "1_3", Recurring: "often"}, completedAt)
+		assert.Error(t, err)
+	})
+}
+
+func TestIsUpcoming(t *testing.T) {
+	assert.True(t, (&Task{Recurring: "1d", Status: StatusPending}).IsUpcoming())
+	assert.False(t, (&Task{Recurring: "1d", Status: StatusWIP}).IsUpcoming())
+	assert.False(t, (&Task{Status: StatusPending}).IsUpcoming())
+}
+
+func TestEpic_ValidateRecurring(t *testing.T) {
+	e := createSubTaskEpic()
+	e.Tasks[0].Recurring = "1w"
+	result := e.Validate()
+	assert.Equal(t, "passed", result.Checks["recurring"])
+
+	e.Tasks[1].Recurring = "1d"
+	e.Tasks[4].Recurring = "often"
+	result = e.Validate()
+	assert.Contains(t, result.Errors, "Task 1_1a is a sub-task and cannot be recurring")
+	assert.Contains(t, result.Errors, `Task 1_2 has an invalid recurring interval "often" (use 7d, 2w or a duration like 12h)`)
+	assert.Equal(t, "failed", result.Checks["recurring"])
+}
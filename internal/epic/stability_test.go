@@ -0,0 +1,28 @@
+package epic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestStability(t *testing.T) {
+	cases := []struct {
+		name string
+		test Test
+		want string
+	}{
+		{"never failed", Test{TestStatus: TestStatusDone, PassCount: 3}, StabilityStable},
+		{"failing now", Test{TestStatus: TestStatusWIP, FailCount: 1}, StabilityFailing},
+		{"failing again after passes", Test{TestStatus: TestStatusWIP, FailCount: 3, PassCount: 2}, StabilityFailing},
+		{"fixed after one failure", Test{TestStatus: TestStatusDone, FailCount: 1, PassCount: 1}, StabilityFixed},
+		{"passing after repeated failures", Test{TestStatus: TestStatusDone, FailCount: FlakyFailures, PassCount: 1}, StabilityFlaky},
+		{"legacy status", Test{Status: StatusCompleted, FailCount: 4, PassCount: 4}, StabilityFlaky},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.test.Stability())
+			assert.Equal(t, tc.want == StabilityFlaky, tc.test.IsFlaky())
+		})
+	}
+}
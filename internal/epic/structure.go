@@ -0,0 +1,385 @@
+package epic
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// StructureViolation is a problem with the XML structure of an epic file,
+// found before the file is interpreted: a missing required attribute, an
+// element or attribute agentpm does not know, a duplicate ID or a reference to
+// an ID that is not defined
+type StructureViolation struct {
+	Line     int    `json:"line"`
+	Severity string `json:"severity"` // "error" or "warning"
+	Message  string `json:"message"`
+}
+
+func (v StructureViolation) String() string {
+	return fmt.Sprintf("line %d: %s", v.Line, v.Message)
+}
+
+// elementSpec describes an element of the epic file format
+type elementSpec struct {
+	required      []string // Required attributes
+	optional      []string // Optional attributes
+	children      []string // Known child elements
+	content       bool     // Holds free-form markup, its children are not checked
+	contentUnless string   // Holds free-form markup unless it has this child
+}
+
+// epicFormat is the epic file format, keyed by the element path from the root
+var epicFormat = map[string]elementSpec{
+	"epic": {
+		required: []string{"id", "name", "status", "created_at"},
+		optional: []string{"status_model", "schema_version", "due"},
+		children: []string{"assignee", "description", "workflow", "requirements", "dependencies", "custom", "notes",
+			"metadata", "current_state", "phases", "milestones", "suppressions", "blockers", "tasks", "tests", "events"},
+	},
+	"epic/assignee":                   {},
+	"epic/description":                {content: true},
+	"epic/workflow":                   {content: true},
+	"epic/requirements":               {content: true},
+	"epic/dependencies":               {content: true},
+	"epic/custom":                     {children: []string{"field"}},
+	"epic/custom/field":               {required: []string{"name"}},
+	"epic/notes":                      {children: []string{"note"}},
+	"epic/notes/note":                 {required: []string{"created_at"}, optional: []string{"author"}},
+	"epic/metadata":                   {children: []string{"created", "assignee", "estimated_effort"}},
+	"epic/metadata/created":           {},
+	"epic/metadata/assignee":          {},
+	"epic/metadata/estimated_effort":  {},
+	"epic/current_state":              {children: []string{"active_phase", "active_task", "next_action"}},
+	"epic/current_state/active_phase": {},
+	"epic/current_state/active_task":  {},
+	"epic/current_state/next_action":  {},
+	"epic/phases":                     {children: []string{"phase"}},
+	"epic/milestones":                 {children: []string{"milestone"}},
+	"epic/suppressions":               {children: []string{"suppress"}},
+	"epic/blockers":                   {children: []string{"blocker"}},
+	"epic/tasks":                      {children: []string{"task"}},
+	"epic/tests":                      {children: []string{"test"}},
+	"epic/events":                     {children: []string{"event"}},
+	"epic/phases/phase": {
+		required: []string{"id"},
+		optional: []string{"name", "status", "assignee", "spec_ref", "due", "depends_on"},
+		children: []string{"description", "deliverables", "started_at", "completed_at", "frozen_at", "frozen_reason", "custom", "notes"},
+	},
+	"epic/phases/phase/description":   {content: true},
+	"epic/phases/phase/deliverables":  {content: true},
+	"epic/phases/phase/started_at":    {},
+	"epic/phases/phase/completed_at":  {},
+	"epic/phases/phase/frozen_at":     {},
+	"epic/phases/phase/frozen_reason": {},
+	"epic/phases/phase/custom":        {children: []string{"field"}},
+	"epic/phases/phase/custom/field":  {required: []string{"name"}},
+	"epic/phases/phase/notes":         {children: []string{"note"}},
+	"epic/phases/phase/notes/note":    {required: []string{"created_at"}, optional: []string{"author"}},
+	"epic/milestones/milestone": {
+		required: []string{"id", "target_date"},
+		optional: []string{"name"},
+		children: []string{"description"},
+	},
+	"epic/milestones/milestone/description": {content: true},
+	"epic/suppressions/suppress":            {required: []string{"rule"}, optional: []string{"entity"}},
+	"epic/blockers/blocker": {
+		required: []string{"id", "raised_at"},
+		optional: []string{"type", "entity", "raised_by", "resolved_at"},
+		children: []string{"description", "resolution"},
+	},
+	"epic/blockers/blocker/description": {content: true},
+	"epic/blockers/blocker/resolution":  {content: true},
+	"epic/tasks/task": {
+		required: []string{"id", "phase_id"},
+		optional: []string{"name", "status", "assignee", "spec_ref", "due", "depends_on", "parent_task_id", "recurring", "estimate"},
+		children: []string{"description", "acceptance_criteria", "started_at", "completed_at", "cancelled_at", "custom", "notes"},
+	},
+	"epic/tasks/task/description":         {content: true},
+	"epic/tasks/task/acceptance_criteria": {content: true},
+	"epic/tasks/task/started_at":          {},
+	"epic/tasks/task/completed_at":        {},
+	"epic/tasks/task/cancelled_at":        {},
+	"epic/tasks/task/custom":              {children: []string{"field"}},
+	"epic/tasks/task/custom/field":        {required: []string{"name"}},
+	"epic/tasks/task/notes":               {children: []string{"note"}},
+	"epic/tasks/task/notes/note":          {required: []string{"created_at"}, optional: []string{"author"}},
+	"epic/tests/test": {
+		required: []string{"id", "task_id"},
+		optional: []string{"phase_id", "name", "status", "test_status", "requires", "tags", "type", "verified_by", "evidence", "fail_count", "pass_count"},
+		children: []string{"description", "started_at", "passed_at", "failed_at", "cancelled_at", "failure_note", "failure_context", "cancellation_reason", "custom", "notes"},
+		// <test>Given ... When ... Then ...</test> is the short form of a description
+		contentUnless: "description",
+	},
+	"epic/tests/test/description":              {content: true},
+	"epic/tests/test/started_at":               {},
+	"epic/tests/test/passed_at":                {},
+	"epic/tests/test/failed_at":                {},
+	"epic/tests/test/cancelled_at":             {},
+	"epic/tests/test/failure_note":             {content: true},
+	"epic/tests/test/failure_context":          {children: []string{"expected", "actual", "stack", "link"}},
+	"epic/tests/test/failure_context/expected": {content: true},
+	"epic/tests/test/failure_context/actual":   {content: true},
+	"epic/tests/test/failure_context/stack":    {content: true},
+	"epic/tests/test/failure_context/link":     {},
+	"epic/tests/test/cancellation_reason":      {content: true},
+	"epic/tests/test/custom":                   {children: []string{"field"}},
+	"epic/tests/test/custom/field":             {required: []string{"name"}},
+	"epic/tests/test/notes":                    {children: []string{"note"}},
+	"epic/tests/test/notes/note":               {required: []string{"created_at"}, optional: []string{"author"}},
+	"epic/events/event": {
+		required: []string{"type", "timestamp"},
+		optional: []string{"id", "actor", "commit", "branch", "correlation_id"},
+		children: []string{"data"},
+	},
+	"epic/events/event/data": {},
+}
+
+// structureElement is an element read by ValidateStructure
+type structureElement struct {
+	path     string
+	line     int
+	start    xml.StartElement
+	children []*structureElement
+	text     string
+}
+
+// ValidateStructure checks the XML of an epic file against the epic file
+// format and reports each violation with its line. Malformed XML is reported
+// as a single violation at the line the parser stopped.
+//
+// Unknown elements and attributes are warnings: agentpm ignores them, which
+// also means they are lost the next time it saves the file.
+func ValidateStructure(content []byte) []StructureViolation {
+	root, err := parseStructure(content)
+	if err != nil {
+		line := 0
+		var syntaxErr *xml.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			line = syntaxErr.Line
+			err = errors.New(syntaxErr.Msg)
+		}
+		return []StructureViolation{{Line: line, Severity: "error", Message: fmt.Sprintf("malformed XML: %v", err)}}
+	}
+
+	v := &structureValidator{}
+	if root.start.Name.Local != "epic" {
+		v.report(root.line, "error", "root element is %s, expected epic", root.start.Name.Local)
+		return v.violations
+	}
+	v.checkElement(root)
+	v.checkReferences(root)
+
+	sort.SliceStable(v.violations, func(i, j int) bool { return v.violations[i].Line < v.violations[j].Line })
+	return v.violations
+}
+
+// parseStructure reads the element tree of content, with the line each element starts on
+func parseStructure(content []byte) (*structureElement, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	var root *structureElement
+	var stack []*structureElement
+	for {
+		// Whitespace between elements is a token of its own, so the position
+		// before reading a start element is the line of its "<"
+		line, _ := decoder.InputPos()
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			elem := &structureElement{line: line, start: t.Copy()}
+			if len(stack) == 0 {
+				elem.path = t.Name.Local
+				root = elem
+			} else {
+				parent := stack[len(stack)-1]
+				elem.path = parent.path + "/" + t.Name.Local
+				parent.children = append(parent.children, elem)
+			}
+			stack = append(stack, elem)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.New("no root element")
+	}
+	return root, nil
+}
+
+type structureValidator struct {
+	violations []StructureViolation
+}
+
+func (v *structureValidator) report(line int, severity, format string, args ...interface{}) {
+	v.violations = append(v.violations, StructureViolation{Line: line, Severity: severity, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *structureValidator) checkElement(elem *structureElement) {
+	spec := epicFormat[elem.path]
+	name := elem.start.Name.Local
+
+	for _, attr := range spec.required {
+		if strings.TrimSpace(elem.attr(attr)) == "" {
+			v.report(elem.line, "error", "%s is missing required attribute %s", name, attr)
+		}
+	}
+	for _, attr := range elem.start.Attr {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
+		if !hasString(spec.required, attr.Name.Local) && !hasString(spec.optional, attr.Name.Local) {
+			v.report(elem.line, "warning", "unknown attribute %s on %s (ignored, and dropped on the next save)", attr.Name.Local, name)
+		}
+	}
+
+	if spec.content || (spec.contentUnless != "" && elem.child(spec.contentUnless) == nil) {
+		return
+	}
+	for _, child := range elem.children {
+		if !hasString(spec.children, child.start.Name.Local) {
+			v.report(child.line, "warning", "unknown element %s in %s (ignored, and dropped on the next save)", child.start.Name.Local, name)
+			continue
+		}
+		v.checkElement(child)
+	}
+}
+
+// checkReferences reports duplicate IDs and references to phases, tasks and tests that are not defined
+func (v *structureValidator) checkReferences(root *structureElement) {
+	phases := v.collectIDs(root.child("phases"), "phase")
+	tasks := v.collectIDs(root.child("tasks"), "task")
+	tests := v.collectIDs(root.child("tests"), "test")
+	v.collectIDs(root.child("milestones"), "milestone")
+	v.collectIDs(root.child("blockers"), "blocker")
+
+	checkRef := func(elem *structureElement, attr, kind string, known ...map[string]int) {
+		for _, id := range splitIDs(elem.attr(attr)) {
+			found := false
+			for _, ids := range known {
+				if _, ok := ids[id]; ok {
+					found = true
+				}
+			}
+			if !found {
+				v.report(elem.line, "error", "%s references undefined %s %s (%s)", elem.describe(), kind, id, attr)
+			}
+		}
+	}
+	for _, phase := range root.child("phases").all("phase") {
+		checkRef(phase, "depends_on", "phase", phases)
+	}
+	for _, task := range root.child("tasks").all("task") {
+		checkRef(task, "phase_id", "phase", phases)
+		checkRef(task, "depends_on", "task", tasks)
+		checkRef(task, "parent_task_id", "task", tasks)
+	}
+	for _, test := range root.child("tests").all("test") {
+		checkRef(test, "task_id", "task", tasks)
+		checkRef(test, "phase_id", "phase", phases)
+		checkRef(test, "requires", "test or task", tests, tasks)
+	}
+	for _, blocker := range root.child("blockers").all("blocker") {
+		checkRef(blocker, "entity", "phase or task", phases, tasks)
+	}
+
+	if state := root.child("current_state"); state != nil {
+		if active := state.child("active_phase"); active != nil {
+			if id := strings.TrimSpace(active.text); id != "" {
+				if _, ok := phases[id]; !ok {
+					v.report(active.line, "error", "active_phase references undefined phase %s", id)
+				}
+			}
+		}
+		if active := state.child("active_task"); active != nil {
+			if id := strings.TrimSpace(active.text); id != "" {
+				if _, ok := tasks[id]; !ok {
+					v.report(active.line, "error", "active_task references undefined task %s", id)
+				}
+			}
+		}
+	}
+}
+
+// collectIDs returns the IDs of the named children of parent with the line
+// they are defined on, reporting duplicates
+func (v *structureValidator) collectIDs(parent *structureElement, name string) map[string]int {
+	ids := make(map[string]int)
+	for _, elem := range parent.all(name) {
+		id := elem.attr("id")
+		if id == "" {
+			continue // Reported as a missing attribute
+		}
+		if first, ok := ids[id]; ok {
+			v.report(elem.line, "error", "duplicate %s ID %s (first defined on line %d)", name, id, first)
+			continue
+		}
+		ids[id] = elem.line
+	}
+	return ids
+}
+
+// describe names the element for messages, e.g. "task 1A_1"
+func (e *structureElement) describe() string {
+	if id := e.attr("id"); id != "" {
+		return e.start.Name.Local + " " + id
+	}
+	return e.start.Name.Local
+}
+
+func (e *structureElement) attr(name string) string {
+	for _, attr := range e.start.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+func (e *structureElement) child(name string) *structureElement {
+	if e == nil {
+		return nil
+	}
+	for _, child := range e.children {
+		if child.start.Name.Local == name {
+			return child
+		}
+	}
+	return nil
+}
+
+func (e *structureElement) all(name string) []*structureElement {
+	if e == nil {
+		return nil
+	}
+	var matches []*structureElement
+	for _, child := range e.children {
+		if child.start.Name.Local == name {
+			matches = append(matches, child)
+		}
+	}
+	return matches
+}
+
+func hasString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
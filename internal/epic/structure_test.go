@@ -0,0 +1,105 @@
+package epic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateStructure(t *testing.T) {
+	t.Run("valid epic has no violations", func(t *testing.T) {
+		content := `<?xml version="1.0" encoding="UTF-8"?>
+<epic id="8" name="Checkout" status="wip" created_at="2025-08-16T09:00:00Z">
+    <description>Free <b>markup</b> is allowed here</description>
+    <current_state>
+        <active_phase>1</active_phase>
+        <active_task>1_1</active_task>
+    </current_state>
+    <phases>
+        <phase id="1" name="Cart" status="wip"/>
+        <phase id="2" name="Payment" status="pending" depends_on="1"/>
+    </phases>
+    <tasks>
+        <task id="1_1" phase_id="1" name="Add to cart" status="wip">
+            <custom><field name="sprint">7</field></custom>
+            <notes><note author="agent_a" created_at="2025-08-16T09:30:00Z">Uses the v2 API</note></notes>
+        </task>
+        <task id="2_1" phase_id="2" name="Pay" status="pending" depends_on="1_1"/>
+    </tasks>
+    <tests>
+        <test id="T1" task_id="1_1" phase_id="1" name="Adds">
+            <given>an empty cart</given>
+            <then>it holds one item</then>
+        </test>
+        <test id="T2" task_id="2_1" name="Pays" requires="T1,1_1" fail_count="2" pass_count="1">
+            <description>Card payment</description>
+        </test>
+    </tests>
+    <events>
+        <event id="e1" type="task_started" timestamp="2025-08-16T09:00:00Z"><data>Started</data></event>
+    </events>
+</epic>`
+		assert.Empty(t, ValidateStructure([]byte(content)))
+	})
+
+	t.Run("violations are reported with their line", func(t *testing.T) {
+		content := `<?xml version="1.0" encoding="UTF-8"?>
+<epic id="8" name="Checkout" created_at="2025-08-16T09:00:00Z" priority="high">
+    <outline/>
+    <current_state>
+        <active_task>9_9</active_task>
+    </current_state>
+    <phases>
+        <phase id="1" name="Cart"/>
+        <phase id="1" name="Cart again"/>
+    </phases>
+    <tasks>
+        <task id="1_1" phase_id="2" name="Add to cart"/>
+        <task phase_id="1" name="No ID"/>
+    </tasks>
+    <tests>
+        <test id="T1" task_id="1_1" requires="T9">
+            <description>Adds</description>
+            <remarks>Unknown next to a description</remarks>
+        </test>
+    </tests>
+</epic>`
+		assert.Equal(t, []StructureViolation{
+			{Line: 2, Severity: "error", Message: "epic is missing required attribute status"},
+			{Line: 2, Severity: "warning", Message: "unknown attribute priority on epic (ignored, and dropped on the next save)"},
+			{Line: 3, Severity: "warning", Message: "unknown element outline in epic (ignored, and dropped on the next save)"},
+			{Line: 5, Severity: "error", Message: "active_task references undefined task 9_9"},
+			{Line: 9, Severity: "error", Message: "duplicate phase ID 1 (first defined on line 8)"},
+			{Line: 12, Severity: "error", Message: "task 1_1 references undefined phase 2 (phase_id)"},
+			{Line: 13, Severity: "error", Message: "task is missing required attribute id"},
+			{Line: 16, Severity: "error", Message: "test T1 references undefined test or task T9 (requires)"},
+			{Line: 18, Severity: "warning", Message: "unknown element remarks in test (ignored, and dropped on the next save)"},
+		}, ValidateStructure([]byte(content)))
+	})
+
+	t.Run("malformed XML is a single violation", func(t *testing.T) {
+		violations := ValidateStructure([]byte("<epic id=\"8\">\n  <phases>\n</epic>"))
+		assert.Equal(t, []StructureViolation{
+			{Line: 3, Severity: "error", Message: "malformed XML: element <phases> closed by </epic>"},
+		}, violations)
+		assert.Equal(t, "line 3: malformed XML: element <phases> closed by </epic>", violations[0].String())
+	})
+
+	t.Run("other root elements are rejected", func(t *testing.T) {
+		assert.Equal(t, []StructureViolation{
+			{Line: 1, Severity: "error", Message: "root element is project, expected epic"},
+		}, ValidateStructure([]byte("<project/>")))
+	})
+}
+
+func TestValidationResult_AddStructureViolations(t *testing.T) {
+	result := &ValidationResult{Valid: true}
+	result.AddStructureViolations([]StructureViolation{{Line: 3, Severity: "warning", Message: "unknown element outline in epic"}})
+	assert.True(t, result.Valid)
+	assert.Equal(t, []string{"line 3: unknown element outline in epic"}, result.Warnings)
+	assert.Equal(t, "warning", result.Checks["xml_schema"])
+
+	result.AddStructureViolations([]StructureViolation{{Line: 2, Severity: "error", Message: "epic is missing required attribute id"}})
+	assert.False(t, result.Valid)
+	assert.Equal(t, "failed", result.Checks["xml_schema"])
+}
@@ -0,0 +1,80 @@
+package epic
+
+// Tasks can be split into sub-tasks, one level deep: a sub-task names its
+// parent in ParentTaskID and belongs to the same phase. A sub-task is started
+// while its parent is active, and the parent can only be completed or
+// cancelled once all its sub-tasks are.
+
+// IsSubTask reports whether the task is a sub-task of another task
+func (t *Task) IsSubTask() bool {
+	return t.ParentTaskID != ""
+}
+
+// ParentTask returns the parent of a sub-task, or nil for top-level tasks and
+// unknown parents
+func (e *Epic) ParentTask(task *Task) *Task {
+	if !task.IsSubTask() {
+		return nil
+	}
+	for i := range e.Tasks {
+		if e.Tasks[i].ID == task.ParentTaskID {
+			return &e.Tasks[i]
+		}
+	}
+	return nil
+}
+
+// SubTasks returns the sub-tasks of a task in the order of the epic
+func (e *Epic) SubTasks(taskID string) []*Task {
+	var subTasks []*Task
+	for i := range e.Tasks {
+		if e.Tasks[i].ParentTaskID == taskID {
+			subTasks = append(subTasks, &e.Tasks[i])
+		}
+	}
+	return subTasks
+}
+
+// OpenSubTasks returns the IDs of the sub-tasks of a task that are neither
+// completed nor cancelled
+func (e *Epic) OpenSubTasks(taskID string) []string {
+	var open []string
+	for _, subTask := range e.SubTasks(taskID) {
+		if subTask.Status != StatusCompleted && subTask.Status != StatusCancelled {
+			open = append(open, subTask.ID)
+		}
+	}
+	return open
+}
+
+// HasActiveSubTask reports whether one of the sub-tasks of a task is active
+func (e *Epic) HasActiveSubTask(taskID string) bool {
+	for _, subTask := range e.SubTasks(taskID) {
+		if subTask.Status == StatusWIP {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveTask returns the task being worked on in a phase, or in the whole
+// epic when phaseID is empty: the first active task, or its active sub-task
+// when it has one. It returns nil when no task is active.
+func (e *Epic) ActiveTask(phaseID string) *Task {
+	for i := range e.Tasks {
+		task := &e.Tasks[i]
+		if task.Status != StatusWIP || (phaseID != "" && task.PhaseID != phaseID) {
+			continue
+		}
+		if parent := e.ParentTask(task); parent != nil && parent.Status == StatusWIP {
+			return task
+		}
+		for _, subTask := range e.SubTasks(task.ID) {
+			if subTask.Status == StatusWIP {
+				return subTask
+			}
+		}
+		return task
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package epic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createSubTaskEpic() *Epic {
+	return &Epic{
+		ID:     "subtasks",
+		Name:   "Sub-task Epic",
+		Status: StatusWIP,
+		Phases: []Phase{
+			{ID: "1", Name: "Backend", Status: StatusWIP},
+		},
+		Tasks: []Task{
+			{ID: "1_1", PhaseID: "1", Name: "API", Status: StatusWIP},
+			{ID: "1_1a", PhaseID: "1", Name: "Routes", Status: StatusCompleted, ParentTaskID: "1_1"},
+			{ID: "1_1b", PhaseID: "1", Name: "Handlers", Status: StatusPending, ParentTaskID: "1_1"},
+			{ID: "1_1c", PhaseID: "1", Name: "Docs", Status: StatusCancelled, ParentTaskID: "1_1"},
+			{ID: "1_2", PhaseID: "1", Name: "Storage", Status: StatusPending},
+		},
+	}
+}
+
+func TestSubTasks(t *testing.T) {
+	e := createSubTaskEpic()
+
+	assert.False(t, e.Tasks[0].IsSubTask())
+	assert.True(t, e.Tasks[1].IsSubTask())
+	assert.Nil(t, e.ParentTask(&e.Tasks[0]))
+	assert.Equal(t, &e.Tasks[0], e.ParentTask(&e.Tasks[2]))
+
+	subTasks := e.SubTasks("1_1")
+	require.Len(t, subTasks, 3)
+	assert.Equal(t, "1_1a", subTasks[0].ID)
+	assert.Empty(t, e.SubTasks("1_2"))
+
+	assert.Equal(t, []string{"1_1b"}, e.OpenSubTasks("1_1"))
+	assert.Empty(t, e.OpenSubTasks("1_2"))
+}
+
+func TestActiveTask_PrefersActiveSubTask(t *testing.T) {
+	e := createSubTaskEpic()
+
+	assert.Equal(t, "1_1", e.ActiveTask("").ID, "parent without an active sub-task")
+	assert.False(t, e.HasActiveSubTask("1_1"))
+
+	e.Tasks[2].Status = StatusWIP
+	assert.True(t, e.HasActiveSubTask("1_1"))
+	assert.Equal(t, "1_1b", e.ActiveTask("").ID)
+	assert.Equal(t, "1_1b", e.ActiveTask("1").ID)
+	assert.Nil(t, e.ActiveTask("2"))
+}
+
+func TestEpic_ValidateSubTasks(t *testing.T) {
+	e := createSubTaskEpic()
+	e.Phases = append(e.Phases, Phase{ID: "2", Name: "Frontend", Status: StatusPending})
+	e.Tasks = append(e.Tasks,
+		Task{ID: "1_3", PhaseID: "1", Name: "Self", Status: StatusPending, ParentTaskID: "1_3"},
+		Task{ID: "1_4", PhaseID: "1", Name: "Orphan", Status: StatusPending, ParentTaskID: "1_9"},
+		Task{ID: "1_5", PhaseID: "1", Name: "Nested", Status: StatusPending, ParentTaskID: "1_1a"},
+		Task{ID: "2_1", PhaseID: "2", Name: "Elsewhere", Status: StatusPending, ParentTaskID: "1_2"},
+	)
+
+	result := e.Validate()
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Errors, "Task 1_3 is its own parent task")
+	assert.Contains(t, result.Errors, "Task 1_4 has non-existent parent task: 1_9")
+	assert.Contains(t, result.Errors, "Task 1_5 has parent task 1_1a, which is a sub-task itself (only one level of sub-tasks is allowed)")
+	assert.Contains(t, result.Errors, "Task 2_1 is in phase 2 but its parent task 1_2 is in phase 1")
+	assert.Equal(t, "failed", result.Checks["sub_tasks"])
+
+	e.Tasks = e.Tasks[:5]
+	result = e.Validate()
+	assert.Equal(t, "passed", result.Checks["sub_tasks"])
+}
@@ -2,6 +2,7 @@ package epic
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -10,8 +11,21 @@ type ValidationResult struct {
 	Warnings []string          `json:"warnings,omitempty"`
 	Errors   []string          `json:"errors,omitempty"`
 	Checks   map[string]string `json:"checks_performed"`
+
+	// WarningCounts counts the warnings per category, see AddCategorizedWarning
+	WarningCounts map[string]int `json:"-"`
 }
 
+// Categories of validation warnings, limited by warning budgets in CI
+const (
+	WarningStructure    = "structure"     // Unknown elements and attributes of the epic file
+	WarningMissingTests = "missing_tests" // Tasks without tests
+	WarningPolicy       = "policy"        // Violated warning rules of the organization policy
+)
+
+// WarningCategories lists the categories of validation warnings
+var WarningCategories = []string{WarningStructure, WarningMissingTests, WarningPolicy}
+
 func (vr *ValidationResult) AddError(msg string) {
 	vr.Errors = append(vr.Errors, msg)
 	vr.Valid = false
@@ -21,6 +35,30 @@ func (vr *ValidationResult) AddWarning(msg string) {
 	vr.Warnings = append(vr.Warnings, msg)
 }
 
+// AddCategorizedWarning adds a warning and counts it for its category
+func (vr *ValidationResult) AddCategorizedWarning(category, msg string) {
+	vr.AddWarning(msg)
+	if vr.WarningCounts == nil {
+		vr.WarningCounts = make(map[string]int)
+	}
+	vr.WarningCounts[category]++
+}
+
+// ApplyWarningBudgets fails the validation when a category has more warnings
+// than its budget allows. Categories without a budget allow no warnings.
+// The outcome is recorded as the warning_budget check.
+func (vr *ValidationResult) ApplyWarningBudgets(budgets map[string]int) {
+	status := "passed"
+	for _, category := range WarningCategories {
+		count, budget := vr.WarningCounts[category], budgets[category]
+		if count > budget {
+			vr.AddError(fmt.Sprintf("Warning budget exceeded: %d %s warning(s), budget %d", count, category, budget))
+			status = "failed"
+		}
+	}
+	vr.SetCheck("warning_budget", status)
+}
+
 func (vr *ValidationResult) SetCheck(name, status string) {
 	if vr.Checks == nil {
 		vr.Checks = make(map[string]string)
@@ -28,6 +66,35 @@ func (vr *ValidationResult) SetCheck(name, status string) {
 	vr.Checks[name] = status
 }
 
+// CheckNames returns the names of the performed checks in alphabetical order,
+// the order in which outputs list them
+func (vr *ValidationResult) CheckNames() []string {
+	names := make([]string, 0, len(vr.Checks))
+	for name := range vr.Checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AddStructureViolations adds the violations of ValidateStructure, with their
+// line numbers, and records the outcome as the xml_schema check
+func (vr *ValidationResult) AddStructureViolations(violations []StructureViolation) {
+	status := "passed"
+	for _, violation := range violations {
+		if violation.Severity == "error" {
+			vr.AddError(violation.String())
+			status = "failed"
+		} else {
+			vr.AddCategorizedWarning(WarningStructure, violation.String())
+			if status == "passed" {
+				status = "warning"
+			}
+		}
+	}
+	vr.SetCheck("xml_schema", status)
+}
+
 func (vr *ValidationResult) Message() string {
 	if len(vr.Errors) > 0 {
 		return fmt.Sprintf("Epic validation failed with %d error(s)", len(vr.Errors))
@@ -47,8 +114,13 @@ func (e *Epic) Validate() *ValidationResult {
 	e.validateBasicStructure(result)
 	e.validateStatusValues(result)
 	e.validatePhaseDependencies(result)
+	e.validateTaskDependencies(result)
+	e.validateSubTasks(result)
+	e.validateRecurring(result)
+	e.validateEstimates(result)
 	e.validateTaskPhaseMapping(result)
 	e.validateTestCoverage(result)
+	e.validateDates(result)
 
 	return result
 }
@@ -150,22 +222,95 @@ func (e *Epic) validateStatusValues(result *ValidationResult) {
 }
 
 func (e *Epic) validatePhaseDependencies(result *ValidationResult) {
-	// Build phase map for quick lookup
-	phaseMap := make(map[string]*Phase)
+	errorCount := len(result.Errors)
+
+	phaseIDs := make(map[string]bool)
+	for _, phase := range e.Phases {
+		phaseIDs[phase.ID] = true
+	}
+	graph := make(map[string][]string)
+	var order []string
 	for i := range e.Phases {
-		phaseMap[e.Phases[i].ID] = &e.Phases[i]
+		phase := &e.Phases[i]
+		for _, id := range phase.DependencyIDs() {
+			if !phaseIDs[id] {
+				result.AddError(fmt.Sprintf("Phase %s depends on non-existent phase: %s", phase.ID, id))
+			}
+		}
+		graph[phase.ID] = phase.DependencyIDs()
+		order = append(order, phase.ID)
+	}
+	if cycle := dependencyCycle(graph, order); cycle != nil {
+		result.AddError(fmt.Sprintf("Phase dependencies form a cycle: %s", strings.Join(cycle, " -> ")))
 	}
 
-	// For now, just ensure phase IDs are valid
-	// Future: implement dependency chain validation
-
-	if len(phaseMap) == len(e.Phases) {
+	if len(result.Errors) == errorCount {
 		result.SetCheck("phase_dependencies", "passed")
 	} else {
 		result.SetCheck("phase_dependencies", "failed")
 	}
 }
 
+func (e *Epic) validateTaskDependencies(result *ValidationResult) {
+	errorCount := len(result.Errors)
+
+	taskIDs := make(map[string]bool)
+	for _, task := range e.Tasks {
+		taskIDs[task.ID] = true
+	}
+	graph := make(map[string][]string)
+	var order []string
+	for i := range e.Tasks {
+		task := &e.Tasks[i]
+		for _, id := range task.DependencyIDs() {
+			if !taskIDs[id] {
+				result.AddError(fmt.Sprintf("Task %s depends on non-existent task: %s", task.ID, id))
+			}
+		}
+		graph[task.ID] = task.DependencyIDs()
+		order = append(order, task.ID)
+	}
+	if cycle := dependencyCycle(graph, order); cycle != nil {
+		result.AddError(fmt.Sprintf("Task dependencies form a cycle: %s", strings.Join(cycle, " -> ")))
+	}
+
+	if len(result.Errors) == errorCount {
+		result.SetCheck("task_dependencies", "passed")
+	} else {
+		result.SetCheck("task_dependencies", "failed")
+	}
+}
+
+// validateSubTasks checks that sub-tasks name an existing top-level task of
+// their own phase as parent
+func (e *Epic) validateSubTasks(result *ValidationResult) {
+	errorCount := len(result.Errors)
+
+	for i := range e.Tasks {
+		task := &e.Tasks[i]
+		if !task.IsSubTask() {
+			continue
+		}
+		parent := e.ParentTask(task)
+		switch {
+		case task.ParentTaskID == task.ID:
+			result.AddError(fmt.Sprintf("Task %s is its own parent task", task.ID))
+		case parent == nil:
+			result.AddError(fmt.Sprintf("Task %s has non-existent parent task: %s", task.ID, task.ParentTaskID))
+		case parent.IsSubTask():
+			result.AddError(fmt.Sprintf("Task %s has parent task %s, which is a sub-task itself (only one level of sub-tasks is allowed)", task.ID, parent.ID))
+		case parent.PhaseID != task.PhaseID:
+			result.AddError(fmt.Sprintf("Task %s is in phase %s but its parent task %s is in phase %s", task.ID, task.PhaseID, parent.ID, parent.PhaseID))
+		}
+	}
+
+	if len(result.Errors) == errorCount {
+		result.SetCheck("sub_tasks", "passed")
+	} else {
+		result.SetCheck("sub_tasks", "failed")
+	}
+}
+
 func (e *Epic) validateTaskPhaseMapping(result *ValidationResult) {
 	// Build phase map for quick lookup
 	phaseMap := make(map[string]bool)
@@ -187,6 +332,35 @@ func (e *Epic) validateTaskPhaseMapping(result *ValidationResult) {
 	}
 }
 
+func (e *Epic) validateDates(result *ValidationResult) {
+	errorCount := len(result.Errors)
+
+	if _, _, err := ParseDate(e.Due); e.Due != "" && err != nil {
+		result.AddError(fmt.Sprintf("Epic %s has an %v", e.ID, err))
+	}
+	for _, phase := range e.Phases {
+		if _, _, err := ParseDate(phase.Due); phase.Due != "" && err != nil {
+			result.AddError(fmt.Sprintf("Phase %s has an %v", phase.ID, err))
+		}
+	}
+	for _, task := range e.Tasks {
+		if _, _, err := ParseDate(task.Due); task.Due != "" && err != nil {
+			result.AddError(fmt.Sprintf("Task %s has an %v", task.ID, err))
+		}
+	}
+	for _, milestone := range e.Milestones {
+		if _, _, err := ParseDate(milestone.TargetDate); err != nil {
+			result.AddError(fmt.Sprintf("Milestone %s has an %v", milestone.ID, err))
+		}
+	}
+
+	if len(result.Errors) == errorCount {
+		result.SetCheck("dates", "passed")
+	} else {
+		result.SetCheck("dates", "failed")
+	}
+}
+
 func (e *Epic) validateTestCoverage(result *ValidationResult) {
 	// Build task map for quick lookup
 	taskMap := make(map[string]bool)
@@ -213,7 +387,7 @@ func (e *Epic) validateTestCoverage(result *ValidationResult) {
 
 	for _, task := range e.Tasks {
 		if !tasksWithTests[task.ID] {
-			result.AddWarning(fmt.Sprintf("Task %s has no tests defined", task.ID))
+			result.AddCategorizedWarning(WarningMissingTests, fmt.Sprintf("Task %s has no tests defined", task.ID))
 		}
 	}
 
@@ -274,7 +448,8 @@ func formatValidationResultXML(result *ValidationResult) string {
 
 	if len(result.Checks) > 0 {
 		output.WriteString("    <checks_performed>\n")
-		for name, status := range result.Checks {
+		for _, name := range result.CheckNames() {
+			status := result.Checks[name]
 			output.WriteString(fmt.Sprintf("        <check name=\"%s\">%s</check>\n", name, status))
 		}
 		output.WriteString("    </checks_performed>\n")
@@ -357,7 +532,8 @@ func formatValidationResultText(result *ValidationResult) string {
 	}
 
 	output.WriteString("Checks performed:\n")
-	for name, status := range result.Checks {
+	for _, name := range result.CheckNames() {
+		status := result.Checks[name]
 		var icon string
 		switch status {
 		case "passed":
@@ -286,6 +286,35 @@ func TestValidationResult_AddWarning(t *testing.T) {
 	assert.Contains(t, result.Warnings, "test warning")
 }
 
+func TestValidationResult_ApplyWarningBudgets(t *testing.T) {
+	newResult := func() *ValidationResult {
+		result := &ValidationResult{Valid: true}
+		for i := 0; i < 3; i++ {
+			result.AddCategorizedWarning(WarningMissingTests, fmt.Sprintf("Task %d has no tests defined", i))
+		}
+		result.AddCategorizedWarning(WarningStructure, "line 3: unknown element outline in epic")
+		return result
+	}
+
+	t.Run("within budget", func(t *testing.T) {
+		result := newResult()
+		result.ApplyWarningBudgets(map[string]int{WarningMissingTests: 3, WarningStructure: 1})
+		assert.True(t, result.Valid)
+		assert.Equal(t, "passed", result.Checks["warning_budget"])
+	})
+
+	t.Run("over budget and without budget", func(t *testing.T) {
+		result := newResult()
+		result.ApplyWarningBudgets(map[string]int{WarningMissingTests: 2})
+		assert.False(t, result.Valid)
+		assert.Equal(t, []string{
+			"Warning budget exceeded: 1 structure warning(s), budget 0",
+			"Warning budget exceeded: 3 missing_tests warning(s), budget 2",
+		}, result.Errors)
+		assert.Equal(t, "failed", result.Checks["warning_budget"])
+	})
+}
+
 func TestValidationResult_SetCheck(t *testing.T) {
 	result := &ValidationResult{}
 
@@ -294,6 +323,17 @@ func TestValidationResult_SetCheck(t *testing.T) {
 	assert.Equal(t, "passed", result.Checks["test_check"])
 }
 
+func TestValidationResult_CheckNames(t *testing.T) {
+	result := &ValidationResult{}
+	assert.Empty(t, result.CheckNames())
+
+	result.SetCheck("xml_schema", "passed")
+	result.SetCheck("dependencies", "warning")
+	result.SetCheck("ids", "passed")
+
+	assert.Equal(t, []string{"dependencies", "ids", "xml_schema"}, result.CheckNames())
+}
+
 func TestStatus_IsValid(t *testing.T) {
 	validStatuses := []Status{
 		StatusPending,
@@ -420,3 +460,113 @@ func TestNewEpic(t *testing.T) {
 	assert.Equal(t, StatusPending, epic.Status)
 	assert.False(t, epic.CreatedAt.IsZero())
 }
+
+func TestParseDate(t *testing.T) {
+	day, allDay, err := ParseDate("2025-09-15")
+	require.NoError(t, err)
+	assert.True(t, allDay)
+	assert.Equal(t, time.Date(2025, 9, 15, 0, 0, 0, 0, time.UTC), day)
+
+	moment, allDay, err := ParseDate("2025-09-15T17:00:00+02:00")
+	require.NoError(t, err)
+	assert.False(t, allDay)
+	assert.Equal(t, time.Date(2025, 9, 15, 15, 0, 0, 0, time.UTC), moment.UTC())
+
+	_, _, err = ParseDate("15.09.2025")
+	assert.EqualError(t, err, `invalid date "15.09.2025" (use 2006-01-02 or RFC3339)`)
+}
+
+func TestEpic_ValidateDates(t *testing.T) {
+	epic := &Epic{
+		ID:     "test-1",
+		Name:   "Test Epic",
+		Status: StatusPending,
+		Phases: []Phase{{ID: "P1", Name: "Phase 1", Status: StatusPending, Due: "2025-09-15"}},
+		Tasks: []Task{
+			{ID: "T1", PhaseID: "P1", Name: "Task 1", Status: StatusPending, Due: "next week"},
+		},
+		Milestones: []Milestone{{ID: "M1", Name: "Beta", TargetDate: "2025-10-01"}},
+	}
+
+	result := epic.Validate()
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Errors, `Task T1 has an invalid date "next week" (use 2006-01-02 or RFC3339)`)
+	assert.Equal(t, "failed", result.Checks["dates"])
+
+	epic.Tasks[0].Due = "2025-09-10T12:00:00Z"
+	result = epic.Validate()
+	assert.Equal(t, "passed", result.Checks["dates"])
+}
+
+func TestEpic_ValidateDependencies(t *testing.T) {
+	epic := &Epic{
+		ID:     "test-1",
+		Name:   "Test Epic",
+		Status: StatusPending,
+		Phases: []Phase{
+			{ID: "P1", Name: "Phase 1", Status: StatusPending, DependsOn: "P2"},
+			{ID: "P2", Name: "Phase 2", Status: StatusPending, DependsOn: "P1"},
+		},
+		Tasks: []Task{
+			{ID: "T1", PhaseID: "P1", Name: "Task 1", Status: StatusPending, DependsOn: "T2, T9"},
+			{ID: "T2", PhaseID: "P2", Name: "Task 2", Status: StatusPending},
+		},
+	}
+
+	result := epic.Validate()
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Errors, "Phase dependencies form a cycle: P1 -> P2 -> P1")
+	assert.Contains(t, result.Errors, "Task T1 depends on non-existent task: T9")
+	assert.Equal(t, "failed", result.Checks["phase_dependencies"])
+	assert.Equal(t, "failed", result.Checks["task_dependencies"])
+
+	epic.Phases[0].DependsOn = ""
+	epic.Tasks[0].DependsOn = "T2"
+	result = epic.Validate()
+	assert.Equal(t, "passed", result.Checks["phase_dependencies"])
+	assert.Equal(t, "passed", result.Checks["task_dependencies"])
+}
+
+func TestEpic_UnmetDependencies(t *testing.T) {
+	epic := &Epic{
+		Phases: []Phase{
+			{ID: "P1", Status: StatusCompleted},
+			{ID: "P2", Status: StatusWIP},
+			{ID: "P3", Status: StatusPending, DependsOn: "P1,P2, P9"},
+		},
+		Tasks: []Task{
+			{ID: "T1", Status: StatusCancelled},
+			{ID: "T2", Status: StatusPending, DependsOn: "T1"},
+		},
+	}
+
+	assert.Equal(t, []Dependency{{ID: "P2", Status: StatusWIP}, {ID: "P9"}}, epic.UnmetPhaseDependencies(&epic.Phases[2]))
+	assert.Equal(t, []Dependency{{ID: "T1", Status: StatusCancelled}}, epic.UnmetTaskDependencies(&epic.Tasks[1]),
+		"a cancelled task does not satisfy a dependency")
+	assert.Equal(t, []string{"P3"}, epic.PhaseDependents("P2"))
+	assert.Equal(t, []string{"P1", "P2", "P9"}, epic.UpstreamPhaseIDs(&epic.Phases[2]))
+	assert.Equal(t, []string{"P1"}, epic.UpstreamPhaseIDs(&epic.Phases[1]), "without depends_on, all earlier phases are upstream")
+}
+
+func TestEpic_StrandedTasks(t *testing.T) {
+	epic := &Epic{
+		Tasks: []Task{
+			{ID: "T1", PhaseID: "P1", Status: StatusWIP},
+			{ID: "T2", PhaseID: "P1", Status: StatusPending, DependsOn: "T1"},
+			{ID: "T3", PhaseID: "P2", Status: StatusPending, DependsOn: "T2"},
+			{ID: "T4", PhaseID: "P2", Status: StatusWIP, DependsOn: "T1"},
+			{ID: "T5", PhaseID: "P2", Status: StatusPending, DependsOn: "T3,T4"},
+			{ID: "T6", PhaseID: "P2", Status: StatusPending},
+		},
+	}
+
+	assert.Equal(t, []StrandedTask{
+		{ID: "T2", PhaseID: "P1", Via: "T1"},
+		{ID: "T3", PhaseID: "P2", Via: "T2"},
+		{ID: "T5", PhaseID: "P2", Via: "T3"},
+	}, epic.StrandedTasks([]string{"T1"}), "started tasks are not stranded")
+
+	assert.Equal(t, []StrandedTask{{ID: "T5", PhaseID: "P2", Via: "T3"}}, epic.StrandedTasks([]string{"T2", "T3"}),
+		"cancelled tasks are not listed as stranded")
+	assert.Empty(t, epic.StrandedTasks([]string{"T6"}))
+}
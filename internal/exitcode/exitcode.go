@@ -0,0 +1,77 @@
+// Package exitcode defines the exit codes agentpm ends with, so that scripts
+// can tell a rejected state transition from a missing file without parsing
+// error messages. The codes are part of the CLI contract and do not change.
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	Success    = 0 // The command succeeded
+	General    = 1 // An error that fits no other class
+	Validation = 2 // Invalid input: arguments, flags or file contents
+	Constraint = 3 // A valid request the epic's state does not allow
+	NotFound   = 4 // A file, epic, phase, task or test that does not exist
+	Storage    = 5 // Reading, writing or locking a file failed
+)
+
+// Name returns the stable name of a code, used as the code of error envelopes
+func Name(code int) string {
+	switch code {
+	case Validation:
+		return "validation"
+	case Constraint:
+		return "constraint"
+	case NotFound:
+		return "not_found"
+	case Storage:
+		return "storage"
+	default:
+		return "error"
+	}
+}
+
+// Error is an error classified with an exit code. Reported marks errors the
+// command already printed in the requested output format.
+type Error struct {
+	Code     int
+	Err      error
+	Hint     string
+	Reported bool
+}
+
+func (e *Error) Error() string {
+	if e.Hint != "" {
+		return fmt.Sprintf("%v\nHint: %s", e.Err, e.Hint)
+	}
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap classifies err with code; a nil err stays nil
+func Wrap(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// Errorf formats an error classified with code
+func Errorf(code int, format string, args ...interface{}) error {
+	return &Error{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// Of returns the code of the first classified error in the chain of err, or
+// General when there is none
+func Of(err error) (int, bool) {
+	var classified *Error
+	if errors.As(err, &classified) {
+		return classified.Code, true
+	}
+	return General, false
+}
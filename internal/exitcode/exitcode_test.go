@@ -0,0 +1,39 @@
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestName(t *testing.T) {
+	assert.Equal(t, "validation", Name(Validation))
+	assert.Equal(t, "constraint", Name(Constraint))
+	assert.Equal(t, "not_found", Name(NotFound))
+	assert.Equal(t, "storage", Name(Storage))
+	assert.Equal(t, "error", Name(General))
+	assert.Equal(t, "error", Name(42))
+}
+
+func TestError(t *testing.T) {
+	cause := errors.New("task 1A_1 is already active")
+	err := &Error{Code: Constraint, Err: cause, Hint: "Complete task 1A_1 first"}
+
+	assert.Equal(t, "task 1A_1 is already active\nHint: Complete task 1A_1 first", err.Error())
+	assert.ErrorIs(t, err, cause)
+
+	assert.Equal(t, "config file not found: x", Errorf(NotFound, "config file not found: %s", "x").Error())
+	assert.Nil(t, Wrap(Storage, nil))
+}
+
+func TestOf(t *testing.T) {
+	code, ok := Of(fmt.Errorf("failed to load configuration: %w", Errorf(NotFound, "config file not found")))
+	assert.True(t, ok)
+	assert.Equal(t, NotFound, code)
+
+	code, ok = Of(errors.New("plain"))
+	assert.False(t, ok)
+	assert.Equal(t, General, code)
+}
@@ -0,0 +1,161 @@
+package forecast
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/service"
+)
+
+// Options control the simulation
+type Options struct {
+	Runs     int   // Number of simulated futures
+	Parallel int   // Tasks worked on at the same time
+	Seed     int64 // Random seed, for reproducible forecasts
+}
+
+// Estimate is the completion time reached by a share of the simulated runs
+type Estimate struct {
+	Percentile int       `json:"percentile"`
+	Minutes    int       `json:"minutes"`
+	Date       time.Time `json:"date"`
+}
+
+// Result is the forecast for the remaining work of an epic
+type Result struct {
+	EpicID      string     `json:"epic_id"`
+	Remaining   int        `json:"remaining_tasks"`
+	InProgress  int        `json:"in_progress_tasks"`
+	Samples     int        `json:"history_samples"`
+	Runs        int        `json:"runs"`
+	Parallel    int        `json:"parallel"`
+	Seed        int64      `json:"seed"`
+	Estimates   []Estimate `json:"estimates"`
+	Assumptions []string   `json:"assumptions"`
+}
+
+// Percentiles reported by Simulate
+var Percentiles = []int{50, 80, 95}
+
+// CycleTimes returns how long each completed task of the epic took from start to
+// completion, using the task timestamps or else its first start and last
+// completion event
+func CycleTimes(e *epic.Epic) []time.Duration {
+	firstStart := make(map[string]time.Time)
+	lastCompletion := make(map[string]time.Time)
+	for _, event := range e.Events {
+		id := service.EventEntityID(event)
+		switch service.EventType(event.Type) {
+		case service.EventTaskStarted:
+			if first, ok := firstStart[id]; !ok || event.Timestamp.Before(first) {
+				firstStart[id] = event.Timestamp
+			}
+		case service.EventTaskCompleted:
+			if event.Timestamp.After(lastCompletion[id]) {
+				lastCompletion[id] = event.Timestamp
+			}
+		}
+	}
+
+	var cycleTimes []time.Duration
+	for _, task := range e.Tasks {
+		if task.Status != epic.StatusCompleted {
+			continue
+		}
+		start, end := firstStart[task.ID], lastCompletion[task.ID]
+		if task.StartedAt != nil {
+			start = *task.StartedAt
+		}
+		if task.CompletedAt != nil {
+			end = *task.CompletedAt
+		}
+		if start.IsZero() || !end.After(start) {
+			continue
+		}
+		cycleTimes = append(cycleTimes, end.Sub(start))
+	}
+	return cycleTimes
+}
+
+// Simulate forecasts when the epic's open tasks will be done by drawing their
+// cycle times from history, many times over. Tasks are scheduled on
+// opts.Parallel workers, each picking the next task as soon as it is free;
+// in-progress tasks only need what is left of their drawn cycle time.
+func Simulate(e *epic.Epic, history []time.Duration, now time.Time, opts Options) (*Result, error) {
+	if len(history) == 0 {
+		return nil, fmt.Errorf("no completed tasks with start and completion times to learn cycle times from")
+	}
+	opts.Runs = max(opts.Runs, 1)
+	opts.Parallel = max(opts.Parallel, 1)
+
+	result := &Result{
+		EpicID:   e.ID,
+		Samples:  len(history),
+		Runs:     opts.Runs,
+		Parallel: opts.Parallel,
+		Seed:     opts.Seed,
+	}
+
+	// Elapsed time of each open task; zero for pending tasks
+	var open []time.Duration
+	for _, task := range e.Tasks {
+		switch task.Status {
+		case epic.StatusCompleted, epic.StatusCancelled:
+			continue
+		case epic.StatusWIP:
+			result.InProgress++
+			elapsed := time.Duration(0)
+			if task.StartedAt != nil && now.After(*task.StartedAt) {
+				elapsed = now.Sub(*task.StartedAt)
+			}
+			open = append(open, elapsed)
+		default:
+			open = append(open, 0)
+		}
+	}
+	result.Remaining = len(open)
+
+	rng := rand.New(rand.NewPCG(uint64(opts.Seed), uint64(opts.Seed)))
+	totals := make([]time.Duration, opts.Runs)
+	workers := make([]time.Duration, opts.Parallel)
+	for run := range totals {
+		clear(workers)
+		for _, elapsed := range open {
+			duration := max(history[rng.IntN(len(history))]-elapsed, 0)
+			next := 0
+			for i := range workers {
+				if workers[i] < workers[next] {
+					next = i
+				}
+			}
+			workers[next] += duration
+		}
+		for _, load := range workers {
+			totals[run] = max(totals[run], load)
+		}
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i] < totals[j] })
+
+	for _, percentile := range Percentiles {
+		index := int(math.Ceil(float64(percentile)/100*float64(len(totals)))) - 1
+		total := totals[max(index, 0)]
+		result.Estimates = append(result.Estimates, Estimate{
+			Percentile: percentile,
+			Minutes:    int(total.Minutes()),
+			Date:       now.Add(total),
+		})
+	}
+
+	result.Assumptions = []string{
+		fmt.Sprintf("Cycle times (start to completion) of %d completed tasks are representative of the %d open ones", len(history), len(open)),
+		"Open tasks take independent draws from that history, regardless of size or phase",
+		fmt.Sprintf("%d task(s) are worked on at a time and the next one starts right away, without idle time", opts.Parallel),
+		"In-progress tasks only need what is left of their drawn cycle time",
+		"Scope stays as it is: no tasks are added, cancelled or reset",
+	}
+	return result, nil
+}
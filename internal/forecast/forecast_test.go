@@ -0,0 +1,86 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func at(hour int) time.Time {
+	return time.Date(2025, 8, 15, hour, 0, 0, 0, time.UTC)
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestCycleTimes(t *testing.T) {
+	e := &epic.Epic{
+		ID: "epic-1",
+		Tasks: []epic.Task{
+			{ID: "T1", Status: epic.StatusCompleted, StartedAt: ptr(at(9)), CompletedAt: ptr(at(11))},
+			{ID: "T2", Name: "Task 2", Status: epic.StatusCompleted},
+			{ID: "T3", Status: epic.StatusCompleted, CompletedAt: ptr(at(12))}, // start unknown
+			{ID: "T4", Status: epic.StatusWIP, StartedAt: ptr(at(9))},
+		},
+	}
+	service.CreateEvent(e, service.EventTaskStarted, "", "T2", "", "", at(10))
+	service.CreateEvent(e, service.EventTaskCompleted, "", "T2", "", "", at(13))
+
+	assert.Equal(t, []time.Duration{2 * time.Hour, 3 * time.Hour}, CycleTimes(e))
+}
+
+func TestSimulate(t *testing.T) {
+	e := &epic.Epic{
+		ID: "epic-1",
+		Tasks: []epic.Task{
+			{ID: "T1", Status: epic.StatusCompleted},
+			{ID: "T2", Status: epic.StatusWIP, StartedAt: ptr(at(9))},
+			{ID: "T3", Status: epic.StatusPending},
+			{ID: "T4", Status: epic.StatusPending},
+			{ID: "T5", Status: epic.StatusCancelled},
+		},
+	}
+	now := at(10)
+
+	t.Run("constant history gives a deterministic result", func(t *testing.T) {
+		result, err := Simulate(e, []time.Duration{3 * time.Hour}, now, Options{Runs: 100, Parallel: 1, Seed: 1})
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, result.Remaining)
+		assert.Equal(t, 1, result.InProgress)
+		require.Len(t, result.Estimates, 3)
+		for _, estimate := range result.Estimates {
+			assert.Equal(t, 8*60, estimate.Minutes, "2h left on T2 plus 3h each for T3 and T4")
+			assert.Equal(t, at(18), estimate.Date)
+		}
+		assert.NotEmpty(t, result.Assumptions)
+	})
+
+	t.Run("parallel workers", func(t *testing.T) {
+		result, err := Simulate(e, []time.Duration{3 * time.Hour}, now, Options{Runs: 10, Parallel: 2, Seed: 1})
+		require.NoError(t, err)
+		assert.Equal(t, 5*60, result.Estimates[0].Minutes, "T3 runs beside T2, T4 follows on the first free worker")
+	})
+
+	t.Run("percentiles are ordered and reproducible", func(t *testing.T) {
+		history := []time.Duration{time.Hour, 2 * time.Hour, 4 * time.Hour, 8 * time.Hour}
+		first, err := Simulate(e, history, now, Options{Runs: 1000, Parallel: 1, Seed: 42})
+		require.NoError(t, err)
+		second, err := Simulate(e, history, now, Options{Runs: 1000, Parallel: 1, Seed: 42})
+		require.NoError(t, err)
+
+		assert.Equal(t, first.Estimates, second.Estimates)
+		assert.LessOrEqual(t, first.Estimates[0].Minutes, first.Estimates[1].Minutes)
+		assert.LessOrEqual(t, first.Estimates[1].Minutes, first.Estimates[2].Minutes)
+	})
+
+	t.Run("requires history", func(t *testing.T) {
+		_, err := Simulate(e, nil, now, Options{Runs: 10})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,135 @@
+// Package gitmeta reads the commit and branch checked out in a git working
+// tree straight from its git directory, so that events can be linked to the
+// commit they were recorded at without running git.
+package gitmeta
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Info is the state of HEAD in a working tree
+type Info struct {
+	Commit string // Full SHA of the checked out commit
+	Branch string // Checked out branch, empty for a detached HEAD
+}
+
+// Detect finds the git working tree containing dir, searching its parents like
+// git does, and reads its HEAD. It returns nil outside of git and when HEAD
+// cannot be resolved, e.g. on a branch without commits.
+func Detect(dir string) *Info {
+	gitDir, commonDir := findGitDir(dir)
+	if gitDir == "" {
+		return nil
+	}
+
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return nil
+	}
+	ref, symbolic := strings.CutPrefix(strings.TrimSpace(string(head)), "ref:")
+	if !symbolic {
+		if !isSHA(ref) {
+			return nil
+		}
+		return &Info{Commit: ref}
+	}
+
+	ref = strings.TrimSpace(ref)
+	commit := resolveRef(gitDir, commonDir, ref)
+	if commit == "" {
+		return nil
+	}
+	return &Info{Commit: commit, Branch: strings.TrimPrefix(ref, "refs/heads/")}
+}
+
+// ShortSHA abbreviates a commit SHA the way git log --oneline does
+func ShortSHA(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
+
+// findGitDir returns the git directory of the working tree containing dir and
+// the directory its refs are shared in, which differ for linked worktrees
+func findGitDir(dir string) (gitDir, commonDir string) {
+	for dir = filepath.Clean(dir); ; dir = filepath.Dir(dir) {
+		gitPath := filepath.Join(dir, ".git")
+		if info, err := os.Stat(gitPath); err == nil {
+			if info.IsDir() {
+				return gitPath, gitPath
+			}
+			return readGitFile(dir, gitPath)
+		}
+		if filepath.Dir(dir) == dir {
+			return "", ""
+		}
+	}
+}
+
+// readGitFile follows the .git file of a linked worktree or submodule
+func readGitFile(root, gitFile string) (gitDir, commonDir string) {
+	data, err := os.ReadFile(gitFile)
+	if err != nil {
+		return "", ""
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", ""
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(root, gitDir)
+	}
+
+	commonDir = gitDir
+	if common, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir = strings.TrimSpace(string(common))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+	}
+	return filepath.Clean(gitDir), filepath.Clean(commonDir)
+}
+
+// resolveRef reads a ref from its loose file, else from packed-refs
+func resolveRef(gitDir, commonDir, ref string) string {
+	for _, dir := range []string{gitDir, commonDir} {
+		if data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(ref))); err == nil {
+			if commit := strings.TrimSpace(string(data)); isSHA(commit) {
+				return commit
+			}
+		}
+	}
+
+	file, err := os.Open(filepath.Join(commonDir, "packed-refs"))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		commit, name, ok := strings.Cut(scanner.Text(), " ")
+		if ok && name == ref && isSHA(commit) {
+			return commit
+		}
+	}
+	return ""
+}
+
+// isSHA reports whether s is a full SHA-1 or SHA-256 object name
+func isSHA(s string) bool {
+	if len(s) != 40 && len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package gitmeta
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	commitA = strings.Repeat("a", 40)
+	commitB = strings.Repeat("b", 40)
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestDetect(t *testing.T) {
+	t.Run("branch with a loose ref, from a subdirectory", func(t *testing.T) {
+		root := t.TempDir()
+		writeFile(t, filepath.Join(root, ".git", "HEAD"), "ref: refs/heads/feature/login\n")
+		writeFile(t, filepath.Join(root, ".git", "refs", "heads", "feature", "login"), commitA+"\n")
+		sub := filepath.Join(root, "docs", "epics")
+		require.NoError(t, os.MkdirAll(sub, 0755))
+
+		assert.Equal(t, &Info{Commit: commitA, Branch: "feature/login"}, Detect(sub))
+	})
+
+	t.Run("branch with a packed ref", func(t *testing.T) {
+		root := t.TempDir()
+		writeFile(t, filepath.Join(root, ".git", "HEAD"), "ref: refs/heads/main\n")
+		writeFile(t, filepath.Join(root, ".git", "packed-refs"),
+			"# pack-refs with: peeled fully-peeled sorted\n"+commitB+" refs/heads/other\n"+commitA+" refs/heads/main\n")
+
+		assert.Equal(t, &Info{Commit: commitA, Branch: "main"}, Detect(root))
+	})
+
+	t.Run("detached HEAD", func(t *testing.T) {
+		root := t.TempDir()
+		writeFile(t, filepath.Join(root, ".git", "HEAD"), commitB+"\n")
+
+		assert.Equal(t, &Info{Commit: commitB}, Detect(root))
+	})
+
+	t.Run("linked worktree reads its own HEAD and the shared refs", func(t *testing.T) {
+		main := t.TempDir()
+		gitDir := filepath.Join(main, ".git", "worktrees", "wt")
+		writeFile(t, filepath.Join(gitDir, "HEAD"), "ref: refs/heads/wt-branch\n")
+		writeFile(t, filepath.Join(gitDir, "commondir"), "../..\n")
+		writeFile(t, filepath.Join(main, ".git", "refs", "heads", "wt-branch"), commitB+"\n")
+
+		worktree := t.TempDir()
+		writeFile(t, filepath.Join(worktree, ".git"), "gitdir: "+gitDir+"\n")
+
+		assert.Equal(t, &Info{Commit: commitB, Branch: "wt-branch"}, Detect(worktree))
+	})
+
+	t.Run("branch without commits", func(t *testing.T) {
+		root := t.TempDir()
+		writeFile(t, filepath.Join(root, ".git", "HEAD"), "ref: refs/heads/main\n")
+
+		assert.Nil(t, Detect(root))
+	})
+
+	t.Run("outside of git", func(t *testing.T) {
+		assert.Nil(t, Detect(t.TempDir()))
+	})
+}
+
+func TestShortSHA(t *testing.T) {
+	assert.Equal(t, "aaaaaaa", ShortSHA(commitA))
+	assert.Equal(t, "abc", ShortSHA("abc"))
+}
@@ -0,0 +1,155 @@
+// Package handoff issues and verifies signed handoff tokens. A token captures
+// the epic, the task being handed over and the state version of the epic file,
+// so that exactly one agent can resume from it and only while the epic is
+// unchanged.
+package handoff
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/service"
+)
+
+// Token is the content of a handoff token
+type Token struct {
+	ID       string    `json:"id"`
+	EpicID   string    `json:"epic"`
+	Task     string    `json:"task,omitempty"` // The active task being handed over
+	Version  string    `json:"version"`        // State version of the epic file, see Version
+	IssuedAt time.Time `json:"issued_at"`
+	IssuedBy string    `json:"issued_by,omitempty"`
+}
+
+// ErrInvalidSignature is returned for tokens not signed with the project's key
+var ErrInvalidSignature = errors.New("invalid handoff token: signature does not match (issued for another project, or altered)")
+
+// DivergedError is returned when the epic changed after the token was issued
+type DivergedError struct {
+	Token   *Token
+	Current string // Current state version
+	Reason  string
+}
+
+func (e *DivergedError) Error() string {
+	return fmt.Sprintf("epic %s has diverged since handoff %s was issued: %s", e.Token.EpicID, e.Token.ID, e.Reason)
+}
+
+// Version returns the state version of epic file contents
+func Version(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// LoadKey reads the project's signing key, creating it on first use
+func LoadKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(key) < 32 {
+			return nil, fmt.Errorf("invalid handoff key %s", path)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read handoff key: %w", err)
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate handoff key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create handoff key directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(key)+"\n"), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write handoff key: %w", err)
+	}
+	return key, nil
+}
+
+// NewToken captures the state of an epic file for handing it over
+func NewToken(e *epic.Epic, content []byte, now time.Time) (*Token, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate handoff ID: %w", err)
+	}
+	token := &Token{
+		ID:       hex.EncodeToString(id),
+		EpicID:   e.ID,
+		Version:  Version(content),
+		IssuedAt: now.UTC(),
+		IssuedBy: service.Actor(),
+	}
+	if task := e.ActiveTask(""); task != nil {
+		token.Task = task.ID
+	}
+	return token, nil
+}
+
+// Sign encodes a token as <payload>.<signature>, both base64url
+func Sign(key []byte, token *Token) (string, error) {
+	payload, err := json.Marshal(token)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal handoff token: %w", err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(signature(key, encoded)), nil
+}
+
+// Parse verifies the signature of an encoded token and decodes it
+func Parse(key []byte, encoded string) (*Token, error) {
+	payload, sig, found := strings.Cut(strings.TrimSpace(encoded), ".")
+	if !found {
+		return nil, fmt.Errorf("invalid handoff token: malformed")
+	}
+	decodedSig, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(decodedSig, signature(key, payload)) {
+		return nil, ErrInvalidSignature
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid handoff token: %w", err)
+	}
+	var token Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("invalid handoff token: %w", err)
+	}
+	return &token, nil
+}
+
+// Check verifies that a token can be resumed against the epic file: same
+// epic, not resumed before and the state version unchanged
+func (t *Token) Check(e *epic.Epic, content []byte) error {
+	current := Version(content)
+	if e.ID != t.EpicID {
+		return &DivergedError{Token: t, Current: current, Reason: fmt.Sprintf("the epic file now holds epic %s", e.ID)}
+	}
+	for _, event := range e.Events {
+		if event.Type == string(service.EventHandoffResumed) && service.EventEntityID(event) == t.ID {
+			return &DivergedError{Token: t, Current: current, Reason: fmt.Sprintf("it was already resumed at %s", event.Timestamp.Format(time.RFC3339))}
+		}
+	}
+	if current != t.Version {
+		return &DivergedError{Token: t, Current: current, Reason: "the epic file was changed after the handoff"}
+	}
+	return nil
+}
+
+func signature(key []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,66 @@
+package handoff
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandoffToken(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), ".agentpm", "handoff.key")
+	key, err := LoadKey(keyPath)
+	require.NoError(t, err)
+	reloaded, err := LoadKey(keyPath)
+	require.NoError(t, err)
+	assert.Equal(t, key, reloaded, "the key is created once and reused")
+
+	e := epic.NewEpic("8", "Handoff")
+	e.Tasks = []epic.Task{{ID: "T1", Status: epic.StatusCompleted}, {ID: "T2", Status: epic.StatusWIP}}
+	content := []byte("<epic id=\"8\"/>")
+	now := time.Date(2025, 8, 20, 12, 0, 0, 0, time.UTC)
+
+	token, err := NewToken(e, content, now)
+	require.NoError(t, err)
+	assert.Equal(t, "T2", token.Task)
+	assert.Equal(t, Version(content), token.Version)
+
+	encoded, err := Sign(key, token)
+	require.NoError(t, err)
+
+	t.Run("verifies the signature", func(t *testing.T) {
+		parsed, err := Parse(key, encoded)
+		require.NoError(t, err)
+		assert.Equal(t, token, parsed)
+
+		_, err = Parse(key, encoded[:len(encoded)-2]+"xx")
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+
+		otherKey, err := LoadKey(filepath.Join(t.TempDir(), "handoff.key"))
+		require.NoError(t, err)
+		_, err = Parse(otherKey, encoded)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("rejects diverged epics", func(t *testing.T) {
+		assert.NoError(t, token.Check(e, content))
+
+		var diverged *DivergedError
+		require.True(t, errors.As(token.Check(e, []byte("<epic id=\"8\" status=\"wip\"/>")), &diverged))
+		assert.Equal(t, "the epic file was changed after the handoff", diverged.Reason)
+
+		other := epic.NewEpic("9", "Other")
+		assert.ErrorContains(t, token.Check(other, content), "the epic file now holds epic 9")
+	})
+
+	t.Run("rejects a second resume", func(t *testing.T) {
+		service.CreateEvent(e, service.EventHandoffResumed, "", token.Task, "", token.ID, now)
+		assert.Equal(t, "Handoff "+token.ID+" resumed with task T2", e.Events[len(e.Events)-1].Data)
+		assert.ErrorContains(t, token.Check(e, content), "it was already resumed at 2025-08-20T12:00:00Z")
+	})
+}
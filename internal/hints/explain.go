@@ -0,0 +1,84 @@
+package hints
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Generator outcomes of an explanation
+const (
+	OutcomeSelected      = "selected"       // Its hint is the one shown
+	OutcomeNotApplicable = "not_applicable" // CanHandle rejected the context
+	OutcomeNoHint        = "no_hint"        // It handles the context but produced no hint
+	OutcomeBelowMinimum  = "below_minimum"  // Its hint is filtered by the minimum priority
+	OutcomeNotReached    = "not_reached"    // It would fire, but an earlier generator was selected
+)
+
+// GeneratorTrace is what one generator contributes for a context
+type GeneratorTrace struct {
+	Name     string `json:"name"`
+	Priority int    `json:"priority"`
+	Outcome  string `json:"outcome"`
+	Hint     *Hint  `json:"hint,omitempty"`
+}
+
+// Explanation shows how the registry arrives at the hint for a context
+type Explanation struct {
+	Enabled     bool             `json:"enabled"`
+	MinPriority HintPriority     `json:"min_priority"`
+	Generators  []GeneratorTrace `json:"generators"` // In evaluation order
+	Selected    *Hint            `json:"selected,omitempty"`
+	SelectedBy  string           `json:"selected_by,omitempty"` // Generator name, or "default"
+}
+
+// Explain evaluates every generator for ctx the way GenerateHint does, without
+// stopping at the first match and without counting the error as a repeat
+func (hr *HintRegistry) Explain(ctx *HintContext) *Explanation {
+	explanation := &Explanation{Enabled: hr.config.Enabled, MinPriority: hr.config.MinPriority}
+	if !hr.config.Enabled {
+		return explanation
+	}
+
+	for _, generator := range hr.generators {
+		trace := GeneratorTrace{Name: GeneratorName(generator), Priority: generator.Priority()}
+		switch {
+		case !generator.CanHandle(ctx):
+			trace.Outcome = OutcomeNotApplicable
+		default:
+			trace.Hint = hr.applyConfiguration(generator.GenerateHint(ctx), ctx)
+			switch {
+			case trace.Hint == nil:
+				trace.Outcome = OutcomeNoHint
+			case !hr.meetsMinimumPriority(trace.Hint.Priority):
+				trace.Outcome = OutcomeBelowMinimum
+			case explanation.Selected != nil:
+				trace.Outcome = OutcomeNotReached
+			default:
+				trace.Outcome = OutcomeSelected
+				explanation.Selected = trace.Hint
+				explanation.SelectedBy = trace.Name
+			}
+		}
+		explanation.Generators = append(explanation.Generators, trace)
+	}
+
+	if explanation.Selected == nil {
+		// Same fallback as GenerateHint
+		defaultHint := hr.applyConfiguration(&Hint{
+			Content:  "Check the current state and try again",
+			Category: HintCategoryInformational,
+			Priority: HintPriorityLow,
+		}, ctx)
+		if hr.meetsMinimumPriority(defaultHint.Priority) {
+			explanation.Selected = defaultHint
+			explanation.SelectedBy = "default"
+		}
+	}
+	return explanation
+}
+
+// GeneratorName returns the type name of a generator, e.g. "PhaseConstraintHintGenerator"
+func GeneratorName(generator HintGenerator) string {
+	name := fmt.Sprintf("%T", generator)
+	return name[strings.LastIndex(name, ".")+1:]
+}
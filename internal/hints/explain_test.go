@@ -0,0 +1,69 @@
+package hints
+
+import (
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHintRegistry_Explain(t *testing.T) {
+	phaseConflict := func() *HintContext {
+		return &HintContext{
+			ErrorType:     "PhaseConstraintError",
+			OperationType: "start",
+			EntityType:    "phase",
+			EntityID:      "phase-2",
+			ActivePhase:   &epic.Phase{ID: "phase-1"},
+		}
+	}
+
+	t.Run("selects the hint GenerateHint returns", func(t *testing.T) {
+		registry := DefaultHintRegistry()
+		explanation := registry.Explain(phaseConflict())
+
+		require.NotNil(t, explanation.Selected)
+		assert.Equal(t, registry.GenerateHint(phaseConflict()), explanation.Selected)
+		assert.Equal(t, "PhaseConstraintHintGenerator", explanation.SelectedBy)
+		require.Len(t, explanation.Generators, len(registry.Generators()))
+
+		outcomes := make(map[string]string)
+		for _, trace := range explanation.Generators {
+			outcomes[trace.Name] = trace.Outcome
+		}
+		assert.Equal(t, OutcomeSelected, outcomes["PhaseConstraintHintGenerator"])
+		assert.Equal(t, OutcomeNotApplicable, outcomes["TaskConstraintHintGenerator"])
+		assert.Equal(t, OutcomeBelowMinimum, outcomes["WorkflowHintGenerator"])
+	})
+
+	t.Run("falls back to the default hint", func(t *testing.T) {
+		config := DefaultHintRegistryConfig()
+		config.MinPriority = HintPriorityLow
+		registry := NewHintRegistryWithConfig(config)
+
+		explanation := registry.Explain(&HintContext{ErrorType: "UnknownError"})
+		assert.Empty(t, explanation.Generators)
+		require.NotNil(t, explanation.Selected)
+		assert.Equal(t, "default", explanation.SelectedBy)
+		assert.Equal(t, "Check the current state and try again", explanation.Selected.Content)
+	})
+
+	t.Run("shows no hint below the minimum priority", func(t *testing.T) {
+		registry := NewHintRegistry()
+		registry.Register(&WorkflowHintGenerator{})
+
+		explanation := registry.Explain(&HintContext{ErrorType: "UnknownError"})
+		require.Len(t, explanation.Generators, 1)
+		assert.Equal(t, OutcomeBelowMinimum, explanation.Generators[0].Outcome)
+		assert.Nil(t, explanation.Selected)
+	})
+
+	t.Run("disabled registry explains nothing", func(t *testing.T) {
+		registry := NewHintRegistryWithConfig(&HintRegistryConfig{Enabled: false})
+		explanation := registry.Explain(phaseConflict())
+		assert.False(t, explanation.Enabled)
+		assert.Empty(t, explanation.Generators)
+		assert.Nil(t, explanation.Selected)
+	})
+}
@@ -2,8 +2,11 @@ package hints
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/planner"
 )
 
 // HintCategory represents different categories of hints
@@ -28,12 +31,13 @@ const (
 
 // Hint represents a structured hint with metadata
 type Hint struct {
-	Content    string       `json:"content"`    // The hint text
-	Category   HintCategory `json:"category"`   // Type of hint
-	Priority   HintPriority `json:"priority"`   // Priority level
-	Command    string       `json:"command"`    // Suggested command (optional)
-	Reference  string       `json:"reference"`  // Documentation reference (optional)
-	Conditions []string     `json:"conditions"` // When this hint applies
+	Content    string       `json:"content"`           // The hint text
+	Category   HintCategory `json:"category"`          // Type of hint
+	Priority   HintPriority `json:"priority"`          // Priority level
+	Command    string       `json:"command"`           // Suggested command (optional)
+	Reference  string       `json:"reference"`         // Documentation reference (optional)
+	Conditions []string     `json:"conditions"`        // When this hint applies
+	Repeats    int          `json:"repeats,omitempty"` // Times the error was hit this session, when tracked
 }
 
 // HintContext contains context information for generating hints
@@ -66,6 +70,11 @@ type HintGenerator interface {
 type HintRegistry struct {
 	generators []HintGenerator
 	config     *HintRegistryConfig
+
+	// Repeat tracking, see TrackRepeats
+	repeats *RepeatStore
+	agent   string
+	now     time.Time
 }
 
 // HintRegistryConfig controls hint generation behavior
@@ -76,6 +85,9 @@ type HintRegistryConfig struct {
 	MinPriority    HintPriority
 	MaxHints       int
 	Customizations map[string]string
+	EscalateAfter  int  // Hits of an error per session from which its hints are escalated (0 = never)
+	MetaHints      bool // Whether to prepend a "you've hit this N times" meta-hint to escalated hints
+	MetaHintAfter  int  // Hits of an error per session from which the meta-hint is added
 }
 
 // NewHintRegistry creates a new hint registry with default configuration
@@ -103,14 +115,32 @@ func DefaultHintRegistryConfig() *HintRegistryConfig {
 		MinPriority:    HintPriorityMedium,
 		MaxHints:       3,
 		Customizations: make(map[string]string),
+		EscalateAfter:  2,
+		MetaHints:      true,
+		MetaHintAfter:  3,
 	}
 }
 
+// Generators returns the registered generators in evaluation order
+func (hr *HintRegistry) Generators() []HintGenerator {
+	return hr.generators
+}
+
 // Register adds a hint generator to the registry
 func (hr *HintRegistry) Register(generator HintGenerator) {
 	hr.generators = append(hr.generators, generator)
 }
 
+// TrackRepeats makes the registry count the errors it generates hints for in
+// store, which is saved after each count, for agent at time now, and escalate the hints of errors the agent
+// keeps hitting: higher priority and the suggested command spelled out, plus
+// a meta-hint once the error was hit MetaHintAfter times in the session.
+func (hr *HintRegistry) TrackRepeats(store *RepeatStore, agent string, now time.Time) {
+	hr.repeats = store
+	hr.agent = agent
+	hr.now = now
+}
+
 // GenerateHint generates a hint using the first matching generator
 func (hr *HintRegistry) GenerateHint(ctx *HintContext) *Hint {
 	// Check if hints are disabled
@@ -118,13 +148,19 @@ func (hr *HintRegistry) GenerateHint(ctx *HintContext) *Hint {
 		return nil
 	}
 
+	repeats := 0
+	if hr.repeats != nil && ctx.ErrorType != "" {
+		repeats = hr.repeats.Record(hr.agent, ctx.ErrorType, hr.now)
+		hr.repeats.Save(hr.now) // Best effort, like reading the counts
+	}
+
 	// Sort generators by priority (highest first)
 	for _, generator := range hr.generators {
 		if generator.CanHandle(ctx) {
 			hint := generator.GenerateHint(ctx)
 			if hint != nil {
 				// Apply configuration filtering and customization
-				hint = hr.applyConfiguration(hint, ctx)
+				hint = hr.escalate(hr.applyConfiguration(hint, ctx), ctx, repeats)
 				if hint != nil && hr.meetsMinimumPriority(hint.Priority) {
 					return hint
 				}
@@ -139,13 +175,41 @@ func (hr *HintRegistry) GenerateHint(ctx *HintContext) *Hint {
 		Priority: HintPriorityLow,
 	}
 
+	defaultHint = hr.escalate(hr.applyConfiguration(defaultHint, ctx), ctx, repeats)
 	if hr.meetsMinimumPriority(defaultHint.Priority) {
-		return hr.applyConfiguration(defaultHint, ctx)
+		return defaultHint
 	}
 
 	return nil
 }
 
+// escalate raises the priority and verbosity of a hint for an error hit
+// repeatedly in the session
+func (hr *HintRegistry) escalate(hint *Hint, ctx *HintContext, repeats int) *Hint {
+	if hint == nil || hr.config.EscalateAfter <= 0 || repeats < hr.config.EscalateAfter {
+		return hint
+	}
+
+	hint.Repeats = repeats
+	switch hint.Priority {
+	case HintPriorityLow:
+		hint.Priority = HintPriorityMedium
+	case HintPriorityMedium:
+		hint.Priority = HintPriorityHigh
+	}
+
+	if hr.config.MetaHints && hr.config.MetaHintAfter > 0 && repeats >= hr.config.MetaHintAfter {
+		suggestion := "running 'agentpm current' to review the active work"
+		if hint.Command != "" {
+			suggestion = fmt.Sprintf("running '%s'", hint.Command)
+		}
+		hint.Content = fmt.Sprintf("You've hit %s %d times this session — consider %s. %s", ctx.ErrorType, repeats, suggestion, hint.Content)
+	} else if hint.Command != "" && !strings.Contains(hint.Content, hint.Command) {
+		hint.Content = fmt.Sprintf("%s. Run: %s", strings.TrimSuffix(hint.Content, "."), hint.Command)
+	}
+	return hint
+}
+
 // applyConfiguration applies registry configuration to a hint
 func (hr *HintRegistry) applyConfiguration(hint *Hint, ctx *HintContext) *Hint {
 	if hint == nil {
@@ -184,14 +248,49 @@ func (hr *HintRegistry) meetsMinimumPriority(priority HintPriority) bool {
 	}
 }
 
+// The configuration of the registries DefaultHintRegistry creates, see Configure
+var registryConfig *HintRegistryConfig
+
+// Configure makes the registries created by DefaultHintRegistry use config,
+// such as the hint settings of the project. nil restores the defaults.
+func Configure(config *HintRegistryConfig) {
+	registryConfig = config
+}
+
+// RegistryConfig returns a copy of the configuration DefaultHintRegistry uses
+func RegistryConfig() *HintRegistryConfig {
+	if registryConfig == nil {
+		return DefaultHintRegistryConfig()
+	}
+	config := *registryConfig
+	return &config
+}
+
+// The repeat store DefaultHintRegistry tracks errors in, see EnableRepeatTracking
+var repeatsFile, repeatsAgent string
+
+// EnableRepeatTracking makes the registries created by DefaultHintRegistry
+// track the errors agent hits in the repeat store at path. An empty path
+// disables tracking.
+func EnableRepeatTracking(path, agent string) {
+	repeatsFile, repeatsAgent = path, agent
+}
+
 // DefaultHintRegistry creates a registry with default generators
 func DefaultHintRegistry() *HintRegistry {
-	registry := NewHintRegistry()
+	registry := NewHintRegistryWithConfig(RegistryConfig())
+	if repeatsFile != "" {
+		// Hints are best effort: without readable counts they are not escalated
+		if store, err := LoadRepeatStore(repeatsFile); err == nil {
+			registry.TrackRepeats(store, repeatsAgent, time.Now())
+		}
+	}
 
 	// Register default generators
 	registry.Register(&PhaseConstraintHintGenerator{})
 	registry.Register(&TaskConstraintHintGenerator{})
 	registry.Register(&StateTransitionHintGenerator{})
+	registry.Register(&CurrentWorkHintGenerator{})
 	registry.Register(&WorkflowHintGenerator{})
 	registry.Register(&EpicPhaseAwareHintGenerator{})
 	registry.Register(&TestDependencyHintGenerator{})
@@ -453,6 +552,78 @@ func (g *WorkflowHintGenerator) GenerateHint(ctx *HintContext) *Hint {
 
 func (g *WorkflowHintGenerator) Priority() int { return 10 }
 
+// CurrentWorkHintGenerator suggests the single most useful next step for the active work,
+// used by the current command
+type CurrentWorkHintGenerator struct{}
+
+func (g *CurrentWorkHintGenerator) CanHandle(ctx *HintContext) bool {
+	return ctx.OperationType == "current" && ctx.Epic != nil
+}
+
+func (g *CurrentWorkHintGenerator) GenerateHint(ctx *HintContext) *Hint {
+	hint := &Hint{
+		Category: HintCategoryActionable,
+		Priority: HintPriorityMedium,
+	}
+
+	if ctx.ActiveTask == nil {
+		// Explain a stalled epic instead of pointing at work that cannot be started
+		if stall := planner.AnalyzeStall(ctx.Epic); stall != nil {
+			hint.Content = stall.Message
+			hint.Command = stall.Suggestion
+			return hint
+		}
+		if ctx.ActivePhase != nil {
+			hint.Content = fmt.Sprintf("No task is active in phase %s. Start the next pending task", ctx.ActivePhase.ID)
+		} else {
+			hint.Content = "No work is active. Pick up the next available phase or task"
+		}
+		hint.Command = "agentpm next"
+		return hint
+	}
+
+	task := ctx.ActiveTask
+	var pendingTest, wipTest *epic.Test
+	for i := range ctx.Epic.Tests {
+		test := &ctx.Epic.Tests[i]
+		if test.TaskID != task.ID {
+			continue
+		}
+		if test.GetTestResult() == epic.TestResultFailing && test.GetTestStatusUnified() != epic.TestStatusCancelled {
+			hint.Priority = HintPriorityHigh
+			hint.Content = fmt.Sprintf("Test %s is failing. Fix it, then mark it as passed", test.ID)
+			hint.Command = fmt.Sprintf("agentpm pass %s", test.ID)
+			return hint
+		}
+		switch test.GetTestStatusUnified() {
+		case epic.TestStatusWIP:
+			if wipTest == nil {
+				wipTest = test
+			}
+		case epic.TestStatusPending:
+			if pendingTest == nil {
+				pendingTest = test
+			}
+		}
+	}
+
+	switch {
+	case wipTest != nil:
+		hint.Content = fmt.Sprintf("Test %s is in progress. Mark it as passed once it succeeds", wipTest.ID)
+		hint.Command = fmt.Sprintf("agentpm pass %s", wipTest.ID)
+	case pendingTest != nil:
+		hint.Content = fmt.Sprintf("Start test %s for task %s", pendingTest.ID, task.ID)
+		hint.Command = fmt.Sprintf("agentpm start test %s", pendingTest.ID)
+	default:
+		hint.Content = fmt.Sprintf("All tests for task %s are done. Complete the task once its acceptance criteria are met", task.ID)
+		hint.Command = fmt.Sprintf("agentpm done task %s", task.ID)
+	}
+
+	return hint
+}
+
+func (g *CurrentWorkHintGenerator) Priority() int { return 85 }
+
 // EpicPhaseAwareHintGenerator generates hints based on epic workflow and phase relationships
 type EpicPhaseAwareHintGenerator struct{}
 
@@ -513,7 +684,7 @@ func (g *EpicPhaseAwareHintGenerator) generatePhaseWorkflowHint(ctx *HintContext
 
 		if len(dependencies) > 0 {
 			hint.Content = fmt.Sprintf("Complete phase '%s' before starting '%s'. Dependencies: %s",
-				activePhase.ID, targetPhase.ID, dependencies[0])
+				activePhase.ID, targetPhase.ID, strings.Join(dependencies, ", "))
 			hint.Command = fmt.Sprintf("agentpm done-phase %s", activePhase.ID)
 			hint.Conditions = []string{
 				"Phases should be completed in dependency order",
@@ -543,17 +714,13 @@ func (g *EpicPhaseAwareHintGenerator) generateTaskWorkflowHint(ctx *HintContext,
 	var taskPhase *epic.Phase
 	var activeTask *epic.Task
 
-	for i := range epicData.Tasks {
-		if epicData.Tasks[i].Status == epic.StatusWIP {
-			activeTask = &epicData.Tasks[i]
-			// Find the phase for this task
-			for j := range epicData.Phases {
-				if epicData.Phases[j].ID == epicData.Tasks[i].PhaseID {
-					taskPhase = &epicData.Phases[j]
-					break
-				}
+	if activeTask = epicData.ActiveTask(""); activeTask != nil {
+		// Find the phase for this task
+		for j := range epicData.Phases {
+			if epicData.Phases[j].ID == activeTask.PhaseID {
+				taskPhase = &epicData.Phases[j]
+				break
 			}
-			break
 		}
 	}
 
@@ -592,31 +759,18 @@ func (g *EpicPhaseAwareHintGenerator) generateGenericWorkflowHint(ctx *HintConte
 	return hint
 }
 
+// analyzePhaseDependencies lists the depends_on phases of the target phase
+// that are not completed yet, e.g. "phase-1 (wip)". Document order alone is
+// no dependency.
 func (g *EpicPhaseAwareHintGenerator) analyzePhaseDependencies(epicData *epic.Epic, activePhase, targetPhase *epic.Phase) []string {
 	var dependencies []string
-
-	// Simple dependency analysis - in a real implementation, this could check:
-	// - Phase order in the epic
-	// - Explicit dependencies defined in phase metadata
-	// - Task completion requirements
-
-	// For now, check if target phase comes after active phase in the list
-	activeIndex := -1
-	targetIndex := -1
-
-	for i, phase := range epicData.Phases {
-		if phase.ID == activePhase.ID {
-			activeIndex = i
+	for _, dependency := range epicData.UnmetPhaseDependencies(targetPhase) {
+		state := string(dependency.Status)
+		if !dependency.Found() {
+			state = "not found"
 		}
-		if phase.ID == targetPhase.ID {
-			targetIndex = i
-		}
-	}
-
-	if activeIndex >= 0 && targetIndex >= 0 && targetIndex > activeIndex {
-		dependencies = append(dependencies, "Sequential phase order")
+		dependencies = append(dependencies, fmt.Sprintf("%s (%s)", dependency.ID, state))
 	}
-
 	return dependencies
 }
 
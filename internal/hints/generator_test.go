@@ -13,7 +13,7 @@ func TestHintRegistry_DefaultRegistry(t *testing.T) {
 	registry := DefaultHintRegistry()
 
 	assert.NotNil(t, registry)
-	assert.Len(t, registry.generators, 6) // PhaseConstraint, TaskConstraint, StateTransition, Workflow, EpicPhaseAware, TestDependency
+	assert.Len(t, registry.generators, 7) // PhaseConstraint, TaskConstraint, StateTransition, CurrentWork, Workflow, EpicPhaseAware, TestDependency
 	assert.NotNil(t, registry.config)
 	assert.True(t, registry.config.Enabled)
 	assert.True(t, registry.config.ShowCommands)
@@ -39,6 +39,26 @@ func TestHintRegistry_NewRegistryWithConfig(t *testing.T) {
 	assert.Len(t, registry.generators, 0) // No generators registered by default
 }
 
+func TestHintRegistry_Configure(t *testing.T) {
+	t.Cleanup(func() { Configure(nil) })
+
+	config := DefaultHintRegistryConfig()
+	config.ShowCommands = false
+	config.MinPriority = HintPriorityHigh
+	Configure(config)
+
+	registry := DefaultHintRegistry()
+	assert.False(t, registry.config.ShowCommands)
+	assert.Equal(t, HintPriorityHigh, registry.config.MinPriority)
+	assert.Len(t, registry.generators, 7)
+
+	registry.config.MinPriority = HintPriorityLow
+	assert.Equal(t, HintPriorityHigh, RegistryConfig().MinPriority, "registries get a copy")
+
+	Configure(nil)
+	assert.Equal(t, DefaultHintRegistryConfig(), DefaultHintRegistry().config)
+}
+
 func TestHintRegistry_GenerateHint_DisabledConfiguration(t *testing.T) {
 	config := &HintRegistryConfig{
 		Enabled: false,
@@ -204,7 +224,7 @@ func TestEpicPhaseAwareHintGenerator(t *testing.T) {
 			Workflow: "Sequential",
 			Phases: []epic.Phase{
 				{ID: "phase-1", Status: epic.StatusWIP, Name: "Phase 1"},
-				{ID: "phase-2", Status: epic.StatusPending, Name: "Phase 2"},
+				{ID: "phase-2", Status: epic.StatusPending, Name: "Phase 2", DependsOn: "phase-1"},
 			},
 		}
 
@@ -219,7 +239,7 @@ func TestEpicPhaseAwareHintGenerator(t *testing.T) {
 		assert.NotNil(t, hint)
 		assert.Equal(t, HintCategoryWorkflow, hint.Category)
 		assert.Equal(t, HintPriorityMedium, hint.Priority)
-		assert.Contains(t, hint.Content, "Complete phase 'phase-1' before starting 'phase-2'")
+		assert.Contains(t, hint.Content, "Complete phase 'phase-1' before starting 'phase-2'. Dependencies: phase-1 (wip)")
 		assert.Equal(t, "agentpm done-phase phase-1", hint.Command)
 		assert.Contains(t, hint.Reference, "Sequential")
 		assert.Contains(t, hint.Conditions, "Phases should be completed in dependency order")
@@ -278,9 +298,9 @@ func TestEpicPhaseAwareHintGenerator(t *testing.T) {
 	t.Run("analyzes phase dependencies correctly", func(t *testing.T) {
 		epic := &epic.Epic{
 			Phases: []epic.Phase{
-				{ID: "phase-1", Name: "Phase 1"},
-				{ID: "phase-2", Name: "Phase 2"},
-				{ID: "phase-3", Name: "Phase 3"},
+				{ID: "phase-1", Name: "Phase 1", Status: "wip"},
+				{ID: "phase-2", Name: "Phase 2", Status: "completed"},
+				{ID: "phase-3", Name: "Phase 3", DependsOn: "phase-1, phase-2, phase-9"},
 			},
 		}
 
@@ -289,8 +309,21 @@ func TestEpicPhaseAwareHintGenerator(t *testing.T) {
 
 		dependencies := generator.analyzePhaseDependencies(epic, activePhase, targetPhase)
 
-		assert.Len(t, dependencies, 1)
-		assert.Contains(t, dependencies, "Sequential phase order")
+		assert.Equal(t, []string{"phase-1 (wip)", "phase-9 (not found)"}, dependencies)
+	})
+
+	t.Run("document order alone is no dependency", func(t *testing.T) {
+		epic := &epic.Epic{
+			Phases: []epic.Phase{
+				{ID: "phase-1", Name: "Phase 1"},
+				{ID: "phase-2", Name: "Phase 2"},
+				{ID: "phase-3", Name: "Phase 3"},
+			},
+		}
+
+		dependencies := generator.analyzePhaseDependencies(epic, &epic.Phases[0], &epic.Phases[2])
+
+		assert.Empty(t, dependencies)
 	})
 
 	t.Run("no dependencies for reverse order", func(t *testing.T) {
@@ -1013,3 +1046,51 @@ func TestHintRegistryIntegration(t *testing.T) {
 		assert.Equal(t, HintPriorityLow, hint.Priority)
 	})
 }
+
+func TestCurrentWorkHintGenerator(t *testing.T) {
+	generator := &CurrentWorkHintGenerator{}
+
+	t.Run("handles only current operation with epic", func(t *testing.T) {
+		assert.True(t, generator.CanHandle(&HintContext{OperationType: "current", Epic: &epic.Epic{}}))
+		assert.False(t, generator.CanHandle(&HintContext{OperationType: "current"}))
+		assert.False(t, generator.CanHandle(&HintContext{OperationType: "start", Epic: &epic.Epic{}}))
+	})
+
+	t.Run("suggests next when nothing is active", func(t *testing.T) {
+		hint := generator.GenerateHint(&HintContext{OperationType: "current", Epic: &epic.Epic{}})
+		assert.Equal(t, "agentpm next", hint.Command)
+	})
+
+	t.Run("explains a stalled epic", func(t *testing.T) {
+		epicData := &epic.Epic{
+			Phases: []epic.Phase{{ID: "P1", Status: epic.StatusWIP}},
+			Tasks:  []epic.Task{{ID: "T1", PhaseID: "P1", Status: epic.StatusCompleted}},
+			Tests:  []epic.Test{{ID: "TEST1", PhaseID: "P1", TaskID: "T1", TestStatus: epic.TestStatusPending, Status: epic.StatusPending}},
+		}
+		hint := generator.GenerateHint(&HintContext{OperationType: "current", Epic: epicData, ActivePhase: &epicData.Phases[0]})
+		assert.Equal(t, "All tasks of phase P1 are done, but test TEST1 (pending) is not passed", hint.Content)
+		assert.Equal(t, "agentpm start test TEST1", hint.Command)
+	})
+
+	t.Run("suggests starting the first pending test of the active task", func(t *testing.T) {
+		epicData := &epic.Epic{
+			Tasks: []epic.Task{{ID: "T1", PhaseID: "P1", Status: epic.StatusWIP}},
+			Tests: []epic.Test{
+				{ID: "TEST1", TaskID: "T1", TestStatus: epic.TestStatusDone, Status: epic.StatusCompleted},
+				{ID: "TEST2", TaskID: "T1", TestStatus: epic.TestStatusPending, Status: epic.StatusPending},
+			},
+		}
+		hint := generator.GenerateHint(&HintContext{OperationType: "current", Epic: epicData, ActiveTask: &epicData.Tasks[0]})
+		assert.Equal(t, "agentpm start test TEST2", hint.Command)
+		assert.Equal(t, HintPriorityMedium, hint.Priority)
+	})
+
+	t.Run("suggests completing the task when all tests are done", func(t *testing.T) {
+		epicData := &epic.Epic{
+			Tasks: []epic.Task{{ID: "T1", PhaseID: "P1", Status: epic.StatusWIP}},
+			Tests: []epic.Test{{ID: "TEST1", TaskID: "T1", TestStatus: epic.TestStatusDone, Status: epic.StatusCompleted}},
+		}
+		hint := generator.GenerateHint(&HintContext{OperationType: "current", Epic: epicData, ActiveTask: &epicData.Tasks[0]})
+		assert.Equal(t, "agentpm done task T1", hint.Command)
+	})
+}
@@ -0,0 +1,84 @@
+package hints
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SessionTimeout is the inactivity after which an agent's error counts start over
+const SessionTimeout = time.Hour
+
+// RepeatStore counts how often each agent hit each error type in its current
+// session, persisted so that counts survive between CLI invocations
+type RepeatStore struct {
+	Path     string                    `json:"-"`
+	Sessions map[string]*RepeatSession `json:"sessions"` // Keyed by agent
+}
+
+// RepeatSession is the error counts of one agent's session
+type RepeatSession struct {
+	LastSeen time.Time      `json:"last_seen"`
+	Counts   map[string]int `json:"counts"` // Keyed by error type
+}
+
+// LoadRepeatStore reads the repeat store at path. A missing file is an empty store.
+func LoadRepeatStore(path string) (*RepeatStore, error) {
+	store := &RepeatStore{Path: path, Sessions: make(map[string]*RepeatSession)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return store, nil
+		}
+		return nil, fmt.Errorf("failed to read hint repeats file %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("invalid hint repeats file %s: %w", path, err)
+	}
+	if store.Sessions == nil {
+		store.Sessions = make(map[string]*RepeatSession)
+	}
+	return store, nil
+}
+
+// Record counts a hit of errorType by agent and returns how often the agent
+// hit it in the current session, this hit included
+func (s *RepeatStore) Record(agent, errorType string, now time.Time) int {
+	session := s.Sessions[agent]
+	if session == nil || now.Sub(session.LastSeen) > SessionTimeout {
+		session = &RepeatSession{Counts: make(map[string]int)}
+		s.Sessions[agent] = session
+	}
+	session.LastSeen = now.UTC()
+	session.Counts[errorType]++
+	return session.Counts[errorType]
+}
+
+// Save writes the repeat store, dropping expired sessions
+func (s *RepeatStore) Save(now time.Time) error {
+	for agent, session := range s.Sessions {
+		if now.Sub(session.LastSeen) > SessionTimeout {
+			delete(s.Sessions, agent)
+		}
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal hint repeats: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0755); err != nil {
+		return fmt.Errorf("failed to create hint repeats directory: %w", err)
+	}
+
+	tempFile := s.Path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write hint repeats file: %w", err)
+	}
+	if err := os.Rename(tempFile, s.Path); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to move hint repeats file: %w", err)
+	}
+	return nil
+}
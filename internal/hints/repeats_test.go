@@ -0,0 +1,111 @@
+package hints
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepeatStore(t *testing.T) {
+	start := time.Date(2025, 8, 16, 10, 0, 0, 0, time.UTC)
+
+	t.Run("counts per agent and error type", func(t *testing.T) {
+		store, err := LoadRepeatStore(filepath.Join(t.TempDir(), "repeats.json"))
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, store.Record("agent-1", "TaskConstraintError", start))
+		assert.Equal(t, 2, store.Record("agent-1", "TaskConstraintError", start.Add(time.Minute)))
+		assert.Equal(t, 1, store.Record("agent-1", "PhaseConstraintError", start.Add(2*time.Minute)))
+		assert.Equal(t, 1, store.Record("agent-2", "TaskConstraintError", start.Add(3*time.Minute)))
+	})
+
+	t.Run("session starts over after inactivity", func(t *testing.T) {
+		store, err := LoadRepeatStore(filepath.Join(t.TempDir(), "repeats.json"))
+		require.NoError(t, err)
+
+		store.Record("agent-1", "TaskConstraintError", start)
+		store.Record("agent-1", "TaskConstraintError", start.Add(50*time.Minute))
+		assert.Equal(t, 3, store.Record("agent-1", "TaskConstraintError", start.Add(100*time.Minute)))
+		assert.Equal(t, 1, store.Record("agent-1", "TaskConstraintError", start.Add(100*time.Minute+SessionTimeout+time.Second)))
+	})
+
+	t.Run("persists and drops expired sessions", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".agentpm", "repeats.json")
+		store, err := LoadRepeatStore(path)
+		require.NoError(t, err)
+		store.Record("old-agent", "TaskStateError", start)
+		store.Record("agent-1", "TaskStateError", start.Add(2*time.Hour))
+		require.NoError(t, store.Save(start.Add(2*time.Hour)))
+
+		loaded, err := LoadRepeatStore(path)
+		require.NoError(t, err)
+		assert.NotContains(t, loaded.Sessions, "old-agent")
+		assert.Equal(t, 2, loaded.Record("agent-1", "TaskStateError", start.Add(2*time.Hour+time.Minute)))
+	})
+}
+
+func TestHintRegistry_TrackRepeats(t *testing.T) {
+	now := time.Date(2025, 8, 16, 10, 0, 0, 0, time.UTC)
+	store, err := LoadRepeatStore(filepath.Join(t.TempDir(), "repeats.json"))
+	require.NoError(t, err)
+
+	ctx := &HintContext{
+		ErrorType:      "PhaseConstraintError",
+		OperationType:  "start",
+		EntityType:     "phase",
+		EntityID:       "P2",
+		AdditionalData: map[string]interface{}{"active_phase": "P1"},
+	}
+	generate := func() *Hint {
+		registry := DefaultHintRegistry()
+		registry.TrackRepeats(store, "agent-1", now)
+		return registry.GenerateHint(ctx)
+	}
+
+	first := generate()
+	require.NotNil(t, first)
+	assert.Equal(t, "Complete phase 'P1' before starting 'P2'", first.Content)
+	assert.Zero(t, first.Repeats)
+
+	second := generate()
+	assert.Equal(t, 2, second.Repeats)
+	assert.Equal(t, "Complete phase 'P1' before starting 'P2'. Run: agentpm done-phase P1", second.Content)
+
+	third := generate()
+	assert.Equal(t, 3, third.Repeats)
+	assert.Equal(t, "You've hit PhaseConstraintError 3 times this session — consider running 'agentpm done-phase P1'. Complete phase 'P1' before starting 'P2'", third.Content)
+
+	t.Run("priority is raised", func(t *testing.T) {
+		stateCtx := &HintContext{ErrorType: "TaskStateError", OperationType: "start", EntityType: "task", EntityID: "T1", CurrentStatus: "wip", TargetStatus: "wip"}
+		registry := DefaultHintRegistry()
+		registry.TrackRepeats(store, "agent-1", now)
+		assert.Equal(t, HintPriorityMedium, registry.GenerateHint(stateCtx).Priority)
+		assert.Equal(t, HintPriorityHigh, registry.GenerateHint(stateCtx).Priority)
+	})
+
+	t.Run("meta-hints can be disabled", func(t *testing.T) {
+		config := DefaultHintRegistryConfig()
+		config.MetaHints = false
+		registry := NewHintRegistryWithConfig(config)
+		registry.Register(&PhaseConstraintHintGenerator{})
+		registry.TrackRepeats(store, "agent-1", now)
+
+		hint := registry.GenerateHint(ctx)
+		assert.Equal(t, 4, hint.Repeats)
+		assert.NotContains(t, hint.Content, "You've hit")
+	})
+
+	t.Run("low priority hints surface once escalated", func(t *testing.T) {
+		unknown := &HintContext{ErrorType: "UnknownError"}
+		registry := NewHintRegistry()
+		registry.TrackRepeats(store, "agent-1", now)
+		assert.Nil(t, registry.GenerateHint(unknown))
+
+		hint := registry.GenerateHint(unknown)
+		require.NotNil(t, hint)
+		assert.Equal(t, HintPriorityMedium, hint.Priority)
+	})
+}
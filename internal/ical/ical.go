@@ -0,0 +1,148 @@
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// Event is one calendar entry, a deadline or a milestone
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	AllDay      bool
+	Cancelled   bool
+}
+
+// FromEpic collects the phase and task deadlines and the milestone target dates of
+// an epic as calendar events, in that order
+func FromEpic(e *epic.Epic) ([]Event, error) {
+	var events []Event
+	add := func(entityType, id, date, summary, description string, cancelled bool) error {
+		start, allDay, err := epic.ParseDate(date)
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", entityType, id, err)
+		}
+		events = append(events, Event{
+			UID:         fmt.Sprintf("%s-%s@%s.agentpm", entityType, id, e.ID),
+			Summary:     summary,
+			Description: description,
+			Start:       start,
+			AllDay:      allDay,
+			Cancelled:   cancelled,
+		})
+		return nil
+	}
+
+	for _, phase := range e.Phases {
+		if phase.Due == "" {
+			continue
+		}
+		summary := fmt.Sprintf("Phase %s due: %s", phase.ID, phase.Name)
+		description := fmt.Sprintf("Epic %s (%s)\nStatus: %s", e.ID, e.Name, phase.Status)
+		if err := add("phase", phase.ID, phase.Due, summary, description, phase.Status == epic.StatusCancelled); err != nil {
+			return nil, err
+		}
+	}
+	for _, task := range e.Tasks {
+		if task.Due == "" {
+			continue
+		}
+		summary := fmt.Sprintf("Task %s due: %s", task.ID, task.Name)
+		description := fmt.Sprintf("Epic %s (%s), phase %s\nStatus: %s", e.ID, e.Name, task.PhaseID, task.Status)
+		if task.Assignee != "" {
+			description += "\nAssignee: " + task.Assignee
+		}
+		if err := add("task", task.ID, task.Due, summary, description, task.Status == epic.StatusCancelled); err != nil {
+			return nil, err
+		}
+	}
+	for _, milestone := range e.Milestones {
+		summary := fmt.Sprintf("Milestone: %s", milestone.Name)
+		description := fmt.Sprintf("Epic %s (%s)", e.ID, e.Name)
+		if milestone.Description != "" {
+			description += "\n" + milestone.Description
+		}
+		if err := add("milestone", milestone.ID, milestone.TargetDate, summary, description, false); err != nil {
+			return nil, err
+		}
+	}
+
+	return events, nil
+}
+
+// Write renders the events as an iCalendar (RFC 5545) feed. stamp is used as the
+// DTSTAMP of every event.
+func Write(w io.Writer, name string, events []Event, stamp time.Time) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//agentpm//agentpm//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:" + escape(name),
+	}
+
+	for _, event := range events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+event.UID,
+			"DTSTAMP:"+formatDateTime(stamp),
+		)
+		if event.AllDay {
+			lines = append(lines,
+				"DTSTART;VALUE=DATE:"+event.Start.Format("20060102"),
+				"DTEND;VALUE=DATE:"+event.Start.AddDate(0, 0, 1).Format("20060102"),
+			)
+		} else {
+			lines = append(lines, "DTSTART:"+formatDateTime(event.Start))
+		}
+		lines = append(lines, "SUMMARY:"+escape(event.Summary))
+		if event.Description != "" {
+			lines = append(lines, "DESCRIPTION:"+escape(event.Description))
+		}
+		if event.Cancelled {
+			lines = append(lines, "STATUS:CANCELLED")
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, fold(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatDateTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escape escapes text values: backslashes, semicolons, commas and newlines
+func escape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// fold splits lines longer than 75 octets into continuation lines starting with a
+// space, without splitting UTF-8 sequences
+func fold(line string) string {
+	const limit = 75
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
@@ -0,0 +1,88 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createDatedEpic() *epic.Epic {
+	return &epic.Epic{
+		ID:   "epic-1",
+		Name: "Search, v2",
+		Phases: []epic.Phase{
+			{ID: "P1", Name: "Indexing", Status: epic.StatusWIP, Due: "2025-09-15"},
+			{ID: "P2", Name: "Query", Status: epic.StatusPending},
+		},
+		Tasks: []epic.Task{
+			{ID: "T1", PhaseID: "P1", Name: "Schema", Status: epic.StatusWIP, Assignee: "alice", Due: "2025-09-10T17:00:00+02:00"},
+			{ID: "T2", PhaseID: "P1", Name: "Dropped", Status: epic.StatusCancelled, Due: "2025-09-11"},
+			{ID: "T3", PhaseID: "P2", Name: "Parser", Status: epic.StatusPending},
+		},
+		Milestones: []epic.Milestone{
+			{ID: "beta", Name: "Beta", TargetDate: "2025-10-01", Description: "Feature complete"},
+		},
+	}
+}
+
+func TestFromEpic(t *testing.T) {
+	events, err := FromEpic(createDatedEpic())
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+
+	assert.Equal(t, "phase-P1@epic-1.agentpm", events[0].UID)
+	assert.Equal(t, "Phase P1 due: Indexing", events[0].Summary)
+	assert.True(t, events[0].AllDay)
+
+	assert.Equal(t, "task-T1@epic-1.agentpm", events[1].UID)
+	assert.False(t, events[1].AllDay)
+	assert.Equal(t, time.Date(2025, 9, 10, 15, 0, 0, 0, time.UTC), events[1].Start.UTC())
+	assert.Contains(t, events[1].Description, "Assignee: alice")
+
+	assert.True(t, events[2].Cancelled)
+	assert.Equal(t, "Milestone: Beta", events[3].Summary)
+	assert.Contains(t, events[3].Description, "Feature complete")
+
+	t.Run("rejects invalid dates", func(t *testing.T) {
+		e := createDatedEpic()
+		e.Tasks[2].Due = "soon"
+		_, err := FromEpic(e)
+		assert.EqualError(t, err, `task T3: invalid date "soon" (use 2006-01-02 or RFC3339)`)
+	})
+}
+
+func TestWrite(t *testing.T) {
+	events, err := FromEpic(createDatedEpic())
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, "Search, v2", events, time.Date(2025, 8, 16, 15, 30, 0, 0, time.UTC)))
+	output := buf.String()
+
+	assert.True(t, strings.HasPrefix(output, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(output, "END:VCALENDAR\r\n"))
+	assert.Contains(t, output, "X-WR-CALNAME:Search\\, v2\r\n")
+	assert.Equal(t, 4, strings.Count(output, "BEGIN:VEVENT\r\n"))
+	assert.Equal(t, 4, strings.Count(output, "DTSTAMP:20250816T153000Z\r\n"))
+	assert.Contains(t, output, "DTSTART;VALUE=DATE:20250915\r\nDTEND;VALUE=DATE:20250916\r\n")
+	assert.Contains(t, output, "DTSTART:20250910T150000Z\r\n")
+	assert.Contains(t, output, "STATUS:CANCELLED\r\n")
+	assert.Contains(t, output, "DESCRIPTION:Epic epic-1 (Search\\, v2)\\nFeature complete\r\n")
+}
+
+func TestFold(t *testing.T) {
+	short := "SUMMARY:short"
+	assert.Equal(t, short, fold(short))
+
+	long := "SUMMARY:" + strings.Repeat("ä", 60)
+	folded := fold(long)
+	for _, line := range strings.Split(folded, "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+	}
+	assert.Equal(t, long, strings.ReplaceAll(folded, "\r\n ", ""))
+}
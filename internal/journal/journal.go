@@ -0,0 +1,171 @@
+// Package journal records the epic file as it was before each mutating command,
+// so that `agentpm undo` can restore it. The journal is a sidecar file next to
+// the epic (epic-8.xml -> .epic-8.xml.journal).
+package journal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// MaxEntries is the number of operations kept; older ones can no longer be undone
+const MaxEntries = 50
+
+// Entry is one journaled operation
+type Entry struct {
+	Operation string    `xml:"operation,attr"` // The command line, e.g. "done task 1_2"
+	Timestamp time.Time `xml:"timestamp,attr"`
+	Actor     string    `xml:"actor,attr,omitempty"`
+	After     string    `xml:"after,attr"` // Hash of the file the operation wrote
+	Before    string    `xml:",chardata"`  // The file before the operation; empty if it did not exist
+}
+
+type document struct {
+	XMLName xml.Name `xml:"journal"`
+	Entries []Entry  `xml:"entry"`
+}
+
+// ErrEmpty is returned when there is nothing to undo
+var ErrEmpty = errors.New("nothing to undo: the journal is empty")
+
+// ModifiedError is returned when the epic file changed after the last journaled
+// operation; undoing would silently drop that change
+type ModifiedError struct {
+	Path      string
+	Operation string
+}
+
+func (e *ModifiedError) Error() string {
+	return fmt.Sprintf("%s changed after '%s' was recorded; undoing would discard that change", e.Path, e.Operation)
+}
+
+// Path returns the journal file of an epic file
+func Path(epicPath string) string {
+	return filepath.Join(filepath.Dir(epicPath), "."+filepath.Base(epicPath)+".journal")
+}
+
+// Hash returns the hash recorded for file contents
+func Hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// Entries returns the journaled operations of an epic file, oldest first
+func Entries(epicPath string) ([]Entry, error) {
+	data, err := os.ReadFile(Path(epicPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	var doc document
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid journal %s: %w", Path(epicPath), err)
+	}
+	return doc.Entries, nil
+}
+
+// Record appends an operation, dropping the oldest ones beyond MaxEntries
+func Record(epicPath string, entry Entry) error {
+	entries, err := Entries(epicPath)
+	if err != nil {
+		return err
+	}
+	entries = append(entries, entry)
+	if len(entries) > MaxEntries {
+		entries = entries[len(entries)-MaxEntries:]
+	}
+	return save(epicPath, entries)
+}
+
+// Amend updates the hash of the file written by the last operation, for
+// operations that save the epic more than once
+func Amend(epicPath, after string) error {
+	entries, err := Entries(epicPath)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return ErrEmpty
+	}
+	entries[len(entries)-1].After = after
+	return save(epicPath, entries)
+}
+
+// Undo restores the epic file to its state before the last `steps` operations
+// and removes them from the journal. Unless force is set, it refuses when the
+// file changed after the last operation. It returns the undone operations,
+// most recent first.
+func Undo(epicPath string, steps int, force bool) ([]Entry, error) {
+	entries, err := Entries(epicPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, ErrEmpty
+	}
+	if steps < 1 || steps > len(entries) {
+		return nil, fmt.Errorf("cannot undo %d operations: the journal holds %d", steps, len(entries))
+	}
+
+	last := entries[len(entries)-1]
+	current, err := os.ReadFile(epicPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read epic file: %w", err)
+	}
+	if !force && Hash(current) != last.After {
+		return nil, &ModifiedError{Path: epicPath, Operation: last.Operation}
+	}
+
+	target := entries[len(entries)-steps]
+	if err := writeAtomic(epicPath, []byte(target.Before)); err != nil {
+		return nil, fmt.Errorf("failed to restore epic file: %w", err)
+	}
+
+	undone := make([]Entry, 0, steps)
+	for i := len(entries) - 1; i >= len(entries)-steps; i-- {
+		undone = append(undone, entries[i])
+	}
+	if err := save(epicPath, entries[:len(entries)-steps]); err != nil {
+		return nil, err
+	}
+	return undone, nil
+}
+
+func save(epicPath string, entries []Entry) error {
+	path := Path(epicPath)
+	if len(entries) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove journal: %w", err)
+		}
+		return nil
+	}
+
+	data, err := xml.MarshalIndent(document{Entries: entries}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal: %w", err)
+	}
+	if err := writeAtomic(path, append([]byte(xml.Header), data...)); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return nil
+}
+
+func writeAtomic(path string, data []byte) error {
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournal(t *testing.T) {
+	epicFile := filepath.Join(t.TempDir(), "epic-8.xml")
+	timestamp := time.Date(2025, 8, 16, 12, 0, 0, 0, time.UTC)
+
+	// write simulates a journaled command changing the epic file
+	write := func(operation, content string) {
+		before, _ := os.ReadFile(epicFile)
+		require.NoError(t, os.WriteFile(epicFile, []byte(content), 0644))
+		require.NoError(t, Record(epicFile, Entry{Operation: operation, Timestamp: timestamp, After: Hash([]byte(content)), Before: string(before)}))
+	}
+	read := func() string {
+		content, err := os.ReadFile(epicFile)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	require.NoError(t, os.WriteFile(epicFile, []byte("<epic status=\"pending\">\n    <tasks/>\n</epic>\n"), 0644))
+	original := read()
+	write("start epic", "<epic status=\"wip\">\n</epic>\n")
+	write("start phase 1", "<epic status=\"wip\" phase=\"1\"/>")
+	write("start task 1_1", "<epic status=\"wip\" task=\"1_1\"/>")
+
+	assert.Equal(t, filepath.Join(filepath.Dir(epicFile), ".epic-8.xml.journal"), Path(epicFile))
+	entries, err := Entries(epicFile)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, original, entries[0].Before, "the previous file is kept verbatim")
+
+	t.Run("undoes the last operation", func(t *testing.T) {
+		undone, err := Undo(epicFile, 1, false)
+		require.NoError(t, err)
+		require.Len(t, undone, 1)
+		assert.Equal(t, "start task 1_1", undone[0].Operation)
+		assert.Equal(t, `<epic status="wip" phase="1"/>`, read())
+	})
+
+	t.Run("refuses when the file changed after the last operation", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(epicFile, []byte("hand edit"), 0644))
+
+		_, err := Undo(epicFile, 1, false)
+		var modified *ModifiedError
+		require.ErrorAs(t, err, &modified)
+		assert.Equal(t, "start phase 1", modified.Operation)
+		assert.Equal(t, "hand edit", read())
+	})
+
+	t.Run("undoes several operations with force", func(t *testing.T) {
+		_, err := Undo(epicFile, 3, true)
+		assert.EqualError(t, err, "cannot undo 3 operations: the journal holds 2")
+
+		undone, err := Undo(epicFile, 2, true)
+		require.NoError(t, err)
+		assert.Equal(t, "start phase 1", undone[0].Operation)
+		assert.Equal(t, "start epic", undone[1].Operation)
+		assert.Equal(t, original, read())
+		assert.NoFileExists(t, Path(epicFile), "an empty journal is removed")
+
+		_, err = Undo(epicFile, 1, false)
+		assert.ErrorIs(t, err, ErrEmpty)
+	})
+
+	t.Run("keeps the last MaxEntries operations", func(t *testing.T) {
+		for i := 0; i < MaxEntries+5; i++ {
+			write("pass T1", "content")
+		}
+		entries, err := Entries(epicFile)
+		require.NoError(t, err)
+		assert.Len(t, entries, MaxEntries)
+	})
+}
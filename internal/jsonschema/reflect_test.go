@@ -0,0 +1,67 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sampleBase struct {
+	ID string `json:"id"`
+}
+
+type sampleNode struct {
+	Name     string        `json:"name"`
+	Children []*sampleNode `json:"children,omitempty"`
+}
+
+type sample struct {
+	sampleBase
+	Name      string          `json:"name" doc:"Display name"`
+	Count     int             `json:"count"`
+	Ratio     float64         `json:"ratio,omitempty"`
+	Done      bool            `json:"done"`
+	Tags      []string        `json:"tags"`
+	Labels    map[string]int  `json:"labels,omitempty"`
+	Created   time.Time       `json:"created_at"`
+	Finished  *time.Time      `json:"finished_at,omitempty"`
+	Raw       json.RawMessage `json:"raw,omitempty"`
+	Any       interface{}     `json:"any,omitempty"`
+	Tree      sampleNode      `json:"tree"`
+	Skipped   string          `json:"-"`
+	Untagged  string
+	unexposed string
+}
+
+func TestReflect(t *testing.T) {
+	schema := Reflect(sample{})
+	assert.Equal(t, Draft, schema["$schema"])
+	assert.Equal(t, "object", schema["type"])
+	assert.Equal(t, false, schema["additionalProperties"])
+	assert.Equal(t, []string{"id", "name", "count", "done", "tags", "created_at", "tree", "Untagged"}, schema["required"])
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.Len(t, properties, 13)
+	assert.NotContains(t, properties, "Skipped")
+	assert.NotContains(t, properties, "unexposed")
+
+	assert.Equal(t, map[string]interface{}{"type": "string"}, properties["id"], "embedded fields are flattened")
+	assert.Equal(t, map[string]interface{}{"type": "string", "description": "Display name"}, properties["name"])
+	assert.Equal(t, map[string]interface{}{"type": "integer"}, properties["count"])
+	assert.Equal(t, map[string]interface{}{"type": "number"}, properties["ratio"])
+	assert.Equal(t, map[string]interface{}{"type": "boolean"}, properties["done"])
+	assert.Equal(t, map[string]interface{}{"type": []string{"array", "null"}, "items": map[string]interface{}{"type": "string"}}, properties["tags"])
+	assert.Equal(t, map[string]interface{}{"type": []string{"object", "null"}, "additionalProperties": map[string]interface{}{"type": "integer"}}, properties["labels"])
+	assert.Equal(t, map[string]interface{}{"type": "string", "format": "date-time"}, properties["created_at"])
+	assert.Equal(t, map[string]interface{}{"type": []string{"string", "null"}, "format": "date-time"}, properties["finished_at"])
+	assert.Equal(t, map[string]interface{}{}, properties["raw"], "custom marshalers allow any value")
+	assert.Equal(t, map[string]interface{}{}, properties["any"])
+
+	tree := properties["tree"].(map[string]interface{})
+	children := tree["properties"].(map[string]interface{})["children"].(map[string]interface{})
+	require.Contains(t, children, "items")
+	assert.Equal(t, map[string]interface{}{}, children["items"], "recursive types allow any value")
+}
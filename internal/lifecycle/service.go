@@ -224,6 +224,7 @@ func (ls *LifecycleService) DoneEpic(request DoneEpicRequest) (*DoneEpicResult,
 		CompletedAt:    completedTime,
 		Duration:       duration,
 		Summary:        summary,
+		Validation:     ls.calculateValidationSummary(loadedEpic),
 		Message:        fmt.Sprintf("Epic %s completed successfully. All phases and tests complete.", loadedEpic.ID),
 		EventCreated:   true,
 	}, nil
@@ -268,6 +269,7 @@ type DoneEpicResult struct {
 	CompletedAt    time.Time
 	Duration       time.Duration
 	Summary        string
+	Validation     ValidationSummary // Counts of the completed epic, as ValidateEpicCompletion reports them
 	Message        string
 	EventCreated   bool
 }
@@ -383,15 +383,21 @@ func (ls *LifecycleService) validatePhaseConstraints(epicData *epic.Epic, result
 // validateTaskConstraints checks task-specific constraints
 func (ls *LifecycleService) validateTaskConstraints(epicData *epic.Epic, result *StateValidationResult) {
 	activeTasksByPhase := make(map[string][]string)
+	var phaseIDs []string // in order of appearance, for a stable issue order
 
 	for _, task := range epicData.Tasks {
-		if task.Status == epic.StatusWIP {
+		// A parent task is active alongside its active sub-task
+		if task.Status == epic.StatusWIP && !epicData.HasActiveSubTask(task.ID) {
+			if _, seen := activeTasksByPhase[task.PhaseID]; !seen {
+				phaseIDs = append(phaseIDs, task.PhaseID)
+			}
 			activeTasksByPhase[task.PhaseID] = append(activeTasksByPhase[task.PhaseID], task.ID)
 		}
 	}
 
 	// Check single active task per phase constraint
-	for phaseID, activeTasks := range activeTasksByPhase {
+	for _, phaseID := range phaseIDs {
+		activeTasks := activeTasksByPhase[phaseID]
 		if len(activeTasks) > 1 {
 			issue := StateValidationIssue{
 				Type:    "multiple_active_tasks_in_phase",
@@ -556,7 +562,7 @@ func (ls *LifecycleService) isPhaseReadyForCompletion(epicData *epic.Epic, phase
 	hasPendingTasks := false
 
 	for _, task := range epicData.Tasks {
-		if task.PhaseID == phaseID {
+		if task.PhaseID == phaseID && !task.IsUpcoming() {
 			switch task.Status {
 			case epic.StatusWIP:
 				hasActiveTasks = true
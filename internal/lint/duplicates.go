@@ -0,0 +1,76 @@
+package lint
+
+import (
+	"strings"
+)
+
+// Duplicate is a task or test name used in more than one epic, a hint for
+// copy-paste duplication or overlapping scope
+type Duplicate struct {
+	Type        string   `json:"type"`
+	Name        string   `json:"name"`
+	Occurrences []Entity `json:"occurrences"`
+}
+
+// Files returns the distinct epic files the duplicate occurs in
+func (d Duplicate) Files() []string {
+	var files []string
+	seen := make(map[string]bool)
+	for _, occurrence := range d.Occurrences {
+		if !seen[occurrence.File] {
+			seen[occurrence.File] = true
+			files = append(files, occurrence.File)
+		}
+	}
+	return files
+}
+
+// FindDuplicates groups tasks and tests by type and name and returns the groups
+// that span at least two epic files. Names are compared case-insensitively with
+// whitespace collapsed. Duplicates are ordered by their first occurrence.
+func FindDuplicates(entities []Entity) []Duplicate {
+	type groupKey struct{ entityType, name string }
+
+	var order []groupKey
+	groups := make(map[groupKey]*Duplicate)
+	for _, entity := range entities {
+		name := normalizeName(entity.Name)
+		if name == "" {
+			continue
+		}
+		key := groupKey{entity.Type, name}
+		group, ok := groups[key]
+		if !ok {
+			group = &Duplicate{Type: entity.Type, Name: entity.Name}
+			groups[key] = group
+			order = append(order, key)
+		}
+		group.Occurrences = append(group.Occurrences, entity)
+	}
+
+	var duplicates []Duplicate
+	for _, key := range order {
+		if group := groups[key]; len(group.Files()) > 1 {
+			duplicates = append(duplicates, *group)
+		}
+	}
+	return duplicates
+}
+
+// Involving returns the duplicates that occur in the given epic file
+func Involving(duplicates []Duplicate, file string) []Duplicate {
+	var result []Duplicate
+	for _, duplicate := range duplicates {
+		for _, occurrence := range duplicate.Occurrences {
+			if occurrence.File == file {
+				result = append(result, duplicate)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
@@ -0,0 +1,122 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const epicA = `<?xml version="1.0" encoding="UTF-8"?>
+<epic id="a" name="A" status="pending">
+    <tasks>
+        <task id="1_1" phase_id="1" name="Add login form" status="pending"/>
+        <task id="1_2" phase_id="1" name="Unique task" status="pending"/>
+    </tasks>
+    <tests>
+        <test id="T1" task_id="1_1" phase_id="1" name="Login works" status="pending"/>
+    </tests>
+</epic>
+`
+
+const epicB = `<?xml version="1.0" encoding="UTF-8"?>
+<epic id="b" name="B" status="pending">
+    <tasks>
+        <task id="2_1" phase_id="2" name="add   LOGIN form" status="pending"/>
+        <task id="2_2" phase_id="2" name="Login works" status="pending"/>
+        <task id="2_3" phase_id="2" name="Same epic" status="pending"/>
+        <task id="2_4" phase_id="2" name="Same epic" status="pending"/>
+    </tasks>
+</epic>
+`
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestFindEpicFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "b.xml"), epicB)
+	writeFile(t, filepath.Join(root, "epics", "a.xml"), epicA)
+	writeFile(t, filepath.Join(root, ".git", "c.xml"), epicA)
+	writeFile(t, filepath.Join(root, "pom.xml"), `<project/>`)
+	writeFile(t, filepath.Join(root, "broken.xml"), `not xml`)
+
+	files, err := FindEpicFiles(root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(root, "b.xml"), filepath.Join(root, "epics", "a.xml")}, files)
+}
+
+func TestLoadEntities(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.xml")
+	writeFile(t, path, epicA)
+
+	entities, err := LoadEntities(path)
+	require.NoError(t, err)
+	assert.Equal(t, []Entity{
+		{File: path, Line: 4, EpicID: "a", Type: "task", ID: "1_1", Name: "Add login form"},
+		{File: path, Line: 5, EpicID: "a", Type: "task", ID: "1_2", Name: "Unique task"},
+		{File: path, Line: 8, EpicID: "a", Type: "test", ID: "T1", Name: "Login works"},
+	}, entities)
+	assert.Equal(t, path+":4", entities[0].Link())
+}
+
+func TestFindDuplicates(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "a.xml"), epicA)
+	writeFile(t, filepath.Join(root, "b.xml"), epicB)
+
+	var entities []Entity
+	for _, name := range []string{"a.xml", "b.xml"} {
+		fileEntities, err := LoadEntities(filepath.Join(root, name))
+		require.NoError(t, err)
+		entities = append(entities, fileEntities...)
+	}
+
+	duplicates := FindDuplicates(entities)
+	require.Len(t, duplicates, 1, "a test and a task with the same name or duplicates within one epic are not reported")
+	assert.Equal(t, "task", duplicates[0].Type)
+	assert.Equal(t, "Add login form", duplicates[0].Name)
+	assert.Equal(t, []string{filepath.Join(root, "a.xml"), filepath.Join(root, "b.xml")}, duplicates[0].Files())
+	assert.Equal(t, "2_1", duplicates[0].Occurrences[1].ID)
+
+	assert.Len(t, Involving(duplicates, filepath.Join(root, "b.xml")), 1)
+	assert.Empty(t, Involving(duplicates, filepath.Join(root, "c.xml")))
+}
+
+func TestCheck(t *testing.T) {
+	e := &epic.Epic{
+		Phases: []epic.Phase{{ID: "1", Name: "Build", Description: "Build it"}},
+		Tasks: []epic.Task{
+			{ID: "1_1", PhaseID: "1", Name: "Parent"},
+			{ID: "1_2", PhaseID: "1", Name: "Child", ParentTaskID: "1_1"},
+			{ID: "1_3", PhaseID: "1", Name: "Dropped", Status: epic.StatusCancelled},
+			{ID: "1_4", PhaseID: "1", Name: "child"},
+		},
+		Tests: []epic.Test{
+			{ID: "T1", TaskID: "1_2", PhaseID: "1", Name: "Child works"},
+			{ID: "T2", PhaseID: "1", Name: "Orphan"},
+		},
+	}
+
+	findings := Check(e, nil)
+	require.Len(t, findings, 3)
+	assert.Equal(t, Finding{Rule: RuleTaskWithoutTests, Severity: SeverityWarn, Type: "task", ID: "1_4", Message: "task 1_4 (child) has no tests"}, findings[0])
+	assert.Equal(t, Finding{Rule: RuleTestWithoutTask, Severity: SeverityError, Type: "test", ID: "T2", Message: "test T2 (Orphan) is not linked to a task"}, findings[1])
+	assert.Equal(t, Finding{Rule: RuleDuplicateNames, Severity: SeverityError, Type: "task", ID: "1_4", Message: `task 1_4 has the same name as task 1_2: "child"`}, findings[2])
+
+	findings = Check(e, map[string]string{RuleDuplicateNames: SeverityOff, RuleTaskWithoutTests: SeverityError})
+	require.Len(t, findings, 2)
+	assert.Equal(t, SeverityError, findings[0].Severity)
+}
+
+func TestValidateSeverities(t *testing.T) {
+	assert.NoError(t, ValidateSeverities(map[string]string{RuleTaskWithoutTests: SeverityOff, RuleDuplicateNames: SeverityWarn}))
+	assert.ErrorContains(t, ValidateSeverities(map[string]string{"missing_docs": SeverityWarn}), `unknown lint rule "missing_docs"`)
+	assert.ErrorContains(t, ValidateSeverities(map[string]string{RuleTaskWithoutTests: "warning"}), `invalid severity "warning" (expected error, warn or off)`)
+}
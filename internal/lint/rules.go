@@ -0,0 +1,188 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// Severities of lint rules, set per rule in the lint key of the config
+const (
+	SeverityError = "error" // Reported and fails lint
+	SeverityWarn  = "warn"  // Reported only
+	SeverityOff   = "off"   // Rule is disabled
+)
+
+// Names of the lint rules
+const (
+	RuleTaskWithoutTests        = "task_without_tests"
+	RulePhaseWithoutDescription = "phase_without_description"
+	RuleTestWithoutTask         = "test_without_task"
+	RuleDuplicateNames          = "duplicate_names"
+)
+
+// Rule is a check of the structure of an epic that validation does not enforce
+type Rule struct {
+	Name        string
+	Description string
+	Severity    string // Default severity
+	check       func(e *epic.Epic) []Finding
+}
+
+// Rules are the lint rules in the order they are checked
+var Rules = []Rule{
+	{Name: RuleTaskWithoutTests, Description: "Task without tests (tasks with sub-tasks and cancelled tasks are skipped)", Severity: SeverityWarn, check: tasksWithoutTests},
+	{Name: RulePhaseWithoutDescription, Description: "Phase without description", Severity: SeverityWarn, check: phasesWithoutDescription},
+	{Name: RuleTestWithoutTask, Description: "Test not linked to a task of the epic", Severity: SeverityError, check: testsWithoutTask},
+	{Name: RuleDuplicateNames, Description: "Phases, tasks or tests sharing a name, within the epic or across the workspace", Severity: SeverityError, check: duplicateNames},
+}
+
+// Finding is a problem a lint rule found in an epic
+type Finding struct {
+	File     string `json:"file,omitempty"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Type     string `json:"type"` // "phase", "task" or "test"
+	ID       string `json:"id"`
+	Message  string `json:"message"`
+}
+
+// ValidateSeverities checks the configured severities: every key must name a
+// rule and every value must be error, warn or off
+func ValidateSeverities(severities map[string]string) error {
+	names := make([]string, 0, len(severities))
+	for name := range severities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := findRule(name); !ok {
+			return fmt.Errorf("unknown lint rule %q (expected %s)", name, strings.Join(RuleNames(), ", "))
+		}
+		switch severities[name] {
+		case SeverityError, SeverityWarn, SeverityOff:
+		default:
+			return fmt.Errorf("lint rule %s: invalid severity %q (expected error, warn or off)", name, severities[name])
+		}
+	}
+	return nil
+}
+
+// RuleNames returns the names of the lint rules
+func RuleNames() []string {
+	names := make([]string, len(Rules))
+	for i, rule := range Rules {
+		names[i] = rule.Name
+	}
+	return names
+}
+
+// Severity returns the severity of a rule: the configured one, else its default
+func Severity(severities map[string]string, name string) string {
+	if severity, ok := severities[name]; ok {
+		return severity
+	}
+	if rule, ok := findRule(name); ok {
+		return rule.Severity
+	}
+	return SeverityOff
+}
+
+// Check runs the rules that are not off against an epic
+func Check(e *epic.Epic, severities map[string]string) []Finding {
+	var findings []Finding
+	for _, rule := range Rules {
+		severity := Severity(severities, rule.Name)
+		if severity == SeverityOff {
+			continue
+		}
+		for _, finding := range rule.check(e) {
+			finding.Rule = rule.Name
+			finding.Severity = severity
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+func findRule(name string) (Rule, bool) {
+	for _, rule := range Rules {
+		if rule.Name == name {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+func tasksWithoutTests(e *epic.Epic) []Finding {
+	tested := make(map[string]bool)
+	for _, test := range e.Tests {
+		tested[test.TaskID] = true
+	}
+	var findings []Finding
+	for _, task := range e.Tasks {
+		if tested[task.ID] || task.Status == epic.StatusCancelled || len(e.SubTasks(task.ID)) > 0 {
+			continue
+		}
+		findings = append(findings, Finding{Type: "task", ID: task.ID, Message: fmt.Sprintf("task %s (%s) has no tests", task.ID, task.Name)})
+	}
+	return findings
+}
+
+func phasesWithoutDescription(e *epic.Epic) []Finding {
+	var findings []Finding
+	for _, phase := range e.Phases {
+		if strings.TrimSpace(phase.Description) == "" {
+			findings = append(findings, Finding{Type: "phase", ID: phase.ID, Message: fmt.Sprintf("phase %s (%s) has no description", phase.ID, phase.Name)})
+		}
+	}
+	return findings
+}
+
+func testsWithoutTask(e *epic.Epic) []Finding {
+	tasks := make(map[string]bool)
+	for _, task := range e.Tasks {
+		tasks[task.ID] = true
+	}
+	var findings []Finding
+	for _, test := range e.Tests {
+		switch {
+		case test.TaskID == "":
+			findings = append(findings, Finding{Type: "test", ID: test.ID, Message: fmt.Sprintf("test %s (%s) is not linked to a task", test.ID, test.Name)})
+		case !tasks[test.TaskID]:
+			findings = append(findings, Finding{Type: "test", ID: test.ID, Message: fmt.Sprintf("test %s (%s) is linked to unknown task %s", test.ID, test.Name, test.TaskID)})
+		}
+	}
+	return findings
+}
+
+// duplicateNames finds phases, tasks and tests named like an earlier one of
+// the same type; duplicates across epics are found by FindDuplicates
+func duplicateNames(e *epic.Epic) []Finding {
+	var findings []Finding
+	seen := make(map[string]string)
+	check := func(entityType, id, name string) {
+		normalized := normalizeName(name)
+		if normalized == "" {
+			return
+		}
+		key := entityType + "\x00" + normalized
+		if first, ok := seen[key]; ok {
+			findings = append(findings, Finding{Type: entityType, ID: id, Message: fmt.Sprintf("%s %s has the same name as %s %s: %q", entityType, id, entityType, first, name)})
+			return
+		}
+		seen[key] = id
+	}
+	for _, phase := range e.Phases {
+		check("phase", phase.ID, phase.Name)
+	}
+	for _, task := range e.Tasks {
+		check("task", task.ID, task.Name)
+	}
+	for _, test := range e.Tests {
+		check("test", test.ID, test.Name)
+	}
+	return findings
+}
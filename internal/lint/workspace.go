@@ -0,0 +1,141 @@
+package lint
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Entity is a named task or test of an epic file
+type Entity struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	EpicID string `json:"epic_id"`
+	Type   string `json:"type"` // "task" or "test"
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+}
+
+// Link points to the entity's definition as file:line
+func (e Entity) Link() string {
+	return fmt.Sprintf("%s:%d", e.File, e.Line)
+}
+
+// FindEpicFiles returns the epic files below root in lexical order. Every XML file
+// whose root element is <epic> counts; hidden directories such as .git are skipped.
+func FindEpicFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.EqualFold(filepath.Ext(path), ".xml") {
+			return nil
+		}
+		if isEpic, err := isEpicFile(path); err != nil {
+			return err
+		} else if isEpic {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan workspace %s: %w", root, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func isEpicFile(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	decoder := xml.NewDecoder(file)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			// Not well-formed XML before the root element: not an epic
+			return false, nil
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local == "epic", nil
+		}
+	}
+}
+
+// LoadEntities reads the tasks and tests of an epic file together with the line
+// they are defined on
+func LoadEntities(path string) ([]Entity, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read epic file: %w", err)
+	}
+	defer file.Close()
+
+	var entities []Entity
+	var epicID string
+	var parents []string
+
+	decoder := xml.NewDecoder(file)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse epic file %s: %w", path, err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			parent := ""
+			if len(parents) > 0 {
+				parent = parents[len(parents)-1]
+			}
+			parents = append(parents, t.Name.Local)
+
+			switch {
+			case t.Name.Local == "epic" && parent == "":
+				epicID = attr(t, "id")
+			case t.Name.Local == "task" && parent == "tasks", t.Name.Local == "test" && parent == "tests":
+				line, _ := decoder.InputPos()
+				entities = append(entities, Entity{
+					File:   path,
+					Line:   line,
+					EpicID: epicID,
+					Type:   t.Name.Local,
+					ID:     attr(t, "id"),
+					Name:   attr(t, "name"),
+				})
+			}
+		case xml.EndElement:
+			parents = parents[:len(parents)-1]
+		}
+	}
+
+	return entities, nil
+}
+
+func attr(start xml.StartElement, name string) string {
+	for _, a := range start.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"math"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/burndown"
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// Day is the task progress of an epic at the end of one day (UTC). Tasks
+// count as done once completed or cancelled, like in the burn-down.
+type Day struct {
+	Date      string `json:"date"`
+	Done      int    `json:"done"`
+	Remaining int    `json:"remaining"`
+	Closed    int    `json:"closed"` // net change of Done on this day, negative after resets
+}
+
+// Metrics is the progress of one epic: its daily burn-down of tasks and the
+// rate at which they get done
+type Metrics struct {
+	EpicID         string  `json:"epic_id"`
+	EpicName       string  `json:"epic_name"`
+	TotalTasks     int     `json:"total_tasks"`
+	DoneTasks      int     `json:"done_tasks"`
+	RemainingTasks int     `json:"remaining_tasks"`
+	Days           []Day   `json:"days"`
+	Velocity       float64 `json:"velocity"`        // tasks per day since the epic started
+	RecentVelocity float64 `json:"recent_velocity"` // tasks per day over the last Window days
+	Window         int     `json:"window"`
+	// DaysLeft and EstimatedCompletion project the remaining tasks at the
+	// recent velocity, or the overall one when nothing was done recently.
+	// Both are empty when the epic is done or no task has been done yet.
+	DaysLeft            int    `json:"days_left,omitempty"`
+	EstimatedCompletion string `json:"estimated_completion,omitempty"`
+}
+
+// Build computes the metrics of an epic up to now from its burn-down series;
+// the recent velocity spans the last window days
+func Build(e *epic.Epic, now time.Time, window int) *Metrics {
+	window = max(window, 1)
+	series := burndown.Build(e, burndown.IntervalDay, now)
+
+	m := &Metrics{
+		EpicID:     e.ID,
+		EpicName:   e.Name,
+		TotalTasks: series.TotalTasks,
+		Window:     window,
+	}
+
+	previous := 0
+	for _, point := range series.Points {
+		done := series.TotalTasks - point.RemainingTasks
+		m.Days = append(m.Days, Day{
+			Date:      point.Date,
+			Done:      done,
+			Remaining: point.RemainingTasks,
+			Closed:    done - previous,
+		})
+		previous = done
+	}
+	if len(m.Days) == 0 {
+		m.RemainingTasks = m.TotalTasks
+		return m
+	}
+
+	last := m.Days[len(m.Days)-1]
+	m.DoneTasks, m.RemainingTasks = last.Done, last.Remaining
+	m.Velocity = float64(last.Done) / float64(len(m.Days))
+
+	// Days before the epic started had nothing done
+	before := 0
+	if i := len(m.Days) - 1 - window; i >= 0 {
+		before = m.Days[i].Done
+	}
+	m.RecentVelocity = float64(max(last.Done-before, 0)) / float64(window)
+
+	pace := m.RecentVelocity
+	if pace == 0 {
+		pace = m.Velocity
+	}
+	if pace > 0 && m.RemainingTasks > 0 {
+		m.DaysLeft = int(math.Ceil(float64(m.RemainingTasks) / pace))
+		m.EstimatedCompletion = burndown.IntervalDay.Start(now).AddDate(0, 0, m.DaysLeft).Format(time.DateOnly)
+	}
+
+	return m
+}
+
+// Remaining returns the remaining tasks per day, e.g. for a sparkline
+func (m *Metrics) Remaining() []int {
+	values := make([]int, len(m.Days))
+	for i, day := range m.Days {
+		values[i] = day.Remaining
+	}
+	return values
+}
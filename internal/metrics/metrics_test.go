@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func day(d, hour int) time.Time {
+	return time.Date(2025, 8, d, hour, 0, 0, 0, time.UTC)
+}
+
+func createEpicWithHistory() *epic.Epic {
+	e := &epic.Epic{
+		ID:        "epic-1",
+		Name:      "Metrics Epic",
+		CreatedAt: day(11, 9),
+		Phases:    []epic.Phase{{ID: "P1", Name: "Phase 1"}},
+		Tasks: []epic.Task{
+			{ID: "T1", PhaseID: "P1", Name: "Task 1", Status: epic.StatusCompleted},
+			{ID: "T2", PhaseID: "P1", Name: "Task 2", Status: epic.StatusCancelled},
+			{ID: "T3", PhaseID: "P1", Name: "Task 3", Status: epic.StatusWIP},
+			{ID: "T4", PhaseID: "P1", Name: "Task 4", Status: epic.StatusPending},
+		},
+	}
+
+	service.CreateEvent(e, service.EventTaskCompleted, "P1", "T1", "", "", day(12, 11))
+	service.CreateEvent(e, service.EventTaskCompleted, "P1", "T3", "", "", day(13, 10))
+	service.CreateEvent(e, service.EventTaskReset, "P1", "T3", "", "", day(14, 9))
+	service.CreateEvent(e, service.EventTaskCancelled, "P1", "T2", "", "", day(14, 12))
+	return e
+}
+
+func TestBuild(t *testing.T) {
+	m := Build(createEpicWithHistory(), day(14, 18), 2)
+
+	assert.Equal(t, []Day{
+		{Date: "2025-08-11", Done: 0, Remaining: 4, Closed: 0},
+		{Date: "2025-08-12", Done: 1, Remaining: 3, Closed: 1},
+		{Date: "2025-08-13", Done: 2, Remaining: 2, Closed: 1},
+		{Date: "2025-08-14", Done: 2, Remaining: 2, Closed: 0},
+	}, m.Days)
+	assert.Equal(t, 4, m.TotalTasks)
+	assert.Equal(t, 2, m.DoneTasks)
+	assert.Equal(t, 2, m.RemainingTasks)
+	assert.Equal(t, 0.5, m.Velocity)
+	assert.Equal(t, 0.5, m.RecentVelocity)
+	assert.Equal(t, 4, m.DaysLeft)
+	assert.Equal(t, "2025-08-18", m.EstimatedCompletion)
+	assert.Equal(t, []int{4, 3, 2, 2}, m.Remaining())
+}
+
+func TestBuild_FallsBackToOverallVelocity(t *testing.T) {
+	m := Build(createEpicWithHistory(), day(20, 18), 3)
+
+	assert.Equal(t, 0.0, m.RecentVelocity)
+	assert.InDelta(t, 0.2, m.Velocity, 0.001)
+	assert.Equal(t, 10, m.DaysLeft)
+	assert.Equal(t, "2025-08-30", m.EstimatedCompletion)
+}
+
+func TestBuild_NoEstimate(t *testing.T) {
+	e := &epic.Epic{
+		ID:        "epic-2",
+		CreatedAt: day(11, 9),
+		Tasks:     []epic.Task{{ID: "T1", Status: epic.StatusPending}},
+	}
+	m := Build(e, day(12, 9), 7)
+	assert.Equal(t, 0.0, m.Velocity)
+	assert.Empty(t, m.EstimatedCompletion)
+
+	e.Tasks[0].Status = epic.StatusCompleted
+	e.Tasks[0].CompletedAt = ptr(day(12, 8))
+	m = Build(e, day(12, 9), 7)
+	assert.Equal(t, 0, m.RemainingTasks)
+	assert.Empty(t, m.EstimatedCompletion)
+	assert.Zero(t, m.DaysLeft)
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
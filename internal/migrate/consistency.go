@@ -0,0 +1,97 @@
+package migrate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// Inconsistency is a broken invariant of an epic that commands changing it rely on
+type Inconsistency struct {
+	Rule    string `json:"rule"` // "multiple_active_phases", "multiple_active_tasks" or "invalid_status"
+	Message string `json:"message"`
+}
+
+// CheckConsistency is the quick check of an epic before a command changes it:
+// at most one active phase, at most one active task per phase (a parent task
+// is active alongside its active sub-task), and status values that are either
+// canonical or a legacy spelling Statuses can migrate. Legacy spellings count
+// with their canonical meaning, a missing status as pending.
+func CheckConsistency(e *epic.Epic) []Inconsistency {
+	var problems []Inconsistency
+	invalid := func(entityType, id, field string, value epic.Status) {
+		problems = append(problems, Inconsistency{
+			Rule:    "invalid_status",
+			Message: fmt.Sprintf("%s %s has an invalid %s %q", entityType, id, field, value),
+		})
+	}
+	known := func(status epic.Status) bool {
+		_, ok := canonicalStatus(status)
+		return ok || status == ""
+	}
+	active := func(status epic.Status) bool {
+		canonical, ok := canonicalStatus(status)
+		return ok && canonical == epic.StatusWIP
+	}
+
+	if !known(e.Status) {
+		invalid("epic", e.ID, "status", e.Status)
+	}
+
+	var activePhases []string
+	for _, phase := range e.Phases {
+		if !known(phase.Status) {
+			invalid("phase", phase.ID, "status", phase.Status)
+		}
+		if active(phase.Status) {
+			activePhases = append(activePhases, phase.ID)
+		}
+	}
+	if len(activePhases) > 1 {
+		problems = append(problems, Inconsistency{
+			Rule:    "multiple_active_phases",
+			Message: fmt.Sprintf("phases %s are active at the same time", strings.Join(activePhases, ", ")),
+		})
+	}
+
+	activeTasks := make(map[string][]string)
+	var phaseIDs []string // in order of appearance, for a stable order of problems
+	for _, task := range e.Tasks {
+		if !known(task.Status) {
+			invalid("task", task.ID, "status", task.Status)
+		}
+		if !active(task.Status) || e.HasActiveSubTask(task.ID) {
+			continue
+		}
+		if _, seen := activeTasks[task.PhaseID]; !seen {
+			phaseIDs = append(phaseIDs, task.PhaseID)
+		}
+		activeTasks[task.PhaseID] = append(activeTasks[task.PhaseID], task.ID)
+	}
+	for _, phaseID := range phaseIDs {
+		if tasks := activeTasks[phaseID]; len(tasks) > 1 {
+			problems = append(problems, Inconsistency{
+				Rule:    "multiple_active_tasks",
+				Message: fmt.Sprintf("tasks %s of phase %s are active at the same time", strings.Join(tasks, ", "), phaseID),
+			})
+		}
+	}
+
+	for _, test := range e.Tests {
+		if test.Status != "" {
+			if _, _, ok := testStatus(string(test.Status)); !ok {
+				invalid("test", test.ID, "status", test.Status)
+			}
+		}
+		if test.TestStatus != "" {
+			if _, _, ok := testStatus(string(test.TestStatus)); !ok {
+				invalid("test", test.ID, "test_status", epic.Status(test.TestStatus))
+			}
+		}
+		if test.TestResult != "" && !test.TestResult.IsValid() {
+			invalid("test", test.ID, "result", epic.Status(test.TestResult))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,49 @@
+package migrate
+
+import (
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckConsistency(t *testing.T) {
+	consistent := func() *epic.Epic {
+		return &epic.Epic{
+			ID:     "8",
+			Status: "active",
+			Phases: []epic.Phase{
+				{ID: "1A", Status: epic.StatusCompleted},
+				{ID: "1B", Status: "in_progress"},
+			},
+			Tasks: []epic.Task{
+				{ID: "1B_1", PhaseID: "1B", Status: epic.StatusWIP},
+				{ID: "1B_1a", PhaseID: "1B", ParentTaskID: "1B_1", Status: epic.StatusWIP},
+				{ID: "1B_2", PhaseID: "1B"},
+			},
+			Tests: []epic.Test{
+				{ID: "T1", TaskID: "1B_1", Status: "passed"},
+				{ID: "T2", TaskID: "1B_1", Status: epic.StatusWIP, TestStatus: epic.TestStatusWIP, TestResult: epic.TestResultFailing},
+			},
+		}
+	}
+
+	t.Run("accepts legacy spellings and an active sub-task", func(t *testing.T) {
+		assert.Empty(t, CheckConsistency(consistent()))
+	})
+
+	t.Run("reports broken invariants", func(t *testing.T) {
+		e := consistent()
+		e.Phases[0].Status = "wip"
+		e.Tasks[1].ParentTaskID = ""
+		e.Tasks[2].Status = "halfway"
+		e.Tests[1].TestStatus = "flaky"
+
+		assert.Equal(t, []Inconsistency{
+			{Rule: "multiple_active_phases", Message: "phases 1A, 1B are active at the same time"},
+			{Rule: "invalid_status", Message: `task 1B_2 has an invalid status "halfway"`},
+			{Rule: "multiple_active_tasks", Message: "tasks 1B_1, 1B_1a of phase 1B are active at the same time"},
+			{Rule: "invalid_status", Message: `test T2 has an invalid test_status "flaky"`},
+		}, CheckConsistency(e))
+	})
+}
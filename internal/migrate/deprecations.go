@@ -0,0 +1,46 @@
+package migrate
+
+import "github.com/mindreframer/agentpm/internal/epic"
+
+// Deprecation is a status value of a legacy epic that the unified model no longer
+// knows: a legacy spelling, a test status that encodes its result (passed, failed)
+// or a status that was removed (tasks on hold, cancelled phases)
+type Deprecation struct {
+	EntityType  string `json:"entity_type"`
+	EntityID    string `json:"entity_id"`
+	Field       string `json:"field"`
+	Value       string `json:"value"`
+	Replacement string `json:"replacement,omitempty"` // Empty when the value needs a manual decision
+	Reason      string `json:"reason,omitempty"`
+}
+
+// Deprecations lists the deprecated status values of an epic, without changing it.
+// Tests that only lack a test_status are not deprecated: their status reads the same
+// in both models.
+func Deprecations(e *epic.Epic) []Deprecation {
+	report := Inspect(e)
+
+	var deprecations []Deprecation
+	for _, change := range report.Changes {
+		if change.Field != "status" {
+			continue
+		}
+		deprecations = append(deprecations, Deprecation{
+			EntityType:  change.EntityType,
+			EntityID:    change.EntityID,
+			Field:       change.Field,
+			Value:       change.From,
+			Replacement: change.To,
+		})
+	}
+	for _, ambiguity := range report.Ambiguities {
+		deprecations = append(deprecations, Deprecation{
+			EntityType: ambiguity.EntityType,
+			EntityID:   ambiguity.EntityID,
+			Field:      ambiguity.Field,
+			Value:      ambiguity.Value,
+			Reason:     ambiguity.Reason,
+		})
+	}
+	return deprecations
+}
@@ -0,0 +1,101 @@
+package migrate
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// Migration upgrades an epic by one schema version
+type Migration struct {
+	Version     int // Schema version the epic has once the migration applied
+	Description string
+	apply       func(e *epic.Epic) ([]Change, []Ambiguity)
+}
+
+// Migrations are the schema migrations in the order they apply; the last one
+// brings an epic to epic.CurrentSchemaVersion
+var Migrations = []Migration{
+	{Version: 1, Description: "Unify statuses (legacy spellings, test_status)", apply: migrateStatuses},
+	{Version: 2, Description: "Backfill lifecycle timestamps from the event log", apply: migrateTimestamps},
+}
+
+// Step is a migration applied to an epic and what it changed
+type Step struct {
+	Version     int      `json:"version"`
+	Description string   `json:"description"`
+	Changes     []Change `json:"changes"`
+}
+
+// SchemaReport lists the migrations applied to an epic. An epic with
+// ambiguities stops at the version before the migration that found them.
+type SchemaReport struct {
+	EpicID      string      `json:"epic_id"`
+	From        int         `json:"from_version"`
+	To          int         `json:"to_version"`
+	Steps       []Step      `json:"steps"`
+	Ambiguities []Ambiguity `json:"ambiguities"`
+}
+
+// Upgraded reports whether the epic changed, its schema version at least
+func (r *SchemaReport) Upgraded() bool {
+	if r.To != r.From {
+		return true
+	}
+	for _, step := range r.Steps {
+		if len(step.Changes) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Schema applies the migrations an epic is missing, in order, and stamps the
+// version reached. A migration that finds ambiguities keeps what it changed but
+// stops the upgrade until they are resolved. Epics of a newer schema than this
+// build knows are refused.
+func Schema(e *epic.Epic) (*SchemaReport, error) {
+	if e.SchemaVersion > epic.CurrentSchemaVersion {
+		return nil, fmt.Errorf("epic %s has schema version %d, newer than version %d this agentpm supports; upgrade agentpm", e.ID, e.SchemaVersion, epic.CurrentSchemaVersion)
+	}
+
+	report := &SchemaReport{EpicID: e.ID, From: e.SchemaVersion, To: e.SchemaVersion, Steps: []Step{}, Ambiguities: []Ambiguity{}}
+	for _, migration := range Migrations {
+		if migration.Version <= e.SchemaVersion {
+			continue
+		}
+		changes, ambiguities := migration.apply(e)
+		if changes == nil {
+			changes = []Change{}
+		}
+		report.Steps = append(report.Steps, Step{Version: migration.Version, Description: migration.Description, Changes: changes})
+		if len(ambiguities) > 0 {
+			report.Ambiguities = ambiguities
+			break
+		}
+		e.SchemaVersion = migration.Version
+		report.To = migration.Version
+	}
+	return report, nil
+}
+
+func migrateStatuses(e *epic.Epic) ([]Change, []Ambiguity) {
+	report := Statuses(e)
+	return report.Changes, report.Ambiguities
+}
+
+// migrateTimestamps backfills what the event log recorded; timestamps it does
+// not have stay missing, as they were
+func migrateTimestamps(e *epic.Epic) ([]Change, []Ambiguity) {
+	var changes []Change
+	for _, backfill := range Timestamps(e, true).Backfilled {
+		changes = append(changes, Change{
+			EntityType: backfill.EntityType,
+			EntityID:   backfill.EntityID,
+			Field:      backfill.Field,
+			To:         backfill.Value.UTC().Format(time.RFC3339),
+		})
+	}
+	return changes, nil
+}
@@ -0,0 +1,79 @@
+package migrate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations(t *testing.T) {
+	for i, migration := range Migrations {
+		assert.Equal(t, i+1, migration.Version, "migrations are numbered in order")
+	}
+	assert.Equal(t, epic.CurrentSchemaVersion, Migrations[len(Migrations)-1].Version)
+}
+
+func TestSchema(t *testing.T) {
+	t.Run("applies the missing migrations in order", func(t *testing.T) {
+		e := legacyEpic()
+		e.Events = []epic.Event{{ID: "e1", Type: "task_started", Timestamp: time.Date(2025, 8, 16, 9, 0, 0, 0, time.UTC), Data: "Task T2 started"}}
+
+		report, err := Schema(e)
+		require.NoError(t, err)
+		assert.Equal(t, 0, report.From)
+		assert.Equal(t, 2, report.To)
+		assert.True(t, report.Upgraded())
+		assert.Empty(t, report.Ambiguities)
+		require.Len(t, report.Steps, 2)
+		assert.Len(t, report.Steps[0].Changes, 11)
+		assert.Equal(t, []Change{
+			{EntityType: "task", EntityID: "T2", Field: "started_at", To: "2025-08-16T09:00:00Z"},
+		}, report.Steps[1].Changes)
+
+		assert.Equal(t, 2, e.SchemaVersion)
+		assert.Equal(t, StatusModel, e.StatusModel)
+		require.NotNil(t, e.Tasks[1].StartedAt)
+	})
+
+	t.Run("skips the migrations an epic already has", func(t *testing.T) {
+		e := legacyEpic()
+		e.SchemaVersion = 1
+
+		report, err := Schema(e)
+		require.NoError(t, err)
+		require.Len(t, report.Steps, 1)
+		assert.Equal(t, 2, report.Steps[0].Version)
+		assert.Equal(t, epic.Status("active"), e.Status, "statuses are left to migration 1")
+	})
+
+	t.Run("current epic is left alone", func(t *testing.T) {
+		e := &epic.Epic{ID: "8", Status: epic.StatusPending, SchemaVersion: epic.CurrentSchemaVersion}
+
+		report, err := Schema(e)
+		require.NoError(t, err)
+		assert.Empty(t, report.Steps)
+		assert.False(t, report.Upgraded())
+	})
+
+	t.Run("ambiguities stop the upgrade", func(t *testing.T) {
+		e := legacyEpic()
+		e.Tasks[0].Status = "on_hold"
+
+		report, err := Schema(e)
+		require.NoError(t, err)
+		assert.Equal(t, 0, report.To)
+		require.Len(t, report.Steps, 1)
+		require.Len(t, report.Ambiguities, 1)
+		assert.Equal(t, "T1", report.Ambiguities[0].EntityID)
+		assert.True(t, report.Upgraded(), "the unambiguous statuses are migrated")
+		assert.Equal(t, 0, e.SchemaVersion)
+	})
+
+	t.Run("refuses newer schemas", func(t *testing.T) {
+		_, err := Schema(&epic.Epic{ID: "8", SchemaVersion: epic.CurrentSchemaVersion + 1})
+		assert.ErrorContains(t, err, "newer than version 2")
+	})
+}
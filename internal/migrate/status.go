@@ -0,0 +1,217 @@
+package migrate
+
+import (
+	"fmt"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// StatusModel is stamped on epics whose statuses all follow the unified Epic 13 model
+const StatusModel = "epic13"
+
+// Change is one status rewritten by the migration
+type Change struct {
+	EntityType string `json:"entity_type"` // "epic", "phase", "task" or "test"
+	EntityID   string `json:"entity_id"`
+	Field      string `json:"field"` // "status", "test_status" or "status_model"
+	From       string `json:"from"`
+	To         string `json:"to"`
+}
+
+// Ambiguity is a status the migration cannot map without a human decision; the
+// entity is left untouched
+type Ambiguity struct {
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+	Field      string `json:"field"`
+	Value      string `json:"value"`
+	Reason     string `json:"reason"`
+}
+
+// Report lists what migrating an epic changed and what needs a manual decision
+type Report struct {
+	EpicID      string      `json:"epic_id"`
+	Changes     []Change    `json:"changes"`
+	Ambiguities []Ambiguity `json:"ambiguities"`
+	Stamped     bool        `json:"stamped"` // The epic is (now) marked as migrated
+}
+
+// legacyStatuses maps status spellings of older epics to the canonical status
+var legacyStatuses = map[string]epic.Status{
+	"planning":    epic.StatusPending,
+	"planned":     epic.StatusPending,
+	"todo":        epic.StatusPending,
+	"not_started": epic.StatusPending,
+	"active":      epic.StatusWIP,
+	"in_progress": epic.StatusWIP,
+	"started":     epic.StatusWIP,
+	"done":        epic.StatusCompleted,
+	"complete":    epic.StatusCompleted,
+	"finished":    epic.StatusCompleted,
+	"canceled":    epic.StatusCancelled,
+}
+
+// canonicalStatus returns the canonical form of a status value
+func canonicalStatus(value epic.Status) (epic.Status, bool) {
+	if value.IsValid() {
+		return value, true
+	}
+	status, ok := legacyStatuses[string(value)]
+	return status, ok
+}
+
+// testStatus maps a legacy or unified test status value to the unified test
+// status; failing reports the legacy "failed" spelling, which implies a failing result
+func testStatus(value string) (status epic.TestStatus, failing bool, ok bool) {
+	switch value {
+	case "passed", "passing":
+		return epic.TestStatusDone, false, true
+	case "failed", "failing":
+		return epic.TestStatusWIP, true, true
+	}
+	if unified := epic.TestStatus(value); unified.IsValid() {
+		return unified, false, true
+	}
+	canonical, ok := canonicalStatus(epic.Status(value))
+	if !ok || canonical == epic.StatusOnHold {
+		return "", false, false
+	}
+	switch canonical {
+	case epic.StatusWIP:
+		return epic.TestStatusWIP, false, true
+	case epic.StatusCompleted:
+		return epic.TestStatusDone, false, true
+	case epic.StatusCancelled:
+		return epic.TestStatusCancelled, false, true
+	default:
+		return epic.TestStatusPending, false, true
+	}
+}
+
+// Inspect reports what Statuses would do, without changing the epic
+func Inspect(e *epic.Epic) *Report {
+	clone := *e
+	clone.Phases = append([]epic.Phase(nil), e.Phases...)
+	clone.Tasks = append([]epic.Task(nil), e.Tasks...)
+	clone.Tests = append([]epic.Test(nil), e.Tests...)
+	return Statuses(&clone)
+}
+
+// Statuses rewrites the statuses of an epic and all its entities to the unified
+// Epic 13 model: legacy spellings become canonical, and tests get a test_status
+// that agrees with their status. Values without a clear mapping are reported as
+// ambiguities and left as they are. Only an epic without ambiguities is stamped
+// as migrated.
+func Statuses(e *epic.Epic) *Report {
+	report := &Report{EpicID: e.ID}
+
+	// Epics and phases know pending, wip and done only
+	migrateLifecycle := func(entityType, id string, status *epic.Status) {
+		canonical, ok := canonicalStatus(*status)
+		switch {
+		case *status == "":
+			report.ambiguous(entityType, id, "status", "", "status is missing; set pending, wip or completed")
+		case !ok:
+			report.ambiguous(entityType, id, "status", string(*status), "unknown status; set pending, wip or completed")
+		case canonical == epic.StatusCancelled || canonical == epic.StatusOnHold:
+			report.ambiguous(entityType, id, "status", string(*status), fmt.Sprintf("%ss cannot be %s in the unified model; set pending, wip or completed", entityType, canonical))
+		default:
+			report.set(entityType, id, "status", status, canonical)
+		}
+	}
+
+	migrateLifecycle("epic", e.ID, &e.Status)
+	for i := range e.Phases {
+		migrateLifecycle("phase", e.Phases[i].ID, &e.Phases[i].Status)
+	}
+
+	for i := range e.Tasks {
+		task := &e.Tasks[i]
+		canonical, ok := canonicalStatus(task.Status)
+		switch {
+		case task.Status == "":
+			report.ambiguous("task", task.ID, "status", "", "status is missing; set pending, wip, completed or cancelled")
+		case !ok:
+			report.ambiguous("task", task.ID, "status", string(task.Status), "unknown status; set pending, wip, completed or cancelled")
+		case canonical == epic.StatusOnHold:
+			report.ambiguous("task", task.ID, "status", string(task.Status), "tasks cannot be on hold in the unified model; resume it (wip) or cancel it")
+		default:
+			report.set("task", task.ID, "status", &task.Status, canonical)
+		}
+	}
+
+	for i := range e.Tests {
+		report.migrateTest(&e.Tests[i])
+	}
+
+	if len(report.Ambiguities) == 0 {
+		if e.StatusModel != StatusModel {
+			report.Changes = append(report.Changes, Change{EntityType: "epic", EntityID: e.ID, Field: "status_model", From: e.StatusModel, To: StatusModel})
+			e.StatusModel = StatusModel
+		}
+		report.Stamped = true
+	}
+	return report
+}
+
+func (r *Report) migrateTest(test *epic.Test) {
+	legacy, legacyFailing, legacyOK := testStatus(string(test.Status))
+	unified, unifiedFailing, unifiedOK := testStatus(string(test.TestStatus))
+
+	switch {
+	case test.Status == "" && test.TestStatus == "":
+		r.ambiguous("test", test.ID, "status", "", "status is missing; set pending, wip, done or cancelled")
+		return
+	case test.Status != "" && !legacyOK:
+		r.ambiguous("test", test.ID, "status", string(test.Status), "unknown status; set pending, wip, done or cancelled")
+		return
+	case test.TestStatus != "" && !unifiedOK:
+		r.ambiguous("test", test.ID, "test_status", string(test.TestStatus), "unknown test_status; set pending, wip, done or cancelled")
+		return
+	case test.Status != "" && test.TestStatus != "" && legacy != unified:
+		r.ambiguous("test", test.ID, "status", string(test.Status),
+			fmt.Sprintf("status and test_status (%s) disagree; decide which one is right", test.TestStatus))
+		return
+	}
+
+	target := unified
+	if test.TestStatus == "" {
+		target = legacy
+	}
+	const lostFailure = "failed without failed_at, so the failure would read as passing; add failed_at or set the test to pending"
+	if legacyFailing && test.FailedAt == nil {
+		r.ambiguous("test", test.ID, "status", string(test.Status), lostFailure)
+		return
+	}
+	if unifiedFailing && test.FailedAt == nil {
+		r.ambiguous("test", test.ID, "test_status", string(test.TestStatus), lostFailure)
+		return
+	}
+
+	r.setTest(test, target)
+}
+
+// set rewrites a status when the canonical form differs
+func (r *Report) set(entityType, id, field string, status *epic.Status, canonical epic.Status) {
+	if *status == canonical {
+		return
+	}
+	r.Changes = append(r.Changes, Change{EntityType: entityType, EntityID: id, Field: field, From: string(*status), To: string(canonical)})
+	*status = canonical
+}
+
+// setTest rewrites both status fields of a test to agree on target
+func (r *Report) setTest(test *epic.Test, target epic.TestStatus) {
+	before := *test
+	test.SetTestStatusUnified(target)
+	if before.Status != test.Status {
+		r.Changes = append(r.Changes, Change{EntityType: "test", EntityID: test.ID, Field: "status", From: string(before.Status), To: string(test.Status)})
+	}
+	if before.TestStatus != test.TestStatus {
+		r.Changes = append(r.Changes, Change{EntityType: "test", EntityID: test.ID, Field: "test_status", From: string(before.TestStatus), To: string(test.TestStatus)})
+	}
+}
+
+func (r *Report) ambiguous(entityType, id, field, value, reason string) {
+	r.Ambiguities = append(r.Ambiguities, Ambiguity{EntityType: entityType, EntityID: id, Field: field, Value: value, Reason: reason})
+}
@@ -0,0 +1,132 @@
+package migrate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func legacyEpic() *epic.Epic {
+	failedAt := time.Date(2025, 8, 16, 10, 0, 0, 0, time.UTC)
+	return &epic.Epic{
+		ID:     "8",
+		Status: "active",
+		Phases: []epic.Phase{
+			{ID: "P1", Status: "done"},
+			{ID: "P2", Status: "planning"},
+		},
+		Tasks: []epic.Task{
+			{ID: "T1", PhaseID: "P1", Status: epic.StatusCompleted},
+			{ID: "T2", PhaseID: "P2", Status: "in_progress"},
+			{ID: "T3", PhaseID: "P2", Status: "canceled"},
+		},
+		Tests: []epic.Test{
+			{ID: "T1_1", TaskID: "T1", Status: "passed"},
+			{ID: "T2_1", TaskID: "T2", Status: "failed", FailedAt: &failedAt},
+			{ID: "T2_2", TaskID: "T2", Status: epic.StatusPending},
+			{ID: "T2_3", TaskID: "T2", Status: epic.StatusCompleted, TestStatus: epic.TestStatusDone},
+		},
+	}
+}
+
+func TestStatuses(t *testing.T) {
+	t.Run("rewrites legacy statuses and stamps the epic", func(t *testing.T) {
+		e := legacyEpic()
+
+		report := Statuses(e)
+		assert.Empty(t, report.Ambiguities)
+		assert.True(t, report.Stamped)
+		assert.Equal(t, []Change{
+			{EntityType: "epic", EntityID: "8", Field: "status", From: "active", To: "wip"},
+			{EntityType: "phase", EntityID: "P1", Field: "status", From: "done", To: "completed"},
+			{EntityType: "phase", EntityID: "P2", Field: "status", From: "planning", To: "pending"},
+			{EntityType: "task", EntityID: "T2", Field: "status", From: "in_progress", To: "wip"},
+			{EntityType: "task", EntityID: "T3", Field: "status", From: "canceled", To: "cancelled"},
+			{EntityType: "test", EntityID: "T1_1", Field: "status", From: "passed", To: "completed"},
+			{EntityType: "test", EntityID: "T1_1", Field: "test_status", From: "", To: "done"},
+			{EntityType: "test", EntityID: "T2_1", Field: "status", From: "failed", To: "wip"},
+			{EntityType: "test", EntityID: "T2_1", Field: "test_status", From: "", To: "wip"},
+			{EntityType: "test", EntityID: "T2_2", Field: "test_status", From: "", To: "pending"},
+			{EntityType: "epic", EntityID: "8", Field: "status_model", From: "", To: "epic13"},
+		}, report.Changes)
+
+		assert.Equal(t, epic.StatusWIP, e.Status)
+		assert.Equal(t, StatusModel, e.StatusModel)
+		assert.Equal(t, epic.TestResultFailing, e.Tests[1].GetTestResult())
+		assert.Equal(t, epic.TestResultPassing, e.Tests[0].GetTestResult())
+
+		again := Statuses(e)
+		assert.Empty(t, again.Changes, "migration is idempotent")
+		assert.True(t, again.Stamped)
+	})
+
+	t.Run("reports ambiguous cases and leaves them untouched", func(t *testing.T) {
+		e := legacyEpic()
+		e.Phases[1].Status = epic.StatusCancelled
+		e.Tasks[1].Status = epic.StatusOnHold
+		e.Tests[0].Status = "flaky"
+		e.Tests[1].FailedAt = nil
+		e.Tests[3].TestStatus = epic.TestStatusWIP
+
+		report := Statuses(e)
+		assert.False(t, report.Stamped)
+		assert.Empty(t, e.StatusModel)
+
+		var ambiguous []string
+		for _, ambiguity := range report.Ambiguities {
+			ambiguous = append(ambiguous, ambiguity.EntityID+" "+ambiguity.Field+"="+ambiguity.Value)
+		}
+		assert.Equal(t, []string{
+			"P2 status=cancelled",
+			"T2 status=on_hold",
+			"T1_1 status=flaky",
+			"T2_1 status=failed",
+			"T2_3 status=completed",
+		}, ambiguous)
+		assert.Contains(t, report.Ambiguities[4].Reason, "status and test_status (wip) disagree")
+
+		assert.Equal(t, epic.StatusOnHold, e.Tasks[1].Status)
+		assert.Equal(t, epic.Status("failed"), e.Tests[1].Status)
+		// Unambiguous entities are still migrated
+		assert.Equal(t, epic.StatusWIP, e.Status)
+	})
+}
+
+func TestInspect(t *testing.T) {
+	e := legacyEpic()
+
+	report := Inspect(e)
+	require.NotEmpty(t, report.Changes)
+	assert.Equal(t, epic.Status("active"), e.Status, "Inspect leaves the epic unchanged")
+	assert.Equal(t, epic.Status("passed"), e.Tests[0].Status)
+	assert.Empty(t, e.StatusModel)
+}
+
+func TestDeprecations(t *testing.T) {
+	e := legacyEpic()
+	e.Tasks[1].Status = epic.StatusOnHold
+
+	var got []string
+	for _, deprecation := range Deprecations(e) {
+		got = append(got, deprecation.EntityID+" "+deprecation.Value+" -> "+deprecation.Replacement)
+	}
+	assert.Equal(t, []string{
+		"8 active -> wip",
+		"P1 done -> completed",
+		"P2 planning -> pending",
+		"T3 canceled -> cancelled",
+		"T1_1 passed -> completed",
+		"T2_1 failed -> wip",
+		"T2 on_hold -> ",
+	}, got, "tests that only lack a test_status are not deprecated")
+	assert.Equal(t, epic.Status("active"), e.Status)
+
+	assert.Empty(t, Deprecations(&epic.Epic{
+		ID:     "9",
+		Status: epic.StatusWIP,
+		Tests:  []epic.Test{{ID: "T1_1", Status: epic.StatusPending}},
+	}))
+}
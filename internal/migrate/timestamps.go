@@ -0,0 +1,113 @@
+package migrate
+
+import (
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/service"
+)
+
+// Backfill is a missing timestamp reconstructed from the event log
+type Backfill struct {
+	EntityType string    `json:"entity_type"` // "phase", "task" or "test"
+	EntityID   string    `json:"entity_id"`
+	Field      string    `json:"field"` // "started_at", "completed_at", "passed_at", ...
+	Value      time.Time `json:"value"`
+	EventID    string    `json:"event_id"`
+}
+
+// Gap is a timestamp an entity's status calls for that neither the entity nor
+// the event log has
+type Gap struct {
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+	Field      string `json:"field"`
+	Status     string `json:"status"`
+}
+
+// TimestampReport lists the timestamps backfilled and those still missing
+type TimestampReport struct {
+	EpicID     string     `json:"epic_id"`
+	Backfilled []Backfill `json:"backfilled"`
+	Missing    []Gap      `json:"missing"`
+}
+
+// timestampSlot is a timestamp field of an entity together with the event that records it
+type timestampSlot struct {
+	field     string
+	eventType service.EventType
+	value     **time.Time
+}
+
+// Timestamps finds the timestamps that completed, started or cancelled phases,
+// tasks and tests lack. With fromEvents, each one is set from the latest event
+// that recorded it; the rest are reported as missing.
+func Timestamps(e *epic.Epic, fromEvents bool) *TimestampReport {
+	report := &TimestampReport{EpicID: e.ID}
+
+	latest := make(map[string]epic.Event)
+	if fromEvents {
+		for _, event := range e.Events {
+			key := event.Type + ":" + service.EventEntityID(event)
+			if previous, ok := latest[key]; !ok || !event.Timestamp.Before(previous.Timestamp) {
+				latest[key] = event
+			}
+		}
+	}
+
+	fill := func(entityType, id string, status string, slots []timestampSlot) {
+		for _, slot := range slots {
+			if *slot.value != nil {
+				continue
+			}
+			event, ok := latest[string(slot.eventType)+":"+id]
+			if !ok {
+				report.Missing = append(report.Missing, Gap{EntityType: entityType, EntityID: id, Field: slot.field, Status: status})
+				continue
+			}
+			value := event.Timestamp
+			*slot.value = &value
+			report.Backfilled = append(report.Backfilled, Backfill{EntityType: entityType, EntityID: id, Field: slot.field, Value: value, EventID: event.ID})
+		}
+	}
+
+	for i := range e.Phases {
+		phase := &e.Phases[i]
+		started := timestampSlot{"started_at", service.EventPhaseStarted, &phase.StartedAt}
+		switch phase.Status {
+		case epic.StatusWIP:
+			fill("phase", phase.ID, string(phase.Status), []timestampSlot{started})
+		case epic.StatusCompleted:
+			fill("phase", phase.ID, string(phase.Status), []timestampSlot{started, {"completed_at", service.EventPhaseCompleted, &phase.CompletedAt}})
+		}
+	}
+
+	for i := range e.Tasks {
+		task := &e.Tasks[i]
+		started := timestampSlot{"started_at", service.EventTaskStarted, &task.StartedAt}
+		switch task.Status {
+		case epic.StatusWIP:
+			fill("task", task.ID, string(task.Status), []timestampSlot{started})
+		case epic.StatusCompleted:
+			fill("task", task.ID, string(task.Status), []timestampSlot{started, {"completed_at", service.EventTaskCompleted, &task.CompletedAt}})
+		case epic.StatusCancelled:
+			fill("task", task.ID, string(task.Status), []timestampSlot{{"cancelled_at", service.EventTaskCancelled, &task.CancelledAt}})
+		}
+	}
+
+	for i := range e.Tests {
+		test := &e.Tests[i]
+		status := test.GetTestStatusUnified()
+		started := timestampSlot{"started_at", service.EventTestStarted, &test.StartedAt}
+		switch status {
+		case epic.TestStatusWIP:
+			fill("test", test.ID, string(status), []timestampSlot{started})
+		case epic.TestStatusDone:
+			fill("test", test.ID, string(status), []timestampSlot{started, {"passed_at", service.EventTestPassed, &test.PassedAt}})
+		case epic.TestStatusCancelled:
+			fill("test", test.ID, string(status), []timestampSlot{{"cancelled_at", service.EventTestCancelled, &test.CancelledAt}})
+		}
+	}
+
+	return report
+}
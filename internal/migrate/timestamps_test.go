@@ -0,0 +1,68 @@
+package migrate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestamps(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2025, 8, 16, hour, 0, 0, 0, time.UTC) }
+	started := at(8)
+	newEpic := func() *epic.Epic {
+		return &epic.Epic{
+			ID: "8",
+			Phases: []epic.Phase{
+				{ID: "P1", Status: epic.StatusCompleted, StartedAt: &started},
+				{ID: "P2", Status: epic.StatusPending},
+			},
+			Tasks: []epic.Task{
+				{ID: "T1", PhaseID: "P1", Name: "Schema", Status: epic.StatusCompleted},
+				{ID: "T2", PhaseID: "P1", Status: epic.StatusCancelled},
+			},
+			Tests: []epic.Test{
+				{ID: "T1_1", TaskID: "T1", Status: epic.StatusCompleted, TestStatus: epic.TestStatusDone},
+			},
+			Events: []epic.Event{
+				{ID: "e1", Type: "task_started", Timestamp: at(9), Data: "Task T1 (Schema) started"},
+				{ID: "e2", Type: "task_started", Timestamp: at(10), Data: "Task T1 (Schema) started"},
+				{ID: "e3", Type: "task_completed", Timestamp: at(11), Data: "Task T1 (Schema) completed"},
+				{ID: "e4", Type: "test_passed", Timestamp: at(12), Data: "Test T1_1 passed"},
+			},
+		}
+	}
+
+	t.Run("lists missing timestamps without a source", func(t *testing.T) {
+		e := newEpic()
+		report := Timestamps(e, false)
+		assert.Empty(t, report.Backfilled)
+		assert.Len(t, report.Missing, 6)
+		assert.Nil(t, e.Tasks[0].StartedAt)
+	})
+
+	t.Run("backfills from the latest event and reports the rest", func(t *testing.T) {
+		e := newEpic()
+		report := Timestamps(e, true)
+
+		assert.Equal(t, []Backfill{
+			{EntityType: "task", EntityID: "T1", Field: "started_at", Value: at(10), EventID: "e2"},
+			{EntityType: "task", EntityID: "T1", Field: "completed_at", Value: at(11), EventID: "e3"},
+			{EntityType: "test", EntityID: "T1_1", Field: "passed_at", Value: at(12), EventID: "e4"},
+		}, report.Backfilled)
+		assert.Equal(t, []Gap{
+			{EntityType: "phase", EntityID: "P1", Field: "completed_at", Status: "completed"},
+			{EntityType: "task", EntityID: "T2", Field: "cancelled_at", Status: "cancelled"},
+			{EntityType: "test", EntityID: "T1_1", Field: "started_at", Status: "done"},
+		}, report.Missing)
+
+		require.NotNil(t, e.Tasks[0].StartedAt)
+		assert.Equal(t, at(10), *e.Tasks[0].StartedAt)
+		assert.Equal(t, started, *e.Phases[0].StartedAt, "existing timestamps are kept")
+
+		again := Timestamps(e, true)
+		assert.Empty(t, again.Backfilled, "backfilling is idempotent")
+	})
+}
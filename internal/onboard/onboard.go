@@ -0,0 +1,285 @@
+// Package onboard builds the orientation an agent gets when it joins a
+// project: how the workflow is configured, which gates completions have to
+// pass, how IDs are named, what the current epic is about, where its specs
+// are and the command to run first. Everything is derived from the config,
+// the policy file and the epic, so orchestration prompts need not repeat it.
+package onboard
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/policy"
+	"github.com/mindreframer/agentpm/internal/spec"
+)
+
+// maxExamples is how many IDs of each kind illustrate the naming of the epic
+const maxExamples = 3
+
+// Guide is the orientation for an agent starting on the current epic
+type Guide struct {
+	Project      string    `json:"project,omitempty"`
+	EpicFile     string    `json:"epic_file"`
+	Epic         EpicGoals `json:"epic"`
+	Settings     []Setting `json:"settings"`
+	Gates        []string  `json:"gates"`
+	Naming       []string  `json:"naming"`
+	Specs        []Spec    `json:"specs"`
+	FirstCommand Step      `json:"first_command"`
+}
+
+// EpicGoals is what the epic is about and how far it got
+type EpicGoals struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Status         string `json:"status"`
+	Description    string `json:"description,omitempty"`
+	Requirements   string `json:"requirements,omitempty"`
+	Workflow       string `json:"workflow,omitempty"`
+	Phases         int    `json:"phases"`
+	Tasks          int    `json:"tasks"`
+	CompletedTasks int    `json:"completed_tasks"`
+	Tests          int    `json:"tests"`
+	OpenBlockers   int    `json:"open_blockers"`
+	CurrentPhase   string `json:"current_phase,omitempty"`
+	CurrentTask    string `json:"current_task,omitempty"`
+}
+
+// Setting is a config value that shapes the workflow, with what it is for
+type Setting struct {
+	Key        string `json:"key"`
+	Value      string `json:"value"`
+	Meaning    string `json:"meaning"`
+	Overridden bool   `json:"overridden,omitempty"` // set by the epic's sidecar config
+}
+
+// Spec is a spec document referenced by phases and tasks of the epic
+type Spec struct {
+	Path string `json:"path"`
+	Refs int    `json:"refs"`
+}
+
+// Step is a command to run and why
+type Step struct {
+	Command string `json:"command"`
+	Reason  string `json:"reason"`
+}
+
+// Build derives the guide from the effective config (nil without a config
+// file), the policy (nil without a policy file) and the epic loaded from epicFile
+func Build(cfg *config.Config, p *policy.Policy, e *epic.Epic, epicFile string) *Guide {
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	return &Guide{
+		Project:      cfg.ProjectName,
+		EpicFile:     epicFile,
+		Epic:         goals(e),
+		Settings:     settings(cfg),
+		Gates:        gates(cfg, p, e),
+		Naming:       naming(e),
+		Specs:        specs(e, filepath.Dir(epicFile)),
+		FirstCommand: FirstCommand(e),
+	}
+}
+
+func goals(e *epic.Epic) EpicGoals {
+	g := EpicGoals{
+		ID:           e.ID,
+		Name:         e.Name,
+		Status:       string(e.Status),
+		Description:  strings.TrimSpace(e.Description),
+		Requirements: strings.TrimSpace(e.Requirements),
+		Workflow:     strings.TrimSpace(e.Workflow),
+		Phases:       len(e.Phases),
+		Tasks:        len(e.Tasks),
+		Tests:        len(e.Tests),
+		OpenBlockers: len(e.OpenBlockers()),
+	}
+	for _, task := range e.Tasks {
+		if task.Status == epic.StatusCompleted {
+			g.CompletedTasks++
+		}
+	}
+	for _, phase := range e.Phases {
+		if phase.Status == epic.StatusWIP {
+			g.CurrentPhase = phase.ID
+			break
+		}
+	}
+	if task := e.ActiveTask(""); task != nil {
+		g.CurrentTask = task.ID
+	}
+	return g
+}
+
+func settings(cfg *config.Config) []Setting {
+	orDefault := func(value, fallback string) string {
+		if value == "" {
+			return fallback + " (default)"
+		}
+		return value
+	}
+	values := []struct{ key, value string }{
+		{"test_gating", orDefault(cfg.TestGating, config.TestGatingStrict)},
+		{"strict", strconv.FormatBool(cfg.Strict)},
+		{"gate_tags", orDefault(cfg.GateTags, "all tests")},
+		{"default_assignee", orDefault(cfg.DefaultAssignee, "none")},
+	}
+
+	var result []Setting
+	for _, v := range values {
+		result = append(result, Setting{
+			Key:        v.key,
+			Value:      v.value,
+			Meaning:    config.Describe(v.key),
+			Overridden: cfg.IsOverridden(v.key),
+		})
+	}
+	return result
+}
+
+// gates lists what completions and mutations of the epic have to get past
+func gates(cfg *config.Config, p *policy.Policy, e *epic.Epic) []string {
+	var result []string
+	switch tags := cfg.GateTagList(); {
+	case cfg.TestGating == config.TestGatingOff:
+		result = append(result, "Test gating is off: tests never hold up the completion of their phase")
+	case cfg.TestGating == config.TestGatingLenient:
+		result = append(result, "Test gating is lenient: only failing tests hold up the completion of their phase")
+	case len(tags) > 0:
+		result = append(result, fmt.Sprintf("Tests tagged %s, and untagged tests, must pass or be cancelled before their phase can be completed", strings.Join(tags, ", ")))
+	default:
+		result = append(result, "Every test of a phase must pass or be cancelled before the phase can be completed")
+	}
+	if cfg.Strict {
+		result = append(result, "Strict mode: 'agentpm done task' refuses a task without tests, with tests that are not passing, or with unchecked acceptance criteria")
+	}
+
+	var manual []string
+	for _, test := range e.Tests {
+		if test.IsManual() && test.Status != epic.StatusCompleted && test.Status != epic.StatusCancelled {
+			manual = append(manual, test.ID)
+		}
+	}
+	if len(manual) > 0 {
+		result = append(result, fmt.Sprintf("Manual tests %s need a person: they only pass or fail with --verified-by <name>", strings.Join(manual, ", ")))
+	}
+
+	if p != nil {
+		for _, rule := range p.Rules {
+			if rule.Severity == policy.SeverityOff {
+				continue
+			}
+			severity := rule.Severity
+			if severity == "" {
+				severity = policy.SeverityError
+			}
+			description := rule.Description
+			if description == "" {
+				description = rule.Check
+			}
+			result = append(result, fmt.Sprintf("Policy rule %s (%s): %s", rule.ID, severity, description))
+		}
+	}
+
+	for _, phase := range e.Phases {
+		if !phase.IsFrozen() {
+			continue
+		}
+		frozen := fmt.Sprintf("Phase %s is frozen: its tasks and tests cannot change", phase.ID)
+		if phase.FrozenReason != "" {
+			frozen += " (" + phase.FrozenReason + ")"
+		}
+		result = append(result, frozen)
+	}
+	return result
+}
+
+// naming describes the ID conventions of the epic by example
+func naming(e *epic.Epic) []string {
+	result := []string{}
+	examples := func(kind string, ids []string) {
+		if len(ids) == 0 {
+			return
+		}
+		result = append(result, fmt.Sprintf("%s IDs like %s", kind, strings.Join(ids[:min(len(ids), maxExamples)], ", ")))
+	}
+
+	var phaseIDs, taskIDs, testIDs []string
+	for _, phase := range e.Phases {
+		phaseIDs = append(phaseIDs, phase.ID)
+	}
+	prefixed := len(e.Tasks) > 0
+	for _, task := range e.Tasks {
+		taskIDs = append(taskIDs, task.ID)
+		if !strings.HasPrefix(task.ID, task.PhaseID) {
+			prefixed = false
+		}
+	}
+	for _, test := range e.Tests {
+		testIDs = append(testIDs, test.ID)
+	}
+
+	examples("Phase", phaseIDs)
+	examples("Task", taskIDs)
+	if prefixed {
+		result = append(result, "Task IDs start with the ID of their phase")
+	}
+	examples("Test", testIDs)
+	return result
+}
+
+// specs lists the spec documents the phases and tasks reference, in order
+// of first reference; relative paths are resolved against baseDir
+func specs(e *epic.Epic, baseDir string) []Spec {
+	result := []Spec{}
+	index := make(map[string]int)
+	for _, ref := range spec.Refs(e) {
+		file, _ := spec.ParseRef(ref.Ref)
+		if file == "" {
+			continue
+		}
+		path := file
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(baseDir, path)
+		}
+		if i, ok := index[path]; ok {
+			result[i].Refs++
+			continue
+		}
+		index[path] = len(result)
+		result = append(result, Spec{Path: path, Refs: 1})
+	}
+	return result
+}
+
+// FirstCommand is the command an agent joining the epic should run first
+func FirstCommand(e *epic.Epic) Step {
+	switch e.Status {
+	case epic.StatusPending, "":
+		return Step{Command: "agentpm start epic", Reason: "The epic has not been started yet"}
+	case epic.StatusCompleted, epic.StatusCancelled:
+		return Step{Command: "agentpm status", Reason: fmt.Sprintf("The epic is %s; review its status before changing anything", e.Status)}
+	}
+
+	task := e.ActiveTask("")
+	if task == nil {
+		return Step{Command: "agentpm start-next", Reason: "No task is in progress; this starts the next one"}
+	}
+	for _, blocker := range e.OpenBlockers() {
+		if blocker.Entity == task.ID || blocker.Entity == task.PhaseID {
+			return Step{Command: "agentpm blocker list", Reason: fmt.Sprintf("Task %s is in progress but held up by blocker %s", task.ID, blocker.ID)}
+		}
+	}
+	for _, test := range e.Tests {
+		if test.TaskID == task.ID && test.GetTestResult() == epic.TestResultFailing {
+			return Step{Command: "agentpm failing", Reason: fmt.Sprintf("Task %s is in progress and test %s is failing", task.ID, test.ID)}
+		}
+	}
+	return Step{Command: "agentpm show task " + task.ID, Reason: fmt.Sprintf("Task %s is in progress; read it and its tests before continuing", task.ID)}
+}
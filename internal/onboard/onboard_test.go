@@ -0,0 +1,106 @@
+package onboard
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/config"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createOnboardEpic() *epic.Epic {
+	frozenAt := time.Date(2025, 8, 10, 9, 0, 0, 0, time.UTC)
+	return &epic.Epic{
+		ID:           "epic-8",
+		Name:         "Billing",
+		Status:       epic.StatusWIP,
+		Description:  "  Invoices for every customer  ",
+		Requirements: "PDF export",
+		Phases: []epic.Phase{
+			{ID: "1A", Name: "Setup", Status: epic.StatusCompleted, SpecRef: "docs/spec.md#setup", FrozenAt: &frozenAt, FrozenReason: "released"},
+			{ID: "1B", Name: "Invoices", Status: epic.StatusWIP, SpecRef: "docs/spec.md#invoices"},
+		},
+		Tasks: []epic.Task{
+			{ID: "1A_1", PhaseID: "1A", Name: "Schema", Status: epic.StatusCompleted},
+			{ID: "1B_1", PhaseID: "1B", Name: "Layout", Status: epic.StatusWIP, SpecRef: "/abs/design.md"},
+		},
+		Tests: []epic.Test{
+			{ID: "T1B_1", TaskID: "1B_1", PhaseID: "1B", Name: "Layout test", Status: epic.StatusPending},
+			{ID: "T1B_2", TaskID: "1B_1", PhaseID: "1B", Name: "Visual review", Status: epic.StatusPending, Type: epic.TestTypeManual},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ProjectName = "Shop"
+	cfg.Strict = true
+	cfg.GateTags = "unit, integration"
+	p, err := policy.Parse([]byte("rules:\n  - id: tests-per-task\n    check: task_min_tests\n    description: Every task has a test\n  - id: wip\n    check: task_max_wip\n    max: 3d\n    severity: off\n"))
+	require.NoError(t, err)
+
+	guide := Build(cfg, p, createOnboardEpic(), filepath.Join("epics", "epic-8.xml"))
+
+	assert.Equal(t, "Shop", guide.Project)
+	assert.Equal(t, EpicGoals{
+		ID: "epic-8", Name: "Billing", Status: "wip",
+		Description: "Invoices for every customer", Requirements: "PDF export",
+		Phases: 2, Tasks: 2, CompletedTasks: 1, Tests: 2,
+		CurrentPhase: "1B", CurrentTask: "1B_1",
+	}, guide.Epic)
+
+	require.Len(t, guide.Settings, 4)
+	assert.Equal(t, "test_gating", guide.Settings[0].Key)
+	assert.Equal(t, "strict (default)", guide.Settings[0].Value)
+	assert.Equal(t, "true", guide.Settings[1].Value)
+
+	assert.Equal(t, []string{
+		"Tests tagged unit, integration, and untagged tests, must pass or be cancelled before their phase can be completed",
+		"Strict mode: 'agentpm done task' refuses a task without tests, with tests that are not passing, or with unchecked acceptance criteria",
+		"Manual tests T1B_2 need a person: they only pass or fail with --verified-by <name>",
+		"Policy rule tests-per-task (error): Every task has a test",
+		"Phase 1A is frozen: its tasks and tests cannot change (released)",
+	}, guide.Gates)
+
+	assert.Equal(t, []string{
+		"Phase IDs like 1A, 1B",
+		"Task IDs like 1A_1, 1B_1",
+		"Task IDs start with the ID of their phase",
+		"Test IDs like T1B_1, T1B_2",
+	}, guide.Naming)
+
+	assert.Equal(t, []Spec{
+		{Path: filepath.Join("epics", "docs", "spec.md"), Refs: 2},
+		{Path: "/abs/design.md", Refs: 1},
+	}, guide.Specs)
+}
+
+func TestFirstCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(e *epic.Epic)
+		command string
+	}{
+		{"pending epic", func(e *epic.Epic) { e.Status = epic.StatusPending }, "agentpm start epic"},
+		{"completed epic", func(e *epic.Epic) { e.Status = epic.StatusCompleted }, "agentpm status"},
+		{"no active task", func(e *epic.Epic) { e.Tasks[1].Status = epic.StatusCompleted }, "agentpm start-next"},
+		{"blocked task", func(e *epic.Epic) {
+			e.Blockers = []epic.Blocker{{ID: "B1", Entity: "1B_1", Description: "Waiting for the logo"}}
+		}, "agentpm blocker list"},
+		{"failing test", func(e *epic.Epic) { e.Tests[0].TestResult = epic.TestResultFailing }, "agentpm failing"},
+		{"active task", func(e *epic.Epic) {}, "agentpm show task 1B_1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := createOnboardEpic()
+			tt.change(e)
+			step := FirstCommand(e)
+			assert.Equal(t, tt.command, step.Command)
+			assert.NotEmpty(t, step.Reason)
+		})
+	}
+}
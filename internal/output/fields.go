@@ -0,0 +1,245 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/beevik/etree"
+)
+
+// ParseFields splits a comma separated --fields value into dotted field paths
+func ParseFields(value string) []string {
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// Project keeps only the requested fields of a rendered JSON, JSON Lines or XML document.
+// Fields are dotted paths from the top-level object (JSON, each JSONL line) or the root element (XML);
+// arrays and repeated elements are traversed, so "events.type" selects the type of
+// every event. Selecting a field keeps its complete value.
+func Project(format string, data []byte, fields []string) ([]byte, error) {
+	paths := splitPaths(fields)
+	if len(paths) == 0 {
+		return data, nil
+	}
+
+	switch format {
+	case "json":
+		return projectJSON(data, paths)
+	case "xml":
+		return projectXML(data, paths)
+	case "jsonl":
+		var buf bytes.Buffer
+		projector := NewJSONLProjector(&buf, fields)
+		if _, err := projector.Write(data); err != nil {
+			return nil, err
+		}
+		if err := projector.Flush(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("--fields is only supported for json, jsonl and xml output, not %s", format)
+	}
+}
+
+func splitPaths(fields []string) [][]string {
+	var paths [][]string
+	for _, field := range fields {
+		paths = append(paths, strings.Split(field, "."))
+	}
+	return paths
+}
+
+// groupPaths groups paths by their first segment in order of first appearance.
+// A nil tail list means the whole value is selected.
+func groupPaths(paths [][]string) ([]string, map[string][][]string) {
+	var order []string
+	tails := make(map[string][][]string)
+	whole := make(map[string]bool)
+
+	for _, path := range paths {
+		head := path[0]
+		if _, seen := tails[head]; !seen && !whole[head] {
+			order = append(order, head)
+			tails[head] = [][]string{}
+		}
+		if len(path) == 1 {
+			whole[head] = true
+		}
+		if !whole[head] {
+			tails[head] = append(tails[head], path[1:])
+		}
+	}
+
+	for head := range whole {
+		tails[head] = nil
+	}
+	return order, tails
+}
+
+// orderedObject is a JSON object that keeps its keys in the requested field order
+type orderedObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (o *orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		keyData, err := marshalJSON(key, "")
+		if err != nil {
+			return nil, err
+		}
+		valueData, err := marshalJSON(o.values[key], "")
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(keyData)
+		buf.WriteByte(':')
+		buf.Write(valueData)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func projectJSON(data []byte, paths [][]string) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("cannot apply --fields: output is not valid JSON: %w", err)
+	}
+
+	projected, _ := projectJSONValue(value, paths)
+	if projected == nil {
+		projected = &orderedObject{values: map[string]interface{}{}}
+	}
+
+	result, err := marshalJSON(projected, "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal projected JSON: %w", err)
+	}
+	return append(result, '\n'), nil
+}
+
+// marshalJSON encodes like the hand written outputs do: without HTML escaping
+func marshalJSON(value interface{}, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if indent != "" {
+		encoder.SetIndent("", indent)
+	}
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func projectJSONValue(value interface{}, paths [][]string) (interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		order, tails := groupPaths(paths)
+		obj := &orderedObject{values: make(map[string]interface{})}
+		for _, key := range order {
+			child, ok := v[key]
+			if !ok {
+				continue
+			}
+			if tails[key] == nil {
+				obj.keys = append(obj.keys, key)
+				obj.values[key] = child
+				continue
+			}
+			if projected, ok := projectJSONValue(child, tails[key]); ok {
+				obj.keys = append(obj.keys, key)
+				obj.values[key] = projected
+			}
+		}
+		return obj, len(obj.keys) > 0
+	case []interface{}:
+		// Objects are kept even without matching fields, so the array keeps its length
+		items := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			if projected, _ := projectJSONValue(item, paths); projected != nil {
+				items = append(items, projected)
+			}
+		}
+		return items, true
+	default:
+		// A scalar has no fields to select below it
+		return nil, false
+	}
+}
+
+func projectXML(data []byte, paths [][]string) ([]byte, error) {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(data); err != nil {
+		return nil, fmt.Errorf("cannot apply --fields: output is not valid XML: %w", err)
+	}
+
+	root := doc.Root()
+	if root == nil {
+		return nil, fmt.Errorf("cannot apply --fields: output has no XML root element")
+	}
+
+	result := etree.NewDocument()
+	for _, token := range doc.Child {
+		if procInst, ok := token.(*etree.ProcInst); ok {
+			result.CreateProcInst(procInst.Target, procInst.Inst)
+		}
+	}
+	result.SetRoot(projectXMLElement(root, paths))
+	result.Indent(4)
+
+	var buf bytes.Buffer
+	if _, err := result.WriteTo(&buf); err != nil {
+		return nil, fmt.Errorf("failed to write projected XML: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// projectXMLElement keeps the selected attributes and child elements. Selected
+// elements are always kept, even when none of their fields match, so repeated
+// elements keep their count.
+func projectXMLElement(elem *etree.Element, paths [][]string) *etree.Element {
+	order, tails := groupPaths(paths)
+	projected := etree.NewElement(elem.Tag)
+	projected.Space = elem.Space
+
+	for _, name := range order {
+		if tails[name] == nil {
+			if attr := elem.SelectAttr(name); attr != nil {
+				projected.CreateAttr(attr.FullKey(), attr.Value)
+			}
+		}
+	}
+
+	for _, child := range elem.ChildElements() {
+		childTails, ok := tails[child.Tag]
+		if !ok {
+			continue
+		}
+		if childTails == nil {
+			projected.AddChild(child.Copy())
+		} else {
+			projected.AddChild(projectXMLElement(child, childTails))
+		}
+	}
+
+	return projected
+}
@@ -0,0 +1,111 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFields(t *testing.T) {
+	assert.Equal(t, []string{"id", "progress.completion_percentage"}, ParseFields(" id, progress.completion_percentage ,"))
+	assert.Nil(t, ParseFields(""))
+}
+
+func TestProjectJSON(t *testing.T) {
+	data := []byte(`{
+  "epic": "8",
+  "name": "Epic <Name>",
+  "progress": {"completion_percentage": 40, "total_phases": 5},
+  "events": [
+    {"type": "task_started", "content": "Started"},
+    {"type": "task_completed", "content": "Done"}
+  ]
+}`)
+
+	t.Run("keeps requested fields in requested order", func(t *testing.T) {
+		result, err := Project("json", data, []string{"progress.completion_percentage", "name"})
+		require.NoError(t, err)
+		assert.Equal(t, `{
+  "progress": {
+    "completion_percentage": 40
+  },
+  "name": "Epic <Name>"
+}
+`, string(result))
+	})
+
+	t.Run("traverses arrays", func(t *testing.T) {
+		result, err := Project("json", data, []string{"events.type"})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"events": [{"type": "task_started"}, {"type": "task_completed"}]}`, string(result))
+	})
+
+	t.Run("selecting a parent keeps the whole value", func(t *testing.T) {
+		result, err := Project("json", data, []string{"progress", "progress.total_phases"})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"progress": {"completion_percentage": 40, "total_phases": 5}}`, string(result))
+	})
+
+	t.Run("unknown fields are omitted", func(t *testing.T) {
+		result, err := Project("json", data, []string{"missing", "name.first"})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{}`, string(result))
+	})
+
+	t.Run("invalid JSON is reported", func(t *testing.T) {
+		_, err := Project("json", []byte(`{"name": "unterminated`), []string{"name"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "output is not valid JSON")
+	})
+}
+
+func TestProjectXML(t *testing.T) {
+	data := []byte(`<events limit="10" total="2">
+    <event timestamp="2025-08-16T09:00:00Z" type="task_started">
+        <content>Started</content>
+    </event>
+    <event timestamp="2025-08-16T10:00:00Z" type="task_completed">
+        <content>Done</content>
+    </event>
+</events>
+`)
+
+	t.Run("keeps selected attributes and elements", func(t *testing.T) {
+		result, err := Project("xml", data, []string{"total", "event.type"})
+		require.NoError(t, err)
+		assert.Equal(t, `<events total="2">
+    <event type="task_started"/>
+    <event type="task_completed"/>
+</events>
+`, string(result))
+	})
+
+	t.Run("selecting an element keeps its content", func(t *testing.T) {
+		result, err := Project("xml", data, []string{"event.content"})
+		require.NoError(t, err)
+		assert.Contains(t, string(result), "<content>Started</content>")
+		assert.NotContains(t, string(result), "timestamp=")
+	})
+
+	t.Run("processing instruction is preserved", func(t *testing.T) {
+		result, err := Project("xml", []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<result><count>1</count><query>//task</query></result>`), []string{"count"})
+		require.NoError(t, err)
+		assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<result>
+    <count>1</count>
+</result>
+`, string(result))
+	})
+}
+
+func TestProjectUnsupportedFormat(t *testing.T) {
+	_, err := Project("text", []byte("Epic Status"), []string{"name"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only supported for json, jsonl and xml")
+
+	result, err := Project("text", []byte("Epic Status"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Epic Status", string(result))
+}
@@ -0,0 +1,95 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// JSONLWriter streams records as JSON Lines: one compact JSON object per line,
+// written as soon as it is produced
+type JSONLWriter struct {
+	encoder *json.Encoder
+}
+
+// NewJSONLWriter creates a JSON Lines writer on w
+func NewJSONLWriter(w io.Writer) *JSONLWriter {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return &JSONLWriter{encoder: encoder}
+}
+
+// Write encodes one record as a single line
+func (w *JSONLWriter) Write(record interface{}) error {
+	if err := w.encoder.Encode(record); err != nil {
+		return fmt.Errorf("failed to write JSONL record: %w", err)
+	}
+	return nil
+}
+
+// JSONLProjector applies --fields to a JSON Lines stream line by line, so the
+// output is still streamed instead of being collected first
+type JSONLProjector struct {
+	w       io.Writer
+	paths   [][]string
+	pending bytes.Buffer
+}
+
+// NewJSONLProjector writes the projection of every line written to it to w
+func NewJSONLProjector(w io.Writer, fields []string) *JSONLProjector {
+	return &JSONLProjector{w: w, paths: splitPaths(fields)}
+}
+
+func (p *JSONLProjector) Write(data []byte) (int, error) {
+	p.pending.Write(data)
+	for {
+		line, err := p.pending.ReadBytes('\n')
+		if err != nil {
+			// Keep the incomplete line until the rest arrives
+			rest := append([]byte(nil), line...)
+			p.pending.Reset()
+			p.pending.Write(rest)
+			return len(data), nil
+		}
+		if err := p.writeLine(line); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Flush projects a final line that was not terminated by a newline
+func (p *JSONLProjector) Flush() error {
+	if p.pending.Len() == 0 {
+		return nil
+	}
+	line := p.pending.Bytes()
+	p.pending.Reset()
+	return p.writeLine(line)
+}
+
+func (p *JSONLProjector) writeLine(line []byte) error {
+	if len(bytes.TrimSpace(line)) == 0 {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("cannot apply --fields: output line is not valid JSON: %w", err)
+	}
+
+	projected, _ := projectJSONValue(value, p.paths)
+	if projected == nil {
+		projected = &orderedObject{values: map[string]interface{}{}}
+	}
+
+	data, err := marshalJSON(projected, "")
+	if err != nil {
+		return fmt.Errorf("failed to marshal projected JSON: %w", err)
+	}
+	_, err = p.w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,58 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONLWriter(t *testing.T) {
+	var buf bytes.Buffer
+	lines := NewJSONLWriter(&buf)
+
+	require.NoError(t, lines.Write(map[string]string{"id": "T1", "name": "<Setup>"}))
+	require.NoError(t, lines.Write(struct {
+		ID string `json:"id"`
+	}{ID: "T2"}))
+
+	assert.Equal(t, "{\"id\":\"T1\",\"name\":\"<Setup>\"}\n{\"id\":\"T2\"}\n", buf.String())
+}
+
+func TestJSONLProjector(t *testing.T) {
+	t.Run("projects each line, also across split writes", func(t *testing.T) {
+		var buf bytes.Buffer
+		projector := NewJSONLProjector(&buf, []string{"id"})
+
+		_, err := projector.Write([]byte(`{"id":"T1","name":"one"}` + "\n" + `{"id":"T2",`))
+		require.NoError(t, err)
+		assert.Equal(t, "{\"id\":\"T1\"}\n", buf.String(), "incomplete line is held back")
+
+		_, err = projector.Write([]byte(`"name":"two"}` + "\n"))
+		require.NoError(t, err)
+		require.NoError(t, projector.Flush())
+
+		assert.Equal(t, "{\"id\":\"T1\"}\n{\"id\":\"T2\"}\n", buf.String())
+	})
+
+	t.Run("flush projects an unterminated last line", func(t *testing.T) {
+		var buf bytes.Buffer
+		projector := NewJSONLProjector(&buf, []string{"name"})
+
+		_, err := projector.Write([]byte(`{"id":"T1","name":"one"}`))
+		require.NoError(t, err)
+		require.NoError(t, projector.Flush())
+
+		assert.Equal(t, "{\"name\":\"one\"}\n", buf.String())
+	})
+
+	t.Run("invalid line is reported", func(t *testing.T) {
+		var buf bytes.Buffer
+		projector := NewJSONLProjector(&buf, []string{"id"})
+
+		_, err := projector.Write([]byte("not json\n"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not valid JSON")
+	})
+}
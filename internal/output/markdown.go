@@ -0,0 +1,134 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Markdown renders the markdown output format: reports that are pasted into
+// pull requests and issue trackers. Blocks are separated by blank lines;
+// consecutive list items and fields form one list.
+type Markdown struct {
+	b      strings.Builder
+	inList bool
+}
+
+// NewMarkdown creates an empty Markdown document
+func NewMarkdown() *Markdown {
+	return &Markdown{}
+}
+
+// Heading writes a heading of the given level (1 for the document title)
+func (m *Markdown) Heading(level int, text string) {
+	m.endList()
+	fmt.Fprintf(&m.b, "%s %s\n\n", strings.Repeat("#", level), Inline(text))
+}
+
+// Paragraph writes a paragraph of already formatted Markdown
+func (m *Markdown) Paragraph(format string, args ...interface{}) {
+	m.endList()
+	fmt.Fprintf(&m.b, format, args...)
+	m.b.WriteString("\n\n")
+}
+
+// Field writes a list item with a bold label, e.g. "- **Status:** wip"
+func (m *Markdown) Field(label, value string) {
+	m.Item("**%s:** %s", label, Inline(value))
+}
+
+// Item writes a list item of already formatted Markdown
+func (m *Markdown) Item(format string, args ...interface{}) {
+	m.inList = true
+	m.b.WriteString("- ")
+	fmt.Fprintf(&m.b, format, args...)
+	m.b.WriteString("\n")
+}
+
+// SubItem writes a list item nested under the previous item
+func (m *Markdown) SubItem(format string, args ...interface{}) {
+	m.b.WriteString("  ")
+	m.Item(format, args...)
+}
+
+// Table writes a table; cells are already formatted Markdown and are kept on
+// one line with pipes escaped
+func (m *Markdown) Table(headers []string, rows [][]string) {
+	m.endList()
+	m.tableRow(headers)
+	m.b.WriteString("|")
+	for _, header := range headers {
+		m.b.WriteString(strings.Repeat("-", len(header)+2) + "|")
+	}
+	m.b.WriteString("\n")
+	for _, row := range rows {
+		m.tableRow(row)
+	}
+	m.b.WriteString("\n")
+}
+
+func (m *Markdown) tableRow(cells []string) {
+	m.b.WriteString("|")
+	for _, cell := range cells {
+		fmt.Fprintf(&m.b, " %s |", TableCell(cell))
+	}
+	m.b.WriteString("\n")
+}
+
+// Rule writes a horizontal rule
+func (m *Markdown) Rule() {
+	m.endList()
+	m.b.WriteString("---\n")
+}
+
+// Raw writes already formatted Markdown as it is
+func (m *Markdown) Raw(text string) {
+	m.endList()
+	m.b.WriteString(text)
+}
+
+func (m *Markdown) endList() {
+	if m.inList {
+		m.b.WriteString("\n")
+		m.inList = false
+	}
+}
+
+// String returns the document, ending after its last block
+func (m *Markdown) String() string {
+	m.endList()
+	return strings.TrimRight(m.b.String(), "\n") + "\n"
+}
+
+// WriteTo writes the document to w
+func (m *Markdown) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, m.String())
+	return int64(n), err
+}
+
+// Inline keeps free text on one line, so that it cannot break the block it
+// is written into
+func Inline(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// TableCell keeps free text on one line and escapes the pipes that would
+// split its table cell
+func TableCell(text string) string {
+	return strings.ReplaceAll(Inline(text), "|", `\|`)
+}
+
+// Code formats text as inline code, e.g. an ID or a command
+func Code(text string) string {
+	if text == "" {
+		return ""
+	}
+	fence := "`"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		return fence + " " + text + " " + fence
+	}
+	return fence + text + fence
+}
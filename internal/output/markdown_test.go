@@ -0,0 +1,56 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdown(t *testing.T) {
+	md := NewMarkdown()
+	md.Heading(2, "Epic:\nPayments")
+	md.Field("Status", "wip")
+	md.Item("%s %s", Code("1A_1"), "Setup")
+	md.SubItem("%s %s", Code("1A_1a"), "Tools")
+	md.Table([]string{"Task", "Notes"}, [][]string{
+		{"1A_1", "a | b"},
+		{"1A_2", "first line\nsecond line"},
+	})
+	md.Paragraph("**Total:** %d", 2)
+	md.Rule()
+	md.Raw("*footer*\n")
+
+	assert.Equal(t, "## Epic: Payments\n\n"+
+		"- **Status:** wip\n"+
+		"- `1A_1` Setup\n"+
+		"  - `1A_1a` Tools\n"+
+		"\n"+
+		"| Task | Notes |\n"+
+		"|------|-------|\n"+
+		"| 1A_1 | a \\| b |\n"+
+		"| 1A_2 | first line second line |\n"+
+		"\n"+
+		"**Total:** 2\n\n"+
+		"---\n"+
+		"*footer*\n", md.String())
+
+	var buf bytes.Buffer
+	_, err := md.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, md.String(), buf.String())
+}
+
+func TestMarkdownEndsListAtDocumentEnd(t *testing.T) {
+	md := NewMarkdown()
+	md.Item("only item")
+	assert.Equal(t, "- only item\n", md.String())
+}
+
+func TestCode(t *testing.T) {
+	assert.Equal(t, "`1A_1`", Code("1A_1"))
+	assert.Equal(t, "``a`b``", Code("a`b"))
+	assert.Equal(t, "`` `x ``", Code("`x"))
+	assert.Equal(t, "", Code(""))
+}
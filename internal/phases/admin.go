@@ -0,0 +1,271 @@
+package phases
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/service"
+)
+
+const (
+	OperationResetPhase           = "reset_phase"
+	OperationCancelPendingInPhase = "cancel_pending_in_phase"
+)
+
+// PhaseBulkResult describes the entities touched by an administrative bulk operation on a phase
+type PhaseBulkResult struct {
+	PhaseID      string   `json:"phase_id"`
+	Operation    string   `json:"operation"`
+	PhaseChanged bool     `json:"phase_changed"`
+	Tasks        []string `json:"tasks"`
+	Tests        []string `json:"tests"`
+	// Stranded lists the pending tasks elsewhere that can never start once
+	// the cancelled tasks are gone; only set by the cancel operation
+	Stranded []epic.StrandedTask `json:"stranded,omitempty"`
+}
+
+// IsEmpty reports whether the operation does not change anything
+func (r *PhaseBulkResult) IsEmpty() bool {
+	return !r.PhaseChanged && len(r.Tasks) == 0 && len(r.Tests) == 0
+}
+
+// PreviewPhaseReset lists the phase, tasks and tests that ResetPhase would reset without changing the epic
+func (s *PhaseService) PreviewPhaseReset(epicData *epic.Epic, phaseID string) (*PhaseBulkResult, error) {
+	return s.resetPhase(epicData, phaseID, "", time.Time{}, false)
+}
+
+// ResetPhase moves a phase and all of its tasks and tests back to pending,
+// clearing timestamps and results and recording one event per reset entity
+func (s *PhaseService) ResetPhase(epicData *epic.Epic, phaseID, reason string, timestamp time.Time) (*PhaseBulkResult, error) {
+	return s.resetPhase(epicData, phaseID, reason, timestamp, true)
+}
+
+func (s *PhaseService) resetPhase(epicData *epic.Epic, phaseID, reason string, timestamp time.Time, apply bool) (*PhaseBulkResult, error) {
+	if err := epicData.EnsurePhaseMutable(phaseID, "reset phase "+phaseID); err != nil {
+		return nil, err
+	}
+
+	phase := s.findPhase(epicData, phaseID)
+	if phase == nil {
+		return nil, fmt.Errorf("phase %s not found", phaseID)
+	}
+
+	result := &PhaseBulkResult{
+		PhaseID:   phaseID,
+		Operation: OperationResetPhase,
+		Tasks:     []string{},
+		Tests:     []string{},
+	}
+
+	if phase.Status != epic.StatusPending || phase.StartedAt != nil || phase.CompletedAt != nil {
+		result.PhaseChanged = true
+		if apply {
+			phase.Status = epic.StatusPending
+			phase.StartedAt = nil
+			phase.CompletedAt = nil
+			service.CreateEvent(epicData, service.EventPhaseReset, phaseID, "", "", reason, timestamp)
+		}
+	}
+
+	phaseTasks := make(map[string]bool)
+	for i := range epicData.Tasks {
+		task := &epicData.Tasks[i]
+		if task.PhaseID != phaseID {
+			continue
+		}
+		phaseTasks[task.ID] = true
+
+		if task.Status == epic.StatusPending && task.StartedAt == nil && task.CompletedAt == nil && task.CancelledAt == nil {
+			continue
+		}
+		result.Tasks = append(result.Tasks, task.ID)
+		if apply {
+			task.Status = epic.StatusPending
+			task.StartedAt = nil
+			task.CompletedAt = nil
+			task.CancelledAt = nil
+			service.CreateEvent(epicData, service.EventTaskReset, phaseID, task.ID, "", reason, timestamp)
+		}
+	}
+
+	for i := range epicData.Tests {
+		test := &epicData.Tests[i]
+		if test.PhaseID != phaseID && !phaseTasks[test.TaskID] {
+			continue
+		}
+		if !s.testNeedsReset(test) {
+			continue
+		}
+		result.Tests = append(result.Tests, test.ID)
+		if apply {
+			test.SetTestStatusUnified(epic.TestStatusPending)
+			test.TestResult = ""
+			test.StartedAt = nil
+			test.PassedAt = nil
+			test.FailedAt = nil
+			test.CancelledAt = nil
+			test.FailureNote = ""
+			test.FailureContext = nil
+			test.CancellationReason = ""
+			test.VerifiedBy = ""
+			test.Evidence = ""
+			service.CreateEvent(epicData, service.EventTestReset, phaseID, test.TaskID, test.ID, reason, timestamp)
+		}
+	}
+
+	// Nothing in a reset phase can be active any more
+	if apply && epicData.CurrentState != nil {
+		if epicData.CurrentState.ActivePhase == phaseID {
+			epicData.CurrentState.ActivePhase = ""
+		}
+		if phaseTasks[epicData.CurrentState.ActiveTask] {
+			epicData.CurrentState.ActiveTask = ""
+		}
+	}
+
+	return result, nil
+}
+
+// testNeedsReset checks whether a test carries any state beyond a fresh pending test
+func (s *PhaseService) testNeedsReset(test *epic.Test) bool {
+	return test.GetTestStatusUnified() != epic.TestStatusPending ||
+		test.TestResult != "" ||
+		test.StartedAt != nil || test.PassedAt != nil || test.FailedAt != nil || test.CancelledAt != nil ||
+		test.FailureNote != "" || test.FailureContext != nil || test.CancellationReason != "" ||
+		test.VerifiedBy != "" || test.Evidence != ""
+}
+
+// PreviewCancelPendingInPhase lists the pending tasks and tests that CancelPendingInPhase would cancel
+func (s *PhaseService) PreviewCancelPendingInPhase(epicData *epic.Epic, phaseID string) (*PhaseBulkResult, error) {
+	return s.cancelPendingInPhase(epicData, phaseID, "", time.Time{}, false)
+}
+
+// CancelPendingInPhase cancels every pending task and test of a phase, recording a cancellation event for each
+func (s *PhaseService) CancelPendingInPhase(epicData *epic.Epic, phaseID, reason string, timestamp time.Time) (*PhaseBulkResult, error) {
+	return s.cancelPendingInPhase(epicData, phaseID, reason, timestamp, true)
+}
+
+func (s *PhaseService) cancelPendingInPhase(epicData *epic.Epic, phaseID, reason string, timestamp time.Time, apply bool) (*PhaseBulkResult, error) {
+	if err := epicData.EnsurePhaseMutable(phaseID, "cancel pending work in phase "+phaseID); err != nil {
+		return nil, err
+	}
+
+	if s.findPhase(epicData, phaseID) == nil {
+		return nil, fmt.Errorf("phase %s not found", phaseID)
+	}
+
+	result := &PhaseBulkResult{
+		PhaseID:   phaseID,
+		Operation: OperationCancelPendingInPhase,
+		Tasks:     []string{},
+		Tests:     []string{},
+	}
+
+	phaseTasks := make(map[string]bool)
+	for i := range epicData.Tasks {
+		task := &epicData.Tasks[i]
+		if task.PhaseID != phaseID {
+			continue
+		}
+		phaseTasks[task.ID] = true
+
+		if task.Status != epic.StatusPending {
+			continue
+		}
+		result.Tasks = append(result.Tasks, task.ID)
+		if apply {
+			task.Status = epic.StatusCancelled
+			task.CancelledAt = &timestamp
+			service.CreateEvent(epicData, service.EventTaskCancelled, phaseID, task.ID, "", reason, timestamp)
+		}
+	}
+
+	for i := range epicData.Tests {
+		test := &epicData.Tests[i]
+		if test.PhaseID != phaseID && !phaseTasks[test.TaskID] {
+			continue
+		}
+		if test.GetTestStatusUnified() != epic.TestStatusPending {
+			continue
+		}
+		result.Tests = append(result.Tests, test.ID)
+		if apply {
+			test.SetTestStatusUnified(epic.TestStatusCancelled)
+			test.CancelledAt = &timestamp
+			test.CancellationReason = reason
+			service.CreateEvent(epicData, service.EventTestCancelled, phaseID, test.TaskID, test.ID, reason, timestamp)
+		}
+	}
+
+	result.Stranded = epicData.StrandedTasks(result.Tasks)
+	return result, nil
+}
+
+// FreezePhase marks a phase immutable, e.g. once it has shipped: until it is unfrozen,
+// neither the phase nor its tasks and tests can change status. The reason is required
+// and recorded on the phase and in the event log.
+func (s *PhaseService) FreezePhase(epicData *epic.Epic, phaseID, reason string, timestamp time.Time) error {
+	if err := epicData.EnsureMutable("freeze phase " + phaseID); err != nil {
+		return err
+	}
+
+	phase := s.findPhase(epicData, phaseID)
+	if phase == nil {
+		return fmt.Errorf("phase %s not found", phaseID)
+	}
+	if phase.IsFrozen() {
+		return fmt.Errorf("phase %s is already frozen", phaseID)
+	}
+	if reason == "" {
+		return fmt.Errorf("a reason is required to freeze phase %s", phaseID)
+	}
+
+	phase.FrozenAt = &timestamp
+	phase.FrozenReason = reason
+	service.CreateEvent(epicData, service.EventPhaseFrozen, phaseID, "", "", reason, timestamp)
+	return nil
+}
+
+// UnfreezePhase makes a frozen phase mutable again
+func (s *PhaseService) UnfreezePhase(epicData *epic.Epic, phaseID, reason string, timestamp time.Time) error {
+	if err := epicData.EnsureMutable("unfreeze phase " + phaseID); err != nil {
+		return err
+	}
+
+	phase := s.findPhase(epicData, phaseID)
+	if phase == nil {
+		return fmt.Errorf("phase %s not found", phaseID)
+	}
+	if !phase.IsFrozen() {
+		return fmt.Errorf("phase %s is not frozen", phaseID)
+	}
+
+	phase.FrozenAt = nil
+	phase.FrozenReason = ""
+	service.CreateEvent(epicData, service.EventPhaseUnfrozen, phaseID, "", "", reason, timestamp)
+	return nil
+}
+
+// AssignPhase makes an agent the default assignee of a phase's tasks; tasks
+// with an assignee of their own keep it. An empty assignee removes the
+// phase's assignment.
+func (s *PhaseService) AssignPhase(epicData *epic.Epic, phaseID, assignee string, timestamp time.Time) error {
+	if err := epicData.EnsurePhaseMutable(phaseID, "assign phase "+phaseID); err != nil {
+		return err
+	}
+
+	phase := s.findPhase(epicData, phaseID)
+	if phase == nil {
+		return fmt.Errorf("phase %s not found", phaseID)
+	}
+
+	assignee = strings.TrimSpace(assignee)
+	if phase.Assignee == assignee {
+		return nil
+	}
+	phase.Assignee = assignee
+	service.CreateEvent(epicData, service.EventPhaseAssigned, phaseID, "", "", "", timestamp)
+	return nil
+}
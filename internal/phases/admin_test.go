@@ -0,0 +1,264 @@
+package phases
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/query"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createMessyPhaseEpic() *epic.Epic {
+	started := time.Date(2025, 8, 16, 10, 0, 0, 0, time.UTC)
+	return &epic.Epic{
+		ID:     "epic-1",
+		Name:   "Test Epic",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{
+			{ID: "phase-1", Name: "Phase 1", Status: epic.StatusWIP, StartedAt: &started},
+			{ID: "phase-2", Name: "Phase 2", Status: epic.StatusPending},
+		},
+		Tasks: []epic.Task{
+			{ID: "task-1", PhaseID: "phase-1", Name: "Task 1", Status: epic.StatusCompleted, StartedAt: &started, CompletedAt: &started},
+			{ID: "task-2", PhaseID: "phase-1", Name: "Task 2", Status: epic.StatusWIP, StartedAt: &started},
+			{ID: "task-3", PhaseID: "phase-1", Name: "Task 3", Status: epic.StatusPending},
+			{ID: "task-4", PhaseID: "phase-2", Name: "Task 4", Status: epic.StatusPending},
+		},
+		Tests: []epic.Test{
+			{ID: "test-1", TaskID: "task-1", PhaseID: "phase-1", Name: "Test 1", Status: epic.StatusCompleted, TestStatus: epic.TestStatusDone, TestResult: epic.TestResultPassing, StartedAt: &started, PassedAt: &started},
+			{ID: "test-2", TaskID: "task-2", PhaseID: "phase-1", Name: "Test 2", Status: epic.StatusWIP, TestStatus: epic.TestStatusWIP, FailedAt: &started, FailureNote: "broken"},
+			{ID: "test-3", TaskID: "task-3", PhaseID: "phase-1", Name: "Test 3", Status: epic.StatusPending},
+			{ID: "test-4", TaskID: "task-4", PhaseID: "phase-2", Name: "Test 4", Status: epic.StatusPending},
+		},
+		CurrentState: &epic.CurrentState{ActivePhase: "phase-1", ActiveTask: "task-2"},
+	}
+}
+
+func TestPhaseService_ResetPhase(t *testing.T) {
+	storage := storage.NewMemoryStorage()
+	queryService := query.NewQueryService(storage)
+	phaseService := NewPhaseService(storage, queryService)
+	testTime := time.Date(2025, 8, 16, 15, 30, 0, 0, time.UTC)
+
+	t.Run("preview does not modify the epic", func(t *testing.T) {
+		epicData := createMessyPhaseEpic()
+
+		result, err := phaseService.PreviewPhaseReset(epicData, "phase-1")
+		require.NoError(t, err)
+
+		assert.True(t, result.PhaseChanged)
+		assert.Equal(t, []string{"task-1", "task-2"}, result.Tasks)
+		assert.Equal(t, []string{"test-1", "test-2"}, result.Tests)
+		assert.Equal(t, epic.StatusWIP, epicData.Phases[0].Status)
+		assert.Empty(t, epicData.Events)
+	})
+
+	t.Run("reset moves phase, tasks and tests back to pending", func(t *testing.T) {
+		epicData := createMessyPhaseEpic()
+
+		result, err := phaseService.ResetPhase(epicData, "phase-1", "agent made a mess", testTime)
+		require.NoError(t, err)
+		assert.Equal(t, OperationResetPhase, result.Operation)
+
+		phase := findPhaseByID(epicData, "phase-1")
+		assert.Equal(t, epic.StatusPending, phase.Status)
+		assert.Nil(t, phase.StartedAt)
+
+		for _, task := range epicData.Tasks[:3] {
+			assert.Equal(t, epic.StatusPending, task.Status, task.ID)
+			assert.Nil(t, task.StartedAt, task.ID)
+			assert.Nil(t, task.CompletedAt, task.ID)
+		}
+		for _, test := range epicData.Tests[:3] {
+			assert.Equal(t, epic.TestStatusPending, test.GetTestStatusUnified(), test.ID)
+			assert.Equal(t, epic.TestResult(""), test.TestResult, test.ID)
+			assert.Nil(t, test.PassedAt, test.ID)
+			assert.Nil(t, test.FailedAt, test.ID)
+			assert.Empty(t, test.FailureNote, test.ID)
+		}
+
+		// Other phases are untouched
+		assert.Equal(t, epic.StatusPending, epicData.Tasks[3].Status)
+
+		// One event per reset entity
+		require.Len(t, epicData.Events, 5)
+		assert.Equal(t, "phase_reset", epicData.Events[0].Type)
+		assert.Equal(t, "Phase phase-1 (Phase 1) reset to pending: agent made a mess", epicData.Events[0].Data)
+		assert.Equal(t, "task_reset", epicData.Events[1].Type)
+		assert.Equal(t, "test_reset", epicData.Events[4].Type)
+
+		// Current state no longer points at the reset phase
+		assert.Empty(t, epicData.CurrentState.ActivePhase)
+		assert.Empty(t, epicData.CurrentState.ActiveTask)
+	})
+
+	t.Run("unknown phase", func(t *testing.T) {
+		_, err := phaseService.ResetPhase(createMessyPhaseEpic(), "missing", "", testTime)
+		assert.EqualError(t, err, "phase missing not found")
+	})
+
+	t.Run("completed epic", func(t *testing.T) {
+		epicData := createMessyPhaseEpic()
+		epicData.Status = epic.StatusCompleted
+
+		_, err := phaseService.ResetPhase(epicData, "phase-1", "", testTime)
+		assert.True(t, epic.IsEpicCompleted(err))
+		_, err = phaseService.CancelPendingInPhase(epicData, "phase-1", "", testTime)
+		assert.True(t, epic.IsEpicCompleted(err))
+		assert.Equal(t, epic.StatusWIP, epicData.Phases[0].Status)
+		assert.Empty(t, epicData.Events)
+	})
+}
+
+func TestPhaseService_CancelPendingInPhase(t *testing.T) {
+	storage := storage.NewMemoryStorage()
+	queryService := query.NewQueryService(storage)
+	phaseService := NewPhaseService(storage, queryService)
+	testTime := time.Date(2025, 8, 16, 15, 30, 0, 0, time.UTC)
+
+	epicData := createMessyPhaseEpic()
+
+	result, err := phaseService.CancelPendingInPhase(epicData, "phase-1", "descoped", testTime)
+	require.NoError(t, err)
+
+	assert.False(t, result.PhaseChanged)
+	assert.Equal(t, []string{"task-3"}, result.Tasks)
+	assert.Equal(t, []string{"test-3"}, result.Tests)
+
+	assert.Equal(t, epic.StatusCancelled, epicData.Tasks[2].Status)
+	assert.Equal(t, testTime, *epicData.Tasks[2].CancelledAt)
+	assert.Equal(t, epic.TestStatusCancelled, epicData.Tests[2].GetTestStatusUnified())
+	assert.Equal(t, "descoped", epicData.Tests[2].CancellationReason)
+
+	// Active and completed work is left alone
+	assert.Equal(t, epic.StatusWIP, epicData.Tasks[1].Status)
+	assert.Equal(t, epic.StatusCompleted, epicData.Tasks[0].Status)
+	assert.Equal(t, epic.StatusPending, epicData.Tasks[3].Status)
+
+	require.Len(t, epicData.Events, 2)
+	assert.Equal(t, "task_cancelled", epicData.Events[0].Type)
+	assert.Equal(t, "test_cancelled", epicData.Events[1].Type)
+}
+
+func TestPhaseService_CancelPendingInPhase_Stranded(t *testing.T) {
+	storage := storage.NewMemoryStorage()
+	phaseService := NewPhaseService(storage, query.NewQueryService(storage))
+
+	epicData := createMessyPhaseEpic()
+	epicData.Tasks[3].DependsOn = "task-3"
+
+	result, err := phaseService.PreviewCancelPendingInPhase(epicData, "phase-1")
+	require.NoError(t, err)
+	assert.Equal(t, []epic.StrandedTask{{ID: "task-4", PhaseID: "phase-2", Via: "task-3"}}, result.Stranded)
+
+	result, err = phaseService.ResetPhase(epicData, "phase-1", "", time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, result.Stranded, "only cancelling strands work")
+}
+
+func TestPhaseService_FreezePhase(t *testing.T) {
+	storage := storage.NewMemoryStorage()
+	queryService := query.NewQueryService(storage)
+	phaseService := NewPhaseService(storage, queryService)
+	testTime := time.Date(2025, 8, 16, 15, 30, 0, 0, time.UTC)
+
+	t.Run("freeze records reason, timestamp and event", func(t *testing.T) {
+		epicData := createMessyPhaseEpic()
+
+		require.NoError(t, phaseService.FreezePhase(epicData, "phase-1", "Shipped in v1.2", testTime))
+
+		phase := epicData.Phases[0]
+		assert.True(t, phase.IsFrozen())
+		assert.Equal(t, testTime, *phase.FrozenAt)
+		assert.Equal(t, "Shipped in v1.2", phase.FrozenReason)
+		require.Len(t, epicData.Events, 1)
+		assert.Equal(t, "phase_frozen", epicData.Events[0].Type)
+		assert.Equal(t, "Phase phase-1 (Phase 1) frozen: Shipped in v1.2", epicData.Events[0].Data)
+	})
+
+	t.Run("frozen phase rejects status changes", func(t *testing.T) {
+		epicData := createMessyPhaseEpic()
+		require.NoError(t, phaseService.FreezePhase(epicData, "phase-1", "Shipped", testTime))
+
+		err := phaseService.CompletePhase(epicData, "phase-1", testTime)
+		assert.True(t, epic.IsPhaseFrozen(err))
+
+		_, err = phaseService.ResetPhase(epicData, "phase-1", "", testTime)
+		assert.True(t, epic.IsPhaseFrozen(err))
+
+		_, err = phaseService.PreviewCancelPendingInPhase(epicData, "phase-1")
+		assert.True(t, epic.IsPhaseFrozen(err))
+
+		assert.Equal(t, epic.StatusWIP, epicData.Phases[0].Status)
+		assert.Len(t, epicData.Events, 1)
+
+		// Other phases are not affected
+		_, err = phaseService.PreviewCancelPendingInPhase(epicData, "phase-2")
+		assert.NoError(t, err)
+	})
+
+	t.Run("freeze validation", func(t *testing.T) {
+		epicData := createMessyPhaseEpic()
+
+		assert.EqualError(t, phaseService.FreezePhase(epicData, "phase-1", "", testTime), "a reason is required to freeze phase phase-1")
+		assert.EqualError(t, phaseService.FreezePhase(epicData, "missing", "Shipped", testTime), "phase missing not found")
+
+		require.NoError(t, phaseService.FreezePhase(epicData, "phase-1", "Shipped", testTime))
+		assert.EqualError(t, phaseService.FreezePhase(epicData, "phase-1", "Again", testTime), "phase phase-1 is already frozen")
+	})
+
+	t.Run("unfreeze makes the phase mutable again", func(t *testing.T) {
+		epicData := createMessyPhaseEpic()
+
+		assert.EqualError(t, phaseService.UnfreezePhase(epicData, "phase-1", "", testTime), "phase phase-1 is not frozen")
+
+		require.NoError(t, phaseService.FreezePhase(epicData, "phase-1", "Shipped", testTime))
+		require.NoError(t, phaseService.UnfreezePhase(epicData, "phase-1", "Hotfix", testTime))
+
+		assert.False(t, epicData.Phases[0].IsFrozen())
+		assert.Empty(t, epicData.Phases[0].FrozenReason)
+		assert.Equal(t, "Phase phase-1 (Phase 1) unfrozen: Hotfix", epicData.Events[1].Data)
+
+		_, err := phaseService.PreviewPhaseReset(epicData, "phase-1")
+		assert.NoError(t, err)
+	})
+}
+
+func TestPhaseService_AssignPhase(t *testing.T) {
+	storage := storage.NewMemoryStorage()
+	phaseService := NewPhaseService(storage, query.NewQueryService(storage))
+	testTime := time.Date(2025, 8, 16, 15, 30, 0, 0, time.UTC)
+
+	t.Run("assign and clear record events", func(t *testing.T) {
+		epicData := createMessyPhaseEpic()
+
+		require.NoError(t, phaseService.AssignPhase(epicData, "phase-2", " agent_b ", testTime))
+		assert.Equal(t, "agent_b", epicData.Phases[1].Assignee)
+		assert.Equal(t, "agent_b", epicData.TaskAssignee(&epicData.Tasks[3]))
+
+		// Assigning the same agent again changes nothing
+		require.NoError(t, phaseService.AssignPhase(epicData, "phase-2", "agent_b", testTime))
+
+		require.NoError(t, phaseService.AssignPhase(epicData, "phase-2", "", testTime))
+		assert.Empty(t, epicData.Phases[1].Assignee)
+
+		require.Len(t, epicData.Events, 2)
+		assert.Equal(t, "phase_assigned", epicData.Events[0].Type)
+		assert.Equal(t, "Phase phase-2 (Phase 2) assigned to agent_b", epicData.Events[0].Data)
+		assert.Equal(t, "Phase phase-2 (Phase 2) unassigned", epicData.Events[1].Data)
+	})
+
+	t.Run("frozen and unknown phases", func(t *testing.T) {
+		epicData := createMessyPhaseEpic()
+		require.NoError(t, phaseService.FreezePhase(epicData, "phase-1", "Shipped", testTime))
+
+		err := phaseService.AssignPhase(epicData, "phase-1", "agent_b", testTime)
+		assert.True(t, epic.IsPhaseFrozen(err))
+
+		err = phaseService.AssignPhase(epicData, "phase-9", "agent_b", testTime)
+		assert.EqualError(t, err, "phase phase-9 not found")
+	})
+}
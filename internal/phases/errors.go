@@ -169,3 +169,23 @@ func NewPhaseTestPrerequisiteErrorWithHint(phaseID string, prerequisiteTests []e
 		Hint:              hint,
 	}
 }
+
+// PhaseDependencyError represents attempting to start a phase whose depends_on phases are not completed
+type PhaseDependencyError struct {
+	PhaseID string
+	Unmet   []epic.Dependency
+	Hint    string // Actionable hint for completing the dependencies
+}
+
+func (e *PhaseDependencyError) Error() string {
+	return fmt.Sprintf("phase %s: cannot start with %d unmet dependencies",
+		e.PhaseID, len(e.Unmet))
+}
+
+func NewPhaseDependencyError(phaseID string, unmet []epic.Dependency) *PhaseDependencyError {
+	return &PhaseDependencyError{
+		PhaseID: phaseID,
+		Unmet:   unmet,
+		Hint:    "", // Will be populated by hint generator
+	}
+}
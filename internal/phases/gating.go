@@ -0,0 +1,42 @@
+package phases
+
+import "github.com/mindreframer/agentpm/internal/epic"
+
+// gateTags limits the tests that gate phases, see SetGateTags
+var gateTags []string
+
+// SetGateTags makes only the tests with one of the given tags gate the
+// completion of their phase (and the start of the phases after it). Untagged
+// tests always gate; without tags, every test does.
+func SetGateTags(tags []string) {
+	gateTags = tags
+}
+
+// GateTags returns the tags set with SetGateTags
+func GateTags() []string {
+	return gateTags
+}
+
+// testGating is the test gating mode of phases, see SetTestGating
+var testGating string
+
+// SetTestGating sets how strictly tests gate their phase, see epic.Test.GatesUnder
+func SetTestGating(mode string) {
+	testGating = mode
+}
+
+// GatesPhase reports whether a test has to be completed before its phase can be
+func GatesPhase(test epic.Test) bool {
+	if !test.GatesUnder(testGating) {
+		return false
+	}
+	if len(gateTags) == 0 || len(test.TagList()) == 0 {
+		return true
+	}
+	for _, tag := range gateTags {
+		if test.HasTag(tag) {
+			return true
+		}
+	}
+	return false
+}
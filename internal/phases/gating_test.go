@@ -0,0 +1,116 @@
+package phases
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/query"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gatingEpic(tests ...epic.Test) *epic.Epic {
+	return &epic.Epic{
+		ID:     "epic-1",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{
+			{ID: "1", Name: "Phase 1", Status: epic.StatusWIP},
+			{ID: "2", Name: "Phase 2", Status: epic.StatusPending},
+		},
+		Tasks: []epic.Task{{ID: "1_1", PhaseID: "1", Status: epic.StatusCompleted}},
+		Tests: tests,
+	}
+}
+
+func TestGatesPhase(t *testing.T) {
+	t.Cleanup(func() { SetGateTags(nil) })
+
+	unit := epic.Test{ID: "T1", Tags: "unit"}
+	manual := epic.Test{ID: "T2", Tags: "Manual"}
+	mixed := epic.Test{ID: "T3", Tags: "manual, integration"}
+	untagged := epic.Test{ID: "T4"}
+
+	SetGateTags(nil)
+	for _, test := range []epic.Test{unit, manual, mixed, untagged} {
+		assert.True(t, GatesPhase(test), "%s gates without gate tags", test.ID)
+	}
+
+	SetGateTags([]string{"unit", "INTEGRATION"})
+	assert.True(t, GatesPhase(unit))
+	assert.False(t, GatesPhase(manual))
+	assert.True(t, GatesPhase(mixed), "one gating tag is enough")
+	assert.True(t, GatesPhase(untagged), "untagged tests always gate")
+}
+
+func TestGatesPhase_TestGating(t *testing.T) {
+	t.Cleanup(func() {
+		SetTestGating("")
+		SetGateTags(nil)
+	})
+
+	failedAt := time.Date(2025, 8, 16, 15, 30, 0, 0, time.UTC)
+	pending := epic.Test{ID: "T1", Status: epic.StatusPending, TestStatus: epic.TestStatusPending}
+	failing := epic.Test{ID: "T2", Tags: "manual", Status: epic.StatusWIP, TestStatus: epic.TestStatusWIP, FailedAt: &failedAt}
+
+	SetTestGating("strict")
+	assert.True(t, GatesPhase(pending))
+	assert.True(t, GatesPhase(failing))
+
+	SetTestGating(epic.TestGatingLenient)
+	assert.False(t, GatesPhase(pending), "tests that are not failing do not gate")
+	assert.True(t, GatesPhase(failing))
+	SetGateTags([]string{"unit"})
+	assert.False(t, GatesPhase(failing), "gate tags still apply")
+	SetGateTags(nil)
+
+	SetTestGating(epic.TestGatingOff)
+	assert.False(t, GatesPhase(pending))
+	assert.False(t, GatesPhase(failing))
+}
+
+func TestPhaseService_CompletePhase_GateTags(t *testing.T) {
+	t.Cleanup(func() { SetGateTags(nil) })
+	memory := storage.NewMemoryStorage()
+	phaseService := NewPhaseService(memory, query.NewQueryService(memory))
+	now := time.Date(2025, 8, 16, 15, 30, 0, 0, time.UTC)
+
+	passedUnit := epic.Test{ID: "T1", TaskID: "1_1", PhaseID: "1", Tags: "unit", Status: epic.StatusCompleted, TestStatus: epic.TestStatusDone}
+	pendingManual := epic.Test{ID: "T2", TaskID: "1_1", PhaseID: "1", Tags: "manual", Status: epic.StatusPending, TestStatus: epic.TestStatusPending}
+	pendingIntegration := epic.Test{ID: "T3", TaskID: "1_1", PhaseID: "1", Tags: "integration", Status: epic.StatusWIP, TestStatus: epic.TestStatusWIP}
+
+	t.Run("every test gates without gate tags", func(t *testing.T) {
+		SetGateTags(nil)
+		epicData := gatingEpic(passedUnit, pendingManual)
+		err := phaseService.CompletePhase(epicData, "1", now)
+		var depErr *PhaseTestDependencyError
+		require.ErrorAs(t, err, &depErr)
+		assert.Equal(t, "T2", depErr.IncompleteTests[0].ID)
+	})
+
+	t.Run("tests without a gate tag do not block", func(t *testing.T) {
+		SetGateTags([]string{"unit", "integration"})
+		epicData := gatingEpic(passedUnit, pendingManual)
+		require.NoError(t, NewPhaseValidationService().ValidatePhaseCompletion(epicData, &epicData.Phases[0]))
+		require.NoError(t, phaseService.CompletePhase(epicData, "1", now))
+		assert.Equal(t, epic.StatusCompleted, epicData.Phases[0].Status)
+	})
+
+	t.Run("tests with a gate tag block", func(t *testing.T) {
+		SetGateTags([]string{"unit", "integration"})
+		epicData := gatingEpic(passedUnit, pendingManual, pendingIntegration)
+
+		err := NewPhaseValidationService().ValidatePhaseCompletion(epicData, &epicData.Phases[0])
+		var validationErr *epic.StatusValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.Len(t, validationErr.BlockingItems, 1)
+		assert.Equal(t, "T3", validationErr.BlockingItems[0].ID)
+
+		err = phaseService.CompletePhase(epicData, "1", now)
+		var depErr *PhaseTestDependencyError
+		require.ErrorAs(t, err, &depErr)
+		require.Len(t, depErr.IncompleteTests, 1)
+		assert.Equal(t, "T3", depErr.IncompleteTests[0].ID)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/policy"
 	"github.com/mindreframer/agentpm/internal/query"
 	"github.com/mindreframer/agentpm/internal/service"
 	"github.com/mindreframer/agentpm/internal/storage"
@@ -30,6 +31,10 @@ type PhaseStartResult struct {
 
 // StartPhase transitions a phase from pending to active
 func (s *PhaseService) StartPhase(epicData *epic.Epic, phaseID string, timestamp time.Time) error {
+	if err := epicData.EnsurePhaseMutable(phaseID, "start phase "+phaseID); err != nil {
+		return err
+	}
+
 	// Find the phase
 	phase := s.findPhase(epicData, phaseID)
 	if phase == nil {
@@ -46,6 +51,9 @@ func (s *PhaseService) StartPhase(epicData *epic.Epic, phaseID string, timestamp
 	if err := s.validatePhaseStart(epicData, phase); err != nil {
 		return err
 	}
+	if err := policy.Enforce(epicData, policy.ActionStartPhase, phaseID, timestamp); err != nil {
+		return err
+	}
 
 	// Transition phase status and set timestamp
 	phase.Status = epic.StatusWIP
@@ -59,6 +67,10 @@ func (s *PhaseService) StartPhase(epicData *epic.Epic, phaseID string, timestamp
 
 // CompletePhase transitions a phase from wip to done
 func (s *PhaseService) CompletePhase(epicData *epic.Epic, phaseID string, timestamp time.Time) error {
+	if err := epicData.EnsurePhaseMutable(phaseID, "complete phase "+phaseID); err != nil {
+		return err
+	}
+
 	// Find the phase
 	phase := s.findPhase(epicData, phaseID)
 	if phase == nil {
@@ -69,11 +81,24 @@ func (s *PhaseService) CompletePhase(epicData *epic.Epic, phaseID string, timest
 	if err := s.validatePhaseCompletion(epicData, phase); err != nil {
 		return err
 	}
+	if err := policy.Enforce(epicData, policy.ActionDonePhase, phaseID, timestamp); err != nil {
+		return err
+	}
 
 	// Transition phase status and set timestamp
 	phase.Status = epic.StatusCompleted
 	phase.CompletedAt = &timestamp
 
+	// Recurring tasks end with their phase
+	for i := range epicData.Tasks {
+		task := &epicData.Tasks[i]
+		if task.PhaseID == phaseID && task.IsUpcoming() {
+			task.Status = epic.StatusCancelled
+			task.CancelledAt = &timestamp
+			service.CreateEvent(epicData, service.EventTaskCancelled, phaseID, task.ID, "", "", timestamp)
+		}
+	}
+
 	// Create automatic event for phase completion
 	service.CreateEvent(epicData, service.EventPhaseCompleted, phaseID, "", "", "", timestamp)
 
@@ -118,6 +143,11 @@ func (s *PhaseService) validatePhaseStart(epicData *epic.Epic, phase *epic.Phase
 		return NewPhaseConstraintError(phase.ID, activePhase.ID, "Cannot start phase: another phase is already active")
 	}
 
+	// Check the phases it depends on are completed
+	if unmet := epicData.UnmetPhaseDependencies(phase); len(unmet) > 0 {
+		return NewPhaseDependencyError(phase.ID, unmet)
+	}
+
 	// Check prerequisite tests from earlier phases are completed
 	prerequisiteTests := s.getIncompleteTestsInEarlierPhases(epicData, phase.ID)
 	if len(prerequisiteTests) > 0 {
@@ -153,18 +183,19 @@ func (s *PhaseService) validatePhaseCompletion(epicData *epic.Epic, phase *epic.
 func (s *PhaseService) getPendingTasksInPhase(epicData *epic.Epic, phaseID string) []epic.Task {
 	var pendingTasks []epic.Task
 	for _, task := range epicData.Tasks {
-		if task.PhaseID == phaseID && task.Status != epic.StatusCompleted && task.Status != epic.StatusCancelled {
+		if task.PhaseID == phaseID && task.Status != epic.StatusCompleted && task.Status != epic.StatusCancelled && !task.IsUpcoming() {
 			pendingTasks = append(pendingTasks, task)
 		}
 	}
 	return pendingTasks
 }
 
-// getIncompleteTestsInPhase returns tests in the phase that are not passed
+// getIncompleteTestsInPhase returns tests in the phase that are not passed,
+// leaving out tests whose tags do not gate phases (see SetGateTags)
 func (s *PhaseService) getIncompleteTestsInPhase(epicData *epic.Epic, phaseID string) []epic.Test {
 	var incompleteTests []epic.Test
 	for _, test := range epicData.Tests {
-		if test.PhaseID == phaseID && !s.isTestCompleted(test) {
+		if test.PhaseID == phaseID && GatesPhase(test) && !s.isTestCompleted(test) {
 			incompleteTests = append(incompleteTests, test)
 		}
 	}
@@ -186,11 +217,13 @@ func (s *PhaseService) isTestCompleted(test epic.Test) bool {
 // getIncompleteTestsInEarlierPhases returns tests from earlier phases that are not completed
 func (s *PhaseService) getIncompleteTestsInEarlierPhases(epicData *epic.Epic, currentPhaseID string) []epic.Test {
 	var incompleteTests []epic.Test
-	currentPhaseIndex := s.getPhaseIndex(epicData, currentPhaseID)
+	currentPhase := s.findPhase(epicData, currentPhaseID)
+	if currentPhase == nil {
+		return nil
+	}
 
-	// Check all phases before the current one
-	for i := 0; i < currentPhaseIndex; i++ {
-		phaseID := epicData.Phases[i].ID
+	// Check the upstream phases: explicit dependencies, or all phases before the current one
+	for _, phaseID := range epicData.UpstreamPhaseIDs(currentPhase) {
 		phaseIncompleteTests := s.getIncompleteTestsInPhase(epicData, phaseID)
 		incompleteTests = append(incompleteTests, phaseIncompleteTests...)
 	}
@@ -198,16 +231,6 @@ func (s *PhaseService) getIncompleteTestsInEarlierPhases(epicData *epic.Epic, cu
 	return incompleteTests
 }
 
-// getPhaseIndex returns the index of a phase in the phases slice
-func (s *PhaseService) getPhaseIndex(epicData *epic.Epic, phaseID string) int {
-	for i, phase := range epicData.Phases {
-		if phase.ID == phaseID {
-			return i
-		}
-	}
-	return -1
-}
-
 // GetTestCompletionStatus returns detailed status of tests in a phase
 func (s *PhaseService) GetTestCompletionStatus(epicData *epic.Epic, phaseID string) TestCompletionStatus {
 	var totalTests, passedTests, failedTests, pendingTests int
@@ -991,3 +991,83 @@ func TestPhaseService_ErrorMessageClarity(t *testing.T) {
 		assert.Contains(t, incompleteIDs, "test-pending-2")
 	})
 }
+
+func TestPhaseService_DependsOn(t *testing.T) {
+	storage := storage.NewMemoryStorage()
+	queryService := query.NewQueryService(storage)
+	phaseService := NewPhaseService(storage, queryService)
+	testTime := time.Date(2025, 8, 16, 15, 30, 0, 0, time.UTC)
+
+	newEpic := func() *epic.Epic {
+		return &epic.Epic{
+			ID:     "epic-deps",
+			Name:   "Dependencies Epic",
+			Status: epic.StatusWIP,
+			Phases: []epic.Phase{
+				{ID: "phase-1", Name: "Phase 1", Status: epic.StatusPending},
+				{ID: "phase-2", Name: "Phase 2", Status: epic.StatusPending, DependsOn: "phase-1"},
+				{ID: "phase-3", Name: "Phase 3", Status: epic.StatusPending, DependsOn: "phase-4"},
+				{ID: "phase-4", Name: "Phase 4", Status: epic.StatusCompleted},
+			},
+			Tests: []epic.Test{
+				{ID: "test-1", PhaseID: "phase-1", Name: "Test 1", Status: epic.StatusPending, TestStatus: epic.TestStatusPending},
+			},
+		}
+	}
+
+	t.Run("start blocked until dependencies are completed", func(t *testing.T) {
+		epicData := newEpic()
+
+		err := phaseService.StartPhase(epicData, "phase-2", testTime)
+		var depErr *PhaseDependencyError
+		require.ErrorAs(t, err, &depErr)
+		assert.Equal(t, "phase-2", depErr.PhaseID)
+		assert.Equal(t, []epic.Dependency{{ID: "phase-1", Status: epic.StatusPending}}, depErr.Unmet)
+		assert.Equal(t, epic.StatusPending, findPhaseByID(epicData, "phase-2").Status)
+	})
+
+	t.Run("explicit dependencies replace document order", func(t *testing.T) {
+		epicData := newEpic()
+
+		// phase-1 comes first and has a pending test, but phase-3 only depends on phase-4
+		err := phaseService.StartPhase(epicData, "phase-3", testTime)
+		require.NoError(t, err)
+		assert.Equal(t, epic.StatusWIP, findPhaseByID(epicData, "phase-3").Status)
+	})
+}
+
+func TestPhaseService_CompletePhaseEndsRecurringTasks(t *testing.T) {
+	storage := storage.NewMemoryStorage()
+	queryService := query.NewQueryService(storage)
+	phaseService := NewPhaseService(storage, queryService)
+	testTime := time.Date(2025, 9, 16, 10, 0, 0, 0, time.UTC)
+
+	epicData := &epic.Epic{
+		ID:     "epic-recurring",
+		Name:   "Recurring Epic",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{
+			{ID: "phase-1", Name: "Phase 1", Status: epic.StatusWIP},
+		},
+		Tasks: []epic.Task{
+			{ID: "task-1", PhaseID: "phase-1", Name: "Build", Status: epic.StatusCompleted},
+			{ID: "task-2", PhaseID: "phase-1", Name: "Rotate keys", Status: epic.StatusCompleted, Recurring: "7d"},
+			{ID: "task-2_r2", PhaseID: "phase-1", Name: "Rotate keys", Status: epic.StatusPending, Recurring: "7d"},
+		},
+	}
+
+	// A recurring task in progress still has to be finished
+	epicData.Tasks[2].Status = epic.StatusWIP
+	var incompleteErr *PhaseIncompleteError
+	require.ErrorAs(t, phaseService.CompletePhase(epicData, "phase-1", testTime), &incompleteErr)
+
+	// Its next occurrence does not hold up the phase and ends with it
+	epicData.Tasks[2].Status = epic.StatusPending
+	require.NoError(t, NewPhaseValidationService().ValidatePhaseCompletion(epicData, &epicData.Phases[0]))
+	require.NoError(t, phaseService.CompletePhase(epicData, "phase-1", testTime))
+
+	assert.Equal(t, epic.StatusCompleted, epicData.Phases[0].Status)
+	assert.Equal(t, epic.StatusCancelled, epicData.Tasks[2].Status)
+	assert.Equal(t, testTime, *epicData.Tasks[2].CancelledAt)
+	assert.Equal(t, epic.StatusCompleted, epicData.Tasks[1].Status)
+}
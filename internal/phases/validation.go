@@ -32,7 +32,7 @@ func (pvs *PhaseValidationService) checkPhaseCompletionPrerequisites(epicData *e
 
 	// Collect blocking tasks
 	for _, task := range epicData.Tasks {
-		if task.PhaseID == phase.ID {
+		if task.PhaseID == phase.ID && !task.IsUpcoming() {
 			switch task.Status {
 			case epic.StatusPending:
 				blockingItems = append(blockingItems, epic.BlockingItem{
@@ -54,7 +54,7 @@ func (pvs *PhaseValidationService) checkPhaseCompletionPrerequisites(epicData *e
 
 	// Collect blocking tests
 	for _, test := range epicData.Tests {
-		if test.PhaseID == phase.ID {
+		if test.PhaseID == phase.ID && GatesPhase(test) {
 			switch test.TestStatus {
 			case epic.TestStatusPending:
 				blockingItems = append(blockingItems, epic.BlockingItem{
@@ -94,7 +94,7 @@ func (pvs *PhaseValidationService) checkPhaseCompletionPrerequisites(epicData *e
 
 func (pvs *PhaseValidationService) countTasksByStatus(epicData *epic.Epic, phaseID string) (pending int, active int) {
 	for _, task := range epicData.Tasks {
-		if task.PhaseID == phaseID {
+		if task.PhaseID == phaseID && !task.IsUpcoming() {
 			switch task.Status {
 			case epic.StatusPending:
 				pending++
@@ -108,7 +108,7 @@ func (pvs *PhaseValidationService) countTasksByStatus(epicData *epic.Epic, phase
 
 func (pvs *PhaseValidationService) countTestsByStatus(epicData *epic.Epic, phaseID string) (pending int, wip int) {
 	for _, test := range epicData.Tests {
-		if test.PhaseID == phaseID {
+		if test.PhaseID == phaseID && GatesPhase(test) {
 			switch test.TestStatus {
 			case epic.TestStatusPending:
 				pending++
@@ -0,0 +1,261 @@
+// Package planner explains why an epic has no work that can be started and
+// what unblocks it. It is shared by start-next and the hints.
+package planner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// StallReason classifies why no phase or task can be started
+type StallReason string
+
+const (
+	// StallPhaseComplete: all tasks of the active phase are done, the phase is not
+	StallPhaseComplete StallReason = "phase_complete"
+	// StallTestsBlocking: the tasks of the active phase are done, its tests are not
+	StallTestsBlocking StallReason = "tests_blocking"
+	// StallTasksBlocked: the active phase has unfinished tasks, none of them pending
+	StallTasksBlocked StallReason = "tasks_blocked"
+	// StallDependenciesUnmet: the next phase or the pending tasks wait for tests of
+	// earlier phases or for the phases and tasks they depend on
+	StallDependenciesUnmet StallReason = "dependencies_unmet"
+	// StallPhasesBlocked: no phase is pending, but not all of them are finished
+	StallPhasesBlocked StallReason = "phases_blocked"
+)
+
+// Blocker is a phase, task or test that keeps work from being started
+type Blocker struct {
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+	PhaseID    string `json:"phase_id,omitempty"`
+	Status     string `json:"status"`
+	Failing    bool   `json:"failing,omitempty"`
+}
+
+// Stall is the analysis of an epic in which nothing can be started
+type Stall struct {
+	Reason     StallReason `json:"reason"`
+	PhaseID    string      `json:"phase_id,omitempty"` // The active phase, or the next one for dependencies_unmet
+	Message    string      `json:"message"`
+	Suggestion string      `json:"suggestion"` // Command that moves the epic on
+	Blockers   []Blocker   `json:"blockers,omitempty"`
+}
+
+// AnalyzeStall explains why no phase or task of the epic can be started. It
+// returns nil when there is startable work, work in progress (an active task) or
+// nothing left to do (all phases finished).
+func AnalyzeStall(e *epic.Epic) *Stall {
+	for i := range e.Phases {
+		if e.Phases[i].Status == epic.StatusWIP {
+			return analyzeActivePhase(e, &e.Phases[i])
+		}
+	}
+
+	var waiting *epic.Phase
+	for i := range e.Phases {
+		if e.Phases[i].Status != epic.StatusPending {
+			continue
+		}
+		if len(e.UnmetPhaseDependencies(&e.Phases[i])) == 0 {
+			return analyzeNextPhase(e, &e.Phases[i])
+		}
+		if waiting == nil {
+			waiting = &e.Phases[i]
+		}
+	}
+	if waiting != nil {
+		return analyzeWaitingPhase(e, waiting)
+	}
+
+	var blockers []Blocker
+	for _, phase := range e.Phases {
+		if phase.Status != epic.StatusCompleted && phase.Status != epic.StatusCancelled {
+			blockers = append(blockers, Blocker{EntityType: "phase", EntityID: phase.ID, Status: string(phase.Status)})
+		}
+	}
+	if len(blockers) == 0 {
+		return nil
+	}
+	return &Stall{
+		Reason:     StallPhasesBlocked,
+		Message:    fmt.Sprintf("No phase is pending, but %s not finished", describe("phase", blockers)),
+		Suggestion: "agentpm show phase " + blockers[0].EntityID,
+		Blockers:   blockers,
+	}
+}
+
+func analyzeActivePhase(e *epic.Epic, phase *epic.Phase) *Stall {
+	var blockers []Blocker
+	var waiting *epic.Task
+	for i, task := range e.Tasks {
+		if task.PhaseID != phase.ID {
+			continue
+		}
+		switch task.Status {
+		case epic.StatusWIP:
+			return nil
+		case epic.StatusPending:
+			if len(e.UnmetTaskDependencies(&e.Tasks[i])) == 0 {
+				return nil
+			}
+			if waiting == nil {
+				waiting = &e.Tasks[i]
+			}
+		case epic.StatusCompleted, epic.StatusCancelled:
+		default:
+			blockers = append(blockers, Blocker{EntityType: "task", EntityID: task.ID, PhaseID: phase.ID, Status: string(task.Status)})
+		}
+	}
+	if waiting != nil {
+		blockers = unmetBlockers("task", waiting.ID, e.UnmetTaskDependencies(waiting), func(id string) string {
+			for _, task := range e.Tasks {
+				if task.ID == id {
+					return task.PhaseID
+				}
+			}
+			return ""
+		})
+		return &Stall{
+			Reason:     StallDependenciesUnmet,
+			PhaseID:    phase.ID,
+			Message:    fmt.Sprintf("Task %s waits for its dependencies: %s not completed", waiting.ID, describe("task", blockers)),
+			Suggestion: dependencySuggestion(blockers[0]),
+			Blockers:   blockers,
+		}
+	}
+	if len(blockers) > 0 {
+		return &Stall{
+			Reason:     StallTasksBlocked,
+			PhaseID:    phase.ID,
+			Message:    fmt.Sprintf("Phase %s has no pending tasks, but %s not finished", phase.ID, describe("task", blockers)),
+			Suggestion: "agentpm show task " + blockers[0].EntityID,
+			Blockers:   blockers,
+		}
+	}
+
+	if blockers = incompleteTests(e, phase.ID); len(blockers) > 0 {
+		return &Stall{
+			Reason:     StallTestsBlocking,
+			PhaseID:    phase.ID,
+			Message:    fmt.Sprintf("All tasks of phase %s are done, but %s not passed", phase.ID, describe("test", blockers)),
+			Suggestion: testSuggestion(blockers[0]),
+			Blockers:   blockers,
+		}
+	}
+
+	return &Stall{
+		Reason:     StallPhaseComplete,
+		PhaseID:    phase.ID,
+		Message:    fmt.Sprintf("All tasks and tests of phase %s are done", phase.ID),
+		Suggestion: "agentpm done phase " + phase.ID,
+	}
+}
+
+func analyzeNextPhase(e *epic.Epic, next *epic.Phase) *Stall {
+	var blockers []Blocker
+	for _, phaseID := range e.UpstreamPhaseIDs(next) {
+		blockers = append(blockers, incompleteTests(e, phaseID)...)
+	}
+	if len(blockers) == 0 {
+		return nil
+	}
+	return &Stall{
+		Reason:     StallDependenciesUnmet,
+		PhaseID:    next.ID,
+		Message:    fmt.Sprintf("Phase %s cannot start: in earlier phases, %s not passed", next.ID, describe("test", blockers)),
+		Suggestion: testSuggestion(blockers[0]),
+		Blockers:   blockers,
+	}
+}
+
+// analyzeWaitingPhase explains a pending phase whose depends_on phases are not completed
+func analyzeWaitingPhase(e *epic.Epic, phase *epic.Phase) *Stall {
+	blockers := unmetBlockers("phase", phase.ID, e.UnmetPhaseDependencies(phase), func(string) string { return "" })
+	return &Stall{
+		Reason:     StallDependenciesUnmet,
+		PhaseID:    phase.ID,
+		Message:    fmt.Sprintf("Phase %s waits for its dependencies: %s not completed", phase.ID, describe("phase", blockers)),
+		Suggestion: dependencySuggestion(blockers[0]),
+		Blockers:   blockers,
+	}
+}
+
+// unmetBlockers turns the unmet dependencies of a phase or task into blockers;
+// phaseOf returns the phase of a task dependency
+func unmetBlockers(entityType, id string, unmet []epic.Dependency, phaseOf func(id string) string) []Blocker {
+	blockers := make([]Blocker, len(unmet))
+	for i, dependency := range unmet {
+		status := string(dependency.Status)
+		if !dependency.Found() {
+			status = "not found"
+		}
+		blockers[i] = Blocker{EntityType: entityType, EntityID: dependency.ID, PhaseID: phaseOf(dependency.ID), Status: status}
+	}
+	return blockers
+}
+
+// dependencySuggestion returns the command that moves an unmet dependency on
+func dependencySuggestion(blocker Blocker) string {
+	switch blocker.Status {
+	case string(epic.StatusPending):
+		return fmt.Sprintf("agentpm start %s %s", blocker.EntityType, blocker.EntityID)
+	case string(epic.StatusWIP):
+		return fmt.Sprintf("agentpm done %s %s", blocker.EntityType, blocker.EntityID)
+	default:
+		return fmt.Sprintf("agentpm show %s %s", blocker.EntityType, blocker.EntityID)
+	}
+}
+
+// incompleteTests lists the tests of a phase that keep it from being completed:
+// everything not passed or cancelled, the same rule completing a phase enforces
+func incompleteTests(e *epic.Epic, phaseID string) []Blocker {
+	var blockers []Blocker
+	for _, test := range e.Tests {
+		if test.PhaseID != phaseID || testFinished(test) {
+			continue
+		}
+		blockers = append(blockers, Blocker{
+			EntityType: "test",
+			EntityID:   test.ID,
+			PhaseID:    phaseID,
+			Status:     string(test.GetTestStatusUnified()),
+			Failing:    test.GetTestResult() == epic.TestResultFailing,
+		})
+	}
+	return blockers
+}
+
+func testFinished(test epic.Test) bool {
+	if test.TestStatus != "" {
+		return (test.Status == epic.StatusCompleted && test.TestStatus == epic.TestStatusDone) ||
+			(test.Status == epic.StatusCancelled && test.TestStatus == epic.TestStatusCancelled)
+	}
+	return test.Status == epic.StatusCompleted || test.Status == epic.StatusCancelled
+}
+
+// testSuggestion returns the command that moves a blocking test on
+func testSuggestion(blocker Blocker) string {
+	if blocker.Status == string(epic.TestStatusPending) {
+		return "agentpm start test " + blocker.EntityID
+	}
+	return "agentpm pass " + blocker.EntityID
+}
+
+// describe lists blockers for a message: "tests 1_1 (failing), 1_2 (pending) are"
+func describe(entityType string, blockers []Blocker) string {
+	items := make([]string, len(blockers))
+	for i, blocker := range blockers {
+		state := blocker.Status
+		if blocker.Failing {
+			state = "failing"
+		}
+		items[i] = fmt.Sprintf("%s (%s)", blocker.EntityID, state)
+	}
+	if len(blockers) == 1 {
+		return fmt.Sprintf("%s %s is", entityType, items[0])
+	}
+	return fmt.Sprintf("%ss %s are", entityType, strings.Join(items, ", "))
+}
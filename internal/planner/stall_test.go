@@ -0,0 +1,141 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stalledEpic() *epic.Epic {
+	failedAt := time.Date(2025, 8, 16, 12, 0, 0, 0, time.UTC)
+	return &epic.Epic{
+		ID:     "epic-1",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{
+			{ID: "P1", Status: epic.StatusWIP},
+			{ID: "P2", Status: epic.StatusPending},
+		},
+		Tasks: []epic.Task{
+			{ID: "T1", PhaseID: "P1", Status: epic.StatusCompleted},
+			{ID: "T2", PhaseID: "P1", Status: epic.StatusCancelled},
+			{ID: "T3", PhaseID: "P2", Status: epic.StatusPending},
+		},
+		Tests: []epic.Test{
+			{ID: "T1_1", PhaseID: "P1", TaskID: "T1", Status: epic.StatusCompleted, TestStatus: epic.TestStatusDone},
+			{ID: "T1_2", PhaseID: "P1", TaskID: "T1", Status: epic.StatusWIP, TestStatus: epic.TestStatusWIP, FailedAt: &failedAt},
+			{ID: "T1_3", PhaseID: "P1", TaskID: "T1", Status: epic.StatusPending, TestStatus: epic.TestStatusPending},
+		},
+	}
+}
+
+func TestAnalyzeStall(t *testing.T) {
+	t.Run("startable or active work is no stall", func(t *testing.T) {
+		e := stalledEpic()
+		e.Tasks[0].Status = epic.StatusWIP
+		assert.Nil(t, AnalyzeStall(e))
+
+		e.Tasks[0].Status = epic.StatusPending
+		assert.Nil(t, AnalyzeStall(e))
+	})
+
+	t.Run("tests blocking the active phase", func(t *testing.T) {
+		stall := AnalyzeStall(stalledEpic())
+		require.NotNil(t, stall)
+		assert.Equal(t, StallTestsBlocking, stall.Reason)
+		assert.Equal(t, "P1", stall.PhaseID)
+		assert.Equal(t, "All tasks of phase P1 are done, but tests T1_2 (failing), T1_3 (pending) are not passed", stall.Message)
+		assert.Equal(t, "agentpm pass T1_2", stall.Suggestion)
+		assert.Equal(t, []Blocker{
+			{EntityType: "test", EntityID: "T1_2", PhaseID: "P1", Status: "wip", Failing: true},
+			{EntityType: "test", EntityID: "T1_3", PhaseID: "P1", Status: "pending"},
+		}, stall.Blockers)
+	})
+
+	t.Run("all tasks and tests done suggests completing the phase", func(t *testing.T) {
+		e := stalledEpic()
+		e.Tests = e.Tests[:1]
+
+		stall := AnalyzeStall(e)
+		require.NotNil(t, stall)
+		assert.Equal(t, StallPhaseComplete, stall.Reason)
+		assert.Equal(t, "agentpm done phase P1", stall.Suggestion)
+		assert.Empty(t, stall.Blockers)
+	})
+
+	t.Run("tasks neither pending nor finished", func(t *testing.T) {
+		e := stalledEpic()
+		e.Tasks[1].Status = epic.StatusOnHold
+
+		stall := AnalyzeStall(e)
+		require.NotNil(t, stall)
+		assert.Equal(t, StallTasksBlocked, stall.Reason)
+		assert.Equal(t, "Phase P1 has no pending tasks, but task T2 (on_hold) is not finished", stall.Message)
+		assert.Equal(t, "agentpm show task T2", stall.Suggestion)
+	})
+
+	t.Run("next phase waits for upstream tests", func(t *testing.T) {
+		e := stalledEpic()
+		e.Phases[0].Status = epic.StatusCompleted
+		e.Tests[2].Status = epic.StatusCancelled
+		e.Tests[2].TestStatus = epic.TestStatusCancelled
+
+		stall := AnalyzeStall(e)
+		require.NotNil(t, stall)
+		assert.Equal(t, StallDependenciesUnmet, stall.Reason)
+		assert.Equal(t, "P2", stall.PhaseID)
+		assert.Equal(t, "Phase P2 cannot start: in earlier phases, test T1_2 (failing) is not passed", stall.Message)
+		require.Len(t, stall.Blockers, 1)
+
+		e.Tests[1].Status = epic.StatusCompleted
+		e.Tests[1].TestStatus = epic.TestStatusDone
+		assert.Nil(t, AnalyzeStall(e), "a startable next phase is no stall")
+	})
+
+	t.Run("finished epics and phases that are not pending", func(t *testing.T) {
+		e := stalledEpic()
+		e.Tests = nil
+		e.Phases[0].Status = epic.StatusCompleted
+		e.Phases[1].Status = epic.StatusCancelled
+		assert.Nil(t, AnalyzeStall(e))
+
+		e.Phases[1].Status = epic.StatusOnHold
+		stall := AnalyzeStall(e)
+		require.NotNil(t, stall)
+		assert.Equal(t, StallPhasesBlocked, stall.Reason)
+		assert.Equal(t, "No phase is pending, but phase P2 (on_hold) is not finished", stall.Message)
+	})
+
+	t.Run("explicit dependencies of phases and tasks", func(t *testing.T) {
+		e := stalledEpic()
+		e.Tests = nil
+		e.Phases[0].Status = epic.StatusCompleted
+		e.Phases = append(e.Phases, epic.Phase{ID: "P3", Status: epic.StatusPending})
+		e.Phases[1].DependsOn = "P3"
+		e.Phases[2].DependsOn = "P2"
+
+		stall := AnalyzeStall(e)
+		require.NotNil(t, stall)
+		assert.Equal(t, StallDependenciesUnmet, stall.Reason)
+		assert.Equal(t, "P2", stall.PhaseID)
+		assert.Equal(t, "Phase P2 waits for its dependencies: phase P3 (pending) is not completed", stall.Message)
+		assert.Equal(t, "agentpm start phase P3", stall.Suggestion)
+
+		e.Phases[2].DependsOn = ""
+		assert.Nil(t, AnalyzeStall(e), "a later phase whose dependencies are met can start")
+
+		e.Phases[1].Status = epic.StatusWIP
+		e.Tasks = append(e.Tasks, epic.Task{ID: "T4", PhaseID: "P2", Status: epic.StatusPending})
+		e.Tasks[2].DependsOn = "T4"
+		assert.Nil(t, AnalyzeStall(e), "T4 can start")
+
+		e.Tasks[3].DependsOn = "T9"
+		stall = AnalyzeStall(e)
+		require.NotNil(t, stall)
+		assert.Equal(t, StallDependenciesUnmet, stall.Reason)
+		assert.Equal(t, "Task T3 waits for its dependencies: task T4 (pending) is not completed", stall.Message)
+		assert.Equal(t, []Blocker{{EntityType: "task", EntityID: "T4", PhaseID: "P2", Status: "pending"}}, stall.Blockers)
+	})
+}
@@ -0,0 +1,473 @@
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"gopkg.in/yaml.v3"
+)
+
+// Severities of a rule
+const (
+	SeverityError   = "error"   // Fails validate and refuses the mutation
+	SeverityWarning = "warning" // Reported by validate and the mutations it gates (an error in strict mode)
+	SeverityOff     = "off"     // Rule is disabled
+)
+
+// Built-in checks a rule can use
+const (
+	CheckPhaseMinTests          = "phase_min_tests"          // A phase has at least min tests; gates starting and completing it
+	CheckTaskMinTests           = "task_min_tests"           // A task has at least min tests; gates completing it and its phase
+	CheckTaskAcceptanceCriteria = "task_acceptance_criteria" // A task has acceptance criteria; gates starting it, or completing it and its phase with on: done
+	CheckTaskMaxWIP             = "task_max_wip"             // No task stays wip longer than max; gates starting new work
+)
+
+// When task_acceptance_criteria is checked
+const (
+	OnStart = "start" // Before the task is started (default)
+	OnDone  = "done"  // Before the task or its phase is completed
+)
+
+// Action is a mutation that rules can gate
+type Action string
+
+const (
+	ActionStartPhase Action = "start phase"
+	ActionDonePhase  Action = "complete phase"
+	ActionStartTask  Action = "start task"
+	ActionDoneTask   Action = "complete task"
+)
+
+// Rule is one organization rule of a policy file or the policies of the config
+type Rule struct {
+	ID          string `yaml:"id" json:"id"`
+	Check       string `yaml:"check" json:"check"`
+	Severity    string `yaml:"severity" json:"severity,omitempty"` // error (default), warning or off
+	Description string `yaml:"description" json:"description,omitempty"`
+	Min         int    `yaml:"min" json:"min,omitempty"` // phase_min_tests, task_min_tests (default 1)
+	Max         string `yaml:"max" json:"max,omitempty"` // task_max_wip: duration like 72h or 3d
+	On          string `yaml:"on" json:"on,omitempty"`   // task_acceptance_criteria: start (default) or done
+
+	maxWIP time.Duration
+}
+
+// Policy is a set of rules, usually loaded from .agentpm/policy.yaml
+type Policy struct {
+	Path  string `yaml:"-"`
+	Rules []Rule `yaml:"rules"`
+}
+
+// Violation is a rule broken by an epic entity
+type Violation struct {
+	Rule       string `json:"rule"`
+	Check      string `json:"check"`
+	Severity   string `json:"severity"`
+	EntityType string `json:"entity_type"` // "phase" or "task"
+	EntityID   string `json:"entity_id"`
+	Message    string `json:"message"`
+	Hint       string `json:"hint,omitempty"` // How to fix the entity
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("policy %s: %s", v.Rule, v.Message)
+}
+
+// ViolationError is returned when a mutation breaks error-severity rules
+type ViolationError struct {
+	Action     Action
+	EntityID   string
+	Violations []Violation
+	Hint       string // Actionable hint for resolving the error
+}
+
+func (e *ViolationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	var fixes []string
+	for i, violation := range e.Violations {
+		messages[i] = violation.String()
+		if violation.Hint != "" && !slices.Contains(fixes, violation.Hint) {
+			fixes = append(fixes, violation.Hint)
+		}
+	}
+	hint := e.Hint
+	if len(fixes) > 0 {
+		hint = strings.Join(fixes, ". ") + ". " + hint
+	}
+	return fmt.Sprintf("cannot %s %s: %s. %s", e.Action, e.EntityID, strings.Join(messages, "; "), hint)
+}
+
+// IsViolation reports whether err is (or wraps) a ViolationError
+func IsViolation(err error) bool {
+	var violationErr *ViolationError
+	return errors.As(err, &violationErr)
+}
+
+// SuppressionHint tells how to resolve a violation that is intended
+const SuppressionHint = "Fix the epic or suppress the rule with <suppress rule=\"...\" entity=\"...\">reason</suppress> in its <suppressions>"
+
+// active is the policy enforced on the mutations of this process, see SetActive
+var active *Policy
+
+// SetActive sets the policy enforced from now on; nil disables enforcement
+func SetActive(p *Policy) {
+	active = p
+}
+
+// Active returns the policy set with SetActive
+func Active() *Policy {
+	return active
+}
+
+// Enforce checks an action against the active policy. Without a policy every
+// action is allowed.
+func Enforce(e *epic.Epic, action Action, entityID string, now time.Time) error {
+	if active == nil {
+		return nil
+	}
+	return active.Enforce(e, action, entityID, now)
+}
+
+// Warnings returns the warning-severity rules of the active policy that an
+// action on an entity breaks. Without a policy there are none.
+func Warnings(e *epic.Epic, action Action, entityID string, now time.Time) []Violation {
+	if active == nil {
+		return nil
+	}
+	return active.Warnings(e, action, entityID, now)
+}
+
+// Load reads and checks a policy file. A missing file is not an error and yields
+// a nil policy.
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read policy file %s: %w", path, err)
+	}
+
+	p, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", path, err)
+	}
+	p.Path = path
+	return p, nil
+}
+
+// Parse decodes policy YAML, rejecting unknown keys, unknown checks and
+// invalid parameters
+func Parse(data []byte) (*Policy, error) {
+	var p Policy
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&p); err != nil && err != io.EOF {
+		return nil, err
+	}
+	if err := p.check(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// New returns a policy of rules, such as the policies of the config, rejecting
+// unknown checks and invalid parameters like Parse
+func New(rules []Rule) (*Policy, error) {
+	p := &Policy{Rules: append([]Rule(nil), rules...)}
+	if err := p.check(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Merge returns a policy with the rules of both policies, either of which may
+// be nil. Rule IDs must be unique across both.
+func Merge(p, other *Policy) (*Policy, error) {
+	switch {
+	case p == nil:
+		return other, nil
+	case other == nil:
+		return p, nil
+	}
+	merged := &Policy{Path: p.Path, Rules: append(append([]Rule(nil), p.Rules...), other.Rules...)}
+	if err := merged.check(); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// check validates the rules and fills in their defaults
+func (p *Policy) check() error {
+	seen := make(map[string]bool)
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if rule.ID == "" {
+			return fmt.Errorf("rule %d: id is required", i+1)
+		}
+		if seen[rule.ID] {
+			return fmt.Errorf("duplicate rule id: %s", rule.ID)
+		}
+		seen[rule.ID] = true
+
+		switch rule.Severity {
+		case "":
+			rule.Severity = SeverityError
+		case SeverityError, SeverityWarning, SeverityOff:
+		default:
+			return fmt.Errorf("rule %s: invalid severity %q (expected error, warning or off)", rule.ID, rule.Severity)
+		}
+
+		switch rule.Check {
+		case CheckPhaseMinTests, CheckTaskMinTests:
+			if rule.Min < 0 {
+				return fmt.Errorf("rule %s: min must not be negative", rule.ID)
+			}
+			if rule.Min == 0 {
+				rule.Min = 1
+			}
+		case CheckTaskAcceptanceCriteria:
+			switch rule.On {
+			case "":
+				rule.On = OnStart
+			case OnStart, OnDone:
+			default:
+				return fmt.Errorf("rule %s: invalid on %q (expected %s or %s)", rule.ID, rule.On, OnStart, OnDone)
+			}
+		case CheckTaskMaxWIP:
+			maxWIP, err := parseDuration(rule.Max)
+			if err != nil {
+				return fmt.Errorf("rule %s: %w", rule.ID, err)
+			}
+			rule.maxWIP = maxWIP
+		default:
+			return fmt.Errorf("rule %s: unknown check %q (expected %s, %s, %s or %s)", rule.ID, rule.Check,
+				CheckPhaseMinTests, CheckTaskMinTests, CheckTaskAcceptanceCriteria, CheckTaskMaxWIP)
+		}
+		if rule.On != "" && rule.Check != CheckTaskAcceptanceCriteria {
+			return fmt.Errorf("rule %s: on only applies to %s", rule.ID, CheckTaskAcceptanceCriteria)
+		}
+	}
+	return nil
+}
+
+// parseDuration accepts Go durations (72h, 90m) and whole days (3d)
+func parseDuration(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid max %q (use a duration like 72h or 3d)", value)
+	}
+	return duration, nil
+}
+
+// Strict returns a copy of the policy with warning rules upgraded to errors
+func (p *Policy) Strict() *Policy {
+	strict := &Policy{Path: p.Path, Rules: append([]Rule(nil), p.Rules...)}
+	for i := range strict.Rules {
+		if strict.Rules[i].Severity == SeverityWarning {
+			strict.Rules[i].Severity = SeverityError
+		}
+	}
+	return strict
+}
+
+// Evaluate returns the violations of the epic's current state that are not
+// suppressed in the epic
+func (p *Policy) Evaluate(e *epic.Epic, now time.Time) []Violation {
+	var violations []Violation
+	for _, rule := range p.Rules {
+		if rule.Severity == SeverityOff {
+			continue
+		}
+		switch rule.Check {
+		case CheckPhaseMinTests:
+			for _, phase := range e.Phases {
+				if phase.Status != epic.StatusCancelled {
+					violations = append(violations, rule.phaseMinTests(e, phase.ID)...)
+				}
+			}
+		case CheckTaskMinTests, CheckTaskAcceptanceCriteria:
+			for _, task := range e.Tasks {
+				if task.Status != epic.StatusCancelled {
+					violations = append(violations, rule.taskCheck(e, &task)...)
+				}
+			}
+		case CheckTaskMaxWIP:
+			violations = append(violations, rule.taskMaxWIP(e, "", now)...)
+		}
+	}
+	return unsuppressed(e, violations)
+}
+
+// Enforce checks the rules gating an action on an entity and returns a
+// ViolationError listing the error-severity rules it would break
+func (p *Policy) Enforce(e *epic.Epic, action Action, entityID string, now time.Time) error {
+	violations := p.gate(e, action, entityID, now, SeverityError)
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ViolationError{
+		Action:     action,
+		EntityID:   entityID,
+		Violations: violations,
+		Hint:       SuppressionHint,
+	}
+}
+
+// Warnings returns the warning-severity rules gating an action on an entity
+// that the epic breaks. Mutations report them without being refused.
+func (p *Policy) Warnings(e *epic.Epic, action Action, entityID string, now time.Time) []Violation {
+	return p.gate(e, action, entityID, now, SeverityWarning)
+}
+
+// gate returns the unsuppressed violations of the rules of a severity that gate
+// an action on an entity
+func (p *Policy) gate(e *epic.Epic, action Action, entityID string, now time.Time, severity string) []Violation {
+	var violations []Violation
+	for _, rule := range p.Rules {
+		if rule.Severity != severity {
+			continue
+		}
+		switch {
+		case rule.Check == CheckPhaseMinTests && (action == ActionStartPhase || action == ActionDonePhase):
+			violations = append(violations, rule.phaseMinTests(e, entityID)...)
+		case rule.gatesTask(action):
+			for i := range e.Tasks {
+				if e.Tasks[i].ID == entityID {
+					violations = append(violations, rule.taskCheck(e, &e.Tasks[i])...)
+				}
+			}
+		case rule.gatesTask(ActionDoneTask) && action == ActionDonePhase:
+			for i := range e.Tasks {
+				if e.Tasks[i].PhaseID == entityID && e.Tasks[i].Status != epic.StatusCancelled {
+					violations = append(violations, rule.taskCheck(e, &e.Tasks[i])...)
+				}
+			}
+		case rule.Check == CheckTaskMaxWIP && (action == ActionStartTask || action == ActionStartPhase):
+			violations = append(violations, rule.taskMaxWIP(e, entityID, now)...)
+		}
+	}
+
+	return unsuppressed(e, violations)
+}
+
+// gatesTask reports whether the rule checks the task an action is taken on.
+// Rules gating the completion of tasks also gate completing their phase.
+func (r Rule) gatesTask(action Action) bool {
+	switch r.Check {
+	case CheckTaskMinTests:
+		return action == ActionDoneTask
+	case CheckTaskAcceptanceCriteria:
+		return (r.On == OnDone && action == ActionDoneTask) || (r.On != OnDone && action == ActionStartTask)
+	}
+	return false
+}
+
+func (r Rule) violation(entityType, entityID, message, hint string) Violation {
+	return Violation{Rule: r.ID, Check: r.Check, Severity: r.Severity, EntityType: entityType, EntityID: entityID, Message: message, Hint: hint}
+}
+
+func (r Rule) phaseMinTests(e *epic.Epic, phaseID string) []Violation {
+	tasks := make(map[string]bool)
+	for _, task := range e.Tasks {
+		if task.PhaseID == phaseID {
+			tasks[task.ID] = true
+		}
+	}
+	count := 0
+	for _, test := range e.Tests {
+		if (test.PhaseID == phaseID || tasks[test.TaskID]) && test.GetTestStatusUnified() != epic.TestStatusCancelled {
+			count++
+		}
+	}
+	if count >= r.Min {
+		return nil
+	}
+	return []Violation{r.violation("phase", phaseID, fmt.Sprintf("phase %s has %d test(s), at least %d required", phaseID, count, r.Min),
+		fmt.Sprintf("Add tests with phase_id=\"%s\" or for its tasks to the epic", phaseID))}
+}
+
+func (r Rule) taskCheck(e *epic.Epic, task *epic.Task) []Violation {
+	switch r.Check {
+	case CheckTaskAcceptanceCriteria:
+		if strings.TrimSpace(task.AcceptanceCriteria) == "" {
+			when := "started"
+			if r.On == OnDone {
+				when = "completed"
+			}
+			return []Violation{r.violation("task", task.ID, fmt.Sprintf("task %s has no acceptance criteria (required before it is %s)", task.ID, when),
+				fmt.Sprintf("Add <acceptance_criteria> to task %s in the epic", task.ID))}
+		}
+	case CheckTaskMinTests:
+		count := 0
+		for _, test := range e.Tests {
+			if test.TaskID == task.ID && test.GetTestStatusUnified() != epic.TestStatusCancelled {
+				count++
+			}
+		}
+		if count < r.Min {
+			return []Violation{r.violation("task", task.ID, fmt.Sprintf("task %s has %d test(s), at least %d required", task.ID, count, r.Min),
+				fmt.Sprintf("Link tests to task %s with <test task_id=\"%s\"> in the epic", task.ID, task.ID))}
+		}
+	}
+	return nil
+}
+
+// taskMaxWIP reports the wip tasks over the limit, except the one being acted on
+func (r Rule) taskMaxWIP(e *epic.Epic, exceptID string, now time.Time) []Violation {
+	var violations []Violation
+	for _, task := range e.Tasks {
+		if task.Status != epic.StatusWIP || task.StartedAt == nil || task.ID == exceptID {
+			continue
+		}
+		if wip := now.Sub(*task.StartedAt); wip > r.maxWIP {
+			violations = append(violations, r.violation("task", task.ID,
+				fmt.Sprintf("task %s has been wip for %s, longer than %s", task.ID, formatDuration(wip), r.Max),
+				fmt.Sprintf("Complete or cancel task %s first", task.ID)))
+		}
+	}
+	return violations
+}
+
+// formatDuration renders a duration in whole hours or days: "30h", "4d"
+func formatDuration(d time.Duration) string {
+	hours := int(d.Hours())
+	if hours >= 48 {
+		return fmt.Sprintf("%dd", hours/24)
+	}
+	return fmt.Sprintf("%dh", hours)
+}
+
+// unsuppressed drops the violations suppressed in the epic, either for the
+// violating entity or (without entity) for the whole epic
+func unsuppressed(e *epic.Epic, violations []Violation) []Violation {
+	if len(e.Suppressions) == 0 {
+		return violations
+	}
+	var kept []Violation
+	for _, violation := range violations {
+		suppressed := false
+		for _, suppression := range e.Suppressions {
+			if suppression.Rule == violation.Rule && (suppression.Entity == "" || suppression.Entity == violation.EntityID) {
+				suppressed = true
+				break
+			}
+		}
+		if !suppressed {
+			kept = append(kept, violation)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,242 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicy = `
+rules:
+  - id: phase-needs-tests
+    check: phase_min_tests
+    min: 2
+  - id: criteria-before-start
+    check: task_acceptance_criteria
+    severity: warning
+  - id: max-wip
+    check: task_max_wip
+    max: 3d
+  - id: task-needs-tests
+    check: task_min_tests
+    severity: off
+`
+
+var now = time.Date(2025, 8, 20, 12, 0, 0, 0, time.UTC)
+
+func newPolicyEpic() *epic.Epic {
+	started := time.Date(2025, 8, 16, 9, 0, 0, 0, time.UTC)
+	return &epic.Epic{
+		ID:     "epic-1",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{
+			{ID: "P1", Status: epic.StatusWIP},
+			{ID: "P2", Status: epic.StatusPending},
+		},
+		Tasks: []epic.Task{
+			{ID: "T1", PhaseID: "P1", Status: epic.StatusWIP, StartedAt: &started, AcceptanceCriteria: "Works"},
+			{ID: "T2", PhaseID: "P2", Status: epic.StatusPending},
+		},
+		Tests: []epic.Test{
+			{ID: "T1_1", TaskID: "T1", TestStatus: epic.TestStatusDone},
+			{ID: "T1_2", TaskID: "T1", TestStatus: epic.TestStatusPending},
+			{ID: "T2_1", TaskID: "T2", TestStatus: epic.TestStatusPending},
+			{ID: "T2_2", TaskID: "T2", TestStatus: epic.TestStatusCancelled},
+		},
+	}
+}
+
+func TestParse(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	require.NoError(t, err)
+	require.Len(t, p.Rules, 4)
+	assert.Equal(t, SeverityError, p.Rules[0].Severity)
+	assert.Equal(t, 2, p.Rules[0].Min)
+	assert.Equal(t, 72*time.Hour, p.Rules[2].maxWIP)
+	assert.Equal(t, 1, p.Rules[3].Min)
+
+	for name, tc := range map[string]struct {
+		yaml string
+		err  string
+	}{
+		"missing id":       {"rules:\n  - check: phase_min_tests\n", "rule 1: id is required"},
+		"duplicate id":     {"rules:\n  - {id: a, check: phase_min_tests}\n  - {id: a, check: task_min_tests}\n", "duplicate rule id: a"},
+		"unknown check":    {"rules:\n  - {id: a, check: everything_green}\n", `rule a: unknown check "everything_green"`},
+		"invalid severity": {"rules:\n  - {id: a, check: phase_min_tests, severity: fatal}\n", `rule a: invalid severity "fatal"`},
+		"invalid max":      {"rules:\n  - {id: a, check: task_max_wip, max: soon}\n", `rule a: invalid max "soon" (use a duration like 72h or 3d)`},
+		"unknown key":      {"rules:\n  - {id: a, check: phase_min_tests, minimum: 2}\n", "field minimum not found"},
+		"invalid on":       {"rules:\n  - {id: a, check: task_acceptance_criteria, on: review}\n", `rule a: invalid on "review" (expected start or done)`},
+		"misplaced on":     {"rules:\n  - {id: a, check: task_min_tests, on: done}\n", "rule a: on only applies to task_acceptance_criteria"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.yaml))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	p, err := Load(filepath.Join(dir, "policy.yaml"))
+	require.NoError(t, err)
+	assert.Nil(t, p, "a missing policy file is no policy")
+
+	path := filepath.Join(dir, "broken.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("rules: [{id: a}]"), 0644))
+	_, err = Load(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid policy file "+path)
+}
+
+func TestEvaluate(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	require.NoError(t, err)
+
+	t.Run("reports violations of the current state", func(t *testing.T) {
+		violations := p.Evaluate(newPolicyEpic(), now)
+
+		var got []string
+		for _, violation := range violations {
+			got = append(got, violation.String())
+		}
+		assert.Equal(t, []string{
+			"policy phase-needs-tests: phase P2 has 1 test(s), at least 2 required",
+			"policy criteria-before-start: task T2 has no acceptance criteria (required before it is started)",
+			"policy max-wip: task T1 has been wip for 4d, longer than 3d",
+		}, got)
+		assert.Equal(t, SeverityWarning, violations[1].Severity)
+	})
+
+	t.Run("suppressions silence a rule per entity or epic-wide", func(t *testing.T) {
+		e := newPolicyEpic()
+		e.Suppressions = []epic.Suppression{
+			{Rule: "max-wip", Entity: "T1", Reason: "Waiting for review"},
+			{Rule: "criteria-before-start", Entity: "T9"},
+			{Rule: "phase-needs-tests"},
+		}
+
+		violations := p.Evaluate(e, now)
+		require.Len(t, violations, 1)
+		assert.Equal(t, "criteria-before-start", violations[0].Rule)
+	})
+}
+
+func TestEnforce(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	require.NoError(t, err)
+
+	t.Run("gates the actions of each check", func(t *testing.T) {
+		e := newPolicyEpic()
+
+		err := p.Enforce(e, ActionStartPhase, "P2", now)
+		require.Error(t, err)
+		assert.True(t, IsViolation(err))
+		assert.Contains(t, err.Error(), "cannot start phase P2: policy phase-needs-tests: phase P2 has 1 test(s), at least 2 required")
+		assert.Contains(t, err.Error(), "policy max-wip: task T1 has been wip for 4d")
+
+		assert.NoError(t, p.Enforce(e, ActionDonePhase, "P1", now))
+		assert.NoError(t, p.Enforce(e, ActionDoneTask, "T1", now), "task-needs-tests is off")
+
+		err = p.Enforce(e, ActionStartTask, "T2", now)
+		require.Error(t, err)
+		violationErr := err.(*ViolationError)
+		require.Len(t, violationErr.Violations, 1, "warnings do not block")
+		assert.Equal(t, "max-wip", violationErr.Violations[0].Rule)
+	})
+
+	t.Run("strict policy blocks on warnings", func(t *testing.T) {
+		e := newPolicyEpic()
+		e.Suppressions = []epic.Suppression{{Rule: "max-wip"}}
+
+		assert.NoError(t, p.Enforce(e, ActionStartTask, "T2", now))
+		err := p.Strict().Enforce(e, ActionStartTask, "T2", now)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "policy criteria-before-start: task T2 has no acceptance criteria")
+		assert.Equal(t, SeverityWarning, p.Rules[1].Severity, "Strict returns a copy")
+	})
+
+	t.Run("without an active policy everything is allowed", func(t *testing.T) {
+		SetActive(nil)
+		assert.NoError(t, Enforce(newPolicyEpic(), ActionStartPhase, "P2", now))
+
+		SetActive(p)
+		defer SetActive(nil)
+		assert.Error(t, Enforce(newPolicyEpic(), ActionStartPhase, "P2", now))
+	})
+}
+
+func TestEnforceBeforeDone(t *testing.T) {
+	p, err := New([]Rule{
+		{ID: "criteria-before-done", Check: CheckTaskAcceptanceCriteria, On: OnDone},
+		{ID: "tests-before-done", Check: CheckTaskMinTests},
+	})
+	require.NoError(t, err)
+	e := newPolicyEpic()
+	e.Tasks = append(e.Tasks, epic.Task{ID: "T3", PhaseID: "P2", Status: epic.StatusWIP})
+
+	assert.NoError(t, p.Enforce(e, ActionStartTask, "T3", now), "criteria are only required before done")
+	assert.NoError(t, p.Enforce(e, ActionDoneTask, "T1", now))
+
+	err = p.Enforce(e, ActionDoneTask, "T3", now)
+	require.Error(t, err)
+	assert.Equal(t, "cannot complete task T3: "+
+		"policy criteria-before-done: task T3 has no acceptance criteria (required before it is completed); "+
+		"policy tests-before-done: task T3 has 0 test(s), at least 1 required. "+
+		"Add <acceptance_criteria> to task T3 in the epic. Link tests to task T3 with <test task_id=\"T3\"> in the epic. "+
+		SuppressionHint, err.Error())
+
+	t.Run("completing a phase checks its tasks", func(t *testing.T) {
+		err := p.Enforce(e, ActionDonePhase, "P2", now)
+		require.Error(t, err)
+		violationErr := err.(*ViolationError)
+		require.Len(t, violationErr.Violations, 3)
+		assert.Equal(t, "T2", violationErr.Violations[0].EntityID)
+		assert.NoError(t, p.Enforce(e, ActionDonePhase, "P1", now))
+	})
+}
+
+func TestMerge(t *testing.T) {
+	file, err := Parse([]byte(testPolicy))
+	require.NoError(t, err)
+	config, err := New([]Rule{{ID: "tests-before-done", Check: CheckTaskMinTests}})
+	require.NoError(t, err)
+
+	merged, err := Merge(file, config)
+	require.NoError(t, err)
+	assert.Len(t, merged.Rules, 5)
+	assert.Equal(t, 72*time.Hour, merged.Rules[2].maxWIP)
+
+	same, err := Merge(nil, config)
+	require.NoError(t, err)
+	assert.Same(t, config, same)
+
+	_, err = Merge(file, file)
+	assert.ErrorContains(t, err, "duplicate rule id: phase-needs-tests")
+}
+
+func TestWarnings(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	require.NoError(t, err)
+	e := newPolicyEpic()
+
+	warnings := p.Warnings(e, ActionStartTask, "T2", now)
+	require.Len(t, warnings, 1, "only warning-severity rules")
+	assert.Equal(t, "criteria-before-start", warnings[0].Rule)
+	assert.Equal(t, "T2", warnings[0].EntityID)
+
+	assert.Empty(t, p.Warnings(e, ActionStartTask, "T1", now), "T1 has acceptance criteria")
+	assert.Empty(t, p.Warnings(e, ActionDoneTask, "T2", now), "the rule gates starting only")
+
+	e.Suppressions = []epic.Suppression{{Rule: "criteria-before-start", Entity: "T2"}}
+	assert.Empty(t, p.Warnings(e, ActionStartTask, "T2", now))
+
+	SetActive(nil)
+	assert.Empty(t, Warnings(newPolicyEpic(), ActionStartTask, "T2", now))
+}
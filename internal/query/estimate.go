@@ -0,0 +1,125 @@
+package query
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// PhaseEffort is the estimated effort of the tasks of one phase
+type PhaseEffort struct {
+	PhaseID     string
+	Name        string
+	Total       float64
+	Remaining   float64
+	Unestimated int // Open tasks without an estimate
+}
+
+// RemainingEffort is the estimated effort of an epic, what is left of it and
+// when it will be done at the pace of the work so far
+type RemainingEffort struct {
+	Total       float64
+	Remaining   float64
+	Unestimated int // Open tasks without an estimate
+	Phases      []PhaseEffort
+	// Velocity is the estimated effort completed per day, from the first
+	// task_started event up to now; zero before anything estimated was done
+	Velocity float64
+	// ETA projects the remaining effort at the velocity; zero when nothing
+	// remains or the velocity is unknown
+	ETA time.Time
+}
+
+// HasEstimates reports whether any task of the epic has an estimate
+func (r *RemainingEffort) HasEstimates() bool {
+	return r.Total > 0
+}
+
+// GetRemainingEffort sums the estimates of the tasks per phase and projects
+// when the remaining ones will be done. Cancelled tasks and recurring chores
+// are left out, and tasks with sub-tasks count through their sub-tasks.
+func (qs *QueryService) GetRemainingEffort(now time.Time) (*RemainingEffort, error) {
+	if qs.epic == nil {
+		return nil, fmt.Errorf("no epic loaded")
+	}
+
+	result := &RemainingEffort{}
+	byPhase := make(map[string]*PhaseEffort)
+	for _, phase := range qs.epic.Phases {
+		result.Phases = append(result.Phases, PhaseEffort{PhaseID: phase.ID, Name: phase.Name})
+	}
+	for i := range result.Phases {
+		byPhase[result.Phases[i].PhaseID] = &result.Phases[i]
+	}
+
+	completed := qs.taskCompletions()
+	var done float64
+	for i := range qs.epic.Tasks {
+		task := &qs.epic.Tasks[i]
+		if task.Status == epic.StatusCancelled || task.IsRecurring() || len(qs.epic.SubTasks(task.ID)) > 0 {
+			continue
+		}
+		phase := byPhase[task.PhaseID]
+		if phase == nil {
+			phase = &PhaseEffort{}
+		}
+		open := task.Status != epic.StatusCompleted
+
+		estimate, ok := task.EstimatedEffort()
+		if !ok {
+			if open {
+				phase.Unestimated++
+				result.Unestimated++
+			}
+			continue
+		}
+		phase.Total += estimate
+		result.Total += estimate
+		if open {
+			phase.Remaining += estimate
+			result.Remaining += estimate
+		} else if _, ok := completed[task.ID]; ok {
+			done += estimate
+		}
+	}
+
+	if start, ok := qs.firstTaskStart(); ok && done > 0 {
+		days := max(now.Sub(start).Hours()/24, 1)
+		result.Velocity = done / days
+	}
+	if result.Velocity > 0 && result.Remaining > 0 {
+		hours := math.Ceil(result.Remaining / result.Velocity * 24)
+		result.ETA = now.Add(time.Duration(hours) * time.Hour)
+	}
+	return result, nil
+}
+
+// taskCompletions returns when each task was last completed, by its
+// task_completed events
+func (qs *QueryService) taskCompletions() map[string]time.Time {
+	completions := make(map[string]time.Time)
+	for _, event := range qs.epic.Events {
+		if event.Type != "task_completed" {
+			continue
+		}
+		id := epic.EventEntityID(event)
+		if id != "" && event.Timestamp.After(completions[id]) {
+			completions[id] = event.Timestamp
+		}
+	}
+	return completions
+}
+
+// firstTaskStart returns when work on the epic began: its earliest
+// task_started event
+func (qs *QueryService) firstTaskStart() (time.Time, bool) {
+	var first time.Time
+	for _, event := range qs.epic.Events {
+		if event.Type == "task_started" && (first.IsZero() || event.Timestamp.Before(first)) {
+			first = event.Timestamp
+		}
+	}
+	return first, !first.IsZero()
+}
@@ -0,0 +1,64 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryService_GetRemainingEffort(t *testing.T) {
+	at := func(day, hour int) time.Time { return time.Date(2025, 9, day, hour, 0, 0, 0, time.UTC) }
+	storage := storage.NewMemoryStorage()
+	require.NoError(t, storage.SaveEpic(&epic.Epic{
+		ID:     "8",
+		Name:   "Checkout",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{
+			{ID: "1", Name: "Cart", Status: epic.StatusWIP},
+			{ID: "2", Name: "Payment", Status: epic.StatusPending},
+		},
+		Tasks: []epic.Task{
+			{ID: "1_1", PhaseID: "1", Name: "Add item", Status: epic.StatusCompleted, Estimate: "3"},
+			{ID: "1_2", PhaseID: "1", Name: "Remove item", Status: epic.StatusCompleted, Estimate: "2"},
+			{ID: "1_3", PhaseID: "1", Name: "Totals", Status: epic.StatusPending, Estimate: "5"},
+			{ID: "1_4", PhaseID: "1", Name: "Coupons", Status: epic.StatusPending},
+			{ID: "1_5", PhaseID: "1", Name: "Wishlist", Status: epic.StatusCancelled, Estimate: "8"},
+			{ID: "2_1", PhaseID: "2", Name: "Rotate keys", Status: epic.StatusPending, Estimate: "1", Recurring: "7d"},
+			{ID: "2_2", PhaseID: "2", Name: "Card form", Status: epic.StatusWIP, Estimate: "10"},
+			{ID: "2_2a", PhaseID: "2", ParentTaskID: "2_2", Name: "Validation", Status: epic.StatusWIP, Estimate: "4"},
+		},
+		Events: []epic.Event{
+			{ID: "E1", Type: "task_started", Timestamp: at(1, 10), Data: "Task 1_1 (Add item) started"},
+			{ID: "E2", Type: "task_completed", Timestamp: at(2, 10), Data: "Task 1_1 (Add item) completed"},
+			{ID: "E3", Type: "task_completed", Timestamp: at(3, 10), Data: "Task 1_2 (Remove item) completed"},
+		},
+	}, "epic.xml"))
+
+	qs := NewQueryService(storage)
+	require.NoError(t, qs.LoadEpic("epic.xml"))
+
+	effort, err := qs.GetRemainingEffort(at(11, 10))
+	require.NoError(t, err)
+	assert.True(t, effort.HasEstimates())
+	assert.Equal(t, 14.0, effort.Total, "cancelled, recurring and parent tasks are left out")
+	assert.Equal(t, 9.0, effort.Remaining)
+	assert.Equal(t, 1, effort.Unestimated)
+	assert.Equal(t, []PhaseEffort{
+		{PhaseID: "1", Name: "Cart", Total: 10, Remaining: 5, Unestimated: 1},
+		{PhaseID: "2", Name: "Payment", Total: 4, Remaining: 4},
+	}, effort.Phases)
+	assert.Equal(t, 0.5, effort.Velocity, "5 done in the 10 days since the first start")
+	assert.Equal(t, at(29, 10), effort.ETA)
+
+	t.Run("no ETA before estimated work is done", func(t *testing.T) {
+		qs.epic.Events = qs.epic.Events[:1]
+		effort, err := qs.GetRemainingEffort(at(11, 10))
+		require.NoError(t, err)
+		assert.Zero(t, effort.Velocity)
+		assert.True(t, effort.ETA.IsZero())
+	})
+}
@@ -0,0 +1,81 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// IndexEntry is one line of the epic's ID index: an entity with its name,
+// status and the ID of the entity it belongs to
+type IndexEntry struct {
+	Type   string `json:"type"` // epic, phase, task or test
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Parent string `json:"parent,omitempty"`
+}
+
+// GetIndex lists every entity of the epic in outline order: the epic, then
+// each phase followed by its tasks, each task followed by its tests. Tasks
+// and tests whose phase or task does not exist come last. Tests report
+// "failing" while their last result failed, otherwise their unified status.
+func (qs *QueryService) GetIndex() ([]IndexEntry, error) {
+	if qs.epic == nil {
+		return nil, fmt.Errorf("no epic loaded")
+	}
+	e := qs.epic
+
+	index := []IndexEntry{{Type: "epic", ID: e.ID, Name: e.Name, Status: string(e.Status)}}
+	listed := make(map[*epic.Test]bool)
+
+	addTests := func(match func(test *epic.Test) bool) {
+		for i := range e.Tests {
+			test := &e.Tests[i]
+			if listed[test] || !match(test) {
+				continue
+			}
+			listed[test] = true
+			parent := test.TaskID
+			if parent == "" {
+				parent = test.PhaseID
+			}
+			index = append(index, IndexEntry{Type: "test", ID: test.ID, Name: test.Name, Status: indexTestStatus(test), Parent: parent})
+		}
+	}
+	addTask := func(task *epic.Task) {
+		index = append(index, IndexEntry{Type: "task", ID: task.ID, Name: task.Name, Status: string(task.Status), Parent: task.PhaseID})
+		addTests(func(test *epic.Test) bool { return test.TaskID == task.ID })
+	}
+
+	phaseIDs := make(map[string]bool)
+	for _, phase := range e.Phases {
+		phaseIDs[phase.ID] = true
+		index = append(index, IndexEntry{Type: "phase", ID: phase.ID, Name: phase.Name, Status: string(phase.Status), Parent: e.ID})
+		for i := range e.Tasks {
+			if e.Tasks[i].PhaseID == phase.ID {
+				addTask(&e.Tasks[i])
+			}
+		}
+		// Tests attached to the phase only
+		addTests(func(test *epic.Test) bool { return test.TaskID == "" && test.PhaseID == phase.ID })
+	}
+
+	for i := range e.Tasks {
+		if !phaseIDs[e.Tasks[i].PhaseID] {
+			addTask(&e.Tasks[i])
+		}
+	}
+	addTests(func(test *epic.Test) bool { return true })
+
+	return index, nil
+}
+
+// indexTestStatus is the status a test has in the index
+func indexTestStatus(test *epic.Test) string {
+	status := test.GetTestStatusUnified()
+	if status != epic.TestStatusCancelled && test.GetTestResult() == epic.TestResultFailing {
+		return string(epic.TestResultFailing)
+	}
+	return string(status)
+}
@@ -0,0 +1,53 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryService_GetIndex(t *testing.T) {
+	storage := storage.NewMemoryStorage()
+	require.NoError(t, storage.SaveEpic(&epic.Epic{
+		ID:     "8",
+		Name:   "Checkout",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{
+			{ID: "1", Name: "Cart", Status: epic.StatusWIP},
+			{ID: "2", Name: "Payment", Status: epic.StatusPending},
+		},
+		Tasks: []epic.Task{
+			{ID: "2_1", PhaseID: "2", Name: "Card form", Status: epic.StatusPending},
+			{ID: "1_1", PhaseID: "1", Name: "Add item", Status: epic.StatusWIP},
+			{ID: "9_1", PhaseID: "9", Name: "Orphan", Status: epic.StatusPending},
+		},
+		Tests: []epic.Test{
+			{ID: "T1_1", TaskID: "1_1", Name: "Item added", TestStatus: epic.TestStatusWIP, TestResult: epic.TestResultFailing},
+			{ID: "T2", PhaseID: "2", Name: "Payment flow", TestStatus: epic.TestStatusDone, TestResult: epic.TestResultPassing},
+			{ID: "T9", TaskID: "9_9", Name: "Lost", TestStatus: epic.TestStatusCancelled},
+		},
+	}, "epic.xml"))
+
+	qs := NewQueryService(storage)
+	_, err := qs.GetIndex()
+	assert.Error(t, err)
+
+	require.NoError(t, qs.LoadEpic("epic.xml"))
+	index, err := qs.GetIndex()
+	require.NoError(t, err)
+
+	assert.Equal(t, []IndexEntry{
+		{Type: "epic", ID: "8", Name: "Checkout", Status: "wip"},
+		{Type: "phase", ID: "1", Name: "Cart", Status: "wip", Parent: "8"},
+		{Type: "task", ID: "1_1", Name: "Add item", Status: "wip", Parent: "1"},
+		{Type: "test", ID: "T1_1", Name: "Item added", Status: "failing", Parent: "1_1"},
+		{Type: "phase", ID: "2", Name: "Payment", Status: "pending", Parent: "8"},
+		{Type: "task", ID: "2_1", Name: "Card form", Status: "pending", Parent: "2"},
+		{Type: "test", ID: "T2", Name: "Payment flow", Status: "done", Parent: "2"},
+		{Type: "task", ID: "9_1", Name: "Orphan", Status: "pending", Parent: "9"},
+		{Type: "test", ID: "T9", Name: "Lost", Status: "cancelled", Parent: "9_9"},
+	}, index)
+}
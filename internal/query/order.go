@@ -0,0 +1,131 @@
+package query
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// Order is the deterministic order of list outputs: phases as they appear in
+// the epic, tasks and tests by the position of their phase, then by ID.
+// QueryService applies it to every epic it loads, so list outputs do not
+// depend on where an entity sits in the file.
+type Order struct {
+	phases     map[string]int
+	taskPhases map[string]string
+}
+
+// NewOrder builds the order of the entities of an epic
+func NewOrder(e *epic.Epic) *Order {
+	o := &Order{
+		phases:     make(map[string]int, len(e.Phases)),
+		taskPhases: make(map[string]string, len(e.Tasks)),
+	}
+	for i, phase := range e.Phases {
+		if _, ok := o.phases[phase.ID]; !ok {
+			o.phases[phase.ID] = i
+		}
+	}
+	for _, task := range e.Tasks {
+		o.taskPhases[task.ID] = task.PhaseID
+	}
+	return o
+}
+
+// TestPhase returns the phase a test belongs to: its own, else its task's
+func (o *Order) TestPhase(test *epic.Test) string {
+	if test.PhaseID != "" {
+		return test.PhaseID
+	}
+	return o.taskPhases[test.TaskID]
+}
+
+// Compare orders two entities by the position of their phase, then by ID.
+// Entities of unknown phases come after all others, sorted by phase ID.
+func (o *Order) Compare(phaseA, idA, phaseB, idB string) int {
+	rankA, knownA := o.phases[phaseA]
+	rankB, knownB := o.phases[phaseB]
+	switch {
+	case knownA && knownB:
+		if c := cmp.Compare(rankA, rankB); c != 0 {
+			return c
+		}
+	case knownA != knownB:
+		if knownA {
+			return -1
+		}
+		return 1
+	default:
+		if c := CompareIDs(phaseA, phaseB); c != 0 {
+			return c
+		}
+	}
+	return CompareIDs(idA, idB)
+}
+
+// SortTasks sorts tasks by phase, then ID
+func (o *Order) SortTasks(tasks []epic.Task) {
+	slices.SortStableFunc(tasks, func(a, b epic.Task) int {
+		return o.Compare(a.PhaseID, a.ID, b.PhaseID, b.ID)
+	})
+}
+
+// SortTests sorts tests by phase, then ID
+func (o *Order) SortTests(tests []epic.Test) {
+	slices.SortStableFunc(tests, func(a, b epic.Test) int {
+		return o.Compare(o.TestPhase(&a), a.ID, o.TestPhase(&b), b.ID)
+	})
+}
+
+// Apply returns a copy of the epic with its tasks and tests sorted; phases
+// and events keep their order and the epic itself is left untouched
+func (o *Order) Apply(e *epic.Epic) *epic.Epic {
+	ordered := *e
+	ordered.Tasks = slices.Clone(e.Tasks)
+	ordered.Tests = slices.Clone(e.Tests)
+	o.SortTasks(ordered.Tasks)
+	o.SortTests(ordered.Tests)
+	return &ordered
+}
+
+// CompareIDs compares two IDs naturally: runs of digits by their numeric
+// value, so that 1_2 sorts before 1_10 and T9 before T10
+func CompareIDs(a, b string) int {
+	x, y := a, b
+	for x != "" && y != "" {
+		chunkX, restX := idChunk(x)
+		chunkY, restY := idChunk(y)
+		if isDigit(chunkX[0]) && isDigit(chunkY[0]) {
+			numX, numY := strings.TrimLeft(chunkX, "0"), strings.TrimLeft(chunkY, "0")
+			if c := cmp.Compare(len(numX), len(numY)); c != 0 {
+				return c
+			}
+			if c := strings.Compare(numX, numY); c != 0 {
+				return c
+			}
+		} else if c := strings.Compare(chunkX, chunkY); c != 0 {
+			return c
+		}
+		x, y = restX, restY
+	}
+	if c := cmp.Compare(len(x), len(y)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// idChunk splits off the leading run of digits or non-digits of s
+func idChunk(s string) (chunk, rest string) {
+	digits := isDigit(s[0])
+	i := 1
+	for i < len(s) && isDigit(s[i]) == digits {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
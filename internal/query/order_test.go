@@ -0,0 +1,87 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareIDs(t *testing.T) {
+	ordered := []string{"1", "1A_1", "1_2", "1_10", "2", "T9", "T010", "T10", "T10_a", "T10a", "a"}
+	for i := range ordered {
+		for j := range ordered {
+			got := CompareIDs(ordered[i], ordered[j])
+			switch {
+			case i < j:
+				assert.Negative(t, got, "%s < %s", ordered[i], ordered[j])
+			case i > j:
+				assert.Positive(t, got, "%s > %s", ordered[i], ordered[j])
+			default:
+				assert.Zero(t, got)
+			}
+		}
+	}
+}
+
+func shuffledEpic() *epic.Epic {
+	return &epic.Epic{
+		ID: "order",
+		Phases: []epic.Phase{
+			{ID: "2", Name: "Second in ID, first in file"},
+			{ID: "1", Name: "First in ID, second in file"},
+		},
+		Tasks: []epic.Task{
+			{ID: "1_10", PhaseID: "1", Status: epic.StatusPending},
+			{ID: "9_1", PhaseID: "9", Status: epic.StatusPending},
+			{ID: "1_2", PhaseID: "1", Status: epic.StatusPending},
+			{ID: "2_1", PhaseID: "2", Status: epic.StatusPending},
+		},
+		Tests: []epic.Test{
+			{ID: "T1_2", TaskID: "1_2", Status: epic.StatusPending},
+			{ID: "T2_1b", TaskID: "2_1", PhaseID: "2", Status: epic.StatusPending},
+			{ID: "T2_1a", TaskID: "2_1", Status: epic.StatusPending},
+		},
+	}
+}
+
+func TestOrder_Apply(t *testing.T) {
+	e := shuffledEpic()
+	ordered := NewOrder(e).Apply(e)
+
+	var tasks, tests []string
+	for _, task := range ordered.Tasks {
+		tasks = append(tasks, task.ID)
+	}
+	for _, test := range ordered.Tests {
+		tests = append(tests, test.ID)
+	}
+	assert.Equal(t, []string{"2_1", "1_2", "1_10", "9_1"}, tasks, "phase position first, unknown phases last")
+	assert.Equal(t, []string{"T2_1a", "T2_1b", "T1_2"}, tests, "tests without a phase use their task's")
+	assert.Equal(t, "1_10", e.Tasks[0].ID, "the epic itself keeps its order")
+}
+
+func TestQueryService_ListsInOrder(t *testing.T) {
+	storage := storage.NewMemoryStorage()
+	require.NoError(t, storage.SaveEpic(shuffledEpic(), "epic.xml"))
+	qs := NewQueryService(storage)
+	require.NoError(t, qs.LoadEpic("epic.xml"))
+
+	pending, err := qs.GetPendingWork()
+	require.NoError(t, err)
+	require.Len(t, pending.Tasks, 4)
+	assert.Equal(t, "2_1", pending.Tasks[0].ID)
+	assert.Equal(t, "1_10", pending.Tasks[2].ID)
+
+	failing, err := qs.GetFailingTests()
+	require.NoError(t, err)
+	require.Len(t, failing, 3)
+	assert.Equal(t, "T2_1a", failing[0].ID)
+
+	related, err := qs.GetRelatedItems("phase", "1")
+	require.NoError(t, err)
+	assert.Equal(t, "1_2", related[0].ID)
+	assert.Equal(t, "1_10", related[1].ID)
+}
@@ -0,0 +1,125 @@
+package query
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// searchSnippetContext is the number of bytes a snippet shows around a match
+const searchSnippetContext = 30
+
+// SearchMatch is an entity with text matching a search
+type SearchMatch struct {
+	File    string `json:"file,omitempty"` // Epic file, set when searching several epics
+	Epic    string `json:"epic"`
+	Type    string `json:"type"` // epic, phase, task or test
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Status  string `json:"status"` // As in the index: failing for tests whose last run failed
+	Field   string `json:"field"`  // First field that matched: name, description, deliverables, acceptance_criteria or failure_note
+	Snippet string `json:"snippet"`
+}
+
+// SearchFilter restricts a search to entities of some types and statuses.
+// Zero fields match every entity.
+type SearchFilter struct {
+	Types    []string
+	Statuses []string
+}
+
+// searchField is a text of an entity a search looks at
+type searchField struct {
+	name, text string
+}
+
+// Search finds the entities whose name, description, acceptance criteria,
+// deliverables or failure note contain text, ignoring case, in outline order
+// (see GetIndex). Each entity is reported once, for the first field that
+// matched.
+func (qs *QueryService) Search(text string, filter SearchFilter) ([]SearchMatch, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("search text is required")
+	}
+	index, err := qs.GetIndex()
+	if err != nil {
+		return nil, err
+	}
+	fields := qs.searchFields()
+
+	needle := strings.ToLower(text)
+	var matches []SearchMatch
+	for _, entry := range index {
+		if len(filter.Types) > 0 && !slices.Contains(filter.Types, entry.Type) {
+			continue
+		}
+		if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, entry.Status) {
+			continue
+		}
+		for _, field := range fields[entry.Type+":"+entry.ID] {
+			if at := strings.Index(strings.ToLower(field.text), needle); at >= 0 {
+				matches = append(matches, SearchMatch{
+					Epic: qs.epic.ID, Type: entry.Type, ID: entry.ID, Name: entry.Name, Status: entry.Status,
+					Field: field.name, Snippet: searchSnippet(field.text, at, len(needle)),
+				})
+				break
+			}
+		}
+	}
+	return matches, nil
+}
+
+// searchFields collects the searchable texts of every entity by type:id
+func (qs *QueryService) searchFields() map[string][]searchField {
+	e := qs.epic
+	fields := map[string][]searchField{
+		"epic:" + e.ID: {{"name", e.Name}, {"description", e.Description}},
+	}
+	for _, phase := range e.Phases {
+		fields["phase:"+phase.ID] = []searchField{{"name", phase.Name}, {"description", phase.Description}, {"deliverables", phase.Deliverables}}
+	}
+	for _, task := range e.Tasks {
+		fields["task:"+task.ID] = []searchField{{"name", task.Name}, {"description", task.Description}, {"acceptance_criteria", task.AcceptanceCriteria}}
+	}
+	for _, test := range e.Tests {
+		fields["test:"+test.ID] = []searchField{{"name", test.Name}, {"description", test.Description}, {"failure_note", test.FailureNote}}
+	}
+	return fields
+}
+
+// searchSnippet cuts the text around a match of length n at byte offset at,
+// on one line, with "..." where it was shortened. Offsets found in the
+// lowercased text may be off for the few runes lowercasing changes in length.
+func searchSnippet(text string, at, n int) string {
+	at = min(at, len(text))
+	matchEnd := min(len(text), at+n)
+	start := max(0, at-searchSnippetContext)
+	end := min(len(text), matchEnd+searchSnippetContext)
+	// Cut between words where possible, and never within a UTF-8 sequence
+	if start > 0 {
+		if space := strings.IndexAny(text[start:at], " \t\n"); space >= 0 {
+			start += space + 1
+		}
+	}
+	if end < len(text) {
+		if space := strings.LastIndexAny(text[matchEnd:end], " \t\n"); space >= 0 {
+			end = matchEnd + space
+		}
+	}
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+
+	snippet := strings.Join(strings.Fields(text[start:end]), " ")
+	if start > 0 {
+		snippet = "..." + snippet
+	}
+	if end < len(text) {
+		snippet += "..."
+	}
+	return snippet
+}
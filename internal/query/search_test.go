@@ -0,0 +1,59 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryService_Search(t *testing.T) {
+	storage := storage.NewMemoryStorage()
+	require.NoError(t, storage.SaveEpic(&epic.Epic{
+		ID:          "8",
+		Name:        "Checkout",
+		Status:      epic.StatusWIP,
+		Description: "Let customers pay with a saved card",
+		Phases: []epic.Phase{
+			{ID: "1", Name: "Cart", Status: epic.StatusWIP, Deliverables: "Cart page with rate limited updates"},
+		},
+		Tasks: []epic.Task{
+			{ID: "1_1", PhaseID: "1", Name: "Add item", Status: epic.StatusWIP, AcceptanceCriteria: "- [ ] Respects the RATE LIMIT of the API"},
+			{ID: "1_2", PhaseID: "1", Name: "Rate limit banner", Status: epic.StatusPending},
+		},
+		Tests: []epic.Test{
+			{ID: "T1", TaskID: "1_1", Name: "Item added", TestStatus: epic.TestStatusWIP, TestResult: epic.TestResultFailing,
+				FailureNote: "Request was rejected by the rate limit after " + strings.Repeat("many ", 20) + "retries"},
+		},
+	}, "epic.xml"))
+
+	qs := NewQueryService(storage)
+	require.NoError(t, qs.LoadEpic("epic.xml"))
+
+	matches, err := qs.Search("rate limit", SearchFilter{})
+	require.NoError(t, err)
+	require.Len(t, matches, 4)
+	assert.Equal(t, SearchMatch{Epic: "8", Type: "phase", ID: "1", Name: "Cart", Status: "wip", Field: "deliverables", Snippet: "Cart page with rate limited updates"}, matches[0])
+	assert.Equal(t, "acceptance_criteria", matches[1].Field)
+	assert.Equal(t, "- [ ] Respects the RATE LIMIT of the API", matches[1].Snippet)
+	assert.Equal(t, "failure_note", matches[2].Field, "tests follow their task")
+	assert.Equal(t, "failing", matches[2].Status)
+	assert.Equal(t, "Request was rejected by the rate limit after many many many many...", matches[2].Snippet)
+	assert.Equal(t, "name", matches[3].Field, "each entity is reported for its first matching field")
+
+	matches, err = qs.Search("rate limit", SearchFilter{Types: []string{"task"}, Statuses: []string{"pending"}})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "1_2", matches[0].ID)
+
+	matches, err = qs.Search("saved card", SearchFilter{})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "epic", matches[0].Type)
+
+	_, err = qs.Search("  ", SearchFilter{})
+	assert.Error(t, err)
+}
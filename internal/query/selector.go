@@ -0,0 +1,316 @@
+package query
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mindreframer/agentpm/internal/customfields"
+	"github.com/mindreframer/agentpm/internal/storage"
+)
+
+// Selector is a parsed entity selector such as tasks[status=wip][phase=2A]:
+// an entity type followed by conditions that all have to hold
+type Selector struct {
+	Entity     string // epic, phase, task or test
+	Conditions []Condition
+}
+
+// Condition compares one field of an entity against a list of values
+type Condition struct {
+	Field  string
+	Op     string // "=" (any of the values), "!=" (none of them), "~=" (contains one of them) or, for custom fields, "<", "<=", ">" and ">=" (one value)
+	Values []string
+	// Custom declares the type of a custom.<name> field, see ResolveCustomFields
+	Custom customfields.Definition
+}
+
+// selectorEntities maps the names accepted in a selector to entity types
+var selectorEntities = map[string]string{
+	"epic": "epic", "epics": "epic",
+	"phase": "phase", "phases": "phase",
+	"task": "task", "tasks": "task",
+	"test": "test", "tests": "test",
+}
+
+// selectorFields lists the fields that can be compared per entity type
+var selectorFields = map[string][]string{
+	"epic":  {"id", "name", "status", "assignee"},
+	"phase": {"id", "name", "status", "assignee"},
+	"task":  {"id", "name", "status", "assignee", "phase"},
+	"test":  {"id", "name", "status", "assignee", "phase", "task", "result"},
+}
+
+// IsSelector reports whether expr is written in the selector syntax rather
+// than as an XPath expression, i.e. starts with an entity name
+func IsSelector(expr string) bool {
+	name, _, _ := strings.Cut(strings.TrimSpace(expr), "[")
+	_, ok := selectorEntities[strings.ToLower(name)]
+	return ok
+}
+
+// ParseSelector parses a selector of the form entity[cond][cond]..., where
+// a condition is field=value, field!=value or field~=value (values separated
+// by commas are alternatives), or a bare status or test result like [wip] or
+// [failed]. Custom fields are written custom.<name> and can also be ordered
+// with <, <=, > and >=.
+func ParseSelector(expr string) (*Selector, error) {
+	rest := strings.TrimSpace(expr)
+	name, conditions, _ := strings.Cut(rest, "[")
+	entity, ok := selectorEntities[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, fmt.Errorf("invalid selector %q: unknown entity %q (must be epic, phases, tasks or tests)", expr, name)
+	}
+
+	sel := &Selector{Entity: entity}
+	if rest == name {
+		return sel, nil
+	}
+	rest = "[" + conditions
+	for rest != "" {
+		if rest[0] != '[' {
+			return nil, fmt.Errorf("invalid selector %q: expected '[' before %q", expr, rest)
+		}
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			return nil, fmt.Errorf("invalid selector %q: missing ']'", expr)
+		}
+		cond, err := parseCondition(entity, rest[1:end])
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %w", expr, err)
+		}
+		sel.Conditions = append(sel.Conditions, cond)
+		rest = strings.TrimSpace(rest[end+1:])
+	}
+	return sel, nil
+}
+
+func parseCondition(entity, text string) (Condition, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return Condition{}, fmt.Errorf("empty condition")
+	}
+
+	i := strings.IndexAny(text, "!~=<>")
+	if i < 0 {
+		return flagCondition(entity, text)
+	}
+
+	op := "="
+	field := strings.ToLower(strings.TrimSpace(text[:i]))
+	value := text[i+1:]
+	switch {
+	case text[i] == '<' || text[i] == '>':
+		op = text[i : i+1]
+		if strings.HasPrefix(value, "=") {
+			op += "="
+			value = value[1:]
+		}
+	case text[i] != '=':
+		if !strings.HasPrefix(text[i+1:], "=") {
+			return Condition{}, fmt.Errorf("unknown operator in %q (use =, != or ~=)", text)
+		}
+		op = text[i : i+2]
+		value = text[i+2:]
+	}
+
+	custom := strings.HasPrefix(field, customfields.Prefix)
+	if custom {
+		// Custom field names keep their case, as declared in the config
+		field = customfields.Prefix + strings.TrimSpace(text[len(customfields.Prefix):i])
+	} else if !slices.Contains(selectorFields[entity], field) {
+		return Condition{}, fmt.Errorf("unknown field %q for %ss (valid: %s, custom.<name>)", field, entity, strings.Join(selectorFields[entity], ", "))
+	}
+	if isOrdering(op) && !custom {
+		return Condition{}, fmt.Errorf("%s only compares custom fields, not %q", op, field)
+	}
+
+	cond := Condition{Field: field, Op: op}
+	for _, v := range strings.Split(value, ",") {
+		v = strings.Trim(strings.TrimSpace(v), `'"`)
+		if v == "" {
+			return Condition{}, fmt.Errorf("missing value in %q", text)
+		}
+		cond.Values = append(cond.Values, normalizeSelectorValue(field, v))
+	}
+	if isOrdering(op) && len(cond.Values) > 1 {
+		return Condition{}, fmt.Errorf("%s takes a single value in %q", op, text)
+	}
+	return cond, nil
+}
+
+func isOrdering(op string) bool {
+	return op == "<" || op == "<=" || op == ">" || op == ">="
+}
+
+// HasCustomFields reports whether the selector compares custom fields
+func (s *Selector) HasCustomFields() bool {
+	return slices.ContainsFunc(s.Conditions, func(cond Condition) bool {
+		return strings.HasPrefix(cond.Field, customfields.Prefix)
+	})
+}
+
+// ResolveCustomFields looks up the custom fields the selector compares in the
+// schema, so that they are compared by their declared type, and brings the
+// values into the form stored in the epic
+func (s *Selector) ResolveCustomFields(schema customfields.Schema) error {
+	for i := range s.Conditions {
+		cond := &s.Conditions[i]
+		name, ok := strings.CutPrefix(cond.Field, customfields.Prefix)
+		if !ok {
+			continue
+		}
+		def, err := schema.Lookup(s.Entity, name)
+		if err != nil {
+			return err
+		}
+		cond.Custom = def
+		if cond.Op == "~=" {
+			continue
+		}
+		for j, value := range cond.Values {
+			if cond.Values[j], err = def.Normalize(value); err != nil {
+				return fmt.Errorf("%s: %w", cond.Field, err)
+			}
+		}
+	}
+	return nil
+}
+
+// flagCondition turns a bare word like [wip] or [failed] into a status or,
+// for tests, a result condition
+func flagCondition(entity, flag string) (Condition, error) {
+	value := normalizeSelectorValue("status", strings.ToLower(flag))
+	switch value {
+	case "pending", "wip", "done", "cancelled":
+		return Condition{Field: "status", Op: "=", Values: []string{value}}, nil
+	case "on_hold":
+		if entity == "task" || entity == "phase" {
+			return Condition{Field: "status", Op: "=", Values: []string{value}}, nil
+		}
+	}
+	if entity == "test" {
+		if result := normalizeSelectorValue("result", strings.ToLower(flag)); result == "passing" || result == "failing" {
+			return Condition{Field: "result", Op: "=", Values: []string{result}}, nil
+		}
+	}
+	return Condition{}, fmt.Errorf("unknown condition [%s] for %ss", flag, entity)
+}
+
+// normalizeSelectorValue maps aliases onto the values stored in the epic,
+// so that [status=completed] and [failed] work as well as done and failing
+func normalizeSelectorValue(field, value string) string {
+	switch {
+	case field == "status" && strings.EqualFold(value, "completed"):
+		return "done"
+	case field == "result" && strings.EqualFold(value, "failed"):
+		return "failing"
+	case field == "result" && strings.EqualFold(value, "passed"):
+		return "passing"
+	}
+	return value
+}
+
+// Select returns the index entries of all entities matching sel, in the
+// outline order of GetIndex
+func (qs *QueryService) Select(sel *Selector) ([]IndexEntry, error) {
+	index, err := qs.GetIndex()
+	if err != nil {
+		return nil, err
+	}
+	fields := qs.selectorFieldValues(sel.Entity)
+
+	var matches []IndexEntry
+	for _, entry := range index {
+		if entry.Type != sel.Entity {
+			continue
+		}
+		if sel.Matches(fields[entry.ID]) {
+			matches = append(matches, entry)
+		}
+	}
+	return matches, nil
+}
+
+// Matches reports whether an entity with these field values meets all
+// conditions of the selector
+func (s *Selector) Matches(fields map[string]string) bool {
+	return !slices.ContainsFunc(s.Conditions, func(cond Condition) bool { return !cond.matches(fields[cond.Field]) })
+}
+
+func (c Condition) matches(actual string) bool {
+	if strings.HasPrefix(c.Field, customfields.Prefix) {
+		return c.matchesCustom(actual)
+	}
+	actual = normalizeSelectorValue(c.Field, strings.TrimSpace(actual))
+	for _, want := range c.Values {
+		switch c.Op {
+		case "~=":
+			if strings.Contains(strings.ToLower(actual), strings.ToLower(want)) {
+				return true
+			}
+		case "!=":
+			if strings.EqualFold(actual, want) {
+				return false
+			}
+		default:
+			if strings.EqualFold(actual, want) {
+				return true
+			}
+		}
+	}
+	return c.Op == "!="
+}
+
+// matchesCustom compares a custom field by its declared type. Entities without
+// the field, or with a value that does not fit its type, only match !=.
+func (c Condition) matchesCustom(actual string) bool {
+	if c.Op == "~=" {
+		return actual != "" && slices.ContainsFunc(c.Values, func(want string) bool {
+			return strings.Contains(strings.ToLower(actual), strings.ToLower(want))
+		})
+	}
+	value, err := c.Custom.Normalize(actual)
+	if actual == "" || err != nil {
+		return c.Op == "!="
+	}
+
+	switch c.Op {
+	case "<":
+		return c.Custom.Compare(value, c.Values[0]) < 0
+	case "<=":
+		return c.Custom.Compare(value, c.Values[0]) <= 0
+	case ">":
+		return c.Custom.Compare(value, c.Values[0]) > 0
+	case ">=":
+		return c.Custom.Compare(value, c.Values[0]) >= 0
+	}
+	equal := slices.ContainsFunc(c.Values, func(want string) bool { return c.Custom.Compare(value, want) == 0 })
+	return equal == (c.Op == "=")
+}
+
+// selectorFieldValues collects the comparable fields of all entities of one
+// type by ID, see storage.EntityRecords
+func (qs *QueryService) selectorFieldValues(entity string) map[string]map[string]string {
+	values := make(map[string]map[string]string)
+	for _, record := range storage.EntityRecords(qs.epic, entity) {
+		values[record.ID] = record.Fields
+	}
+	return values
+}
+
+// SelectAcross returns the entities of the epics at paths that match sel,
+// epic by epic, as found by a storage backend
+func SelectAcross(backend storage.Backend, paths []string, sel *Selector) ([]storage.EntityRecord, error) {
+	records, err := backend.Entities(paths, sel.Entity)
+	if err != nil {
+		return nil, err
+	}
+	var matches []storage.EntityRecord
+	for _, record := range records {
+		if sel.Matches(record.Fields) {
+			matches = append(matches, record)
+		}
+	}
+	return matches, nil
+}
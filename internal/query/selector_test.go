@@ -0,0 +1,168 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/customfields"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSelector(t *testing.T) {
+	sel, err := ParseSelector("tasks[status=wip][phase=2A, 2B]")
+	require.NoError(t, err)
+	assert.Equal(t, &Selector{Entity: "task", Conditions: []Condition{
+		{Field: "status", Op: "=", Values: []string{"wip"}},
+		{Field: "phase", Op: "=", Values: []string{"2A", "2B"}},
+	}}, sel)
+
+	sel, err = ParseSelector("tests[failed][name~='login form']")
+	require.NoError(t, err)
+	assert.Equal(t, &Selector{Entity: "test", Conditions: []Condition{
+		{Field: "result", Op: "=", Values: []string{"failing"}},
+		{Field: "name", Op: "~=", Values: []string{"login form"}},
+	}}, sel)
+
+	sel, err = ParseSelector("phase[status!=completed]")
+	require.NoError(t, err)
+	assert.Equal(t, []Condition{{Field: "status", Op: "!=", Values: []string{"done"}}}, sel.Conditions)
+
+	for expr, message := range map[string]string{
+		"milestones":               `unknown entity "milestones"`,
+		"tasks[wip":                "missing ']'",
+		"tasks[wip]x":              `expected '[' before "x"`,
+		"tasks[]":                  "empty condition",
+		"tasks[failed]":            "unknown condition [failed] for tasks",
+		"tasks[task=1]":            `unknown field "task" for tasks`,
+		"tasks[status=]":           `missing value in "status="`,
+		"tasks[status!wip]":        "unknown operator",
+		"epic[on_hold]":            "unknown condition [on_hold] for epics",
+		"tests[result=wip,]":       "missing value",
+		"phases[assignee!=x]":      "",
+		"tasks[custom.Sprint>=7]":  "",
+		"tasks[status<wip]":        `< only compares custom fields, not "status"`,
+		"tasks[custom.sprint>1,2]": "> takes a single value",
+	} {
+		_, err := ParseSelector(expr)
+		if message == "" {
+			assert.NoError(t, err, expr)
+			continue
+		}
+		assert.ErrorContains(t, err, message, expr)
+	}
+}
+
+func TestIsSelector(t *testing.T) {
+	assert.True(t, IsSelector("tasks"))
+	assert.True(t, IsSelector("Tests[failed]"))
+	assert.True(t, IsSelector("epic"))
+	assert.False(t, IsSelector("//task"))
+	assert.False(t, IsSelector("//task[@status='wip']"))
+	assert.False(t, IsSelector("metadata/assignee"))
+}
+
+func TestQueryService_Select(t *testing.T) {
+	storage := storage.NewMemoryStorage()
+	require.NoError(t, storage.SaveEpic(&epic.Epic{
+		ID:       "8",
+		Name:     "Checkout",
+		Status:   epic.StatusWIP,
+		Assignee: "agent_a",
+		Phases: []epic.Phase{
+			{ID: "1", Name: "Cart", Status: epic.StatusCompleted},
+			{ID: "2", Name: "Payment", Status: epic.StatusWIP, Assignee: "agent_b"},
+		},
+		Tasks: []epic.Task{
+			{ID: "1_1", PhaseID: "1", Name: "Add item", Status: epic.StatusCompleted},
+			{ID: "2_1", PhaseID: "2", Name: "Card form", Status: epic.StatusWIP},
+			{ID: "2_2", PhaseID: "2", Name: "Card check", Status: epic.StatusPending, Assignee: "agent_a"},
+		},
+		Tests: []epic.Test{
+			{ID: "T1_1", TaskID: "1_1", Name: "Item added", TestStatus: epic.TestStatusDone, TestResult: epic.TestResultPassing},
+			{ID: "T2_1", TaskID: "2_1", Name: "Form shown", TestStatus: epic.TestStatusWIP, TestResult: epic.TestResultFailing},
+			{ID: "T2_2", TaskID: "2_2", Name: "Check runs", TestStatus: epic.TestStatusPending},
+		},
+	}, "epic.xml"))
+	qs := NewQueryService(storage)
+	require.NoError(t, qs.LoadEpic("epic.xml"))
+
+	ids := func(expr string) []string {
+		sel, err := ParseSelector(expr)
+		require.NoError(t, err, expr)
+		matches, err := qs.Select(sel)
+		require.NoError(t, err, expr)
+		var ids []string
+		for _, match := range matches {
+			ids = append(ids, match.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"1_1", "2_1", "2_2"}, ids("tasks"))
+	assert.Equal(t, []string{"2_1"}, ids("tasks[status=wip][phase=2]"))
+	assert.Equal(t, []string{"1_1", "2_1"}, ids("tasks[status=done,wip]"))
+	assert.Equal(t, []string{"1_1"}, ids("tasks[done]"))
+	assert.Equal(t, []string{"2"}, ids("phases[status!=completed]"))
+	assert.Equal(t, []string{"2_1", "2_2"}, ids("tasks[name~=CARD]"))
+	assert.Equal(t, []string{"2_1"}, ids("tasks[assignee=agent_b]"))
+	assert.Equal(t, []string{"T2_1"}, ids("tests[failed]"))
+	assert.Equal(t, []string{"T2_1", "T2_2"}, ids("tests[phase=2]"))
+	assert.Equal(t, []string{"T1_1"}, ids("tests[passing][task=1_1]"))
+	assert.Equal(t, []string{"8"}, ids("epic[wip]"))
+	assert.Empty(t, ids("tests[assignee=agent_c]"))
+}
+
+func TestQueryService_SelectCustomFields(t *testing.T) {
+	storage := storage.NewMemoryStorage()
+	require.NoError(t, storage.SaveEpic(&epic.Epic{
+		ID:     "8",
+		Name:   "Checkout",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{{ID: "1", Name: "Cart", Status: epic.StatusWIP}},
+		Tasks: []epic.Task{
+			{ID: "1_1", PhaseID: "1", Name: "Add item", Custom: epic.CustomFields{{Name: "risk", Value: "high"}, {Name: "sprint", Value: "9"}}},
+			{ID: "1_2", PhaseID: "1", Name: "Remove item", Custom: epic.CustomFields{{Name: "release", Value: "2025-09-01"}, {Name: "sprint", Value: "10"}}},
+			{ID: "1_3", PhaseID: "1", Name: "Empty cart", Custom: epic.CustomFields{{Name: "risk", Value: "low"}, {Name: "sprint", Value: "soon"}}},
+		},
+	}, "epic.xml"))
+	qs := NewQueryService(storage)
+	require.NoError(t, qs.LoadEpic("epic.xml"))
+
+	schema := customfields.Schema{"task": {
+		"sprint":  {Type: customfields.TypeInt},
+		"release": {Type: customfields.TypeDate},
+		"risk":    {Type: customfields.TypeEnum, Values: []string{"low", "medium", "high"}},
+	}}
+	ids := func(expr string) []string {
+		sel, err := ParseSelector(expr)
+		require.NoError(t, err, expr)
+		require.NoError(t, sel.ResolveCustomFields(schema), expr)
+		matches, err := qs.Select(sel)
+		require.NoError(t, err, expr)
+		var ids []string
+		for _, match := range matches {
+			ids = append(ids, match.ID)
+		}
+		return ids
+	}
+
+	// Integers compare by number, not alphabetically; values that do not fit the type never match
+	assert.Equal(t, []string{"1_2"}, ids("tasks[custom.sprint>9]"))
+	assert.Equal(t, []string{"1_1", "1_2"}, ids("tasks[custom.sprint>=09]"))
+	assert.Equal(t, []string{"1_1"}, ids("tasks[custom.sprint=9,11]"))
+	assert.Equal(t, []string{"1_2", "1_3"}, ids("tasks[custom.sprint!=9]"))
+	assert.Equal(t, []string{"1_1"}, ids("tasks[custom.risk>medium]"))
+	assert.Equal(t, []string{"1_3"}, ids("tasks[custom.risk=LOW]"))
+	assert.Equal(t, []string{"1_2"}, ids("tasks[custom.release<2025-09-01T12:00:00Z]"))
+	assert.Equal(t, []string{"1_1", "1_3"}, ids("tasks[custom.release!=2025-09-01]"))
+
+	sel, err := ParseSelector("tasks[custom.sprint=next]")
+	require.NoError(t, err)
+	assert.ErrorContains(t, sel.ResolveCustomFields(schema), `custom.sprint: invalid value "next": expected an integer`)
+
+	sel, err = ParseSelector("tasks[custom.team=core]")
+	require.NoError(t, err)
+	assert.ErrorContains(t, sel.ResolveCustomFields(schema), `unknown custom field "team" for tasks`)
+}
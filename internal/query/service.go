@@ -8,6 +8,7 @@ import (
 
 	"github.com/mindreframer/agentpm/internal/epic"
 	"github.com/mindreframer/agentpm/internal/storage"
+	"github.com/mindreframer/agentpm/internal/throughput"
 )
 
 // QueryService provides read-only query operations for epic data
@@ -23,13 +24,25 @@ func NewQueryService(storage storage.Storage) *QueryService {
 	}
 }
 
-// LoadEpic loads and caches an epic for query operations
+// LoadEpic loads and caches an epic for query operations. The cached copy
+// has its tasks and tests in Order, so every list derived from it comes out
+// the same way however the file is arranged.
 func (qs *QueryService) LoadEpic(epicFile string) error {
 	epic, err := qs.storage.LoadEpic(epicFile)
 	if err != nil {
 		return fmt.Errorf("failed to load epic: %w", err)
 	}
-	qs.epic = epic
+	qs.epic = NewOrder(epic).Apply(epic)
+	return nil
+}
+
+// FilterAssignee narrows the loaded epic to the work of one agent, so that
+// all following queries only see its tasks, their tests and its phases
+func (qs *QueryService) FilterAssignee(agent string) error {
+	if qs.epic == nil {
+		return fmt.Errorf("no epic loaded")
+	}
+	qs.epic = qs.epic.ForAssignee(agent)
 	return nil
 }
 
@@ -62,13 +75,64 @@ type EpicStatus struct {
 	TotalPhases          int
 	PassingTests         int
 	FailingTests         int
+	FlakyTests           int // Tests that pass again after failing repeatedly, see epic.Test.Stability
 	CompletionPercentage int
 	CurrentPhase         string
 	CurrentTask          string
+	CurrentParentTask    string // Parent of the current task when it is a sub-task
+	// Recurring work is kept apart from the one-off tasks; nil without recurring tasks
+	Recurring *RecurringStatus
+	// Test results of the last week, see GetTestThroughput; nil unless requested
+	TestThroughput *throughput.Report
+	// Open deadlines that passed or come up soon, see GetDeadlines; nil unless requested
+	Deadlines []Deadline
+	// Estimated effort of the tasks, see GetRemainingEffort; nil unless requested
+	Effort *RemainingEffort
 	// Epic 13 Enhanced Validation Information
 	Epic13Status Epic13StatusInfo
 }
 
+// GetTestThroughput counts the tests fixed and newly failing per day over
+// the last `days` days up to now
+func (qs *QueryService) GetTestThroughput(now time.Time, days int) (*throughput.Report, error) {
+	if qs.epic == nil {
+		return nil, fmt.Errorf("no epic loaded")
+	}
+	return throughput.Build(qs.epic, now, days), nil
+}
+
+// Deadline is an open deadline as of the time it was looked up
+type Deadline struct {
+	epic.Deadline
+	Overdue bool
+}
+
+// GetDeadlines returns the open deadlines of the epic, its phases and tasks
+// that have passed at now or pass within the window after it, earliest first
+func (qs *QueryService) GetDeadlines(now time.Time, window time.Duration) ([]Deadline, error) {
+	if qs.epic == nil {
+		return nil, fmt.Errorf("no epic loaded")
+	}
+	var deadlines []Deadline
+	for _, deadline := range qs.epic.OpenDeadlines() {
+		if deadline.At.After(now.Add(window)) {
+			break
+		}
+		deadlines = append(deadlines, Deadline{Deadline: deadline, Overdue: deadline.IsOverdue(now)})
+	}
+	return deadlines, nil
+}
+
+// RecurringStatus counts one-off tasks and the occurrences of recurring tasks
+type RecurringStatus struct {
+	OneOffTasks          int
+	CompletedOneOffTasks int
+	OpenOccurrences      int    // Pending and active occurrences of recurring tasks
+	CompletedOccurrences int    // Completed occurrences of recurring tasks
+	NextDue              string // Earliest due date of the pending occurrences
+	NextTask             string // Pending occurrence that is due next
+}
+
 // GetEpicStatus calculates and returns comprehensive epic status information
 func (qs *QueryService) GetEpicStatus() (*EpicStatus, error) {
 	if qs.epic == nil {
@@ -97,6 +161,9 @@ func (qs *QueryService) GetEpicStatus() (*EpicStatus, error) {
 			// For now, treat non-completed tests as "failing" for reporting
 			status.FailingTests++
 		}
+		if test.IsFlaky() {
+			status.FlakyTests++
+		}
 	}
 
 	// Calculate completion percentage with enhanced phase/task weighting
@@ -105,6 +172,11 @@ func (qs *QueryService) GetEpicStatus() (*EpicStatus, error) {
 	// Find current phase and task
 	status.CurrentPhase = qs.findCurrentPhase()
 	status.CurrentTask = qs.findCurrentTask()
+	if task := qs.epic.ActiveTask(""); task != nil {
+		status.CurrentParentTask = task.ParentTaskID
+	}
+
+	status.Recurring = qs.calculateRecurringStatus()
 
 	// Calculate Epic 13 validation information
 	status.Epic13Status = qs.calculateEpic13Status()
@@ -112,6 +184,35 @@ func (qs *QueryService) GetEpicStatus() (*EpicStatus, error) {
 	return status, nil
 }
 
+// calculateRecurringStatus tells recurring from one-off tasks, or returns nil
+// when the epic has no recurring tasks
+func (qs *QueryService) calculateRecurringStatus() *RecurringStatus {
+	recurring := &RecurringStatus{}
+	var nextDue time.Time
+	for _, task := range qs.epic.Tasks {
+		switch {
+		case !task.IsRecurring():
+			recurring.OneOffTasks++
+			if task.Status == epic.StatusCompleted {
+				recurring.CompletedOneOffTasks++
+			}
+		case task.Status == epic.StatusCompleted:
+			recurring.CompletedOccurrences++
+		case task.Status == epic.StatusPending || task.Status == epic.StatusWIP:
+			recurring.OpenOccurrences++
+			if due, _, err := epic.ParseDate(task.Due); task.Status == epic.StatusPending && err == nil && (nextDue.IsZero() || due.Before(nextDue)) {
+				nextDue = due
+				recurring.NextDue = task.Due
+				recurring.NextTask = task.ID
+			}
+		}
+	}
+	if recurring.OpenOccurrences == 0 && recurring.CompletedOccurrences == 0 {
+		return nil
+	}
+	return recurring
+}
+
 // CurrentState represents the active work state
 type CurrentState struct {
 	EpicStatus   epic.Status
@@ -119,6 +220,19 @@ type CurrentState struct {
 	ActiveTask   string
 	NextAction   string
 	FailingTests int
+	// Context needed to act on the active task without further queries
+	ActiveTaskDetails *ActiveTaskDetails
+	PendingTests      []PendingTest
+	Blockers          []string
+}
+
+// ActiveTaskDetails carries the description and acceptance criteria of the active task
+type ActiveTaskDetails struct {
+	ID                 string
+	PhaseID            string
+	Name               string
+	Description        string
+	AcceptanceCriteria string
 }
 
 // GetCurrentState returns information about currently active work
@@ -145,9 +259,105 @@ func (qs *QueryService) GetCurrentState() (*CurrentState, error) {
 	// Determine next action
 	state.NextAction = qs.getNextAction()
 
+	// Attach active task context
+	if state.ActiveTask != "" {
+		for _, task := range qs.epic.Tasks {
+			if task.ID == state.ActiveTask {
+				state.ActiveTaskDetails = &ActiveTaskDetails{
+					ID:                 task.ID,
+					PhaseID:            task.PhaseID,
+					Name:               task.Name,
+					Description:        task.Description,
+					AcceptanceCriteria: task.AcceptanceCriteria,
+				}
+				break
+			}
+		}
+		state.PendingTests = qs.getPendingTestsForTask(state.ActiveTask)
+	}
+
+	state.Blockers = qs.findBlockers()
+
 	return state, nil
 }
 
+// getPendingTestsForTask returns the tests of a task that still need work
+// (pending, in progress or failing)
+func (qs *QueryService) getPendingTestsForTask(taskID string) []PendingTest {
+	var pending []PendingTest
+	for _, test := range qs.epic.Tests {
+		if test.TaskID != taskID {
+			continue
+		}
+		status := test.GetTestStatusUnified()
+		if status == epic.TestStatusCancelled {
+			continue
+		}
+		if status == epic.TestStatusDone && test.GetTestResult() != epic.TestResultFailing {
+			continue
+		}
+		phaseID := test.PhaseID
+		if phaseID == "" {
+			if task := qs.findTaskByID(taskID); task != nil {
+				phaseID = task.PhaseID
+			}
+		}
+		pending = append(pending, PendingTest{
+			ID:      test.ID,
+			TaskID:  test.TaskID,
+			PhaseID: phaseID,
+			Name:    test.Name,
+			Status:  test.Status,
+		})
+	}
+	return pending
+}
+
+// findBlockers collects unresolved blockers: open blockers and questions,
+// failing tests and logged blocker events
+func (qs *QueryService) findBlockers() []string {
+	var blockers []string
+	for _, blocker := range qs.epic.OpenBlockers() {
+		label := "Blocker"
+		if blocker.IsQuestion() {
+			label = "Question"
+		}
+		if blocker.Entity != "" {
+			blockers = append(blockers, fmt.Sprintf("%s %s on %s: %s", label, blocker.ID, blocker.Entity, blocker.Description))
+		} else {
+			blockers = append(blockers, fmt.Sprintf("%s %s: %s", label, blocker.ID, blocker.Description))
+		}
+	}
+	for _, test := range qs.epic.Tests {
+		if test.GetTestStatusUnified() == epic.TestStatusCancelled {
+			continue
+		}
+		if test.GetTestResult() == epic.TestResultFailing {
+			if test.FailureNote != "" {
+				blockers = append(blockers, fmt.Sprintf("Failing test %s: %s (%s)", test.ID, test.Name, test.FailureNote))
+			} else {
+				blockers = append(blockers, fmt.Sprintf("Failing test %s: %s", test.ID, test.Name))
+			}
+		}
+	}
+	for _, event := range qs.epic.Events {
+		if event.Type == "blocker" {
+			blockers = append(blockers, event.Data)
+		}
+	}
+	return blockers
+}
+
+// findTaskByID returns the task with the given ID, or nil
+func (qs *QueryService) findTaskByID(taskID string) *epic.Task {
+	for i := range qs.epic.Tasks {
+		if qs.epic.Tasks[i].ID == taskID {
+			return &qs.epic.Tasks[i]
+		}
+	}
+	return nil
+}
+
 // PendingWork represents work that hasn't been completed
 type PendingWork struct {
 	Phases []PendingPhase
@@ -155,17 +365,21 @@ type PendingWork struct {
 	Tests  []PendingTest
 }
 
+// PendingPhase and PendingTask carry the agent the work is assigned to; it is
+// empty for work that falls back to the epic's assignee
 type PendingPhase struct {
-	ID     string
-	Name   string
-	Status epic.Status
+	ID       string
+	Name     string
+	Status   epic.Status
+	Assignee string
 }
 
 type PendingTask struct {
-	ID      string
-	PhaseID string
-	Name    string
-	Status  epic.Status
+	ID       string
+	PhaseID  string
+	Name     string
+	Status   epic.Status
+	Assignee string
 }
 
 type PendingTest struct {
@@ -188,9 +402,10 @@ func (qs *QueryService) GetPendingWork() (*PendingWork, error) {
 	for _, phase := range qs.epic.Phases {
 		if phase.Status != epic.StatusCompleted {
 			pending.Phases = append(pending.Phases, PendingPhase{
-				ID:     phase.ID,
-				Name:   phase.Name,
-				Status: phase.Status,
+				ID:       phase.ID,
+				Name:     phase.Name,
+				Status:   phase.Status,
+				Assignee: phase.Assignee,
 			})
 		}
 	}
@@ -198,11 +413,16 @@ func (qs *QueryService) GetPendingWork() (*PendingWork, error) {
 	// Collect pending tasks
 	for _, task := range qs.epic.Tasks {
 		if task.Status != epic.StatusCompleted {
+			assignee := task.Assignee
+			if assignee == "" {
+				assignee = qs.getPhaseAssignee(task.PhaseID)
+			}
 			pending.Tasks = append(pending.Tasks, PendingTask{
-				ID:      task.ID,
-				PhaseID: task.PhaseID,
-				Name:    task.Name,
-				Status:  task.Status,
+				ID:       task.ID,
+				PhaseID:  task.PhaseID,
+				Name:     task.Name,
+				Status:   task.Status,
+				Assignee: assignee,
 			})
 		}
 	}
@@ -240,6 +460,9 @@ type FailingTest struct {
 	Name        string
 	Description string
 	FailureNote string
+	Context     *epic.FailureContext // Structured detail of the last failure, nil when none was given
+	Manual      bool                 // Checked by a person, see epic.TestTypeManual
+	VerifiedBy  string               // Who gave the last verdict on a manual test
 }
 
 // GetFailingTests returns tests with non-completed status (considered failing for reporting)
@@ -268,7 +491,10 @@ func (qs *QueryService) GetFailingTests() ([]FailingTest, error) {
 				TaskID:      test.TaskID,
 				Name:        test.Name,
 				Description: test.Description,
-				FailureNote: "", // Field not available in current epic model
+				FailureNote: test.FailureNote,
+				Context:     test.FailureContext,
+				Manual:      test.IsManual(),
+				VerifiedBy:  test.VerifiedBy,
 			})
 		}
 	}
@@ -276,22 +502,129 @@ func (qs *QueryService) GetFailingTests() ([]FailingTest, error) {
 	return failing, nil
 }
 
+// GetManualChecks returns the manual tests still waiting for a person to pass
+// them: pending, in progress or failed, but not cancelled
+func (qs *QueryService) GetManualChecks() ([]FailingTest, error) {
+	if qs.epic == nil {
+		return nil, fmt.Errorf("no epic loaded")
+	}
+
+	var checks []FailingTest
+	for _, test := range qs.epic.Tests {
+		if !test.IsManual() {
+			continue
+		}
+		status := test.GetTestStatusUnified()
+		if status == epic.TestStatusDone || status == epic.TestStatusCancelled {
+			continue
+		}
+		phaseID := test.PhaseID
+		if phaseID == "" {
+			for _, task := range qs.epic.Tasks {
+				if task.ID == test.TaskID {
+					phaseID = task.PhaseID
+					break
+				}
+			}
+		}
+		checks = append(checks, FailingTest{
+			ID:          test.ID,
+			PhaseID:     phaseID,
+			TaskID:      test.TaskID,
+			Name:        test.Name,
+			Description: test.Description,
+			FailureNote: test.FailureNote,
+			Context:     test.FailureContext,
+			Manual:      true,
+			VerifiedBy:  test.VerifiedBy,
+		})
+	}
+	return checks, nil
+}
+
+// TestHistory is the pass/fail record of a test that failed at least once
+type TestHistory struct {
+	ID           string
+	PhaseID      string
+	TaskID       string
+	Name         string
+	Stability    string // epic.StabilityFailing, epic.StabilityFixed or epic.StabilityFlaky
+	FailCount    int
+	PassCount    int
+	LastFailedAt *time.Time
+	LastPassedAt *time.Time
+}
+
+// GetTestHistory returns the pass/fail record of every test that failed at
+// least once, in epic order
+func (qs *QueryService) GetTestHistory() ([]TestHistory, error) {
+	if qs.epic == nil {
+		return nil, fmt.Errorf("no epic loaded")
+	}
+
+	var history []TestHistory
+	for _, test := range qs.epic.Tests {
+		if test.FailCount == 0 {
+			continue
+		}
+		phaseID := test.PhaseID
+		if phaseID == "" {
+			for _, task := range qs.epic.Tasks {
+				if task.ID == test.TaskID {
+					phaseID = task.PhaseID
+					break
+				}
+			}
+		}
+		history = append(history, TestHistory{
+			ID:           test.ID,
+			PhaseID:      phaseID,
+			TaskID:       test.TaskID,
+			Name:         test.Name,
+			Stability:    test.Stability(),
+			FailCount:    test.FailCount,
+			PassCount:    test.PassCount,
+			LastFailedAt: test.FailedAt,
+			LastPassedAt: test.PassedAt,
+		})
+	}
+	return history, nil
+}
+
 // Event represents an epic event with metadata
 type Event struct {
+	ID        string
+	EpicID    string
 	Timestamp time.Time
 	Agent     string
-	PhaseID   string
+	PhaseID   string // Phase the event concerns, resolved through its task or test
+	TaskID    string // Task the event concerns, resolved through its test
 	Type      string
 	Content   string
+
+	CorrelationID string // Shared by the events of one command invocation
+}
+
+// EventFilter selects the events GetRecentEvents returns. Zero fields match
+// every event.
+type EventFilter struct {
+	Types   []string  // Event types, or entity prefixes like "task" for all task_* events
+	Since   time.Time // Only events at or after this time
+	PhaseID string    // Only events concerning this phase, its tasks or their tests
+	TaskID  string    // Only events concerning this task or its tests
+	Limit   int       // Maximum number of events (default 10, max 100); negative for all
+
+	CorrelationID string // Only events recorded by the command invocation with this correlation ID
 }
 
-// GetRecentEvents returns events in reverse chronological order with optional limit
-func (qs *QueryService) GetRecentEvents(limit int) ([]Event, error) {
+// GetRecentEvents returns the events matching filter in reverse chronological order
+func (qs *QueryService) GetRecentEvents(filter EventFilter) ([]Event, error) {
 	if qs.epic == nil {
 		return nil, fmt.Errorf("no epic loaded")
 	}
 
-	if limit <= 0 {
+	limit := filter.Limit
+	if limit == 0 {
 		limit = 10 // default limit
 	}
 	if limit > 100 {
@@ -300,28 +633,89 @@ func (qs *QueryService) GetRecentEvents(limit int) ([]Event, error) {
 
 	var events []Event
 	for _, event := range qs.epic.Events {
+		if !filter.matchesType(event.Type) || event.Timestamp.Before(filter.Since) {
+			continue
+		}
+		phaseID, taskID := qs.epic.EventScope(event)
+		if filter.PhaseID != "" && phaseID != filter.PhaseID {
+			continue
+		}
+		if filter.TaskID != "" && taskID != filter.TaskID {
+			continue
+		}
+		if filter.CorrelationID != "" && event.CorrelationID != filter.CorrelationID {
+			continue
+		}
 		events = append(events, Event{
+			ID:        event.ID,
+			EpicID:    qs.epic.ID,
 			Timestamp: event.Timestamp,
-			Agent:     "", // Field not available in current epic model
-			PhaseID:   "", // Field not available in current epic model
+			Agent:     event.Actor,
+			PhaseID:   phaseID,
+			TaskID:    taskID,
 			Type:      event.Type,
 			Content:   event.Data, // Using Data field as Content
+
+			CorrelationID: event.CorrelationID,
 		})
 	}
 
 	// Sort by timestamp in reverse chronological order (most recent first)
-	sort.Slice(events, func(i, j int) bool {
+	sort.SliceStable(events, func(i, j int) bool {
 		return events[i].Timestamp.After(events[j].Timestamp)
 	})
 
 	// Apply limit
-	if len(events) > limit {
+	if limit > 0 && len(events) > limit {
 		events = events[:limit]
 	}
 
 	return events, nil
 }
 
+// RecentEventsAcross returns the events of the epics at paths matching
+// filter in reverse chronological order, as found by a storage backend. The
+// limit defaults like GetRecentEvents but is not capped.
+func RecentEventsAcross(backend storage.Backend, paths []string, filter EventFilter) ([]Event, error) {
+	limit := filter.Limit
+	if limit == 0 {
+		limit = 10
+	}
+	found, err := backend.Events(paths, storage.EventFilter{
+		Types:   filter.Types,
+		Since:   filter.Since,
+		PhaseID: filter.PhaseID,
+		TaskID:  filter.TaskID,
+		Limit:   limit,
+
+		CorrelationID: filter.CorrelationID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]Event, 0, len(found))
+	for _, event := range found {
+		events = append(events, Event{
+			ID:        event.Event.ID,
+			EpicID:    event.EpicID,
+			Timestamp: event.Event.Timestamp,
+			Agent:     event.Event.Actor,
+			PhaseID:   event.PhaseID,
+			TaskID:    event.TaskID,
+			Type:      event.Event.Type,
+			Content:   event.Event.Data,
+
+			CorrelationID: event.Event.CorrelationID,
+		})
+	}
+	return events, nil
+}
+
+func (f EventFilter) matchesType(eventType string) bool {
+	return storage.EventFilter{Types: f.Types}.MatchesType(eventType)
+}
+
 // Helper methods for internal logic
 
 // getPhaseStatus determines the status of a phase based on its tasks
@@ -354,6 +748,16 @@ func (qs *QueryService) getPhaseStatus(phaseID string) epic.Status {
 	return epic.StatusPending
 }
 
+// getPhaseAssignee returns the assignee set on a phase, or ""
+func (qs *QueryService) getPhaseAssignee(phaseID string) string {
+	for _, phase := range qs.epic.Phases {
+		if phase.ID == phaseID {
+			return phase.Assignee
+		}
+	}
+	return ""
+}
+
 // getTasksForPhase returns all tasks for a given phase
 func (qs *QueryService) getTasksForPhase(phaseID string) []epic.Task {
 	var tasks []epic.Task
@@ -383,12 +787,11 @@ func (qs *QueryService) findCurrentPhase() string {
 	return ""
 }
 
-// findCurrentTask finds the currently active task (active status)
+// findCurrentTask finds the currently active task (active status); an active
+// sub-task is current rather than its parent
 func (qs *QueryService) findCurrentTask() string {
-	for _, task := range qs.epic.Tasks {
-		if task.Status == epic.StatusWIP {
-			return task.ID
-		}
+	if task := qs.epic.ActiveTask(""); task != nil {
+		return task.ID
 	}
 	return ""
 }
@@ -398,7 +801,54 @@ type RelatedItem struct {
 	Type         string // "phase", "task", "test"
 	ID           string
 	Name         string
-	Relationship string // "dependency", "blocker", "sequence", "related"
+	Relationship string // "contains", "validates", "parent", "subtask", "dependency", "dependent"
+	ParentID     string `json:",omitempty"` // Parent task of a sub-task listed as "contains"
+}
+
+// phaseDependencyItems lists the phases a phase depends on ("dependency") and
+// the phases depending on it ("dependent")
+func (qs *QueryService) phaseDependencyItems(phaseID string) []RelatedItem {
+	var related []RelatedItem
+	names := make(map[string]string)
+	for _, phase := range qs.epic.Phases {
+		names[phase.ID] = phase.Name
+	}
+	for i := range qs.epic.Phases {
+		if qs.epic.Phases[i].ID != phaseID {
+			continue
+		}
+		for _, id := range qs.epic.Phases[i].DependencyIDs() {
+			related = append(related, RelatedItem{Type: "phase", ID: id, Name: names[id], Relationship: "dependency"})
+		}
+		break
+	}
+	for _, id := range qs.epic.PhaseDependents(phaseID) {
+		related = append(related, RelatedItem{Type: "phase", ID: id, Name: names[id], Relationship: "dependent"})
+	}
+	return related
+}
+
+// taskDependencyItems lists the tasks a task depends on ("dependency") and
+// the tasks depending on it ("dependent")
+func (qs *QueryService) taskDependencyItems(taskID string) []RelatedItem {
+	var related []RelatedItem
+	names := make(map[string]string)
+	for _, task := range qs.epic.Tasks {
+		names[task.ID] = task.Name
+	}
+	for i := range qs.epic.Tasks {
+		if qs.epic.Tasks[i].ID != taskID {
+			continue
+		}
+		for _, id := range qs.epic.Tasks[i].DependencyIDs() {
+			related = append(related, RelatedItem{Type: "task", ID: id, Name: names[id], Relationship: "dependency"})
+		}
+		break
+	}
+	for _, id := range qs.epic.TaskDependents(taskID) {
+		related = append(related, RelatedItem{Type: "task", ID: id, Name: names[id], Relationship: "dependent"})
+	}
+	return related
 }
 
 // GetRelatedItems finds items related to a given phase, task, or test
@@ -419,6 +869,7 @@ func (qs *QueryService) GetRelatedItems(itemType, itemID string) ([]RelatedItem,
 					ID:           task.ID,
 					Name:         task.Name,
 					Relationship: "contains",
+					ParentID:     task.ParentTaskID,
 				})
 			}
 		}
@@ -437,9 +888,11 @@ func (qs *QueryService) GetRelatedItems(itemType, itemID string) ([]RelatedItem,
 			}
 		}
 
+		related = append(related, qs.phaseDependencyItems(itemID)...)
+
 	case "task":
-		// Find parent phase
-		for _, task := range qs.epic.Tasks {
+		// Find parent phase, and the parent task of a sub-task
+		for i, task := range qs.epic.Tasks {
 			if task.ID == itemID {
 				for _, phase := range qs.epic.Phases {
 					if phase.ID == task.PhaseID {
@@ -451,10 +904,28 @@ func (qs *QueryService) GetRelatedItems(itemType, itemID string) ([]RelatedItem,
 						})
 					}
 				}
+				if parent := qs.epic.ParentTask(&qs.epic.Tasks[i]); parent != nil {
+					related = append(related, RelatedItem{
+						Type:         "task",
+						ID:           parent.ID,
+						Name:         parent.Name,
+						Relationship: "parent",
+					})
+				}
 				break
 			}
 		}
 
+		// Find sub-tasks of this task
+		for _, subTask := range qs.epic.SubTasks(itemID) {
+			related = append(related, RelatedItem{
+				Type:         "task",
+				ID:           subTask.ID,
+				Name:         subTask.Name,
+				Relationship: "subtask",
+			})
+		}
+
 		// Find tests for this task
 		for _, test := range qs.epic.Tests {
 			if test.TaskID == itemID {
@@ -467,6 +938,8 @@ func (qs *QueryService) GetRelatedItems(itemType, itemID string) ([]RelatedItem,
 			}
 		}
 
+		related = append(related, qs.taskDependencyItems(itemID)...)
+
 	case "test":
 		// Find parent task and phase
 		for _, test := range qs.epic.Tests {
@@ -649,7 +1122,7 @@ func (qs *QueryService) GetProgressInsights() (*ProgressInsight, error) {
 
 	activeTaskCount := 0
 	for _, task := range qs.epic.Tasks {
-		if task.Status == epic.StatusWIP {
+		if task.Status == epic.StatusWIP && !qs.epic.HasActiveSubTask(task.ID) {
 			activeTaskCount++
 		}
 	}
@@ -759,15 +1232,20 @@ func (qs *QueryService) calculateEnhancedCompletionPercentage() int {
 		phaseCompletion = float64(completedPhases) / float64(totalPhases)
 	}
 
-	// Calculate task completion
+	// Calculate task completion; upcoming occurrences of recurring tasks are
+	// not outstanding work
 	if totalTasks > 0 {
 		completedTasks := 0
 		for _, task := range qs.epic.Tasks {
 			if task.Status == epic.StatusCompleted {
 				completedTasks++
+			} else if task.IsUpcoming() {
+				totalTasks--
 			}
 		}
-		taskCompletion = float64(completedTasks) / float64(totalTasks)
+		if totalTasks > 0 {
+			taskCompletion = float64(completedTasks) / float64(totalTasks)
+		}
 	}
 
 	// Calculate test completion
@@ -903,7 +1381,8 @@ func (qs *QueryService) validateEpicState() (string, []string) {
 	activeTasks := 0
 	var activeTaskPhases []string
 	for _, task := range qs.epic.Tasks {
-		if task.Status == epic.StatusWIP {
+		// A parent task is active alongside its active sub-task
+		if task.Status == epic.StatusWIP && !qs.epic.HasActiveSubTask(task.ID) {
 			activeTasks++
 			activeTaskPhases = append(activeTaskPhases, task.PhaseID)
 		}
@@ -1127,12 +1606,9 @@ func (qs *QueryService) generateNextActions() []string {
 	} else {
 		// Active phase exists - check for next task or completion
 		hasActiveTask := false
-		for _, task := range qs.epic.Tasks {
-			if task.PhaseID == activePhase && task.GetTaskStatus() == epic.TaskStatusWIP {
-				hasActiveTask = true
-				actions = append(actions, fmt.Sprintf("Complete active task: %s", task.Name))
-				break
-			}
+		if task := qs.epic.ActiveTask(activePhase); task != nil {
+			hasActiveTask = true
+			actions = append(actions, fmt.Sprintf("Complete active task: %s", task.Name))
 		}
 
 		if !hasActiveTask {
@@ -182,6 +182,14 @@ func TestQueryService_GetCurrentState(t *testing.T) {
 		assert.Equal(t, "T3", state.ActiveTask)
 		assert.Equal(t, 2, state.FailingTests)
 		assert.Contains(t, state.NextAction, "Fix failing tests")
+
+		require.NotNil(t, state.ActiveTaskDetails)
+		assert.Equal(t, "Task 3", state.ActiveTaskDetails.Name)
+		assert.Equal(t, "P2", state.ActiveTaskDetails.PhaseID)
+		require.Len(t, state.PendingTests, 1)
+		assert.Equal(t, "TEST3", state.PendingTests[0].ID)
+		assert.Equal(t, "P2", state.PendingTests[0].PhaseID)
+		assert.Empty(t, state.Blockers)
 	})
 
 	t.Run("epic with no active work", func(t *testing.T) {
@@ -202,6 +210,29 @@ func TestQueryService_GetCurrentState(t *testing.T) {
 		assert.Equal(t, "", state.ActiveTask)
 		assert.Equal(t, 0, state.FailingTests)
 		assert.Equal(t, "Epic ready for completion", state.NextAction)
+		assert.Nil(t, state.ActiveTaskDetails)
+		assert.Empty(t, state.PendingTests)
+	})
+
+	t.Run("blockers include failing tests and blocker events", func(t *testing.T) {
+		storage := storage.NewMemoryStorage()
+		testEpic := createTestEpic()
+		failedAt := time.Date(2025, 8, 16, 12, 0, 0, 0, time.UTC)
+		testEpic.Tests[2].Status = epic.StatusWIP
+		testEpic.Tests[2].TestStatus = epic.TestStatusWIP
+		testEpic.Tests[2].FailedAt = &failedAt
+		testEpic.Events = append(testEpic.Events, epic.Event{ID: "E4", Type: "blocker", Timestamp: failedAt, Data: "API keys missing"})
+		err := storage.SaveEpic(testEpic, "test.xml")
+		require.NoError(t, err)
+
+		qs := NewQueryService(storage)
+		err = qs.LoadEpic("test.xml")
+		require.NoError(t, err)
+
+		state, err := qs.GetCurrentState()
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"Failing test TEST3: Test 3", "API keys missing"}, state.Blockers)
 	})
 }
 
@@ -306,6 +337,27 @@ func TestQueryService_GetFailingTests(t *testing.T) {
 	}
 }
 
+func TestQueryService_GetManualChecks(t *testing.T) {
+	qs := NewQueryService(storage.NewMemoryStorage())
+	qs.epic = &epic.Epic{
+		ID: "epic-1",
+		Tests: []epic.Test{
+			{ID: "manual-passed", TaskID: "task-1", Type: "manual", TestStatus: epic.TestStatusDone, VerifiedBy: "alice"},
+			{ID: "manual-failed", TaskID: "task-1", Type: "manual", TestStatus: epic.TestStatusWIP, VerifiedBy: "bob", FailureNote: "Logo cut off"},
+			{ID: "manual-pending", TaskID: "task-1", Type: "Manual", TestStatus: epic.TestStatusPending},
+			{ID: "manual-cancelled", TaskID: "task-1", Type: "manual", TestStatus: epic.TestStatusCancelled},
+			{ID: "automated", TaskID: "task-1", TestStatus: epic.TestStatusPending},
+		},
+		Tasks: []epic.Task{{ID: "task-1", PhaseID: "phase-1"}},
+	}
+
+	checks, err := qs.GetManualChecks()
+	require.NoError(t, err)
+	require.Len(t, checks, 2)
+	assert.Equal(t, FailingTest{ID: "manual-failed", PhaseID: "phase-1", TaskID: "task-1", FailureNote: "Logo cut off", Manual: true, VerifiedBy: "bob"}, checks[0])
+	assert.Equal(t, "manual-pending", checks[1].ID)
+}
+
 func TestQueryService_GetRelatedItems(t *testing.T) {
 	storage := storage.NewMemoryStorage()
 	qs := NewQueryService(storage)
@@ -487,7 +539,7 @@ func TestQueryService_GetRecentEvents(t *testing.T) {
 		err = qs.LoadEpic("test.xml")
 		require.NoError(t, err)
 
-		events, err := qs.GetRecentEvents(0) // default limit
+		events, err := qs.GetRecentEvents(EventFilter{}) // default limit
 		require.NoError(t, err)
 
 		assert.Len(t, events, 3)
@@ -507,7 +559,7 @@ func TestQueryService_GetRecentEvents(t *testing.T) {
 		err = qs.LoadEpic("test.xml")
 		require.NoError(t, err)
 
-		events, err := qs.GetRecentEvents(2)
+		events, err := qs.GetRecentEvents(EventFilter{Limit: 2})
 		require.NoError(t, err)
 
 		assert.Len(t, events, 2)
@@ -525,13 +577,68 @@ func TestQueryService_GetRecentEvents(t *testing.T) {
 		err = qs.LoadEpic("test.xml")
 		require.NoError(t, err)
 
-		events, err := qs.GetRecentEvents(200) // exceeds max of 100
+		events, err := qs.GetRecentEvents(EventFilter{Limit: 200}) // exceeds max of 100
 		require.NoError(t, err)
 
 		assert.Len(t, events, 3) // all available events
 	})
 }
 
+func TestQueryService_GetRecentEventsFilter(t *testing.T) {
+	storage := storage.NewMemoryStorage()
+	testEpic := createTestEpic()
+	testEpic.Events = append(testEpic.Events,
+		epic.Event{ID: "E4", Type: "test_passed", Timestamp: time.Date(2025, 8, 16, 12, 0, 0, 0, time.UTC), Actor: "agent-2", Data: "Test TEST2 passed"},
+		epic.Event{ID: "E5", Type: "task_started", Timestamp: time.Date(2025, 8, 16, 13, 0, 0, 0, time.UTC), Data: "Task T3 (Task 3) started"},
+	)
+	require.NoError(t, storage.SaveEpic(testEpic, "test.xml"))
+
+	qs := NewQueryService(storage)
+	require.NoError(t, qs.LoadEpic("test.xml"))
+
+	ids := func(events []Event) []string {
+		var result []string
+		for _, event := range events {
+			result = append(result, event.ID)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		filter   EventFilter
+		expected []string
+	}{
+		{"no filter", EventFilter{}, []string{"E5", "E4", "E3", "E2", "E1"}},
+		{"exact type", EventFilter{Types: []string{"task_started"}}, []string{"E5"}},
+		{"type prefix", EventFilter{Types: []string{"task", "phase"}}, []string{"E5", "E3", "E2"}},
+		{"since", EventFilter{Since: time.Date(2025, 8, 16, 11, 0, 0, 0, time.UTC)}, []string{"E5", "E4", "E3"}},
+		{"phase includes tasks and tests", EventFilter{PhaseID: "P1"}, []string{"E4", "E2"}},
+		{"phase event", EventFilter{PhaseID: "P2"}, []string{"E5", "E3"}},
+		{"task includes tests", EventFilter{TaskID: "T2"}, []string{"E4"}},
+		{"combined", EventFilter{PhaseID: "P1", Types: []string{"task"}}, []string{"E2"}},
+		{"limit", EventFilter{Limit: 2}, []string{"E5", "E4"}},
+		{"no limit", EventFilter{Limit: -1}, []string{"E5", "E4", "E3", "E2", "E1"}},
+		{"no match", EventFilter{TaskID: "T9"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := qs.GetRecentEvents(tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ids(events))
+		})
+	}
+
+	t.Run("resolves scope and agent", func(t *testing.T) {
+		events, err := qs.GetRecentEvents(EventFilter{TaskID: "T2"})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "P1", events[0].PhaseID)
+		assert.Equal(t, "T2", events[0].TaskID)
+		assert.Equal(t, "agent-2", events[0].Agent)
+	})
+}
+
 func TestQueryService_PhaseStatusDetermination(t *testing.T) {
 	storage := storage.NewMemoryStorage()
 	testEpic := createTestEpic()
@@ -693,7 +800,7 @@ func TestQueryService_EdgeCases(t *testing.T) {
 		_, err = qs.GetFailingTests()
 		assert.Error(t, err)
 
-		_, err = qs.GetRecentEvents(10)
+		_, err = qs.GetRecentEvents(EventFilter{Limit: 10})
 		assert.Error(t, err)
 	})
 }
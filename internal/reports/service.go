@@ -2,10 +2,12 @@ package reports
 
 import (
 	"fmt"
-	"strings"
+	"regexp"
+	"sort"
 	"time"
 
 	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/output"
 	"github.com/mindreframer/agentpm/internal/storage"
 )
 
@@ -30,12 +32,16 @@ func (rs *ReportService) LoadEpic(filename string) error {
 }
 
 type HandoffReport struct {
-	EpicInfo     EpicInfo     `xml:"epic_info"`
-	CurrentState CurrentState `xml:"current_state"`
-	Summary      Summary      `xml:"summary"`
-	RecentEvents []Event      `xml:"recent_events>event"`
-	Blockers     []string     `xml:"blockers>blocker"`
-	GeneratedAt  time.Time    `xml:"generated_at,attr"`
+	EpicInfo     EpicInfo      `xml:"epic_info"`
+	CurrentState CurrentState  `xml:"current_state"`
+	Summary      Summary       `xml:"summary"`
+	OpenBlockers []OpenBlocker `xml:"open_blockers>blocker"`
+	FailingTests []FailingTest `xml:"failing_tests>test"`
+	FlakyTests   []FlakyTest   `xml:"flaky_tests>test"`
+	Notes        []HandoffNote `xml:"notes>note"`
+	RecentEvents []Event       `xml:"recent_events>event"`
+	Blockers     []string      `xml:"blockers>blocker"`
+	GeneratedAt  time.Time     `xml:"generated_at,attr"`
 }
 
 type EpicInfo struct {
@@ -60,6 +66,43 @@ type Summary struct {
 	CompletionPercentage int `xml:"completion_percentage"`
 }
 
+// OpenBlocker is a blocker or open question raised on the epic and not resolved yet
+type OpenBlocker struct {
+	ID          string    `xml:"id,attr" json:"id"`
+	Type        string    `xml:"type,attr" json:"type"` // blocker or question
+	Entity      string    `xml:"entity,attr,omitempty" json:"entity,omitempty"`
+	RaisedAt    time.Time `xml:"raised_at,attr" json:"raised_at"`
+	RaisedBy    string    `xml:"raised_by,attr,omitempty" json:"raised_by,omitempty"`
+	Description string    `xml:",chardata" json:"description"`
+}
+
+// FailingTest is a test whose last run failed, with the context given when it was failed
+type FailingTest struct {
+	ID          string               `xml:"id,attr" json:"id"`
+	TaskID      string               `xml:"task_id,attr" json:"task_id"`
+	Name        string               `xml:"name" json:"name"`
+	FailureNote string               `xml:"failure_note,omitempty" json:"failure_note,omitempty"`
+	Context     *epic.FailureContext `xml:"failure_context,omitempty" json:"failure_context,omitempty"`
+}
+
+// FlakyTest is a test that passes again after failing repeatedly, see epic.Test.Stability
+type FlakyTest struct {
+	ID        string `xml:"id,attr" json:"id"`
+	TaskID    string `xml:"task_id,attr" json:"task_id"`
+	FailCount int    `xml:"fail_count,attr" json:"fail_count"`
+	PassCount int    `xml:"pass_count,attr" json:"pass_count"`
+	Name      string `xml:",chardata" json:"name"`
+}
+
+// HandoffNote is a note left on the epic, a phase, a task or a test
+type HandoffNote struct {
+	EntityType string    `xml:"entity_type,attr" json:"entity_type"` // epic, phase, task or test
+	EntityID   string    `xml:"entity_id,attr" json:"entity_id"`
+	Author     string    `xml:"author,attr,omitempty" json:"author,omitempty"`
+	CreatedAt  time.Time `xml:"created_at,attr" json:"created_at"`
+	Text       string    `xml:",chardata" json:"text"`
+}
+
 type Event struct {
 	Timestamp time.Time `xml:"timestamp,attr"`
 	Type      string    `xml:"type,attr"`
@@ -98,7 +141,11 @@ func (rs *ReportService) GenerateHandoffReport(limit int) (*HandoffReport, error
 	// Get recent events
 	report.RecentEvents = rs.getRecentEvents(limit)
 
-	// Identify blockers
+	// Collect open blockers and questions, and identify other blockers
+	report.OpenBlockers = rs.findOpenBlockers()
+	report.FailingTests = rs.findFailingTests()
+	report.FlakyTests = rs.findFlakyTests()
+	report.Notes = rs.collectNotes()
 	report.Blockers = rs.identifyBlockers()
 
 	return report, nil
@@ -114,10 +161,8 @@ func (rs *ReportService) findActivePhase() string {
 }
 
 func (rs *ReportService) findActiveTask() string {
-	for _, task := range rs.epic.Tasks {
-		if task.Status == epic.StatusWIP {
-			return task.ID
-		}
+	if task := rs.epic.ActiveTask(""); task != nil {
+		return task.ID
 	}
 	return ""
 }
@@ -243,6 +288,91 @@ func (rs *ReportService) getRecentEvents(limit int) []Event {
 	return events
 }
 
+// findOpenBlockers returns the unresolved blockers and questions of the epic
+// in the order they were raised
+func (rs *ReportService) findOpenBlockers() []OpenBlocker {
+	open := make([]OpenBlocker, 0)
+	for _, blocker := range rs.epic.OpenBlockers() {
+		open = append(open, OpenBlocker{
+			ID:          blocker.ID,
+			Type:        blocker.Kind(),
+			Entity:      blocker.Entity,
+			RaisedAt:    blocker.RaisedAt,
+			RaisedBy:    blocker.RaisedBy,
+			Description: blocker.Description,
+		})
+	}
+	return open
+}
+
+// findFlakyTests returns the flaky tests of the epic, so that the next agent
+// does not mistake their next failure for a regression
+// findFailingTests returns the tests that were failed and are back in progress
+func (rs *ReportService) findFailingTests() []FailingTest {
+	failing := make([]FailingTest, 0)
+	for _, test := range rs.epic.Tests {
+		if test.GetTestStatusUnified() != epic.TestStatusWIP || test.FailedAt == nil {
+			continue
+		}
+		failing = append(failing, FailingTest{
+			ID:          test.ID,
+			TaskID:      test.TaskID,
+			Name:        test.Name,
+			FailureNote: test.FailureNote,
+			Context:     test.FailureContext,
+		})
+	}
+	return failing
+}
+
+func (rs *ReportService) findFlakyTests() []FlakyTest {
+	flaky := make([]FlakyTest, 0)
+	for _, test := range rs.epic.Tests {
+		if !test.IsFlaky() {
+			continue
+		}
+		flaky = append(flaky, FlakyTest{
+			ID:        test.ID,
+			TaskID:    test.TaskID,
+			FailCount: test.FailCount,
+			PassCount: test.PassCount,
+			Name:      test.Name,
+		})
+	}
+	return flaky
+}
+
+// collectNotes returns the notes on the epic and all its phases, tasks and
+// tests, oldest first
+func (rs *ReportService) collectNotes() []HandoffNote {
+	notes := make([]HandoffNote, 0)
+	add := func(entityType, id string, entityNotes []epic.Note) {
+		for _, note := range entityNotes {
+			notes = append(notes, HandoffNote{
+				EntityType: entityType,
+				EntityID:   id,
+				Author:     note.Author,
+				CreatedAt:  note.CreatedAt,
+				Text:       note.Text,
+			})
+		}
+	}
+	add(epic.NoteOnEpic, rs.epic.ID, rs.epic.Notes)
+	for _, phase := range rs.epic.Phases {
+		add(epic.NoteOnPhase, phase.ID, phase.Notes)
+	}
+	for _, task := range rs.epic.Tasks {
+		add(epic.NoteOnTask, task.ID, task.Notes)
+	}
+	for _, test := range rs.epic.Tests {
+		add(epic.NoteOnTest, test.ID, test.Notes)
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		return notes[i].CreatedAt.Before(notes[j].CreatedAt)
+	})
+	return notes
+}
+
 func (rs *ReportService) identifyBlockers() []string {
 	blockers := make([]string, 0)
 
@@ -306,13 +436,14 @@ type TaskStatus struct {
 }
 
 type TaskDetail struct {
-	ID          string     `json:"id"`
-	PhaseID     string     `json:"phase_id"`
-	Name        string     `json:"name"`
-	Status      string     `json:"status"`
-	Assignee    string     `json:"assignee,omitempty"`
-	StartedAt   *time.Time `json:"started_at,omitempty"`
-	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ID           string     `json:"id"`
+	PhaseID      string     `json:"phase_id"`
+	ParentTaskID string     `json:"parent_task_id,omitempty"`
+	Name         string     `json:"name"`
+	Status       string     `json:"status"`
+	Assignee     string     `json:"assignee,omitempty"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
 }
 
 type TestResults struct {
@@ -414,28 +545,40 @@ func (rs *ReportService) generateTaskStatus() TaskStatus {
 		Tasks:      make([]TaskDetail, 0, len(rs.epic.Tasks)),
 	}
 
+	// Sub-tasks follow their parent task
 	for _, task := range rs.epic.Tasks {
-		if task.Status == epic.StatusCompleted {
-			status.CompletedTasks++
+		if rs.epic.ParentTask(&task) != nil {
+			continue
 		}
-		if task.Status == epic.StatusWIP {
-			status.ActiveTask = task.ID
+		status.add(task)
+		for _, subTask := range rs.epic.SubTasks(task.ID) {
+			status.add(*subTask)
 		}
+	}
 
-		taskDetail := TaskDetail{
-			ID:          task.ID,
-			PhaseID:     task.PhaseID,
-			Name:        task.Name,
-			Status:      string(task.Status),
-			Assignee:    task.Assignee,
-			StartedAt:   task.StartedAt,
-			CompletedAt: task.CompletedAt,
-		}
+	return status
+}
 
-		status.Tasks = append(status.Tasks, taskDetail)
+// add lists a task, counting it when completed and taking it as the active
+// task when it is wip; an active sub-task comes after its active parent
+func (status *TaskStatus) add(task epic.Task) {
+	if task.Status == epic.StatusCompleted {
+		status.CompletedTasks++
+	}
+	if task.Status == epic.StatusWIP {
+		status.ActiveTask = task.ID
 	}
 
-	return status
+	status.Tasks = append(status.Tasks, TaskDetail{
+		ID:           task.ID,
+		PhaseID:      task.PhaseID,
+		ParentTaskID: task.ParentTaskID,
+		Name:         task.Name,
+		Status:       string(task.Status),
+		Assignee:     task.Assignee,
+		StartedAt:    task.StartedAt,
+		CompletedAt:  task.CompletedAt,
+	})
 }
 
 func (rs *ReportService) generateTestResults() TestResults {
@@ -476,123 +619,132 @@ func (rs *ReportService) GenerateMarkdownDocumentation() (string, error) {
 }
 
 func (rs *ReportService) formatMarkdown(report *DocumentationReport) string {
-	var md strings.Builder
+	md := output.NewMarkdown()
 
 	// Title and overview
-	md.WriteString(fmt.Sprintf("# %s\n\n", report.EpicOverview.Name))
-	md.WriteString("## Epic Overview\n\n")
-	md.WriteString(fmt.Sprintf("- **ID:** %s\n", report.EpicOverview.ID))
-	md.WriteString(fmt.Sprintf("- **Status:** %s\n", rs.formatStatusIcon(report.EpicOverview.Status)))
-	md.WriteString(fmt.Sprintf("- **Assignee:** %s\n", report.EpicOverview.Assignee))
-	md.WriteString(fmt.Sprintf("- **Started:** %s\n", report.EpicOverview.Started.Format("2006-01-02 15:04:05")))
-	md.WriteString(fmt.Sprintf("- **Progress:** %d%% complete\n\n", report.EpicOverview.Completion))
+	md.Heading(1, report.EpicOverview.Name)
+	md.Heading(2, "Epic Overview")
+	md.Field("ID", report.EpicOverview.ID)
+	md.Field("Status", rs.formatStatusIcon(report.EpicOverview.Status))
+	md.Field("Assignee", report.EpicOverview.Assignee)
+	md.Field("Started", report.EpicOverview.Started.Format("2006-01-02 15:04:05"))
+	md.Field("Progress", fmt.Sprintf("%d%% complete", report.EpicOverview.Completion))
 
 	if report.EpicOverview.Description != "" {
-		md.WriteString(fmt.Sprintf("**Description:** %s\n\n", report.EpicOverview.Description))
+		md.Paragraph("**Description:** %s", output.Inline(report.EpicOverview.Description))
 	}
 
 	// Phase Progress
-	md.WriteString("## Phase Progress\n\n")
-	md.WriteString(fmt.Sprintf("**Completed:** %d/%d phases\n\n",
-		report.PhaseProgress.CompletedPhases, report.PhaseProgress.TotalPhases))
-
-	md.WriteString("| Phase | Status | Tasks | Started | Completed |\n")
-	md.WriteString("|-------|--------|-------|---------|----------|\n")
+	md.Heading(2, "Phase Progress")
+	md.Paragraph("**Completed:** %d/%d phases", report.PhaseProgress.CompletedPhases, report.PhaseProgress.TotalPhases)
 
+	var rows [][]string
 	for _, phase := range report.PhaseProgress.Phases {
-		started := "—"
-		if phase.StartedAt != nil {
-			started = phase.StartedAt.Format("2006-01-02")
-		}
-		completed := "—"
-		if phase.CompletedAt != nil {
-			completed = phase.CompletedAt.Format("2006-01-02")
-		}
-
-		md.WriteString(fmt.Sprintf("| %s | %s | %d | %s | %s |\n",
-			phase.Name, rs.formatStatusIcon(phase.Status), phase.TaskCount, started, completed))
+		rows = append(rows, []string{
+			anchorTag("phase", phase.ID) + phase.Name,
+			rs.formatStatusIcon(phase.Status),
+			fmt.Sprintf("%d", phase.TaskCount),
+			formatDocsDate(phase.StartedAt),
+			formatDocsDate(phase.CompletedAt),
+		})
 	}
-	md.WriteString("\n")
+	md.Table([]string{"Phase", "Status", "Tasks", "Started", "Completed"}, rows)
 
 	// Task Status
-	md.WriteString("## Task Status\n\n")
-	md.WriteString(fmt.Sprintf("**Completed:** %d/%d tasks\n",
-		report.TaskStatus.CompletedTasks, report.TaskStatus.TotalTasks))
+	md.Heading(2, "Task Status")
+	taskSummary := fmt.Sprintf("**Completed:** %d/%d tasks", report.TaskStatus.CompletedTasks, report.TaskStatus.TotalTasks)
 	if report.TaskStatus.ActiveTask != "" {
-		md.WriteString(fmt.Sprintf("**Active Task:** %s\n", report.TaskStatus.ActiveTask))
+		taskSummary += fmt.Sprintf("\n**Active Task:** %s", report.TaskStatus.ActiveTask)
 	}
-	md.WriteString("\n")
-
-	md.WriteString("| Task | Phase | Status | Assignee | Started | Completed |\n")
-	md.WriteString("|------|--------|--------|----------|---------|----------|\n")
+	md.Paragraph("%s", taskSummary)
 
+	rows = nil
 	for _, task := range report.TaskStatus.Tasks {
-		started := "—"
-		if task.StartedAt != nil {
-			started = task.StartedAt.Format("2006-01-02")
-		}
-		completed := "—"
-		if task.CompletedAt != nil {
-			completed = task.CompletedAt.Format("2006-01-02")
-		}
 		assignee := task.Assignee
 		if assignee == "" {
 			assignee = "—"
 		}
-
-		md.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n",
-			task.Name, task.PhaseID, rs.formatStatusIcon(task.Status), assignee, started, completed))
+		name := task.Name
+		if task.ParentTaskID != "" {
+			name = "↳ " + name
+		}
+		rows = append(rows, []string{
+			anchorTag("task", task.ID) + name,
+			task.PhaseID,
+			rs.formatStatusIcon(task.Status),
+			assignee,
+			formatDocsDate(task.StartedAt),
+			formatDocsDate(task.CompletedAt),
+		})
 	}
-	md.WriteString("\n")
+	md.Table([]string{"Task", "Phase", "Status", "Assignee", "Started", "Completed"}, rows)
 
 	// Test Results
-	md.WriteString("## Test Results\n\n")
-	md.WriteString(fmt.Sprintf("**Summary:** %d passing, %d failing (%d total)\n\n",
-		report.TestResults.PassingTests, report.TestResults.FailingTests, report.TestResults.TotalTests))
+	md.Heading(2, "Test Results")
+	md.Paragraph("**Summary:** %d passing, %d failing (%d total)",
+		report.TestResults.PassingTests, report.TestResults.FailingTests, report.TestResults.TotalTests)
 
 	if len(report.TestResults.Tests) > 0 {
-		md.WriteString("| Test | Task | Status | Notes |\n")
-		md.WriteString("|------|------|--------|---------|\n")
-
+		rows = nil
 		for _, test := range report.TestResults.Tests {
 			notes := test.FailureNote
 			if notes == "" {
 				notes = "—"
 			}
-
-			md.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
-				test.Name, test.TaskID, rs.formatTestStatusWithContext(test), notes))
+			rows = append(rows, []string{
+				anchorTag("test", test.ID) + test.Name,
+				test.TaskID,
+				rs.formatTestStatusWithContext(test),
+				notes,
+			})
 		}
-		md.WriteString("\n")
+		md.Table([]string{"Test", "Task", "Status", "Notes"}, rows)
 	}
 
 	// Blockers
 	if len(report.RecentActivity.Blockers) > 0 {
-		md.WriteString("## Blockers\n\n")
+		md.Heading(2, "Blockers")
 		for _, blocker := range report.RecentActivity.Blockers {
-			md.WriteString(fmt.Sprintf("- 🚫 %s\n", blocker))
+			md.Item("🚫 %s", output.Inline(blocker))
 		}
-		md.WriteString("\n")
 	}
 
 	// Recent Activity
 	if len(report.RecentActivity.Events) > 0 {
-		md.WriteString("## Recent Activity\n\n")
+		md.Heading(2, "Recent Activity")
 		for _, event := range report.RecentActivity.Events {
-			md.WriteString(fmt.Sprintf("- **%s** (%s): %s\n",
-				event.Timestamp.Format("2006-01-02 15:04"), event.Type, event.Data))
+			md.Item("**%s** (%s): %s", event.Timestamp.Format("2006-01-02 15:04"), event.Type, output.Inline(event.Data))
 		}
-		md.WriteString("\n")
 	}
 
 	// Footer
-	md.WriteString("---\n")
-	md.WriteString(fmt.Sprintf("*Generated on %s by AgentPM*\n",
-		report.GeneratedAt.Format("2006-01-02 15:04:05")))
+	md.Rule()
+	md.Raw(fmt.Sprintf("*Generated on %s by AgentPM*\n", report.GeneratedAt.Format("2006-01-02 15:04:05")))
 
 	return md.String()
 }
 
+// formatDocsDate writes the day of an optional timestamp, or a dash
+func formatDocsDate(t *time.Time) string {
+	if t == nil {
+		return "—"
+	}
+	return t.Format("2006-01-02")
+}
+
+var nonAnchorChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// Anchor returns the stable anchor of a phase, task or test in the generated
+// documentation, e.g. "task-1A_1"
+func Anchor(entityType, id string) string {
+	return entityType + "-" + nonAnchorChars.ReplaceAllString(id, "-")
+}
+
+// anchorTag is the HTML anchor that makes an entity's row linkable
+func anchorTag(entityType, id string) string {
+	return fmt.Sprintf(`<a id="%s"></a>`, Anchor(entityType, id))
+}
+
 func (rs *ReportService) formatStatusIcon(status string) string {
 	switch status {
 	case "completed":
@@ -334,3 +334,37 @@ func TestReportService_EpicInfoExtraction(t *testing.T) {
 		assert.Equal(t, time.Date(2025, 8, 16, 9, 0, 0, 0, time.UTC), info.Started)
 	})
 }
+
+func TestAnchor(t *testing.T) {
+	assert.Equal(t, "task-1A_1", Anchor("task", "1A_1"))
+	assert.Equal(t, "test-api-v2-login", Anchor("test", "api.v2/login"))
+}
+
+func TestReportService_DocumentationSubTasks(t *testing.T) {
+	storage := storage.NewMemoryStorage()
+	require.NoError(t, storage.SaveEpic(&epic.Epic{
+		ID:     "epic-1",
+		Name:   "Epic",
+		Phases: []epic.Phase{{ID: "1A", Name: "Phase", Status: epic.StatusWIP}},
+		Tasks: []epic.Task{
+			{ID: "1A_1", PhaseID: "1A", Name: "Parent", Status: epic.StatusWIP},
+			{ID: "1A_2", PhaseID: "1A", Name: "Other", Status: epic.StatusPending},
+			{ID: "1A_1a", PhaseID: "1A", Name: "Child", Status: epic.StatusWIP, ParentTaskID: "1A_1"},
+		},
+	}, "test.xml"))
+	rs := NewReportService(storage)
+	require.NoError(t, rs.LoadEpic("test.xml"))
+
+	report, err := rs.GenerateDocumentationReport()
+	require.NoError(t, err)
+	var order []string
+	for _, task := range report.TaskStatus.Tasks {
+		order = append(order, task.ID)
+	}
+	assert.Equal(t, []string{"1A_1", "1A_1a", "1A_2"}, order, "sub-tasks follow their parent")
+	assert.Equal(t, "1A_1a", report.TaskStatus.ActiveTask, "the active sub-task is the active task")
+
+	markdown, err := rs.GenerateMarkdownDocumentation()
+	require.NoError(t, err)
+	assert.Contains(t, markdown, `<a id="task-1A_1a"></a>↳ Child`)
+}
@@ -0,0 +1,240 @@
+package scaffold
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// Options control how a brief is turned into an epic
+type Options struct {
+	ID        string
+	Name      string // defaults to the brief's title heading
+	MaxPhases int    // 0 = one phase per heading
+	CreatedAt time.Time
+}
+
+var (
+	headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+	bulletPattern  = regexp.MustCompile(`^(\s*)(?:[-*+]|\d+[.)])\s+(.+)$`)
+	checkboxPrefix = regexp.MustCompile(`^\[[ xX]\]\s+`)
+)
+
+type heading struct {
+	level int
+	text  string
+}
+
+type section struct {
+	name        string
+	description []string
+	tasks       []*taskDraft
+}
+
+type taskDraft struct {
+	name    string
+	details []string
+}
+
+// FromBrief derives a skeleton epic from a Markdown brief without interpreting its
+// prose: the brief's title becomes the epic name, its headings become phases,
+// top-level list items below a heading become tasks, and every task gets one
+// placeholder test. The same brief always produces the same epic.
+//
+// A single top-level (#) heading is the title, phases then come from the next
+// level. Deeper headings are folded into the phase they appear in, and with
+// MaxPhases set the headings beyond the limit are folded into the last phase.
+func FromBrief(brief string, opts Options) (*epic.Epic, error) {
+	lines := readLines(brief)
+	title, phaseLevel := detectStructure(lines)
+
+	var intro []string
+	var sections []*section
+	var current *section
+	var lastTask *taskDraft
+	inCode := false
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+
+		if h, ok := parseHeading(line); ok {
+			if h.level == phaseLevel {
+				if opts.MaxPhases > 0 && len(sections) == opts.MaxPhases {
+					// Beyond the limit: keep collecting tasks in the last phase
+					lastTask = nil
+					continue
+				}
+				current = &section{name: h.text}
+				sections = append(sections, current)
+				lastTask = nil
+			}
+			continue
+		}
+
+		if match := bulletPattern.FindStringSubmatch(line); match != nil && current != nil {
+			text := cleanText(match[2])
+			if indentWidth(match[1]) < 2 || lastTask == nil {
+				lastTask = &taskDraft{name: text}
+				current.tasks = append(current.tasks, lastTask)
+			} else {
+				lastTask.details = append(lastTask.details, text)
+			}
+			continue
+		}
+
+		if trimmed == "" {
+			continue
+		}
+		switch {
+		case current == nil:
+			intro = append(intro, trimmed)
+		case lastTask != nil && startsWithSpace(line):
+			// Continuation of a list item
+			lastTask.details = append(lastTask.details, trimmed)
+		case len(current.tasks) == 0:
+			current.description = append(current.description, trimmed)
+		}
+	}
+
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("brief has no headings to derive phases from")
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = title
+	}
+	if name == "" {
+		name = opts.ID
+	}
+
+	result := &epic.Epic{
+		ID:            opts.ID,
+		Name:          name,
+		Status:        epic.StatusPending,
+		CreatedAt:     opts.CreatedAt,
+		SchemaVersion: epic.CurrentSchemaVersion,
+		Description:   strings.Join(intro, " "),
+	}
+
+	for i, s := range sections {
+		phaseID := fmt.Sprintf("%d", i+1)
+		result.Phases = append(result.Phases, epic.Phase{
+			ID:          phaseID,
+			Name:        s.name,
+			Description: strings.Join(s.description, " "),
+			Status:      epic.StatusPending,
+		})
+
+		for j, t := range s.tasks {
+			taskID := fmt.Sprintf("%s_%d", phaseID, j+1)
+			result.Tasks = append(result.Tasks, epic.Task{
+				ID:          taskID,
+				PhaseID:     phaseID,
+				Name:        t.name,
+				Description: strings.Join(t.details, "\n"),
+				Status:      epic.StatusPending,
+			})
+			result.Tests = append(result.Tests, epic.Test{
+				ID:          "T" + taskID,
+				TaskID:      taskID,
+				PhaseID:     phaseID,
+				Name:        "Verify " + t.name,
+				Description: "TODO: describe how to verify: " + t.name,
+				Status:      epic.StatusPending,
+				TestStatus:  epic.TestStatusPending,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+func readLines(brief string) []string {
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(brief))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), " \t\r"))
+	}
+	return lines
+}
+
+// detectStructure returns the title and the heading level that becomes phases
+func detectStructure(lines []string) (string, int) {
+	var headings []heading
+	inCode := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+			continue
+		}
+		if h, ok := parseHeading(line); ok && !inCode {
+			headings = append(headings, h)
+		}
+	}
+	if len(headings) == 0 {
+		return "", 0
+	}
+
+	top := headings[0].level
+	for _, h := range headings {
+		if h.level < top {
+			top = h.level
+		}
+	}
+
+	var topHeadings []heading
+	for _, h := range headings {
+		if h.level == top {
+			topHeadings = append(topHeadings, h)
+		}
+	}
+	if len(topHeadings) > 1 || len(headings) == 1 {
+		return "", top
+	}
+
+	// A single top heading is the title; phases come from the next level used
+	next := 0
+	for _, h := range headings {
+		if h.level > top && (next == 0 || h.level < next) {
+			next = h.level
+		}
+	}
+	return topHeadings[0].text, next
+}
+
+func parseHeading(line string) (heading, bool) {
+	match := headingPattern.FindStringSubmatch(line)
+	if match == nil {
+		return heading{}, false
+	}
+	return heading{level: len(match[1]), text: cleanText(match[2])}, true
+}
+
+// cleanText strips checkboxes and inline emphasis markers from Markdown text
+func cleanText(text string) string {
+	text = checkboxPrefix.ReplaceAllString(strings.TrimSpace(text), "")
+	text = strings.NewReplacer("**", "", "__", "", "`", "").Replace(text)
+	return strings.TrimSpace(text)
+}
+
+func indentWidth(indent string) int {
+	return len(strings.ReplaceAll(indent, "\t", "    "))
+}
+
+func startsWithSpace(line string) bool {
+	return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+}
@@ -0,0 +1,113 @@
+package scaffold
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const brief = "# Search Feature\n" +
+	"\n" +
+	"Add full text search to the catalog.\n" +
+	"\n" +
+	"## Indexing\n" +
+	"\n" +
+	"Build the search index.\n" +
+	"\n" +
+	"- Define **index schema**\n" +
+	"  - fields: title, body\n" +
+	"- [ ] Write indexer\n" +
+	"1. Schedule reindex job\n" +
+	"\n" +
+	"```\n" +
+	"## not a heading\n" +
+	"- not a task\n" +
+	"```\n" +
+	"\n" +
+	"## Query API\n" +
+	"- Parse query syntax\n" +
+	"\n" +
+	"### Ranking\n" +
+	"- Rank results\n" +
+	"\n" +
+	"## UI\n" +
+	"- Search box\n"
+
+func TestFromBrief(t *testing.T) {
+	createdAt := time.Date(2025, 8, 16, 9, 0, 0, 0, time.UTC)
+
+	result, err := FromBrief(brief, Options{ID: "search", CreatedAt: createdAt})
+	require.NoError(t, err)
+
+	assert.Equal(t, "search", result.ID)
+	assert.Equal(t, "Search Feature", result.Name)
+	assert.Equal(t, epic.StatusPending, result.Status)
+	assert.Equal(t, createdAt, result.CreatedAt)
+	assert.Equal(t, "Add full text search to the catalog.", result.Description)
+
+	require.Len(t, result.Phases, 3)
+	assert.Equal(t, epic.Phase{ID: "1", Name: "Indexing", Description: "Build the search index.", Status: epic.StatusPending}, result.Phases[0])
+	assert.Equal(t, "Query API", result.Phases[1].Name)
+	assert.Equal(t, "UI", result.Phases[2].Name)
+
+	var names []string
+	for _, task := range result.Tasks {
+		names = append(names, task.ID+" "+task.Name)
+	}
+	assert.Equal(t, []string{
+		"1_1 Define index schema",
+		"1_2 Write indexer",
+		"1_3 Schedule reindex job",
+		"2_1 Parse query syntax",
+		"2_2 Rank results",
+		"3_1 Search box",
+	}, names)
+	assert.Equal(t, "fields: title, body", result.Tasks[0].Description)
+
+	require.Len(t, result.Tests, len(result.Tasks))
+	assert.Equal(t, epic.Test{
+		ID:          "T1_1",
+		TaskID:      "1_1",
+		PhaseID:     "1",
+		Name:        "Verify Define index schema",
+		Description: "TODO: describe how to verify: Define index schema",
+		Status:      epic.StatusPending,
+		TestStatus:  epic.TestStatusPending,
+	}, result.Tests[0])
+}
+
+func TestFromBriefMaxPhases(t *testing.T) {
+	result, err := FromBrief(brief, Options{ID: "search", MaxPhases: 2})
+	require.NoError(t, err)
+
+	require.Len(t, result.Phases, 2)
+	last := result.Tasks[len(result.Tasks)-1]
+	assert.Equal(t, "2_3", last.ID, "tasks of later headings are folded into the last phase")
+	assert.Equal(t, "Search box", last.Name)
+}
+
+func TestFromBriefIsDeterministic(t *testing.T) {
+	first, err := FromBrief(brief, Options{ID: "search"})
+	require.NoError(t, err)
+	second, err := FromBrief(brief, Options{ID: "search"})
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+}
+
+func TestFromBriefWithoutTitle(t *testing.T) {
+	result, err := FromBrief("# Backend\n- API\n# Frontend\n- Page\n", Options{ID: "app"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "app", result.Name)
+	require.Len(t, result.Phases, 2)
+	assert.Equal(t, "Backend", result.Phases[0].Name)
+	assert.Equal(t, "Frontend", result.Phases[1].Name)
+}
+
+func TestFromBriefWithoutHeadings(t *testing.T) {
+	_, err := FromBrief("- just a list\n", Options{ID: "empty"})
+	assert.EqualError(t, err, "brief has no headings to derive phases from")
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// RaiseBlocker records a new blocker, or an open question when question is
+// set, on the epic or on one of its phases or tasks
+func RaiseBlocker(e *epic.Epic, description, entity string, question bool, timestamp time.Time) (*epic.Blocker, error) {
+	if err := e.EnsureMutable("raise blocker"); err != nil {
+		return nil, err
+	}
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return nil, fmt.Errorf("a description is required to raise a blocker")
+	}
+	if entity != "" && findPhaseByID(e, entity) == nil && findTaskByID(e, entity) == nil {
+		return nil, fmt.Errorf("phase or task %s not found", entity)
+	}
+
+	blocker := epic.Blocker{
+		ID:          e.NextBlockerID(),
+		Entity:      entity,
+		RaisedAt:    timestamp,
+		RaisedBy:    actor,
+		Description: description,
+	}
+	if question {
+		blocker.Type = epic.BlockerTypeQuestion
+	}
+	e.Blockers = append(e.Blockers, blocker)
+
+	CreateEvent(e, EventBlockerRaised, "", "", "", blocker.ID, timestamp)
+	return e.FindBlocker(blocker.ID), nil
+}
+
+// ResolveBlocker marks an open blocker or question as resolved
+func ResolveBlocker(e *epic.Epic, id, resolution string, timestamp time.Time) (*epic.Blocker, error) {
+	if err := e.EnsureMutable("resolve blocker " + id); err != nil {
+		return nil, err
+	}
+	blocker := e.FindBlocker(id)
+	if blocker == nil {
+		return nil, fmt.Errorf("blocker %s not found", id)
+	}
+	if blocker.IsResolved() {
+		return nil, fmt.Errorf("blocker %s is already resolved", id)
+	}
+
+	blocker.ResolvedAt = &timestamp
+	blocker.Resolution = strings.TrimSpace(resolution)
+
+	CreateEvent(e, EventBlockerResolved, "", "", "", id, timestamp)
+	return blocker, nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+func TestRaiseAndResolveBlocker(t *testing.T) {
+	e := &epic.Epic{
+		ID:     "epic-1",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{{ID: "P1", Name: "Phase 1"}},
+		Tasks:  []epic.Task{{ID: "T1", PhaseID: "P1", Name: "Task 1"}},
+	}
+	raisedAt := time.Date(2025, 8, 16, 9, 0, 0, 0, time.UTC)
+	resolvedAt := raisedAt.Add(time.Hour)
+
+	SetActor("agent_a")
+	defer SetActor("")
+
+	blocker, err := RaiseBlocker(e, "  Waiting for API credentials ", "T1", false, raisedAt)
+	if err != nil {
+		t.Fatalf("RaiseBlocker() error = %v", err)
+	}
+	if blocker.ID != "B1" || blocker.Description != "Waiting for API credentials" || blocker.RaisedBy != "agent_a" {
+		t.Errorf("RaiseBlocker() = %+v", blocker)
+	}
+
+	question, err := RaiseBlocker(e, "Which API version?", "", true, raisedAt)
+	if err != nil {
+		t.Fatalf("RaiseBlocker() error = %v", err)
+	}
+	if question.ID != "B2" || !question.IsQuestion() {
+		t.Errorf("RaiseBlocker() question = %+v", question)
+	}
+
+	if _, err := ResolveBlocker(e, "B1", "Credentials arrived", resolvedAt); err != nil {
+		t.Fatalf("ResolveBlocker() error = %v", err)
+	}
+	if open := e.OpenBlockers(); len(open) != 1 || open[0].ID != "B2" {
+		t.Errorf("OpenBlockers() = %+v, want only B2", open)
+	}
+
+	wantEvents := []struct{ eventType, data string }{
+		{"blocker_raised", "Blocker B1 raised on T1: Waiting for API credentials"},
+		{"blocker_raised", "Blocker B2 (question) raised: Which API version?"},
+		{"blocker_resolved", "Blocker B1 resolved: Credentials arrived"},
+	}
+	if len(e.Events) != len(wantEvents) {
+		t.Fatalf("got %d events, want %d", len(e.Events), len(wantEvents))
+	}
+	for i, want := range wantEvents {
+		if e.Events[i].Type != want.eventType || e.Events[i].Data != want.data {
+			t.Errorf("event %d = %s %q, want %s %q", i, e.Events[i].Type, e.Events[i].Data, want.eventType, want.data)
+		}
+	}
+	if id := EventEntityID(e.Events[2]); id != "B1" {
+		t.Errorf("EventEntityID() = %q, want B1", id)
+	}
+}
+
+func TestRaiseAndResolveBlockerErrors(t *testing.T) {
+	e := &epic.Epic{ID: "epic-1", Status: epic.StatusWIP}
+	now := time.Date(2025, 8, 16, 9, 0, 0, 0, time.UTC)
+
+	if _, err := RaiseBlocker(e, " ", "", false, now); err == nil {
+		t.Error("RaiseBlocker() without description succeeded")
+	}
+	if _, err := RaiseBlocker(e, "Blocked", "T9", false, now); err == nil {
+		t.Error("RaiseBlocker() on an unknown task succeeded")
+	}
+	if _, err := ResolveBlocker(e, "B1", "", now); err == nil {
+		t.Error("ResolveBlocker() of an unknown blocker succeeded")
+	}
+
+	if _, err := RaiseBlocker(e, "Blocked", "", false, now); err != nil {
+		t.Fatalf("RaiseBlocker() error = %v", err)
+	}
+	if _, err := ResolveBlocker(e, "B1", "", now); err != nil {
+		t.Fatalf("ResolveBlocker() error = %v", err)
+	}
+	if _, err := ResolveBlocker(e, "B1", "", now); err == nil {
+		t.Error("ResolveBlocker() of a resolved blocker succeeded")
+	}
+
+	e.Status = epic.StatusCompleted
+	if _, err := RaiseBlocker(e, "Too late", "", false, now); !epic.IsEpicCompleted(err) {
+		t.Errorf("RaiseBlocker() on a completed epic error = %v", err)
+	}
+}
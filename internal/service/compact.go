@@ -0,0 +1,77 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// CompactEvents collapses all events before the cutoff into one
+// events_compacted event counting them by type, placed where the last of them
+// was and carrying its timestamp. Earlier summaries - of compactions and of
+// SummarizeEvents - are kept as they are, as their counts cannot be merged.
+// It returns nil and leaves the events alone when fewer than two events
+// precede the cutoff.
+func CompactEvents(e *epic.Epic, before time.Time) *EventSummary {
+	var indices []int
+	for i, event := range e.Events {
+		if event.Timestamp.Before(before) && !isSummaryEvent(event) {
+			indices = append(indices, i)
+		}
+	}
+	if len(indices) < 2 {
+		return nil
+	}
+
+	summary := &EventSummary{
+		From:   e.Events[indices[0]].Timestamp,
+		Events: len(indices),
+		Counts: make(map[string]int),
+	}
+	last := indices[0]
+	remove := make(map[int]bool, len(indices))
+	for _, i := range indices {
+		event := e.Events[i]
+		summary.Counts[event.Type]++
+		if event.Timestamp.Before(summary.From) {
+			summary.From = event.Timestamp
+		}
+		if !event.Timestamp.Before(summary.To) {
+			summary.To = event.Timestamp
+			last = i
+		}
+		remove[i] = true
+	}
+	summary.ID = fmt.Sprintf("%s_%d", EventCompacted, summary.To.Unix())
+	summary.Data = formatSummaryData(*summary)
+
+	events := make([]epic.Event, 0, len(e.Events)-len(indices)+1)
+	for i, event := range e.Events {
+		if !remove[i] {
+			events = append(events, event)
+		}
+		if i == last {
+			events = append(events, epic.Event{
+				ID:        summary.ID,
+				Type:      string(EventCompacted),
+				Timestamp: summary.To,
+				Data:      summary.Data,
+
+				CorrelationID: correlationID,
+			})
+		}
+	}
+	e.Events = events
+	return summary
+}
+
+// isSummaryEvent reports whether an event stands for other events, see
+// SummarizeEvents and CompactEvents
+func isSummaryEvent(event epic.Event) bool {
+	switch EventType(event.Type) {
+	case EventSummarized, EventCompacted:
+		return true
+	}
+	return false
+}
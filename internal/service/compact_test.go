@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompactEvents(t *testing.T) {
+	e := createBusyEpic()
+	cutoff := time.Date(2025, 8, 16, 18, 0, 0, 0, time.UTC)
+
+	summary := CompactEvents(e, cutoff)
+	if summary == nil {
+		t.Fatal("expected the events before the cutoff to be compacted")
+	}
+	wantData := "Summary of 6 events from 2025-08-16 09:00 to 2025-08-16 17:40: 1 phase_started, 1 task_completed, 1 task_started, 1 test_failed, 1 test_passed, 1 test_started"
+	if summary.Data != wantData {
+		t.Errorf("summary data = %q, want %q", summary.Data, wantData)
+	}
+
+	if len(e.Events) != 3 {
+		t.Fatalf("expected the summary and 2 later events, got %d events", len(e.Events))
+	}
+	compacted := e.Events[0]
+	if compacted.Type != string(EventCompacted) || compacted.Data != wantData {
+		t.Errorf("first event = %s %q, want the compaction summary", compacted.Type, compacted.Data)
+	}
+	if !compacted.Timestamp.Equal(time.Date(2025, 8, 16, 17, 40, 0, 0, time.UTC)) {
+		t.Errorf("summary timestamp = %v, want the last compacted event's", compacted.Timestamp)
+	}
+	if e.Events[1].Type != string(EventTaskStarted) || e.Events[2].Type != string(EventTaskStarted) {
+		t.Errorf("events after the cutoff changed: %+v", e.Events[1:])
+	}
+
+	// The summary is not compacted again, and one event is not worth a summary
+	later := time.Date(2025, 8, 16, 23, 0, 0, 0, time.UTC)
+	if summary := CompactEvents(e, later); summary != nil {
+		t.Errorf("expected nothing to compact, got %q", summary.Data)
+	}
+	if len(e.Events) != 3 {
+		t.Errorf("expected the events to stay as they are, got %d events", len(e.Events))
+	}
+}
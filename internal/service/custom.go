@@ -0,0 +1,113 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/customfields"
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// CustomFieldChange sets a custom field to Value, or removes it when Unset
+type CustomFieldChange struct {
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+	Unset bool   `json:"unset,omitempty"`
+}
+
+// CustomFieldsResult describes the custom fields of an entity after EditCustomFields
+type CustomFieldsResult struct {
+	EntityType string              `json:"entity_type"`
+	ID         string              `json:"id"`
+	Changes    []CustomFieldChange `json:"changes"`
+	Custom     epic.CustomFields   `json:"custom"`
+}
+
+// EditCustomFields applies changes to the custom fields of the epic, or of the
+// phase, task or test with the given ID. Each value is checked against the
+// type the schema declares for it and stored in its canonical form. Fields no
+// longer declared can still be unset.
+func EditCustomFields(e *epic.Epic, schema customfields.Schema, entityType, id string, changes []CustomFieldChange, timestamp time.Time) (*CustomFieldsResult, error) {
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("at least one custom field change is required")
+	}
+
+	var custom *epic.CustomFields
+	var label string
+	operation := fmt.Sprintf("edit %s %s", entityType, id)
+	switch entityType {
+	case "epic":
+		if err := e.EnsureMutable("edit epic"); err != nil {
+			return nil, err
+		}
+		id, custom, label = e.ID, &e.Custom, "Epic "+e.ID
+	case "phase":
+		phase := findPhaseByID(e, id)
+		if phase == nil {
+			return nil, fmt.Errorf("phase %s not found", id)
+		}
+		if err := e.EnsurePhaseMutable(phase.ID, operation); err != nil {
+			return nil, err
+		}
+		custom, label = &phase.Custom, "Phase "+id
+	case "task":
+		task := findTaskByID(e, id)
+		if task == nil {
+			return nil, fmt.Errorf("task %s not found", id)
+		}
+		if err := e.EnsurePhaseMutable(task.PhaseID, operation); err != nil {
+			return nil, err
+		}
+		custom, label = &task.Custom, "Task "+id
+	case "test":
+		test := findTestByID(e, id)
+		if test == nil {
+			return nil, fmt.Errorf("test %s not found", id)
+		}
+		if err := e.EnsurePhaseMutable(test.PhaseID, operation); err != nil {
+			return nil, err
+		}
+		custom, label = &test.Custom, "Test "+id
+	default:
+		return nil, fmt.Errorf("invalid entity type %q (expected epic, phase, task or test)", entityType)
+	}
+
+	// Check all changes before applying any
+	applied := make([]CustomFieldChange, 0, len(changes))
+	for _, change := range changes {
+		if change.Unset {
+			if _, stored := custom.Get(change.Name); stored {
+				applied = append(applied, change)
+				continue
+			}
+		}
+		def, err := schema.Lookup(entityType, change.Name)
+		if err != nil {
+			return nil, err
+		}
+		if !change.Unset {
+			value, err := def.Normalize(change.Value)
+			if err != nil {
+				return nil, fmt.Errorf("custom.%s: %w", change.Name, err)
+			}
+			change.Value = value
+		}
+		applied = append(applied, change)
+	}
+
+	descriptions := make([]string, 0, len(applied))
+	for _, change := range applied {
+		if change.Unset {
+			*custom = custom.Unset(change.Name)
+			descriptions = append(descriptions, change.Name+" unset")
+		} else {
+			*custom = custom.Set(change.Name, change.Value)
+			descriptions = append(descriptions, change.Name+"="+change.Value)
+		}
+	}
+
+	CreateEvent(e, EventCustomFieldsChanged, "", "", "", fmt.Sprintf("%s custom fields changed: %s", label, strings.Join(descriptions, ", ")), timestamp)
+
+	return &CustomFieldsResult{EntityType: entityType, ID: id, Changes: applied, Custom: *custom}, nil
+}
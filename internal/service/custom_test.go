@@ -0,0 +1,116 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/customfields"
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+func createCustomFieldsEpic() *epic.Epic {
+	frozenAt := time.Date(2025, 8, 16, 9, 0, 0, 0, time.UTC)
+	return &epic.Epic{
+		ID:     "epic-1",
+		Name:   "Custom Fields Epic",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{
+			{ID: "P1", Name: "Phase 1", Status: epic.StatusWIP},
+			{ID: "P2", Name: "Phase 2", Status: epic.StatusPending, FrozenAt: &frozenAt, FrozenReason: "Release"},
+		},
+		Tasks: []epic.Task{
+			{ID: "T1", PhaseID: "P1", Name: "Task 1", Status: epic.StatusWIP},
+			{ID: "T2", PhaseID: "P2", Name: "Task 2", Status: epic.StatusPending},
+		},
+		Tests: []epic.Test{
+			{ID: "TS1", TaskID: "T1", PhaseID: "P1", Name: "Test 1", Status: epic.StatusPending},
+		},
+	}
+}
+
+var customFieldsSchema = customfields.Schema{
+	"task": {
+		"sprint": {Type: customfields.TypeInt},
+		"risk":   {Type: customfields.TypeEnum, Values: []string{"low", "high"}},
+	},
+	"test": {"due": {Type: customfields.TypeDate}},
+}
+
+func TestEditCustomFields(t *testing.T) {
+	editedAt := time.Date(2025, 8, 17, 9, 0, 0, 0, time.UTC)
+
+	t.Run("stores values in their canonical form and records an event", func(t *testing.T) {
+		e := createCustomFieldsEpic()
+		result, err := EditCustomFields(e, customFieldsSchema, "task", "T1", []CustomFieldChange{
+			{Name: "sprint", Value: "07"},
+			{Name: "risk", Value: "High"},
+		}, editedAt)
+		if err != nil {
+			t.Fatalf("EditCustomFields() error = %v", err)
+		}
+
+		want := epic.CustomFields{{Name: "risk", Value: "high"}, {Name: "sprint", Value: "7"}}
+		if !reflect.DeepEqual(e.Tasks[0].Custom, want) || !reflect.DeepEqual(result.Custom, want) {
+			t.Errorf("custom fields = %+v, result %+v, want %+v", e.Tasks[0].Custom, result.Custom, want)
+		}
+		if len(e.Events) != 1 || e.Events[0].Type != string(EventCustomFieldsChanged) ||
+			e.Events[0].Data != "Task T1 custom fields changed: sprint=7, risk=high" {
+			t.Errorf("events = %+v", e.Events)
+		}
+
+		if _, err := EditCustomFields(e, customFieldsSchema, "task", "T1", []CustomFieldChange{{Name: "risk", Unset: true}}, editedAt); err != nil {
+			t.Fatalf("EditCustomFields() unset error = %v", err)
+		}
+		if want := (epic.CustomFields{{Name: "sprint", Value: "7"}}); !reflect.DeepEqual(e.Tasks[0].Custom, want) {
+			t.Errorf("custom fields after unset = %+v, want %+v", e.Tasks[0].Custom, want)
+		}
+	})
+
+	t.Run("rejects values of the wrong type without applying any change", func(t *testing.T) {
+		e := createCustomFieldsEpic()
+		_, err := EditCustomFields(e, customFieldsSchema, "task", "T1", []CustomFieldChange{
+			{Name: "risk", Value: "low"},
+			{Name: "sprint", Value: "soon"},
+		}, editedAt)
+		if err == nil || !strings.Contains(err.Error(), `custom.sprint: invalid value "soon": expected an integer`) {
+			t.Fatalf("EditCustomFields() error = %v", err)
+		}
+		if e.Tasks[0].Custom != nil || len(e.Events) != 0 {
+			t.Errorf("task changed after a rejected edit: %+v, events %+v", e.Tasks[0].Custom, e.Events)
+		}
+	})
+
+	t.Run("rejects undeclared fields", func(t *testing.T) {
+		e := createCustomFieldsEpic()
+		_, err := EditCustomFields(e, customFieldsSchema, "test", "TS1", []CustomFieldChange{{Name: "sprint", Value: "7"}}, editedAt)
+		if err == nil || !strings.Contains(err.Error(), `unknown custom field "sprint" for tests (declared: due)`) {
+			t.Errorf("EditCustomFields() error = %v", err)
+		}
+	})
+
+	t.Run("unsets fields no longer declared", func(t *testing.T) {
+		e := createCustomFieldsEpic()
+		e.Phases[0].Custom = epic.CustomFields{{Name: "owner", Value: "alice"}}
+		if _, err := EditCustomFields(e, customFieldsSchema, "phase", "P1", []CustomFieldChange{{Name: "owner", Unset: true}}, editedAt); err != nil {
+			t.Fatalf("EditCustomFields() error = %v", err)
+		}
+		if e.Phases[0].Custom != nil {
+			t.Errorf("phase custom fields = %+v, want none", e.Phases[0].Custom)
+		}
+	})
+
+	t.Run("respects frozen phases and unknown entities", func(t *testing.T) {
+		e := createCustomFieldsEpic()
+		_, err := EditCustomFields(e, customFieldsSchema, "task", "T2", []CustomFieldChange{{Name: "sprint", Value: "7"}}, editedAt)
+		if err == nil || !strings.Contains(err.Error(), "frozen") {
+			t.Errorf("EditCustomFields() on frozen phase error = %v", err)
+		}
+
+		_, err = EditCustomFields(e, customFieldsSchema, "task", "T9", []CustomFieldChange{{Name: "sprint", Value: "7"}}, editedAt)
+		if err == nil || err.Error() != "task T9 not found" {
+			t.Errorf("EditCustomFields() on unknown task error = %v", err)
+		}
+	})
+}
@@ -176,10 +176,11 @@ func TestEpicService_GetConfiguration(t *testing.T) {
 		err := config.SaveConfig(cfg, svc.configPath)
 		require.NoError(t, err)
 
-		// Store epic (need to store with the path that EpicFilePath() will return)
+		// Store epic (need to store with the path that EpicFilePath() will return,
+		// which is resolved relative to the config file's directory)
 		testEpic := createTestEpic(true)
 		memStorage := svc.storage.(*storage.MemoryStorage)
-		memStorage.StoreEpic("./test-epic.xml", testEpic)
+		memStorage.StoreEpic(filepath.Join(filepath.Dir(svc.configPath), "test-epic.xml"), testEpic)
 
 		result, err := svc.GetConfiguration()
 
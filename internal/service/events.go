@@ -1,10 +1,13 @@
 package service
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"time"
 
 	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/gitmeta"
 )
 
 // EventType represents the different types of events that can be created
@@ -22,8 +25,94 @@ const (
 	EventTestCancelled  EventType = "test_cancelled"
 	EventEpicStarted    EventType = "epic_started"
 	EventEpicCompleted  EventType = "epic_completed"
+	EventPhaseReset     EventType = "phase_reset"
+	EventTaskReset      EventType = "task_reset"
+	EventTestReset      EventType = "test_reset"
+	EventPhaseFrozen    EventType = "phase_frozen"
+	EventPhaseUnfrozen  EventType = "phase_unfrozen"
+	EventPhaseAssigned  EventType = "phase_assigned"
+	EventTaskAssigned   EventType = "task_assigned"
+
+	// EventValidationWarning records that an edit made outside agentpm left the epic inconsistent
+	EventValidationWarning EventType = "validation_warning"
+	// EventHandoffResumed records that an agent resumed work from a handoff token
+	EventHandoffResumed EventType = "handoff_resumed"
+	// EventSummarized rolls up a burst of task and test events, see SummarizeEvents
+	EventSummarized EventType = "events_summarized"
+	// EventCompacted replaces all events before a cutoff, see CompactEvents
+	EventCompacted EventType = "events_compacted"
+	// EventBlockerRaised and EventBlockerResolved record the lifecycle of a blocker or open question
+	EventBlockerRaised   EventType = "blocker_raised"
+	EventBlockerResolved EventType = "blocker_resolved"
+	// EventTaskScheduled records the next occurrence of a recurring task, see epic.Epic.NextOccurrence
+	EventTaskScheduled EventType = "task_scheduled"
+	// EventTaskReopened, EventPhaseReopened and EventEpicReopened record work
+	// brought back from cancelled or completed, see ReopenTask
+	EventTaskReopened  EventType = "task_reopened"
+	EventPhaseReopened EventType = "phase_reopened"
+	EventEpicReopened  EventType = "epic_reopened"
+	// EventCustomFieldsChanged records values set or unset with EditCustomFields
+	EventCustomFieldsChanged EventType = "custom_fields_changed"
+	// EventEpicSplit and EventEpicMerged record phases moved between epics, see SplitEpic and MergeEpic
+	EventEpicSplit  EventType = "epic_split"
+	EventEpicMerged EventType = "epic_merged"
+	// EventNoteAdded records a note left with AddNote
+	EventNoteAdded EventType = "note_added"
 )
 
+// actor is attributed to the events created by this process, see SetActor
+var actor string
+
+// SetActor sets the agent or human that events created from now on are attributed
+// to. An empty name leaves events unattributed.
+func SetActor(name string) {
+	actor = name
+}
+
+// Actor returns the name set with SetActor
+func Actor() string {
+	return actor
+}
+
+// gitInfo is the git commit and branch the events created by this process are
+// linked to, see SetGitInfo
+var gitInfo *gitmeta.Info
+
+// SetGitInfo links the events created from now on to a git commit and branch.
+// nil leaves them unlinked.
+func SetGitInfo(info *gitmeta.Info) {
+	gitInfo = info
+}
+
+// GitInfo returns the commit and branch set with SetGitInfo, or nil
+func GitInfo() *gitmeta.Info {
+	return gitInfo
+}
+
+// correlationID is shared by the events created by this process, see SetCorrelationID
+var correlationID string
+
+// SetCorrelationID sets the ID shared by the events created from now on, so
+// that the events of one command invocation can be found together. An empty
+// ID leaves events uncorrelated.
+func SetCorrelationID(id string) {
+	correlationID = id
+}
+
+// CorrelationID returns the ID set with SetCorrelationID
+func CorrelationID() string {
+	return correlationID
+}
+
+// NewCorrelationID returns a random ID for the events of a command invocation
+func NewCorrelationID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
 // CreateEvent creates a new event and appends it to the epic's events
 // Only creates an event if the referenced entity (phase, task, test, or epic) exists
 func CreateEvent(epicData *epic.Epic, eventType EventType, phaseID, taskID, testID, reason string, timestamp time.Time) {
@@ -106,12 +195,91 @@ func CreateEvent(epicData *epic.Epic, eventType EventType, phaseID, taskID, test
 			entityExists = true
 			data = formatTestCancelledData(test, reason)
 		}
+	case EventPhaseReset:
+		phase := findPhaseByID(epicData, phaseID)
+		if phase != nil {
+			entityExists = true
+			data = formatResetData("Phase", phase.ID, phase.Name, reason)
+		}
+	case EventPhaseFrozen, EventPhaseUnfrozen:
+		phase := findPhaseByID(epicData, phaseID)
+		if phase != nil {
+			entityExists = true
+			data = formatFreezeData(phase.ID, phase.Name, eventType == EventPhaseFrozen, reason)
+		}
+	case EventPhaseAssigned:
+		phase := findPhaseByID(epicData, phaseID)
+		if phase != nil {
+			entityExists = true
+			data = formatAssignData("Phase", phase.ID, phase.Name, phase.Assignee)
+		}
+	case EventTaskAssigned:
+		task := findTaskByID(epicData, taskID)
+		if task != nil {
+			entityExists = true
+			data = formatAssignData("Task", task.ID, task.Name, task.Assignee)
+		}
+	case EventTaskScheduled:
+		// The reason carries the ID of the completed occurrence
+		task := findTaskByID(epicData, taskID)
+		if task != nil {
+			entityExists = true
+			data = formatScheduledData(task, reason)
+		}
+	case EventTaskReopened:
+		task := findTaskByID(epicData, taskID)
+		if task != nil {
+			entityExists = true
+			data = formatReopenData("Task", task.ID, task.Name, task.Status, reason)
+		}
+	case EventPhaseReopened:
+		phase := findPhaseByID(epicData, phaseID)
+		if phase != nil {
+			entityExists = true
+			data = formatReopenData("Phase", phase.ID, phase.Name, phase.Status, reason)
+		}
+	case EventEpicReopened:
+		entityExists = true
+		data = formatReopenData("Epic", epicData.ID, epicData.Name, epicData.Status, reason)
+	case EventTaskReset:
+		task := findTaskByID(epicData, taskID)
+		if task != nil {
+			entityExists = true
+			data = formatResetData("Task", task.ID, task.Name, reason)
+		}
+	case EventTestReset:
+		test := findTestByID(epicData, testID)
+		if test != nil {
+			entityExists = true
+			data = formatResetData("Test", test.ID, test.Name, reason)
+		}
 	case EventEpicStarted:
 		entityExists = true
 		data = formatEpicStartedData(epicData)
 	case EventEpicCompleted:
 		entityExists = true
 		data = formatEpicCompletedData(epicData)
+	case EventValidationWarning:
+		entityExists = true
+		data = fmt.Sprintf("Epic file became inconsistent after an external edit: %s", reason)
+	case EventCustomFieldsChanged, EventEpicSplit, EventEpicMerged, EventNoteAdded:
+		// The reason carries the description of the changes
+		entityExists = true
+		data = reason
+	case EventHandoffResumed:
+		// The reason carries the handoff token ID
+		entityExists = true
+		data = fmt.Sprintf("Handoff %s resumed", reason)
+		if taskID != "" {
+			data += fmt.Sprintf(" with task %s", taskID)
+		}
+	case EventBlockerRaised, EventBlockerResolved:
+		// The reason carries the blocker ID
+		blocker := epicData.FindBlocker(reason)
+		if blocker != nil {
+			entityExists = true
+			data = formatBlockerData(blocker, eventType)
+		}
 	default:
 		// For unknown event types, we don't validate entity existence
 		entityExists = true
@@ -131,7 +299,14 @@ func CreateEvent(epicData *epic.Epic, eventType EventType, phaseID, taskID, test
 		ID:        eventID,
 		Type:      string(eventType),
 		Timestamp: timestamp,
+		Actor:     actor,
 		Data:      data,
+
+		CorrelationID: correlationID,
+	}
+	if gitInfo != nil {
+		event.Commit = gitInfo.Commit
+		event.Branch = gitInfo.Branch
 	}
 
 	epicData.Events = append(epicData.Events, event)
@@ -165,6 +340,16 @@ func findTestByID(epicData *epic.Epic, testID string) *epic.Test {
 	return nil
 }
 
+// formatScheduledData describes the next occurrence of a recurring task, e.g.
+// "Task 1A_3_r2 (Rotate keys) scheduled after 1A_3, due 2025-09-22"
+func formatScheduledData(task *epic.Task, previousID string) string {
+	data := fmt.Sprintf("Task %s scheduled after %s, due %s", task.ID, previousID, task.Due)
+	if task.Name != "" {
+		data = fmt.Sprintf("Task %s (%s) scheduled after %s, due %s", task.ID, task.Name, previousID, task.Due)
+	}
+	return data
+}
+
 // Test event data formatting functions
 func formatTestStartedData(test *epic.Test) string {
 	if test.Name != "" {
@@ -175,9 +360,9 @@ func formatTestStartedData(test *epic.Test) string {
 
 func formatTestPassedData(test *epic.Test) string {
 	if test.Name != "" {
-		return fmt.Sprintf("Test %s (%s) passed", test.ID, test.Name)
+		return fmt.Sprintf("Test %s (%s) passed", test.ID, test.Name) + formatVerification(test)
 	}
-	return fmt.Sprintf("Test %s passed", test.ID)
+	return fmt.Sprintf("Test %s passed", test.ID) + formatVerification(test)
 }
 
 func formatTestFailedData(test *epic.Test, reason string) string {
@@ -191,7 +376,18 @@ func formatTestFailedData(test *epic.Test, reason string) string {
 	if reason != "" {
 		baseData += fmt.Sprintf(": %s", reason)
 	}
-	return baseData
+	return baseData + formatVerification(test)
+}
+
+// formatVerification names who checked a test and the evidence, if recorded
+func formatVerification(test *epic.Test) string {
+	if test.VerifiedBy == "" {
+		return ""
+	}
+	if test.Evidence != "" {
+		return fmt.Sprintf(" (verified by %s, evidence: %s)", test.VerifiedBy, test.Evidence)
+	}
+	return fmt.Sprintf(" (verified by %s)", test.VerifiedBy)
 }
 
 func formatTestCancelledData(test *epic.Test, reason string) string {
@@ -208,6 +404,89 @@ func formatTestCancelledData(test *epic.Test, reason string) string {
 	return baseData
 }
 
+// formatBlockerData describes a blocker being raised or resolved, e.g.
+// "Blocker B2 (question) raised on task 2A_1: Which API version?"
+func formatBlockerData(blocker *epic.Blocker, eventType EventType) string {
+	data := fmt.Sprintf("Blocker %s", blocker.ID)
+	if blocker.IsQuestion() {
+		data += " (question)"
+	}
+	if eventType == EventBlockerResolved {
+		data += " resolved"
+		if blocker.Resolution != "" {
+			data += fmt.Sprintf(": %s", blocker.Resolution)
+		}
+		return data
+	}
+
+	data += " raised"
+	if blocker.Entity != "" {
+		data += fmt.Sprintf(" on %s", blocker.Entity)
+	}
+	return data + fmt.Sprintf(": %s", blocker.Description)
+}
+
+// formatResetData describes an entity reset by an administrative operation
+func formatResetData(entityType, id, name, reason string) string {
+	baseData := ""
+	if name != "" {
+		baseData = fmt.Sprintf("%s %s (%s) reset to pending", entityType, id, name)
+	} else {
+		baseData = fmt.Sprintf("%s %s reset to pending", entityType, id)
+	}
+
+	if reason != "" {
+		baseData += fmt.Sprintf(": %s", reason)
+	}
+	return baseData
+}
+
+// formatReopenData describes an entity brought back from cancelled or
+// completed, e.g. "Task 1A_1 (Setup) reopened as wip: Bug in the setup"
+func formatReopenData(entityType, id, name string, status epic.Status, reason string) string {
+	data := fmt.Sprintf("%s %s reopened as %s", entityType, id, status)
+	if name != "" {
+		data = fmt.Sprintf("%s %s (%s) reopened as %s", entityType, id, name, status)
+	}
+	if reason != "" {
+		data += fmt.Sprintf(": %s", reason)
+	}
+	return data
+}
+
+func formatFreezeData(id, name string, frozen bool, reason string) string {
+	action := "unfrozen"
+	if frozen {
+		action = "frozen"
+	}
+
+	baseData := ""
+	if name != "" {
+		baseData = fmt.Sprintf("Phase %s (%s) %s", id, name, action)
+	} else {
+		baseData = fmt.Sprintf("Phase %s %s", id, action)
+	}
+
+	if reason != "" {
+		baseData += fmt.Sprintf(": %s", reason)
+	}
+	return baseData
+}
+
+// formatAssignData describes a phase or task handed to an agent, or taken
+// back when assignee is empty
+func formatAssignData(entityType, id, name, assignee string) string {
+	baseData := fmt.Sprintf("%s %s", entityType, id)
+	if name != "" {
+		baseData += fmt.Sprintf(" (%s)", name)
+	}
+
+	if assignee == "" {
+		return baseData + " unassigned"
+	}
+	return baseData + fmt.Sprintf(" assigned to %s", assignee)
+}
+
 // Epic event data formatting functions
 func formatEpicStartedData(epicData *epic.Epic) string {
 	if epicData.Name != "" {
@@ -222,3 +501,9 @@ func formatEpicCompletedData(epicData *epic.Epic) string {
 	}
 	return fmt.Sprintf("Epic %s completed", epicData.ID)
 }
+
+// EventEntityID returns the ID of the phase, task or test an event refers to,
+// see epic.EventEntityID
+func EventEntityID(event epic.Event) string {
+	return epic.EventEntityID(event)
+}
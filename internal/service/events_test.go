@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/gitmeta"
 )
 
 func TestCreateEvent(t *testing.T) {
@@ -528,3 +529,87 @@ func TestFindTestByID(t *testing.T) {
 		t.Errorf("Expected nil for non-existent test, got %v", test)
 	}
 }
+
+func TestEventEntityID(t *testing.T) {
+	epicData := &epic.Epic{
+		ID:     "epic1",
+		Phases: []epic.Phase{{ID: "1A", Name: "Setup"}},
+		Tasks:  []epic.Task{{ID: "1A_1", PhaseID: "1A"}},
+		Tests:  []epic.Test{{ID: "T1", TaskID: "1A_1", PhaseID: "1A", Name: "Test 1"}},
+	}
+	timestamp := time.Date(2025, 8, 16, 15, 30, 0, 0, time.UTC)
+
+	CreateEvent(epicData, EventPhaseStarted, "1A", "", "", "", timestamp)
+	CreateEvent(epicData, EventTaskCompleted, "1A", "1A_1", "", "", timestamp)
+	CreateEvent(epicData, EventTestFailed, "1A", "1A_1", "T1", "timeout", timestamp)
+	CreateEvent(epicData, EventTaskReset, "1A", "1A_1", "", "rework", timestamp)
+	CreateEvent(epicData, EventEpicStarted, "", "", "", "", timestamp)
+
+	expected := []string{"1A", "1A_1", "T1", "1A_1", ""}
+	for i, event := range epicData.Events {
+		if got := EventEntityID(event); got != expected[i] {
+			t.Errorf("event %s: expected entity %q, got %q", event.Type, expected[i], got)
+		}
+	}
+
+	if got := EventEntityID(epic.Event{Type: "task_completed", Data: "something else"}); got != "" {
+		t.Errorf("Expected no entity for unrelated data, got %q", got)
+	}
+}
+
+func TestCreateEvent_Actor(t *testing.T) {
+	defer SetActor("")
+
+	epicData := &epic.Epic{ID: "epic1", Tasks: []epic.Task{{ID: "task1", Name: "Task 1"}}}
+	timestamp := time.Date(2025, 8, 16, 15, 30, 0, 0, time.UTC)
+
+	CreateEvent(epicData, EventTaskStarted, "", "task1", "", "", timestamp)
+	SetActor("claude-1")
+	CreateEvent(epicData, EventTaskCompleted, "", "task1", "", "", timestamp)
+
+	if epicData.Events[0].Actor != "" {
+		t.Errorf("Expected no actor before SetActor, got %q", epicData.Events[0].Actor)
+	}
+	if epicData.Events[1].Actor != "claude-1" {
+		t.Errorf("Expected actor claude-1, got %q", epicData.Events[1].Actor)
+	}
+}
+
+func TestCreateEvent_GitInfo(t *testing.T) {
+	defer SetGitInfo(nil)
+
+	epicData := &epic.Epic{ID: "epic1", Tasks: []epic.Task{{ID: "task1", Name: "Task 1"}}}
+	timestamp := time.Date(2025, 8, 16, 15, 30, 0, 0, time.UTC)
+
+	CreateEvent(epicData, EventTaskStarted, "", "task1", "", "", timestamp)
+	SetGitInfo(&gitmeta.Info{Commit: "3855220", Branch: "main"})
+	CreateEvent(epicData, EventTaskCompleted, "", "task1", "", "", timestamp)
+
+	if event := epicData.Events[0]; event.Commit != "" || event.Branch != "" {
+		t.Errorf("Expected no commit before SetGitInfo, got %q on %q", event.Commit, event.Branch)
+	}
+	if event := epicData.Events[1]; event.Commit != "3855220" || event.Branch != "main" {
+		t.Errorf("Expected commit 3855220 on main, got %q on %q", event.Commit, event.Branch)
+	}
+}
+
+func TestCreateEvent_CorrelationID(t *testing.T) {
+	defer SetCorrelationID("")
+
+	epicData := &epic.Epic{ID: "epic1", Tasks: []epic.Task{{ID: "task1", Name: "Task 1"}}}
+	timestamp := time.Date(2025, 8, 16, 15, 30, 0, 0, time.UTC)
+
+	id := NewCorrelationID()
+	if len(id) != 12 || id == NewCorrelationID() {
+		t.Fatalf("Expected a random 12 character correlation ID, got %q", id)
+	}
+	SetCorrelationID(id)
+	CreateEvent(epicData, EventTaskCompleted, "", "task1", "", "", timestamp)
+	CreateEvent(epicData, EventPhaseCompleted, "", "", "", "", timestamp)
+
+	for _, event := range epicData.Events {
+		if event.CorrelationID != id {
+			t.Errorf("Expected correlation ID %q on %s, got %q", id, event.Type, event.CorrelationID)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// AddNote leaves a note on the epic, or on the phase, task or test with the
+// given ID, attributed to the current actor. Notes do not change the work, so
+// they can also be added to frozen phases; a completed epic takes no more notes.
+func AddNote(e *epic.Epic, entityType, id, text string, timestamp time.Time) (*epic.Note, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("note text cannot be empty")
+	}
+
+	var label string
+	switch entityType {
+	case epic.NoteOnEpic:
+		id, label = e.ID, "Epic "+e.ID
+	case epic.NoteOnPhase:
+		label = "Phase " + id
+	case epic.NoteOnTask:
+		label = "Task " + id
+	case epic.NoteOnTest:
+		label = "Test " + id
+	default:
+		return nil, fmt.Errorf("invalid entity type %q (expected epic, phase, task or test)", entityType)
+	}
+
+	notes := e.NotesOf(entityType, id)
+	if notes == nil {
+		return nil, fmt.Errorf("%s %s not found", entityType, id)
+	}
+	if err := e.EnsureMutable(fmt.Sprintf("add note to %s %s", entityType, id)); err != nil {
+		return nil, err
+	}
+
+	*notes = append(*notes, epic.Note{Author: actor, CreatedAt: timestamp, Text: text})
+	CreateEvent(e, EventNoteAdded, "", "", "", fmt.Sprintf("Note on %s: %s", label, text), timestamp)
+
+	note := (*notes)[len(*notes)-1]
+	return &note, nil
+}
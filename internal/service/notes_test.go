@@ -0,0 +1,66 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+func TestAddNote(t *testing.T) {
+	SetActor("agent_a")
+	defer SetActor("")
+	notedAt := time.Date(2025, 8, 17, 9, 0, 0, 0, time.UTC)
+
+	t.Run("adds an attributed note and records an event", func(t *testing.T) {
+		e := createCustomFieldsEpic()
+		note, err := AddNote(e, "task", "T1", "  Uses the v2 endpoint ", notedAt)
+		if err != nil {
+			t.Fatalf("AddNote() error = %v", err)
+		}
+
+		want := epic.Note{Author: "agent_a", CreatedAt: notedAt, Text: "Uses the v2 endpoint"}
+		if *note != want || len(e.Tasks[0].Notes) != 1 || e.Tasks[0].Notes[0] != want {
+			t.Errorf("note = %+v, task notes %+v, want %+v", *note, e.Tasks[0].Notes, want)
+		}
+		if len(e.Events) != 1 || e.Events[0].Type != string(EventNoteAdded) ||
+			e.Events[0].Data != "Note on Task T1: Uses the v2 endpoint" {
+			t.Errorf("events = %+v", e.Events)
+		}
+	})
+
+	t.Run("adds notes to the epic and to frozen phases", func(t *testing.T) {
+		e := createCustomFieldsEpic()
+		if _, err := AddNote(e, "epic", "", "Scope agreed", notedAt); err != nil {
+			t.Fatalf("AddNote() epic error = %v", err)
+		}
+		if _, err := AddNote(e, "phase", "P2", "Frozen until the release", notedAt); err != nil {
+			t.Fatalf("AddNote() frozen phase error = %v", err)
+		}
+		if len(e.Notes) != 1 || len(e.Phases[1].Notes) != 1 {
+			t.Errorf("epic notes = %+v, phase notes = %+v", e.Notes, e.Phases[1].Notes)
+		}
+	})
+
+	t.Run("rejects empty text, unknown entities and completed epics", func(t *testing.T) {
+		e := createCustomFieldsEpic()
+		for _, tc := range []struct{ entityType, id, text, want string }{
+			{"task", "T1", "   ", "note text cannot be empty"},
+			{"test", "TS9", "Hello", "test TS9 not found"},
+			{"milestone", "M1", "Hello", `invalid entity type "milestone"`},
+		} {
+			if _, err := AddNote(e, tc.entityType, tc.id, tc.text, notedAt); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("AddNote(%s %s) error = %v, want %q", tc.entityType, tc.id, err, tc.want)
+			}
+		}
+
+		e.Status = epic.StatusCompleted
+		if _, err := AddNote(e, "task", "T1", "Too late", notedAt); err == nil {
+			t.Error("AddNote() on a completed epic succeeded")
+		}
+		if len(e.Events) != 0 {
+			t.Errorf("events = %+v", e.Events)
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// ReopenResult describes an entity brought back from cancelled or completed
+type ReopenResult struct {
+	EntityType     string      `json:"entity_type"`
+	ID             string      `json:"id"`
+	PreviousStatus epic.Status `json:"previous_status"`
+	Status         epic.Status `json:"status"`
+	// ReopenedPhase is the completed phase reopened along with a task
+	ReopenedPhase string `json:"reopened_phase,omitempty"`
+	// ReopenedEpic tells that the completed epic was reopened along with the entity
+	ReopenedEpic bool `json:"reopened_epic"`
+	// Warning is set when completed work is reopened
+	Warning string `json:"warning,omitempty"`
+}
+
+// ReopenTask moves a cancelled task back to pending, or a completed task back
+// to wip. Reopening a task of a completed phase reopens the phase, and
+// reopening anything in a completed epic reopens the epic.
+func ReopenTask(e *epic.Epic, taskID, reason string, timestamp time.Time) (*ReopenResult, error) {
+	task := findTaskByID(e, taskID)
+	if task == nil {
+		return nil, fmt.Errorf("task %s not found", taskID)
+	}
+	phase := findPhaseByID(e, task.PhaseID)
+	if phase == nil {
+		return nil, fmt.Errorf("phase %s of task %s not found", task.PhaseID, taskID)
+	}
+	if phase.IsFrozen() {
+		return nil, epic.NewPhaseFrozenError(phase.ID, phase.FrozenReason, "reopen task "+taskID)
+	}
+	if phase.Status == epic.StatusCancelled {
+		return nil, fmt.Errorf("cannot reopen task %s: phase %s is cancelled, reopen it first", taskID, phase.ID)
+	}
+	if parent := e.ParentTask(task); parent != nil && (parent.Status == epic.StatusCompleted || parent.Status == epic.StatusCancelled) {
+		return nil, fmt.Errorf("cannot reopen task %s: its parent task %s is %s, reopen it first", taskID, parent.ID, parent.Status)
+	}
+
+	result := &ReopenResult{EntityType: "task", ID: taskID, PreviousStatus: task.Status}
+	switch task.Status {
+	case epic.StatusCancelled:
+		result.Status = epic.StatusPending
+	case epic.StatusCompleted:
+		result.Status = epic.StatusWIP
+		if phase.Status == epic.StatusPending {
+			return nil, fmt.Errorf("cannot reopen task %s: phase %s is pending, start it first", taskID, phase.ID)
+		}
+		// A sub-task is worked on while its parent is active
+		if active := e.ActiveTask(task.PhaseID); active != nil && active.ID != task.ParentTaskID {
+			return nil, fmt.Errorf("cannot reopen task %s: task %s is active in phase %s, complete it first", taskID, active.ID, task.PhaseID)
+		}
+		result.Warning = fmt.Sprintf("task %s was completed; its tests keep their results", taskID)
+	default:
+		return nil, fmt.Errorf("cannot reopen task %s: it is %s (only cancelled and completed tasks can be reopened)", taskID, task.Status)
+	}
+	if phase.Status == epic.StatusCompleted {
+		if active := activePhase(e); active != nil {
+			return nil, fmt.Errorf("cannot reopen task %s: its phase %s is completed and phase %s is active, complete it first", taskID, phase.ID, active.ID)
+		}
+	}
+
+	result.ReopenedEpic = reopenCompletedEpic(e, reason, timestamp)
+	if phase.Status == epic.StatusCompleted {
+		reopenPhaseAsWIP(e, phase, reason, timestamp)
+		result.ReopenedPhase = phase.ID
+	}
+
+	task.Status = result.Status
+	task.CompletedAt = nil
+	task.CancelledAt = nil
+	if task.Status == epic.StatusPending {
+		task.StartedAt = nil
+	} else {
+		ensureCurrentState(e).ActiveTask = task.ID
+		e.CurrentState.NextAction = fmt.Sprintf("Complete task: %s", task.Name)
+	}
+	CreateEvent(e, EventTaskReopened, task.PhaseID, taskID, "", reason, timestamp)
+
+	return result, nil
+}
+
+// ReopenPhase moves a cancelled phase back to pending, or a completed phase
+// back to wip. Its tasks and tests keep their status; reopening a phase of a
+// completed epic reopens the epic.
+func ReopenPhase(e *epic.Epic, phaseID, reason string, timestamp time.Time) (*ReopenResult, error) {
+	phase := findPhaseByID(e, phaseID)
+	if phase == nil {
+		return nil, fmt.Errorf("phase %s not found", phaseID)
+	}
+	if phase.IsFrozen() {
+		return nil, epic.NewPhaseFrozenError(phaseID, phase.FrozenReason, "reopen phase "+phaseID)
+	}
+
+	result := &ReopenResult{EntityType: "phase", ID: phaseID, PreviousStatus: phase.Status}
+	switch phase.Status {
+	case epic.StatusCancelled:
+		result.Status = epic.StatusPending
+	case epic.StatusCompleted:
+		result.Status = epic.StatusWIP
+		if active := activePhase(e); active != nil {
+			return nil, fmt.Errorf("cannot reopen phase %s: phase %s is active, complete it first", phaseID, active.ID)
+		}
+		result.Warning = fmt.Sprintf("phase %s was completed; its completed tasks and passed tests are kept", phaseID)
+	default:
+		return nil, fmt.Errorf("cannot reopen phase %s: it is %s (only cancelled and completed phases can be reopened)", phaseID, phase.Status)
+	}
+
+	result.ReopenedEpic = reopenCompletedEpic(e, reason, timestamp)
+	if result.Status == epic.StatusWIP {
+		reopenPhaseAsWIP(e, phase, reason, timestamp)
+		return result, nil
+	}
+
+	phase.Status = epic.StatusPending
+	phase.StartedAt = nil
+	phase.CompletedAt = nil
+	CreateEvent(e, EventPhaseReopened, phaseID, "", "", reason, timestamp)
+	return result, nil
+}
+
+// ReopenEpic moves a completed epic back to wip, so that it can change again
+func ReopenEpic(e *epic.Epic, reason string, timestamp time.Time) (*ReopenResult, error) {
+	if e.GetEpicStatus() != epic.EpicStatusDone {
+		return nil, fmt.Errorf("cannot reopen epic %s: it is %s (only completed epics can be reopened)", e.ID, e.Status)
+	}
+
+	result := &ReopenResult{EntityType: "epic", ID: e.ID, PreviousStatus: e.Status, Status: epic.StatusWIP}
+	result.ReopenedEpic = reopenCompletedEpic(e, reason, timestamp)
+	result.Warning = fmt.Sprintf("epic %s was completed; its phases keep their status", e.ID)
+	return result, nil
+}
+
+// reopenCompletedEpic moves a completed epic back to wip and reports whether
+// it did
+func reopenCompletedEpic(e *epic.Epic, reason string, timestamp time.Time) bool {
+	if e.GetEpicStatus() != epic.EpicStatusDone {
+		return false
+	}
+	e.Status = epic.StatusWIP
+	CreateEvent(e, EventEpicReopened, "", "", "", reason, timestamp)
+	return true
+}
+
+// reopenPhaseAsWIP makes a phase the active phase again
+func reopenPhaseAsWIP(e *epic.Epic, phase *epic.Phase, reason string, timestamp time.Time) {
+	phase.Status = epic.StatusWIP
+	phase.CompletedAt = nil
+	if phase.StartedAt == nil {
+		phase.StartedAt = &timestamp
+	}
+	ensureCurrentState(e).ActivePhase = phase.ID
+	CreateEvent(e, EventPhaseReopened, phase.ID, "", "", reason, timestamp)
+}
+
+// activePhase returns the phase in progress, or nil
+func activePhase(e *epic.Epic) *epic.Phase {
+	for i := range e.Phases {
+		if e.Phases[i].Status == epic.StatusWIP {
+			return &e.Phases[i]
+		}
+	}
+	return nil
+}
+
+func ensureCurrentState(e *epic.Epic) *epic.CurrentState {
+	if e.CurrentState == nil {
+		e.CurrentState = &epic.CurrentState{}
+	}
+	return e.CurrentState
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+func createReopenEpic() *epic.Epic {
+	doneAt := time.Date(2025, 8, 16, 9, 0, 0, 0, time.UTC)
+	return &epic.Epic{
+		ID:     "epic-1",
+		Name:   "Reopen Epic",
+		Status: epic.StatusCompleted,
+		Phases: []epic.Phase{
+			{ID: "P1", Name: "Phase 1", Status: epic.StatusCompleted, StartedAt: &doneAt, CompletedAt: &doneAt},
+			{ID: "P2", Name: "Phase 2", Status: epic.StatusCancelled},
+		},
+		Tasks: []epic.Task{
+			{ID: "T1", PhaseID: "P1", Name: "Task 1", Status: epic.StatusCompleted, StartedAt: &doneAt, CompletedAt: &doneAt},
+			{ID: "T2", PhaseID: "P1", Name: "Task 2", Status: epic.StatusCancelled, StartedAt: &doneAt, CancelledAt: &doneAt},
+			{ID: "T3", PhaseID: "P2", Name: "Task 3", Status: epic.StatusCancelled, CancelledAt: &doneAt},
+		},
+	}
+}
+
+func TestReopenTask(t *testing.T) {
+	reopenedAt := time.Date(2025, 8, 17, 9, 0, 0, 0, time.UTC)
+
+	t.Run("completed task reopens its phase and the epic", func(t *testing.T) {
+		e := createReopenEpic()
+		result, err := ReopenTask(e, "T1", "Regression", reopenedAt)
+		if err != nil {
+			t.Fatalf("ReopenTask() error = %v", err)
+		}
+		if result.Status != epic.StatusWIP || result.PreviousStatus != epic.StatusCompleted ||
+			result.ReopenedPhase != "P1" || !result.ReopenedEpic || result.Warning == "" {
+			t.Errorf("ReopenTask() = %+v", result)
+		}
+
+		task := &e.Tasks[0]
+		if task.Status != epic.StatusWIP || task.CompletedAt != nil || task.StartedAt == nil {
+			t.Errorf("task = %+v, want wip and still started", task)
+		}
+		if phase := e.Phases[0]; phase.Status != epic.StatusWIP || phase.CompletedAt != nil {
+			t.Errorf("phase = %+v, want wip", phase)
+		}
+		if e.Status != epic.StatusWIP {
+			t.Errorf("epic status = %s, want wip", e.Status)
+		}
+		if e.CurrentState.ActivePhase != "P1" || e.CurrentState.ActiveTask != "T1" {
+			t.Errorf("current state = %+v", e.CurrentState)
+		}
+
+		wantEvents := []struct{ eventType, data string }{
+			{"epic_reopened", "Epic epic-1 (Reopen Epic) reopened as wip: Regression"},
+			{"phase_reopened", "Phase P1 (Phase 1) reopened as wip: Regression"},
+			{"task_reopened", "Task T1 (Task 1) reopened as wip: Regression"},
+		}
+		if len(e.Events) != len(wantEvents) {
+			t.Fatalf("got %d events, want %d", len(e.Events), len(wantEvents))
+		}
+		for i, want := range wantEvents {
+			if e.Events[i].Type != want.eventType || e.Events[i].Data != want.data {
+				t.Errorf("event %d = %s %q, want %s %q", i, e.Events[i].Type, e.Events[i].Data, want.eventType, want.data)
+			}
+		}
+	})
+
+	t.Run("cancelled task goes back to pending", func(t *testing.T) {
+		e := createReopenEpic()
+		e.Status = epic.StatusWIP
+		e.Phases[0].Status = epic.StatusWIP
+		result, err := ReopenTask(e, "T2", "", reopenedAt)
+		if err != nil {
+			t.Fatalf("ReopenTask() error = %v", err)
+		}
+		if result.Status != epic.StatusPending || result.ReopenedPhase != "" || result.ReopenedEpic || result.Warning != "" {
+			t.Errorf("ReopenTask() = %+v", result)
+		}
+		if task := e.Tasks[1]; task.Status != epic.StatusPending || task.StartedAt != nil || task.CancelledAt != nil {
+			t.Errorf("task = %+v, want a fresh pending task", task)
+		}
+	})
+
+	for name, tc := range map[string]struct {
+		prepare func(e *epic.Epic)
+		taskID  string
+		want    string
+	}{
+		"unknown task":    {taskID: "T9", want: "task T9 not found"},
+		"pending task":    {prepare: func(e *epic.Epic) { e.Tasks[1].Status = epic.StatusPending }, taskID: "T2", want: "it is pending"},
+		"cancelled phase": {taskID: "T3", want: "phase P2 is cancelled, reopen it first"},
+		"other active task": {
+			prepare: func(e *epic.Epic) {
+				e.Phases[0].Status = epic.StatusWIP
+				e.Tasks[1].Status = epic.StatusWIP
+			},
+			taskID: "T1", want: "task T2 is active in phase P1",
+		},
+		"other active phase": {
+			prepare: func(e *epic.Epic) { e.Phases[1].Status = epic.StatusWIP },
+			taskID:  "T1", want: "phase P2 is active",
+		},
+		"frozen phase": {
+			prepare: func(e *epic.Epic) { e.Phases[0].FrozenAt = &time.Time{} },
+			taskID:  "T1", want: "phase P1 is frozen",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			e := createReopenEpic()
+			if tc.prepare != nil {
+				tc.prepare(e)
+			}
+			_, err := ReopenTask(e, tc.taskID, "", reopenedAt)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("ReopenTask() error = %v, want it to contain %q", err, tc.want)
+			}
+			if len(e.Events) != 0 || e.Status != epic.StatusCompleted {
+				t.Errorf("a rejected reopen changed the epic: status %s, %d events", e.Status, len(e.Events))
+			}
+		})
+	}
+}
+
+func TestReopenPhaseAndEpic(t *testing.T) {
+	reopenedAt := time.Date(2025, 8, 17, 9, 0, 0, 0, time.UTC)
+
+	e := createReopenEpic()
+	result, err := ReopenPhase(e, "P2", "Back in scope", reopenedAt)
+	if err != nil {
+		t.Fatalf("ReopenPhase() error = %v", err)
+	}
+	if result.Status != epic.StatusPending || !result.ReopenedEpic || result.Warning != "" {
+		t.Errorf("ReopenPhase() = %+v", result)
+	}
+	if e.Phases[1].Status != epic.StatusPending || e.Tasks[2].Status != epic.StatusCancelled {
+		t.Errorf("phase %s with task %s, want a pending phase keeping its tasks", e.Phases[1].Status, e.Tasks[2].Status)
+	}
+
+	result, err = ReopenPhase(e, "P1", "", reopenedAt)
+	if err != nil {
+		t.Fatalf("ReopenPhase() error = %v", err)
+	}
+	if result.Status != epic.StatusWIP || result.ReopenedEpic || result.Warning == "" {
+		t.Errorf("ReopenPhase() = %+v", result)
+	}
+	if _, err := ReopenPhase(e, "P1", "", reopenedAt); err == nil {
+		t.Error("ReopenPhase() of an active phase succeeded")
+	}
+
+	if _, err := ReopenEpic(e, "", reopenedAt); err == nil {
+		t.Error("ReopenEpic() of an epic in progress succeeded")
+	}
+	e.Status = epic.StatusCompleted
+	result, err = ReopenEpic(e, "Follow-up work", reopenedAt)
+	if err != nil {
+		t.Fatalf("ReopenEpic() error = %v", err)
+	}
+	if result.Status != epic.StatusWIP || e.Status != epic.StatusWIP {
+		t.Errorf("ReopenEpic() = %+v, epic status %s", result, e.Status)
+	}
+}
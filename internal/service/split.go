@@ -0,0 +1,485 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// SplitResult describes the epic SplitEpic carved out of another one
+type SplitResult struct {
+	SourceID string   `json:"source_id"`
+	EpicID   string   `json:"epic_id"`
+	Phases   []string `json:"phases"`
+	Tasks    int      `json:"tasks"`
+	Tests    int      `json:"tests"`
+	Blockers int      `json:"blockers"`
+	Events   int      `json:"events"`
+	// DroppedDependencies lists the dependencies of moved work on work left
+	// behind, e.g. "task 3A_1 on 2B_4"
+	DroppedDependencies []string `json:"dropped_dependencies,omitempty"`
+}
+
+// SplitEpic moves the given phases with their tasks, tests, blockers,
+// suppressions and events out of e into a new epic with the given ID and
+// name. Work left behind must not depend on work that moves; dependencies of
+// moved work on work left behind are dropped and reported.
+func SplitEpic(e *epic.Epic, phaseIDs []string, newID, newName string, timestamp time.Time) (*epic.Epic, *SplitResult, error) {
+	if len(phaseIDs) == 0 {
+		return nil, nil, fmt.Errorf("at least one phase to split off is required")
+	}
+	if newID == "" {
+		return nil, nil, fmt.Errorf("an ID for the new epic is required")
+	}
+	if newID == e.ID {
+		return nil, nil, fmt.Errorf("invalid epic ID %s: the new epic must not reuse the ID of epic %s", newID, e.ID)
+	}
+	if err := e.EnsureMutable("split epic"); err != nil {
+		return nil, nil, err
+	}
+
+	movedPhases := make(map[string]bool)
+	for _, id := range phaseIDs {
+		if findPhaseByID(e, id) == nil {
+			return nil, nil, fmt.Errorf("phase %s not found", id)
+		}
+		if err := e.EnsurePhaseMutable(id, "split off phase "+id); err != nil {
+			return nil, nil, err
+		}
+		movedPhases[id] = true
+	}
+	if len(movedPhases) == len(e.Phases) {
+		return nil, nil, fmt.Errorf("cannot split off all phases of epic %s, at least one must stay", e.ID)
+	}
+
+	movedTasks := make(map[string]bool)
+	for _, task := range e.Tasks {
+		if movedPhases[task.PhaseID] {
+			movedTasks[task.ID] = true
+		}
+	}
+	movedTests := make(map[string]bool)
+	for _, test := range e.Tests {
+		if movedPhases[test.PhaseID] || movedTasks[test.TaskID] {
+			movedTests[test.ID] = true
+		}
+	}
+	if err := checkSplitDependencies(e, movedPhases, movedTasks, movedTests); err != nil {
+		return nil, nil, err
+	}
+
+	if newName == "" {
+		newName = e.Name
+	}
+	split := &epic.Epic{
+		ID:            newID,
+		Name:          newName,
+		CreatedAt:     timestamp,
+		StatusModel:   e.StatusModel,
+		SchemaVersion: e.SchemaVersion,
+		Assignee:      e.Assignee,
+		Description:   fmt.Sprintf("Split from epic %s", e.ID),
+	}
+	result := &SplitResult{SourceID: e.ID, EpicID: newID}
+
+	var dropped []string
+	keep := func(ids []string, moved func(string) bool, entity, id string) string {
+		var kept []string
+		for _, dep := range ids {
+			if moved(dep) {
+				kept = append(kept, dep)
+			} else {
+				dropped = append(dropped, fmt.Sprintf("%s %s on %s", entity, id, dep))
+			}
+		}
+		return strings.Join(kept, ",")
+	}
+
+	var phases []epic.Phase
+	for _, phase := range e.Phases {
+		if !movedPhases[phase.ID] {
+			phases = append(phases, phase)
+			continue
+		}
+		phase.DependsOn = keep(phase.DependencyIDs(), func(id string) bool { return movedPhases[id] }, "phase", phase.ID)
+		split.Phases = append(split.Phases, phase)
+		result.Phases = append(result.Phases, phase.ID)
+	}
+	e.Phases = phases
+
+	var tasks []epic.Task
+	for _, task := range e.Tasks {
+		if !movedTasks[task.ID] {
+			tasks = append(tasks, task)
+			continue
+		}
+		task.DependsOn = keep(task.DependencyIDs(), func(id string) bool { return movedTasks[id] }, "task", task.ID)
+		if task.ParentTaskID != "" && !movedTasks[task.ParentTaskID] {
+			dropped = append(dropped, fmt.Sprintf("task %s on parent task %s", task.ID, task.ParentTaskID))
+			task.ParentTaskID = ""
+		}
+		split.Tasks = append(split.Tasks, task)
+	}
+	e.Tasks = tasks
+
+	var tests []epic.Test
+	for _, test := range e.Tests {
+		if !movedTests[test.ID] {
+			tests = append(tests, test)
+			continue
+		}
+		test.Requires = keep(test.RequiredIDs(), func(id string) bool { return movedTests[id] || movedTasks[id] }, "test", test.ID)
+		split.Tests = append(split.Tests, test)
+	}
+	e.Tests = tests
+
+	movedEntity := func(id string) bool { return movedPhases[id] || movedTasks[id] }
+	var blockers []epic.Blocker
+	movedBlockers := make(map[string]bool)
+	for _, blocker := range e.Blockers {
+		if blocker.Entity != "" && movedEntity(blocker.Entity) {
+			split.Blockers = append(split.Blockers, blocker)
+			movedBlockers[blocker.ID] = true
+		} else {
+			blockers = append(blockers, blocker)
+		}
+	}
+	e.Blockers = blockers
+
+	var suppressions []epic.Suppression
+	for _, suppression := range e.Suppressions {
+		if suppression.Entity != "" && movedEntity(suppression.Entity) {
+			split.Suppressions = append(split.Suppressions, suppression)
+		} else {
+			suppressions = append(suppressions, suppression)
+		}
+	}
+	e.Suppressions = suppressions
+
+	var events []epic.Event
+	for _, event := range e.Events {
+		kind, id := eventSubject(event)
+		moved := (kind == "phase" && movedPhases[id]) || (kind == "task" && movedTasks[id]) ||
+			(kind == "test" && movedTests[id]) || (kind == "blocker" && movedBlockers[id])
+		if moved {
+			split.Events = append(split.Events, event)
+		} else {
+			events = append(events, event)
+		}
+	}
+	e.Events = events
+
+	if state := e.CurrentState; state != nil && (movedPhases[state.ActivePhase] || movedTasks[state.ActiveTask]) {
+		split.CurrentState = state
+		e.CurrentState = &epic.CurrentState{}
+	}
+	split.Status = statusOfPhases(split.Phases)
+
+	result.Tasks = len(split.Tasks)
+	result.Tests = len(split.Tests)
+	result.Blockers = len(split.Blockers)
+	result.Events = len(split.Events)
+	result.DroppedDependencies = dropped
+
+	phaseList := strings.Join(result.Phases, ", ")
+	CreateEvent(e, EventEpicSplit, "", "", "", fmt.Sprintf("Epic %s split: phases %s moved to epic %s", e.ID, phaseList, newID), timestamp)
+	CreateEvent(split, EventEpicSplit, "", "", "", fmt.Sprintf("Epic %s split from epic %s: phases %s", newID, e.ID, phaseList), timestamp)
+
+	return split, result, nil
+}
+
+// checkSplitDependencies fails if work that stays depends on work that moves
+func checkSplitDependencies(e *epic.Epic, movedPhases, movedTasks, movedTests map[string]bool) error {
+	blocked := func(entity, id, dep string) error {
+		return fmt.Errorf("cannot split: %s %s depends on %s, which would move to the new epic", entity, id, dep)
+	}
+	for _, phase := range e.Phases {
+		if movedPhases[phase.ID] {
+			continue
+		}
+		for _, dep := range phase.DependencyIDs() {
+			if movedPhases[dep] {
+				return blocked("phase", phase.ID, dep)
+			}
+		}
+	}
+	for _, task := range e.Tasks {
+		if movedTasks[task.ID] {
+			continue
+		}
+		for _, dep := range append(task.DependencyIDs(), task.ParentTaskID) {
+			if movedTasks[dep] {
+				return blocked("task", task.ID, dep)
+			}
+		}
+	}
+	for _, test := range e.Tests {
+		if movedTests[test.ID] {
+			continue
+		}
+		for _, dep := range test.RequiredIDs() {
+			if movedTests[dep] || movedTasks[dep] {
+				return blocked("test", test.ID, dep)
+			}
+		}
+	}
+	return nil
+}
+
+// statusOfPhases returns pending while no phase has started, completed once
+// all phases are completed or cancelled and wip otherwise
+func statusOfPhases(phases []epic.Phase) epic.Status {
+	started, finished := false, true
+	for _, phase := range phases {
+		switch phase.Status {
+		case epic.StatusCompleted, epic.StatusCancelled:
+			started = true
+		case epic.StatusWIP:
+			started, finished = true, false
+		default:
+			finished = false
+		}
+	}
+	switch {
+	case finished && len(phases) > 0:
+		return epic.StatusCompleted
+	case started:
+		return epic.StatusWIP
+	default:
+		return epic.StatusPending
+	}
+}
+
+// IDRemap records an ID MergeEpic renamed because the target epic already used it
+type IDRemap struct {
+	Kind string `json:"kind"` // phase, task, test, milestone or blocker
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// MergeResult describes the epic MergeEpic folded into another one
+type MergeResult struct {
+	EpicID     string    `json:"epic_id"`
+	MergedID   string    `json:"merged_id"`
+	Phases     []string  `json:"phases"`
+	Tasks      int       `json:"tasks"`
+	Tests      int       `json:"tests"`
+	Milestones int       `json:"milestones"`
+	Blockers   int       `json:"blockers"`
+	Events     int       `json:"events"`
+	Remapped   []IDRemap `json:"remapped,omitempty"`
+}
+
+// MergeEpic folds the phases, tasks, tests, milestones, blockers,
+// suppressions and events of other into target. IDs other shares with target
+// are collisions: they fail the merge unless remap is set, which renames them
+// to the first free <id>-<n> and updates every reference to them.
+func MergeEpic(target, other *epic.Epic, remap bool, timestamp time.Time) (*MergeResult, error) {
+	if err := target.EnsureMutable("merge epic " + other.ID); err != nil {
+		return nil, err
+	}
+	if active, incoming := activePhase(target), activePhase(other); active != nil && incoming != nil {
+		return nil, fmt.Errorf("cannot merge epic %s: both epics have a phase in progress (%s and %s)", other.ID, active.ID, incoming.ID)
+	}
+
+	remaps := collidingIDs(target, other)
+	if len(remaps) > 0 && !remap {
+		collisions := make([]string, len(remaps))
+		for i, r := range remaps {
+			collisions[i] = r.Kind + " " + r.From
+		}
+		return nil, fmt.Errorf("cannot merge epic %s: IDs already used in epic %s: %s (use --remap to rename them)",
+			other.ID, target.ID, strings.Join(collisions, ", "))
+	}
+	renameIDs(other, remaps)
+
+	result := &MergeResult{
+		EpicID:     target.ID,
+		MergedID:   other.ID,
+		Tasks:      len(other.Tasks),
+		Tests:      len(other.Tests),
+		Milestones: len(other.Milestones),
+		Blockers:   len(other.Blockers),
+		Events:     len(other.Events),
+		Remapped:   remaps,
+	}
+	for _, phase := range other.Phases {
+		result.Phases = append(result.Phases, phase.ID)
+	}
+
+	target.Phases = append(target.Phases, other.Phases...)
+	target.Tasks = append(target.Tasks, other.Tasks...)
+	target.Tests = append(target.Tests, other.Tests...)
+	target.Milestones = append(target.Milestones, other.Milestones...)
+	target.Blockers = append(target.Blockers, other.Blockers...)
+	target.Suppressions = append(target.Suppressions, other.Suppressions...)
+	target.Events = append(target.Events, other.Events...)
+	sort.SliceStable(target.Events, func(i, j int) bool {
+		return target.Events[i].Timestamp.Before(target.Events[j].Timestamp)
+	})
+
+	if state := other.CurrentState; state != nil && state.ActivePhase != "" &&
+		(target.CurrentState == nil || target.CurrentState.ActivePhase == "") {
+		target.CurrentState = state
+	}
+	if target.Status == epic.StatusPending && statusOfPhases(target.Phases) != epic.StatusPending {
+		target.Status = epic.StatusWIP
+	}
+
+	data := fmt.Sprintf("Epic %s merged into epic %s: phases %s", other.ID, target.ID, strings.Join(result.Phases, ", "))
+	if len(remaps) > 0 {
+		renamed := make([]string, len(remaps))
+		for i, r := range remaps {
+			renamed[i] = r.From + " as " + r.To
+		}
+		data += fmt.Sprintf(" (renamed %s)", strings.Join(renamed, ", "))
+	}
+	CreateEvent(target, EventEpicMerged, "", "", "", data, timestamp)
+
+	return result, nil
+}
+
+// collidingIDs returns the IDs of other already used in target, each with the
+// first <id>-<n> free in both epics
+func collidingIDs(target, other *epic.Epic) []IDRemap {
+	taken, incoming := entityIDs(target), entityIDs(other)
+
+	var remaps []IDRemap
+	for _, kind := range []string{"phase", "task", "test", "milestone", "blocker"} {
+		used := make(map[string]bool)
+		for _, id := range append(taken[kind], incoming[kind]...) {
+			used[id] = true
+		}
+		for _, id := range incoming[kind] {
+			if !slices.Contains(taken[kind], id) {
+				continue
+			}
+			renamed := id
+			for n := 2; used[renamed]; n++ {
+				renamed = fmt.Sprintf("%s-%d", id, n)
+			}
+			used[renamed] = true
+			remaps = append(remaps, IDRemap{Kind: kind, From: id, To: renamed})
+		}
+	}
+	return remaps
+}
+
+// entityIDs returns the IDs of the phases, tasks, tests, milestones and blockers of e by kind
+func entityIDs(e *epic.Epic) map[string][]string {
+	ids := make(map[string][]string)
+	for _, phase := range e.Phases {
+		ids["phase"] = append(ids["phase"], phase.ID)
+	}
+	for _, task := range e.Tasks {
+		ids["task"] = append(ids["task"], task.ID)
+	}
+	for _, test := range e.Tests {
+		ids["test"] = append(ids["test"], test.ID)
+	}
+	for _, milestone := range e.Milestones {
+		ids["milestone"] = append(ids["milestone"], milestone.ID)
+	}
+	for _, blocker := range e.Blockers {
+		ids["blocker"] = append(ids["blocker"], blocker.ID)
+	}
+	return ids
+}
+
+// renameIDs applies remaps to the entities of e and every reference to them
+func renameIDs(e *epic.Epic, remaps []IDRemap) {
+	if len(remaps) == 0 {
+		return
+	}
+	renamed := make(map[string]map[string]string)
+	for _, r := range remaps {
+		if renamed[r.Kind] == nil {
+			renamed[r.Kind] = make(map[string]string)
+		}
+		renamed[r.Kind][r.From] = r.To
+	}
+	rename := func(id string, kinds ...string) string {
+		for _, kind := range kinds {
+			if to, ok := renamed[kind][id]; ok {
+				return to
+			}
+		}
+		return id
+	}
+	renameList := func(ids []string, kinds ...string) string {
+		for i, id := range ids {
+			ids[i] = rename(id, kinds...)
+		}
+		return strings.Join(ids, ",")
+	}
+
+	for i := range e.Phases {
+		phase := &e.Phases[i]
+		phase.ID = rename(phase.ID, "phase")
+		phase.DependsOn = renameList(phase.DependencyIDs(), "phase")
+	}
+	for i := range e.Tasks {
+		task := &e.Tasks[i]
+		task.ID = rename(task.ID, "task")
+		task.PhaseID = rename(task.PhaseID, "phase")
+		task.DependsOn = renameList(task.DependencyIDs(), "task")
+		if task.ParentTaskID != "" {
+			task.ParentTaskID = rename(task.ParentTaskID, "task")
+		}
+	}
+	for i := range e.Tests {
+		test := &e.Tests[i]
+		test.ID = rename(test.ID, "test")
+		test.TaskID = rename(test.TaskID, "task")
+		test.PhaseID = rename(test.PhaseID, "phase")
+		test.Requires = renameList(test.RequiredIDs(), "test", "task")
+	}
+	for i := range e.Milestones {
+		e.Milestones[i].ID = rename(e.Milestones[i].ID, "milestone")
+	}
+	for i := range e.Blockers {
+		blocker := &e.Blockers[i]
+		blocker.ID = rename(blocker.ID, "blocker")
+		if blocker.Entity != "" {
+			blocker.Entity = rename(blocker.Entity, "task", "phase")
+		}
+	}
+	for i := range e.Suppressions {
+		if entity := e.Suppressions[i].Entity; entity != "" {
+			e.Suppressions[i].Entity = rename(entity, "task", "phase")
+		}
+	}
+	if state := e.CurrentState; state != nil {
+		state.ActivePhase = rename(state.ActivePhase, "phase")
+		state.ActiveTask = rename(state.ActiveTask, "task")
+	}
+	for i := range e.Events {
+		event := &e.Events[i]
+		kind, id := eventSubject(*event)
+		if to, ok := renamed[kind][id]; ok {
+			subject := strings.Fields(event.Data)[0] + " "
+			if rest, found := strings.CutPrefix(event.Data, subject+id); found {
+				event.Data = subject + to + rest
+			}
+		}
+	}
+}
+
+// eventSubject returns the kind (phase, task, test or blocker) and ID of the
+// entity an event's data starts with, e.g. "Task 1A_1 (Name) completed"
+func eventSubject(event epic.Event) (kind, id string) {
+	fields := strings.Fields(event.Data)
+	if len(fields) < 2 {
+		return "", ""
+	}
+	kind = strings.ToLower(fields[0])
+	switch kind {
+	case "phase", "task", "test", "blocker":
+		return kind, strings.TrimSuffix(fields[1], ":")
+	}
+	return "", ""
+}
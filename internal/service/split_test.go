@@ -0,0 +1,229 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+func createSplitEpic() *epic.Epic {
+	at := func(hour int) time.Time { return time.Date(2025, 8, 16, hour, 0, 0, 0, time.UTC) }
+	return &epic.Epic{
+		ID:     "epic-9",
+		Name:   "Large Epic",
+		Status: epic.StatusWIP,
+		CurrentState: &epic.CurrentState{
+			ActivePhase: "3A",
+			ActiveTask:  "3A_1",
+		},
+		Phases: []epic.Phase{
+			{ID: "2A", Name: "Foundations", Status: epic.StatusCompleted},
+			{ID: "3A", Name: "Reports", Status: epic.StatusWIP, DependsOn: "2A"},
+			{ID: "3B", Name: "Exports", Status: epic.StatusPending, DependsOn: "3A"},
+		},
+		Milestones: []epic.Milestone{{ID: "M1", Name: "Beta", TargetDate: "2025-09-01"}},
+		Blockers: []epic.Blocker{
+			{ID: "B1", Entity: "3A_1", RaisedAt: at(10), Description: "Waiting for data"},
+			{ID: "B2", RaisedAt: at(10), Description: "Budget"},
+		},
+		Suppressions: []epic.Suppression{{Rule: "stale-wip", Entity: "3A", Reason: "Slow reviews"}},
+		Tasks: []epic.Task{
+			{ID: "2A_1", PhaseID: "2A", Name: "Schema", Status: epic.StatusCompleted},
+			{ID: "3A_1", PhaseID: "3A", Name: "Report view", Status: epic.StatusWIP, DependsOn: "2A_1"},
+			{ID: "3B_1", PhaseID: "3B", Name: "CSV export", Status: epic.StatusPending, DependsOn: "3A_1"},
+		},
+		Tests: []epic.Test{
+			{ID: "2A_1_T1", TaskID: "2A_1", PhaseID: "2A", Name: "Schema test", Status: epic.StatusCompleted},
+			{ID: "3A_1_T1", TaskID: "3A_1", PhaseID: "3A", Name: "Report test", Status: epic.StatusPending, Requires: "2A_1_T1"},
+			{ID: "3B_1_T1", TaskID: "3B_1", PhaseID: "3B", Name: "Export test", Status: epic.StatusPending, Requires: "3A_1"},
+		},
+		Events: []epic.Event{
+			{ID: "e1", Type: "task_completed", Timestamp: at(8), Data: "Task 2A_1 (Schema) completed"},
+			{ID: "e2", Type: "phase_started", Timestamp: at(9), Data: "Phase 3A (Reports) started"},
+			{ID: "e3", Type: "blocker_raised", Timestamp: at(10), Data: "Blocker B1 raised on 3A_1: Waiting for data"},
+			{ID: "e4", Type: "custom_fields_changed", Timestamp: at(11), Data: "Task 3B_1 custom fields changed: sprint=7"},
+		},
+	}
+}
+
+func TestSplitEpic(t *testing.T) {
+	splitAt := time.Date(2025, 8, 17, 9, 0, 0, 0, time.UTC)
+
+	t.Run("moves phases with their tasks, tests, blockers and events", func(t *testing.T) {
+		e := createSplitEpic()
+		split, result, err := SplitEpic(e, []string{"3B", "3A"}, "epic-9b", "", splitAt)
+		if err != nil {
+			t.Fatalf("SplitEpic() error = %v", err)
+		}
+
+		if split.ID != "epic-9b" || split.Name != "Large Epic" || split.Status != epic.StatusWIP {
+			t.Errorf("split epic = %s %q %s", split.ID, split.Name, split.Status)
+		}
+		if want := []string{"3A", "3B"}; !reflect.DeepEqual(result.Phases, want) {
+			t.Errorf("moved phases = %v, want %v", result.Phases, want)
+		}
+		if len(e.Phases) != 1 || len(e.Tasks) != 1 || len(e.Tests) != 1 || len(e.Blockers) != 1 || len(e.Milestones) != 1 {
+			t.Errorf("source keeps %d phases, %d tasks, %d tests, %d blockers, %d milestones",
+				len(e.Phases), len(e.Tasks), len(e.Tests), len(e.Blockers), len(e.Milestones))
+		}
+		if len(split.Tasks) != 2 || len(split.Tests) != 2 || len(split.Blockers) != 1 || len(split.Suppressions) != 1 {
+			t.Errorf("split epic has %d tasks, %d tests, %d blockers, %d suppressions",
+				len(split.Tasks), len(split.Tests), len(split.Blockers), len(split.Suppressions))
+		}
+		if result.Events != 3 || split.Events[0].ID != "e2" || split.Events[3].Type != string(EventEpicSplit) {
+			t.Errorf("split events = %+v", split.Events)
+		}
+		if last := e.Events[len(e.Events)-1]; len(e.Events) != 2 || last.Data != "Epic epic-9 split: phases 3A, 3B moved to epic epic-9b" {
+			t.Errorf("source events = %+v", e.Events)
+		}
+
+		if split.CurrentState == nil || split.CurrentState.ActiveTask != "3A_1" || e.CurrentState.ActivePhase != "" {
+			t.Errorf("current state = %+v, source %+v", split.CurrentState, e.CurrentState)
+		}
+		if split.Phases[0].DependsOn != "" || split.Tasks[0].DependsOn != "" || split.Tests[0].Requires != "" {
+			t.Errorf("dependencies on work left behind were kept")
+		}
+		if split.Phases[1].DependsOn != "3A" || split.Tasks[1].DependsOn != "3A_1" {
+			t.Errorf("dependencies between moved work were dropped")
+		}
+		want := []string{"phase 3A on 2A", "task 3A_1 on 2A_1", "test 3A_1_T1 on 2A_1_T1"}
+		if !reflect.DeepEqual(result.DroppedDependencies, want) {
+			t.Errorf("dropped dependencies = %v, want %v", result.DroppedDependencies, want)
+		}
+	})
+
+	t.Run("refuses to leave work depending on moved work behind", func(t *testing.T) {
+		e := createSplitEpic()
+		_, _, err := SplitEpic(e, []string{"3A"}, "epic-9b", "", splitAt)
+		if err == nil || !strings.Contains(err.Error(), "cannot split: phase 3B depends on 3A") {
+			t.Fatalf("SplitEpic() error = %v", err)
+		}
+		if len(e.Phases) != 3 {
+			t.Errorf("source changed after a rejected split")
+		}
+	})
+
+	t.Run("rejects invalid splits", func(t *testing.T) {
+		frozenAt := splitAt
+		for message, split := range map[string]func(e *epic.Epic) error{
+			"phase 9Z not found": func(e *epic.Epic) error {
+				_, _, err := SplitEpic(e, []string{"9Z"}, "epic-9b", "", splitAt)
+				return err
+			},
+			"cannot split off all phases": func(e *epic.Epic) error {
+				_, _, err := SplitEpic(e, []string{"2A", "3A", "3B"}, "epic-9b", "", splitAt)
+				return err
+			},
+			"must not reuse the ID": func(e *epic.Epic) error {
+				_, _, err := SplitEpic(e, []string{"3B"}, "epic-9", "", splitAt)
+				return err
+			},
+			"frozen": func(e *epic.Epic) error {
+				e.Phases[2].FrozenAt = &frozenAt
+				_, _, err := SplitEpic(e, []string{"3B"}, "epic-9b", "", splitAt)
+				return err
+			},
+		} {
+			if err := split(createSplitEpic()); err == nil || !strings.Contains(err.Error(), message) {
+				t.Errorf("SplitEpic() error = %v, want %q", err, message)
+			}
+		}
+	})
+}
+
+func TestMergeEpic(t *testing.T) {
+	mergeAt := time.Date(2025, 8, 18, 9, 0, 0, 0, time.UTC)
+
+	t.Run("folds a split epic back in", func(t *testing.T) {
+		e := createSplitEpic()
+		split, _, err := SplitEpic(e, []string{"3A", "3B"}, "epic-9b", "", mergeAt)
+		if err != nil {
+			t.Fatalf("SplitEpic() error = %v", err)
+		}
+
+		result, err := MergeEpic(e, split, false, mergeAt)
+		if err != nil {
+			t.Fatalf("MergeEpic() error = %v", err)
+		}
+		if len(e.Phases) != 3 || len(e.Tasks) != 3 || len(e.Tests) != 3 || len(e.Blockers) != 2 || len(result.Remapped) != 0 {
+			t.Errorf("merged epic has %d phases, %d tasks, %d tests, %d blockers, remapped %v",
+				len(e.Phases), len(e.Tasks), len(e.Tests), len(e.Blockers), result.Remapped)
+		}
+		if e.CurrentState.ActivePhase != "3A" {
+			t.Errorf("current state = %+v, want the merged active phase", e.CurrentState)
+		}
+		for i := 1; i < len(e.Events); i++ {
+			if e.Events[i].Timestamp.Before(e.Events[i-1].Timestamp) {
+				t.Fatalf("events not in chronological order: %+v", e.Events)
+			}
+		}
+		if last := e.Events[len(e.Events)-1]; last.Type != string(EventEpicMerged) || last.Data != "Epic epic-9b merged into epic epic-9: phases 3A, 3B" {
+			t.Errorf("last event = %+v", last)
+		}
+	})
+
+	t.Run("detects and remaps colliding IDs", func(t *testing.T) {
+		target := createSplitEpic()
+		target.Phases[1].Status = epic.StatusCompleted
+		target.CurrentState = nil
+		other := createSplitEpic()
+		other.ID = "epic-10"
+		other.Phases = append(other.Phases, epic.Phase{ID: "3A-2", Name: "Taken", Status: epic.StatusPending})
+
+		_, err := MergeEpic(target, other, false, mergeAt)
+		if err == nil || !strings.Contains(err.Error(), "IDs already used in epic epic-9: phase 2A, phase 3A, phase 3B, task 2A_1") {
+			t.Fatalf("MergeEpic() error = %v", err)
+		}
+		if len(target.Phases) != 3 {
+			t.Errorf("target changed after a rejected merge")
+		}
+
+		result, err := MergeEpic(target, other, true, mergeAt)
+		if err != nil {
+			t.Fatalf("MergeEpic() with remap error = %v", err)
+		}
+		if want := (IDRemap{Kind: "phase", From: "3A", To: "3A-3"}); result.Remapped[1] != want {
+			t.Errorf("remapped = %+v, want %+v", result.Remapped[1], want)
+		}
+
+		task := findTaskByID(target, "3B_1-2")
+		if task == nil || task.PhaseID != "3B-2" || task.DependsOn != "3A_1-2" {
+			t.Fatalf("remapped task = %+v", task)
+		}
+		test := findTestByID(target, "3B_1_T1-2")
+		if test == nil || test.TaskID != "3B_1-2" || test.Requires != "3A_1-2" {
+			t.Errorf("remapped test = %+v", test)
+		}
+		if phase := findPhaseByID(target, "3B-2"); phase == nil || phase.DependsOn != "3A-3" {
+			t.Errorf("remapped phase = %+v", phase)
+		}
+		if blocker := target.FindBlocker("B1-2"); blocker == nil || blocker.Entity != "3A_1-2" {
+			t.Errorf("remapped blocker = %+v", blocker)
+		}
+		if target.CurrentState.ActivePhase != "3A-3" || target.CurrentState.ActiveTask != "3A_1-2" {
+			t.Errorf("current state = %+v", target.CurrentState)
+		}
+
+		var renamedEvent bool
+		for _, event := range target.Events {
+			if event.Data == "Phase 3A-3 (Reports) started" {
+				renamedEvent = true
+			}
+		}
+		if !renamedEvent {
+			t.Errorf("events of remapped entities keep their old IDs: %+v", target.Events)
+		}
+	})
+
+	t.Run("refuses two phases in progress", func(t *testing.T) {
+		other := createSplitEpic()
+		other.ID = "epic-10"
+		_, err := MergeEpic(createSplitEpic(), other, true, mergeAt)
+		if err == nil || !strings.Contains(err.Error(), "both epics have a phase in progress (3A and 3A)") {
+			t.Errorf("MergeEpic() error = %v", err)
+		}
+	})
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// SummarizeOptions control which events SummarizeEvents rolls up
+type SummarizeOptions struct {
+	Window  time.Duration // Width of the time buckets (aligned to UTC midnight for whole days)
+	Min     int           // Fewest events of one actor in a bucket that count as a burst
+	Compact bool          // Drop the summarized events, keeping only the summaries
+}
+
+// EventSummary is one roll-up event created by SummarizeEvents
+type EventSummary struct {
+	ID     string         `json:"id"`
+	Actor  string         `json:"actor,omitempty"`
+	From   time.Time      `json:"from"`
+	To     time.Time      `json:"to"`
+	Events int            `json:"events"`
+	Counts map[string]int `json:"counts"`
+	Data   string         `json:"data"`
+}
+
+// SummarizeReport lists the summaries SummarizeEvents added
+type SummarizeReport struct {
+	EpicID    string         `json:"epic_id"`
+	Window    string         `json:"window"`
+	Min       int            `json:"min"`
+	Compact   bool           `json:"compact"`
+	Summaries []EventSummary `json:"summaries"`
+	Removed   int            `json:"removed_events"`
+}
+
+// summaryKey identifies a burst: the events of one actor in one window
+type summaryKey struct {
+	start int64 // Unix time of the window start
+	actor string
+}
+
+// SummarizeEvents collapses bursts of task and test events into roll-up
+// events: per window and actor, at least Min events become one
+// events_summarized event counting them by type. The summary is placed after
+// the last event of its burst and carries its timestamp. Milestones - epic and
+// phase events, warnings, handoffs - are never rolled up. Bursts that already
+// have a summary are left alone, so running it again adds nothing.
+func SummarizeEvents(e *epic.Epic, opts SummarizeOptions) *SummarizeReport {
+	report := &SummarizeReport{
+		EpicID:    e.ID,
+		Window:    formatWindow(opts.Window),
+		Min:       opts.Min,
+		Compact:   opts.Compact,
+		Summaries: []EventSummary{},
+	}
+
+	summarized := make(map[summaryKey]bool)
+	for _, event := range e.Events {
+		if EventType(event.Type) == EventSummarized {
+			summarized[summaryKey{summaryStart(event.ID), event.Actor}] = true
+		}
+	}
+
+	var keys []summaryKey
+	bursts := make(map[summaryKey][]int)
+	for i, event := range e.Events {
+		if !isRollupEvent(event) {
+			continue
+		}
+		key := summaryKey{event.Timestamp.UTC().Truncate(opts.Window).Unix(), event.Actor}
+		if _, ok := bursts[key]; !ok {
+			keys = append(keys, key)
+		}
+		bursts[key] = append(bursts[key], i)
+	}
+
+	insertAfter := make(map[int]epic.Event)
+	remove := make(map[int]bool)
+	for _, key := range keys {
+		indices := bursts[key]
+		if len(indices) < max(opts.Min, 1) || summarized[key] {
+			continue
+		}
+
+		summary := EventSummary{
+			ID:     fmt.Sprintf("%s_%d", EventSummarized, key.start),
+			Actor:  key.actor,
+			From:   e.Events[indices[0]].Timestamp,
+			Events: len(indices),
+			Counts: make(map[string]int),
+		}
+		last := indices[0]
+		for _, i := range indices {
+			event := e.Events[i]
+			summary.Counts[event.Type]++
+			if event.Timestamp.Before(summary.From) {
+				summary.From = event.Timestamp
+			}
+			if !event.Timestamp.Before(summary.To) {
+				summary.To = event.Timestamp
+				last = i
+			}
+			if opts.Compact {
+				remove[i] = true
+			}
+		}
+		summary.Data = formatSummaryData(summary)
+
+		insertAfter[last] = epic.Event{
+			ID:        summary.ID,
+			Type:      string(EventSummarized),
+			Timestamp: summary.To,
+			Actor:     summary.Actor,
+			Data:      summary.Data,
+
+			CorrelationID: correlationID,
+		}
+		report.Summaries = append(report.Summaries, summary)
+	}
+
+	if len(report.Summaries) == 0 {
+		return report
+	}
+
+	events := make([]epic.Event, 0, len(e.Events)-len(remove)+len(insertAfter))
+	for i, event := range e.Events {
+		if !remove[i] {
+			events = append(events, event)
+		}
+		if summary, ok := insertAfter[i]; ok {
+			events = append(events, summary)
+		}
+	}
+	e.Events = events
+	report.Removed = len(remove)
+
+	return report
+}
+
+// isRollupEvent reports whether an event is low-level activity that may be
+// rolled up: any task or test event
+func isRollupEvent(event epic.Event) bool {
+	entityType, _, _ := strings.Cut(event.Type, "_")
+	return entityType == "task" || entityType == "test"
+}
+
+// summaryStart reads the window start back from a summary's ID
+func summaryStart(id string) int64 {
+	unix, err := strconv.ParseInt(strings.TrimPrefix(id, string(EventSummarized)+"_"), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return unix
+}
+
+// formatSummaryData describes a burst, e.g. "Summary of 12 events by agent_a
+// from 2025-08-16 09:02 to 2025-08-16 17:40: 7 test_passed, 5 task_started"
+func formatSummaryData(summary EventSummary) string {
+	types := make([]string, 0, len(summary.Counts))
+	for eventType := range summary.Counts {
+		types = append(types, eventType)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if summary.Counts[types[i]] != summary.Counts[types[j]] {
+			return summary.Counts[types[i]] > summary.Counts[types[j]]
+		}
+		return types[i] < types[j]
+	})
+
+	counts := make([]string, len(types))
+	for i, eventType := range types {
+		counts[i] = fmt.Sprintf("%d %s", summary.Counts[eventType], eventType)
+	}
+
+	by := ""
+	if summary.Actor != "" {
+		by = " by " + summary.Actor
+	}
+	const layout = "2006-01-02 15:04"
+	return fmt.Sprintf("Summary of %d events%s from %s to %s: %s", summary.Events, by,
+		summary.From.UTC().Format(layout), summary.To.UTC().Format(layout), strings.Join(counts, ", "))
+}
+
+// formatWindow writes whole days as 1d, other windows as Go durations
+// without trailing zero units (6h, 90m)
+func formatWindow(window time.Duration) string {
+	if window > 0 && window%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", window/(24*time.Hour))
+	}
+	formatted := window.String()
+	if strings.HasSuffix(formatted, "m0s") {
+		formatted = strings.TrimSuffix(formatted, "0s")
+	}
+	if strings.HasSuffix(formatted, "h0m") {
+		formatted = strings.TrimSuffix(formatted, "0m")
+	}
+	return formatted
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+func createBusyEpic() *epic.Epic {
+	e := &epic.Epic{
+		ID:     "busy",
+		Phases: []epic.Phase{{ID: "P1", Name: "Phase 1"}},
+		Tasks: []epic.Task{
+			{ID: "T1", PhaseID: "P1", Name: "Task 1"},
+			{ID: "T2", PhaseID: "P1", Name: "Task 2"},
+		},
+		Tests: []epic.Test{{ID: "T1_1", TaskID: "T1", PhaseID: "P1", Name: "Test 1"}},
+	}
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 8, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	SetActor("agent_a")
+	defer SetActor("")
+	CreateEvent(e, EventPhaseStarted, "P1", "", "", "", at(16, 9, 0))
+	CreateEvent(e, EventTaskStarted, "P1", "T1", "", "", at(16, 9, 1))
+	CreateEvent(e, EventTestStarted, "P1", "T1", "T1_1", "", at(16, 9, 2))
+	CreateEvent(e, EventTestFailed, "P1", "T1", "T1_1", "flaky", at(16, 9, 3))
+	CreateEvent(e, EventTestPassed, "P1", "T1", "T1_1", "", at(16, 9, 4))
+	CreateEvent(e, EventTaskCompleted, "P1", "T1", "", "", at(16, 17, 40))
+	// A second agent and a second day stay below the minimum
+	SetActor("agent_b")
+	CreateEvent(e, EventTaskStarted, "P1", "T2", "", "", at(16, 18, 0))
+	SetActor("agent_a")
+	CreateEvent(e, EventTaskStarted, "P1", "T2", "", "", at(17, 9, 0))
+	return e
+}
+
+func TestSummarizeEvents(t *testing.T) {
+	e := createBusyEpic()
+	report := SummarizeEvents(e, SummarizeOptions{Window: 24 * time.Hour, Min: 3})
+
+	if len(report.Summaries) != 1 {
+		t.Fatalf("expected 1 summary, got %d", len(report.Summaries))
+	}
+	summary := report.Summaries[0]
+	wantData := "Summary of 5 events by agent_a from 2025-08-16 09:01 to 2025-08-16 17:40: 1 task_completed, 1 task_started, 1 test_failed, 1 test_passed, 1 test_started"
+	if summary.Data != wantData {
+		t.Errorf("summary data = %q, want %q", summary.Data, wantData)
+	}
+	if report.Window != "1d" || report.Removed != 0 {
+		t.Errorf("window = %q, removed = %d", report.Window, report.Removed)
+	}
+
+	if len(e.Events) != 9 {
+		t.Fatalf("expected the summary alongside 8 events, got %d events", len(e.Events))
+	}
+	added := e.Events[6]
+	if added.Type != "events_summarized" || added.ID != "events_summarized_1755302400" || added.Actor != "agent_a" {
+		t.Errorf("summary event placed or built wrong: %+v", added)
+	}
+	if !added.Timestamp.Equal(summary.To) {
+		t.Errorf("summary timestamp = %v, want %v", added.Timestamp, summary.To)
+	}
+
+	again := SummarizeEvents(e, SummarizeOptions{Window: 24 * time.Hour, Min: 3})
+	if len(again.Summaries) != 0 || len(e.Events) != 9 {
+		t.Errorf("summarizing again added %d summaries", len(again.Summaries))
+	}
+}
+
+func TestSummarizeEvents_Compact(t *testing.T) {
+	e := createBusyEpic()
+	report := SummarizeEvents(e, SummarizeOptions{Window: 24 * time.Hour, Min: 3, Compact: true})
+
+	if report.Removed != 5 {
+		t.Errorf("removed = %d, want 5", report.Removed)
+	}
+	var types []string
+	for _, event := range e.Events {
+		types = append(types, event.Type)
+	}
+	want := []string{"phase_started", "events_summarized", "task_started", "task_started"}
+	if len(types) != len(want) {
+		t.Fatalf("events = %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("events = %v, want %v", types, want)
+			break
+		}
+	}
+}
+
+func TestSummarizeEvents_HourWindows(t *testing.T) {
+	e := createBusyEpic()
+	report := SummarizeEvents(e, SummarizeOptions{Window: time.Hour, Min: 4})
+
+	if len(report.Summaries) != 1 || report.Summaries[0].Events != 4 {
+		t.Fatalf("expected the 09:00 burst of 4 events, got %+v", report.Summaries)
+	}
+	if report.Window != "1h" {
+		t.Errorf("window = %q", report.Window)
+	}
+}
@@ -0,0 +1,122 @@
+package spec
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Section is a heading of a Markdown spec document
+type Section struct {
+	Anchor string `json:"anchor"`
+	Title  string `json:"title"`
+	Level  int    `json:"level"`
+	Line   int    `json:"line"`
+}
+
+// Document is a parsed spec document
+type Document struct {
+	Path     string
+	Sections []Section
+}
+
+var headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+
+// ParseRef splits a spec reference of the form file#anchor. The anchor is optional.
+func ParseRef(ref string) (file, anchor string) {
+	file, anchor, _ = strings.Cut(strings.TrimSpace(ref), "#")
+	return file, anchor
+}
+
+// LoadDocument reads a Markdown spec document and collects its sections
+func LoadDocument(path string) (*Document, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("spec document not found: %s", path)
+		}
+		return nil, fmt.Errorf("failed to read spec document: %w", err)
+	}
+	defer file.Close()
+
+	doc := &Document{Path: path}
+	seen := make(map[string]int)
+	inCode := false
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		trimmed := strings.TrimSpace(text)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+
+		match := headingPattern.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
+
+		// Repeated headings get -1, -2, ... suffixes like on GitHub
+		anchor := Slug(match[2])
+		if count := seen[anchor]; count > 0 {
+			seen[anchor] = count + 1
+			anchor = fmt.Sprintf("%s-%d", anchor, count)
+		} else {
+			seen[anchor] = 1
+		}
+
+		doc.Sections = append(doc.Sections, Section{
+			Anchor: anchor,
+			Title:  strings.TrimSpace(match[2]),
+			Level:  len(match[1]),
+			Line:   line,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read spec document: %w", err)
+	}
+
+	return doc, nil
+}
+
+// Section finds a section by its anchor
+func (d *Document) Section(anchor string) *Section {
+	for i := range d.Sections {
+		if d.Sections[i].Anchor == anchor {
+			return &d.Sections[i]
+		}
+	}
+	return nil
+}
+
+// Slug turns a heading into its anchor the way GitHub does: lower case, spaces
+// become hyphens and punctuation is dropped
+func Slug(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+// resolve returns the path of a spec file referenced from an epic in baseDir
+func resolve(baseDir, file string) string {
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(baseDir, file)
+}
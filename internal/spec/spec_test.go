@@ -0,0 +1,141 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const specDoc = "# Search Spec\n" +
+	"\n" +
+	"## Indexing\n" +
+	"### Schema\n" +
+	"### Reindexing\n" +
+	"\n" +
+	"```\n" +
+	"## Not a section\n" +
+	"```\n" +
+	"\n" +
+	"## Query Syntax (v2)\n" +
+	"## Notes\n" +
+	"## Notes\n"
+
+func writeSpec(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "docs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docs", "spec.md"), []byte(specDoc), 0644))
+	return dir
+}
+
+func traceEpic() *epic.Epic {
+	return &epic.Epic{
+		ID:        "9",
+		Name:      "Search",
+		Status:    epic.StatusPending,
+		CreatedAt: time.Date(2025, 8, 16, 9, 0, 0, 0, time.UTC),
+		Phases: []epic.Phase{
+			{ID: "1", Name: "Indexing", Status: epic.StatusPending, SpecRef: "docs/spec.md#indexing"},
+			{ID: "2", Name: "Query", Status: epic.StatusPending, SpecRef: "docs/spec.md#query-syntax-v2"},
+		},
+		Tasks: []epic.Task{
+			{ID: "1_1", PhaseID: "1", Name: "Schema", Status: epic.StatusPending, SpecRef: "docs/spec.md#schema"},
+			{ID: "1_2", PhaseID: "1", Name: "Typo", Status: epic.StatusPending, SpecRef: "docs/spec.md#reindexng"},
+		},
+	}
+}
+
+func TestSlug(t *testing.T) {
+	assert.Equal(t, "query-syntax-v2", Slug("Query Syntax (v2)"))
+	assert.Equal(t, "api_keys--tokens", Slug("API_Keys & Tokens"))
+}
+
+func TestParseRef(t *testing.T) {
+	file, anchor := ParseRef("docs/spec.md#schema")
+	assert.Equal(t, "docs/spec.md", file)
+	assert.Equal(t, "schema", anchor)
+
+	file, anchor = ParseRef("docs/spec.md")
+	assert.Equal(t, "docs/spec.md", file)
+	assert.Empty(t, anchor)
+}
+
+func TestLoadDocument(t *testing.T) {
+	dir := writeSpec(t)
+
+	doc, err := LoadDocument(filepath.Join(dir, "docs", "spec.md"))
+	require.NoError(t, err)
+
+	var anchors []string
+	for _, section := range doc.Sections {
+		anchors = append(anchors, section.Anchor)
+	}
+	assert.Equal(t, []string{"search-spec", "indexing", "schema", "reindexing", "query-syntax-v2", "notes", "notes-1"}, anchors)
+	assert.Equal(t, Section{Anchor: "schema", Title: "Schema", Level: 3, Line: 4}, *doc.Section("schema"))
+
+	_, err = LoadDocument(filepath.Join(dir, "missing.md"))
+	assert.ErrorContains(t, err, "spec document not found")
+}
+
+func TestCheckRefs(t *testing.T) {
+	dir := writeSpec(t)
+	e := traceEpic()
+	e.Tasks = append(e.Tasks,
+		epic.Task{ID: "1_3", PhaseID: "1", Name: "Missing file", SpecRef: "docs/other.md#x"},
+		epic.Task{ID: "1_4", PhaseID: "1", Name: "No file", SpecRef: "#schema"},
+	)
+
+	problems := CheckRefs(e, dir)
+	require.Len(t, problems, 3)
+	assert.Equal(t, `task 1_2: spec_ref "docs/spec.md#reindexng": section #reindexng not found in docs/spec.md`, problems[0].String())
+	assert.Contains(t, problems[1].Problem, "spec document not found")
+	assert.Equal(t, "missing spec file (expected file#anchor)", problems[2].Problem)
+}
+
+func TestTrace(t *testing.T) {
+	dir := writeSpec(t)
+
+	report, err := Trace(traceEpic(), dir, nil)
+	require.NoError(t, err)
+
+	require.Len(t, report.Documents, 1)
+	doc := report.Documents[0]
+	assert.Equal(t, filepath.Join("docs", "spec.md"), doc.Path)
+
+	covered := make(map[string]bool)
+	for _, section := range doc.Sections {
+		covered[section.Anchor] = section.Covered
+	}
+	assert.Equal(t, map[string]bool{
+		"search-spec":     true,  // through its covered subsections
+		"indexing":        true,  // phase 1 has tasks
+		"schema":          true,  // task 1_1
+		"reindexing":      false, // only a broken reference
+		"query-syntax-v2": false, // phase 2 has no tasks
+		"notes":           false,
+		"notes-1":         false,
+	}, covered)
+	assert.Equal(t, 4, doc.Uncovered)
+
+	assert.Equal(t, []string{"1"}, doc.Sections[1].Phases)
+	assert.Equal(t, []string{"1_1"}, doc.Sections[2].Tasks)
+
+	require.Len(t, report.Problems, 1)
+	assert.Equal(t, "1_2", report.Problems[0].EntityID)
+}
+
+func TestTraceExtraDocuments(t *testing.T) {
+	dir := writeSpec(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.md"), []byte("# Other\n## Part\n"), 0644))
+
+	report, err := Trace(&epic.Epic{ID: "empty"}, dir, []string{filepath.Join(dir, "other.md")})
+	require.NoError(t, err)
+	require.Len(t, report.Documents, 1)
+	assert.Equal(t, "other.md", report.Documents[0].Path)
+	assert.Equal(t, 2, report.Documents[0].Uncovered)
+}
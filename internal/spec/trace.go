@@ -0,0 +1,229 @@
+package spec
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// Ref is a spec reference of a phase or task
+type Ref struct {
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+	Ref        string `json:"spec_ref"`
+}
+
+// RefProblem is a spec reference that does not point to an existing section
+type RefProblem struct {
+	Ref
+	Problem string `json:"problem"`
+}
+
+func (p RefProblem) String() string {
+	return fmt.Sprintf("%s %s: spec_ref %q: %s", p.EntityType, p.EntityID, p.Ref.Ref, p.Problem)
+}
+
+// SectionTrace lists the phases and tasks that reference a spec section. A section
+// is covered when a task refers to it, directly or through its phase, or when one
+// of its subsections is covered.
+type SectionTrace struct {
+	Section
+	Phases  []string `json:"phases,omitempty"`
+	Tasks   []string `json:"tasks,omitempty"`
+	Covered bool     `json:"covered"`
+}
+
+// DocumentTrace is the traceability of one spec document
+type DocumentTrace struct {
+	Path      string         `json:"path"`
+	Sections  []SectionTrace `json:"sections"`
+	Uncovered int            `json:"uncovered"`
+}
+
+// TraceReport maps spec sections to the epic's phases and tasks
+type TraceReport struct {
+	Documents []DocumentTrace `json:"documents"`
+	Problems  []RefProblem    `json:"problems,omitempty"`
+}
+
+// Refs returns the spec references of all phases and tasks in epic order
+func Refs(e *epic.Epic) []Ref {
+	var refs []Ref
+	for _, phase := range e.Phases {
+		if phase.SpecRef != "" {
+			refs = append(refs, Ref{EntityType: "phase", EntityID: phase.ID, Ref: phase.SpecRef})
+		}
+	}
+	for _, task := range e.Tasks {
+		if task.SpecRef != "" {
+			refs = append(refs, Ref{EntityType: "task", EntityID: task.ID, Ref: task.SpecRef})
+		}
+	}
+	return refs
+}
+
+// CheckRefs verifies that every spec reference names an existing document and,
+// if it has an anchor, an existing section. Relative files are resolved against baseDir,
+// the directory of the epic file.
+func CheckRefs(e *epic.Epic, baseDir string) []RefProblem {
+	docs := newDocumentCache(baseDir)
+	var problems []RefProblem
+
+	for _, ref := range Refs(e) {
+		if problem := docs.check(ref); problem != "" {
+			problems = append(problems, RefProblem{Ref: ref, Problem: problem})
+		}
+	}
+	return problems
+}
+
+// Trace builds the traceability report for every spec document referenced by the
+// epic plus the given extra documents (paths relative to baseDir or absolute)
+func Trace(e *epic.Epic, baseDir string, extraDocs []string) (*TraceReport, error) {
+	docs := newDocumentCache(baseDir)
+	report := &TraceReport{}
+
+	var order []string
+	traces := make(map[string]*DocumentTrace)
+	addDocument := func(file string) (*DocumentTrace, error) {
+		key := resolve(baseDir, file)
+		if trace, ok := traces[key]; ok {
+			return trace, nil
+		}
+		doc, err := docs.load(file)
+		if err != nil {
+			return nil, err
+		}
+		trace := &DocumentTrace{Path: displayPath(baseDir, key)}
+		for _, section := range doc.Sections {
+			trace.Sections = append(trace.Sections, SectionTrace{Section: section})
+		}
+		traces[key] = trace
+		order = append(order, key)
+		return trace, nil
+	}
+
+	for _, file := range extraDocs {
+		if _, err := addDocument(file); err != nil {
+			return nil, err
+		}
+	}
+
+	tasksByPhase := make(map[string][]string)
+	for _, task := range e.Tasks {
+		tasksByPhase[task.PhaseID] = append(tasksByPhase[task.PhaseID], task.ID)
+	}
+
+	for _, ref := range Refs(e) {
+		if problem := docs.check(ref); problem != "" {
+			report.Problems = append(report.Problems, RefProblem{Ref: ref, Problem: problem})
+			continue
+		}
+
+		file, anchor := ParseRef(ref.Ref)
+		trace, err := addDocument(file)
+		if err != nil {
+			return nil, err
+		}
+		if anchor == "" {
+			continue
+		}
+
+		for i := range trace.Sections {
+			section := &trace.Sections[i]
+			if section.Anchor != anchor {
+				continue
+			}
+			if ref.EntityType == "phase" {
+				section.Phases = append(section.Phases, ref.EntityID)
+				section.Covered = section.Covered || len(tasksByPhase[ref.EntityID]) > 0
+			} else {
+				section.Tasks = append(section.Tasks, ref.EntityID)
+				section.Covered = true
+			}
+		}
+	}
+
+	for _, key := range order {
+		trace := traces[key]
+		propagateCoverage(trace.Sections)
+		for _, section := range trace.Sections {
+			if !section.Covered {
+				trace.Uncovered++
+			}
+		}
+		report.Documents = append(report.Documents, *trace)
+	}
+
+	return report, nil
+}
+
+// propagateCoverage marks a section covered when one of its subsections is covered
+func propagateCoverage(sections []SectionTrace) {
+	for i := len(sections) - 1; i >= 0; i-- {
+		if !sections[i].Covered {
+			continue
+		}
+		level := sections[i].Level
+		for j := i - 1; j >= 0 && level > 1; j-- {
+			if sections[j].Level < level {
+				sections[j].Covered = true
+				level = sections[j].Level
+			}
+		}
+	}
+}
+
+func displayPath(baseDir, path string) string {
+	if rel, err := filepath.Rel(baseDir, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// documentCache loads each spec document once
+type documentCache struct {
+	baseDir string
+	docs    map[string]*Document
+	errs    map[string]error
+}
+
+func newDocumentCache(baseDir string) *documentCache {
+	return &documentCache{baseDir: baseDir, docs: make(map[string]*Document), errs: make(map[string]error)}
+}
+
+func (c *documentCache) load(file string) (*Document, error) {
+	path := resolve(c.baseDir, file)
+	if err, ok := c.errs[path]; ok {
+		return nil, err
+	}
+	if doc, ok := c.docs[path]; ok {
+		return doc, nil
+	}
+
+	doc, err := LoadDocument(path)
+	if err != nil {
+		c.errs[path] = err
+		return nil, err
+	}
+	c.docs[path] = doc
+	return doc, nil
+}
+
+// check returns a description of what is wrong with a reference, or "" if it is valid
+func (c *documentCache) check(ref Ref) string {
+	file, anchor := ParseRef(ref.Ref)
+	if file == "" {
+		return "missing spec file (expected file#anchor)"
+	}
+
+	doc, err := c.load(file)
+	if err != nil {
+		return err.Error()
+	}
+	if anchor != "" && doc.Section(anchor) == nil {
+		return fmt.Sprintf("section #%s not found in %s", anchor, file)
+	}
+	return ""
+}
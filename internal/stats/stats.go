@@ -0,0 +1,233 @@
+// Package stats aggregates the statistics of an epic - status counts, cycle
+// times and a health score - into one document for dashboards.
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/forecast"
+	"github.com/mindreframer/agentpm/internal/query"
+	"github.com/mindreframer/agentpm/internal/storage"
+)
+
+// Health grades
+const (
+	GradeHealthy   = "healthy"
+	GradeAtRisk    = "at_risk"
+	GradeUnhealthy = "unhealthy"
+)
+
+// Health score deductions: each finding costs its points, up to the cap of its kind
+const (
+	failingTestPoints   = 10
+	failingTestCap      = 40
+	overduePoints       = 10
+	overdueCap          = 30
+	invalidEpicPoints   = 30
+	healthyThreshold    = 80
+	unhealthyBelowScore = 50
+)
+
+// Stats is the aggregated statistics document of an epic
+type Stats struct {
+	EpicID      string         `json:"epic_id"`
+	Name        string         `json:"name"`
+	Status      string         `json:"status"`
+	GeneratedAt time.Time      `json:"generated_at"`
+	Phases      map[string]int `json:"phases"` // Count per status
+	Tasks       map[string]int `json:"tasks"`
+	Tests       map[string]int `json:"tests"` // Count per status, plus "failing"
+	Completion  int            `json:"completion_percent"`
+	CycleTimes  CycleTimes     `json:"cycle_times"`
+	Health      Health         `json:"health"`
+}
+
+// CycleTimes summarises how long completed tasks took, in minutes
+type CycleTimes struct {
+	Samples int `json:"samples"`
+	Mean    int `json:"mean_minutes"`
+	Median  int `json:"median_minutes"`
+	P80     int `json:"p80_minutes"`
+}
+
+// Health is a 0-100 score with the findings that lowered it
+type Health struct {
+	Score    int      `json:"score"`
+	Grade    string   `json:"grade"`
+	Findings []string `json:"findings"`
+}
+
+// Build aggregates the statistics of an epic. Deadlines before now count as overdue.
+func Build(e *epic.Epic, now time.Time) *Stats {
+	stats := &Stats{
+		EpicID:      e.ID,
+		Name:        e.Name,
+		Status:      string(e.Status),
+		GeneratedAt: now.UTC(),
+		Phases:      make(map[string]int),
+		Tasks:       make(map[string]int),
+		Tests:       make(map[string]int),
+		Completion:  completion(e),
+		CycleTimes:  summarise(forecast.CycleTimes(e)),
+	}
+
+	for _, phase := range e.Phases {
+		stats.Phases[string(phase.Status)]++
+	}
+	for _, task := range e.Tasks {
+		stats.Tasks[string(task.Status)]++
+	}
+	failing := 0
+	for _, test := range e.Tests {
+		stats.Tests[string(test.GetTestStatusUnified())]++
+		if test.GetTestResult() == epic.TestResultFailing {
+			failing++
+		}
+	}
+	stats.Tests["failing"] = failing
+
+	stats.Health = health(e, failing, now)
+	return stats
+}
+
+// completion is the completion percentage shown by 'agentpm status'
+func completion(e *epic.Epic) int {
+	memoryStorage := storage.NewMemoryStorage()
+	memoryStorage.StoreEpic(e.ID, e)
+	queryService := query.NewQueryService(memoryStorage)
+	if err := queryService.LoadEpic(e.ID); err != nil {
+		return 0
+	}
+	status, err := queryService.GetEpicStatus()
+	if err != nil {
+		return 0
+	}
+	return status.CompletionPercentage
+}
+
+func summarise(durations []time.Duration) CycleTimes {
+	if len(durations) == 0 {
+		return CycleTimes{}
+	}
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	return CycleTimes{
+		Samples: len(sorted),
+		Mean:    int((total / time.Duration(len(sorted))).Minutes()),
+		Median:  int(sorted[(len(sorted)-1)/2].Minutes()),
+		P80:     int(sorted[(len(sorted)*80+99)/100-1].Minutes()),
+	}
+}
+
+// health starts at 100 and deducts points for failing tests, overdue open
+// phases and tasks, and validation errors
+func health(e *epic.Epic, failing int, now time.Time) Health {
+	h := Health{Score: 100, Findings: []string{}}
+	deduct := func(points, limit int, finding string) {
+		h.Score -= min(points, limit)
+		h.Findings = append(h.Findings, finding)
+	}
+
+	if failing > 0 {
+		deduct(failing*failingTestPoints, failingTestCap, fmt.Sprintf("%d failing test(s)", failing))
+	}
+
+	overdue := 0
+	isOverdue := func(due string, status epic.Status) bool {
+		if due == "" || status == epic.StatusCompleted || status == epic.StatusCancelled {
+			return false
+		}
+		at, err := epic.DueAt(due)
+		return err == nil && now.After(at)
+	}
+	for _, phase := range e.Phases {
+		if isOverdue(phase.Due, phase.Status) {
+			overdue++
+		}
+	}
+	for _, task := range e.Tasks {
+		if isOverdue(task.Due, task.Status) {
+			overdue++
+		}
+	}
+	if overdue > 0 {
+		deduct(overdue*overduePoints, overdueCap, fmt.Sprintf("%d overdue phase(s) or task(s)", overdue))
+	}
+
+	if result := e.Validate(); !result.Valid {
+		deduct(invalidEpicPoints, invalidEpicPoints, fmt.Sprintf("%d validation error(s)", len(result.Errors)))
+	}
+
+	switch {
+	case h.Score >= healthyThreshold:
+		h.Grade = GradeHealthy
+	case h.Score >= unhealthyBelowScore:
+		h.Grade = GradeAtRisk
+	default:
+		h.Grade = GradeUnhealthy
+	}
+	return h
+}
+
+// Cache holds the statistics document of an epic file as JSON and rebuilds it
+// only when the file's modification time or size changes. It is safe for
+// concurrent use.
+type Cache struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	epicID  string
+	data    []byte
+}
+
+// NewCache returns a cache for the statistics of an epic file
+func NewCache(path string) *Cache {
+	return &Cache{path: path}
+}
+
+// JSON returns the statistics document, rebuilding it if the file changed
+func (sc *Cache) JSON(now time.Time) ([]byte, error) {
+	_, data, err := sc.Document(now)
+	return data, err
+}
+
+// Document returns the ID of the epic with its statistics document, rebuilding
+// both if the file changed
+func (sc *Cache) Document(now time.Time) (string, []byte, error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	info, err := os.Stat(sc.path)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read epic file: %w", err)
+	}
+	if sc.data != nil && info.ModTime().Equal(sc.modTime) && info.Size() == sc.size {
+		return sc.epicID, sc.data, nil
+	}
+
+	// The cache only reads, so it takes no lock that would hold up the commands
+	// changing the epic
+	epicData, err := storage.NewReadOnlyFileStorage().LoadEpic(sc.path)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load epic: %w", err)
+	}
+	data, err := json.MarshalIndent(Build(epicData, now), "", "  ")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal statistics to JSON: %w", err)
+	}
+	sc.epicID, sc.data, sc.modTime, sc.size = epicData.ID, data, info.ModTime(), info.Size()
+	return sc.epicID, sc.data, nil
+}
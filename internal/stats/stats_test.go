@@ -0,0 +1,100 @@
+package stats
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func at(value string) *time.Time {
+	t, _ := time.Parse(time.RFC3339, value)
+	return &t
+}
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2025, 8, 20, 12, 0, 0, 0, time.UTC)
+
+	t.Run("aggregates counts and cycle times", func(t *testing.T) {
+		e := &epic.Epic{
+			ID: "8", Name: "Stats", Status: epic.StatusWIP, CreatedAt: *at("2025-08-01T09:00:00Z"),
+			Phases: []epic.Phase{{ID: "P1", Name: "Build", Status: epic.StatusWIP}},
+			Tasks: []epic.Task{
+				{ID: "T1", PhaseID: "P1", Name: "One", Status: epic.StatusCompleted, StartedAt: at("2025-08-01T10:00:00Z"), CompletedAt: at("2025-08-01T11:00:00Z")},
+				{ID: "T2", PhaseID: "P1", Name: "Two", Status: epic.StatusCompleted, StartedAt: at("2025-08-02T10:00:00Z"), CompletedAt: at("2025-08-02T13:00:00Z")},
+				{ID: "T3", PhaseID: "P1", Name: "Three", Status: epic.StatusCompleted, StartedAt: at("2025-08-03T10:00:00Z"), CompletedAt: at("2025-08-03T12:00:00Z")},
+				{ID: "T4", PhaseID: "P1", Name: "Four", Status: epic.StatusPending},
+			},
+			Tests: []epic.Test{
+				{ID: "X1", TaskID: "T1", PhaseID: "P1", Name: "Passes", TestStatus: epic.TestStatusDone, TestResult: epic.TestResultPassing},
+			},
+		}
+
+		stats := Build(e, now)
+		assert.Equal(t, map[string]int{"wip": 1}, stats.Phases)
+		assert.Equal(t, map[string]int{"completed": 3, "pending": 1}, stats.Tasks)
+		assert.Equal(t, map[string]int{"done": 1, "failing": 0}, stats.Tests)
+		assert.Equal(t, CycleTimes{Samples: 3, Mean: 120, Median: 120, P80: 180}, stats.CycleTimes)
+		assert.Equal(t, Health{Score: 100, Grade: GradeHealthy, Findings: []string{}}, stats.Health)
+	})
+
+	t.Run("health drops for failing tests and overdue work", func(t *testing.T) {
+		e := &epic.Epic{
+			ID: "8", Name: "Stats", Status: epic.StatusWIP, CreatedAt: *at("2025-08-01T09:00:00Z"),
+			Phases: []epic.Phase{{ID: "P1", Name: "Build", Status: epic.StatusWIP, Due: "2025-08-19"}},
+			Tasks: []epic.Task{
+				{ID: "T1", PhaseID: "P1", Name: "One", Status: epic.StatusWIP, Due: "2025-08-20"},
+				{ID: "T2", PhaseID: "P1", Name: "Two", Status: epic.StatusCompleted, Due: "2025-08-01"},
+			},
+			Tests: []epic.Test{
+				{ID: "X1", TaskID: "T1", PhaseID: "P1", Name: "Fails", TestStatus: epic.TestStatusWIP, TestResult: epic.TestResultFailing},
+				{ID: "X2", TaskID: "T1", PhaseID: "P1", Name: "Fails too", TestStatus: epic.TestStatusWIP, TestResult: epic.TestResultFailing},
+			},
+		}
+
+		health := Build(e, now).Health
+		assert.Equal(t, 70, health.Score, "a deadline on the current day is not overdue yet")
+		assert.Equal(t, GradeAtRisk, health.Grade)
+		assert.Equal(t, []string{"2 failing test(s)", "1 overdue phase(s) or task(s)"}, health.Findings)
+	})
+}
+
+func TestCache(t *testing.T) {
+	epicFile := filepath.Join(t.TempDir(), "epic.xml")
+	write := func(status string) {
+		content := `<epic id="8" name="Cached" status="wip"><phases><phase id="P1" name="Build" status="wip"/></phases>` +
+			`<tasks><task id="T1" phase_id="P1" name="One" status="` + status + `"/></tasks><tests/><events/></epic>`
+		require.NoError(t, os.WriteFile(epicFile, []byte(content), 0644))
+	}
+	tasks := func(data []byte) map[string]int {
+		var document Stats
+		require.NoError(t, json.Unmarshal(data, &document))
+		return document.Tasks
+	}
+
+	write("pending")
+	cache := NewCache(epicFile)
+	first, err := cache.JSON(time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"pending": 1}, tasks(first))
+
+	second, err := cache.JSON(time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, string(first), string(second), "unchanged file is served from the cache")
+
+	write("wip")
+	os.Chtimes(epicFile, time.Now().Add(time.Second), time.Now().Add(time.Second))
+	third, err := cache.JSON(time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"wip": 1}, tasks(third))
+
+	epicID, data, err := cache.Document(time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "8", epicID)
+	assert.Equal(t, string(third), string(data))
+}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CopyToArchive copies the file at path into the archive directory dir under
+// name, creating the directory. An archived file is never overwritten.
+func CopyToArchive(path, dir, name string) (string, error) {
+	source, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer source.Close()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	target := filepath.Join(dir, name)
+	archived, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if os.IsExist(err) {
+		return "", fmt.Errorf("%s is already archived as %s", path, target)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", target, err)
+	}
+	if _, err := io.Copy(archived, source); err != nil {
+		archived.Close()
+		os.Remove(target)
+		return "", fmt.Errorf("failed to archive %s: %w", path, err)
+	}
+	if err := archived.Close(); err != nil {
+		os.Remove(target)
+		return "", fmt.Errorf("failed to archive %s: %w", path, err)
+	}
+	return target, nil
+}
+
+// SnapshotName returns the name a copy of the epic file at path taken at t is
+// archived under, e.g. epic-8.20250816T120000Z.xml
+func SnapshotName(path string, t time.Time) string {
+	base := filepath.Base(path)
+	ext := filepath.Ext(base)
+	return fmt.Sprintf("%s.%s%s", strings.TrimSuffix(base, ext), t.UTC().Format("20060102T150405Z"), ext)
+}
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyToArchive(t *testing.T) {
+	dir := t.TempDir()
+	epicFile := filepath.Join(dir, "epic-8.xml")
+	archiveDir := filepath.Join(dir, ".agentpm", "archive")
+	require.NoError(t, os.WriteFile(epicFile, []byte("<epic id=\"8\"/>"), 0644))
+
+	target, err := CopyToArchive(epicFile, archiveDir, "epic-8.xml")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(archiveDir, "epic-8.xml"), target)
+	content, err := os.ReadFile(target)
+	require.NoError(t, err)
+	assert.Equal(t, "<epic id=\"8\"/>", string(content))
+	assert.FileExists(t, epicFile, "the original stays")
+
+	require.NoError(t, os.WriteFile(epicFile, []byte("<epic id=\"8\" name=\"changed\"/>"), 0644))
+	_, err = CopyToArchive(epicFile, archiveDir, "epic-8.xml")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already archived")
+	content, err = os.ReadFile(target)
+	require.NoError(t, err)
+	assert.Equal(t, "<epic id=\"8\"/>", string(content), "an archived file is never overwritten")
+}
+
+func TestSnapshotName(t *testing.T) {
+	at := time.Date(2025, 8, 16, 12, 0, 0, 0, time.FixedZone("CEST", 2*3600))
+	assert.Equal(t, "epic-8.20250816T100000Z.xml", SnapshotName("epics/epic-8.xml", at))
+	assert.Equal(t, "epic.20250816T100000Z", SnapshotName("epic", at))
+}
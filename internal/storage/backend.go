@@ -0,0 +1,217 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/customfields"
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// Storage backends, see the storage_backend config key
+const (
+	BackendFile   = "file"   // Every query loads the epic files it reads
+	BackendSQLite = "sqlite" // Queries read an index of the epic files kept in a SQLite database
+)
+
+// Backend stores the epics of a workspace and answers questions across many
+// of them at once. The epic files stay the source of truth: backends only
+// differ in how they find the events and entities of a set of epics.
+type Backend interface {
+	Storage
+
+	// Events returns the events of the epics at paths that pass filter, most
+	// recent first
+	Events(paths []string, filter EventFilter) ([]EpicEvent, error)
+	// Entities returns the entities of one type (epic, phase, task or test)
+	// of the epics at paths, epic by epic in the order of the epic file
+	Entities(paths []string, entityType string) ([]EntityRecord, error)
+	// Close releases the resources of the backend
+	Close() error
+}
+
+// OpenBackend opens the backend of a name; the SQLite backend keeps its index
+// in the database file at dbPath
+func OpenBackend(name, dbPath string) (Backend, error) {
+	switch name {
+	case "", BackendFile:
+		return NewFileBackend(), nil
+	case BackendSQLite:
+		return OpenSQLiteBackend(dbPath)
+	}
+	return nil, fmt.Errorf("unknown storage backend %q (expected %s or %s)", name, BackendFile, BackendSQLite)
+}
+
+// EventFilter selects events by type, time and the phase or task they concern
+type EventFilter struct {
+	Types   []string  // Event types, or entity prefixes like "task" for all task_* events
+	Since   time.Time // Only events at or after this time
+	PhaseID string    // Only events concerning this phase, its tasks or their tests
+	TaskID  string    // Only events concerning this task or its tests
+	Limit   int       // Maximum number of events; 0 or less for all
+
+	CorrelationID string // Only events recorded by the command invocation with this correlation ID
+}
+
+// MatchesType reports whether an event type is one of the filter's types or
+// starts with one of its entity prefixes
+func (f EventFilter) MatchesType(eventType string) bool {
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, t := range f.Types {
+		if eventType == t || strings.HasPrefix(eventType, t+"_") {
+			return true
+		}
+	}
+	return false
+}
+
+// EpicEvent is an event of one epic of a workspace
+type EpicEvent struct {
+	Path    string // Epic file the event was read from
+	EpicID  string
+	PhaseID string // Phase the event concerns, see epic.EventScope
+	TaskID  string // Task the event concerns
+	Event   epic.Event
+}
+
+// EntityRecord is a phase, task, test or the epic itself with the fields
+// selectors compare
+type EntityRecord struct {
+	Path   string // Epic file the entity was read from
+	EpicID string
+	Type   string // epic, phase, task or test
+	ID     string
+	Name   string
+	Status string
+	Parent string            // The epic of a phase, the phase of a task, the task (or phase) of a test
+	Fields map[string]string // id, name, status, assignee, phase, task, result and custom.<name> by type
+}
+
+// EpicEvents returns the events of an epic with the phase and task they
+// concern, in the order of the epic
+func EpicEvents(e *epic.Epic) []EpicEvent {
+	events := make([]EpicEvent, 0, len(e.Events))
+	for _, event := range e.Events {
+		phaseID, taskID := e.EventScope(event)
+		events = append(events, EpicEvent{EpicID: e.ID, PhaseID: phaseID, TaskID: taskID, Event: event})
+	}
+	return events
+}
+
+// EntityRecords returns the entities of one type of an epic in the order of
+// the epic. Assignees are the effective ones and tests belong to the phase
+// of their task.
+func EntityRecords(e *epic.Epic, entityType string) []EntityRecord {
+	var records []EntityRecord
+	add := func(id, name, status, parent string, fields map[string]string, custom epic.CustomFields) {
+		for _, field := range custom {
+			fields[customfields.Prefix+field.Name] = field.Value
+		}
+		records = append(records, EntityRecord{EpicID: e.ID, Type: entityType, ID: id, Name: name, Status: status, Parent: parent, Fields: fields})
+	}
+
+	switch entityType {
+	case "epic":
+		add(e.ID, e.Name, string(e.Status), "", map[string]string{"id": e.ID, "name": e.Name, "status": string(e.Status), "assignee": e.Assignee}, e.Custom)
+	case "phase":
+		for _, phase := range e.Phases {
+			add(phase.ID, phase.Name, string(phase.Status), e.ID, map[string]string{"id": phase.ID, "name": phase.Name, "status": string(phase.Status), "assignee": e.PhaseAssignee(phase.ID)}, phase.Custom)
+		}
+	case "task":
+		for i := range e.Tasks {
+			task := &e.Tasks[i]
+			add(task.ID, task.Name, string(task.Status), task.PhaseID, map[string]string{"id": task.ID, "name": task.Name, "status": string(task.Status), "assignee": e.TaskAssignee(task), "phase": task.PhaseID}, task.Custom)
+		}
+	case "test":
+		taskPhases := make(map[string]string)
+		for _, task := range e.Tasks {
+			taskPhases[task.ID] = task.PhaseID
+		}
+		for i := range e.Tests {
+			test := &e.Tests[i]
+			phaseID := test.PhaseID
+			if phaseID == "" {
+				phaseID = taskPhases[test.TaskID]
+			}
+			parent := test.TaskID
+			if parent == "" {
+				parent = test.PhaseID
+			}
+			status := string(test.GetTestStatusUnified())
+			add(test.ID, test.Name, status, parent, map[string]string{
+				"id": test.ID, "name": test.Name, "status": status,
+				"assignee": e.TestAssignee(test), "phase": phaseID, "task": test.TaskID,
+				"result": string(test.GetTestResult()),
+			}, test.Custom)
+		}
+	}
+	return records
+}
+
+// FileBackend answers every query by loading the epic files it reads
+type FileBackend struct {
+	*FileStorage
+}
+
+func NewFileBackend() *FileBackend {
+	return &FileBackend{FileStorage: NewFileStorage()}
+}
+
+func (b *FileBackend) Events(paths []string, filter EventFilter) ([]EpicEvent, error) {
+	var events []EpicEvent
+	for _, path := range paths {
+		e, err := b.LoadEpic(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", path, err)
+		}
+		for _, event := range EpicEvents(e) {
+			if filter.matches(event) {
+				event.Path = path
+				events = append(events, event)
+			}
+		}
+	}
+	return sortEvents(events, filter.Limit), nil
+}
+
+func (b *FileBackend) Entities(paths []string, entityType string) ([]EntityRecord, error) {
+	var records []EntityRecord
+	for _, path := range paths {
+		e, err := b.LoadEpic(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", path, err)
+		}
+		for _, record := range EntityRecords(e, entityType) {
+			record.Path = path
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func (b *FileBackend) Close() error {
+	return nil
+}
+
+func (f EventFilter) matches(event EpicEvent) bool {
+	return f.MatchesType(event.Event.Type) &&
+		!event.Event.Timestamp.Before(f.Since) &&
+		(f.PhaseID == "" || event.PhaseID == f.PhaseID) &&
+		(f.TaskID == "" || event.TaskID == f.TaskID) &&
+		(f.CorrelationID == "" || event.Event.CorrelationID == f.CorrelationID)
+}
+
+// sortEvents orders events most recent first and keeps the first limit of them
+func sortEvents(events []EpicEvent, limit int) []EpicEvent {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Event.Timestamp.After(events[j].Event.Timestamp)
+	})
+	if limit > 0 && len(events) > limit {
+		events = events[:limit]
+	}
+	return events
+}
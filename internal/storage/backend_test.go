@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func backendEpicXML(id, taskStatus, eventTime string) string {
+	return `<epic id="` + id + `" name="Epic ` + id + `" status="wip" created_at="2025-08-01T09:00:00Z">
+    <phases>
+        <phase id="1A" name="Build" status="wip"/>
+    </phases>
+    <tasks>
+        <task id="1A_1" phase_id="1A" name="Login" status="` + taskStatus + `"/>
+        <task id="1A_2" phase_id="1A" name="Logout" status="pending"/>
+    </tasks>
+    <tests>
+        <test id="T1" task_id="1A_1" name="Login works" status="wip"/>
+    </tests>
+    <events>
+        <event type="task_started" timestamp="` + eventTime + `">Task 1A_1 (Login) started</event>
+        <event type="test_failed" timestamp="` + eventTime + `">Test T1 (Login works) failed</event>
+    </events>
+</epic>`
+}
+
+func TestBackends(t *testing.T) {
+	for _, name := range []string{BackendFile, BackendSQLite} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			first := filepath.Join(dir, "epic-1.xml")
+			second := filepath.Join(dir, "epic-2.xml")
+			require.NoError(t, os.WriteFile(first, []byte(backendEpicXML("1", "wip", "2025-08-10T10:00:00Z")), 0644))
+			require.NoError(t, os.WriteFile(second, []byte(backendEpicXML("2", "done", "2025-08-12T10:00:00Z")), 0644))
+			paths := []string{first, second}
+
+			backend, err := OpenBackend(name, filepath.Join(dir, ".agentpm", "index.db"))
+			require.NoError(t, err)
+			defer backend.Close()
+
+			events, err := backend.Events(paths, EventFilter{Types: []string{"task"}})
+			require.NoError(t, err)
+			require.Len(t, events, 2)
+			assert.Equal(t, "2", events[0].EpicID, "most recent first")
+			assert.Equal(t, second, events[0].Path)
+			assert.Equal(t, "1A", events[0].PhaseID)
+			assert.Equal(t, "1A_1", events[0].TaskID)
+
+			events, err = backend.Events(paths, EventFilter{TaskID: "1A_1", Since: time.Date(2025, 8, 11, 0, 0, 0, 0, time.UTC)})
+			require.NoError(t, err)
+			require.Len(t, events, 2, "test events belong to the task of the test")
+			assert.Equal(t, "test_failed", events[1].Event.Type)
+
+			events, err = backend.Events(paths, EventFilter{Limit: 1})
+			require.NoError(t, err)
+			assert.Len(t, events, 1)
+
+			records, err := backend.Entities(paths, "task")
+			require.NoError(t, err)
+			require.Len(t, records, 4)
+			assert.Equal(t, []string{"1A_1", "1A_2", "1A_1", "1A_2"}, []string{records[0].ID, records[1].ID, records[2].ID, records[3].ID})
+			assert.Equal(t, "done", records[2].Fields["status"])
+			assert.Equal(t, "1A", records[2].Parent)
+			assert.Equal(t, first, records[0].Path)
+
+			tests, err := backend.Entities(paths, "test")
+			require.NoError(t, err)
+			require.Len(t, tests, 2)
+			assert.Equal(t, "1A", tests[0].Fields["phase"])
+			assert.Equal(t, "1A_1", tests[0].Parent)
+		})
+	}
+}
+
+func TestSQLiteBackendReindexesChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	epicFile := filepath.Join(dir, "epic-1.xml")
+	dbPath := filepath.Join(dir, "index.db")
+	require.NoError(t, os.WriteFile(epicFile, []byte(backendEpicXML("1", "wip", "2025-08-10T10:00:00Z")), 0644))
+
+	backend, err := OpenSQLiteBackend(dbPath)
+	require.NoError(t, err)
+	records, err := backend.Entities([]string{epicFile}, "task")
+	require.NoError(t, err)
+	assert.Equal(t, "wip", records[0].Status)
+	require.NoError(t, backend.Close())
+
+	require.NoError(t, os.WriteFile(epicFile, []byte(backendEpicXML("1", "done", "2025-08-10T10:00:00Z")), 0644))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(epicFile, later, later))
+
+	backend, err = OpenSQLiteBackend(dbPath)
+	require.NoError(t, err)
+	defer backend.Close()
+	records, err = backend.Entities([]string{epicFile}, "task")
+	require.NoError(t, err)
+	assert.Equal(t, "done", records[0].Status, "the index follows the epic file")
+
+	_, err = backend.Entities([]string{filepath.Join(dir, "missing.xml")}, "task")
+	assert.Error(t, err)
+}
+
+func TestOpenBackendRejectsUnknownNames(t *testing.T) {
+	_, err := OpenBackend("postgres", "")
+	assert.ErrorContains(t, err, "unknown storage backend")
+}
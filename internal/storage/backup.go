@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// backupRetention is how many previous versions of an epic file saves keep, see SetBackupRetention
+var backupRetention int
+
+// SetBackupRetention makes every save of an epic file first keep its previous
+// version as <epic>.bak.1, shifting older backups up to <epic>.bak.<count>.
+// Backups beyond count are removed. 0 keeps no backups.
+func SetBackupRetention(count int) {
+	backupRetention = max(count, 0)
+}
+
+// BackupRetention returns how many previous versions saves keep, 0 for none
+func BackupRetention() int {
+	return backupRetention
+}
+
+// Backup is a previous version of an epic file
+type Backup struct {
+	N       int       `json:"n"` // 1 is the most recent
+	Path    string    `json:"path"`
+	ModTime time.Time `json:"saved_at"`
+	Size    int64     `json:"size"`
+}
+
+// BackupPath returns the path of the n-th most recent backup of an epic file
+func BackupPath(epicPath string, n int) string {
+	return fmt.Sprintf("%s.bak.%d", epicPath, n)
+}
+
+// ListBackups returns the backups of an epic file, most recent first
+func ListBackups(epicPath string) ([]Backup, error) {
+	absPath, err := filepath.Abs(epicPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve epic file path: %w", err)
+	}
+	entries, err := os.ReadDir(filepath.Dir(absPath))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	backups := []Backup{}
+	prefix := filepath.Base(absPath) + ".bak."
+	for _, entry := range entries {
+		suffix, found := strings.CutPrefix(entry.Name(), prefix)
+		if !found {
+			continue
+		}
+		n, err := strconv.Atoi(suffix)
+		if err != nil || n < 1 {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Backup{N: n, Path: BackupPath(absPath, n), ModTime: info.ModTime(), Size: info.Size()})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].N < backups[j].N })
+	return backups, nil
+}
+
+// RestoreBackup replaces an epic file with its n-th most recent backup. The
+// replaced version becomes a backup itself when backups are kept, so a restore
+// can be rolled back as well.
+func (fs *FileStorage) RestoreBackup(epicPath string, n int) error {
+	absPath, err := filepath.Abs(epicPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve epic file path: %w", err)
+	}
+	if fs.readOnly {
+		return errReadOnly(absPath)
+	}
+	if err := acquireLock(absPath); err != nil {
+		return err
+	}
+	defer releaseLock(absPath)
+
+	backupPath := BackupPath(absPath, n)
+	content, err := os.ReadFile(backupPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no backup %d of %s (see 'agentpm restore --list')", n, epicPath)
+		}
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+	restored, err := DecodeEpic(content, FormatOf(absPath))
+	if err != nil {
+		return fmt.Errorf("backup %d is not a valid epic: %w", n, err)
+	}
+	if dryRun != nil {
+		stored, _ := fs.loadEpicFile(absPath)
+		dryRun(absPath, stored, restored)
+		return nil
+	}
+
+	before, _ := os.ReadFile(absPath)
+	if err := writeEpicFile(absPath, func(w io.Writer) error {
+		_, err := w.Write(content)
+		return err
+	}); err != nil {
+		return err
+	}
+	if saveHook != nil {
+		saveHook(absPath, before, content)
+	}
+	return nil
+}
+
+// writeEpicFile writes an epic file atomically: the content goes to a temporary
+// file in the same directory, which replaces the epic file by a rename once it
+// is complete, after the current version was kept as backup
+func writeEpicFile(absPath string, write func(w io.Writer) error) error {
+	dir := filepath.Dir(absPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create epic directory: %w", err)
+	}
+
+	temp, err := os.CreateTemp(dir, "."+filepath.Base(absPath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write epic file: %w", err)
+	}
+	tempFile := temp.Name()
+	err = write(temp)
+	if err == nil {
+		err = temp.Sync()
+	}
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tempFile, 0644)
+	}
+	if err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to write epic file: %w", err)
+	}
+
+	if err := rotateBackups(absPath); err != nil {
+		os.Remove(tempFile)
+		return err
+	}
+	if err := os.Rename(tempFile, absPath); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to move epic file: %w", err)
+	}
+	return nil
+}
+
+// rotateBackups keeps the current version of an epic file as backup 1,
+// shifting the older ones and dropping those beyond the retention
+func rotateBackups(absPath string) error {
+	if backupRetention == 0 {
+		return nil
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return nil
+	}
+
+	backups, err := ListBackups(absPath)
+	if err != nil {
+		return err
+	}
+	for i := len(backups) - 1; i >= 0; i-- {
+		backup := backups[i]
+		if backup.N >= backupRetention {
+			os.Remove(backup.Path)
+			continue
+		}
+		if err := os.Rename(backup.Path, BackupPath(absPath, backup.N+1)); err != nil {
+			return fmt.Errorf("failed to rotate backups: %w", err)
+		}
+	}
+
+	// A copy rather than a hard link, which editors writing in place would change too
+	content, err := os.ReadFile(absPath)
+	if err != nil {
+		return fmt.Errorf("failed to back up epic file: %w", err)
+	}
+	if err := os.WriteFile(BackupPath(absPath, 1), content, 0644); err != nil {
+		return fmt.Errorf("failed to back up epic file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEpicFileBackups(t *testing.T) {
+	SetBackupRetention(2)
+	t.Cleanup(func() { SetBackupRetention(0) })
+
+	dir := t.TempDir()
+	epicFile := filepath.Join(dir, "epic.xml")
+	fs := NewFileStorage()
+	save := func(name string) {
+		require.NoError(t, fs.SaveEpic(&epic.Epic{ID: "backup-epic", Name: name, Status: epic.StatusWIP}, epicFile))
+	}
+	nameOf := func(path string) string {
+		epicData, err := fs.loadEpicFile(path)
+		require.NoError(t, err)
+		return epicData.Name
+	}
+
+	t.Run("saves keep the configured number of versions", func(t *testing.T) {
+		save("v1")
+		backups, err := ListBackups(epicFile)
+		require.NoError(t, err)
+		assert.Empty(t, backups, "a new file has no previous version")
+
+		save("v2")
+		save("v3")
+		save("v4")
+		backups, err = ListBackups(epicFile)
+		require.NoError(t, err)
+		require.Len(t, backups, 2)
+		assert.Equal(t, 1, backups[0].N)
+		assert.Equal(t, "v3", nameOf(backups[0].Path))
+		assert.Equal(t, "v2", nameOf(backups[1].Path))
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 3, "no temporary files are left behind")
+	})
+
+	t.Run("restore makes the replaced version a backup", func(t *testing.T) {
+		require.NoError(t, fs.RestoreBackup(epicFile, 2))
+		assert.Equal(t, "v2", nameOf(epicFile))
+		assert.Equal(t, "v4", nameOf(BackupPath(epicFile, 1)))
+		assert.Equal(t, "v3", nameOf(BackupPath(epicFile, 2)))
+	})
+
+	t.Run("restore of a missing or invalid backup", func(t *testing.T) {
+		assert.ErrorContains(t, fs.RestoreBackup(epicFile, 3), "no backup 3 of")
+
+		require.NoError(t, os.WriteFile(BackupPath(epicFile, 2), []byte("<broken"), 0644))
+		assert.ErrorContains(t, fs.RestoreBackup(epicFile, 2), "backup 2 is not a valid epic")
+		assert.Equal(t, "v2", nameOf(epicFile))
+	})
+
+	t.Run("lower retention drops older backups", func(t *testing.T) {
+		SetBackupRetention(1)
+		save("v5")
+		backups, err := ListBackups(epicFile)
+		require.NoError(t, err)
+		require.Len(t, backups, 1)
+		assert.Equal(t, "v2", nameOf(backups[0].Path))
+	})
+}
@@ -0,0 +1,285 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"gopkg.in/yaml.v3"
+)
+
+// EncodeEpic writes an epic in the given storage format
+func EncodeEpic(epicData *epic.Epic, format Format) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(toDocument(epicData), "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode epic: %w", err)
+		}
+		return append(data, '\n'), nil
+	case FormatYAML:
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(toDocument(epicData)); err != nil {
+			return nil, fmt.Errorf("failed to encode epic: %w", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, fmt.Errorf("failed to encode epic: %w", err)
+		}
+		return buf.Bytes(), nil
+	default:
+		return encodeXML(epicData)
+	}
+}
+
+// DecodeEpic parses an epic in the given storage format. JSON and YAML epics
+// are decoded strictly: unknown keys are errors rather than silently dropped.
+func DecodeEpic(data []byte, format Format) (*epic.Epic, error) {
+	var doc epicDocument
+	switch format {
+	case FormatJSON:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("invalid epic file: %w", err)
+		}
+	case FormatYAML:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("invalid epic file: %w", err)
+		}
+	default:
+		return decodeXML(data)
+	}
+	return doc.toEpic(), nil
+}
+
+// The document types spell out the JSON and YAML layout of an epic. Their
+// entities convert to and from the epic package's types, so that a field
+// added there does not compile until it is added here as well.
+
+type epicDocument struct {
+	ID            string                `json:"id" yaml:"id"`
+	Name          string                `json:"name" yaml:"name"`
+	Status        epic.Status           `json:"status" yaml:"status"`
+	CreatedAt     time.Time             `json:"created_at" yaml:"created_at"`
+	StatusModel   string                `json:"status_model,omitempty" yaml:"status_model,omitempty"`
+	SchemaVersion int                   `json:"schema_version,omitempty" yaml:"schema_version,omitempty"`
+	Due           string                `json:"due,omitempty" yaml:"due,omitempty"`
+	Assignee      string                `json:"assignee,omitempty" yaml:"assignee,omitempty"`
+	Description   string                `json:"description,omitempty" yaml:"description,omitempty"`
+	Workflow      string                `json:"workflow,omitempty" yaml:"workflow,omitempty"`
+	Requirements  string                `json:"requirements,omitempty" yaml:"requirements,omitempty"`
+	Dependencies  string                `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+	Custom        epic.CustomFields     `json:"custom,omitempty" yaml:"custom,omitempty"`
+	Notes         []epic.Note           `json:"notes,omitempty" yaml:"notes,omitempty"`
+	Metadata      *metadataDocument     `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	CurrentState  *currentStateDocument `json:"current_state,omitempty" yaml:"current_state,omitempty"`
+	Phases        []phaseDocument       `json:"phases,omitempty" yaml:"phases,omitempty"`
+	Milestones    []milestoneDocument   `json:"milestones,omitempty" yaml:"milestones,omitempty"`
+	Suppressions  []suppressionDocument `json:"suppressions,omitempty" yaml:"suppressions,omitempty"`
+	Blockers      []blockerDocument     `json:"blockers,omitempty" yaml:"blockers,omitempty"`
+	Tasks         []taskDocument        `json:"tasks,omitempty" yaml:"tasks,omitempty"`
+	Tests         []testDocument        `json:"tests,omitempty" yaml:"tests,omitempty"`
+	Events        []eventDocument       `json:"events,omitempty" yaml:"events,omitempty"`
+}
+
+type metadataDocument struct {
+	Created         time.Time `json:"created" yaml:"created"`
+	Assignee        string    `json:"assignee,omitempty" yaml:"assignee,omitempty"`
+	EstimatedEffort string    `json:"estimated_effort,omitempty" yaml:"estimated_effort,omitempty"`
+}
+
+type currentStateDocument struct {
+	ActivePhase string `json:"active_phase,omitempty" yaml:"active_phase,omitempty"`
+	ActiveTask  string `json:"active_task,omitempty" yaml:"active_task,omitempty"`
+	NextAction  string `json:"next_action,omitempty" yaml:"next_action,omitempty"`
+}
+
+type phaseDocument struct {
+	ID           string            `json:"id" yaml:"id"`
+	Name         string            `json:"name" yaml:"name"`
+	Description  string            `json:"description,omitempty" yaml:"description,omitempty"`
+	Deliverables string            `json:"deliverables,omitempty" yaml:"deliverables,omitempty"`
+	SpecRef      string            `json:"spec_ref,omitempty" yaml:"spec_ref,omitempty"`
+	Due          string            `json:"due,omitempty" yaml:"due,omitempty"`
+	DependsOn    string            `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+	Status       epic.Status       `json:"status" yaml:"status"`
+	Assignee     string            `json:"assignee,omitempty" yaml:"assignee,omitempty"`
+	StartedAt    *time.Time        `json:"started_at,omitempty" yaml:"started_at,omitempty"`
+	CompletedAt  *time.Time        `json:"completed_at,omitempty" yaml:"completed_at,omitempty"`
+	FrozenAt     *time.Time        `json:"frozen_at,omitempty" yaml:"frozen_at,omitempty"`
+	FrozenReason string            `json:"frozen_reason,omitempty" yaml:"frozen_reason,omitempty"`
+	Custom       epic.CustomFields `json:"custom,omitempty" yaml:"custom,omitempty"`
+	Notes        []epic.Note       `json:"notes,omitempty" yaml:"notes,omitempty"`
+}
+
+type milestoneDocument struct {
+	ID          string `json:"id" yaml:"id"`
+	Name        string `json:"name" yaml:"name"`
+	TargetDate  string `json:"target_date,omitempty" yaml:"target_date,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+type suppressionDocument struct {
+	Rule   string `json:"rule,omitempty" yaml:"rule,omitempty"`
+	Entity string `json:"entity,omitempty" yaml:"entity,omitempty"`
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+type blockerDocument struct {
+	ID          string     `json:"id" yaml:"id"`
+	Type        string     `json:"type,omitempty" yaml:"type,omitempty"`
+	Entity      string     `json:"entity,omitempty" yaml:"entity,omitempty"`
+	RaisedAt    time.Time  `json:"raised_at" yaml:"raised_at"`
+	RaisedBy    string     `json:"raised_by,omitempty" yaml:"raised_by,omitempty"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty" yaml:"resolved_at,omitempty"`
+	Description string     `json:"description,omitempty" yaml:"description,omitempty"`
+	Resolution  string     `json:"resolution,omitempty" yaml:"resolution,omitempty"`
+}
+
+type taskDocument struct {
+	ID                 string            `json:"id" yaml:"id"`
+	PhaseID            string            `json:"phase_id,omitempty" yaml:"phase_id,omitempty"`
+	Name               string            `json:"name" yaml:"name"`
+	Description        string            `json:"description,omitempty" yaml:"description,omitempty"`
+	AcceptanceCriteria string            `json:"acceptance_criteria,omitempty" yaml:"acceptance_criteria,omitempty"`
+	SpecRef            string            `json:"spec_ref,omitempty" yaml:"spec_ref,omitempty"`
+	Due                string            `json:"due,omitempty" yaml:"due,omitempty"`
+	DependsOn          string            `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+	ParentTaskID       string            `json:"parent_task_id,omitempty" yaml:"parent_task_id,omitempty"`
+	Recurring          string            `json:"recurring,omitempty" yaml:"recurring,omitempty"`
+	Estimate           string            `json:"estimate,omitempty" yaml:"estimate,omitempty"`
+	Status             epic.Status       `json:"status" yaml:"status"`
+	Assignee           string            `json:"assignee,omitempty" yaml:"assignee,omitempty"`
+	StartedAt          *time.Time        `json:"started_at,omitempty" yaml:"started_at,omitempty"`
+	CompletedAt        *time.Time        `json:"completed_at,omitempty" yaml:"completed_at,omitempty"`
+	CancelledAt        *time.Time        `json:"cancelled_at,omitempty" yaml:"cancelled_at,omitempty"`
+	Custom             epic.CustomFields `json:"custom,omitempty" yaml:"custom,omitempty"`
+	Notes              []epic.Note       `json:"notes,omitempty" yaml:"notes,omitempty"`
+}
+
+type testDocument struct {
+	ID                 string               `json:"id" yaml:"id"`
+	TaskID             string               `json:"task_id,omitempty" yaml:"task_id,omitempty"`
+	PhaseID            string               `json:"phase_id,omitempty" yaml:"phase_id,omitempty"`
+	Name               string               `json:"name" yaml:"name"`
+	Description        string               `json:"description,omitempty" yaml:"description,omitempty"`
+	Status             epic.Status          `json:"status" yaml:"status"`
+	TestStatus         epic.TestStatus      `json:"test_status,omitempty" yaml:"test_status,omitempty"`
+	TestResult         epic.TestResult      `json:"result,omitempty" yaml:"result,omitempty"`
+	Requires           string               `json:"requires,omitempty" yaml:"requires,omitempty"`
+	Tags               string               `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Type               string               `json:"type,omitempty" yaml:"type,omitempty"`
+	VerifiedBy         string               `json:"verified_by,omitempty" yaml:"verified_by,omitempty"`
+	Evidence           string               `json:"evidence,omitempty" yaml:"evidence,omitempty"`
+	FailCount          int                  `json:"fail_count,omitempty" yaml:"fail_count,omitempty"`
+	PassCount          int                  `json:"pass_count,omitempty" yaml:"pass_count,omitempty"`
+	StartedAt          *time.Time           `json:"started_at,omitempty" yaml:"started_at,omitempty"`
+	PassedAt           *time.Time           `json:"passed_at,omitempty" yaml:"passed_at,omitempty"`
+	FailedAt           *time.Time           `json:"failed_at,omitempty" yaml:"failed_at,omitempty"`
+	CancelledAt        *time.Time           `json:"cancelled_at,omitempty" yaml:"cancelled_at,omitempty"`
+	FailureNote        string               `json:"failure_note,omitempty" yaml:"failure_note,omitempty"`
+	FailureContext     *epic.FailureContext `json:"failure_context,omitempty" yaml:"failure_context,omitempty"`
+	CancellationReason string               `json:"cancellation_reason,omitempty" yaml:"cancellation_reason,omitempty"`
+	Custom             epic.CustomFields    `json:"custom,omitempty" yaml:"custom,omitempty"`
+	Notes              []epic.Note          `json:"notes,omitempty" yaml:"notes,omitempty"`
+}
+
+type eventDocument struct {
+	ID        string    `json:"id" yaml:"id"`
+	Type      string    `json:"type,omitempty" yaml:"type,omitempty"`
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+	Actor     string    `json:"actor,omitempty" yaml:"actor,omitempty"`
+	Commit    string    `json:"commit,omitempty" yaml:"commit,omitempty"`
+	Branch    string    `json:"branch,omitempty" yaml:"branch,omitempty"`
+
+	CorrelationID string `json:"correlation_id,omitempty" yaml:"correlation_id,omitempty"`
+	Data          string `json:"data,omitempty" yaml:"data,omitempty"`
+}
+
+func toDocument(e *epic.Epic) *epicDocument {
+	doc := &epicDocument{
+		ID:            e.ID,
+		Name:          e.Name,
+		Status:        e.Status,
+		CreatedAt:     e.CreatedAt,
+		StatusModel:   e.StatusModel,
+		SchemaVersion: e.SchemaVersion,
+		Due:           e.Due,
+		Assignee:      e.Assignee,
+		Description:   e.Description,
+		Workflow:      e.Workflow,
+		Requirements:  e.Requirements,
+		Dependencies:  e.Dependencies,
+		Custom:        e.Custom,
+		Notes:         e.Notes,
+		Phases:        convertAll(e.Phases, func(p epic.Phase) phaseDocument { return phaseDocument(p) }),
+		Milestones:    convertAll(e.Milestones, func(m epic.Milestone) milestoneDocument { return milestoneDocument(m) }),
+		Suppressions:  convertAll(e.Suppressions, func(s epic.Suppression) suppressionDocument { return suppressionDocument(s) }),
+		Blockers:      convertAll(e.Blockers, func(b epic.Blocker) blockerDocument { return blockerDocument(b) }),
+		Tasks:         convertAll(e.Tasks, func(t epic.Task) taskDocument { return taskDocument(t) }),
+		Tests:         convertAll(e.Tests, func(t epic.Test) testDocument { return testDocument(t) }),
+		Events:        convertAll(e.Events, func(ev epic.Event) eventDocument { return eventDocument(ev) }),
+	}
+	if e.Metadata != nil {
+		metadata := metadataDocument(*e.Metadata)
+		doc.Metadata = &metadata
+	}
+	if e.CurrentState != nil {
+		state := currentStateDocument(*e.CurrentState)
+		doc.CurrentState = &state
+	}
+	return doc
+}
+
+func (doc *epicDocument) toEpic() *epic.Epic {
+	e := &epic.Epic{
+		ID:            doc.ID,
+		Name:          doc.Name,
+		Status:        doc.Status,
+		CreatedAt:     doc.CreatedAt,
+		StatusModel:   doc.StatusModel,
+		SchemaVersion: doc.SchemaVersion,
+		Due:           doc.Due,
+		Assignee:      doc.Assignee,
+		Description:   doc.Description,
+		Workflow:      doc.Workflow,
+		Requirements:  doc.Requirements,
+		Dependencies:  doc.Dependencies,
+		Custom:        doc.Custom,
+		Notes:         doc.Notes,
+		Phases:        convertAll(doc.Phases, func(p phaseDocument) epic.Phase { return epic.Phase(p) }),
+		Milestones:    convertAll(doc.Milestones, func(m milestoneDocument) epic.Milestone { return epic.Milestone(m) }),
+		Suppressions:  convertAll(doc.Suppressions, func(s suppressionDocument) epic.Suppression { return epic.Suppression(s) }),
+		Blockers:      convertAll(doc.Blockers, func(b blockerDocument) epic.Blocker { return epic.Blocker(b) }),
+		Tasks:         convertAll(doc.Tasks, func(t taskDocument) epic.Task { return epic.Task(t) }),
+		Tests:         convertAll(doc.Tests, func(t testDocument) epic.Test { return epic.Test(t) }),
+		Events:        convertAll(doc.Events, func(ev eventDocument) epic.Event { return epic.Event(ev) }),
+	}
+	if doc.Metadata != nil {
+		metadata := epic.EpicMetadata(*doc.Metadata)
+		e.Metadata = &metadata
+	}
+	if doc.CurrentState != nil {
+		state := epic.CurrentState(*doc.CurrentState)
+		e.CurrentState = &state
+	}
+	return e
+}
+
+// convertAll converts each item, keeping nil slices nil
+func convertAll[From, To any](items []From, convert func(From) To) []To {
+	if items == nil {
+		return nil
+	}
+	converted := make([]To, len(items))
+	for i, item := range items {
+		converted[i] = convert(item)
+	}
+	return converted
+}
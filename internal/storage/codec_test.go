@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func codecTestEpic() *epic.Epic {
+	at := func(value string) *time.Time {
+		t, _ := time.Parse(time.RFC3339Nano, value)
+		return &t
+	}
+	return &epic.Epic{
+		ID:           "epic-7",
+		Name:         "Codec Epic",
+		Status:       epic.StatusWIP,
+		CreatedAt:    *at("2025-08-01T09:00:00Z"),
+		StatusModel:  "epic13",
+		Assignee:     "agent_claude",
+		Description:  "Descriptions keep <b>inner markup</b> & entities",
+		Workflow:     "TDD",
+		Requirements: "Round trips",
+		Custom:       epic.CustomFields{{Name: "budget", Value: "1200"}},
+		Notes:        []epic.Note{{Author: "agent_claude", CreatedAt: *at("2025-08-02T09:00:00Z"), Text: "Scope agreed with <ops> & QA"}},
+		Metadata: &epic.EpicMetadata{
+			Created:         *at("2025-08-01T09:00:00Z"),
+			Assignee:        "agent_claude",
+			EstimatedEffort: "2 days",
+		},
+		CurrentState: &epic.CurrentState{ActivePhase: "P1", ActiveTask: "T2", NextAction: "Complete task: Build"},
+		Phases: []epic.Phase{
+			{ID: "P1", Name: "Build", Status: epic.StatusWIP, Due: "2025-09-15", StartedAt: at("2025-08-02T10:00:00.123456789Z"), Custom: epic.CustomFields{{Name: "release", Value: "2025-10-01"}}},
+			{ID: "P2", Name: "Ship", Status: epic.StatusPending, DependsOn: "P1", FrozenAt: at("2025-08-03T10:00:00Z"), FrozenReason: "Release freeze"},
+		},
+		Milestones:   []epic.Milestone{{ID: "M1", Name: "Beta", TargetDate: "2025-09-01", Description: "First users"}},
+		Suppressions: []epic.Suppression{{Rule: "task-needs-test", Entity: "T1", Reason: "Covered manually"}},
+		Blockers: []epic.Blocker{
+			{ID: "B1", Entity: "P1", RaisedAt: *at("2025-08-02T11:00:00Z"), RaisedBy: "agent", Description: "API keys", ResolvedAt: at("2025-08-02T12:00:00Z"), Resolution: "Rotated"},
+		},
+		Tasks: []epic.Task{
+			{ID: "T1", PhaseID: "P1", Name: "Design", Status: epic.StatusCompleted, StartedAt: at("2025-08-02T10:00:00Z"), CompletedAt: at("2025-08-02T11:00:00Z")},
+			{ID: "T2", PhaseID: "P1", Name: "Build", Status: epic.StatusWIP, Recurring: "7d", Assignee: "agent_b", StartedAt: at("2025-08-02T11:30:00Z"),
+				Custom: epic.CustomFields{{Name: "risk", Value: "high"}, {Name: "sprint", Value: "7"}},
+				Notes:  []epic.Note{{Author: "agent_b", CreatedAt: *at("2025-08-02T11:40:00Z"), Text: "Uses the v2 endpoint"}, {CreatedAt: *at("2025-08-02T11:50:00Z"), Text: "v1 is gone"}}},
+			{ID: "T2_1", PhaseID: "P1", Name: "Sub", ParentTaskID: "T2", Status: epic.StatusCancelled, CancelledAt: at("2025-08-02T11:45:00Z")},
+		},
+		Tests: []epic.Test{
+			{ID: "TS1", TaskID: "T1", PhaseID: "P1", Name: "Designs", Description: "Designs are signed off", Status: epic.StatusCompleted, TestStatus: epic.TestStatusDone, TestResult: epic.TestResultPassing, Type: epic.TestTypeManual, VerifiedBy: "alice", PassedAt: at("2025-08-02T11:00:00Z")},
+			{ID: "TS2", TaskID: "T2", PhaseID: "P1", Name: "Builds", Description: "The build is green", Status: epic.StatusWIP, TestStatus: epic.TestStatusWIP, TestResult: epic.TestResultFailing, Tags: "unit", FailCount: 2, PassCount: 1, FailedAt: at("2025-08-02T12:00:00Z"), FailureNote: "Flaky",
+				FailureContext: &epic.FailureContext{Expected: "exit 0", Actual: "exit <1>", Stack: "main.go:12\nmain.go:40", Links: []string{"https://ci.example.com/1", "https://ci.example.com/2"}}},
+			{ID: "TS3", TaskID: "T2", PhaseID: "P1", Name: "Ships", Description: "The release is tagged", Status: epic.StatusPending, Custom: epic.CustomFields{{Name: "owner", Value: "qa"}},
+				Notes: []epic.Note{{Author: "qa", CreatedAt: *at("2025-08-02T12:30:00Z"), Text: "Needs the staging tag"}}},
+		},
+		Events: []epic.Event{
+			{ID: "E1", Type: "task_completed", Timestamp: *at("2025-08-02T11:00:00Z"), Actor: "agent", Commit: "abc123", Branch: "main", Data: "Task T1 completed"},
+		},
+	}
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	fs := NewFileStorage()
+
+	for _, name := range []string{"epic.json", "epic.yaml", "epic.yml"} {
+		t.Run(name, func(t *testing.T) {
+			original := codecTestEpic()
+			epicFile := filepath.Join(t.TempDir(), name)
+			require.NoError(t, fs.SaveEpic(original, epicFile))
+
+			loaded, err := fs.LoadEpic(epicFile)
+			require.NoError(t, err)
+			assert.Equal(t, original, loaded)
+		})
+	}
+
+	t.Run("xml through json and yaml", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, fs.SaveEpic(codecTestEpic(), filepath.Join(dir, "epic.xml")))
+		fromXML, err := fs.LoadEpic(filepath.Join(dir, "epic.xml"))
+		require.NoError(t, err)
+		assert.Equal(t, codecTestEpic().Notes, fromXML.Notes)
+		assert.Equal(t, codecTestEpic().Tasks[1].Notes, fromXML.Tasks[1].Notes)
+		assert.Equal(t, codecTestEpic().Tests[2].Notes, fromXML.Tests[2].Notes)
+		assert.Equal(t, codecTestEpic().Tests[1].FailureContext, fromXML.Tests[1].FailureContext)
+
+		for _, format := range []Format{FormatJSON, FormatYAML, FormatXML} {
+			data, err := EncodeEpic(fromXML, format)
+			require.NoError(t, err)
+			decoded, err := DecodeEpic(data, format)
+			require.NoError(t, err)
+			assert.Equal(t, fromXML, decoded, "format %s", format)
+		}
+	})
+}
+
+func TestDecodeEpicRejectsUnknownKeys(t *testing.T) {
+	_, err := DecodeEpic([]byte(`{"id": "1", "name": "Epic", "phasez": []}`), FormatJSON)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid epic file")
+
+	_, err = DecodeEpic([]byte("id: \"1\"\nname: Epic\ntasks:\n  - id: T1\n    owner: bob\n"), FormatYAML)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid epic file")
+}
+
+func TestFormatOf(t *testing.T) {
+	assert.Equal(t, FormatXML, FormatOf("epic.xml"))
+	assert.Equal(t, FormatJSON, FormatOf("epics/epic.JSON"))
+	assert.Equal(t, FormatYAML, FormatOf("epic.yml"))
+	assert.Equal(t, FormatXML, FormatOf("epic"))
+	assert.Equal(t, "epic.yaml", WithFormat("epic.xml", FormatYAML))
+}
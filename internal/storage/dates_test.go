@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDueDatesAndMilestonesRoundTrip(t *testing.T) {
+	tempDir := t.TempDir()
+	epicFile := filepath.Join(tempDir, "test-epic.xml")
+
+	testEpic := epic.NewEpic("epic-1", "Dated Epic")
+	testEpic.Phases = []epic.Phase{
+		{ID: "1", Name: "Indexing", Status: epic.StatusPending, Due: "2025-09-15"},
+		{ID: "2", Name: "Query", Status: epic.StatusPending},
+	}
+	testEpic.Tasks = []epic.Task{
+		{ID: "1_1", PhaseID: "1", Name: "Schema", Status: epic.StatusPending, Due: "2025-09-10T17:00:00Z"},
+	}
+	testEpic.Milestones = []epic.Milestone{
+		{ID: "beta", Name: "Beta", TargetDate: "2025-10-01", Description: "Feature complete"},
+	}
+
+	storage := NewFileStorage()
+	require.NoError(t, storage.SaveEpic(testEpic, epicFile))
+
+	content, err := os.ReadFile(epicFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `due="2025-09-15"`)
+	assert.Equal(t, 2, strings.Count(string(content), "due="), "entities without a deadline should not get the attribute")
+	assert.Contains(t, string(content), `<milestone id="beta" name="Beta" target_date="2025-10-01">`)
+
+	loadedEpic, err := storage.LoadEpic(epicFile)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-09-15", loadedEpic.Phases[0].Due)
+	assert.Empty(t, loadedEpic.Phases[1].Due)
+	assert.Equal(t, "2025-09-10T17:00:00Z", loadedEpic.Tasks[0].Due)
+	assert.Equal(t, testEpic.Milestones, loadedEpic.Milestones)
+}
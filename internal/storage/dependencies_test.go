@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependsOnRoundTrip(t *testing.T) {
+	epicFile := filepath.Join(t.TempDir(), "test-epic.xml")
+
+	testEpic := epic.NewEpic("epic-1", "Dependent Epic")
+	testEpic.Phases = []epic.Phase{
+		{ID: "1", Name: "Schema", Status: epic.StatusPending},
+		{ID: "2", Name: "API", Status: epic.StatusPending, DependsOn: "1"},
+	}
+	testEpic.Tasks = []epic.Task{
+		{ID: "1_1", PhaseID: "1", Name: "Tables", Status: epic.StatusPending},
+		{ID: "1_2", PhaseID: "1", Name: "Indexes", Status: epic.StatusPending, DependsOn: "1_1"},
+	}
+
+	storage := NewFileStorage()
+	require.NoError(t, storage.SaveEpic(testEpic, epicFile))
+
+	content, err := os.ReadFile(epicFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `depends_on="1"`)
+	assert.Contains(t, string(content), `depends_on="1_1"`)
+
+	loadedEpic, err := storage.LoadEpic(epicFile)
+	require.NoError(t, err)
+	assert.Empty(t, loadedEpic.Phases[0].DependsOn)
+	assert.Equal(t, []string{"1"}, loadedEpic.Phases[1].DependencyIDs())
+	assert.Equal(t, []string{"1_1"}, loadedEpic.Tasks[1].DependencyIDs())
+}
@@ -2,8 +2,10 @@ package storage
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,20 +13,75 @@ import (
 	"github.com/mindreframer/agentpm/internal/epic"
 )
 
-type FileStorage struct{}
+type FileStorage struct {
+	readOnly bool
+}
 
 func NewFileStorage() *FileStorage {
 	return &FileStorage{}
 }
 
+// NewReadOnlyFileStorage returns a storage for processes that only read epics,
+// like serve: it loads without locking the file or running the load hooks and
+// checks, and refuses to save
+func NewReadOnlyFileStorage() *FileStorage {
+	return &FileStorage{readOnly: true}
+}
+
+// errReadOnly is returned by the writes of a read-only storage
+func errReadOnly(filePath string) error {
+	return fmt.Errorf("cannot write %s: the epic is opened read-only", filePath)
+}
+
 func (fs *FileStorage) LoadEpic(filePath string) (*epic.Epic, error) {
 	absPath, err := filepath.Abs(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve epic file path: %w", err)
 	}
+	if !fs.readOnly {
+		if err := acquireLock(absPath); err != nil {
+			return nil, err
+		}
+	}
+
+	// Inside a linked git worktree the shared epic is layered with this worktree's overlay
+	var epicData *epic.Epic
+	if wt := worktreeEpicFor(absPath); wt != nil {
+		epicData, err = fs.loadWorktreeEpic(wt)
+	} else {
+		epicData, err = fs.loadEpicFile(absPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if fs.readOnly {
+		return epicData, nil
+	}
+
+	if loadHook != nil {
+		loadHook(absPath, epicData)
+	}
+	if loadCheck != nil {
+		if err := loadCheck(absPath, epicData); err != nil {
+			releaseLock(absPath)
+			return nil, err
+		}
+	}
+	return epicData, nil
+}
+
+func (fs *FileStorage) loadEpicFile(absPath string) (*epic.Epic, error) {
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read epic file: %w", err)
+	}
+	return DecodeEpic(data, FormatOf(absPath))
+}
 
+// decodeXML parses an epic in the XML storage format
+func decodeXML(data []byte) (*epic.Epic, error) {
 	doc := etree.NewDocument()
-	if err := doc.ReadFromFile(absPath); err != nil {
+	if err := doc.ReadFromBytes(data); err != nil {
 		return nil, fmt.Errorf("failed to read epic file: %w", err)
 	}
 
@@ -38,6 +95,15 @@ func (fs *FileStorage) LoadEpic(filePath string) (*epic.Epic, error) {
 	epicData.ID = root.SelectAttrValue("id", "")
 	epicData.Name = root.SelectAttrValue("name", "")
 	epicData.Status = epic.Status(root.SelectAttrValue("status", ""))
+	epicData.StatusModel = root.SelectAttrValue("status_model", "")
+	if version := root.SelectAttrValue("schema_version", ""); version != "" {
+		n, err := strconv.Atoi(version)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid epic file: invalid schema_version %q", version)
+		}
+		epicData.SchemaVersion = n
+	}
+	epicData.Due = root.SelectAttrValue("due", "")
 
 	// Parse created_at timestamp
 	if createdAtStr := root.SelectAttrValue("created_at", ""); createdAtStr != "" {
@@ -66,6 +132,9 @@ func (fs *FileStorage) LoadEpic(filePath string) (*epic.Epic, error) {
 		epicData.Dependencies = getInnerXML(dependenciesElem)
 	}
 
+	epicData.Custom = decodeCustomFields(root)
+	epicData.Notes = decodeNotes(root)
+
 	// Parse metadata section (Epic 7)
 	if metadataElem := root.SelectElement("metadata"); metadataElem != nil {
 		metadata := &epic.EpicMetadata{}
@@ -109,9 +178,13 @@ func (fs *FileStorage) LoadEpic(filePath string) (*epic.Epic, error) {
 	if phasesElem := root.SelectElement("phases"); phasesElem != nil {
 		for _, phaseElem := range phasesElem.SelectElements("phase") {
 			phase := epic.Phase{
-				ID:     phaseElem.SelectAttrValue("id", ""),
-				Name:   phaseElem.SelectAttrValue("name", ""),
-				Status: epic.Status(phaseElem.SelectAttrValue("status", "")),
+				ID:        phaseElem.SelectAttrValue("id", ""),
+				Name:      phaseElem.SelectAttrValue("name", ""),
+				Status:    epic.Status(phaseElem.SelectAttrValue("status", "")),
+				Assignee:  phaseElem.SelectAttrValue("assignee", ""),
+				SpecRef:   phaseElem.SelectAttrValue("spec_ref", ""),
+				Due:       phaseElem.SelectAttrValue("due", ""),
+				DependsOn: phaseElem.SelectAttrValue("depends_on", ""),
 			}
 			if descElem := phaseElem.SelectElement("description"); descElem != nil {
 				phase.Description = getInnerXML(descElem)
@@ -130,18 +203,82 @@ func (fs *FileStorage) LoadEpic(filePath string) (*epic.Epic, error) {
 					phase.CompletedAt = &t
 				}
 			}
+			if frozenElem := phaseElem.SelectElement("frozen_at"); frozenElem != nil {
+				if t, err := time.Parse(time.RFC3339, frozenElem.Text()); err == nil {
+					phase.FrozenAt = &t
+				}
+			}
+			if reasonElem := phaseElem.SelectElement("frozen_reason"); reasonElem != nil {
+				phase.FrozenReason = reasonElem.Text()
+			}
+			phase.Custom = decodeCustomFields(phaseElem)
+			phase.Notes = decodeNotes(phaseElem)
 			epicData.Phases = append(epicData.Phases, phase)
 		}
 	}
 
+	if milestonesElem := root.SelectElement("milestones"); milestonesElem != nil {
+		for _, milestoneElem := range milestonesElem.SelectElements("milestone") {
+			milestone := epic.Milestone{
+				ID:         milestoneElem.SelectAttrValue("id", ""),
+				Name:       milestoneElem.SelectAttrValue("name", ""),
+				TargetDate: milestoneElem.SelectAttrValue("target_date", ""),
+			}
+			if descElem := milestoneElem.SelectElement("description"); descElem != nil {
+				milestone.Description = getInnerXML(descElem)
+			}
+			epicData.Milestones = append(epicData.Milestones, milestone)
+		}
+	}
+
+	if suppressionsElem := root.SelectElement("suppressions"); suppressionsElem != nil {
+		for _, suppressElem := range suppressionsElem.SelectElements("suppress") {
+			epicData.Suppressions = append(epicData.Suppressions, epic.Suppression{
+				Rule:   suppressElem.SelectAttrValue("rule", ""),
+				Entity: suppressElem.SelectAttrValue("entity", ""),
+				Reason: strings.TrimSpace(suppressElem.Text()),
+			})
+		}
+	}
+
+	if blockersElem := root.SelectElement("blockers"); blockersElem != nil {
+		for _, blockerElem := range blockersElem.SelectElements("blocker") {
+			blocker := epic.Blocker{
+				ID:       blockerElem.SelectAttrValue("id", ""),
+				Type:     blockerElem.SelectAttrValue("type", ""),
+				Entity:   blockerElem.SelectAttrValue("entity", ""),
+				RaisedBy: blockerElem.SelectAttrValue("raised_by", ""),
+			}
+			if t, err := time.Parse(time.RFC3339, blockerElem.SelectAttrValue("raised_at", "")); err == nil {
+				blocker.RaisedAt = t
+			}
+			if t, err := time.Parse(time.RFC3339, blockerElem.SelectAttrValue("resolved_at", "")); err == nil {
+				blocker.ResolvedAt = &t
+			}
+			if descElem := blockerElem.SelectElement("description"); descElem != nil {
+				blocker.Description = strings.TrimSpace(descElem.Text())
+			}
+			if resolutionElem := blockerElem.SelectElement("resolution"); resolutionElem != nil {
+				blocker.Resolution = strings.TrimSpace(resolutionElem.Text())
+			}
+			epicData.Blockers = append(epicData.Blockers, blocker)
+		}
+	}
+
 	if tasksElem := root.SelectElement("tasks"); tasksElem != nil {
 		for _, taskElem := range tasksElem.SelectElements("task") {
 			task := epic.Task{
-				ID:       taskElem.SelectAttrValue("id", ""),
-				PhaseID:  taskElem.SelectAttrValue("phase_id", ""),
-				Name:     taskElem.SelectAttrValue("name", ""),
-				Status:   epic.Status(taskElem.SelectAttrValue("status", "")),
-				Assignee: taskElem.SelectAttrValue("assignee", ""),
+				ID:           taskElem.SelectAttrValue("id", ""),
+				PhaseID:      taskElem.SelectAttrValue("phase_id", ""),
+				Name:         taskElem.SelectAttrValue("name", ""),
+				Status:       epic.Status(taskElem.SelectAttrValue("status", "")),
+				Assignee:     taskElem.SelectAttrValue("assignee", ""),
+				SpecRef:      taskElem.SelectAttrValue("spec_ref", ""),
+				Due:          taskElem.SelectAttrValue("due", ""),
+				DependsOn:    taskElem.SelectAttrValue("depends_on", ""),
+				ParentTaskID: taskElem.SelectAttrValue("parent_task_id", ""),
+				Recurring:    taskElem.SelectAttrValue("recurring", ""),
+				Estimate:     taskElem.SelectAttrValue("estimate", ""),
 			}
 			if descElem := taskElem.SelectElement("description"); descElem != nil {
 				task.Description = getInnerXML(descElem)
@@ -165,6 +302,8 @@ func (fs *FileStorage) LoadEpic(filePath string) (*epic.Epic, error) {
 					task.CancelledAt = &t
 				}
 			}
+			task.Custom = decodeCustomFields(taskElem)
+			task.Notes = decodeNotes(taskElem)
 			epicData.Tasks = append(epicData.Tasks, task)
 		}
 	}
@@ -178,7 +317,14 @@ func (fs *FileStorage) LoadEpic(filePath string) (*epic.Epic, error) {
 				Name:       testElem.SelectAttrValue("name", ""),
 				Status:     epic.Status(testElem.SelectAttrValue("status", "")),
 				TestStatus: epic.TestStatus(testElem.SelectAttrValue("test_status", "")),
+				Requires:   testElem.SelectAttrValue("requires", ""),
+				Tags:       testElem.SelectAttrValue("tags", ""),
+				Type:       testElem.SelectAttrValue("type", ""),
+				VerifiedBy: testElem.SelectAttrValue("verified_by", ""),
+				Evidence:   testElem.SelectAttrValue("evidence", ""),
 			}
+			test.FailCount, _ = strconv.Atoi(testElem.SelectAttrValue("fail_count", "0"))
+			test.PassCount, _ = strconv.Atoi(testElem.SelectAttrValue("pass_count", "0"))
 
 			// First try to get content from inner text (direct content within <test>)
 			// This handles the format: <test>content here</test>
@@ -186,7 +332,7 @@ func (fs *FileStorage) LoadEpic(filePath string) (*epic.Epic, error) {
 			if descElem := testElem.SelectElement("description"); descElem != nil {
 				// Use description element format: <test><description>content</description></test>
 				test.Description = getInnerXML(descElem)
-			} else {
+			} else if !hasTestFieldElements(testElem) {
 				// Fall back to inner text format: <test>content here</test>
 				innerText := getInnerXML(testElem)
 				if innerText != "" {
@@ -220,9 +366,12 @@ func (fs *FileStorage) LoadEpic(filePath string) (*epic.Epic, error) {
 			if failureElem := testElem.SelectElement("failure_note"); failureElem != nil {
 				test.FailureNote = getInnerXML(failureElem)
 			}
+			test.FailureContext = decodeFailureContext(testElem)
 			if cancellationElem := testElem.SelectElement("cancellation_reason"); cancellationElem != nil {
 				test.CancellationReason = getInnerXML(cancellationElem)
 			}
+			test.Custom = decodeCustomFields(testElem)
+			test.Notes = decodeNotes(testElem)
 
 			epicData.Tests = append(epicData.Tests, test)
 		}
@@ -232,8 +381,13 @@ func (fs *FileStorage) LoadEpic(filePath string) (*epic.Epic, error) {
 	if eventsElem := root.SelectElement("events"); eventsElem != nil {
 		for _, eventElem := range eventsElem.SelectElements("event") {
 			event := epic.Event{
-				ID:   eventElem.SelectAttrValue("id", ""),
-				Type: eventElem.SelectAttrValue("type", ""),
+				ID:     eventElem.SelectAttrValue("id", ""),
+				Type:   eventElem.SelectAttrValue("type", ""),
+				Actor:  eventElem.SelectAttrValue("actor", ""),
+				Commit: eventElem.SelectAttrValue("commit", ""),
+				Branch: eventElem.SelectAttrValue("branch", ""),
+
+				CorrelationID: eventElem.SelectAttrValue("correlation_id", ""),
 			}
 
 			// Parse timestamp
@@ -263,7 +417,66 @@ func (fs *FileStorage) SaveEpic(epicData *epic.Epic, filePath string) error {
 	if err != nil {
 		return fmt.Errorf("failed to resolve epic file path: %w", err)
 	}
+	if fs.readOnly {
+		return errReadOnly(absPath)
+	}
+	// Saving completes the load-modify-save cycle; an epic saved without being
+	// loaded is locked for the write only
+	if err := acquireLock(absPath); err != nil {
+		return err
+	}
+	defer releaseLock(absPath)
+
+	wt := worktreeEpicFor(absPath)
+	if dryRun != nil || saveCheck != nil {
+		var stored *epic.Epic
+		if wt != nil {
+			stored, _ = fs.loadWorktreeEpic(wt)
+		} else {
+			stored, _ = fs.loadEpicFile(absPath)
+		}
+		if saveCheck != nil {
+			if err := saveCheck(absPath, stored, epicData); err != nil {
+				return err
+			}
+		}
+		if dryRun != nil {
+			dryRun(absPath, stored, epicData)
+			return nil
+		}
+	}
+	if wt != nil {
+		return fs.saveWorktreeEpic(epicData, wt)
+	}
 
+	if saveHook == nil {
+		return fs.saveEpicFile(epicData, absPath)
+	}
+	before, _ := os.ReadFile(absPath)
+	if err := fs.saveEpicFile(epicData, absPath); err != nil {
+		return err
+	}
+	after, err := os.ReadFile(absPath)
+	if err != nil {
+		return fmt.Errorf("failed to read back epic file: %w", err)
+	}
+	saveHook(absPath, before, after)
+	return nil
+}
+
+func (fs *FileStorage) saveEpicFile(epicData *epic.Epic, absPath string) error {
+	data, err := EncodeEpic(epicData, FormatOf(absPath))
+	if err != nil {
+		return err
+	}
+	return writeEpicFile(absPath, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// encodeXML writes an epic in the XML storage format
+func encodeXML(epicData *epic.Epic) ([]byte, error) {
 	doc := etree.NewDocument()
 	doc.CreateProcInst("xml", `version="1.0" encoding="UTF-8"`)
 
@@ -272,6 +485,15 @@ func (fs *FileStorage) SaveEpic(epicData *epic.Epic, filePath string) error {
 	root.CreateAttr("name", epicData.Name)
 	root.CreateAttr("status", string(epicData.Status))
 	root.CreateAttr("created_at", epicData.CreatedAt.Format("2006-01-02T15:04:05Z"))
+	if epicData.StatusModel != "" {
+		root.CreateAttr("status_model", epicData.StatusModel)
+	}
+	if epicData.SchemaVersion > 0 {
+		root.CreateAttr("schema_version", strconv.Itoa(epicData.SchemaVersion))
+	}
+	if epicData.Due != "" {
+		root.CreateAttr("due", epicData.Due)
+	}
 
 	if epicData.Assignee != "" {
 		assigneeElem := root.CreateElement("assignee")
@@ -298,6 +520,9 @@ func (fs *FileStorage) SaveEpic(epicData *epic.Epic, filePath string) error {
 		setInnerXML(dependenciesElem, epicData.Dependencies)
 	}
 
+	encodeCustomFields(root, epicData.Custom)
+	encodeNotes(root, epicData.Notes)
+
 	// Save metadata section (Epic 7)
 	if epicData.Metadata != nil {
 		metadataElem := root.CreateElement("metadata")
@@ -345,6 +570,18 @@ func (fs *FileStorage) SaveEpic(epicData *epic.Epic, filePath string) error {
 			phaseElem.CreateAttr("id", phase.ID)
 			phaseElem.CreateAttr("name", phase.Name)
 			phaseElem.CreateAttr("status", string(phase.Status))
+			if phase.Assignee != "" {
+				phaseElem.CreateAttr("assignee", phase.Assignee)
+			}
+			if phase.SpecRef != "" {
+				phaseElem.CreateAttr("spec_ref", phase.SpecRef)
+			}
+			if phase.Due != "" {
+				phaseElem.CreateAttr("due", phase.Due)
+			}
+			if phase.DependsOn != "" {
+				phaseElem.CreateAttr("depends_on", phase.DependsOn)
+			}
 			if phase.Description != "" {
 				descElem := phaseElem.CreateElement("description")
 				setInnerXML(descElem, phase.Description)
@@ -362,6 +599,66 @@ func (fs *FileStorage) SaveEpic(epicData *epic.Epic, filePath string) error {
 				completedElem := phaseElem.CreateElement("completed_at")
 				completedElem.SetText(phase.CompletedAt.Format(time.RFC3339))
 			}
+			if phase.FrozenAt != nil {
+				frozenElem := phaseElem.CreateElement("frozen_at")
+				frozenElem.SetText(phase.FrozenAt.Format(time.RFC3339))
+				if phase.FrozenReason != "" {
+					phaseElem.CreateElement("frozen_reason").SetText(phase.FrozenReason)
+				}
+			}
+			encodeCustomFields(phaseElem, phase.Custom)
+			encodeNotes(phaseElem, phase.Notes)
+		}
+	}
+
+	if len(epicData.Milestones) > 0 {
+		milestonesElem := root.CreateElement("milestones")
+		for _, milestone := range epicData.Milestones {
+			milestoneElem := milestonesElem.CreateElement("milestone")
+			milestoneElem.CreateAttr("id", milestone.ID)
+			milestoneElem.CreateAttr("name", milestone.Name)
+			milestoneElem.CreateAttr("target_date", milestone.TargetDate)
+			if milestone.Description != "" {
+				descElem := milestoneElem.CreateElement("description")
+				setInnerXML(descElem, milestone.Description)
+			}
+		}
+	}
+
+	if len(epicData.Suppressions) > 0 {
+		suppressionsElem := root.CreateElement("suppressions")
+		for _, suppression := range epicData.Suppressions {
+			suppressElem := suppressionsElem.CreateElement("suppress")
+			suppressElem.CreateAttr("rule", suppression.Rule)
+			if suppression.Entity != "" {
+				suppressElem.CreateAttr("entity", suppression.Entity)
+			}
+			suppressElem.SetText(suppression.Reason)
+		}
+	}
+
+	if len(epicData.Blockers) > 0 {
+		blockersElem := root.CreateElement("blockers")
+		for _, blocker := range epicData.Blockers {
+			blockerElem := blockersElem.CreateElement("blocker")
+			blockerElem.CreateAttr("id", blocker.ID)
+			if blocker.Type != "" {
+				blockerElem.CreateAttr("type", blocker.Type)
+			}
+			if blocker.Entity != "" {
+				blockerElem.CreateAttr("entity", blocker.Entity)
+			}
+			blockerElem.CreateAttr("raised_at", blocker.RaisedAt.Format(time.RFC3339))
+			if blocker.RaisedBy != "" {
+				blockerElem.CreateAttr("raised_by", blocker.RaisedBy)
+			}
+			if blocker.ResolvedAt != nil {
+				blockerElem.CreateAttr("resolved_at", blocker.ResolvedAt.Format(time.RFC3339))
+			}
+			blockerElem.CreateElement("description").SetText(blocker.Description)
+			if blocker.Resolution != "" {
+				blockerElem.CreateElement("resolution").SetText(blocker.Resolution)
+			}
 		}
 	}
 
@@ -376,6 +673,24 @@ func (fs *FileStorage) SaveEpic(epicData *epic.Epic, filePath string) error {
 			if task.Assignee != "" {
 				taskElem.CreateAttr("assignee", task.Assignee)
 			}
+			if task.SpecRef != "" {
+				taskElem.CreateAttr("spec_ref", task.SpecRef)
+			}
+			if task.Due != "" {
+				taskElem.CreateAttr("due", task.Due)
+			}
+			if task.DependsOn != "" {
+				taskElem.CreateAttr("depends_on", task.DependsOn)
+			}
+			if task.ParentTaskID != "" {
+				taskElem.CreateAttr("parent_task_id", task.ParentTaskID)
+			}
+			if task.Recurring != "" {
+				taskElem.CreateAttr("recurring", task.Recurring)
+			}
+			if task.Estimate != "" {
+				taskElem.CreateAttr("estimate", task.Estimate)
+			}
 			if task.Description != "" {
 				descElem := taskElem.CreateElement("description")
 				setInnerXML(descElem, task.Description)
@@ -397,6 +712,8 @@ func (fs *FileStorage) SaveEpic(epicData *epic.Epic, filePath string) error {
 				cancelledElem := taskElem.CreateElement("cancelled_at")
 				cancelledElem.SetText(task.CancelledAt.Format(time.RFC3339))
 			}
+			encodeCustomFields(taskElem, task.Custom)
+			encodeNotes(taskElem, task.Notes)
 		}
 	}
 
@@ -416,10 +733,31 @@ func (fs *FileStorage) SaveEpic(epicData *epic.Epic, filePath string) error {
 			if test.TestStatus != "" {
 				testElem.CreateAttr("test_status", string(test.TestStatus))
 			}
+			if test.Requires != "" {
+				testElem.CreateAttr("requires", test.Requires)
+			}
+			if test.Tags != "" {
+				testElem.CreateAttr("tags", test.Tags)
+			}
+			if test.Type != "" {
+				testElem.CreateAttr("type", test.Type)
+			}
+			if test.VerifiedBy != "" {
+				testElem.CreateAttr("verified_by", test.VerifiedBy)
+			}
+			if test.Evidence != "" {
+				testElem.CreateAttr("evidence", test.Evidence)
+			}
+			if test.FailCount > 0 {
+				testElem.CreateAttr("fail_count", strconv.Itoa(test.FailCount))
+			}
+			if test.PassCount > 0 {
+				testElem.CreateAttr("pass_count", strconv.Itoa(test.PassCount))
+			}
 
 			// Check if test has any additional fields beyond description
 			hasAdditionalFields := test.StartedAt != nil || test.PassedAt != nil || test.FailedAt != nil ||
-				test.CancelledAt != nil || test.FailureNote != "" || test.CancellationReason != ""
+				test.CancelledAt != nil || test.FailureNote != "" || !test.FailureContext.IsEmpty() || test.CancellationReason != "" || len(test.Custom) > 0 || len(test.Notes) > 0
 
 			// If test only has description, save as inner text for simpler XML format
 			// Otherwise, use child elements to avoid conflicts
@@ -453,10 +791,13 @@ func (fs *FileStorage) SaveEpic(epicData *epic.Epic, filePath string) error {
 				failureElem := testElem.CreateElement("failure_note")
 				setInnerXML(failureElem, test.FailureNote)
 			}
+			encodeFailureContext(testElem, test.FailureContext)
 			if test.CancellationReason != "" {
 				cancellationElem := testElem.CreateElement("cancellation_reason")
 				setInnerXML(cancellationElem, test.CancellationReason)
 			}
+			encodeCustomFields(testElem, test.Custom)
+			encodeNotes(testElem, test.Notes)
 		}
 	}
 
@@ -474,6 +815,18 @@ func (fs *FileStorage) SaveEpic(epicData *epic.Epic, filePath string) error {
 			if !event.Timestamp.IsZero() {
 				eventElem.CreateAttr("timestamp", event.Timestamp.Format(time.RFC3339))
 			}
+			if event.Actor != "" {
+				eventElem.CreateAttr("actor", event.Actor)
+			}
+			if event.Commit != "" {
+				eventElem.CreateAttr("commit", event.Commit)
+			}
+			if event.Branch != "" {
+				eventElem.CreateAttr("branch", event.Branch)
+			}
+			if event.CorrelationID != "" {
+				eventElem.CreateAttr("correlation_id", event.CorrelationID)
+			}
 
 			// Store event data as text content
 			if event.Data != "" {
@@ -485,25 +838,14 @@ func (fs *FileStorage) SaveEpic(epicData *epic.Epic, filePath string) error {
 		root.CreateElement("events")
 	}
 
-	dir := filepath.Dir(absPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create epic directory: %w", err)
-	}
-
 	// Format XML with proper indentation for better readability and git diffs
 	doc.Indent(4)
 
-	tempFile := absPath + ".tmp"
-	if err := doc.WriteToFile(tempFile); err != nil {
-		return fmt.Errorf("failed to write epic file: %w", err)
-	}
-
-	if err := os.Rename(tempFile, absPath); err != nil {
-		os.Remove(tempFile)
-		return fmt.Errorf("failed to move epic file: %w", err)
+	data, err := doc.WriteToBytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode epic: %w", err)
 	}
-
-	return nil
+	return data, nil
 }
 
 func (fs *FileStorage) EpicExists(filePath string) bool {
@@ -516,6 +858,128 @@ func (fs *FileStorage) EpicExists(filePath string) bool {
 	return err == nil
 }
 
+// decodeCustomFields reads the <custom> values of an epic, phase, task or test
+func decodeCustomFields(elem *etree.Element) epic.CustomFields {
+	customElem := elem.SelectElement("custom")
+	if customElem == nil {
+		return nil
+	}
+	var fields epic.CustomFields
+	for _, fieldElem := range customElem.SelectElements("field") {
+		fields = append(fields, epic.CustomField{
+			Name:  fieldElem.SelectAttrValue("name", ""),
+			Value: fieldElem.Text(),
+		})
+	}
+	return fields
+}
+
+// encodeCustomFields writes the custom field values as <custom><field name="..">value</field></custom>
+func encodeCustomFields(elem *etree.Element, fields epic.CustomFields) {
+	if len(fields) == 0 {
+		return
+	}
+	customElem := elem.CreateElement("custom")
+	for _, field := range fields {
+		fieldElem := customElem.CreateElement("field")
+		fieldElem.CreateAttr("name", field.Name)
+		fieldElem.SetText(field.Value)
+	}
+}
+
+// decodeFailureContext reads the <failure_context> of a test
+func decodeFailureContext(testElem *etree.Element) *epic.FailureContext {
+	contextElem := testElem.SelectElement("failure_context")
+	if contextElem == nil {
+		return nil
+	}
+	context := &epic.FailureContext{}
+	if elem := contextElem.SelectElement("expected"); elem != nil {
+		context.Expected = elem.Text()
+	}
+	if elem := contextElem.SelectElement("actual"); elem != nil {
+		context.Actual = elem.Text()
+	}
+	if elem := contextElem.SelectElement("stack"); elem != nil {
+		context.Stack = elem.Text()
+	}
+	for _, linkElem := range contextElem.SelectElements("link") {
+		context.Links = append(context.Links, linkElem.Text())
+	}
+	return context
+}
+
+// encodeFailureContext writes the failure context of a test as
+// <failure_context><expected/><actual/><stack/><link/></failure_context>
+func encodeFailureContext(testElem *etree.Element, context *epic.FailureContext) {
+	if context.IsEmpty() {
+		return
+	}
+	contextElem := testElem.CreateElement("failure_context")
+	if context.Expected != "" {
+		contextElem.CreateElement("expected").SetText(context.Expected)
+	}
+	if context.Actual != "" {
+		contextElem.CreateElement("actual").SetText(context.Actual)
+	}
+	if context.Stack != "" {
+		contextElem.CreateElement("stack").SetText(context.Stack)
+	}
+	for _, link := range context.Links {
+		contextElem.CreateElement("link").SetText(link)
+	}
+}
+
+// testFieldElements are the child elements of a test holding its fields rather than its description
+var testFieldElements = []string{"started_at", "passed_at", "failed_at", "cancelled_at", "failure_note", "failure_context", "cancellation_reason", "custom", "notes"}
+
+// hasTestFieldElements reports whether a test is stored with field elements,
+// so that its inner content is not a description
+func hasTestFieldElements(testElem *etree.Element) bool {
+	for _, name := range testFieldElements {
+		if testElem.SelectElement(name) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeNotes reads the notes of an element from <notes><note author=".." created_at="..">text</note></notes>
+func decodeNotes(elem *etree.Element) []epic.Note {
+	notesElem := elem.SelectElement("notes")
+	if notesElem == nil {
+		return nil
+	}
+	var notes []epic.Note
+	for _, noteElem := range notesElem.SelectElements("note") {
+		note := epic.Note{
+			Author: noteElem.SelectAttrValue("author", ""),
+			Text:   noteElem.Text(),
+		}
+		if t, err := time.Parse(time.RFC3339, noteElem.SelectAttrValue("created_at", "")); err == nil {
+			note.CreatedAt = t
+		}
+		notes = append(notes, note)
+	}
+	return notes
+}
+
+// encodeNotes writes the notes as <notes><note author=".." created_at="..">text</note></notes>
+func encodeNotes(elem *etree.Element, notes []epic.Note) {
+	if len(notes) == 0 {
+		return
+	}
+	notesElem := elem.CreateElement("notes")
+	for _, note := range notes {
+		noteElem := notesElem.CreateElement("note")
+		if note.Author != "" {
+			noteElem.CreateAttr("author", note.Author)
+		}
+		noteElem.CreateAttr("created_at", note.CreatedAt.Format(time.RFC3339))
+		noteElem.SetText(note.Text)
+	}
+}
+
 // getInnerXML returns the inner XML content of an element, preserving any inner XML markup
 func getInnerXML(elem *etree.Element) string {
 	if elem == nil {
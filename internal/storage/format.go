@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Format is an encoding epic files are stored in, told apart by file extension
+type Format string
+
+const (
+	FormatXML  Format = "xml"
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+// Formats returns the formats FileStorage can read and write
+func Formats() []Format {
+	return []Format{FormatXML, FormatJSON, FormatYAML}
+}
+
+// ParseFormat returns the storage format with the given name
+func ParseFormat(name string) (Format, error) {
+	if strings.EqualFold(name, "yml") {
+		return FormatYAML, nil
+	}
+	for _, format := range Formats() {
+		if strings.EqualFold(name, string(format)) {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported epic format %q (supported: %s)", name, FormatNames())
+}
+
+// FormatOf returns the format of the epic file at path by its extension.
+// Files without a known extension are XML, the original epic format.
+func FormatOf(path string) Format {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	if format, err := ParseFormat(ext); err == nil {
+		return format
+	}
+	return FormatXML
+}
+
+// Extension returns the file extension of the format, including the dot
+func (f Format) Extension() string {
+	return "." + string(f)
+}
+
+// WithFormat returns path with its extension replaced by the one of format
+func WithFormat(path string, format Format) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + format.Extension()
+}
+
+// FormatNames lists the supported formats for messages and help texts
+func FormatNames() string {
+	names := make([]string, 0, len(Formats()))
+	for _, format := range Formats() {
+		names = append(names, string(format))
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,60 @@
+package storage
+
+import "github.com/mindreframer/agentpm/internal/epic"
+
+// loadHook is called with every epic loaded from a file by this process, see SetLoadHook
+var loadHook func(path string, epicData *epic.Epic)
+
+// SetLoadHook registers a function called with the absolute path and the contents of
+// every epic FileStorage loads, e.g. to warn about deprecated content. nil removes it.
+func SetLoadHook(hook func(path string, epicData *epic.Epic)) {
+	loadHook = hook
+}
+
+// saveHook is called around every epic file FileStorage writes, see SetSaveHook
+var saveHook func(path string, before, after []byte)
+
+// SetSaveHook registers a function called after FileStorage wrote an epic file,
+// with its absolute path and its contents before and after the write, e.g. to
+// journal changes. Epics of git worktrees, which save to an overlay, are not
+// reported. nil removes it.
+func SetSaveHook(hook func(path string, before, after []byte)) {
+	saveHook = hook
+}
+
+// loadCheck may reject an epic loaded by this process, see SetLoadCheck
+var loadCheck func(path string, epicData *epic.Epic) error
+
+// SetLoadCheck registers a function called with the absolute path and the contents
+// of every epic FileStorage loads, after the load hook. An error it returns fails
+// the load, e.g. to keep commands from changing an epic that is already
+// inconsistent. nil removes it.
+func SetLoadCheck(check func(path string, epicData *epic.Epic) error) {
+	loadCheck = check
+}
+
+// saveCheck may reject an epic this process is about to save, see SetSaveCheck
+var saveCheck func(path string, before, after *epic.Epic) error
+
+// SetSaveCheck registers a function called before FileStorage saves an epic, with
+// its absolute path, the epic as stored (nil if it cannot be read) and the epic
+// to save. An error it returns fails the save, e.g. to keep commands from
+// changing a completed epic. nil removes it.
+func SetSaveCheck(check func(path string, before, after *epic.Epic) error) {
+	saveCheck = check
+}
+
+// dryRun receives the epics FileStorage would save while a dry run is active, see SetDryRun
+var dryRun func(path string, before, after *epic.Epic)
+
+// SetDryRun starts a dry run: FileStorage hands every epic it would save to the
+// function instead of writing it, with its absolute path, the epic as stored
+// (nil if it cannot be read) and the epic to save. nil ends the dry run.
+func SetDryRun(record func(path string, before, after *epic.Epic)) {
+	dryRun = record
+}
+
+// DryRun reports whether a dry run is active, so that nothing is written
+func DryRun() bool {
+	return dryRun != nil
+}
@@ -343,6 +343,29 @@ func TestInnerXMLContentPreservation(t *testing.T) {
 		assert.Equal(t, "Some failure note", loadedTest.FailureNote)
 		assert.NotNil(t, loadedTest.StartedAt)
 	})
+	t.Run("test with notes and no description keeps an empty description", func(t *testing.T) {
+		epicFile := t.TempDir() + "/test-epic.xml"
+		noted := time.Date(2025, 8, 16, 9, 0, 0, 0, time.UTC)
+		testEpic := &epic.Epic{
+			ID:        "test-epic",
+			Name:      "Test Epic",
+			Status:    epic.StatusWIP,
+			CreatedAt: noted,
+			Tests: []epic.Test{
+				{ID: "test-1", TaskID: "task-1", Name: "Noted Test", Status: epic.StatusPending,
+					Notes: []epic.Note{{Author: "agent_a", CreatedAt: noted, Text: "Needs <fixtures>"}}},
+			},
+		}
+
+		fs := NewFileStorage()
+		require.NoError(t, fs.SaveEpic(testEpic, epicFile))
+		loadedEpic, err := fs.LoadEpic(epicFile)
+		require.NoError(t, err)
+		require.Len(t, loadedEpic.Tests, 1)
+
+		assert.Empty(t, loadedEpic.Tests[0].Description)
+		assert.Equal(t, testEpic.Tests[0].Notes, loadedEpic.Tests[0].Notes)
+	})
 }
 
 func TestPhaseDeliverables(t *testing.T) {
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// LockTimeout is how long FileStorage waits for an epic file locked by another process
+var LockTimeout = 5 * time.Second
+
+// lockRetryInterval is how often a held lock is tried again while waiting
+const lockRetryInterval = 50 * time.Millisecond
+
+// errLockBusy is returned by tryLockFile when another process holds the lock
+var errLockBusy = errors.New("lock busy")
+
+// LockedError is returned when an epic file stays locked by another process
+type LockedError struct {
+	Path   string
+	Waited time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("epic file %s is locked by another agentpm process (waited %s); retry when it is done, or pass --no-lock to skip locking", e.Path, e.Waited)
+}
+
+var (
+	lockingEnabled bool
+	locksMu        sync.Mutex
+	locks          = make(map[string]*os.File) // Held locks by absolute epic path
+)
+
+// SetLocking turns advisory locking of epic files on or off. With locking,
+// LoadEpic locks the epic file exclusively until SaveEpic wrote it back (or
+// ReleaseLocks is called), so that the load-modify-save cycles of concurrent
+// processes cannot interleave. Locks are released when the process exits.
+func SetLocking(enabled bool) {
+	lockingEnabled = enabled
+	if !enabled {
+		ReleaseLocks()
+	}
+}
+
+// ReleaseLocks releases all epic file locks held by this process, e.g. before
+// a long-running command waits for the next change
+func ReleaseLocks() {
+	locksMu.Lock()
+	defer locksMu.Unlock()
+	for path, file := range locks {
+		file.Close()
+		delete(locks, path)
+	}
+}
+
+// acquireLock locks the epic file at absPath for this process, waiting up to
+// LockTimeout for other processes. It is a no-op without locking, when the lock
+// is already held, or when the file does not exist yet.
+func acquireLock(absPath string) error {
+	if !lockingEnabled {
+		return nil
+	}
+	locksMu.Lock()
+	defer locksMu.Unlock()
+	if _, held := locks[absPath]; held {
+		return nil
+	}
+
+	start := time.Now()
+	for {
+		file, err := tryLockFile(absPath)
+		switch {
+		case err == nil:
+			if file != nil {
+				locks[absPath] = file
+			}
+			return nil
+		case os.IsNotExist(err):
+			return nil
+		case !errors.Is(err, errLockBusy):
+			return fmt.Errorf("failed to lock epic file: %w", err)
+		}
+		if waited := time.Since(start); waited >= LockTimeout {
+			return &LockedError{Path: absPath, Waited: waited.Round(time.Millisecond)}
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// releaseLock releases the lock of this process on the epic file at absPath, if held
+func releaseLock(absPath string) {
+	locksMu.Lock()
+	defer locksMu.Unlock()
+	if file, held := locks[absPath]; held {
+		file.Close()
+		delete(locks, absPath)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package storage
+
+import "os"
+
+// tryLockFile does not lock where flock is unavailable
+func tryLockFile(path string) (*os.File, error) {
+	return nil, nil
+}
@@ -0,0 +1,84 @@
+//go:build unix
+
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEpicFileLocking(t *testing.T) {
+	SetLocking(true)
+	t.Cleanup(func() { SetLocking(false) })
+	timeout := LockTimeout
+	LockTimeout = 200 * time.Millisecond
+	t.Cleanup(func() { LockTimeout = timeout })
+
+	epicFile := filepath.Join(t.TempDir(), "epic.xml")
+	fs := NewFileStorage()
+	require.NoError(t, fs.SaveEpic(&epic.Epic{ID: "lock-epic", Name: "Lock Epic", Status: epic.StatusWIP}, epicFile))
+	absPath, err := filepath.Abs(epicFile)
+	require.NoError(t, err)
+
+	t.Run("load holds the lock until the save", func(t *testing.T) {
+		epicData, err := fs.LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Contains(t, locks, absPath)
+
+		epicData.Name = "Renamed"
+		require.NoError(t, fs.SaveEpic(epicData, epicFile))
+		assert.NotContains(t, locks, absPath)
+	})
+
+	t.Run("held by another process", func(t *testing.T) {
+		// A separate open file description conflicts like another process would
+		other, err := tryLockFile(absPath)
+		require.NoError(t, err)
+
+		_, err = fs.LoadEpic(epicFile)
+		var lockedErr *LockedError
+		require.ErrorAs(t, err, &lockedErr)
+		assert.Equal(t, absPath, lockedErr.Path)
+		assert.Contains(t, err.Error(), "--no-lock")
+
+		err = fs.SaveEpic(&epic.Epic{ID: "lock-epic", Name: "Clobbered"}, epicFile)
+		require.ErrorAs(t, err, &lockedErr)
+
+		other.Close()
+		epicData, err := fs.LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, "Renamed", epicData.Name)
+		ReleaseLocks()
+		assert.Empty(t, locks)
+	})
+
+	t.Run("without locking", func(t *testing.T) {
+		other, err := tryLockFile(absPath)
+		require.NoError(t, err)
+		defer other.Close()
+
+		SetLocking(false)
+		defer SetLocking(true)
+		_, err = fs.LoadEpic(epicFile)
+		assert.NoError(t, err)
+	})
+	t.Run("read-only storage", func(t *testing.T) {
+		other, err := tryLockFile(absPath)
+		require.NoError(t, err)
+		defer other.Close()
+
+		readOnly := NewReadOnlyFileStorage()
+		epicData, err := readOnly.LoadEpic(epicFile)
+		require.NoError(t, err)
+		assert.Equal(t, "Renamed", epicData.Name)
+		assert.NotContains(t, locks, absPath)
+
+		assert.ErrorContains(t, readOnly.SaveEpic(epicData, epicFile), "read-only")
+		assert.ErrorContains(t, readOnly.RestoreBackup(epicFile, 1), "read-only")
+	})
+}
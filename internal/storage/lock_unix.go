@@ -0,0 +1,42 @@
+//go:build unix
+
+package storage
+
+import (
+	"os"
+	"syscall"
+)
+
+// tryLockFile takes an exclusive flock on the file at path without blocking.
+// Saves replace the epic file by a rename, so the lock only counts when the
+// locked file is still the one at path; otherwise it is tried again.
+func tryLockFile(path string) (*os.File, error) {
+	for {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+			file.Close()
+			if err == syscall.EWOULDBLOCK {
+				return nil, errLockBusy
+			}
+			return nil, err
+		}
+
+		locked, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		current, err := os.Stat(path)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		if os.SameFile(locked, current) {
+			return file, nil
+		}
+		file.Close()
+	}
+}
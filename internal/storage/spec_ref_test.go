@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpecRefRoundTrip(t *testing.T) {
+	tempDir := t.TempDir()
+	epicFile := filepath.Join(tempDir, "test-epic.xml")
+
+	testEpic := epic.NewEpic("epic-1", "Spec Epic")
+	testEpic.Phases = []epic.Phase{
+		{ID: "1", Name: "Indexing", Status: epic.StatusPending, SpecRef: "docs/spec.md#indexing"},
+		{ID: "2", Name: "Query", Status: epic.StatusPending},
+	}
+	testEpic.Tasks = []epic.Task{
+		{ID: "1_1", PhaseID: "1", Name: "Schema", Status: epic.StatusPending, SpecRef: "docs/spec.md#schema"},
+		{ID: "2_1", PhaseID: "2", Name: "Parser", Status: epic.StatusPending},
+	}
+
+	storage := NewFileStorage()
+	require.NoError(t, storage.SaveEpic(testEpic, epicFile))
+
+	content, err := os.ReadFile(epicFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `spec_ref="docs/spec.md#indexing"`)
+	assert.Equal(t, 2, strings.Count(string(content), "spec_ref="), "entities without a reference should not get the attribute")
+
+	loadedEpic, err := storage.LoadEpic(epicFile)
+	require.NoError(t, err)
+	assert.Equal(t, "docs/spec.md#indexing", loadedEpic.Phases[0].SpecRef)
+	assert.Empty(t, loadedEpic.Phases[1].SpecRef)
+	assert.Equal(t, "docs/spec.md#schema", loadedEpic.Tasks[0].SpecRef)
+	assert.Empty(t, loadedEpic.Tasks[1].SpecRef)
+}
@@ -0,0 +1,295 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchemaVersion is the layout of the index database; an index of
+// another version is dropped and rebuilt from the epic files
+const sqliteSchemaVersion = 2
+
+const sqliteSchema = `
+CREATE TABLE epics (
+	path     TEXT PRIMARY KEY,
+	mod_time INTEGER NOT NULL,
+	size     INTEGER NOT NULL
+);
+CREATE TABLE events (
+	path      TEXT NOT NULL,
+	seq       INTEGER NOT NULL,
+	epic_id   TEXT NOT NULL,
+	type      TEXT NOT NULL,
+	timestamp INTEGER NOT NULL,
+	phase_id  TEXT NOT NULL,
+	task_id   TEXT NOT NULL,
+	correlation_id TEXT NOT NULL,
+	event     TEXT NOT NULL,
+	PRIMARY KEY (path, seq)
+);
+CREATE INDEX events_timestamp ON events (timestamp);
+CREATE TABLE entities (
+	path    TEXT NOT NULL,
+	type    TEXT NOT NULL,
+	seq     INTEGER NOT NULL,
+	epic_id TEXT NOT NULL,
+	id      TEXT NOT NULL,
+	name    TEXT NOT NULL,
+	status  TEXT NOT NULL,
+	parent  TEXT NOT NULL,
+	fields  TEXT NOT NULL,
+	PRIMARY KEY (path, type, seq)
+);
+`
+
+// SQLiteBackend keeps an index of the events and entities of the epic files
+// in a SQLite database. Before a query it re-reads the epic files that
+// changed since they were indexed (by modification time and size), so a query
+// across hundreds of epics parses only the ones that changed and filters
+// events in the database.
+type SQLiteBackend struct {
+	*FileStorage
+	db *sql.DB
+}
+
+// OpenSQLiteBackend opens the index database at dbPath, creating it if needed
+func OpenSQLiteBackend(dbPath string) (*SQLiteBackend, error) {
+	if dbPath == "" {
+		return nil, fmt.Errorf("no database path for the sqlite storage backend")
+	}
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create index directory: %w", err)
+	}
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open index %s: %w", dbPath, err)
+	}
+	// One connection: the index is written and read by this process only
+	db.SetMaxOpenConns(1)
+
+	backend := &SQLiteBackend{FileStorage: NewFileStorage(), db: db}
+	if err := backend.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to prepare index %s: %w", dbPath, err)
+	}
+	return backend, nil
+}
+
+// migrate creates the tables, dropping an index of another schema version
+func (b *SQLiteBackend) migrate() error {
+	if _, err := b.db.Exec("PRAGMA busy_timeout = 5000"); err != nil {
+		return err
+	}
+	var version int
+	if err := b.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+	if version == sqliteSchemaVersion {
+		return nil
+	}
+
+	tx, err := b.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, table := range []string{"epics", "events", "entities"} {
+		if _, err := tx.Exec("DROP TABLE IF EXISTS " + table); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(sqliteSchema); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", sqliteSchemaVersion)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (b *SQLiteBackend) Events(paths []string, filter EventFilter) ([]EpicEvent, error) {
+	keys, err := b.sync(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	var where []string
+	args := []any{keys}
+	if len(filter.Types) > 0 {
+		var types []string
+		for _, t := range filter.Types {
+			types = append(types, "e.type = ? OR substr(e.type, 1, ?) = ?")
+			args = append(args, t, len(t)+1, t+"_")
+		}
+		where = append(where, "("+strings.Join(types, " OR ")+")")
+	}
+	if !filter.Since.IsZero() {
+		where = append(where, "e.timestamp >= ?")
+		args = append(args, filter.Since.UnixNano())
+	}
+	if filter.PhaseID != "" {
+		where = append(where, "e.phase_id = ?")
+		args = append(args, filter.PhaseID)
+	}
+	if filter.TaskID != "" {
+		where = append(where, "e.task_id = ?")
+		args = append(args, filter.TaskID)
+	}
+	if filter.CorrelationID != "" {
+		where = append(where, "e.correlation_id = ?")
+		args = append(args, filter.CorrelationID)
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+	args = append(args, limit)
+
+	// Paths are passed as one JSON array; its keys keep the order of the paths
+	// for events at the same time
+	stmt := `SELECT p.key, e.epic_id, e.phase_id, e.task_id, e.event
+		FROM json_each(?) AS p JOIN events AS e ON e.path = p.value`
+	if len(where) > 0 {
+		stmt += " WHERE " + strings.Join(where, " AND ")
+	}
+	stmt += " ORDER BY e.timestamp DESC, p.key, e.seq LIMIT ?"
+
+	rows, err := b.db.Query(stmt, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []EpicEvent
+	for rows.Next() {
+		var index int
+		var data string
+		var event EpicEvent
+		if err := rows.Scan(&index, &event.EpicID, &event.PhaseID, &event.TaskID, &data); err != nil {
+			return nil, fmt.Errorf("failed to read events: %w", err)
+		}
+		if err := json.Unmarshal([]byte(data), &event.Event); err != nil {
+			return nil, fmt.Errorf("failed to decode indexed event: %w", err)
+		}
+		event.Path = paths[index]
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+func (b *SQLiteBackend) Entities(paths []string, entityType string) ([]EntityRecord, error) {
+	keys, err := b.sync(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := b.db.Query(`SELECT p.key, n.epic_id, n.id, n.name, n.status, n.parent, n.fields
+		FROM json_each(?) AS p JOIN entities AS n ON n.path = p.value
+		WHERE n.type = ? ORDER BY p.key, n.seq`, keys, entityType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query entities: %w", err)
+	}
+	defer rows.Close()
+
+	var records []EntityRecord
+	for rows.Next() {
+		var index int
+		var fields string
+		record := EntityRecord{Type: entityType}
+		if err := rows.Scan(&index, &record.EpicID, &record.ID, &record.Name, &record.Status, &record.Parent, &fields); err != nil {
+			return nil, fmt.Errorf("failed to read entities: %w", err)
+		}
+		if err := json.Unmarshal([]byte(fields), &record.Fields); err != nil {
+			return nil, fmt.Errorf("failed to decode indexed entity: %w", err)
+		}
+		record.Path = paths[index]
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+func (b *SQLiteBackend) Close() error {
+	return b.db.Close()
+}
+
+// sync brings the index of the epic files at paths up to date and returns
+// their absolute paths, the keys of the index, as a JSON array
+func (b *SQLiteBackend) sync(paths []string) (string, error) {
+	keys := make([]string, len(paths))
+	for i, path := range paths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve epic file path: %w", err)
+		}
+		keys[i] = absPath
+		if err := b.syncEpic(path, absPath); err != nil {
+			return "", err
+		}
+	}
+	data, err := json.Marshal(keys)
+	return string(data), err
+}
+
+// syncEpic re-indexes one epic file unless it is unchanged since it was indexed
+func (b *SQLiteBackend) syncEpic(path, absPath string) error {
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", path, err)
+	}
+	var modTime, size int64
+	err = b.db.QueryRow("SELECT mod_time, size FROM epics WHERE path = ?", absPath).Scan(&modTime, &size)
+	if err == nil && modTime == info.ModTime().UnixNano() && size == info.Size() {
+		return nil
+	}
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to read index: %w", err)
+	}
+
+	e, err := b.LoadEpic(path)
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", path, err)
+	}
+
+	tx, err := b.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to update index: %w", err)
+	}
+	defer tx.Rollback()
+	for _, stmt := range []string{"DELETE FROM events WHERE path = ?", "DELETE FROM entities WHERE path = ?"} {
+		if _, err := tx.Exec(stmt, absPath); err != nil {
+			return fmt.Errorf("failed to update index: %w", err)
+		}
+	}
+	for seq, event := range EpicEvents(e) {
+		data, err := json.Marshal(event.Event)
+		if err != nil {
+			return fmt.Errorf("failed to encode event: %w", err)
+		}
+		if _, err := tx.Exec("INSERT INTO events VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			absPath, seq, event.EpicID, event.Event.Type, event.Event.Timestamp.UnixNano(), event.PhaseID, event.TaskID, event.Event.CorrelationID, string(data)); err != nil {
+			return fmt.Errorf("failed to index event: %w", err)
+		}
+	}
+	for _, entityType := range []string{"epic", "phase", "task", "test"} {
+		for seq, record := range EntityRecords(e, entityType) {
+			fields, err := json.Marshal(record.Fields)
+			if err != nil {
+				return fmt.Errorf("failed to encode entity: %w", err)
+			}
+			if _, err := tx.Exec("INSERT INTO entities VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+				absPath, entityType, seq, record.EpicID, record.ID, record.Name, record.Status, record.Parent, string(fields)); err != nil {
+				return fmt.Errorf("failed to index entity: %w", err)
+			}
+		}
+	}
+	if _, err := tx.Exec("INSERT OR REPLACE INTO epics VALUES (?, ?, ?)", absPath, info.ModTime().UnixNano(), info.Size()); err != nil {
+		return fmt.Errorf("failed to update index: %w", err)
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// StdioPath stands for standard input or output in place of an epic file
+const StdioPath = "-"
+
+// ReadEpic decodes an epic in the given format from r. Without a format it is
+// told from the content, as streams have no extension.
+func ReadEpic(r io.Reader, format Format) (*epic.Epic, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read epic: %w", err)
+	}
+	if format == "" {
+		format = DetectFormat(data)
+	}
+	return DecodeEpic(data, format)
+}
+
+// WriteEpic encodes the epic in the given format to w
+func WriteEpic(w io.Writer, epicData *epic.Epic, format Format) error {
+	data, err := EncodeEpic(epicData, format)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write epic: %w", err)
+	}
+	return nil
+}
+
+// DetectFormat tells the format of encoded epic data by its first character:
+// XML starts with '<', JSON with '{', anything else is read as YAML.
+func DetectFormat(data []byte) Format {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(data, []byte("<")):
+		return FormatXML
+	case bytes.HasPrefix(data, []byte("{")):
+		return FormatJSON
+	default:
+		return FormatYAML
+	}
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadWriteEpic(t *testing.T) {
+	fs := NewFileStorage()
+	epicFile := filepath.Join(t.TempDir(), "epic.xml")
+	require.NoError(t, fs.SaveEpic(codecTestEpic(), epicFile))
+	original, err := fs.LoadEpic(epicFile)
+	require.NoError(t, err)
+
+	for _, format := range Formats() {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, WriteEpic(&buf, original, format))
+			assert.Equal(t, format, DetectFormat(buf.Bytes()))
+
+			read, err := ReadEpic(bytes.NewReader(buf.Bytes()), format)
+			require.NoError(t, err)
+			assert.Equal(t, original, read)
+
+			detected, err := ReadEpic(bytes.NewReader(buf.Bytes()), "")
+			require.NoError(t, err)
+			assert.Equal(t, original, detected)
+		})
+	}
+
+	_, err = ReadEpic(strings.NewReader(`{"id": "1", "phasez": []}`), "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid epic file")
+}
+
+func TestDetectFormat(t *testing.T) {
+	assert.Equal(t, FormatXML, DetectFormat([]byte("\n  <?xml version=\"1.0\"?><epic/>")))
+	assert.Equal(t, FormatXML, DetectFormat([]byte("\xef\xbb\xbf<epic/>")))
+	assert.Equal(t, FormatJSON, DetectFormat([]byte(" {\"id\": \"1\"}")))
+	assert.Equal(t, FormatYAML, DetectFormat([]byte("id: \"1\"\n")))
+	assert.Equal(t, FormatYAML, DetectFormat([]byte("---\nid: \"1\"\n")))
+}
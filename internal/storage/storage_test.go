@@ -379,3 +379,140 @@ func TestXMLRoundTrip(t *testing.T) {
 		assert.Equal(t, test.Description, loaded2.Tests[i].Description)
 	}
 }
+
+func TestEventActorRoundTrip(t *testing.T) {
+	storage := NewFileStorage()
+	epicPath := filepath.Join(t.TempDir(), "actors.xml")
+
+	original := epic.NewEpic("actors-1", "Actor Epic")
+	original.Events = []epic.Event{
+		{ID: "e1", Type: "task_started", Timestamp: time.Date(2025, 8, 16, 9, 0, 0, 0, time.UTC), Actor: "claude-1", Data: "Task T1 started"},
+		{ID: "e2", Type: "task_completed", Timestamp: time.Date(2025, 8, 16, 10, 0, 0, 0, time.UTC), Data: "Task T1 completed"},
+	}
+	require.NoError(t, storage.SaveEpic(original, epicPath))
+
+	content, err := os.ReadFile(epicPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `actor="claude-1"`)
+	assert.NotContains(t, string(content), `actor=""`)
+
+	loaded, err := storage.LoadEpic(epicPath)
+	require.NoError(t, err)
+	require.Len(t, loaded.Events, 2)
+	assert.Equal(t, "claude-1", loaded.Events[0].Actor)
+	assert.Empty(t, loaded.Events[1].Actor)
+}
+
+func TestEventCommitRoundTrip(t *testing.T) {
+	storage := NewFileStorage()
+	epicPath := filepath.Join(t.TempDir(), "commits.xml")
+
+	commit := "385522073bb1687837c8f1ea7858e70a8c63d8a2"
+	original := epic.NewEpic("commits-1", "Commit Epic")
+	original.Events = []epic.Event{
+		{ID: "e1", Type: "task_completed", Timestamp: time.Date(2025, 8, 16, 9, 0, 0, 0, time.UTC), Commit: commit, Branch: "feature/login", Data: "Task T1 completed"},
+		{ID: "e2", Type: "task_completed", Timestamp: time.Date(2025, 8, 16, 10, 0, 0, 0, time.UTC), Data: "Task T2 completed"},
+	}
+	require.NoError(t, storage.SaveEpic(original, epicPath))
+
+	content, err := os.ReadFile(epicPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `commit="`+commit+`" branch="feature/login"`)
+	assert.NotContains(t, string(content), `commit=""`)
+
+	loaded, err := storage.LoadEpic(epicPath)
+	require.NoError(t, err)
+	require.Len(t, loaded.Events, 2)
+	assert.Equal(t, original.Events, loaded.Events)
+}
+
+func TestSuppressionsRoundTrip(t *testing.T) {
+	storage := NewFileStorage()
+	epicPath := filepath.Join(t.TempDir(), "suppressions.xml")
+
+	original := epic.NewEpic("suppress-1", "Suppression Epic")
+	original.Suppressions = []epic.Suppression{
+		{Rule: "max-wip", Entity: "T3", Reason: "Blocked on vendor API access"},
+		{Rule: "phase-needs-tests"},
+	}
+	require.NoError(t, storage.SaveEpic(original, epicPath))
+
+	content, err := os.ReadFile(epicPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `<suppress rule="max-wip" entity="T3">Blocked on vendor API access</suppress>`)
+	assert.NotContains(t, string(content), `entity=""`)
+
+	loaded, err := storage.LoadEpic(epicPath)
+	require.NoError(t, err)
+	assert.Equal(t, original.Suppressions, loaded.Suppressions)
+}
+
+func TestBlockersRoundTrip(t *testing.T) {
+	storage := NewFileStorage()
+	epicPath := filepath.Join(t.TempDir(), "blockers.xml")
+
+	raised := time.Date(2025, 8, 16, 9, 0, 0, 0, time.UTC)
+	resolved := raised.Add(2 * time.Hour)
+	original := epic.NewEpic("blockers-1", "Blocker Epic")
+	original.Blockers = []epic.Blocker{
+		{ID: "B1", Entity: "T3", RaisedAt: raised, RaisedBy: "agent_a", ResolvedAt: &resolved,
+			Description: "Waiting for API credentials & quota", Resolution: "Credentials arrived"},
+		{ID: "B2", Type: epic.BlockerTypeQuestion, RaisedAt: raised, Description: "Which <api> version?"},
+	}
+	require.NoError(t, storage.SaveEpic(original, epicPath))
+
+	content, err := os.ReadFile(epicPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `<blocker id="B2" type="question" raised_at="2025-08-16T09:00:00Z">`)
+	assert.Contains(t, string(content), `<description>Which &lt;api&gt; version?</description>`)
+
+	loaded, err := storage.LoadEpic(epicPath)
+	require.NoError(t, err)
+	assert.Equal(t, original.Blockers, loaded.Blockers)
+}
+
+func TestSubTasksRoundTrip(t *testing.T) {
+	storage := NewFileStorage()
+	epicPath := filepath.Join(t.TempDir(), "subtasks.xml")
+
+	original := epic.NewEpic("subtasks-1", "Sub-task Epic")
+	original.Phases = []epic.Phase{{ID: "1", Name: "Phase 1", Status: epic.StatusPending}}
+	original.Tasks = []epic.Task{
+		{ID: "1_1", PhaseID: "1", Name: "Task 1", Status: epic.StatusPending},
+		{ID: "1_1a", PhaseID: "1", ParentTaskID: "1_1", Name: "Sub-task 1a", Status: epic.StatusPending},
+	}
+	require.NoError(t, storage.SaveEpic(original, epicPath))
+
+	content, err := os.ReadFile(epicPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `parent_task_id="1_1"`)
+
+	loaded, err := storage.LoadEpic(epicPath)
+	require.NoError(t, err)
+	require.Len(t, loaded.Tasks, 2)
+	assert.Empty(t, loaded.Tasks[0].ParentTaskID)
+	assert.Equal(t, "1_1", loaded.Tasks[1].ParentTaskID)
+}
+
+func TestRecurringTaskRoundTrip(t *testing.T) {
+	storage := NewFileStorage()
+	epicPath := filepath.Join(t.TempDir(), "recurring.xml")
+
+	original := epic.NewEpic("recurring-1", "Recurring Epic")
+	original.Phases = []epic.Phase{{ID: "1", Name: "Phase 1", Status: epic.StatusPending}}
+	original.Tasks = []epic.Task{
+		{ID: "1_1", PhaseID: "1", Name: "Task 1", Status: epic.StatusPending},
+		{ID: "1_2", PhaseID: "1", Name: "Rotate keys", Status: epic.StatusPending, Recurring: "2w", Due: "2025-09-15"},
+	}
+	require.NoError(t, storage.SaveEpic(original, epicPath))
+
+	content, err := os.ReadFile(epicPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `recurring="2w"`)
+
+	loaded, err := storage.LoadEpic(epicPath)
+	require.NoError(t, err)
+	require.Len(t, loaded.Tasks, 2)
+	assert.Empty(t, loaded.Tasks[0].Recurring)
+	assert.Equal(t, "2w", loaded.Tasks[1].Recurring)
+}
@@ -0,0 +1,450 @@
+package storage
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// WorktreeInfo describes a linked git worktree (created with `git worktree add`)
+type WorktreeInfo struct {
+	Name      string // Name of the worktree below <common dir>/worktrees
+	Root      string // Top-level directory of the linked worktree
+	MainRoot  string // Top-level directory of the main worktree
+	GitDir    string // Private git directory of the linked worktree
+	CommonDir string // Git directory shared by all worktrees
+}
+
+// DetectWorktree finds the linked git worktree containing path. It returns nil
+// for the main worktree, bare repositories, submodules and paths outside of git.
+func DetectWorktree(path string) *WorktreeInfo {
+	for dir := filepath.Clean(path); ; dir = filepath.Dir(dir) {
+		gitPath := filepath.Join(dir, ".git")
+		if info, err := os.Stat(gitPath); err == nil {
+			if info.IsDir() {
+				return nil
+			}
+			return readWorktreeInfo(dir, gitPath)
+		}
+		if filepath.Dir(dir) == dir {
+			return nil
+		}
+	}
+}
+
+func readWorktreeInfo(root, gitFile string) *WorktreeInfo {
+	data, err := os.ReadFile(gitFile)
+	if err != nil {
+		return nil
+	}
+
+	line := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(line, "gitdir:") {
+		return nil
+	}
+	gitDir := strings.TrimSpace(strings.TrimPrefix(line, "gitdir:"))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(root, gitDir)
+	}
+
+	// Only linked worktrees have a commondir file, submodules do not
+	common, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		return nil
+	}
+	commonDir := strings.TrimSpace(string(common))
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(gitDir, commonDir)
+	}
+	commonDir = filepath.Clean(commonDir)
+
+	// A bare repository has no main worktree holding the shared epic
+	if filepath.Base(commonDir) != ".git" {
+		return nil
+	}
+
+	return &WorktreeInfo{
+		Name:      filepath.Base(gitDir),
+		Root:      root,
+		MainRoot:  filepath.Dir(commonDir),
+		GitDir:    filepath.Clean(gitDir),
+		CommonDir: commonDir,
+	}
+}
+
+// worktreeEpic is an epic file opened from a linked worktree. All worktrees share
+// the copy in the main worktree; each keeps its own claims and WIP in an overlay.
+type worktreeEpic struct {
+	*WorktreeInfo
+	RelPath    string // Epic path relative to the worktree root
+	SharedPath string // Epic file in the main worktree
+}
+
+// worktreeEpicFor returns the worktree view of an epic path, or nil when the path
+// is not inside a linked worktree or the main worktree has no shared copy of it
+func worktreeEpicFor(absPath string) *worktreeEpic {
+	wt := DetectWorktree(filepath.Dir(absPath))
+	if wt == nil {
+		return nil
+	}
+
+	rel, err := filepath.Rel(wt.Root, absPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil
+	}
+
+	shared := filepath.Join(wt.MainRoot, rel)
+	if _, err := os.Stat(shared); err != nil {
+		return nil
+	}
+
+	return &worktreeEpic{WorktreeInfo: wt, RelPath: rel, SharedPath: shared}
+}
+
+// overlayPath is the overlay file of the worktree owning gitDir
+func (w *worktreeEpic) overlayPath(gitDir string) string {
+	return filepath.Join(gitDir, "agentpm", w.RelPath+".overlay.xml")
+}
+
+// otherOverlayPaths lists the overlays of all other worktrees for the same epic
+func (w *worktreeEpic) otherOverlayPaths() []string {
+	entries, err := os.ReadDir(filepath.Join(w.CommonDir, "worktrees"))
+	if err != nil {
+		return nil
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		gitDir := filepath.Join(w.CommonDir, "worktrees", entry.Name())
+		if !entry.IsDir() || gitDir == w.GitDir {
+			continue
+		}
+		path := w.overlayPath(gitDir)
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// worktreeOverlay holds the entities a worktree changed relative to the shared epic.
+// The other epic-level fields it changed (notes, blockers, milestones, ...) are
+// named in EpicFields and carried in Epic, a copy of the epic without its entities.
+type worktreeOverlay struct {
+	XMLName      xml.Name           `xml:"worktree_overlay"`
+	Worktree     string             `xml:"worktree,attr"`
+	EpicStatus   epic.Status        `xml:"epic_status,attr,omitempty"`
+	EpicFields   string             `xml:"epic_fields,attr,omitempty"`
+	CurrentState *epic.CurrentState `xml:"current_state,omitempty"`
+	Epic         *epic.Epic         `xml:"epic,omitempty"`
+	Phases       []epic.Phase       `xml:"phases>phase"`
+	Tasks        []epic.Task        `xml:"tasks>task"`
+	Tests        []epic.Test        `xml:"tests>test"`
+	Events       []epic.Event       `xml:"events>event"`
+}
+
+// overlayEntityFields are the fields of epic.Epic an overlay tracks on their own;
+// every other field is carried whole once it changed
+var overlayEntityFields = map[string]bool{
+	"Status": true, "CurrentState": true, "Phases": true, "Tasks": true, "Tests": true, "Events": true,
+}
+
+func (o *worktreeOverlay) isEmpty() bool {
+	return o.EpicStatus == "" && o.EpicFields == "" && o.CurrentState == nil &&
+		len(o.Phases) == 0 && len(o.Tasks) == 0 && len(o.Tests) == 0 && len(o.Events) == 0
+}
+
+// claimedTasks returns the tasks this worktree is working on
+func (o *worktreeOverlay) claimedTasks() []string {
+	var claimed []string
+	for _, task := range o.Tasks {
+		if task.Status == epic.StatusWIP {
+			claimed = append(claimed, task.ID)
+		}
+	}
+	return claimed
+}
+
+func loadOverlay(path string) (*worktreeOverlay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read worktree overlay: %w", err)
+	}
+
+	var overlay worktreeOverlay
+	if err := xml.Unmarshal(data, &overlay); err != nil {
+		return nil, fmt.Errorf("invalid worktree overlay %s: %w", path, err)
+	}
+	return &overlay, nil
+}
+
+func saveOverlay(overlay *worktreeOverlay, path string) error {
+	data, err := xml.MarshalIndent(overlay, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal worktree overlay: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create worktree overlay directory: %w", err)
+	}
+
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, append([]byte(xml.Header), data...), 0644); err != nil {
+		return fmt.Errorf("failed to write worktree overlay: %w", err)
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to move worktree overlay: %w", err)
+	}
+	return nil
+}
+
+// loadWorktreeEpic reads the shared epic and layers this worktree's overlay on top
+func (fs *FileStorage) loadWorktreeEpic(w *worktreeEpic) (*epic.Epic, error) {
+	epicData, err := fs.loadEpicFile(w.SharedPath)
+	if err != nil {
+		return nil, err
+	}
+
+	overlay, err := loadOverlay(w.overlayPath(w.GitDir))
+	if err != nil {
+		return nil, err
+	}
+	if overlay != nil {
+		applyOverlay(epicData, overlay)
+	}
+	return epicData, nil
+}
+
+// saveWorktreeEpic stores the changes of a worktree in its overlay. Once the
+// worktree holds no task claims any more, the overlay is merged back into the
+// shared epic and removed.
+func (fs *FileStorage) saveWorktreeEpic(epicData *epic.Epic, w *worktreeEpic) error {
+	shared, err := fs.loadEpicFile(w.SharedPath)
+	if err != nil {
+		return err
+	}
+
+	overlay := diffOverlay(shared, epicData)
+	overlay.Worktree = w.Name
+	overlayPath := w.overlayPath(w.GitDir)
+
+	if overlay.isEmpty() {
+		return removeOverlay(overlayPath)
+	}
+
+	claimed := overlay.claimedTasks()
+	if len(claimed) > 0 {
+		if err := w.checkClaims(claimed); err != nil {
+			return err
+		}
+		return saveOverlay(overlay, overlayPath)
+	}
+
+	mergeOverlay(shared, overlay)
+	if err := fs.saveEpicFile(shared, w.SharedPath); err != nil {
+		return err
+	}
+	return removeOverlay(overlayPath)
+}
+
+// checkClaims rejects tasks that another worktree is already working on
+func (w *worktreeEpic) checkClaims(claimed []string) error {
+	for _, path := range w.otherOverlayPaths() {
+		other, err := loadOverlay(path)
+		if err != nil || other == nil {
+			continue
+		}
+		for _, otherTask := range other.claimedTasks() {
+			for _, taskID := range claimed {
+				if taskID == otherTask {
+					return fmt.Errorf("task %s is already claimed by worktree %s", taskID, other.Worktree)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func removeOverlay(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove worktree overlay: %w", err)
+	}
+	return nil
+}
+
+// diffOverlay collects everything in the worktree view that differs from the shared epic
+func diffOverlay(shared, view *epic.Epic) *worktreeOverlay {
+	overlay := &worktreeOverlay{}
+
+	if view.Status != shared.Status {
+		overlay.EpicStatus = view.Status
+	}
+
+	if !reflect.DeepEqual(view.CurrentState, shared.CurrentState) {
+		state := epic.CurrentState{}
+		if view.CurrentState != nil {
+			state = *view.CurrentState
+		}
+		overlay.CurrentState = &state
+	}
+
+	if fields := changedEpicFields(shared, view); len(fields) > 0 {
+		header := *view
+		header.Status, header.CurrentState = "", nil
+		header.Phases, header.Tasks, header.Tests, header.Events = nil, nil, nil, nil
+		overlay.EpicFields = strings.Join(fields, " ")
+		overlay.Epic = &header
+	}
+
+	for _, phase := range view.Phases {
+		if base := findOverlayPhase(shared.Phases, phase.ID); base == nil || !reflect.DeepEqual(*base, phase) {
+			overlay.Phases = append(overlay.Phases, phase)
+		}
+	}
+	for _, task := range view.Tasks {
+		if base := findOverlayTask(shared.Tasks, task.ID); base == nil || !reflect.DeepEqual(*base, task) {
+			overlay.Tasks = append(overlay.Tasks, task)
+		}
+	}
+	for _, test := range view.Tests {
+		if base := findOverlayTest(shared.Tests, test.ID); base == nil || !reflect.DeepEqual(*base, test) {
+			overlay.Tests = append(overlay.Tests, test)
+		}
+	}
+
+	known := make(map[string]bool, len(shared.Events))
+	for _, event := range shared.Events {
+		known[eventKey(event)] = true
+	}
+	for _, event := range view.Events {
+		if !known[eventKey(event)] {
+			overlay.Events = append(overlay.Events, event)
+		}
+	}
+
+	return overlay
+}
+
+// changedEpicFields names the epic-level fields of view that differ from the
+// shared epic, besides the ones the overlay tracks on their own. Empty and
+// missing lists are the same.
+func changedEpicFields(shared, view *epic.Epic) []string {
+	var fields []string
+	before, after := reflect.ValueOf(shared).Elem(), reflect.ValueOf(view).Elem()
+	for i := 0; i < after.NumField(); i++ {
+		name := after.Type().Field(i).Name
+		if overlayEntityFields[name] {
+			continue
+		}
+		a, b := before.Field(i), after.Field(i)
+		if a.Kind() == reflect.Slice && a.Len() == 0 && b.Len() == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// applyOverlay layers a worktree overlay over the shared epic
+func applyOverlay(epicData *epic.Epic, overlay *worktreeOverlay) {
+	if overlay.EpicStatus != "" {
+		epicData.Status = overlay.EpicStatus
+	}
+	if overlay.CurrentState != nil {
+		state := *overlay.CurrentState
+		epicData.CurrentState = &state
+	}
+	if overlay.Epic != nil {
+		target, source := reflect.ValueOf(epicData).Elem(), reflect.ValueOf(overlay.Epic).Elem()
+		for _, name := range strings.Fields(overlay.EpicFields) {
+			if field := target.FieldByName(name); field.IsValid() && !overlayEntityFields[name] {
+				field.Set(source.FieldByName(name))
+			}
+		}
+	}
+
+	for _, phase := range overlay.Phases {
+		if base := findOverlayPhase(epicData.Phases, phase.ID); base != nil {
+			*base = phase
+		} else {
+			epicData.Phases = append(epicData.Phases, phase)
+		}
+	}
+	for _, task := range overlay.Tasks {
+		if base := findOverlayTask(epicData.Tasks, task.ID); base != nil {
+			*base = task
+		} else {
+			epicData.Tasks = append(epicData.Tasks, task)
+		}
+	}
+	for _, test := range overlay.Tests {
+		if base := findOverlayTest(epicData.Tests, test.ID); base != nil {
+			*base = test
+		} else {
+			epicData.Tests = append(epicData.Tests, test)
+		}
+	}
+
+	epicData.Events = append(epicData.Events, overlay.Events...)
+}
+
+// mergeOverlay folds a finished worktree overlay into the shared epic. The shared
+// active task is kept while it is still in progress, since it belongs to another checkout.
+func mergeOverlay(shared *epic.Epic, overlay *worktreeOverlay) {
+	var activeTask string
+	if shared.CurrentState != nil {
+		activeTask = shared.CurrentState.ActiveTask
+	}
+
+	applyOverlay(shared, overlay)
+
+	if overlay.CurrentState != nil {
+		if task := findOverlayTask(shared.Tasks, activeTask); task != nil && task.Status == epic.StatusWIP {
+			shared.CurrentState.ActiveTask = activeTask
+		}
+	}
+}
+
+func eventKey(event epic.Event) string {
+	return fmt.Sprintf("%s|%s|%d|%s", event.ID, event.Type, event.Timestamp.Unix(), event.Data)
+}
+
+func findOverlayPhase(phases []epic.Phase, id string) *epic.Phase {
+	for i := range phases {
+		if phases[i].ID == id {
+			return &phases[i]
+		}
+	}
+	return nil
+}
+
+func findOverlayTask(tasks []epic.Task, id string) *epic.Task {
+	for i := range tasks {
+		if tasks[i].ID == id {
+			return &tasks[i]
+		}
+	}
+	return nil
+}
+
+func findOverlayTest(tests []epic.Test, id string) *epic.Test {
+	for i := range tests {
+		if tests[i].ID == id {
+			return &tests[i]
+		}
+	}
+	return nil
+}
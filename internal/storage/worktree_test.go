@@ -0,0 +1,234 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupWorktrees lays out a main worktree with two linked worktrees the way
+// `git worktree add` does, each checkout holding a copy of epic.xml
+func setupWorktrees(t *testing.T) (mainRoot string, worktrees []string) {
+	t.Helper()
+
+	root := t.TempDir()
+	mainRoot = filepath.Join(root, "main")
+	require.NoError(t, os.MkdirAll(filepath.Join(mainRoot, ".git"), 0755))
+
+	shared := &epic.Epic{
+		ID:        "wt-epic",
+		Name:      "Worktree Epic",
+		Status:    epic.StatusWIP,
+		CreatedAt: time.Date(2025, 8, 16, 9, 0, 0, 0, time.UTC),
+		Phases: []epic.Phase{
+			{ID: "P1", Name: "Phase 1", Status: epic.StatusWIP},
+		},
+		Tasks: []epic.Task{
+			{ID: "T1", PhaseID: "P1", Name: "Task 1", Status: epic.StatusPending},
+			{ID: "T2", PhaseID: "P1", Name: "Task 2", Status: epic.StatusPending},
+		},
+	}
+	fs := NewFileStorage()
+	require.NoError(t, fs.SaveEpic(shared, filepath.Join(mainRoot, "epic.xml")))
+
+	for _, name := range []string{"agent-a", "agent-b"} {
+		gitDir := filepath.Join(mainRoot, ".git", "worktrees", name)
+		require.NoError(t, os.MkdirAll(gitDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(gitDir, "commondir"), []byte("../..\n"), 0644))
+
+		wtRoot := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(wtRoot, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(wtRoot, ".git"), []byte("gitdir: "+gitDir+"\n"), 0644))
+		require.NoError(t, fs.saveEpicFile(shared, filepath.Join(wtRoot, "epic.xml")))
+
+		worktrees = append(worktrees, wtRoot)
+	}
+
+	return mainRoot, worktrees
+}
+
+func startTaskInView(t *testing.T, epicPath, taskID string, ts time.Time) error {
+	t.Helper()
+
+	fs := NewFileStorage()
+	view, err := fs.LoadEpic(epicPath)
+	require.NoError(t, err)
+
+	task := findOverlayTask(view.Tasks, taskID)
+	require.NotNil(t, task)
+	task.Status = epic.StatusWIP
+	task.StartedAt = &ts
+	view.CurrentState = &epic.CurrentState{ActivePhase: "P1", ActiveTask: taskID}
+	view.Events = append(view.Events, epic.Event{ID: "task_started_" + taskID, Type: "task_started", Timestamp: ts, Data: "Task " + taskID + " started"})
+
+	return fs.SaveEpic(view, epicPath)
+}
+
+func TestDetectWorktree(t *testing.T) {
+	mainRoot, worktrees := setupWorktrees(t)
+
+	assert.Nil(t, DetectWorktree(mainRoot), "main worktree is not a linked worktree")
+	assert.Nil(t, DetectWorktree(t.TempDir()), "directories outside git are not worktrees")
+
+	nested := filepath.Join(worktrees[0], "src")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+
+	info := DetectWorktree(nested)
+	require.NotNil(t, info)
+	assert.Equal(t, "agent-a", info.Name)
+	assert.Equal(t, worktrees[0], info.Root)
+	assert.Equal(t, mainRoot, info.MainRoot)
+	assert.Equal(t, filepath.Join(mainRoot, ".git"), info.CommonDir)
+}
+
+func TestWorktreeOverlay(t *testing.T) {
+	ts := time.Date(2025, 8, 16, 10, 0, 0, 0, time.UTC)
+	fs := NewFileStorage()
+
+	t.Run("claims stay in the worktree overlay", func(t *testing.T) {
+		mainRoot, worktrees := setupWorktrees(t)
+		epicA := filepath.Join(worktrees[0], "epic.xml")
+		epicB := filepath.Join(worktrees[1], "epic.xml")
+
+		require.NoError(t, startTaskInView(t, epicA, "T1", ts))
+
+		// The shared epic is untouched while the task is in progress
+		shared, err := fs.LoadEpic(filepath.Join(mainRoot, "epic.xml"))
+		require.NoError(t, err)
+		assert.Equal(t, epic.StatusPending, findOverlayTask(shared.Tasks, "T1").Status)
+		assert.Empty(t, shared.Events)
+
+		viewA, err := fs.LoadEpic(epicA)
+		require.NoError(t, err)
+		assert.Equal(t, epic.StatusWIP, findOverlayTask(viewA.Tasks, "T1").Status)
+		assert.Equal(t, "T1", viewA.CurrentState.ActiveTask)
+
+		// The other worktree can work on another task of the same phase
+		require.NoError(t, startTaskInView(t, epicB, "T2", ts))
+		viewB, err := fs.LoadEpic(epicB)
+		require.NoError(t, err)
+		assert.Equal(t, epic.StatusPending, findOverlayTask(viewB.Tasks, "T1").Status)
+		assert.Equal(t, epic.StatusWIP, findOverlayTask(viewB.Tasks, "T2").Status)
+	})
+
+	t.Run("a task claimed by another worktree cannot be claimed", func(t *testing.T) {
+		_, worktrees := setupWorktrees(t)
+
+		require.NoError(t, startTaskInView(t, filepath.Join(worktrees[0], "epic.xml"), "T1", ts))
+
+		err := startTaskInView(t, filepath.Join(worktrees[1], "epic.xml"), "T1", ts)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "task T1 is already claimed by worktree agent-a")
+	})
+
+	t.Run("completed work is merged back into the shared epic", func(t *testing.T) {
+		mainRoot, worktrees := setupWorktrees(t)
+		epicA := filepath.Join(worktrees[0], "epic.xml")
+
+		require.NoError(t, startTaskInView(t, epicA, "T1", ts))
+
+		view, err := fs.LoadEpic(epicA)
+		require.NoError(t, err)
+		done := ts.Add(time.Hour)
+		task := findOverlayTask(view.Tasks, "T1")
+		task.Status = epic.StatusCompleted
+		task.CompletedAt = &done
+		view.CurrentState.ActiveTask = ""
+		view.Events = append(view.Events, epic.Event{ID: "task_completed_T1", Type: "task_completed", Timestamp: done, Data: "Task T1 completed"})
+		require.NoError(t, fs.SaveEpic(view, epicA))
+
+		shared, err := fs.LoadEpic(filepath.Join(mainRoot, "epic.xml"))
+		require.NoError(t, err)
+		merged := findOverlayTask(shared.Tasks, "T1")
+		assert.Equal(t, epic.StatusCompleted, merged.Status)
+		require.NotNil(t, merged.CompletedAt)
+		require.Len(t, shared.Events, 2)
+		assert.Equal(t, "task_started", shared.Events[0].Type)
+		assert.Equal(t, "task_completed", shared.Events[1].Type)
+
+		_, err = os.Stat(filepath.Join(mainRoot, ".git", "worktrees", "agent-a", "agentpm", "epic.xml.overlay.xml"))
+		assert.True(t, os.IsNotExist(err), "overlay is removed after merging")
+	})
+
+	t.Run("blockers and notes travel with the overlay", func(t *testing.T) {
+		mainRoot, worktrees := setupWorktrees(t)
+		epicA := filepath.Join(worktrees[0], "epic.xml")
+		sharedPath := filepath.Join(mainRoot, "epic.xml")
+
+		require.NoError(t, startTaskInView(t, epicA, "T1", ts))
+
+		view, err := fs.LoadEpic(epicA)
+		require.NoError(t, err)
+		view.Blockers = append(view.Blockers, epic.Blocker{ID: "B1", Entity: "T1", RaisedAt: ts, Description: "Waiting for credentials"})
+		view.Notes = append(view.Notes, epic.Note{Author: "agent_a", CreatedAt: ts, Text: "Uses the v2 endpoint"})
+		view.Due = "2025-09-01"
+		require.NoError(t, fs.SaveEpic(view, epicA))
+
+		// Kept in the overlay while the task is claimed
+		shared, err := fs.LoadEpic(sharedPath)
+		require.NoError(t, err)
+		assert.Empty(t, shared.Blockers)
+		assert.Empty(t, shared.Notes)
+
+		view, err = fs.LoadEpic(epicA)
+		require.NoError(t, err)
+		require.Len(t, view.Blockers, 1)
+		assert.Equal(t, "Waiting for credentials", view.Blockers[0].Description)
+		require.Len(t, view.Notes, 1)
+		assert.Equal(t, "Uses the v2 endpoint", view.Notes[0].Text)
+		assert.Equal(t, "2025-09-01", view.Due)
+
+		viewB, err := fs.LoadEpic(filepath.Join(worktrees[1], "epic.xml"))
+		require.NoError(t, err)
+		assert.Empty(t, viewB.Blockers, "other worktrees do not see the overlay")
+
+		// Merged into the shared epic with the completed task
+		done := ts.Add(time.Hour)
+		view.Blockers[0].ResolvedAt = &done
+		task := findOverlayTask(view.Tasks, "T1")
+		task.Status = epic.StatusCompleted
+		task.CompletedAt = &done
+		view.CurrentState.ActiveTask = ""
+		require.NoError(t, fs.SaveEpic(view, epicA))
+
+		shared, err = fs.LoadEpic(sharedPath)
+		require.NoError(t, err)
+		require.Len(t, shared.Blockers, 1)
+		assert.True(t, shared.Blockers[0].IsResolved())
+		require.Len(t, shared.Notes, 1)
+		assert.Equal(t, "agent_a", shared.Notes[0].Author)
+		assert.Equal(t, "2025-09-01", shared.Due)
+		assert.Equal(t, "Worktree Epic", shared.Name)
+	})
+
+	t.Run("epic-level changes without claims are merged right away", func(t *testing.T) {
+		mainRoot, worktrees := setupWorktrees(t)
+		epicA := filepath.Join(worktrees[0], "epic.xml")
+
+		view, err := fs.LoadEpic(epicA)
+		require.NoError(t, err)
+		view.Blockers = append(view.Blockers, epic.Blocker{ID: "B1", RaisedAt: ts, Description: "Which API version?", Type: epic.BlockerTypeQuestion})
+		require.NoError(t, fs.SaveEpic(view, epicA))
+
+		shared, err := fs.LoadEpic(filepath.Join(mainRoot, "epic.xml"))
+		require.NoError(t, err)
+		require.Len(t, shared.Blockers, 1)
+		assert.Equal(t, epic.BlockerTypeQuestion, shared.Blockers[0].Type)
+	})
+
+	t.Run("epic outside a worktree is stored directly", func(t *testing.T) {
+		mainRoot, _ := setupWorktrees(t)
+		epicPath := filepath.Join(mainRoot, "epic.xml")
+
+		require.NoError(t, startTaskInView(t, epicPath, "T1", ts))
+
+		shared, err := fs.LoadEpic(epicPath)
+		require.NoError(t, err)
+		assert.Equal(t, epic.StatusWIP, findOverlayTask(shared.Tasks, "T1").Status)
+	})
+}
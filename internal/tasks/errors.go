@@ -2,6 +2,7 @@ package tasks
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/mindreframer/agentpm/internal/epic"
 )
@@ -122,3 +123,56 @@ func NewTaskAlreadyActiveError(taskID string) *TaskAlreadyActiveError {
 		TaskID: taskID,
 	}
 }
+
+// TaskDependencyError represents attempting to start a task whose depends_on tasks are not completed
+type TaskDependencyError struct {
+	TaskID string
+	Unmet  []epic.Dependency
+	Hint   string // Actionable hint for completing the dependencies
+}
+
+func (e *TaskDependencyError) Error() string {
+	return fmt.Sprintf("task %s: cannot start with %d unmet dependencies",
+		e.TaskID, len(e.Unmet))
+}
+
+func NewTaskDependencyError(taskID string, unmet []epic.Dependency) *TaskDependencyError {
+	return &TaskDependencyError{
+		TaskID: taskID,
+		Unmet:  unmet,
+		Hint:   "", // Will be populated by hint generator
+	}
+}
+
+// TaskSubTaskError represents a transition the sub-tasks of a task do not
+// allow: starting a sub-task whose parent is not active, or completing or
+// cancelling a task with open sub-tasks
+type TaskSubTaskError struct {
+	TaskID       string
+	ParentTaskID string   // Set when the parent of a sub-task is not active
+	OpenSubTasks []string // Set when the task still has open sub-tasks
+	Message      string
+	Hint         string // Actionable hint for resolving the sub-task issue
+}
+
+func (e *TaskSubTaskError) Error() string {
+	return fmt.Sprintf("task %s: %s", e.TaskID, e.Message)
+}
+
+func NewTaskParentInactiveError(taskID, parentTaskID string) *TaskSubTaskError {
+	return &TaskSubTaskError{
+		TaskID:       taskID,
+		ParentTaskID: parentTaskID,
+		Message:      fmt.Sprintf("parent task %s is not active", parentTaskID),
+		Hint:         fmt.Sprintf("Start the parent task first: agentpm start task %s", parentTaskID),
+	}
+}
+
+func NewTaskOpenSubTasksError(taskID string, openSubTasks []string) *TaskSubTaskError {
+	return &TaskSubTaskError{
+		TaskID:       taskID,
+		OpenSubTasks: openSubTasks,
+		Message:      fmt.Sprintf("sub-tasks %s are not completed or cancelled", strings.Join(openSubTasks, ", ")),
+		Hint:         fmt.Sprintf("Complete or cancel the sub-tasks first, e.g. agentpm done task %s", openSubTasks[0]),
+	}
+}
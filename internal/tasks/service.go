@@ -2,9 +2,11 @@ package tasks
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/policy"
 	"github.com/mindreframer/agentpm/internal/query"
 	"github.com/mindreframer/agentpm/internal/service"
 	"github.com/mindreframer/agentpm/internal/storage"
@@ -24,11 +26,18 @@ func NewTaskService(storage storage.Storage, query *query.QueryService) *TaskSer
 
 // StartTask transitions a task from pending to wip
 func (s *TaskService) StartTask(epicData *epic.Epic, taskID string, timestamp time.Time) error {
+	if err := epicData.EnsureMutable("start task " + taskID); err != nil {
+		return err
+	}
+
 	// Find the task
 	task := s.findTask(epicData, taskID)
 	if task == nil {
 		return fmt.Errorf("task %s not found", taskID)
 	}
+	if err := epicData.EnsurePhaseMutable(task.PhaseID, "start task "+taskID); err != nil {
+		return err
+	}
 
 	// Check if task is already active
 	if task.Status == epic.StatusWIP {
@@ -40,6 +49,9 @@ func (s *TaskService) StartTask(epicData *epic.Epic, taskID string, timestamp ti
 	if err := s.validateTaskStart(epicData, task); err != nil {
 		return err
 	}
+	if err := policy.Enforce(epicData, policy.ActionStartTask, taskID, timestamp); err != nil {
+		return err
+	}
 
 	// Transition task status and set timestamp
 	task.Status = epic.StatusWIP
@@ -53,16 +65,26 @@ func (s *TaskService) StartTask(epicData *epic.Epic, taskID string, timestamp ti
 
 // CompleteTask transitions a task from wip to done
 func (s *TaskService) CompleteTask(epicData *epic.Epic, taskID string, timestamp time.Time) error {
+	if err := epicData.EnsureMutable("complete task " + taskID); err != nil {
+		return err
+	}
+
 	// Find the task
 	task := s.findTask(epicData, taskID)
 	if task == nil {
 		return fmt.Errorf("task %s not found", taskID)
 	}
+	if err := epicData.EnsurePhaseMutable(task.PhaseID, "complete task "+taskID); err != nil {
+		return err
+	}
 
 	// Validate task can be completed
 	if err := s.validateTaskCompletion(epicData, task); err != nil {
 		return err
 	}
+	if err := policy.Enforce(epicData, policy.ActionDoneTask, taskID, timestamp); err != nil {
+		return err
+	}
 
 	// Transition task status and set timestamp
 	task.Status = epic.StatusCompleted
@@ -71,16 +93,39 @@ func (s *TaskService) CompleteTask(epicData *epic.Epic, taskID string, timestamp
 	// Create automatic event for task completion
 	service.CreateEvent(epicData, service.EventTaskCompleted, task.PhaseID, taskID, "", "", timestamp)
 
+	if task.IsRecurring() {
+		return s.scheduleNextOccurrence(epicData, task, timestamp)
+	}
+	return nil
+}
+
+// scheduleNextOccurrence adds the pending task that follows a completed
+// recurring task
+func (s *TaskService) scheduleNextOccurrence(epicData *epic.Epic, task *epic.Task, timestamp time.Time) error {
+	next, err := epicData.NextOccurrence(task, timestamp)
+	if err != nil {
+		return fmt.Errorf("cannot schedule task %s again: %w", task.ID, err)
+	}
+	epicData.Tasks = append(epicData.Tasks, next)
+
+	service.CreateEvent(epicData, service.EventTaskScheduled, next.PhaseID, next.ID, "", task.ID, timestamp)
 	return nil
 }
 
 // CancelTask transitions a task from wip to cancelled
 func (s *TaskService) CancelTask(epicData *epic.Epic, taskID string, timestamp time.Time) error {
+	if err := epicData.EnsureMutable("cancel task " + taskID); err != nil {
+		return err
+	}
+
 	// Find the task
 	task := s.findTask(epicData, taskID)
 	if task == nil {
 		return fmt.Errorf("task %s not found", taskID)
 	}
+	if err := epicData.EnsurePhaseMutable(task.PhaseID, "cancel task "+taskID); err != nil {
+		return err
+	}
 
 	// Validate task can be cancelled
 	if err := s.validateTaskCancellation(epicData, task); err != nil {
@@ -97,24 +142,44 @@ func (s *TaskService) CancelTask(epicData *epic.Epic, taskID string, timestamp t
 	return nil
 }
 
-// GetActiveTask returns the currently active task in the given phase, if any
-func (s *TaskService) GetActiveTask(epicData *epic.Epic, phaseID string) *epic.Task {
-	for i := range epicData.Tasks {
-		if epicData.Tasks[i].PhaseID == phaseID && epicData.Tasks[i].Status == epic.StatusWIP {
-			return &epicData.Tasks[i]
-		}
+// AssignTask hands a task to an agent; an empty assignee removes the task's
+// own assignment so that it falls back to the assignee of its phase
+func (s *TaskService) AssignTask(epicData *epic.Epic, taskID, assignee string, timestamp time.Time) error {
+	if err := epicData.EnsureMutable("assign task " + taskID); err != nil {
+		return err
+	}
+
+	task := s.findTask(epicData, taskID)
+	if task == nil {
+		return fmt.Errorf("task %s not found", taskID)
+	}
+	if err := epicData.EnsurePhaseMutable(task.PhaseID, "assign task "+taskID); err != nil {
+		return err
 	}
+
+	assignee = strings.TrimSpace(assignee)
+	if task.Assignee == assignee {
+		return nil
+	}
+	task.Assignee = assignee
+	service.CreateEvent(epicData, service.EventTaskAssigned, task.PhaseID, taskID, "", "", timestamp)
+
 	return nil
 }
 
-// GetActiveTaskInEpic returns the currently active task in the entire epic, if any
-func (s *TaskService) GetActiveTaskInEpic(epicData *epic.Epic) *epic.Task {
-	for i := range epicData.Tasks {
-		if epicData.Tasks[i].Status == epic.StatusWIP {
-			return &epicData.Tasks[i]
-		}
+// GetActiveTask returns the currently active task in the given phase, if any;
+// an active sub-task is returned rather than its parent
+func (s *TaskService) GetActiveTask(epicData *epic.Epic, phaseID string) *epic.Task {
+	if phaseID == "" {
+		return nil
 	}
-	return nil
+	return epicData.ActiveTask(phaseID)
+}
+
+// GetActiveTaskInEpic returns the currently active task in the entire epic, if any;
+// an active sub-task is returned rather than its parent
+func (s *TaskService) GetActiveTaskInEpic(epicData *epic.Epic) *epic.Task {
+	return epicData.ActiveTask("")
 }
 
 // findTask returns a pointer to the task with the given ID
@@ -154,9 +219,22 @@ func (s *TaskService) validateTaskStart(epicData *epic.Epic, task *epic.Task) er
 		return NewTaskPhaseError(task.ID, task.PhaseID, phase.Status, "Cannot start task: phase is not active")
 	}
 
-	// Check no other task is active in the same phase
+	// Check the tasks it depends on are completed
+	if unmet := epicData.UnmetTaskDependencies(task); len(unmet) > 0 {
+		return NewTaskDependencyError(task.ID, unmet)
+	}
+
+	// A sub-task is worked on while its parent is active
+	if task.IsSubTask() {
+		if parent := epicData.ParentTask(task); parent == nil || parent.Status != epic.StatusWIP {
+			return NewTaskParentInactiveError(task.ID, task.ParentTaskID)
+		}
+	}
+
+	// Check no other task is active in the same phase; the active parent of a
+	// sub-task does not count
 	activeTask := s.GetActiveTask(epicData, task.PhaseID)
-	if activeTask != nil && activeTask.ID != task.ID {
+	if activeTask != nil && activeTask.ID != task.ID && activeTask.ID != task.ParentTaskID {
 		return NewTaskConstraintError(task.ID, activeTask.ID, task.PhaseID, "Cannot start task: another task is already active in this phase")
 	}
 
@@ -169,6 +247,13 @@ func (s *TaskService) validateTaskCompletion(epicData *epic.Epic, task *epic.Tas
 	if task.Status != epic.StatusWIP {
 		return NewTaskStateError(task.ID, task.Status, epic.StatusCompleted, "Task is not in active state")
 	}
+	if open := epicData.OpenSubTasks(task.ID); len(open) > 0 {
+		return NewTaskOpenSubTasksError(task.ID, open)
+	}
+
+	if strict {
+		return NewTaskValidationService().ValidateStrictTaskCompletion(epicData, task)
+	}
 
 	return nil
 }
@@ -179,6 +264,9 @@ func (s *TaskService) validateTaskCancellation(epicData *epic.Epic, task *epic.T
 	if task.Status != epic.StatusWIP {
 		return NewTaskStateError(task.ID, task.Status, epic.StatusCancelled, "Task is not in active state")
 	}
+	if open := epicData.OpenSubTasks(task.ID); len(open) > 0 {
+		return NewTaskOpenSubTasksError(task.ID, open)
+	}
 
 	return nil
 }
@@ -623,3 +623,197 @@ func findTaskByID(epicData *epic.Epic, taskID string) *epic.Task {
 	}
 	return nil
 }
+
+func TestTaskService_CompletedEpicGuard(t *testing.T) {
+	storage := storage.NewMemoryStorage()
+	queryService := query.NewQueryService(storage)
+	taskService := NewTaskService(storage, queryService)
+	testTime := time.Date(2025, 8, 16, 15, 30, 0, 0, time.UTC)
+
+	epicData := &epic.Epic{
+		ID:     "epic-1",
+		Name:   "Test Epic",
+		Status: epic.StatusCompleted,
+		Phases: []epic.Phase{
+			{ID: "phase-1", Name: "Phase 1", Status: epic.StatusWIP},
+		},
+		Tasks: []epic.Task{
+			{ID: "task-1", PhaseID: "phase-1", Name: "Task 1", Status: epic.StatusPending},
+			{ID: "task-2", PhaseID: "phase-1", Name: "Task 2", Status: epic.StatusWIP},
+		},
+	}
+
+	err := taskService.StartTask(epicData, "task-1", testTime)
+	require.Error(t, err)
+	assert.True(t, epic.IsEpicCompleted(err))
+	assert.Equal(t, epic.StatusPending, epicData.Tasks[0].Status)
+
+	err = taskService.CompleteTask(epicData, "task-2", testTime)
+	require.Error(t, err)
+	assert.True(t, epic.IsEpicCompleted(err))
+
+	err = taskService.CancelTask(epicData, "task-2", testTime)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Reopen epic epic-1")
+	assert.Empty(t, epicData.Events)
+}
+
+func TestTaskService_FrozenPhaseGuard(t *testing.T) {
+	storage := storage.NewMemoryStorage()
+	queryService := query.NewQueryService(storage)
+	taskService := NewTaskService(storage, queryService)
+	testTime := time.Date(2025, 8, 16, 15, 30, 0, 0, time.UTC)
+
+	epicData := &epic.Epic{
+		ID:     "epic-1",
+		Name:   "Test Epic",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{
+			{ID: "phase-1", Name: "Phase 1", Status: epic.StatusWIP, FrozenAt: &testTime, FrozenReason: "Shipped"},
+		},
+		Tasks: []epic.Task{
+			{ID: "task-1", PhaseID: "phase-1", Name: "Task 1", Status: epic.StatusPending},
+			{ID: "task-2", PhaseID: "phase-1", Name: "Task 2", Status: epic.StatusWIP},
+		},
+	}
+
+	err := taskService.StartTask(epicData, "task-1", testTime)
+	require.Error(t, err)
+	assert.True(t, epic.IsPhaseFrozen(err))
+	assert.Equal(t, epic.StatusPending, epicData.Tasks[0].Status)
+
+	err = taskService.CompleteTask(epicData, "task-2", testTime)
+	require.Error(t, err)
+	assert.True(t, epic.IsPhaseFrozen(err))
+
+	err = taskService.CancelTask(epicData, "task-2", testTime)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "agentpm unfreeze phase phase-1")
+	assert.Equal(t, epic.StatusWIP, epicData.Tasks[1].Status)
+	assert.Empty(t, epicData.Events)
+}
+
+func TestTaskService_DependsOn(t *testing.T) {
+	storage := storage.NewMemoryStorage()
+	queryService := query.NewQueryService(storage)
+	taskService := NewTaskService(storage, queryService)
+	testTime := time.Date(2025, 8, 16, 15, 30, 0, 0, time.UTC)
+
+	epicData := &epic.Epic{
+		ID:     "epic-deps",
+		Name:   "Dependencies Epic",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{
+			{ID: "phase-1", Name: "Phase 1", Status: epic.StatusWIP},
+		},
+		Tasks: []epic.Task{
+			{ID: "task-1", PhaseID: "phase-1", Name: "Task 1", Status: epic.StatusCancelled},
+			{ID: "task-2", PhaseID: "phase-1", Name: "Task 2", Status: epic.StatusPending},
+			{ID: "task-3", PhaseID: "phase-1", Name: "Task 3", Status: epic.StatusPending, DependsOn: "task-1,task-2"},
+		},
+	}
+
+	err := taskService.StartTask(epicData, "task-3", testTime)
+	var depErr *TaskDependencyError
+	require.ErrorAs(t, err, &depErr)
+	assert.Equal(t, "task-3", depErr.TaskID)
+	assert.Equal(t, []epic.Dependency{
+		{ID: "task-1", Status: epic.StatusCancelled},
+		{ID: "task-2", Status: epic.StatusPending},
+	}, depErr.Unmet)
+
+	epicData.Tasks[0].Status = epic.StatusCompleted
+	epicData.Tasks[1].Status = epic.StatusCompleted
+	require.NoError(t, taskService.StartTask(epicData, "task-3", testTime))
+	assert.Equal(t, epic.StatusWIP, findTaskByID(epicData, "task-3").Status)
+}
+
+func TestTaskService_SubTasks(t *testing.T) {
+	storage := storage.NewMemoryStorage()
+	queryService := query.NewQueryService(storage)
+	taskService := NewTaskService(storage, queryService)
+	testTime := time.Date(2025, 8, 16, 15, 30, 0, 0, time.UTC)
+
+	epicData := &epic.Epic{
+		ID:     "epic-subtasks",
+		Name:   "Sub-tasks Epic",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{
+			{ID: "phase-1", Name: "Phase 1", Status: epic.StatusWIP},
+		},
+		Tasks: []epic.Task{
+			{ID: "task-1", PhaseID: "phase-1", Name: "Task 1", Status: epic.StatusPending},
+			{ID: "task-1a", PhaseID: "phase-1", Name: "Task 1a", Status: epic.StatusPending, ParentTaskID: "task-1"},
+			{ID: "task-1b", PhaseID: "phase-1", Name: "Task 1b", Status: epic.StatusPending, ParentTaskID: "task-1"},
+		},
+	}
+
+	// A sub-task waits for its parent
+	err := taskService.StartTask(epicData, "task-1a", testTime)
+	var subTaskErr *TaskSubTaskError
+	require.ErrorAs(t, err, &subTaskErr)
+	assert.Equal(t, "task-1", subTaskErr.ParentTaskID)
+
+	// The parent stays active while one sub-task after the other is worked on
+	require.NoError(t, taskService.StartTask(epicData, "task-1", testTime))
+	require.NoError(t, taskService.StartTask(epicData, "task-1a", testTime))
+	assert.Equal(t, "task-1a", taskService.GetActiveTask(epicData, "phase-1").ID)
+
+	var constraintErr *TaskConstraintError
+	require.ErrorAs(t, taskService.StartTask(epicData, "task-1b", testTime), &constraintErr)
+	assert.Equal(t, "task-1a", constraintErr.ActiveTaskID)
+
+	// The parent is only done once its sub-tasks are
+	require.NoError(t, taskService.CompleteTask(epicData, "task-1a", testTime))
+	assert.Equal(t, "task-1", taskService.GetActiveTask(epicData, "phase-1").ID)
+
+	require.ErrorAs(t, taskService.CompleteTask(epicData, "task-1", testTime), &subTaskErr)
+	assert.Equal(t, []string{"task-1b"}, subTaskErr.OpenSubTasks)
+	require.ErrorAs(t, taskService.CancelTask(epicData, "task-1", testTime), &subTaskErr)
+
+	require.NoError(t, taskService.StartTask(epicData, "task-1b", testTime))
+	require.NoError(t, taskService.CancelTask(epicData, "task-1b", testTime))
+	require.NoError(t, taskService.CompleteTask(epicData, "task-1", testTime))
+	assert.Equal(t, epic.StatusCompleted, findTaskByID(epicData, "task-1").Status)
+}
+
+func TestTaskService_RecurringTask(t *testing.T) {
+	storage := storage.NewMemoryStorage()
+	queryService := query.NewQueryService(storage)
+	taskService := NewTaskService(storage, queryService)
+	testTime := time.Date(2025, 9, 16, 10, 0, 0, 0, time.UTC)
+
+	epicData := &epic.Epic{
+		ID:     "epic-recurring",
+		Name:   "Recurring Epic",
+		Status: epic.StatusWIP,
+		Phases: []epic.Phase{
+			{ID: "phase-1", Name: "Phase 1", Status: epic.StatusWIP},
+		},
+		Tasks: []epic.Task{
+			{ID: "task-1", PhaseID: "phase-1", Name: "Rotate keys", Status: epic.StatusPending, Recurring: "7d", Due: "2025-09-15"},
+		},
+	}
+
+	require.NoError(t, taskService.StartTask(epicData, "task-1", testTime))
+	require.NoError(t, taskService.CompleteTask(epicData, "task-1", testTime))
+
+	require.Len(t, epicData.Tasks, 2)
+	assert.Equal(t, epic.StatusCompleted, epicData.Tasks[0].Status)
+	next := epicData.Tasks[1]
+	assert.Equal(t, "task-1_r2", next.ID)
+	assert.Equal(t, epic.StatusPending, next.Status)
+	assert.Equal(t, "2025-09-22", next.Due)
+	assert.Equal(t, "7d", next.Recurring)
+
+	lastEvent := epicData.Events[len(epicData.Events)-1]
+	assert.Equal(t, "task_scheduled", lastEvent.Type)
+	assert.Equal(t, "Task task-1_r2 (Rotate keys) scheduled after task-1, due 2025-09-22", lastEvent.Data)
+
+	// The next occurrence is scheduled after the one completed last
+	require.NoError(t, taskService.StartTask(epicData, "task-1_r2", testTime.Add(7*24*time.Hour)))
+	require.NoError(t, taskService.CompleteTask(epicData, "task-1_r2", testTime.Add(7*24*time.Hour)))
+	require.Len(t, epicData.Tasks, 3)
+	assert.Equal(t, "task-1_r3", epicData.Tasks[2].ID)
+	assert.Equal(t, "2025-09-29", epicData.Tasks[2].Due)
+}
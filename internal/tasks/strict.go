@@ -0,0 +1,91 @@
+package tasks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// strict refuses task completions that validation would only warn about, see SetStrict
+var strict bool
+
+// SetStrict enables or disables strict mode for the tasks completed by this process
+func SetStrict(enabled bool) {
+	strict = enabled
+}
+
+// Strict reports whether strict mode is enabled
+func Strict() bool {
+	return strict
+}
+
+// checklistItem matches a markdown checklist item: "- [ ] open" or "- [x] done"
+var checklistItem = regexp.MustCompile(`^\s*(?:[-*+]|\d+\.)\s+\[([ xX])\]\s*(.*)$`)
+
+// ValidateStrictTaskCompletion checks what strict mode additionally demands before a
+// task is done: it has tests, all of them (except cancelled ones) are done and
+// passing, and no acceptance criterion in its checklist is left unchecked
+func (tvs *TaskValidationService) ValidateStrictTaskCompletion(epicData *epic.Epic, task *epic.Task) error {
+	var blockingItems []epic.BlockingItem
+	var reasons []string
+
+	hasTests := false
+	for _, test := range epicData.Tests {
+		if test.TaskID != task.ID {
+			continue
+		}
+		hasTests = true
+
+		status := test.GetTestStatusUnified()
+		switch {
+		case status == epic.TestStatusCancelled:
+		case test.GetTestResult() == epic.TestResultFailing:
+			blockingItems = append(blockingItems, epic.BlockingItem{Type: "test", ID: test.ID, Name: test.Name, Status: string(status), Result: string(epic.TestResultFailing)})
+			reasons = append(reasons, fmt.Sprintf("test %s is failing", test.ID))
+		case status != epic.TestStatusDone:
+			blockingItems = append(blockingItems, epic.BlockingItem{Type: "test", ID: test.ID, Name: test.Name, Status: string(status)})
+			reasons = append(reasons, fmt.Sprintf("test %s is %s", test.ID, status))
+		}
+	}
+	if !hasTests {
+		blockingItems = append(blockingItems, epic.BlockingItem{Type: "tests", ID: task.ID, Name: "no tests defined", Status: "missing"})
+		reasons = append(reasons, "no tests defined")
+	}
+
+	criterion := 0
+	for _, line := range strings.Split(task.AcceptanceCriteria, "\n") {
+		match := checklistItem.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		criterion++
+		if match[1] != " " {
+			continue
+		}
+		text := strings.TrimSpace(match[2])
+		blockingItems = append(blockingItems, epic.BlockingItem{
+			Type:   "acceptance_criterion",
+			ID:     fmt.Sprintf("AC%d", criterion),
+			Name:   text,
+			Status: "unchecked",
+		})
+		reasons = append(reasons, fmt.Sprintf("acceptance criterion %q is unchecked", text))
+	}
+
+	if len(blockingItems) == 0 {
+		return nil
+	}
+
+	return &epic.StatusValidationError{
+		EntityType:    "task",
+		EntityID:      task.ID,
+		EntityName:    task.Name,
+		CurrentStatus: string(task.Status),
+		TargetStatus:  string(epic.StatusCompleted),
+		BlockingItems: blockingItems,
+		Message:       fmt.Sprintf("Task %s cannot be completed in strict mode: %s", task.ID, strings.Join(reasons, "; ")),
+		Suggestions:   []string{"Add and pass the missing tests, check off the acceptance criteria, or run without --strict"},
+	}
+}
@@ -0,0 +1,122 @@
+package tasks
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+func TestTaskValidationService_ValidateStrictTaskCompletion(t *testing.T) {
+	tvs := NewTaskValidationService()
+
+	t.Run("task without tests is refused", func(t *testing.T) {
+		epicData := &epic.Epic{}
+		task := &epic.Task{ID: "T1", Name: "Task 1", Status: epic.StatusWIP}
+
+		err := tvs.ValidateStrictTaskCompletion(epicData, task)
+		validationErr, ok := err.(*epic.StatusValidationError)
+		if !ok {
+			t.Fatalf("Expected StatusValidationError but got: %v", err)
+		}
+		if len(validationErr.BlockingItems) != 1 || validationErr.BlockingItems[0].Status != "missing" {
+			t.Errorf("Expected one missing-tests blocking item, got %+v", validationErr.BlockingItems)
+		}
+		if !strings.Contains(validationErr.Message, "strict mode: no tests defined") {
+			t.Errorf("Unexpected message: %s", validationErr.Message)
+		}
+	})
+
+	t.Run("failing and open tests are refused, cancelled ones ignored", func(t *testing.T) {
+		epicData := &epic.Epic{
+			Tests: []epic.Test{
+				{ID: "T1_1", TaskID: "T1", Name: "Passing", TestStatus: epic.TestStatusDone, TestResult: epic.TestResultPassing},
+				{ID: "T1_2", TaskID: "T1", Name: "Failing", TestStatus: epic.TestStatusWIP, TestResult: epic.TestResultFailing},
+				{ID: "T1_3", TaskID: "T1", Name: "Pending", TestStatus: epic.TestStatusPending},
+				{ID: "T1_4", TaskID: "T1", Name: "Dropped", TestStatus: epic.TestStatusCancelled},
+			},
+		}
+		task := &epic.Task{ID: "T1", Name: "Task 1", Status: epic.StatusWIP}
+
+		err := tvs.ValidateStrictTaskCompletion(epicData, task)
+		validationErr, ok := err.(*epic.StatusValidationError)
+		if !ok {
+			t.Fatalf("Expected StatusValidationError but got: %v", err)
+		}
+		var ids []string
+		for _, item := range validationErr.BlockingItems {
+			ids = append(ids, item.ID)
+		}
+		if strings.Join(ids, ",") != "T1_2,T1_3" {
+			t.Errorf("Expected T1_2 and T1_3 to block, got %v", ids)
+		}
+		if !strings.Contains(validationErr.Message, "test T1_2 is failing") {
+			t.Errorf("Unexpected message: %s", validationErr.Message)
+		}
+	})
+
+	t.Run("unchecked acceptance criteria are refused", func(t *testing.T) {
+		epicData := &epic.Epic{
+			Tests: []epic.Test{{ID: "T1_1", TaskID: "T1", TestStatus: epic.TestStatusDone, TestResult: epic.TestResultPassing}},
+		}
+		task := &epic.Task{ID: "T1", Name: "Task 1", Status: epic.StatusWIP, AcceptanceCriteria: `
+			- [x] Users can log in
+			- [ ] Sessions expire after 30 minutes
+			Plain prose is not a checklist item
+		`}
+
+		err := tvs.ValidateStrictTaskCompletion(epicData, task)
+		validationErr, ok := err.(*epic.StatusValidationError)
+		if !ok {
+			t.Fatalf("Expected StatusValidationError but got: %v", err)
+		}
+		if len(validationErr.BlockingItems) != 1 {
+			t.Fatalf("Expected one blocking item, got %+v", validationErr.BlockingItems)
+		}
+		item := validationErr.BlockingItems[0]
+		if item.ID != "AC2" || item.Name != "Sessions expire after 30 minutes" || item.Status != "unchecked" {
+			t.Errorf("Unexpected blocking item: %+v", item)
+		}
+	})
+
+	t.Run("tested task with checked criteria passes", func(t *testing.T) {
+		epicData := &epic.Epic{
+			Tests: []epic.Test{{ID: "T1_1", TaskID: "T1", TestStatus: epic.TestStatusDone, TestResult: epic.TestResultPassing}},
+		}
+		task := &epic.Task{ID: "T1", Status: epic.StatusWIP, AcceptanceCriteria: "- [X] Done"}
+
+		if err := tvs.ValidateStrictTaskCompletion(epicData, task); err != nil {
+			t.Errorf("Expected no error but got: %v", err)
+		}
+	})
+}
+
+func TestTaskService_CompleteTask_Strict(t *testing.T) {
+	newEpic := func() *epic.Epic {
+		return &epic.Epic{
+			ID:     "epic-1",
+			Status: epic.StatusWIP,
+			Phases: []epic.Phase{{ID: "P1", Status: epic.StatusWIP}},
+			Tasks:  []epic.Task{{ID: "T1", PhaseID: "P1", Status: epic.StatusWIP}},
+		}
+	}
+	service := NewTaskService(nil, nil)
+	now := time.Date(2025, 8, 16, 15, 0, 0, 0, time.UTC)
+
+	if err := service.CompleteTask(newEpic(), "T1", now); err != nil {
+		t.Fatalf("Expected untested task to complete outside strict mode, got: %v", err)
+	}
+
+	SetStrict(true)
+	defer SetStrict(false)
+
+	epicData := newEpic()
+	err := service.CompleteTask(epicData, "T1", now)
+	if _, ok := err.(*epic.StatusValidationError); !ok {
+		t.Fatalf("Expected StatusValidationError in strict mode but got: %v", err)
+	}
+	if epicData.Tasks[0].Status != epic.StatusWIP {
+		t.Errorf("Expected refused task to stay wip, got %s", epicData.Tasks[0].Status)
+	}
+}
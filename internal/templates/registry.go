@@ -0,0 +1,143 @@
+package templates
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxTemplateSize limits downloads so a bad URL cannot fill the disk
+const maxTemplateSize = 10 << 20
+
+// RegistryEntry is one template offered by a registry
+type RegistryEntry struct {
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	SHA256      string `json:"sha256"`
+	Description string `json:"description,omitempty"`
+}
+
+// Registry is the JSON index a template registry publishes:
+//
+//	{"templates": [{"name": "...", "url": "...", "sha256": "...", "description": "..."}]}
+//
+// Entry URLs may be relative to the registry location.
+type Registry struct {
+	Source    string          `json:"-"`
+	Templates []RegistryEntry `json:"templates"`
+}
+
+// Fetcher downloads registries and templates over HTTP(S) or from local files
+type Fetcher struct {
+	Client *http.Client
+}
+
+// NewFetcher creates a fetcher with a request timeout
+func NewFetcher() *Fetcher {
+	return &Fetcher{Client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// IsURL reports whether a fetch argument is a URL rather than a registry template name
+func IsURL(value string) bool {
+	return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")
+}
+
+// FetchRegistry loads the registry index from a URL or a local file
+func (f *Fetcher) FetchRegistry(source string) (*Registry, error) {
+	data, err := f.read(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch template registry: %w", err)
+	}
+
+	var registry Registry
+	if err := json.Unmarshal(data, &registry); err != nil {
+		return nil, fmt.Errorf("invalid template registry %s: %w", source, err)
+	}
+	registry.Source = source
+	return &registry, nil
+}
+
+// Lookup finds a template by name
+func (r *Registry) Lookup(name string) (*RegistryEntry, error) {
+	for i := range r.Templates {
+		if r.Templates[i].Name == name {
+			return &r.Templates[i], nil
+		}
+	}
+	return nil, fmt.Errorf("template %s not found in registry %s", name, r.Source)
+}
+
+// Resolve returns the location of an entry, resolving relative URLs against the registry
+func (r *Registry) Resolve(entry *RegistryEntry) (string, error) {
+	if IsURL(entry.URL) || filepath.IsAbs(entry.URL) {
+		return entry.URL, nil
+	}
+	if IsURL(r.Source) {
+		base, err := url.Parse(r.Source)
+		if err != nil {
+			return "", fmt.Errorf("invalid registry URL %s: %w", r.Source, err)
+		}
+		ref, err := url.Parse(entry.URL)
+		if err != nil {
+			return "", fmt.Errorf("invalid template URL %s: %w", entry.URL, err)
+		}
+		return base.ResolveReference(ref).String(), nil
+	}
+	return filepath.Join(filepath.Dir(r.Source), entry.URL), nil
+}
+
+// FetchTemplate downloads a template and verifies its SHA-256 checksum
+func (f *Fetcher) FetchTemplate(source, checksum string) ([]byte, error) {
+	if checksum == "" {
+		return nil, fmt.Errorf("a sha256 checksum is required to fetch %s", source)
+	}
+
+	data, err := f.read(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch template: %w", err)
+	}
+
+	if actual := Checksum(data); !strings.EqualFold(actual, strings.TrimSpace(checksum)) {
+		return nil, fmt.Errorf("checksum mismatch for %s: expected %s, got %s", source, checksum, actual)
+	}
+	return data, nil
+}
+
+// Checksum returns the hex encoded SHA-256 of data
+func Checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (f *Fetcher) read(source string) ([]byte, error) {
+	if !IsURL(source) {
+		return os.ReadFile(source)
+	}
+
+	resp, err := f.Client.Get(source)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", source, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxTemplateSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxTemplateSize {
+		return nil, fmt.Errorf("GET %s: response exceeds %d bytes", source, maxTemplateSize)
+	}
+	return data, nil
+}
@@ -0,0 +1,81 @@
+package templates
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRegistryServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/registry/index.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"templates": [{"name": "service", "url": "templates/service.xml", "sha256": "%s", "description": "Service skeleton"}]}`, Checksum([]byte(serviceTemplate)))
+	})
+	mux.HandleFunc("/registry/templates/service.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, serviceTemplate)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFetchRegistry(t *testing.T) {
+	server := newRegistryServer(t)
+	fetcher := &Fetcher{Client: server.Client()}
+
+	registry, err := fetcher.FetchRegistry(server.URL + "/registry/index.json")
+	require.NoError(t, err)
+	require.Len(t, registry.Templates, 1)
+
+	entry, err := registry.Lookup("service")
+	require.NoError(t, err)
+	source, err := registry.Resolve(entry)
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/registry/templates/service.xml", source)
+
+	data, err := fetcher.FetchTemplate(source, entry.SHA256)
+	require.NoError(t, err)
+	assert.Equal(t, serviceTemplate, string(data))
+
+	_, err = registry.Lookup("missing")
+	assert.ErrorContains(t, err, "template missing not found in registry")
+
+	_, err = fetcher.FetchRegistry(server.URL + "/registry/missing.json")
+	assert.ErrorContains(t, err, "404")
+}
+
+func TestFetchRegistryFromFile(t *testing.T) {
+	dir := t.TempDir()
+	index := filepath.Join(dir, "index.json")
+	require.NoError(t, os.WriteFile(index, []byte(`{"templates": [{"name": "service", "url": "service.xml"}]}`), 0644))
+
+	registry, err := NewFetcher().FetchRegistry(index)
+	require.NoError(t, err)
+	source, err := registry.Resolve(&registry.Templates[0])
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "service.xml"), source)
+}
+
+func TestFetchTemplateChecksum(t *testing.T) {
+	server := newRegistryServer(t)
+	fetcher := &Fetcher{Client: server.Client()}
+	source := server.URL + "/registry/templates/service.xml"
+
+	_, err := fetcher.FetchTemplate(source, "")
+	assert.ErrorContains(t, err, "a sha256 checksum is required")
+
+	_, err = fetcher.FetchTemplate(source, "0000")
+	assert.ErrorContains(t, err, "checksum mismatch")
+
+	_, err = fetcher.FetchTemplate(source, Checksum([]byte(serviceTemplate)))
+	assert.NoError(t, err)
+}
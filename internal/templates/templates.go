@@ -0,0 +1,231 @@
+package templates
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// Template sources
+const (
+	SourceBuiltIn  = "built-in"
+	SourceLocal    = "local"
+	SourceRegistry = "registry"
+)
+
+// Template describes an epic template that can be used with init --template
+type Template struct {
+	Name        string `json:"name"`
+	Source      string `json:"source"`
+	Path        string `json:"path,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+//go:embed builtin/*.xml
+var builtinFS embed.FS
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ValidateName checks that a template name can be used as a file name
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid template name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	return nil
+}
+
+// List returns the built-in templates and the templates installed in dir, sorted by
+// name. A local template shadows a built-in template of the same name.
+func List(dir string) ([]Template, error) {
+	byName := make(map[string]Template)
+
+	entries, err := builtinFS.ReadDir("builtin")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read built-in templates: %w", err)
+	}
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".xml")
+		data, err := builtinFS.ReadFile("builtin/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read built-in template %s: %w", name, err)
+		}
+		byName[name] = Template{Name: name, Source: SourceBuiltIn, Description: describe(data)}
+	}
+
+	if dir != "" {
+		entries, err := os.ReadDir(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read templates directory: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || filepath.Ext(entry.Name()) != ".xml" {
+				continue
+			}
+			name := strings.TrimSuffix(entry.Name(), ".xml")
+			path := filepath.Join(dir, entry.Name())
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read template %s: %w", name, err)
+			}
+			byName[name] = Template{Name: name, Source: SourceLocal, Path: path, Description: describe(data)}
+		}
+	}
+
+	list := make([]Template, 0, len(byName))
+	for _, template := range byName {
+		list = append(list, template)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// Load returns the content of the named template, preferring a template installed
+// in dir over a built-in one
+func Load(dir, name string) ([]byte, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	if dir != "" {
+		data, err := os.ReadFile(filepath.Join(dir, name+".xml"))
+		if err == nil {
+			return data, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read template %s: %w", name, err)
+		}
+	}
+
+	data, err := builtinFS.ReadFile("builtin/" + name + ".xml")
+	if err != nil {
+		return nil, fmt.Errorf("template %s not found (see 'agentpm template list')", name)
+	}
+	return data, nil
+}
+
+// Install validates a template and writes it into dir as <name>.xml. An existing
+// template is only replaced when force is set.
+func Install(dir, name string, data []byte, force bool) (string, error) {
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+	if err := Validate(data); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, name+".xml")
+	if _, err := os.Stat(path); err == nil && !force {
+		return "", fmt.Errorf("template %s already exists at %s (use --force to replace it)", name, path)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create templates directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write template: %w", err)
+	}
+	return path, nil
+}
+
+// Validate checks that data is an XML document with an <epic> root element
+func Validate(data []byte) error {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(data); err != nil {
+		return fmt.Errorf("template is not valid XML: %w", err)
+	}
+	if root := doc.Root(); root == nil || root.Tag != "epic" {
+		return fmt.Errorf("template is not an epic: root element must be <epic>")
+	}
+	return nil
+}
+
+// LoadFile returns the content of a template file given by path
+func LoadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template %s: %w", path, err)
+	}
+	if err := Validate(data); err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// Options customizes the epic created from a template. Empty fields keep the
+// template's values.
+type Options struct {
+	ID          string
+	Name        string
+	Description string
+	CreatedAt   time.Time
+}
+
+// Instantiate turns template content into a new epic file, stamping its creation time
+func Instantiate(data []byte, createdAt time.Time) ([]byte, error) {
+	return InstantiateWith(data, Options{CreatedAt: createdAt})
+}
+
+// InstantiateWith turns template content into a new epic file with the given
+// ID, name and description, stamping its creation time
+func InstantiateWith(data []byte, opts Options) ([]byte, error) {
+	if err := Validate(data); err != nil {
+		return nil, err
+	}
+
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(data); err != nil {
+		return nil, fmt.Errorf("template is not valid XML: %w", err)
+	}
+	root := doc.Root()
+	if opts.ID != "" {
+		root.CreateAttr("id", opts.ID)
+	}
+	if opts.Name != "" {
+		root.CreateAttr("name", opts.Name)
+	}
+	if opts.Description != "" {
+		description := root.SelectElement("description")
+		if description == nil {
+			description = etree.NewElement("description")
+			root.InsertChildAt(0, description)
+			doc.Indent(4)
+		}
+		description.SetText(opts.Description)
+	}
+	root.CreateAttr("created_at", opts.CreatedAt.UTC().Format(time.RFC3339))
+	if root.SelectAttr("schema_version") == nil {
+		root.CreateAttr("schema_version", strconv.Itoa(epic.CurrentSchemaVersion))
+	}
+
+	return doc.WriteToBytes()
+}
+
+// Attrs returns the ID and name of a template's epic
+func Attrs(data []byte) (id, name string) {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(data); err != nil || doc.Root() == nil {
+		return "", ""
+	}
+	return doc.Root().SelectAttrValue("id", ""), doc.Root().SelectAttrValue("name", "")
+}
+
+// describe returns the epic description of a template, if it has one
+func describe(data []byte) string {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(data); err != nil || doc.Root() == nil {
+		return ""
+	}
+	if description := doc.Root().SelectElement("description"); description != nil {
+		return strings.TrimSpace(description.Text())
+	}
+	return ""
+}
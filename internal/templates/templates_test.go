@@ -0,0 +1,126 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const serviceTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<epic id="service" name="Service" status="pending">
+    <description>Service skeleton</description>
+</epic>
+`
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+
+	list, err := List(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, Template{Name: "basic", Source: SourceBuiltIn, Description: "Describe the goal of this epic"}, list[0])
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "service.xml"), []byte(serviceTemplate), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0644))
+
+	list, err = List(dir)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "basic", list[0].Name)
+	assert.Equal(t, Template{Name: "service", Source: SourceLocal, Path: filepath.Join(dir, "service.xml"), Description: "Service skeleton"}, list[1])
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	data, err := Load(dir, "basic")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `<epic id="new-epic"`)
+
+	// A local template shadows the built-in one
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "basic.xml"), []byte(serviceTemplate), 0644))
+	data, err = Load(dir, "basic")
+	require.NoError(t, err)
+	assert.Equal(t, serviceTemplate, string(data))
+
+	_, err = Load(dir, "missing")
+	assert.EqualError(t, err, "template missing not found (see 'agentpm template list')")
+
+	_, err = Load(dir, "../basic")
+	assert.Error(t, err)
+}
+
+func TestInstall(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "templates")
+
+	path, err := Install(dir, "service", []byte(serviceTemplate), false)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "service.xml"), path)
+
+	_, err = Install(dir, "service", []byte(serviceTemplate), false)
+	assert.ErrorContains(t, err, "already exists")
+
+	_, err = Install(dir, "service", []byte(serviceTemplate), true)
+	assert.NoError(t, err)
+
+	_, err = Install(dir, "broken", []byte("<epic>"), false)
+	assert.ErrorContains(t, err, "not valid XML")
+
+	_, err = Install(dir, "html", []byte("<html></html>"), false)
+	assert.ErrorContains(t, err, "root element must be <epic>")
+}
+
+func TestInstantiate(t *testing.T) {
+	content, err := Instantiate([]byte(serviceTemplate), time.Date(2025, 8, 16, 9, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `<epic id="service" name="Service" status="pending" created_at="2025-08-16T09:00:00Z" schema_version="2">`)
+	assert.Contains(t, string(content), "<description>Service skeleton</description>")
+}
+
+func TestInstantiateWith(t *testing.T) {
+	createdAt := time.Date(2025, 8, 16, 9, 0, 0, 0, time.UTC)
+
+	t.Run("replaces ID, name and description", func(t *testing.T) {
+		content, err := InstantiateWith([]byte(serviceTemplate), Options{ID: "9", Name: "Search", Description: "Full-text search", CreatedAt: createdAt})
+		require.NoError(t, err)
+		assert.Contains(t, string(content), `<epic id="9" name="Search" status="pending" created_at="2025-08-16T09:00:00Z" schema_version="2">`)
+		assert.Contains(t, string(content), "<description>Full-text search</description>")
+		assert.NotContains(t, string(content), "Service skeleton")
+	})
+
+	t.Run("adds a missing description", func(t *testing.T) {
+		content, err := InstantiateWith([]byte(`<epic id="x" name="X"><phases/></epic>`), Options{Description: "Added", CreatedAt: createdAt})
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "<epic id=\"x\" name=\"X\" created_at=\"2025-08-16T09:00:00Z\" schema_version=\"2\">\n    <description>Added</description>\n    <phases/>")
+	})
+
+	t.Run("empty options keep the template", func(t *testing.T) {
+		content, err := InstantiateWith([]byte(serviceTemplate), Options{CreatedAt: createdAt})
+		require.NoError(t, err)
+		id, name := Attrs(content)
+		assert.Equal(t, "service", id)
+		assert.Equal(t, "Service", name)
+	})
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.xml")
+	require.NoError(t, os.WriteFile(path, []byte(serviceTemplate), 0644))
+
+	data, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, serviceTemplate, string(data))
+
+	invalid := filepath.Join(dir, "invalid.xml")
+	require.NoError(t, os.WriteFile(invalid, []byte("<task/>"), 0644))
+	_, err = LoadFile(invalid)
+	assert.ErrorContains(t, err, "root element must be <epic>")
+
+	_, err = LoadFile(filepath.Join(dir, "missing.xml"))
+	assert.ErrorContains(t, err, "failed to read template")
+}
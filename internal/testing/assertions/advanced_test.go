@@ -103,6 +103,68 @@ func TestAssertionBuilder_StateProgression_Failure(t *testing.T) {
 	}
 }
 
+// progressionResult returns a result whose snapshots went through the given epic states
+func progressionResult(states ...string) *executor.TransitionChainResult {
+	result := &executor.TransitionChainResult{FinalState: &epic.Epic{ID: "progression"}, Success: true}
+	for _, state := range states {
+		result.IntermediateStates = append(result.IntermediateStates, executor.StateSnapshot{
+			EpicState: &epic.Epic{ID: "progression", Status: epic.Status(state)},
+		})
+	}
+	return result
+}
+
+func TestAssertionBuilder_StateProgression_Patterns(t *testing.T) {
+	result := progressionResult("pending", "wip", "wip", "wip", "completed")
+
+	matching := [][]string{
+		{"pending", "wip*", "completed"},
+		{"pending", "...", "completed"},
+		{"...", "completed"},
+		{"pending", "wip*", "..."},
+		{"...", "wip", "..."},
+		{"pending", "wip", "...", "wip", "completed"},
+		{"pending*", "wip*", "completed*"},
+	}
+	for _, pattern := range matching {
+		if err := Assert(result).StateProgression(pattern).Check(); err != nil {
+			t.Errorf("expected %v to match, got error: %v", pattern, err)
+		}
+	}
+
+	failing := []struct {
+		pattern []string
+		message string
+	}{
+		{[]string{"pending", "wip*"}, "at step 5: expected end of progression, got completed"},
+		{[]string{"pending", "completed*"}, "at step 2: expected completed*, got wip"},
+		{[]string{"...", "cancelled"}, "at step 6: expected cancelled, got end of progression"},
+		{[]string{"pending", "wip*", "completed", "done"}, "at step 6: expected done, got end of progression"},
+	}
+	for _, tc := range failing {
+		err := Assert(result).StateProgression(tc.pattern).Check()
+		if err == nil {
+			t.Errorf("expected %v not to match", tc.pattern)
+			continue
+		}
+		if !strings.Contains(err.Error(), tc.message) {
+			t.Errorf("expected error for %v to contain %q, got: %v", tc.pattern, tc.message, err)
+		}
+	}
+}
+
+func TestAssertionBuilder_StateProgression_PatternsOnRealChain(t *testing.T) {
+	result := createAdvancedTestResult(t)
+
+	err := Assert(result).
+		StateProgression([]string{"pending", "...", "wip*", "..."}).
+		Check()
+
+	if err != nil {
+		t.Errorf("Expected pattern to match the progression of the chain, got error: %v", err)
+	}
+}
+
 func TestAssertionBuilder_IntermediateState_Success(t *testing.T) {
 	result := createAdvancedTestResult(t)
 
@@ -2,6 +2,8 @@ package assertions
 
 import (
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/mindreframer/agentpm/internal/epic"
@@ -479,7 +481,11 @@ func (ab *AssertionBuilder) PerformanceBenchmark(maxDuration time.Duration, maxM
 	return ab
 }
 
-// StateProgression validates that the epic progressed through expected states
+// StateProgression validates that the epic progressed through expected states.
+// Besides plain states, which match exactly one snapshot, the expected states
+// may hold patterns: "wip*" matches one or more snapshots in state wip, and
+// "..." any number of snapshots, including none. Without patterns the
+// progression must have exactly as many states as expected.
 func (ab *AssertionBuilder) StateProgression(expectedStates []string) *AssertionBuilder {
 	if len(ab.result.IntermediateStates) == 0 {
 		ab.addError("state_progression", "No intermediate states captured", expectedStates, nil, nil)
@@ -493,6 +499,22 @@ func (ab *AssertionBuilder) StateProgression(expectedStates []string) *Assertion
 		}
 	}
 
+	if hasProgressionPattern(expectedStates) {
+		if step, expected, ok := matchProgression(expectedStates, actualStates); !ok {
+			actual := "end of progression"
+			if step < len(actualStates) {
+				actual = actualStates[step]
+			}
+			ab.addError("state_progression",
+				fmt.Sprintf("State progression does not match pattern at step %d: expected %s, got %s", step+1, expected, actual),
+				expectedStates, actualStates,
+				map[string]interface{}{
+					"step": step + 1,
+				})
+		}
+		return ab
+	}
+
 	if len(actualStates) != len(expectedStates) {
 		ab.addError("state_progression",
 			fmt.Sprintf("Expected %d state transitions, got %d", len(expectedStates), len(actualStates)),
@@ -515,6 +537,70 @@ func (ab *AssertionBuilder) StateProgression(expectedStates []string) *Assertion
 	return ab
 }
 
+// progressionGap is the StateProgression pattern element matching any number of states
+const progressionGap = "..."
+
+// hasProgressionPattern reports whether expected states use "state*" or "..." patterns
+func hasProgressionPattern(expectedStates []string) bool {
+	for _, expected := range expectedStates {
+		if expected == progressionGap || strings.HasSuffix(expected, "*") {
+			return true
+		}
+	}
+	return false
+}
+
+// matchProgression matches states against a StateProgression pattern. When
+// they do not match, it returns the furthest step any attempt got to and the
+// pattern element expected there.
+func matchProgression(pattern, states []string) (int, string, bool) {
+	furthest, expectedAt := -1, ""
+	fail := func(step int, expected string) bool {
+		if step > furthest {
+			furthest, expectedAt = step, expected
+		}
+		return false
+	}
+
+	// Partial matches are remembered, as gaps and repetitions retry the same positions
+	failed := make(map[[2]int]bool)
+	var match func(p, s int) bool
+	match = func(p, s int) bool {
+		if failed[[2]int{p, s}] {
+			return false
+		}
+		var ok bool
+		switch {
+		case p == len(pattern):
+			ok = s == len(states) || fail(s, "end of progression")
+		case pattern[p] == progressionGap:
+			ok = match(p+1, s) || (s < len(states) && match(p, s+1))
+		case strings.HasSuffix(pattern[p], "*"):
+			state := strings.TrimSuffix(pattern[p], "*")
+			if s < len(states) && states[s] == state {
+				ok = match(p+1, s+1) || match(p, s+1)
+			} else {
+				ok = fail(s, pattern[p])
+			}
+		default:
+			if s < len(states) && states[s] == pattern[p] {
+				ok = match(p+1, s+1)
+			} else {
+				ok = fail(s, pattern[p])
+			}
+		}
+		if !ok {
+			failed[[2]int{p, s}] = true
+		}
+		return ok
+	}
+
+	if match(0, 0) {
+		return 0, "", true
+	}
+	return furthest, expectedAt, false
+}
+
 // IntermediateState validates the state at a specific point in the execution
 func (ab *AssertionBuilder) IntermediateState(stepIndex int, validator func(*epic.Epic) error) *AssertionBuilder {
 	if stepIndex < 0 || stepIndex >= len(ab.result.IntermediateStates) {
@@ -625,10 +711,15 @@ func (ab *AssertionBuilder) CustomAssertion(name string, validator func(*executo
 }
 
 // Check validates all assertions and returns any errors
+// When AGENTPM_CHAIN_EXPORT_DIR is set, a failed check also exports the chain
+// result with its assertion failures into that directory.
 func (ab *AssertionBuilder) Check() error {
 	if len(ab.errors) == 0 {
 		return nil
 	}
+	if dir := os.Getenv(executor.ExportDirEnv); dir != "" {
+		ab.exportFailure(dir)
+	}
 
 	if len(ab.errors) == 1 {
 		return ab.errors[0]
@@ -648,6 +739,26 @@ func (ab *AssertionBuilder) MustPass() {
 	}
 }
 
+// exportFailure writes the chain result and the assertion failures into dir.
+// Export problems are reported on stderr; they never mask the failures.
+func (ab *AssertionBuilder) exportFailure(dir string) {
+	if ab.result == nil {
+		return
+	}
+	export, err := ab.result.Export()
+	if err == nil {
+		for _, assertionErr := range ab.errors {
+			export.AssertionFailures = append(export.AssertionFailures, assertionErr.Message)
+		}
+		var path string
+		if path, err = export.ExportToDir(dir); err == nil {
+			fmt.Fprintf(os.Stderr, "chain result exported to %s\n", path)
+			return
+		}
+	}
+	fmt.Fprintf(os.Stderr, "failed to export chain result: %v\n", err)
+}
+
 // CompositeAssertionError represents multiple assertion failures
 type CompositeAssertionError struct {
 	Errors []AssertionError
@@ -1,6 +1,8 @@
 package assertions
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -83,6 +85,34 @@ func TestAssertionBuilder_EpicStatus_Failure(t *testing.T) {
 	}
 }
 
+func TestAssertionBuilder_Check_ExportsFailedResult(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "chain-results")
+	t.Setenv(executor.ExportDirEnv, dir)
+	result := createTestResult(t)
+
+	if err := Assert(result).EpicStatus("wip").Check(); err != nil {
+		t.Fatalf("Expected assertion to pass, got error: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Expected no export for a passing check, got %v", err)
+	}
+
+	if err := Assert(result).EpicStatus("completed").Check(); err == nil {
+		t.Fatal("Expected assertion to fail")
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "chain-test-epic-*.json"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("Expected one exported chain result, got %v (%v)", files, err)
+	}
+	export, err := executor.ReadResultFile(files[0])
+	if err != nil {
+		t.Fatalf("Failed to read exported result: %v", err)
+	}
+	if len(export.AssertionFailures) != 1 || !strings.Contains(export.AssertionFailures[0], "Expected epic status completed, got wip") {
+		t.Errorf("Expected the assertion failure in the export, got %v", export.AssertionFailures)
+	}
+}
+
 func TestAssertionBuilder_PhaseStatus_Success(t *testing.T) {
 	result := createTestResult(t)
 
@@ -409,6 +409,7 @@ func (tc *TransitionChain) Execute() (*TransitionChainResult, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get initial epic state: %w", err)
 	}
+	initialState = copyEpicState(initialState)
 
 	executedCommands := make([]CommandExecution, 0, len(tc.commands))
 	errors := make([]TransitionError, 0)
@@ -423,11 +424,12 @@ func (tc *TransitionChain) Execute() (*TransitionChainResult, error) {
 		cmdEndTime := tc.timeSource()
 		cmdDuration := cmdEndTime.Sub(cmdStartTime)
 
-		// Get epic state after command
+		// Get epic state after command, copied as later commands change the epic
 		epicState, getErr := tc.environment.GetCurrentEpic()
 		if getErr != nil {
 			epicState = nil
 		}
+		epicState = copyEpicState(epicState)
 
 		// Record command execution
 		execution := CommandExecution{
@@ -0,0 +1,242 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/storage"
+)
+
+// ExportDirEnv names the environment variable that, when set, makes failed
+// assertion checks write their chain result as JSON into that directory, so
+// failed scenario runs in CI can be downloaded and inspected with
+// tools/chain-viewer
+const ExportDirEnv = "AGENTPM_CHAIN_EXPORT_DIR"
+
+// ResultExportVersion is the version of the ResultExport JSON layout
+const ResultExportVersion = 1
+
+// ResultExport is the JSON form of a TransitionChainResult. Epic states are
+// stored in the JSON storage format, errors as their messages and durations
+// in nanoseconds.
+type ResultExport struct {
+	Version           int              `json:"version"`
+	ExportedAt        time.Time        `json:"exported_at"`
+	Success           bool             `json:"success"`
+	ExecutionTime     time.Duration    `json:"execution_time_ns"`
+	MemoryUsage       int64            `json:"memory_usage"`
+	InitialState      json.RawMessage  `json:"initial_state,omitempty"`
+	FinalState        json.RawMessage  `json:"final_state,omitempty"`
+	Commands          []CommandExport  `json:"commands"`
+	Snapshots         []SnapshotExport `json:"snapshots"`
+	Errors            []ErrorExport    `json:"errors"`
+	AssertionFailures []string         `json:"assertion_failures,omitempty"`
+}
+
+// CommandExport is the JSON form of a CommandExecution
+type CommandExport struct {
+	Type        string          `json:"type"`
+	Target      string          `json:"target,omitempty"`
+	Description string          `json:"description,omitempty"`
+	StartTime   time.Time       `json:"start_time"`
+	EndTime     time.Time       `json:"end_time"`
+	Duration    time.Duration   `json:"duration_ns"`
+	Success     bool            `json:"success"`
+	Error       string          `json:"error,omitempty"`
+	EpicState   json.RawMessage `json:"epic_state,omitempty"`
+}
+
+// SnapshotExport is the JSON form of a StateSnapshot
+type SnapshotExport struct {
+	Command   string                 `json:"command"`
+	Timestamp time.Time              `json:"timestamp"`
+	Success   bool                   `json:"success"`
+	Error     string                 `json:"error,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	EpicState json.RawMessage        `json:"epic_state,omitempty"`
+}
+
+// ErrorExport is the JSON form of a TransitionError
+type ErrorExport struct {
+	Command        string                 `json:"command"`
+	Message        string                 `json:"message"`
+	ExpectedState  string                 `json:"expected_state,omitempty"`
+	ActualState    string                 `json:"actual_state,omitempty"`
+	ContextualInfo map[string]interface{} `json:"contextual_info,omitempty"`
+	Suggestions    []string               `json:"suggestions,omitempty"`
+}
+
+// Export converts the result into its JSON form, including the epic state
+// after every command and every intermediate snapshot
+func (r *TransitionChainResult) Export() (*ResultExport, error) {
+	export := &ResultExport{
+		Version:       ResultExportVersion,
+		ExportedAt:    time.Now().UTC(),
+		Success:       r.Success,
+		ExecutionTime: r.ExecutionTime,
+		MemoryUsage:   r.MemoryUsage,
+		Commands:      make([]CommandExport, 0, len(r.ExecutedCommands)),
+		Snapshots:     make([]SnapshotExport, 0, len(r.IntermediateStates)),
+		Errors:        make([]ErrorExport, 0, len(r.Errors)),
+	}
+
+	var err error
+	if export.InitialState, err = exportEpic(r.InitialState); err != nil {
+		return nil, err
+	}
+	if export.FinalState, err = exportEpic(r.FinalState); err != nil {
+		return nil, err
+	}
+	for _, execution := range r.ExecutedCommands {
+		state, err := exportEpic(execution.EpicState)
+		if err != nil {
+			return nil, err
+		}
+		export.Commands = append(export.Commands, CommandExport{
+			Type:        execution.Command.Type,
+			Target:      execution.Command.Target,
+			Description: execution.Command.Description,
+			StartTime:   execution.StartTime,
+			EndTime:     execution.EndTime,
+			Duration:    execution.Duration,
+			Success:     execution.Success,
+			Error:       errorMessage(execution.Error),
+			EpicState:   state,
+		})
+	}
+	for _, snapshot := range r.IntermediateStates {
+		state, err := exportEpic(snapshot.EpicState)
+		if err != nil {
+			return nil, err
+		}
+		export.Snapshots = append(export.Snapshots, SnapshotExport{
+			Command:   snapshot.Command,
+			Timestamp: snapshot.Timestamp,
+			Success:   snapshot.Success,
+			Error:     errorMessage(snapshot.Error),
+			Metadata:  snapshot.Metadata,
+			EpicState: state,
+		})
+	}
+	for _, transitionErr := range r.Errors {
+		export.Errors = append(export.Errors, ErrorExport{
+			Command:        transitionErr.Command,
+			Message:        transitionErr.Error(),
+			ExpectedState:  transitionErr.ExpectedState,
+			ActualState:    transitionErr.ActualState,
+			ContextualInfo: transitionErr.ContextualInfo,
+			Suggestions:    transitionErr.Suggestions,
+		})
+	}
+	return export, nil
+}
+
+// WriteJSON writes the result as indented JSON
+func (r *TransitionChainResult) WriteJSON(w io.Writer) error {
+	export, err := r.Export()
+	if err != nil {
+		return err
+	}
+	return export.WriteJSON(w)
+}
+
+// WriteFile writes the result as JSON to path
+func (r *TransitionChainResult) WriteFile(path string) error {
+	export, err := r.Export()
+	if err != nil {
+		return err
+	}
+	return export.WriteFile(path)
+}
+
+// WriteJSON writes the export as indented JSON
+func (e *ResultExport) WriteJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal chain result: %w", err)
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// WriteFile writes the export as JSON to path
+func (e *ResultExport) WriteFile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := e.WriteJSON(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// ExportToDir writes the export into dir under a new file name derived from
+// the epic, e.g. chain-8-1234567.json, and returns its path
+func (e *ResultExport) ExportToDir(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create export directory: %w", err)
+	}
+	name := "chain"
+	if final, err := e.Epic(e.FinalState); err == nil && final != nil && final.ID != "" {
+		name += "-" + filepath.Base(final.ID)
+	}
+	file, err := os.CreateTemp(dir, name+"-*.json")
+	if err != nil {
+		return "", fmt.Errorf("failed to create chain result file: %w", err)
+	}
+	if err := e.WriteJSON(file); err != nil {
+		file.Close()
+		return "", err
+	}
+	return file.Name(), file.Close()
+}
+
+// Epic decodes an epic state of the export; it returns nil for an absent state
+func (e *ResultExport) Epic(state json.RawMessage) (*epic.Epic, error) {
+	if len(state) == 0 {
+		return nil, nil
+	}
+	return storage.DecodeEpic(state, storage.FormatJSON)
+}
+
+// ReadResultFile reads a chain result exported with WriteFile
+func ReadResultFile(path string) (*ResultExport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var export ResultExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("failed to parse chain result %s: %w", path, err)
+	}
+	if export.Version != ResultExportVersion {
+		return nil, fmt.Errorf("unsupported chain result version %d in %s", export.Version, path)
+	}
+	return &export, nil
+}
+
+// exportEpic encodes an epic state in the JSON storage format
+func exportEpic(e *epic.Epic) (json.RawMessage, error) {
+	if e == nil {
+		return nil, nil
+	}
+	data, err := storage.EncodeEpic(e, storage.FormatJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode epic state: %w", err)
+	}
+	return json.RawMessage(data), nil
+}
+
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
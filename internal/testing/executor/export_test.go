@@ -0,0 +1,140 @@
+package executor
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/testing/builders"
+)
+
+func exportTestResult(t *testing.T) *TransitionChainResult {
+	t.Helper()
+	env := NewTestExecutionEnvironment("export-epic.xml")
+	testEpic, err := builders.NewEpicBuilder("export-epic").
+		WithStatus("pending").
+		WithPhase("1A", "Setup", "pending").
+		WithTask("1A_1", "1A", "Initialize Project", "pending").
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build test epic: %v", err)
+	}
+	if err := env.LoadEpic(testEpic); err != nil {
+		t.Fatalf("Failed to load epic: %v", err)
+	}
+
+	// Starting the task before its phase fails and is recorded as an error
+	result, err := CreateTransitionChain(env).
+		StartEpic().
+		StartTask("1A_1").
+		Execute()
+	if err != nil {
+		t.Fatalf("Failed to execute transition chain: %v", err)
+	}
+	return result
+}
+
+func TestResultExport_RoundTrip(t *testing.T) {
+	result := exportTestResult(t)
+	if result.Success {
+		t.Fatalf("Expected the chain to fail")
+	}
+
+	path := filepath.Join(t.TempDir(), "result.json")
+	if err := result.WriteFile(path); err != nil {
+		t.Fatalf("Failed to write result: %v", err)
+	}
+	export, err := ReadResultFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read result: %v", err)
+	}
+
+	if export.Version != ResultExportVersion || export.Success {
+		t.Errorf("Unexpected header: version %d, success %v", export.Version, export.Success)
+	}
+	if export.ExecutionTime != result.ExecutionTime {
+		t.Errorf("Expected execution time %v, got %v", result.ExecutionTime, export.ExecutionTime)
+	}
+	if len(export.Commands) != 2 {
+		t.Fatalf("Expected 2 commands, got %d", len(export.Commands))
+	}
+	if export.Commands[0].Type != "start_epic" || !export.Commands[0].Success {
+		t.Errorf("Unexpected first command: %+v", export.Commands[0])
+	}
+	if export.Commands[1].Target != "1A_1" || export.Commands[1].Success || export.Commands[1].Error == "" {
+		t.Errorf("Expected the failed start_task with its error, got %+v", export.Commands[1])
+	}
+	if export.Commands[1].Duration != result.ExecutedCommands[1].Duration {
+		t.Errorf("Expected command duration %v, got %v", result.ExecutedCommands[1].Duration, export.Commands[1].Duration)
+	}
+	if len(export.Errors) != len(result.Errors) || !strings.Contains(export.Errors[0].Message, "start_task") {
+		t.Errorf("Unexpected errors: %+v", export.Errors)
+	}
+	if len(export.Snapshots) != len(result.IntermediateStates) {
+		t.Errorf("Expected %d snapshots, got %d", len(result.IntermediateStates), len(export.Snapshots))
+	}
+
+	initial, err := export.Epic(export.InitialState)
+	if err != nil || initial.Status != epic.StatusPending {
+		t.Errorf("Expected the pending initial state, got %+v (%v)", initial, err)
+	}
+	afterStart, err := export.Epic(export.Commands[0].EpicState)
+	if err != nil || afterStart.Status != epic.StatusWIP {
+		t.Errorf("Expected the started epic after start_epic, got %+v (%v)", afterStart, err)
+	}
+	final, err := export.Epic(export.FinalState)
+	if err != nil || final.ID != "export-epic" {
+		t.Errorf("Expected the final state of export-epic, got %+v (%v)", final, err)
+	}
+}
+
+func TestResultExport_ExportToDir(t *testing.T) {
+	export, err := exportTestResult(t).Export()
+	if err != nil {
+		t.Fatalf("Failed to export result: %v", err)
+	}
+	export.AssertionFailures = []string{"Expected epic status completed, got wip"}
+
+	dir := filepath.Join(t.TempDir(), "results")
+	path, err := export.ExportToDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to export to directory: %v", err)
+	}
+	if filepath.Dir(path) != dir || !strings.HasPrefix(filepath.Base(path), "chain-export-epic-") {
+		t.Errorf("Unexpected export path %s", path)
+	}
+	if second, err := export.ExportToDir(dir); err != nil || second == path {
+		t.Errorf("Expected a second export to get its own file, got %s (%v)", second, err)
+	}
+
+	read, err := ReadResultFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read result: %v", err)
+	}
+	if len(read.AssertionFailures) != 1 {
+		t.Errorf("Expected the assertion failure to be exported, got %v", read.AssertionFailures)
+	}
+}
+
+func TestResultExport_UnsupportedVersion(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&ResultExport{Version: 99}).WriteJSON(&buf); err != nil {
+		t.Fatalf("Failed to write export: %v", err)
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &raw); err != nil {
+		t.Fatalf("Expected valid JSON: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "result.json")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if _, err := ReadResultFile(path); err == nil || !strings.Contains(err.Error(), "unsupported chain result version 99") {
+		t.Errorf("Expected an unsupported version error, got %v", err)
+	}
+}
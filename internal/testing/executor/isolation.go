@@ -164,29 +164,10 @@ func (env *TestExecutionEnvironment) Cleanup() error {
 
 // addSnapshot adds a state snapshot (must be called with mutex held)
 func (env *TestExecutionEnvironment) addSnapshot(command string, e *epic.Epic, success bool, err error) {
-	// Create a deep copy of the epic to prevent mutation
-	epicCopy := *e
-	if e.Phases != nil {
-		epicCopy.Phases = make([]epic.Phase, len(e.Phases))
-		copy(epicCopy.Phases, e.Phases)
-	}
-	if e.Tasks != nil {
-		epicCopy.Tasks = make([]epic.Task, len(e.Tasks))
-		copy(epicCopy.Tasks, e.Tasks)
-	}
-	if e.Tests != nil {
-		epicCopy.Tests = make([]epic.Test, len(e.Tests))
-		copy(epicCopy.Tests, e.Tests)
-	}
-	if e.Events != nil {
-		epicCopy.Events = make([]epic.Event, len(e.Events))
-		copy(epicCopy.Events, e.Events)
-	}
-
 	snapshot := StateSnapshot{
 		Command:   command,
 		Timestamp: env.timeSource(),
-		EpicState: &epicCopy,
+		EpicState: copyEpicState(e),
 		Success:   success,
 		Error:     err,
 		Metadata:  make(map[string]interface{}),
@@ -312,3 +293,29 @@ func (env *TestExecutionEnvironment) GetExecutionSummary() (*ExecutionSummary, e
 		ExecutionTime: executionTime,
 	}, nil
 }
+
+// copyEpicState copies the phases, tasks, tests and events of an epic, so the
+// copy keeps the state when the epic is changed by later commands
+func copyEpicState(e *epic.Epic) *epic.Epic {
+	if e == nil {
+		return nil
+	}
+	epicCopy := *e
+	if e.Phases != nil {
+		epicCopy.Phases = make([]epic.Phase, len(e.Phases))
+		copy(epicCopy.Phases, e.Phases)
+	}
+	if e.Tasks != nil {
+		epicCopy.Tasks = make([]epic.Task, len(e.Tasks))
+		copy(epicCopy.Tasks, e.Tasks)
+	}
+	if e.Tests != nil {
+		epicCopy.Tests = make([]epic.Test, len(e.Tests))
+		copy(epicCopy.Tests, e.Tests)
+	}
+	if e.Events != nil {
+		epicCopy.Events = make([]epic.Event, len(e.Events))
+		copy(epicCopy.Events, e.Events)
+	}
+	return &epicCopy
+}
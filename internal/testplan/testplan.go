@@ -0,0 +1,271 @@
+// Package testplan imports tests from test plans kept in spreadsheets. A plan
+// is a CSV file with a header row; every further row describes one test and
+// names the task it verifies.
+package testplan
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// Columns names the header columns a plan is read from. Task and Name are
+// required; the other columns are used when the plan has them.
+type Columns struct {
+	ID          string
+	Task        string // Task ID or task name
+	Phase       string // Phase ID or name, checked against the task's phase
+	Name        string
+	Description string
+	Tags        string // Comma-separated test tags, e.g. "unit,integration"
+	Type        string // "manual" for tests a person checks, empty for agent-executable tests
+}
+
+// DefaultColumns returns the column names used when none are given
+func DefaultColumns() Columns {
+	return Columns{ID: "id", Task: "task", Phase: "phase", Name: "name", Description: "description", Tags: "tags", Type: "type"}
+}
+
+// Row is one test of a plan
+type Row struct {
+	Line        int // Line number in the CSV file
+	ID          string
+	Task        string
+	Phase       string
+	Name        string
+	Description string
+	Tags        string
+	Type        string
+}
+
+// Unmatched is a row that could not be imported
+type Unmatched struct {
+	Line   int    `json:"line"`
+	Task   string `json:"task,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// Result is the outcome of importing a plan into an epic
+type Result struct {
+	Imported  []epic.Test
+	Unmatched []Unmatched
+}
+
+// Read parses a CSV plan. Header names are matched case-insensitively and
+// empty rows are skipped.
+func Read(r io.Reader, delimiter rune, columns Columns) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("test plan is empty (a header row is required)")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid test plan: %w", err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff") // Spreadsheet exports often start with a BOM
+		}
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	column := func(name string, required bool) (int, error) {
+		if i, ok := index[strings.ToLower(strings.TrimSpace(name))]; ok && name != "" {
+			return i, nil
+		}
+		if required {
+			return 0, fmt.Errorf("test plan has no %q column (columns: %s)", name, strings.Join(header, ", "))
+		}
+		return -1, nil
+	}
+
+	taskColumn, err := column(columns.Task, true)
+	if err != nil {
+		return nil, err
+	}
+	nameColumn, err := column(columns.Name, true)
+	if err != nil {
+		return nil, err
+	}
+	idColumn, _ := column(columns.ID, false)
+	phaseColumn, _ := column(columns.Phase, false)
+	descriptionColumn, _ := column(columns.Description, false)
+	tagsColumn, _ := column(columns.Tags, false)
+	typeColumn, _ := column(columns.Type, false)
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid test plan: %w", err)
+		}
+		field := func(i int) string {
+			if i < 0 || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, Row{
+			Line:        line,
+			ID:          field(idColumn),
+			Task:        field(taskColumn),
+			Phase:       field(phaseColumn),
+			Name:        field(nameColumn),
+			Description: field(descriptionColumn),
+			Tags:        field(tagsColumn),
+			Type:        field(typeColumn),
+		})
+	}
+	return rows, nil
+}
+
+// Apply adds a pending test to the epic for every row that references an
+// existing task. Rows with an unknown, ambiguous or frozen task, a phase that
+// does not hold the task, a test ID already in use or a test name the task
+// already has are reported as unmatched and leave the epic unchanged, so that
+// importing a plan again only adds its new rows.
+func Apply(e *epic.Epic, rows []Row) *Result {
+	result := &Result{}
+	usedIDs := make(map[string]bool, len(e.Tests))
+	for _, test := range e.Tests {
+		usedIDs[test.ID] = true
+	}
+
+	for _, row := range rows {
+		unmatched := func(format string, args ...interface{}) {
+			result.Unmatched = append(result.Unmatched, Unmatched{
+				Line: row.Line, Task: row.Task, Name: row.Name, Reason: fmt.Sprintf(format, args...),
+			})
+		}
+
+		if row.Name == "" {
+			unmatched("no test name")
+			continue
+		}
+		if row.Task == "" {
+			unmatched("no task")
+			continue
+		}
+		if row.Type != "" && !strings.EqualFold(row.Type, epic.TestTypeManual) {
+			unmatched("unknown test type %q (expected %s or none)", row.Type, epic.TestTypeManual)
+			continue
+		}
+		task, err := findTask(e, row.Task)
+		if err != nil {
+			unmatched("%v", err)
+			continue
+		}
+		if row.Phase != "" && !phaseMatches(e, task.PhaseID, row.Phase) {
+			unmatched("task %s is not in phase %s", task.ID, row.Phase)
+			continue
+		}
+		if phase := findPhase(e, task.PhaseID); phase != nil && phase.IsFrozen() {
+			unmatched("phase %s is frozen", phase.ID)
+			continue
+		}
+
+		if existing := findTestByName(e, task.ID, row.Name); existing != nil {
+			unmatched("task %s already has test %s named %q", task.ID, existing.ID, existing.Name)
+			continue
+		}
+
+		id := row.ID
+		if id == "" {
+			id = nextTestID(usedIDs, task.ID)
+		} else if usedIDs[id] {
+			unmatched("test %s already exists", id)
+			continue
+		}
+		usedIDs[id] = true
+
+		test := epic.Test{
+			ID:          id,
+			TaskID:      task.ID,
+			PhaseID:     task.PhaseID,
+			Name:        row.Name,
+			Description: row.Description,
+			Tags:        row.Tags,
+			Type:        strings.ToLower(row.Type),
+			Status:      epic.StatusPending,
+			TestStatus:  epic.TestStatusPending,
+		}
+		e.Tests = append(e.Tests, test)
+		result.Imported = append(result.Imported, test)
+	}
+	return result
+}
+
+// findTask finds a task by ID, else by its name if exactly one task has it
+func findTask(e *epic.Epic, ref string) (*epic.Task, error) {
+	for i := range e.Tasks {
+		if e.Tasks[i].ID == ref {
+			return &e.Tasks[i], nil
+		}
+	}
+	var found *epic.Task
+	for i := range e.Tasks {
+		if strings.EqualFold(e.Tasks[i].Name, ref) {
+			if found != nil {
+				return nil, fmt.Errorf("task name %q is ambiguous (tasks %s and %s)", ref, found.ID, e.Tasks[i].ID)
+			}
+			found = &e.Tasks[i]
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("task %s not found", ref)
+	}
+	return found, nil
+}
+
+func findTestByName(e *epic.Epic, taskID, name string) *epic.Test {
+	for i := range e.Tests {
+		if e.Tests[i].TaskID == taskID && strings.EqualFold(e.Tests[i].Name, name) {
+			return &e.Tests[i]
+		}
+	}
+	return nil
+}
+
+func findPhase(e *epic.Epic, id string) *epic.Phase {
+	for i := range e.Phases {
+		if e.Phases[i].ID == id {
+			return &e.Phases[i]
+		}
+	}
+	return nil
+}
+
+// phaseMatches reports whether ref, a phase ID or name, is the phase with the given ID
+func phaseMatches(e *epic.Epic, phaseID, ref string) bool {
+	if ref == phaseID {
+		return true
+	}
+	phase := findPhase(e, phaseID)
+	return phase != nil && strings.EqualFold(phase.Name, ref)
+}
+
+// nextTestID returns the first unused ID of the form T<task>_<n>
+func nextTestID(usedIDs map[string]bool, taskID string) string {
+	for n := 1; ; n++ {
+		if id := fmt.Sprintf("T%s_%d", taskID, n); !usedIDs[id] {
+			return id
+		}
+	}
+}
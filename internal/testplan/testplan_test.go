@@ -0,0 +1,109 @@
+package testplan
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func planEpic() *epic.Epic {
+	frozenAt := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	e := epic.NewEpic("8", "Checkout")
+	e.Phases = []epic.Phase{
+		{ID: "1", Name: "Cart", Status: epic.StatusWIP},
+		{ID: "2", Name: "Payment", Status: epic.StatusPending},
+		{ID: "3", Name: "Legacy", Status: epic.StatusCompleted, FrozenAt: &frozenAt},
+	}
+	e.Tasks = []epic.Task{
+		{ID: "1_1", PhaseID: "1", Name: "Add to cart"},
+		{ID: "2_1", PhaseID: "2", Name: "Pay by card"},
+		{ID: "2_2", PhaseID: "2", Name: "Refund"},
+		{ID: "2_3", PhaseID: "2", Name: "refund"},
+		{ID: "3_1", PhaseID: "3", Name: "Old checkout"},
+	}
+	e.Tests = []epic.Test{{ID: "T1_1_1", TaskID: "1_1", PhaseID: "1", Name: "Existing"}}
+	return e
+}
+
+func TestRead(t *testing.T) {
+	plan := "\ufeffTest Case;Story;Notes\n" +
+		"Adds an item;1_1;Happy path\n" +
+		";;\n" +
+		"\"Multi\nline\";2_1\n"
+	rows, err := Read(strings.NewReader(plan), ';', Columns{Task: "story", Name: "test case", Description: "Notes", Phase: "phase"})
+	require.NoError(t, err)
+	assert.Equal(t, []Row{
+		{Line: 2, Task: "1_1", Name: "Adds an item", Description: "Happy path"},
+		{Line: 4, Task: "2_1", Name: "Multi\nline"},
+	}, rows)
+
+	rows, err = Read(strings.NewReader("task,name,tags\n1_1,Checkout by hand,\"manual, e2e\"\n"), ',', DefaultColumns())
+	require.NoError(t, err)
+	assert.Equal(t, []Row{{Line: 2, Task: "1_1", Name: "Checkout by hand", Tags: "manual, e2e"}}, rows)
+	result := Apply(planEpic(), rows)
+	require.Len(t, result.Imported, 1)
+	assert.Equal(t, []string{"manual", "e2e"}, result.Imported[0].TagList())
+
+	rows, err = Read(strings.NewReader("task,name,type\n1_1,Looks right,Manual\n1_1,Sounds right,audio\n"), ',', DefaultColumns())
+	require.NoError(t, err)
+	result = Apply(planEpic(), rows)
+	require.Len(t, result.Imported, 1)
+	assert.True(t, result.Imported[0].IsManual())
+	assert.Equal(t, "manual", result.Imported[0].Type)
+	require.Len(t, result.Unmatched, 1)
+	assert.Equal(t, `unknown test type "audio" (expected manual or none)`, result.Unmatched[0].Reason)
+
+	_, err = Read(strings.NewReader("name,phase\nx,1\n"), ',', DefaultColumns())
+	assert.EqualError(t, err, `test plan has no "task" column (columns: name, phase)`)
+
+	_, err = Read(strings.NewReader(""), ',', DefaultColumns())
+	assert.EqualError(t, err, "test plan is empty (a header row is required)")
+}
+
+func TestApply(t *testing.T) {
+	e := planEpic()
+	result := Apply(e, []Row{
+		{Line: 2, Task: "1_1", Name: "Adds an item"},
+		{Line: 3, Task: "pay by card", Phase: "Payment", Name: "Card accepted", Description: "Visa and Mastercard"},
+		{Line: 4, ID: "T-custom", Task: "2_1", Phase: "2", Name: "Card declined"},
+		{Line: 5, Task: "9_9", Name: "Unknown task"},
+		{Line: 6, Task: "Refund", Name: "Ambiguous task"},
+		{Line: 7, Task: "1_1", Phase: "Payment", Name: "Wrong phase"},
+		{Line: 8, Task: "3_1", Name: "Frozen phase"},
+		{Line: 9, ID: "T1_1_1", Task: "1_1", Name: "Duplicate ID"},
+		{Line: 10, Task: "1_1"},
+		{Line: 11, Name: "No task"},
+		{Line: 12, Task: "1_1", Name: "existing"},
+		{Line: 13, Task: "1_1", Name: "Adds an item"},
+	})
+
+	require.Len(t, result.Imported, 3)
+	assert.Equal(t, epic.Test{
+		ID: "T1_1_2", TaskID: "1_1", PhaseID: "1", Name: "Adds an item",
+		Status: epic.StatusPending, TestStatus: epic.TestStatusPending,
+	}, result.Imported[0])
+	assert.Equal(t, "T2_1_1", result.Imported[1].ID)
+	assert.Equal(t, "Visa and Mastercard", result.Imported[1].Description)
+	assert.Equal(t, "T-custom", result.Imported[2].ID)
+	assert.Len(t, e.Tests, 4)
+
+	reasons := make(map[int]string)
+	for _, row := range result.Unmatched {
+		reasons[row.Line] = row.Reason
+	}
+	assert.Equal(t, map[int]string{
+		5:  "task 9_9 not found",
+		6:  `task name "Refund" is ambiguous (tasks 2_2 and 2_3)`,
+		7:  "task 1_1 is not in phase Payment",
+		8:  "phase 3 is frozen",
+		9:  "test T1_1_1 already exists",
+		10: "no test name",
+		11: "no task",
+		12: `task 1_1 already has test T1_1_1 named "Existing"`,
+		13: `task 1_1 already has test T1_1_2 named "Adds an item"`,
+	}, reasons)
+}
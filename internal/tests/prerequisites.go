@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// validateRequiredPrerequisites checks the tests and tasks a test explicitly
+// requires: tests must be done and passing, tasks completed. The error names
+// every unmet prerequisite and hints at the commands that satisfy them, in order.
+func (s *TestService) validateRequiredPrerequisites(e *epic.Epic, test *epic.Test) error {
+	if cycle := s.requiresCycle(e, test.ID, nil); cycle != nil {
+		return &TestError{
+			Type:    ErrorTypePrerequisite,
+			TestID:  test.ID,
+			Message: fmt.Sprintf("Cannot start test %s: its requires form a cycle (%s)", test.ID, strings.Join(cycle, " -> ")),
+			Hint:    "Remove one of the requires references of the cycle",
+		}
+	}
+
+	var unmet []string
+	var steps []string
+	for _, id := range test.RequiredIDs() {
+		if required := s.findTestByID(e, id); required != nil {
+			if s.testDone(required) {
+				continue
+			}
+			unmet = append(unmet, fmt.Sprintf("test %s is %s", id, s.describeTest(required)))
+		} else if task := findTaskByID(e, id); task != nil {
+			if task.Status == epic.StatusCompleted {
+				continue
+			}
+			unmet = append(unmet, fmt.Sprintf("task %s is %s", id, task.Status))
+		} else {
+			return &TestError{
+				Type:    ErrorTypePrerequisite,
+				TestID:  test.ID,
+				Message: fmt.Sprintf("Cannot start test %s: required %s is neither a test nor a task of this epic", test.ID, id),
+				Hint:    fmt.Sprintf("Fix the requires attribute of test %s", test.ID),
+			}
+		}
+		steps = s.appendPrerequisiteSteps(e, id, steps)
+	}
+
+	if len(unmet) == 0 {
+		return nil
+	}
+	return &TestError{
+		Type:    ErrorTypePrerequisite,
+		TestID:  test.ID,
+		Message: fmt.Sprintf("Cannot start test %s: prerequisites not done: %s", test.ID, strings.Join(unmet, ", ")),
+		Hint:    "Run in order: " + strings.Join(steps, " -> "),
+	}
+}
+
+// appendPrerequisiteSteps appends the commands that get prerequisite id done,
+// preceded by those for its own prerequisites. Steps already listed are skipped.
+func (s *TestService) appendPrerequisiteSteps(e *epic.Epic, id string, steps []string) []string {
+	add := func(step string) {
+		for _, existing := range steps {
+			if existing == step {
+				return
+			}
+		}
+		steps = append(steps, step)
+	}
+
+	if task := findTaskByID(e, id); task != nil {
+		switch task.Status {
+		case epic.StatusCompleted:
+		case epic.StatusCancelled:
+			add(fmt.Sprintf("drop the cancelled task %s from requires", id))
+		case epic.StatusWIP:
+			add("agentpm done task " + id)
+		default:
+			add("agentpm start task " + id)
+			add("agentpm done task " + id)
+		}
+		return steps
+	}
+
+	test := s.findTestByID(e, id)
+	if test == nil || s.testDone(test) {
+		return steps
+	}
+	if s.getTestStatus(test) == epic.TestStatusCancelled {
+		add(fmt.Sprintf("drop the cancelled test %s from requires", id))
+		return steps
+	}
+
+	for _, requiredID := range test.RequiredIDs() {
+		steps = s.appendPrerequisiteSteps(e, requiredID, steps)
+	}
+	if task := findTaskByID(e, test.TaskID); task != nil && task.Status != epic.StatusWIP && task.Status != epic.StatusCompleted {
+		add("agentpm start task " + task.ID)
+	}
+	if s.getTestStatus(test) == epic.TestStatusPending {
+		add("agentpm start test " + id)
+	}
+	add("agentpm pass " + id)
+	return steps
+}
+
+// requiresCycle returns the path of a requires cycle reachable from test id, or nil
+func (s *TestService) requiresCycle(e *epic.Epic, id string, path []string) []string {
+	for i, visited := range path {
+		if visited == id {
+			return append(append([]string(nil), path[i:]...), id)
+		}
+	}
+
+	test := s.findTestByID(e, id)
+	if test == nil {
+		return nil
+	}
+	path = append(path, id)
+	for _, requiredID := range test.RequiredIDs() {
+		if cycle := s.requiresCycle(e, requiredID, path); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// testDone reports whether a required test is satisfied: done and passing
+func (s *TestService) testDone(test *epic.Test) bool {
+	return s.getTestStatus(test) == epic.TestStatusDone && test.GetTestResult() != epic.TestResultFailing
+}
+
+// describeTest returns the state of a test for error messages, failing included
+func (s *TestService) describeTest(test *epic.Test) string {
+	status := s.getTestStatus(test)
+	if test.GetTestResult() == epic.TestResultFailing {
+		return string(status) + " (failing)"
+	}
+	return string(status)
+}
+
+func (s *TestService) findTestByID(e *epic.Epic, id string) *epic.Test {
+	for i := range e.Tests {
+		if e.Tests[i].ID == id {
+			return &e.Tests[i]
+		}
+	}
+	return nil
+}
+
+func findTaskByID(e *epic.Epic, id string) *epic.Task {
+	for i := range e.Tasks {
+		if e.Tasks[i].ID == id {
+			return &e.Tasks[i]
+		}
+	}
+	return nil
+}
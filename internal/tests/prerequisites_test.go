@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+func createRequiresEpic() *epic.Epic {
+	failedAt := time.Date(2025, 8, 16, 12, 0, 0, 0, time.UTC)
+	e := createTestEpic()
+	e.Phases = []epic.Phase{{ID: "P1", Status: epic.StatusWIP}}
+	e.Tasks = []epic.Task{
+		{ID: "T1", PhaseID: "P1", Status: epic.StatusCompleted},
+		{ID: "T2", PhaseID: "P1", Status: epic.StatusPending},
+		{ID: "T3", PhaseID: "P1", Status: epic.StatusWIP},
+	}
+	e.Tests = []epic.Test{
+		{ID: "T1_1", TaskID: "T1", Status: epic.StatusCompleted, TestStatus: epic.TestStatusDone},
+		{ID: "T1_2", TaskID: "T1", Status: epic.StatusWIP, TestStatus: epic.TestStatusWIP, FailedAt: &failedAt},
+		{ID: "T2_1", TaskID: "T2", Status: epic.StatusPending, TestStatus: epic.TestStatusPending, Requires: "T1_2"},
+		{ID: "T3_1", TaskID: "T3", Status: epic.StatusPending, TestStatus: epic.TestStatusPending, Requires: "T1_1, T1"},
+		{ID: "T3_2", TaskID: "T3", Status: epic.StatusPending, TestStatus: epic.TestStatusPending, Requires: "T2_1,T2"},
+	}
+	return e
+}
+
+func TestStartTest_Requires(t *testing.T) {
+	t.Run("satisfied requires allow the start", func(t *testing.T) {
+		service, epicFile := setupTestService(t)
+		if err := service.storage.SaveEpic(createRequiresEpic(), epicFile); err != nil {
+			t.Fatalf("Failed to save test epic: %v", err)
+		}
+
+		if _, err := service.StartTest(epicFile, "T3_1", nil); err != nil {
+			t.Fatalf("StartTest failed: %v", err)
+		}
+	})
+
+	t.Run("unmet requires name each prerequisite and the steps in order", func(t *testing.T) {
+		service, epicFile := setupTestService(t)
+		if err := service.storage.SaveEpic(createRequiresEpic(), epicFile); err != nil {
+			t.Fatalf("Failed to save test epic: %v", err)
+		}
+
+		_, err := service.StartTest(epicFile, "T3_2", nil)
+		if !IsPrerequisite(err) {
+			t.Fatalf("Expected prerequisite error, got %v", err)
+		}
+		testErr := err.(*TestError)
+
+		expectedMessage := "Cannot start test T3_2: prerequisites not done: test T2_1 is pending, task T2 is pending"
+		if testErr.Message != expectedMessage {
+			t.Errorf("Expected message %q, got %q", expectedMessage, testErr.Message)
+		}
+		expectedHint := "Run in order: agentpm pass T1_2 -> agentpm start task T2 -> agentpm start test T2_1 -> agentpm pass T2_1 -> agentpm done task T2"
+		if testErr.Hint != expectedHint {
+			t.Errorf("Expected hint %q, got %q", expectedHint, testErr.Hint)
+		}
+
+		updatedEpic, _ := service.storage.LoadEpic(epicFile)
+		if updatedEpic.Tests[4].TestStatus != epic.TestStatusPending {
+			t.Errorf("Expected T3_2 to stay pending, got %s", updatedEpic.Tests[4].TestStatus)
+		}
+	})
+
+	t.Run("failing required tests are not done", func(t *testing.T) {
+		service, epicFile := setupTestService(t)
+		e := createRequiresEpic()
+		e.Tasks[1].Status = epic.StatusWIP
+		e.Tasks[2].Status = epic.StatusCompleted
+		if err := service.storage.SaveEpic(e, epicFile); err != nil {
+			t.Fatalf("Failed to save test epic: %v", err)
+		}
+
+		_, err := service.StartTest(epicFile, "T2_1", nil)
+		if !IsPrerequisite(err) {
+			t.Fatalf("Expected prerequisite error, got %v", err)
+		}
+		if err.Error() != "Cannot start test T2_1: prerequisites not done: test T1_2 is wip (failing)" {
+			t.Errorf("Unexpected message: %s", err.Error())
+		}
+	})
+
+	t.Run("unknown references and cycles", func(t *testing.T) {
+		service, epicFile := setupTestService(t)
+		e := createRequiresEpic()
+		e.Tests[3].Requires = "T9"
+		e.Tests[4].Requires = "T3_2"
+		if err := service.storage.SaveEpic(e, epicFile); err != nil {
+			t.Fatalf("Failed to save test epic: %v", err)
+		}
+
+		_, err := service.StartTest(epicFile, "T3_1", nil)
+		if err == nil || err.Error() != "Cannot start test T3_1: required T9 is neither a test nor a task of this epic" {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		_, err = service.StartTest(epicFile, "T3_2", nil)
+		if err == nil || err.Error() != "Cannot start test T3_2: its requires form a cycle (T3_2 -> T3_2)" {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+}
+
+func TestStartNextTest_SkipsUnmetRequires(t *testing.T) {
+	service, epicFile := setupTestService(t)
+	e := createRequiresEpic()
+	e.Tests[3].Requires = "T2"
+	if err := service.storage.SaveEpic(e, epicFile); err != nil {
+		t.Fatalf("Failed to save test epic: %v", err)
+	}
+
+	_, err := service.StartNextTest(epicFile, nil)
+	if !IsPrerequisite(err) {
+		t.Fatalf("Expected prerequisite error when every pending test is blocked, got %v", err)
+	}
+
+	// T3_2 comes first now but still waits for T2_1
+	e.Tests[3].Requires = "T1"
+	e.Tests[3], e.Tests[4] = e.Tests[4], e.Tests[3]
+	if err := service.storage.SaveEpic(e, epicFile); err != nil {
+		t.Fatalf("Failed to save test epic: %v", err)
+	}
+
+	selection, err := service.StartNextTest(epicFile, nil)
+	if err != nil {
+		t.Fatalf("StartNextTest failed: %v", err)
+	}
+	if selection.TestID != "T3_1" {
+		t.Errorf("Expected the blocked T3_2 to be skipped for T3_1, got %s", selection.TestID)
+	}
+}
@@ -42,11 +42,17 @@ func (s *TestService) StartTest(epicFile, testID string, timestamp *time.Time) (
 	if err != nil {
 		return nil, err
 	}
+	if err := s.ensureMutable(e, testID, "start test "+testID); err != nil {
+		return nil, err
+	}
 
 	test, err := s.findTest(e, testID)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.ensurePhaseMutable(e, test, "start test "+testID); err != nil {
+		return nil, err
+	}
 
 	// Get current test status (prefer TestStatus, fallback to Status conversion)
 	currentTestStatus := s.getTestStatus(test)
@@ -72,30 +78,18 @@ func (s *TestService) StartTest(epicFile, testID string, timestamp *time.Time) (
 		}
 	}
 
-	// Validate prerequisite: associated task/phase must be active or completed
+	// Validate prerequisites: associated task/phase must be active or completed,
+	// required tests and tasks done
 	if err := s.validateTestPrerequisites(e, test); err != nil {
 		return nil, err
 	}
 
-	// Update test status and timestamp
-	s.setTestStatus(test, epic.TestStatusWIP)
-	if timestamp == nil {
-		now := s.timeSource()
-		timestamp = &now
-	}
-	test.StartedAt = timestamp
-
-	// Create event for test start
-	service.CreateEvent(e, service.EventTestStarted, test.PhaseID, test.TaskID, testID, "", *timestamp)
+	// Update test status, timestamp and event log
+	timestamp = s.applyTestStart(e, test, timestamp)
 
 	// Save epic
 	if err := s.storage.SaveEpic(e, epicFile); err != nil {
-		return nil, &TestError{
-			Type:    ErrorTypeIO,
-			TestID:  testID,
-			Message: fmt.Sprintf("Failed to save epic: %v", err),
-			Cause:   err,
-		}
+		return nil, saveError(err, testID)
 	}
 
 	return &TestOperation{
@@ -106,17 +100,106 @@ func (s *TestService) StartTest(epicFile, testID string, timestamp *time.Time) (
 	}, nil
 }
 
+// StartNextTest picks the next pending test of the active task, starts it and
+// returns its details. When the active task has no pending tests left, the
+// returned selection has Started set to false and carries an explanatory message.
+func (s *TestService) StartNextTest(epicFile string, timestamp *time.Time) (*NextTestSelection, error) {
+	e, err := s.loadAndValidateEpic(epicFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureMutable(e, "", "start next test"); err != nil {
+		return nil, err
+	}
+
+	activeTask := e.ActiveTask("")
+	if activeTask == nil {
+		return nil, &TestError{
+			Type:    ErrorTypeValidation,
+			Message: "Cannot start next test: no task is active (start a task first)",
+		}
+	}
+
+	// Tests waiting for their required tests or tasks are skipped
+	var blocked error
+	for i := range e.Tests {
+		test := &e.Tests[i]
+		if test.TaskID != activeTask.ID || s.getTestStatus(test) != epic.TestStatusPending {
+			continue
+		}
+
+		if err := s.ensurePhaseMutable(e, test, "start test "+test.ID); err != nil {
+			return nil, err
+		}
+		if err := s.validateTestPrerequisites(e, test); err != nil {
+			if IsPrerequisite(err) {
+				if blocked == nil {
+					blocked = err
+				}
+				continue
+			}
+			return nil, err
+		}
+
+		timestamp = s.applyTestStart(e, test, timestamp)
+
+		if err := s.storage.SaveEpic(e, epicFile); err != nil {
+			return nil, saveError(err, test.ID)
+		}
+
+		return &NextTestSelection{
+			Started:     true,
+			TestID:      test.ID,
+			TaskID:      test.TaskID,
+			PhaseID:     activeTask.PhaseID,
+			Name:        test.Name,
+			Description: test.Description,
+			StartedAt:   timestamp,
+			Message:     fmt.Sprintf("Started test %s for task %s", test.ID, activeTask.ID),
+		}, nil
+	}
+
+	if blocked != nil {
+		return nil, blocked
+	}
+
+	return &NextTestSelection{
+		TaskID:  activeTask.ID,
+		PhaseID: activeTask.PhaseID,
+		Message: fmt.Sprintf("No pending tests left for task %s", activeTask.ID),
+	}, nil
+}
+
 // PassTest transitions a test from wip to passed status
-func (s *TestService) PassTest(epicFile, testID string, timestamp *time.Time) (*TestOperation, error) {
+func (s *TestService) PassTest(epicFile, testID string, verification Verification, timestamp *time.Time) (*TestOperation, error) {
 	e, err := s.loadAndValidateEpic(epicFile)
 	if err != nil {
 		return nil, err
 	}
+	operation, err := s.ApplyPass(e, testID, verification, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.saveEpic(e, epicFile, testID); err != nil {
+		return nil, err
+	}
+	return operation, nil
+}
+
+// ApplyPass passes a test of an already loaded epic without saving it, so
+// that several tests can be passed in one load/save cycle
+func (s *TestService) ApplyPass(e *epic.Epic, testID string, verification Verification, timestamp *time.Time) (*TestOperation, error) {
+	if err := s.ensureMutable(e, testID, "pass test "+testID); err != nil {
+		return nil, err
+	}
 
 	test, err := s.findTest(e, testID)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.ensurePhaseMutable(e, test, "pass test "+testID); err != nil {
+		return nil, err
+	}
 
 	// Get current test status
 	currentTestStatus := s.getTestStatus(test)
@@ -131,6 +214,9 @@ func (s *TestService) PassTest(epicFile, testID string, timestamp *time.Time) (*
 			Message: fmt.Sprintf("Cannot pass test %s: test is not currently in progress", testID),
 		}
 	}
+	if err := verification.check(test, "pass"); err != nil {
+		return nil, err
+	}
 
 	// Update test status and result
 	s.setTestStatus(test, epic.TestStatusDone)
@@ -140,22 +226,15 @@ func (s *TestService) PassTest(epicFile, testID string, timestamp *time.Time) (*
 		timestamp = &now
 	}
 	test.PassedAt = timestamp
-	// Clear any previous failure note
+	test.PassCount++
+	// Clear any previous failure note and context
 	test.FailureNote = ""
+	test.FailureContext = nil
+	verification.record(test)
 
 	// Create event for test pass
 	service.CreateEvent(e, service.EventTestPassed, test.PhaseID, test.TaskID, testID, "", *timestamp)
 
-	// Save epic
-	if err := s.storage.SaveEpic(e, epicFile); err != nil {
-		return nil, &TestError{
-			Type:    ErrorTypeIO,
-			TestID:  testID,
-			Message: fmt.Sprintf("Failed to save epic: %v", err),
-			Cause:   err,
-		}
-	}
-
 	return &TestOperation{
 		TestID:    testID,
 		Operation: "passed",
@@ -164,17 +243,37 @@ func (s *TestService) PassTest(epicFile, testID string, timestamp *time.Time) (*
 	}, nil
 }
 
-// FailTest transitions a test from wip to failed status with failure details
-func (s *TestService) FailTest(epicFile, testID, failureReason string, timestamp *time.Time) (*TestOperation, error) {
+// FailTest transitions a test from wip to failed status with failure details.
+// context holds optional structured detail on the failure and may be nil.
+func (s *TestService) FailTest(epicFile, testID, failureReason string, context *epic.FailureContext, verification Verification, timestamp *time.Time) (*TestOperation, error) {
 	e, err := s.loadAndValidateEpic(epicFile)
 	if err != nil {
 		return nil, err
 	}
+	operation, err := s.ApplyFail(e, testID, failureReason, context, verification, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.saveEpic(e, epicFile, testID); err != nil {
+		return nil, err
+	}
+	return operation, nil
+}
+
+// ApplyFail fails a test of an already loaded epic without saving it, so
+// that several tests can be failed in one load/save cycle
+func (s *TestService) ApplyFail(e *epic.Epic, testID, failureReason string, context *epic.FailureContext, verification Verification, timestamp *time.Time) (*TestOperation, error) {
+	if err := s.ensureMutable(e, testID, "fail test "+testID); err != nil {
+		return nil, err
+	}
 
 	test, err := s.findTest(e, testID)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.ensurePhaseMutable(e, test, "fail test "+testID); err != nil {
+		return nil, err
+	}
 
 	// Get current test status
 	currentTestStatus := s.getTestStatus(test)
@@ -189,6 +288,9 @@ func (s *TestService) FailTest(epicFile, testID, failureReason string, timestamp
 			Message: fmt.Sprintf("Cannot fail test %s: test must be in progress (wip) or done to be failed", testID),
 		}
 	}
+	if err := verification.check(test, "fail"); err != nil {
+		return nil, err
+	}
 
 	// Update test status and result
 	s.setTestStatus(test, epic.TestStatusWIP)
@@ -198,21 +300,17 @@ func (s *TestService) FailTest(epicFile, testID, failureReason string, timestamp
 		timestamp = &now
 	}
 	test.FailedAt = timestamp
+	test.FailCount++
 	test.FailureNote = failureReason
+	test.FailureContext = nil
+	if !context.IsEmpty() {
+		test.FailureContext = context
+	}
+	verification.record(test)
 
 	// Create event for test failure
 	service.CreateEvent(e, service.EventTestFailed, test.PhaseID, test.TaskID, testID, failureReason, *timestamp)
 
-	// Save epic
-	if err := s.storage.SaveEpic(e, epicFile); err != nil {
-		return nil, &TestError{
-			Type:    ErrorTypeIO,
-			TestID:  testID,
-			Message: fmt.Sprintf("Failed to save epic: %v", err),
-			Cause:   err,
-		}
-	}
-
 	return &TestOperation{
 		TestID:        testID,
 		Operation:     "failed",
@@ -228,11 +326,17 @@ func (s *TestService) CancelTest(epicFile, testID, cancellationReason string, ti
 	if err != nil {
 		return nil, err
 	}
+	if err := s.ensureMutable(e, testID, "cancel test "+testID); err != nil {
+		return nil, err
+	}
 
 	test, err := s.findTest(e, testID)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.ensurePhaseMutable(e, test, "cancel test "+testID); err != nil {
+		return nil, err
+	}
 
 	// Get current test status
 	currentTestStatus := s.getTestStatus(test)
@@ -262,12 +366,7 @@ func (s *TestService) CancelTest(epicFile, testID, cancellationReason string, ti
 
 	// Save epic
 	if err := s.storage.SaveEpic(e, epicFile); err != nil {
-		return nil, &TestError{
-			Type:    ErrorTypeIO,
-			TestID:  testID,
-			Message: fmt.Sprintf("Failed to save epic: %v", err),
-			Cause:   err,
-		}
+		return nil, saveError(err, testID)
 	}
 
 	return &TestOperation{
@@ -301,6 +400,64 @@ func (s *TestService) loadAndValidateEpic(epicFile string) (*epic.Epic, error) {
 	return e, nil
 }
 
+// saveEpic saves the epic, wrapping a failure into a TestError
+func (s *TestService) saveEpic(e *epic.Epic, epicFile, testID string) error {
+	if err := s.storage.SaveEpic(e, epicFile); err != nil {
+		return saveError(err, testID)
+	}
+	return nil
+}
+
+// saveError wraps a failed save into a TestError; a save rejected because the
+// epic is completed keeps its own type and message
+func saveError(err error, testID string) *TestError {
+	if epic.IsEpicCompleted(err) {
+		return &TestError{Type: ErrorTypeEpicCompleted, TestID: testID, Message: err.Error(), Cause: err}
+	}
+	return &TestError{
+		Type:    ErrorTypeIO,
+		TestID:  testID,
+		Message: fmt.Sprintf("Failed to save epic: %v", err),
+		Cause:   err,
+	}
+}
+
+// ensureMutable wraps the shared completed-epic guard into a TestError
+func (s *TestService) ensureMutable(e *epic.Epic, testID, operation string) error {
+	if err := e.EnsureMutable(operation); err != nil {
+		return &TestError{
+			Type:    ErrorTypeEpicCompleted,
+			TestID:  testID,
+			Message: err.Error(),
+			Cause:   err,
+		}
+	}
+	return nil
+}
+
+// ensurePhaseMutable wraps the frozen-phase guard for the phase a test belongs to into a TestError
+func (s *TestService) ensurePhaseMutable(e *epic.Epic, test *epic.Test, operation string) error {
+	phaseID := test.PhaseID
+	if phaseID == "" {
+		for _, task := range e.Tasks {
+			if task.ID == test.TaskID {
+				phaseID = task.PhaseID
+				break
+			}
+		}
+	}
+
+	if err := e.EnsurePhaseMutable(phaseID, operation); err != nil {
+		return &TestError{
+			Type:    ErrorTypePhaseFrozen,
+			TestID:  test.ID,
+			Message: err.Error(),
+			Cause:   err,
+		}
+	}
+	return nil
+}
+
 func (s *TestService) findTest(e *epic.Epic, testID string) (*epic.Test, error) {
 	for i := range e.Tests {
 		if e.Tests[i].ID == testID {
@@ -353,6 +510,20 @@ func (s *TestService) setTestStatus(test *epic.Test, status epic.TestStatus) {
 	}
 }
 
+// applyTestStart moves a test to wip, stamps started_at and records the event.
+// It returns the timestamp that was used.
+func (s *TestService) applyTestStart(e *epic.Epic, test *epic.Test, timestamp *time.Time) *time.Time {
+	s.setTestStatus(test, epic.TestStatusWIP)
+	if timestamp == nil {
+		now := s.timeSource()
+		timestamp = &now
+	}
+	test.StartedAt = timestamp
+
+	service.CreateEvent(e, service.EventTestStarted, test.PhaseID, test.TaskID, test.ID, "", *timestamp)
+	return timestamp
+}
+
 func (s *TestService) validateTestPrerequisites(e *epic.Epic, test *epic.Test) error {
 	// Check if associated task is active or completed
 	if test.TaskID != "" {
@@ -386,7 +557,7 @@ func (s *TestService) validateTestPrerequisites(e *epic.Epic, test *epic.Test) e
 		}
 	}
 
-	return nil
+	return s.validateRequiredPrerequisites(e, test)
 }
 
 // Result types for test operations
@@ -399,6 +570,18 @@ type TestOperation struct {
 	CancellationReason string    `json:"cancellation_reason,omitempty"`
 }
 
+// NextTestSelection describes the outcome of StartNextTest
+type NextTestSelection struct {
+	Started     bool       `json:"started"`
+	TestID      string     `json:"test_id,omitempty"`
+	TaskID      string     `json:"task_id"`
+	PhaseID     string     `json:"phase_id"`
+	Name        string     `json:"name,omitempty"`
+	Description string     `json:"description,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	Message     string     `json:"message"`
+}
+
 // Error types and handling
 type ErrorType string
 
@@ -407,6 +590,9 @@ const (
 	ErrorTypeValidation        ErrorType = "validation"
 	ErrorTypeIO                ErrorType = "io"
 	ErrorTypeInvalidTransition ErrorType = "invalid_transition"
+	ErrorTypeEpicCompleted     ErrorType = "epic_completed"
+	ErrorTypePhaseFrozen       ErrorType = "phase_frozen"
+	ErrorTypePrerequisite      ErrorType = "prerequisite"
 )
 
 type TestError struct {
@@ -415,6 +601,7 @@ type TestError struct {
 	Current string    `json:"current_status,omitempty"`
 	Target  string    `json:"target_status,omitempty"`
 	Message string    `json:"message"`
+	Hint    string    `json:"hint,omitempty"`
 	Cause   error     `json:"-"`
 }
 
@@ -441,6 +628,13 @@ func IsValidation(err error) bool {
 	return false
 }
 
+func IsPrerequisite(err error) bool {
+	if te, ok := err.(*TestError); ok {
+		return te.Type == ErrorTypePrerequisite
+	}
+	return false
+}
+
 func IsInvalidTransition(err error) bool {
 	if te, ok := err.(*TestError); ok {
 		return te.Type == ErrorTypeInvalidTransition
@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// Verification names the human who checked a test and, optionally, where the
+// evidence is. Manual tests (type="manual") cannot pass or fail without it.
+type Verification struct {
+	By       string
+	Evidence string // Link to a screenshot, recording or ticket
+}
+
+// check refuses a verdict on a manual test without a verifier
+func (v Verification) check(test *epic.Test, operation string) error {
+	if strings.TrimSpace(v.By) != "" {
+		return nil
+	}
+	if v.Evidence != "" {
+		return &TestError{
+			Type:    ErrorTypeValidation,
+			TestID:  test.ID,
+			Message: fmt.Sprintf("Cannot %s test %s: evidence needs the name of the verifier", operation, test.ID),
+			Hint:    "Add --verified-by <name>",
+		}
+	}
+	if test.IsManual() {
+		return &TestError{
+			Type:    ErrorTypeValidation,
+			TestID:  test.ID,
+			Message: fmt.Sprintf("Cannot %s test %s: it is a manual test and needs the name of the person who checked it", operation, test.ID),
+			Hint:    fmt.Sprintf("agentpm %s %s --verified-by <name> [--evidence <link>]", operation, test.ID),
+		}
+	}
+	return nil
+}
+
+// record stores the verifier and evidence of a verdict on the test, replacing
+// those of an earlier verdict
+func (v Verification) record(test *epic.Test) {
+	test.VerifiedBy = strings.TrimSpace(v.By)
+	test.Evidence = strings.TrimSpace(v.Evidence)
+}
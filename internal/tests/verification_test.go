@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+func createManualTestEpic(t *testing.T, service *TestService, epicFile string) {
+	t.Helper()
+	e := createTestEpic()
+	e.Phases = []epic.Phase{{ID: "phase_1", Status: epic.StatusWIP}}
+	e.Tasks = []epic.Task{{ID: "task_1", PhaseID: "phase_1", Status: epic.StatusWIP}}
+	e.Tests = []epic.Test{
+		{ID: "manual_1", TaskID: "task_1", PhaseID: "phase_1", Type: epic.TestTypeManual, Status: epic.StatusWIP, TestStatus: epic.TestStatusWIP},
+		{ID: "auto_1", TaskID: "task_1", PhaseID: "phase_1", Status: epic.StatusWIP, TestStatus: epic.TestStatusWIP},
+	}
+	if err := service.storage.SaveEpic(e, epicFile); err != nil {
+		t.Fatalf("Failed to save test epic: %v", err)
+	}
+}
+
+func findSavedTest(t *testing.T, service *TestService, epicFile, testID string) epic.Test {
+	t.Helper()
+	e, err := service.storage.LoadEpic(epicFile)
+	if err != nil {
+		t.Fatalf("Failed to load epic: %v", err)
+	}
+	for _, test := range e.Tests {
+		if test.ID == testID {
+			return test
+		}
+	}
+	t.Fatalf("Test %s not found", testID)
+	return epic.Test{}
+}
+
+func TestManualTestRequiresVerifier(t *testing.T) {
+	service, epicFile := setupTestService(t)
+	createManualTestEpic(t, service, epicFile)
+
+	_, err := service.PassTest(epicFile, "manual_1", Verification{}, nil)
+	testErr, ok := err.(*TestError)
+	if !ok {
+		t.Fatalf("Expected TestError, got %v", err)
+	}
+	if testErr.Type != ErrorTypeValidation || !strings.Contains(testErr.Message, "manual test") {
+		t.Errorf("Unexpected error: %s %s", testErr.Type, testErr.Message)
+	}
+	if !strings.Contains(testErr.Hint, "--verified-by") {
+		t.Errorf("Expected hint to name --verified-by, got %q", testErr.Hint)
+	}
+
+	_, err = service.FailTest(epicFile, "manual_1", "Layout broken", nil, Verification{}, nil)
+	if err == nil {
+		t.Fatal("Expected failing a manual test without verifier to be refused")
+	}
+
+	if test := findSavedTest(t, service, epicFile, "manual_1"); test.GetTestStatusUnified() != epic.TestStatusWIP {
+		t.Errorf("Expected refused test to stay wip, got %s", test.GetTestStatusUnified())
+	}
+
+	// Agent-executable tests pass without a verifier
+	if _, err := service.PassTest(epicFile, "auto_1", Verification{}, nil); err != nil {
+		t.Fatalf("Expected auto_1 to pass: %v", err)
+	}
+}
+
+func TestManualTestRecordsVerification(t *testing.T) {
+	service, epicFile := setupTestService(t)
+	createManualTestEpic(t, service, epicFile)
+
+	_, err := service.FailTest(epicFile, "manual_1", "Logo cut off", nil, Verification{By: "bob"}, nil)
+	if err != nil {
+		t.Fatalf("Expected fail to succeed: %v", err)
+	}
+	if test := findSavedTest(t, service, epicFile, "manual_1"); test.VerifiedBy != "bob" || test.Evidence != "" {
+		t.Errorf("Expected bob without evidence, got %q %q", test.VerifiedBy, test.Evidence)
+	}
+
+	verification := Verification{By: "alice", Evidence: "https://example.com/shot.png"}
+	if _, err := service.PassTest(epicFile, "manual_1", verification, nil); err != nil {
+		t.Fatalf("Expected pass to succeed: %v", err)
+	}
+	test := findSavedTest(t, service, epicFile, "manual_1")
+	if test.VerifiedBy != "alice" || test.Evidence != "https://example.com/shot.png" {
+		t.Errorf("Expected alice with evidence, got %q %q", test.VerifiedBy, test.Evidence)
+	}
+
+	e, _ := service.storage.LoadEpic(epicFile)
+	last := e.Events[len(e.Events)-1]
+	if last.Type != "test_passed" || last.Data != "Test manual_1 passed (verified by alice, evidence: https://example.com/shot.png)" {
+		t.Errorf("Unexpected pass event: %s %q", last.Type, last.Data)
+	}
+}
+
+func TestEvidenceRequiresVerifier(t *testing.T) {
+	service, epicFile := setupTestService(t)
+	createManualTestEpic(t, service, epicFile)
+
+	_, err := service.PassTest(epicFile, "auto_1", Verification{Evidence: "https://example.com/run/1"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "evidence needs the name of the verifier") {
+		t.Fatalf("Expected evidence without verifier to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package throughput
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/service"
+)
+
+// Trends of the tests of an epic over a reporting window
+const (
+	TrendConverging = "converging" // more tests passing at the end of the window
+	TrendRegressing = "regressing" // fewer tests passing at the end of the window
+	TrendThrashing  = "thrashing"  // tests passed and failed, but no more of them pass
+	TrendIdle       = "idle"       // no test passed or failed
+)
+
+// Day counts the test results recorded on one calendar day (UTC)
+type Day struct {
+	Date         string `json:"date"`
+	Passed       int    `json:"passed"`        // test_passed events
+	Failed       int    `json:"failed"`        // test_failed events
+	Fixed        int    `json:"fixed"`         // failing tests that passed
+	NewlyFailing int    `json:"newly_failing"` // tests that failed without failing before
+	Net          int    `json:"net"`           // change in the number of passing tests
+}
+
+// Report is the daily test throughput of an epic, derived from its test events
+type Report struct {
+	Epic         string `json:"epic"`
+	Days         []Day  `json:"days"`
+	Passed       int    `json:"passed"`
+	Failed       int    `json:"failed"`
+	Fixed        int    `json:"fixed"`
+	NewlyFailing int    `json:"newly_failing"`
+	Net          int    `json:"net"`
+	Trend        string `json:"trend"`
+}
+
+// test results a test can be in while replaying its events
+const (
+	resultNone = iota
+	resultPassing
+	resultFailing
+)
+
+// Build replays the test_passed, test_failed, test_reset and test_cancelled
+// events of the epic and counts them per day over the last `days` days up to
+// now. A test is fixed when it passes after failing, and newly failing when
+// it fails after passing or without a result. Resetting or cancelling a
+// passing test takes it out of the passing ones.
+func Build(e *epic.Epic, now time.Time, days int) *Report {
+	days = max(days, 1)
+	report := &Report{Epic: e.ID}
+
+	today := now.UTC().Truncate(24 * time.Hour)
+	first := today.AddDate(0, 0, -(days - 1))
+	index := make(map[string]int)
+	for i := 0; i < days; i++ {
+		date := first.AddDate(0, 0, i).Format(time.DateOnly)
+		index[date] = i
+		report.Days = append(report.Days, Day{Date: date})
+	}
+
+	events := make([]epic.Event, len(e.Events))
+	copy(events, e.Events)
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Timestamp.Before(events[j].Timestamp) })
+
+	results := make(map[string]int)
+	for _, event := range events {
+		eventType := service.EventType(event.Type)
+		switch eventType {
+		case service.EventTestPassed, service.EventTestFailed, service.EventTestReset, service.EventTestCancelled:
+		default:
+			continue
+		}
+		id := service.EventEntityID(event)
+		if id == "" {
+			continue
+		}
+
+		// Earlier events only set the result a test starts the window with
+		var day Day
+		previous := results[id]
+		switch eventType {
+		case service.EventTestPassed:
+			day.Passed++
+			if previous == resultFailing {
+				day.Fixed++
+			}
+			if previous != resultPassing {
+				day.Net++
+			}
+			results[id] = resultPassing
+		case service.EventTestFailed:
+			day.Failed++
+			if previous != resultFailing {
+				day.NewlyFailing++
+			}
+			if previous == resultPassing {
+				day.Net--
+			}
+			results[id] = resultFailing
+		default:
+			if previous == resultPassing {
+				day.Net--
+			}
+			results[id] = resultNone
+		}
+
+		i, ok := index[event.Timestamp.UTC().Format(time.DateOnly)]
+		if !ok || event.Timestamp.After(now) {
+			continue
+		}
+		report.Days[i].add(day)
+	}
+
+	for _, day := range report.Days {
+		report.Passed += day.Passed
+		report.Failed += day.Failed
+		report.Fixed += day.Fixed
+		report.NewlyFailing += day.NewlyFailing
+		report.Net += day.Net
+	}
+	report.Trend = trend(report)
+	return report
+}
+
+func (d *Day) add(other Day) {
+	d.Passed += other.Passed
+	d.Failed += other.Failed
+	d.Fixed += other.Fixed
+	d.NewlyFailing += other.NewlyFailing
+	d.Net += other.Net
+}
+
+func trend(report *Report) string {
+	switch {
+	case report.Net > 0:
+		return TrendConverging
+	case report.Net < 0:
+		return TrendRegressing
+	case report.Passed+report.Failed > 0:
+		return TrendThrashing
+	default:
+		return TrendIdle
+	}
+}
@@ -0,0 +1,75 @@
+package throughput
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// at returns a time on the given day of August 2025
+func at(day, hour int) time.Time {
+	return time.Date(2025, 8, day, hour, 0, 0, 0, time.UTC)
+}
+
+func createThroughputEpic() *epic.Epic {
+	return &epic.Epic{
+		ID: "epic-1",
+		Tests: []epic.Test{
+			{ID: "T1", TaskID: "1A_1", PhaseID: "1A", Name: "First"},
+			{ID: "T2", TaskID: "1A_1", PhaseID: "1A", Name: "Second"},
+			{ID: "T3", TaskID: "1A_1", PhaseID: "1A", Name: "Third"},
+		},
+	}
+}
+
+func record(e *epic.Epic, eventType service.EventType, testID string, ts time.Time) {
+	service.CreateEvent(e, eventType, "1A", "1A_1", testID, "broken", ts)
+}
+
+func TestBuild(t *testing.T) {
+	t.Run("counts fixed and newly failing tests per day", func(t *testing.T) {
+		e := createThroughputEpic()
+		record(e, service.EventTestFailed, "T1", at(1, 9)) // before the window
+		record(e, service.EventTestPassed, "T2", at(1, 9))
+		record(e, service.EventTestPassed, "T1", at(10, 9))
+		record(e, service.EventTestPassed, "T3", at(10, 10))
+		record(e, service.EventTestFailed, "T2", at(11, 9))
+		record(e, service.EventTestFailed, "T2", at(11, 10))
+		record(e, service.EventTestReset, "T3", at(12, 9))
+
+		report := Build(e, at(12, 18), 3)
+
+		require.Len(t, report.Days, 3)
+		assert.Equal(t, Day{Date: "2025-08-10", Passed: 2, Fixed: 1, Net: 2}, report.Days[0])
+		assert.Equal(t, Day{Date: "2025-08-11", Failed: 2, NewlyFailing: 1, Net: -1}, report.Days[1])
+		assert.Equal(t, Day{Date: "2025-08-12", Net: -1}, report.Days[2])
+		assert.Equal(t, 1, report.Fixed)
+		assert.Equal(t, 1, report.NewlyFailing)
+		assert.Equal(t, 0, report.Net)
+		assert.Equal(t, TrendThrashing, report.Trend)
+	})
+
+	t.Run("replays events in chronological order", func(t *testing.T) {
+		e := createThroughputEpic()
+		record(e, service.EventTestPassed, "T1", at(10, 12))
+		record(e, service.EventTestFailed, "T1", at(10, 9))
+
+		report := Build(e, at(10, 18), 1)
+		assert.Equal(t, Day{Date: "2025-08-10", Passed: 1, Failed: 1, Fixed: 1, NewlyFailing: 1, Net: 1}, report.Days[0])
+		assert.Equal(t, TrendConverging, report.Trend)
+	})
+
+	t.Run("reports the trend", func(t *testing.T) {
+		e := createThroughputEpic()
+		assert.Equal(t, TrendIdle, Build(e, at(10, 18), 7).Trend)
+
+		record(e, service.EventTestPassed, "T1", at(9, 9))
+		record(e, service.EventTestFailed, "T1", at(10, 9))
+		assert.Equal(t, TrendRegressing, Build(e, at(10, 18), 1).Trend)
+		assert.Equal(t, TrendIdle, Build(e, at(8, 18), 7).Trend)
+	})
+}
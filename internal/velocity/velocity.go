@@ -0,0 +1,133 @@
+package velocity
+
+import (
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/burndown"
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/service"
+)
+
+// Trend directions of the recent velocity compared to the window before it
+const (
+	TrendRising  = "rising"
+	TrendFalling = "falling"
+	TrendSteady  = "steady"
+)
+
+// trendThreshold is the relative change below which velocity counts as steady
+const trendThreshold = 0.1
+
+// Week is the number of tasks completed in one calendar week (Monday to Sunday, UTC)
+type Week struct {
+	Start          string         `json:"start"`
+	Tasks          int            `json:"tasks"`
+	ByEpic         map[string]int `json:"by_epic,omitempty"`
+	RollingAverage float64        `json:"rolling_average"`
+}
+
+// Report is the weekly velocity across a set of epics
+type Report struct {
+	Epics   int     `json:"epics"`
+	Window  int     `json:"window"`
+	Weeks   []Week  `json:"weeks"`
+	Average float64 `json:"average"`
+	Trend   string  `json:"trend"`
+}
+
+// Build counts the tasks completed per week over the last `weeks` weeks up to now.
+// Rolling averages span `window` weeks; the trend compares the average of the last
+// window with the window before it.
+func Build(epics []*epic.Epic, now time.Time, weeks, window int) *Report {
+	weeks = max(weeks, 1)
+	window = max(window, 1)
+
+	report := &Report{Epics: len(epics), Window: window}
+
+	current := burndown.IntervalWeek.Start(now)
+	first := current.AddDate(0, 0, -7*(weeks-1))
+	index := make(map[time.Time]int)
+	for i, start := 0, first; i < weeks; i, start = i+1, burndown.IntervalWeek.Next(start) {
+		index[start] = i
+		report.Weeks = append(report.Weeks, Week{Start: start.Format(time.DateOnly)})
+	}
+
+	for _, e := range epics {
+		for _, completedAt := range CompletionTimes(e) {
+			i, ok := index[burndown.IntervalWeek.Start(completedAt)]
+			if !ok || completedAt.After(now) {
+				continue
+			}
+			week := &report.Weeks[i]
+			week.Tasks++
+			if week.ByEpic == nil {
+				week.ByEpic = make(map[string]int)
+			}
+			week.ByEpic[e.ID]++
+		}
+	}
+
+	total := 0
+	for i := range report.Weeks {
+		total += report.Weeks[i].Tasks
+		report.Weeks[i].RollingAverage = average(report.Weeks, i-window+1, i+1)
+	}
+	report.Average = float64(total) / float64(len(report.Weeks))
+	report.Trend = trend(average(report.Weeks, weeks-window, weeks), average(report.Weeks, weeks-2*window, weeks-window))
+
+	return report
+}
+
+// CompletionTimes returns when each completed task of the epic was completed: its
+// completed_at timestamp, or the last task_completed event for it
+func CompletionTimes(e *epic.Epic) []time.Time {
+	lastEvent := make(map[string]time.Time)
+	for _, event := range e.Events {
+		if service.EventType(event.Type) != service.EventTaskCompleted {
+			continue
+		}
+		id := service.EventEntityID(event)
+		if event.Timestamp.After(lastEvent[id]) {
+			lastEvent[id] = event.Timestamp
+		}
+	}
+
+	var times []time.Time
+	for _, task := range e.Tasks {
+		if task.Status != epic.StatusCompleted {
+			continue
+		}
+		if task.CompletedAt != nil {
+			times = append(times, *task.CompletedAt)
+		} else if at, ok := lastEvent[task.ID]; ok {
+			times = append(times, at)
+		}
+	}
+	return times
+}
+
+// average is the mean number of tasks of weeks[from:to], clipped to the available weeks
+func average(weeks []Week, from, to int) float64 {
+	from = max(from, 0)
+	if to <= from {
+		return 0
+	}
+	total := 0
+	for _, week := range weeks[from:to] {
+		total += week.Tasks
+	}
+	return float64(total) / float64(to-from)
+}
+
+func trend(recent, previous float64) string {
+	switch {
+	case previous == 0 && recent == 0:
+		return TrendSteady
+	case previous == 0 || recent > previous*(1+trendThreshold):
+		return TrendRising
+	case recent < previous*(1-trendThreshold):
+		return TrendFalling
+	default:
+		return TrendSteady
+	}
+}
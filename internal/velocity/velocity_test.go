@@ -0,0 +1,93 @@
+package velocity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// at returns a time on the given day of August 2025; the 4th, 11th and 18th are Mondays
+func at(day int) *time.Time {
+	t := time.Date(2025, 8, day, 12, 0, 0, 0, time.UTC)
+	return &t
+}
+
+func completedTask(id string, completedAt *time.Time) epic.Task {
+	return epic.Task{ID: id, PhaseID: "P1", Name: "Task " + id, Status: epic.StatusCompleted, CompletedAt: completedAt}
+}
+
+func TestCompletionTimes(t *testing.T) {
+	e := &epic.Epic{
+		ID: "epic-1",
+		Tasks: []epic.Task{
+			completedTask("T1", at(5)),
+			completedTask("T2", nil),
+			completedTask("T3", nil),
+			{ID: "T4", PhaseID: "P1", Name: "Task T4", Status: epic.StatusCancelled, CancelledAt: at(6)},
+		},
+	}
+	service.CreateEvent(e, service.EventTaskCompleted, "P1", "T2", "", "", *at(6))
+	service.CreateEvent(e, service.EventTaskCompleted, "P1", "T2", "", "", *at(7))
+
+	assert.Equal(t, []time.Time{*at(5), *at(7)}, CompletionTimes(e))
+}
+
+func TestCompletionTimes_ReopenedTask(t *testing.T) {
+	e := &epic.Epic{
+		ID:     "epic-1",
+		Phases: []epic.Phase{{ID: "P1", Name: "Phase 1", Status: epic.StatusWIP}},
+		Tasks:  []epic.Task{completedTask("T1", at(5)), completedTask("T2", at(6))},
+	}
+	service.CreateEvent(e, service.EventTaskCompleted, "P1", "T1", "", "", *at(5))
+	_, err := service.ReopenTask(e, "T1", "missed a case", *at(7))
+	require.NoError(t, err)
+
+	assert.Equal(t, []time.Time{*at(6)}, CompletionTimes(e))
+}
+
+func TestBuild(t *testing.T) {
+	epicA := &epic.Epic{ID: "epic-a", Status: epic.StatusCompleted, Tasks: []epic.Task{
+		completedTask("A1", at(4)),
+		completedTask("A2", at(5)),
+		completedTask("A3", at(13)),
+	}}
+	epicB := &epic.Epic{ID: "epic-b", Status: epic.StatusWIP, Tasks: []epic.Task{
+		completedTask("B1", at(12)),
+		completedTask("B2", at(19)),
+		completedTask("B3", at(20)),
+		completedTask("B4", at(21)),
+		completedTask("B5", at(1)), // before the reported weeks
+		{ID: "B6", Name: "Pending", Status: epic.StatusPending},
+	}}
+
+	report := Build([]*epic.Epic{epicA, epicB}, *at(21), 3, 1)
+
+	assert.Equal(t, 2, report.Epics)
+	require.Len(t, report.Weeks, 3)
+	assert.Equal(t, "2025-08-04", report.Weeks[0].Start)
+	assert.Equal(t, 2, report.Weeks[0].Tasks)
+	assert.Equal(t, map[string]int{"epic-a": 1, "epic-b": 1}, report.Weeks[1].ByEpic)
+	assert.Equal(t, 3, report.Weeks[2].Tasks)
+	assert.InDelta(t, 7.0/3, report.Average, 0.001)
+	assert.Equal(t, TrendRising, report.Trend)
+
+	t.Run("rolling average over the window", func(t *testing.T) {
+		report := Build([]*epic.Epic{epicA, epicB}, *at(21), 3, 2)
+
+		assert.InDelta(t, 2.0, report.Weeks[0].RollingAverage, 0.001)
+		assert.InDelta(t, 2.0, report.Weeks[1].RollingAverage, 0.001)
+		assert.InDelta(t, 2.5, report.Weeks[2].RollingAverage, 0.001)
+	})
+}
+
+func TestTrend(t *testing.T) {
+	assert.Equal(t, TrendSteady, trend(0, 0))
+	assert.Equal(t, TrendRising, trend(1, 0))
+	assert.Equal(t, TrendRising, trend(5, 4))
+	assert.Equal(t, TrendFalling, trend(3, 4))
+	assert.Equal(t, TrendSteady, trend(4.2, 4))
+}
@@ -0,0 +1,90 @@
+package xmlquery
+
+import (
+	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/epic"
+)
+
+// FilterAssignee keeps the matches of the last query that belong to the work
+// of one agent: phases, tasks and tests whose effective assignee is agent,
+// and elements nested in them. Other matches, e.g. events or metadata, are
+// dropped. Assignees are resolved like epic.TaskAssignee does: the task's own,
+// then its phase's, then the epic's.
+func (s *Service) FilterAssignee(result *QueryResult, agent string) {
+	doc := s.engine.GetDocument()
+	if doc == nil || doc.Root() == nil {
+		return
+	}
+	resolver := newAssigneeResolver(doc.Root())
+
+	var kept []*etree.Element
+	for _, elem := range result.Elements {
+		if owner, ok := resolver.owner(elem); ok && epic.SameAssignee(owner, agent) {
+			kept = append(kept, elem)
+		}
+	}
+	result.Elements = kept
+	result.MatchCount = len(kept)
+	if result.IsEmpty() {
+		result.Message = "No elements found matching query"
+	}
+}
+
+// assigneeResolver looks up the effective assignee of epic elements
+type assigneeResolver struct {
+	epicAssignee string
+	phases       map[string]*etree.Element
+	tasks        map[string]*etree.Element
+}
+
+func newAssigneeResolver(root *etree.Element) *assigneeResolver {
+	r := &assigneeResolver{
+		phases: make(map[string]*etree.Element),
+		tasks:  make(map[string]*etree.Element),
+	}
+	if assignee := root.SelectElement("assignee"); assignee != nil {
+		r.epicAssignee = assignee.Text()
+	}
+	for _, phase := range root.FindElements("//phase") {
+		r.phases[phase.SelectAttrValue("id", "")] = phase
+	}
+	for _, task := range root.FindElements("//task") {
+		r.tasks[task.SelectAttrValue("id", "")] = task
+	}
+	return r
+}
+
+// owner returns the assignee of the nearest phase, task or test containing
+// elem (elem included); ok is false for elements outside of them
+func (r *assigneeResolver) owner(elem *etree.Element) (assignee string, ok bool) {
+	for ; elem != nil; elem = elem.Parent() {
+		switch elem.Tag {
+		case "phase":
+			return r.phaseAssignee(elem.SelectAttrValue("id", "")), true
+		case "task":
+			return r.taskAssignee(elem), true
+		case "test":
+			if task, found := r.tasks[elem.SelectAttrValue("task_id", "")]; found {
+				return r.taskAssignee(task), true
+			}
+			return r.phaseAssignee(elem.SelectAttrValue("phase_id", "")), true
+		}
+	}
+	return "", false
+}
+
+func (r *assigneeResolver) taskAssignee(task *etree.Element) string {
+	if assignee := task.SelectAttrValue("assignee", ""); assignee != "" {
+		return assignee
+	}
+	return r.phaseAssignee(task.SelectAttrValue("phase_id", ""))
+}
+
+func (r *assigneeResolver) phaseAssignee(phaseID string) string {
+	if phase, found := r.phases[phaseID]; found {
+		if assignee := phase.SelectAttrValue("assignee", ""); assignee != "" {
+			return assignee
+		}
+	}
+	return r.epicAssignee
+}
@@ -4,18 +4,21 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/beevik/etree"
+	"github.com/mindreframer/agentpm/internal/output"
 )
 
 // OutputFormat represents supported output formats
 type OutputFormat string
 
 const (
-	FormatXML  OutputFormat = "xml"
-	FormatText OutputFormat = "text"
-	FormatJSON OutputFormat = "json"
+	FormatXML   OutputFormat = "xml"
+	FormatText  OutputFormat = "text"
+	FormatJSON  OutputFormat = "json"
+	FormatJSONL OutputFormat = "jsonl"
 )
 
 // Formatter provides output formatting for query results
@@ -32,6 +35,9 @@ type TextFormatter struct{}
 // JSONFormatter formats results as JSON
 type JSONFormatter struct{}
 
+// JSONLFormatter formats results as JSON Lines, one match per line
+type JSONLFormatter struct{}
+
 // NewFormatter creates the appropriate formatter for the given format
 func NewFormatter(format OutputFormat) Formatter {
 	switch format {
@@ -41,6 +47,8 @@ func NewFormatter(format OutputFormat) Formatter {
 		return &TextFormatter{}
 	case FormatJSON:
 		return &JSONFormatter{}
+	case FormatJSONL:
+		return &JSONLFormatter{}
 	default:
 		return &XMLFormatter{} // default to XML
 	}
@@ -335,37 +343,42 @@ func (f *JSONFormatter) formatElementsJSON(result *QueryResult) interface{} {
 	var elements []map[string]interface{}
 
 	for _, elem := range result.Elements {
-		element := map[string]interface{}{
-			"tag":  elem.Tag,
-			"text": elem.Text(),
-		}
+		elements = append(elements, elementJSON(elem))
+	}
 
-		// Add attributes
-		if len(elem.Attr) > 0 {
-			attributes := make(map[string]string)
-			for _, attr := range elem.Attr {
-				attributes[attr.Key] = attr.Value
-			}
-			element["attributes"] = attributes
+	return elements
+}
+
+// elementJSON converts a matched element with its attributes and direct children
+func elementJSON(elem *etree.Element) map[string]interface{} {
+	element := map[string]interface{}{
+		"tag":  elem.Tag,
+		"text": elem.Text(),
+	}
+
+	// Add attributes
+	if len(elem.Attr) > 0 {
+		attributes := make(map[string]string)
+		for _, attr := range elem.Attr {
+			attributes[attr.Key] = attr.Value
 		}
+		element["attributes"] = attributes
+	}
 
-		// Add child elements (simplified)
-		if children := elem.ChildElements(); len(children) > 0 {
-			var childList []map[string]interface{}
-			for _, child := range children {
-				childElement := map[string]interface{}{
-					"tag":  child.Tag,
-					"text": child.Text(),
-				}
-				childList = append(childList, childElement)
+	// Add child elements (simplified)
+	if children := elem.ChildElements(); len(children) > 0 {
+		var childList []map[string]interface{}
+		for _, child := range children {
+			childElement := map[string]interface{}{
+				"tag":  child.Tag,
+				"text": child.Text(),
 			}
-			element["children"] = childList
+			childList = append(childList, childElement)
 		}
-
-		elements = append(elements, element)
+		element["children"] = childList
 	}
 
-	return elements
+	return element
 }
 
 func (f *JSONFormatter) formatAttributesJSON(result *QueryResult) interface{} {
@@ -394,3 +407,42 @@ func (f *JSONFormatter) formatTextNodesJSON(result *QueryResult) interface{} {
 
 	return textNodes
 }
+
+// Format formats the query result as JSON Lines
+func (f *JSONLFormatter) Format(result *QueryResult) (string, error) {
+	var sb strings.Builder
+	if err := WriteJSONL(&sb, result); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// WriteJSONL streams the matches of a query result to w, one JSON object per line.
+// Elements, attributes and text nodes are written in the same shape as in JSON output.
+func WriteJSONL(w io.Writer, result *QueryResult) error {
+	lines := output.NewJSONLWriter(w)
+	formatter := &JSONFormatter{}
+
+	for _, elem := range result.Elements {
+		switch {
+		case formatter.isAttributeQuery(result.Query):
+			for _, attr := range elem.Attr {
+				if err := lines.Write(map[string]string{"name": attr.Key, "value": attr.Value}); err != nil {
+					return err
+				}
+			}
+		case formatter.isTextQuery(result.Query):
+			if text := elem.Text(); text != "" {
+				if err := lines.Write(map[string]string{"text": text}); err != nil {
+					return err
+				}
+			}
+		default:
+			if err := lines.Write(elementJSON(elem)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -446,3 +446,25 @@ func TestFormatterErrorHandling(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestJSONLFormatter_Format(t *testing.T) {
+	t.Run("one element per line", func(t *testing.T) {
+		output, err := NewFormatter(FormatJSONL).Format(createSampleQueryResult())
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSpace(output), "\n")
+		require.Len(t, lines, 2)
+
+		var first map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+		assert.Equal(t, "task", first["tag"])
+		assert.Equal(t, "Create QueryEngine interface", first["text"])
+		assert.Equal(t, map[string]interface{}{"id": "10A_1", "status": "done"}, first["attributes"])
+	})
+
+	t.Run("empty results produce no lines", func(t *testing.T) {
+		output, err := NewFormatter(FormatJSONL).Format(createEmptyQueryResult())
+		require.NoError(t, err)
+		assert.Empty(t, output)
+	})
+}
@@ -3,6 +3,8 @@ package xmlquery
 import (
 	"fmt"
 	"os"
+
+	"github.com/beevik/etree"
 )
 
 // Service provides high-level XML query operations for epic files
@@ -49,6 +51,39 @@ func (s *Service) QueryEpicFile(filePath, xpathExpr string) (*QueryResult, error
 	return result, nil
 }
 
+// SelectEpicElements returns the <tag> elements of the epic file whose id is
+// one of ids, in the order of ids, as the result of query. It serves queries
+// that were resolved against the epic model instead of by XPath.
+func (s *Service) SelectEpicElements(filePath, query, tag string, ids []string) (*QueryResult, error) {
+	result, err := s.QueryEpicFile(filePath, "//"+tag)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*etree.Element, len(result.Elements))
+	for _, elem := range result.Elements {
+		id := elem.SelectAttrValue("id", "")
+		if _, seen := byID[id]; !seen {
+			byID[id] = elem
+		}
+	}
+	var kept []*etree.Element
+	for _, id := range ids {
+		if elem, ok := byID[id]; ok {
+			kept = append(kept, elem)
+		}
+	}
+
+	result.Query = query
+	result.Elements = kept
+	result.MatchCount = len(kept)
+	result.Message = ""
+	if result.IsEmpty() {
+		result.Message = "No elements found matching query"
+	}
+	return result, nil
+}
+
 // QueryEpicFileFormatted executes an XPath query and returns formatted output
 func (s *Service) QueryEpicFileFormatted(filePath, xpathExpr string, format OutputFormat) (string, error) {
 	result, err := s.QueryEpicFile(filePath, xpathExpr)
@@ -219,3 +219,57 @@ func TestService_IntegrationWithRealEpicStructure(t *testing.T) {
 		}
 	})
 }
+
+func TestService_FilterAssignee(t *testing.T) {
+	epicFile := filepath.Join(t.TempDir(), "epic.xml")
+	require.NoError(t, os.WriteFile(epicFile, []byte(`<epic id="split">
+    <assignee>agent_a</assignee>
+    <phases>
+        <phase id="1" name="Backend"/>
+        <phase id="2" name="Frontend" assignee="agent_b"/>
+    </phases>
+    <tasks>
+        <task id="1_1" phase_id="1"><description>API</description></task>
+        <task id="1_2" phase_id="1" assignee="agent_b"><description>Client</description></task>
+        <task id="2_1" phase_id="2"><description>Form</description></task>
+    </tasks>
+    <tests>
+        <test id="T1_1" task_id="1_1"/>
+        <test id="T2_1" task_id="2_1"/>
+    </tests>
+    <events>
+        <event id="E1" type="created"/>
+    </events>
+</epic>`), 0644))
+
+	ids := func(result *QueryResult) []string {
+		var ids []string
+		for _, elem := range result.Elements {
+			ids = append(ids, elem.Tag+":"+elem.SelectAttrValue("id", elem.Parent().SelectAttrValue("id", "")))
+		}
+		return ids
+	}
+	service := NewService()
+
+	result, err := service.QueryEpicFile(epicFile, "//task")
+	require.NoError(t, err)
+	service.FilterAssignee(result, "AGENT_B")
+	assert.Equal(t, []string{"task:1_2", "task:2_1"}, ids(result))
+	assert.Equal(t, 2, result.MatchCount)
+
+	result, err = service.QueryEpicFile(epicFile, "//description")
+	require.NoError(t, err)
+	service.FilterAssignee(result, "agent_a")
+	assert.Equal(t, []string{"description:1_1"}, ids(result), "nested elements belong to their task")
+
+	result, err = service.QueryEpicFile(epicFile, "//test")
+	require.NoError(t, err)
+	service.FilterAssignee(result, "agent_b")
+	assert.Equal(t, []string{"test:T2_1"}, ids(result))
+
+	result, err = service.QueryEpicFile(epicFile, "//event")
+	require.NoError(t, err)
+	service.FilterAssignee(result, "agent_a")
+	assert.True(t, result.IsEmpty(), "events belong to no agent")
+	assert.Equal(t, "No elements found matching query", result.Message)
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"os"
 
 	"github.com/mindreframer/agentpm/cmd"
@@ -19,6 +18,69 @@ func main() {
 	app := &cli.Command{
 		Name:  "agentpm",
 		Usage: "CLI tool for LLM agents to manage epic-based development work",
+		Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+			// An explicit --config disables walking up parent directories
+			ctx, err := cmd.PinExplicitConfig(ctx, c)
+			if err != nil {
+				return ctx, err
+			}
+			if ctx, err = cmd.ApplyProfile(ctx, c); err != nil {
+				return ctx, err
+			}
+			if ctx, err = cmd.ApplyAPIVersion(ctx, c); err != nil {
+				return ctx, err
+			}
+			// serve never changes the epic, so the hooks below have nothing to do for it
+			if cmd.ServesReadOnly(c) {
+				return ctx, nil
+			}
+			if ctx, err = cmd.LockEpicFiles(ctx, c); err != nil {
+				return ctx, err
+			}
+			if ctx, err = cmd.KeepEpicBackups(ctx, c); err != nil {
+				return ctx, err
+			}
+			if ctx, err = cmd.ApplyStrictMode(ctx, c); err != nil {
+				return ctx, err
+			}
+			if ctx, err = cmd.ApplyPolicy(ctx, c); err != nil {
+				return ctx, err
+			}
+			if ctx, err = cmd.ApplyGateTags(ctx, c); err != nil {
+				return ctx, err
+			}
+			if ctx, err = cmd.ApplyTestGating(ctx, c); err != nil {
+				return ctx, err
+			}
+			if ctx, err = cmd.WarnDeprecations(ctx, c); err != nil {
+				return ctx, err
+			}
+			if ctx, err = cmd.CheckConsistency(ctx, c); err != nil {
+				return ctx, err
+			}
+			if ctx, err = cmd.GuardCompletedEpics(ctx, c); err != nil {
+				return ctx, err
+			}
+			if ctx, err = cmd.JournalMutations(ctx, c); err != nil {
+				return ctx, err
+			}
+			if ctx, err = cmd.TrackHintRepeats(ctx, c); err != nil {
+				return ctx, err
+			}
+			if ctx, err = cmd.ApplyHintConfig(ctx, c); err != nil {
+				return ctx, err
+			}
+			if ctx, err = cmd.RecordGitCommit(ctx, c); err != nil {
+				return ctx, err
+			}
+			if ctx, err = cmd.CorrelateEvents(ctx, c); err != nil {
+				return ctx, err
+			}
+			if ctx, err = cmd.ApplyDryRun(ctx, c); err != nil {
+				return ctx, err
+			}
+			return cmd.RecordActor(ctx, c)
+		},
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:    "file",
@@ -28,7 +90,7 @@ func main() {
 			&cli.StringFlag{
 				Name:    "config",
 				Aliases: []string{"c"},
-				Usage:   "Override config file path",
+				Usage:   "Override config file path (default: nearest .agentpm.json in this or a parent directory)",
 				Value:   "./.agentpm.json",
 			},
 			&cli.StringFlag{
@@ -39,9 +101,58 @@ func main() {
 			&cli.StringFlag{
 				Name:    "format",
 				Aliases: []string{"F"},
-				Usage:   "Output format - text (default) / json / xml",
+				Usage:   "Output format - text (default) / json / xml / markdown (docs, handoff, status, show)",
 				Value:   "text",
 			},
+			&cli.StringFlag{
+				Name:    "profile",
+				Usage:   "Profile of the config to apply (profiles key of .agentpm.json), e.g. ci or local",
+				Sources: cli.EnvVars("AGENTPM_PROFILE"),
+			},
+			&cli.StringFlag{
+				Name:    "actor",
+				Usage:   "Agent or human that recorded events are attributed to",
+				Sources: cli.EnvVars("AGENTPM_ACTOR"),
+			},
+			&cli.StringFlag{
+				Name:    "api-version",
+				Usage:   "Version of the json/xml output (default: api_version of the config, else 1)",
+				Sources: cli.EnvVars("AGENTPM_API_VERSION"),
+			},
+			&cli.BoolFlag{
+				Name:    "strict",
+				Usage:   "Refuse completions that validation would only warn about (missing tests, unchecked acceptance criteria)",
+				Sources: cli.EnvVars("AGENTPM_STRICT"),
+			},
+			&cli.BoolFlag{
+				Name:    "no-deprecation-warnings",
+				Usage:   "Do not warn about legacy statuses in the epic (see migrate-status)",
+				Sources: cli.EnvVars("AGENTPM_NO_DEPRECATION_WARNINGS"),
+			},
+			&cli.BoolFlag{
+				Name:    "no-lock",
+				Usage:   "Do not lock the epic file against concurrent agentpm processes",
+				Sources: cli.EnvVars("AGENTPM_NO_LOCK"),
+			},
+			&cli.BoolFlag{
+				Name:    "no-check",
+				Usage:   "Change the epic even if it is inconsistent (several active phases or tasks, invalid statuses)",
+				Sources: cli.EnvVars("AGENTPM_NO_CHECK"),
+			},
+			&cli.BoolFlag{
+				Name:    "no-git",
+				Usage:   "Do not link recorded events to the checked out git commit and branch",
+				Sources: cli.EnvVars("AGENTPM_NO_GIT"),
+			},
+			&cli.BoolFlag{
+				Name:    "quiet",
+				Aliases: []string{"q"},
+				Usage:   "Print only a single result line for mutating commands",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Print the changes and events mutating commands would save, without saving them",
+			},
 		},
 		Commands: []*cli.Command{
 			// CORE WORKFLOW - Most frequently used commands
@@ -49,6 +160,13 @@ func main() {
 			addCategory(cmd.DoneCommand(), "CORE WORKFLOW"),
 			addCategory(cmd.CancelCommand(), "CORE WORKFLOW"),
 			addCategory(cmd.StartNextCommand(), "CORE WORKFLOW"),
+			addCategory(cmd.StartNextTestCommand(), "CORE WORKFLOW"),
+			addCategory(cmd.UndoCommand(), "CORE WORKFLOW"),
+			addCategory(cmd.ReopenCommand(), "CORE WORKFLOW"),
+			addCategory(cmd.AssignCommand(), "CORE WORKFLOW"),
+			addCategory(cmd.EditCommand(), "CORE WORKFLOW"),
+			addCategory(cmd.BlockerCommand(), "CORE WORKFLOW"),
+			addCategory(cmd.NoteCommand(), "CORE WORKFLOW"),
 
 			// TESTING - Test management commands
 			addCategory(cmd.PassCommand(), "TESTING"),
@@ -59,31 +177,75 @@ func main() {
 			addCategory(cmd.CurrentCommand(), "STATUS"),
 			addCategory(cmd.PendingCommand(), "STATUS"),
 			addCategory(cmd.FailingCommand(), "STATUS"),
+			addCategory(cmd.UpcomingCommand(), "STATUS"),
 
 			// INSPECTION - Detailed entity examination
 			addCategory(cmd.ShowCommand(), "INSPECTION"),
 			addCategory(cmd.QueryCommand(), "INSPECTION"),
+			addCategory(cmd.IndexCommand(), "INSPECTION"),
+			addCategory(cmd.SearchCommand(), "INSPECTION"),
+			addCategory(cmd.HintsCommand(), "INSPECTION"),
+			addCategory(cmd.OnboardCommand(), "INSPECTION"),
 
 			// PROJECT - Project setup and management
 			addCategory(cmd.InitCommand(), "PROJECT"),
+			addCategory(cmd.CreateCommand(), "PROJECT"),
 			addCategory(cmd.SwitchCommand(), "PROJECT"),
+			addCategory(cmd.EpicsCommand(), "PROJECT"),
 			addCategory(cmd.ConfigCommand(), "PROJECT"),
+			addCategory(cmd.TokenCommand(), "PROJECT"),
+			addCategory(cmd.TemplateCommand(), "PROJECT"),
+			addCategory(cmd.ScaffoldCommand(), "PROJECT"),
+			addCategory(cmd.ImportCommand(), "PROJECT"),
 			addCategory(cmd.ValidateCommand(), "PROJECT"),
+			addCategory(cmd.LintCommand(), "PROJECT"),
+			addCategory(cmd.WatchCommand(), "PROJECT"),
+			addCategory(cmd.ServeCommand(), "PROJECT"),
 			addCategory(cmd.FixXMLCommand(), "PROJECT"),
+			addCategory(cmd.MigrateCommand(), "PROJECT"),
+			addCategory(cmd.MigrateStatusCommand(), "PROJECT"),
+			addCategory(cmd.ConvertCommand(), "PROJECT"),
+			addCategory(cmd.SplitCommand(), "PROJECT"),
+			addCategory(cmd.MergeCommand(), "PROJECT"),
+			addCategory(cmd.BackfillTimestampsCommand(), "PROJECT"),
+			addCategory(cmd.ResetCommand(), "PROJECT"),
+			addCategory(cmd.RestoreCommand(), "PROJECT"),
+			addCategory(cmd.FreezeCommand(), "PROJECT"),
+			addCategory(cmd.UnfreezeCommand(), "PROJECT"),
+			addCategory(cmd.ArchiveCommand(), "PROJECT"),
+			addCategory(cmd.CompactCommand(), "PROJECT"),
 
 			// REPORTING - Documentation and handoff
 			addCategory(cmd.LogCommand(), "REPORTING"),
 			addCategory(cmd.EventsCommand(), "REPORTING"),
+			addCategory(cmd.SummarizeEventsCommand(), "REPORTING"),
 			addCategory(cmd.DocsCommand(), "REPORTING"),
+			addCategory(cmd.LinkCommand(), "REPORTING"),
 			addCategory(cmd.HandoffCommand(), "REPORTING"),
+			addCategory(cmd.ResumeCommand(), "REPORTING"),
+			addCategory(cmd.TraceCommand(), "REPORTING"),
+			addCategory(cmd.BurndownCommand(), "REPORTING"),
+			addCategory(cmd.VelocityCommand(), "REPORTING"),
+			addCategory(cmd.ThroughputCommand(), "REPORTING"),
+			addCategory(cmd.MetricsCommand(), "REPORTING"),
+			addCategory(cmd.ForecastCommand(), "REPORTING"),
+			addCategory(cmd.StatsCommand(), "REPORTING"),
+			addCategory(cmd.EffortCommand(), "REPORTING"),
+			addCategory(cmd.CompareRunsCommand(), "REPORTING"),
+			addCategory(cmd.DiffCommand(), "REPORTING"),
+			addCategory(cmd.ExportCommand(), "REPORTING"),
+			addCategory(cmd.BadgeCommand(), "REPORTING"),
 
 			// SYSTEM - Version and help
 			addCategory(cmd.VersionCommand(), "SYSTEM"),
+			addCategory(cmd.SchemaCommand(), "SYSTEM"),
 		},
 	}
 
+	// Errors are reported in the output format of the command that failed,
+	// with an exit code scripts can rely on (see internal/exitcode)
+	invoked := cmd.TrackInvokedCommand(app)
 	if err := app.Run(context.Background(), os.Args); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(cmd.ReportError(os.Stderr, invoked(), err))
 	}
 }
@@ -0,0 +1,340 @@
+// Chain Viewer
+// Renders a transition chain result exported by the Epic 14 executor as an
+// HTML timeline
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mindreframer/agentpm/internal/epic"
+	"github.com/mindreframer/agentpm/internal/testing/executor"
+)
+
+type Config struct {
+	InputFile  string
+	OutputFile string
+}
+
+// Timeline is the view of an exported chain result the page is rendered from
+type Timeline struct {
+	Title             string
+	Success           bool
+	ExecutionTime     time.Duration
+	ExportedAt        time.Time
+	Steps             []Step
+	Snapshots         []Snapshot
+	Errors            []executor.ErrorExport
+	AssertionFailures []string
+	Initial           *StateSummary
+	Final             *StateSummary
+}
+
+// Step is one executed command on the timeline
+type Step struct {
+	Index       int
+	Type        string
+	Target      string
+	Description string
+	Offset      time.Duration
+	Duration    time.Duration
+	BarStart    float64
+	BarWidth    float64
+	Success     bool
+	Error       string
+	State       *StateSummary
+}
+
+// Snapshot is an intermediate state snapshot
+type Snapshot struct {
+	Command string
+	Offset  time.Duration
+	Success bool
+	Error   string
+	State   *StateSummary
+}
+
+// StateSummary lists the statuses of an epic state
+type StateSummary struct {
+	ID       string
+	Name     string
+	Status   string
+	Entities []EntityStatus
+	Events   int
+}
+
+// EntityStatus is the status of a phase, task or test
+type EntityStatus struct {
+	Type   string
+	ID     string
+	Name   string
+	Status string
+}
+
+func main() {
+	config := parseFlags()
+
+	export, err := executor.ReadResultFile(config.InputFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading chain result: %v\n", err)
+		os.Exit(1)
+	}
+
+	timeline, err := buildTimeline(export, filepath.Base(config.InputFile))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error building timeline: %v\n", err)
+		os.Exit(1)
+	}
+
+	out := io.Writer(os.Stdout)
+	if config.OutputFile != "" && config.OutputFile != "-" {
+		file, err := os.Create(config.OutputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating output file: %v\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if err := pageTemplate.Execute(out, timeline); err != nil {
+		fmt.Fprintf(os.Stderr, "Error rendering timeline: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func parseFlags() Config {
+	var config Config
+
+	flag.StringVar(&config.InputFile, "input", "", "Chain result JSON file exported by the executor")
+	flag.StringVar(&config.OutputFile, "output", "", "Output HTML file (default: stdout)")
+
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [options]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nOptions:\n")
+		flag.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExample:\n")
+		fmt.Fprintf(os.Stderr, "  %s -input chain-8-123.json -output timeline.html\n", os.Args[0])
+	}
+
+	flag.Parse()
+
+	if config.InputFile == "" {
+		fmt.Fprintf(os.Stderr, "Error: -input flag is required\n\n")
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	return config
+}
+
+func buildTimeline(export *executor.ResultExport, title string) (*Timeline, error) {
+	timeline := &Timeline{
+		Title:             title,
+		Success:           export.Success,
+		ExecutionTime:     export.ExecutionTime,
+		ExportedAt:        export.ExportedAt,
+		Errors:            export.Errors,
+		AssertionFailures: export.AssertionFailures,
+	}
+
+	var err error
+	if timeline.Initial, err = summarize(export, export.InitialState); err != nil {
+		return nil, err
+	}
+	if timeline.Final, err = summarize(export, export.FinalState); err != nil {
+		return nil, err
+	}
+
+	// The timeline spans from the first command start to the last command end
+	var start, end time.Time
+	for i, command := range export.Commands {
+		if i == 0 || command.StartTime.Before(start) {
+			start = command.StartTime
+		}
+		if command.EndTime.After(end) {
+			end = command.EndTime
+		}
+	}
+	span := end.Sub(start)
+
+	for i, command := range export.Commands {
+		state, err := summarize(export, command.EpicState)
+		if err != nil {
+			return nil, err
+		}
+		step := Step{
+			Index:       i + 1,
+			Type:        command.Type,
+			Target:      command.Target,
+			Description: command.Description,
+			Offset:      command.StartTime.Sub(start),
+			Duration:    command.Duration,
+			Success:     command.Success,
+			Error:       command.Error,
+			State:       state,
+			BarWidth:    100,
+		}
+		if span > 0 {
+			step.BarStart = 100 * float64(step.Offset) / float64(span)
+			step.BarWidth = 100 * float64(command.Duration) / float64(span)
+		}
+		timeline.Steps = append(timeline.Steps, step)
+	}
+
+	for _, snapshot := range export.Snapshots {
+		state, err := summarize(export, snapshot.EpicState)
+		if err != nil {
+			return nil, err
+		}
+		offset := time.Duration(0)
+		if !start.IsZero() {
+			offset = snapshot.Timestamp.Sub(start)
+		}
+		timeline.Snapshots = append(timeline.Snapshots, Snapshot{
+			Command: snapshot.Command,
+			Offset:  offset,
+			Success: snapshot.Success,
+			Error:   snapshot.Error,
+			State:   state,
+		})
+	}
+
+	return timeline, nil
+}
+
+func summarize(export *executor.ResultExport, state json.RawMessage) (*StateSummary, error) {
+	e, err := export.Epic(state)
+	if err != nil || e == nil {
+		return nil, err
+	}
+
+	summary := &StateSummary{ID: e.ID, Name: e.Name, Status: string(e.Status), Events: len(e.Events)}
+	for _, phase := range e.Phases {
+		summary.Entities = append(summary.Entities, EntityStatus{"phase", phase.ID, phase.Name, string(phase.Status)})
+	}
+	for _, task := range e.Tasks {
+		summary.Entities = append(summary.Entities, EntityStatus{"task", task.ID, task.Name, string(task.Status)})
+	}
+	for _, test := range e.Tests {
+		summary.Entities = append(summary.Entities, EntityStatus{"test", test.ID, test.Name, testStatus(test)})
+	}
+	return summary, nil
+}
+
+func testStatus(test epic.Test) string {
+	status := string(test.TestStatus)
+	if status == "" {
+		status = string(test.Status)
+	}
+	if test.TestResult != "" {
+		status += " (" + string(test.TestResult) + ")"
+	}
+	return status
+}
+
+var pageTemplate = template.Must(template.New("timeline").Funcs(template.FuncMap{
+	"statusClass": func(status string) string {
+		return "status-" + strings.Fields(status + " none")[0]
+	},
+	"percent": func(value float64) string {
+		return fmt.Sprintf("%.2f%%", value)
+	},
+	"timestamp": func(t time.Time) string {
+		return t.Format(time.RFC3339)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Chain result: {{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", sans-serif; margin: 2em; color: #222; }
+h1 { font-size: 1.4em; }
+.badge { padding: 0.2em 0.6em; border-radius: 4px; color: #fff; }
+.passed { background: #2e7d32; }
+.failed { background: #c62828; }
+.timeline { border-left: 3px solid #ccc; margin: 1em 0; padding-left: 1em; }
+.step { margin-bottom: 1em; }
+.step.failed-step .title { color: #c62828; }
+.bar-track { position: relative; height: 8px; background: #eee; border-radius: 4px; margin: 0.3em 0; }
+.bar { position: absolute; height: 8px; min-width: 2px; border-radius: 4px; background: #1565c0; }
+.failed-step .bar { background: #c62828; }
+.meta { color: #666; font-size: 0.9em; }
+.error { color: #c62828; white-space: pre-wrap; }
+table { border-collapse: collapse; margin: 0.5em 0; font-size: 0.9em; }
+td, th { border: 1px solid #ddd; padding: 0.2em 0.6em; text-align: left; }
+.status-completed, .status-done { color: #2e7d32; }
+.status-wip { color: #1565c0; }
+.status-cancelled, .status-failed { color: #c62828; }
+.status-pending, .status-none { color: #777; }
+</style>
+</head>
+<body>
+<h1>Chain result: {{.Title}}
+{{if .Success}}<span class="badge passed">passed</span>{{else}}<span class="badge failed">failed</span>{{end}}</h1>
+<p class="meta">{{len .Steps}} commands in {{.ExecutionTime}} &middot; exported {{timestamp .ExportedAt}}</p>
+
+{{if .AssertionFailures}}
+<h2>Assertion failures</h2>
+<ul>{{range .AssertionFailures}}<li class="error">{{.}}</li>{{end}}</ul>
+{{end}}
+
+{{if .Errors}}
+<h2>Errors</h2>
+<ul>{{range .Errors}}
+<li><span class="error">{{.Message}}</span>
+{{if .Suggestions}}<ul>{{range .Suggestions}}<li>{{.}}</li>{{end}}</ul>{{end}}
+</li>{{end}}
+</ul>
+{{end}}
+
+<h2>Timeline</h2>
+{{with .Initial}}<details><summary>Initial state: epic {{.ID}} <span class="{{statusClass .Status}}">{{.Status}}</span></summary>{{template "state" .}}</details>{{end}}
+<div class="timeline">
+{{range .Steps}}
+<div class="step{{if not .Success}} failed-step{{end}}">
+<div class="title"><strong>{{.Index}}. {{.Type}}</strong>{{if .Target}} {{.Target}}{{end}}{{if .Description}} &mdash; {{.Description}}{{end}}</div>
+<div class="bar-track"><div class="bar" style="left: {{percent .BarStart}}; width: {{percent .BarWidth}}"></div></div>
+<div class="meta">+{{.Offset}} &middot; took {{.Duration}}</div>
+{{if .Error}}<div class="error">{{.Error}}</div>{{end}}
+{{with .State}}<details><summary>State after: epic <span class="{{statusClass .Status}}">{{.Status}}</span></summary>{{template "state" .}}</details>{{end}}
+</div>
+{{else}}
+<p>No commands were executed.</p>
+{{end}}
+</div>
+{{with .Final}}<details open><summary>Final state: epic {{.ID}} <span class="{{statusClass .Status}}">{{.Status}}</span></summary>{{template "state" .}}</details>{{end}}
+
+{{if .Snapshots}}
+<h2>Snapshots</h2>
+<table>
+<tr><th>Offset</th><th>Command</th><th>Epic</th><th>Error</th></tr>
+{{range .Snapshots}}
+<tr>
+<td>+{{.Offset}}</td><td>{{.Command}}</td>
+<td>{{with .State}}<span class="{{statusClass .Status}}">{{.Status}}</span>{{end}}</td>
+<td class="error">{{.Error}}</td>
+</tr>
+{{end}}
+</table>
+{{end}}
+</body>
+</html>
+{{define "state"}}
+<table>
+<tr><th>Type</th><th>ID</th><th>Name</th><th>Status</th></tr>
+<tr><td>epic</td><td>{{.ID}}</td><td>{{.Name}}</td><td class="{{statusClass .Status}}">{{.Status}}</td></tr>
+{{range .Entities}}<tr><td>{{.Type}}</td><td>{{.ID}}</td><td>{{.Name}}</td><td class="{{statusClass .Status}}">{{.Status}}</td></tr>{{end}}
+</table>
+<p class="meta">{{.Events}} events</p>
+{{end}}
+`))